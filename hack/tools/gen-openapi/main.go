@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
@@ -14,6 +15,13 @@ import (
 
 	"github.com/agentregistry-dev/agentregistry/internal/registry/api/router"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/config"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/controller"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/gc"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/mcptraffic"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/secrets"
+	deploymentsvc "github.com/agentregistry-dev/agentregistry/internal/registry/service/deployment"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/snapshots"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/usagestats"
 	"github.com/agentregistry-dev/agentregistry/internal/version"
 	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
 	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
@@ -53,12 +61,7 @@ func main() {
 func generateSpec(apiVersion string) *huma.OpenAPI {
 	mux := http.NewServeMux()
 
-	humaConfig := huma.DefaultConfig("AgentRegistry", apiVersion)
-	humaConfig.Info.Description = "AgentRegistry API for managing MCP servers, agents, skills, and deployments."
-	// Disable $schema property injection in responses
-	humaConfig.CreateHooks = []func(huma.Config) huma.Config{}
-
-	api := humago.New(mux, humaConfig)
+	api := humago.New(mux, router.NewHumaConfig(apiVersion))
 
 	// Some registration functions (auth handlers) dereference the config at
 	// registration time to set up JWT managers, so we need a minimal config
@@ -70,6 +73,11 @@ func generateSpec(apiVersion string) *huma.OpenAPI {
 		// (opt-in via AGENT_REGISTRY_MCP_REGISTRY_COMPAT_ENABLED); documenting
 		// the surface regardless keeps the published OpenAPI complete.
 		MCPRegistryCompatEnabled: true,
+		// Same for the public cacheable mirror (opt-in via
+		// AGENT_REGISTRY_PUBLIC_MIRROR_ENABLED).
+		PublicMirrorEnabled:   true,
+		PublicMirrorNamespace: "default",
+		PublicMirrorMaxAge:    time.Hour,
 	}
 
 	// Register all routes. Services and metrics are nil because they are only
@@ -81,6 +89,33 @@ func generateSpec(apiVersion string) *huma.OpenAPI {
 		BuildTime: version.BuildDate,
 	}, &router.RouteOptions{
 		Stores: v1alpha1store.NewStores(nil, pkgdb.OSSSchemaRegistry()),
+		// A typed-nil controller is enough to register the admin reconcile
+		// plan and Deployment dry run routes; it is only dereferenced at
+		// request time.
+		ReconcilePlanner:   (*controller.DeploymentController)(nil),
+		DeploymentRenderer: (*controller.DeploymentController)(nil),
+		// The logs route only calls its resolver per request too.
+		DeploymentLogResolver:  (*deploymentsvc.AdapterResolver)(nil),
+		DeploymentMCPEndpoints: (*deploymentsvc.AdapterResolver)(nil),
+		MCPTraffic:             mcptraffic.NewRecorder(0),
+		// Same for the Webhook delivery log; the nil pool is never queried.
+		WebhookDeliveries:   v1alpha1store.NewWebhookDeliveryStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		Namespaces:          v1alpha1store.NewNamespaceStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		APIKeys:             v1alpha1store.NewAPIKeyStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		ReservedPrefixes:    v1alpha1store.NewReservedPrefixStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		RuntimeGrants:       v1alpha1store.NewRuntimeGrantStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		DeploymentManifests: v1alpha1store.NewDeploymentManifestStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		DeploymentNotes:     v1alpha1store.NewDeploymentNoteStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		DeploymentShares:    v1alpha1store.NewDeploymentShareStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		PromptEvaluations:   v1alpha1store.NewPromptEvaluationStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		RuntimeSecrets:      secrets.NewSealer(nil),
+		Readmes:             v1alpha1store.NewArtifactReadmeStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		Icons:               v1alpha1store.NewArtifactIconStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		ServerTools:         v1alpha1store.NewServerToolStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		Usage:               usagestats.New(v1alpha1store.NewUsageStatsStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema))),
+		VersionGC:           &gc.Collector{},
+		Snapshots:           v1alpha1store.NewCatalogSnapshotStore(nil, pkgdb.MustNewSchema(pkgdb.OSSSchema)),
+		SnapshotTaker:       &snapshots.Catalog{},
 	}); err != nil {
 		panic(fmt.Sprintf("router.RegisterRoutes: %v", err))
 	}
@@ -0,0 +1,137 @@
+// Package a2a is the A2A client shared by everything in arctl that talks
+// to an agent: the chat TUI today, evaluation runs and the relay next. It
+// wraps trpc-a2a-go with the parts a long agent turn needs and the library
+// leaves to callers:
+//
+//   - Timeouts that fit streaming. A plain http.Client timeout also bounds
+//     reading the body, so it cuts off any turn that outlasts it. Options
+//     bound connecting, the gap between events and, optionally, the turn.
+//   - Reconnection. When a stream drops before the task finishes, Stream
+//     looks the task up (tasks/get). If it finished meanwhile, Stream
+//     delivers the results the caller has not seen yet; otherwise it
+//     resubscribes (tasks/resubscribe) and carries on delivering events on
+//     the same channel.
+//   - Pooling. A Pool hands out one Client per agent URL, all sharing the
+//     process-wide transport from internal/httpclient, so repeated turns
+//     and concurrent callers reuse connections.
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	a2aclient "trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+)
+
+// Defaults for zero Options fields.
+const (
+	DefaultConnectTimeout = 30 * time.Second
+	DefaultIdleTimeout    = 5 * time.Minute
+	DefaultMaxReconnects  = 3
+	DefaultBackoff        = time.Second
+)
+
+// Options tune a Client. The zero value uses the defaults above and no
+// overall turn timeout.
+type Options struct {
+	// ConnectTimeout bounds opening a stream, up to its response headers.
+	ConnectTimeout time.Duration
+	// IdleTimeout is the longest a stream may go without an event before
+	// it is treated as dropped and reconnected. Negative disables it.
+	IdleTimeout time.Duration
+	// Timeout bounds a whole turn, reconnects included. Zero means no
+	// limit: the caller's context decides.
+	Timeout time.Duration
+	// MaxReconnects caps reconnects per turn. Negative disables them.
+	MaxReconnects int
+	// Backoff is the wait before the first reconnect; it doubles for each
+	// one after.
+	Backoff time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.ConnectTimeout <= 0 {
+		o.ConnectTimeout = DefaultConnectTimeout
+	}
+	if o.IdleTimeout == 0 {
+		o.IdleTimeout = DefaultIdleTimeout
+	}
+	if o.MaxReconnects == 0 {
+		o.MaxReconnects = DefaultMaxReconnects
+	}
+	if o.Backoff <= 0 {
+		o.Backoff = DefaultBackoff
+	}
+	return o
+}
+
+// transport is the subset of *a2aclient.A2AClient a Client drives.
+type transport interface {
+	StreamMessage(ctx context.Context, params protocol.SendMessageParams, opts ...a2aclient.RequestOption) (<-chan protocol.StreamingMessageEvent, error)
+	ResubscribeTask(ctx context.Context, params protocol.TaskIDParams, opts ...a2aclient.RequestOption) (<-chan protocol.StreamingMessageEvent, error)
+	GetTasks(ctx context.Context, params protocol.TaskQueryParams, opts ...a2aclient.RequestOption) (*protocol.Task, error)
+}
+
+var _ transport = (*a2aclient.A2AClient)(nil)
+
+// Client streams messages to one agent.
+type Client struct {
+	url  string
+	conn transport
+	opts Options
+}
+
+// NewClient returns a Client for the agent at agentURL.
+func NewClient(agentURL string, opts Options) (*Client, error) {
+	// No http.Client timeout: it would also bound reading the stream.
+	// Options.ConnectTimeout and IdleTimeout cover what it protected.
+	conn, err := a2aclient.NewA2AClient(agentURL, a2aclient.WithHTTPClient(httpclient.New(0)))
+	if err != nil {
+		return nil, fmt.Errorf("create A2A client for %s: %w", agentURL, err)
+	}
+	return newClient(agentURL, conn, opts), nil
+}
+
+func newClient(agentURL string, conn transport, opts Options) *Client {
+	return &Client{url: agentURL, conn: conn, opts: opts.withDefaults()}
+}
+
+// URL returns the agent URL the client talks to.
+func (c *Client) URL() string { return c.url }
+
+// Pool hands out one Client per agent URL.
+type Pool struct {
+	opts Options
+
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// NewPool returns a Pool whose clients use opts.
+func NewPool(opts Options) *Pool {
+	return &Pool{opts: opts, clients: map[string]*Client{}}
+}
+
+// Client returns the pool's Client for agentURL, creating it on first use.
+func (p *Pool) Client(agentURL string) (*Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.clients[agentURL]; ok {
+		return c, nil
+	}
+	c, err := NewClient(agentURL, p.opts)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[agentURL] = c
+	return c, nil
+}
+
+// ErrInterrupted reports a stream that dropped and could not be resumed.
+var ErrInterrupted = errors.New("agent stream interrupted")
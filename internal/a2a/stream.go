@@ -0,0 +1,246 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// streamBuffer matches trpc-a2a-go's own channel size.
+const streamBuffer = 1024
+
+// Stream is one streamed turn. Read Events until it closes, then check
+// Err.
+type Stream struct {
+	events     chan protocol.StreamingMessageEvent
+	err        error
+	reconnects int
+}
+
+// Events delivers the agent's events, in order and across reconnects. It
+// is closed when the turn ends.
+func (s *Stream) Events() <-chan protocol.StreamingMessageEvent { return s.events }
+
+// Err reports why the turn ended early, or nil if the agent finished it.
+// It is only meaningful once Events is closed.
+func (s *Stream) Err() error { return s.err }
+
+// Reconnects counts the times the turn's stream was reconnected. It is
+// only meaningful once Events is closed.
+func (s *Stream) Reconnects() int { return s.reconnects }
+
+// Stream sends params to the agent and streams its reply. A message
+// without an ID is given one. An error is returned only if the stream
+// cannot be opened; once open, failures end Events and surface in Err.
+func (c *Client) Stream(ctx context.Context, params protocol.SendMessageParams) (*Stream, error) {
+	if params.Message.MessageID == "" {
+		params.Message.MessageID = protocol.GenerateMessageID()
+	}
+	cancel := context.CancelFunc(func() {})
+	if c.opts.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+	}
+	attemptCtx, cancelAttempt := context.WithCancel(ctx)
+	ch, err := c.open(attemptCtx, cancelAttempt, func(ctx context.Context) (<-chan protocol.StreamingMessageEvent, error) {
+		return c.conn.StreamMessage(ctx, params)
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	s := &Stream{events: make(chan protocol.StreamingMessageEvent, streamBuffer)}
+	go c.run(ctx, cancel, s, ch, cancelAttempt)
+	return s, nil
+}
+
+// open dials one stream under ctx, which cancel ends, giving up after
+// ConnectTimeout. The stream lives as long as ctx.
+func (c *Client) open(ctx context.Context, cancel context.CancelFunc, dial func(context.Context) (<-chan protocol.StreamingMessageEvent, error)) (<-chan protocol.StreamingMessageEvent, error) {
+	timer := time.AfterFunc(c.opts.ConnectTimeout, cancel)
+	ch, err := dial(ctx)
+	if !timer.Stop() {
+		cancel()
+		return nil, fmt.Errorf("connect to agent at %s: no response within %s", c.url, c.opts.ConnectTimeout)
+	}
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("connect to agent at %s: %w", c.url, err)
+	}
+	return ch, nil
+}
+
+// run forwards ch to s, reconnecting while the task is unfinished, and
+// closes s when the turn ends. A failed reconnect counts against
+// MaxReconnects like a dropped one.
+func (c *Client) run(ctx context.Context, cancel context.CancelFunc, s *Stream, ch <-chan protocol.StreamingMessageEvent, cancelAttempt context.CancelFunc) {
+	defer close(s.events)
+	defer cancel()
+	t := &turn{completed: map[string]bool{}}
+	for {
+		if ch != nil {
+			c.forward(ctx, ch, s, t)
+		}
+		cancelAttempt()
+		switch {
+		case t.done:
+			return
+		case ctx.Err() != nil:
+			s.err = c.contextErr(ctx)
+			return
+		case t.taskID == "":
+			s.err = fmt.Errorf("%w before the agent reported a task", ErrInterrupted)
+			return
+		case s.reconnects >= c.opts.MaxReconnects:
+			s.err = fmt.Errorf("%w: gave up after %d reconnects", ErrInterrupted, max(s.reconnects, 0))
+			return
+		}
+		if !sleep(ctx, c.opts.Backoff<<s.reconnects) {
+			s.err = c.contextErr(ctx)
+			return
+		}
+		s.reconnects++
+
+		// If the task finished while the stream was down, its results are
+		// all there is left to deliver; otherwise pick the stream back up.
+		if c.fetch(ctx, s, t) {
+			return
+		}
+		var attemptCtx context.Context
+		attemptCtx, cancelAttempt = context.WithCancel(ctx)
+		ch, _ = c.open(attemptCtx, cancelAttempt, func(ctx context.Context) (<-chan protocol.StreamingMessageEvent, error) {
+			return c.conn.ResubscribeTask(ctx, protocol.TaskIDParams{ID: t.taskID})
+		})
+	}
+}
+
+// forward delivers ch's events until it closes, goes IdleTimeout without
+// one, or the turn is done.
+func (c *Client) forward(ctx context.Context, ch <-chan protocol.StreamingMessageEvent, s *Stream, t *turn) {
+	var idle *time.Timer
+	var idleC <-chan time.Time
+	if c.opts.IdleTimeout > 0 {
+		idle = time.NewTimer(c.opts.IdleTimeout)
+		defer idle.Stop()
+		idleC = idle.C
+	}
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return
+			}
+			if idle != nil {
+				idle.Reset(c.opts.IdleTimeout)
+			}
+			if !t.observe(ev) {
+				continue
+			}
+			select {
+			case s.events <- ev:
+			case <-ctx.Done():
+				return
+			}
+			if t.done {
+				return
+			}
+		case <-idleC:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// fetch reads the task and, if it has finished, delivers it without the
+// artifacts already streamed and reports true.
+func (c *Client) fetch(ctx context.Context, s *Stream, t *turn) bool {
+	getCtx, cancel := context.WithTimeout(ctx, c.opts.ConnectTimeout)
+	defer cancel()
+	task, err := c.conn.GetTasks(getCtx, protocol.TaskQueryParams{ID: t.taskID})
+	if err != nil || task == nil || !finalState(task.Status.State) {
+		return false
+	}
+	missed := *task
+	missed.Artifacts = nil
+	for _, artifact := range task.Artifacts {
+		if !t.completed[artifact.ArtifactID] {
+			missed.Artifacts = append(missed.Artifacts, artifact)
+		}
+	}
+	select {
+	case s.events <- protocol.StreamingMessageEvent{Result: &missed}:
+	case <-ctx.Done():
+		s.err = c.contextErr(ctx)
+	}
+	t.done = true
+	return true
+}
+
+func (c *Client) contextErr(ctx context.Context) error {
+	err := ctx.Err()
+	if c.opts.Timeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("agent did not finish within %s: %w", c.opts.Timeout, err)
+	}
+	return err
+}
+
+// turn is what a Stream has seen of the task so far.
+type turn struct {
+	taskID string
+	done   bool
+	// completed holds the IDs of artifacts whose last chunk was
+	// delivered, so a resubscribe that replays them does not repeat them.
+	completed map[string]bool
+}
+
+// observe records ev and reports whether to deliver it.
+func (t *turn) observe(ev protocol.StreamingMessageEvent) bool {
+	switch res := ev.Result.(type) {
+	case *protocol.Message:
+		if res.TaskID != nil {
+			t.taskID = *res.TaskID
+		}
+		t.done = true
+	case *protocol.Task:
+		t.taskID = res.ID
+		t.done = finalState(res.Status.State)
+	case *protocol.TaskStatusUpdateEvent:
+		t.taskID = res.TaskID
+		t.done = res.Final || finalState(res.Status.State)
+	case *protocol.TaskArtifactUpdateEvent:
+		t.taskID = res.TaskID
+		if res.IsFinal() {
+			if t.completed[res.Artifact.ArtifactID] {
+				return false
+			}
+			t.completed[res.Artifact.ArtifactID] = true
+		}
+	}
+	return true
+}
+
+// finalState reports whether a task in state s has nothing more to
+// stream: it finished, or waits on the user.
+func finalState(s protocol.TaskState) bool {
+	switch s {
+	case protocol.TaskStateCompleted, protocol.TaskStateCanceled, protocol.TaskStateFailed,
+		protocol.TaskStateRejected, protocol.TaskStateInputRequired, protocol.TaskStateAuthRequired:
+		return true
+	}
+	return false
+}
+
+// sleep waits d or until ctx ends, reporting whether it waited d.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,239 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	a2aclient "trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// fakeTransport plays back scripted streams: streams[0] answers
+// StreamMessage and each later one a ResubscribeTask. A nil script
+// fails the call; a script with hang set stays open after its events.
+type fakeTransport struct {
+	mu          sync.Mutex
+	streams     []*script
+	task        *protocol.Task
+	calls       []string
+	resubscribe []string
+}
+
+type script struct {
+	events []protocol.StreamingMessageEvent
+	hang   bool
+}
+
+func (f *fakeTransport) next(ctx context.Context, call string) (<-chan protocol.StreamingMessageEvent, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	var sc *script
+	if len(f.streams) > 0 {
+		sc, f.streams = f.streams[0], f.streams[1:]
+	}
+	f.mu.Unlock()
+	if sc == nil {
+		return nil, errors.New("unavailable")
+	}
+	ch := make(chan protocol.StreamingMessageEvent)
+	go func() {
+		defer close(ch)
+		for _, ev := range sc.events {
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if sc.hang {
+			<-ctx.Done()
+		}
+	}()
+	return ch, nil
+}
+
+func (f *fakeTransport) StreamMessage(ctx context.Context, _ protocol.SendMessageParams, _ ...a2aclient.RequestOption) (<-chan protocol.StreamingMessageEvent, error) {
+	return f.next(ctx, "stream")
+}
+
+func (f *fakeTransport) ResubscribeTask(ctx context.Context, params protocol.TaskIDParams, _ ...a2aclient.RequestOption) (<-chan protocol.StreamingMessageEvent, error) {
+	f.mu.Lock()
+	f.resubscribe = append(f.resubscribe, params.ID)
+	f.mu.Unlock()
+	return f.next(ctx, "resubscribe")
+}
+
+func (f *fakeTransport) GetTasks(_ context.Context, params protocol.TaskQueryParams, _ ...a2aclient.RequestOption) (*protocol.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "get")
+	if f.task == nil || f.task.ID != params.ID {
+		return nil, errors.New("task not found")
+	}
+	return f.task, nil
+}
+
+func status(taskID string, state protocol.TaskState, final bool) protocol.StreamingMessageEvent {
+	return protocol.StreamingMessageEvent{Result: &protocol.TaskStatusUpdateEvent{
+		Kind: protocol.KindTaskStatusUpdate, TaskID: taskID, Status: protocol.TaskStatus{State: state}, Final: final,
+	}}
+}
+
+func artifact(taskID, id string) protocol.StreamingMessageEvent {
+	last := true
+	return protocol.StreamingMessageEvent{Result: &protocol.TaskArtifactUpdateEvent{
+		Kind: protocol.KindTaskArtifactUpdate, TaskID: taskID, LastChunk: &last,
+		Artifact: protocol.Artifact{ArtifactID: id, Parts: []protocol.Part{protocol.NewTextPart(id)}},
+	}}
+}
+
+var fast = Options{Backoff: time.Millisecond, ConnectTimeout: time.Second}
+
+func drain(t *testing.T, s *Stream) []protocol.StreamingMessageEvent {
+	t.Helper()
+	var out []protocol.StreamingMessageEvent
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev, ok := <-s.Events():
+			if !ok {
+				return out
+			}
+			out = append(out, ev)
+		case <-timeout:
+			t.Fatal("stream did not close")
+		}
+	}
+}
+
+func send(t *testing.T, c *Client) *Stream {
+	t.Helper()
+	s, err := c.Stream(context.Background(), protocol.SendMessageParams{
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestStream_Completes(t *testing.T) {
+	f := &fakeTransport{streams: []*script{{events: []protocol.StreamingMessageEvent{
+		status("t1", protocol.TaskStateWorking, false),
+		artifact("t1", "a1"),
+		status("t1", protocol.TaskStateCompleted, true),
+	}, hang: true}}}
+	s := send(t, newClient("http://agent", f, fast))
+
+	require.Len(t, drain(t, s), 3)
+	require.NoError(t, s.Err())
+	require.Zero(t, s.Reconnects())
+	require.Equal(t, []string{"stream"}, f.calls)
+}
+
+func TestStream_ResubscribesAfterDrop(t *testing.T) {
+	f := &fakeTransport{streams: []*script{
+		{events: []protocol.StreamingMessageEvent{status("t1", protocol.TaskStateWorking, false), artifact("t1", "a1")}},
+		{events: []protocol.StreamingMessageEvent{
+			artifact("t1", "a1"), // replayed by the agent: not delivered twice
+			artifact("t1", "a2"),
+			status("t1", protocol.TaskStateCompleted, true),
+		}},
+	}}
+	s := send(t, newClient("http://agent", f, fast))
+
+	events := drain(t, s)
+	require.NoError(t, s.Err())
+	require.Equal(t, 1, s.Reconnects())
+	require.Len(t, events, 4)
+	require.Equal(t, "a2", events[2].Result.(*protocol.TaskArtifactUpdateEvent).Artifact.ArtifactID)
+	require.Equal(t, []string{"stream", "get", "resubscribe"}, f.calls)
+	require.Equal(t, []string{"t1"}, f.resubscribe)
+}
+
+func TestStream_DeliversMissedResultsOfFinishedTask(t *testing.T) {
+	f := &fakeTransport{
+		streams: []*script{{events: []protocol.StreamingMessageEvent{status("t1", protocol.TaskStateWorking, false), artifact("t1", "a1")}}},
+		task: &protocol.Task{ID: "t1", Status: protocol.TaskStatus{State: protocol.TaskStateCompleted}, Artifacts: []protocol.Artifact{
+			{ArtifactID: "a1"}, {ArtifactID: "a2"},
+		}},
+	}
+	s := send(t, newClient("http://agent", f, fast))
+
+	events := drain(t, s)
+	require.NoError(t, s.Err())
+	require.Len(t, events, 3)
+	task := events[2].Result.(*protocol.Task)
+	require.Equal(t, []protocol.Artifact{{ArtifactID: "a2"}}, task.Artifacts, "only results the caller has not seen")
+	require.Len(t, f.task.Artifacts, 2, "the fetched task is not modified")
+}
+
+func TestStream_IdleTimeoutReconnects(t *testing.T) {
+	opts := fast
+	opts.IdleTimeout = 20 * time.Millisecond
+	f := &fakeTransport{streams: []*script{
+		{events: []protocol.StreamingMessageEvent{status("t1", protocol.TaskStateWorking, false)}, hang: true},
+		{events: []protocol.StreamingMessageEvent{status("t1", protocol.TaskStateCompleted, true)}},
+	}}
+	s := send(t, newClient("http://agent", f, opts))
+
+	require.Len(t, drain(t, s), 2)
+	require.NoError(t, s.Err())
+	require.Equal(t, 1, s.Reconnects())
+}
+
+func TestStream_GivesUp(t *testing.T) {
+	opts := fast
+	opts.MaxReconnects = 2
+	f := &fakeTransport{streams: []*script{
+		{events: []protocol.StreamingMessageEvent{status("t1", protocol.TaskStateWorking, false)}},
+	}}
+	s := send(t, newClient("http://agent", f, opts))
+
+	drain(t, s)
+	require.ErrorIs(t, s.Err(), ErrInterrupted)
+	require.Equal(t, 2, s.Reconnects())
+	require.Equal(t, []string{"stream", "get", "resubscribe", "get", "resubscribe"}, f.calls)
+}
+
+func TestStream_CannotResumeWithoutTask(t *testing.T) {
+	f := &fakeTransport{streams: []*script{{}}}
+	s := send(t, newClient("http://agent", f, fast))
+
+	drain(t, s)
+	require.ErrorIs(t, s.Err(), ErrInterrupted)
+	require.Equal(t, []string{"stream"}, f.calls)
+}
+
+func TestStream_ConnectFailure(t *testing.T) {
+	c := newClient("http://agent", &fakeTransport{}, fast)
+	_, err := c.Stream(context.Background(), protocol.SendMessageParams{})
+	require.ErrorContains(t, err, "connect to agent at http://agent")
+}
+
+func TestStream_TurnTimeout(t *testing.T) {
+	opts := fast
+	opts.Timeout = 20 * time.Millisecond
+	f := &fakeTransport{streams: []*script{{events: []protocol.StreamingMessageEvent{status("t1", protocol.TaskStateWorking, false)}, hang: true}}}
+	s := send(t, newClient("http://agent", f, opts))
+
+	drain(t, s)
+	require.ErrorIs(t, s.Err(), context.DeadlineExceeded)
+	require.ErrorContains(t, s.Err(), "did not finish within 20ms")
+}
+
+func TestPool_ReusesClients(t *testing.T) {
+	p := NewPool(Options{})
+	a, err := p.Client("http://agent-a")
+	require.NoError(t, err)
+	again, err := p.Client("http://agent-a")
+	require.NoError(t, err)
+	b, err := p.Client("http://agent-b")
+	require.NoError(t, err)
+
+	require.Same(t, a, again)
+	require.NotSame(t, a, b)
+	require.Equal(t, "http://agent-b", b.URL())
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/common/workspace"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+// NewCleanCommand returns `arctl clean`, which purges the workspaces arctl
+// commands leave under the workspace root.
+func NewCleanCommand() *cobra.Command {
+	var all bool
+	cmd := &cobra.Command{
+		Use:   cliruntime.CommandClean,
+		Short: "Remove leftover build workspaces",
+		Long: `Clean removes the scratch workspaces arctl commands build in, such as
+the staged copy of the built-in frameworks, from ~/.arctl/work (or
+` + workspace.EnvDir + `).
+
+By default only stale workspaces go: those whose command has exited or
+crashed, or that are older than a day. arctl also sweeps these whenever it
+creates a new workspace. --all removes every workspace, including those of
+commands still running, which may make them fail.`,
+		Example: `  arctl clean
+  arctl clean --all`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			root := workspace.Root()
+			var (
+				removed []string
+				err     error
+			)
+			if all {
+				removed, err = workspace.Purge(root)
+			} else {
+				removed, err = workspace.Sweep(root, time.Now())
+			}
+			out := cmd.OutOrStdout()
+			for _, path := range removed {
+				fmt.Fprintf(out, "removed %s\n", path)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Removed %d workspace(s) from %s\n", len(removed), root)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "Also remove the workspaces of running commands")
+	return cmd
+}
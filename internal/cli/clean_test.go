@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/common/workspace"
+)
+
+func TestCleanCommand(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(workspace.EnvDir, root)
+
+	live, err := workspace.New("frameworks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	crashed := filepath.Join(root, "frameworks-crashed")
+	if err := os.Mkdir(crashed, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	host, _ := os.Hostname()
+	lock, _ := json.Marshal(workspace.Lock{PID: 1 << 30, Host: host, Created: time.Now()})
+	if err := os.WriteFile(crashed+".lock", lock, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) string {
+		cmd := NewCleanCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("clean %v: %v", args, err)
+		}
+		return out.String()
+	}
+
+	out := run()
+	if !strings.Contains(out, "removed "+crashed) || !strings.Contains(out, "Removed 1 workspace(s)") {
+		t.Errorf("clean output:\n%s", out)
+	}
+	if _, err := os.Stat(live.Dir); err != nil {
+		t.Errorf("clean removed the workspace of a running command: %v", err)
+	}
+
+	out = run("--all")
+	if !strings.Contains(out, "removed "+live.Dir) {
+		t.Errorf("clean --all output:\n%s", out)
+	}
+}
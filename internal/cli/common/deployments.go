@@ -21,19 +21,23 @@ type DeploymentRecord struct {
 	Name      string `json:"name"`
 	ID        string `json:"id"`
 
-	TargetName        string            `json:"serverName"`
-	TargetTag         string            `json:"targetTag,omitempty"`
-	ResourceType      string            `json:"resourceType"`
-	RuntimeID         string            `json:"runtimeId,omitempty"`
-	Status            string            `json:"status"`
-	Origin            string            `json:"origin"`
-	Env               map[string]string `json:"env,omitempty"`
-	RuntimeConfig     map[string]any    `json:"runtimeConfig,omitempty"`
-	RuntimeMetadata   map[string]any    `json:"runtimeMetadata,omitempty"`
-	Error             string            `json:"error,omitempty"`
-	CreatedAt         time.Time         `json:"deployedAt,omitempty"`
-	UpdatedAt         time.Time         `json:"updatedAt,omitempty"`
-	DeletionTimestamp *time.Time        `json:"deletionTimestamp,omitempty"`
+	TargetName      string            `json:"serverName"`
+	TargetTag       string            `json:"targetTag,omitempty"`
+	ResourceType    string            `json:"resourceType"`
+	RuntimeID       string            `json:"runtimeId,omitempty"`
+	Status          string            `json:"status"`
+	Origin          string            `json:"origin"`
+	Env             map[string]string `json:"env,omitempty"`
+	RuntimeConfig   map[string]any    `json:"runtimeConfig,omitempty"`
+	RuntimeMetadata map[string]any    `json:"runtimeMetadata,omitempty"`
+	CostAllocation  map[string]string `json:"costAllocation,omitempty"`
+	// ResolvedTags are the tags the target Agent's version-constraint refs
+	// resolved to when the Deployment was last applied.
+	ResolvedTags      []v1alpha1.ResolvedTag `json:"resolvedTags,omitempty"`
+	Error             string                 `json:"error,omitempty"`
+	CreatedAt         time.Time              `json:"deployedAt,omitempty"`
+	UpdatedAt         time.Time              `json:"updatedAt,omitempty"`
+	DeletionTimestamp *time.Time             `json:"deletionTimestamp,omitempty"`
 
 	// Conditions is the raw v1alpha1.Status.Conditions list as reported by
 	// reconcilers. Surfaced alongside the derived Status phase so YAML/JSON
@@ -110,6 +114,8 @@ func DeploymentRecordFromObject(dep *v1alpha1.Deployment) *DeploymentRecord {
 		Env:               cloneStringMap(dep.Spec.Env),
 		RuntimeConfig:     cloneAnyMap(dep.Spec.RuntimeConfig),
 		RuntimeMetadata:   deploymentRuntimeMetadata(dep.Metadata.Annotations),
+		CostAllocation:    deploymentCostAllocation(dep.Metadata.Annotations),
+		ResolvedTags:      deploymentResolvedTags(dep.Metadata.Annotations),
 		Error:             deploymentError(dep.Status),
 		CreatedAt:         dep.Metadata.CreatedAt,
 		UpdatedAt:         dep.Metadata.UpdatedAt,
@@ -153,6 +159,8 @@ func DeploymentResourceName(targetName, runtimeID string) string {
 }
 
 // DeploymentStatus derives the old CLI phase strings from v1alpha1 conditions.
+// v1alpha1store.SortByDeploymentStatus derives the same phases in SQL; keep
+// the two in step.
 func DeploymentStatus(dep *v1alpha1.Deployment) string {
 	if dep == nil {
 		return "unknown"
@@ -212,6 +220,34 @@ func deploymentRuntimeMetadata(annotations map[string]string) map[string]any {
 	return out
 }
 
+// deploymentCostAllocation decodes the cost-allocation tags the runtime
+// adapter recorded; a malformed annotation is ignored.
+func deploymentCostAllocation(annotations map[string]string) map[string]string {
+	raw := annotations[v1alpha1.DeploymentCostAllocationAnnotation]
+	if raw == "" {
+		return nil
+	}
+	var tags map[string]string
+	if err := json.Unmarshal([]byte(raw), &tags); err != nil {
+		return nil
+	}
+	return tags
+}
+
+// deploymentResolvedTags decodes the tags the controller pinned; a
+// malformed annotation is ignored.
+func deploymentResolvedTags(annotations map[string]string) []v1alpha1.ResolvedTag {
+	raw := annotations[v1alpha1.DeploymentResolvedTagsAnnotation]
+	if raw == "" {
+		return nil
+	}
+	var resolved []v1alpha1.ResolvedTag
+	if err := json.Unmarshal([]byte(raw), &resolved); err != nil {
+		return nil
+	}
+	return resolved
+}
+
 func deploymentResourceType(kind string) string {
 	switch kind {
 	case v1alpha1.KindMCPServer:
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -162,3 +163,51 @@ func (e *Executor) RemoveContainer(containerID string) error {
 	}
 	return nil
 }
+
+// ServerVersion returns the docker daemon version, failing when the CLI is
+// missing or the daemon is unreachable.
+func ServerVersion() (string, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return "", fmt.Errorf("docker command not found in PATH")
+	}
+	out, err := exec.Command("docker", "version", "--format", "{{.Server.Version}}").Output()
+	if err != nil {
+		return "", fmt.Errorf("docker daemon is not running or not accessible")
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ComposeVersion returns the version of the compose invocation
+// ComposeCommand selects.
+func ComposeVersion() (string, error) {
+	compose := ComposeCommand()
+	args := append(compose[1:], "version", "--short")
+	out, err := exec.Command(compose[0], args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("neither docker compose nor docker-compose is available")
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// NVIDIARuntime reports whether the docker daemon can hand NVIDIA GPUs to
+// containers, i.e. the NVIDIA container toolkit has registered its
+// runtime. It returns the nvidia-ctk version when the binary is on PATH.
+func NVIDIARuntime() (string, error) {
+	out, err := exec.Command("docker", "info", "--format", "{{json .Runtimes}}").Output()
+	if err != nil {
+		return "", fmt.Errorf("query docker runtimes: %w", err)
+	}
+	var runtimes map[string]json.RawMessage
+	if err := json.Unmarshal(out, &runtimes); err != nil {
+		return "", fmt.Errorf("decode docker runtimes: %w", err)
+	}
+	if _, ok := runtimes["nvidia"]; !ok {
+		return "", fmt.Errorf("docker has no nvidia runtime registered")
+	}
+	version, err := exec.Command("nvidia-ctk", "--version").Output()
+	if err != nil {
+		return "nvidia runtime registered", nil
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(string(version)), "\n")
+	return first, nil
+}
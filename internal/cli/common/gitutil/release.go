@@ -0,0 +1,177 @@
+package gitutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrDirtyWorkTree is returned when the work tree has uncommitted or
+	// untracked changes, so HEAD does not describe what would be built.
+	ErrDirtyWorkTree = errors.New("work tree is not clean")
+	// ErrNoReleaseTag is returned when HEAD carries no vX.Y.Z tag.
+	ErrNoReleaseTag = errors.New("no release tag on HEAD")
+	// ErrUnsignedTag is returned when a release tag is required to be
+	// signed and its signature is missing or does not verify.
+	ErrUnsignedTag = errors.New("release tag is not signed")
+)
+
+// releaseTagRegex matches release tags: v1.2.3, optionally with a
+// pre-release suffix such as v1.2.3-rc.1. The version is the tag without
+// its leading "v".
+var releaseTagRegex = regexp.MustCompile(`^v(\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?)$`)
+
+// ReleaseOptions controls ResolveRelease.
+type ReleaseOptions struct {
+	// Remote names the git remote the repository name is derived from.
+	// Empty means "origin".
+	Remote string
+	// RequireSignedTag fails unless `git verify-tag` accepts the release
+	// tag.
+	RequireSignedTag bool
+}
+
+// Release describes the release checked out in a work tree.
+type Release struct {
+	// Name is the repository name, lowercased, e.g. "weather-agent" for
+	// git@github.com:acme/Weather_Agent.git.
+	Name string
+	// Version is Tag without its leading "v", e.g. "1.2.3".
+	Version string
+	Tag     string
+	Commit  string
+	// RemoteURL is the remote's URL as configured.
+	RemoteURL string
+	// Subfolder is the directory the release was resolved from, relative
+	// to the repository root. Empty at the root.
+	Subfolder string
+}
+
+// ResolveRelease derives the release checked out in the git work tree
+// containing dir: the name comes from the remote's repository and the
+// version from the single vX.Y.Z tag on HEAD. The work tree must be clean.
+func ResolveRelease(ctx context.Context, dir string, opts ReleaseOptions) (*Release, error) {
+	remote := opts.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+	if err := safeGitRef(remote); err != nil {
+		return nil, err
+	}
+
+	root, err := git(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git work tree: %w", dir, err)
+	}
+	status, err := git(ctx, dir, "status", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("git status: %w", err)
+	}
+	if status != "" {
+		return nil, fmt.Errorf("%w:\n%s", ErrDirtyWorkTree, status)
+	}
+	commit, err := git(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("resolve HEAD: %w", err)
+	}
+
+	tags, err := git(ctx, dir, "tag", "--points-at", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("list tags on HEAD: %w", err)
+	}
+	var releaseTags []string
+	for _, tag := range strings.Fields(tags) {
+		if releaseTagRegex.MatchString(tag) {
+			releaseTags = append(releaseTags, tag)
+		}
+	}
+	switch len(releaseTags) {
+	case 0:
+		return nil, fmt.Errorf("%w (want a tag like v1.2.3 on %s)", ErrNoReleaseTag, shortCommit(commit))
+	case 1:
+	default:
+		return nil, fmt.Errorf("HEAD has several release tags (%s); keep one", strings.Join(releaseTags, ", "))
+	}
+	tag := releaseTags[0]
+	if opts.RequireSignedTag {
+		if _, err := git(ctx, dir, "verify-tag", tag); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrUnsignedTag, tag, err)
+		}
+	}
+
+	remoteURL, err := git(ctx, dir, "remote", "get-url", remote)
+	if err != nil {
+		return nil, fmt.Errorf("read remote %q: %w", remote, err)
+	}
+	name, err := RepositoryName(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	subfolder := ""
+	if abs, err := filepath.Abs(dir); err == nil {
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			abs = resolved
+		}
+		if rel, err := filepath.Rel(root, abs); err == nil && rel != "." {
+			subfolder = filepath.ToSlash(rel)
+		}
+	}
+
+	return &Release{
+		Name:      name,
+		Version:   releaseTagRegex.FindStringSubmatch(tag)[1],
+		Tag:       tag,
+		Commit:    commit,
+		RemoteURL: remoteURL,
+		Subfolder: subfolder,
+	}, nil
+}
+
+// RepositoryName returns the repository name of a git remote URL,
+// lowercased with underscores turned into hyphens so it is a valid
+// resource name. Both URL (https://host/owner/repo.git) and scp-like
+// (git@host:owner/repo.git) forms are accepted.
+func RepositoryName(remoteURL string) (string, error) {
+	p := remoteURL
+	if u, err := url.Parse(remoteURL); err == nil && u.Scheme != "" {
+		p = u.Path
+	} else if _, rest, ok := strings.Cut(remoteURL, ":"); ok {
+		p = rest
+	}
+	name := strings.TrimSuffix(path.Base(strings.TrimRight(p, "/")), ".git")
+	if name == "" || name == "." || name == "/" {
+		return "", fmt.Errorf("cannot derive a repository name from remote %q", remoteURL)
+	}
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-"), nil
+}
+
+// git runs a git subcommand in dir and returns its trimmed stdout.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
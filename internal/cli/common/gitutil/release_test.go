@@ -0,0 +1,101 @@
+package gitutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// initRepo creates a git repository with one commit and an origin remote.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=ci", "-c", "user.email=ci@example.com", "-c", "tag.gpgSign=false", "-c", "commit.gpgSign=false"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	if err := os.MkdirAll(filepath.Join(dir, "agents", "weather"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "agents", "weather", "agent.yaml"), []byte("kind: Agent\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "-A")
+	run("commit", "-q", "-m", "initial")
+	run("remote", "add", "origin", "git@github.com:acme/Weather_Agent.git")
+	run("tag", "-a", "v1.2.3", "-m", "release 1.2.3")
+	return dir
+}
+
+func TestResolveRelease(t *testing.T) {
+	ctx := context.Background()
+	dir := initRepo(t)
+
+	rel, err := ResolveRelease(ctx, filepath.Join(dir, "agents", "weather"), ReleaseOptions{})
+	if err != nil {
+		t.Fatalf("ResolveRelease: %v", err)
+	}
+	if rel.Name != "weather-agent" || rel.Version != "1.2.3" || rel.Tag != "v1.2.3" {
+		t.Errorf("release = %+v, want weather-agent 1.2.3 (v1.2.3)", rel)
+	}
+	if rel.Subfolder != "agents/weather" {
+		t.Errorf("Subfolder = %q, want agents/weather", rel.Subfolder)
+	}
+	if len(rel.Commit) != 40 {
+		t.Errorf("Commit = %q, want a full SHA", rel.Commit)
+	}
+
+	// The annotated tag is unsigned, so requiring a signature fails.
+	if _, err := ResolveRelease(ctx, dir, ReleaseOptions{RequireSignedTag: true}); !errors.Is(err, ErrUnsignedTag) {
+		t.Errorf("RequireSignedTag error = %v, want ErrUnsignedTag", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "scratch.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ResolveRelease(ctx, dir, ReleaseOptions{}); !errors.Is(err, ErrDirtyWorkTree) {
+		t.Errorf("dirty tree error = %v, want ErrDirtyWorkTree", err)
+	}
+}
+
+func TestResolveRelease_NoReleaseTag(t *testing.T) {
+	dir := initRepo(t)
+	if out, err := exec.Command("git", "-C", dir, "tag", "-d", "v1.2.3").CombinedOutput(); err != nil {
+		t.Fatalf("delete tag: %v\n%s", err, out)
+	}
+	if out, err := exec.Command("git", "-C", dir, "tag", "nightly").CombinedOutput(); err != nil {
+		t.Fatalf("tag nightly: %v\n%s", err, out)
+	}
+	if _, err := ResolveRelease(context.Background(), dir, ReleaseOptions{}); !errors.Is(err, ErrNoReleaseTag) {
+		t.Errorf("error = %v, want ErrNoReleaseTag", err)
+	}
+}
+
+func TestRepositoryName(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/acme/weather-agent.git": "weather-agent",
+		"https://github.com/acme/weather-agent":     "weather-agent",
+		"git@github.com:acme/Weather_Agent.git":     "weather-agent",
+		"ssh://git@gitlab.acme.dev/team/sub/bot/":   "bot",
+		"/srv/git/bot.git":                          "bot",
+	}
+	for remote, want := range tests {
+		got, err := RepositoryName(remote)
+		if err != nil || got != want {
+			t.Errorf("RepositoryName(%q) = %q, %v; want %q", remote, got, err, want)
+		}
+	}
+	if _, err := RepositoryName(""); err == nil {
+		t.Error("RepositoryName(\"\") succeeded, want error")
+	}
+}
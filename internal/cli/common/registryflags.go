@@ -6,11 +6,11 @@ import (
 
 // registryFlagNames lists the root-level persistent flags that are irrelevant
 // for commands that operate purely offline (e.g. init, build, add-tool).
-var registryFlagNames = []string{"registry-url", "registry-token"}
+var registryFlagNames = []string{"registry-url", "registry-token", "context"}
 
-// HideRegistryFlags marks the inherited registry-url and registry-token flags
-// as hidden so they do not appear in the --help output of commands that do not
-// interact with the registry. Multiple commands can be passed at once.
+// HideRegistryFlags marks the inherited registry-url, registry-token and
+// context flags as hidden so they do not appear in the --help output of
+// commands that do not interact with the registry. Multiple commands can be passed at once.
 func HideRegistryFlags(cmds ...*cobra.Command) {
 	for _, cmd := range cmds {
 		original := cmd.HelpFunc()
@@ -0,0 +1,187 @@
+// Package tunnel opens public tunnels to local ports through an external
+// client such as cloudflared or ngrok. Providers are pluggable: Register adds
+// one under a name `arctl deployment expose --provider` can select.
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStartTimeout bounds how long a provider may take to report its
+// public URL.
+const DefaultStartTimeout = 30 * time.Second
+
+// Provider starts tunnels to local URLs.
+type Provider interface {
+	// Start opens a tunnel to localURL and returns once the public URL is
+	// known. The tunnel stays up until Close or until ctx is cancelled.
+	Start(ctx context.Context, localURL string) (*Tunnel, error)
+}
+
+// Tunnel is a running tunnel.
+type Tunnel struct {
+	// PublicURL is the provider-assigned URL, without a trailing slash.
+	PublicURL string
+
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error
+}
+
+// Done is closed when the tunnel process exits.
+func (t *Tunnel) Done() <-chan struct{} { return t.done }
+
+// Err reports why the tunnel process exited. Valid after Done is closed.
+func (t *Tunnel) Err() error { return t.err }
+
+// Close stops the tunnel process and waits for it to exit.
+func (t *Tunnel) Close() error {
+	select {
+	case <-t.done:
+		return nil
+	default:
+	}
+	if t.cmd.Process != nil {
+		_ = t.cmd.Process.Kill()
+	}
+	<-t.done
+	return nil
+}
+
+// CommandProvider runs a tunnel client binary and scrapes the public URL
+// from its output.
+type CommandProvider struct {
+	// Binary is the executable to run, looked up on PATH.
+	Binary string
+	// Args returns the arguments that tunnel localURL.
+	Args func(localURL string) []string
+	// URLPattern matches the public URL in the client's stdout or stderr;
+	// its first capture group, when present, is the URL.
+	URLPattern *regexp.Regexp
+	// StartTimeout overrides DefaultStartTimeout.
+	StartTimeout time.Duration
+}
+
+// Start implements Provider.
+func (p CommandProvider) Start(ctx context.Context, localURL string) (*Tunnel, error) {
+	if _, err := exec.LookPath(p.Binary); err != nil {
+		return nil, fmt.Errorf("%s not found in PATH", p.Binary)
+	}
+	cmd := exec.CommandContext(ctx, p.Binary, p.Args(localURL)...)
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	// Children of the client can hold the pipe open after it is killed;
+	// don't wait on them.
+	cmd.WaitDelay = time.Second
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %s: %w", p.Binary, err)
+	}
+	t := &Tunnel{cmd: cmd, done: make(chan struct{})}
+	go func() {
+		t.err = cmd.Wait()
+		_ = pw.Close()
+		close(t.done)
+	}()
+
+	found := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			m := p.URLPattern.FindStringSubmatch(scanner.Text())
+			if m == nil {
+				continue
+			}
+			url := m[0]
+			if len(m) > 1 {
+				url = m[1]
+			}
+			select {
+			case found <- url:
+			default:
+			}
+		}
+		// Keep draining so the client never blocks on a full pipe.
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+
+	timeout := p.StartTimeout
+	if timeout == 0 {
+		timeout = DefaultStartTimeout
+	}
+	select {
+	case url := <-found:
+		t.PublicURL = strings.TrimRight(url, "/")
+		return t, nil
+	case <-t.done:
+		return nil, fmt.Errorf("%s exited before reporting a public URL: %v", p.Binary, t.err)
+	case <-time.After(timeout):
+		_ = t.Close()
+		return nil, fmt.Errorf("%s did not report a public URL within %s", p.Binary, timeout)
+	case <-ctx.Done():
+		_ = t.Close()
+		return nil, ctx.Err()
+	}
+}
+
+var (
+	mu        sync.RWMutex
+	providers = map[string]Provider{
+		"cloudflared": CommandProvider{
+			Binary: "cloudflared",
+			Args: func(localURL string) []string {
+				return []string{"tunnel", "--no-autoupdate", "--url", localURL}
+			},
+			URLPattern: regexp.MustCompile(`https://[a-z0-9-]+\.trycloudflare\.com`),
+		},
+		"ngrok": CommandProvider{
+			Binary: "ngrok",
+			Args: func(localURL string) []string {
+				return []string{"http", localURL, "--log", "stdout", "--log-format", "logfmt"}
+			},
+			URLPattern: regexp.MustCompile(`url=(https://\S+)`),
+		},
+	}
+)
+
+// Register adds or replaces the provider selected by name.
+func Register(name string, p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+	providers[name] = p
+}
+
+// Lookup returns the provider registered under name.
+func Lookup(name string) (Provider, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if p, ok := providers[name]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("unknown tunnel provider %q (available: %v)", name, namesLocked())
+}
+
+// Names lists the registered providers, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return namesLocked()
+}
+
+func namesLocked() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,54 @@
+package tunnel
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func shellProvider(script string) CommandProvider {
+	return CommandProvider{
+		Binary:       "sh",
+		Args:         func(string) []string { return []string{"-c", script} },
+		URLPattern:   regexp.MustCompile(`url=(https://\S+)`),
+		StartTimeout: 5 * time.Second,
+	}
+}
+
+func TestCommandProvider_ScrapesPublicURL(t *testing.T) {
+	p := shellProvider(`echo "starting" >&2; echo "t=1 url=https://abc.example.test/"; sleep 30`)
+	tun, err := p.Start(context.Background(), "http://localhost:21212")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if tun.PublicURL != "https://abc.example.test" {
+		t.Errorf("PublicURL = %q", tun.PublicURL)
+	}
+	if err := tun.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case <-tun.Done():
+	default:
+		t.Fatal("Done not closed after Close")
+	}
+}
+
+func TestCommandProvider_ExitBeforeURL(t *testing.T) {
+	p := shellProvider(`echo "auth token missing" >&2; exit 1`)
+	if _, err := p.Start(context.Background(), "http://localhost:21212"); err == nil {
+		t.Fatal("expected an error when the client exits without a URL")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"cloudflared", "ngrok"} {
+		if _, err := Lookup(name); err != nil {
+			t.Errorf("Lookup(%q): %v", name, err)
+		}
+	}
+	if _, err := Lookup("carrier-pigeon"); err == nil {
+		t.Error("Lookup of an unknown provider should fail")
+	}
+}
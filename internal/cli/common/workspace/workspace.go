@@ -0,0 +1,188 @@
+// Package workspace manages the scratch directories arctl commands work in,
+// such as the staged copy of the embedded frameworks, under one root
+// (~/.arctl/work, or ARCTL_WORK_DIR) instead of the system temp dir.
+//
+// Every workspace <root>/<name> has a lockfile <root>/<name>.lock naming the
+// process that owns it. A workspace whose owner is gone, because it exited
+// without closing it or crashed, is stale: the next New sweeps stale
+// workspaces away, and `arctl clean` purges them on demand.
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// EnvDir overrides the workspace root.
+const EnvDir = "ARCTL_WORK_DIR"
+
+// MaxAge is how long a workspace may live even when its owner still looks
+// alive, which bounds leaks through PID reuse.
+const MaxAge = 24 * time.Hour
+
+// unlockedGrace spares a directory without a lockfile for a while, since
+// New creates the directory just before it writes the lock.
+const unlockedGrace = time.Minute
+
+const lockSuffix = ".lock"
+
+// Root returns the workspace root: ARCTL_WORK_DIR when set, else
+// ~/.arctl/work, else arctl-work in the system temp dir.
+func Root() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		return filepath.Join(home, ".arctl", "work")
+	}
+	return filepath.Join(os.TempDir(), "arctl-work")
+}
+
+// Lock is the content of a workspace lockfile.
+type Lock struct {
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Created time.Time `json:"created"`
+}
+
+// Workspace is a directory owned by this process until Close.
+type Workspace struct {
+	// Dir is the workspace directory.
+	Dir string
+}
+
+// New sweeps stale workspaces from Root and creates a fresh workspace there
+// whose name starts with prefix.
+func New(prefix string) (*Workspace, error) {
+	return NewIn(Root(), prefix)
+}
+
+// NewIn is New with an explicit root.
+func NewIn(root, prefix string) (*Workspace, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("create workspace root %s: %w", root, err)
+	}
+	// A failed sweep only leaves stale workspaces for the next one.
+	_, _ = Sweep(root, time.Now())
+
+	dir, err := os.MkdirTemp(root, prefix+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("create workspace: %w", err)
+	}
+	host, _ := os.Hostname()
+	data, err := json.Marshal(Lock{PID: os.Getpid(), Host: host, Created: time.Now().UTC()})
+	if err == nil {
+		err = os.WriteFile(dir+lockSuffix, data, 0o644)
+	}
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("lock workspace %s: %w", dir, err)
+	}
+	return &Workspace{Dir: dir}, nil
+}
+
+// Close removes the workspace and its lockfile.
+func (w *Workspace) Close() error {
+	if err := os.RemoveAll(w.Dir); err != nil {
+		return err
+	}
+	if err := os.Remove(w.Dir + lockSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Sweep removes the stale workspaces under root and returns their paths.
+func Sweep(root string, now time.Time) ([]string, error) {
+	return remove(root, func(path string) bool { return stale(path, now) })
+}
+
+// Purge removes every workspace under root, stale or not, and returns
+// their paths. Workspaces of running commands go too, so those commands
+// may fail.
+func Purge(root string) ([]string, error) {
+	return remove(root, func(string) bool { return true })
+}
+
+func remove(root string, match func(path string) bool) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read workspace root %s: %w", root, err)
+	}
+	var removed []string
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			// Lockfiles whose workspace is gone.
+			name, ok := strings.CutSuffix(entry.Name(), lockSuffix)
+			if !ok {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(root, name)); errors.Is(err, fs.ErrNotExist) {
+				_ = os.Remove(filepath.Join(root, entry.Name()))
+			}
+			continue
+		}
+		path := filepath.Join(root, entry.Name())
+		if !match(path) {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_ = os.Remove(path + lockSuffix)
+		removed = append(removed, path)
+	}
+	return removed, errors.Join(errs...)
+}
+
+// stale reports whether the workspace at path has outlived its owner.
+func stale(path string, now time.Time) bool {
+	data, err := os.ReadFile(path + lockSuffix)
+	if err != nil {
+		info, statErr := os.Stat(path)
+		return statErr == nil && now.Sub(info.ModTime()) > unlockedGrace
+	}
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return true
+	}
+	if now.Sub(lock.Created) > MaxAge {
+		return true
+	}
+	// Owners on other hosts sharing the root can't be probed; MaxAge
+	// bounds them.
+	if host, _ := os.Hostname(); lock.Host != host {
+		return false
+	}
+	return !processAlive(lock.PID)
+}
+
+// processAlive reports whether pid is running. Windows can't probe a
+// process without opening it, so there MaxAge alone retires workspaces.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	if goruntime.GOOS == "windows" {
+		return true
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = proc.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,78 @@
+package workspace_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/common/workspace"
+)
+
+// deadPID is above every kernel's pid_max, so no process has it.
+const deadPID = 1 << 30
+
+func writeWorkspace(t *testing.T, root, name string, lock *workspace.Lock) string {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "adk"), 0o755))
+	if lock != nil {
+		data, err := json.Marshal(lock)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(dir+".lock", data, 0o644))
+	}
+	return dir
+}
+
+func TestNewAndClose(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "work")
+	ws, err := workspace.NewIn(root, "frameworks")
+	require.NoError(t, err)
+	require.DirExists(t, ws.Dir)
+	require.Equal(t, root, filepath.Dir(ws.Dir))
+	require.FileExists(t, ws.Dir+".lock")
+
+	removed, err := workspace.Sweep(root, time.Now())
+	require.NoError(t, err)
+	require.Empty(t, removed, "a workspace of this process is not stale")
+
+	require.NoError(t, ws.Close())
+	require.NoDirExists(t, ws.Dir)
+	require.NoFileExists(t, ws.Dir+".lock")
+}
+
+func TestSweep(t *testing.T) {
+	root := t.TempDir()
+	host, _ := os.Hostname()
+	now := time.Now()
+	live := writeWorkspace(t, root, "live", &workspace.Lock{PID: os.Getpid(), Host: host, Created: now})
+	crashed := writeWorkspace(t, root, "crashed", &workspace.Lock{PID: deadPID, Host: host, Created: now})
+	expired := writeWorkspace(t, root, "expired", &workspace.Lock{PID: os.Getpid(), Host: host, Created: now.Add(-workspace.MaxAge - time.Minute)})
+	remote := writeWorkspace(t, root, "remote", &workspace.Lock{PID: deadPID, Host: host + "-elsewhere", Created: now})
+	unlocked := writeWorkspace(t, root, "unlocked", nil)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "orphan.lock"), []byte("{}"), 0o644))
+
+	removed, err := workspace.Sweep(root, now)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{crashed, expired}, removed)
+	require.DirExists(t, live)
+	require.DirExists(t, remote, "owners on other hosts are only retired by age")
+	require.DirExists(t, unlocked, "a workspace being created is spared")
+	require.NoFileExists(t, crashed+".lock")
+	require.NoFileExists(t, filepath.Join(root, "orphan.lock"))
+
+	removed, err = workspace.Sweep(root, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, []string{unlocked}, removed)
+
+	removed, err = workspace.Purge(root)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{live, remote}, removed)
+
+	removed, err = workspace.Sweep(filepath.Join(root, "missing"), now)
+	require.NoError(t, err)
+	require.Empty(t, removed)
+}
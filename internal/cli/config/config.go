@@ -0,0 +1,302 @@
+// Package config implements `arctl config`, which manages named contexts:
+// registries the CLI can target, each with its own URL, stored
+// credentials and default Runtime.
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+	"github.com/agentregistry-dev/agentregistry/pkg/printer"
+)
+
+// NewCommand returns the "config" command group.
+func NewCommand(deps cliruntime.Deps) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   cliruntime.CommandConfig,
+		Short: "Manage contexts for the registries arctl talks to",
+		Long: `Manage named contexts, each pointing arctl at one registry with its own
+credentials and default Runtime, so one CLI can work against dev, staging
+and prod without re-exporting environment variables.
+
+Every command targets the context named by --context or ARCTL_CONTEXT, else
+the current context set with 'arctl config use-context'. --registry-url and
+--registry-token always win; a context named with --context or ARCTL_CONTEXT
+wins over ARCTL_API_BASE_URL and ARCTL_API_TOKEN, which in turn win over the
+current context.
+
+Contexts are kept in $XDG_CONFIG_HOME/arctl/config.yaml (default
+~/.config/arctl). Tokens are kept apart in credentials.yaml, readable only
+by you.`,
+		Example: `  arctl config set-context staging --registry-url https://registry.staging.example.com --runtime staging-k8s
+  arctl config set-credentials staging --token-stdin < token.txt
+  arctl config use-context staging
+  arctl --context prod get agents`,
+	}
+	cmd.AddCommand(newGetContextsCmd(deps))
+	cmd.AddCommand(newCurrentContextCmd(deps))
+	cmd.AddCommand(newUseContextCmd(deps))
+	cmd.AddCommand(newSetContextCmd(deps))
+	cmd.AddCommand(newDeleteContextCmd(deps))
+	cmd.AddCommand(newSetCredentialsCmd(deps))
+	return cmd
+}
+
+func newGetContextsCmd(deps cliruntime.Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:          "get-contexts",
+		Short:        "List contexts",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			store := deps.Runtime.Contexts()
+			cfg, err := store.Load()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(cfg.Contexts) == 0 {
+				fmt.Fprintln(out, "No contexts found. Create one with 'arctl config set-context'.")
+				return nil
+			}
+			// Mark the context in effect, which --context may have changed
+			// from the current one.
+			var active string
+			if ctx, err := deps.Runtime.Context(); err == nil && ctx != nil {
+				active = ctx.Name
+			}
+			t := printer.NewTablePrinter(out)
+			t.SetHeaders("CURRENT", "NAME", "REGISTRY URL", "RUNTIME", "CREDENTIALS")
+			for _, ctx := range cfg.Contexts {
+				token, err := store.Token(ctx.Name)
+				if err != nil {
+					return err
+				}
+				t.AddRow(
+					marker(ctx.Name == active),
+					ctx.Name,
+					printer.EmptyValueOrDefault(ctx.RegistryURL, "-"),
+					printer.EmptyValueOrDefault(ctx.Runtime, "-"),
+					marker(token != ""),
+				)
+			}
+			return t.Render()
+		},
+	}
+}
+
+func marker(set bool) string {
+	if set {
+		return "*"
+	}
+	return ""
+}
+
+func newCurrentContextCmd(deps cliruntime.Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:          "current-context",
+		Short:        "Print the context in effect",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, err := deps.Runtime.Context()
+			if err != nil {
+				return err
+			}
+			if ctx == nil {
+				return fmt.Errorf("current context is not set")
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), ctx.Name)
+			return nil
+		},
+	}
+}
+
+func newUseContextCmd(deps cliruntime.Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:               "use-context NAME",
+		Short:             "Set the current context",
+		Args:              cobra.ExactArgs(1),
+		SilenceUsage:      true,
+		ValidArgsFunction: contextNames(deps),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := deps.Runtime.Contexts()
+			cfg, err := store.Load()
+			if err != nil {
+				return err
+			}
+			if _, ok := cfg.Lookup(args[0]); !ok {
+				return fmt.Errorf("context %q not found; create it with 'arctl config set-context %s'", args[0], args[0])
+			}
+			cfg.CurrentContext = args[0]
+			if err := store.Save(cfg); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Switched to context %q.\n", args[0])
+			return nil
+		},
+	}
+}
+
+type setContextOptions struct {
+	registryURL string
+	runtime     string
+	use         bool
+}
+
+func newSetContextCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts setContextOptions
+	cmd := &cobra.Command{
+		Use:   "set-context NAME",
+		Short: "Create or update a context",
+		Long: `Create a context, or update the fields given as flags on an existing one.
+An empty flag value clears the field.`,
+		Example: `  arctl config set-context prod --registry-url https://registry.example.com --runtime prod-k8s
+  arctl config set-context dev --registry-url localhost:12121 --use`,
+		Args:              cobra.ExactArgs(1),
+		SilenceUsage:      true,
+		ValidArgsFunction: contextNames(deps),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := deps.Runtime.Contexts()
+			cfg, err := store.Load()
+			if err != nil {
+				return err
+			}
+			ctx := cliruntime.Context{Name: args[0]}
+			existing, updated := cfg.Lookup(args[0])
+			if updated {
+				ctx = *existing
+			}
+			if cmd.Flags().Changed("registry-url") {
+				ctx.RegistryURL = opts.registryURL
+			}
+			if cmd.Flags().Changed("runtime") {
+				ctx.Runtime = opts.runtime
+			}
+			cfg.Set(ctx)
+			if opts.use {
+				cfg.CurrentContext = ctx.Name
+			}
+			if err := store.Save(cfg); err != nil {
+				return err
+			}
+			verb := "Created"
+			if updated {
+				verb = "Updated"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s context %q.\n", verb, ctx.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&opts.registryURL, "registry-url", "", "Registry URL the context targets")
+	cmd.Flags().StringVar(&opts.runtime, "runtime", "", "Runtime a Deployment is placed on when it names neither runtimeRef nor runtimeSelector")
+	cmd.Flags().BoolVar(&opts.use, "use", false, "Also make the context current")
+	return cmd
+}
+
+func newDeleteContextCmd(deps cliruntime.Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:               "delete-context NAME",
+		Short:             "Delete a context and its stored credentials",
+		Args:              cobra.ExactArgs(1),
+		SilenceUsage:      true,
+		ValidArgsFunction: contextNames(deps),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := deps.Runtime.Contexts()
+			cfg, err := store.Load()
+			if err != nil {
+				return err
+			}
+			if !cfg.Delete(args[0]) {
+				return fmt.Errorf("context %q not found", args[0])
+			}
+			if err := store.Save(cfg); err != nil {
+				return err
+			}
+			if err := store.SetToken(args[0], ""); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted context %q.\n", args[0])
+			return nil
+		},
+	}
+}
+
+type setCredentialsOptions struct {
+	token      string
+	tokenStdin bool
+}
+
+func newSetCredentialsCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts setCredentialsOptions
+	cmd := &cobra.Command{
+		Use:   "set-credentials NAME",
+		Short: "Store the bearer token for a context",
+		Long: `Store the bearer token arctl sends when targeting the context, such as an
+API key from 'arctl auth apikey create'. An empty token removes the stored
+one. Prefer --token-stdin so the token stays out of shell history.`,
+		Example:           `  arctl auth apikey create --name laptop --action read --action push | arctl config set-credentials staging --token-stdin`,
+		Args:              cobra.ExactArgs(1),
+		SilenceUsage:      true,
+		ValidArgsFunction: contextNames(deps),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.tokenStdin == cmd.Flags().Changed("token") {
+				return fmt.Errorf("exactly one of --token and --token-stdin is required")
+			}
+			store := deps.Runtime.Contexts()
+			cfg, err := store.Load()
+			if err != nil {
+				return err
+			}
+			if _, ok := cfg.Lookup(args[0]); !ok {
+				return fmt.Errorf("context %q not found; create it with 'arctl config set-context %s'", args[0], args[0])
+			}
+			token := opts.token
+			if opts.tokenStdin {
+				if token, err = readToken(cmd.InOrStdin()); err != nil {
+					return err
+				}
+			}
+			if err := store.SetToken(args[0], token); err != nil {
+				return err
+			}
+			if token == "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Removed credentials of context %q.\n", args[0])
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Stored credentials for context %q.\n", args[0])
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&opts.token, "token", "", "Bearer token")
+	cmd.Flags().BoolVar(&opts.tokenStdin, "token-stdin", false, "Read the bearer token from stdin")
+	return cmd
+}
+
+// readToken reads the first line of in.
+func readToken(in io.Reader) (string, error) {
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("reading token: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// contextNames completes the first argument with context names.
+func contextNames(deps cliruntime.Deps) cobra.CompletionFunc {
+	return func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		cfg, err := deps.Runtime.Contexts().Load()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return cfg.Names(), cobra.ShellCompDirectiveNoFileComp
+	}
+}
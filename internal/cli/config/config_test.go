@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+type testEnv map[string]string
+
+func (e testEnv) Getenv(key string) string { return e[key] }
+
+func run(t *testing.T, dir, stdin string, args ...string) (string, error) {
+	t.Helper()
+	rt := cliruntime.New(cliruntime.Config{Env: testEnv{}, ConfigDir: dir})
+	cmd := NewCommand(cliruntime.Deps{Runtime: rt})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestContextLifecycle(t *testing.T) {
+	dir := t.TempDir()
+
+	out, err := run(t, dir, "", "get-contexts")
+	require.NoError(t, err)
+	require.Contains(t, out, "No contexts found")
+	_, err = run(t, dir, "", "current-context")
+	require.ErrorContains(t, err, "current context is not set")
+
+	out, err = run(t, dir, "", "set-context", "dev", "--registry-url", "localhost:12121", "--use")
+	require.NoError(t, err)
+	require.Equal(t, "Created context \"dev\".\n", out)
+	_, err = run(t, dir, "", "set-context", "prod", "--registry-url", "https://registry.example.com", "--runtime", "prod-k8s")
+	require.NoError(t, err)
+	out, err = run(t, dir, "", "set-context", "prod", "--runtime", "prod-eks")
+	require.NoError(t, err)
+	require.Equal(t, "Updated context \"prod\".\n", out)
+
+	out, err = run(t, dir, "prod-token\n", "set-credentials", "prod", "--token-stdin")
+	require.NoError(t, err)
+	require.Equal(t, "Stored credentials for context \"prod\".\n", out)
+	_, err = run(t, dir, "", "set-credentials", "staging", "--token", "x")
+	require.ErrorContains(t, err, `context "staging" not found`)
+	_, err = run(t, dir, "", "set-credentials", "prod")
+	require.ErrorContains(t, err, "exactly one of --token and --token-stdin")
+
+	out, err = run(t, dir, "", "current-context")
+	require.NoError(t, err)
+	require.Equal(t, "dev\n", out)
+
+	out, err = run(t, dir, "", "use-context", "prod")
+	require.NoError(t, err)
+	require.Equal(t, "Switched to context \"prod\".\n", out)
+	_, err = run(t, dir, "", "use-context", "staging")
+	require.ErrorContains(t, err, `context "staging" not found`)
+
+	out, err = run(t, dir, "", "get-contexts")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	require.Regexp(t, `^\s*dev\s+localhost:12121\s+-\s*$`, lines[1])
+	require.Regexp(t, `^\*\s+prod\s+https://registry.example.com\s+prod-eks\s+\*$`, lines[2])
+
+	store := cliruntime.ContextStore{Dir: dir}
+	token, err := store.Token("prod")
+	require.NoError(t, err)
+	require.Equal(t, "prod-token", token)
+
+	_, err = run(t, dir, "", "delete-context", "prod")
+	require.NoError(t, err)
+	token, err = store.Token("prod")
+	require.NoError(t, err)
+	require.Empty(t, token)
+	_, err = run(t, dir, "", "current-context")
+	require.ErrorContains(t, err, "current context is not set")
+	_, err = run(t, dir, "", "delete-context", "prod")
+	require.ErrorContains(t, err, `context "prod" not found`)
+}
@@ -0,0 +1,56 @@
+package configure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ServerConfigPath returns the config file `arctl mcp install` writes for
+// an MCP client: Claude Desktop's per-user claude_desktop_config.json, or
+// the project's .cursor/mcp.json or .vscode/mcp.json.
+func ServerConfigPath(client string) (string, error) {
+	switch client {
+	case "claude":
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "Claude", "claude_desktop_config.json"), nil
+	case "cursor":
+		return (&CursorConfigurer{}).GetConfigPath()
+	case "vscode":
+		return (&VSCodeConfigurer{}).GetConfigPath()
+	default:
+		return "", fmt.Errorf("client %q is not supported; expected claude, cursor or vscode", client)
+	}
+}
+
+// AddServer sets configPath's key[serverKey] to entry, creating the file if
+// it doesn't exist. Other servers and settings in the file are kept.
+func AddServer(configPath, key, serverKey string, entry any) error {
+	config := map[string]any{}
+	if data, err := os.ReadFile(configPath); err == nil {
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("failed to parse existing config: %w", err)
+		}
+		if config == nil {
+			config = map[string]any{}
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read existing config: %w", err)
+	}
+
+	servers, ok := config[key].(map[string]any)
+	if !ok {
+		if config[key] != nil {
+			return fmt.Errorf("existing config: %s is not an object", key)
+		}
+		servers = map[string]any{}
+	}
+	servers[serverKey] = entry
+	config[key] = servers
+
+	return writeConfigFile(configPath, config)
+}
@@ -0,0 +1,64 @@
+package configure
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAddServer(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".cursor", "mcp.json")
+
+	if err := AddServer(configPath, "mcpServers", "weather", map[string]any{"command": "npx"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	existing := `{"theme":"dark","mcpServers":{"ARCTL":{"url":"http://localhost:21212/mcp"},"weather":{"url":"old"}}}`
+	if err := os.WriteFile(configPath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddServer(configPath, "mcpServers", "weather", map[string]any{"command": "npx"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config struct {
+		Theme      string                    `json:"theme"`
+		MCPServers map[string]map[string]any `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Theme != "dark" {
+		t.Errorf("Expected other settings to be kept, got theme %q", config.Theme)
+	}
+	if config.MCPServers["ARCTL"]["url"] != "http://localhost:21212/mcp" {
+		t.Errorf("Expected other servers to be kept, got %v", config.MCPServers)
+	}
+	if got := config.MCPServers["weather"]; got["command"] != "npx" || got["url"] != nil {
+		t.Errorf("Expected weather to be replaced, got %v", got)
+	}
+
+	if err := os.WriteFile(configPath, []byte(`{"mcpServers":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddServer(configPath, "mcpServers", "weather", map[string]any{}); err == nil {
+		t.Error("Expected an error for a malformed mcpServers field")
+	}
+}
+
+func TestServerConfigPath(t *testing.T) {
+	for client, want := range map[string]string{"cursor": ".cursor/mcp.json", "vscode": ".vscode/mcp.json"} {
+		got, err := ServerConfigPath(client)
+		if err != nil || got != want {
+			t.Errorf("ServerConfigPath(%q) = %q, %v; want %q", client, got, err, want)
+		}
+	}
+	if _, err := ServerConfigPath("zed"); err == nil {
+		t.Error("Expected an error for an unsupported client")
+	}
+}
@@ -0,0 +1,363 @@
+package declarative
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/a2a"
+	"github.com/agentregistry-dev/agentregistry/internal/cli/buildconfig"
+	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative/agenteval"
+	"github.com/agentregistry-dev/agentregistry/internal/cli/frameworks"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/embeddings"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+// defaultEvalSuite is the suite file eval looks for in the project
+// directory when --suite is not given.
+const defaultEvalSuite = "eval.yaml"
+
+// NewAgentCmd returns the "agent" command group.
+func NewAgentCmd(deps cliruntime.Deps) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     cliruntime.CommandAgent,
+		Aliases: []string{"agents"},
+		Short:   "Agent shortcuts such as scripted evaluation",
+	}
+	cmd.AddCommand(newAgentEvalCmd(deps))
+	return cmd
+}
+
+type agentEvalOptions struct {
+	suite           string
+	url             string
+	extraEnv        []string
+	turnTimeout     time.Duration
+	embeddingsURL   string
+	embeddingsModel string
+	output          string
+	publish         bool
+	dryRun          bool
+}
+
+func newAgentEvalCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts agentEvalOptions
+	cmd := &cobra.Command{
+		Use:   "eval [DIRECTORY]",
+		Short: "Run a scripted A2A conversation suite against an agent",
+		Long: `Start the agent in the project directory (defaults to ".") the way
+'arctl run' does, send it the scripted conversations of an eval suite over
+A2A, check every reply and print a pass/fail report. The agent is torn
+down afterwards. The command exits non-zero when any case fails, so it can
+gate CI.
+
+The suite defaults to eval.yaml in the project directory. Each case is one
+conversation on its own A2A context; each turn sends a message and checks
+the reply with any of:
+
+  matches: REGEX          the regular expression matches the reply
+  notMatches: REGEX       the regular expression does not match
+  jsonPath: items[0].name the reply is JSON with a value at the path,
+    equals: VALUE         equal to VALUE when given
+  similarTo: TEXT         the reply's embedding is at least threshold
+    threshold: 0.8        cosine-similar to TEXT's
+
+similarTo embeds through an Ollama server (--embeddings-url), as the
+registry's semantic search does.
+
+Use --url to evaluate an agent that is already running instead. With
+--publish the summary is recorded on the project's Agent version in the
+registry under the agentregistry.solo.io/eval-results annotation, whether
+the suite passed or not.`,
+		Example: `  arctl agent eval
+  arctl agent eval ./myagent --suite ./evals/smoke.yaml
+  arctl agent eval --url http://localhost:8080/ -o json
+  arctl agent eval ./myagent --publish`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := resolveProjectDir(args)
+			if err != nil {
+				return err
+			}
+			return runAgentEval(cmd.Context(), cmd.OutOrStdout(), deps, dir, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.suite, "suite", "", "Eval suite file (default: eval.yaml in the project directory)")
+	cmd.Flags().StringVar(&opts.url, "url", "", "Evaluate the agent already running at this A2A URL instead of starting the project")
+	cmd.Flags().StringArrayVarP(&opts.extraEnv, "env", "e", nil, "KEY=VALUE env override")
+	cmd.Flags().DurationVar(&opts.turnTimeout, "turn-timeout", 5*time.Minute, "Fail a turn whose reply takes longer than this")
+	cmd.Flags().StringVar(&opts.embeddingsURL, "embeddings-url", embeddings.DefaultLocalURL, "Ollama server similarTo checks embed through")
+	cmd.Flags().StringVar(&opts.embeddingsModel, "embeddings-model", embeddings.DefaultLocalModel, "Embedding model of similarTo checks")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+	cmd.Flags().BoolVar(&opts.publish, "publish", false, "Record the result on the project's Agent version in the registry")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Validate the suite and print what would run without starting the agent")
+	return cmd
+}
+
+func runAgentEval(ctx context.Context, out io.Writer, deps cliruntime.Deps, projectDir string, opts agentEvalOptions) error {
+	if opts.output != "table" && opts.output != "json" {
+		return fmt.Errorf("--output must be table or json, got %q", opts.output)
+	}
+	suitePath := opts.suite
+	if suitePath == "" {
+		suitePath = filepath.Join(projectDir, defaultEvalSuite)
+	}
+	suite, err := agenteval.LoadSuite(suitePath)
+	if err != nil {
+		return err
+	}
+
+	var agent *v1alpha1.Agent
+	if opts.publish {
+		if agent, err = projectAgent(projectDir); err != nil {
+			return err
+		}
+	}
+
+	// Progress goes to stderr with -o json so stdout stays parseable.
+	progress := out
+	if opts.output == "json" {
+		progress = os.Stderr
+	}
+
+	agentURL := opts.url
+	if agentURL == "" {
+		frameworkName, rendered, envv, err := agentRunCommand(progress, projectDir, opts.extraEnv)
+		if err != nil {
+			return err
+		}
+		upArgv := composeUpDetachedArgs(rendered)
+		downArgv := composeDownArgs(rendered, projectDir)
+		if opts.dryRun {
+			fmt.Fprintf(out, "→ %s: %s\n", frameworkName, strings.Join(upArgv, " "))
+			fmt.Fprintf(out, "→ would wait for %s, then run %d eval cases from %s\n", agentReadinessURL, len(suite.Cases), suitePath)
+			fmt.Fprintf(out, "→ afterwards would teardown: %s\n", strings.Join(downArgv, " "))
+			fmt.Fprintln(out, "(dry-run; skipping exec)")
+			return nil
+		}
+		stop, err := startAgent(progress, projectDir, frameworkName, upArgv, downArgv, envv)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		agentURL = agentReadinessURL
+	} else if opts.dryRun {
+		fmt.Fprintf(out, "→ would run %d eval cases from %s against %s\n", len(suite.Cases), suitePath, agentURL)
+		fmt.Fprintln(out, "(dry-run; skipping exec)")
+		return nil
+	}
+
+	// Ctrl+C ends the run and still reaches the deferred teardown.
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	c, err := a2a.NewClient(agentURL, a2a.Options{Timeout: opts.turnTimeout})
+	if err != nil {
+		return err
+	}
+	var embedder agenteval.Embedder
+	if suite.NeedsEmbeddings() {
+		embedder = embeddings.NewOllama(opts.embeddingsURL, opts.embeddingsModel, nil)
+	}
+
+	fmt.Fprintf(progress, "→ Running %d eval cases against %s...\n", len(suite.Cases), agentURL)
+	report := agenteval.Run(ctx, suite, agenteval.A2ASender(c), embedder)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := printEvalReport(out, report, opts.output); err != nil {
+		return err
+	}
+
+	if opts.publish {
+		summary := report.Summarize(filepath.Base(suitePath), time.Now())
+		if err := publishEvalSummary(ctx, progress, deps, agent, summary); err != nil {
+			return err
+		}
+	}
+	if !report.Passed {
+		return fmt.Errorf("%d of %d eval cases failed", len(report.Cases)-report.PassedCases, len(report.Cases))
+	}
+	return nil
+}
+
+// agentRunCommand resolves the project's agent framework and renders its
+// run command and environment the way `arctl run` does.
+func agentRunCommand(out io.Writer, projectDir string, extraEnv []string) (frameworkName string, rendered, envv []string, err error) {
+	cfg, err := buildconfig.Read(projectDir)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	r, err := loadFrameworkRegistry(projectDir)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	p, ok := r.Lookup("agent", cfg.Framework, cfg.Language)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("no agent framework for framework=%s language=%s", cfg.Framework, cfg.Language)
+	}
+
+	dotEnv, err := LoadDotEnv(projectDir)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(dotEnv) > 0 {
+		fmt.Fprintf(out, "→ Loaded .env (%d vars)\n", len(dotEnv))
+	}
+	required := append([]string(nil), p.Env.Required...)
+	if cfg.ModelProvider != "" {
+		required = append(required, ModelProviderEnvKeys(cfg.ModelProvider)...)
+	}
+	if err := ValidateRequiredEnv(dotEnv, extraEnv, required); err != nil {
+		return "", nil, nil, err
+	}
+
+	vars := map[string]any{
+		"ProjectDir":   projectDir,
+		"FrameworkDir": p.SourceDir,
+		"Image":        defaultImage(filepath.Base(projectDir)),
+		"Port":         cfg.Port,
+	}
+	rendered, err = frameworks.RenderArgs(p.Run.Command, vars)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("render run command: %w", err)
+	}
+	return p.Name, rendered, mergeEnv(dotEnv, extraEnv), nil
+}
+
+// projectAgent returns the Agent the project directory declares.
+func projectAgent(projectDir string) (*v1alpha1.Agent, error) {
+	obj, yamlFile, err := findDeclarativeResource(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	agent, ok := obj.(*v1alpha1.Agent)
+	if !ok {
+		return nil, fmt.Errorf("--publish needs an agent project; %s declares a %s", yamlFile, obj.GetKind())
+	}
+	return agent, nil
+}
+
+func printEvalReport(out io.Writer, report *agenteval.Report, output string) error {
+	if output == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CASE\tRESULT\tDURATION\tDETAIL")
+	for _, c := range report.Cases {
+		result := "pass"
+		if !c.Passed {
+			result = "fail"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Name, result, c.Duration, orDashString(evalCaseDetail(c)))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, c := range report.Cases {
+		for _, check := range c.Checks {
+			if check.Passed {
+				continue
+			}
+			fmt.Fprintf(out, "\n%s, turn %d: failed %s\n", c.Name, check.Turn, check.Check)
+			if check.Detail != "" {
+				fmt.Fprintf(out, "  %s\n", check.Detail)
+			}
+			printNoteBody(out, "  > ", check.Reply, nil)
+		}
+	}
+	fmt.Fprintf(out, "\n%d/%d cases passed\n", report.PassedCases, len(report.Cases))
+	return nil
+}
+
+// evalCaseDetail summarizes why a case failed.
+func evalCaseDetail(c agenteval.CaseResult) string {
+	if c.Error != "" {
+		return c.Error
+	}
+	failed := 0
+	for _, check := range c.Checks {
+		if !check.Passed {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d of %d checks failed", failed, len(c.Checks))
+}
+
+// publishEvalSummary records summary on the registry's copy of the
+// project's Agent version by re-applying it with the annotation set.
+func publishEvalSummary(ctx context.Context, out io.Writer, deps cliruntime.Deps, agent *v1alpha1.Agent, summary agenteval.Summary) error {
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	meta := agent.Metadata
+	namespace := meta.NamespaceOrDefault()
+	tag := tagOrLatest(meta.Tag)
+	raw, err := c.Get(ctx, v1alpha1.KindAgent, namespace, meta.Name, tag)
+	if err != nil {
+		return fmt.Errorf("fetching agent %s@%s to publish eval results (publish it first): %w", meta.Name, tag, err)
+	}
+	var spec v1alpha1.AgentSpec
+	if err := json.Unmarshal(raw.Spec, &spec); err != nil {
+		return fmt.Errorf("decoding agent %s: %w", meta.Name, err)
+	}
+	value, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	annotations := map[string]string{}
+	for k, v := range raw.Metadata.Annotations {
+		annotations[k] = v
+	}
+	annotations[v1alpha1.AgentEvalAnnotation] = string(value)
+	published := &v1alpha1.Agent{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindAgent},
+		Metadata: v1alpha1.ObjectMeta{
+			Namespace:   raw.Metadata.Namespace,
+			Name:        raw.Metadata.Name,
+			Tag:         raw.Metadata.Tag,
+			Labels:      raw.Metadata.Labels,
+			Annotations: annotations,
+		},
+		Spec: spec,
+	}
+	data, err := yaml.Marshal(published)
+	if err != nil {
+		return fmt.Errorf("encode Agent: %w", err)
+	}
+	results, err := c.Apply(ctx, data, client.ApplyOpts{})
+	if err != nil {
+		return fmt.Errorf("publishing eval results: %w", err)
+	}
+	for _, r := range results {
+		if r.Status == arv0.ApplyStatusFailed {
+			return fmt.Errorf("publishing eval results on agent %s@%s: %s", meta.Name, raw.Metadata.Tag, r.Error)
+		}
+	}
+	fmt.Fprintf(out, "✓ Recorded eval results on agent %s@%s\n", meta.Name, raw.Metadata.Tag)
+	return nil
+}
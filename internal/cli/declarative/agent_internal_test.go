@@ -0,0 +1,53 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative/agenteval"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+func TestAgentEval_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "eval.yaml"), []byte(`
+cases:
+  - name: greets
+    turns:
+      - send: Hello!
+        expect:
+          - matches: (?i)hello
+`), 0o644))
+
+	var buf bytes.Buffer
+	err := runAgentEval(context.Background(), &buf, cliruntime.Deps{}, dir, agentEvalOptions{url: "http://localhost:9999/", output: "table", dryRun: true})
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "would run 1 eval cases from "+filepath.Join(dir, "eval.yaml")+" against http://localhost:9999/")
+
+	err = runAgentEval(context.Background(), &buf, cliruntime.Deps{}, dir, agentEvalOptions{suite: filepath.Join(dir, "missing.yaml"), output: "table"})
+	require.ErrorContains(t, err, "reading eval suite")
+	err = runAgentEval(context.Background(), &buf, cliruntime.Deps{}, dir, agentEvalOptions{output: "yaml"})
+	require.ErrorContains(t, err, "--output must be table or json")
+}
+
+func TestPrintEvalReport(t *testing.T) {
+	report := &agenteval.Report{PassedCases: 1, Cases: []agenteval.CaseResult{
+		{Name: "greets", Passed: true, Duration: "1.2s", Checks: []agenteval.CheckResult{{Turn: 1, Check: `matches "hello"`, Passed: true}}},
+		{Name: "remembers", Duration: "2s", Checks: []agenteval.CheckResult{{Turn: 2, Check: `matches "Ada"`, Reply: "You are Bob."}}},
+		{Name: "weather", Duration: "0s", Error: "turn 1: stream closed"},
+	}}
+	var buf bytes.Buffer
+	require.NoError(t, printEvalReport(&buf, report, "table"))
+	out := buf.String()
+	require.Contains(t, out, "greets     pass    1.2s      -")
+	require.Contains(t, out, "remembers  fail    2s        1 of 1 checks failed")
+	require.Contains(t, out, "weather    fail    0s        turn 1: stream closed")
+	require.Contains(t, out, "remembers, turn 2: failed matches \"Ada\"\n  > You are Bob.\n")
+	require.True(t, strings.HasSuffix(out, "1/3 cases passed\n"))
+}
@@ -0,0 +1,80 @@
+package agenteval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+
+	"github.com/agentregistry-dev/agentregistry/internal/a2a"
+)
+
+// A2ASender returns a Sender that streams each turn to the agent behind
+// client. The reply is the text of the artifacts the turn produced or,
+// when it produced none, of the agent's last message.
+func A2ASender(client *a2a.Client) Sender {
+	return func(ctx context.Context, contextID, text string) (string, error) {
+		stream, err := client.Stream(ctx, protocol.SendMessageParams{
+			Message: protocol.Message{
+				Kind:      protocol.KindMessage,
+				Role:      protocol.MessageRoleUser,
+				ContextID: &contextID,
+				Parts:     []protocol.Part{protocol.NewTextPart(text)},
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+		var artifacts strings.Builder
+		var last string
+		var failed bool
+		for ev := range stream.Events() {
+			switch res := ev.Result.(type) {
+			case *protocol.TaskArtifactUpdateEvent:
+				artifacts.WriteString(partsText(res.Artifact.Parts))
+			case *protocol.TaskStatusUpdateEvent:
+				if res.Status.Message != nil && res.Status.Message.Role == protocol.MessageRoleAgent {
+					last = partsText(res.Status.Message.Parts)
+				}
+				failed = res.Status.State == protocol.TaskStateFailed
+			case *protocol.Message:
+				if res.Role == protocol.MessageRoleAgent {
+					last = partsText(res.Parts)
+				}
+			case *protocol.Task:
+				for _, a := range res.Artifacts {
+					artifacts.WriteString(partsText(a.Parts))
+				}
+				failed = res.Status.State == protocol.TaskStateFailed
+			}
+		}
+		if err := stream.Err(); err != nil {
+			return "", err
+		}
+		if failed {
+			return "", fmt.Errorf("agent task failed: %s", last)
+		}
+		if artifacts.Len() > 0 {
+			return artifacts.String(), nil
+		}
+		return last, nil
+	}
+}
+
+// partsText joins the text parts of a message, with data parts as JSON.
+func partsText(parts []protocol.Part) string {
+	var b strings.Builder
+	for _, p := range parts {
+		switch part := p.(type) {
+		case *protocol.TextPart:
+			b.WriteString(part.Text)
+		case *protocol.DataPart:
+			if data, err := json.Marshal(part.Data); err == nil {
+				b.Write(data)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,433 @@
+// Package agenteval runs scripted A2A conversations against an agent and
+// checks its replies, for `arctl agent eval`.
+//
+// A suite is a YAML file of cases. Each case is one conversation: its
+// turns are sent in order on a shared A2A context, and every reply is
+// checked against the turn's expectations:
+//
+//	cases:
+//	  - name: greets
+//	    turns:
+//	      - send: Hello!
+//	        expect:
+//	          - matches: (?i)\b(hi|hello)\b
+//	  - name: reports weather as JSON
+//	    turns:
+//	      - send: Weather in Paris as JSON with a "city" field.
+//	        expect:
+//	          - jsonPath: city
+//	            equals: Paris
+//	          - similarTo: The weather in Paris is sunny.
+//	            threshold: 0.7
+package agenteval
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/embeddings"
+)
+
+// DefaultThreshold is the similarity a similarTo expectation needs when
+// the suite does not set one.
+const DefaultThreshold = 0.8
+
+// Suite is a parsed eval suite.
+type Suite struct {
+	Cases []Case `yaml:"cases"`
+}
+
+// Case is one scripted conversation.
+type Case struct {
+	Name  string `yaml:"name"`
+	Turns []Turn `yaml:"turns"`
+}
+
+// Turn is one message sent to the agent and the checks on its reply.
+type Turn struct {
+	Send   string   `yaml:"send"`
+	Expect []Expect `yaml:"expect,omitempty"`
+}
+
+// Expect is one check on a reply. Exactly one of Matches, NotMatches,
+// JSONPath and SimilarTo is set.
+type Expect struct {
+	// Matches passes when the regular expression matches the reply.
+	Matches string `yaml:"matches,omitempty"`
+	// NotMatches passes when the regular expression does not match the
+	// reply.
+	NotMatches string `yaml:"notMatches,omitempty"`
+	// JSONPath passes when the reply is JSON with a value at the dotted
+	// path (e.g. items[0].name), equal to Equals when that is set.
+	JSONPath string  `yaml:"jsonPath,omitempty"`
+	Equals   *string `yaml:"equals,omitempty"`
+	// SimilarTo passes when the embedding of the reply is at least
+	// Threshold cosine-similar to the embedding of the text.
+	SimilarTo string  `yaml:"similarTo,omitempty"`
+	Threshold float64 `yaml:"threshold,omitempty"`
+}
+
+// LoadSuite reads and validates the suite at path.
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading eval suite: %w", err)
+	}
+	var suite Suite
+	if err := yaml.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("parsing eval suite %s: %w", path, err)
+	}
+	if err := suite.Validate(); err != nil {
+		return nil, fmt.Errorf("eval suite %s: %w", path, err)
+	}
+	return &suite, nil
+}
+
+// Validate checks the suite's shape, regular expressions and JSON paths.
+func (s *Suite) Validate() error {
+	if len(s.Cases) == 0 {
+		return errors.New("no cases")
+	}
+	seen := map[string]bool{}
+	for i, c := range s.Cases {
+		if strings.TrimSpace(c.Name) == "" {
+			return fmt.Errorf("case %d has no name", i+1)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("case %q is defined twice", c.Name)
+		}
+		seen[c.Name] = true
+		if len(c.Turns) == 0 {
+			return fmt.Errorf("case %q has no turns", c.Name)
+		}
+		for j, t := range c.Turns {
+			if strings.TrimSpace(t.Send) == "" {
+				return fmt.Errorf("case %q turn %d has nothing to send", c.Name, j+1)
+			}
+			for _, e := range t.Expect {
+				if err := e.validate(); err != nil {
+					return fmt.Errorf("case %q turn %d: %w", c.Name, j+1, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (e Expect) validate() error {
+	set := 0
+	for _, v := range []string{e.Matches, e.NotMatches, e.JSONPath, e.SimilarTo} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("an expectation needs exactly one of matches, notMatches, jsonPath and similarTo")
+	}
+	if e.Equals != nil && e.JSONPath == "" {
+		return errors.New("equals only applies to jsonPath")
+	}
+	if e.Threshold != 0 && e.SimilarTo == "" {
+		return errors.New("threshold only applies to similarTo")
+	}
+	if e.Threshold < 0 || e.Threshold > 1 {
+		return fmt.Errorf("threshold %g is not between 0 and 1", e.Threshold)
+	}
+	for _, pattern := range []string{e.Matches, e.NotMatches} {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return err
+		}
+	}
+	if e.JSONPath != "" {
+		if _, err := parsePath(e.JSONPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NeedsEmbeddings reports whether any expectation compares embeddings.
+func (s *Suite) NeedsEmbeddings() bool {
+	for _, c := range s.Cases {
+		for _, t := range c.Turns {
+			for _, e := range t.Expect {
+				if e.SimilarTo != "" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Sender sends text to the agent as one turn of the conversation
+// contextID and returns its reply.
+type Sender func(ctx context.Context, contextID, text string) (string, error)
+
+// Embedder turns text into vectors. embeddings.Provider satisfies it.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+var _ Embedder = (embeddings.Provider)(nil)
+
+// Report is the outcome of a suite run.
+type Report struct {
+	Passed      bool         `json:"passed"`
+	PassedCases int          `json:"passedCases"`
+	Cases       []CaseResult `json:"cases"`
+}
+
+// CaseResult is the outcome of one case. Error is set when a turn could
+// not be sent; the turns after it are not sent.
+type CaseResult struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Duration string        `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+	Checks   []CheckResult `json:"checks"`
+}
+
+// CheckResult is the outcome of one expectation. Turn counts from 1.
+type CheckResult struct {
+	Turn   int    `json:"turn"`
+	Check  string `json:"check"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+	Reply  string `json:"reply,omitempty"`
+}
+
+// Summary is the short form of a report recorded on the Agent version
+// under v1alpha1.AgentEvalAnnotation.
+type Summary struct {
+	Suite       string    `json:"suite"`
+	Passed      bool      `json:"passed"`
+	Cases       int       `json:"cases"`
+	PassedCases int       `json:"passedCases"`
+	FailedCases []string  `json:"failedCases,omitempty"`
+	EvaluatedAt time.Time `json:"evaluatedAt"`
+}
+
+// Summarize returns the summary of r for the suite named suite.
+func (r *Report) Summarize(suite string, now time.Time) Summary {
+	s := Summary{Suite: suite, Passed: r.Passed, Cases: len(r.Cases), PassedCases: r.PassedCases, EvaluatedAt: now.UTC()}
+	for _, c := range r.Cases {
+		if !c.Passed {
+			s.FailedCases = append(s.FailedCases, c.Name)
+		}
+	}
+	return s
+}
+
+// Run sends every case of suite through send, each on a new A2A context,
+// and checks the replies. embed may be nil when the suite has no
+// similarTo expectations; those fail without it.
+func Run(ctx context.Context, suite *Suite, send Sender, embed Embedder) *Report {
+	report := &Report{Passed: true, Cases: make([]CaseResult, 0, len(suite.Cases))}
+	for _, c := range suite.Cases {
+		res := runCase(ctx, c, send, embed)
+		if res.Passed {
+			report.PassedCases++
+		} else {
+			report.Passed = false
+		}
+		report.Cases = append(report.Cases, res)
+	}
+	return report
+}
+
+func runCase(ctx context.Context, c Case, send Sender, embed Embedder) CaseResult {
+	start := time.Now()
+	res := CaseResult{Name: c.Name, Passed: true, Checks: []CheckResult{}}
+	contextID := newContextID()
+	for i, t := range c.Turns {
+		reply, err := send(ctx, contextID, t.Send)
+		if err != nil {
+			res.Passed = false
+			res.Error = fmt.Sprintf("turn %d: %v", i+1, err)
+			break
+		}
+		for _, e := range t.Expect {
+			check := e.check(ctx, reply, embed)
+			check.Turn = i + 1
+			if !check.Passed {
+				res.Passed = false
+				check.Reply = truncate(reply, 200)
+			}
+			res.Checks = append(res.Checks, check)
+		}
+	}
+	res.Duration = time.Since(start).Round(time.Millisecond).String()
+	return res
+}
+
+func (e Expect) check(ctx context.Context, reply string, embed Embedder) CheckResult {
+	switch {
+	case e.Matches != "":
+		re := regexp.MustCompile(e.Matches)
+		return CheckResult{Check: "matches " + strconv.Quote(e.Matches), Passed: re.MatchString(reply)}
+	case e.NotMatches != "":
+		re := regexp.MustCompile(e.NotMatches)
+		return CheckResult{Check: "notMatches " + strconv.Quote(e.NotMatches), Passed: !re.MatchString(reply)}
+	case e.JSONPath != "":
+		return e.checkJSONPath(reply)
+	default:
+		return e.checkSimilarity(ctx, reply, embed)
+	}
+}
+
+func (e Expect) checkJSONPath(reply string) CheckResult {
+	check := CheckResult{Check: "jsonPath " + e.JSONPath}
+	if e.Equals != nil {
+		check.Check += " == " + strconv.Quote(*e.Equals)
+	}
+	var doc any
+	if err := json.Unmarshal([]byte(unfence(reply)), &doc); err != nil {
+		check.Detail = "reply is not JSON"
+		return check
+	}
+	steps, _ := parsePath(e.JSONPath)
+	value, ok := lookup(doc, steps)
+	if !ok {
+		check.Detail = "no value at path"
+		return check
+	}
+	if e.Equals == nil {
+		check.Passed = true
+		return check
+	}
+	got := scalarString(value)
+	check.Passed = got == *e.Equals
+	if !check.Passed {
+		check.Detail = "got " + strconv.Quote(truncate(got, 80))
+	}
+	return check
+}
+
+func (e Expect) checkSimilarity(ctx context.Context, reply string, embed Embedder) CheckResult {
+	threshold := e.Threshold
+	if threshold == 0 {
+		threshold = DefaultThreshold
+	}
+	check := CheckResult{Check: fmt.Sprintf("similarTo %s >= %g", strconv.Quote(truncate(e.SimilarTo, 40)), threshold)}
+	if embed == nil {
+		check.Detail = "no embeddings provider"
+		return check
+	}
+	vectors, err := embed.Embed(ctx, []string{e.SimilarTo, reply})
+	if err != nil {
+		check.Detail = "embedding failed: " + err.Error()
+		return check
+	}
+	if len(vectors) != 2 {
+		check.Detail = fmt.Sprintf("embedding returned %d vectors for 2 texts", len(vectors))
+		return check
+	}
+	score := embeddings.Cosine(vectors[0], vectors[1])
+	check.Passed = score >= threshold
+	check.Detail = fmt.Sprintf("similarity %.2f", score)
+	return check
+}
+
+// pathStep is one step of a JSON path: an object key, or an array index
+// when key is empty.
+type pathStep struct {
+	key   string
+	index int
+}
+
+// parsePath splits a dotted path such as `$.items[0].name` into steps.
+// The leading `$` is optional.
+func parsePath(path string) ([]pathStep, error) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	var steps []pathStep
+	for _, segment := range strings.Split(rest, ".") {
+		if segment == "" {
+			return nil, fmt.Errorf("bad jsonPath %q", path)
+		}
+		key, indexes, _ := strings.Cut(segment, "[")
+		if key != "" {
+			steps = append(steps, pathStep{key: key})
+		}
+		if indexes == "" {
+			continue
+		}
+		for _, idx := range strings.Split(strings.TrimSuffix(indexes, "]"), "][") {
+			n, err := strconv.Atoi(idx)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("bad index [%s] in jsonPath %q", idx, path)
+			}
+			steps = append(steps, pathStep{index: n})
+		}
+	}
+	return steps, nil
+}
+
+func lookup(doc any, steps []pathStep) (any, bool) {
+	for _, step := range steps {
+		if step.key != "" {
+			obj, ok := doc.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if doc, ok = obj[step.key]; !ok {
+				return nil, false
+			}
+			continue
+		}
+		arr, ok := doc.([]any)
+		if !ok || step.index >= len(arr) {
+			return nil, false
+		}
+		doc = arr[step.index]
+	}
+	return doc, true
+}
+
+// scalarString renders a decoded JSON value for comparison with an
+// equals string: strings as they are, everything else as JSON.
+func scalarString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// unfence strips the Markdown code fence models like to wrap JSON in.
+func unfence(reply string) string {
+	s := strings.TrimSpace(reply)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	if nl := strings.IndexByte(s, '\n'); nl >= 0 {
+		s = s[nl+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+}
+
+func newContextID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "arctl-eval-" + hex.EncodeToString(b)
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
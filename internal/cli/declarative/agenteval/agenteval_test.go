@@ -0,0 +1,158 @@
+package agenteval_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative/agenteval"
+)
+
+const suiteYAML = `cases:
+  - name: greets
+    turns:
+      - send: Hello!
+        expect:
+          - matches: (?i)\bhello\b
+          - notMatches: (?i)error
+  - name: remembers
+    turns:
+      - send: My name is Ada.
+      - send: What is my name?
+        expect:
+          - matches: Ada
+  - name: weather
+    turns:
+      - send: Weather in Paris as JSON
+        expect:
+          - jsonPath: $.city
+            equals: Paris
+          - jsonPath: forecast[1].high
+            equals: "21"
+          - similarTo: sunny in Paris
+            threshold: 0.9
+`
+
+func loadSuite(t *testing.T, content string) (*agenteval.Suite, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "eval.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return agenteval.LoadSuite(path)
+}
+
+// fakeAgent replies from a script keyed by message and remembers the
+// context of every turn.
+type fakeAgent struct {
+	replies  map[string]string
+	contexts []string
+}
+
+func (f *fakeAgent) send(_ context.Context, contextID, text string) (string, error) {
+	f.contexts = append(f.contexts, contextID)
+	reply, ok := f.replies[text]
+	if !ok {
+		return "", errors.New("stream closed")
+	}
+	return reply, nil
+}
+
+// fixedEmbedder embeds every text to the same vector except those it
+// lists.
+type fixedEmbedder map[string][]float32
+
+func (f fixedEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		if v, ok := f[text]; ok {
+			out[i] = v
+		} else {
+			out[i] = []float32{1, 0}
+		}
+	}
+	return out, nil
+}
+
+func TestRun(t *testing.T) {
+	suite, err := loadSuite(t, suiteYAML)
+	require.NoError(t, err)
+	require.True(t, suite.NeedsEmbeddings())
+
+	agent := &fakeAgent{replies: map[string]string{
+		"Hello!":                   "Hello there.",
+		"My name is Ada.":          "Nice to meet you.",
+		"What is my name?":         "You are Bob.",
+		"Weather in Paris as JSON": "```json\n{\"city\": \"Paris\", \"forecast\": [{\"high\": 19}, {\"high\": 21}]}\n```",
+	}}
+	report := agenteval.Run(context.Background(), suite, agent.send, fixedEmbedder{})
+
+	require.False(t, report.Passed)
+	require.Equal(t, 2, report.PassedCases)
+	require.True(t, report.Cases[0].Passed)
+	require.Len(t, report.Cases[0].Checks, 2)
+
+	remembers := report.Cases[1]
+	require.False(t, remembers.Passed)
+	require.Equal(t, []agenteval.CheckResult{{Turn: 2, Check: `matches "Ada"`, Reply: "You are Bob."}}, remembers.Checks)
+	require.Equal(t, agent.contexts[1], agent.contexts[2], "turns of a case share a context")
+	require.NotEqual(t, agent.contexts[0], agent.contexts[1], "cases get their own context")
+
+	weather := report.Cases[2]
+	require.True(t, weather.Passed, "%+v", weather.Checks)
+	require.Equal(t, "similarity 1.00", weather.Checks[2].Detail)
+
+	summary := report.Summarize("eval.yaml", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.Equal(t, []string{"remembers"}, summary.FailedCases)
+	require.Equal(t, 3, summary.Cases)
+}
+
+func TestRunFailures(t *testing.T) {
+	suite, err := loadSuite(t, suiteYAML)
+	require.NoError(t, err)
+	agent := &fakeAgent{replies: map[string]string{
+		"Hello!":                   "Hello there.",
+		"Weather in Paris as JSON": `{"city": "Lyon"}`,
+	}}
+	report := agenteval.Run(context.Background(), suite, agent.send, fixedEmbedder{"sunny in Paris": {0, 1}})
+
+	require.Equal(t, "turn 1: stream closed", report.Cases[1].Error)
+	require.Empty(t, report.Cases[1].Checks, "a case stops at the turn that could not be sent")
+	require.Len(t, agent.contexts, 3)
+
+	checks := report.Cases[2].Checks
+	require.Equal(t, `got "Lyon"`, checks[0].Detail)
+	require.Equal(t, "no value at path", checks[1].Detail)
+	require.False(t, checks[2].Passed)
+	require.Equal(t, "similarity 0.00", checks[2].Detail)
+
+	report = agenteval.Run(context.Background(), suite, agent.send, nil)
+	require.Equal(t, "no embeddings provider", report.Cases[2].Checks[2].Detail)
+}
+
+func TestLoadSuiteErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		suite string
+		err   string
+	}{
+		"no cases":        {"cases: []", "no cases"},
+		"unnamed case":    {"cases: [{turns: [{send: hi}]}]", "case 1 has no name"},
+		"duplicate case":  {"cases: [{name: a, turns: [{send: hi}]}, {name: a, turns: [{send: hi}]}]", `case "a" is defined twice`},
+		"no turns":        {"cases: [{name: a}]", `case "a" has no turns`},
+		"empty send":      {"cases: [{name: a, turns: [{send: ' '}]}]", `case "a" turn 1 has nothing to send`},
+		"two checks":      {"cases: [{name: a, turns: [{send: hi, expect: [{matches: x, jsonPath: y}]}]}]", "exactly one of"},
+		"bad regex":       {"cases: [{name: a, turns: [{send: hi, expect: [{matches: '('}]}]}]", "missing closing )"},
+		"stray equals":    {"cases: [{name: a, turns: [{send: hi, expect: [{matches: x, equals: y}]}]}]", "equals only applies to jsonPath"},
+		"bad threshold":   {"cases: [{name: a, turns: [{send: hi, expect: [{similarTo: x, threshold: 2}]}]}]", "threshold 2 is not between 0 and 1"},
+		"bad path index":  {"cases: [{name: a, turns: [{send: hi, expect: [{jsonPath: 'a[x]'}]}]}]", "bad index [x]"},
+		"empty path step": {"cases: [{name: a, turns: [{send: hi, expect: [{jsonPath: 'a..b'}]}]}]", `bad jsonPath "a..b"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadSuite(t, tc.suite)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -6,14 +6,18 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
 	"github.com/agentregistry-dev/agentregistry/internal/cli/buildconfig"
+	cliCommon "github.com/agentregistry-dev/agentregistry/internal/cli/common"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/scheme"
 	"github.com/agentregistry-dev/agentregistry/internal/client"
 	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
 )
 
@@ -21,34 +25,120 @@ import (
 // independent command with its own flag state, which is required for testing
 // since cobra flags accumulate across Execute() calls on the same command instance.
 func NewApplyCmd(deps cliruntime.Deps) *cobra.Command {
-	var dryRun bool
+	var (
+		dryRun        bool
+		showManifests bool
+		watch         bool
+		watchTimeout  time.Duration
+		prune         bool
+		selector      string
+		validate      bool
+		validateEnv   []string
+	)
 	cmd := &cobra.Command{
 		Use:   cliruntime.CommandApply + " -f FILE",
 		Short: "Apply one or more resources from a YAML file",
 		Long: `Apply reads a YAML file (or stdin with -f -) containing one or more resource
-documents and applies them via POST /v0/apply.
+documents and applies them via POST /v0/apply. An oci://REF argument applies
+the manifest inside a metadata artifact written by "arctl push".
 
 Each resource is applied atomically; the server reports per-resource status.
 Best-effort: per-resource errors are reported without aborting the batch.
 
+With --watch, apply then streams the progress of every Deployment it applied
+(adapter progress, rollout status and errors) and exits non-zero if one fails
+or does not become ready within --watch-timeout.
+
+With --dry-run --show-manifests, apply also renders the runtime manifests
+(compose file, gateway config, Kubernetes resources, Helm releases) for every
+Deployment in the files via POST /v0/deployments:dryRun, so translation
+errors surface before anything is deployed. Targets and Runtimes must already
+be in the registry.
+
+With --validate-runtime, every bundled MCPServer (spec.source.package) is
+first booted with docker the way a local runtime would run it, in a
+throwaway container. apply runs the MCP initialize handshake and tools/list
+against it and records the tools in spec.tools, which also makes them
+searchable. A server that does not start or answer fails the apply before
+anything is sent. Pass the package's environment variables with
+--validate-env NAME=VALUE.
+
+With --prune, apply converges Deployments to the files: once everything
+applied cleanly, managed Deployments matching --selector that the files no
+longer declare are deleted, in each namespace the files declare Deployments
+in. Every Deployment in the files must carry the --selector labels, so one
+file (or directory of files) owns one set of Deployments, e.g. an
+environment.
+
+A Deployment that names neither a runtimeRef nor a runtimeSelector is placed
+on the default Runtime of the arctl context in use, when it has one (see
+"arctl config set-context --runtime").
+
 Examples:
   arctl apply -f agent.yaml
   arctl apply -f stack.yaml --dry-run
-  cat stack.yaml | arctl apply -f -`,
+  arctl apply -f deployment.yaml --dry-run --show-manifests
+  arctl apply -f deployment.yaml --watch
+  arctl apply -f mcp.yaml --validate-runtime --validate-env API_KEY=test
+  arctl apply -f staging.yaml --prune --selector env=staging
+  cat stack.yaml | arctl apply -f -
+  arctl apply -f oci://ghcr.io/acme/skills/summarize:1.0.0`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runApply(cmd, deps, dryRun)
+			if showManifests && !dryRun {
+				return fmt.Errorf("--show-manifests requires --dry-run")
+			}
+			if selector != "" && !prune {
+				return fmt.Errorf("--selector requires --prune")
+			}
+			if len(validateEnv) > 0 && !validate {
+				return fmt.Errorf("--validate-env requires --validate-runtime")
+			}
+			var runtimeEnv map[string]string
+			if validate {
+				env, err := parseValidateEnv(validateEnv)
+				if err != nil {
+					return err
+				}
+				runtimeEnv = env
+			}
+			return runApply(cmd, deps, dryRun, showManifests, watch, watchTimeout, pruneSelector(prune, selector), runtimeEnv)
 		},
 	}
 	cmd.Flags().StringArrayP("filename", "f", nil,
-		"YAML file to apply (repeatable; use - for stdin)")
+		"YAML file to apply (repeatable; use - for stdin, oci://REF for a pushed artifact)")
 	_ = cmd.MarkFlagRequired("filename")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false,
 		"Validate and simulate without mutating state")
+	cmd.Flags().BoolVar(&showManifests, "show-manifests", false,
+		"With --dry-run, print the runtime manifests each Deployment would apply")
+	cmd.Flags().BoolVar(&watch, "watch", false,
+		"Stream the progress of applied Deployments until they are ready or fail")
+	cmd.Flags().DurationVar(&watchTimeout, "watch-timeout", cliCommon.DefaultWaitTimeout,
+		"Maximum time to watch each Deployment. 0 or negative watches forever.")
+	cmd.Flags().BoolVar(&prune, "prune", false,
+		"Delete managed Deployments matching --selector that the files no longer declare")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "",
+		"Label selector (key=value,...) of the Deployments the files own; required with --prune")
+	cmd.Flags().BoolVar(&validate, "validate-runtime", false,
+		"Boot each bundled MCPServer in a throwaway container and record the tools it lists before applying")
+	cmd.Flags().StringArrayVar(&validateEnv, "validate-env", nil,
+		"Environment variable for --validate-runtime containers, as NAME=VALUE (repeatable)")
 	return cmd
 }
 
-func runApply(cmd *cobra.Command, deps cliruntime.Deps, dryRun bool) error {
+// pruneSelector returns the selector to prune with, or nil when --prune is
+// off.
+func pruneSelector(prune bool, selector string) *string {
+	if !prune {
+		return nil
+	}
+	return &selector
+}
+
+// runApply applies the -f files. A non-nil runtimeEnv turns on
+// --validate-runtime with those environment variables.
+func runApply(cmd *cobra.Command, deps cliruntime.Deps, dryRun, showManifests, watch bool, watchTimeout time.Duration, prune *string, runtimeEnv map[string]string) error {
 	filePaths, err := cmd.Flags().GetStringArray("filename")
 	if err != nil {
 		return fmt.Errorf("getting filename flag: %w", err)
@@ -58,25 +148,56 @@ func runApply(cmd *cobra.Command, deps cliruntime.Deps, dryRun bool) error {
 	var allData [][]byte
 	for _, path := range filePaths {
 		var data []byte
-		if path == "-" {
+		switch {
+		case path == "-":
 			data, err = io.ReadAll(cmd.InOrStdin())
 			if err != nil {
 				return fmt.Errorf("reading stdin: %w", err)
 			}
-		} else {
+		case strings.HasPrefix(path, ociApplyPrefix):
+			data, err = readOCIManifest(cmd.Context(), strings.TrimPrefix(path, ociApplyPrefix))
+			if err != nil {
+				return err
+			}
+		default:
 			data, err = InjectArctlLabels(path)
 			if err != nil {
 				return err
 			}
 		}
 
+		if deps.Runtime != nil {
+			target := deps.Runtime.RegistryTarget()
+			data, err = defaultDeploymentRuntime(data, target.Runtime, target.Context, cmd.ErrOrStderr())
+			if err != nil {
+				return fmt.Errorf("parsing %s: %w", path, err)
+			}
+		}
+
 		// Validate locally via registry decode — catches unknown kinds before sending.
 		if _, err := scheme.DecodeBytes(data); err != nil {
 			return fmt.Errorf("parsing %s: %w", path, err)
 		}
+		if runtimeEnv != nil {
+			data, err = validateMCPRuntimes(cmd.Context(), data, runtimeEnv, cmd.ErrOrStderr())
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
 		allData = append(allData, data)
 	}
 
+	var declared map[string]map[string]bool
+	if prune != nil {
+		selector, err := parsePruneSelector(*prune)
+		if err != nil {
+			return err
+		}
+		if declared, err = declaredDeployments(allData, selector); err != nil {
+			return err
+		}
+	}
+
 	if deps.Runtime == nil {
 		return fmt.Errorf("API client not initialized")
 	}
@@ -85,8 +206,42 @@ func runApply(cmd *cobra.Command, deps cliruntime.Deps, dryRun bool) error {
 		return fmt.Errorf("API client not initialized")
 	}
 
+	// 2. Check Agent Deployments against the secrets their agent declares,
+	// prompting for missing ones when a user is at the terminal.
+	var prompt secretPrompter
+	if isatty() && !dryRun {
+		prompt = promptSecret
+	}
+	runtimeDefaults := registryRuntimeDefaults(cmd.Context(), c)
+	for i, data := range allData {
+		filled, err := fillDeploymentSecrets(data, registryAgentSecrets(cmd.Context(), c), runtimeDefaults, prompt, cmd.ErrOrStderr())
+		if err != nil {
+			return fmt.Errorf("%s: %w", filePaths[i], err)
+		}
+		allData[i] = filled
+	}
+
+	// Show what each Deployment inherits from its Runtime's
+	// deploymentDefaults.
+	for i, data := range allData {
+		if err := previewDeploymentDefaults(data, runtimeDefaults, cmd.ErrOrStderr()); err != nil {
+			return fmt.Errorf("%s: %w", filePaths[i], err)
+		}
+	}
+
+	// Warn, without blocking, when a Deployment moves an MCPServer across
+	// a capability-breaking change.
+	for i, data := range allData {
+		if err := warnCapabilityBreaks(data, registryUpgradeLookups(cmd.Context(), c), cmd.ErrOrStderr()); err != nil {
+			return fmt.Errorf("%s: %w", filePaths[i], err)
+		}
+	}
+
 	// 3. Send each file as a separate batch call (preserves document separation).
-	var anyFailure bool
+	var (
+		anyFailure bool
+		applied    []arv0.ApplyResult
+	)
 	for i, data := range allData {
 		results, err := c.Apply(cmd.Context(), data, client.ApplyOpts{
 			DryRun: dryRun,
@@ -98,6 +253,7 @@ func runApply(cmd *cobra.Command, deps cliruntime.Deps, dryRun bool) error {
 			continue
 		}
 		printResults(cmd.OutOrStdout(), results, dryRun)
+		applied = append(applied, results...)
 		for _, r := range results {
 			if r.Status == arv0.ApplyStatusFailed {
 				anyFailure = true
@@ -105,9 +261,29 @@ func runApply(cmd *cobra.Command, deps cliruntime.Deps, dryRun bool) error {
 		}
 	}
 
+	if showManifests {
+		for i, data := range allData {
+			if err := printDeploymentManifests(cmd.Context(), c, data, cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error rendering %s: %v\n", filePaths[i], err)
+				anyFailure = true
+			}
+		}
+	}
+
 	if anyFailure {
+		if prune != nil {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Skipping prune because not every resource applied.")
+		}
 		return fmt.Errorf("one or more resources failed to apply")
 	}
+	if prune != nil {
+		if err := pruneDeployments(cmd.Context(), c, cmd.OutOrStdout(), declared, *prune, dryRun); err != nil {
+			return err
+		}
+	}
+	if watch && !dryRun {
+		return watchAppliedDeployments(cmd.Context(), c, cmd.OutOrStdout(), applied, watchTimeout)
+	}
 	return nil
 }
 
@@ -157,8 +333,8 @@ func InjectArctlLabels(yamlPath string) ([]byte, error) {
 		}
 		meta := findOrCreateMappingChild(root, "metadata")
 		labels := findOrCreateMappingChild(meta, "labels")
-		upsertLabel(labels, "arctl.dev/framework", cfg.Framework)
-		upsertLabel(labels, "arctl.dev/language", cfg.Language)
+		upsertLabel(labels, v1alpha1.AgentFrameworkLabel, cfg.Framework)
+		upsertLabel(labels, v1alpha1.AgentLanguageLabel, cfg.Language)
 		injected = true
 	}
 
@@ -3,6 +3,7 @@ package declarative_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -161,6 +162,67 @@ func TestApplyNoQueryNoise(t *testing.T) {
 	assert.Empty(t, captured.URL.RawQuery, "expected no query params when dryRun is false")
 }
 
+// newWatchTestServer answers POST /v0/apply with results and streams events
+// on GET /v0/deployments/bot-prod/events.
+func newWatchTestServer(t *testing.T, results []arv0.ApplyResult, events ...arv0.DeploymentEvent) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(batchApplyResponse(results))
+			return
+		}
+		if r.URL.Path != "/v0/deployments/bot-prod/events" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i, event := range events {
+			data, _ := json.Marshal(event)
+			_, _ = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", i+1, data)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestApplyWatchStreamsUntilDeployed verifies --watch prints Deployment
+// progress and exits zero once the Deployment is ready.
+func TestApplyWatchStreamsUntilDeployed(t *testing.T) {
+	srv := newWatchTestServer(t,
+		[]arv0.ApplyResult{{Kind: "Deployment", Namespace: "default", Name: "bot-prod", Status: arv0.ApplyStatusCreated}},
+		arv0.DeploymentEvent{Type: arv0.DeploymentEventCondition, Condition: "Progressing", Status: "True", Reason: "Applying", Message: "creating pod"},
+		arv0.DeploymentEvent{Type: arv0.DeploymentEventCondition, Condition: "Ready", Status: "True", Reason: "Deployed"},
+	)
+
+	var out bytes.Buffer
+	cmd := declarative.NewApplyCmd(applyDeps(t, srv))
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"-f", writeTempYAML(t, agentYAML), "--watch"})
+	require.NoError(t, cmd.Execute(), out.String())
+
+	assert.Contains(t, out.String(), "deployment/default/bot-prod Progressing=True Applying: creating pod")
+	assert.Contains(t, out.String(), "✓ deployment/default/bot-prod deployed")
+}
+
+// TestApplyWatchFailsOnDegraded verifies --watch exits non-zero with the
+// adapter's error once the Deployment is degraded.
+func TestApplyWatchFailsOnDegraded(t *testing.T) {
+	srv := newWatchTestServer(t,
+		[]arv0.ApplyResult{{Kind: "Deployment", Namespace: "default", Name: "bot-prod", Status: arv0.ApplyStatusConfigured}},
+		arv0.DeploymentEvent{Type: arv0.DeploymentEventCondition, Condition: "Degraded", Status: "True", Reason: "AdapterApplyFailed", Message: "image pull failed"},
+	)
+
+	cmd := declarative.NewApplyCmd(applyDeps(t, srv))
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"-f", writeTempYAML(t, agentYAML), "--watch"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "deployment default/bot-prod failed: image pull failed")
+}
+
 // TestApplyRejectsUnknownKind verifies that an unknown kind fails before hitting the server.
 func TestApplyRejectsUnknownKind(t *testing.T) {
 	badYAML := `apiVersion: ar.dev/v1alpha1
@@ -295,3 +357,50 @@ spec:
 	assert.Contains(t, string(got), "arctl.dev/framework: fastmcp")
 	assert.Contains(t, string(got), "arctl.dev/language: python")
 }
+
+// TestApplyDryRunShowManifests verifies --show-manifests renders each
+// Deployment through POST /v0/deployments:dryRun and prints the manifests.
+func TestApplyDryRunShowManifests(t *testing.T) {
+	const deploymentYAML = `apiVersion: ar.dev/v1alpha1
+kind: Deployment
+metadata:
+  name: bot-dev
+spec:
+  targetRef:
+    kind: Agent
+    name: acme-bot
+  runtimeRef:
+    kind: Runtime
+    name: local
+`
+	var dryRunBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v0/deployments:dryRun" {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&dryRunBody))
+			_ = json.NewEncoder(w).Encode(arv0.DeploymentDryRun{
+				Namespace: "default", Name: "bot-dev",
+				Manifests: []arv0.DeploymentManifest{{Name: "docker-compose.yaml", Content: "services:\n  bot: {}\n"}},
+			})
+			return
+		}
+		_, _ = w.Write(batchApplyResponse([]arv0.ApplyResult{{Kind: "Deployment", Name: "bot-dev", Status: arv0.ApplyStatusCreated}}))
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewApplyCmd(applyDeps(t, srv))
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"-f", writeTempYAML(t, deploymentYAML), "--dry-run", "--show-manifests"})
+	require.NoError(t, cmd.Execute(), out.String())
+
+	assert.Equal(t, "bot-dev", dryRunBody["metadata"].(map[string]any)["name"])
+	assert.Contains(t, out.String(), "# deployment/default/bot-dev: docker-compose.yaml\nservices:\n  bot: {}\n")
+
+	cmd = declarative.NewApplyCmd(applyDeps(t, srv))
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"-f", writeTempYAML(t, deploymentYAML), "--show-manifests"})
+	require.ErrorContains(t, cmd.Execute(), "--show-manifests requires --dry-run")
+}
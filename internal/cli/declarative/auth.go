@@ -0,0 +1,173 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+	"github.com/agentregistry-dev/agentregistry/pkg/printer"
+)
+
+// NewAuthCmd returns the "auth" command group for registry credentials.
+func NewAuthCmd(deps cliruntime.Deps) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   cliruntime.CommandAuth,
+		Short: "Manage registry credentials",
+	}
+	apikey := &cobra.Command{
+		Use:   "apikey",
+		Short: "Create, list and revoke scoped API keys",
+	}
+	apikey.AddCommand(newAPIKeyCreateCmd(deps))
+	apikey.AddCommand(newAPIKeyListCmd(deps))
+	apikey.AddCommand(newAPIKeyRevokeCmd(deps))
+	cmd.AddCommand(apikey)
+	return cmd
+}
+
+type apiKeyCreateOptions struct {
+	name     string
+	kinds    []string
+	prefixes []string
+	actions  []string
+	expires  time.Duration
+}
+
+func newAPIKeyCreateCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts apiKeyCreateOptions
+	cmd := &cobra.Command{
+		Use:   "create --name NAME --action ACTION",
+		Short: "Create an API key for automation such as CI",
+		Long: `Create an API key that acts on your behalf, restricted to the given
+artifact kinds, name prefixes and actions:
+
+  read    get and list artifacts and deployments
+  push    apply and delete artifacts
+  deploy  apply and delete Deployments of artifacts in scope
+
+Omitting --kind reaches every artifact kind; omitting --prefix reaches every
+name. The token is printed once and cannot be retrieved again; send it as
+"Authorization: Bearer <token>".`,
+		Example: `  arctl auth apikey create --name ci --kind agent --prefix acme/ --action push
+  arctl auth apikey create --name deployer --action read --action deploy --expires 720h`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runAPIKeyCreate(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), deps, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.name, "name", "", "Name describing what the key is for")
+	cmd.Flags().StringSliceVar(&opts.kinds, "kind", nil, "Artifact kind the key reaches (agent, mcpserver, skill, prompt); repeatable")
+	cmd.Flags().StringSliceVar(&opts.prefixes, "prefix", nil, "Name prefix the key reaches; repeatable")
+	cmd.Flags().StringSliceVar(&opts.actions, "action", nil, "Action the key may perform (read, push, deploy); repeatable")
+	cmd.Flags().DurationVar(&opts.expires, "expires", 0, "Expire the key after this long (default: never)")
+	_ = cmd.MarkFlagRequired("name")
+	_ = cmd.MarkFlagRequired("action")
+	return cmd
+}
+
+func runAPIKeyCreate(ctx context.Context, out, errOut io.Writer, deps cliruntime.Deps, opts apiKeyCreateOptions) error {
+	if opts.expires < 0 {
+		return fmt.Errorf("--expires must be positive")
+	}
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	in := arv0.APIKeyInput{Name: opts.name, Kinds: opts.kinds, Prefixes: opts.prefixes, Actions: opts.actions}
+	if opts.expires > 0 {
+		expiresAt := time.Now().Add(opts.expires).UTC()
+		in.ExpiresAt = &expiresAt
+	}
+	created, err := c.CreateAPIKey(ctx, in)
+	if err != nil {
+		return fmt.Errorf("creating API key: %w", err)
+	}
+	fmt.Fprintf(errOut, "Created API key %s (%s). Store the token now; it is not shown again.\n", created.Key.ID, created.Key.Name)
+	fmt.Fprintln(out, created.Token)
+	return nil
+}
+
+func newAPIKeyListCmd(deps cliruntime.Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List your API keys (every key for registry admins)",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := registryClient(cmd, deps)
+			if err != nil {
+				return err
+			}
+			keys, err := c.ListAPIKeys(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("listing API keys: %w", err)
+			}
+			return printAPIKeys(cmd.OutOrStdout(), keys)
+		},
+	}
+}
+
+func printAPIKeys(out io.Writer, keys []arv0.APIKey) error {
+	if len(keys) == 0 {
+		fmt.Fprintln(out, "No API keys found.")
+		return nil
+	}
+	orAll := func(values []string) string {
+		if len(values) == 0 {
+			return "*"
+		}
+		return strings.Join(values, ",")
+	}
+	orDash := func(at *time.Time) string {
+		if at == nil {
+			return "-"
+		}
+		return at.Format(time.RFC3339)
+	}
+	t := printer.NewTablePrinter(out)
+	t.SetHeaders("ID", "NAME", "OWNER", "KINDS", "PREFIXES", "ACTIONS", "EXPIRES", "LAST USED", "REVOKED")
+	for _, key := range keys {
+		t.AddRow(
+			key.ID,
+			key.Name,
+			printer.EmptyValueOrDefault(key.Owner, "-"),
+			orAll(key.Kinds),
+			orAll(key.Prefixes),
+			strings.Join(key.Actions, ","),
+			orDash(key.ExpiresAt),
+			orDash(key.LastUsedAt),
+			orDash(key.RevokedAt),
+		)
+	}
+	return t.Render()
+}
+
+func newAPIKeyRevokeCmd(deps cliruntime.Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:          "revoke ID",
+		Short:        "Revoke an API key",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := registryClient(cmd, deps)
+			if err != nil {
+				return err
+			}
+			if _, err := c.RevokeAPIKey(cmd.Context(), args[0]); err != nil {
+				return fmt.Errorf("revoking API key: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "API key %s revoked\n", args[0])
+			return nil
+		},
+	}
+}
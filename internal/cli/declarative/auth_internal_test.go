@@ -0,0 +1,60 @@
+package declarative
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+func TestAPIKeyCreate_PrintsTokenOnce(t *testing.T) {
+	var got arv0.APIKeyInput
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method+" "+r.URL.Path != "POST /v0/apikeys" {
+			http.NotFound(w, r)
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(arv0.APIKeyCreated{
+			Key:   arv0.APIKey{ID: "0123456789abcdef", Name: got.Name, Actions: got.Actions},
+			Token: "arreg_0123456789abcdef_secret",
+		})
+	}))
+	t.Cleanup(srv.Close)
+	deps := internalDeclarativeTestDeps(client.NewClient(srv.URL, ""))
+
+	var out, errOut bytes.Buffer
+	require.ErrorContains(t, runAPIKeyCreate(t.Context(), &out, &errOut, deps, apiKeyCreateOptions{name: "ci", expires: -time.Hour}), "--expires")
+
+	opts := apiKeyCreateOptions{name: "ci", kinds: []string{"agent"}, prefixes: []string{"acme/"}, actions: []string{"push"}, expires: time.Hour}
+	require.NoError(t, runAPIKeyCreate(t.Context(), &out, &errOut, deps, opts))
+	require.Equal(t, "arreg_0123456789abcdef_secret\n", out.String())
+	require.Contains(t, errOut.String(), "not shown again")
+	require.Equal(t, []string{"agent"}, got.Kinds)
+	require.Equal(t, []string{"acme/"}, got.Prefixes)
+	require.NotNil(t, got.ExpiresAt)
+	require.WithinDuration(t, time.Now().Add(time.Hour), *got.ExpiresAt, time.Minute)
+}
+
+func TestPrintAPIKeys(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, printAPIKeys(&out, nil))
+	require.Contains(t, out.String(), "No API keys found.")
+
+	out.Reset()
+	revoked := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, printAPIKeys(&out, []arv0.APIKey{{
+		ID: "k1", Name: "ci", Owner: "alice", Prefixes: []string{"acme/"}, Actions: []string{"read", "push"}, RevokedAt: &revoked,
+	}}))
+	require.Contains(t, out.String(), "acme/")
+	require.Contains(t, out.String(), "read,push")
+	require.Contains(t, out.String(), "2026-01-02T03:04:05Z")
+}
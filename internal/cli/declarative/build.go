@@ -1,6 +1,7 @@
 package declarative
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -18,14 +19,16 @@ import (
 	"github.com/agentregistry-dev/agentregistry/internal/version"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+	"github.com/agentregistry-dev/agentregistry/pkg/ociartifact"
 )
 
 // NewBuildCmd returns a new "build" cobra command.
 func NewBuildCmd(deps cliruntime.Deps) *cobra.Command {
 	var (
-		buildImage    string
-		buildPush     bool
-		buildPlatform string
+		buildImage          string
+		buildPush           bool
+		buildPlatform       string
+		buildAttachMetadata bool
 	)
 
 	cmd := &cobra.Command{
@@ -42,7 +45,13 @@ Supported kinds: Agent, MCPServer
 Examples:
   arctl build ./my-agent
   arctl build ./my-server --push
-  arctl build ./my-agent  --image ghcr.io/acme/my-agent:v1.0.0 --platform linux/amd64`,
+  arctl build ./my-server --push --attach-metadata
+  arctl build ./my-agent  --image ghcr.io/acme/my-agent:v1.0.0 --platform linux/amd64
+
+--attach-metadata pushes the resource's manifest and README (plus the
+server.json card for MCP servers) as an OCI artifact referring to the pushed
+image, so 'oras discover <image>' and other OCI tooling find it next to the
+image.`,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -57,6 +66,9 @@ Examples:
 			if !info.IsDir() {
 				return fmt.Errorf("expected a project directory, not a file — try: arctl build ./my-project")
 			}
+			if buildAttachMetadata && !buildPush {
+				return fmt.Errorf("--attach-metadata requires --push")
+			}
 
 			obj, yamlFile, err := findDeclarativeResource(projectDir)
 			if err != nil {
@@ -73,11 +85,17 @@ Examples:
 			out := cmd.OutOrStdout()
 			switch kind {
 			case v1alpha1.KindAgent, v1alpha1.KindMCPServer:
-				return buildViaFramework(out, projectDir, obj, buildImage, buildPlatform, buildPush)
+				image, err := buildViaFramework(out, projectDir, obj, buildImage, buildPlatform, buildPush)
+				if err != nil || !buildAttachMetadata {
+					return err
+				}
+				return attachMetadata(cmd.Context(), out, image, obj)
 			case v1alpha1.KindPrompt:
 				return fmt.Errorf("prompts have no build step — use 'arctl apply -f %s' directly", yamlFile)
 			case v1alpha1.KindSkill:
 				return fmt.Errorf("skills have no build step — use 'arctl apply -f %s' directly", yamlFile)
+			case v1alpha1.KindChart:
+				return fmt.Errorf("charts have no build step — use 'arctl apply -f %s' directly", yamlFile)
 			default:
 				return nil
 			}
@@ -87,6 +105,7 @@ Examples:
 	cmd.Flags().StringVar(&buildImage, "image", "", "Docker image tag override (default: from spec.source.image / spec.source.package.origin.identifier)")
 	cmd.Flags().BoolVar(&buildPush, "push", false, "Push the image after building")
 	cmd.Flags().StringVar(&buildPlatform, "platform", "", "Target platform (e.g. linux/amd64, linux/arm64)")
+	cmd.Flags().BoolVar(&buildAttachMetadata, "attach-metadata", false, "After --push, attach the resource metadata to the image as an OCI referrer")
 
 	// build is an offline command — hide inherited registry flags from --help output.
 	common.HideRegistryFlags(cmd)
@@ -159,15 +178,16 @@ func mcpSpecPackageIdentifier(obj v1alpha1.Object) string {
 // buildViaFramework dispatches the build to the framework matching
 // (framework, language) in arctl.yaml. The framework's Build command is exec'd
 // in the project directory with template vars {Image, ProjectDir, Platform, FrameworkDir}.
-func buildViaFramework(out io.Writer, projectDir string, obj v1alpha1.Object, flagImage, platform string, push bool) error {
+// Returns the image reference that was built.
+func buildViaFramework(out io.Writer, projectDir string, obj v1alpha1.Object, flagImage, platform string, push bool) (string, error) {
 	cfg, err := buildconfig.Read(projectDir)
 	if err != nil {
-		return fmt.Errorf("read arctl.yaml: %w", err)
+		return "", fmt.Errorf("read arctl.yaml: %w", err)
 	}
 
 	r, err := loadFrameworkRegistry(projectDir)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	frameworkType := "agent"
@@ -179,7 +199,7 @@ func buildViaFramework(out io.Writer, projectDir string, obj v1alpha1.Object, fl
 
 	p, ok := r.Lookup(frameworkType, cfg.Framework, cfg.Language)
 	if !ok {
-		return fmt.Errorf("no framework for %s framework=%s language=%s", frameworkType, cfg.Framework, cfg.Language)
+		return "", fmt.Errorf("no framework for %s framework=%s language=%s", frameworkType, cfg.Framework, cfg.Language)
 	}
 
 	image := resolveImage(flagImage, specImage, obj.GetMetadata().Name)
@@ -192,11 +212,11 @@ func buildViaFramework(out io.Writer, projectDir string, obj v1alpha1.Object, fl
 
 	rendered, err := frameworks.RenderArgs(p.Build.Command, vars)
 	if err != nil {
-		return fmt.Errorf("render build command: %w", err)
+		return "", fmt.Errorf("render build command: %w", err)
 	}
 	fmt.Fprintf(out, "→ %s: %s\n", p.Name, strings.Join(rendered, " "))
 	if err := frameworks.ExecForeground(p.Build, projectDir, vars, nil); err != nil {
-		return fmt.Errorf("framework build: %w", err)
+		return "", fmt.Errorf("framework build: %w", err)
 	}
 	if push {
 		fmt.Fprintf(out, "→ pushing %s...\n", image)
@@ -204,10 +224,26 @@ func buildViaFramework(out io.Writer, projectDir string, obj v1alpha1.Object, fl
 		pushCmd.Stdout = out
 		pushCmd.Stderr = out
 		if err := pushCmd.Run(); err != nil {
-			return fmt.Errorf("docker push: %w", err)
+			return "", fmt.Errorf("docker push: %w", err)
 		}
 	}
 	fmt.Fprintf(out, "✓ Built %s\n", image)
+	return image, nil
+}
+
+// attachMetadata pushes obj's metadata artifact as a referrer of image, using
+// the same Docker credentials `docker push` just used.
+func attachMetadata(ctx context.Context, out io.Writer, image string, obj v1alpha1.Object) error {
+	artifact, err := ociartifact.ForObject(obj)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "→ attaching metadata to %s...\n", image)
+	pushed, err := ociartifact.Attach(ctx, image, artifact, ociOptions()...)
+	if err != nil {
+		return fmt.Errorf("attach metadata: %w", err)
+	}
+	fmt.Fprintf(out, "✓ Attached metadata %s\n", pushed)
 	return nil
 }
 
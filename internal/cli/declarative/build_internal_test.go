@@ -0,0 +1,47 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/ociartifact"
+)
+
+func TestAttachMetadata_PushesReferrer(t *testing.T) {
+	srv := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(srv.Close)
+	image := strings.TrimPrefix(srv.URL, "http://") + "/acme/weather:1.0.0"
+
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	tag, err := name.NewTag(image)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(tag, img))
+
+	server := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Name: "weather", Tag: "1.0.0"},
+	}
+	var out bytes.Buffer
+	require.NoError(t, attachMetadata(context.Background(), &out, image, server))
+	require.Contains(t, out.String(), "Attached metadata")
+
+	digest, err := img.Digest()
+	require.NoError(t, err)
+	index, err := remote.Referrers(tag.Context().Digest(digest.String()))
+	require.NoError(t, err)
+	manifest, err := index.IndexManifest()
+	require.NoError(t, err)
+	require.Len(t, manifest.Manifests, 1)
+	require.Equal(t, ociartifact.ArtifactType(v1alpha1.KindMCPServer), manifest.Manifests[0].ArtifactType)
+}
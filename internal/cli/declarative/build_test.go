@@ -176,3 +176,13 @@ func TestBuild_DispatchesViaFramework(t *testing.T) {
 	assert.Equal(t, "adk", cfg.Framework)
 	assert.Equal(t, "python", cfg.Language)
 }
+
+// TestBuildCmd_AttachMetadataRequiresPush verifies --attach-metadata is
+// rejected up front when the image is not being pushed.
+func TestBuildCmd_AttachMetadataRequiresPush(t *testing.T) {
+	cmd := declarative.NewBuildCmd(declarativeTestDeps(nil))
+	cmd.SetArgs([]string{t.TempDir(), "--attach-metadata"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--attach-metadata requires --push")
+}
@@ -0,0 +1,197 @@
+package declarative
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// upgradeLookups resolves what warnCapabilityBreaks compares against. Each
+// returns a non-nil error when the object can't be resolved; the affected
+// check is skipped.
+type upgradeLookups struct {
+	// deployment returns the spec of the Deployment as it runs today.
+	deployment func(namespace, name string) (*v1alpha1.DeploymentSpec, error)
+	// agentServers returns the MCPServer refs an Agent version declares.
+	agentServers func(namespace, name, tag string) ([]v1alpha1.ResourceRef, error)
+	diff         func(namespace, name, from, to string) (*arv0.CapabilityDiff, error)
+}
+
+// registryUpgradeLookups resolves upgrades against the registry.
+func registryUpgradeLookups(ctx context.Context, c *client.Client) upgradeLookups {
+	return upgradeLookups{
+		deployment: func(namespace, name string) (*v1alpha1.DeploymentSpec, error) {
+			obj, err := c.GetLatest(ctx, v1alpha1.KindDeployment, namespace, name)
+			if err != nil {
+				return nil, err
+			}
+			var spec v1alpha1.DeploymentSpec
+			if err := json.Unmarshal(obj.Spec, &spec); err != nil {
+				return nil, fmt.Errorf("decoding deployment %s: %w", name, err)
+			}
+			return &spec, nil
+		},
+		agentServers: func(namespace, name, tag string) ([]v1alpha1.ResourceRef, error) {
+			obj, err := c.Get(ctx, v1alpha1.KindAgent, namespace, name, tagOrLatest(tag))
+			if err != nil {
+				return nil, err
+			}
+			var spec v1alpha1.AgentSpec
+			if err := json.Unmarshal(obj.Spec, &spec); err != nil {
+				return nil, fmt.Errorf("decoding agent %s: %w", name, err)
+			}
+			return spec.MCPServers, nil
+		},
+		diff: func(namespace, name, from, to string) (*arv0.CapabilityDiff, error) {
+			return c.CapabilityDiff(ctx, namespace, name, from, to)
+		},
+	}
+}
+
+// serverUpgrade is one MCPServer a Deployment moves from one tag to another.
+type serverUpgrade struct {
+	namespace, name, from, to string
+}
+
+// warnCapabilityBreaks compares every Deployment in data with the one
+// already running under the same name. When the change moves an MCPServer
+// to another tag, either as the direct target or through the target
+// Agent's spec.mcpServers, and the registry reports the upgrade as
+// capability-breaking, a warning is written to out. Warnings never block
+// the apply. Agents defined in the same stream take precedence over the
+// registry copy, as in fillDeploymentSecrets.
+func warnCapabilityBreaks(data []byte, lookups upgradeLookups, out io.Writer) error {
+	docs, err := splitYAMLDocs(data)
+	if err != nil {
+		return err
+	}
+
+	local := map[string][]v1alpha1.ResourceRef{}
+	for _, root := range mappingRoots(docs) {
+		if scalarValue(root, "kind") != v1alpha1.KindAgent {
+			continue
+		}
+		var agent v1alpha1.Agent
+		if err := root.Decode(&agent); err != nil {
+			continue
+		}
+		local[agentKey(agent.Metadata.Namespace, agent.Metadata.Name, agent.Metadata.Tag)] = agent.Spec.MCPServers
+	}
+	agentServers := func(ref v1alpha1.ResourceRef) ([]v1alpha1.ResourceRef, error) {
+		if refs, ok := local[agentKey(ref.Namespace, ref.Name, ref.Tag)]; ok {
+			return refs, nil
+		}
+		return lookups.agentServers(ref.Namespace, ref.Name, ref.Tag)
+	}
+
+	for _, root := range mappingRoots(docs) {
+		if scalarValue(root, "kind") != v1alpha1.KindDeployment {
+			continue
+		}
+		var dep v1alpha1.Deployment
+		if err := root.Decode(&dep); err != nil {
+			continue
+		}
+		ns := dep.Metadata.Namespace
+		if ns == "" {
+			ns = v1alpha1.DefaultNamespace
+		}
+		current, err := lookups.deployment(ns, dep.Metadata.Name)
+		if err != nil {
+			continue
+		}
+		from, to := withNamespace(current.TargetRef, ns), withNamespace(dep.Spec.TargetRef, ns)
+		if from.Kind != to.Kind || from.Namespace != to.Namespace || from.Name != to.Name {
+			continue
+		}
+
+		var upgrades []serverUpgrade
+		switch to.Kind {
+		case v1alpha1.KindMCPServer:
+			upgrades = serverUpgrades([]v1alpha1.ResourceRef{from}, []v1alpha1.ResourceRef{to}, ns)
+		case v1alpha1.KindAgent:
+			fromServers, err := agentServers(from)
+			if err != nil {
+				continue
+			}
+			toServers, err := agentServers(to)
+			if err != nil {
+				continue
+			}
+			upgrades = serverUpgrades(fromServers, toServers, to.Namespace)
+		}
+
+		for _, u := range upgrades {
+			diff, err := lookups.diff(u.namespace, u.name, u.from, u.to)
+			if err != nil || !diff.Breaking {
+				continue
+			}
+			fmt.Fprintf(out, "Warning: Deployment %s upgrades MCPServer %s from %s to %s across a breaking capability change: %s\n",
+				dep.Metadata.Name, u.name, u.from, u.to, describeBreaks(diff))
+		}
+	}
+	return nil
+}
+
+// serverUpgrades pairs MCPServer refs by name and returns those whose tag
+// differs. Refs without a namespace resolve in namespace; refs to a peer
+// registry are skipped since this registry can't diff them.
+func serverUpgrades(from, to []v1alpha1.ResourceRef, namespace string) []serverUpgrade {
+	before := map[string]string{}
+	for _, ref := range from {
+		ref = withNamespace(ref, namespace)
+		if ref.Registry == "" && (ref.Kind == "" || ref.Kind == v1alpha1.KindMCPServer) {
+			before[ref.Namespace+"/"+ref.Name] = tagOrLatest(ref.Tag)
+		}
+	}
+	var out []serverUpgrade
+	for _, ref := range to {
+		ref = withNamespace(ref, namespace)
+		if ref.Registry != "" || (ref.Kind != "" && ref.Kind != v1alpha1.KindMCPServer) {
+			continue
+		}
+		tag, ok := before[ref.Namespace+"/"+ref.Name]
+		if !ok || tag == tagOrLatest(ref.Tag) {
+			continue
+		}
+		out = append(out, serverUpgrade{namespace: ref.Namespace, name: ref.Name, from: tag, to: tagOrLatest(ref.Tag)})
+	}
+	return out
+}
+
+// describeBreaks summarizes the breaking parts of a diff on one line.
+func describeBreaks(diff *arv0.CapabilityDiff) string {
+	var parts []string
+	for _, name := range diff.Removed {
+		parts = append(parts, fmt.Sprintf("tool %s removed", name))
+	}
+	for _, r := range diff.Renamed {
+		parts = append(parts, fmt.Sprintf("tool %s renamed to %s", r.From, r.To))
+	}
+	for _, change := range diff.Changed {
+		if change.Breaking {
+			parts = append(parts, fmt.Sprintf("tool %s: %s", change.Name, strings.Join(change.Changes, ", ")))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+func withNamespace(ref v1alpha1.ResourceRef, namespace string) v1alpha1.ResourceRef {
+	if ref.Namespace == "" {
+		ref.Namespace = namespace
+	}
+	return ref
+}
+
+func tagOrLatest(tag string) string {
+	if tag == "" {
+		return "latest"
+	}
+	return tag
+}
@@ -0,0 +1,122 @@
+package declarative
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+const upgradeDeploymentYAML = `apiVersion: ar.dev/v1alpha1
+kind: Deployment
+metadata:
+  name: bot-prod
+spec:
+  targetRef:
+    kind: Agent
+    name: bot
+    tag: "2.0.0"
+  runtimeRef:
+    kind: Runtime
+    name: k8s
+`
+
+func TestWarnCapabilityBreaks(t *testing.T) {
+	agents := map[string][]v1alpha1.ResourceRef{
+		"1.0.0": {{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "1.0.0"}, {Kind: v1alpha1.KindMCPServer, Name: "search"}},
+		"2.0.0": {{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "2.0.0"}, {Kind: v1alpha1.KindMCPServer, Name: "search"}},
+	}
+	var diffed []string
+	lookups := upgradeLookups{
+		deployment: func(namespace, name string) (*v1alpha1.DeploymentSpec, error) {
+			require.Equal(t, v1alpha1.DefaultNamespace, namespace)
+			require.Equal(t, "bot-prod", name)
+			return &v1alpha1.DeploymentSpec{TargetRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "bot", Tag: "1.0.0"}}, nil
+		},
+		agentServers: func(namespace, name, tag string) ([]v1alpha1.ResourceRef, error) {
+			refs, ok := agents[tag]
+			if !ok {
+				return nil, errors.New("not found")
+			}
+			return refs, nil
+		},
+		diff: func(namespace, name, from, to string) (*arv0.CapabilityDiff, error) {
+			diffed = append(diffed, name+"@"+from+"->"+to)
+			return &arv0.CapabilityDiff{
+				Breaking: true,
+				Removed:  []string{"alerts"},
+				Changed:  []arv0.ToolChange{{Name: "forecast", Breaking: true, Changes: []string{`required property "units" added`}}},
+			}, nil
+		},
+	}
+
+	t.Run("agent upgrade across a breaking server change", func(t *testing.T) {
+		diffed = nil
+		var out bytes.Buffer
+		require.NoError(t, warnCapabilityBreaks([]byte(upgradeDeploymentYAML), lookups, &out))
+		require.Equal(t, []string{"weather@1.0.0->2.0.0"}, diffed)
+		require.Equal(t, "Warning: Deployment bot-prod upgrades MCPServer weather from 1.0.0 to 2.0.0 across a breaking capability change: "+
+			"tool alerts removed; tool forecast: required property \"units\" added\n", out.String())
+	})
+
+	t.Run("agent in the same stream wins over the registry", func(t *testing.T) {
+		diffed = nil
+		data := `apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  name: bot
+  tag: "2.0.0"
+spec:
+  mcpServers:
+    - kind: MCPServer
+      name: weather
+      tag: "3.0.0"
+---
+` + upgradeDeploymentYAML
+		require.NoError(t, warnCapabilityBreaks([]byte(data), lookups, &bytes.Buffer{}))
+		require.Equal(t, []string{"weather@1.0.0->3.0.0"}, diffed)
+	})
+
+	t.Run("direct server upgrade", func(t *testing.T) {
+		diffed = nil
+		direct := lookups
+		direct.deployment = func(namespace, name string) (*v1alpha1.DeploymentSpec, error) {
+			return &v1alpha1.DeploymentSpec{TargetRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "1.0.0"}}, nil
+		}
+		data := `apiVersion: ar.dev/v1alpha1
+kind: Deployment
+metadata:
+  name: weather-prod
+spec:
+  targetRef:
+    kind: MCPServer
+    name: weather
+  runtimeRef:
+    kind: Runtime
+    name: k8s
+`
+		require.NoError(t, warnCapabilityBreaks([]byte(data), direct, &bytes.Buffer{}))
+		require.Equal(t, []string{"weather@1.0.0->latest"}, diffed)
+	})
+
+	t.Run("new deployments and non-breaking changes stay quiet", func(t *testing.T) {
+		quiet := lookups
+		quiet.deployment = func(namespace, name string) (*v1alpha1.DeploymentSpec, error) {
+			return nil, errors.New("not found")
+		}
+		var out bytes.Buffer
+		require.NoError(t, warnCapabilityBreaks([]byte(upgradeDeploymentYAML), quiet, &out))
+		require.Empty(t, out.String())
+
+		quiet = lookups
+		quiet.diff = func(namespace, name, from, to string) (*arv0.CapabilityDiff, error) {
+			return &arv0.CapabilityDiff{Added: []string{"radar"}}, nil
+		}
+		require.NoError(t, warnCapabilityBreaks([]byte(upgradeDeploymentYAML), quiet, &out))
+		require.Empty(t, out.String())
+	})
+}
@@ -9,6 +9,7 @@ package chat
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -19,14 +20,14 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/reflow/wordwrap"
-	a2aclient "trpc.group/trpc-go/trpc-a2a-go/client"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 
+	"github.com/agentregistry-dev/agentregistry/internal/a2a"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative/chat/theme"
 )
 
-// SendMessageFn mirrors the A2A client's StreamMessage signature.
-type SendMessageFn func(ctx context.Context, params protocol.SendMessageParams) (<-chan protocol.StreamingMessageEvent, error)
+// SendMessageFn mirrors a2a.Client.Stream.
+type SendMessageFn func(ctx context.Context, params protocol.SendMessageParams) (*a2a.Stream, error)
 
 // RunChat starts the chat UI and blocks until the user exits.
 func RunChat(agentRef string, sessionID string, sendFn SendMessageFn, verbose bool) error {
@@ -38,24 +39,25 @@ func RunChat(agentRef string, sessionID string, sendFn SendMessageFn, verbose bo
 
 // LaunchA2A is a convenience wrapper that creates an A2A client against
 // agentURL and starts the chat TUI. Used by `arctl run` after the agent
-// passes its readiness probe.
-func LaunchA2A(ctx context.Context, agentName, agentURL string, verbose bool) error {
+// passes its readiness probe. opts set the client's timeouts and
+// reconnects.
+func LaunchA2A(ctx context.Context, agentName, agentURL string, opts a2a.Options, verbose bool) error {
 	sessionID := protocol.GenerateContextID()
-	client, err := a2aclient.NewA2AClient(agentURL, a2aclient.WithTimeout(60*time.Second))
+	client, err := a2a.NewClient(agentURL, opts)
 	if err != nil {
 		return fmt.Errorf("create chat client: %w", err)
 	}
-	sendFn := func(ctx context.Context, params protocol.SendMessageParams) (<-chan protocol.StreamingMessageEvent, error) {
-		return client.StreamMessage(ctx, params)
-	}
-	return RunChat(agentName, sessionID, sendFn, verbose)
+	return RunChat(agentName, sessionID, client.Stream, verbose)
 }
 
 type a2aEventMsg struct {
 	Event protocol.StreamingMessageEvent
 }
 
-type streamDoneMsg struct{}
+// streamDoneMsg ends a turn; err is why it ended early, if it did.
+type streamDoneMsg struct {
+	err error
+}
 
 type toolCall struct {
 	Name string `json:"name"`
@@ -85,7 +87,7 @@ type chatModel struct {
 	spin spinner.Model
 
 	send      SendMessageFn
-	streamCh  <-chan protocol.StreamingMessageEvent
+	stream    *a2a.Stream
 	cancel    context.CancelFunc
 	streaming bool
 
@@ -191,6 +193,9 @@ func (m *chatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.appendEvent(msg.Event)
 		return m, m.waitNext()
 	case streamDoneMsg:
+		if msg.err != nil && !errors.Is(msg.err, context.Canceled) {
+			m.appendError(msg.err)
+		}
 		m.streaming = false
 		m.working = false
 		m.updateStatus()
@@ -245,26 +250,26 @@ func (m *chatModel) submit(text string) tea.Cmd {
 		},
 	}
 
-	ch, err := m.send(ctx, params)
+	stream, err := m.send(ctx, params)
 	if err != nil {
 		m.appendError(err)
 		m.streaming = false
 		cancel()
 		return nil
 	}
-	m.streamCh = ch
+	m.stream = stream
 	return tea.Batch(m.waitNext(), m.tick())
 }
 
 func (m *chatModel) waitNext() tea.Cmd {
-	ch := m.streamCh
-	if ch == nil {
+	stream := m.stream
+	if stream == nil {
 		return nil
 	}
 	return func() tea.Msg {
-		ev, ok := <-ch
+		ev, ok := <-stream.Events()
 		if !ok {
-			return streamDoneMsg{}
+			return streamDoneMsg{err: stream.Err()}
 		}
 		return a2aEventMsg{Event: ev}
 	}
@@ -84,6 +84,14 @@ func init() {
 		promptRow,
 	))
 
+	scheme.Register(typedKind(
+		"chart", "charts", []string{"Chart"},
+		[]scheme.Column{{Header: "NAME"}, {Header: "TAG"}, {Header: "CHART"}, {Header: "VERSION"}},
+		v1alpha1.KindChart,
+		func() *v1alpha1.Chart { return &v1alpha1.Chart{} },
+		chartRow,
+	))
+
 	// Runtime is registered manually because it is a mutable namespace/name
 	// object: the server's runtime store does not expose /tags or
 	// DeleteAllTags endpoints. Routing it through
@@ -101,6 +109,36 @@ func init() {
 		),
 	)
 
+	scheme.Register(
+		mutableTypedKind(
+			"webhook", "webhooks", []string{"Webhook"},
+			[]scheme.Column{{Header: "NAME"}, {Header: "URL"}, {Header: "EVENTS"}},
+			v1alpha1.KindWebhook,
+			func() *v1alpha1.Webhook { return &v1alpha1.Webhook{} },
+			webhookRow,
+		),
+	)
+
+	scheme.Register(
+		mutableTypedKind(
+			"flag", "flags", []string{"FeatureFlag", "featureflags"},
+			[]scheme.Column{{Header: "NAME"}, {Header: "ENABLED"}, {Header: "OVERRIDES"}},
+			v1alpha1.KindFeatureFlag,
+			func() *v1alpha1.FeatureFlag { return &v1alpha1.FeatureFlag{} },
+			featureFlagRow,
+		),
+	)
+
+	scheme.Register(
+		mutableTypedKind(
+			"deploymenttemplate", "deploymenttemplates", []string{"DeploymentTemplate", "template", "templates"},
+			[]scheme.Column{{Header: "NAME"}, {Header: "RUNTIME"}, {Header: "ENV"}, {Header: "DESCRIPTION"}},
+			v1alpha1.KindDeploymentTemplate,
+			func() *v1alpha1.DeploymentTemplate { return &v1alpha1.DeploymentTemplate{} },
+			deploymentTemplateRow,
+		),
+	)
+
 	// Deployment is registered manually because it is a mutable namespace/name
 	// object: the server's deployment store does not expose /tags or
 	// DeleteAllTags endpoints. Explicit get/delete accept either NAME or
@@ -112,6 +150,7 @@ func init() {
 			[]scheme.Column{
 				{Header: "NAME"}, {Header: "TARGET"}, {Header: "VERSION"},
 				{Header: "TYPE"}, {Header: "RUNTIME"}, {Header: "STATUS"},
+				{Header: "RESOLVED"},
 			},
 			v1alpha1.KindDeployment,
 			func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} },
@@ -0,0 +1,113 @@
+package declarative
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+	"github.com/agentregistry-dev/agentregistry/pkg/printer"
+)
+
+// NewDeploymentCmd returns the "deployment" command group for creating,
+// inspecting and sharing deployments, and for deployment-wide reports that
+// don't fit get/apply/delete.
+func NewDeploymentCmd(deps cliruntime.Deps) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     cliruntime.CommandDeployment,
+		Aliases: []string{"deployments"},
+		Short:   "Create, describe, expose, share and report on deployments",
+	}
+	cmd.AddCommand(newDeploymentCreateCmd(deps))
+	cmd.AddCommand(newDeploymentOutdatedCmd(deps))
+	cmd.AddCommand(newDeploymentExposeCmd(deps))
+	cmd.AddCommand(newDeploymentLogsCmd(deps))
+	cmd.AddCommand(newDeploymentNotesCmd(deps))
+	cmd.AddCommand(newDeploymentShareCmd(deps))
+	cmd.AddCommand(newDeploymentDescribeCmd(deps))
+	return cmd
+}
+
+func newDeploymentOutdatedCmd(deps cliruntime.Deps) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "List deployments whose pinned artifacts have newer or deprecated versions",
+		Long: `List deployments whose pinned artifacts have newer or deprecated versions.
+
+Checks each deployment's target and, for agents, the MCP servers, skills,
+plugins, charts and instructions the pinned agent version references.
+References without a tag follow "latest" and are only reported when that
+version is deprecated.
+
+Severity:
+  high    pinned version deprecated, newer major version, or a newer
+          version's changelog flags a BREAKING change
+  medium  newer minor version
+  low     newer patch version, or newer non-semver tags`,
+		Example: `  arctl deployment outdated
+  arctl deployment outdated --min-severity high
+  arctl deployment outdated -o json`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDeploymentOutdated(cmd, deps)
+		},
+	}
+	cmd.Flags().StringP("output", "o", "table", "Output format: table, yaml, json")
+	cmd.Flags().String("min-severity", "low", "Only show deployments at or above this severity: low, medium, high")
+	return cmd
+}
+
+func runDeploymentOutdated(cmd *cobra.Command, deps cliruntime.Deps) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	minSeverity, _ := cmd.Flags().GetString("min-severity")
+	switch minSeverity {
+	case "low", "medium", "high":
+	default:
+		return fmt.Errorf("--min-severity must be low, medium or high (got %q)", minSeverity)
+	}
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	report, err := c.OutdatedDeployments(cmd.Context(), v1alpha1.DefaultNamespace, minSeverity)
+	if err != nil {
+		return fmt.Errorf("fetching outdated deployments: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return marshalJSON(cmd, report)
+	case "yaml":
+		return marshalYAML(cmd, report)
+	}
+	if len(report.Deployments) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "All deployments are up to date.")
+		return nil
+	}
+	t := printer.NewTablePrinter(cmd.OutOrStdout())
+	t.SetHeaders("DEPLOYMENT", "SEVERITY", "ARTIFACT", "PINNED", "LATEST", "REASON")
+	for _, dep := range report.Deployments {
+		for _, art := range dep.Artifacts {
+			t.AddRow(
+				dep.Name,
+				art.Severity,
+				fmt.Sprintf("%s/%s", art.Kind, art.Name),
+				art.Pinned,
+				printer.EmptyValueOrDefault(art.Latest, "-"),
+				printer.TruncateString(art.Reason, 60),
+			)
+		}
+	}
+	if err := t.Render(); err != nil {
+		return err
+	}
+	s := report.Summary
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "\nOutdated deployments: %d (%d high, %d medium, %d low)\n",
+		len(report.Deployments), s.High, s.Medium, s.Low)
+	return err
+}
@@ -2,6 +2,9 @@ package declarative_test
 
 import (
 	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -58,3 +61,77 @@ func TestDeploymentApply_InvalidTemplateRefSurfaces(t *testing.T) {
 	assert.Contains(t, output, "not found",
 		"the server's error message should be surfaced to the user")
 }
+
+const stagingDeploymentYAML = `apiVersion: ar.dev/v1alpha1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    env: staging
+spec:
+  targetRef:
+    kind: Agent
+    name: web
+    tag: "1.0.0"
+  runtimeRef:
+    kind: Runtime
+    name: local
+`
+
+// TestDeploymentApply_PruneDeletesUndeclared asserts --prune deletes the
+// managed Deployments matching the selector that the file no longer
+// declares, and leaves the declared ones alone.
+func TestDeploymentApply_PruneDeletesUndeclared(t *testing.T) {
+	var listQuery string
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v0/apply":
+			_, _ = w.Write(batchApplyResponse([]arv0.ApplyResult{{Kind: "Deployment", Name: "web", Status: arv0.ApplyStatusUnchanged}}))
+		case r.Method == http.MethodGet && r.URL.Path == "/v0/deployments":
+			listQuery = r.URL.RawQuery
+			_, _ = w.Write([]byte(`{"items":[{"kind":"Deployment","metadata":{"name":"web"}},{"kind":"Deployment","metadata":{"name":"old"}}]}`))
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v0/deployments/"):
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/v0/deployments/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewApplyCmd(applyDeps(t, srv))
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"-f", writeTempYAML(t, stagingDeploymentYAML), "--prune", "--selector", "env=staging"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, []string{"old"}, deleted)
+	assert.Contains(t, listQuery, "labels=env%3Dstaging")
+	assert.Contains(t, listQuery, "origin=managed")
+	assert.Contains(t, out.String(), "✓ Deployment/old deleted")
+}
+
+// TestDeploymentApply_PruneRequiresOwnedDeployments asserts --prune refuses,
+// before sending anything, files whose Deployments lack the selector labels,
+// and a missing selector.
+func TestDeploymentApply_PruneRequiresOwnedDeployments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	t.Cleanup(srv.Close)
+
+	for args, want := range map[string]string{
+		"--prune --selector env=prod": "must carry label env=prod",
+		"--prune":                     "--prune requires --selector",
+		"--selector env=prod":         "--selector requires --prune",
+	} {
+		cmd := declarative.NewApplyCmd(applyDeps(t, srv))
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append([]string{"-f", writeTempYAML(t, stagingDeploymentYAML)}, strings.Fields(args)...))
+		require.ErrorContains(t, cmd.Execute(), want, args)
+	}
+}
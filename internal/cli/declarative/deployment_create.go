@@ -0,0 +1,216 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+type deploymentCreateOptions struct {
+	target   string
+	tag      string
+	runtime  string
+	template string
+	snapshot string
+	env      []string
+	dryRun   bool
+}
+
+func newDeploymentCreateCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts deploymentCreateOptions
+	cmd := &cobra.Command{
+		Use:   "create NAME --target KIND/NAME",
+		Short: "Create a Deployment, optionally from a DeploymentTemplate",
+		Long: `Create a Deployment of an Agent, MCPServer or Chart without writing a
+manifest.
+
+--template fills in what the Deployment does not set itself from a
+DeploymentTemplate in the registry: its runtimeRef or runtimeSelector, each
+env and runtimeConfig key, and each resource request and limit. Flags win
+over the template. The Deployment records the template it was created from
+in the ` + v1alpha1.DeploymentTemplateAnnotation + ` annotation; later
+edits of the template do not change it.
+
+--snapshot pins the Deployment to a catalog snapshot: the target, and
+every artifact it refers to, resolve to the versions the snapshot holds
+rather than the live catalog (see arctl registry admin snapshot).
+
+Without --runtime or a template placement, the Deployment runs on the
+default Runtime of the arctl context in use.`,
+		Example: `  arctl deployment create summarizer-prod --target agent/summarizer --tag 1.2.0 --template prod-gpu
+  arctl deployment create github-mcp --target mcpserver/github --runtime local -e LOG_LEVEL=debug
+  arctl deployment create summarizer-prod --target agent/summarizer --template prod-gpu --dry-run
+  arctl deployment create summarizer-staging --target agent/summarizer --snapshot 2024-06-release`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDeploymentCreate(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), deps, args[0], opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.target, "target", "", "What to deploy, as KIND/NAME (agent, mcpserver or chart)")
+	cmd.Flags().StringVar(&opts.tag, "tag", "", "Tag of the target to deploy (default: latest)")
+	cmd.Flags().StringVar(&opts.runtime, "runtime", "", "Runtime to deploy to")
+	cmd.Flags().StringVar(&opts.template, "template", "", "DeploymentTemplate to take defaults from")
+	cmd.Flags().StringVar(&opts.snapshot, "snapshot", "", "Catalog snapshot to resolve the target and its refs from")
+	cmd.Flags().StringArrayVarP(&opts.env, "env", "e", nil, "KEY=VALUE env var of the Deployment (repeatable)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Validate the Deployment on the server without creating it")
+	_ = cmd.MarkFlagRequired("target")
+	return cmd
+}
+
+func runDeploymentCreate(ctx context.Context, out, errOut io.Writer, deps cliruntime.Deps, name string, opts deploymentCreateOptions) error {
+	deployment, err := newDeploymentFromFlags(deps, name, opts)
+	if err != nil {
+		return err
+	}
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+
+	// Apply would replace an existing Deployment of the same name.
+	switch _, err := c.GetLatest(ctx, v1alpha1.KindDeployment, deployment.Metadata.Namespace, name); {
+	case err == nil:
+		return fmt.Errorf("deployment %q already exists; change it with arctl apply", name)
+	case !errors.Is(err, client.ErrNotFound):
+		return fmt.Errorf("checking deployment %q: %w", name, err)
+	}
+
+	if opts.template != "" {
+		tmpl, err := client.GetTyped(ctx, c, v1alpha1.KindDeploymentTemplate, deployment.Metadata.Namespace, opts.template, "",
+			func() *v1alpha1.DeploymentTemplate { return &v1alpha1.DeploymentTemplate{} })
+		if err != nil {
+			return fmt.Errorf("fetching deployment template %q: %w", opts.template, err)
+		}
+		applyDeploymentTemplate(deployment, tmpl, errOut)
+	}
+
+	data, err := yaml.Marshal(deployment)
+	if err != nil {
+		return fmt.Errorf("encode deployment: %w", err)
+	}
+	target := deps.Runtime.RegistryTarget()
+	data, err = defaultDeploymentRuntime(data, target.Runtime, target.Context, errOut)
+	if err != nil {
+		return err
+	}
+	if err := previewDeploymentDefaults(data, registryRuntimeDefaults(ctx, c), errOut); err != nil {
+		return err
+	}
+
+	results, err := c.Apply(ctx, data, client.ApplyOpts{DryRun: opts.dryRun})
+	if err != nil {
+		return fmt.Errorf("creating deployment %q: %w", name, err)
+	}
+	printResults(out, results, opts.dryRun)
+	for _, r := range results {
+		if r.Status == arv0.ApplyStatusFailed {
+			return fmt.Errorf("creating deployment %q failed", name)
+		}
+	}
+	return nil
+}
+
+// newDeploymentFromFlags builds the Deployment the create flags describe,
+// before any template is applied.
+func newDeploymentFromFlags(deps cliruntime.Deps, name string, opts deploymentCreateOptions) (*v1alpha1.Deployment, error) {
+	kindName, targetName, ok := strings.Cut(opts.target, "/")
+	if !ok || kindName == "" || targetName == "" {
+		return nil, fmt.Errorf("--target must be KIND/NAME, got %q", opts.target)
+	}
+	kinds := kindRegistry(deps)
+	k, err := kinds.Lookup(kindName)
+	if err != nil {
+		return nil, fmt.Errorf("--target: %w", err)
+	}
+	// Resolve the CLI kind (any of its aliases) to the API kind it names.
+	var targetKind string
+	for _, kind := range []string{v1alpha1.KindAgent, v1alpha1.KindMCPServer, v1alpha1.KindChart} {
+		if deployable, err := kinds.Lookup(kind); err == nil && deployable == k {
+			targetKind = kind
+		}
+	}
+	if targetKind == "" {
+		return nil, fmt.Errorf("--target: cannot deploy a %s; expected an agent, mcpserver or chart", k.Kind)
+	}
+
+	deployment := &v1alpha1.Deployment{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindDeployment},
+		Metadata: v1alpha1.ObjectMeta{Namespace: v1alpha1.DefaultNamespace, Name: name},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef: v1alpha1.ResourceRef{Kind: targetKind, Name: targetName, Tag: opts.tag},
+			Snapshot:  opts.snapshot,
+		},
+	}
+	if opts.runtime != "" {
+		deployment.Spec.RuntimeRef = v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: opts.runtime}
+	}
+	for _, kv := range opts.env {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("--env must be KEY=VALUE, got %q", kv)
+		}
+		if deployment.Spec.Env == nil {
+			deployment.Spec.Env = map[string]string{}
+		}
+		deployment.Spec.Env[key] = value
+	}
+	return deployment, nil
+}
+
+// applyDeploymentTemplate fills deployment in from tmpl, records tmpl in
+// its annotations, and notes on out what the template contributed.
+func applyDeploymentTemplate(deployment *v1alpha1.Deployment, tmpl *v1alpha1.DeploymentTemplate, out io.Writer) {
+	own := deployment.Spec
+	deployment.Spec = tmpl.Spec.ApplyTo(own)
+	if deployment.Metadata.Annotations == nil {
+		deployment.Metadata.Annotations = map[string]string{}
+	}
+	deployment.Metadata.Annotations[v1alpha1.DeploymentTemplateAnnotation] = tmpl.Metadata.Name
+
+	fmt.Fprintf(out, "→ Deployment %s: using DeploymentTemplate %s\n", deployment.Metadata.Name, tmpl.Metadata.Name)
+	if own.RuntimeRef.Name == "" && own.RuntimeSelector == nil {
+		switch {
+		case deployment.Spec.RuntimeRef.Name != "":
+			fmt.Fprintf(out, "  runtime: %s\n", deployment.Spec.RuntimeRef.Name)
+		case deployment.Spec.RuntimeSelector != nil:
+			fmt.Fprintf(out, "  runtimeSelector: %s\n", describeRuntimeSelector(deployment.Spec.RuntimeSelector))
+		}
+	}
+	inheritedEnv, overriddenEnv := splitInherited(deployment.Spec.Env, own.Env, tmpl.Spec.Env)
+	if len(inheritedEnv) > 0 {
+		fmt.Fprintf(out, "  env: %s\n", strings.Join(inheritedEnv, ", "))
+	}
+	if inheritedCfg, _ := splitInherited(deployment.Spec.RuntimeConfig, own.RuntimeConfig, tmpl.Spec.RuntimeConfig); len(inheritedCfg) > 0 {
+		fmt.Fprintf(out, "  runtimeConfig: %s\n", strings.Join(inheritedCfg, ", "))
+	}
+	if len(overriddenEnv) > 0 {
+		fmt.Fprintf(out, "  env overridden by flags: %s\n", strings.Join(overriddenEnv, ", "))
+	}
+}
+
+func describeRuntimeSelector(sel *v1alpha1.RuntimeSelector) string {
+	parts := make([]string, 0, len(sel.MatchLabels)+1)
+	if sel.Type != "" {
+		parts = append(parts, "type="+sel.Type)
+	}
+	for _, key := range slices.Sorted(maps.Keys(sel.MatchLabels)) {
+		parts = append(parts, key+"="+sel.MatchLabels[key])
+	}
+	return strings.Join(parts, ",")
+}
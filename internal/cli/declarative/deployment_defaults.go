@@ -0,0 +1,181 @@
+package declarative
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// runtimeDefaultsLookup returns the deploymentDefaults of a Runtime. A
+// non-nil error means the Runtime couldn't be resolved; the Deployment is
+// treated as inheriting nothing and the server reports the unresolved
+// runtimeRef instead.
+type runtimeDefaultsLookup func(namespace, name string) (*v1alpha1.DeploymentDefaults, error)
+
+// registryRuntimeDefaults looks Runtimes up on the registry.
+func registryRuntimeDefaults(ctx context.Context, c *client.Client) runtimeDefaultsLookup {
+	return func(namespace, name string) (*v1alpha1.DeploymentDefaults, error) {
+		obj, err := c.GetLatest(ctx, v1alpha1.KindRuntime, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		var spec v1alpha1.RuntimeSpec
+		if err := json.Unmarshal(obj.Spec, &spec); err != nil {
+			return nil, fmt.Errorf("decoding runtime %s: %w", name, err)
+		}
+		return spec.DeploymentDefaults, nil
+	}
+}
+
+// deploymentDefaultsResolver returns the defaults each Deployment in docs
+// inherits from its Runtime. Runtimes defined in the same stream take
+// precedence over the registry copy; a nil lookup only sees those.
+func deploymentDefaultsResolver(docs []*yaml.Node, lookup runtimeDefaultsLookup) func(dep *v1alpha1.Deployment) *v1alpha1.DeploymentDefaults {
+	local := map[string]*v1alpha1.DeploymentDefaults{}
+	for _, root := range mappingRoots(docs) {
+		if scalarValue(root, "kind") != v1alpha1.KindRuntime {
+			continue
+		}
+		var rt v1alpha1.Runtime
+		if err := root.Decode(&rt); err != nil {
+			continue
+		}
+		local[runtimeKey(rt.Metadata.Namespace, rt.Metadata.Name)] = rt.Spec.DeploymentDefaults
+	}
+	return func(dep *v1alpha1.Deployment) *v1alpha1.DeploymentDefaults {
+		ref := withNamespace(dep.Spec.RuntimeRef, dep.Metadata.Namespace)
+		if defaults, ok := local[runtimeKey(ref.Namespace, ref.Name)]; ok {
+			return defaults
+		}
+		// A Deployment placed by its runtimeSelector has no Runtime until
+		// the registry picks one.
+		if lookup == nil || ref.Name == "" {
+			return nil
+		}
+		if ref.Namespace == "" {
+			ref.Namespace = v1alpha1.DefaultNamespace
+		}
+		defaults, err := lookup(ref.Namespace, ref.Name)
+		if err != nil {
+			return nil
+		}
+		return defaults
+	}
+}
+
+// defaultDeploymentRuntime places every Deployment in data that names
+// neither a runtimeRef nor a runtimeSelector on runtime, the default
+// Runtime of the arctl context in use, and notes each one on out. Data
+// without such Deployments comes back unchanged.
+func defaultDeploymentRuntime(data []byte, runtime, contextName string, out io.Writer) ([]byte, error) {
+	if runtime == "" {
+		return data, nil
+	}
+	docs, err := splitYAMLDocs(data)
+	if err != nil {
+		return nil, err
+	}
+	var defaulted bool
+	for _, root := range mappingRoots(docs) {
+		if scalarValue(root, "kind") != v1alpha1.KindDeployment {
+			continue
+		}
+		var dep v1alpha1.Deployment
+		if err := root.Decode(&dep); err != nil {
+			continue
+		}
+		if dep.Spec.RuntimeRef.Name != "" || dep.Spec.RuntimeSelector != nil {
+			continue
+		}
+		spec := findOrCreateMappingChild(root, "spec")
+		ref := findOrCreateMappingChild(spec, "runtimeRef")
+		upsertLabel(ref, "kind", v1alpha1.KindRuntime)
+		upsertLabel(ref, "name", runtime)
+		fmt.Fprintf(out, "→ Deployment %s: using Runtime %s, the default of context %s\n", dep.Metadata.Name, runtime, contextName)
+		defaulted = true
+	}
+	if !defaulted {
+		return data, nil
+	}
+	return marshalYAMLDocs(docs)
+}
+
+// previewDeploymentDefaults writes, for every Deployment in data, which
+// env and runtimeConfig keys it inherits from its Runtime's
+// deploymentDefaults and which defaults its own spec overrides, so an
+// apply or --dry-run shows what the Deployment will actually run with.
+// Deployments that inherit nothing are not mentioned.
+func previewDeploymentDefaults(data []byte, lookup runtimeDefaultsLookup, out io.Writer) error {
+	docs, err := splitYAMLDocs(data)
+	if err != nil {
+		return err
+	}
+	defaultsFor := deploymentDefaultsResolver(docs, lookup)
+
+	for _, root := range mappingRoots(docs) {
+		if scalarValue(root, "kind") != v1alpha1.KindDeployment {
+			continue
+		}
+		var dep v1alpha1.Deployment
+		if err := root.Decode(&dep); err != nil {
+			continue
+		}
+		defaults := defaultsFor(&dep)
+		if defaults == nil {
+			continue
+		}
+		merged := v1alpha1.MergeDeploymentDefaults(dep.Spec, defaults)
+
+		inheritedEnv, overriddenEnv := splitInherited(merged.Env, dep.Spec.Env, defaults.Env)
+		inheritedCfg, overriddenCfg := splitInherited(merged.RuntimeConfig, dep.Spec.RuntimeConfig, defaults.RuntimeConfig)
+		if len(inheritedEnv)+len(inheritedCfg)+len(overriddenEnv)+len(overriddenCfg) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "Deployment %s inherits from Runtime %s:\n", dep.Metadata.Name, dep.Spec.RuntimeRef.Name)
+		if len(inheritedEnv) > 0 {
+			fmt.Fprintf(out, "  env: %s\n", strings.Join(inheritedEnv, ", "))
+		}
+		if len(inheritedCfg) > 0 {
+			fmt.Fprintf(out, "  runtimeConfig: %s\n", strings.Join(inheritedCfg, ", "))
+		}
+		if len(overriddenEnv) > 0 {
+			fmt.Fprintf(out, "  env overridden by the Deployment: %s\n", strings.Join(overriddenEnv, ", "))
+		}
+		if len(overriddenCfg) > 0 {
+			fmt.Fprintf(out, "  runtimeConfig overridden by the Deployment: %s\n", strings.Join(overriddenCfg, ", "))
+		}
+	}
+	return nil
+}
+
+// splitInherited returns, sorted, the keys of merged that came from
+// defaults and the keys of defaults that own replaced.
+func splitInherited[V any](merged, own, defaults map[string]V) (inherited, overridden []string) {
+	for _, key := range slices.Sorted(maps.Keys(merged)) {
+		if _, ok := own[key]; !ok {
+			inherited = append(inherited, key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(defaults)) {
+		if _, ok := own[key]; ok {
+			overridden = append(overridden, key)
+		}
+	}
+	return inherited, overridden
+}
+
+func runtimeKey(namespace, name string) string {
+	if namespace == "" {
+		namespace = v1alpha1.DefaultNamespace
+	}
+	return namespace + "/" + name
+}
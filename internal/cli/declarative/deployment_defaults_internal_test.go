@@ -0,0 +1,136 @@
+package declarative
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+func TestPreviewDeploymentDefaults(t *testing.T) {
+	deployment := `apiVersion: ar.dev/v1alpha1
+kind: Deployment
+metadata:
+  name: bot-prod
+spec:
+  targetRef:
+    kind: Agent
+    name: bot
+  runtimeRef:
+    kind: Runtime
+    name: k8s
+  env:
+    LOG_LEVEL: debug
+  runtimeConfig:
+    replicas: 3
+`
+	registry := func(namespace, name string) (*v1alpha1.DeploymentDefaults, error) {
+		return &v1alpha1.DeploymentDefaults{
+			Env:           map[string]string{"LOG_LEVEL": "info", "HTTPS_PROXY": "http://proxy:3128"},
+			RuntimeConfig: map[string]any{"replicas": 2, "namespace": "agents"},
+		}, nil
+	}
+
+	t.Run("reports inherited and overridden keys", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, previewDeploymentDefaults([]byte(deployment), registry, &out))
+		require.Equal(t, `Deployment bot-prod inherits from Runtime k8s:
+  env: HTTPS_PROXY
+  runtimeConfig: namespace
+  env overridden by the Deployment: LOG_LEVEL
+  runtimeConfig overridden by the Deployment: replicas
+`, out.String())
+	})
+
+	t.Run("runtime in the same stream wins over the registry", func(t *testing.T) {
+		data := `apiVersion: ar.dev/v1alpha1
+kind: Runtime
+metadata:
+  name: k8s
+spec:
+  type: Kubernetes
+---
+` + deployment
+		lookup := func(namespace, name string) (*v1alpha1.DeploymentDefaults, error) {
+			t.Fatalf("registry lookup should not run for %s", name)
+			return nil, nil
+		}
+		var out bytes.Buffer
+		require.NoError(t, previewDeploymentDefaults([]byte(data), lookup, &out))
+		require.Empty(t, out.String())
+	})
+
+	t.Run("unresolvable runtime is left to the server", func(t *testing.T) {
+		lookup := func(namespace, name string) (*v1alpha1.DeploymentDefaults, error) {
+			return nil, errors.New("not found")
+		}
+		var out bytes.Buffer
+		require.NoError(t, previewDeploymentDefaults([]byte(deployment), lookup, &out))
+		require.Empty(t, out.String())
+	})
+}
+
+func TestDefaultDeploymentRuntime(t *testing.T) {
+	data := `apiVersion: ar.dev/v1alpha1
+kind: Deployment
+metadata:
+  name: unplaced
+spec:
+  targetRef:
+    kind: Agent
+    name: bot
+---
+apiVersion: ar.dev/v1alpha1
+kind: Deployment
+metadata:
+  name: placed
+spec:
+  targetRef:
+    kind: Agent
+    name: bot
+  runtimeRef:
+    kind: Runtime
+    name: local
+---
+apiVersion: ar.dev/v1alpha1
+kind: Deployment
+metadata:
+  name: selected
+spec:
+  targetRef:
+    kind: Agent
+    name: bot
+  runtimeSelector:
+    matchLabels:
+      env: prod
+`
+	t.Run("places only Deployments without a runtime", func(t *testing.T) {
+		var out bytes.Buffer
+		got, err := defaultDeploymentRuntime([]byte(data), "staging-k8s", "staging", &out)
+		require.NoError(t, err)
+		require.Equal(t, "→ Deployment unplaced: using Runtime staging-k8s, the default of context staging\n", out.String())
+
+		docs, err := splitYAMLDocs(got)
+		require.NoError(t, err)
+		refs := map[string]v1alpha1.ResourceRef{}
+		for _, root := range mappingRoots(docs) {
+			var dep v1alpha1.Deployment
+			require.NoError(t, root.Decode(&dep))
+			refs[dep.Metadata.Name] = dep.Spec.RuntimeRef
+		}
+		require.Equal(t, v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "staging-k8s"}, refs["unplaced"])
+		require.Equal(t, "local", refs["placed"].Name)
+		require.Empty(t, refs["selected"].Name)
+	})
+
+	t.Run("no default runtime leaves data untouched", func(t *testing.T) {
+		var out bytes.Buffer
+		got, err := defaultDeploymentRuntime([]byte(data), "", "", &out)
+		require.NoError(t, err)
+		require.Equal(t, data, string(got))
+		require.Empty(t, out.String())
+	})
+}
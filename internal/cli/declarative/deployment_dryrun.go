@@ -0,0 +1,50 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// printDeploymentManifests renders every Deployment document in data through
+// the server's dry run and writes the manifests to out as one YAML stream,
+// each preceded by a comment naming its Deployment. A Deployment that fails
+// to render is reported on errOut and the rest still render; the returned
+// error says whether any failed.
+func printDeploymentManifests(ctx context.Context, c *client.Client, data []byte, out, errOut io.Writer) error {
+	docs, err := splitYAMLDocs(data)
+	if err != nil {
+		return err
+	}
+	var failed int
+	for _, root := range mappingRoots(docs) {
+		if scalarValue(root, "kind") != v1alpha1.KindDeployment {
+			continue
+		}
+		var dep v1alpha1.Deployment
+		if err := root.Decode(&dep); err != nil {
+			return fmt.Errorf("decoding Deployment: %w", err)
+		}
+		result, err := c.DryRunDeployment(ctx, &dep)
+		if err != nil {
+			fmt.Fprintf(errOut, "✗ deployment/%s manifests: %v\n", dep.Metadata.Name, err)
+			failed++
+			continue
+		}
+		for _, m := range result.Manifests {
+			fmt.Fprintf(out, "---\n# deployment/%s/%s: %s\n", result.Namespace, result.Name, m.Name)
+			fmt.Fprint(out, m.Content)
+			if !strings.HasSuffix(m.Content, "\n") {
+				fmt.Fprintln(out)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d Deployment(s) failed to render", failed)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/common"
+	"github.com/agentregistry-dev/agentregistry/internal/cli/common/tunnel"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/local"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+// exposeCleanupTimeout bounds deleting the ephemeral tag after the tunnel
+// closes, when the command's own context is already cancelled.
+const exposeCleanupTimeout = 10 * time.Second
+
+type exposeOptions struct {
+	provider string
+	port     string
+}
+
+func newDeploymentExposeCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts exposeOptions
+	cmd := &cobra.Command{
+		Use:   "expose NAME",
+		Short: "Expose a Local deployment through a public tunnel",
+		Long: `Expose a Deployment running on a Local runtime at a public URL until the
+command exits.
+
+Starts a tunnel (cloudflared by default, or ngrok) to the local agent
+gateway and publishes an ephemeral "tunnel-NAME" tag of the deployed
+artifact that records the public URL: an MCPServer tag with spec.remote set
+to it, or an Agent tag with the ` + v1alpha1.TunnelURLAnnotation + `
+annotation. Agents can reference the MCPServer tag like any other remote
+MCP server, and A2A peers can read an agent's URL from the annotation. On
+exit (Ctrl-C) the tag is deleted and the tunnel closed.
+
+The tunnel forwards the whole gateway, so every local agent and MCP server
+behind it is reachable through the public URL while it is up.`,
+		Example: `  arctl deployment expose summarizer-local
+  arctl deployment expose github-mcp --provider ngrok`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return runDeploymentExpose(ctx, cmd.OutOrStdout(), deps, args[0], opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.provider, "provider", "cloudflared", fmt.Sprintf("Tunnel provider: %v", tunnel.Names()))
+	cmd.Flags().StringVar(&opts.port, "port", common.DefaultAgentGatewayPort, "Local agent gateway port")
+	return cmd
+}
+
+func runDeploymentExpose(ctx context.Context, out io.Writer, deps cliruntime.Deps, name string, opts exposeOptions) error {
+	provider, err := tunnel.Lookup(opts.provider)
+	if err != nil {
+		return err
+	}
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+
+	ns := v1alpha1.DefaultNamespace
+	deployment, err := client.GetTyped(ctx, c, v1alpha1.KindDeployment, ns, name, "",
+		func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} })
+	if err != nil {
+		return fmt.Errorf("fetching deployment %q: %w", name, err)
+	}
+	runtimeRef := deployment.Spec.RuntimeRef
+	runtimeNS := runtimeRef.Namespace
+	if runtimeNS == "" {
+		runtimeNS = ns
+	}
+	rt, err := client.GetTyped(ctx, c, v1alpha1.KindRuntime, runtimeNS, runtimeRef.Name, "",
+		func() *v1alpha1.Runtime { return &v1alpha1.Runtime{} })
+	if err != nil {
+		return fmt.Errorf("fetching runtime %q: %w", runtimeRef.Name, err)
+	}
+	if rt.Spec.Type != v1alpha1.TypeLocal {
+		return fmt.Errorf("deployment %q runs on a %s runtime; only %s deployments can be exposed", name, rt.Spec.Type, v1alpha1.TypeLocal)
+	}
+
+	target, err := exposeTarget(ctx, c, deployment)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "→ starting %s tunnel to localhost:%s...\n", opts.provider, opts.port)
+	tun, err := provider.Start(ctx, "http://localhost:"+opts.port)
+	if err != nil {
+		return fmt.Errorf("starting %s tunnel: %w", opts.provider, err)
+	}
+	defer func() { _ = tun.Close() }()
+
+	ephemeral, endpoint := ephemeralTunnelTag(target, deployment.Metadata.Name, tun.PublicURL)
+	data, err := yaml.Marshal(ephemeral)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", ephemeral.GetKind(), err)
+	}
+	// An apply interrupted by Ctrl-C could still land after the cleanup
+	// below ran, leaving the tag behind; let it finish.
+	results, err := c.Apply(context.WithoutCancel(ctx), data, client.ApplyOpts{})
+	if err != nil {
+		return fmt.Errorf("publishing tunnel tag: %w", err)
+	}
+	for _, r := range results {
+		if r.Status == arv0.ApplyStatusFailed {
+			return fmt.Errorf("publishing tunnel tag: %s", r.Error)
+		}
+	}
+	meta := ephemeral.GetMetadata()
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), exposeCleanupTimeout)
+		defer cancel()
+		if err := c.Delete(cleanupCtx, ephemeral.GetKind(), meta.Namespace, meta.Name, meta.Tag); err != nil {
+			fmt.Fprintf(out, "! could not delete %s %s/%s: %v\n", ephemeral.GetKind(), meta.Name, meta.Tag, err)
+			return
+		}
+		fmt.Fprintf(out, "✓ deleted %s %s/%s\n", ephemeral.GetKind(), meta.Name, meta.Tag)
+	}()
+
+	fmt.Fprintf(out, "✓ %s is public at %s\n", name, endpoint)
+	fmt.Fprintf(out, "✓ published %s %s/%s (ephemeral)\n", ephemeral.GetKind(), meta.Name, meta.Tag)
+	fmt.Fprintln(out, "Press Ctrl-C to stop.")
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-tun.Done():
+		if err := tun.Err(); err != nil {
+			return fmt.Errorf("%s tunnel exited: %w", opts.provider, err)
+		}
+		return fmt.Errorf("%s tunnel exited", opts.provider)
+	}
+}
+
+// exposeTarget fetches the artifact a Deployment runs. Only Agents and
+// MCPServers sit behind the agent gateway.
+func exposeTarget(ctx context.Context, c *client.Client, deployment *v1alpha1.Deployment) (v1alpha1.Object, error) {
+	ref := deployment.Spec.TargetRef
+	ns := ref.Namespace
+	if ns == "" {
+		ns = deployment.Metadata.Namespace
+	}
+	switch ref.Kind {
+	case v1alpha1.KindAgent:
+		return client.GetTyped(ctx, c, ref.Kind, ns, ref.Name, ref.Tag, func() *v1alpha1.Agent { return &v1alpha1.Agent{} })
+	case v1alpha1.KindMCPServer:
+		return client.GetTyped(ctx, c, ref.Kind, ns, ref.Name, ref.Tag, func() *v1alpha1.MCPServer { return &v1alpha1.MCPServer{} })
+	default:
+		return nil, fmt.Errorf("deployment %q targets a %s; only Agent and MCPServer deployments can be exposed", deployment.Metadata.Name, ref.Kind)
+	}
+}
+
+// ephemeralTunnelTag builds the "tunnel-<deployment>" tag of target that
+// records publicURL, and returns it with the public endpoint of the
+// deployment: the agent's gateway route, or the gateway's MCP route.
+func ephemeralTunnelTag(target v1alpha1.Object, deploymentName, publicURL string) (v1alpha1.Object, string) {
+	src := target.GetMetadata()
+	meta := v1alpha1.ObjectMeta{
+		Namespace:   src.Namespace,
+		Name:        src.Name,
+		Tag:         "tunnel-" + deploymentName,
+		Labels:      maps.Clone(src.Labels),
+		Annotations: map[string]string{},
+	}
+	if meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	meta.Labels[v1alpha1.EphemeralLabel] = "true"
+
+	switch t := target.(type) {
+	case *v1alpha1.Agent:
+		endpoint := publicURL + local.AgentRoutePrefix(src.Name, deploymentName)
+		meta.Annotations[v1alpha1.TunnelURLAnnotation] = endpoint
+		return &v1alpha1.Agent{
+			TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindAgent},
+			Metadata: meta,
+			Spec:     t.Spec,
+		}, endpoint
+	case *v1alpha1.MCPServer:
+		endpoint := publicURL + local.MCPRoutePrefix
+		meta.Annotations[v1alpha1.TunnelURLAnnotation] = endpoint
+		spec := t.Spec
+		spec.Source = nil
+		spec.Remote = &v1alpha1.MCPRemote{Type: "streamable-http", URL: endpoint}
+		return &v1alpha1.MCPServer{
+			TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+			Metadata: meta,
+			Spec:     spec,
+		}, endpoint
+	}
+	return target, publicURL
+}
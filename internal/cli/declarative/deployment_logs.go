@@ -0,0 +1,108 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+type deploymentLogsOptions struct {
+	follow     bool
+	tail       int
+	since      string
+	timestamps bool
+}
+
+func newDeploymentLogsCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts deploymentLogsOptions
+	cmd := &cobra.Command{
+		Use:   "logs NAME",
+		Short: "Print the logs of a deployment",
+		Long: `Print the logs of a Deployment's workload: the lines log shipping retained,
+then the ones the runtime still has. Each line is prefixed with the
+container or pod it came from.
+
+With --follow the command keeps streaming new lines until the workload's
+log stream ends or it is interrupted (Ctrl-C).`,
+		Example: `  arctl deployment logs summarizer-local
+  arctl deployment logs -f summarizer-local
+  arctl deployment logs summarizer-prod --tail 100 --since 1h`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return runDeploymentLogs(ctx, cmd.OutOrStdout(), deps, args[0], opts)
+		},
+	}
+	cmd.Flags().BoolVarP(&opts.follow, "follow", "f", false, "Keep streaming new log lines")
+	cmd.Flags().IntVar(&opts.tail, "tail", 0, "Lines to show before following; 0 shows all available")
+	cmd.Flags().StringVar(&opts.since, "since", "", "Only lines at or after this RFC3339 time, or within this duration (e.g. 1h)")
+	cmd.Flags().BoolVar(&opts.timestamps, "timestamps", false, "Prefix each line with its timestamp")
+	return cmd
+}
+
+func runDeploymentLogs(ctx context.Context, out io.Writer, deps cliruntime.Deps, name string, opts deploymentLogsOptions) error {
+	if opts.tail < 0 {
+		return fmt.Errorf("--tail must not be negative (got %d)", opts.tail)
+	}
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	logsOpts := client.DeploymentLogsOpts{
+		Namespace: v1alpha1.DefaultNamespace,
+		TailLines: opts.tail,
+		Since:     opts.since,
+	}
+	emit := func(line arv0.DeploymentLogLine) error {
+		return printDeploymentLogLine(out, line, opts.timestamps)
+	}
+
+	if opts.follow {
+		err := c.FollowDeploymentLogs(ctx, name, logsOpts, emit)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("following logs of deployment %s: %w", name, err)
+		}
+		return nil
+	}
+	logs, err := c.DeploymentLogs(ctx, name, logsOpts)
+	if err != nil {
+		return fmt.Errorf("fetching logs of deployment %s: %w", name, err)
+	}
+	for _, line := range logs.Lines {
+		if err := emit(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printDeploymentLogLine(out io.Writer, line arv0.DeploymentLogLine, timestamps bool) error {
+	prefix := ""
+	if line.Stream != "" {
+		prefix = line.Stream + " | "
+	}
+	if timestamps && line.Timestamp != "" {
+		prefix += line.Timestamp + " "
+	}
+	_, err := fmt.Fprintln(out, prefix+line.Line)
+	return err
+}
@@ -0,0 +1,245 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cliCommon "github.com/agentregistry-dev/agentregistry/internal/cli/common"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+type deploymentNotesOptions struct {
+	namespace   string
+	note        string
+	setNote     bool
+	addLinks    []string
+	removeLinks []string
+	history     bool
+}
+
+func newDeploymentNotesCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts deploymentNotesOptions
+	cmd := &cobra.Command{
+		Use:   "notes NAME",
+		Short: "Show or edit the notes and links of a deployment",
+		Long: `Show or edit the free-form note and titled links (runbook, dashboard)
+attached to a Deployment.
+
+Without flags the current note and links are printed. --note replaces the
+note (--note "" clears it); --add-link and --remove-link edit the links.
+Every edit is kept: --history lists earlier versions with who made them.`,
+		Example: `  arctl deployment notes summarizer-prod
+  arctl deployment notes summarizer-prod --note "rolled back to 1.4.2: 5xx spike on 1.5.0"
+  arctl deployment notes summarizer-prod --add-link Runbook=https://runbooks.example.com/summarizer
+  arctl deployment notes summarizer-prod --history`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.setNote = cmd.Flags().Changed("note")
+			return runDeploymentNotes(cmd.Context(), cmd.OutOrStdout(), deps, args[0], opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.namespace, "namespace", v1alpha1.DefaultNamespace, "Namespace of the deployment")
+	cmd.Flags().StringVar(&opts.note, "note", "", "Replace the note; an empty value clears it")
+	cmd.Flags().StringArrayVar(&opts.addLinks, "add-link", nil, "Add or replace a link as TITLE=URL; repeatable")
+	cmd.Flags().StringArrayVar(&opts.removeLinks, "remove-link", nil, "Remove the link with this title; repeatable")
+	cmd.Flags().BoolVar(&opts.history, "history", false, "Also list earlier versions of the notes")
+	return cmd
+}
+
+func runDeploymentNotes(ctx context.Context, out io.Writer, deps cliruntime.Deps, name string, opts deploymentNotesOptions) error {
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	notes, err := c.DeploymentNotes(ctx, opts.namespace, name)
+	if err != nil {
+		return fmt.Errorf("fetching notes of deployment %s: %w", name, err)
+	}
+
+	if opts.setNote || len(opts.addLinks) > 0 || len(opts.removeLinks) > 0 {
+		var patch arv0.DeploymentNotesPatch
+		if opts.setNote {
+			patch.Note = &opts.note
+		}
+		if len(opts.addLinks) > 0 || len(opts.removeLinks) > 0 {
+			if patch.Links, err = editLinks(notes.Links, opts.addLinks, opts.removeLinks); err != nil {
+				return err
+			}
+		}
+		if notes, err = c.PatchDeploymentNotes(ctx, opts.namespace, name, patch); err != nil {
+			return fmt.Errorf("updating notes of deployment %s: %w", name, err)
+		}
+	}
+
+	printDeploymentNotes(out, notes)
+	if opts.history && len(notes.History) > 1 {
+		fmt.Fprintln(out, "History:")
+		for _, rev := range notes.History[1:] {
+			fmt.Fprintf(out, "  #%d %s by %s\n", rev.Revision, rev.CreatedAt.Format(time.RFC3339), orUnknown(rev.Author))
+			printNoteBody(out, "    ", rev.Note, rev.Links)
+		}
+	}
+	return nil
+}
+
+// editLinks applies --add-link and --remove-link to links. Adding a link
+// whose title exists replaces its URL in place; removing a title that
+// does not exist is an error, so typos are not silently ignored.
+func editLinks(links []arv0.DeploymentLink, add, remove []string) ([]arv0.DeploymentLink, error) {
+	out := slices.Clone(links)
+	if out == nil {
+		out = []arv0.DeploymentLink{}
+	}
+	for _, title := range remove {
+		n := len(out)
+		out = slices.DeleteFunc(out, func(l arv0.DeploymentLink) bool { return l.Title == title })
+		if len(out) == n {
+			return nil, fmt.Errorf("--remove-link %q: no link has that title", title)
+		}
+	}
+	for _, spec := range add {
+		title, u, ok := strings.Cut(spec, "=")
+		if !ok || title == "" || u == "" {
+			return nil, fmt.Errorf("--add-link %q: expected TITLE=URL", spec)
+		}
+		link := arv0.DeploymentLink{Title: title, URL: u}
+		if i := slices.IndexFunc(out, func(l arv0.DeploymentLink) bool { return l.Title == title }); i >= 0 {
+			out[i] = link
+		} else {
+			out = append(out, link)
+		}
+	}
+	return out, nil
+}
+
+// printDeploymentNotes writes the current note and links, the way
+// describe shows them.
+func printDeploymentNotes(out io.Writer, notes *arv0.DeploymentNotes) {
+	if notes.UpdatedAt == nil {
+		fmt.Fprintln(out, "Notes:  <none>")
+		return
+	}
+	fmt.Fprintf(out, "Notes:  updated %s by %s\n", notes.UpdatedAt.Format(time.RFC3339), orUnknown(notes.UpdatedBy))
+	printNoteBody(out, "  ", notes.Note, notes.Links)
+}
+
+func printNoteBody(out io.Writer, indent, note string, links []arv0.DeploymentLink) {
+	for line := range strings.SplitSeq(strings.TrimRight(note, "\n"), "\n") {
+		if line != "" || note != "" {
+			fmt.Fprintf(out, "%s%s\n", indent, line)
+		}
+	}
+	for _, l := range links {
+		fmt.Fprintf(out, "%s%s: %s\n", indent, l.Title, l.URL)
+	}
+}
+
+func orUnknown(author string) string {
+	if author == "" {
+		return "<unknown>"
+	}
+	return author
+}
+
+func newDeploymentDescribeCmd(deps cliruntime.Deps) *cobra.Command {
+	var namespace string
+	cmd := &cobra.Command{
+		Use:   "describe NAME",
+		Short: "Show a deployment's details, conditions and notes",
+		Example: `  arctl deployment describe summarizer-prod
+  arctl deployment describe summarizer-prod --namespace team-a`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDeploymentDescribe(cmd.Context(), cmd.OutOrStdout(), deps, namespace, args[0])
+		},
+	}
+	cmd.Flags().StringVar(&namespace, "namespace", v1alpha1.DefaultNamespace, "Namespace of the deployment")
+	return cmd
+}
+
+func runDeploymentDescribe(ctx context.Context, out io.Writer, deps cliruntime.Deps, namespace, name string) error {
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	dep, err := client.GetTyped(ctx, c, v1alpha1.KindDeployment, namespace, name, "",
+		func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} })
+	if err != nil {
+		return fmt.Errorf("fetching deployment %s: %w", name, err)
+	}
+	// Registries without the notes subresource answer 404; describe the
+	// Deployment without them.
+	notes, err := c.DeploymentNotes(ctx, namespace, name)
+	if err != nil && !errors.Is(err, client.ErrNotFound) {
+		return fmt.Errorf("fetching notes of deployment %s: %w", name, err)
+	}
+
+	rec := cliCommon.DeploymentRecordFromObject(dep)
+	target := dep.Spec.TargetRef.Kind + "/" + dep.Spec.TargetRef.Name
+	if dep.Spec.TargetRef.Tag != "" {
+		target += "@" + dep.Spec.TargetRef.Tag
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Name:\t%s\n", rec.Name)
+	fmt.Fprintf(tw, "Namespace:\t%s\n", rec.Namespace)
+	fmt.Fprintf(tw, "Target:\t%s\n", target)
+	fmt.Fprintf(tw, "Runtime:\t%s\n", orDashString(rec.RuntimeID))
+	fmt.Fprintf(tw, "Status:\t%s\n", rec.Status)
+	fmt.Fprintf(tw, "Created:\t%s\n", formatTime(rec.CreatedAt))
+	fmt.Fprintf(tw, "Updated:\t%s\n", formatTime(rec.UpdatedAt))
+	if rec.Error != "" {
+		fmt.Fprintf(tw, "Error:\t%s\n", rec.Error)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(rec.Conditions) > 0 {
+		fmt.Fprintln(out, "Conditions:")
+		tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+		for _, cond := range rec.Conditions {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", cond.Type, cond.Status, orDashString(cond.Reason), cond.Message)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	if notes != nil {
+		printDeploymentNotes(out, notes)
+	}
+	return nil
+}
+
+func orDashString(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
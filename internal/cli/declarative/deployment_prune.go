@@ -0,0 +1,130 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/scheme"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// prunePageSize is the Deployment list page size used while pruning.
+const prunePageSize = 100
+
+// parsePruneSelector decodes a --selector value in the "key=value,key2=value2"
+// form the list endpoints accept. Pruning without one would reach every
+// Deployment in the namespace, so an empty selector is an error.
+func parsePruneSelector(selector string) (map[string]string, error) {
+	out := map[string]string{}
+	for pair := range strings.SplitSeq(selector, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid --selector %q: label %q must be key=value", selector, pair)
+		}
+		out[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("--prune requires --selector so only Deployments owned by these files are deleted")
+	}
+	return out, nil
+}
+
+// declaredDeployments returns the Deployments the files declare, as a set of
+// names per namespace. Every one must carry the selector's labels: one that
+// didn't would not be found by the next prune, and a later edit adding the
+// labels would make an unrelated Deployment look owned.
+func declaredDeployments(files [][]byte, selector map[string]string) (map[string]map[string]bool, error) {
+	declared := map[string]map[string]bool{}
+	for _, data := range files {
+		objs, err := scheme.DecodeBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			deployment, ok := obj.(*v1alpha1.Deployment)
+			if !ok {
+				continue
+			}
+			for key, value := range selector {
+				if deployment.Metadata.Labels[key] != value {
+					return nil, fmt.Errorf("deployment %q must carry label %s=%s to be applied with --prune",
+						deployment.Metadata.Name, key, value)
+				}
+			}
+			namespace := deployment.Metadata.NamespaceOrDefault()
+			if declared[namespace] == nil {
+				declared[namespace] = map[string]bool{}
+			}
+			declared[namespace][deployment.Metadata.Name] = true
+		}
+	}
+	return declared, nil
+}
+
+// pruneDeployments deletes the managed Deployments matching selector that
+// the files no longer declare, in every namespace the files declare
+// Deployments in (the default namespace when they declare none). Discovered
+// Deployments are never pruned.
+func pruneDeployments(ctx context.Context, c *client.Client, out io.Writer, declared map[string]map[string]bool, selector string, dryRun bool) error {
+	namespaces := slices.Sorted(maps.Keys(declared))
+	if len(namespaces) == 0 {
+		namespaces = []string{v1alpha1.DefaultNamespace}
+	}
+	var results []arv0.ApplyResult
+	for _, namespace := range namespaces {
+		opts := client.ListOpts{
+			Namespace: namespace,
+			Labels:    selector,
+			Origin:    v1alpha1.DeploymentOriginManaged,
+			Limit:     prunePageSize,
+		}
+		for {
+			rows, cursor, err := c.List(ctx, v1alpha1.KindDeployment, opts)
+			if err != nil {
+				return fmt.Errorf("listing Deployments to prune in %s: %w", namespace, err)
+			}
+			for _, row := range rows {
+				if declared[namespace][row.Metadata.Name] {
+					continue
+				}
+				results = append(results, arv0.ApplyResult{
+					Kind:      v1alpha1.KindDeployment,
+					Namespace: namespace,
+					Name:      row.Metadata.Name,
+					Status:    arv0.ApplyStatusDeleted,
+				})
+			}
+			if cursor == "" {
+				break
+			}
+			opts.Cursor = cursor
+		}
+	}
+	// Delete only after listing so deletions don't shift the pages.
+	var failed bool
+	for i := range results {
+		if dryRun {
+			continue
+		}
+		r := &results[i]
+		if err := c.Delete(ctx, r.Kind, r.Namespace, r.Name, ""); err != nil {
+			r.Status, r.Error = arv0.ApplyStatusFailed, err.Error()
+			failed = true
+		}
+	}
+	printResults(out, results, dryRun)
+	if failed {
+		return fmt.Errorf("one or more Deployments failed to prune")
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+type deploymentShareOptions struct {
+	namespace string
+	expires   string
+	list      bool
+	revoke    string
+}
+
+func newDeploymentShareCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts deploymentShareOptions
+	cmd := &cobra.Command{
+		Use:   "share NAME",
+		Short: "Create, list or revoke expiring share links for a deployment",
+		Long: `Create a link that lets someone without a registry account read a
+Deployment's status and logs and chat with the deployed agent until it
+expires (24h unless --expires says otherwise, at most 720h).
+
+The link is printed only once. --list shows the deployment's links with
+who created them and how often they were used; --revoke ID ends a link
+before it expires.`,
+		Example: `  arctl deployment share summarizer-prod
+  arctl deployment share summarizer-prod --expires 2h
+  arctl deployment share summarizer-prod --list
+  arctl deployment share summarizer-prod --revoke 3f9c2a1b7d6e5f40`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDeploymentShare(cmd.Context(), cmd.OutOrStdout(), deps, args[0], opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.namespace, "namespace", v1alpha1.DefaultNamespace, "Namespace of the deployment")
+	cmd.Flags().StringVar(&opts.expires, "expires", "", "How long the new link stays valid, e.g. 2h (default 24h)")
+	cmd.Flags().BoolVar(&opts.list, "list", false, "List the deployment's share links instead of creating one")
+	cmd.Flags().StringVar(&opts.revoke, "revoke", "", "Revoke the share link with this ID instead of creating one")
+	cmd.MarkFlagsMutuallyExclusive("list", "revoke", "expires")
+	return cmd
+}
+
+func runDeploymentShare(ctx context.Context, out io.Writer, deps cliruntime.Deps, name string, opts deploymentShareOptions) error {
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+
+	switch {
+	case opts.list:
+		shares, err := c.DeploymentShares(ctx, opts.namespace, name)
+		if err != nil {
+			return fmt.Errorf("listing share links of deployment %s: %w", name, err)
+		}
+		return printDeploymentShares(out, shares)
+	case opts.revoke != "":
+		share, err := c.RevokeDeploymentShare(ctx, opts.namespace, name, opts.revoke)
+		if err != nil {
+			return fmt.Errorf("revoking share link %s of deployment %s: %w", opts.revoke, name, err)
+		}
+		fmt.Fprintf(out, "Revoked share link %s of deployment %s\n", share.ID, name)
+		return nil
+	}
+
+	created, err := c.CreateDeploymentShare(ctx, opts.namespace, name, arv0.DeploymentShareInput{ExpiresIn: opts.expires})
+	if err != nil {
+		return fmt.Errorf("sharing deployment %s: %w", name, err)
+	}
+	// BaseURL carries the /v0 prefix that Path starts with.
+	link := strings.TrimSuffix(strings.TrimRight(c.BaseURL, "/"), "/v0") + created.Path
+	fmt.Fprintf(out, "Share link %s for deployment %s, valid until %s:\n", created.Share.ID, name, created.Share.ExpiresAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(out, "  %s\n", link)
+	fmt.Fprintln(out, "It is shown only once. Revoke it with:")
+	fmt.Fprintf(out, "  arctl deployment share %s --namespace %s --revoke %s\n", name, opts.namespace, created.Share.ID)
+	return nil
+}
+
+func printDeploymentShares(out io.Writer, shares []arv0.DeploymentShare) error {
+	if len(shares) == 0 {
+		fmt.Fprintln(out, "No share links.")
+		return nil
+	}
+	now := time.Now()
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATE\tCREATED BY\tEXPIRES\tUSES\tLAST USED")
+	for _, s := range shares {
+		state := "active"
+		switch {
+		case s.RevokedAt != nil:
+			state = "revoked by " + orUnknown(s.RevokedBy)
+		case !now.Before(s.ExpiresAt):
+			state = "expired"
+		}
+		lastUsed := "-"
+		if s.LastUsedAt != nil {
+			lastUsed = formatTime(*s.LastUsedAt)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n", s.ID, state, orUnknown(s.CreatedBy), formatTime(s.ExpiresAt), s.UseCount, lastUsed)
+	}
+	return tw.Flush()
+}
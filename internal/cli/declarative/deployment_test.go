@@ -0,0 +1,317 @@
+package declarative_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/common/tunnel"
+	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+func TestDeploymentOutdated_PrintsTable(t *testing.T) {
+	var gotURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(arv0.OutdatedReport{
+			Deployments: []arv0.OutdatedDeployment{{
+				Namespace: "default",
+				Name:      "summarizer-prod",
+				Target:    "Agent/summarizer@1.0.0",
+				Severity:  arv0.OutdatedSeverityHigh,
+				Artifacts: []arv0.OutdatedArtifact{{
+					Field: "spec.skills[0]", Kind: "Skill", Name: "summarize",
+					Pinned: "1.2.0", Latest: "2.0.0",
+					Severity: arv0.OutdatedSeverityHigh, Reason: "major version behind (1.2.0 -> 2.0.0)",
+				}},
+			}},
+			Summary: arv0.OutdatedSummary{High: 1},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewDeploymentCmd(declarativeTestDeps(client.NewClient(srv.URL, "")))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"outdated", "--min-severity", "medium"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, "/v0/deployments/outdated?minSeverity=medium", gotURL)
+	require.Contains(t, out.String(), "summarizer-prod")
+	require.Contains(t, out.String(), "Skill/summarize")
+	require.Contains(t, out.String(), "Outdated deployments: 1 (1 high, 0 medium, 0 low)")
+}
+
+func TestDeploymentOutdated_RejectsUnknownSeverity(t *testing.T) {
+	cmd := declarative.NewDeploymentCmd(declarativeTestDeps(nil))
+	cmd.SetArgs([]string{"outdated", "--min-severity", "urgent"})
+	require.ErrorContains(t, cmd.Execute(), "--min-severity")
+}
+
+func TestDeploymentExpose_PublishesEphemeralTagUntilExit(t *testing.T) {
+	// The fake tunnel closes on its own shortly after reporting its URL.
+	tunnel.Register("fake", tunnel.CommandProvider{
+		Binary:     "sh",
+		Args:       func(string) []string { return []string{"-c", "echo url=https://fake.example.test; sleep 1"} },
+		URLPattern: regexp.MustCompile(`url=(https://\S+)`),
+	})
+
+	var (
+		applied []byte
+		deleted string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v0/deployments/summarizer-local":
+			_ = json.NewEncoder(w).Encode(v1alpha1.Deployment{
+				TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindDeployment},
+				Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "summarizer-local"},
+				Spec: v1alpha1.DeploymentSpec{
+					TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "summarizer", Tag: "1.0.0"},
+					RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "local"},
+				},
+			})
+		case "GET /v0/runtimes/local":
+			_ = json.NewEncoder(w).Encode(v1alpha1.Runtime{
+				TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindRuntime},
+				Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "local"},
+				Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeLocal},
+			})
+		case "GET /v0/agents/summarizer/1.0.0":
+			_ = json.NewEncoder(w).Encode(v1alpha1.Agent{
+				TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindAgent},
+				Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "summarizer", Tag: "1.0.0"},
+				Spec:     v1alpha1.AgentSpec{Source: &v1alpha1.AgentSource{Image: "ghcr.io/acme/summarizer:1.0.0"}},
+			})
+		case "POST /v0/apply":
+			applied, _ = io.ReadAll(r.Body)
+			_, _ = w.Write(batchApplyResponse([]arv0.ApplyResult{{
+				Kind: v1alpha1.KindAgent, Name: "summarizer", Tag: "tunnel-summarizer-local", Status: arv0.ApplyStatusCreated,
+			}}))
+		case "DELETE /v0/agents/summarizer/tunnel-summarizer-local":
+			deleted = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewDeploymentCmd(declarativeTestDeps(client.NewClient(srv.URL, "")))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"expose", "summarizer-local", "--provider", "fake"})
+	require.ErrorContains(t, cmd.Execute(), "fake tunnel exited")
+
+	require.Contains(t, string(applied), "tag: tunnel-summarizer-local")
+	require.Contains(t, string(applied), v1alpha1.EphemeralLabel+`: "true"`)
+	require.Contains(t, string(applied), "https://fake.example.test/agents/summarizer-summarizer-local")
+	require.Equal(t, "/v0/agents/summarizer/tunnel-summarizer-local", deleted)
+	require.Contains(t, out.String(), "is public at https://fake.example.test/agents/summarizer-summarizer-local")
+}
+
+func TestDeploymentCreate_AppliesTemplateUnderFlags(t *testing.T) {
+	var applied []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v0/deploymenttemplates/prod-gpu":
+			_ = json.NewEncoder(w).Encode(v1alpha1.DeploymentTemplate{
+				TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindDeploymentTemplate},
+				Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "prod-gpu"},
+				Spec: v1alpha1.DeploymentTemplateSpec{
+					RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "gpu-pool"},
+					Env:        map[string]string{"LOG_LEVEL": "info", "REGION": "us-east-1"},
+					Resources:  &v1alpha1.ResourceRequirements{Limits: &v1alpha1.ComputeResources{CPU: "4", Memory: "16Gi"}},
+				},
+			})
+		case "POST /v0/apply":
+			applied, _ = io.ReadAll(r.Body)
+			_, _ = w.Write(batchApplyResponse([]arv0.ApplyResult{{
+				Kind: v1alpha1.KindDeployment, Name: "summarizer-prod", Status: arv0.ApplyStatusCreated,
+			}}))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	var out, errOut bytes.Buffer
+	cmd := declarative.NewDeploymentCmd(declarativeTestDeps(client.NewClient(srv.URL, "")))
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"create", "summarizer-prod", "--target", "agent/summarizer", "--tag", "1.2.0", "--template", "prod-gpu", "--snapshot", "2024-06-release", "-e", "LOG_LEVEL=debug"})
+	require.NoError(t, cmd.Execute())
+
+	var got v1alpha1.Deployment
+	require.NoError(t, yaml.Unmarshal(applied, &got))
+	require.Equal(t, v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "summarizer", Tag: "1.2.0"}, got.Spec.TargetRef)
+	require.Equal(t, "gpu-pool", got.Spec.RuntimeRef.Name)
+	require.Equal(t, "2024-06-release", got.Spec.Snapshot)
+	require.Equal(t, map[string]string{"LOG_LEVEL": "debug", "REGION": "us-east-1"}, got.Spec.Env)
+	require.Equal(t, "16Gi", got.Spec.Resources.Limits.Memory)
+	require.Equal(t, "prod-gpu", got.Metadata.Annotations[v1alpha1.DeploymentTemplateAnnotation])
+	require.Contains(t, errOut.String(), "env: REGION")
+	require.Contains(t, errOut.String(), "env overridden by flags: LOG_LEVEL")
+	require.Contains(t, out.String(), "✓ Deployment/summarizer-prod")
+}
+
+func TestDeploymentCreate_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v0/deployments/existing" {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(v1alpha1.Deployment{Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "existing"}})
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"malformed target", []string{"create", "web", "--target", "summarizer"}, "--target must be KIND/NAME"},
+		{"undeployable kind", []string{"create", "web", "--target", "skill/summarize"}, "cannot deploy a skill"},
+		{"malformed env", []string{"create", "web", "--target", "agent/web", "-e", "LOG_LEVEL"}, "--env must be KEY=VALUE"},
+		{"existing deployment", []string{"create", "existing", "--target", "agent/web"}, `deployment "existing" already exists`},
+		{"missing template", []string{"create", "web", "--target", "agent/web", "--template", "nope"}, `fetching deployment template "nope"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := declarative.NewDeploymentCmd(declarativeTestDeps(client.NewClient(srv.URL, "")))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			require.ErrorContains(t, cmd.Execute(), tc.want)
+		})
+	}
+}
+
+func TestDeploymentLogs_PrintsLines(t *testing.T) {
+	var gotURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(arv0.DeploymentLogs{Lines: []arv0.DeploymentLogLine{
+			{Timestamp: "2026-05-01T10:00:00Z", Stream: "summarizer-1", Line: "starting"},
+			{Timestamp: "2026-05-01T10:00:01Z", Stream: "summarizer-1", Line: "listening on :8080"},
+		}})
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewDeploymentCmd(declarativeTestDeps(client.NewClient(srv.URL, "")))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"logs", "summarizer-local", "--tail", "2", "--timestamps"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, "/v0/deployments/summarizer-local/logs?tailLines=2", gotURL)
+	require.Equal(t, "summarizer-1 | 2026-05-01T10:00:00Z starting\nsummarizer-1 | 2026-05-01T10:00:01Z listening on :8080\n", out.String())
+}
+
+func TestDeploymentLogs_FollowStreamsEvents(t *testing.T) {
+	var gotURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "id: 1\ndata: {\"stream\":\"weather-abc\",\"line\":\"ready\"}\n\n")
+		_, _ = io.WriteString(w, ": heartbeat\n\n")
+		_, _ = io.WriteString(w, "id: 2\ndata: {\"stream\":\"weather-abc\",\"line\":\"GET /mcp 200\"}\n\n")
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewDeploymentCmd(declarativeTestDeps(client.NewClient(srv.URL, "")))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"logs", "-f", "weather-kube", "--since", "10m"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, "/v0/deployments/weather-kube/logs?follow=true&since=10m", gotURL)
+	require.Equal(t, "weather-abc | ready\nweather-abc | GET /mcp 200\n", out.String())
+}
+
+func TestDeploymentNotes_EditsLinksAndNote(t *testing.T) {
+	updatedAt := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	var patched arv0.DeploymentNotesPatch
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v0/deployments/summarizer-prod/notes?namespace=team-a", r.URL.String())
+		w.Header().Set("Content-Type", "application/json")
+		current := arv0.DeploymentNotes{
+			Namespace: "team-a", Name: "summarizer-prod",
+			Links: []arv0.DeploymentLink{
+				{Title: "Runbook", URL: "https://old.example.com/runbook"},
+				{Title: "Dashboard", URL: "https://grafana.example.com/d/1"},
+			},
+		}
+		if r.Method == http.MethodPatch {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&patched))
+			current.Note, current.Links = *patched.Note, patched.Links
+			current.UpdatedBy, current.UpdatedAt = "alice", &updatedAt
+		}
+		_ = json.NewEncoder(w).Encode(current)
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewDeploymentCmd(declarativeTestDeps(client.NewClient(srv.URL, "")))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"notes", "summarizer-prod", "--namespace", "team-a",
+		"--note", "rolled back to 1.4.2",
+		"--add-link", "Runbook=https://runbooks.example.com/summarizer",
+		"--remove-link", "Dashboard"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, []arv0.DeploymentLink{{Title: "Runbook", URL: "https://runbooks.example.com/summarizer"}}, patched.Links)
+	require.Equal(t, "Notes:  updated 2026-05-01T10:00:00Z by alice\n  rolled back to 1.4.2\n  Runbook: https://runbooks.example.com/summarizer\n", out.String())
+}
+
+func TestDeploymentNotes_RemoveUnknownLinkFails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(arv0.DeploymentNotes{Name: "summarizer-prod"})
+	}))
+	t.Cleanup(srv.Close)
+
+	cmd := declarative.NewDeploymentCmd(declarativeTestDeps(client.NewClient(srv.URL, "")))
+	cmd.SetArgs([]string{"notes", "summarizer-prod", "--remove-link", "Runbook"})
+	require.ErrorContains(t, cmd.Execute(), `--remove-link "Runbook"`)
+}
+
+func TestDeploymentDescribe_WithoutNotesSubresource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v0/deployments/summarizer-prod/notes" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"apiVersion":"ar.dev/v1alpha1","kind":"Deployment",
+			"metadata":{"namespace":"default","name":"summarizer-prod"},
+			"spec":{"targetRef":{"kind":"Agent","name":"summarizer","tag":"1.4.2"},"runtimeRef":{"kind":"Runtime","name":"prod-k8s"}}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewDeploymentCmd(declarativeTestDeps(client.NewClient(srv.URL, "")))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"describe", "summarizer-prod"})
+	require.NoError(t, cmd.Execute())
+
+	require.Contains(t, out.String(), "Target:     Agent/summarizer@1.4.2")
+	require.NotContains(t, out.String(), "Notes:")
+}
@@ -0,0 +1,107 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	cliCommon "github.com/agentregistry-dev/agentregistry/internal/cli/common"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// errWatchDone ends a deployment event stream once the watched Deployment
+// settles.
+var errWatchDone = errors.New("watch done")
+
+// watchAppliedDeployments streams progress for every Deployment in results
+// that applied, one after another, until each is deployed. It returns an
+// error naming the first Deployment that failed, was deleted, or did not
+// settle within timeout.
+func watchAppliedDeployments(ctx context.Context, c *client.Client, out io.Writer, results []arv0.ApplyResult, timeout time.Duration) error {
+	for _, r := range results {
+		if !strings.EqualFold(r.Kind, v1alpha1.KindDeployment) || r.Status == arv0.ApplyStatusFailed {
+			continue
+		}
+		if err := watchDeployment(ctx, c, out, r.Namespace, r.Name, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchDeployment prints each event on the Deployment's progress stream
+// and returns once the conditions read as deployed.
+func watchDeployment(ctx context.Context, c *client.Client, out io.Writer, namespace, name string, timeout time.Duration) error {
+	if namespace == "" {
+		namespace = v1alpha1.DefaultNamespace
+	}
+	id := cliCommon.DeploymentID(namespace, name)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	dep := &v1alpha1.Deployment{}
+	var failure error
+	err := c.WatchDeploymentEvents(ctx, namespace, name, func(event arv0.DeploymentEvent) error {
+		switch event.Type {
+		case arv0.DeploymentEventCondition:
+			line := fmt.Sprintf("  deployment/%s %s=%s", id, event.Condition, event.Status)
+			if event.Reason != "" {
+				line += " " + event.Reason
+			}
+			if event.Message != "" {
+				line += ": " + event.Message
+			}
+			fmt.Fprintln(out, line)
+			dep.Status.SetCondition(v1alpha1.Condition{
+				Type:    event.Condition,
+				Status:  v1alpha1.ConditionStatus(event.Status),
+				Reason:  event.Reason,
+				Message: event.Message,
+			})
+		case arv0.DeploymentEventTerminating:
+			fmt.Fprintf(out, "  deployment/%s terminating\n", id)
+			now := event.Time
+			dep.Metadata.DeletionTimestamp = &now
+		case arv0.DeploymentEventDeleted:
+			fmt.Fprintf(out, "  deployment/%s deleted\n", id)
+			failure = fmt.Errorf("deployment %s was deleted before it was deployed", id)
+			return errWatchDone
+		}
+
+		switch cliCommon.DeploymentStatus(dep) {
+		case "deployed":
+			fmt.Fprintf(out, "✓ deployment/%s deployed\n", id)
+			return errWatchDone
+		case "failed":
+			failure = fmt.Errorf("deployment %s failed: %s", id, deploymentFailureMessage(dep))
+			return errWatchDone
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errWatchDone):
+		return failure
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("timed out after %s waiting for deployment %s", timeout, id)
+	case err != nil:
+		return fmt.Errorf("watching deployment %s: %w", id, err)
+	}
+	return fmt.Errorf("event stream for deployment %s ended before it was deployed", id)
+}
+
+// deploymentFailureMessage returns the message of the condition that marked
+// dep failed.
+func deploymentFailureMessage(dep *v1alpha1.Deployment) string {
+	if c := dep.Status.GetCondition("Degraded"); c != nil && c.Message != "" {
+		return c.Message
+	}
+	return "Degraded"
+}
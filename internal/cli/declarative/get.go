@@ -50,6 +50,7 @@ Examples:
 	cmd.Flags().String("tag", "", "Tagged kinds only. With NAME: fetch one tag (defaults to latest). Without NAME: filter the list to this tag.")
 	cmd.Flags().Bool("latest", false, "List mode only: restrict to rows pinned to the literal 'latest' tag (equivalent to --tag latest).")
 	cmd.Flags().Bool("all-tags", false, "List every tag of NAME (tagged content kinds only)")
+	cmd.Flags().String("sort", "", "List mode only: order by name (default), updatedAt (newest first), or status (deployments only).")
 	cmd.Flags().String("origin", "", "Deployments only: filter by provenance — managed, discovered, or all (defaults to managed when unset).")
 	return cmd
 }
@@ -61,6 +62,7 @@ func runGet(cmd *cobra.Command, deps cliruntime.Deps, args []string) error {
 	latest, _ := cmd.Flags().GetBool("latest")
 	tag, _ := cmd.Flags().GetString("tag")
 	origin, _ := cmd.Flags().GetString("origin")
+	sort, _ := cmd.Flags().GetString("sort")
 	allTagsFlag := "--all-tags"
 	tagFlag := "--tag"
 	latestFlag := "--latest"
@@ -74,6 +76,9 @@ func runGet(cmd *cobra.Command, deps cliruntime.Deps, args []string) error {
 	if latest && tag != "" {
 		return fmt.Errorf("%s and %s are mutually exclusive", tagFlag, latestFlag)
 	}
+	if allTags && sort != "" {
+		return fmt.Errorf("--sort and %s are mutually exclusive", allTagsFlag)
+	}
 
 	originOpt, err := resolveOrigin(origin)
 	if err != nil {
@@ -84,6 +89,9 @@ func runGet(cmd *cobra.Command, deps cliruntime.Deps, args []string) error {
 		if origin != "" {
 			return fmt.Errorf("--origin cannot be used with `get all`")
 		}
+		if sort != "" {
+			return fmt.Errorf("--sort cannot be used with `get all`")
+		}
 		return runGetAllArg(cmd, deps, kinds, outputFormat, getFlags{
 			allTags: allTags,
 			latest:  latest,
@@ -119,6 +127,9 @@ func runGet(cmd *cobra.Command, deps cliruntime.Deps, args []string) error {
 	if origin != "" && len(args) == 2 {
 		return fmt.Errorf("--origin is a list filter and cannot be combined with a resource NAME")
 	}
+	if sort != "" && len(args) == 2 {
+		return fmt.Errorf("--sort orders a list and cannot be combined with a resource NAME")
+	}
 
 	if deps.Runtime == nil {
 		return fmt.Errorf("registry runtime not configured")
@@ -141,7 +152,7 @@ func runGet(cmd *cobra.Command, deps cliruntime.Deps, args []string) error {
 		return printItem(cmd, k, item, outputFormat)
 	}
 
-	listOpts := scheme.ListOpts{Tag: tag, LatestOnly: latest, Origin: originOpt}
+	listOpts := scheme.ListOpts{Tag: tag, LatestOnly: latest, Origin: originOpt, Sort: sort}
 	items, err := listItems(cmd.Context(), c, k, listOpts)
 	if err != nil {
 		return fmt.Errorf("listing %s: %w", kindPlural(k), err)
@@ -371,3 +371,52 @@ func TestGet_Latest_RejectsGetAll(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "--latest cannot be used with `get all`")
 }
+
+// TestGet_Sort_DeploymentsListPassesSort verifies `--sort` reaches the
+// list query and that every page continues the same sort.
+func TestGet_Sort_DeploymentsListPassesSort(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		captured []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		captured = append(captured, r.URL.RawQuery)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{"items":[],"nextCursor":"page2"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+	setupClientForServer(t, srv)
+
+	cmd := declarative.NewGetCmd(declarativeTestDeps(nil))
+	cmd.SetArgs([]string{"deployments", "--sort", "status"})
+	require.NoError(t, cmd.Execute())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, captured, 2)
+	for _, q := range captured {
+		assert.Contains(t, q, "sort=status", "every page must carry the sort, got %q", q)
+	}
+	assert.Contains(t, captured[1], "cursor=page2")
+}
+
+// TestGet_Sort_RejectsNameAndGetAll pins the --sort guards.
+func TestGet_Sort_RejectsNameAndGetAll(t *testing.T) {
+	setDeclarativeTestClient(t, client.NewClient("http://127.0.0.1:1", ""))
+
+	for _, args := range [][]string{
+		{"agents", "summarizer", "--sort", "updatedAt"},
+		{"agents", "summarizer", "--all-tags", "--sort", "updatedAt"},
+		{"all", "--sort", "updatedAt"},
+	} {
+		cmd := declarative.NewGetCmd(declarativeTestDeps(nil))
+		cmd.SetArgs(args)
+		require.Error(t, cmd.Execute(), "%v", args)
+	}
+}
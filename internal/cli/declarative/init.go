@@ -14,6 +14,7 @@ import (
 
 	"github.com/agentregistry-dev/agentregistry/internal/cli/buildconfig"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/common"
+	"github.com/agentregistry-dev/agentregistry/internal/cli/common/workspace"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative/mcpresolve"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/frameworks"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/scheme"
@@ -537,13 +538,16 @@ func agentTemplateVars(name, description, modelProvider, modelName, image, frame
 }
 
 // loadFrameworkRegistry centralizes the standard load order for arctl commands.
+// The embedded frameworks are staged in a workspace that lives as long as
+// the command: the registry reads its templates from there, and the next
+// command sweeps it once this one has exited.
 func loadFrameworkRegistry(projectRoot string) (*frameworks.Registry, error) {
-	stage, err := os.MkdirTemp("", "arctl-frameworks-*")
+	stage, err := workspace.New("frameworks")
 	if err != nil {
 		return nil, err
 	}
 	return frameworks.LoadAll(frameworks.LoadOpts{
-		StageDir:    stage,
+		StageDir:    stage.Dir,
 		UserDir:     frameworks.UserFrameworksDir(),
 		ProjectRoot: projectRoot,
 	})
@@ -0,0 +1,252 @@
+package declarative
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/internal/version"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+// remoteProbeTimeout bounds the initialize + tools/list round trip against
+// a remote MCP endpoint.
+const remoteProbeTimeout = 30 * time.Second
+
+// NewMCPCmd returns the "mcp" command group for MCP-server shortcuts that
+// don't fit init/apply.
+func NewMCPCmd(deps cliruntime.Deps) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   cliruntime.CommandMCP,
+		Short: "MCP server shortcuts",
+	}
+	cmd.AddCommand(newMCPAddRemoteCmd(deps))
+	cmd.AddCommand(newMCPDiscoverCmd(deps))
+	cmd.AddCommand(newMCPInstallCmd(deps))
+	return cmd
+}
+
+type addRemoteOptions struct {
+	transport   string
+	headers     []string
+	tag         string
+	title       string
+	description string
+	noProbe     bool
+	dryRun      bool
+}
+
+func newMCPAddRemoteCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts addRemoteOptions
+	cmd := &cobra.Command{
+		Use:   "add-remote NAME URL",
+		Short: "Register a hosted MCP endpoint as a remote MCPServer",
+		Long: `Register an already-running MCP endpoint as a remote MCPServer.
+
+Connects to URL, runs initialize and tools/list, and applies an MCPServer
+with spec.remote set. Title and description default to what the server
+reports about itself, and spec.tools records the tools it lists. Headers
+given with --header are sent on the probe and stored on spec.remote.headers.`,
+		Example: `  arctl mcp add-remote linear https://mcp.linear.app/mcp
+  arctl mcp add-remote search https://search.example.com/sse --transport sse --header "Authorization=Bearer $TOKEN"
+  arctl mcp add-remote internal-docs https://docs.internal/mcp --no-probe --dry-run`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMCPAddRemote(cmd.Context(), cmd.OutOrStdout(), deps, args[0], args[1], opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.transport, "transport", "streamable-http", "Remote transport: streamable-http or sse")
+	cmd.Flags().StringArrayVar(&opts.headers, "header", nil, "Header sent to the endpoint, as NAME=VALUE (repeatable)")
+	cmd.Flags().StringVar(&opts.tag, "tag", "", "Tag to publish (default: latest)")
+	cmd.Flags().StringVar(&opts.title, "title", "", "Title (default: the server's reported title)")
+	cmd.Flags().StringVar(&opts.description, "description", "", "Description (default: the server's reported instructions)")
+	cmd.Flags().BoolVar(&opts.noProbe, "no-probe", false, "Skip connecting to the endpoint")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the MCPServer instead of applying it")
+	return cmd
+}
+
+func runMCPAddRemote(ctx context.Context, out io.Writer, deps cliruntime.Deps, name, endpoint string, opts addRemoteOptions) error {
+	if opts.transport != "streamable-http" && opts.transport != "sse" {
+		return fmt.Errorf("--transport must be streamable-http or sse, got %q", opts.transport)
+	}
+	headers, err := parseRemoteHeaders(opts.headers)
+	if err != nil {
+		return err
+	}
+
+	server := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Name: name, Tag: opts.tag},
+		Spec: v1alpha1.MCPServerSpec{
+			Title:       opts.title,
+			Description: opts.description,
+			Remote:      &v1alpha1.MCPRemote{Type: opts.transport, URL: endpoint, Headers: headers},
+		},
+	}
+	if !opts.noProbe {
+		fmt.Fprintf(out, "→ probing %s...\n", endpoint)
+		if err := probeRemote(ctx, server); err != nil {
+			return fmt.Errorf("probe %s (use --no-probe to skip): %w", endpoint, err)
+		}
+		fmt.Fprintf(out, "✓ %d tools\n", len(server.Spec.Tools))
+	}
+	// The server fills in the default namespace on apply.
+	check := *server
+	check.Metadata.Namespace = v1alpha1.DefaultNamespace
+	if err := check.Validate(); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(server)
+	if err != nil {
+		return fmt.Errorf("encode MCPServer: %w", err)
+	}
+	if opts.dryRun {
+		_, err := out.Write(data)
+		return err
+	}
+
+	if deps.Runtime == nil {
+		return fmt.Errorf("registry runtime not configured")
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	results, err := c.Apply(ctx, data, client.ApplyOpts{})
+	if err != nil {
+		return err
+	}
+	printResults(out, results, false)
+	for _, r := range results {
+		if r.Status == arv0.ApplyStatusFailed {
+			return fmt.Errorf("failed to apply MCPServer %q", name)
+		}
+	}
+	return nil
+}
+
+// parseRemoteHeaders turns NAME=VALUE flags into remote headers.
+func parseRemoteHeaders(raw []string) ([]v1alpha1.HTTPHeader, error) {
+	var headers []v1alpha1.HTTPHeader
+	for _, h := range raw {
+		name, value, ok := strings.Cut(h, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("--header must be NAME=VALUE, got %q", h)
+		}
+		headers = append(headers, v1alpha1.HTTPHeader{Name: name, Value: value})
+	}
+	return headers, nil
+}
+
+// probeRemote connects to server's remote endpoint and fills in the title,
+// description and tools it reports. Flag-supplied title and description win.
+func probeRemote(ctx context.Context, server *v1alpha1.MCPServer) error {
+	ctx, cancel := context.WithTimeout(ctx, remoteProbeTimeout)
+	defer cancel()
+
+	remote := server.Spec.Remote
+	httpClient := &http.Client{Transport: headerTransport{
+		base:    httpclient.DefaultTransport(),
+		headers: remote.Headers,
+	}}
+	var transport mcp.Transport
+	if remote.Type == "sse" {
+		transport = &mcp.SSEClientTransport{Endpoint: remote.URL, HTTPClient: httpClient}
+	} else {
+		transport = &mcp.StreamableClientTransport{Endpoint: remote.URL, HTTPClient: httpClient, DisableStandaloneSSE: true, MaxRetries: -1}
+	}
+
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "arctl", Version: version.Version}, nil)
+	session, err := mcpClient.Connect(ctx, transport, nil)
+	if err != nil {
+		return fmt.Errorf("initialize: %w", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	if init := session.InitializeResult(); init != nil {
+		if server.Spec.Title == "" && init.ServerInfo != nil {
+			server.Spec.Title = init.ServerInfo.Title
+		}
+		if server.Spec.Description == "" {
+			server.Spec.Description = strings.TrimSpace(init.Instructions)
+		}
+	}
+	tools, err := sessionTools(ctx, session)
+	if err != nil {
+		return err
+	}
+	server.Spec.Tools = append(server.Spec.Tools, tools...)
+	return nil
+}
+
+// sessionTools lists the tools of a connected MCP server, or none when
+// it doesn't declare the tools capability.
+func sessionTools(ctx context.Context, session *mcp.ClientSession) ([]v1alpha1.MCPTool, error) {
+	if init := session.InitializeResult(); init != nil && (init.Capabilities == nil || init.Capabilities.Tools == nil) {
+		return nil, nil
+	}
+	var tools []v1alpha1.MCPTool
+	for tool, err := range session.Tools(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("tools/list: %w", err)
+		}
+		schema, err := toolInputSchema(tool.InputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("tool %q: %w", tool.Name, err)
+		}
+		tools = append(tools, v1alpha1.MCPTool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: schema,
+		})
+	}
+	return tools, nil
+}
+
+// toolInputSchema normalizes a tool's reported input schema to a plain
+// JSON object.
+func toolInputSchema(schema any) (map[string]any, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("encode input schema: %w", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decode input schema: %w", err)
+	}
+	return out, nil
+}
+
+// headerTransport adds static headers to every request.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers []v1alpha1.HTTPHeader
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.headers) > 0 {
+		req = req.Clone(req.Context())
+		for _, h := range t.headers {
+			req.Header.Set(h.Name, h.Value)
+		}
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,252 @@
+package declarative
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/local"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+	"github.com/agentregistry-dev/agentregistry/pkg/printer"
+)
+
+// scanDockerContainers is a package var so tests can stub the docker
+// shell-out.
+var scanDockerContainers = local.ScanMCPContainers
+
+type discoverOptions struct {
+	docker  bool
+	runtime string
+	yes     bool
+}
+
+func newMCPDiscoverCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts discoverOptions
+	cmd := &cobra.Command{
+		Use:   "discover --docker",
+		Short: "Find MCP servers running in local docker containers",
+		Long: `Scan the running docker containers for MCP servers and offer to register
+them as discovered Deployments on a Local runtime.
+
+A container is an MCP server when its image carries the
+` + local.MCPServerNameLabel + ` label, or when its image is the
+OCI package of an MCPServer already in the registry; the latter takes the
+registry's name, tag and transport. Otherwise the transport is inferred:
+http when the container publishes a TCP port, stdio when it keeps stdin open.
+Containers of the runtime's own compose project are skipped.
+
+Registering records the containers in the runtime's
+spec.config.` + local.DiscoveredContainersConfigKey + `; the registry then keeps
+an origin=discovered Deployment for each while it runs, the same way
+provider discovery does.`,
+		Example: `  arctl mcp discover --docker
+  arctl mcp discover --docker --runtime local --yes`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runMCPDiscover(cmd.Context(), cmd.OutOrStdout(), cmd.InOrStdin(), deps, opts)
+		},
+	}
+	cmd.Flags().BoolVar(&opts.docker, "docker", false, "Scan running docker containers (the only source today)")
+	cmd.Flags().StringVar(&opts.runtime, "runtime", "local", "Local Runtime to register containers on")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Register without asking")
+	return cmd
+}
+
+// discoveryCandidate is a running container that serves MCP, with the
+// runtime entry registering it would add.
+type discoveryCandidate struct {
+	container  local.MCPContainer
+	entry      local.DiscoveredContainer
+	transport  string
+	port       uint16
+	registered bool
+}
+
+func runMCPDiscover(ctx context.Context, out io.Writer, in io.Reader, deps cliruntime.Deps, opts discoverOptions) error {
+	if !opts.docker {
+		return fmt.Errorf("--docker is required; docker is the only discovery source")
+	}
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+
+	ns := v1alpha1.DefaultNamespace
+	rt, err := client.GetTyped(ctx, c, v1alpha1.KindRuntime, ns, opts.runtime, "",
+		func() *v1alpha1.Runtime { return &v1alpha1.Runtime{} })
+	if err != nil {
+		return fmt.Errorf("fetching runtime %q: %w", opts.runtime, err)
+	}
+	if rt.Spec.Type != v1alpha1.TypeLocal {
+		return fmt.Errorf("runtime %q is a %s runtime; docker containers can only be registered on a %s runtime", opts.runtime, rt.Spec.Type, v1alpha1.TypeLocal)
+	}
+	existing, err := local.DiscoveredContainers(rt)
+	if err != nil {
+		return fmt.Errorf("runtime %q: %w", opts.runtime, err)
+	}
+
+	containers, err := scanDockerContainers(ctx)
+	if err != nil {
+		return fmt.Errorf("scanning docker containers: %w", err)
+	}
+	servers, err := client.ListAllTyped(ctx, c, v1alpha1.KindMCPServer,
+		client.ListOpts{Namespace: ns, Limit: 200},
+		func() *v1alpha1.MCPServer { return &v1alpha1.MCPServer{} })
+	if err != nil {
+		return fmt.Errorf("listing MCP servers: %w", err)
+	}
+
+	candidates := mcpDiscoveryCandidates(containers, servers, existing)
+	if len(candidates) == 0 {
+		fmt.Fprintln(out, "No running MCP server containers found.")
+		return nil
+	}
+	t := printer.NewTablePrinter(out)
+	t.SetHeaders("CONTAINER", "IMAGE", "MCPSERVER", "TAG", "TRANSPORT", "PORT", "REGISTERED")
+	var toRegister []local.DiscoveredContainer
+	for _, cand := range candidates {
+		port := "-"
+		if cand.port != 0 {
+			port = strconv.Itoa(int(cand.port))
+		}
+		transport := cand.transport
+		if transport == "" {
+			transport = "unknown"
+		}
+		t.AddRow(cand.container.Name, cand.container.Image, cand.entry.MCPServer, cand.entry.Tag, transport, port, strconv.FormatBool(cand.registered))
+		if !cand.registered {
+			toRegister = append(toRegister, cand.entry)
+		}
+	}
+	if err := t.Render(); err != nil {
+		return err
+	}
+	if len(toRegister) == 0 {
+		fmt.Fprintf(out, "✓ every container is already registered on runtime %q\n", opts.runtime)
+		return nil
+	}
+
+	if !opts.yes {
+		ok, err := confirmDiscoveryRegistration(out, in, len(toRegister), opts.runtime)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(out, "✗ nothing registered")
+			return nil
+		}
+	}
+
+	local.SetDiscoveredContainers(rt, append(existing, toRegister...))
+	data, err := yaml.Marshal(&v1alpha1.Runtime{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindRuntime},
+		Metadata: v1alpha1.ObjectMeta{
+			Namespace:   rt.Metadata.Namespace,
+			Name:        rt.Metadata.Name,
+			Labels:      rt.Metadata.Labels,
+			Annotations: rt.Metadata.Annotations,
+		},
+		Spec: rt.Spec,
+	})
+	if err != nil {
+		return fmt.Errorf("encode Runtime: %w", err)
+	}
+	results, err := c.Apply(ctx, data, client.ApplyOpts{})
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Status == arv0.ApplyStatusFailed {
+			return fmt.Errorf("updating runtime %q: %s", opts.runtime, r.Error)
+		}
+	}
+	fmt.Fprintf(out, "✓ registered %d container(s) on runtime %q\n", len(toRegister), opts.runtime)
+	fmt.Fprintln(out, "  They appear in `arctl get deployments --origin discovered` after the next discovery poll.")
+	return nil
+}
+
+// mcpDiscoveryCandidates picks the containers that serve MCP: those whose
+// image is the OCI package of a known MCPServer, and those carrying the
+// MCP server name label. A known image wins, so the registry's name, tag
+// and transport are used.
+func mcpDiscoveryCandidates(containers []local.MCPContainer, servers []*v1alpha1.MCPServer, existing []local.DiscoveredContainer) []discoveryCandidate {
+	known := map[string]*v1alpha1.MCPServer{}
+	for _, server := range servers {
+		if server.Spec.Source == nil || server.Spec.Source.Package == nil {
+			continue
+		}
+		pkg := server.Spec.Source.Package
+		if pkg.Origin.Type != v1alpha1.MCPPackageOriginTypeOCI || pkg.Origin.Identifier == "" {
+			continue
+		}
+		if _, ok := known[pkg.Origin.Identifier]; !ok {
+			known[pkg.Origin.Identifier] = server
+		}
+	}
+	registered := map[string]bool{}
+	for _, e := range existing {
+		registered[e.Container] = true
+	}
+
+	var out []discoveryCandidate
+	for _, container := range containers {
+		cand := discoveryCandidate{
+			container:  container,
+			transport:  container.Transport,
+			port:       container.HostPort(0),
+			registered: registered[container.Name],
+		}
+		if server, ok := known[container.Image]; ok {
+			transport := server.Spec.Source.Package.Transport
+			cand.entry = local.DiscoveredContainer{Container: container.Name, MCPServer: server.Metadata.Name, Tag: server.Metadata.Tag}
+			cand.transport = transport.Type
+			cand.port = 0
+			if transport.Type != "stdio" {
+				cand.port = container.HostPort(transport.Port)
+			}
+		} else if container.ServerName != "" {
+			cand.entry = local.DiscoveredContainer{Container: container.Name, MCPServer: container.ServerName, Tag: imageTag(container.Image)}
+		} else {
+			continue
+		}
+		out = append(out, cand)
+	}
+	return out
+}
+
+// imageTag returns the tag of an image reference: "latest" when it has
+// none, empty when it is pinned by digest.
+func imageTag(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return "latest"
+}
+
+// confirmDiscoveryRegistration asks y/N on in before registering
+// containers. Anything but "y"/"yes" (including EOF) declines.
+func confirmDiscoveryRegistration(out io.Writer, in io.Reader, n int, runtime string) (bool, error) {
+	fmt.Fprintf(out, "? Register %d container(s) as discovered deployments on runtime %q? (y/N): ", n, runtime)
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("read confirmation: %w", err)
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes", nil
+}
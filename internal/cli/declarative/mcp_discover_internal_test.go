@@ -0,0 +1,107 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/local"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+func TestMCPDiscoveryCandidates(t *testing.T) {
+	containers := []local.MCPContainer{
+		{Name: "files", Image: "ghcr.io/acme/files:2.0.0", Transport: "http", Ports: map[uint16]uint16{3000: 13000, 9000: 19000}},
+		{Name: "postgres", Image: "postgres:16"},
+		{Name: "weather", Image: "ghcr.io/acme/weather:1.2.0", ServerName: "io.github.acme/weather", Transport: "http", Ports: map[uint16]uint16{8080: 18080}},
+		{Name: "pinned", Image: "ghcr.io/acme/pinned@sha256:abc", ServerName: "pinned", Transport: "stdio"},
+	}
+	servers := []*v1alpha1.MCPServer{{
+		Metadata: v1alpha1.ObjectMeta{Name: "files", Tag: "2.0.0"},
+		Spec: v1alpha1.MCPServerSpec{Source: &v1alpha1.MCPServerSource{Package: &v1alpha1.MCPPackage{
+			Origin:    v1alpha1.MCPPackageOrigin{Type: v1alpha1.MCPPackageOriginTypeOCI, Identifier: "ghcr.io/acme/files:2.0.0"},
+			Transport: v1alpha1.MCPTransport{Type: "http", Port: 9000, Path: "/mcp"},
+		}}},
+	}}
+
+	got := mcpDiscoveryCandidates(containers, servers, []local.DiscoveredContainer{{Container: "weather", MCPServer: "io.github.acme/weather"}})
+	require.Len(t, got, 3)
+
+	require.Equal(t, local.DiscoveredContainer{Container: "files", MCPServer: "files", Tag: "2.0.0"}, got[0].entry)
+	require.Equal(t, "http", got[0].transport)
+	require.Equal(t, uint16(19000), got[0].port, "a known image maps the package's declared port")
+	require.False(t, got[0].registered)
+
+	require.Equal(t, local.DiscoveredContainer{Container: "weather", MCPServer: "io.github.acme/weather", Tag: "1.2.0"}, got[1].entry)
+	require.Equal(t, uint16(18080), got[1].port)
+	require.True(t, got[1].registered)
+
+	require.Equal(t, local.DiscoveredContainer{Container: "pinned", MCPServer: "pinned"}, got[2].entry)
+	require.Equal(t, "stdio", got[2].transport)
+}
+
+func TestMCPDiscover_RegistersConfirmedContainersOnRuntime(t *testing.T) {
+	original := scanDockerContainers
+	t.Cleanup(func() { scanDockerContainers = original })
+	scanDockerContainers = func(context.Context) ([]local.MCPContainer, error) {
+		return []local.MCPContainer{
+			{Name: "weather", Image: "ghcr.io/acme/weather:1.2.0", ServerName: "weather", Transport: "http", Ports: map[uint16]uint16{8080: 18080}},
+		}, nil
+	}
+
+	var applied []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v0/runtimes/local":
+			_ = json.NewEncoder(w).Encode(v1alpha1.Runtime{
+				TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindRuntime},
+				Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "local"},
+				Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeLocal, Config: map[string]any{"verbose": true}},
+			})
+		case "GET /v0/mcpservers":
+			_, _ = w.Write([]byte(`{"items":[]}`))
+		case "POST /v0/apply":
+			applied, _ = io.ReadAll(r.Body)
+			body, _ := json.Marshal(map[string]any{"results": []arv0.ApplyResult{{
+				Kind: v1alpha1.KindRuntime, Name: "local", Status: arv0.ApplyStatusConfigured,
+			}}})
+			_, _ = w.Write(body)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	deps := internalDeclarativeTestDeps(client.NewClient(srv.URL, ""))
+
+	var out bytes.Buffer
+	require.ErrorContains(t, runMCPDiscover(t.Context(), &out, strings.NewReader(""), deps, discoverOptions{runtime: "local"}), "--docker is required")
+
+	// Declining registers nothing.
+	out.Reset()
+	require.NoError(t, runMCPDiscover(t.Context(), &out, strings.NewReader("n\n"), deps, discoverOptions{docker: true, runtime: "local"}))
+	require.Contains(t, out.String(), "18080")
+	require.Contains(t, out.String(), "nothing registered")
+	require.Nil(t, applied)
+
+	out.Reset()
+	require.NoError(t, runMCPDiscover(t.Context(), &out, strings.NewReader("y\n"), deps, discoverOptions{docker: true, runtime: "local"}))
+	require.Contains(t, out.String(), "registered 1 container(s)")
+
+	var rt v1alpha1.Runtime
+	require.NoError(t, yaml.Unmarshal(applied, &rt))
+	require.Equal(t, true, rt.Spec.Config["verbose"])
+	entries, err := local.DiscoveredContainers(&rt)
+	require.NoError(t, err)
+	require.Equal(t, []local.DiscoveredContainer{{Container: "weather", MCPServer: "weather", Tag: "1.2.0"}}, entries)
+}
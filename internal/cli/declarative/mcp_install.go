@@ -0,0 +1,104 @@
+package declarative
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/configure"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+type installOptions struct {
+	client     string
+	tag        string
+	namespace  string
+	configPath string
+	as         string
+	dryRun     bool
+}
+
+func newMCPInstallCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts installOptions
+	cmd := &cobra.Command{
+		Use:   "install NAME --client claude|cursor|vscode",
+		Short: "Add an MCPServer to a local MCP client's config file",
+		Long: `Add an MCPServer to the config file of Claude Desktop, Cursor or VS Code.
+
+The registry renders the client's entry from the server's remote or stdio
+package (GET /v0/mcpservers/{name}/install); it is written under the last
+segment of NAME, replacing an entry of the same key and keeping everything
+else in the file. Claude Desktop's file is the per-user
+claude_desktop_config.json; Cursor and VS Code use .cursor/mcp.json and
+.vscode/mcp.json in the current directory.
+
+Environment variables and headers the server declares without a value, or
+whose value reads a registry secret, are written empty and listed so you can
+fill them in.`,
+		Example: `  arctl mcp install acme/weather --client cursor
+  arctl mcp install acme/weather --client claude --tag 1.2.0
+  arctl mcp install acme/weather --client vscode --as weather-eu --dry-run`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMCPInstall(cmd.Context(), cmd.OutOrStdout(), deps, args[0], opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.client, "client", "", "MCP client: claude (Claude Desktop), cursor or vscode")
+	cmd.Flags().StringVar(&opts.tag, "tag", "", "Tag to install (default: latest)")
+	cmd.Flags().StringVar(&opts.namespace, "namespace", v1alpha1.DefaultNamespace, "Namespace of the MCPServer")
+	cmd.Flags().StringVar(&opts.configPath, "config", "", "Config file to write (default: the client's)")
+	cmd.Flags().StringVar(&opts.as, "as", "", "Key to add the server under (default: the last segment of NAME)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the config snippet instead of writing it")
+	_ = cmd.MarkFlagRequired("client")
+	return cmd
+}
+
+func runMCPInstall(ctx context.Context, out io.Writer, deps cliruntime.Deps, name string, opts installOptions) error {
+	configPath := opts.configPath
+	if configPath == "" && !opts.dryRun {
+		path, err := configure.ServerConfigPath(opts.client)
+		if err != nil {
+			return err
+		}
+		configPath = path
+	}
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	install, err := c.MCPServerInstall(ctx, opts.namespace, name, opts.tag, opts.client)
+	if err != nil {
+		return fmt.Errorf("rendering %s config for MCPServer %s: %w", opts.client, name, err)
+	}
+	serverKey := install.ServerKey
+	if opts.as != "" {
+		serverKey = opts.as
+	}
+
+	if opts.dryRun {
+		snippet := map[string]map[string]any{install.Key: {serverKey: install.Entry}}
+		data, err := json.MarshalIndent(snippet, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode config: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	} else {
+		if err := configure.AddServer(configPath, install.Key, serverKey, install.Entry); err != nil {
+			return fmt.Errorf("writing %s: %w", configPath, err)
+		}
+		fmt.Fprintf(out, "✓ added %s@%s to %s as %q\n", install.Name, install.Tag, configPath, serverKey)
+	}
+	if len(install.Missing) > 0 {
+		fmt.Fprintf(out, "Fill in before starting the client: %s\n", strings.Join(install.Missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package declarative_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// newRemoteMCPServer serves a streamable-http MCP server with one tool and
+// records the Authorization header of the last request.
+func newRemoteMCPServer(t *testing.T) (*httptest.Server, *string) {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "weather", Title: "Weather", Version: "1.0.0"},
+		&mcp.ServerOptions{Instructions: "Forecasts by city."})
+	type forecastIn struct {
+		City string `json:"city"`
+	}
+	mcp.AddTool(server, &mcp.Tool{Name: "forecast", Description: "Forecast for a city"},
+		func(context.Context, *mcp.CallToolRequest, forecastIn) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{}, nil, nil
+		})
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &auth
+}
+
+func TestMCPAddRemote_ProbesAndApplies(t *testing.T) {
+	remote, auth := newRemoteMCPServer(t)
+
+	var applied []byte
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		applied, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(batchApplyResponse([]arv0.ApplyResult{{
+			Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "latest", Status: arv0.ApplyStatusCreated,
+		}}))
+	}))
+	t.Cleanup(registry.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewMCPCmd(applyDeps(t, registry))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"add-remote", "weather", remote.URL, "--header", "Authorization=Bearer secret"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, "Bearer secret", *auth)
+	require.Contains(t, out.String(), "MCPServer/weather")
+
+	var server v1alpha1.MCPServer
+	require.NoError(t, yaml.Unmarshal(applied, &server))
+	require.Equal(t, "Weather", server.Spec.Title)
+	require.Equal(t, "Forecasts by city.", server.Spec.Description)
+	require.Equal(t, &v1alpha1.MCPRemote{
+		Type:    "streamable-http",
+		URL:     remote.URL,
+		Headers: []v1alpha1.HTTPHeader{{Name: "Authorization", Value: "Bearer secret"}},
+	}, server.Spec.Remote)
+	require.Len(t, server.Spec.Tools, 1)
+	require.Equal(t, "forecast", server.Spec.Tools[0].Name)
+	require.Equal(t, "Forecast for a city", server.Spec.Tools[0].Description)
+	require.Contains(t, server.Spec.Tools[0].InputSchema, "properties")
+}
+
+func TestMCPAddRemote_DryRunWithoutProbe(t *testing.T) {
+	var out bytes.Buffer
+	cmd := declarative.NewMCPCmd(declarativeTestDeps(nil))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"add-remote", "docs", "https://docs.example.com/sse",
+		"--transport", "sse", "--title", "Docs", "--no-probe", "--dry-run"})
+	require.NoError(t, cmd.Execute())
+
+	var server v1alpha1.MCPServer
+	require.NoError(t, yaml.Unmarshal(out.Bytes(), &server))
+	require.Equal(t, v1alpha1.KindMCPServer, server.Kind)
+	require.Equal(t, "docs", server.Metadata.Name)
+	require.Equal(t, "Docs", server.Spec.Title)
+	require.Equal(t, "sse", server.Spec.Remote.Type)
+	require.Empty(t, server.Spec.Tools)
+}
+
+func TestMCPAddRemote_RejectsBadFlags(t *testing.T) {
+	for name, args := range map[string][]string{
+		"transport": {"add-remote", "x", "https://x.example.com", "--transport", "stdio", "--no-probe"},
+		"header":    {"add-remote", "x", "https://x.example.com", "--header", "novalue", "--no-probe"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cmd := declarative.NewMCPCmd(declarativeTestDeps(nil))
+			cmd.SetArgs(args)
+			require.ErrorContains(t, cmd.Execute(), "--"+name)
+		})
+	}
+}
+
+func TestMCPInstall_WritesClientConfig(t *testing.T) {
+	var query url.Values
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v0/mcpservers/acme%2Fweather/install", r.URL.EscapedPath())
+		query = r.URL.Query()
+		entry := arv0.MCPClientServer{Command: "npx", Args: []string{"-y", "@acme/weather-mcp@1.2.0"}, Env: map[string]string{"API_KEY": ""}}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(arv0.MCPServerInstall{
+			Namespace: "default", Name: "acme/weather", Tag: "1.2.0", Client: "cursor",
+			Key: "mcpServers", ServerKey: "weather", Entry: entry,
+			Config:  map[string]map[string]arv0.MCPClientServer{"mcpServers": {"weather": entry}},
+			Missing: []string{"API_KEY"},
+		})
+	}))
+	t.Cleanup(registry.Close)
+
+	configPath := filepath.Join(t.TempDir(), "mcp.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"mcpServers":{"ARCTL":{"url":"http://localhost:21212/mcp"}}}`), 0o644))
+
+	var out bytes.Buffer
+	cmd := declarative.NewMCPCmd(applyDeps(t, registry))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"install", "acme/weather", "--client", "cursor", "--tag", "1.2.0", "--config", configPath})
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, url.Values{"client": {"cursor"}, "tag": {"1.2.0"}}, query)
+	require.Contains(t, out.String(), `added acme/weather@1.2.0 to `+configPath+` as "weather"`)
+	require.Contains(t, out.String(), "Fill in before starting the client: API_KEY")
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"mcpServers":{
+		"ARCTL":{"url":"http://localhost:21212/mcp"},
+		"weather":{"command":"npx","args":["-y","@acme/weather-mcp@1.2.0"],"env":{"API_KEY":""}}
+	}}`, string(data))
+
+	out.Reset()
+	cmd = declarative.NewMCPCmd(applyDeps(t, registry))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"install", "acme/weather", "--client", "cursor", "--as", "weather-eu", "--dry-run"})
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, out.String(), `"weather-eu": {`)
+}
+
+func TestMCPInstall_RequiresSupportedClient(t *testing.T) {
+	cmd := declarative.NewMCPCmd(declarativeTestDeps(nil))
+	cmd.SetArgs([]string{"install", "acme/weather"})
+	require.ErrorContains(t, cmd.Execute(), `"client" not set`)
+
+	cmd = declarative.NewMCPCmd(declarativeTestDeps(nil))
+	cmd.SetArgs([]string{"install", "acme/weather", "--client", "zed"})
+	require.ErrorContains(t, cmd.Execute(), `client "zed" is not supported`)
+}
@@ -0,0 +1,204 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/common/docker"
+	runtimetypes "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/types"
+	runtimeutils "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/utils"
+	"github.com/agentregistry-dev/agentregistry/internal/version"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// runtimeProbeTimeout bounds booting one MCP server package and listing
+// its tools, pulling its image included.
+const runtimeProbeTimeout = 3 * time.Minute
+
+// probeMCPPackage boots a resolved MCP server package and lists its
+// tools. A package var so tests can stub the docker shell-out.
+var probeMCPPackage = probeMCPContainer
+
+// validateMCPRuntimes boots every bundled MCPServer in data in an
+// ephemeral container, runs the MCP initialize handshake and tools/list,
+// and records the tools it reports in spec.tools. A server that fails to
+// boot or answer fails the validation. Remote servers are skipped; env
+// fills the package's environment variables, required ones included.
+func validateMCPRuntimes(ctx context.Context, data []byte, env map[string]string, out io.Writer) ([]byte, error) {
+	docs, err := splitYAMLDocs(data)
+	if err != nil {
+		return nil, err
+	}
+	var probed bool
+	for _, root := range mappingRoots(docs) {
+		if scalarValue(root, "kind") != v1alpha1.KindMCPServer {
+			continue
+		}
+		var server v1alpha1.MCPServer
+		if err := root.Decode(&server); err != nil {
+			return nil, fmt.Errorf("decode MCPServer: %w", err)
+		}
+		if server.Spec.Source == nil || server.Spec.Source.Package == nil {
+			fmt.Fprintf(out, "→ MCPServer %s has no package; skipping runtime validation\n", server.Metadata.Name)
+			continue
+		}
+		envValues := make(map[string]string, len(env))
+		for k, v := range env {
+			envValues[k] = v
+		}
+		resolved, err := runtimeutils.TranslateMCPServer(ctx, &runtimeutils.MCPServerRunRequest{
+			Name:      server.Metadata.Name,
+			Spec:      server.Spec,
+			EnvValues: envValues,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("MCPServer %s: %w", server.Metadata.Name, err)
+		}
+		fmt.Fprintf(out, "→ booting MCPServer %s (%s)\n", server.Metadata.Name, resolved.Local.Deployment.Image)
+		tools, err := probeMCPPackage(ctx, resolved.Local)
+		if err != nil {
+			return nil, fmt.Errorf("MCPServer %s failed runtime validation: %w", server.Metadata.Name, err)
+		}
+		fmt.Fprintf(out, "✓ MCPServer %s answered initialize and listed %d tools\n", server.Metadata.Name, len(tools))
+
+		var toolsNode yaml.Node
+		if err := toolsNode.Encode(tools); err != nil {
+			return nil, fmt.Errorf("encode tools: %w", err)
+		}
+		setMappingChild(findOrCreateMappingChild(root, "spec"), "tools", &toolsNode)
+		probed = true
+	}
+	if !probed {
+		return data, nil
+	}
+	return marshalYAMLDocs(docs)
+}
+
+// setMappingChild sets mapping[key] = value, replacing an existing entry.
+func setMappingChild(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i < len(mapping.Content)-1; i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+// parseValidateEnv turns NAME=VALUE flags into an env map.
+func parseValidateEnv(raw []string) (map[string]string, error) {
+	env := make(map[string]string, len(raw))
+	for _, kv := range raw {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("--validate-env must be NAME=VALUE, got %q", kv)
+		}
+		env[name] = value
+	}
+	return env, nil
+}
+
+// probeMCPContainer runs the package with docker the way the local
+// runtime would, connects over its transport and lists its tools. The
+// container is removed afterwards.
+func probeMCPContainer(ctx context.Context, server *runtimetypes.LocalMCPServer) ([]v1alpha1.MCPTool, error) {
+	if _, err := docker.ServerVersion(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, runtimeProbeTimeout)
+	defer cancel()
+
+	deployment := server.Deployment
+	name := "arctl-validate-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	defer func() { _ = exec.Command("docker", "rm", "-f", name).Run() }()
+
+	args := []string{"run", "--name", name}
+	for _, kv := range runtimeutils.EnvMapToStringSlice(deployment.Env) {
+		args = append(args, "-e", kv)
+	}
+	var command []string
+	if deployment.Cmd != "" {
+		command = append([]string{deployment.Cmd}, deployment.Args...)
+	}
+
+	var (
+		transport mcp.Transport
+		stderr    bytes.Buffer
+	)
+	if server.TransportType == runtimetypes.TransportTypeStdio {
+		args = append(append(args, "--rm", "-i", deployment.Image), command...)
+		cmd := exec.CommandContext(ctx, "docker", args...)
+		cmd.Stderr = &stderr
+		transport = &mcp.CommandTransport{Command: cmd}
+	} else {
+		if server.HTTP == nil || server.HTTP.Port == 0 {
+			return nil, fmt.Errorf("http transport needs a port")
+		}
+		port := strconv.FormatUint(uint64(server.HTTP.Port), 10)
+		args = append(append(args, "-d", "-p", "127.0.0.1::"+port, deployment.Image), command...)
+		if output, err := exec.CommandContext(ctx, "docker", args...).CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("docker run: %w: %s", err, strings.TrimSpace(string(output)))
+		}
+		output, err := exec.CommandContext(ctx, "docker", "port", name, port+"/tcp").Output()
+		if err != nil {
+			return nil, fmt.Errorf("docker port: %w", err)
+		}
+		addr, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+		transport = &mcp.StreamableClientTransport{
+			Endpoint:             "http://" + addr + server.HTTP.Path,
+			DisableStandaloneSSE: true,
+			MaxRetries:           -1,
+		}
+	}
+
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "arctl", Version: version.Version}, nil)
+	session, err := connectMCP(ctx, mcpClient, transport, name, server.TransportType != runtimetypes.TransportTypeStdio)
+	if err != nil {
+		if detail := lastLines(stderr.String(), 20); detail != "" {
+			return nil, fmt.Errorf("initialize: %w\n%s", err, detail)
+		}
+		return nil, fmt.Errorf("initialize: %w", err)
+	}
+	defer func() { _ = session.Close() }()
+	return sessionTools(ctx, session)
+}
+
+// connectMCP runs the initialize handshake. An http server gets until ctx
+// is done to start listening, as long as its container keeps running;
+// the container's logs are returned when it exits.
+func connectMCP(ctx context.Context, c *mcp.Client, transport mcp.Transport, container string, retry bool) (*mcp.ClientSession, error) {
+	for {
+		session, err := c.Connect(ctx, transport, nil)
+		if err == nil || !retry {
+			return session, err
+		}
+		state, _ := exec.Command("docker", "inspect", "-f", "{{.State.Running}}", container).Output()
+		if strings.TrimSpace(string(state)) != "true" {
+			logs, _ := exec.Command("docker", "logs", "--tail", "20", container).CombinedOutput()
+			return nil, fmt.Errorf("container exited: %w\n%s", err, strings.TrimSpace(string(logs)))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// lastLines returns the last n lines of s, trimmed.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,106 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	runtimetypes "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/types"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+const validateRuntimeManifests = `apiVersion: ar.dev/v1alpha1
+kind: MCPServer
+metadata:
+  name: weather
+  tag: 1.0.0
+spec:
+  description: Forecasts.
+  tools:
+    - name: stale
+  source:
+    package:
+      origin:
+        type: npm
+        identifier: "@acme/weather"
+        npm:
+          version: 1.0.0
+          serverName: weather
+      launch:
+        command: npx
+        args:
+          - type: positional
+            value: "@acme/weather@1.0.0"
+        env:
+          - name: WEATHER_API_KEY
+            isRequired: true
+      transport:
+        type: stdio
+---
+apiVersion: ar.dev/v1alpha1
+kind: MCPServer
+metadata:
+  name: hosted
+spec:
+  remote:
+    type: streamable-http
+    url: https://mcp.example.com/mcp
+`
+
+func TestValidateMCPRuntimes_RecordsListedTools(t *testing.T) {
+	original := probeMCPPackage
+	t.Cleanup(func() { probeMCPPackage = original })
+	var probed []*runtimetypes.LocalMCPServer
+	probeMCPPackage = func(_ context.Context, server *runtimetypes.LocalMCPServer) ([]v1alpha1.MCPTool, error) {
+		probed = append(probed, server)
+		return []v1alpha1.MCPTool{{Name: "get_forecast", Description: "Forecast for a city."}}, nil
+	}
+
+	var out bytes.Buffer
+	data, err := validateMCPRuntimes(context.Background(), []byte(validateRuntimeManifests), map[string]string{"WEATHER_API_KEY": "test"}, &out)
+	require.NoError(t, err)
+
+	require.Len(t, probed, 1, "remote servers are not booted")
+	require.Equal(t, types.DefaultNPMRunnerImage, probed[0].Deployment.Image)
+	require.Equal(t, "npx", probed[0].Deployment.Cmd)
+	require.Equal(t, []string{"@acme/weather@1.0.0"}, probed[0].Deployment.Args)
+	require.Equal(t, "test", probed[0].Deployment.Env["WEATHER_API_KEY"])
+	require.Equal(t, runtimetypes.TransportTypeStdio, probed[0].TransportType)
+	require.Contains(t, out.String(), "MCPServer weather answered initialize and listed 1 tools")
+	require.Contains(t, out.String(), "MCPServer hosted has no package")
+
+	docs, err := splitYAMLDocs(data)
+	require.NoError(t, err)
+	roots := mappingRoots(docs)
+	require.Len(t, roots, 2)
+	var server v1alpha1.MCPServer
+	require.NoError(t, roots[0].Decode(&server))
+	require.Equal(t, []v1alpha1.MCPTool{{Name: "get_forecast", Description: "Forecast for a city."}}, server.Spec.Tools)
+	require.Equal(t, "Forecasts.", server.Spec.Description)
+}
+
+func TestValidateMCPRuntimes_Failures(t *testing.T) {
+	original := probeMCPPackage
+	t.Cleanup(func() { probeMCPPackage = original })
+	probeMCPPackage = func(context.Context, *runtimetypes.LocalMCPServer) ([]v1alpha1.MCPTool, error) {
+		return nil, errors.New("initialize: EOF")
+	}
+
+	_, err := validateMCPRuntimes(context.Background(), []byte(validateRuntimeManifests), map[string]string{}, &bytes.Buffer{})
+	require.ErrorContains(t, err, "missing required environment variables: WEATHER_API_KEY")
+
+	_, err = validateMCPRuntimes(context.Background(), []byte(validateRuntimeManifests), map[string]string{"WEATHER_API_KEY": "test"}, &bytes.Buffer{})
+	require.ErrorContains(t, err, "MCPServer weather failed runtime validation: initialize: EOF")
+}
+
+func TestParseValidateEnv(t *testing.T) {
+	env, err := parseValidateEnv([]string{"A=1", "B=x=y", "C="})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"A": "1", "B": "x=y", "C": ""}, env)
+	_, err = parseValidateEnv([]string{"A"})
+	require.ErrorContains(t, err, "NAME=VALUE")
+}
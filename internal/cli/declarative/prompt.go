@@ -0,0 +1,186 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+// NewPromptCmd returns the "prompt" command group.
+func NewPromptCmd(deps cliruntime.Deps) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     cliruntime.CommandPrompt,
+		Aliases: []string{"prompts"},
+		Short:   "Evaluate prompts and show their scores",
+	}
+	cmd.AddCommand(newPromptEvaluateCmd(deps))
+	cmd.AddCommand(newPromptScoresCmd(deps))
+	return cmd
+}
+
+func newPromptEvaluateCmd(deps cliruntime.Deps) *cobra.Command {
+	var namespace, tag string
+	var verbose bool
+	cmd := &cobra.Command{
+		Use:   "evaluate NAME",
+		Short: "Score a prompt tag against the registry's evaluation model",
+		Long: `Run the cases in a Prompt's spec.evaluation against the model the
+registry is configured with and record the score. Tags are also scored
+when they are published; use this to re-run a tag, for example after
+changing the model.
+
+The score is compared with the newest earlier score of another tag, so a
+regression shows before the tag is promoted.`,
+		Example: `  arctl prompt evaluate summarizer-system-prompt --tag 1.3.0
+  arctl prompt evaluate summarizer-system-prompt --tag 1.3.0 --verbose`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPromptEvaluate(cmd.Context(), cmd.OutOrStdout(), deps, namespace, args[0], tag, verbose)
+		},
+	}
+	cmd.Flags().StringVar(&namespace, "namespace", v1alpha1.DefaultNamespace, "Namespace of the prompt")
+	cmd.Flags().StringVar(&tag, "tag", "latest", "Tag to evaluate")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Also print each case's answer and checks")
+	return cmd
+}
+
+func runPromptEvaluate(ctx context.Context, out io.Writer, deps cliruntime.Deps, namespace, name, tag string, verbose bool) error {
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	eval, err := c.EvaluatePrompt(ctx, namespace, name, tag)
+	if err != nil {
+		return fmt.Errorf("evaluating prompt %s@%s: %w", name, tag, err)
+	}
+	if eval.Score == nil {
+		fmt.Fprintf(out, "Prompt %s@%s could not be scored with %s: %s\n", name, eval.Tag, eval.Model, eval.Error)
+	} else {
+		fmt.Fprintf(out, "Prompt %s@%s scored %s with %s%s\n", name, eval.Tag, formatScore(eval.Score), eval.Model, formatChange(*eval))
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  CASE\tSCORE\tDETAIL")
+	for _, cs := range eval.Cases {
+		detail := cs.Error
+		if detail == "" {
+			detail = failedChecks(cs.Checks)
+		}
+		fmt.Fprintf(tw, "  %s\t%.0f%%\t%s\n", cs.Name, cs.Score*100, orDashString(detail))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if verbose {
+		for _, cs := range eval.Cases {
+			fmt.Fprintf(out, "\n%s:\n", cs.Name)
+			printNoteBody(out, "  ", cs.Answer, nil)
+			for _, check := range cs.Checks {
+				fmt.Fprintf(out, "  - %s: %g", check.Check, check.Score)
+				if check.Detail != "" {
+					fmt.Fprintf(out, " (%s)", check.Detail)
+				}
+				fmt.Fprintln(out)
+			}
+		}
+	}
+	return nil
+}
+
+func newPromptScoresCmd(deps cliruntime.Deps) *cobra.Command {
+	var namespace, tag string
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "scores NAME",
+		Short: "Show a prompt's evaluation score history",
+		Long: `List a Prompt's evaluation scores, newest first. CHANGE compares each
+score with the newest earlier score of another tag; a negative change is a
+regression.`,
+		Example: `  arctl prompt scores summarizer-system-prompt
+  arctl prompt scores summarizer-system-prompt --tag 1.3.0`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPromptScores(cmd.Context(), cmd.OutOrStdout(), deps, namespace, args[0], tag, limit)
+		},
+	}
+	cmd.Flags().StringVar(&namespace, "namespace", v1alpha1.DefaultNamespace, "Namespace of the prompt")
+	cmd.Flags().StringVar(&tag, "tag", "", "Only show scores of this tag")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Max scores to show")
+	return cmd
+}
+
+func runPromptScores(ctx context.Context, out io.Writer, deps cliruntime.Deps, namespace, name, tag string, limit int) error {
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	evals, err := c.PromptEvaluations(ctx, namespace, name, tag, limit)
+	if err != nil {
+		return fmt.Errorf("fetching scores of prompt %s: %w", name, err)
+	}
+	if len(evals) == 0 {
+		fmt.Fprintln(out, "No evaluations.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TAG\tSCORE\tCHANGE\tMODEL\tTRIGGER\tWHEN")
+	for _, e := range evals {
+		change := "-"
+		if e.Change != nil {
+			change = fmt.Sprintf("%+.1f vs %s", *e.Change, e.ChangeFrom)
+		}
+		score := formatScore(e.Score)
+		if e.Score == nil {
+			score = "error"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Tag, score, change, e.Model, e.Trigger, formatTime(e.CreatedAt))
+	}
+	return tw.Flush()
+}
+
+func formatScore(score *float64) string {
+	if score == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*score, 'f', 1, 64)
+}
+
+func formatChange(e arv0.PromptEvalRun) string {
+	if e.Change == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (%+.1f vs %s)", *e.Change, e.ChangeFrom)
+}
+
+// failedChecks names the checks of a case that did not fully pass.
+func failedChecks(checks []arv0.PromptCheckResult) string {
+	var failed string
+	for _, check := range checks {
+		if check.Score >= 1 {
+			continue
+		}
+		if failed != "" {
+			failed += "; "
+		}
+		failed += "failed " + check.Check
+		if check.Detail != "" {
+			failed += " (" + check.Detail + ")"
+		}
+	}
+	return failed
+}
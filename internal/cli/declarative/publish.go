@@ -0,0 +1,241 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/common/gitutil"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+	"github.com/agentregistry-dev/agentregistry/pkg/printer"
+)
+
+type publishOptions struct {
+	fromGit          bool
+	remote           string
+	requireSignedTag bool
+	image            string
+	platform         string
+	dryRun           bool
+	manifestDir      string
+	skipReadmeFetch  bool
+}
+
+// NewPublishCmd returns a new "publish" cobra command.
+func NewPublishCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts publishOptions
+	cmd := &cobra.Command{
+		Use:   cliruntime.CommandPublish + " [DIRECTORY]",
+		Short: "Build, push and publish an agent project in one step",
+		Long: `Build and push the agent's image, then apply its agent.yaml to the registry.
+
+With --from-git the release is taken from git instead of agent.yaml, so a
+CI job publishing a tag needs no scripting:
+
+  - metadata.name is the repository name of the remote (--remote, default
+    origin), e.g. weather-agent for git@github.com:acme/weather-agent.git
+  - metadata.tag is the vX.Y.Z tag on HEAD without its "v", e.g. 1.2.3
+  - the image keeps its repository and is tagged with that version
+  - spec.source.repository records the remote, commit and subfolder
+
+The work tree must be clean and, unless --require-signed-tag=false, the tag
+must pass 'git verify-tag'.
+
+A README.md in the project directory is uploaded as the published tag's
+README.
+
+With --from-manifest-dir, publish every server.json, mcp.yaml, skill.yaml,
+prompt.yaml and agent.yaml under a directory tree, as a monorepo release job
+would. Every manifest is validated first; tags the registry already has are
+skipped, and the rest are applied MCP servers, skills and prompts first, then
+agents. Images are not built, so agents must reference pushed images. A
+summary table is printed and the command exits non-zero if any manifest
+failed. Under GitHub Actions failures are also emitted as ::error
+annotations. An MCP server with neither spec.readme nor a README.md next to
+its manifest gets the README of its GitHub source repository when the
+registry fetches READMEs; --skip-readme-fetch publishes it without one.
+--skip-readme-fetch sets the same annotation when publishing one directory.
+
+Examples:
+  arctl publish ./my-agent
+  arctl publish . --from-git
+  arctl publish ./agents/weather --from-git --require-signed-tag=false --dry-run
+  arctl publish --from-manifest-dir . --dry-run`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.manifestDir == "" {
+				if len(args) != 1 {
+					return fmt.Errorf("requires a DIRECTORY argument or --from-manifest-dir")
+				}
+				return runPublish(cmd.Context(), cmd.OutOrStdout(), deps, args[0], opts)
+			}
+			if len(args) > 0 || opts.fromGit || opts.image != "" {
+				return fmt.Errorf("--from-manifest-dir does not take DIRECTORY, --from-git or --image")
+			}
+			c, err := registryClient(cmd, deps)
+			if err != nil {
+				return err
+			}
+			annotate := os.Getenv("GITHUB_ACTIONS") == "true"
+			return runPublishBatch(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), c, opts.manifestDir, opts.dryRun, annotate, opts.skipReadmeFetch)
+		},
+	}
+	cmd.Flags().BoolVar(&opts.fromGit, "from-git", false, "Derive name and version from the git remote and the vX.Y.Z tag on HEAD")
+	cmd.Flags().StringVar(&opts.remote, "remote", "origin", "Git remote the name is derived from (with --from-git)")
+	cmd.Flags().BoolVar(&opts.requireSignedTag, "require-signed-tag", true, "Require the release tag to be signed (with --from-git)")
+	cmd.Flags().StringVar(&opts.image, "image", "", "Docker image override (default: from spec.source.image)")
+	cmd.Flags().StringVar(&opts.platform, "platform", "", "Target platform (e.g. linux/amd64, linux/arm64)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the manifest that would be published without building or applying")
+	cmd.Flags().StringVar(&opts.manifestDir, "from-manifest-dir", "", "Publish every manifest found under this directory tree")
+	cmd.Flags().BoolVar(&opts.skipReadmeFetch, "skip-readme-fetch", false, "Keep the registry from fetching MCP server READMEs from GitHub")
+	return cmd
+}
+
+func runPublish(ctx context.Context, out io.Writer, deps cliruntime.Deps, dir string, opts publishOptions) error {
+	projectDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("resolving project directory: %w", err)
+	}
+	if info, err := os.Stat(projectDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("project directory not found: %s", projectDir)
+	}
+	obj, yamlFile, err := findDeclarativeResource(projectDir)
+	if err != nil {
+		return err
+	}
+	agent, ok := obj.(*v1alpha1.Agent)
+	if !ok {
+		return fmt.Errorf("publish supports agents; %s declares a %s — use 'arctl apply -f %s'",
+			yamlFile, obj.GetKind(), yamlFile)
+	}
+
+	image := resolveImage(opts.image, agentSpecImage(agent), agent.Metadata.Name)
+	if agent.Spec.Source == nil {
+		agent.Spec.Source = &v1alpha1.AgentSource{}
+	}
+	if opts.fromGit {
+		release, err := gitutil.ResolveRelease(ctx, projectDir, gitutil.ReleaseOptions{
+			Remote:           opts.remote,
+			RequireSignedTag: opts.requireSignedTag,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "→ release %s %s from %s (%s)\n", release.Name, release.Version, release.Tag, release.Commit[:12])
+		agent.Metadata.Name = release.Name
+		agent.Metadata.Tag = release.Version
+		image = imageWithTag(resolveImage(opts.image, agentSpecImage(agent), release.Name), release.Version)
+		agent.Spec.Source.Repository = &v1alpha1.Repository{
+			URL:       release.RemoteURL,
+			Commit:    release.Commit,
+			Subfolder: release.Subfolder,
+		}
+	}
+	agent.Spec.Source.Image = image
+	// The image is published for exactly the platforms it's built for,
+	// which lets local runtimes pick a matching one.
+	if opts.platform != "" {
+		agent.Spec.Source.Platforms = strings.Split(opts.platform, ",")
+	}
+	if opts.skipReadmeFetch {
+		if agent.Metadata.Annotations == nil {
+			agent.Metadata.Annotations = map[string]string{}
+		}
+		agent.Metadata.Annotations[v1alpha1.SkipReadmeFetchAnnotation] = "true"
+	}
+
+	// The server fills in the default namespace on apply.
+	check := *agent
+	if check.Metadata.Namespace == "" {
+		check.Metadata.Namespace = v1alpha1.DefaultNamespace
+	}
+	if err := check.Validate(); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(agent)
+	if err != nil {
+		return fmt.Errorf("encode Agent: %w", err)
+	}
+	readme, err := readProjectReadme(projectDir)
+	if err != nil {
+		return err
+	}
+	if opts.dryRun {
+		_, err := out.Write(data)
+		return err
+	}
+
+	if deps.Runtime == nil {
+		return fmt.Errorf("registry runtime not configured")
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	if _, err := buildViaFramework(out, projectDir, agent, image, opts.platform, true); err != nil {
+		return err
+	}
+	results, err := c.Apply(ctx, data, client.ApplyOpts{})
+	if err != nil {
+		return err
+	}
+	printResults(out, results, false)
+	for _, r := range results {
+		if r.Status == arv0.ApplyStatusFailed {
+			return fmt.Errorf("failed to publish agent %q", agent.Metadata.Name)
+		}
+	}
+	if readme == nil {
+		return nil
+	}
+	return uploadReadme(ctx, out, c, agent, readme)
+}
+
+// readProjectReadme returns the project's README.md, or nil when it has
+// none. It is read before building so an oversized README fails the
+// publish early.
+func readProjectReadme(projectDir string) ([]byte, error) {
+	readme, err := os.ReadFile(filepath.Join(projectDir, "README.md"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading README.md: %w", err)
+	}
+	if len(readme) > v1alpha1.MaxReadmeLength {
+		return nil, fmt.Errorf("README.md is %d bytes; the registry accepts at most %d", len(readme), v1alpha1.MaxReadmeLength)
+	}
+	return readme, nil
+}
+
+// uploadReadme stores readme as the README of the agent tag just published.
+func uploadReadme(ctx context.Context, out io.Writer, c *client.Client, agent *v1alpha1.Agent, readme []byte) error {
+	tag := printer.EmptyValueOrDefault(agent.Metadata.Tag, "latest")
+	if err := c.PutReadme(ctx, v1alpha1.KindAgent, agent.Metadata.NamespaceOrDefault(), agent.Metadata.Name, tag, readme); err != nil {
+		return fmt.Errorf("uploading README.md: %w", err)
+	}
+	fmt.Fprintf(out, "→ uploaded README.md for %s:%s\n", agent.Metadata.Name, tag)
+	return nil
+}
+
+// imageWithTag replaces image's tag (and any digest) with tag, keeping its
+// repository: ghcr.io/acme/bot:latest becomes ghcr.io/acme/bot:1.2.3.
+func imageWithTag(image, tag string) string {
+	repo, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	return repo + ":" + tag
+}
@@ -0,0 +1,282 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/scheme"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/mcpregistry"
+	"github.com/agentregistry-dev/agentregistry/pkg/printer"
+)
+
+// manifestFiles are the file names --from-manifest-dir collects. server.json
+// is the upstream MCP Registry format and is translated to an MCPServer.
+var manifestFiles = []string{"server.json", "mcp.yaml", "skill.yaml", "prompt.yaml", "agent.yaml"}
+
+// publishOrder ranks the kinds batch publish accepts. Agents reference MCP
+// servers, skills and prompts, so those are published first.
+var publishOrder = map[string]int{
+	v1alpha1.KindMCPServer: 0,
+	v1alpha1.KindSkill:     1,
+	v1alpha1.KindPrompt:    2,
+	v1alpha1.KindAgent:     3,
+}
+
+// Batch publish statuses besides the apply statuses the server reports.
+const (
+	publishStatusInvalid = "invalid"
+	publishStatusExists  = "exists"
+)
+
+// manifestItem is one resource found under the manifest directory.
+type manifestItem struct {
+	file    string
+	obj     v1alpha1.Object
+	status  string
+	message string
+}
+
+func (m *manifestItem) failed() bool {
+	return m.status == publishStatusInvalid || m.status == arv0.ApplyStatusFailed
+}
+
+// runPublishBatch publishes every manifest under root: it validates them all,
+// skips tags the registry already has and applies the rest in dependency
+// order, one resource per request so a failure only costs that resource.
+// Images are not built; agents must reference images that are already
+// pushed. skipReadmeFetch annotates every MCP server so the registry does
+// not fill in its README from GitHub. It returns an error when any manifest
+// was invalid or failed, after the summary is printed.
+func runPublishBatch(ctx context.Context, out, errOut io.Writer, c *client.Client, root string, dryRun, annotate, skipReadmeFetch bool) error {
+	items, err := collectManifests(root)
+	if err != nil {
+		return err
+	}
+	if skipReadmeFetch {
+		for _, item := range items {
+			if server, ok := item.obj.(*v1alpha1.MCPServer); ok {
+				if server.Metadata.Annotations == nil {
+					server.Metadata.Annotations = map[string]string{}
+				}
+				server.Metadata.Annotations[v1alpha1.SkipReadmeFetchAnnotation] = "true"
+			}
+		}
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("no manifests found under %s (looked for %s)", root, strings.Join(manifestFiles, ", "))
+	}
+	seen := make(map[string]string, len(items))
+	for _, item := range items {
+		if item.status != "" {
+			continue
+		}
+		meta := item.obj.GetMetadata()
+		key := strings.Join([]string{item.obj.GetKind(), meta.NamespaceOrDefault(), meta.Name, meta.Tag}, "/")
+		if first, ok := seen[key]; ok {
+			item.status, item.message = publishStatusInvalid, "duplicate of "+first
+			continue
+		}
+		seen[key] = item.file
+		if meta.Tag == "" {
+			// An untagged manifest is the mutable "latest" tag; apply
+			// reports whether it changed.
+			continue
+		}
+		_, err := c.Get(ctx, item.obj.GetKind(), meta.NamespaceOrDefault(), meta.Name, meta.Tag)
+		switch {
+		case err == nil:
+			item.status, item.message = publishStatusExists, "tag already published"
+		case !errors.Is(err, client.ErrNotFound):
+			item.status, item.message = arv0.ApplyStatusFailed, err.Error()
+		}
+	}
+
+	for _, item := range items {
+		if item.status != "" {
+			continue
+		}
+		data, err := yaml.Marshal(item.obj)
+		if err != nil {
+			item.status, item.message = arv0.ApplyStatusFailed, err.Error()
+			continue
+		}
+		results, err := c.Apply(ctx, data, client.ApplyOpts{DryRun: dryRun})
+		switch {
+		case err != nil:
+			item.status, item.message = arv0.ApplyStatusFailed, err.Error()
+		case len(results) != 1:
+			item.status, item.message = arv0.ApplyStatusFailed, fmt.Sprintf("registry returned %d results", len(results))
+		default:
+			item.status, item.message = results[0].Status, results[0].Error
+		}
+	}
+
+	var published, skipped, failed int
+	t := printer.NewTablePrinter(out)
+	t.SetHeaders("FILE", "KIND", "NAME", "TAG", "STATUS", "MESSAGE")
+	for _, item := range items {
+		kind, name, tag := "-", "-", "-"
+		if item.obj != nil {
+			meta := item.obj.GetMetadata()
+			kind, name, tag = item.obj.GetKind(), meta.Name, printer.EmptyValueOrDefault(meta.Tag, "latest")
+		}
+		t.AddRow(item.file, kind, name, tag, item.status, printer.EmptyValueOrDefault(item.message, "-"))
+		switch {
+		case item.failed():
+			failed++
+			if annotate {
+				fmt.Fprintf(errOut, "::error file=%s::%s\n", filepath.Join(root, item.file), item.message)
+			}
+		case item.status == publishStatusExists || item.status == arv0.ApplyStatusUnchanged:
+			skipped++
+		default:
+			published++
+		}
+	}
+	if err := t.Render(); err != nil {
+		return err
+	}
+	suffix := ""
+	if dryRun {
+		suffix = " (dry run)"
+	}
+	fmt.Fprintf(out, "\n%d published, %d skipped, %d failed%s\n", published, skipped, failed, suffix)
+	printDocSuggestions(out, items)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d manifests failed to publish", failed, len(items))
+	}
+	return nil
+}
+
+// printDocSuggestions lists how each MCP server that was published could
+// raise its documentation score, as the registry computes it on publish.
+func printDocSuggestions(out io.Writer, items []*manifestItem) {
+	header := false
+	for _, item := range items {
+		server, ok := item.obj.(*v1alpha1.MCPServer)
+		if !ok || item.failed() || item.status == publishStatusExists || item.status == arv0.ApplyStatusUnchanged {
+			continue
+		}
+		score := v1alpha1.ScoreMCPServerDocs(&server.Spec)
+		if len(score.Suggestions) == 0 {
+			continue
+		}
+		if !header {
+			fmt.Fprintln(out, "\nDocumentation suggestions:")
+			header = true
+		}
+		fmt.Fprintf(out, "  %s (%s): %d/100\n", server.Metadata.Name, item.file, score.Score)
+		for _, suggestion := range score.Suggestions {
+			fmt.Fprintf(out, "    - %s\n", suggestion)
+		}
+	}
+}
+
+// collectManifests walks root for manifestFiles, skipping hidden directories,
+// node_modules and vendor, and returns one validated item per resource in
+// publish order. An MCP server without spec.readme takes the README.md next
+// to its manifest. Files that fail to parse or validate come back as invalid
+// items so they show up in the summary.
+func collectManifests(root string) ([]*manifestItem, error) {
+	var items []*manifestItem
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules" || d.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !slices.Contains(manifestFiles, d.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		objs, err := decodeManifest(path)
+		if err != nil {
+			items = append(items, &manifestItem{file: rel, status: publishStatusInvalid, message: err.Error()})
+			return nil
+		}
+		for _, obj := range objs {
+			if server, ok := obj.(*v1alpha1.MCPServer); ok && server.Spec.Readme == "" {
+				readme, err := os.ReadFile(filepath.Join(filepath.Dir(path), "README.md"))
+				if err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return err
+				}
+				server.Spec.Readme = string(readme)
+			}
+			item := &manifestItem{file: rel, obj: obj}
+			if err := validateManifest(obj); err != nil {
+				item.status, item.message = publishStatusInvalid, err.Error()
+			}
+			items = append(items, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %w", root, err)
+	}
+	slices.SortStableFunc(items, func(a, b *manifestItem) int {
+		return manifestRank(a) - manifestRank(b)
+	})
+	return items, nil
+}
+
+func decodeManifest(path string) ([]v1alpha1.Object, error) {
+	if filepath.Base(path) != "server.json" {
+		return scheme.DecodeFile(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	server, err := mcpregistry.ParseServerJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return []v1alpha1.Object{server}, nil
+}
+
+// validateManifest runs the structural validation apply would, with the
+// namespace the server fills in.
+func validateManifest(obj v1alpha1.Object) error {
+	if _, ok := publishOrder[obj.GetKind()]; !ok {
+		return fmt.Errorf("publish supports MCPServer, Skill, Prompt and Agent; use 'arctl apply' for %s", obj.GetKind())
+	}
+	meta := obj.GetMetadata()
+	if meta.Namespace == "" {
+		meta.Namespace = v1alpha1.DefaultNamespace
+		defer func() { meta.Namespace = "" }()
+	}
+	if v, ok := obj.(v1alpha1.StructuralValidator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// manifestRank orders items by kind; unreadable files sort last.
+func manifestRank(item *manifestItem) int {
+	if item.obj == nil {
+		return len(publishOrder)
+	}
+	if rank, ok := publishOrder[item.obj.GetKind()]; ok {
+		return rank
+	}
+	return len(publishOrder)
+}
@@ -0,0 +1,144 @@
+package declarative
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/scheme"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+func writeManifest(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestRunPublishBatch(t *testing.T) {
+	root := t.TempDir()
+	writeManifest(t, root, "agents/bot/agent.yaml", `
+apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  name: bot
+  tag: 2.0.0
+spec:
+  description: bot
+  source:
+    image: ghcr.io/acme/bot:2.0.0
+`)
+	writeManifest(t, root, "servers/weather/server.json", `{
+  "name": "default/weather",
+  "description": "Weather lookups",
+  "version": "1.2.0",
+  "packages": [{"registryType": "npm", "identifier": "@acme/weather", "version": "1.2.0", "transport": {"type": "stdio"}}]
+}`)
+	writeManifest(t, root, "servers/weather/README.md", "# Weather\n\nForecasts for any city.\n")
+	writeManifest(t, root, "skills/summarize/skill.yaml", `
+apiVersion: ar.dev/v1alpha1
+kind: Skill
+metadata:
+  name: summarize
+  tag: 1.0.0
+spec:
+  title: Summarize
+`)
+	writeManifest(t, root, "prompts/broken/prompt.yaml", `
+apiVersion: ar.dev/v1alpha1
+kind: Prompt
+metadata:
+  tag: 1.0.0
+spec: {}
+`)
+	writeManifest(t, root, ".git/agent.yaml", "not: a manifest")
+
+	var applied []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v0/skills/summarize/1.0.0":
+			_, _ = w.Write([]byte(`{"apiVersion":"ar.dev/v1alpha1","kind":"Skill","metadata":{"name":"summarize","tag":"1.0.0"},"spec":{}}`))
+		case "POST /v0/apply":
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			objs, err := scheme.DecodeBytes(body)
+			require.NoError(t, err)
+			require.Len(t, objs, 1)
+			meta := objs[0].GetMetadata()
+			applied = append(applied, objs[0].GetKind()+"/"+meta.Namespace+"/"+meta.Name)
+			if server, ok := objs[0].(*v1alpha1.MCPServer); ok {
+				require.Contains(t, server.Spec.Readme, "Forecasts for any city.")
+			}
+			require.Equal(t, "true", r.URL.Query().Get("dryRun"))
+			_ = json.NewEncoder(w).Encode(arv0.ApplyResultsResponse{Results: []arv0.ApplyResult{{
+				Kind: objs[0].GetKind(), Name: meta.Name, Tag: meta.Tag, Status: arv0.ApplyStatusDryRun,
+			}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	var out, errOut bytes.Buffer
+	err := runPublishBatch(t.Context(), &out, &errOut, client.NewClient(srv.URL, ""), root, true, true, false)
+	require.ErrorContains(t, err, "1 of 4 manifests failed")
+	require.Equal(t, []string{"MCPServer/default/weather", "Agent//bot"}, applied)
+
+	got := out.String()
+	require.Contains(t, got, "tag already published")
+	require.Contains(t, got, "2 published, 1 skipped, 1 failed (dry run)")
+	require.Contains(t, got, "weather ("+filepath.Join("servers", "weather", "server.json")+"): 30/100")
+	require.Contains(t, got, "add a usage example to the README in a fenced code block")
+	require.Contains(t, errOut.String(), "::error file="+filepath.Join(root, "prompts/broken/prompt.yaml")+"::")
+}
+
+func TestCollectManifests_Empty(t *testing.T) {
+	items, err := collectManifests(t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
+func TestRunPublishBatch_SkipReadmeFetch(t *testing.T) {
+	root := t.TempDir()
+	writeManifest(t, root, "servers/weather/server.json", `{
+  "name": "default/weather",
+  "description": "Weather lookups",
+  "version": "1.2.0",
+  "repository": {"url": "https://github.com/acme/weather", "source": "github"},
+  "packages": [{"registryType": "npm", "identifier": "@acme/weather", "version": "1.2.0", "transport": {"type": "stdio"}}]
+}`)
+
+	var annotations map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		objs, err := scheme.DecodeBytes(body)
+		require.NoError(t, err)
+		require.Len(t, objs, 1)
+		annotations = objs[0].GetMetadata().Annotations
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(arv0.ApplyResultsResponse{Results: []arv0.ApplyResult{{
+			Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "1.2.0", Status: arv0.ApplyStatusCreated,
+		}}})
+	}))
+	t.Cleanup(srv.Close)
+
+	var out, errOut bytes.Buffer
+	require.NoError(t, runPublishBatch(t.Context(), &out, &errOut, client.NewClient(srv.URL, ""), root, false, false, true), out.String())
+	require.Equal(t, "true", annotations[v1alpha1.SkipReadmeFetchAnnotation])
+}
@@ -0,0 +1,48 @@
+package declarative
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+func TestReadProjectReadme(t *testing.T) {
+	dir := t.TempDir()
+	readme, err := readProjectReadme(dir)
+	require.NoError(t, err)
+	require.Nil(t, readme)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte(strings.Repeat("x", v1alpha1.MaxReadmeLength+1)), 0o644))
+	_, err = readProjectReadme(dir)
+	require.ErrorContains(t, err, "the registry accepts at most")
+}
+
+func TestUploadReadme(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "PUT /v0/agents/bot/latest/readme", r.Method+" "+r.URL.Path)
+		require.Equal(t, "team-a", r.URL.Query().Get("namespace"))
+		require.Equal(t, "text/markdown", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		got = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	agent := &v1alpha1.Agent{Metadata: v1alpha1.ObjectMeta{Namespace: "team-a", Name: "bot"}}
+	var out bytes.Buffer
+	require.NoError(t, uploadReadme(t.Context(), &out, client.NewClient(srv.URL, ""), agent, []byte("# Bot\n")))
+	require.Equal(t, "# Bot\n", got)
+	require.Contains(t, out.String(), "uploaded README.md for bot:latest")
+}
@@ -0,0 +1,147 @@
+package declarative_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// initPublishRepo creates a git repository holding an agent project in
+// agents/weather, tagged v1.2.3.
+func initPublishRepo(t *testing.T) (repo, project string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo = t.TempDir()
+	project = filepath.Join(repo, "agents", "weather")
+	writeBuildYAML(t, project, "agent.yaml", `
+apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  name: placeholder
+  tag: dev
+spec:
+  description: weather agent
+  source:
+    image: ghcr.io/acme/weather:latest
+`)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"commit", "-q", "-m", "initial"},
+		{"remote", "add", "origin", "https://github.com/acme/weather-agent.git"},
+		{"tag", "-a", "v1.2.3", "-m", "release 1.2.3"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=ci", "-c", "user.email=ci@example.com",
+			"-c", "commit.gpgSign=false", "-c", "tag.gpgSign=false"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, out)
+	}
+	return repo, project
+}
+
+func TestPublishCmd_FromGitDryRun(t *testing.T) {
+	_, project := initPublishRepo(t)
+
+	var out bytes.Buffer
+	cmd := declarative.NewPublishCmd(declarativeTestDeps(nil))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{project, "--from-git", "--require-signed-tag=false", "--dry-run"})
+	require.NoError(t, cmd.Execute())
+
+	got := out.String()
+	assert.Contains(t, got, "→ release weather-agent 1.2.3 from v1.2.3")
+	assert.Contains(t, got, "name: weather-agent")
+	assert.Contains(t, got, "tag: 1.2.3")
+	assert.Contains(t, got, "image: ghcr.io/acme/weather:1.2.3")
+	assert.Contains(t, got, "url: https://github.com/acme/weather-agent.git")
+	assert.Contains(t, got, "subfolder: agents/weather")
+}
+
+func TestPublishCmd_RecordsPlatforms(t *testing.T) {
+	project := t.TempDir()
+	writeBuildYAML(t, project, "agent.yaml", `
+apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  name: weather
+  tag: 1.0.0
+spec:
+  source:
+    image: ghcr.io/acme/weather:1.0.0
+`)
+
+	var out bytes.Buffer
+	cmd := declarative.NewPublishCmd(declarativeTestDeps(nil))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{project, "--platform", "linux/amd64,linux/arm64", "--dry-run"})
+	require.NoError(t, cmd.Execute())
+	got := out.String()
+	assert.Contains(t, got, "platforms:")
+	assert.Contains(t, got, "- linux/amd64")
+	assert.Contains(t, got, "- linux/arm64")
+}
+
+func TestPublishCmd_FromGitRequiresSignedTag(t *testing.T) {
+	_, project := initPublishRepo(t)
+
+	cmd := declarative.NewPublishCmd(declarativeTestDeps(nil))
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{project, "--from-git", "--dry-run"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "release tag is not signed")
+}
+
+func TestPublishCmd_FromGitRequiresCleanTree(t *testing.T) {
+	repo, project := initPublishRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "NOTES.md"), []byte("wip"), 0o644))
+
+	cmd := declarative.NewPublishCmd(declarativeTestDeps(nil))
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{project, "--from-git", "--require-signed-tag=false", "--dry-run"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "work tree is not clean")
+}
+
+func TestPublishCmd_RejectsNonAgent(t *testing.T) {
+	project := t.TempDir()
+	writeBuildYAML(t, project, "skill.yaml", "apiVersion: ar.dev/v1alpha1\nkind: Skill\nmetadata:\n  name: s\n")
+
+	cmd := declarative.NewPublishCmd(declarativeTestDeps(nil))
+	cmd.SetArgs([]string{project, "--dry-run"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "publish supports agents")
+}
+
+func TestPublishCmd_SkipReadmeFetch(t *testing.T) {
+	project := t.TempDir()
+	writeBuildYAML(t, project, "agent.yaml", `
+apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  name: weather
+  tag: 1.0.0
+spec:
+  source:
+    image: ghcr.io/acme/weather:1.0.0
+`)
+
+	var out bytes.Buffer
+	cmd := declarative.NewPublishCmd(declarativeTestDeps(nil))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{project, "--skip-readme-fetch", "--dry-run"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), v1alpha1.SkipReadmeFetchAnnotation+`: "true"`)
+}
@@ -3,6 +3,7 @@ package declarative
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -12,10 +13,14 @@ import (
 	"github.com/agentregistry-dev/agentregistry/internal/client"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+	"github.com/agentregistry-dev/agentregistry/pkg/ociartifact"
 )
 
 func NewPullCmd(deps cliruntime.Deps) *cobra.Command {
-	var tag string
+	var (
+		tag    string
+		bundle bool
+	)
 	cmd := &cobra.Command{
 		Use:   cliruntime.CommandPull + " TYPE NAME [DIRECTORY]",
 		Short: "Fetch a registry resource's source repo to local",
@@ -23,15 +28,26 @@ func NewPullCmd(deps cliruntime.Deps) *cobra.Command {
 
 Supported types: agent, mcp, skill. Reads the resource's
 Spec.Source.Repository.URL from the registry and clones it into DIRECTORY
-(defaults to NAME if omitted).`,
+(defaults to NAME if omitted).
+
+With --bundle (mcp only), downloads the version's metadata — manifest,
+README and server.json card — as an OCI artifact in an OCI image layout
+tarball written to DIRECTORY (defaults to the current directory). Push it
+to any OCI registry with ORAS:
+
+  oras copy --from-oci-layout myserver-latest.tar:latest ghcr.io/acme/myserver-meta:latest`,
 		Example: `  arctl pull agent myagent
   arctl pull mcp myserver ./vendor/myserver
-  arctl pull skill myskill --tag stable`,
+  arctl pull skill myskill --tag stable
+  arctl pull mcp myserver --bundle --tag 1.2.0`,
 		SilenceUsage: true,
 		Args:         cobra.RangeArgs(2, 3),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			typ, name := args[0], args[1]
 			outDir := name
+			if bundle {
+				outDir = "."
+			}
 			if len(args) == 3 {
 				outDir = args[2]
 			}
@@ -39,14 +55,47 @@ Spec.Source.Repository.URL from the registry and clones it into DIRECTORY
 			if err != nil {
 				return err
 			}
-			return pullResource(cmd.Context(), deps, typ, name, tag, abs)
+			if bundle {
+				return pullBundle(cmd.Context(), cmd.OutOrStdout(), deps, typ, name, tag, abs)
+			}
+			return pullResource(cmd.Context(), cmd.OutOrStdout(), deps, typ, name, tag, abs)
 		},
 	}
 	cmd.Flags().StringVar(&tag, "tag", "", "Specific tag to pull")
+	cmd.Flags().BoolVar(&bundle, "bundle", false, "Download the version's metadata as an OCI artifact tarball instead of cloning its source")
 	return cmd
 }
 
-func pullResource(ctx context.Context, deps cliruntime.Deps, typ, name, tag, outDir string) error {
+func pullBundle(ctx context.Context, out io.Writer, deps cliruntime.Deps, typ, name, tag, outDir string) error {
+	if typ != "mcp" {
+		return fmt.Errorf("--bundle is only supported for type mcp, got %q", typ)
+	}
+	if tag == "" {
+		tag = "latest"
+	}
+	if deps.Runtime == nil {
+		return fmt.Errorf("registry runtime not configured")
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	data, err := c.GetMCPServerBundle(ctx, v1alpha1.DefaultNamespace, name, tag)
+	if err != nil {
+		return fmt.Errorf("fetch mcp %q bundle: %w", name, err)
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(outDir, ociartifact.LayoutFileName(name, tag))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	fmt.Fprintf(out, "Wrote %s (OCI image layout, ref %q)\n", path, tag)
+	return nil
+}
+
+func pullResource(ctx context.Context, out io.Writer, deps cliruntime.Deps, typ, name, tag, outDir string) error {
 	switch typ {
 	case "agent", "mcp", "skill":
 	default:
@@ -100,18 +149,18 @@ func pullResource(ctx context.Context, deps cliruntime.Deps, typ, name, tag, out
 	}
 	switch {
 	case repo.Commit != "":
-		fmt.Printf("Cloning %s @ %s into %s\n", repo.URL, repo.Commit, outDir)
+		fmt.Fprintf(out, "Cloning %s @ %s into %s\n", repo.URL, repo.Commit, outDir)
 	case repo.Branch != "":
-		fmt.Printf("Cloning %s (branch %s) into %s\n", repo.URL, repo.Branch, outDir)
+		fmt.Fprintf(out, "Cloning %s (branch %s) into %s\n", repo.URL, repo.Branch, outDir)
 	default:
-		fmt.Printf("Cloning %s into %s\n", repo.URL, outDir)
+		fmt.Fprintf(out, "Cloning %s into %s\n", repo.URL, outDir)
 	}
 	if err := gitutil.CloneAndCopyContext(ctx, repo.URL, repo.Branch, repo.Commit, repo.Subfolder, outDir, false); err != nil {
 		return err
 	}
 	if repo.Subfolder != "" {
-		fmt.Printf("(subfolder hint: %s)\n", repo.Subfolder)
+		fmt.Fprintf(out, "(subfolder hint: %s)\n", repo.Subfolder)
 	}
-	fmt.Printf("Pulled %s\n", name)
+	fmt.Fprintf(out, "Pulled %s\n", name)
 	return nil
 }
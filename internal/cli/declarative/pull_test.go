@@ -1,11 +1,17 @@
 package declarative_test
 
 import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
 )
 
 func TestPull_RejectsUnknownType(t *testing.T) {
@@ -13,3 +19,32 @@ func TestPull_RejectsUnknownType(t *testing.T) {
 	cmd.SetArgs([]string{"unknown", "foo"})
 	require.Error(t, cmd.Execute())
 }
+
+func TestPull_BundleWritesLayoutTarball(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/vnd.oci.image.layout.v1+tar")
+		_, _ = w.Write([]byte("layout"))
+	}))
+	t.Cleanup(srv.Close)
+
+	out := t.TempDir()
+	var stdout bytes.Buffer
+	cmd := declarative.NewPullCmd(declarativeTestDeps(client.NewClient(srv.URL, "")))
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"mcp", "acme/weather", out, "--bundle", "--tag", "1.0.0"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, "/v0/mcpservers/acme%2Fweather/1.0.0/bundle", gotPath)
+	data, err := os.ReadFile(filepath.Join(out, "acme-weather-1.0.0.tar"))
+	require.NoError(t, err)
+	require.Equal(t, "layout", string(data))
+	require.Contains(t, stdout.String(), "Wrote "+filepath.Join(out, "acme-weather-1.0.0.tar"))
+}
+
+func TestPull_BundleRejectsNonMCP(t *testing.T) {
+	cmd := declarative.NewPullCmd(declarativeTestDeps(nil))
+	cmd.SetArgs([]string{"agent", "foo", t.TempDir(), "--bundle"})
+	require.ErrorContains(t, cmd.Execute(), "only supported for type mcp")
+}
@@ -0,0 +1,121 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+	"github.com/agentregistry-dev/agentregistry/pkg/ociartifact"
+)
+
+// ociApplyPrefix marks an `apply -f` argument as an OCI reference to a
+// metadata artifact written by `arctl push`.
+const ociApplyPrefix = "oci://"
+
+func NewPushCmd(deps cliruntime.Deps) *cobra.Command {
+	var (
+		tag    string
+		ociRef string
+	)
+	cmd := &cobra.Command{
+		Use:   cliruntime.CommandPush + " TYPE NAME --oci-ref REF",
+		Short: "Publish a registry resource's manifest to an OCI registry",
+		Long: `Publish a registered resource's manifest as an OCI artifact.
+
+Supported types: agent, mcp, skill. Fetches the resource version from the
+registry and pushes its metadata — manifest, README and, for mcp, the
+server.json card — to REF, so it can be mirrored alongside images in
+registries such as Harbor or ECR. Credentials come from the Docker config,
+the same ones ` + "`docker push`" + ` uses.
+
+Apply a pushed manifest on another registry with:
+
+  arctl apply -f oci://ghcr.io/acme/skills/summarize:1.0.0`,
+		Example: `  arctl push skill summarize --tag 1.0.0 --oci-ref ghcr.io/acme/skills/summarize:1.0.0
+  arctl push agent summarizer --oci-ref harbor.acme.dev/agents/summarizer:latest`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pushResource(cmd.Context(), cmd.OutOrStdout(), deps, args[0], args[1], tag, ociRef)
+		},
+	}
+	cmd.Flags().StringVar(&tag, "tag", "", "Specific tag to push (default: latest)")
+	cmd.Flags().StringVar(&ociRef, "oci-ref", "", "OCI reference to push the manifest artifact to")
+	_ = cmd.MarkFlagRequired("oci-ref")
+	return cmd
+}
+
+func pushResource(ctx context.Context, out io.Writer, deps cliruntime.Deps, typ, name, tag, ociRef string) error {
+	var kind string
+	switch typ {
+	case "agent":
+		kind = v1alpha1.KindAgent
+	case "mcp":
+		kind = v1alpha1.KindMCPServer
+	case "skill":
+		kind = v1alpha1.KindSkill
+	default:
+		return fmt.Errorf("unknown type %q (want one of: agent, mcp, skill)", typ)
+	}
+
+	if deps.Runtime == nil {
+		return fmt.Errorf("registry runtime not configured")
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+
+	var obj v1alpha1.Object
+	switch kind {
+	case v1alpha1.KindAgent:
+		obj, err = client.GetTyped(ctx, c, kind, v1alpha1.DefaultNamespace, name, tag,
+			func() *v1alpha1.Agent { return &v1alpha1.Agent{} })
+	case v1alpha1.KindMCPServer:
+		obj, err = client.GetTyped(ctx, c, kind, v1alpha1.DefaultNamespace, name, tag,
+			func() *v1alpha1.MCPServer { return &v1alpha1.MCPServer{} })
+	case v1alpha1.KindSkill:
+		obj, err = client.GetTyped(ctx, c, kind, v1alpha1.DefaultNamespace, name, tag,
+			func() *v1alpha1.Skill { return &v1alpha1.Skill{} })
+	}
+	if err != nil {
+		return fmt.Errorf("fetch %s %q: %w", typ, name, err)
+	}
+
+	artifact, err := ociartifact.ForObject(obj)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "→ pushing %s %s to %s...\n", typ, name, ociRef)
+	pushed, err := ociartifact.Push(ctx, ociRef, artifact, ociOptions()...)
+	if err != nil {
+		return fmt.Errorf("push %s: %w", ociRef, err)
+	}
+	fmt.Fprintf(out, "✓ Pushed %s\n", pushed)
+	return nil
+}
+
+// readOCIManifest pulls the manifest out of the metadata artifact at ref.
+func readOCIManifest(ctx context.Context, ref string) ([]byte, error) {
+	data, err := ociartifact.PullManifest(ctx, ref, ociOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("pull %s: %w", ref, err)
+	}
+	return data, nil
+}
+
+// ociOptions authenticates with the Docker config, like `docker push`.
+func ociOptions() []remote.Option {
+	return []remote.Option{
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithTransport(httpclient.DefaultTransport()),
+	}
+}
@@ -0,0 +1,53 @@
+package declarative_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/pkg/ociartifact"
+)
+
+func TestPush_PublishesManifestArtifact(t *testing.T) {
+	var gotPath string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"apiVersion":"ar.dev/v1alpha1","kind":"Skill",` +
+			`"metadata":{"namespace":"default","name":"summarize","tag":"1.0.0"},` +
+			`"spec":{"description":"Summarize text"}}`))
+	}))
+	t.Cleanup(api.Close)
+	oci := httptest.NewServer(registry.New())
+	t.Cleanup(oci.Close)
+	ref := strings.TrimPrefix(oci.URL, "http://") + "/acme/skills/summarize:1.0.0"
+
+	cmd := declarative.NewPushCmd(declarativeTestDeps(client.NewClient(api.URL, "")))
+	cmd.SetArgs([]string{"skill", "summarize", "--tag", "1.0.0", "--oci-ref", ref})
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "/v0/skills/summarize/1.0.0", gotPath)
+
+	manifest, err := ociartifact.PullManifest(context.Background(), ref)
+	require.NoError(t, err)
+	require.Contains(t, string(manifest), "kind: Skill")
+	require.Contains(t, string(manifest), "description: Summarize text")
+}
+
+func TestPush_RejectsUnknownType(t *testing.T) {
+	cmd := declarative.NewPushCmd(declarativeTestDeps(nil))
+	cmd.SetArgs([]string{"prompt", "foo", "--oci-ref", "example.com/foo:1"})
+	require.ErrorContains(t, cmd.Execute(), `unknown type "prompt"`)
+}
+
+func TestPush_RequiresOCIRef(t *testing.T) {
+	cmd := declarative.NewPushCmd(declarativeTestDeps(nil))
+	cmd.SetArgs([]string{"skill", "foo"})
+	require.ErrorContains(t, cmd.Execute(), "oci-ref")
+}
@@ -0,0 +1,444 @@
+package declarative
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/scheme"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+// importBatchBytes caps the YAML sent in one POST /v0/apply call. The
+// server rejects request bodies over 1 MiB, and a single document can be up
+// to v1alpha1.MaxSpecBytes, so batches stay at half that.
+const importBatchBytes = 512 << 10
+
+// NewRegistryCmd returns the "registry" command group for whole-registry
+// operations.
+func NewRegistryCmd(deps cliruntime.Deps) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   cliruntime.CommandRegistry,
+		Short: "Export, import, sync, seed, maintain and smoke-test registry contents",
+	}
+	cmd.AddCommand(newRegistryExportCmd(deps))
+	cmd.AddCommand(newRegistryImportCmd(deps))
+	cmd.AddCommand(newRegistrySyncCmd(deps))
+	cmd.AddCommand(newRegistrySeedCmd(deps))
+	cmd.AddCommand(newRegistryAdminCmd(deps))
+	cmd.AddCommand(newRegistrySmokeTestCmd(deps))
+	return cmd
+}
+
+func newRegistryExportCmd(deps cliruntime.Deps) *cobra.Command {
+	var namespace string
+	cmd := &cobra.Command{
+		Use:   "export [FILE]",
+		Short: "Export every MCP server, agent, skill, prompt, plugin and chart",
+		Long: `Export downloads every tag of every MCP server, agent, skill, prompt,
+plugin and chart as one multi-document YAML file, from GET /v0/export.
+Requires registry admin.
+
+The file can be restored into another registry with "arctl registry import".
+Runtimes, deployments and webhooks are not exported. Writes to stdout when
+FILE is omitted.`,
+		Example: `  arctl registry export staging.yaml
+  arctl registry export --namespace team-a > team-a.yaml`,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := registryClient(cmd, deps)
+			if err != nil {
+				return err
+			}
+			data, err := c.Export(cmd.Context(), namespace)
+			if err != nil {
+				return fmt.Errorf("export: %w", err)
+			}
+			if len(args) == 0 {
+				_, err := cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if err := os.WriteFile(args[0], data, 0644); err != nil {
+				return fmt.Errorf("write export: %w", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s\n", args[0])
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&namespace, "namespace", "", "Export only this namespace (default: every namespace)")
+	return cmd
+}
+
+func newRegistryImportCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts importOpts
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import a file written by arctl registry export",
+		Long: `Import applies every document in FILE (or stdin with -) through
+POST /v0/apply, in batches small enough for the server's request size limit.
+Documents of one kind are applied by --parallel workers at once; kinds are
+applied one after another in file order, so agents still come after the
+servers, skills and prompts they reference. Existing tags with identical
+content are left unchanged.
+
+A batch the server cannot be reached for is retried with exponential
+backoff; after --retries attempts the import stops. Progress is checkpointed
+to a state file (FILE.import-state by default), and --resume skips the
+documents it records as imported. The state file is removed once every
+document has been imported.
+
+Per-resource errors are reported without aborting the import.`,
+		Example: `  arctl registry import staging.yaml --dry-run
+  arctl registry import staging.yaml --parallel 8
+  arctl registry import staging.yaml --resume`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				data []byte
+				err  error
+			)
+			if args[0] == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("read %s: %w", args[0], err)
+			}
+			if opts.StatePath == "" && args[0] != "-" {
+				opts.StatePath = args[0] + ".import-state"
+			}
+			if opts.Resume && opts.StatePath == "" {
+				return fmt.Errorf("--resume with stdin needs --state")
+			}
+			return runRegistryImport(cmd, deps, data, opts)
+		},
+	}
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Validate and simulate without mutating state")
+	cmd.Flags().IntVar(&opts.Parallel, "parallel", 4, "Number of batches to apply at once")
+	cmd.Flags().IntVar(&opts.Retries, "retries", 5, "Attempts per batch before the import stops")
+	cmd.Flags().StringVar(&opts.StatePath, "state", "", "Checkpoint file (default: FILE.import-state; none for stdin)")
+	cmd.Flags().BoolVar(&opts.Resume, "resume", false, "Skip documents the state file records as imported")
+	return cmd
+}
+
+// importOpts configures runRegistryImport. The zero value applies one
+// batch at a time, once, without checkpointing.
+type importOpts struct {
+	DryRun bool
+	// Parallel is the number of batches of one kind applied at once.
+	Parallel int
+	// Retries is the number of attempts per batch.
+	Retries int
+	// StatePath is the checkpoint file; empty disables checkpointing.
+	StatePath string
+	// Resume skips the documents StatePath records as imported.
+	Resume bool
+}
+
+// importBackoff is the wait before retry attempt n (1-based) of a batch.
+// A package var so tests don't sleep.
+var importBackoff = func(attempt int) time.Duration {
+	return min(time.Second<<(attempt-1), 30*time.Second)
+}
+
+func runRegistryImport(cmd *cobra.Command, deps cliruntime.Deps, data []byte, opts importOpts) error {
+	// Fail on unknown kinds or malformed documents before sending anything.
+	if _, err := scheme.DecodeBytes(data); err != nil {
+		return err
+	}
+
+	var state *importState
+	if opts.StatePath != "" && !opts.DryRun {
+		var err error
+		if state, err = openImportState(opts.StatePath, data, opts.Resume); err != nil {
+			return err
+		}
+		if n := len(state.Done); n > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Resuming: %d resources already imported\n", n)
+		}
+	}
+	batches, err := importBatches(data, importBatchBytes, state.done())
+	if err != nil {
+		return err
+	}
+	c, err := registryClient(cmd, deps)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	var (
+		mu              sync.Mutex
+		out             = cmd.OutOrStdout()
+		applied, failed int
+		stopErr         error
+	)
+	apply := func(b importBatch) {
+		results, err := applyImportBatch(ctx, c, b.Data, opts)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if stopErr == nil && ctx.Err() == nil {
+				stopErr = err
+			}
+			cancel()
+			return
+		}
+		printResults(out, results, opts.DryRun)
+		var done []int
+		for i, r := range results {
+			if r.Status == arv0.ApplyStatusFailed {
+				failed++
+				continue
+			}
+			applied++
+			// Results come back in document order; only a complete
+			// answer can be matched to the documents it covers.
+			if len(results) == len(b.Docs) {
+				done = append(done, b.Docs[i])
+			}
+		}
+		if state != nil && len(done) > 0 {
+			if err := state.record(done); err != nil && stopErr == nil {
+				stopErr = err
+				cancel()
+			}
+		}
+	}
+
+	workers := max(opts.Parallel, 1)
+	for _, phase := range importPhases(batches) {
+		queue := make(chan importBatch)
+		var wg sync.WaitGroup
+		for range min(workers, len(phase)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for b := range queue {
+					apply(b)
+				}
+			}()
+		}
+	send:
+		for _, b := range phase {
+			select {
+			case queue <- b:
+			case <-ctx.Done():
+				break send
+			}
+		}
+		close(queue)
+		wg.Wait()
+		if stopErr != nil || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if stopErr != nil {
+		if state != nil {
+			return fmt.Errorf("import stopped after %d resources: %w (re-run with --resume to continue)", applied+failed, stopErr)
+		}
+		return fmt.Errorf("import stopped after %d resources: %w", applied+failed, stopErr)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Imported %d resources, %d failed\n", applied, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d resources failed to import", failed)
+	}
+	if state != nil {
+		if err := os.Remove(state.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove import state: %w", err)
+		}
+	}
+	return nil
+}
+
+// applyImportBatch sends one batch, retrying failed requests with
+// importBackoff up to opts.Retries attempts in total.
+func applyImportBatch(ctx context.Context, c *client.Client, batch []byte, opts importOpts) ([]arv0.ApplyResult, error) {
+	attempts := max(opts.Retries, 1)
+	for attempt := 1; ; attempt++ {
+		results, err := c.Apply(ctx, batch, client.ApplyOpts{DryRun: opts.DryRun})
+		if err == nil || attempt == attempts || ctx.Err() != nil {
+			return results, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(importBackoff(attempt)):
+		}
+	}
+}
+
+// importBatch is a YAML stream of documents of one kind. Docs are the
+// indexes of its documents in the import file; Phase counts the kind
+// changes before them.
+type importBatch struct {
+	Data  []byte
+	Docs  []int
+	Phase int
+}
+
+// importBatches splits a multi-document YAML stream into consecutive
+// streams of at most limit bytes each, keeping document order. A batch
+// never spans a change of kind. Documents whose index is in skip are left
+// out. A document larger than limit travels alone.
+func importBatches(data []byte, limit int, skip map[int]bool) ([]importBatch, error) {
+	docs, err := splitYAMLDocs(data)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		batches []importBatch
+		current []*yaml.Node
+		indexes []int
+		size    int
+		phase   = -1
+		kind    string
+	)
+	flush := func() error {
+		if len(current) == 0 {
+			return nil
+		}
+		batch, err := marshalYAMLDocs(current)
+		if err != nil {
+			return err
+		}
+		batches = append(batches, importBatch{Data: batch, Docs: indexes, Phase: phase})
+		current, indexes, size = nil, nil, 0
+		return nil
+	}
+	index := -1
+	for _, doc := range docs {
+		if len(doc.Content) == 0 {
+			continue
+		}
+		index++
+		if k := scalarValue(doc.Content[0], "kind"); phase < 0 || k != kind {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			phase++
+			kind = k
+		}
+		if skip[index] {
+			continue
+		}
+		encoded, err := marshalYAMLDocs([]*yaml.Node{doc})
+		if err != nil {
+			return nil, err
+		}
+		if size > 0 && size+len(encoded) > limit {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+		current = append(current, doc)
+		indexes = append(indexes, index)
+		size += len(encoded)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+// importPhases groups consecutive batches of the same phase.
+func importPhases(batches []importBatch) [][]importBatch {
+	var phases [][]importBatch
+	for i, b := range batches {
+		if i == 0 || b.Phase != batches[i-1].Phase {
+			phases = append(phases, nil)
+		}
+		phases[len(phases)-1] = append(phases[len(phases)-1], b)
+	}
+	return phases
+}
+
+// importState is the checkpoint of an import: the indexes of the
+// documents of Source that have been imported.
+type importState struct {
+	// Source is the sha256 of the import file, so a checkpoint is never
+	// applied to a different file.
+	Source string `json:"source"`
+	Done   []int  `json:"done"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// openImportState starts a checkpoint for data at path. With resume, the
+// checkpoint already at path is continued; it must have been written for
+// the same data. A missing file starts from scratch.
+func openImportState(path string, data []byte, resume bool) (*importState, error) {
+	sum := sha256.Sum256(data)
+	state := &importState{Source: hex.EncodeToString(sum[:]), Done: []int{}, path: path}
+	if !resume {
+		return state, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read import state: %w", err)
+	}
+	var saved importState
+	if err := json.Unmarshal(raw, &saved); err != nil {
+		return nil, fmt.Errorf("parse import state %s: %w", path, err)
+	}
+	if saved.Source != state.Source {
+		return nil, fmt.Errorf("import state %s was written for a different file", path)
+	}
+	state.Done = append(state.Done, saved.Done...)
+	return state, nil
+}
+
+// done returns the checkpointed document indexes; nil for a nil state.
+func (s *importState) done() map[int]bool {
+	if s == nil {
+		return nil
+	}
+	done := make(map[int]bool, len(s.Done))
+	for _, i := range s.Done {
+		done[i] = true
+	}
+	return done
+}
+
+// record adds imported document indexes and rewrites the state file.
+func (s *importState) record(docs []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Done = append(s.Done, docs...)
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0644); err != nil {
+		return fmt.Errorf("write import state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("write import state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package declarative
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+func newRegistryAdminCmd(deps cliruntime.Deps) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Registry maintenance jobs",
+	}
+	cmd.AddCommand(newReindexEmbeddingsCmd(deps))
+	cmd.AddCommand(newGCCmd(deps))
+	cmd.AddCommand(newSnapshotCmd(deps))
+	return cmd
+}
+
+func newGCCmd(deps cliruntime.Deps) *cobra.Command {
+	var (
+		namespace string
+		dryRun    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Delete artifact versions the retention policy does not keep",
+		Long: `Gc runs the server's version garbage collection, through
+POST /v0/admin/gc, and lists the versions it deleted. For each artifact it
+keeps the newest VERSION_GC_KEEP_VERSIONS versions, every version younger
+than VERSION_GC_KEEP_WITHIN, the "latest" tag, the newest version, and
+every version a Deployment, Agent or Skill pins. The server also runs it
+every VERSION_GC_INTERVAL when that is set.
+
+Deleted versions stay restorable for DELETED_ARTIFACT_RETENTION. Preview
+with --dry-run first. Requires registry admin.`,
+		Example: `  arctl registry admin gc --dry-run
+  arctl registry admin gc --namespace team-a`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := registryClient(cmd, deps)
+			if err != nil {
+				return err
+			}
+			report, err := c.CollectVersions(cmd.Context(), namespace, dryRun)
+			if err != nil {
+				return fmt.Errorf("gc: %w", err)
+			}
+			out := cmd.OutOrStdout()
+			for _, v := range report.Deleted {
+				status := ""
+				if v.Error != "" {
+					status = " failed: " + v.Error
+				}
+				fmt.Fprintf(out, "%s %s/%s %s (published %s)%s\n",
+					v.Kind, v.Namespace, v.Name, v.Tag, v.CreatedAt.Format("2006-01-02"), status)
+			}
+			verb := "Deleted"
+			if report.DryRun {
+				verb = "Would delete"
+			}
+			fmt.Fprintf(out, "%s %d of %d versions (%d kept, %d of them in use; keeping the newest %d and those younger than %s)\n",
+				verb, len(report.Deleted)-report.Failed, report.Scanned, report.Kept, report.InUse, report.KeepVersions, report.KeepWithin)
+			if report.Failed > 0 {
+				return fmt.Errorf("%d deletions failed", report.Failed)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&namespace, "namespace", "", "Collect only this namespace (default: every namespace)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report what would be deleted without deleting it")
+	return cmd
+}
+
+func newReindexEmbeddingsCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts client.ReindexEmbeddingsOpts
+	cmd := &cobra.Command{
+		Use:   "reindex-embeddings",
+		Short: "Embed artifacts whose semantic search embedding is missing or stale",
+		Long: `Reindex-embeddings runs the server's embeddings backfill, through
+POST /v0/admin/embeddings:reindex, and prints its progress. Embeddings are
+generated when an artifact is published, so artifacts published before
+semantic search was enabled, or whose name, title, description or README
+changed since, are missing from it or found by their old text. The backfill
+embeds the latest tag of each of those, at --rate embeddings per second.
+
+Stopping the command stops the backfill; running it again carries on with
+what is left. Requires registry admin, and a registry with semantic search
+enabled.`,
+		Example: `  arctl registry admin reindex-embeddings --dry-run
+  arctl registry admin reindex-embeddings --types server,agent --rate 2`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := registryClient(cmd, deps)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			var done *arv0.EmbeddingsReindexEvent
+			err = c.ReindexEmbeddings(cmd.Context(), opts, func(event arv0.EmbeddingsReindexEvent) error {
+				switch event.Type {
+				case arv0.EmbeddingsReindexPlanned:
+					verb := "Embedding"
+					if event.DryRun {
+						verb = "Would embed"
+					}
+					fmt.Fprintf(out, "%s %d of %d artifacts (%d up to date)\n", verb, event.Total, event.Scanned, event.UpToDate)
+				case arv0.EmbeddingsReindexItem:
+					status := "embedded"
+					switch {
+					case event.Error != "":
+						status = "failed: " + event.Error
+					case event.DryRun:
+						status = "would embed"
+					}
+					fmt.Fprintf(out, "[%d/%d] %s %s/%s (%s) %s\n",
+						event.Processed, event.Total, event.Kind, event.Namespace, event.Name, event.Reason, status)
+				case arv0.EmbeddingsReindexDone:
+					done = &event
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("reindex embeddings: %w", err)
+			}
+			if done == nil {
+				return errors.New("reindex embeddings: the server ended the run early; run the command again to continue")
+			}
+			if done.DryRun {
+				fmt.Fprintf(out, "Dry run: %d artifacts would be embedded\n", done.Total)
+				return nil
+			}
+			fmt.Fprintf(out, "Embedded %d artifacts, %d failed\n", done.Processed-done.Failed, done.Failed)
+			if done.Failed > 0 {
+				return fmt.Errorf("%d embeddings failed", done.Failed)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&opts.Types, "types", nil, "Artifact types to reindex: server, agent, skill, prompt (default: all)")
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "Reindex only this namespace (default: every namespace)")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Report what would be embedded without embedding it")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Re-embed artifacts whose embedding is up to date")
+	cmd.Flags().Float64Var(&opts.Rate, "rate", 0, "Embeddings per second (default: the server's, 5)")
+	return cmd
+}
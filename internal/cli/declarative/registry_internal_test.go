@@ -0,0 +1,173 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/scheme"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+func TestImportBatches(t *testing.T) {
+	var docs []string
+	for i := range 5 {
+		docs = append(docs, fmt.Sprintf("apiVersion: ar.dev/v1alpha1\nkind: Skill\nmetadata:\n  name: skill-%d\nspec:\n  title: %s\n",
+			i, strings.Repeat("x", 100)))
+	}
+	data := []byte(strings.Join(docs, "---\n"))
+
+	// Room for two documents per batch.
+	batches, err := importBatches(data, 400, nil)
+	require.NoError(t, err)
+	require.Len(t, batches, 3)
+
+	var names []string
+	for _, batch := range batches {
+		require.LessOrEqual(t, len(batch.Data), 400)
+		objs, err := v1alpha1.Default.DecodeMulti(batch.Data)
+		require.NoError(t, err)
+		for _, obj := range objs {
+			names = append(names, obj.(v1alpha1.Object).GetMetadata().Name)
+		}
+	}
+	require.Equal(t, []string{"skill-0", "skill-1", "skill-2", "skill-3", "skill-4"}, names)
+
+	// A document over the limit still goes out, alone.
+	batches, err = importBatches(data, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, batches, 5)
+}
+
+func TestImportBatches_PhasesAndSkip(t *testing.T) {
+	data := []byte(`apiVersion: ar.dev/v1alpha1
+kind: MCPServer
+metadata:
+  name: weather
+---
+apiVersion: ar.dev/v1alpha1
+kind: MCPServer
+metadata:
+  name: maps
+---
+apiVersion: ar.dev/v1alpha1
+kind: Skill
+metadata:
+  name: summarize
+---
+apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  name: reporter
+`)
+	batches, err := importBatches(data, importBatchBytes, map[int]bool{1: true})
+	require.NoError(t, err)
+	require.Len(t, batches, 3, "a batch never spans a change of kind")
+	require.Equal(t, []int{0}, batches[0].Docs)
+	require.Equal(t, []int{2}, batches[1].Docs)
+	require.Equal(t, []int{3}, batches[2].Docs)
+	require.Equal(t, []int{0, 1, 2}, []int{batches[0].Phase, batches[1].Phase, batches[2].Phase})
+	require.NotContains(t, string(batches[0].Data), "maps")
+
+	batches, err = importBatches(data, importBatchBytes, map[int]bool{0: true, 1: true})
+	require.NoError(t, err)
+	phases := importPhases(batches)
+	require.Len(t, phases, 2, "a fully imported kind leaves no phase")
+	require.Equal(t, 1, phases[0][0].Phase)
+}
+
+func TestImportState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "import-state")
+	data := []byte("kind: Skill\n")
+
+	state, err := openImportState(path, data, true)
+	require.NoError(t, err, "a missing state file starts from scratch")
+	require.Empty(t, state.done())
+	require.NoError(t, state.record([]int{0, 2}))
+
+	resumed, err := openImportState(path, data, true)
+	require.NoError(t, err)
+	require.Equal(t, map[int]bool{0: true, 2: true}, resumed.done())
+
+	fresh, err := openImportState(path, data, false)
+	require.NoError(t, err)
+	require.Empty(t, fresh.done(), "without resume the checkpoint is ignored")
+
+	_, err = openImportState(path, []byte("kind: Agent\n"), true)
+	require.ErrorContains(t, err, "different file")
+}
+
+// TestSeedProfiles checks every embedded profile is a valid import stream
+// whose refs resolve within the profile, in apply order.
+func TestSeedProfiles(t *testing.T) {
+	profiles, err := listSeedProfiles()
+	require.NoError(t, err)
+	require.NotEmpty(t, profiles)
+
+	for _, p := range profiles {
+		t.Run(p.Name, func(t *testing.T) {
+			require.NotEmpty(t, p.Description)
+			objs, err := scheme.DecodeBytes(p.Data)
+			require.NoError(t, err)
+			require.NotEmpty(t, objs)
+
+			seen := map[string]bool{}
+			for _, obj := range objs {
+				meta := obj.GetMetadata()
+				require.Equal(t, p.Name, meta.Namespace, "%s %s", obj.GetKind(), meta.Name)
+				v, ok := obj.(v1alpha1.StructuralValidator)
+				require.True(t, ok, "%s has no validator", obj.GetKind())
+				require.NoError(t, v.Validate(), "%s %s", obj.GetKind(), meta.Name)
+				if agent, ok := obj.(*v1alpha1.Agent); ok {
+					for _, ref := range agent.Spec.MCPServers {
+						require.True(t, seen[v1alpha1.KindMCPServer+"/"+ref.Name+"/"+ref.Tag],
+							"agent %s refers to MCPServer %s %s before it is seeded", meta.Name, ref.Name, ref.Tag)
+					}
+				}
+				seen[obj.GetKind()+"/"+meta.Name+"/"+meta.Tag] = true
+			}
+		})
+	}
+}
+
+func TestApplyImportBatch_Retries(t *testing.T) {
+	original := importBackoff
+	t.Cleanup(func() { importBackoff = original })
+	var waits []time.Duration
+	importBackoff = func(attempt int) time.Duration {
+		waits = append(waits, original(attempt))
+		return 0
+	}
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[{"kind":"Skill","name":"summarize","status":"created"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := client.NewClient(srv.URL, "")
+
+	results, err := applyImportBatch(context.Background(), c, []byte("kind: Skill\n"), importOpts{Retries: 3})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, 3, calls)
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second}, waits, "backoff doubles per attempt")
+
+	calls = 0
+	_, err = applyImportBatch(context.Background(), c, []byte("kind: Skill\n"), importOpts{Retries: 2})
+	require.ErrorContains(t, err, "503")
+	require.Equal(t, 2, calls)
+}
@@ -0,0 +1,415 @@
+package declarative
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+
+	"github.com/agentregistry-dev/agentregistry/internal/a2a"
+	cliCommon "github.com/agentregistry-dev/agentregistry/internal/cli/common"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+// smokeTag is the tag of every artifact a smoke test publishes.
+const smokeTag = "1.0.0"
+
+// smokeChatMessage is the one turn the smoke test sends the deployed agent.
+const smokeChatMessage = "This is a registry smoke test. Reply with a short greeting."
+
+// Smoke test step outcomes, as reported.
+const (
+	smokePass = "pass"
+	smokeFail = "fail"
+	smokeSkip = "skip"
+)
+
+type smokeTestOptions struct {
+	namespace  string
+	runtime    string
+	agentImage string
+	skipChat   bool
+	timeout    time.Duration
+	output     string
+}
+
+// smokeStep is the outcome of one smoke test step.
+type smokeStep struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// smokeReport is what `arctl registry smoke-test` prints.
+type smokeReport struct {
+	Registry string      `json:"registry"`
+	Name     string      `json:"name"`
+	Passed   bool        `json:"passed"`
+	Steps    []smokeStep `json:"steps"`
+}
+
+func newRegistrySmokeTestCmd(deps cliruntime.Deps) *cobra.Command {
+	var opts smokeTestOptions
+	cmd := &cobra.Command{
+		Use:   "smoke-test",
+		Short: "Run an end-to-end check against a registry and report pass/fail",
+		Long: `Smoke-test exercises a registry the way its users do, with temporary
+artifacts that share one generated name (arctl-smoke-<random>):
+
+  publish    apply an MCP server, a skill and an agent
+  search     find all three through GET /v0/search
+  deploy     deploy the agent on --runtime and wait until it is ready
+  chat       send the agent one A2A message through its agent card
+  undeploy   delete the deployment and wait until it is gone
+  cleanup    delete the published artifacts
+
+Without --runtime the deploy, chat and undeploy steps are skipped. The
+deployed agent runs --agent-image, which must answer A2A messages. Cleanup
+runs even when a step fails or the run is interrupted.
+
+Prints a report of every step and exits non-zero when any failed, so it can
+gate an upgrade rollout.`,
+		Example: `  arctl registry smoke-test
+  arctl registry smoke-test --runtime local --agent-image ghcr.io/acme/echo-agent:1.0.0
+  arctl registry smoke-test --runtime prod-cluster --agent-image ghcr.io/acme/echo-agent:1.0.0 --skip-chat -o json`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if opts.runtime != "" && opts.agentImage == "" {
+				return errors.New("--agent-image is required with --runtime")
+			}
+			if opts.output != "table" && opts.output != "json" {
+				return fmt.Errorf("invalid --output %q (want table or json)", opts.output)
+			}
+			c, err := registryClient(cmd, deps)
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			report, err := runRegistrySmokeTest(ctx, c, opts, cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			if err := printSmokeReport(cmd.OutOrStdout(), report, opts.output); err != nil {
+				return err
+			}
+			if !report.Passed {
+				failed := 0
+				for _, step := range report.Steps {
+					if step.Status == smokeFail {
+						failed++
+					}
+				}
+				return fmt.Errorf("smoke test failed: %d of %d steps failed", failed, len(report.Steps))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&opts.namespace, "namespace", v1alpha1.DefaultNamespace, "Namespace to publish and deploy the temporary artifacts in")
+	cmd.Flags().StringVar(&opts.runtime, "runtime", "", "Runtime to deploy the temporary agent on; empty skips deploy, chat and undeploy")
+	cmd.Flags().StringVar(&opts.agentImage, "agent-image", "", "A2A agent image the temporary agent runs (required with --runtime)")
+	cmd.Flags().BoolVar(&opts.skipChat, "skip-chat", false, "Deploy the agent without sending it a message")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", cliCommon.DefaultWaitTimeout, "Maximum time each deployment wait and the chat turn may take")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table, json")
+	return cmd
+}
+
+// smokeRun is the state of one smoke test run.
+type smokeRun struct {
+	c        *client.Client
+	opts     smokeTestOptions
+	progress io.Writer
+	name     string
+	// published are the artifacts publish created, in apply order.
+	published []v1alpha1.ResourceRef
+	// deploymentApplied is set once the Deployment exists, so undeploy
+	// runs even when it never became ready.
+	deploymentApplied bool
+	report            smokeReport
+}
+
+// runRegistrySmokeTest runs every step against c and returns the report.
+// An error means the run could not start; failed steps are in the report.
+func runRegistrySmokeTest(ctx context.Context, c *client.Client, opts smokeTestOptions, progress io.Writer) (smokeReport, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return smokeReport{}, fmt.Errorf("generate smoke test name: %w", err)
+	}
+	r := &smokeRun{
+		c:        c,
+		opts:     opts,
+		progress: progress,
+		name:     "arctl-smoke-" + hex.EncodeToString(suffix),
+	}
+	r.report = smokeReport{Registry: c.BaseURL, Name: r.name}
+
+	// Undeploy and cleanup must run after Ctrl-C too; their waits are
+	// bounded by --timeout and each request by the client.
+	cleanupCtx := context.WithoutCancel(ctx)
+
+	published := r.run("publish", func() (string, error) { return r.publish(ctx) })
+	deployed := false
+	switch {
+	case !published:
+		r.skip("search", "publish failed")
+		r.skip("deploy", "publish failed")
+	default:
+		r.run("search", func() (string, error) { return r.search(ctx) })
+		if opts.runtime == "" {
+			r.skip("deploy", "no --runtime given")
+		} else {
+			deployed = r.run("deploy", func() (string, error) { return r.deploy(ctx) })
+		}
+	}
+	switch {
+	case opts.skipChat:
+		r.skip("chat", "--skip-chat given")
+	case !deployed:
+		r.skip("chat", "agent not deployed")
+	default:
+		r.run("chat", func() (string, error) { return r.chat(ctx) })
+	}
+	if r.deploymentApplied {
+		r.run("undeploy", func() (string, error) { return r.undeploy(cleanupCtx) })
+	} else {
+		r.skip("undeploy", "nothing deployed")
+	}
+	if len(r.published) > 0 {
+		r.run("cleanup", func() (string, error) { return r.cleanup(cleanupCtx) })
+	} else {
+		r.skip("cleanup", "nothing published")
+	}
+
+	r.report.Passed = !slices.ContainsFunc(r.report.Steps, func(s smokeStep) bool { return s.Status == smokeFail })
+	return r.report, nil
+}
+
+// run records the outcome of fn as step name and reports whether it passed.
+func (r *smokeRun) run(name string, fn func() (string, error)) bool {
+	fmt.Fprintf(r.progress, "%s...\n", name)
+	start := time.Now()
+	detail, err := fn()
+	step := smokeStep{Name: name, Status: smokePass, Duration: time.Since(start).Round(time.Millisecond).String(), Detail: detail}
+	if err != nil {
+		step.Status, step.Detail = smokeFail, err.Error()
+	}
+	r.report.Steps = append(r.report.Steps, step)
+	return err == nil
+}
+
+func (r *smokeRun) skip(name, reason string) {
+	r.report.Steps = append(r.report.Steps, smokeStep{Name: name, Status: smokeSkip, Duration: "0s", Detail: reason})
+}
+
+func (r *smokeRun) publish(ctx context.Context) (string, error) {
+	source := ""
+	if r.opts.agentImage != "" {
+		source = fmt.Sprintf("\n  source:\n    image: %q", r.opts.agentImage)
+	}
+	body := fmt.Sprintf(`apiVersion: %[1]s
+kind: MCPServer
+metadata:
+  namespace: %[2]s
+  name: %[3]s
+  tag: %[4]s
+spec:
+  title: arctl smoke test
+  description: Temporary MCP server published by arctl registry smoke-test.
+  remote:
+    type: streamable-http
+    url: https://mcp.example.com/%[3]s/mcp
+---
+apiVersion: %[1]s
+kind: Skill
+metadata:
+  namespace: %[2]s
+  name: %[3]s
+  tag: %[4]s
+spec:
+  title: arctl smoke test
+  description: Temporary skill published by arctl registry smoke-test.
+---
+apiVersion: %[1]s
+kind: Agent
+metadata:
+  namespace: %[2]s
+  name: %[3]s
+  tag: %[4]s
+spec:
+  title: arctl smoke test
+  description: Temporary agent published by arctl registry smoke-test.%[5]s
+`, v1alpha1.GroupVersion, r.opts.namespace, r.name, smokeTag, source)
+
+	results, err := r.c.Apply(ctx, []byte(body), client.ApplyOpts{})
+	if err != nil {
+		return "", err
+	}
+	var failures []string
+	for _, result := range results {
+		if result.Status == arv0.ApplyStatusFailed {
+			failures = append(failures, fmt.Sprintf("%s: %s", result.Kind, result.Error))
+			continue
+		}
+		r.published = append(r.published, v1alpha1.ResourceRef{Kind: result.Kind, Name: result.Name, Tag: result.Tag})
+	}
+	if len(failures) > 0 {
+		return "", errors.New(strings.Join(failures, "; "))
+	}
+	return fmt.Sprintf("published MCPServer, Skill and Agent %s@%s", r.name, smokeTag), nil
+}
+
+func (r *smokeRun) search(ctx context.Context) (string, error) {
+	want := []string{"server", "skill", "agent"}
+	results, err := r.c.Search(ctx, client.SearchOpts{Query: r.name, Types: want, Namespace: r.opts.namespace})
+	if err != nil {
+		return "", err
+	}
+	var missing []string
+	for _, typ := range want {
+		if !slices.ContainsFunc(results.Results, func(res arv0.SearchResult) bool { return res.Type == typ && res.Name == r.name }) {
+			missing = append(missing, typ)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("search for %q did not return the %s", r.name, strings.Join(missing, ", "))
+	}
+	return fmt.Sprintf("found all three among %d results", len(results.Results)), nil
+}
+
+func (r *smokeRun) deploy(ctx context.Context) (string, error) {
+	body := fmt.Sprintf(`apiVersion: %s
+kind: Deployment
+metadata:
+  namespace: %s
+  name: %s
+spec:
+  targetRef:
+    kind: Agent
+    name: %s
+    tag: %s
+  runtimeRef:
+    name: %s
+`, v1alpha1.GroupVersion, r.opts.namespace, r.name, r.name, smokeTag, r.opts.runtime)
+	results, err := r.c.Apply(ctx, []byte(body), client.ApplyOpts{})
+	if err != nil {
+		return "", err
+	}
+	for _, result := range results {
+		if result.Status == arv0.ApplyStatusFailed {
+			return "", fmt.Errorf("apply Deployment: %s", result.Error)
+		}
+	}
+	r.deploymentApplied = true
+
+	if err := cliCommon.WaitForDeployment(ctx, r.resolveDeployment, cliCommon.WaitOptions{
+		TargetStatus: "deployed",
+		Timeout:      r.opts.timeout,
+	}); err != nil {
+		return "", err
+	}
+	return "deployed on runtime " + r.opts.runtime, nil
+}
+
+func (r *smokeRun) chat(ctx context.Context) (string, error) {
+	card, err := r.c.AgentCard(ctx, r.opts.namespace, r.name, r.name)
+	if err != nil {
+		return "", fmt.Errorf("fetch agent card: %w", err)
+	}
+	agent, err := a2a.NewClient(card.URL, a2a.Options{Timeout: r.opts.timeout})
+	if err != nil {
+		return "", err
+	}
+	stream, err := agent.Stream(ctx, protocol.SendMessageParams{
+		Message: protocol.Message{
+			Kind:  protocol.KindMessage,
+			Role:  protocol.MessageRoleUser,
+			Parts: []protocol.Part{protocol.NewTextPart(smokeChatMessage)},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	events := 0
+	for range stream.Events() {
+		events++
+	}
+	if err := stream.Err(); err != nil {
+		return "", err
+	}
+	if events == 0 {
+		return "", fmt.Errorf("agent at %s ended the turn without replying", card.URL)
+	}
+	return fmt.Sprintf("agent at %s replied with %d events", card.URL, events), nil
+}
+
+func (r *smokeRun) undeploy(ctx context.Context) (string, error) {
+	if err := r.c.Delete(ctx, v1alpha1.KindDeployment, r.opts.namespace, r.name, ""); err != nil && !errors.Is(err, client.ErrNotFound) {
+		return "", err
+	}
+	if err := cliCommon.WaitForDeployment(ctx, r.resolveDeployment, cliCommon.WaitOptions{
+		TargetDeleted: true,
+		Timeout:       r.opts.timeout,
+	}); err != nil {
+		return "", err
+	}
+	return "deployment deleted", nil
+}
+
+// cleanup deletes the published artifacts in reverse apply order.
+func (r *smokeRun) cleanup(ctx context.Context) (string, error) {
+	var errs []error
+	for _, ref := range slices.Backward(r.published) {
+		if err := r.c.Delete(ctx, ref.Kind, r.opts.namespace, ref.Name, ref.Tag); err != nil && !errors.Is(err, client.ErrNotFound) {
+			errs = append(errs, fmt.Errorf("delete %s %s@%s: %w", ref.Kind, ref.Name, ref.Tag, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deleted %d artifacts", len(r.published)), nil
+}
+
+func (r *smokeRun) resolveDeployment(ctx context.Context) (*cliCommon.DeploymentRecord, error) {
+	return resolveDeploymentForWait(ctx, r.c, resourceLookupRef{Namespace: r.opts.namespace, Name: r.name})
+}
+
+func printSmokeReport(out io.Writer, report smokeReport, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STEP\tSTATUS\tDURATION\tDETAIL")
+	for _, step := range report.Steps {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", step.Name, strings.ToUpper(step.Status), step.Duration, step.Detail)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	verdict := "PASSED"
+	if !report.Passed {
+		verdict = "FAILED"
+	}
+	_, err := fmt.Fprintf(out, "Smoke test %s against %s\n", verdict, report.Registry)
+	return err
+}
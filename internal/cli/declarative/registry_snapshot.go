@@ -0,0 +1,161 @@
+package declarative
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+	"github.com/agentregistry-dev/agentregistry/pkg/printer"
+)
+
+func newSnapshotCmd(deps cliruntime.Deps) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Take and manage catalog snapshots",
+		Long: `A catalog snapshot is a named copy of every tag of every tagged artifact
+in the registry (MCPServers, Agents, Skills and so on), taken at one point
+in time. Runtimes, Deployments and other mutable kinds are not captured. A
+Deployment with spec.snapshot (arctl deployment create --snapshot) resolves
+its target, and every artifact it refers to, from the snapshot instead of
+the live catalog, so environments deployed from the same snapshot run the
+same versions whatever is published later.`,
+	}
+	cmd.AddCommand(newSnapshotCreateCmd(deps))
+	cmd.AddCommand(newSnapshotListCmd(deps))
+	cmd.AddCommand(newSnapshotGetCmd(deps))
+	cmd.AddCommand(newSnapshotDeleteCmd(deps))
+	return cmd
+}
+
+func newSnapshotCreateCmd(deps cliruntime.Deps) *cobra.Command {
+	var in arv0.CatalogSnapshotInput
+	cmd := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Take a catalog snapshot",
+		Long: `Create takes a catalog snapshot named NAME, through POST /v0/snapshots:
+every live tag of every tagged artifact, in --namespace or in every
+namespace. Snapshots are never overwritten; pick a new name to take
+another. Requires registry admin.`,
+		Example: `  arctl registry admin snapshot create 2024-06-release --description "June release"
+  arctl registry admin snapshot create team-a-2024-06 --namespace team-a`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := registryClient(cmd, deps)
+			if err != nil {
+				return err
+			}
+			in.Name = args[0]
+			snapshot, err := c.CreateSnapshot(cmd.Context(), in)
+			if err != nil {
+				return fmt.Errorf("creating snapshot %q: %w", in.Name, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Snapshot %s taken with %d artifact tags\n", snapshot.Name, snapshot.EntryCount)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&in.Namespace, "namespace", "", "Capture only this namespace (default: every namespace)")
+	cmd.Flags().StringVar(&in.Description, "description", "", "What the snapshot is for")
+	return cmd
+}
+
+func newSnapshotListCmd(deps cliruntime.Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List catalog snapshots, newest first",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := registryClient(cmd, deps)
+			if err != nil {
+				return err
+			}
+			snapshots, err := c.ListSnapshots(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("listing snapshots: %w", err)
+			}
+			return printSnapshots(cmd.OutOrStdout(), snapshots)
+		},
+	}
+}
+
+func printSnapshots(out io.Writer, snapshots []arv0.CatalogSnapshot) error {
+	if len(snapshots) == 0 {
+		fmt.Fprintln(out, "No snapshots found.")
+		return nil
+	}
+	t := printer.NewTablePrinter(out)
+	t.SetHeaders("NAME", "NAMESPACE", "ENTRIES", "CREATED", "CREATED BY", "DESCRIPTION")
+	for _, snapshot := range snapshots {
+		t.AddRow(
+			snapshot.Name,
+			printer.EmptyValueOrDefault(snapshot.Namespace, "*"),
+			strconv.Itoa(snapshot.EntryCount),
+			snapshot.CreatedAt.Format(time.RFC3339),
+			printer.EmptyValueOrDefault(snapshot.CreatedBy, "-"),
+			printer.TruncateString(snapshot.Description, 60),
+		)
+	}
+	return t.Render()
+}
+
+func newSnapshotGetCmd(deps cliruntime.Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:          "get NAME",
+		Short:        "Show a catalog snapshot and the artifact tags it holds",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := registryClient(cmd, deps)
+			if err != nil {
+				return err
+			}
+			snapshot, err := c.GetSnapshot(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("getting snapshot %q: %w", args[0], err)
+			}
+			out := cmd.OutOrStdout()
+			if err := printSnapshots(out, []arv0.CatalogSnapshot{*snapshot}); err != nil {
+				return err
+			}
+			if len(snapshot.Entries) == 0 {
+				return nil
+			}
+			fmt.Fprintln(out)
+			t := printer.NewTablePrinter(out)
+			t.SetHeaders("KIND", "NAMESPACE", "NAME", "TAG")
+			for _, entry := range snapshot.Entries {
+				t.AddRow(entry.Kind, entry.Namespace, entry.Name, entry.Tag)
+			}
+			return t.Render()
+		},
+	}
+}
+
+func newSnapshotDeleteCmd(deps cliruntime.Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete NAME",
+		Short: "Delete a catalog snapshot",
+		Long: `Delete removes a catalog snapshot. Deployments still pinned to it stop
+reconciling with a dangling reference until their spec.snapshot changes.
+Requires registry admin.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := registryClient(cmd, deps)
+			if err != nil {
+				return err
+			}
+			if err := c.DeleteSnapshot(cmd.Context(), args[0]); err != nil {
+				return fmt.Errorf("deleting snapshot %q: %w", args[0], err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Snapshot %s deleted\n", args[0])
+			return nil
+		},
+	}
+}
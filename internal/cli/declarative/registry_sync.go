@@ -0,0 +1,290 @@
+package declarative
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+	"github.com/agentregistry-dev/agentregistry/pkg/mcpregistry"
+)
+
+// syncStateFile is where `arctl registry sync` keeps its watermarks, in
+// the arctl config directory.
+const syncStateFile = "sync-state.yaml"
+
+// syncPageSize is the page size asked of the upstream registry.
+const syncPageSize = 100
+
+func newRegistrySyncCmd(deps cliruntime.Deps) *cobra.Command {
+	var (
+		from      string
+		statePath string
+		full      bool
+		opts      importOpts
+	)
+	cmd := &cobra.Command{
+		Use:   "sync --from URL",
+		Short: "Mirror the MCP servers of an upstream MCP registry",
+		Long: `Sync copies the MCP servers of an upstream registry that speaks the
+official MCP Registry API, such as registry.modelcontextprotocol.io, into
+this registry. Every server version becomes an MCPServer tagged with the
+version, applied the way "arctl registry import" applies a file.
+
+Syncs are incremental: the newest updatedAt seen upstream is recorded per
+source and target registry, and the next sync only asks for servers updated
+since then (the updated_since filter of GET /v0/servers). The watermark only
+advances when every server applied; --full ignores it.
+
+Servers upstream marks deleted are not removed here. Versions that cannot be
+translated to a valid MCPServer, such as packages of an unsupported registry
+type, are reported and skipped.`,
+		Example: `  arctl registry sync --from https://registry.modelcontextprotocol.io
+  arctl registry sync --from https://registry.modelcontextprotocol.io --full --dry-run
+  arctl registry sync --from https://registry.staging.example.com/v0.1/servers`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if statePath == "" {
+				dir := deps.Runtime.Contexts().Dir
+				if dir == "" {
+					return fmt.Errorf("no arctl config directory; pass --state")
+				}
+				statePath = filepath.Join(dir, syncStateFile)
+			}
+			return runRegistrySync(cmd, deps, from, statePath, full, opts)
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "Upstream registry URL; servers are listed from URL/v0/servers unless it ends in /servers")
+	cmd.Flags().StringVar(&statePath, "state", "", "Watermark file (default: sync-state.yaml in the arctl config directory)")
+	cmd.Flags().BoolVar(&full, "full", false, "Ignore the recorded watermark and sync every server")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Validate and simulate without mutating state or advancing the watermark")
+	cmd.Flags().IntVar(&opts.Parallel, "parallel", 4, "Number of batches to apply at once")
+	cmd.Flags().IntVar(&opts.Retries, "retries", 5, "Attempts per batch before the sync stops")
+	_ = cmd.MarkFlagRequired("from")
+	return cmd
+}
+
+func runRegistrySync(cmd *cobra.Command, deps cliruntime.Deps, from, statePath string, full bool, opts importOpts) error {
+	if deps.Runtime == nil {
+		return fmt.Errorf("registry runtime not configured")
+	}
+	source, err := upstreamServersURL(from)
+	if err != nil {
+		return err
+	}
+	target := deps.Runtime.RegistryTarget().BaseURL
+
+	state, err := loadSyncState(statePath)
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	if !full {
+		since = state.watermark(source, target)
+	}
+	errOut := cmd.ErrOrStderr()
+	if since.IsZero() {
+		fmt.Fprintf(errOut, "Fetching every server from %s\n", source)
+	} else {
+		fmt.Fprintf(errOut, "Fetching servers updated since %s from %s\n", since.Format(time.RFC3339), source)
+	}
+
+	servers, newest, err := fetchUpstreamServers(cmd.Context(), source, since)
+	if err != nil {
+		return err
+	}
+	var docs []string
+	for _, s := range servers {
+		server, err := upstreamMCPServer(s)
+		if err != nil {
+			fmt.Fprintf(errOut, "Skipping %s (%s): %v\n", s.Server.Name, s.Server.Version, err)
+			continue
+		}
+		if server == nil {
+			continue
+		}
+		doc, err := yaml.Marshal(server)
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", s.Server.Name, err)
+		}
+		docs = append(docs, string(doc))
+	}
+
+	out := cmd.OutOrStdout()
+	if len(docs) > 0 {
+		if err := runRegistryImport(cmd, deps, []byte(strings.Join(docs, "---\n")), opts); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(out, "No changed servers")
+	}
+	if opts.DryRun || !newest.After(since) {
+		return nil
+	}
+	state.setWatermark(source, target, newest)
+	if err := state.save(statePath); err != nil {
+		return err
+	}
+	fmt.Fprintf(errOut, "Next sync from %s starts at %s\n", source, newest.Format(time.RFC3339))
+	return nil
+}
+
+// upstreamServersURL resolves --from to the servers list URL.
+func upstreamServersURL(from string) (string, error) {
+	u, err := url.Parse(strings.TrimRight(from, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("--from must be an http(s) URL, got %q", from)
+	}
+	if !strings.HasSuffix(u.Path, "/servers") {
+		u.Path += "/v0/servers"
+	}
+	return u.String(), nil
+}
+
+// fetchUpstreamServers pages through the servers list at source, every
+// version of every server updated at or after since (everything when since
+// is zero). It returns the servers in upstream order and the newest
+// updatedAt among them.
+func fetchUpstreamServers(ctx context.Context, source string, since time.Time) ([]mcpregistry.ServerResponse, time.Time, error) {
+	httpClient := httpclient.New(30 * time.Second)
+	var (
+		servers []mcpregistry.ServerResponse
+		newest  = since
+		cursor  string
+	)
+	for {
+		q := url.Values{}
+		q.Set("limit", fmt.Sprint(syncPageSize))
+		if !since.IsZero() {
+			q.Set("updated_since", since.UTC().Format(time.RFC3339))
+		}
+		if cursor != "" {
+			q.Set("cursor", cursor)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source+"?"+q.Encode(), nil)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		req.Header.Set("Accept", "application/json")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("list upstream servers: %w", err)
+		}
+		var page mcpregistry.ServerListResponse
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			return nil, time.Time{}, fmt.Errorf("list upstream servers: %s", resp.Status)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("decode upstream servers: %w", err)
+		}
+		for _, s := range page.Servers {
+			if s.Meta != nil && s.Meta.Official != nil {
+				if ts, err := time.Parse(time.RFC3339, s.Meta.Official.UpdatedAt); err == nil && ts.After(newest) {
+					newest = ts
+				}
+			}
+		}
+		servers = append(servers, page.Servers...)
+		if page.Metadata.NextCursor == "" || len(page.Servers) == 0 {
+			return servers, newest, nil
+		}
+		cursor = page.Metadata.NextCursor
+	}
+}
+
+// upstreamMCPServer translates one upstream server version. It returns nil
+// for versions upstream marks deleted, and an error for versions that do
+// not make a valid MCPServer. Names are canonicalized, since upstream
+// namespaces keep the case of the publisher's account.
+func upstreamMCPServer(s mcpregistry.ServerResponse) (*v1alpha1.MCPServer, error) {
+	if s.Meta != nil && s.Meta.Official != nil && s.Meta.Official.Status == "deleted" {
+		return nil, nil
+	}
+	server, err := mcpregistry.ToMCPServer(s.Server)
+	if err != nil {
+		return nil, err
+	}
+	server.Metadata.Namespace = v1alpha1.CanonicalName(server.Metadata.Namespace)
+	server.Metadata.Name = v1alpha1.CanonicalName(server.Metadata.Name)
+	if err := server.Validate(); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// syncState is the watermark file: for each upstream source and target
+// registry, the newest updatedAt already synced.
+type syncState struct {
+	Watermarks []syncWatermark `yaml:"watermarks"`
+}
+
+type syncWatermark struct {
+	Source       string    `yaml:"source"`
+	Registry     string    `yaml:"registry"`
+	UpdatedSince time.Time `yaml:"updatedSince"`
+}
+
+// loadSyncState reads the watermark file; a missing file has none.
+func loadSyncState(path string) (*syncState, error) {
+	state := &syncState{}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read sync state: %w", err)
+	}
+	if err := yaml.Unmarshal(raw, state); err != nil {
+		return nil, fmt.Errorf("parse sync state %s: %w", path, err)
+	}
+	return state, nil
+}
+
+func (s *syncState) watermark(source, registry string) time.Time {
+	for _, w := range s.Watermarks {
+		if w.Source == source && w.Registry == registry {
+			return w.UpdatedSince
+		}
+	}
+	return time.Time{}
+}
+
+func (s *syncState) setWatermark(source, registry string, ts time.Time) {
+	for i, w := range s.Watermarks {
+		if w.Source == source && w.Registry == registry {
+			s.Watermarks[i].UpdatedSince = ts
+			return
+		}
+	}
+	s.Watermarks = append(s.Watermarks, syncWatermark{Source: source, Registry: registry, UpdatedSince: ts})
+}
+
+func (s *syncState) save(path string) error {
+	raw, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("write sync state: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return fmt.Errorf("write sync state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,508 @@
+package declarative_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/mcpregistry"
+)
+
+const exportedYAML = `apiVersion: ar.dev/v1alpha1
+kind: MCPServer
+metadata:
+  name: weather
+  tag: 1.0.0
+spec:
+  title: Weather
+---
+apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  name: reporter
+  tag: 1.0.0
+spec:
+  title: Reporter
+`
+
+func TestRegistryExport_WritesFile(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/export"), r.URL.Path)
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = io.WriteString(w, exportedYAML)
+	}))
+	t.Cleanup(srv.Close)
+
+	path := filepath.Join(t.TempDir(), "export.yaml")
+	cmd := declarative.NewRegistryCmd(applyDeps(t, srv))
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"export", path, "--namespace", "team-a"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, "namespace=team-a", gotQuery)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, exportedYAML, string(data))
+}
+
+// importRegistry fakes POST /v0/apply for `arctl registry import`: it
+// records the kinds and names of every batch and answers one result per
+// document, failing the names in fail. Batches of a kind in down get a 503.
+type importRegistry struct {
+	t       *testing.T
+	fail    map[string]bool
+	down    map[string]bool
+	mu      sync.Mutex
+	batches [][]string
+}
+
+var importDocRE = regexp.MustCompile(`(?m)^kind: (\w+)\nmetadata:\n(?:  namespace: \S+\n)?  name: ([\w-]+)$`)
+
+func (f *importRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	require.Equal(f.t, http.MethodPost, r.Method)
+	body, _ := io.ReadAll(r.Body)
+	var (
+		batch   []string
+		results []arv0.ApplyResult
+	)
+	for _, m := range importDocRE.FindAllStringSubmatch(string(body), -1) {
+		if f.down[m[1]] {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		batch = append(batch, m[1]+"/"+m[2])
+		result := arv0.ApplyResult{Kind: m[1], Name: m[2], Tag: "1.0.0", Status: arv0.ApplyStatusCreated}
+		if f.fail[m[2]] {
+			result.Status, result.Error = arv0.ApplyStatusFailed, "refs: not found"
+		}
+		results = append(results, result)
+	}
+	f.mu.Lock()
+	f.batches = append(f.batches, batch)
+	f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(batchApplyResponse(results))
+}
+
+func TestRegistryImport_AppliesInOrder(t *testing.T) {
+	fake := &importRegistry{t: t, fail: map[string]bool{"reporter": true}}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	path := writeTempYAML(t, exportedYAML)
+	cmd := declarative.NewRegistryCmd(applyDeps(t, srv))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"import", path})
+	require.ErrorContains(t, cmd.Execute(), "1 resources failed to import")
+
+	require.Equal(t, [][]string{{"MCPServer/weather"}, {"Agent/reporter"}}, fake.batches, "kinds are applied one after another")
+	require.Contains(t, out.String(), "Imported 1 resources, 1 failed")
+	require.FileExists(t, path+".import-state", "a failed import keeps its checkpoint")
+}
+
+func TestRegistryImport_ParallelWithinKind(t *testing.T) {
+	var docs []string
+	for i := range 6 {
+		docs = append(docs, fmt.Sprintf("apiVersion: ar.dev/v1alpha1\nkind: Skill\nmetadata:\n  name: skill-%d\nspec:\n  title: %s\n",
+			i, strings.Repeat("x", 200<<10)))
+	}
+	docs = append(docs, "apiVersion: ar.dev/v1alpha1\nkind: Agent\nmetadata:\n  name: reporter\nspec:\n  title: Reporter\n")
+	fake := &importRegistry{t: t}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	path := writeTempYAML(t, strings.Join(docs, "---\n"))
+	cmd := declarative.NewRegistryCmd(applyDeps(t, srv))
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"import", path, "--parallel", "3"})
+	require.NoError(t, cmd.Execute())
+
+	require.Len(t, fake.batches, 4)
+	require.Equal(t, []string{"Agent/reporter"}, fake.batches[3], "agents wait for every skill batch")
+	var skills []string
+	for _, batch := range fake.batches[:3] {
+		skills = append(skills, batch...)
+	}
+	require.ElementsMatch(t, []string{"Skill/skill-0", "Skill/skill-1", "Skill/skill-2", "Skill/skill-3", "Skill/skill-4", "Skill/skill-5"}, skills)
+	require.NoFileExists(t, path+".import-state", "a complete import removes its checkpoint")
+}
+
+func TestRegistryImport_Resume(t *testing.T) {
+	fake := &importRegistry{t: t, down: map[string]bool{"Agent": true}}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	path := writeTempYAML(t, exportedYAML)
+	run := func(args ...string) error {
+		cmd := declarative.NewRegistryCmd(applyDeps(t, srv))
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append([]string{"import", path, "--retries", "1"}, args...))
+		return cmd.Execute()
+	}
+	require.ErrorContains(t, run(), "re-run with --resume")
+	require.Equal(t, [][]string{{"MCPServer/weather"}}, fake.batches)
+
+	fake.down, fake.batches = nil, nil
+	require.NoError(t, run("--resume"))
+	require.Equal(t, [][]string{{"Agent/reporter"}}, fake.batches, "checkpointed documents are skipped")
+	require.NoFileExists(t, path+".import-state")
+}
+
+func TestRegistryImport_RejectsUnknownKind(t *testing.T) {
+	cmd := declarative.NewRegistryCmd(declarativeTestDeps(nil))
+	cmd.SetArgs([]string{"import", writeTempYAML(t, "apiVersion: ar.dev/v1alpha1\nkind: Gadget\nmetadata:\n  name: x\n")})
+	require.Error(t, cmd.Execute())
+}
+
+func TestRegistrySeed_AppliesProfile(t *testing.T) {
+	var applied []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "true", r.URL.Query().Get("dryRun"))
+		applied, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(batchApplyResponse([]arv0.ApplyResult{
+			{Kind: v1alpha1.KindMCPServer, Name: "market-data", Tag: "1.0.0", Status: arv0.ApplyStatusCreated},
+		}))
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewRegistryCmd(applyDeps(t, srv))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"seed", "--profile", "demo-finance", "--dry-run"})
+	require.NoError(t, cmd.Execute())
+
+	require.Contains(t, string(applied), "namespace: demo-finance")
+	require.Contains(t, string(applied), "name: portfolio-analyst")
+}
+
+func TestRegistrySeed_ListAndUnknownProfile(t *testing.T) {
+	var out bytes.Buffer
+	cmd := declarative.NewRegistryCmd(declarativeTestDeps(nil))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"seed", "--list"})
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, out.String(), "demo-finance")
+	require.Contains(t, out.String(), "demo-devtools")
+
+	cmd = declarative.NewRegistryCmd(declarativeTestDeps(nil))
+	cmd.SetArgs([]string{"seed", "--profile", "demo-retail"})
+	require.ErrorContains(t, cmd.Execute(), `unknown seed profile "demo-retail"`)
+}
+
+func TestRegistryAdminReindexEmbeddings(t *testing.T) {
+	var gotQuery string
+	events := []arv0.EmbeddingsReindexEvent{
+		{Type: arv0.EmbeddingsReindexPlanned, Total: 2, Scanned: 5, UpToDate: 3},
+		{Type: arv0.EmbeddingsReindexItem, Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "weather", Reason: arv0.EmbeddingsReasonStale, Processed: 1, Total: 2},
+		{Type: arv0.EmbeddingsReindexItem, Kind: v1alpha1.KindAgent, Namespace: "default", Name: "reporter", Reason: arv0.EmbeddingsReasonMissing, Error: "quota exceeded", Processed: 2, Total: 2},
+		{Type: arv0.EmbeddingsReindexDone, Processed: 2, Total: 2, Scanned: 5, UpToDate: 3, Failed: 1},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.True(t, strings.HasSuffix(r.URL.Path, "/admin/embeddings:reindex"), r.URL.Path)
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			data, err := json.Marshal(event)
+			require.NoError(t, err)
+			_, _ = io.WriteString(w, "data: "+string(data)+"\n\n")
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewRegistryCmd(applyDeps(t, srv))
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"admin", "reindex-embeddings", "--types", "server,agent", "--rate", "2"})
+	require.ErrorContains(t, cmd.Execute(), "1 embeddings failed")
+
+	require.Equal(t, "rate=2&types=server%2Cagent", gotQuery)
+	require.Equal(t, `Embedding 2 of 5 artifacts (3 up to date)
+[1/2] MCPServer default/weather (stale) embedded
+[2/2] Agent default/reporter (missing) failed: quota exceeded
+Embedded 1 artifacts, 1 failed
+`, out.String())
+}
+
+func TestRegistryAdminGC(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.True(t, strings.HasSuffix(r.URL.Path, "/admin/gc"), r.URL.Path)
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(arv0.GCReport{
+			DryRun: true, KeepVersions: 10, KeepWithin: "720h0m0s", Scanned: 14, Kept: 12, InUse: 1,
+			Deleted: []arv0.GCVersion{
+				{Kind: v1alpha1.KindMCPServer, Namespace: "team-a", Name: "weather", Tag: "0.1.0", CreatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+				{Kind: v1alpha1.KindMCPServer, Namespace: "team-a", Name: "weather", Tag: "0.2.0", CreatedAt: time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)},
+			},
+		}))
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewRegistryCmd(applyDeps(t, srv))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"admin", "gc", "--dry-run", "--namespace", "team-a"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, "dryRun=true&namespace=team-a", gotQuery)
+	require.Equal(t, `MCPServer team-a/weather 0.1.0 (published 2026-01-02)
+MCPServer team-a/weather 0.2.0 (published 2026-01-09)
+Would delete 2 of 14 versions (12 kept, 1 of them in use; keeping the newest 10 and those younger than 720h0m0s)
+`, out.String())
+}
+
+func TestRegistryAdminSnapshot(t *testing.T) {
+	var created arv0.CatalogSnapshotInput
+	var deleted string
+	snapshot := arv0.CatalogSnapshot{
+		Name: "2024-06-release", Description: "June release", CreatedBy: "admin",
+		CreatedAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), EntryCount: 1,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v0/snapshots":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			require.NoError(t, json.NewEncoder(w).Encode(snapshot))
+		case "GET /v0/snapshots/2024-06-release":
+			withEntries := snapshot
+			withEntries.Entries = []arv0.CatalogSnapshotEntry{{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "weather", Tag: "1.0.0"}}
+			require.NoError(t, json.NewEncoder(w).Encode(withEntries))
+		case "DELETE /v0/snapshots/2024-06-release":
+			deleted = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := declarative.NewRegistryCmd(applyDeps(t, srv))
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append([]string{"admin", "snapshot"}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("create", "2024-06-release", "--description", "June release")
+	require.NoError(t, err)
+	require.Equal(t, arv0.CatalogSnapshotInput{Name: "2024-06-release", Description: "June release"}, created)
+	require.Equal(t, "Snapshot 2024-06-release taken with 1 artifact tags\n", out)
+
+	out, err = run("get", "2024-06-release")
+	require.NoError(t, err)
+	require.Contains(t, out, "2024-06-release")
+	require.Regexp(t, `MCPServer\s+default\s+weather\s+1.0.0`, out)
+
+	_, err = run("get", "2024-07-release")
+	require.ErrorContains(t, err, `getting snapshot "2024-07-release"`)
+
+	out, err = run("delete", "2024-06-release")
+	require.NoError(t, err)
+	require.Equal(t, "/v0/snapshots/2024-06-release", deleted)
+	require.Equal(t, "Snapshot 2024-06-release deleted\n", out)
+}
+
+// smokeRegistry fakes the endpoints `arctl registry smoke-test` calls and
+// records the requests it saw.
+type smokeRegistry struct {
+	t *testing.T
+	// searchTypes are the types search answers with.
+	searchTypes []string
+	name        string
+	applied     []string
+	deleted     []string
+}
+
+var smokeNameRE = regexp.MustCompile(`name: (arctl-smoke-[0-9a-f]+)`)
+
+func (f *smokeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/apply"):
+		body, _ := io.ReadAll(r.Body)
+		f.name = smokeNameRE.FindStringSubmatch(string(body))[1]
+		var results []arv0.ApplyResult
+		for _, kind := range regexp.MustCompile(`(?m)^kind: (\w+)$`).FindAllStringSubmatch(string(body), -1) {
+			f.applied = append(f.applied, kind[1])
+			results = append(results, arv0.ApplyResult{Kind: kind[1], Name: f.name, Tag: "1.0.0", Status: arv0.ApplyStatusCreated})
+		}
+		_, _ = w.Write(batchApplyResponse(results))
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/search"):
+		require.Equal(f.t, f.name, r.URL.Query().Get("q"))
+		results := arv0.SearchResults{Query: f.name, Results: []arv0.SearchResult{}}
+		for _, typ := range f.searchTypes {
+			results.Results = append(results.Results, arv0.SearchResult{Type: typ, Name: f.name, Tag: "1.0.0"})
+		}
+		require.NoError(f.t, json.NewEncoder(w).Encode(results))
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/deployments/"+f.name):
+		if slices.Contains(f.deleted, "deployments") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		dep := v1alpha1.Deployment{
+			TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindDeployment},
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: f.name},
+			Spec:     v1alpha1.DeploymentSpec{TargetRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: f.name}},
+		}
+		dep.Status.SetCondition(v1alpha1.Condition{Type: "Ready", Status: v1alpha1.ConditionTrue})
+		require.NoError(f.t, json.NewEncoder(w).Encode(dep))
+	case r.Method == http.MethodDelete:
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v0/"), "/")
+		f.deleted = append(f.deleted, parts[0])
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestRegistrySmokeTest_PublishesSearchesAndCleansUp(t *testing.T) {
+	fake := &smokeRegistry{t: t, searchTypes: []string{"server", "skill", "agent"}}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewRegistryCmd(applyDeps(t, srv))
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"smoke-test", "-o", "json"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, []string{"MCPServer", "Skill", "Agent"}, fake.applied)
+	require.Equal(t, []string{"agents", "skills", "mcpservers"}, fake.deleted)
+	var report struct {
+		Name   string `json:"name"`
+		Passed bool   `json:"passed"`
+		Steps  []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		} `json:"steps"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.True(t, report.Passed)
+	require.Equal(t, fake.name, report.Name)
+	var steps []string
+	for _, step := range report.Steps {
+		steps = append(steps, step.Name+"="+step.Status)
+	}
+	require.Equal(t, []string{"publish=pass", "search=pass", "deploy=skip", "chat=skip", "undeploy=skip", "cleanup=pass"}, steps)
+}
+
+func TestRegistrySmokeTest_FailedStepStillUndeploysAndCleansUp(t *testing.T) {
+	fake := &smokeRegistry{t: t, searchTypes: []string{"server", "agent"}}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewRegistryCmd(applyDeps(t, srv))
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"smoke-test", "--runtime", "local", "--agent-image", "ghcr.io/acme/echo:1.0.0", "--skip-chat"})
+	require.ErrorContains(t, cmd.Execute(), "smoke test failed: 1 of 6 steps failed")
+
+	require.Equal(t, []string{"MCPServer", "Skill", "Agent", "Deployment"}, fake.applied)
+	require.Equal(t, []string{"deployments", "agents", "skills", "mcpservers"}, fake.deleted)
+	require.Contains(t, out.String(), `search    FAIL`)
+	require.Contains(t, out.String(), `did not return the skill`)
+	require.Contains(t, out.String(), "deploy    PASS")
+	require.Contains(t, out.String(), "deployed on runtime local")
+	require.Contains(t, out.String(), "undeploy  PASS")
+	require.Contains(t, out.String(), "Smoke test FAILED against "+srv.URL+"/v0")
+}
+
+func TestRegistrySync_Incremental(t *testing.T) {
+	var queries []url.Values
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v0/servers", r.URL.Path)
+		queries = append(queries, r.URL.Query())
+		page := mcpregistry.ServerListResponse{Servers: []mcpregistry.ServerResponse{}}
+		meta := func(status, updated string) *mcpregistry.ResponseMeta {
+			return &mcpregistry.ResponseMeta{Official: &mcpregistry.OfficialMeta{Status: status, UpdatedAt: updated}}
+		}
+		switch {
+		case r.URL.Query().Get("updated_since") != "":
+		case r.URL.Query().Get("cursor") == "":
+			page.Servers = append(page.Servers,
+				mcpregistry.ServerResponse{
+					Server: mcpregistry.ServerDetail{Name: "io.github.Acme/weather", Version: "1.0.0", Description: "Forecasts.",
+						Remotes: []mcpregistry.ServerTransport{{Type: "streamable-http", URL: "https://mcp.example.com/mcp"}}},
+					Meta: meta("active", "2026-10-01T00:00:00Z"),
+				},
+				mcpregistry.ServerResponse{
+					Server: mcpregistry.ServerDetail{Name: "io.github.acme/gone", Version: "1.0.0"},
+					Meta:   meta("deleted", "2026-10-03T00:00:00Z"),
+				})
+			page.Metadata.NextCursor = "page-2"
+		default:
+			page.Servers = append(page.Servers, mcpregistry.ServerResponse{
+				Server: mcpregistry.ServerDetail{Name: "io.github.acme/cargo", Version: "0.1.0",
+					Packages: []mcpregistry.ServerPackage{{RegistryType: "cargo", Identifier: "cargo-mcp"}}},
+				Meta: meta("active", "2026-10-02T00:00:00Z"),
+			})
+		}
+		page.Metadata.Count = len(page.Servers)
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(page))
+	}))
+	t.Cleanup(upstream.Close)
+	fake := &importRegistry{t: t}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	state := filepath.Join(t.TempDir(), "sync-state.yaml")
+	run := func() (string, string) {
+		var out, errOut bytes.Buffer
+		cmd := declarative.NewRegistryCmd(applyDeps(t, srv))
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		cmd.SetArgs([]string{"sync", "--from", upstream.URL, "--state", state})
+		require.NoError(t, cmd.Execute())
+		return out.String(), errOut.String()
+	}
+
+	out, errOut := run()
+	require.Len(t, queries, 2)
+	require.Empty(t, queries[0].Get("updated_since"))
+	require.Equal(t, "page-2", queries[1].Get("cursor"))
+	require.Equal(t, [][]string{{"MCPServer/weather"}}, fake.batches, "deleted and untranslatable versions are skipped")
+	require.Contains(t, out, "Imported 1 resources, 0 failed")
+	require.Contains(t, errOut, "Skipping io.github.acme/cargo (0.1.0)")
+	require.Contains(t, errOut, "starts at 2026-10-03T00:00:00Z")
+
+	fake.batches = nil
+	out, _ = run()
+	require.Equal(t, "2026-10-03T00:00:00Z", queries[2].Get("updated_since"))
+	require.Empty(t, fake.batches)
+	require.Contains(t, out, "No changed servers")
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	cliCommon "github.com/agentregistry-dev/agentregistry/internal/cli/common"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/scheme"
@@ -31,6 +33,7 @@ func listAny[T v1alpha1.Object](ctx context.Context, c *client.Client, kind stri
 			Namespace:  v1alpha1.DefaultNamespace,
 			Tag:        opts.Tag,
 			LatestOnly: opts.LatestOnly,
+			Sort:       opts.Sort,
 			Limit:      200,
 		},
 		newObj,
@@ -119,6 +122,7 @@ func listDeploymentResources(ctx context.Context, c *client.Client, opts scheme.
 			Limit:              200,
 			Origin:             opts.Origin,
 			IncludeTerminating: true,
+			Sort:               opts.Sort,
 		},
 		func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} },
 	)
@@ -178,6 +182,22 @@ func promptRow(prompt *v1alpha1.Prompt) []string {
 	}
 }
 
+func chartRow(chart *v1alpha1.Chart) []string {
+	if chart == nil {
+		return []string{"<invalid>"}
+	}
+	ref := chart.Spec.Repository
+	if chart.Spec.Chart != "" {
+		ref += "/" + chart.Spec.Chart
+	}
+	return []string{
+		printer.TruncateString(chart.Metadata.Name, 40),
+		chart.Metadata.Tag,
+		printer.TruncateString(ref, 60),
+		chart.Spec.Version,
+	}
+}
+
 func runtimeRow(runtime *v1alpha1.Runtime) []string {
 	if runtime == nil {
 		return []string{"<invalid>"}
@@ -185,6 +205,51 @@ func runtimeRow(runtime *v1alpha1.Runtime) []string {
 	return []string{runtime.Metadata.Name, runtime.Spec.Type}
 }
 
+func webhookRow(hook *v1alpha1.Webhook) []string {
+	if hook == nil {
+		return []string{"<invalid>"}
+	}
+	events := "*"
+	if len(hook.Spec.Events) > 0 {
+		events = strings.Join(hook.Spec.Events, ",")
+	}
+	return []string{
+		printer.TruncateString(hook.Metadata.Name, 40),
+		printer.TruncateString(hook.Spec.URL, 60),
+		events,
+	}
+}
+
+func featureFlagRow(flag *v1alpha1.FeatureFlag) []string {
+	if flag == nil {
+		return []string{"<invalid>"}
+	}
+	return []string{
+		flag.Metadata.Name,
+		strconv.FormatBool(flag.Spec.Enabled),
+		strconv.Itoa(len(flag.Spec.Agents)),
+	}
+}
+
+func deploymentTemplateRow(tmpl *v1alpha1.DeploymentTemplate) []string {
+	if tmpl == nil {
+		return []string{"<invalid>"}
+	}
+	runtime := tmpl.Spec.RuntimeRef.Name
+	if runtime == "" && tmpl.Spec.RuntimeSelector != nil {
+		runtime = describeRuntimeSelector(tmpl.Spec.RuntimeSelector)
+	}
+	if runtime == "" {
+		runtime = "<none>"
+	}
+	return []string{
+		tmpl.Metadata.Name,
+		runtime,
+		strconv.Itoa(len(tmpl.Spec.Env)),
+		printer.TruncateString(tmpl.Spec.Description, 50),
+	}
+}
+
 func deploymentRow(dep *cliCommon.DeploymentRecord) []string {
 	if dep == nil {
 		return []string{"<invalid>"}
@@ -196,7 +261,21 @@ func deploymentRow(dep *cliCommon.DeploymentRecord) []string {
 		dep.ResourceType,
 		dep.RuntimeID,
 		dep.Status,
+		resolvedTagsCell(dep.ResolvedTags),
+	}
+}
+
+// resolvedTagsCell renders the tags version constraints resolved to as
+// name@tag pairs.
+func resolvedTagsCell(resolved []v1alpha1.ResolvedTag) string {
+	if len(resolved) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(resolved))
+	for _, r := range resolved {
+		parts = append(parts, r.Name+"@"+r.Tag)
 	}
+	return strings.Join(parts, ",")
 }
 
 func errorsJoin(errs []error) error {
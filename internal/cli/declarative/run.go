@@ -15,6 +15,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/agentregistry-dev/agentregistry/internal/a2a"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/buildconfig"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative/chat"
 	inspectorpkg "github.com/agentregistry-dev/agentregistry/internal/cli/declarative/inspector"
@@ -31,6 +32,7 @@ func NewRunCmd(_ cliruntime.Deps) *cobra.Command {
 		watch     bool
 		noChat    bool
 		inspector bool
+		chatOpts  a2a.Options
 	)
 	cmd := &cobra.Command{
 		Use:   cliruntime.CommandRun + " [DIRECTORY]",
@@ -56,6 +58,7 @@ that the framework's required env vars are set.`,
   arctl run -e FOO=bar -e BAZ=qux
   arctl run --no-chat              # agent without chat
   arctl run --watch                # iterative dev loop
+  arctl run --chat-timeout 10m     # give up on a chat turn after 10 minutes
   arctl run mymcp --inspector      # MCP with MCP Inspector launched`,
 		SilenceUsage: true,
 		Args:         cobra.MaximumNArgs(1),
@@ -65,7 +68,7 @@ that the framework's required env vars are set.`,
 				return err
 			}
 			inspectorExplicit := cmd.Flags().Changed("inspector")
-			return runProject(cmd.Context(), cmd.OutOrStdout(), dir, extraEnv, dryRun, watch, noChat, inspector, inspectorExplicit)
+			return runProject(cmd.Context(), cmd.OutOrStdout(), dir, extraEnv, dryRun, watch, noChat, inspector, inspectorExplicit, chatOpts)
 		},
 	}
 	cmd.Flags().StringArrayVarP(&extraEnv, "env", "e", nil, "KEY=VALUE env override")
@@ -73,6 +76,9 @@ that the framework's required env vars are set.`,
 	cmd.Flags().BoolVar(&watch, "watch", false, "Rebuild and restart on file change (skips chat for agents; for chat open a second terminal)")
 	cmd.Flags().BoolVar(&noChat, "no-chat", false, "Skip chat for Agents; run the framework command in the foreground (agent projects only; errors on MCP projects)")
 	cmd.Flags().BoolVar(&inspector, "inspector", false, "Launch MCP Inspector alongside the server; it connects when ready (MCP projects only; errors on agent projects)")
+	cmd.Flags().DurationVar(&chatOpts.ConnectTimeout, "chat-connect-timeout", a2a.DefaultConnectTimeout, "How long chat waits for the agent to start answering a message")
+	cmd.Flags().DurationVar(&chatOpts.IdleTimeout, "chat-idle-timeout", a2a.DefaultIdleTimeout, "How long a chat reply may go silent before chat reconnects to it (negative disables)")
+	cmd.Flags().DurationVar(&chatOpts.Timeout, "chat-timeout", 0, "Give up on a chat reply after this long, reconnects included (0: no limit)")
 	return cmd
 }
 
@@ -97,7 +103,7 @@ func resolveProjectDir(args []string) (string, error) {
 	return abs, nil
 }
 
-func runProject(ctx context.Context, out io.Writer, projectDir string, extraEnv []string, dryRun, watch, noChat, inspector, inspectorExplicit bool) error {
+func runProject(ctx context.Context, out io.Writer, projectDir string, extraEnv []string, dryRun, watch, noChat, inspector, inspectorExplicit bool, chatOpts a2a.Options) error {
 	cfg, err := buildconfig.Read(projectDir)
 	if err != nil {
 		return err
@@ -238,7 +244,7 @@ func runProject(ctx context.Context, out io.Writer, projectDir string, extraEnv
 	chatMode := frameworkType == "agent" && !noChat
 
 	if chatMode {
-		return runWithChat(out, projectDir, name, p.Name, rendered, envv, dryRun)
+		return runWithChat(out, projectDir, name, p.Name, rendered, envv, chatOpts, dryRun)
 	}
 
 	if dryRun {
@@ -284,7 +290,7 @@ const agentReadinessTimeout = 90 * time.Second
 // resurrected from the deleted `arctl agent run`.
 //
 // dryRun short-circuits: narrate what would happen but don't shell out.
-func runWithChat(out io.Writer, projectDir, agentName, frameworkName string, rendered, envv []string, dryRun bool) error {
+func runWithChat(out io.Writer, projectDir, agentName, frameworkName string, rendered, envv []string, chatOpts a2a.Options, dryRun bool) error {
 	upArgv := composeUpDetachedArgs(rendered)
 	downArgv := composeDownArgs(rendered, projectDir)
 
@@ -296,6 +302,23 @@ func runWithChat(out io.Writer, projectDir, agentName, frameworkName string, ren
 		return nil
 	}
 
+	stop, err := startAgent(out, projectDir, frameworkName, upArgv, downArgv, envv)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if err := chat.LaunchA2A(context.Background(), agentName, agentReadinessURL, chatOpts, false); err != nil {
+		return fmt.Errorf("chat: %w", err)
+	}
+	return nil
+}
+
+// startAgent starts the runtime with upArgv (compose up -d) and waits
+// until the agent endpoint responds. The returned stop tears the runtime
+// down with downArgv; startAgent calls it itself when the agent never
+// becomes ready.
+func startAgent(out io.Writer, projectDir, frameworkName string, upArgv, downArgv, envv []string) (stop func(), err error) {
 	fmt.Fprintf(out, "→ %s: %s\n", frameworkName, strings.Join(upArgv, " "))
 	upCmd := exec.Command(upArgv[0], upArgv[1:]...)
 	upCmd.Dir = projectDir
@@ -303,11 +326,11 @@ func runWithChat(out io.Writer, projectDir, agentName, frameworkName string, ren
 	upCmd.Stderr = out
 	upCmd.Env = append(os.Environ(), envv...)
 	if err := upCmd.Run(); err != nil {
-		return fmt.Errorf("docker compose up: %w", err)
+		return nil, fmt.Errorf("docker compose up: %w", err)
 	}
 
-	// Always teardown, even if readiness or chat fails. teardown swallows
-	// errors past a Fprintln so the original error wins.
+	// Callers always teardown, even if what they do with the agent fails.
+	// teardown swallows errors past a Fprintln so the original error wins.
 	teardown := func() {
 		fmt.Fprintln(out, "→ Stopping containers...")
 		downCmd := exec.Command(downArgv[0], downArgv[1:]...)
@@ -319,11 +342,9 @@ func runWithChat(out io.Writer, projectDir, agentName, frameworkName string, ren
 			fmt.Fprintf(out, "warning: docker compose down failed: %v\n", derr)
 		}
 	}
-	defer teardown()
 
 	// Trap SIGINT/SIGTERM so Ctrl+C during the readiness wait still
-	// triggers teardown. Once chat starts, bubbletea owns the terminal
-	// and handles Ctrl+C internally; the deferred teardown still runs.
+	// triggers teardown.
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	defer signal.Stop(sigCh)
@@ -340,14 +361,11 @@ func runWithChat(out io.Writer, projectDir, agentName, frameworkName string, ren
 
 	fmt.Fprintf(out, "→ Waiting for agent at %s (timeout %s)...\n", agentReadinessURL, agentReadinessTimeout)
 	if err := waitForHTTPReady(waitCtx, agentReadinessURL, agentReadinessTimeout, 1*time.Second, nil); err != nil {
-		return fmt.Errorf("agent did not become ready: %w", err)
+		teardown()
+		return nil, fmt.Errorf("agent did not become ready: %w", err)
 	}
 	fmt.Fprintf(out, "✓ Agent ready at %s\n", agentReadinessURL)
-
-	if err := chat.LaunchA2A(context.Background(), agentName, agentReadinessURL, false); err != nil {
-		return fmt.Errorf("chat: %w", err)
-	}
-	return nil
+	return teardown, nil
 }
 
 // composeUpDetachedArgs takes a rendered `docker compose ... up` argv and
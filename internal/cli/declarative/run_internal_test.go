@@ -9,6 +9,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/a2a"
 )
 
 func TestRun_RemoteOnlyMCPProject_Errors(t *testing.T) {
@@ -29,7 +31,7 @@ spec:
 `), 0o644))
 
 	var buf bytes.Buffer
-	err := runProject(context.Background(), &buf, dir, nil, true /*dryRun*/, false, false, false, false)
+	err := runProject(context.Background(), &buf, dir, nil, true /*dryRun*/, false, false, false, false, a2a.Options{})
 	require.Error(t, err, "remote-only mcp.yaml must yield a Run-B error")
 	assert.Contains(t, err.Error(), "remote MCPServer")
 	assert.Contains(t, err.Error(), "https://example.com/mcp")
@@ -0,0 +1,80 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+// maxRuntimeSecretBytes matches the server's limit on a secret value.
+const maxRuntimeSecretBytes = 64 << 10
+
+// NewRuntimeCmd returns the "runtime" command group.
+func NewRuntimeCmd(deps cliruntime.Deps) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     cliruntime.CommandRuntime,
+		Aliases: []string{"runtimes"},
+		Short:   "Manage runtime credentials",
+	}
+	cmd.AddCommand(newRuntimeSetSecretCmd(deps))
+	return cmd
+}
+
+func newRuntimeSetSecretCmd(deps cliruntime.Deps) *cobra.Command {
+	var namespace string
+	cmd := &cobra.Command{
+		Use:   "set-secret NAME KEY",
+		Short: "Store a credential in a runtime's config, read from stdin",
+		Long: `Store a credential as spec.config.KEY of a Runtime. The value is read
+from stdin, so it stays out of shell history and manifests; one trailing
+newline is dropped.
+
+The registry encrypts the value before storing it and shows it as REDACTED
+when the Runtime is read. Applying the Runtime with the REDACTED
+placeholder keeps the stored value. Requires a registry started with a
+secrets master key.`,
+		Example: `  arctl runtime set-secret prod-cluster kubeconfig < ~/.kube/prod.yaml
+  vault read -field=token secret/gateway | arctl runtime set-secret gateway apiToken --namespace team-a`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRuntimeSetSecret(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout(), deps, namespace, args[0], args[1])
+		},
+	}
+	cmd.Flags().StringVar(&namespace, "namespace", v1alpha1.DefaultNamespace, "Namespace of the runtime")
+	return cmd
+}
+
+func runRuntimeSetSecret(ctx context.Context, in io.Reader, out io.Writer, deps cliruntime.Deps, namespace, name, key string) error {
+	if deps.Runtime == nil {
+		return errRegistryRuntimeNotConfigured
+	}
+	data, err := io.ReadAll(io.LimitReader(in, maxRuntimeSecretBytes+1))
+	if err != nil {
+		return fmt.Errorf("reading secret from stdin: %w", err)
+	}
+	if len(data) > maxRuntimeSecretBytes {
+		return fmt.Errorf("secret is larger than %d bytes", maxRuntimeSecretBytes)
+	}
+	value := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	if value == "" {
+		return errors.New("no secret on stdin")
+	}
+	c, err := deps.Runtime.RegistryClient(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving registry client: %w", err)
+	}
+	res, err := c.SetRuntimeSecret(ctx, namespace, name, key, value)
+	if err != nil {
+		return fmt.Errorf("storing secret %s of runtime %s: %w", key, name, err)
+	}
+	fmt.Fprintf(out, "Stored secret %s of runtime %s/%s (generation %d).\n", res.Key, res.Namespace, res.Name, res.Generation)
+	return nil
+}
@@ -0,0 +1,45 @@
+package declarative_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative"
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+func TestRuntimeSetSecret_ReadsValueFromStdin(t *testing.T) {
+	var got arv0.RuntimeSecretInput
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		require.Equal(t, "/v0/runtimes/prod-k8s/secrets/kubeconfig?namespace=team-a", r.URL.String())
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(arv0.RuntimeSecret{Namespace: "team-a", Name: "prod-k8s", Key: "kubeconfig", Generation: 4})
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	cmd := declarative.NewRuntimeCmd(declarativeTestDeps(client.NewClient(srv.URL, "")))
+	cmd.SetIn(strings.NewReader("apiVersion: v1\nkind: Config\n"))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"set-secret", "prod-k8s", "kubeconfig", "--namespace", "team-a"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, "apiVersion: v1\nkind: Config", got.Value)
+	require.Equal(t, "Stored secret kubeconfig of runtime team-a/prod-k8s (generation 4).\n", out.String())
+}
+
+func TestRuntimeSetSecret_EmptyStdinFails(t *testing.T) {
+	cmd := declarative.NewRuntimeCmd(declarativeTestDeps(client.NewClient("http://127.0.0.1:0", "")))
+	cmd.SetIn(strings.NewReader("\n"))
+	cmd.SetArgs([]string{"set-secret", "prod-k8s", "kubeconfig"})
+	require.ErrorContains(t, cmd.Execute(), "no secret on stdin")
+}
@@ -0,0 +1,151 @@
+package declarative
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// agentSecretsLookup returns the secrets an Agent version declares. A
+// non-nil error means the Agent couldn't be resolved; the check is skipped
+// and the server reports the unresolved targetRef instead.
+type agentSecretsLookup func(namespace, name, tag string) ([]v1alpha1.AgentSecret, error)
+
+// secretPrompter asks for one secret value with masked input. Optional
+// secrets may come back empty.
+type secretPrompter func(secret v1alpha1.AgentSecret) (string, error)
+
+// registryAgentSecrets looks Agents up on the registry, falling back to the
+// "latest" tag when the targetRef doesn't pin one.
+func registryAgentSecrets(ctx context.Context, c *client.Client) agentSecretsLookup {
+	return func(namespace, name, tag string) ([]v1alpha1.AgentSecret, error) {
+		if tag == "" {
+			tag = "latest"
+		}
+		obj, err := c.Get(ctx, v1alpha1.KindAgent, namespace, name, tag)
+		if err != nil {
+			return nil, err
+		}
+		var spec v1alpha1.AgentSpec
+		if err := json.Unmarshal(obj.Spec, &spec); err != nil {
+			return nil, fmt.Errorf("decoding agent %s: %w", name, err)
+		}
+		return spec.Secrets, nil
+	}
+}
+
+// fillDeploymentSecrets checks every Agent Deployment in data against the
+// secrets its target declares. Secrets already set in spec.env (inline or as
+// a secretRef), or inherited from the Runtime's deploymentDefaults, are left
+// alone. Missing ones are asked for through prompt
+// and written into spec.env; with a nil prompt, missing required secrets
+// are an error. Agents defined in the same stream take precedence over the
+// registry copy so a first-time apply of agent + deployment works.
+//
+// Returns data unchanged when nothing was filled in.
+func fillDeploymentSecrets(data []byte, lookup agentSecretsLookup, defaults runtimeDefaultsLookup, prompt secretPrompter, out io.Writer) ([]byte, error) {
+	docs, err := splitYAMLDocs(data)
+	if err != nil {
+		return nil, err
+	}
+	defaultsFor := deploymentDefaultsResolver(docs, defaults)
+
+	local := map[string][]v1alpha1.AgentSecret{}
+	for _, root := range mappingRoots(docs) {
+		if scalarValue(root, "kind") != v1alpha1.KindAgent {
+			continue
+		}
+		var agent v1alpha1.Agent
+		if err := root.Decode(&agent); err != nil {
+			continue
+		}
+		local[agentKey(agent.Metadata.Namespace, agent.Metadata.Name, agent.Metadata.Tag)] = agent.Spec.Secrets
+	}
+
+	var filled bool
+	for _, root := range mappingRoots(docs) {
+		if scalarValue(root, "kind") != v1alpha1.KindDeployment {
+			continue
+		}
+		var dep v1alpha1.Deployment
+		if err := root.Decode(&dep); err != nil || dep.Spec.TargetRef.Kind != v1alpha1.KindAgent {
+			continue
+		}
+		ref := dep.Spec.TargetRef
+		if ref.Namespace == "" {
+			ref.Namespace = dep.Metadata.Namespace
+		}
+		secrets, ok := local[agentKey(ref.Namespace, ref.Name, ref.Tag)]
+		if !ok {
+			secrets, err = lookup(ref.Namespace, ref.Name, ref.Tag)
+			if err != nil {
+				continue
+			}
+		}
+
+		env := v1alpha1.MergeDeploymentDefaults(dep.Spec, defaultsFor(&dep)).Env
+		var missing []string
+		for _, secret := range secrets {
+			if env[secret.Name] != "" {
+				continue
+			}
+			if prompt == nil {
+				if secret.Required {
+					missing = append(missing, secret.Name)
+				}
+				continue
+			}
+			if secret.Required {
+				fmt.Fprintf(out, "Deployment %s needs secret %s\n", dep.Metadata.Name, secret.Name)
+			}
+			value, err := prompt(secret)
+			if err != nil {
+				return nil, fmt.Errorf("deployment %s: secret %s: %w", dep.Metadata.Name, secret.Name, err)
+			}
+			if value == "" {
+				continue
+			}
+			spec := findOrCreateMappingChild(root, "spec")
+			upsertLabel(findOrCreateMappingChild(spec, "env"), secret.Name, value)
+			filled = true
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("deployment %s: missing required secrets: %s (set them in spec.env or run interactively)",
+				dep.Metadata.Name, strings.Join(missing, ", "))
+		}
+	}
+
+	if !filled {
+		return data, nil
+	}
+	return marshalYAMLDocs(docs)
+}
+
+// mappingRoots returns the top-level mapping node of each document.
+func mappingRoots(docs []*yaml.Node) []*yaml.Node {
+	var roots []*yaml.Node
+	for _, doc := range docs {
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		roots = append(roots, doc.Content[0])
+	}
+	return roots
+}
+
+func agentKey(namespace, name, tag string) string {
+	if namespace == "" {
+		namespace = v1alpha1.DefaultNamespace
+	}
+	if tag == "" {
+		tag = "latest"
+	}
+	return namespace + "/" + name + "@" + tag
+}
@@ -0,0 +1,109 @@
+package declarative
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+const secretsDeploymentYAML = `apiVersion: ar.dev/v1alpha1
+kind: Deployment
+metadata:
+  name: bot-prod
+spec:
+  targetRef:
+    kind: Agent
+    name: bot
+    tag: "1.0.0"
+  runtimeRef:
+    kind: Runtime
+    name: k8s
+  env:
+    SLACK_TOKEN: secretRef:bot/slack
+`
+
+func TestFillDeploymentSecrets(t *testing.T) {
+	declared := []v1alpha1.AgentSecret{
+		{Name: "OPENAI_API_KEY", Description: "model key", Required: true},
+		{Name: "SLACK_TOKEN", Required: true},
+		{Name: "OPTIONAL_TOKEN"},
+	}
+	registry := func(namespace, name, tag string) ([]v1alpha1.AgentSecret, error) {
+		require.Equal(t, "bot", name)
+		require.Equal(t, "1.0.0", tag)
+		return declared, nil
+	}
+
+	t.Run("prompts for missing secrets only", func(t *testing.T) {
+		var asked []string
+		prompt := func(secret v1alpha1.AgentSecret) (string, error) {
+			asked = append(asked, secret.Name)
+			if secret.Required {
+				return "sk-test", nil
+			}
+			return "", nil
+		}
+		out, err := fillDeploymentSecrets([]byte(secretsDeploymentYAML), registry, nil, prompt, io.Discard)
+		require.NoError(t, err)
+		require.Equal(t, []string{"OPENAI_API_KEY", "OPTIONAL_TOKEN"}, asked)
+
+		var dep v1alpha1.Deployment
+		docs, err := splitYAMLDocs(out)
+		require.NoError(t, err)
+		require.NoError(t, docs[0].Decode(&dep))
+		require.Equal(t, map[string]string{
+			"OPENAI_API_KEY": "sk-test",
+			"SLACK_TOKEN":    "secretRef:bot/slack",
+		}, dep.Spec.Env)
+	})
+
+	t.Run("non-interactive reports missing required secrets", func(t *testing.T) {
+		_, err := fillDeploymentSecrets([]byte(secretsDeploymentYAML), registry, nil, nil, io.Discard)
+		require.ErrorContains(t, err, "deployment bot-prod: missing required secrets: OPENAI_API_KEY")
+	})
+
+	t.Run("agent in the same stream wins over the registry", func(t *testing.T) {
+		data := `apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  name: bot
+  tag: "1.0.0"
+spec:
+  secrets:
+    - name: SLACK_TOKEN
+      required: true
+---
+` + secretsDeploymentYAML
+		lookup := func(namespace, name, tag string) ([]v1alpha1.AgentSecret, error) {
+			t.Fatalf("registry lookup should not run for %s", name)
+			return nil, nil
+		}
+		out, err := fillDeploymentSecrets([]byte(data), lookup, nil, nil, io.Discard)
+		require.NoError(t, err)
+		require.Equal(t, data, string(out))
+	})
+
+	t.Run("secrets inherited from the runtime count as set", func(t *testing.T) {
+		defaults := func(namespace, name string) (*v1alpha1.DeploymentDefaults, error) {
+			require.Equal(t, v1alpha1.DefaultNamespace, namespace)
+			require.Equal(t, "k8s", name)
+			return &v1alpha1.DeploymentDefaults{Env: map[string]string{"OPENAI_API_KEY": "secretRef:platform/openai"}}, nil
+		}
+		out, err := fillDeploymentSecrets([]byte(secretsDeploymentYAML), registry, defaults, nil, io.Discard)
+		require.NoError(t, err)
+		require.Equal(t, secretsDeploymentYAML, string(out))
+	})
+
+	t.Run("unresolvable agent is left to the server", func(t *testing.T) {
+		lookup := func(namespace, name, tag string) ([]v1alpha1.AgentSecret, error) {
+			return nil, errors.New("not found")
+		}
+		out, err := fillDeploymentSecrets([]byte(secretsDeploymentYAML), lookup, nil, nil, io.Discard)
+		require.NoError(t, err)
+		require.Equal(t, secretsDeploymentYAML, string(out))
+	})
+}
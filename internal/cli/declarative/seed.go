@@ -0,0 +1,109 @@
+package declarative
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+// seedProfiles are the curated demo data sets. Each file is an import
+// stream whose first line is a "# description" comment; the file name
+// without .yaml is the profile name.
+//
+//go:embed seeds/*.yaml
+var seedProfiles embed.FS
+
+// seedProfile is one embedded profile.
+type seedProfile struct {
+	Name        string
+	Description string
+	Data        []byte
+}
+
+// listSeedProfiles returns every embedded profile, ordered by name.
+func listSeedProfiles() ([]seedProfile, error) {
+	files, err := fs.Glob(seedProfiles, "seeds/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	profiles := make([]seedProfile, 0, len(files))
+	for _, file := range files {
+		data, err := seedProfiles.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		description, _, _ := bufio.NewReader(bytes.NewReader(data)).ReadLine()
+		profiles = append(profiles, seedProfile{
+			Name:        strings.TrimSuffix(path.Base(file), ".yaml"),
+			Description: strings.TrimSpace(strings.TrimPrefix(string(description), "#")),
+			Data:        data,
+		})
+	}
+	return profiles, nil
+}
+
+func newRegistrySeedCmd(deps cliruntime.Deps) *cobra.Command {
+	var (
+		profile string
+		list    bool
+		dryRun  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "seed --profile NAME",
+		Short: "Load a curated demo data set",
+		Long: `Seed applies one of the demo profiles built into arctl: a themed set of
+MCP servers, agents, skills and prompts in their own namespace, so a demo
+registry needs no imports from live upstream sources. Profiles are applied
+like "arctl registry import", so seeding twice leaves the registry
+unchanged.
+
+Use --list to see the available profiles. To load your own bundle, write it
+in the "arctl registry export" format and use "arctl registry import".`,
+		Example: `  arctl registry seed --list
+  arctl registry seed --profile demo-finance
+  arctl registry seed --profile demo-devtools --dry-run`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			profiles, err := listSeedProfiles()
+			if err != nil {
+				return fmt.Errorf("read seed profiles: %w", err)
+			}
+			if list {
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "PROFILE\tDESCRIPTION")
+				for _, p := range profiles {
+					fmt.Fprintf(w, "%s\t%s\n", p.Name, p.Description)
+				}
+				return w.Flush()
+			}
+			if profile == "" {
+				return fmt.Errorf("--profile is required; use --list to see the available profiles")
+			}
+			i := slices.IndexFunc(profiles, func(p seedProfile) bool { return p.Name == profile })
+			if i < 0 {
+				names := make([]string, 0, len(profiles))
+				for _, p := range profiles {
+					names = append(names, p.Name)
+				}
+				return fmt.Errorf("unknown seed profile %q (available: %s)", profile, strings.Join(names, ", "))
+			}
+			return runRegistryImport(cmd, deps, profiles[i].Data, importOpts{DryRun: dryRun})
+		},
+	}
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile to load")
+	cmd.Flags().BoolVar(&list, "list", false, "List the available profiles")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate and simulate without mutating state")
+	return cmd
+}
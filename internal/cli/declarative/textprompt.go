@@ -10,6 +10,8 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 )
 
 // errTooManyAttempts is returned by the bufio fallback after 3 invalid
@@ -88,6 +90,41 @@ func promptTextTUI(label, defaultValue string, validate validator) (string, erro
 	return strings.TrimSpace(res.input.Value()), nil
 }
 
+// promptSecret asks for a declared agent secret in a TUI text input with
+// masked echo. Required secrets must be non-empty; optional ones may be
+// skipped with an empty value. Only called when stdin is a TTY — there is
+// no bufio fallback, since it would echo the value.
+func promptSecret(secret v1alpha1.AgentSecret) (string, error) {
+	label := secret.Name
+	if secret.Description != "" {
+		label += " (" + secret.Description + ")"
+	}
+	if !secret.Required {
+		label += " [optional, enter to skip]"
+	}
+	var validate validator
+	if secret.Required {
+		validate = func(s string) error {
+			if s == "" {
+				return errors.New("a value is required")
+			}
+			return nil
+		}
+	}
+	m := newTextinputModel(label, "", validate)
+	m.input.EchoMode = textinput.EchoPassword
+	m.input.EchoCharacter = '•'
+	final, err := tea.NewProgram(m).Run()
+	if err != nil {
+		return "", fmt.Errorf("secret prompt: %w", err)
+	}
+	res := final.(textinputModel)
+	if res.cancelled {
+		return "", errTextPromptCancelled
+	}
+	return strings.TrimSpace(res.input.Value()), nil
+}
+
 type textinputModel struct {
 	input     textinput.Model
 	label     string
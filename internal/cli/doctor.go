@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentregistry-dev/agentregistry/internal/cli/common/docker"
+	cliruntime "github.com/agentregistry-dev/agentregistry/pkg/cli/runtime"
+)
+
+// DoctorCheck is one host prerequisite `arctl doctor` probes. Run returns a
+// short detail (usually a version) on success.
+type DoctorCheck struct {
+	Name string
+	// Required checks fail the command; optional ones only warn, since
+	// they gate a subset of features.
+	Required bool
+	// Hint tells the user how to fix a failed check.
+	Hint string
+	Run  func() (string, error)
+}
+
+// DefaultDoctorChecks are the prerequisites for running agents and MCP
+// servers on the Local runtime.
+func DefaultDoctorChecks() []DoctorCheck {
+	return []DoctorCheck{
+		{
+			Name:     "docker",
+			Required: true,
+			Hint:     "install Docker and start the daemon",
+			Run:      docker.ServerVersion,
+		},
+		{
+			Name:     "docker compose",
+			Required: true,
+			Hint:     "install the Docker Compose plugin",
+			Run:      docker.ComposeVersion,
+		},
+		{
+			Name: "NVIDIA container toolkit",
+			Hint: "needed only for agents that set spec.resources.gpus; install the toolkit and run `nvidia-ctk runtime configure --runtime=docker`",
+			Run:  docker.NVIDIARuntime,
+		},
+	}
+}
+
+// NewDoctorCommand returns `arctl doctor`, which runs checks and reports
+// each one. A nil checks runs DefaultDoctorChecks.
+func NewDoctorCommand(checks []DoctorCheck) *cobra.Command {
+	return &cobra.Command{
+		Use:   cliruntime.CommandDoctor,
+		Short: "Check the local environment for runtime prerequisites",
+		Long: `Doctor checks that this machine can run agents and MCP servers on the
+Local runtime: Docker and Docker Compose, and the NVIDIA container toolkit
+that agents requesting GPUs need. It exits non-zero when a required check
+fails; a missing toolkit is only a warning.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if checks == nil {
+				checks = DefaultDoctorChecks()
+			}
+			out := cmd.OutOrStdout()
+			failed := 0
+			for _, check := range checks {
+				detail, err := check.Run()
+				switch {
+				case err == nil:
+					fmt.Fprintf(out, "[ok]   %s: %s\n", check.Name, detail)
+				case check.Required:
+					failed++
+					fmt.Fprintf(out, "[fail] %s: %v\n       %s\n", check.Name, err, check.Hint)
+				default:
+					fmt.Fprintf(out, "[warn] %s: %v\n       %s\n", check.Name, err, check.Hint)
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d required check(s) failed", failed)
+			}
+			return nil
+		},
+	}
+}
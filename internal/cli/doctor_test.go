@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDoctorCommand(t *testing.T) {
+	ok := func() (string, error) { return "27.1.1", nil }
+	missing := func() (string, error) { return "", errors.New("not found") }
+
+	run := func(checks []DoctorCheck) (string, error) {
+		cmd := NewDoctorCommand(checks)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(nil)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run([]DoctorCheck{
+		{Name: "docker", Required: true, Run: ok},
+		{Name: "NVIDIA container toolkit", Hint: "install the toolkit", Run: missing},
+	})
+	if err != nil {
+		t.Fatalf("optional failure should not fail doctor: %v", err)
+	}
+	for _, want := range []string{"[ok]   docker: 27.1.1", "[warn] NVIDIA container toolkit: not found", "install the toolkit"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = run([]DoctorCheck{{Name: "docker", Required: true, Hint: "install Docker", Run: missing}})
+	if err == nil {
+		t.Fatal("required failure should fail doctor")
+	}
+	if !strings.Contains(out, "[fail] docker: not found") {
+		t.Errorf("output missing failed check:\n%s", out)
+	}
+}
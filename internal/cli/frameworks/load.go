@@ -6,8 +6,9 @@ import (
 
 // LoadOpts configures top-level framework loading.
 type LoadOpts struct {
-	// StageDir is where embedded frameworks are written. Defaults to a tmp dir
-	// when empty. arctl normally creates and cleans this on each run.
+	// StageDir is where embedded frameworks are written. When empty, the
+	// embedded source is skipped. arctl stages them in a workspace (see
+	// package workspace) that is swept once the command has exited.
 	StageDir string
 	// UserDir is the user-level framework directory (typically UserFrameworksDir()).
 	// When empty, the user source is skipped.
@@ -39,6 +39,9 @@ type ListOpts struct {
 	// Deployment ListFunc translates these to the server filter; only the
 	// Deployment kind honors this — other kinds ignore it.
 	Origin string
+	// Sort is the server-side list order (?sort=): empty or "name",
+	// "updatedAt", or "status" for Deployments.
+	Sort string
 }
 
 type ListFunc func(context.Context, *client.Client, ListOpts) ([]any, error)
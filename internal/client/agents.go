@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// AgentCard fetches the A2A agent card of a deployed agent via
+// GET /v0/agents/{name}/.well-known/agent-card.json. deployment picks one
+// of the agent's Deployments; empty lets the server choose a ready one.
+func (c *Client) AgentCard(ctx context.Context, namespace, name, deployment string) (*server.AgentCard, error) {
+	q := url.Values{}
+	if namespace != "" && namespace != v1alpha1.DefaultNamespace {
+		q.Set("namespace", namespace)
+	}
+	if deployment != "" {
+		q.Set("deployment", deployment)
+	}
+	path := fmt.Sprintf("/%s/%s/.well-known/agent-card.json",
+		v1alpha1.PluralFor(v1alpha1.KindAgent),
+		url.PathEscape(name))
+	if enc := q.Encode(); enc != "" {
+		path += "?" + enc
+	}
+	req, err := c.newRequest(http.MethodGet, path)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out server.AgentCard
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+// CreateAPIKey creates a scoped API key via POST /v0/apikeys. The returned
+// token is only ever shown in this response.
+func (c *Client) CreateAPIKey(ctx context.Context, in arv0.APIKeyInput) (*arv0.APIKeyCreated, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return nil, fmt.Errorf("encode API key: %w", err)
+	}
+	req, err := c.newRequestWithBody(http.MethodPost, "/apikeys", bytes.NewReader(body), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.APIKeyCreated
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAPIKeys returns the caller's API keys, or every key for registry
+// admins, from GET /v0/apikeys.
+func (c *Client) ListAPIKeys(ctx context.Context) ([]arv0.APIKey, error) {
+	req, err := c.newRequest(http.MethodGet, "/apikeys")
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.APIKeyList
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return out.Keys, nil
+}
+
+// RevokeAPIKey revokes an API key via DELETE /v0/apikeys/{id}.
+func (c *Client) RevokeAPIKey(ctx context.Context, id string) (*arv0.APIKey, error) {
+	req, err := c.newRequest(http.MethodDelete, "/apikeys/"+url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.APIKey
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -12,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
 	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 )
@@ -47,11 +49,9 @@ func NewClient(baseURL, token string) *Client {
 	}
 	baseURL = ensureV0Suffix(baseURL)
 	return &Client{
-		BaseURL: baseURL,
-		token:   token,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		BaseURL:    baseURL,
+		token:      token,
+		httpClient: httpclient.New(30 * time.Second),
 	}
 }
 
@@ -107,6 +107,18 @@ func (c *Client) doJSON(req *http.Request, out any) error {
 		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// checkStatus maps a non-2xx response to ErrNotFound or an error carrying the
+// server's message.
+func checkStatus(resp *http.Response) error {
 	if resp.StatusCode == http.StatusNotFound {
 		return ErrNotFound
 	}
@@ -117,10 +129,7 @@ func (c *Client) doJSON(req *http.Request, out any) error {
 		}
 		return fmt.Errorf("unexpected status: %s, %s", resp.Status, string(errBody))
 	}
-	if out == nil {
-		return nil
-	}
-	return json.NewDecoder(resp.Body).Decode(out)
+	return nil
 }
 
 // extractAPIErrorMessage parses a Huma-style JSON error body and returns a
@@ -196,6 +205,9 @@ type ListOpts struct {
 	// covers the mutable-object latest-row case.
 	LatestOnly         bool
 	IncludeTerminating bool
+	// Sort, when set, forwards ?sort= (name, updatedAt, status on
+	// Deployments). Pages continue the sort the first page used.
+	Sort string
 }
 
 // listResponse mirrors the resource handler's list envelope shape.
@@ -249,6 +261,54 @@ func (c *Client) getRaw(ctx context.Context, path string) (*v1alpha1.RawObject,
 	return &out, nil
 }
 
+// PutReadme uploads content as the Markdown README of the artifact at
+// (kind, namespace, name, tag) via PUT /v0/{plural}/{name}/{tag}/readme.
+func (c *Client) PutReadme(ctx context.Context, kind, namespace, name, tag string, content []byte) error {
+	path := fmt.Sprintf("/%s/%s/%s/readme%s",
+		v1alpha1.PluralFor(kind),
+		url.PathEscape(name),
+		url.PathEscape(tag),
+		namespaceQuery(namespace))
+	req, err := c.newRequestWithBody(http.MethodPut, path, bytes.NewReader(content), "text/markdown")
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	return c.doJSON(req, nil)
+}
+
+// streamEvents sends req and calls fn with the payload of each `data:`
+// line of the server-sent event stream it answers with.
+func (c *Client) streamEvents(req *http.Request, fn func(data []byte) error) error {
+	ctx := req.Context()
+	req.Header.Set("Accept", "text/event-stream")
+	stream := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := stream.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		if err := fn([]byte(data)); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("read event stream: %w", err)
+	}
+	return ctx.Err()
+}
+
 // ListTags returns every non-deleted tag row for (kind, namespace, name) by
 // GET'ing /v0/{plural}/{name}/tags. Mutable-object kinds do not expose this
 // endpoint; callers should branch on that. The endpoint is unpaginated
@@ -301,6 +361,9 @@ func (c *Client) List(ctx context.Context, kind string, opts ListOpts) ([]v1alph
 	if opts.IncludeTerminating {
 		q.Set("includeTerminating", "true")
 	}
+	if opts.Sort != "" {
+		q.Set("sort", opts.Sort)
+	}
 	if enc := q.Encode(); enc != "" {
 		base += "?" + enc
 	}
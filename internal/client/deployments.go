@@ -0,0 +1,245 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// OutdatedDeployments returns the upgrade-planning report from
+// GET /v0/deployments/outdated. minSeverity ("low", "medium", "high") drops
+// deployments below that severity; empty keeps every outdated deployment.
+func (c *Client) OutdatedDeployments(ctx context.Context, namespace, minSeverity string) (*arv0.OutdatedReport, error) {
+	q := url.Values{}
+	if namespace != "" && namespace != v1alpha1.DefaultNamespace {
+		q.Set("namespace", namespace)
+	}
+	if minSeverity != "" {
+		q.Set("minSeverity", minSeverity)
+	}
+	path := "/deployments/outdated"
+	if enc := q.Encode(); enc != "" {
+		path += "?" + enc
+	}
+	req, err := c.newRequest(http.MethodGet, path)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.OutdatedReport
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DryRunDeployment renders the runtime manifests for deployment via
+// POST /v0/deployments:dryRun without applying or storing it.
+func (c *Client) DryRunDeployment(ctx context.Context, deployment *v1alpha1.Deployment) (*arv0.DeploymentDryRun, error) {
+	body, err := json.Marshal(deployment)
+	if err != nil {
+		return nil, fmt.Errorf("encode deployment: %w", err)
+	}
+	req, err := c.newRequestWithBody(http.MethodPost, "/deployments:dryRun", bytes.NewReader(body), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.DeploymentDryRun
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// WatchDeploymentEvents streams GET /v0/deployments/{name}/events and calls
+// fn for each event until the stream ends, ctx is done or fn returns an
+// error, which WatchDeploymentEvents then returns. The stream is read
+// without the client's request timeout; bound it with ctx.
+func (c *Client) WatchDeploymentEvents(ctx context.Context, namespace, name string, fn func(arv0.DeploymentEvent) error) error {
+	path := fmt.Sprintf("/%s/%s/events%s",
+		v1alpha1.PluralFor(v1alpha1.KindDeployment),
+		url.PathEscape(name),
+		namespaceQuery(namespace))
+	req, err := c.newRequest(http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+	return c.streamEvents(req.WithContext(ctx), func(data []byte) error {
+		var event arv0.DeploymentEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("decode deployment event: %w", err)
+		}
+		return fn(event)
+	})
+}
+
+// DeploymentNotes returns a Deployment's notes, links and their history
+// from GET /v0/deployments/{name}/notes.
+func (c *Client) DeploymentNotes(ctx context.Context, namespace, name string) (*arv0.DeploymentNotes, error) {
+	req, err := c.newRequest(http.MethodGet, deploymentNotesPath(namespace, name))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.DeploymentNotes
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PatchDeploymentNotes saves a new revision of a Deployment's notes via
+// PATCH /v0/deployments/{name}/notes and returns the result.
+func (c *Client) PatchDeploymentNotes(ctx context.Context, namespace, name string, patch arv0.DeploymentNotesPatch) (*arv0.DeploymentNotes, error) {
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("encode deployment notes: %w", err)
+	}
+	req, err := c.newRequestWithBody(http.MethodPatch, deploymentNotesPath(namespace, name), bytes.NewReader(body), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.DeploymentNotes
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func deploymentNotesPath(namespace, name string) string {
+	return fmt.Sprintf("/%s/%s/notes%s",
+		v1alpha1.PluralFor(v1alpha1.KindDeployment),
+		url.PathEscape(name),
+		namespaceQuery(namespace))
+}
+
+// CreateDeploymentShare creates an expiring share link for a Deployment via
+// POST /v0/deployments/{name}/share. The token in the result is returned
+// only this once.
+func (c *Client) CreateDeploymentShare(ctx context.Context, namespace, name string, in arv0.DeploymentShareInput) (*arv0.DeploymentShareCreated, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return nil, fmt.Errorf("encode deployment share: %w", err)
+	}
+	req, err := c.newRequestWithBody(http.MethodPost, deploymentSubPath(namespace, name, "/share"), bytes.NewReader(body), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.DeploymentShareCreated
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeploymentShares lists a Deployment's share links, newest first, from
+// GET /v0/deployments/{name}/shares.
+func (c *Client) DeploymentShares(ctx context.Context, namespace, name string) ([]arv0.DeploymentShare, error) {
+	req, err := c.newRequest(http.MethodGet, deploymentSubPath(namespace, name, "/shares"))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.DeploymentShareList
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return out.Shares, nil
+}
+
+// RevokeDeploymentShare revokes a Deployment's share link via
+// DELETE /v0/deployments/{name}/shares/{id}.
+func (c *Client) RevokeDeploymentShare(ctx context.Context, namespace, name, id string) (*arv0.DeploymentShare, error) {
+	req, err := c.newRequest(http.MethodDelete, deploymentSubPath(namespace, name, "/shares/"+url.PathEscape(id)))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.DeploymentShare
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func deploymentSubPath(namespace, name, sub string) string {
+	return fmt.Sprintf("/%s/%s%s%s",
+		v1alpha1.PluralFor(v1alpha1.KindDeployment),
+		url.PathEscape(name),
+		sub,
+		namespaceQuery(namespace))
+}
+
+// DeploymentLogsOpts are the parameters of GET /v0/deployments/{name}/logs.
+type DeploymentLogsOpts struct {
+	Namespace string
+	// TailLines caps the lines before the live tail. Zero uses the server
+	// ceiling.
+	TailLines int
+	// Since is an RFC3339 time or a duration back from now, e.g. "1h".
+	Since string
+}
+
+// DeploymentLogs returns a Deployment's retained and current log lines from
+// GET /v0/deployments/{name}/logs.
+func (c *Client) DeploymentLogs(ctx context.Context, name string, opts DeploymentLogsOpts) (*arv0.DeploymentLogs, error) {
+	req, err := c.newRequest(http.MethodGet, deploymentLogsPath(name, opts, false))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.DeploymentLogs
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FollowDeploymentLogs streams GET /v0/deployments/{name}/logs?follow=true
+// and calls fn for each line until the stream ends, ctx is done or fn
+// returns an error, which FollowDeploymentLogs then returns. Like
+// WatchDeploymentEvents it is read without the request timeout.
+func (c *Client) FollowDeploymentLogs(ctx context.Context, name string, opts DeploymentLogsOpts, fn func(arv0.DeploymentLogLine) error) error {
+	req, err := c.newRequest(http.MethodGet, deploymentLogsPath(name, opts, true))
+	if err != nil {
+		return err
+	}
+	return c.streamEvents(req.WithContext(ctx), func(data []byte) error {
+		var line arv0.DeploymentLogLine
+		if err := json.Unmarshal(data, &line); err != nil {
+			return fmt.Errorf("decode log line: %w", err)
+		}
+		return fn(line)
+	})
+}
+
+func deploymentLogsPath(name string, opts DeploymentLogsOpts, follow bool) string {
+	q := url.Values{}
+	if opts.Namespace != "" && opts.Namespace != v1alpha1.DefaultNamespace {
+		q.Set("namespace", opts.Namespace)
+	}
+	if opts.TailLines > 0 {
+		q.Set("tailLines", strconv.Itoa(opts.TailLines))
+	}
+	if opts.Since != "" {
+		q.Set("since", opts.Since)
+	}
+	if follow {
+		q.Set("follow", "true")
+	}
+	path := fmt.Sprintf("/%s/%s/logs", v1alpha1.PluralFor(v1alpha1.KindDeployment), url.PathEscape(name))
+	if enc := q.Encode(); enc != "" {
+		path += "?" + enc
+	}
+	return path
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// GetMCPServerBundle downloads the OCI image layout tarball for the MCPServer
+// at (namespace, name, tag) from GET /v0/mcpservers/{name}/{tag}/bundle.
+func (c *Client) GetMCPServerBundle(ctx context.Context, namespace, name, tag string) ([]byte, error) {
+	path := fmt.Sprintf("/%s/%s/%s/bundle%s",
+		v1alpha1.PluralFor(v1alpha1.KindMCPServer),
+		url.PathEscape(name),
+		url.PathEscape(tag),
+		namespaceQuery(namespace))
+	req, err := c.newRequest(http.MethodGet, path)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// CapabilityDiff compares the tools of two MCPServer versions via
+// GET /v0/mcpservers/{name}/capability-diff.
+func (c *Client) CapabilityDiff(ctx context.Context, namespace, name, from, to string) (*arv0.CapabilityDiff, error) {
+	q := url.Values{"from": {from}, "to": {to}}
+	if namespace != "" && namespace != v1alpha1.DefaultNamespace {
+		q.Set("namespace", namespace)
+	}
+	path := fmt.Sprintf("/%s/%s/capability-diff?%s",
+		v1alpha1.PluralFor(v1alpha1.KindMCPServer),
+		url.PathEscape(name),
+		q.Encode())
+	req, err := c.newRequest(http.MethodGet, path)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.CapabilityDiff
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MCPServerInstall renders the configuration an MCP client (claude, cursor
+// or vscode) needs to use an MCPServer version via
+// GET /v0/mcpservers/{name}/install. An empty tag renders latest.
+func (c *Client) MCPServerInstall(ctx context.Context, namespace, name, tag, mcpClient string) (*arv0.MCPServerInstall, error) {
+	q := url.Values{"client": {mcpClient}}
+	if tag != "" {
+		q.Set("tag", tag)
+	}
+	if namespace != "" && namespace != v1alpha1.DefaultNamespace {
+		q.Set("namespace", namespace)
+	}
+	path := fmt.Sprintf("/%s/%s/install?%s",
+		v1alpha1.PluralFor(v1alpha1.KindMCPServer),
+		url.PathEscape(name),
+		q.Encode())
+	req, err := c.newRequest(http.MethodGet, path)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.MCPServerInstall
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// PromptEvaluations returns a Prompt's evaluation scores, newest first,
+// from GET /v0/prompts/{name}/evaluations. tag limits them to one tag
+// when non-empty.
+func (c *Client) PromptEvaluations(ctx context.Context, namespace, name, tag string, limit int) ([]arv0.PromptEvalRun, error) {
+	q := url.Values{}
+	if namespace != "" {
+		q.Set("namespace", namespace)
+	}
+	if tag != "" {
+		q.Set("tag", tag)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	path := fmt.Sprintf("/%s/%s/evaluations", v1alpha1.PluralFor(v1alpha1.KindPrompt), url.PathEscape(name))
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	req, err := c.newRequest(http.MethodGet, path)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.PromptEvalRunList
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return out.Evaluations, nil
+}
+
+// EvaluatePrompt scores a Prompt tag against the registry's evaluation
+// model via POST /v0/prompts/{name}/{tag}/evaluations and returns the
+// recorded result.
+func (c *Client) EvaluatePrompt(ctx context.Context, namespace, name, tag string) (*arv0.PromptEvalRun, error) {
+	path := fmt.Sprintf("/%s/%s/%s/evaluations%s",
+		v1alpha1.PluralFor(v1alpha1.KindPrompt),
+		url.PathEscape(name),
+		url.PathEscape(tag),
+		namespaceQuery(namespace))
+	req, err := c.newRequest(http.MethodPost, path)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	// The server answers once every case has run, which can outlast the
+	// client's timeout; ctx bounds the call instead.
+	resp, err := (&http.Client{Transport: c.httpClient.Transport}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	var out arv0.PromptEvalRun
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode prompt evaluation: %w", err)
+	}
+	return &out, nil
+}
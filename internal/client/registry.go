@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+// Export downloads the multi-document YAML export from GET /v0/export.
+// An empty namespace exports every namespace.
+func (c *Client) Export(ctx context.Context, namespace string) ([]byte, error) {
+	path := "/export"
+	if namespace != "" {
+		path += "?namespace=" + url.QueryEscape(namespace)
+	}
+	req, err := c.newRequest(http.MethodGet, path)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// CollectVersions runs the server's version garbage collection through
+// POST /v0/admin/gc. namespace limits it to one namespace; dryRun only
+// reports what would be deleted.
+func (c *Client) CollectVersions(ctx context.Context, namespace string, dryRun bool) (*arv0.GCReport, error) {
+	q := url.Values{}
+	if namespace != "" {
+		q.Set("namespace", namespace)
+	}
+	if dryRun {
+		q.Set("dryRun", "true")
+	}
+	path := "/admin/gc"
+	if enc := q.Encode(); enc != "" {
+		path += "?" + enc
+	}
+	req, err := c.newRequest(http.MethodPost, path)
+	if err != nil {
+		return nil, err
+	}
+	var out arv0.GCReport
+	if err := c.doJSON(req.WithContext(ctx), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// SetRuntimeSecret stores value, encrypted, as spec.config.{key} of the
+// Runtime namespace/name via PUT /v0/runtimes/{name}/secrets/{key}.
+func (c *Client) SetRuntimeSecret(ctx context.Context, namespace, name, key, value string) (*arv0.RuntimeSecret, error) {
+	body, err := json.Marshal(arv0.RuntimeSecretInput{Value: value})
+	if err != nil {
+		return nil, fmt.Errorf("encode runtime secret: %w", err)
+	}
+	path := fmt.Sprintf("/%s/%s/secrets/%s%s",
+		v1alpha1.PluralFor(v1alpha1.KindRuntime),
+		url.PathEscape(name),
+		url.PathEscape(key),
+		namespaceQuery(namespace))
+	req, err := c.newRequestWithBody(http.MethodPut, path, bytes.NewReader(body), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.RuntimeSecret
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+// SearchOpts are the parameters of GET /v0/search. Empty Types searches
+// every artifact type; empty Namespace searches every namespace.
+type SearchOpts struct {
+	Query     string
+	Types     []string
+	Namespace string
+	Limit     int
+}
+
+// Search runs a free-text artifact search via GET /v0/search.
+func (c *Client) Search(ctx context.Context, opts SearchOpts) (*arv0.SearchResults, error) {
+	q := url.Values{"q": {opts.Query}}
+	if len(opts.Types) > 0 {
+		q.Set("types", strings.Join(opts.Types, ","))
+	}
+	if opts.Namespace != "" {
+		q.Set("namespace", opts.Namespace)
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	req, err := c.newRequest(http.MethodGet, "/search?"+q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.SearchResults
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReindexEmbeddingsOpts are the parameters of
+// POST /v0/admin/embeddings:reindex.
+type ReindexEmbeddingsOpts struct {
+	// Types are search types ("server", "agent", "skill", "prompt"). Empty
+	// reindexes every type.
+	Types     []string
+	Namespace string
+	DryRun    bool
+	Force     bool
+	// Rate caps embeddings per second. Zero uses the server default.
+	Rate float64
+}
+
+// ReindexEmbeddings runs POST /v0/admin/embeddings:reindex and calls fn
+// for each progress event until the stream ends, ctx is done or fn
+// returns an error, which ReindexEmbeddings then returns. Like
+// WatchDeploymentEvents it is read without the request timeout.
+func (c *Client) ReindexEmbeddings(ctx context.Context, opts ReindexEmbeddingsOpts, fn func(arv0.EmbeddingsReindexEvent) error) error {
+	q := url.Values{}
+	if len(opts.Types) > 0 {
+		q.Set("types", strings.Join(opts.Types, ","))
+	}
+	if opts.Namespace != "" {
+		q.Set("namespace", opts.Namespace)
+	}
+	if opts.DryRun {
+		q.Set("dryRun", "true")
+	}
+	if opts.Force {
+		q.Set("force", "true")
+	}
+	if opts.Rate > 0 {
+		q.Set("rate", strconv.FormatFloat(opts.Rate, 'f', -1, 64))
+	}
+	path := "/admin/embeddings:reindex"
+	if enc := q.Encode(); enc != "" {
+		path += "?" + enc
+	}
+	req, err := c.newRequest(http.MethodPost, path)
+	if err != nil {
+		return err
+	}
+	return c.streamEvents(req.WithContext(ctx), func(data []byte) error {
+		var event arv0.EmbeddingsReindexEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("decode reindex event: %w", err)
+		}
+		return fn(event)
+	})
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+// CreateSnapshot takes a catalog snapshot via POST /v0/snapshots.
+func (c *Client) CreateSnapshot(ctx context.Context, in arv0.CatalogSnapshotInput) (*arv0.CatalogSnapshot, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return nil, fmt.Errorf("encode snapshot: %w", err)
+	}
+	req, err := c.newRequestWithBody(http.MethodPost, "/snapshots", bytes.NewReader(body), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.CatalogSnapshot
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListSnapshots returns the catalog snapshots, newest first, from
+// GET /v0/snapshots.
+func (c *Client) ListSnapshots(ctx context.Context) ([]arv0.CatalogSnapshot, error) {
+	req, err := c.newRequest(http.MethodGet, "/snapshots")
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.CatalogSnapshotList
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return out.Snapshots, nil
+}
+
+// GetSnapshot returns a catalog snapshot from GET /v0/snapshots/{name},
+// with the artifact tags it holds when the caller is a registry admin.
+func (c *Client) GetSnapshot(ctx context.Context, name string) (*arv0.CatalogSnapshot, error) {
+	req, err := c.newRequest(http.MethodGet, "/snapshots/"+url.PathEscape(name))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	var out arv0.CatalogSnapshot
+	if err := c.doJSON(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteSnapshot deletes a catalog snapshot via DELETE /v0/snapshots/{name}.
+func (c *Client) DeleteSnapshot(ctx context.Context, name string) error {
+	req, err := c.newRequest(http.MethodDelete, "/snapshots/"+url.PathEscape(name))
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req.WithContext(ctx), nil)
+}
@@ -26,4 +26,13 @@ const (
 	// EnvMCPServersConfig is a JSON-encoded array of resolved MCP server
 	// configurations injected into the agent container at deploy time.
 	EnvMCPServersConfig = "MCP_SERVERS_CONFIG"
+
+	// EnvSubAgentsConfig is a JSON-encoded array of the agent's sub-agents,
+	// each with the A2A URL it is served at, injected at deploy time.
+	EnvSubAgentsConfig = "A2A_SUB_AGENTS_CONFIG"
+
+	// EnvFlagsURL is the registry URL that evaluates every FeatureFlag in the
+	// agent's namespace for the agent (GET /v0/flags:evaluate). Set when the
+	// agent's Runtime declares spec.registryURL.
+	EnvFlagsURL = "AGENT_REGISTRY_FLAGS_URL"
 )
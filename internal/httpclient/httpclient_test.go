@@ -0,0 +1,114 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSOptionsClientConfig(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "bad.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a cert"), 0o600))
+
+	tests := []struct {
+		name    string
+		opts    TLSOptions
+		wantNil bool
+		wantErr string
+	}{
+		{name: "zero value keeps stdlib default", opts: TLSOptions{}, wantNil: true},
+		{name: "insecure only", opts: TLSOptions{InsecureSkipVerify: true}},
+		{name: "missing CA file", opts: TLSOptions{CAFile: filepath.Join(dir, "missing.pem")}, wantErr: "read CA bundle"},
+		{name: "CA file without certificates", opts: TLSOptions{CAFile: notPEM}, wantErr: "contains no PEM certificates"},
+		{name: "cert without key", opts: TLSOptions{CertFile: notPEM}, wantErr: "must be set together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.opts.ClientConfig()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantNil, cfg == nil)
+		})
+	}
+}
+
+func TestNewTransportTrustsCustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	// Without the CA the self-signed test server is rejected.
+	_, err := (&http.Client{Timeout: 5 * time.Second}).Get(srv.URL)
+	require.Error(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(block), 0o600))
+
+	transport, err := NewTransport(Options{TLS: TLSOptions{CAFile: caFile}})
+	require.NoError(t, err)
+	resp, err := (&http.Client{Timeout: 5 * time.Second, Transport: transport}).Get(srv.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestConfigureInstallsDefaultTransport(t *testing.T) {
+	t.Cleanup(func() { SetDefault(nil) })
+
+	require.NoError(t, Configure(Options{TLS: TLSOptions{InsecureSkipVerify: true}}))
+	transport, ok := DefaultTransport().(*http.Transport)
+	require.True(t, ok)
+	require.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+	require.Same(t, DefaultTransport(), New(time.Second).Transport)
+
+	SetDefault(nil)
+	require.Same(t, http.DefaultTransport, DefaultTransport())
+}
+
+func TestProxyOptionsProxyFunc(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      ProxyOptions
+		target    string
+		wantProxy string
+		wantErr   string
+	}{
+		{name: "http proxy", opts: ProxyOptions{URL: "http://proxy.corp:3128"}, target: "https://registry.npmjs.org/x", wantProxy: "http://proxy.corp:3128"},
+		{name: "socks5 proxy", opts: ProxyOptions{URL: "socks5://127.0.0.1:1080"}, target: "https://ghcr.io/v2/", wantProxy: "socks5://127.0.0.1:1080"},
+		{name: "no-proxy bypass", opts: ProxyOptions{URL: "http://proxy.corp:3128", NoProxy: ".internal"}, target: "https://registry.internal/v0", wantProxy: ""},
+		{name: "loopback bypass", opts: ProxyOptions{URL: "http://proxy.corp:3128"}, target: "http://localhost:12121/v0", wantProxy: ""},
+		{name: "unsupported scheme", opts: ProxyOptions{URL: "ftp://proxy.corp"}, wantErr: "unsupported proxy scheme"},
+		{name: "missing host", opts: ProxyOptions{URL: "http://"}, wantErr: "has no host"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy, err := tt.opts.ProxyFunc()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodGet, tt.target, nil)
+			require.NoError(t, err)
+			got, err := proxy(req)
+			require.NoError(t, err)
+			if tt.wantProxy == "" {
+				require.Nil(t, got)
+				return
+			}
+			require.Equal(t, tt.wantProxy, got.String())
+		})
+	}
+}
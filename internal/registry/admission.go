@@ -0,0 +1,294 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/crud"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/apikeyauth"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/controller"
+	internaldb "github.com/agentregistry-dev/agentregistry/internal/registry/database"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/deploylock"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/docscore"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/ownership"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/peers"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/ratelimit"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/readmefetch"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/remoteprobe"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/reservednames"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/resourcelimits"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimeaccess"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/scheduler"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/secrets"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/skilldeps"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/uniqueness"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// admission is what the publish and deploy guards need besides the
+// features they belong to.
+type admission struct {
+	hooks     *crud.PerKindHooks
+	isAdmin   func(ctx context.Context) bool
+	resources *resourcelimits.Policy
+	peers     *peers.Registry
+	sealer    *secrets.Sealer
+	throttled metric.Int64Counter
+}
+
+// prepares returns the per-kind Prepare hooks, creating the map on first
+// use. Each guard wraps the hook already registered for its kind, so
+// guards wired later run later.
+func (a admission) prepares() map[string]func(ctx context.Context, obj v1alpha1.Object) error {
+	if a.hooks.Prepares == nil {
+		a.hooks.Prepares = map[string]func(ctx context.Context, obj v1alpha1.Object) error{}
+	}
+	return a.hooks.Prepares
+}
+
+// setupAdmission wraps the Prepare and Authorize hooks with the publish
+// and deploy guards, in the order they run, and returns authn wrapped to
+// accept API keys.
+func (f *features) setupAdmission(ctx context.Context, a admission, authn auth.AuthnProvider) (auth.AuthnProvider, error) {
+	f.setupScheduler(a)
+	if err := f.setupUniqueness(a); err != nil {
+		return nil, err
+	}
+	f.setupDeploymentLocks(a)
+	f.setupResourceLimits(a)
+	f.setupSnapshotPins(a)
+	f.setupSkillDependencies(a)
+	f.setupDocScore(a)
+	f.setupNamespaceOwnership(a)
+	f.setupReservedNames(a)
+	f.setupRuntimeGrants(a)
+	if err := f.setupPublishQuota(a); err != nil {
+		return nil, err
+	}
+	f.setupRemoteProbe(ctx, a)
+	f.setupReadmeFetch(a)
+	authn = f.setupAPIKeys(a, authn)
+	f.setupRuntimeSecrets(a)
+	return authn, nil
+}
+
+// setupScheduler places Deployments that select their Runtime before any
+// other hook sees them, so every later check works on the chosen Runtime.
+func (f *features) setupScheduler(a admission) {
+	if f.stores[v1alpha1.KindDeployment] == nil || f.stores[v1alpha1.KindRuntime] == nil {
+		return
+	}
+	placer := scheduler.New(f.stores[v1alpha1.KindRuntime], f.stores[v1alpha1.KindDeployment])
+	prepares := a.prepares()
+	prepares[v1alpha1.KindDeployment] = placer.Prepare(prepares[v1alpha1.KindDeployment])
+}
+
+// setupUniqueness runs the uniqueness rules after any caller-supplied
+// Prepare hook so they see the object as it will be persisted.
+func (f *features) setupUniqueness(a admission) error {
+	finders := make(map[string]uniqueness.Finder, len(f.stores))
+	for kind, store := range f.stores {
+		finders[kind] = store
+	}
+	checker, err := uniqueness.New(f.cfg.UniquenessRules, finders)
+	if err != nil {
+		return fmt.Errorf("configure uniqueness rules: %w", err)
+	}
+	kinds := checker.Kinds()
+	if len(kinds) == 0 {
+		return nil
+	}
+	prepares := a.prepares()
+	for _, kind := range kinds {
+		prepares[kind] = checker.Prepare(prepares[kind])
+	}
+	slog.Info("uniqueness rules enabled", "rules", checker.RuleNames())
+	return nil
+}
+
+// setupDeploymentLocks answers 409 to deploys, undeploys and deletes of a
+// Deployment while the controller is reconciling the same target on the
+// same Runtime.
+func (f *features) setupDeploymentLocks(a admission) {
+	if f.pool == nil || f.stores[v1alpha1.KindDeployment] == nil {
+		return
+	}
+	f.deploymentLocks = v1alpha1store.NewDeploymentLocks(f.pool, ossSchema(), controller.LockHolderName())
+	prepares := a.prepares()
+	prepares[v1alpha1.KindDeployment] = deploylock.Prepare(f.deploymentLocks, prepares[v1alpha1.KindDeployment])
+}
+
+// setupResourceLimits answers 422 to Deployments whose CPU or memory
+// exceeds the server maxima, or whose requests exceed their limits once
+// merged with the Agent's.
+func (f *features) setupResourceLimits(a admission) {
+	if f.stores[v1alpha1.KindDeployment] == nil {
+		return
+	}
+	prepares := a.prepares()
+	prepares[v1alpha1.KindDeployment] = a.resources.Prepare(a.peers.Getter(internaldb.NewGetter(f.stores)), prepares[v1alpha1.KindDeployment])
+}
+
+// setupSnapshotPins answers 422 to Deployments pinned to a snapshot that
+// doesn't exist or doesn't hold their target.
+func (f *features) setupSnapshotPins(a admission) {
+	if f.catalog == nil || f.stores[v1alpha1.KindDeployment] == nil {
+		return
+	}
+	prepares := a.prepares()
+	prepares[v1alpha1.KindDeployment] = f.catalog.Prepare(prepares[v1alpha1.KindDeployment])
+}
+
+// setupSkillDependencies answers 409 to Skill publishes whose dependencies
+// form a cycle or pin conflicting versions.
+func (f *features) setupSkillDependencies(a admission) {
+	if f.stores[v1alpha1.KindSkill] == nil {
+		return
+	}
+	prepares := a.prepares()
+	prepares[v1alpha1.KindSkill] = skilldeps.Prepare(a.peers.Getter(internaldb.NewGetter(f.stores)), prepares[v1alpha1.KindSkill])
+}
+
+// setupDocScore records each published MCPServer's documentation score.
+func (f *features) setupDocScore(a admission) {
+	if f.stores[v1alpha1.KindMCPServer] == nil {
+		return
+	}
+	prepares := a.prepares()
+	prepares[v1alpha1.KindMCPServer] = docscore.Prepare(prepares[v1alpha1.KindMCPServer])
+}
+
+// setupNamespaceOwnership restricts publishes and deletes of Agents,
+// MCPServers and Skills in a verified namespace to its owner and members.
+func (f *features) setupNamespaceOwnership(a admission) {
+	if f.pool == nil {
+		return
+	}
+	f.namespaceClaims = v1alpha1store.NewNamespaceStore(f.pool, ossSchema())
+	f.namespaceGuard = ownership.New(f.namespaceClaims, a.isAdmin)
+	prepares := a.prepares()
+	for _, kind := range ownership.Kinds() {
+		if f.stores[kind] != nil {
+			prepares[kind] = f.namespaceGuard.Prepare(prepares[kind])
+		}
+	}
+}
+
+// setupReservedNames restricts publishes of Agents, MCPServers, Skills and
+// Prompts under a reserved name prefix to the prefix's allocated owners.
+func (f *features) setupReservedNames(a admission) {
+	if f.pool == nil {
+		return
+	}
+	f.reservedPrefixes = v1alpha1store.NewReservedPrefixStore(f.pool, ossSchema())
+	guard := reservednames.New(f.reservedPrefixes, f.cfg.ReservedNamePrefixes, a.isAdmin)
+	prepares := a.prepares()
+	for _, kind := range reservednames.Kinds() {
+		if f.stores[kind] != nil {
+			prepares[kind] = guard.Prepare(prepares[kind])
+		}
+	}
+}
+
+// setupRuntimeGrants restricts deploys, undeploys and deletes of
+// Deployments on a granted Runtime to its deployers. The guard wraps the
+// scheduler's placement, so a runtimeSelector is checked against the
+// Runtime it resolves to.
+func (f *features) setupRuntimeGrants(a admission) {
+	if f.pool == nil || f.stores[v1alpha1.KindDeployment] == nil {
+		return
+	}
+	f.runtimeGrants = v1alpha1store.NewRuntimeGrantStore(f.pool, ossSchema())
+	f.runtimeGuard = runtimeaccess.New(f.runtimeGrants, a.isAdmin, internaldb.NewGetter(f.stores))
+	prepares := a.prepares()
+	prepares[v1alpha1.KindDeployment] = f.runtimeGuard.Prepare(prepares[v1alpha1.KindDeployment])
+}
+
+// setupPublishQuota caps publish floods per namespace. Like the
+// reserved-name guard the quota is a Prepare hook, so batch applies are
+// charged per document.
+func (f *features) setupPublishQuota(a admission) error {
+	quota, err := ratelimit.ParseRate(f.cfg.PublishQuotaPerNamespace)
+	if err != nil {
+		return fmt.Errorf("publish quota per namespace: %w", err)
+	}
+	if quota.IsZero() {
+		return nil
+	}
+	publishQuota := ratelimit.NewPublishQuota(quota, a.isAdmin, a.throttled)
+	prepares := a.prepares()
+	for _, kind := range ratelimit.Kinds() {
+		if f.stores[kind] != nil {
+			prepares[kind] = publishQuota.Prepare(prepares[kind])
+		}
+	}
+	slog.Info("publish quota enabled", "quota", quota.String())
+	return nil
+}
+
+// setupRemoteProbe probes MCPServer remotes once every other hook has
+// accepted the publish; versions whose URL is dead are annotated and left
+// out of default search until a recheck passes.
+func (f *features) setupRemoteProbe(ctx context.Context, a admission) {
+	servers := f.stores[v1alpha1.KindMCPServer]
+	if servers == nil || !f.cfg.RemoteProbeEnabled {
+		return
+	}
+	verifier := &remoteprobe.Verifier{Probe: remoteprobe.HTTPProbe(nil), Store: servers}
+	prepares := a.prepares()
+	prepares[v1alpha1.KindMCPServer] = verifier.Prepare(prepares[v1alpha1.KindMCPServer])
+	if f.cfg.RemoteProbeInterval > 0 {
+		go verifier.Run(ctx, f.cfg.RemoteProbeInterval)
+	}
+	slog.Info("remote probe enabled", "recheck_interval", f.cfg.RemoteProbeInterval)
+}
+
+// setupReadmeFetch gives MCPServers published from a GitHub repository
+// without a README the repository's; a failed fetch only leaves
+// spec.readme empty.
+func (f *features) setupReadmeFetch(a admission) {
+	if !f.cfg.ReadmeFetchEnabled {
+		return
+	}
+	fetcher := &readmefetch.Fetcher{APIURL: f.cfg.ReadmeFetchGitHubAPIURL, Token: f.cfg.ReadmeFetchGitHubToken}
+	prepares := a.prepares()
+	prepares[v1alpha1.KindMCPServer] = fetcher.Prepare(prepares[v1alpha1.KindMCPServer])
+	slog.Info("readme fetch enabled", "github_api_url", f.cfg.ReadmeFetchGitHubAPIURL, "authenticated", f.cfg.ReadmeFetchGitHubToken != "")
+}
+
+// setupAPIKeys has bearer tokens minted through /v0/apikeys authenticate
+// as their owner, limited to the key's kinds, name prefixes and actions.
+func (f *features) setupAPIKeys(a admission, authn auth.AuthnProvider) auth.AuthnProvider {
+	if f.pool == nil {
+		return authn
+	}
+	f.apiKeys = v1alpha1store.NewAPIKeyStore(f.pool, ossSchema())
+	if a.hooks.Authorizers == nil {
+		a.hooks.Authorizers = map[string]func(ctx context.Context, in resource.AuthorizeInput) error{}
+	}
+	for kind := range f.stores {
+		a.hooks.Authorizers[kind] = apikeyauth.Authorize(a.hooks.Authorizers[kind])
+	}
+	return apikeyauth.NewAuthenticator(f.apiKeys, authn)
+}
+
+// setupRuntimeSecrets seals Runtime credentials after every other hook has
+// accepted the Runtime, and redacts them wherever a Runtime is returned.
+func (f *features) setupRuntimeSecrets(a admission) {
+	runtimes := f.stores[v1alpha1.KindRuntime]
+	if runtimes == nil {
+		return
+	}
+	prepares := a.prepares()
+	prepares[v1alpha1.KindRuntime] = a.sealer.Prepare(runtimes, prepares[v1alpha1.KindRuntime])
+	if a.hooks.Redactors == nil {
+		a.hooks.Redactors = map[string]func(obj v1alpha1.Object){}
+	}
+	a.hooks.Redactors[v1alpha1.KindRuntime] = secrets.Redact
+}
@@ -17,7 +17,8 @@
 // In the public OSS build those hooks are nil, so the catalogue is flat and
 // unfiltered (matching the already-public OSS reads); a downstream build that
 // wires crud.PerKindHooks for MCPServer gets the same RBAC/tenancy scoping here.
-// The endpoint is off by default regardless (config flag).
+// The endpoint is off by default regardless (config flag). A search that
+// returns a server counts as a search hit on it (see Config.Usage).
 package mcpregistry
 
 import (
@@ -71,6 +72,15 @@ type Config struct {
 	// auth.ErrForbidden is surfaced as 404 so the endpoint never leaks the
 	// existence of servers the caller may not read.
 	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+	// Usage, when set, counts one search hit per server returned by a list
+	// with ?search=.
+	Usage SearchHitRecorder
+}
+
+// SearchHitRecorder counts searches that returned an artifact.
+// *usagestats.Recorder satisfies it.
+type SearchHitRecorder interface {
+	RecordSearchHit(kind, namespace, name string)
 }
 
 // Register mounts the v0.1 compatibility routes on api. cfg.PathPrefix is
@@ -118,6 +128,8 @@ type listServersInput struct {
 	UpdatedSince   string `query:"updated_since" doc:"RFC3339 timestamp; only servers updated at or after this time."`
 	Version        string `query:"version" doc:"'latest' (default) or a specific version tag."`
 	IncludeDeleted bool   `query:"include_deleted" doc:"Include servers pending deletion."`
+	MinDocScore    int    `query:"minDocScore" minimum:"0" maximum:"100" doc:"Only servers whose documentation score is at least this. Versions published before scoring existed count as 0."`
+	resource.FieldsParam
 }
 
 type serverListOutput struct {
@@ -139,8 +151,8 @@ func listServers(cfg Config) func(context.Context, *listServersInput) (*serverLi
 			opts.Tag = in.Version
 		}
 
-		preds := make([]string, 0, 3)
-		args := make([]any, 0, 3)
+		preds := make([]string, 0, 4)
+		args := make([]any, 0, 5)
 		// Downstream RBAC filter first: its placeholders ($1..$k) line up with
 		// the leading args, so our own predicates number cleanly after it.
 		if cfg.ListFilter != nil {
@@ -165,6 +177,14 @@ func listServers(cfg Config) func(context.Context, *listServersInput) (*serverLi
 			args = append(args, ts)
 			preds = append(preds, fmt.Sprintf("updated_at >= $%d", len(args)))
 		}
+		if in.MinDocScore > 0 {
+			// Guard the cast: the annotation predates scoring, so old rows
+			// may carry anything under it.
+			args = append(args, v1alpha1.DocScoreAnnotation, in.MinDocScore)
+			key, score := len(args)-1, len(args)
+			preds = append(preds, fmt.Sprintf(
+				"(CASE WHEN annotations->>$%d ~ '^[0-9]{1,3}$' THEN (annotations->>$%d)::int ELSE 0 END) >= $%d", key, key, score))
+		}
 		if len(preds) > 0 {
 			opts.ExtraWhere = strings.Join(preds, " AND ")
 			opts.ExtraArgs = args
@@ -181,6 +201,11 @@ func listServers(cfg Config) func(context.Context, *listServersInput) (*serverLi
 		if err != nil {
 			return nil, err
 		}
+		if in.Search != "" && cfg.Usage != nil {
+			for _, raw := range rows {
+				cfg.Usage.RecordSearchHit(v1alpha1.KindMCPServer, raw.Metadata.Namespace, raw.Metadata.Name)
+			}
+		}
 		out := &serverListOutput{}
 		out.Body = mcpregistry.ServerListResponse{
 			Servers:  servers,
@@ -195,6 +220,7 @@ type listServerVersionsInput struct {
 	Cursor         string `query:"cursor"`
 	Limit          int    `query:"limit"`
 	IncludeDeleted bool   `query:"include_deleted"`
+	resource.FieldsParam
 }
 
 func listServerVersions(cfg Config) func(context.Context, *listServerVersionsInput) (*serverListOutput, error) {
@@ -240,6 +266,7 @@ func listServerVersions(cfg Config) func(context.Context, *listServerVersionsInp
 type getServerVersionInput struct {
 	ServerName string `path:"serverName" doc:"URL-encoded '<namespace>/<name>' server name."`
 	Version    string `path:"version" doc:"A specific version tag, or 'latest'."`
+	resource.FieldsParam
 }
 
 type serverOutput struct {
@@ -303,7 +330,8 @@ func authorizeRead(ctx context.Context, cfg Config, displayName, ns, name, tag s
 
 // parseServerNameParam unescapes a `{serverName}` path capture (Huma keeps
 // captures raw, so a `%2F`-escaped slash arrives verbatim) and splits it into
-// the AgentRegistry namespace + name.
+// the AgentRegistry namespace + name, both canonicalized so
+// `Io.GitHub.Acme/Tool` finds `io.github.acme/tool`.
 func parseServerNameParam(raw string) (namespace, name string, err error) {
 	decoded, uerr := url.PathUnescape(raw)
 	if uerr != nil {
@@ -313,7 +341,7 @@ func parseServerNameParam(raw string) (namespace, name string, err error) {
 	if perr != nil {
 		return "", "", huma.Error404NotFound(perr.Error())
 	}
-	return ns, n, nil
+	return v1alpha1.CanonicalName(ns), v1alpha1.CanonicalName(n), nil
 }
 
 // translateRows decodes and translates a page of raw rows into server
@@ -147,6 +147,49 @@ func TestListServers_VersionAndFilters(t *testing.T) {
 	assert.Equal(t, "%weather%", store.lastOpts.ExtraArgs[0])
 }
 
+func TestListServers_MinDocScore(t *testing.T) {
+	store := &fakeStore{}
+	srv := newAPI(t, store)
+
+	req := httptest.NewRequest(http.MethodGet, "/v0.1/servers?search=weather&minDocScore=60", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t,
+		"name ILIKE $1 AND (CASE WHEN annotations->>$2 ~ '^[0-9]{1,3}$' THEN (annotations->>$2)::int ELSE 0 END) >= $3",
+		store.lastOpts.ExtraWhere)
+	assert.Equal(t, []any{"%weather%", v1alpha1.DocScoreAnnotation, 60}, store.lastOpts.ExtraArgs)
+
+	req = httptest.NewRequest(http.MethodGet, "/v0.1/servers?minDocScore=101", nil)
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+}
+
+type searchHits []string
+
+func (s *searchHits) RecordSearchHit(kind, namespace, name string) {
+	*s = append(*s, kind+" "+namespace+"/"+name)
+}
+
+// Only searches count as search hits, once per returned server.
+func TestListServers_SearchHitsRecorded(t *testing.T) {
+	store := &fakeStore{rows: []*v1alpha1.RawObject{
+		rawMCPServer(t, "team-a", "weather", "latest", npmSpec("Weather")),
+		rawMCPServer(t, "default", "weather-pro", "latest", npmSpec("Weather Pro")),
+	}}
+	hits := &searchHits{}
+	srv := newAPIConfig(t, handler.Config{Store: store, Usage: hits})
+
+	for _, target := range []string{"/v0.1/servers", "/v0.1/servers?search=weather"} {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+	assert.Equal(t, searchHits{"MCPServer team-a/weather", "MCPServer default/weather-pro"}, *hits)
+}
+
 func TestListServers_BadUpdatedSince(t *testing.T) {
 	srv := newAPI(t, &fakeStore{})
 	req := httptest.NewRequest(http.MethodGet, "/v0.1/servers?updated_since=not-a-time", nil)
@@ -175,6 +218,24 @@ func TestGetServerVersion_LatestAndEncodedName(t *testing.T) {
 	assert.Equal(t, "1.0.0", resp.Server.Version)
 }
 
+func TestGetServerVersion_CaseInsensitiveName(t *testing.T) {
+	store := &fakeStore{
+		rows: []*v1alpha1.RawObject{
+			rawMCPServer(t, "io.github.acme", "tool", "latest", npmSpec("Tool")),
+		},
+	}
+	srv := newAPI(t, store)
+
+	req := httptest.NewRequest(http.MethodGet, "/v0.1/servers/Io.GitHub.Acme%2FTool/versions/latest", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp mcpregistry.ServerResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "io.github.acme/tool", resp.Server.Name)
+}
+
 func TestGetServerVersion_NotFound(t *testing.T) {
 	srv := newAPI(t, &fakeStore{})
 	req := httptest.NewRequest(http.MethodGet, "/v0.1/servers/team-a%2Fmissing/versions/latest", nil)
@@ -296,3 +357,26 @@ func TestListServerVersions_ForbiddenIs404(t *testing.T) {
 	srv.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestListServers_Fields(t *testing.T) {
+	store := &fakeStore{rows: []*v1alpha1.RawObject{rawMCPServer(t, "team-a", "weather", "latest", npmSpec("Weather"))}}
+	mux := http.NewServeMux()
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Transformers = append(config.Transformers, resource.SelectFields)
+	handler.Register(humago.New(mux, config), handler.Config{Store: store})
+
+	req := httptest.NewRequest(http.MethodGet, "/v0.1/servers?fields=name,version,description", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Servers  []map[string]any `json:"servers"`
+		Metadata map[string]any   `json:"metadata"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, []map[string]any{{"server": map[string]any{
+		"name": "team-a/weather", "version": "1.0.0", "description": "Weather",
+	}}}, resp.Servers)
+	require.Equal(t, float64(1), resp.Metadata["count"])
+}
@@ -0,0 +1,199 @@
+// Package agentcard owns the A2A discovery document of deployed agents:
+// `GET /v0/agents/{name}/.well-known/agent-card.json`. The card is built
+// from the deployed Agent version and carries the URL the runtime adapter
+// recorded for its Deployment (v1alpha1.DeploymentEndpointAnnotation), so
+// A2A clients can find a deployed agent through the registry without
+// knowing how its runtime exposes it.
+package agentcard
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// protocolVersion is the A2A protocol version the cards declare; agents
+// the registry deploys speak JSON-RPC A2A.
+const protocolVersion = "0.2.6"
+
+// AgentStore is the narrow read surface this handler needs from the Agent
+// store. *v1alpha1store.Store satisfies it; tests supply a fake.
+type AgentStore interface {
+	Get(ctx context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error)
+}
+
+// DeploymentStore is the narrow read surface this handler needs from the
+// Deployment store. *v1alpha1store.Store satisfies it; tests supply a fake.
+type DeploymentStore interface {
+	FindReferrers(ctx context.Context, pathJSON json.RawMessage, opts v1alpha1store.FindReferrersOpts) ([]*v1alpha1.RawObject, error)
+}
+
+var (
+	_ AgentStore      = (*v1alpha1store.Store)(nil)
+	_ DeploymentStore = (*v1alpha1store.Store)(nil)
+)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix  string
+	Agents      AgentStore
+	Deployments DeploymentStore
+	// Authorizers are the per-kind read gates. The card requires "get" on
+	// the Agent version it describes and on the Deployment whose endpoint
+	// it exposes. nil entries allow.
+	Authorizers map[string]func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type agentCardInput struct {
+	Namespace  string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name       string `path:"name"`
+	Deployment string `query:"deployment" doc:"Deployment to describe when the agent has several; defaults to a ready one."`
+}
+
+type agentCardOutput struct {
+	Body server.AgentCard
+}
+
+// Register wires GET {basePrefix}/agents/{name}/.well-known/agent-card.json.
+// An agent with several Deployments is served from a Ready one, then by
+// Deployment name; ?deployment= picks one explicitly.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-agent-card",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/agents/{name}/.well-known/agent-card.json",
+		Summary:     "Get the A2A agent card of a deployed agent",
+		Description: "The card's url is the A2A endpoint the runtime reported for the agent's Deployment.",
+	}, func(ctx context.Context, in *agentCardInput) (*agentCardOutput, error) {
+		ns := in.Namespace
+		if ns == "" {
+			ns = v1alpha1.DefaultNamespace
+		}
+		name, err := resource.UnescapeName(in.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		deployment, err := findDeployment(ctx, cfg, ns, name, in.Deployment)
+		if err != nil {
+			return nil, err
+		}
+		tag := cmp.Or(deployment.Spec.TargetRef.Tag, v1alpha1store.DefaultTag())
+		if err := authorize(ctx, cfg, v1alpha1.KindAgent, ns, name, tag); err != nil {
+			return nil, err
+		}
+		row, err := cfg.Agents.Get(ctx, ns, name, tag)
+		if err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("Agent %q/%q@%q not found", ns, name, tag))
+			}
+			return nil, huma.Error500InternalServerError("fetch Agent", err)
+		}
+		agent, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Agent { return &v1alpha1.Agent{} }, row, v1alpha1.KindAgent)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("decode Agent", err)
+		}
+		return &agentCardOutput{Body: Card(agent, deployment.Metadata.Annotations[v1alpha1.DeploymentEndpointAnnotation])}, nil
+	})
+}
+
+// findDeployment returns the Deployment the card describes: a deployed one
+// of the agent with a recorded endpoint, named deploymentName when set.
+func findDeployment(ctx context.Context, cfg Config, ns, name, deploymentName string) (*v1alpha1.Deployment, error) {
+	path, err := json.Marshal(map[string]any{"targetRef": map[string]string{"kind": v1alpha1.KindAgent, "name": name}})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("encode referrer path", err)
+	}
+	rows, err := cfg.Deployments.FindReferrers(ctx, path, v1alpha1store.FindReferrersOpts{Namespace: ns})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("find Deployments", err)
+	}
+	var candidates []*v1alpha1.Deployment
+	for _, row := range rows {
+		if deploymentName != "" && row.Metadata.Name != deploymentName {
+			continue
+		}
+		deployment, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} }, row, v1alpha1.KindDeployment)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("decode Deployment", err)
+		}
+		if cmp.Or(deployment.Spec.TargetRef.Namespace, deployment.Metadata.Namespace) != ns ||
+			deployment.Spec.DesiredState == v1alpha1.DesiredStateUndeployed ||
+			deployment.Metadata.Annotations[v1alpha1.DeploymentEndpointAnnotation] == "" {
+			continue
+		}
+		if err := authorize(ctx, cfg, v1alpha1.KindDeployment, deployment.Metadata.Namespace, deployment.Metadata.Name, ""); err != nil {
+			// Without explicit selection, Deployments the caller may not
+			// read are skipped rather than failing the lookup.
+			if deploymentName != "" {
+				return nil, err
+			}
+			continue
+		}
+		candidates = append(candidates, deployment)
+	}
+	if len(candidates) == 0 {
+		if deploymentName != "" {
+			return nil, huma.Error404NotFound(fmt.Sprintf("Deployment %q/%q of Agent %q has no recorded endpoint", ns, deploymentName, name))
+		}
+		return nil, huma.Error404NotFound(fmt.Sprintf("Agent %q/%q has no deployment with a recorded endpoint", ns, name))
+	}
+	slices.SortFunc(candidates, func(a, b *v1alpha1.Deployment) int {
+		if ready := a.Status.IsConditionTrue("Ready"); ready != b.Status.IsConditionTrue("Ready") {
+			if ready {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Metadata.Name, b.Metadata.Name)
+	})
+	return candidates[0], nil
+}
+
+func authorize(ctx context.Context, cfg Config, kind, namespace, name, tag string) error {
+	authz := cfg.Authorizers[kind]
+	if authz == nil {
+		return nil
+	}
+	return authz(ctx, resource.AuthorizeInput{
+		Verb: "get", Kind: kind,
+		Namespace: namespace, Name: name, Tag: tag,
+	})
+}
+
+// Card builds the A2A agent card of agent served at endpoint. Each Skill
+// the agent references becomes a card skill.
+func Card(agent *v1alpha1.Agent, endpoint string) server.AgentCard {
+	streaming := true
+	transport := "JSONRPC"
+	version := protocolVersion
+	card := server.AgentCard{
+		Name:               cmp.Or(agent.Spec.Title, agent.Metadata.Name),
+		Description:        agent.Spec.Description,
+		URL:                endpoint,
+		Version:            agent.Metadata.Tag,
+		Capabilities:       server.AgentCapabilities{Streaming: &streaming},
+		DefaultInputModes:  []string{"text"},
+		DefaultOutputModes: []string{"text"},
+		Skills:             make([]server.AgentSkill, 0, len(agent.Spec.Skills)),
+		PreferredTransport: &transport,
+		ProtocolVersion:    &version,
+	}
+	for _, skill := range agent.Spec.Skills {
+		card.Skills = append(card.Skills, server.AgentSkill{ID: skill.Name, Name: skill.Name, Tags: []string{}})
+	}
+	return card
+}
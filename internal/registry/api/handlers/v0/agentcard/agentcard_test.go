@@ -0,0 +1,147 @@
+package agentcard_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/agentcard"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeAgents map[string]*v1alpha1.RawObject
+
+func (f fakeAgents) Get(_ context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	row, ok := f[namespace+"/"+name+"@"+tag]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	return row, nil
+}
+
+type fakeDeployments []*v1alpha1.RawObject
+
+func (f fakeDeployments) FindReferrers(_ context.Context, _ json.RawMessage, _ v1alpha1store.FindReferrersOpts) ([]*v1alpha1.RawObject, error) {
+	return f, nil
+}
+
+func agentRow(t *testing.T, tag string, spec v1alpha1.AgentSpec) *v1alpha1.RawObject {
+	t.Helper()
+	body, err := json.Marshal(spec)
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindAgent},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "summarizer", Tag: tag},
+		Spec:     body,
+	}
+}
+
+func deploymentRow(t *testing.T, name, tag, endpoint string, ready bool) *v1alpha1.RawObject {
+	t.Helper()
+	spec, err := json.Marshal(v1alpha1.DeploymentSpec{
+		TargetRef:    v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "summarizer", Tag: tag},
+		DesiredState: v1alpha1.DesiredStateDeployed,
+	})
+	require.NoError(t, err)
+	var status v1alpha1.Status
+	if ready {
+		status.SetCondition(v1alpha1.Condition{Type: "Ready", Status: v1alpha1.ConditionTrue})
+	}
+	statusJSON, err := json.Marshal(status)
+	require.NoError(t, err)
+	meta := v1alpha1.ObjectMeta{Namespace: "default", Name: name}
+	if endpoint != "" {
+		meta.Annotations = map[string]string{v1alpha1.DeploymentEndpointAnnotation: endpoint}
+	}
+	return &v1alpha1.RawObject{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindDeployment},
+		Metadata: meta,
+		Spec:     spec,
+		Status:   statusJSON,
+	}
+}
+
+func newAPI(t *testing.T, cfg agentcard.Config) humatest.TestAPI {
+	t.Helper()
+	_, api := humatest.New(t)
+	cfg.BasePrefix = "/v0"
+	agentcard.Register(api, cfg)
+	return api
+}
+
+func TestAgentCard_ServesReadyDeploymentEndpoint(t *testing.T) {
+	api := newAPI(t, agentcard.Config{
+		Agents: fakeAgents{
+			"default/summarizer@1.0.0": agentRow(t, "1.0.0", v1alpha1.AgentSpec{
+				Title:       "Summarizer",
+				Description: "Summarizes documents",
+				Skills:      []v1alpha1.ResourceRef{{Name: "summarize"}},
+			}),
+		},
+		Deployments: fakeDeployments{
+			deploymentRow(t, "summarizer-a", "1.0.0", "http://localhost:21212/agents/a", false),
+			deploymentRow(t, "summarizer-b", "1.0.0", "http://summarizer.kagent.svc.cluster.local:8080", true),
+			deploymentRow(t, "summarizer-c", "1.0.0", "", true),
+		},
+	})
+
+	resp := api.Get("/v0/agents/summarizer/.well-known/agent-card.json")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var card server.AgentCard
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &card))
+	require.Equal(t, "Summarizer", card.Name)
+	require.Equal(t, "Summarizes documents", card.Description)
+	require.Equal(t, "http://summarizer.kagent.svc.cluster.local:8080", card.URL)
+	require.Equal(t, "1.0.0", card.Version)
+	require.Equal(t, []server.AgentSkill{{ID: "summarize", Name: "summarize", Tags: []string{}}}, card.Skills)
+
+	resp = api.Get("/v0/agents/summarizer/.well-known/agent-card.json?deployment=summarizer-a")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &card))
+	require.Equal(t, "http://localhost:21212/agents/a", card.URL)
+}
+
+func TestAgentCard_NotFoundWithoutEndpoint(t *testing.T) {
+	api := newAPI(t, agentcard.Config{
+		Agents:      fakeAgents{},
+		Deployments: fakeDeployments{deploymentRow(t, "summarizer-c", "", "", true)},
+	})
+	resp := api.Get("/v0/agents/summarizer/.well-known/agent-card.json")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
+
+func TestAgentCard_SkipsDeploymentsTheCallerCannotRead(t *testing.T) {
+	deny := func(_ context.Context, in resource.AuthorizeInput) error {
+		if in.Name == "summarizer-b" {
+			return huma.Error403Forbidden("denied")
+		}
+		return nil
+	}
+	api := newAPI(t, agentcard.Config{
+		Agents: fakeAgents{"default/summarizer@latest": agentRow(t, "latest", v1alpha1.AgentSpec{})},
+		Deployments: fakeDeployments{
+			deploymentRow(t, "summarizer-a", "", "http://a.example", false),
+			deploymentRow(t, "summarizer-b", "", "http://b.example", true),
+		},
+		Authorizers: map[string]func(context.Context, resource.AuthorizeInput) error{v1alpha1.KindDeployment: deny},
+	})
+
+	resp := api.Get("/v0/agents/summarizer/.well-known/agent-card.json")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var card server.AgentCard
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &card))
+	require.Equal(t, "http://a.example", card.URL)
+	require.Equal(t, "summarizer", card.Name)
+
+	resp = api.Get("/v0/agents/summarizer/.well-known/agent-card.json?deployment=summarizer-b")
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+}
@@ -0,0 +1,246 @@
+// Package apikeys owns the API key endpoints under `/v0/apikeys`:
+// creating scoped keys, listing them and revoking them. Authenticating
+// requests with a key and enforcing its scopes lives in
+// internal/registry/apikeyauth; this package only issues and records keys.
+//
+// Keys cannot manage keys: a request authenticated by one is refused, so
+// a leaked CI key cannot mint itself a broader successor. Nor can a
+// caller mint a key broader than themselves: every scope is checked
+// against the caller's own permissions, which are recorded on the key.
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/apikeyauth"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Store is the API key capability the endpoints need.
+// *v1alpha1store.APIKeyStore satisfies it.
+type Store interface {
+	Create(ctx context.Context, key *v1alpha1store.APIKey) (*v1alpha1store.APIKey, error)
+	Get(ctx context.Context, id string) (*v1alpha1store.APIKey, error)
+	List(ctx context.Context, owner string) ([]*v1alpha1store.APIKey, error)
+	Revoke(ctx context.Context, id string) (*v1alpha1store.APIKey, error)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// IsAdmin lets registry admins list and revoke every key. nil grants
+	// nobody admin.
+	IsAdmin func(ctx context.Context) bool
+	// Check asks the authz provider whether the caller may take verb on
+	// res; a key is only created when the caller holds every permission
+	// its scopes carry. nil allows every scope.
+	Check func(ctx context.Context, verb auth.PermissionAction, res auth.Resource) error
+}
+
+type createInput struct {
+	Body arv0.APIKeyInput
+}
+
+type idInput struct {
+	ID string `path:"id" doc:"API key ID"`
+}
+
+type createOutput struct {
+	Body arv0.APIKeyCreated
+}
+
+type keyOutput struct {
+	Body arv0.APIKey
+}
+
+type listOutput struct {
+	Body arv0.APIKeyList
+}
+
+// Register wires the /v0/apikeys endpoints.
+func Register(api huma.API, cfg Config) {
+	isAdmin := func(ctx context.Context) bool { return cfg.IsAdmin != nil && cfg.IsAdmin(ctx) }
+	base := cfg.BasePrefix + "/apikeys"
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-apikey",
+		Method:      http.MethodPost,
+		Path:        base,
+		Summary:     "Create a scoped API key; its token is returned only once",
+		Tags:        []string{"apikeys"},
+	}, func(ctx context.Context, in *createInput) (*createOutput, error) {
+		if err := refuseKeySession(ctx); err != nil {
+			return nil, err
+		}
+		owner := auth.SubjectFrom(ctx)
+		if owner == "" && !isAdmin(ctx) {
+			return nil, huma.Error401Unauthorized("creating an API key requires an authenticated caller")
+		}
+		key, err := keyFromInput(in.Body)
+		if err != nil {
+			return nil, err
+		}
+		if err := apikeyauth.CheckGrant(ctx, key, cfg.Check); err != nil {
+			return nil, huma.Error403Forbidden(err.Error())
+		}
+		if session, ok := auth.AuthSessionFrom(ctx); ok {
+			key.OwnerPermissions = apikeyauth.OwnerPermissions(session)
+		}
+		id, secret, token, err := apikeyauth.NewToken()
+		if err != nil {
+			return nil, huma.Error500InternalServerError("generate API key", err)
+		}
+		key.ID, key.Owner, key.SecretHash = id, owner, apikeyauth.HashSecret(secret)
+		created, err := cfg.Store.Create(ctx, key)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("create API key", err)
+		}
+		return &createOutput{Body: arv0.APIKeyCreated{Key: toWire(created), Token: token}}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-apikeys",
+		Method:      http.MethodGet,
+		Path:        base,
+		Summary:     "List API keys: every key for registry admins, the caller's own otherwise",
+		Tags:        []string{"apikeys"},
+	}, func(ctx context.Context, _ *struct{}) (*listOutput, error) {
+		if err := refuseKeySession(ctx); err != nil {
+			return nil, err
+		}
+		owner := ""
+		if !isAdmin(ctx) {
+			owner = auth.SubjectFrom(ctx)
+			if owner == "" {
+				return nil, huma.Error401Unauthorized("listing API keys requires an authenticated caller")
+			}
+		}
+		keys, err := cfg.Store.List(ctx, owner)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list API keys", err)
+		}
+		out := &listOutput{Body: arv0.APIKeyList{Keys: make([]arv0.APIKey, 0, len(keys))}}
+		for _, key := range keys {
+			out.Body.Keys = append(out.Body.Keys, toWire(key))
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "revoke-apikey",
+		Method:      http.MethodDelete,
+		Path:        base + "/{id}",
+		Summary:     "Revoke an API key",
+		Tags:        []string{"apikeys"},
+	}, func(ctx context.Context, in *idInput) (*keyOutput, error) {
+		if err := refuseKeySession(ctx); err != nil {
+			return nil, err
+		}
+		key, err := cfg.Store.Get(ctx, in.ID)
+		if err != nil {
+			return nil, mapStoreError(err, in.ID, "get API key")
+		}
+		// Someone else's key answers 404 so IDs can't be probed.
+		if !isAdmin(ctx) && (key.Owner == "" || key.Owner != auth.SubjectFrom(ctx)) {
+			return nil, notFound(in.ID)
+		}
+		key, err = cfg.Store.Revoke(ctx, in.ID)
+		if err != nil {
+			return nil, mapStoreError(err, in.ID, "revoke API key")
+		}
+		return &keyOutput{Body: toWire(key)}, nil
+	})
+}
+
+func refuseKeySession(ctx context.Context) error {
+	if _, ok := apikeyauth.SessionFrom(ctx); ok {
+		return huma.Error403Forbidden("API keys cannot manage API keys; authenticate as their owner")
+	}
+	return nil
+}
+
+// keyFromInput validates and canonicalizes a create request.
+func keyFromInput(in arv0.APIKeyInput) (*v1alpha1store.APIKey, error) {
+	key := &v1alpha1store.APIKey{Name: strings.TrimSpace(in.Name), ExpiresAt: in.ExpiresAt}
+	if key.Name == "" {
+		return nil, huma.Error400BadRequest("name must not be empty")
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return nil, huma.Error400BadRequest("expiresAt must be in the future")
+	}
+	for _, action := range in.Actions {
+		action = strings.ToLower(strings.TrimSpace(action))
+		if !slices.Contains(apikeyauth.Actions(), action) {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("unknown action %q; want one of %s", action, strings.Join(apikeyauth.Actions(), ", ")))
+		}
+		if !slices.Contains(key.Actions, action) {
+			key.Actions = append(key.Actions, action)
+		}
+	}
+	if len(key.Actions) == 0 {
+		return nil, huma.Error400BadRequest("actions must not be empty")
+	}
+	for _, name := range in.Kinds {
+		kind, ok := apikeyauth.ParseKind(strings.TrimSpace(name))
+		if !ok {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("unknown kind %q; want one of %s", name, strings.Join(apikeyauth.Kinds(), ", ")))
+		}
+		if !slices.Contains(key.Kinds, kind) {
+			key.Kinds = append(key.Kinds, kind)
+		}
+	}
+	for _, prefix := range in.Prefixes {
+		prefix = strings.TrimSuffix(strings.TrimSpace(prefix), "*")
+		if prefix == "" {
+			return nil, huma.Error400BadRequest("prefixes must not be empty; omit them to reach every name")
+		}
+		if !slices.Contains(key.Prefixes, prefix) {
+			key.Prefixes = append(key.Prefixes, prefix)
+		}
+	}
+	return key, nil
+}
+
+func mapStoreError(err error, id, op string) error {
+	if errors.Is(err, pkgdb.ErrNotFound) {
+		return notFound(id)
+	}
+	return huma.Error500InternalServerError(op, err)
+}
+
+func notFound(id string) error {
+	return huma.Error404NotFound("API key " + id + " not found")
+}
+
+func toWire(key *v1alpha1store.APIKey) arv0.APIKey {
+	orEmpty := func(values []string) []string {
+		if values == nil {
+			return []string{}
+		}
+		return values
+	}
+	return arv0.APIKey{
+		ID:         key.ID,
+		Name:       key.Name,
+		Owner:      key.Owner,
+		Kinds:      orEmpty(key.Kinds),
+		Prefixes:   orEmpty(key.Prefixes),
+		Actions:    orEmpty(key.Actions),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,173 @@
+package apikeys_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/apikeys"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/internal/testapi"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/apikeyauth"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore map[string]*v1alpha1store.APIKey
+
+func (f fakeStore) Create(_ context.Context, key *v1alpha1store.APIKey) (*v1alpha1store.APIKey, error) {
+	key.CreatedAt = time.Now()
+	f[key.ID] = key
+	return key, nil
+}
+
+func (f fakeStore) Get(_ context.Context, id string) (*v1alpha1store.APIKey, error) {
+	if key, ok := f[id]; ok {
+		return key, nil
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+func (f fakeStore) List(_ context.Context, owner string) ([]*v1alpha1store.APIKey, error) {
+	var out []*v1alpha1store.APIKey
+	for _, key := range f {
+		if owner == "" || key.Owner == owner {
+			out = append(out, key)
+		}
+	}
+	return out, nil
+}
+
+func (f fakeStore) Revoke(_ context.Context, id string) (*v1alpha1store.APIKey, error) {
+	key, ok := f[id]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	now := time.Now()
+	key.RevokedAt = &now
+	return key, nil
+}
+
+// newAPI registers the endpoints on a testapi.New API that also
+// authenticates an API key of alice's with X-Key. The subject "admin" is a
+// registry admin and holds every permission; alice holds every permission
+// on names under acme/ and bob may read everything.
+func newAPI(t *testing.T, store fakeStore) humatest.TestAPI {
+	api := testapi.New(t)
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		if id := ctx.Header("X-Key"); id != "" {
+			ctx = huma.WithContext(ctx, auth.AuthSessionTo(ctx.Context(), &apikeyauth.Session{Key: store[id]}))
+		}
+		next(ctx)
+	})
+	apikeys.Register(api, apikeys.Config{
+		BasePrefix: "/v0",
+		Store:      store,
+		IsAdmin:    testapi.IsAdmin,
+		Check:      check,
+	})
+	return api
+}
+
+func check(ctx context.Context, verb auth.PermissionAction, res auth.Resource) error {
+	switch subject := auth.SubjectFrom(ctx); {
+	case subject == testapi.Admin,
+		subject == "alice" && strings.HasPrefix(res.Name, "acme/"),
+		subject == "bob" && verb == auth.PermissionActionRead:
+		return nil
+	}
+	return auth.ErrForbidden
+}
+
+func TestCreateListRevoke(t *testing.T) {
+	store := fakeStore{}
+	api := newAPI(t, store)
+	body := map[string]any{"name": "ci", "kinds": []string{"agent", "server", "Agent"}, "prefixes": []string{"acme/*"}, "actions": []string{"push", "read"}}
+
+	resp := api.Post("/v0/apikeys", body)
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/apikeys", "X-Subject: alice", body)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var created arv0.APIKeyCreated
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+	require.Equal(t, "alice", created.Key.Owner)
+	require.Equal(t, []string{"Agent", "MCPServer"}, created.Key.Kinds)
+	require.Equal(t, []string{"acme/"}, created.Key.Prefixes)
+	require.Equal(t, []string{"push", "read"}, created.Key.Actions)
+	id, secret, ok := apikeyauth.ParseToken(created.Token)
+	require.True(t, ok)
+	require.Equal(t, created.Key.ID, id)
+	require.Equal(t, apikeyauth.HashSecret(secret), store[id].SecretHash)
+	require.NotContains(t, resp.Body.String(), store[id].SecretHash)
+
+	// Keys cannot manage keys.
+	resp = api.Post("/v0/apikeys", "X-Key: "+id, body)
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+	resp = api.Get("/v0/apikeys", "X-Key: "+id)
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/apikeys", "X-Subject: bob", map[string]any{"name": "bob", "actions": []string{"read"}})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var list arv0.APIKeyList
+	resp = api.Get("/v0/apikeys", "X-Subject: alice")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Len(t, list.Keys, 1)
+	resp = api.Get("/v0/apikeys", "X-Subject: admin")
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Len(t, list.Keys, 2)
+
+	resp = api.Delete("/v0/apikeys/"+id, "X-Subject: bob")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	resp = api.Delete("/v0/apikeys/"+id, "X-Subject: alice")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var revoked arv0.APIKey
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &revoked))
+	require.NotNil(t, revoked.RevokedAt)
+	resp = api.Delete("/v0/apikeys/missing", "X-Subject: admin")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
+
+func TestCreateRefusesScopesBeyondCaller(t *testing.T) {
+	api := newAPI(t, fakeStore{})
+	for name, tc := range map[string]struct {
+		subject string
+		body    map[string]any
+		want    int
+	}{
+		"within own names":    {"alice", map[string]any{"name": "ci", "prefixes": []string{"acme/team/"}, "actions": []string{"push"}}, http.StatusOK},
+		"every name":          {"alice", map[string]any{"name": "ci", "actions": []string{"read"}}, http.StatusForbidden},
+		"broader prefix":      {"alice", map[string]any{"name": "ci", "prefixes": []string{"ac"}, "actions": []string{"read"}}, http.StatusForbidden},
+		"one prefix too many": {"alice", map[string]any{"name": "ci", "prefixes": []string{"acme/", "other/"}, "actions": []string{"read"}}, http.StatusForbidden},
+		"action not held":     {"bob", map[string]any{"name": "ci", "actions": []string{"read", "deploy"}}, http.StatusForbidden},
+		"admin":               {testapi.Admin, map[string]any{"name": "ci", "actions": []string{"read", "push", "deploy"}}, http.StatusOK},
+	} {
+		resp := api.Post("/v0/apikeys", "X-Subject: "+tc.subject, tc.body)
+		require.Equal(t, tc.want, resp.Code, "%s: %s", name, resp.Body.String())
+	}
+}
+
+func TestCreateValidatesScopes(t *testing.T) {
+	api := newAPI(t, fakeStore{})
+	for name, body := range map[string]map[string]any{
+		"no actions":     {"name": "ci", "actions": []string{}},
+		"unknown action": {"name": "ci", "actions": []string{"admin"}},
+		"unknown kind":   {"name": "ci", "actions": []string{"read"}, "kinds": []string{"runtime"}},
+		"empty prefix":   {"name": "ci", "actions": []string{"read"}, "prefixes": []string{"*"}},
+		"expired":        {"name": "ci", "actions": []string{"read"}, "expiresAt": time.Now().Add(-time.Hour)},
+	} {
+		resp := api.Post("/v0/apikeys", "X-Subject: alice", body)
+		require.GreaterOrEqual(t, resp.Code, 400, name)
+		require.Less(t, resp.Code, 500, name)
+	}
+}
@@ -0,0 +1,112 @@
+// Package artifactstats owns the artifact usage subresource:
+// `GET /v0/{plural}/{name}/stats` for Agents, MCPServers and Skills. It
+// reports how often the artifact was fetched, deployed and returned by a
+// search, across all of its tags. The counters are kept by usagestats.
+package artifactstats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Store resolves the artifact being reported on. *v1alpha1store.Store
+// satisfies it.
+type Store interface {
+	GetLatest(ctx context.Context, namespace, name string) (*v1alpha1.RawObject, error)
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Usage reads the counters. *usagestats.Recorder satisfies it.
+type Usage interface {
+	Get(ctx context.Context, key v1alpha1store.UsageKey) (v1alpha1store.UsageCounts, error)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	// Kind is the artifact kind served; Store is its store.
+	Kind  string
+	Store Store
+	Usage Usage
+	// Authorize gates the request the same way the regular GET handler
+	// does (verb "get"). nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type statsInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+}
+
+type statsOutput struct {
+	Body arv0.ArtifactStats
+}
+
+// Register wires GET {basePrefix}/{plural}/{name}/stats?namespace=default.
+// An artifact with no recorded use answers zero counters; one that does
+// not exist answers 404. A tag literally named "stats" stays reachable
+// only through the list endpoints, as with "tags".
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-" + strings.ToLower(cfg.Kind) + "-stats",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/" + v1alpha1.PluralFor(cfg.Kind) + "/{name}/stats",
+		Summary:     fmt.Sprintf("Get %s usage counters", cfg.Kind),
+		Description: "Downloads (GETs of any tag), applied Deployments and MCP registry search hits, across every tag. Counters trail live traffic by up to one flush interval.",
+	}, func(ctx context.Context, in *statsInput) (*statsOutput, error) {
+		ns := in.Namespace
+		if ns == "" {
+			ns = v1alpha1.DefaultNamespace
+		}
+		name, err := resource.UnescapeName(in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, resource.AuthorizeInput{
+				Verb: "get", Kind: cfg.Kind,
+				Namespace: ns, Name: name,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := cfg.Store.GetLatest(ctx, ns, name); err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("%s %q/%q not found", cfg.Kind, ns, name))
+			}
+			return nil, huma.Error500InternalServerError("fetch "+cfg.Kind, err)
+		}
+		counts, err := cfg.Usage.Get(ctx, v1alpha1store.UsageKey{Kind: cfg.Kind, Namespace: ns, Name: name})
+		if err != nil {
+			return nil, huma.Error500InternalServerError("fetch usage stats", err)
+		}
+
+		out := &statsOutput{Body: arv0.ArtifactStats{
+			Kind:      cfg.Kind,
+			Namespace: ns,
+			Name:      name,
+			Usage: arv0.ArtifactUsage{
+				Downloads:  counts.Downloads,
+				Deploys:    counts.Deploys,
+				SearchHits: counts.SearchHits,
+			},
+		}}
+		if !counts.UpdatedAt.IsZero() {
+			updated := counts.UpdatedAt.UTC()
+			out.Body.UpdatedAt = &updated
+		}
+		return out, nil
+	})
+}
@@ -0,0 +1,77 @@
+package artifactstats_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/artifactstats"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore map[string]bool
+
+func (f fakeStore) GetLatest(_ context.Context, namespace, name string) (*v1alpha1.RawObject, error) {
+	if f[namespace+"/"+name] {
+		return &v1alpha1.RawObject{Metadata: v1alpha1.ObjectMeta{Namespace: namespace, Name: name}}, nil
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+type fakeUsage map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts
+
+func (f fakeUsage) Get(_ context.Context, key v1alpha1store.UsageKey) (v1alpha1store.UsageCounts, error) {
+	return f[key], nil
+}
+
+func TestGetArtifactStats(t *testing.T) {
+	updated := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	_, api := humatest.New(t)
+	artifactstats.Register(api, artifactstats.Config{
+		BasePrefix: "/v0",
+		Kind:       v1alpha1.KindMCPServer,
+		Store:      fakeStore{"default/tools": true, "default/fresh": true, "team-a/secret": true},
+		Usage: fakeUsage{
+			{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "tools"}: {Downloads: 12, Deploys: 2, SearchHits: 5, UpdatedAt: updated},
+		},
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			if in.Namespace == "team-a" {
+				return huma.Error403Forbidden("denied")
+			}
+			return nil
+		},
+	})
+
+	resp := api.Get("/v0/mcpservers/tools/stats")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var got arv0.ArtifactStats
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	require.Equal(t, arv0.ArtifactStats{
+		Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "tools",
+		Usage:     arv0.ArtifactUsage{Downloads: 12, Deploys: 2, SearchHits: 5},
+		UpdatedAt: &updated,
+	}, got)
+
+	resp = api.Get("/v0/mcpservers/fresh/stats")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	got = arv0.ArtifactStats{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	require.Zero(t, got.Usage)
+	require.Nil(t, got.UpdatedAt)
+
+	resp = api.Get("/v0/mcpservers/secret/stats?namespace=team-a")
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = api.Get("/v0/mcpservers/missing/stats")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
@@ -0,0 +1,348 @@
+// Package backstage owns the Backstage catalog integration:
+// `GET /v0/integrations/backstage/catalog-info.yaml`. It renders the
+// latest tag of every MCP server, agent and skill as Backstage catalog
+// entities — an API per MCP server, a Component per agent and a Resource
+// per skill — so a Backstage Location pointed at the URL shows the
+// registry's content in the developer portal. The document is built from
+// the stores on every request, so Backstage's periodic refresh keeps it
+// current.
+//
+// Relations follow the registry's references: an agent consumes the APIs
+// of its MCP servers and depends on its skills and sub-agents; a skill
+// depends on its skills and MCP servers. References to peer registries are
+// left out, as Backstage has no entity for them.
+package backstage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// OwnerAnnotation, set on an artifact, names the Backstage owner of its
+// entity, e.g. "group:default/payments". Artifacts without it are owned by
+// the `owner` query parameter, or DefaultOwner.
+const OwnerAnnotation = "agentregistry.solo.io/backstage-owner"
+
+// DefaultOwner owns entities when neither OwnerAnnotation nor the `owner`
+// query parameter says otherwise.
+const DefaultOwner = "group:default/agentregistry"
+
+// RefAnnotation records the registry artifact an entity was rendered
+// from, as Kind/namespace/name@tag.
+const RefAnnotation = "agentregistry.solo.io/ref"
+
+// entityAPIVersion is the Backstage catalog schema version of every entity.
+const entityAPIVersion = "backstage.io/v1alpha1"
+
+// listPageSize is the store page size used while walking each kind.
+const listPageSize = 200
+
+// maxEntityNameLen is Backstage's limit on metadata.name.
+const maxEntityNameLen = 63
+
+// Kinds are the registry kinds rendered, in document order.
+var Kinds = []string{v1alpha1.KindMCPServer, v1alpha1.KindSkill, v1alpha1.KindAgent}
+
+// Store is the narrow surface this handler needs from each kind's store.
+// *v1alpha1store.Store satisfies it; tests supply a fake.
+type Store interface {
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	// Stores maps kind to store. Kinds outside Kinds are ignored, as are
+	// Kinds without a store.
+	Stores map[string]Store
+	// ListFilters scope each kind's entities the same way its list
+	// endpoint is scoped. A nil entry means no filter.
+	ListFilters map[string]func(ctx context.Context, in resource.AuthorizeInput) (string, []any, error)
+}
+
+type catalogInput struct {
+	Namespace string `query:"namespace" doc:"Only render this namespace. Empty renders every namespace."`
+	Owner     string `query:"owner" maxLength:"253" doc:"Backstage owner of entities whose artifact has no agentregistry.solo.io/backstage-owner annotation. Defaults to group:default/agentregistry."`
+}
+
+type catalogOutput struct {
+	ContentType string `header:"Content-Type"`
+	Body        []byte
+}
+
+// Entity is one Backstage catalog entity.
+type Entity struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Metadata   EntityMetadata `json:"metadata"`
+	Spec       EntitySpec     `json:"spec"`
+}
+
+// EntityMetadata is the metadata block of an Entity.
+type EntityMetadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Title       string            `json:"title,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Links       []EntityLink      `json:"links,omitempty"`
+}
+
+// EntityLink is an external link shown on an entity's page.
+type EntityLink struct {
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+}
+
+// EntitySpec holds the spec fields of the Component, API and Resource
+// kinds; each kind uses a subset.
+type EntitySpec struct {
+	Type         string   `json:"type"`
+	Lifecycle    string   `json:"lifecycle,omitempty"`
+	Owner        string   `json:"owner"`
+	ConsumesAPIs []string `json:"consumesApis,omitempty"`
+	DependsOn    []string `json:"dependsOn,omitempty"`
+	Definition   string   `json:"definition,omitempty"`
+}
+
+// Register wires GET {basePrefix}/integrations/backstage/catalog-info.yaml.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-backstage-catalog",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/integrations/backstage/catalog-info.yaml",
+		Summary:     "Render MCP servers, agents and skills as Backstage catalog entities",
+		Description: "A multi-document YAML stream of Backstage entities for the latest tag of every MCP server (API), agent (Component) and skill (Resource) the caller may list. Point a Backstage Location at this URL.",
+		Tags:        []string{"integrations"},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Multi-document YAML stream of Backstage entities",
+				Content: map[string]*huma.MediaType{
+					"application/yaml": {Schema: &huma.Schema{Type: "string"}},
+				},
+			},
+		},
+	}, func(ctx context.Context, in *catalogInput) (*catalogOutput, error) {
+		owner := in.Owner
+		if owner == "" {
+			owner = DefaultOwner
+		}
+		entities, err := Entities(ctx, cfg, in.Namespace, owner)
+		if err != nil {
+			return nil, err
+		}
+		body, err := Marshal(entities)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("encode backstage catalog", err)
+		}
+		return &catalogOutput{ContentType: "application/yaml", Body: body}, nil
+	})
+}
+
+// Entities renders the latest tag of each artifact in cfg.Stores, scoped
+// by cfg.ListFilters, as Backstage entities. An empty namespace renders
+// every namespace; owner owns artifacts without OwnerAnnotation.
+func Entities(ctx context.Context, cfg Config, namespace, owner string) ([]Entity, error) {
+	var out []Entity
+	for _, kind := range Kinds {
+		store := cfg.Stores[kind]
+		if store == nil {
+			continue
+		}
+		opts := v1alpha1store.ListOpts{Namespace: namespace, LatestOnly: true, Limit: listPageSize}
+		if filter := cfg.ListFilters[kind]; filter != nil {
+			where, args, err := filter(ctx, resource.AuthorizeInput{Verb: "list", Kind: kind, Namespace: namespace})
+			if err != nil {
+				return nil, huma.Error500InternalServerError("authz list filter", err)
+			}
+			opts.ExtraWhere, opts.ExtraArgs = where, args
+		}
+		for {
+			rows, next, err := store.List(ctx, opts)
+			if err != nil {
+				return nil, huma.Error500InternalServerError(fmt.Sprintf("list %s", kind), err)
+			}
+			for _, row := range rows {
+				entity, err := toEntity(kind, row, owner)
+				if err != nil {
+					return nil, huma.Error500InternalServerError(fmt.Sprintf("render %s %s/%s", kind, row.Metadata.Namespace, row.Metadata.Name), err)
+				}
+				out = append(out, entity)
+			}
+			if next == "" {
+				break
+			}
+			opts.Cursor = next
+		}
+	}
+	return out, nil
+}
+
+// Marshal renders entities as a multi-document YAML stream.
+func Marshal(entities []Entity) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, entity := range entities {
+		data, err := yaml.Marshal(entity)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+func toEntity(kind string, row *v1alpha1.RawObject, owner string) (Entity, error) {
+	meta := row.Metadata
+	namespace := meta.NamespaceOrDefault()
+	if o := meta.Annotations[OwnerAnnotation]; o != "" {
+		owner = o
+	}
+	lifecycle := "production"
+	if meta.Annotations[v1alpha1.DeprecatedAnnotation] != "" {
+		lifecycle = "deprecated"
+	}
+	entity := Entity{
+		APIVersion: entityAPIVersion,
+		Metadata: EntityMetadata{
+			Name:      EntityName(meta.Name),
+			Namespace: namespace,
+			Annotations: map[string]string{
+				RefAnnotation: fmt.Sprintf("%s/%s/%s@%s", kind, namespace, meta.Name, meta.Tag),
+			},
+		},
+		Spec: EntitySpec{Owner: owner},
+	}
+
+	switch kind {
+	case v1alpha1.KindMCPServer:
+		var spec v1alpha1.MCPServerSpec
+		if err := json.Unmarshal(row.Spec, &spec); err != nil {
+			return Entity{}, err
+		}
+		entity.Kind = "API"
+		entity.Spec.Type = "mcp-server"
+		entity.Spec.Lifecycle = lifecycle
+		setText(&entity.Metadata, spec.Title, spec.Description)
+		if spec.Source != nil {
+			setSourceLocation(&entity.Metadata, spec.Source.Repository)
+		}
+		if spec.Remote != nil && spec.Remote.URL != "" {
+			entity.Metadata.Links = append(entity.Metadata.Links, EntityLink{URL: spec.Remote.URL, Title: "MCP endpoint"})
+		}
+		// The definition is the MCPServer spec the registry serves; its
+		// README stays in the registry.
+		spec.Readme = ""
+		definition, err := yaml.Marshal(spec)
+		if err != nil {
+			return Entity{}, err
+		}
+		entity.Spec.Definition = string(definition)
+
+	case v1alpha1.KindAgent:
+		var spec v1alpha1.AgentSpec
+		if err := json.Unmarshal(row.Spec, &spec); err != nil {
+			return Entity{}, err
+		}
+		entity.Kind = "Component"
+		entity.Spec.Type = "agent"
+		entity.Spec.Lifecycle = lifecycle
+		setText(&entity.Metadata, spec.Title, spec.Description)
+		if spec.Source != nil {
+			setSourceLocation(&entity.Metadata, spec.Source.Repository)
+		}
+		entity.Spec.ConsumesAPIs = entityRefs("api", namespace, spec.MCPServers)
+		entity.Spec.DependsOn = append(entityRefs("resource", namespace, spec.Skills),
+			entityRefs("component", namespace, spec.SubAgents)...)
+
+	case v1alpha1.KindSkill:
+		var spec v1alpha1.SkillSpec
+		if err := json.Unmarshal(row.Spec, &spec); err != nil {
+			return Entity{}, err
+		}
+		entity.Kind = "Resource"
+		entity.Spec.Type = "agent-skill"
+		setText(&entity.Metadata, spec.Title, spec.Description)
+		if spec.Source != nil {
+			setSourceLocation(&entity.Metadata, spec.Source.Repository)
+		}
+		entity.Spec.DependsOn = append(entityRefs("resource", namespace, spec.Skills),
+			entityRefs("api", namespace, spec.MCPServers)...)
+
+	default:
+		return Entity{}, fmt.Errorf("kind %s has no Backstage entity", kind)
+	}
+	return entity, nil
+}
+
+func setText(meta *EntityMetadata, title, description string) {
+	meta.Title = title
+	meta.Description = description
+}
+
+// setSourceLocation points Backstage at the artifact's repository.
+func setSourceLocation(meta *EntityMetadata, repo *v1alpha1.Repository) {
+	if repo == nil || repo.URL == "" {
+		return
+	}
+	meta.Annotations["backstage.io/source-location"] = "url:" + repo.URL
+}
+
+// entityRefs renders refs as Backstage entity references of kind,
+// defaulting their namespace to namespace. Refs to peer registries are
+// dropped.
+func entityRefs(kind, namespace string, refs []v1alpha1.ResourceRef) []string {
+	var out []string
+	for _, ref := range refs {
+		if ref.Registry != "" {
+			continue
+		}
+		ns := ref.Namespace
+		if ns == "" {
+			ns = namespace
+		}
+		out = append(out, fmt.Sprintf("%s:%s/%s", kind, ns, EntityName(ref.Name)))
+	}
+	return out
+}
+
+// entityNameRegex is Backstage's metadata.name rule: alphanumerics joined
+// by single '-', '_' or '.' separators.
+var entityNameRegex = regexp.MustCompile(`^[A-Za-z0-9]+([-_.][A-Za-z0-9]+)*$`)
+
+var separatorRunRegex = regexp.MustCompile(`[-_.]{2,}`)
+
+// EntityName maps a registry name to a Backstage entity name. Registry
+// names are DNS-1123 subdomains, which Backstage accepts unless they run
+// separators together or exceed 63 characters; those are collapsed and
+// truncated, with a hash of the original keeping them unique.
+func EntityName(name string) string {
+	if len(name) <= maxEntityNameLen && entityNameRegex.MatchString(name) {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:8]
+	base := separatorRunRegex.ReplaceAllString(name, "-")
+	if len(base) > maxEntityNameLen-len(suffix) {
+		base = base[:maxEntityNameLen-len(suffix)]
+	}
+	return strings.TrimRight(base, "-_.") + suffix
+}
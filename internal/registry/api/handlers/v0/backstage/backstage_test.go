@@ -0,0 +1,160 @@
+package backstage_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/backstage"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// fakeStore answers LatestOnly lists with its "latest" rows and records
+// the authz fragment it was given.
+type fakeStore struct {
+	rows  []*v1alpha1.RawObject
+	where *string
+}
+
+func (f fakeStore) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	if f.where != nil {
+		*f.where = opts.ExtraWhere
+	}
+	var out []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		if opts.LatestOnly && row.Metadata.Tag != "latest" {
+			continue
+		}
+		if opts.Namespace == "" || row.Metadata.Namespace == opts.Namespace {
+			out = append(out, row)
+		}
+	}
+	return out, "", nil
+}
+
+func raw(t *testing.T, meta v1alpha1.ObjectMeta, spec any) *v1alpha1.RawObject {
+	t.Helper()
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{Metadata: meta, Spec: data}
+}
+
+func testConfig(t *testing.T) backstage.Config {
+	latest := func(name string) v1alpha1.ObjectMeta {
+		return v1alpha1.ObjectMeta{Namespace: "default", Name: name, Tag: "latest"}
+	}
+	weather := latest("weather")
+	weather.Annotations = map[string]string{v1alpha1.DeprecatedAnnotation: "use forecast"}
+	reporter := latest("reporter")
+	reporter.Annotations = map[string]string{backstage.OwnerAnnotation: "group:default/payments"}
+	return backstage.Config{
+		BasePrefix: "/v0",
+		Stores: map[string]backstage.Store{
+			v1alpha1.KindMCPServer: fakeStore{rows: []*v1alpha1.RawObject{
+				raw(t, weather, v1alpha1.MCPServerSpec{
+					Title:  "Weather",
+					Readme: "# Weather",
+					Remote: &v1alpha1.MCPRemote{Type: "streamable-http", URL: "https://weather.example/mcp"},
+				}),
+				raw(t, v1alpha1.ObjectMeta{Namespace: "default", Name: "weather", Tag: "1.0.0"}, v1alpha1.MCPServerSpec{}),
+			}},
+			v1alpha1.KindSkill: fakeStore{rows: []*v1alpha1.RawObject{
+				raw(t, latest("summarize"), v1alpha1.SkillSpec{
+					Description: "Summarizes text.",
+					Source:      &v1alpha1.SkillSource{Repository: &v1alpha1.Repository{URL: "https://github.com/acme/skills"}},
+				}),
+			}},
+			v1alpha1.KindAgent: fakeStore{rows: []*v1alpha1.RawObject{
+				raw(t, reporter, v1alpha1.AgentSpec{
+					Title: "Reporter",
+					MCPServers: []v1alpha1.ResourceRef{
+						{Kind: v1alpha1.KindMCPServer, Name: "weather"},
+						{Kind: v1alpha1.KindMCPServer, Name: "maps", Registry: "upstream"},
+					},
+					Skills:    []v1alpha1.ResourceRef{{Kind: v1alpha1.KindSkill, Name: "summarize"}},
+					SubAgents: []v1alpha1.ResourceRef{{Kind: v1alpha1.KindAgent, Namespace: "team-a", Name: "researcher"}},
+				}),
+			}},
+			// Kinds Backstage has no entity for are ignored.
+			v1alpha1.KindPrompt: fakeStore{rows: []*v1alpha1.RawObject{raw(t, latest("greeting"), v1alpha1.PromptSpec{})}},
+		},
+	}
+}
+
+func TestEntities(t *testing.T) {
+	entities, err := backstage.Entities(context.Background(), testConfig(t), "", backstage.DefaultOwner)
+	require.NoError(t, err)
+	require.Len(t, entities, 3)
+
+	api, skill, agent := entities[0], entities[1], entities[2]
+	require.Equal(t, "API", api.Kind)
+	require.Equal(t, "weather", api.Metadata.Name)
+	require.Equal(t, "default", api.Metadata.Namespace)
+	require.Equal(t, "mcp-server", api.Spec.Type)
+	require.Equal(t, "deprecated", api.Spec.Lifecycle)
+	require.Equal(t, backstage.DefaultOwner, api.Spec.Owner)
+	require.Equal(t, "MCPServer/default/weather@latest", api.Metadata.Annotations[backstage.RefAnnotation])
+	require.Equal(t, []backstage.EntityLink{{URL: "https://weather.example/mcp", Title: "MCP endpoint"}}, api.Metadata.Links)
+	require.Contains(t, api.Spec.Definition, "url: https://weather.example/mcp")
+	require.NotContains(t, api.Spec.Definition, "# Weather")
+
+	require.Equal(t, "Resource", skill.Kind)
+	require.Equal(t, "agent-skill", skill.Spec.Type)
+	require.Equal(t, "Summarizes text.", skill.Metadata.Description)
+	require.Equal(t, "url:https://github.com/acme/skills", skill.Metadata.Annotations["backstage.io/source-location"])
+
+	require.Equal(t, "Component", agent.Kind)
+	require.Equal(t, "agent", agent.Spec.Type)
+	require.Equal(t, "production", agent.Spec.Lifecycle)
+	require.Equal(t, "group:default/payments", agent.Spec.Owner)
+	require.Equal(t, []string{"api:default/weather"}, agent.Spec.ConsumesAPIs)
+	require.Equal(t, []string{"resource:default/summarize", "component:team-a/researcher"}, agent.Spec.DependsOn)
+}
+
+func TestEntityName(t *testing.T) {
+	require.Equal(t, "io.github.acme.weather", backstage.EntityName("io.github.acme.weather"))
+
+	collapsed := backstage.EntityName("weather--eu")
+	require.True(t, strings.HasPrefix(collapsed, "weather-eu-"), collapsed)
+
+	long := strings.Repeat("a", 100)
+	name := backstage.EntityName(long)
+	require.Len(t, name, 63)
+	require.NotEqual(t, name, backstage.EntityName(long+"b"))
+}
+
+func TestRegister(t *testing.T) {
+	var where string
+	cfg := testConfig(t)
+	agents := cfg.Stores[v1alpha1.KindAgent].(fakeStore)
+	agents.where = &where
+	cfg.Stores[v1alpha1.KindAgent] = agents
+	cfg.ListFilters = map[string]func(context.Context, resource.AuthorizeInput) (string, []any, error){
+		v1alpha1.KindAgent: func(context.Context, resource.AuthorizeInput) (string, []any, error) {
+			return "namespace = $1", []any{"default"}, nil
+		},
+	}
+
+	_, api := humatest.New(t)
+	backstage.Register(api, cfg)
+	resp := api.Get("/v0/integrations/backstage/catalog-info.yaml?owner=user:default/alice")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, "application/yaml", resp.Header().Get("Content-Type"))
+	require.Equal(t, "namespace = $1", where)
+
+	docs := bytes.Split(resp.Body.Bytes(), []byte("---\n"))
+	require.Len(t, docs, 3)
+	var first backstage.Entity
+	require.NoError(t, yaml.Unmarshal(docs[0], &first))
+	require.Equal(t, "backstage.io/v1alpha1", first.APIVersion)
+	require.Equal(t, "user:default/alice", first.Spec.Owner)
+}
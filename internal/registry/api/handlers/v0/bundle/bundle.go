@@ -0,0 +1,116 @@
+// Package bundle owns the MCPServer archive subresource:
+// `/v0/mcpservers/{name}/{tag}/bundle`. It packages one tagged version —
+// manifest, README and server.json card — as an OCI artifact in an OCI image
+// layout tarball, so the metadata can be pushed to and distributed through
+// existing OCI registries with ORAS-compatible tooling.
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/ociartifact"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+)
+
+// Store is the narrow read surface this handler needs from the MCPServer
+// store. *v1alpha1store.Store satisfies it; tests supply a fake.
+type Store interface {
+	Get(ctx context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// Authorize gates the request the same way the regular MCPServer GET
+	// handler does (verb "get"). nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type bundleInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Tag       string `path:"tag"`
+}
+
+type bundleOutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	Body               []byte
+}
+
+// Register wires GET {basePrefix}/mcpservers/{name}/{tag}/bundle.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-mcpserver-bundle",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/mcpservers/{name}/{tag}/bundle",
+		Summary:     "Download an MCPServer version as an OCI artifact (OCI image layout tar)",
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "OCI image layout tarball",
+				Content: map[string]*huma.MediaType{
+					ociartifact.MediaTypeLayoutTar: {Schema: &huma.Schema{Type: "string", Format: "binary"}},
+				},
+			},
+		},
+	}, func(ctx context.Context, in *bundleInput) (*bundleOutput, error) {
+		ns := in.Namespace
+		if ns == "" {
+			ns = v1alpha1.DefaultNamespace
+		}
+		name, err := resource.UnescapeName(in.Name)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := resource.UnescapePath("tag", in.Tag)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, resource.AuthorizeInput{
+				Verb: "get", Kind: v1alpha1.KindMCPServer,
+				Namespace: ns, Name: name, Tag: tag,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		row, err := cfg.Store.Get(ctx, ns, name, tag)
+		if err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("MCPServer %q/%q@%q not found", ns, name, tag))
+			}
+			return nil, huma.Error500InternalServerError("fetch MCPServer", err)
+		}
+		server, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.MCPServer { return &v1alpha1.MCPServer{} }, row, v1alpha1.KindMCPServer)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("decode MCPServer", err)
+		}
+
+		artifact, err := ociartifact.ForObject(server)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("package MCPServer", err)
+		}
+		built, err := artifact.Build()
+		if err != nil {
+			return nil, huma.Error500InternalServerError("package MCPServer", err)
+		}
+		var buf bytes.Buffer
+		if err := ociartifact.WriteLayout(&buf, built, tag); err != nil {
+			return nil, huma.Error500InternalServerError("write OCI layout", err)
+		}
+		return &bundleOutput{
+			ContentType:        ociartifact.MediaTypeLayoutTar,
+			ContentDisposition: fmt.Sprintf(`attachment; filename=%q`, ociartifact.LayoutFileName(name, tag)),
+			Body:               buf.Bytes(),
+		}, nil
+	})
+}
@@ -0,0 +1,103 @@
+package bundle_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/bundle"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/ociartifact"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+)
+
+type fakeStore struct {
+	rows map[string]*v1alpha1.RawObject
+}
+
+func (f fakeStore) Get(_ context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	row, ok := f.rows[namespace+"/"+name+"@"+tag]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	return row, nil
+}
+
+func newStore(t *testing.T) fakeStore {
+	t.Helper()
+	spec, err := json.Marshal(v1alpha1.MCPServerSpec{Title: "Weather", Description: "Forecasts"})
+	require.NoError(t, err)
+	return fakeStore{rows: map[string]*v1alpha1.RawObject{
+		"default/acme/weather@1.0.0": {
+			TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "acme/weather", Tag: "1.0.0"},
+			Spec:     spec,
+		},
+	}}
+}
+
+func TestRegisterBundle(t *testing.T) {
+	_, api := humatest.New(t)
+	bundle.Register(api, bundle.Config{BasePrefix: "/v0", Store: newStore(t)})
+
+	resp := api.Get("/v0/mcpservers/acme%2Fweather/1.0.0/bundle")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, ociartifact.MediaTypeLayoutTar, resp.Header().Get("Content-Type"))
+	require.Contains(t, resp.Header().Get("Content-Disposition"), "acme-weather-1.0.0.tar")
+
+	names := map[string]bool{}
+	tr := tar.NewReader(bytes.NewReader(resp.Body.Bytes()))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		names[hdr.Name] = true
+	}
+	require.True(t, names["oci-layout"])
+	require.True(t, names["index.json"])
+	// manifest + empty config + manifest.yaml + README.md + server.json
+	require.Len(t, names, 2+5)
+}
+
+func TestRegisterBundleErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		authorize func(context.Context, resource.AuthorizeInput) error
+		wantCode  int
+	}{
+		{"missing tag", "/v0/mcpservers/acme%2Fweather/2.0.0/bundle", nil, http.StatusNotFound},
+		{"other namespace", "/v0/mcpservers/acme%2Fweather/1.0.0/bundle?namespace=team-b", nil, http.StatusNotFound},
+		{
+			"authorize denies",
+			"/v0/mcpservers/acme%2Fweather/1.0.0/bundle",
+			func(_ context.Context, in resource.AuthorizeInput) error {
+				require.Equal(t, "get", in.Verb)
+				require.Equal(t, v1alpha1.KindMCPServer, in.Kind)
+				require.Equal(t, "acme/weather", in.Name)
+				return huma.Error403Forbidden("denied")
+			},
+			http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, api := humatest.New(t)
+			bundle.Register(api, bundle.Config{BasePrefix: "/v0", Store: newStore(t), Authorize: tt.authorize})
+			resp := api.Get(tt.path)
+			require.Equal(t, tt.wantCode, resp.Code, resp.Body.String())
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Package capabilitydiff owns the MCPServer breaking-change report:
+// `GET /v0/mcpservers/{name}/capability-diff?from=&to=`. It compares the
+// tools two versions record in spec.tools and flags removed and renamed
+// tools and input-schema changes existing calls would no longer satisfy.
+//
+// Only what the versions record is compared. A version published without
+// spec.tools reports every tool of the other side as added or removed.
+package capabilitydiff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+)
+
+// Store is the narrow read surface this handler needs from the MCPServer
+// store. *v1alpha1store.Store satisfies it; tests supply a fake.
+type Store interface {
+	Get(ctx context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// Authorize gates each compared version the same way the regular
+	// MCPServer GET handler does (verb "get"). nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type diffInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	From      string `query:"from" required:"true" doc:"Tag of the version being upgraded from."`
+	To        string `query:"to" required:"true" doc:"Tag of the version being upgraded to."`
+}
+
+type diffOutput struct {
+	Body arv0.CapabilityDiff
+}
+
+// Register wires GET {basePrefix}/mcpservers/{name}/capability-diff. The
+// literal segment wins over GET {basePrefix}/mcpservers/{name}/{tag}, so a
+// tag named "capability-diff" cannot be fetched directly (list and apply
+// still reach it).
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "diff-mcpserver-capabilities",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/mcpservers/{name}/capability-diff",
+		Summary:     "Compare the tools of two MCPServer versions and flag breaking changes",
+	}, func(ctx context.Context, in *diffInput) (*diffOutput, error) {
+		ns := in.Namespace
+		if ns == "" {
+			ns = v1alpha1.DefaultNamespace
+		}
+		name, err := resource.UnescapeName(in.Name)
+		if err != nil {
+			return nil, err
+		}
+		from, err := getTools(ctx, cfg, ns, name, in.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := getTools(ctx, cfg, ns, name, in.To)
+		if err != nil {
+			return nil, err
+		}
+		diff := Diff(from, to)
+		diff.Namespace, diff.Name, diff.From, diff.To = ns, name, in.From, in.To
+		return &diffOutput{Body: diff}, nil
+	})
+}
+
+func getTools(ctx context.Context, cfg Config, namespace, name, tag string) ([]v1alpha1.MCPTool, error) {
+	if cfg.Authorize != nil {
+		if err := cfg.Authorize(ctx, resource.AuthorizeInput{
+			Verb: "get", Kind: v1alpha1.KindMCPServer,
+			Namespace: namespace, Name: name, Tag: tag,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	row, err := cfg.Store.Get(ctx, namespace, name, tag)
+	if err != nil {
+		if errors.Is(err, pkgdb.ErrNotFound) {
+			return nil, huma.Error404NotFound(fmt.Sprintf("MCPServer %q/%q@%q not found", namespace, name, tag))
+		}
+		return nil, huma.Error500InternalServerError("fetch MCPServer", err)
+	}
+	server, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.MCPServer { return &v1alpha1.MCPServer{} }, row, v1alpha1.KindMCPServer)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("decode MCPServer", err)
+	}
+	return server.Spec.Tools, nil
+}
+
+// Diff compares two tool lists. A removed tool is reported as renamed when
+// an added tool has the same non-empty input schema or description.
+func Diff(from, to []v1alpha1.MCPTool) arv0.CapabilityDiff {
+	diff := arv0.CapabilityDiff{
+		Added:   []string{},
+		Removed: []string{},
+		Renamed: []arv0.ToolRename{},
+		Changed: []arv0.ToolChange{},
+	}
+	before := toolsByName(from)
+	after := toolsByName(to)
+
+	var removed, added []v1alpha1.MCPTool
+	for _, name := range slices.Sorted(maps.Keys(before)) {
+		old := before[name]
+		tool, ok := after[name]
+		if !ok {
+			removed = append(removed, old)
+			continue
+		}
+		changes, breaking := schemaChanges(old.InputSchema, tool.InputSchema)
+		if len(changes) > 0 {
+			diff.Changed = append(diff.Changed, arv0.ToolChange{Name: name, Breaking: breaking, Changes: changes})
+			diff.Breaking = diff.Breaking || breaking
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(after)) {
+		if _, ok := before[name]; !ok {
+			added = append(added, after[name])
+		}
+	}
+
+	for _, old := range removed {
+		i := slices.IndexFunc(added, func(tool v1alpha1.MCPTool) bool { return sameTool(old, tool) })
+		if i < 0 {
+			diff.Removed = append(diff.Removed, old.Name)
+			continue
+		}
+		diff.Renamed = append(diff.Renamed, arv0.ToolRename{From: old.Name, To: added[i].Name})
+		added = slices.Delete(added, i, i+1)
+	}
+	for _, tool := range added {
+		diff.Added = append(diff.Added, tool.Name)
+	}
+	diff.Breaking = diff.Breaking || len(diff.Removed) > 0 || len(diff.Renamed) > 0
+	return diff
+}
+
+func toolsByName(tools []v1alpha1.MCPTool) map[string]v1alpha1.MCPTool {
+	out := make(map[string]v1alpha1.MCPTool, len(tools))
+	for _, tool := range tools {
+		out[tool.Name] = tool
+	}
+	return out
+}
+
+func sameTool(a, b v1alpha1.MCPTool) bool {
+	if len(a.InputSchema) > 0 && reflect.DeepEqual(a.InputSchema, b.InputSchema) {
+		return true
+	}
+	return a.Description != "" && a.Description == b.Description
+}
+
+// schemaChanges compares the top-level properties of two JSON Schemas.
+// Removing a property, changing its type, making it required or adding a
+// required property breaks existing calls; adding an optional property
+// does not. Differences below the top level are reported as one
+// non-breaking change.
+func schemaChanges(from, to map[string]any) (changes []string, breaking bool) {
+	fromProps, toProps := properties(from), properties(to)
+	fromReq, toReq := required(from), required(to)
+
+	for _, name := range slices.Sorted(maps.Keys(fromProps)) {
+		toProp, ok := toProps[name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("property %q removed", name))
+			breaking = true
+			continue
+		case !reflect.DeepEqual(schemaType(fromProps[name]), schemaType(toProp)):
+			changes = append(changes, fmt.Sprintf("property %q type changed from %v to %v",
+				name, schemaType(fromProps[name]), schemaType(toProp)))
+			breaking = true
+		}
+		if !fromReq[name] && toReq[name] {
+			changes = append(changes, fmt.Sprintf("property %q is now required", name))
+			breaking = true
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(toProps)) {
+		if _, ok := fromProps[name]; ok {
+			continue
+		}
+		if toReq[name] {
+			changes = append(changes, fmt.Sprintf("required property %q added", name))
+			breaking = true
+		} else {
+			changes = append(changes, fmt.Sprintf("property %q added", name))
+		}
+	}
+	if len(changes) == 0 && !reflect.DeepEqual(from, to) {
+		changes = append(changes, "input schema changed")
+	}
+	return changes, breaking
+}
+
+func properties(schema map[string]any) map[string]any {
+	props, _ := schema["properties"].(map[string]any)
+	return props
+}
+
+func required(schema map[string]any) map[string]bool {
+	out := map[string]bool{}
+	switch req := schema["required"].(type) {
+	case []any:
+		for _, name := range req {
+			if s, ok := name.(string); ok {
+				out[s] = true
+			}
+		}
+	case []string:
+		for _, name := range req {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+func schemaType(prop any) any {
+	m, _ := prop.(map[string]any)
+	return m["type"]
+}
@@ -0,0 +1,148 @@
+package capabilitydiff_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/capabilitydiff"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+type fakeStore struct {
+	rows map[string]*v1alpha1.RawObject
+}
+
+func (f fakeStore) Get(_ context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	row, ok := f.rows[namespace+"/"+name+"@"+tag]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	return row, nil
+}
+
+// schema decodes a JSON Schema literal the way a stored spec comes back.
+func schema(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var out map[string]any
+	require.NoError(t, json.Unmarshal([]byte(s), &out))
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	cityOnly := `{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]}`
+	tests := []struct {
+		name         string
+		from, to     []v1alpha1.MCPTool
+		wantBreaking bool
+		check        func(t *testing.T, d arv0.CapabilityDiff)
+	}{
+		{
+			name:         "identical",
+			from:         []v1alpha1.MCPTool{{Name: "forecast", InputSchema: schema(t, cityOnly)}},
+			to:           []v1alpha1.MCPTool{{Name: "forecast", InputSchema: schema(t, cityOnly)}},
+			wantBreaking: false,
+			check: func(t *testing.T, d arv0.CapabilityDiff) {
+				require.Empty(t, d.Changed)
+				require.Empty(t, d.Added)
+			},
+		},
+		{
+			name: "added tool and optional property",
+			from: []v1alpha1.MCPTool{{Name: "forecast", InputSchema: schema(t, cityOnly)}},
+			to: []v1alpha1.MCPTool{
+				{Name: "forecast", InputSchema: schema(t, `{"type":"object","properties":{"city":{"type":"string"},"units":{"type":"string"}},"required":["city"]}`)},
+				{Name: "alerts"},
+			},
+			wantBreaking: false,
+			check: func(t *testing.T, d arv0.CapabilityDiff) {
+				require.Equal(t, []string{"alerts"}, d.Added)
+				require.Equal(t, []string{`property "units" added`}, d.Changed[0].Changes)
+			},
+		},
+		{
+			name:         "removed tool",
+			from:         []v1alpha1.MCPTool{{Name: "forecast", Description: "Forecast"}, {Name: "alerts", Description: "Alerts"}},
+			to:           []v1alpha1.MCPTool{{Name: "forecast", Description: "Forecast"}},
+			wantBreaking: true,
+			check: func(t *testing.T, d arv0.CapabilityDiff) {
+				require.Equal(t, []string{"alerts"}, d.Removed)
+			},
+		},
+		{
+			name:         "renamed tool",
+			from:         []v1alpha1.MCPTool{{Name: "forecast", InputSchema: schema(t, cityOnly)}},
+			to:           []v1alpha1.MCPTool{{Name: "get_forecast", InputSchema: schema(t, cityOnly)}},
+			wantBreaking: true,
+			check: func(t *testing.T, d arv0.CapabilityDiff) {
+				require.Equal(t, []arv0.ToolRename{{From: "forecast", To: "get_forecast"}}, d.Renamed)
+				require.Empty(t, d.Removed)
+				require.Empty(t, d.Added)
+			},
+		},
+		{
+			name: "breaking schema changes",
+			from: []v1alpha1.MCPTool{{Name: "forecast", InputSchema: schema(t,
+				`{"type":"object","properties":{"city":{"type":"string"},"days":{"type":"integer"},"lang":{"type":"string"}},"required":["city"]}`)}},
+			to: []v1alpha1.MCPTool{{Name: "forecast", InputSchema: schema(t,
+				`{"type":"object","properties":{"city":{"type":"object"},"days":{"type":"integer"},"units":{"type":"string"}},"required":["city","days","units"]}`)}},
+			wantBreaking: true,
+			check: func(t *testing.T, d arv0.CapabilityDiff) {
+				require.Len(t, d.Changed, 1)
+				require.True(t, d.Changed[0].Breaking)
+				require.Equal(t, []string{
+					`property "city" type changed from string to object`,
+					`property "days" is now required`,
+					`property "lang" removed`,
+					`required property "units" added`,
+				}, d.Changed[0].Changes)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := capabilitydiff.Diff(tt.from, tt.to)
+			require.Equal(t, tt.wantBreaking, d.Breaking)
+			tt.check(t, d)
+		})
+	}
+}
+
+func TestRegisterCapabilityDiff(t *testing.T) {
+	row := func(tag string, tools ...v1alpha1.MCPTool) *v1alpha1.RawObject {
+		spec, err := json.Marshal(v1alpha1.MCPServerSpec{Title: "Weather", Tools: tools})
+		require.NoError(t, err)
+		return &v1alpha1.RawObject{
+			TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "acme/weather", Tag: tag},
+			Spec:     spec,
+		}
+	}
+	store := fakeStore{rows: map[string]*v1alpha1.RawObject{
+		"default/acme/weather@1.0.0": row("1.0.0", v1alpha1.MCPTool{Name: "forecast"}, v1alpha1.MCPTool{Name: "alerts"}),
+		"default/acme/weather@2.0.0": row("2.0.0", v1alpha1.MCPTool{Name: "forecast"}),
+	}}
+	_, api := humatest.New(t)
+	capabilitydiff.Register(api, capabilitydiff.Config{BasePrefix: "/v0", Store: store})
+
+	resp := api.Get("/v0/mcpservers/acme%2Fweather/capability-diff?from=1.0.0&to=2.0.0")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var got arv0.CapabilityDiff
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	require.Equal(t, "acme/weather", got.Name)
+	require.Equal(t, "1.0.0", got.From)
+	require.True(t, got.Breaking)
+	require.Equal(t, []string{"alerts"}, got.Removed)
+
+	resp = api.Get("/v0/mcpservers/acme%2Fweather/capability-diff?from=1.0.0&to=3.0.0")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	resp = api.Get("/v0/mcpservers/acme%2Fweather/capability-diff?from=1.0.0")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+}
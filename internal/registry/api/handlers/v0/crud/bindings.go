@@ -38,6 +38,10 @@ func init() {
 	register(v1alpha1.KindSkill, func() *v1alpha1.Skill { return &v1alpha1.Skill{} })
 	register(v1alpha1.KindPlugin, func() *v1alpha1.Plugin { return &v1alpha1.Plugin{} })
 	register(v1alpha1.KindPrompt, func() *v1alpha1.Prompt { return &v1alpha1.Prompt{} })
+	register(v1alpha1.KindChart, func() *v1alpha1.Chart { return &v1alpha1.Chart{} })
 	register(v1alpha1.KindRuntime, func() *v1alpha1.Runtime { return &v1alpha1.Runtime{} })
 	register(v1alpha1.KindDeployment, func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} })
+	register(v1alpha1.KindDeploymentTemplate, func() *v1alpha1.DeploymentTemplate { return &v1alpha1.DeploymentTemplate{} })
+	register(v1alpha1.KindWebhook, func() *v1alpha1.Webhook { return &v1alpha1.Webhook{} })
+	register(v1alpha1.KindFeatureFlag, func() *v1alpha1.FeatureFlag { return &v1alpha1.FeatureFlag{} })
 }
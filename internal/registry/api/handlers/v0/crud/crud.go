@@ -20,6 +20,7 @@ import (
 
 	"github.com/danielgtaylor/huma/v2"
 
+	"github.com/agentregistry-dev/agentregistry/internal/registry/usagestats"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
@@ -55,6 +56,9 @@ type PerKindHooks struct {
 	// InitialFinalizers seeds create-time finalizers per kind; see
 	// resource.Config.InitialFinalizers.
 	InitialFinalizers map[string]func(obj v1alpha1.Object) []string
+	// Redactors blank out sensitive fields of returned objects per kind;
+	// see resource.Config.Redact.
+	Redactors map[string]func(obj v1alpha1.Object)
 }
 
 // Register wires the namespace-scoped + cross-namespace list endpoints for
@@ -67,6 +71,9 @@ type PerKindHooks struct {
 // the call. Go generics still require a concrete type at the route-registration
 // call site, so bindings.go remains the small typed companion to the generic
 // v1alpha1 kind registry.
+//
+// usage, when non-nil, counts downloads and ranks lists for the kinds
+// usagestats tracks (Agent, MCPServer, Skill).
 func Register(
 	api huma.API,
 	basePrefix string,
@@ -75,13 +82,14 @@ func Register(
 	registryValidator v1alpha1.RegistryValidatorFunc,
 	perKind PerKindHooks,
 	deleteAdmission types.DeleteAdmission,
+	usage resource.UsageTracker,
 ) {
 	cfgFor := func(kind string) (resource.Config, bool) {
 		store, ok := stores[kind]
 		if !ok {
 			return resource.Config{}, false
 		}
-		return resource.Config{
+		cfg := resource.Config{
 			Kind:               kind,
 			BasePrefix:         basePrefix,
 			Store:              store,
@@ -95,7 +103,15 @@ func Register(
 			Prepare:            perKind.Prepares[kind],
 			DeleteAdmission:    deleteAdmission,
 			InitialFinalizers:  perKind.InitialFinalizers[kind],
-		}, true
+			Redact:             perKind.Redactors[kind],
+		}
+		if kind == v1alpha1.KindDeployment {
+			cfg.ListSorts = map[string]*v1alpha1store.ListSort{"status": v1alpha1store.SortByDeploymentStatus}
+		}
+		if usage != nil && usagestats.Tracked(kind) {
+			cfg.Usage = usage
+		}
+		return cfg, true
 	}
 
 	for _, kind := range v1alpha1.RegisteredKinds() {
@@ -0,0 +1,116 @@
+// Package deploymentdryrun owns the Deployment dry run endpoint:
+// `POST /v0/deployments:dryRun`. It runs the submitted Deployment through
+// the same target/runtime resolution and adapter translation a reconcile
+// would, and returns the manifests the runtime would receive without
+// applying or storing anything, so translation errors surface before the
+// cluster is touched.
+package deploymentdryrun
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/controller"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// Renderer is the only controller capability needed by this handler.
+// *controller.DeploymentController satisfies it.
+type Renderer interface {
+	DryRun(ctx context.Context, deployment *v1alpha1.Deployment) ([]types.RenderedManifest, error)
+}
+
+var _ Renderer = (*controller.DeploymentController)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Renderer   Renderer
+	// Authorize gates the request the same way a Deployment apply is
+	// gated (verb "apply"), since the response shows what the caller's
+	// apply would run. nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type dryRunInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to metadata.namespace, then 'default')."`
+	Body      v1alpha1.Deployment
+}
+
+type dryRunOutput struct {
+	Body arv0.DeploymentDryRun
+}
+
+// Register wires POST {basePrefix}/deployments:dryRun?namespace=default.
+// Unresolvable refs and invalid Deployments answer 400, translation
+// failures 422, and runtimes whose adapter cannot render 501.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "dry-run-deployment",
+		Method:      http.MethodPost,
+		Path:        cfg.BasePrefix + "/deployments:dryRun",
+		Summary:     "Render the runtime manifests for a deployment without applying it",
+		Description: "Resolves the Deployment's target and runtime and translates it exactly as the controller would, then returns the compose file, gateway config, Kubernetes resources or Helm releases as YAML with secret values replaced by `REDACTED`. Nothing is applied or stored.",
+	}, func(ctx context.Context, in *dryRunInput) (*dryRunOutput, error) {
+		deployment := &in.Body
+		if deployment.Kind != "" && deployment.Kind != v1alpha1.KindDeployment {
+			return nil, huma.Error400BadRequest("kind must be " + v1alpha1.KindDeployment)
+		}
+		deployment.APIVersion = v1alpha1.GroupVersion
+		deployment.Kind = v1alpha1.KindDeployment
+		if in.Namespace != "" {
+			if deployment.Metadata.Namespace != "" && deployment.Metadata.Namespace != in.Namespace {
+				return nil, huma.Error400BadRequest("metadata.namespace does not match the namespace query parameter")
+			}
+			deployment.Metadata.Namespace = in.Namespace
+		}
+		if deployment.Metadata.Namespace == "" {
+			deployment.Metadata.Namespace = v1alpha1.DefaultNamespace
+		}
+		meta := deployment.Metadata
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, resource.AuthorizeInput{
+				Verb: "apply", Kind: v1alpha1.KindDeployment,
+				Namespace: meta.Namespace, Name: meta.Name,
+				Object: deployment,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		if err := v1alpha1.ValidateObject(deployment); err != nil {
+			return nil, huma.Error400BadRequest("validation: " + err.Error())
+		}
+
+		manifests, err := cfg.Renderer.DryRun(ctx, deployment)
+		if err != nil {
+			switch {
+			case errors.Is(err, v1alpha1.ErrDanglingRef):
+				return nil, huma.Error400BadRequest("refs: " + err.Error())
+			case errors.Is(err, pkgdb.ErrInvalidInput):
+				return nil, huma.Error400BadRequest(err.Error())
+			case errors.Is(err, controller.ErrRenderFailed):
+				return nil, huma.Error422UnprocessableEntity(err.Error())
+			case errors.Is(err, controller.ErrDryRunUnsupported):
+				return nil, huma.Error501NotImplemented(err.Error())
+			}
+			return nil, huma.Error500InternalServerError("dry run deployment", err)
+		}
+
+		out := &dryRunOutput{Body: arv0.DeploymentDryRun{
+			Namespace: meta.Namespace,
+			Name:      meta.Name,
+			Manifests: make([]arv0.DeploymentManifest, 0, len(manifests)),
+		}}
+		for _, m := range manifests {
+			out.Body.Manifests = append(out.Body.Manifests, arv0.DeploymentManifest{Name: m.Name, Content: m.Content})
+		}
+		return out, nil
+	})
+}
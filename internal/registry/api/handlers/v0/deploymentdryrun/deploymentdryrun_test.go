@@ -0,0 +1,101 @@
+package deploymentdryrun_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentdryrun"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/controller"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+type fakeRenderer struct {
+	got *v1alpha1.Deployment
+}
+
+func (f *fakeRenderer) DryRun(_ context.Context, deployment *v1alpha1.Deployment) ([]types.RenderedManifest, error) {
+	f.got = deployment
+	switch deployment.Spec.TargetRef.Name {
+	case "missing":
+		return nil, fmt.Errorf("resolve targetRef: %w", v1alpha1.ErrDanglingRef)
+	case "broken":
+		return nil, fmt.Errorf("%w: adapter %q: duplicate Agent name found: bot", controller.ErrRenderFailed, "Local")
+	case "remote":
+		return nil, fmt.Errorf("%w: %q", controller.ErrDryRunUnsupported, "BedrockAgentCore")
+	}
+	return []types.RenderedManifest{{Name: "docker-compose.yaml", Content: "services: {}\n"}}, nil
+}
+
+func deploymentBody(namespace, target string) map[string]any {
+	return map[string]any{
+		"apiVersion": v1alpha1.GroupVersion,
+		"kind":       v1alpha1.KindDeployment,
+		"metadata":   map[string]any{"namespace": namespace, "name": "bot-dev"},
+		"spec": map[string]any{
+			"targetRef":  map[string]any{"kind": v1alpha1.KindAgent, "name": target},
+			"runtimeRef": map[string]any{"kind": v1alpha1.KindRuntime, "name": "local"},
+		},
+	}
+}
+
+func TestDryRunDeployment(t *testing.T) {
+	renderer := &fakeRenderer{}
+	_, api := humatest.New(t)
+	deploymentdryrun.Register(api, deploymentdryrun.Config{
+		BasePrefix: "/v0",
+		Renderer:   renderer,
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			if in.Namespace == "team-a" {
+				return huma.Error403Forbidden("denied")
+			}
+			return nil
+		},
+	})
+
+	resp := api.Post("/v0/deployments:dryRun", deploymentBody("", "bot"))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var got arv0.DeploymentDryRun
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	require.Equal(t, arv0.DeploymentDryRun{
+		Namespace: "default", Name: "bot-dev",
+		Manifests: []arv0.DeploymentManifest{{Name: "docker-compose.yaml", Content: "services: {}\n"}},
+	}, got)
+	require.Equal(t, "default", renderer.got.Metadata.Namespace)
+
+	// The typed struct, as the CLI client sends it, decodes the same way.
+	resp = api.Post("/v0/deployments:dryRun", &v1alpha1.Deployment{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindDeployment},
+		Metadata: v1alpha1.ObjectMeta{Name: "bot-dev"},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "bot"},
+			RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "local"},
+		},
+	})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/deployments:dryRun?namespace=team-a", deploymentBody("", "bot"))
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/deployments:dryRun?namespace=team-b", deploymentBody("team-c", "bot"))
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/deployments:dryRun", deploymentBody("", "missing"))
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/deployments:dryRun", deploymentBody("", "broken"))
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "duplicate Agent name")
+
+	resp = api.Post("/v0/deployments:dryRun", deploymentBody("", "remote"))
+	require.Equal(t, http.StatusNotImplemented, resp.Code, resp.Body.String())
+}
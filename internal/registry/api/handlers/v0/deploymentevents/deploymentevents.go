@@ -0,0 +1,254 @@
+// Package deploymentevents owns the Deployment progress stream:
+// `GET /v0/deployments/{name}/events`, served as server-sent events. The
+// stream opens with the Deployment's current status conditions, then emits
+// one event per condition that appears or changes until the Deployment is
+// deleted or the client disconnects. Adapter progress, rollout status and
+// reconcile errors all land in conditions, so a failed deploy reaches a
+// watching client as soon as the controller records it.
+//
+// The handler polls the Deployment row rather than holding a LISTEN
+// connection per stream, so open streams cost one indexed read per
+// PollInterval each and no dedicated database connections.
+package deploymentevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+const (
+	defaultPollInterval = time.Second
+	// heartbeatInterval is how long a stream may stay silent before an SSE
+	// comment is written, so idle proxies don't cut it.
+	heartbeatInterval = 15 * time.Second
+)
+
+// Store is the narrow read surface this handler needs from the Deployment
+// store. *v1alpha1store.Store satisfies it; tests supply a fake.
+type Store interface {
+	GetLatestIncludingTerminating(ctx context.Context, namespace, name string) (*v1alpha1.RawObject, error)
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// Authorize gates the stream the same way the regular Deployment GET
+	// handler does (verb "get"). Condition messages carry adapter errors,
+	// which can name images, hosts and secrets. nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+	// PollInterval is how often the Deployment row is re-read. Zero uses
+	// one second.
+	PollInterval time.Duration
+}
+
+type deploymentEventsInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+}
+
+// Register wires GET {basePrefix}/deployments/{name}/events?namespace=default.
+// Authorization and the existence check run before the stream opens, so a
+// denied or missing Deployment answers 403 or 404 rather than an empty
+// stream.
+func Register(api huma.API, cfg Config) {
+	pollInterval := cfg.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	eventSchema := api.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(arv0.DeploymentEvent{}), true, "DeploymentEvent")
+
+	huma.Register(api, huma.Operation{
+		OperationID: "watch-deployment-events",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/deployments/{name}/events",
+		Summary:     "Stream a deployment's progress as server-sent events",
+		Description: "Each `data:` line is a JSON DeploymentEvent. The stream ends after a `deleted` event.",
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Server-sent event stream",
+				Content: map[string]*huma.MediaType{
+					"text/event-stream": {Schema: eventSchema},
+				},
+			},
+		},
+	}, func(ctx context.Context, in *deploymentEventsInput) (*huma.StreamResponse, error) {
+		ns := in.Namespace
+		if ns == "" {
+			ns = v1alpha1.DefaultNamespace
+		}
+		name, err := resource.UnescapeName(in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, resource.AuthorizeInput{
+				Verb: "get", Kind: v1alpha1.KindDeployment,
+				Namespace: ns, Name: name,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		row, err := cfg.Store.GetLatestIncludingTerminating(ctx, ns, name)
+		if err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("Deployment %q/%q not found", ns, name))
+			}
+			return nil, huma.Error500InternalServerError("fetch Deployment", err)
+		}
+
+		return &huma.StreamResponse{Body: func(hctx huma.Context) {
+			hctx.SetHeader("Content-Type", "text/event-stream")
+			hctx.SetHeader("Cache-Control", "no-cache")
+			w := &eventWriter{w: hctx.BodyWriter(), last: time.Now()}
+			watch(hctx.Context(), cfg.Store, ns, name, row, pollInterval, w)
+		}}, nil
+	})
+}
+
+// watch emits the events for row and every re-read of it until the
+// Deployment is gone, a read fails or ctx is done.
+func watch(ctx context.Context, store Store, ns, name string, row *v1alpha1.RawObject, pollInterval time.Duration, w *eventWriter) {
+	var seen snapshot
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		events, next := seen.diff(ns, name, row, time.Now().UTC())
+		seen = next
+		for _, event := range events {
+			if err := w.event(event); err != nil {
+				return
+			}
+		}
+		if row == nil {
+			return
+		}
+		if time.Since(w.last) >= heartbeatInterval {
+			if err := w.comment("heartbeat"); err != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		var err error
+		row, err = store.GetLatestIncludingTerminating(ctx, ns, name)
+		if errors.Is(err, pkgdb.ErrNotFound) {
+			row, err = nil, nil
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				_ = w.comment("read failed: " + err.Error())
+			}
+			return
+		}
+	}
+}
+
+// snapshot is what a stream last reported about its Deployment.
+type snapshot struct {
+	conditions  map[string]v1alpha1.Condition
+	terminating bool
+}
+
+// diff returns the events that take s to row, and the snapshot after them.
+// A nil row means the Deployment is gone.
+func (s snapshot) diff(ns, name string, row *v1alpha1.RawObject, now time.Time) ([]arv0.DeploymentEvent, snapshot) {
+	if row == nil {
+		return []arv0.DeploymentEvent{{Type: arv0.DeploymentEventDeleted, Namespace: ns, Name: name, Time: now}}, s
+	}
+	var status v1alpha1.Status
+	if len(row.Status) > 0 {
+		// A status the controller can't have written is reported as no
+		// conditions rather than ending the stream.
+		_ = json.Unmarshal(row.Status, &status)
+	}
+	next := snapshot{conditions: make(map[string]v1alpha1.Condition, len(status.Conditions)), terminating: s.terminating}
+	var events []arv0.DeploymentEvent
+	for _, c := range status.Conditions {
+		next.conditions[c.Type] = c
+		prev, ok := s.conditions[c.Type]
+		if ok && prev.Status == c.Status && prev.Reason == c.Reason && prev.Message == c.Message {
+			continue
+		}
+		at := c.LastTransitionTime
+		if at.IsZero() {
+			at = now
+		}
+		events = append(events, arv0.DeploymentEvent{
+			Type:       arv0.DeploymentEventCondition,
+			Namespace:  ns,
+			Name:       name,
+			Generation: row.Metadata.Generation,
+			Condition:  c.Type,
+			Status:     string(c.Status),
+			Reason:     c.Reason,
+			Message:    c.Message,
+			Time:       at.UTC(),
+		})
+	}
+	if row.Metadata.DeletionTimestamp != nil && !s.terminating {
+		next.terminating = true
+		events = append(events, arv0.DeploymentEvent{
+			Type:       arv0.DeploymentEventTerminating,
+			Namespace:  ns,
+			Name:       name,
+			Generation: row.Metadata.Generation,
+			Time:       row.Metadata.DeletionTimestamp.UTC(),
+		})
+	}
+	return events, next
+}
+
+// eventWriter frames and flushes server-sent events.
+type eventWriter struct {
+	w    io.Writer
+	id   int
+	last time.Time
+}
+
+func (e *eventWriter) event(event arv0.DeploymentEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	e.id++
+	return e.write(fmt.Sprintf("id: %d\ndata: %s\n\n", e.id, data))
+}
+
+func (e *eventWriter) comment(text string) error {
+	return e.write(": " + text + "\n\n")
+}
+
+func (e *eventWriter) write(frame string) error {
+	if _, err := io.WriteString(e.w, frame); err != nil {
+		return err
+	}
+	e.last = time.Now()
+	if rw, ok := e.w.(http.ResponseWriter); ok {
+		return http.NewResponseController(rw).Flush()
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package deploymentevents_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentevents"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+)
+
+// fakeStore replays rows in order, one per read; a nil row reads as
+// not found. The last row repeats once the sequence is exhausted.
+type fakeStore struct {
+	mu   sync.Mutex
+	rows []*v1alpha1.RawObject
+}
+
+func (f *fakeStore) GetLatestIncludingTerminating(_ context.Context, namespace, name string) (*v1alpha1.RawObject, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	row := f.rows[0]
+	if len(f.rows) > 1 {
+		f.rows = f.rows[1:]
+	}
+	if row == nil {
+		return nil, pkgdb.ErrNotFound
+	}
+	return row, nil
+}
+
+func deployment(t *testing.T, generation int64, deleting bool, conditions ...v1alpha1.Condition) *v1alpha1.RawObject {
+	t.Helper()
+	status, err := json.Marshal(v1alpha1.Status{Conditions: conditions})
+	require.NoError(t, err)
+	meta := v1alpha1.ObjectMeta{Namespace: "default", Name: "bot-prod", Generation: generation}
+	if deleting {
+		now := time.Date(2026, 10, 17, 12, 5, 0, 0, time.UTC)
+		meta.DeletionTimestamp = &now
+	}
+	return &v1alpha1.RawObject{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindDeployment},
+		Metadata: meta,
+		Status:   status,
+	}
+}
+
+func readEvents(t *testing.T, body string) []arv0.DeploymentEvent {
+	t.Helper()
+	var events []arv0.DeploymentEvent
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event arv0.DeploymentEvent
+		require.NoError(t, json.Unmarshal([]byte(data), &event))
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestWatch(t *testing.T) {
+	progressing := v1alpha1.Condition{Type: "Progressing", Status: v1alpha1.ConditionTrue, Reason: "Applying", Message: "creating pod"}
+	failed := v1alpha1.Condition{Type: "Degraded", Status: v1alpha1.ConditionTrue, Reason: "AdapterApplyFailed", Message: "duplicate image ref"}
+	store := &fakeStore{rows: []*v1alpha1.RawObject{
+		deployment(t, 1, false, progressing),
+		deployment(t, 1, false, progressing),
+		deployment(t, 1, false, progressing, failed),
+		deployment(t, 2, true, progressing, failed),
+		nil,
+	}}
+
+	_, api := humatest.New(t)
+	deploymentevents.Register(api, deploymentevents.Config{
+		BasePrefix:   "/v0",
+		Store:        store,
+		PollInterval: time.Millisecond,
+	})
+
+	resp := api.Get("/v0/deployments/bot-prod/events")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, "text/event-stream", resp.Header().Get("Content-Type"))
+
+	events := readEvents(t, resp.Body.String())
+	require.Len(t, events, 4)
+	require.Equal(t, arv0.DeploymentEventCondition, events[0].Type)
+	require.Equal(t, "Progressing", events[0].Condition)
+	require.Equal(t, "creating pod", events[0].Message)
+	require.Equal(t, arv0.DeploymentEventCondition, events[1].Type)
+	require.Equal(t, "Degraded", events[1].Condition)
+	require.Equal(t, "duplicate image ref", events[1].Message)
+	require.Equal(t, arv0.DeploymentEventTerminating, events[2].Type)
+	require.EqualValues(t, 2, events[2].Generation)
+	require.Equal(t, arv0.DeploymentEventDeleted, events[3].Type)
+	require.Equal(t, "bot-prod", events[3].Name)
+}
+
+func TestWatch_RejectsBeforeStreaming(t *testing.T) {
+	_, api := humatest.New(t)
+	deploymentevents.Register(api, deploymentevents.Config{
+		BasePrefix: "/v0",
+		Store:      &fakeStore{rows: []*v1alpha1.RawObject{nil}},
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			if in.Name == "secret" {
+				return huma.Error403Forbidden("denied")
+			}
+			return nil
+		},
+	})
+
+	resp := api.Get("/v0/deployments/secret/events")
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = api.Get("/v0/deployments/missing/events?namespace=team-a")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), `Deployment \"team-a\"/\"missing\" not found`)
+}
@@ -1,18 +1,26 @@
 // Package deploymentlogs owns the Deployment logs subresource:
 // `/v0/deployments/{name}/logs`. Drains adapter.Logs through a narrow resolver
-// and returns the captured lines as JSON. The endpoint is bound to one specific
-// kind (Deployment); the rest of the v1alpha1 CRUD surface lives in crud.
+// and returns the captured lines as JSON, preceded by the lines log shipping
+// retained when a LogStore is configured. With follow=true the same lines
+// are streamed as server-sent events, followed by live ones until the
+// workload's log stream ends or the client disconnects. The endpoint is bound
+// to one specific kind (Deployment); the rest of the v1alpha1 CRUD surface
+// lives in crud.
 package deploymentlogs
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"net/url"
+	"reflect"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
@@ -25,6 +33,12 @@ type LogResolver interface {
 	Logs(ctx context.Context, deployment *v1alpha1.Deployment, in types.LogsInput) (<-chan types.LogLine, error)
 }
 
+// LogStore reads the lines log shipping retained for a Deployment.
+// *v1alpha1store.DeploymentLogStore satisfies it.
+type LogStore interface {
+	List(ctx context.Context, namespace, name string, since time.Time, limit int) ([]v1alpha1store.DeploymentLogLine, error)
+}
+
 // Config bundles the inputs for Register. The resolver drives
 // adapter.Logs; the store fetches the Deployment row so the endpoint
 // can reject 404s early.
@@ -32,6 +46,9 @@ type Config struct {
 	BasePrefix  string
 	Store       *v1alpha1store.Store
 	LogResolver LogResolver
+	// Retained, when set, serves the lines log shipping kept, so logs
+	// survive container restarts. nil reads the adapter alone.
+	Retained LogStore
 	// Authorize gates the request the same way the regular Deployment
 	// GET handler does. nil means no gate. Logs leak runtime
 	// stdout/stderr — frequently containing PII or secrets — so a
@@ -50,54 +67,53 @@ type Config struct {
 type deploymentLogsInput struct {
 	Namespace string `query:"namespace" doc:"Namespace (internal; defaults to 'default')."`
 	Name      string `path:"name"`
-	Follow    bool   `query:"follow" doc:"Stream indefinitely until client disconnects."`
+	Follow    bool   `query:"follow" doc:"Stream the lines as server-sent events and keep following the workload until the client disconnects."`
 	TailLines int    `query:"tailLines" doc:"Max backlog lines before live tail; 0 = unbounded."`
+	Since     string `query:"since" doc:"Only lines logged at or after this RFC3339 time, or within this duration of now (e.g. 1h)."`
 }
 
-type deploymentLogLine struct {
-	Timestamp string `json:"timestamp,omitempty" doc:"RFC3339 timestamp."`
-	Stream    string `json:"stream,omitempty"     doc:"stdout | stderr | runtime-specific."`
-	Line      string `json:"line"                 doc:"Single log record."`
-}
-
-// maxLogLines caps the response payload in non-follow mode so a chatty
-// adapter can't OOM the server. Picked to keep payloads under ~10 MB
-// at typical log line sizes; bump in lockstep with the SSE port if
-// callers need denser drains.
-const maxLogLines = 10000
-
-type deploymentLogsOutput struct {
-	Body struct {
-		Lines []deploymentLogLine `json:"lines"`
-	}
-}
+const (
+	// maxLogLines caps the backlog so a chatty adapter can't OOM the
+	// server: the whole response in non-follow mode, and the lines sent
+	// before the live tail with follow=true. Picked to keep payloads
+	// under ~10 MB at typical log line sizes.
+	maxLogLines = 10000
+	// heartbeatInterval is how long a followed stream may stay silent
+	// before an SSE comment is written, so idle proxies don't cut it.
+	heartbeatInterval = 15 * time.Second
+)
 
 // Register wires GET {basePrefix}/deployments/{name}/logs?namespace=default.
-// The response is a JSON payload of log records drained from Resolver.Logs;
-// follow=true keeps the channel open until the client disconnects (or until the
-// adapter's context is cancelled).
-//
-// Non-streaming for now — huma lacks first-class SSE output and the
-// kubernetes/local adapters still return closed channels. When real log
-// streaming lands upstream, swap this for an SSE/chunked handler at the
-// same path without touching the adapter resolver surface.
+// The response is a JSON payload of log records drained from Resolver.Logs.
+// follow=true answers with a server-sent event stream instead: one event per
+// line, the backlog first, then live lines until the adapter closes its
+// channel or the client disconnects. Authorization, the existence check and
+// the adapter call all run before the response starts, so failures answer
+// with a regular status code either way.
 func Register(api huma.API, cfg Config) {
 	path := cfg.BasePrefix + "/deployments/{name}/logs"
+	logsSchema := api.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(arv0.DeploymentLogs{}), true, "DeploymentLogs")
+	lineSchema := api.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(arv0.DeploymentLogLine{}), true, "DeploymentLogLine")
 
 	huma.Register(api, huma.Operation{
 		OperationID: "get-deployment-logs",
 		Method:      http.MethodGet,
 		Path:        path,
 		Summary:     "Stream logs from a deployment's runtime workload",
-	}, func(ctx context.Context, in *deploymentLogsInput) (*deploymentLogsOutput, error) {
-		// follow=true is a streaming hint that this handler can't honor —
-		// huma serializes the full body before responding, so a true
-		// stream would buffer until the channel closes (or never, if the
-		// adapter follows indefinitely) and OOM the process. Reject
-		// upfront with a clear error; SSE/chunked support will land in
-		// a separate endpoint.
-		if in.Follow {
-			return nil, huma.Error400BadRequest("follow=true is not supported on this endpoint; the streaming SSE variant is tracked as a follow-up")
+		Description: "Without `follow` the lines come back as one JSON document. With `follow=true` each `data:` line of the event stream is a JSON DeploymentLogLine.",
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Log lines, or a server-sent event stream of them with follow=true",
+				Content: map[string]*huma.MediaType{
+					"application/json":  {Schema: logsSchema},
+					"text/event-stream": {Schema: lineSchema},
+				},
+			},
+		},
+	}, func(ctx context.Context, in *deploymentLogsInput) (*huma.StreamResponse, error) {
+		since, err := parseSince(in.Since, time.Now())
+		if err != nil {
+			return nil, huma.Error400BadRequest(err.Error())
 		}
 		ns := in.Namespace
 		if ns == "" {
@@ -106,9 +122,9 @@ func Register(api huma.API, cfg Config) {
 		// Names allow `/` so callers must `%2F`-escape them on the wire;
 		// Huma keeps the captures raw, so unescape before consulting
 		// the Store.
-		name, err := url.PathUnescape(in.Name)
+		name, err := resource.UnescapeName(in.Name)
 		if err != nil {
-			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid name path segment: %v", err))
+			return nil, err
 		}
 		if cfg.Authorize != nil {
 			if err := cfg.Authorize(ctx, resource.AuthorizeInput{
@@ -148,25 +164,171 @@ func Register(api huma.API, cfg Config) {
 		if tailLines <= 0 || tailLines > maxLogLines {
 			tailLines = maxLogLines
 		}
+		var (
+			lines      []types.LogLine
+			retainedTo time.Time
+		)
+		if cfg.Retained != nil {
+			retained, err := cfg.Retained.List(ctx, ns, name, since, tailLines)
+			if err != nil {
+				return nil, huma.Error500InternalServerError("read retained logs", err)
+			}
+			for _, line := range retained {
+				lines = append(lines, types.LogLine{Timestamp: line.Timestamp, Stream: line.Stream, Line: line.Line})
+				retainedTo = line.Timestamp
+			}
+		}
 		ch, err := cfg.LogResolver.Logs(ctx, deployment, types.LogsInput{
-			Follow:    false, // gated above; non-follow only for now
+			Follow:    in.Follow,
 			TailLines: tailLines,
 		})
-		if err != nil {
+		// Retained lines still answer once the workload is gone.
+		if err != nil && len(lines) == 0 {
 			return nil, huma.Error502BadGateway("adapter logs: " + err.Error())
 		}
-		out := &deploymentLogsOutput{}
-		out.Body.Lines = []deploymentLogLine{}
-		for line := range ch {
-			out.Body.Lines = append(out.Body.Lines, deploymentLogLine{
-				Timestamp: line.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
-				Stream:    line.Stream,
-				Line:      line.Line,
-			})
-			if len(out.Body.Lines) >= maxLogLines {
-				break
-			}
+
+		if in.Follow {
+			return &huma.StreamResponse{Body: func(hctx huma.Context) {
+				hctx.SetHeader("Content-Type", "text/event-stream")
+				hctx.SetHeader("Cache-Control", "no-cache")
+				w := &eventWriter{w: hctx.BodyWriter(), last: time.Now()}
+				for _, line := range lines {
+					if err := w.event(wireLine(line)); err != nil {
+						return
+					}
+				}
+				if err != nil {
+					_ = w.comment("adapter logs: " + err.Error())
+					return
+				}
+				follow(hctx.Context(), ch, since, retainedTo, w)
+			}}, nil
+		}
+
+		if err == nil {
+			lines = appendLive(lines, ch, since, retainedTo)
+		}
+		if len(lines) > tailLines {
+			lines = lines[len(lines)-tailLines:]
+		}
+		body := arv0.DeploymentLogs{Lines: make([]arv0.DeploymentLogLine, 0, len(lines))}
+		for _, line := range lines {
+			body.Lines = append(body.Lines, wireLine(line))
 		}
-		return out, nil
+		return &huma.StreamResponse{Body: func(hctx huma.Context) {
+			hctx.SetHeader("Content-Type", "application/json")
+			_ = json.NewEncoder(hctx.BodyWriter()).Encode(body)
+		}}, nil
 	})
 }
+
+// appendLive drains up to maxLogLines live lines after the retained ones,
+// skipping lines before since and lines already retained.
+func appendLive(lines []types.LogLine, ch <-chan types.LogLine, since, retainedTo time.Time) []types.LogLine {
+	drained := 0
+	for line := range ch {
+		drained++
+		if isLive(line, since, retainedTo) {
+			lines = append(lines, line)
+		}
+		if drained >= maxLogLines {
+			break
+		}
+	}
+	return lines
+}
+
+// follow sends every live line of ch as an event until ch closes, a write
+// fails or ctx is done, with a heartbeat comment whenever the stream has
+// been silent for heartbeatInterval.
+func follow(ctx context.Context, ch <-chan types.LogLine, since, retainedTo time.Time, w *eventWriter) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-ch:
+			if !ok {
+				return
+			}
+			if !isLive(line, since, retainedTo) {
+				continue
+			}
+			if err := w.event(wireLine(line)); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if time.Since(w.last) >= heartbeatInterval {
+				if err := w.comment("heartbeat"); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// isLive reports whether an adapter line belongs after the retained ones:
+// not before since and newer than the last retained line. Lines without a
+// timestamp can't be placed and are kept as they come.
+func isLive(line types.LogLine, since, retainedTo time.Time) bool {
+	return line.Timestamp.IsZero() || (!line.Timestamp.Before(since) && line.Timestamp.After(retainedTo))
+}
+
+func wireLine(line types.LogLine) arv0.DeploymentLogLine {
+	return arv0.DeploymentLogLine{
+		Timestamp: line.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+		Stream:    line.Stream,
+		Line:      line.Line,
+	}
+}
+
+// parseSince reads the since query parameter: an RFC3339 timestamp, or a
+// duration counted back from now. Empty means no lower bound.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("since must be an RFC3339 time or a positive duration, got %q", value)
+	}
+	return now.Add(-d), nil
+}
+
+// eventWriter frames and flushes server-sent events.
+type eventWriter struct {
+	w    io.Writer
+	id   int
+	last time.Time
+}
+
+func (e *eventWriter) event(line arv0.DeploymentLogLine) error {
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	e.id++
+	return e.write(fmt.Sprintf("id: %d\ndata: %s\n\n", e.id, data))
+}
+
+func (e *eventWriter) comment(text string) error {
+	return e.write(": " + text + "\n\n")
+}
+
+func (e *eventWriter) write(frame string) error {
+	if _, err := io.WriteString(e.w, frame); err != nil {
+		return err
+	}
+	e.last = time.Now()
+	if rw, ok := e.w.(http.ResponseWriter); ok {
+		return http.NewResponseController(rw).Flush()
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package deploymentlogs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseSince("", now)
+	require.NoError(t, err)
+	require.True(t, got.IsZero())
+
+	got, err = parseSince("90m", now)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-90*time.Minute), got)
+
+	got, err = parseSince("2026-03-01T10:00:00Z", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), got)
+
+	for _, bad := range []string{"yesterday", "-1h", "2026-03-01"} {
+		_, err := parseSince(bad, now)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestAppendLiveSkipsRetainedAndOldLines(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ch := make(chan types.LogLine, 5)
+	ch <- types.LogLine{Timestamp: base, Line: "before since"}
+	ch <- types.LogLine{Timestamp: base.Add(time.Minute), Line: "retained"}
+	ch <- types.LogLine{Timestamp: base.Add(2 * time.Minute), Line: "new"}
+	ch <- types.LogLine{Line: "untimed"}
+	close(ch)
+
+	retained := []types.LogLine{{Timestamp: base.Add(time.Minute), Line: "retained"}}
+	got := appendLive(retained, ch, base.Add(30*time.Second), base.Add(time.Minute))
+	lines := make([]string, 0, len(got))
+	for _, line := range got {
+		lines = append(lines, line.Line)
+	}
+	require.Equal(t, []string{"retained", "new", "untimed"}, lines)
+}
+
+func TestFollowStreamsLiveLinesAsEvents(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ch := make(chan types.LogLine, 3)
+	ch <- types.LogLine{Timestamp: base, Stream: "weather-1", Line: "retained"}
+	ch <- types.LogLine{Timestamp: base.Add(time.Minute), Stream: "weather-1", Line: "new"}
+	ch <- types.LogLine{Line: "untimed"}
+	close(ch)
+
+	var buf bytes.Buffer
+	w := &eventWriter{w: &buf, id: 1}
+	follow(context.Background(), ch, time.Time{}, base, w)
+	require.Equal(t,
+		"id: 2\ndata: {\"timestamp\":\"2026-03-01T12:01:00Z\",\"stream\":\"weather-1\",\"line\":\"new\"}\n\n"+
+			"id: 3\ndata: {\"timestamp\":\"0001-01-01T00:00:00Z\",\"line\":\"untimed\"}\n\n",
+		buf.String())
+}
+
+func TestFollowStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var buf bytes.Buffer
+	follow(ctx, make(chan types.LogLine), time.Time{}, time.Time{}, &eventWriter{w: &buf})
+	require.Empty(t, buf.String())
+}
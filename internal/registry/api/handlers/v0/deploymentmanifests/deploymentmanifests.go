@@ -0,0 +1,100 @@
+// Package deploymentmanifests owns the Deployment manifests subresource:
+// `GET /v0/deployments/{name}/manifests`. It serves the runtime artifacts
+// the Deployment controller last applied — compose services and gateway
+// config for Local runtimes, kagent/kmcp resources and Helm releases for
+// Kubernetes — so operators can see what is running without access to the
+// runtime host. Secret values are redacted before the controller records
+// them; nothing unredacted is stored.
+package deploymentmanifests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Store reads recorded manifests. *v1alpha1store.DeploymentManifestStore
+// satisfies it; tests supply a fake.
+type Store interface {
+	Get(ctx context.Context, namespace, name string) (*v1alpha1store.DeploymentManifests, error)
+}
+
+var _ Store = (*v1alpha1store.DeploymentManifestStore)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// Authorize gates the request the same way the regular Deployment GET
+	// handler does (verb "get"). Manifests name images, hosts and config
+	// even with secrets redacted. nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type deploymentManifestsInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+}
+
+type deploymentManifestsOutput struct {
+	Body arv0.DeploymentManifests
+}
+
+// Register wires GET {basePrefix}/deployments/{name}/manifests?namespace=default.
+// A Deployment that has not been applied yet, or has been removed, answers
+// 404.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-deployment-manifests",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/deployments/{name}/manifests",
+		Summary:     "Get the runtime manifests last applied for a deployment",
+		Description: "Returns the compose file, gateway config, Kubernetes resources or Helm releases the controller last applied, as YAML with secret values replaced by `REDACTED`.",
+	}, func(ctx context.Context, in *deploymentManifestsInput) (*deploymentManifestsOutput, error) {
+		ns := in.Namespace
+		if ns == "" {
+			ns = v1alpha1.DefaultNamespace
+		}
+		name, err := resource.UnescapeName(in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, resource.AuthorizeInput{
+				Verb: "get", Kind: v1alpha1.KindDeployment,
+				Namespace: ns, Name: name,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		rec, err := cfg.Store.Get(ctx, ns, name)
+		if err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("no manifests recorded for Deployment %q/%q", ns, name))
+			}
+			return nil, huma.Error500InternalServerError("fetch deployment manifests", err)
+		}
+
+		out := &deploymentManifestsOutput{Body: arv0.DeploymentManifests{
+			Namespace:  rec.Namespace,
+			Name:       rec.Name,
+			Generation: rec.Generation,
+			Runtime:    rec.Runtime,
+			AppliedAt:  rec.AppliedAt.UTC(),
+			Manifests:  make([]arv0.DeploymentManifest, 0, len(rec.Manifests)),
+		}}
+		for _, m := range rec.Manifests {
+			out.Body.Manifests = append(out.Body.Manifests, arv0.DeploymentManifest{Name: m.Name, Content: m.Content})
+		}
+		return out, nil
+	})
+}
@@ -0,0 +1,65 @@
+package deploymentmanifests_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentmanifests"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore map[string]*v1alpha1store.DeploymentManifests
+
+func (f fakeStore) Get(_ context.Context, namespace, name string) (*v1alpha1store.DeploymentManifests, error) {
+	if m, ok := f[namespace+"/"+name]; ok {
+		return m, nil
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+func TestGetDeploymentManifests(t *testing.T) {
+	applied := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	store := fakeStore{
+		"default/bot-prod": {
+			Namespace: "default", Name: "bot-prod", Generation: 3, Runtime: "Kubernetes", AppliedAt: applied,
+			Manifests: []v1alpha1store.DeploymentManifest{{Name: "Agent/kagent/bot-prod", Content: "kind: Agent\n"}},
+		},
+		"team-a/secret": {Namespace: "team-a", Name: "secret"},
+	}
+	_, api := humatest.New(t)
+	deploymentmanifests.Register(api, deploymentmanifests.Config{
+		BasePrefix: "/v0",
+		Store:      store,
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			if in.Namespace == "team-a" {
+				return huma.Error403Forbidden("denied")
+			}
+			return nil
+		},
+	})
+
+	resp := api.Get("/v0/deployments/bot-prod/manifests")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var got arv0.DeploymentManifests
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	require.Equal(t, arv0.DeploymentManifests{
+		Namespace: "default", Name: "bot-prod", Generation: 3, Runtime: "Kubernetes", AppliedAt: applied,
+		Manifests: []arv0.DeploymentManifest{{Name: "Agent/kagent/bot-prod", Content: "kind: Agent\n"}},
+	}, got)
+
+	resp = api.Get("/v0/deployments/secret/manifests?namespace=team-a")
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = api.Get("/v0/deployments/missing/manifests")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
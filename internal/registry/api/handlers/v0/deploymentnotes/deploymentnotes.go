@@ -0,0 +1,213 @@
+// Package deploymentnotes owns the Deployment notes subresource:
+// `GET/PATCH /v0/deployments/{name}/notes`. Operators attach a free-form
+// note ("rolled back due to X") and titled links (runbook, dashboard) to a
+// Deployment. Every PATCH appends a revision rather than overwriting, so
+// GET returns the current notes together with who changed them and when.
+package deploymentnotes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Store reads and appends note revisions.
+// *v1alpha1store.DeploymentNoteStore satisfies it; tests supply a fake.
+type Store interface {
+	Append(ctx context.Context, n v1alpha1store.DeploymentNote) (*v1alpha1store.DeploymentNote, error)
+	History(ctx context.Context, namespace, name string) ([]v1alpha1store.DeploymentNote, error)
+}
+
+var _ Store = (*v1alpha1store.DeploymentNoteStore)(nil)
+
+// Deployments looks up the Deployment the notes belong to.
+// *v1alpha1store.Store satisfies it.
+type Deployments interface {
+	GetLatest(ctx context.Context, namespace, name string) (*v1alpha1.RawObject, error)
+}
+
+var _ Deployments = (*v1alpha1store.Store)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix  string
+	Deployments Deployments
+	Store       Store
+	// Authorize gates reads with verb "get" and edits with verb "apply",
+	// the same as the Deployment itself. nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type getNotesInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+}
+
+type patchNotesInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Body      arv0.DeploymentNotesPatch
+}
+
+type notesOutput struct {
+	Body arv0.DeploymentNotes
+}
+
+// Register wires GET and PATCH {basePrefix}/deployments/{name}/notes
+// ?namespace=default. Both answer 404 when the Deployment does not exist.
+func Register(api huma.API, cfg Config) {
+	path := cfg.BasePrefix + "/deployments/{name}/notes"
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-deployment-notes",
+		Method:      http.MethodGet,
+		Path:        path,
+		Summary:     "Get a deployment's notes and links",
+		Description: "Returns the current note and links, and every earlier revision, newest first.",
+	}, func(ctx context.Context, in *getNotesInput) (*notesOutput, error) {
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "get", ns, name); err != nil {
+			return nil, err
+		}
+		if err := exists(ctx, cfg, ns, name); err != nil {
+			return nil, err
+		}
+		history, err := cfg.Store.History(ctx, ns, name)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("fetch deployment notes", err)
+		}
+		return &notesOutput{Body: notes(ns, name, history)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "patch-deployment-notes",
+		Method:      http.MethodPatch,
+		Path:        path,
+		Summary:     "Edit a deployment's notes and links",
+		Description: "Saves a new revision. Omitted fields keep their current value; an empty note or links list clears it. Earlier revisions stay in the history.",
+	}, func(ctx context.Context, in *patchNotesInput) (*notesOutput, error) {
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "apply", ns, name); err != nil {
+			return nil, err
+		}
+		if err := validateLinks(in.Body.Links); err != nil {
+			return nil, err
+		}
+		if err := exists(ctx, cfg, ns, name); err != nil {
+			return nil, err
+		}
+		history, err := cfg.Store.History(ctx, ns, name)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("fetch deployment notes", err)
+		}
+
+		next := v1alpha1store.DeploymentNote{Namespace: ns, Name: name, Author: auth.SubjectFrom(ctx)}
+		if len(history) > 0 {
+			next.Note, next.Links = history[0].Note, history[0].Links
+		}
+		if in.Body.Note != nil {
+			next.Note = *in.Body.Note
+		}
+		if in.Body.Links != nil {
+			next.Links = make([]v1alpha1store.DeploymentLink, 0, len(in.Body.Links))
+			for _, l := range in.Body.Links {
+				next.Links = append(next.Links, v1alpha1store.DeploymentLink{Title: l.Title, URL: l.URL})
+			}
+		}
+		saved, err := cfg.Store.Append(ctx, next)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("save deployment notes", err)
+		}
+		return &notesOutput{Body: notes(ns, name, append([]v1alpha1store.DeploymentNote{*saved}, history...))}, nil
+	})
+}
+
+// validateLinks requires every link to be an absolute http(s) URL, so
+// dashboards can render them as clickable without sanitizing.
+func validateLinks(links []arv0.DeploymentLink) error {
+	for i, l := range links {
+		u, err := url.Parse(l.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return huma.Error422UnprocessableEntity(fmt.Sprintf("links[%d].url %q is not an http or https URL", i, l.URL))
+		}
+	}
+	return nil
+}
+
+// notes converts a newest-first revision history to the API shape.
+func notes(ns, name string, history []v1alpha1store.DeploymentNote) arv0.DeploymentNotes {
+	out := arv0.DeploymentNotes{
+		Namespace: ns,
+		Name:      name,
+		Links:     []arv0.DeploymentLink{},
+		History:   make([]arv0.DeploymentNotesRevision, 0, len(history)),
+	}
+	for _, n := range history {
+		links := make([]arv0.DeploymentLink, 0, len(n.Links))
+		for _, l := range n.Links {
+			links = append(links, arv0.DeploymentLink{Title: l.Title, URL: l.URL})
+		}
+		out.History = append(out.History, arv0.DeploymentNotesRevision{
+			Revision:  n.ID,
+			Note:      n.Note,
+			Links:     links,
+			Author:    n.Author,
+			CreatedAt: n.CreatedAt.UTC(),
+		})
+	}
+	if len(out.History) > 0 {
+		current := out.History[0]
+		updatedAt := current.CreatedAt
+		out.Note, out.Links = current.Note, current.Links
+		out.UpdatedBy, out.UpdatedAt = current.Author, &updatedAt
+	}
+	return out
+}
+
+func parse(namespace, rawName string) (ns, name string, err error) {
+	ns = namespace
+	if ns == "" {
+		ns = v1alpha1.DefaultNamespace
+	}
+	if name, err = resource.UnescapeName(rawName); err != nil {
+		return "", "", err
+	}
+	return ns, name, nil
+}
+
+func authorize(ctx context.Context, cfg Config, verb, ns, name string) error {
+	if cfg.Authorize == nil {
+		return nil
+	}
+	return cfg.Authorize(ctx, resource.AuthorizeInput{
+		Verb: verb, Kind: v1alpha1.KindDeployment,
+		Namespace: ns, Name: name,
+	})
+}
+
+func exists(ctx context.Context, cfg Config, ns, name string) error {
+	if _, err := cfg.Deployments.GetLatest(ctx, ns, name); err != nil {
+		if errors.Is(err, pkgdb.ErrNotFound) {
+			return huma.Error404NotFound(fmt.Sprintf("Deployment %q/%q not found", ns, name))
+		}
+		return huma.Error500InternalServerError("fetch Deployment", err)
+	}
+	return nil
+}
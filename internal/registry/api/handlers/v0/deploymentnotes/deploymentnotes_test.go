@@ -0,0 +1,154 @@
+package deploymentnotes_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentnotes"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/internal/testapi"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeDeployments map[string]bool
+
+func (f fakeDeployments) GetLatest(_ context.Context, namespace, name string) (*v1alpha1.RawObject, error) {
+	if !f[namespace+"/"+name] {
+		return nil, pkgdb.ErrNotFound
+	}
+	return &v1alpha1.RawObject{Metadata: v1alpha1.ObjectMeta{Namespace: namespace, Name: name}}, nil
+}
+
+// fakeStore keeps revisions oldest first, like the table's id order.
+type fakeStore struct {
+	notes []v1alpha1store.DeploymentNote
+}
+
+func (f *fakeStore) Append(_ context.Context, n v1alpha1store.DeploymentNote) (*v1alpha1store.DeploymentNote, error) {
+	n.ID = int64(len(f.notes) + 1)
+	n.CreatedAt = time.Date(2026, 10, 18, 9, 0, len(f.notes), 0, time.UTC)
+	f.notes = append(f.notes, n)
+	return &n, nil
+}
+
+func (f *fakeStore) History(_ context.Context, namespace, name string) ([]v1alpha1store.DeploymentNote, error) {
+	var out []v1alpha1store.DeploymentNote
+	for i := len(f.notes) - 1; i >= 0; i-- {
+		if f.notes[i].Namespace == namespace && f.notes[i].Name == name {
+			out = append(out, f.notes[i])
+		}
+	}
+	return out, nil
+}
+
+func newAPI(t *testing.T, store *fakeStore) humatest.TestAPI {
+	t.Helper()
+	api := testapi.New(t)
+	deploymentnotes.Register(api, deploymentnotes.Config{
+		BasePrefix:  "/v0",
+		Deployments: fakeDeployments{"default/bot-prod": true, "team-a/bot-prod": true},
+		Store:       store,
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			if in.Namespace == "team-a" && in.Verb == "apply" {
+				return huma.Error403Forbidden("denied")
+			}
+			return nil
+		},
+	})
+	return api
+}
+
+func decode(t *testing.T, body []byte) arv0.DeploymentNotes {
+	t.Helper()
+	var notes arv0.DeploymentNotes
+	require.NoError(t, json.Unmarshal(body, &notes))
+	return notes
+}
+
+func TestPatchAppendsRevisions(t *testing.T) {
+	store := &fakeStore{}
+	api := newAPI(t, store)
+
+	resp := api.Get("/v0/deployments/bot-prod/notes")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	notes := decode(t, resp.Body.Bytes())
+	require.Empty(t, notes.Note)
+	require.Empty(t, notes.Links)
+	require.Empty(t, notes.History)
+	require.Nil(t, notes.UpdatedAt)
+
+	resp = api.Patch("/v0/deployments/bot-prod/notes", "X-Subject: alice", map[string]any{
+		"note":  "canary at 10%",
+		"links": []map[string]string{{"title": "Runbook", "url": "https://runbooks.example.com/bot"}},
+	})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	// Only the note changes; the links carry over from the last revision.
+	resp = api.Patch("/v0/deployments/bot-prod/notes", "X-Subject: bob", map[string]any{
+		"note": "rolled back due to 5xx spike",
+	})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	notes = decode(t, resp.Body.Bytes())
+	require.Equal(t, "rolled back due to 5xx spike", notes.Note)
+	require.Equal(t, []arv0.DeploymentLink{{Title: "Runbook", URL: "https://runbooks.example.com/bot"}}, notes.Links)
+	require.Equal(t, "bob", notes.UpdatedBy)
+	require.NotNil(t, notes.UpdatedAt)
+	require.Len(t, notes.History, 2)
+	require.Equal(t, "alice", notes.History[1].Author)
+	require.Equal(t, "canary at 10%", notes.History[1].Note)
+
+	// An empty links list clears them.
+	resp = api.Patch("/v0/deployments/bot-prod/notes", map[string]any{"links": []any{}})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	resp = api.Get("/v0/deployments/bot-prod/notes")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	notes = decode(t, resp.Body.Bytes())
+	require.Equal(t, "rolled back due to 5xx spike", notes.Note)
+	require.Empty(t, notes.Links)
+	require.Len(t, notes.History, 3)
+	require.Equal(t, int64(3), notes.History[0].Revision)
+	require.Len(t, store.notes, 3)
+}
+
+func TestPatchRejects(t *testing.T) {
+	store := &fakeStore{}
+	api := newAPI(t, store)
+
+	for _, tc := range []struct {
+		name string
+		path string
+		body map[string]any
+		want int
+	}{
+		{"missing deployment", "/v0/deployments/missing/notes", map[string]any{"note": "x"}, http.StatusNotFound},
+		{"non-http link", "/v0/deployments/bot-prod/notes", map[string]any{
+			"links": []map[string]string{{"title": "Runbook", "url": "javascript:alert(1)"}},
+		}, http.StatusUnprocessableEntity},
+		{"link without title", "/v0/deployments/bot-prod/notes", map[string]any{
+			"links": []map[string]string{{"title": "", "url": "https://example.com"}},
+		}, http.StatusUnprocessableEntity},
+		{"unauthorized", "/v0/deployments/bot-prod/notes?namespace=team-a", map[string]any{"note": "x"}, http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := api.Patch(tc.path, tc.body)
+			require.Equal(t, tc.want, resp.Code, resp.Body.String())
+		})
+	}
+	require.Empty(t, store.notes)
+
+	resp := api.Get("/v0/deployments/missing/notes")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	resp = api.Get("/v0/deployments/bot-prod/notes?namespace=team-a")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+}
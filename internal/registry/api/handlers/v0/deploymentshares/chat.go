@@ -0,0 +1,116 @@
+package deploymentshares
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// ChatTimeout bounds one shared chat request, `message/stream` event
+// streams included, so a token holder cannot hold a handler open for as
+// long as the agent takes to answer.
+const ChatTimeout = 5 * time.Minute
+
+// maxChatBody caps the JSON-RPC request a shared chat forwards.
+const maxChatBody = 1 << 20
+
+type chatInput struct {
+	Token   string `path:"token"`
+	RawBody []byte `contentType:"application/json" doc:"A2A JSON-RPC request."`
+}
+
+// registerChat wires the route that proxies A2A JSON-RPC requests from a
+// token holder to the shared Deployment's agent.
+func registerChat(api huma.API, cfg Config, path string) {
+	client := cfg.Client
+	if client == nil {
+		client = httpclient.New(ChatTimeout)
+	}
+	huma.Register(api, huma.Operation{
+		OperationID: "chat-shared-deployment",
+		Method:      http.MethodPost,
+		Path:        path,
+		Summary:     "Chat with a deployed agent through a share link",
+		Description: "Forwards the A2A JSON-RPC request to the endpoint the runtime reported for the deployment and relays the answer, including `message/stream` event streams.",
+		// The request is forwarded as is; the agent validates it.
+		SkipValidateBody: true,
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "The agent's JSON-RPC response, or its server-sent event stream",
+				Content: map[string]*huma.MediaType{
+					"application/json":  {},
+					"text/event-stream": {},
+				},
+			},
+		},
+	}, func(ctx context.Context, in *chatInput) (*huma.StreamResponse, error) {
+		if len(in.RawBody) > maxChatBody {
+			return nil, huma.NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("chat requests are limited to %d bytes", maxChatBody))
+		}
+		_, deployment, err := resolve(ctx, cfg, in.Token)
+		if err != nil {
+			return nil, err
+		}
+		endpoint := chatEndpoint(deployment)
+		if endpoint == "" {
+			return nil, huma.Error409Conflict("the deployment has no chat endpoint; only deployed agents can be chatted with")
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(in.RawBody))
+		if err != nil {
+			return nil, huma.Error500InternalServerError("build chat request", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, huma.Error502BadGateway("reach agent: " + err.Error())
+		}
+		return &huma.StreamResponse{Body: func(hctx huma.Context) {
+			defer resp.Body.Close()
+			if ct := resp.Header.Get("Content-Type"); ct != "" {
+				hctx.SetHeader("Content-Type", ct)
+			}
+			hctx.SetStatus(resp.StatusCode)
+			relay(hctx.BodyWriter(), resp.Body)
+		}}, nil
+	})
+}
+
+// chatEndpoint is the A2A URL the runtime reported for an Agent
+// Deployment, or "" when there is none.
+func chatEndpoint(deployment *v1alpha1.Deployment) string {
+	if deployment.Spec.TargetRef.Kind != v1alpha1.KindAgent {
+		return ""
+	}
+	return deployment.Metadata.Annotations[v1alpha1.DeploymentEndpointAnnotation]
+}
+
+// relay copies the agent's response, flushing after every read so event
+// streams reach the client as they are produced.
+func relay(w io.Writer, r io.Reader) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if rw, ok := w.(http.ResponseWriter); ok {
+				_ = http.NewResponseController(rw).Flush()
+			} else if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
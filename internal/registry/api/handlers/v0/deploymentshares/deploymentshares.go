@@ -0,0 +1,396 @@
+// Package deploymentshares owns Deployment share links: expiring tokens
+// that let an outside collaborator without a registry account read one
+// Deployment's status and logs and chat with the deployed agent.
+//
+// Owners manage links on the Deployment:
+//   - POST   /v0/deployments/{name}/share        create a link
+//   - GET    /v0/deployments/{name}/shares       list links
+//   - DELETE /v0/deployments/{name}/shares/{id}  revoke a link
+//
+// Holders of a token use it in place of credentials:
+//   - GET  /v0/shared/{token}       status
+//   - GET  /v0/shared/{token}/logs  recent logs
+//   - POST /v0/shared/{token}/chat  A2A JSON-RPC, proxied to the agent
+//
+// The shared routes are read-only apart from chat and are served without
+// authentication: the token is the credential. Only the SHA-256 of its
+// secret is stored. Every request a token authenticates is counted on its
+// link, and creating and revoking links reaches the registry's Auditor.
+package deploymentshares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// PathSegment is the segment appended to the base prefix for the routes a
+// share token authenticates. The authn middleware skips paths under it.
+const PathSegment = "/shared"
+
+// TokenPrefix starts every share token, so secret scanners can spot
+// leaked links.
+const TokenPrefix = "arshare_"
+
+const (
+	defaultExpiry = 24 * time.Hour
+	maxExpiry     = 30 * 24 * time.Hour
+	// maxLogLines caps the lines a shared logs request returns.
+	maxLogLines = 1000
+)
+
+// Store reads and writes share links.
+// *v1alpha1store.DeploymentShareStore satisfies it; tests supply a fake.
+type Store interface {
+	Create(ctx context.Context, share *v1alpha1store.DeploymentShare) (*v1alpha1store.DeploymentShare, error)
+	Get(ctx context.Context, id string) (*v1alpha1store.DeploymentShare, error)
+	List(ctx context.Context, namespace, name string) ([]*v1alpha1store.DeploymentShare, error)
+	Revoke(ctx context.Context, id, by string) (*v1alpha1store.DeploymentShare, error)
+	Touch(ctx context.Context, id string, at time.Time) error
+}
+
+var _ Store = (*v1alpha1store.DeploymentShareStore)(nil)
+
+// Deployments looks up the shared Deployment. *v1alpha1store.Store
+// satisfies it.
+type Deployments interface {
+	GetLatest(ctx context.Context, namespace, name string) (*v1alpha1.RawObject, error)
+}
+
+var _ Deployments = (*v1alpha1store.Store)(nil)
+
+// LogResolver reads a Deployment's logs from its runtime adapter.
+type LogResolver interface {
+	Logs(ctx context.Context, deployment *v1alpha1.Deployment, in types.LogsInput) (<-chan types.LogLine, error)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix  string
+	Deployments Deployments
+	Store       Store
+	// LogResolver serves shared logs. nil leaves /logs answering 501.
+	LogResolver LogResolver
+	// Client forwards shared chat requests to the deployed agent. Build it
+	// with httpclient.New so it honors the outbound TLS and proxy
+	// settings. nil uses httpclient.New(ChatTimeout).
+	Client *http.Client
+	// Auditor receives link creations and revocations when it implements
+	// types.ShareAuditor. nil records nothing.
+	Auditor types.Auditor
+	// Authorize gates listing links with verb "get" and creating and
+	// revoking them with verb "apply", the same as the Deployment itself.
+	// nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type deploymentInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+}
+
+type createInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Body      arv0.DeploymentShareInput
+}
+
+type revokeInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	ID        string `path:"id" doc:"Share link ID"`
+}
+
+type createOutput struct {
+	Body arv0.DeploymentShareCreated
+}
+
+type shareOutput struct {
+	Body arv0.DeploymentShare
+}
+
+type listOutput struct {
+	Body arv0.DeploymentShareList
+}
+
+type tokenInput struct {
+	Token string `path:"token"`
+}
+
+type sharedLogsInput struct {
+	Token     string `path:"token"`
+	TailLines int    `query:"tailLines" doc:"Max lines, newest last; 0 or more than 1000 means 1000."`
+}
+
+type sharedOutput struct {
+	Body arv0.SharedDeployment
+}
+
+type sharedLogsOutput struct {
+	Body arv0.DeploymentLogs
+}
+
+// Register wires the share link management routes under
+// {basePrefix}/deployments/{name} and the token routes under
+// {basePrefix}/shared/{token}.
+func Register(api huma.API, cfg Config) {
+	base := cfg.BasePrefix + "/deployments/{name}"
+	shared := cfg.BasePrefix + PathSegment + "/{token}"
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-deployment-share",
+		Method:        http.MethodPost,
+		Path:          base + "/share",
+		Summary:       "Create an expiring share link for a deployment",
+		Description:   "The token is returned only once. Anyone holding it can read the deployment's status and logs and chat with the deployed agent under `/v0/shared/{token}` until the link expires or is revoked.",
+		DefaultStatus: http.StatusCreated,
+	}, func(ctx context.Context, in *createInput) (*createOutput, error) {
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "apply", ns, name); err != nil {
+			return nil, err
+		}
+		expiresIn, err := parseExpiry(in.Body.ExpiresIn)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := getDeployment(ctx, cfg, ns, name); err != nil {
+			return nil, err
+		}
+		id, secret, token, err := NewToken()
+		if err != nil {
+			return nil, huma.Error500InternalServerError("generate share token", err)
+		}
+		created, err := cfg.Store.Create(ctx, &v1alpha1store.DeploymentShare{
+			ID:         id,
+			Namespace:  ns,
+			Name:       name,
+			SecretHash: hashSecret(secret),
+			CreatedBy:  auth.SubjectFrom(ctx),
+			ExpiresAt:  time.Now().Add(expiresIn).UTC(),
+		})
+		if err != nil {
+			return nil, huma.Error500InternalServerError("create share link", err)
+		}
+		if sa, ok := cfg.Auditor.(types.ShareAuditor); ok {
+			sa.DeploymentShareCreated(ctx, ns, name, created.ID)
+		}
+		return &createOutput{Body: arv0.DeploymentShareCreated{
+			Share: toWire(created),
+			Token: token,
+			Path:  cfg.BasePrefix + PathSegment + "/" + token,
+		}}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-deployment-shares",
+		Method:      http.MethodGet,
+		Path:        base + "/shares",
+		Summary:     "List a deployment's share links, newest first",
+		Description: "Revoked and expired links are included, with who created and revoked them and how often they were used.",
+	}, func(ctx context.Context, in *deploymentInput) (*listOutput, error) {
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "get", ns, name); err != nil {
+			return nil, err
+		}
+		shares, err := cfg.Store.List(ctx, ns, name)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list share links", err)
+		}
+		out := &listOutput{Body: arv0.DeploymentShareList{Shares: make([]arv0.DeploymentShare, 0, len(shares))}}
+		for _, share := range shares {
+			out.Body.Shares = append(out.Body.Shares, toWire(share))
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "revoke-deployment-share",
+		Method:      http.MethodDelete,
+		Path:        base + "/shares/{id}",
+		Summary:     "Revoke a deployment's share link",
+	}, func(ctx context.Context, in *revokeInput) (*shareOutput, error) {
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "apply", ns, name); err != nil {
+			return nil, err
+		}
+		share, err := cfg.Store.Get(ctx, in.ID)
+		if err != nil && !errors.Is(err, pkgdb.ErrNotFound) {
+			return nil, huma.Error500InternalServerError("get share link", err)
+		}
+		// A link of another Deployment answers 404 so IDs can't be probed
+		// through a Deployment the caller may edit.
+		if share == nil || share.Namespace != ns || share.Name != name {
+			return nil, huma.Error404NotFound(fmt.Sprintf("share link %s of Deployment %q/%q not found", in.ID, ns, name))
+		}
+		wasActive := share.RevokedAt == nil
+		share, err = cfg.Store.Revoke(ctx, in.ID, auth.SubjectFrom(ctx))
+		if err != nil {
+			return nil, huma.Error500InternalServerError("revoke share link", err)
+		}
+		if sa, ok := cfg.Auditor.(types.ShareAuditor); ok && wasActive {
+			sa.DeploymentShareRevoked(ctx, ns, name, share.ID)
+		}
+		return &shareOutput{Body: toWire(share)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-shared-deployment",
+		Method:      http.MethodGet,
+		Path:        shared,
+		Summary:     "Get the status of a deployment through a share link",
+		Description: "Needs no credentials: the token in the path grants read access to this one deployment.",
+	}, func(ctx context.Context, in *tokenInput) (*sharedOutput, error) {
+		share, deployment, err := resolve(ctx, cfg, in.Token)
+		if err != nil {
+			return nil, err
+		}
+		return &sharedOutput{Body: sharedView(share, deployment)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-shared-deployment-logs",
+		Method:      http.MethodGet,
+		Path:        shared + "/logs",
+		Summary:     "Get recent logs of a deployment through a share link",
+	}, func(ctx context.Context, in *sharedLogsInput) (*sharedLogsOutput, error) {
+		if cfg.LogResolver == nil {
+			return nil, huma.Error501NotImplemented("deployment logs are not available")
+		}
+		_, deployment, err := resolve(ctx, cfg, in.Token)
+		if err != nil {
+			return nil, err
+		}
+		tailLines := in.TailLines
+		if tailLines <= 0 || tailLines > maxLogLines {
+			tailLines = maxLogLines
+		}
+		ch, err := cfg.LogResolver.Logs(ctx, deployment, types.LogsInput{TailLines: tailLines})
+		if err != nil {
+			return nil, huma.Error502BadGateway("adapter logs: " + err.Error())
+		}
+		out := &sharedLogsOutput{Body: arv0.DeploymentLogs{Lines: []arv0.DeploymentLogLine{}}}
+		for line := range ch {
+			out.Body.Lines = append(out.Body.Lines, arv0.DeploymentLogLine{
+				Timestamp: line.Timestamp.Format(time.RFC3339),
+				Stream:    line.Stream,
+				Line:      line.Line,
+			})
+			if len(out.Body.Lines) > tailLines {
+				out.Body.Lines = out.Body.Lines[1:]
+			}
+		}
+		return out, nil
+	})
+
+	registerChat(api, cfg, shared+"/chat")
+}
+
+func getDeployment(ctx context.Context, cfg Config, ns, name string) (*v1alpha1.Deployment, error) {
+	row, err := cfg.Deployments.GetLatest(ctx, ns, name)
+	if err != nil {
+		if errors.Is(err, pkgdb.ErrNotFound) {
+			return nil, huma.Error404NotFound(fmt.Sprintf("Deployment %q/%q not found", ns, name))
+		}
+		return nil, huma.Error500InternalServerError("fetch Deployment", err)
+	}
+	deployment, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} }, row, v1alpha1.KindDeployment)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("decode Deployment", err)
+	}
+	return deployment, nil
+}
+
+func sharedView(share *v1alpha1store.DeploymentShare, deployment *v1alpha1.Deployment) arv0.SharedDeployment {
+	out := arv0.SharedDeployment{
+		Namespace:    share.Namespace,
+		Name:         share.Name,
+		TargetKind:   deployment.Spec.TargetRef.Kind,
+		TargetName:   deployment.Spec.TargetRef.Name,
+		TargetTag:    deployment.Spec.TargetRef.Tag,
+		DesiredState: deployment.Spec.DesiredState,
+		Conditions:   make([]arv0.DeploymentCondition, 0, len(deployment.Status.Conditions)),
+		Chat:         chatEndpoint(deployment) != "",
+		ExpiresAt:    share.ExpiresAt,
+	}
+	for _, c := range deployment.Status.Conditions {
+		out.Conditions = append(out.Conditions, arv0.DeploymentCondition{
+			Type:               c.Type,
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime,
+		})
+	}
+	return out
+}
+
+func parseExpiry(value string) (time.Duration, error) {
+	if value == "" {
+		return defaultExpiry, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, huma.Error400BadRequest(fmt.Sprintf("expiresIn must be a positive duration such as 24h, got %q", value))
+	}
+	if d > maxExpiry {
+		return 0, huma.Error400BadRequest(fmt.Sprintf("expiresIn must be at most %s", maxExpiry))
+	}
+	return d, nil
+}
+
+func parse(namespace, rawName string) (string, string, error) {
+	if namespace == "" {
+		namespace = v1alpha1.DefaultNamespace
+	}
+	name, err := resource.UnescapeName(rawName)
+	if err != nil {
+		return "", "", err
+	}
+	return namespace, name, nil
+}
+
+func authorize(ctx context.Context, cfg Config, verb, ns, name string) error {
+	if cfg.Authorize == nil {
+		return nil
+	}
+	return cfg.Authorize(ctx, resource.AuthorizeInput{
+		Verb: verb, Kind: v1alpha1.KindDeployment,
+		Namespace: ns, Name: name,
+	})
+}
+
+func toWire(share *v1alpha1store.DeploymentShare) arv0.DeploymentShare {
+	return arv0.DeploymentShare{
+		ID:         share.ID,
+		Namespace:  share.Namespace,
+		Name:       share.Name,
+		CreatedBy:  share.CreatedBy,
+		CreatedAt:  share.CreatedAt,
+		ExpiresAt:  share.ExpiresAt,
+		RevokedAt:  share.RevokedAt,
+		RevokedBy:  share.RevokedBy,
+		UseCount:   share.UseCount,
+		LastUsedAt: share.LastUsedAt,
+	}
+}
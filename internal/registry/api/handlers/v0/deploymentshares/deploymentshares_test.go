@@ -0,0 +1,322 @@
+package deploymentshares_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentshares"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore struct {
+	shares map[string]*v1alpha1store.DeploymentShare
+}
+
+func (f *fakeStore) Create(_ context.Context, share *v1alpha1store.DeploymentShare) (*v1alpha1store.DeploymentShare, error) {
+	if _, ok := f.shares[share.ID]; ok {
+		return nil, pkgdb.ErrAlreadyExists
+	}
+	out := *share
+	out.CreatedAt = time.Now().UTC()
+	f.shares[share.ID] = &out
+	return &out, nil
+}
+
+func (f *fakeStore) Get(_ context.Context, id string) (*v1alpha1store.DeploymentShare, error) {
+	share, ok := f.shares[id]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	out := *share
+	return &out, nil
+}
+
+func (f *fakeStore) List(_ context.Context, namespace, name string) ([]*v1alpha1store.DeploymentShare, error) {
+	var out []*v1alpha1store.DeploymentShare
+	for _, share := range f.shares {
+		if share.Namespace == namespace && share.Name == name {
+			out = append(out, share)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) Revoke(_ context.Context, id, by string) (*v1alpha1store.DeploymentShare, error) {
+	share, ok := f.shares[id]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	if share.RevokedAt == nil {
+		now := time.Now().UTC()
+		share.RevokedAt, share.RevokedBy = &now, by
+	}
+	out := *share
+	return &out, nil
+}
+
+func (f *fakeStore) Touch(_ context.Context, id string, at time.Time) error {
+	share, ok := f.shares[id]
+	if !ok {
+		return pkgdb.ErrNotFound
+	}
+	share.UseCount++
+	share.LastUsedAt = &at
+	return nil
+}
+
+type fakeDeployments map[string]*v1alpha1.Deployment
+
+func (f fakeDeployments) GetLatest(_ context.Context, namespace, name string) (*v1alpha1.RawObject, error) {
+	d, ok := f[namespace+"/"+name]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	spec, err := json.Marshal(d.Spec)
+	if err != nil {
+		return nil, err
+	}
+	status, err := json.Marshal(d.Status)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.RawObject{Metadata: d.Metadata, Spec: spec, Status: status}, nil
+}
+
+type fakeAuditor struct {
+	events []string
+}
+
+func (a *fakeAuditor) ResourceTagCreated(context.Context, string, string, string, string) {}
+
+func (a *fakeAuditor) DeploymentShareCreated(_ context.Context, namespace, name, shareID string) {
+	a.events = append(a.events, "created "+namespace+"/"+name+" "+shareID)
+}
+
+func (a *fakeAuditor) DeploymentShareRevoked(_ context.Context, namespace, name, shareID string) {
+	a.events = append(a.events, "revoked "+namespace+"/"+name+" "+shareID)
+}
+
+type fixture struct {
+	api     humatest.TestAPI
+	store   *fakeStore
+	auditor *fakeAuditor
+}
+
+func newFixture(t *testing.T, deployments fakeDeployments) *fixture {
+	t.Helper()
+	return newFixtureWithClient(t, deployments, nil)
+}
+
+func newFixtureWithClient(t *testing.T, deployments fakeDeployments, client *http.Client) *fixture {
+	t.Helper()
+	_, api := humatest.New(t)
+	f := &fixture{api: api, store: &fakeStore{shares: map[string]*v1alpha1store.DeploymentShare{}}, auditor: &fakeAuditor{}}
+	deploymentshares.Register(api, deploymentshares.Config{
+		BasePrefix:  "/v0",
+		Deployments: deployments,
+		Store:       f.store,
+		Client:      client,
+		Auditor:     f.auditor,
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			if in.Namespace == "team-a" {
+				return huma.Error403Forbidden("denied")
+			}
+			return nil
+		},
+	})
+	return f
+}
+
+func agentDeployment(endpoint string) *v1alpha1.Deployment {
+	d := &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "summarizer-prod"},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:    v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "summarizer", Tag: "1.4.2"},
+			DesiredState: v1alpha1.DesiredStateDeployed,
+		},
+	}
+	if endpoint != "" {
+		d.Metadata.Annotations = map[string]string{v1alpha1.DeploymentEndpointAnnotation: endpoint}
+	}
+	return d
+}
+
+func (f *fixture) create(t *testing.T, body map[string]any) arv0.DeploymentShareCreated {
+	t.Helper()
+	resp := f.api.Post("/v0/deployments/summarizer-prod/share", body)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	var out arv0.DeploymentShareCreated
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &out))
+	return out
+}
+
+func TestShare_CreateListRevoke(t *testing.T) {
+	f := newFixture(t, fakeDeployments{"default/summarizer-prod": agentDeployment("")})
+
+	created := f.create(t, map[string]any{"expiresIn": "2h"})
+	require.True(t, strings.HasPrefix(created.Token, deploymentshares.TokenPrefix+created.Share.ID+"_"))
+	require.Equal(t, "/v0/shared/"+created.Token, created.Path)
+	require.WithinDuration(t, time.Now().Add(2*time.Hour), created.Share.ExpiresAt, time.Minute)
+	require.NotContains(t, f.store.shares[created.Share.ID].SecretHash, strings.TrimPrefix(created.Token, deploymentshares.TokenPrefix+created.Share.ID+"_"))
+
+	resp := f.api.Get("/v0/deployments/summarizer-prod/shares")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var list arv0.DeploymentShareList
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Len(t, list.Shares, 1)
+	require.Equal(t, created.Share.ID, list.Shares[0].ID)
+
+	resp = f.api.Delete("/v0/deployments/summarizer-prod/shares/" + created.Share.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	resp = f.api.Delete("/v0/deployments/summarizer-prod/shares/" + created.Share.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	require.Equal(t, []string{
+		"created default/summarizer-prod " + created.Share.ID,
+		"revoked default/summarizer-prod " + created.Share.ID,
+	}, f.auditor.events)
+}
+
+func TestShare_CreateErrors(t *testing.T) {
+	f := newFixture(t, fakeDeployments{"default/summarizer-prod": agentDeployment("")})
+	cases := []struct {
+		name string
+		path string
+		body map[string]any
+		want int
+	}{
+		{"missing deployment", "/v0/deployments/missing/share", map[string]any{}, http.StatusNotFound},
+		{"forbidden", "/v0/deployments/summarizer-prod/share?namespace=team-a", map[string]any{}, http.StatusForbidden},
+		{"bad duration", "/v0/deployments/summarizer-prod/share", map[string]any{"expiresIn": "soon"}, http.StatusBadRequest},
+		{"too long", "/v0/deployments/summarizer-prod/share", map[string]any{"expiresIn": "1000h"}, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := f.api.Post(tc.path, tc.body)
+			require.Equal(t, tc.want, resp.Code, resp.Body.String())
+		})
+	}
+	require.Empty(t, f.store.shares)
+	require.Empty(t, f.auditor.events)
+}
+
+func TestShare_RevokeOtherDeploymentsLinkIsNotFound(t *testing.T) {
+	other := agentDeployment("")
+	other.Metadata.Name = "other"
+	f := newFixture(t, fakeDeployments{"default/summarizer-prod": agentDeployment(""), "default/other": other})
+	created := f.create(t, map[string]any{})
+
+	resp := f.api.Delete("/v0/deployments/other/shares/" + created.Share.ID)
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	require.Nil(t, f.store.shares[created.Share.ID].RevokedAt)
+}
+
+func TestShared_StatusCountsUses(t *testing.T) {
+	d := agentDeployment("http://agent.invalid/")
+	d.Status.Conditions = []v1alpha1.Condition{{Type: "Ready", Status: v1alpha1.ConditionTrue, Reason: "Deployed"}}
+	f := newFixture(t, fakeDeployments{"default/summarizer-prod": d})
+	created := f.create(t, map[string]any{})
+
+	resp := f.api.Get(created.Path)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var view arv0.SharedDeployment
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &view))
+	require.Equal(t, "summarizer-prod", view.Name)
+	require.Equal(t, v1alpha1.KindAgent, view.TargetKind)
+	require.Equal(t, "1.4.2", view.TargetTag)
+	require.True(t, view.Chat)
+	require.Len(t, view.Conditions, 1)
+	require.Equal(t, "Ready", view.Conditions[0].Type)
+
+	share := f.store.shares[created.Share.ID]
+	require.EqualValues(t, 1, share.UseCount)
+	require.NotNil(t, share.LastUsedAt)
+}
+
+func TestShared_RejectsBadRevokedAndExpiredTokens(t *testing.T) {
+	f := newFixture(t, fakeDeployments{"default/summarizer-prod": agentDeployment("")})
+	created := f.create(t, map[string]any{})
+
+	for _, token := range []string{"nope", deploymentshares.TokenPrefix + "unknown_secret", created.Token + "x"} {
+		resp := f.api.Get("/v0/shared/" + token)
+		require.Equal(t, http.StatusNotFound, resp.Code, token)
+	}
+
+	f.store.shares[created.Share.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	resp := f.api.Get(created.Path)
+	require.Equal(t, http.StatusGone, resp.Code, resp.Body.String())
+
+	revoked := f.create(t, map[string]any{})
+	resp = f.api.Delete("/v0/deployments/summarizer-prod/shares/" + revoked.Share.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	resp = f.api.Get(revoked.Path)
+	require.Equal(t, http.StatusGone, resp.Code, resp.Body.String())
+
+	require.Zero(t, f.store.shares[created.Share.ID].UseCount)
+}
+
+func TestShared_ChatProxiesToAgent(t *testing.T) {
+	var got string
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":{"kind":"message"}}`)
+	}))
+	defer agent.Close()
+
+	f := newFixture(t, fakeDeployments{"default/summarizer-prod": agentDeployment(agent.URL)})
+	created := f.create(t, map[string]any{})
+
+	req := `{"jsonrpc":"2.0","id":1,"method":"message/send"}`
+	resp := f.api.Post(created.Path+"/chat", "Content-Type: application/json", strings.NewReader(req))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.JSONEq(t, req, got)
+	require.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":{"kind":"message"}}`, resp.Body.String())
+}
+
+func TestShared_ChatTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	agent := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-release
+	}))
+	defer agent.Close()
+	defer close(release)
+
+	f := newFixtureWithClient(t, fakeDeployments{"default/summarizer-prod": agentDeployment(agent.URL)}, httpclient.New(50*time.Millisecond))
+	created := f.create(t, map[string]any{})
+
+	resp := f.api.Post(created.Path+"/chat", "Content-Type: application/json", strings.NewReader(`{}`))
+	require.Equal(t, http.StatusBadGateway, resp.Code, resp.Body.String())
+}
+
+func TestShared_ChatWithoutEndpointConflicts(t *testing.T) {
+	f := newFixture(t, fakeDeployments{"default/summarizer-prod": agentDeployment("")})
+	created := f.create(t, map[string]any{})
+
+	resp := f.api.Post(created.Path+"/chat", "Content-Type: application/json", strings.NewReader(`{}`))
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body.String())
+}
+
+func TestShared_LogsWithoutResolver(t *testing.T) {
+	f := newFixture(t, fakeDeployments{"default/summarizer-prod": agentDeployment("")})
+	created := f.create(t, map[string]any{})
+
+	resp := f.api.Get(created.Path + "/logs")
+	require.Equal(t, http.StatusNotImplemented, resp.Code, resp.Body.String())
+}
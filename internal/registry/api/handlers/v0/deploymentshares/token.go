@@ -0,0 +1,82 @@
+package deploymentshares
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// NewToken generates a share ID and secret and returns them with the token
+// that carries both.
+func NewToken() (id, secret, token string, err error) {
+	idBytes := make([]byte, 8)
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", "", err
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+	id, secret = hex.EncodeToString(idBytes), hex.EncodeToString(secretBytes)
+	return id, secret, TokenPrefix + id + "_" + secret, nil
+}
+
+// parseToken splits a token into its share ID and secret.
+func parseToken(token string) (id, secret string, ok bool) {
+	rest, ok := strings.CutPrefix(token, TokenPrefix)
+	if !ok {
+		return "", "", false
+	}
+	id, secret, ok = strings.Cut(rest, "_")
+	return id, secret, ok && id != "" && secret != ""
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// resolve authenticates token and returns its share and Deployment.
+// Unknown tokens answer 404, revoked and expired ones 410. Every
+// successful use is counted on the share.
+func resolve(ctx context.Context, cfg Config, token string) (*v1alpha1store.DeploymentShare, *v1alpha1.Deployment, error) {
+	notFound := huma.Error404NotFound("share link not found")
+	id, secret, ok := parseToken(token)
+	if !ok {
+		return nil, nil, notFound
+	}
+	share, err := cfg.Store.Get(ctx, id)
+	switch {
+	case errors.Is(err, pkgdb.ErrNotFound):
+		return nil, nil, notFound
+	case err != nil:
+		return nil, nil, huma.Error500InternalServerError("get share link", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(share.SecretHash)) != 1 {
+		return nil, nil, notFound
+	}
+	now := time.Now()
+	if !share.Active(now) {
+		return nil, nil, huma.Error410Gone("share link is revoked or expired")
+	}
+	if err := cfg.Store.Touch(ctx, share.ID, now); err != nil {
+		slog.Warn("failed to record deployment share use", "share", share.ID, "error", err)
+	}
+	deployment, err := getDeployment(ctx, cfg, share.Namespace, share.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return share, deployment, nil
+}
@@ -0,0 +1,335 @@
+// Package deploymenttraffic relays and records MCP traffic of Deployments
+// in traffic debug mode, so "why does my agent's tool call fail?" can be
+// answered without a packet capture.
+//
+// A Deployment opts in with the v1alpha1.DeploymentDebugTrafficAnnotation
+// annotation. Its clients then send MCP streamable-HTTP requests to the
+// registry instead of the gateway:
+//   - POST /v0/deployments/{name}/mcp      relay to the runtime's MCP gateway
+//   - GET  /v0/deployments/{name}/traffic  the recorded frames
+//
+// Every request and response frame passing through the relay is redacted
+// and kept in the mcptraffic.Recorder ring of the Deployment.
+package deploymenttraffic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/mcptraffic"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// RelayTimeout bounds one relayed MCP request, streamed responses
+// included.
+const RelayTimeout = 5 * time.Minute
+
+const (
+	// maxRequestBody caps the MCP request the relay forwards.
+	maxRequestBody = 1 << 20
+	// maxRecordedResponse caps how much of a response is kept for
+	// decoding; the client still receives all of it.
+	maxRecordedResponse = 1 << 20
+)
+
+// Deployments looks up the Deployment. *v1alpha1store.Store satisfies it.
+type Deployments interface {
+	GetLatest(ctx context.Context, namespace, name string) (*v1alpha1.RawObject, error)
+}
+
+var _ Deployments = (*v1alpha1store.Store)(nil)
+
+// EndpointResolver finds the MCP endpoint the Deployment's runtime serves
+// it on. *deployment.AdapterResolver satisfies it; errors wrapping
+// database.ErrInvalidInput mean the runtime has none.
+type EndpointResolver interface {
+	MCPEndpoint(ctx context.Context, deployment *v1alpha1.Deployment) (string, error)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix  string
+	Deployments Deployments
+	Endpoints   EndpointResolver
+	Recorder    *mcptraffic.Recorder
+	// Client forwards relayed requests. Build it with httpclient.New so it
+	// honors the outbound TLS and proxy settings. nil uses
+	// httpclient.New(RelayTimeout).
+	Client *http.Client
+	// Authorize gates reading traffic with verb "get" and relaying with
+	// verb "apply", as recorded frames may show what callers sent. nil
+	// means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type trafficInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Limit     int    `query:"limit" doc:"Return only the newest frames; 0 returns all that are kept."`
+}
+
+type trafficOutput struct {
+	Body arv0.DeploymentTraffic
+}
+
+type relayInput struct {
+	Namespace       string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name            string `path:"name"`
+	Accept          string `header:"Accept"`
+	SessionID       string `header:"Mcp-Session-Id"`
+	ProtocolVersion string `header:"Mcp-Protocol-Version"`
+	RawBody         []byte `contentType:"application/json" doc:"MCP JSON-RPC message or batch."`
+}
+
+// Register wires the relay and traffic routes under
+// {basePrefix}/deployments/{name}.
+func Register(api huma.API, cfg Config) {
+	base := cfg.BasePrefix + "/deployments/{name}"
+	client := cfg.Client
+	if client == nil {
+		client = httpclient.New(RelayTimeout)
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-deployment-traffic",
+		Method:      http.MethodGet,
+		Path:        base + "/traffic",
+		Summary:     "Get the MCP frames recorded for a deployment",
+		Description: "Frames are recorded while the deployment carries the `" + v1alpha1.DeploymentDebugTrafficAnnotation + ": \"true\"` annotation and its clients go through `POST /v0/deployments/{name}/mcp`. Secret values are redacted. Each registry replica keeps its own frames in memory, oldest first.",
+	}, func(ctx context.Context, in *trafficInput) (*trafficOutput, error) {
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "get", ns, name); err != nil {
+			return nil, err
+		}
+		if in.Limit < 0 {
+			return nil, huma.Error400BadRequest("limit must not be negative")
+		}
+		deployment, err := getDeployment(ctx, cfg, ns, name)
+		if err != nil {
+			return nil, err
+		}
+		return &trafficOutput{Body: arv0.DeploymentTraffic{
+			Namespace: ns,
+			Name:      name,
+			Recording: debugging(deployment),
+			Capacity:  cfg.Recorder.Capacity(),
+			Frames:    cfg.Recorder.Frames(ns, name, in.Limit),
+		}}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "relay-deployment-mcp",
+		Method:      http.MethodPost,
+		Path:        base + "/mcp",
+		Summary:     "Send MCP requests to a deployment, recording the traffic",
+		Description: "Forwards the MCP streamable-HTTP request to the gateway of the deployment's runtime and relays the answer, including event streams, recording both for `GET /v0/deployments/{name}/traffic`. Only deployments in traffic debug mode are relayed.",
+		// The request is forwarded as is; the server validates it.
+		SkipValidateBody: true,
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "The server's JSON-RPC response, or its server-sent event stream",
+				Content: map[string]*huma.MediaType{
+					"application/json":  {},
+					"text/event-stream": {},
+				},
+			},
+		},
+	}, func(ctx context.Context, in *relayInput) (*huma.StreamResponse, error) {
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "apply", ns, name); err != nil {
+			return nil, err
+		}
+		if len(in.RawBody) > maxRequestBody {
+			return nil, huma.NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("MCP requests are limited to %d bytes", maxRequestBody))
+		}
+		deployment, err := getDeployment(ctx, cfg, ns, name)
+		if err != nil {
+			return nil, err
+		}
+		if deployment.Spec.TargetRef.Kind != v1alpha1.KindMCPServer {
+			return nil, huma.Error409Conflict("only MCPServer deployments can be relayed")
+		}
+		if !debugging(deployment) {
+			return nil, huma.Error409Conflict(fmt.Sprintf("Deployment %q/%q is not in traffic debug mode; set the %s annotation to \"true\"", ns, name, v1alpha1.DeploymentDebugTrafficAnnotation))
+		}
+		endpoint, err := cfg.Endpoints.MCPEndpoint(ctx, deployment)
+		if err != nil {
+			if errors.Is(err, pkgdb.ErrInvalidInput) {
+				return nil, huma.Error409Conflict(err.Error())
+			}
+			return nil, huma.Error502BadGateway("resolve MCP endpoint: " + err.Error())
+		}
+
+		requests := mcptraffic.Decode(arv0.MCPFrameRequest, in.RawBody)
+		methods := make(map[string]string, len(requests))
+		for i := range requests {
+			requests[i].SessionID = in.SessionID
+			if requests[i].ID != "" {
+				methods[requests[i].ID] = requests[i].Method
+			}
+		}
+		cfg.Recorder.Record(ns, name, requests...)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(in.RawBody))
+		if err != nil {
+			return nil, huma.Error500InternalServerError("build MCP request", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if in.Accept != "" {
+			req.Header.Set("Accept", in.Accept)
+		}
+		if in.SessionID != "" {
+			req.Header.Set("Mcp-Session-Id", in.SessionID)
+		}
+		if in.ProtocolVersion != "" {
+			req.Header.Set("Mcp-Protocol-Version", in.ProtocolVersion)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			cfg.Recorder.Record(ns, name, arv0.MCPFrame{
+				Direction: arv0.MCPFrameResponse,
+				SessionID: in.SessionID,
+				Status:    http.StatusBadGateway,
+				Error:     "reach MCP server: " + err.Error(),
+			})
+			return nil, huma.Error502BadGateway("reach MCP server: " + err.Error())
+		}
+		return &huma.StreamResponse{Body: func(hctx huma.Context) {
+			defer resp.Body.Close()
+			for _, h := range []string{"Content-Type", "Mcp-Session-Id"} {
+				if v := resp.Header.Get(h); v != "" {
+					hctx.SetHeader(h, v)
+				}
+			}
+			hctx.SetStatus(resp.StatusCode)
+			captured := &capture{limit: maxRecordedResponse}
+			relay(hctx.BodyWriter(), io.TeeReader(resp.Body, captured))
+
+			sessionID := resp.Header.Get("Mcp-Session-Id")
+			if sessionID == "" {
+				sessionID = in.SessionID
+			}
+			var frames []arv0.MCPFrame
+			switch {
+			case captured.overflow:
+				frames = []arv0.MCPFrame{{Error: "response too large to record", Truncated: true}}
+			case strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"):
+				frames = mcptraffic.DecodeEventStream(arv0.MCPFrameResponse, captured.buf.Bytes())
+			default:
+				frames = mcptraffic.Decode(arv0.MCPFrameResponse, captured.buf.Bytes())
+			}
+			if len(frames) == 0 && resp.StatusCode >= http.StatusBadRequest {
+				frames = []arv0.MCPFrame{{Error: http.StatusText(resp.StatusCode)}}
+			}
+			for i := range frames {
+				frames[i].Direction = arv0.MCPFrameResponse
+				frames[i].SessionID = sessionID
+				frames[i].Status = resp.StatusCode
+				if frames[i].Method == "" {
+					frames[i].Method = methods[frames[i].ID]
+				}
+			}
+			cfg.Recorder.Record(ns, name, frames...)
+		}}, nil
+	})
+}
+
+// debugging reports whether the Deployment is in traffic debug mode.
+func debugging(deployment *v1alpha1.Deployment) bool {
+	return deployment.Metadata.Annotations[v1alpha1.DeploymentDebugTrafficAnnotation] == "true"
+}
+
+// capture keeps up to limit bytes written to it and notes whether more
+// arrived.
+type capture struct {
+	buf      bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (c *capture) Write(p []byte) (int, error) {
+	if c.overflow || c.buf.Len()+len(p) > c.limit {
+		c.overflow = true
+		c.buf.Reset()
+		return len(p), nil
+	}
+	return c.buf.Write(p)
+}
+
+// relay copies the server's response, flushing after every read so event
+// streams reach the client as they are produced.
+func relay(w io.Writer, r io.Reader) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if rw, ok := w.(http.ResponseWriter); ok {
+				_ = http.NewResponseController(rw).Flush()
+			} else if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func getDeployment(ctx context.Context, cfg Config, ns, name string) (*v1alpha1.Deployment, error) {
+	row, err := cfg.Deployments.GetLatest(ctx, ns, name)
+	if err != nil {
+		if errors.Is(err, pkgdb.ErrNotFound) {
+			return nil, huma.Error404NotFound(fmt.Sprintf("Deployment %q/%q not found", ns, name))
+		}
+		return nil, huma.Error500InternalServerError("fetch Deployment", err)
+	}
+	deployment, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} }, row, v1alpha1.KindDeployment)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("decode Deployment", err)
+	}
+	return deployment, nil
+}
+
+func parse(namespace, rawName string) (string, string, error) {
+	if namespace == "" {
+		namespace = v1alpha1.DefaultNamespace
+	}
+	name, err := resource.UnescapeName(rawName)
+	if err != nil {
+		return "", "", err
+	}
+	return namespace, name, nil
+}
+
+func authorize(ctx context.Context, cfg Config, verb, ns, name string) error {
+	if cfg.Authorize == nil {
+		return nil
+	}
+	return cfg.Authorize(ctx, resource.AuthorizeInput{
+		Verb: verb, Kind: v1alpha1.KindDeployment,
+		Namespace: ns, Name: name,
+	})
+}
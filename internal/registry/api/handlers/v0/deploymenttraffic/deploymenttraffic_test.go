@@ -0,0 +1,213 @@
+package deploymenttraffic_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymenttraffic"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/mcptraffic"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+)
+
+type fakeDeployments map[string]*v1alpha1.Deployment
+
+func (f fakeDeployments) GetLatest(_ context.Context, namespace, name string) (*v1alpha1.RawObject, error) {
+	d, ok := f[namespace+"/"+name]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	spec, err := json.Marshal(d.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.RawObject{Metadata: d.Metadata, Spec: spec}, nil
+}
+
+type fakeEndpoints struct {
+	url string
+	err error
+}
+
+func (f fakeEndpoints) MCPEndpoint(context.Context, *v1alpha1.Deployment) (string, error) {
+	return f.url, f.err
+}
+
+func mcpDeployment(debug bool) *v1alpha1.Deployment {
+	d := &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather"},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:    v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "1.0.0"},
+			DesiredState: v1alpha1.DesiredStateDeployed,
+		},
+	}
+	if debug {
+		d.Metadata.Annotations = map[string]string{v1alpha1.DeploymentDebugTrafficAnnotation: "true"}
+	}
+	return d
+}
+
+func newAPI(t *testing.T, deployments fakeDeployments, endpoints fakeEndpoints) (humatest.TestAPI, *mcptraffic.Recorder) {
+	t.Helper()
+	return newAPIWithClient(t, deployments, endpoints, nil)
+}
+
+func newAPIWithClient(t *testing.T, deployments fakeDeployments, endpoints fakeEndpoints, client *http.Client) (humatest.TestAPI, *mcptraffic.Recorder) {
+	t.Helper()
+	_, api := humatest.New(t)
+	recorder := mcptraffic.NewRecorder(10)
+	deploymenttraffic.Register(api, deploymenttraffic.Config{
+		BasePrefix:  "/v0",
+		Deployments: deployments,
+		Endpoints:   endpoints,
+		Recorder:    recorder,
+		Client:      client,
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			if in.Namespace == "team-a" {
+				return huma.Error403Forbidden("denied")
+			}
+			return nil
+		},
+	})
+	return api, recorder
+}
+
+func traffic(t *testing.T, api humatest.TestAPI, path string) arv0.DeploymentTraffic {
+	t.Helper()
+	resp := api.Get(path)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var out arv0.DeploymentTraffic
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &out))
+	return out
+}
+
+func TestRelay_RecordsJSONExchange(t *testing.T) {
+	var gotSession string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSession = r.Header.Get("Mcp-Session-Id")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Mcp-Session-Id", "s-1")
+		_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":3,"error":{"code":-32602,"message":"missing city"}}`)
+	}))
+	defer server.Close()
+	api, _ := newAPI(t, fakeDeployments{"default/weather": mcpDeployment(true)}, fakeEndpoints{url: server.URL})
+
+	req := `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"forecast","arguments":{"token":"abc"}}}`
+	resp := api.Post("/v0/deployments/weather/mcp", "Content-Type: application/json", "Mcp-Session-Id: s-1", strings.NewReader(req))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, "s-1", gotSession)
+	require.Equal(t, "s-1", resp.Header().Get("Mcp-Session-Id"))
+	require.Contains(t, resp.Body.String(), "missing city")
+
+	out := traffic(t, api, "/v0/deployments/weather/traffic")
+	require.True(t, out.Recording)
+	require.Equal(t, 10, out.Capacity)
+	require.Len(t, out.Frames, 2)
+	request, response := out.Frames[0], out.Frames[1]
+	require.Equal(t, arv0.MCPFrameRequest, request.Direction)
+	require.Equal(t, "tools/call", request.Method)
+	require.Contains(t, string(request.Body), `"token":"REDACTED"`)
+	require.Equal(t, arv0.MCPFrameResponse, response.Direction)
+	require.Equal(t, "tools/call", response.Method, "responses carry the method of their request")
+	require.Equal(t, "3", response.ID)
+	require.Equal(t, "missing city", response.Error)
+	require.Equal(t, http.StatusOK, response.Status)
+	require.Equal(t, "s-1", response.SessionID)
+
+	require.Len(t, traffic(t, api, "/v0/deployments/weather/traffic?limit=1").Frames, 1)
+	require.Len(t, traffic(t, api, "/v0/deployments/Weather/traffic").Frames, 2, "names are looked up in canonical form")
+}
+
+func TestRelay_RecordsEventStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+		_, _ = io.WriteString(w, "data: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{}}\n\n")
+	}))
+	defer server.Close()
+	api, recorder := newAPI(t, fakeDeployments{"default/weather": mcpDeployment(true)}, fakeEndpoints{url: server.URL})
+
+	resp := api.Post("/v0/deployments/weather/mcp", "Content-Type: application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "notifications/progress")
+
+	frames := recorder.Frames("default", "weather", 0)
+	require.Len(t, frames, 3)
+	require.Equal(t, "notifications/progress", frames[1].Method)
+	require.Equal(t, "tools/list", frames[2].Method)
+}
+
+func TestRelay_UnreachableServerIsRecorded(t *testing.T) {
+	api, recorder := newAPI(t, fakeDeployments{"default/weather": mcpDeployment(true)}, fakeEndpoints{url: "http://127.0.0.1:1/mcp"})
+
+	resp := api.Post("/v0/deployments/weather/mcp", "Content-Type: application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	require.Equal(t, http.StatusBadGateway, resp.Code, resp.Body.String())
+	frames := recorder.Frames("default", "weather", 0)
+	require.Len(t, frames, 2)
+	require.Contains(t, frames[1].Error, "reach MCP server")
+}
+
+func TestRelay_TimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	api, recorder := newAPIWithClient(t, fakeDeployments{"default/weather": mcpDeployment(true)}, fakeEndpoints{url: server.URL}, httpclient.New(50*time.Millisecond))
+
+	resp := api.Post("/v0/deployments/weather/mcp", "Content-Type: application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	require.Equal(t, http.StatusBadGateway, resp.Code, resp.Body.String())
+	require.Len(t, recorder.Frames("default", "weather", 0), 2)
+}
+
+func TestRelay_Errors(t *testing.T) {
+	agent := mcpDeployment(true)
+	agent.Metadata.Name = "agent"
+	agent.Spec.TargetRef.Kind = v1alpha1.KindAgent
+	quiet := mcpDeployment(false)
+	quiet.Metadata.Name = "quiet"
+	deployments := fakeDeployments{"default/weather": mcpDeployment(true), "default/agent": agent, "default/quiet": quiet}
+
+	unsupported := fmt.Errorf("%w: no MCP endpoint", pkgdb.ErrInvalidInput)
+	cases := []struct {
+		name      string
+		path      string
+		endpoints fakeEndpoints
+		want      int
+	}{
+		{"missing deployment", "/v0/deployments/missing/mcp", fakeEndpoints{}, http.StatusNotFound},
+		{"forbidden", "/v0/deployments/weather/mcp?namespace=team-a", fakeEndpoints{}, http.StatusForbidden},
+		{"not in debug mode", "/v0/deployments/quiet/mcp", fakeEndpoints{}, http.StatusConflict},
+		{"not an MCP server", "/v0/deployments/agent/mcp", fakeEndpoints{}, http.StatusConflict},
+		{"unsupported runtime", "/v0/deployments/weather/mcp", fakeEndpoints{err: unsupported}, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			api, recorder := newAPI(t, deployments, tc.endpoints)
+			resp := api.Post(tc.path, "Content-Type: application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+			require.Equal(t, tc.want, resp.Code, resp.Body.String())
+			require.Empty(t, recorder.Frames("default", "weather", 0))
+		})
+	}
+
+	api, _ := newAPI(t, deployments, fakeEndpoints{})
+	require.False(t, traffic(t, api, "/v0/deployments/quiet/traffic").Recording)
+	require.Equal(t, http.StatusNotFound, api.Get("/v0/deployments/missing/traffic").Code)
+	require.Equal(t, http.StatusForbidden, api.Get("/v0/deployments/weather/traffic?namespace=team-a").Code)
+	require.Equal(t, http.StatusBadRequest, api.Get("/v0/deployments/weather/traffic?limit=-1").Code)
+}
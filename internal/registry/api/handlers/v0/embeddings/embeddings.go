@@ -0,0 +1,155 @@
+// Package embeddings owns the admin embeddings backfill endpoint:
+// `POST /v0/admin/embeddings:reindex`. It runs embeddings.Reindex over the
+// searchable kinds and streams its progress as server-sent events, so
+// artifacts published before the semantic index existed, or changed since
+// they were embedded, become findable by meaning.
+//
+// The run lasts as long as the request: a client that disconnects stops
+// it, and running it again picks up where it stopped.
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/search"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/embeddings"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+// typeOrder is the default `types` value: every searchable type.
+var typeOrder = []string{"server", "agent", "skill", "prompt"}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	// Stores are the stores of the searchable kinds, keyed by kind.
+	Stores map[string]embeddings.Lister
+	Index  embeddings.Index
+	// IsAdmin gates the endpoint. A reindex reads every artifact in every
+	// namespace and spends embedding quota, so there is no per-resource
+	// authz to fall back to. nil denies.
+	IsAdmin func(ctx context.Context) bool
+}
+
+type reindexInput struct {
+	Types     []string `query:"types" doc:"Comma-separated artifact types to reindex: server, agent, skill, prompt. Defaults to all."`
+	Namespace string   `query:"namespace" doc:"Reindex only this namespace. Empty reindexes every namespace."`
+	DryRun    bool     `query:"dryRun" doc:"Report what would be embedded without embedding it."`
+	Force     bool     `query:"force" doc:"Re-embed artifacts whose embedding is up to date."`
+	Rate      float64  `query:"rate" minimum:"0" maximum:"100" doc:"Embeddings generated per second (default 5)."`
+}
+
+// Register wires POST {basePrefix}/admin/embeddings:reindex. Validation and
+// the admin gate run before the stream opens, so they answer 400 or 403.
+func Register(api huma.API, cfg Config) {
+	eventSchema := api.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(arv0.EmbeddingsReindexEvent{}), true, "EmbeddingsReindexEvent")
+
+	huma.Register(api, huma.Operation{
+		OperationID: "reindex-embeddings",
+		Method:      http.MethodPost,
+		Path:        cfg.BasePrefix + "/admin/embeddings:reindex",
+		Summary:     "Embed artifacts whose semantic search embedding is missing or stale",
+		Description: "Walks the latest tag of every artifact of the given types and embeds those without an embedding or whose embedding was generated from other text, at a bounded rate. Each `data:` line is a JSON EmbeddingsReindexEvent: one `planned`, one `item` per artifact and a final `done`.",
+		Tags:        []string{"admin"},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Server-sent event stream",
+				Content: map[string]*huma.MediaType{
+					"text/event-stream": {Schema: eventSchema},
+				},
+			},
+		},
+	}, func(ctx context.Context, in *reindexInput) (*huma.StreamResponse, error) {
+		if cfg.IsAdmin == nil || !cfg.IsAdmin(ctx) {
+			return nil, huma.Error403Forbidden("registry admin permission required")
+		}
+		kinds, err := parseTypes(in.Types)
+		if err != nil {
+			return nil, err
+		}
+		opts := embeddings.Options{
+			Kinds:     kinds,
+			Namespace: in.Namespace,
+			DryRun:    in.DryRun,
+			Force:     in.Force,
+			Rate:      in.Rate,
+		}
+
+		return &huma.StreamResponse{Body: func(hctx huma.Context) {
+			hctx.SetHeader("Content-Type", "text/event-stream")
+			hctx.SetHeader("Cache-Control", "no-cache")
+			w := &eventWriter{w: hctx.BodyWriter()}
+			err := embeddings.Reindex(hctx.Context(), cfg.Stores, cfg.Index, opts, w.event)
+			if err != nil && hctx.Context().Err() == nil {
+				_ = w.comment("reindex failed: " + err.Error())
+			}
+		}}, nil
+	})
+}
+
+// parseTypes maps `types` values to kinds, in the order given.
+func parseTypes(values []string) ([]string, error) {
+	var types []string
+	for _, v := range values {
+		for _, typ := range strings.Split(v, ",") {
+			typ = strings.ToLower(strings.TrimSpace(typ))
+			if typ == "" {
+				continue
+			}
+			if _, ok := search.Types[typ]; !ok {
+				return nil, huma.Error400BadRequest(fmt.Sprintf("unknown type %q (want server, agent, skill or prompt)", typ))
+			}
+			if !slices.Contains(types, typ) {
+				types = append(types, typ)
+			}
+		}
+	}
+	if len(types) == 0 {
+		types = typeOrder
+	}
+	kinds := make([]string, 0, len(types))
+	for _, typ := range types {
+		kinds = append(kinds, search.Types[typ])
+	}
+	return kinds, nil
+}
+
+type eventWriter struct {
+	w  io.Writer
+	id int
+}
+
+func (e *eventWriter) event(event arv0.EmbeddingsReindexEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	e.id++
+	return e.write(fmt.Sprintf("id: %d\ndata: %s\n\n", e.id, data))
+}
+
+func (e *eventWriter) comment(text string) error {
+	return e.write(": " + text + "\n\n")
+}
+
+func (e *eventWriter) write(frame string) error {
+	if _, err := io.WriteString(e.w, frame); err != nil {
+		return err
+	}
+	if rw, ok := e.w.(http.ResponseWriter); ok {
+		return http.NewResponseController(rw).Flush()
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
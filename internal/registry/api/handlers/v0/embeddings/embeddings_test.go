@@ -0,0 +1,89 @@
+package embeddings_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	v0embeddings "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/embeddings"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/embeddings"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeLister struct {
+	rows []*v1alpha1.RawObject
+}
+
+func (f fakeLister) List(context.Context, v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	return f.rows, "", nil
+}
+
+type fakeIndex struct {
+	embedded []string
+}
+
+func (f *fakeIndex) Checksum(context.Context, embeddings.Ref) (string, error) { return "", nil }
+
+func (f *fakeIndex) Embed(_ context.Context, ref embeddings.Ref, _, _ string) error {
+	f.embedded = append(f.embedded, ref.Kind+"/"+ref.Name)
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	stores := map[string]embeddings.Lister{
+		v1alpha1.KindMCPServer: fakeLister{rows: []*v1alpha1.RawObject{
+			{Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather", Tag: "latest"}},
+		}},
+		v1alpha1.KindAgent: fakeLister{rows: []*v1alpha1.RawObject{
+			{Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "reporter", Tag: "latest"}},
+		}},
+	}
+	tests := []struct {
+		name     string
+		query    string
+		isAdmin  func(context.Context) bool
+		wantCode int
+		want     []string
+	}{
+		{"admin reindexes", "?rate=100", func(context.Context) bool { return true }, http.StatusOK, []string{"MCPServer/weather", "Agent/reporter"}},
+		{"types narrow", "?types=agent&rate=100", func(context.Context) bool { return true }, http.StatusOK, []string{"Agent/reporter"}},
+		{"dry run", "?dryRun=true", func(context.Context) bool { return true }, http.StatusOK, nil},
+		{"unknown type", "?types=chart", func(context.Context) bool { return true }, http.StatusBadRequest, nil},
+		{"non-admin forbidden", "", func(context.Context) bool { return false }, http.StatusForbidden, nil},
+		{"nil gate denies", "", nil, http.StatusForbidden, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := &fakeIndex{}
+			_, api := humatest.New(t)
+			v0embeddings.Register(api, v0embeddings.Config{
+				BasePrefix: "/v0",
+				Stores:     stores,
+				Index:      index,
+				IsAdmin:    tt.isAdmin,
+			})
+			resp := api.Post("/v0/admin/embeddings:reindex" + tt.query)
+			require.Equal(t, tt.wantCode, resp.Code, resp.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			require.Equal(t, "text/event-stream", resp.Header().Get("Content-Type"))
+			require.Equal(t, tt.want, index.embedded)
+
+			var last arv0.EmbeddingsReindexEvent
+			for _, line := range strings.Split(resp.Body.String(), "\n") {
+				if data, ok := strings.CutPrefix(line, "data: "); ok {
+					require.NoError(t, json.Unmarshal([]byte(data), &last))
+				}
+			}
+			require.Equal(t, arv0.EmbeddingsReindexDone, last.Type)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Package export owns the registry export endpoint: `GET /v0/export`. It
+// renders every live tag of every tagged artifact kind (MCP servers,
+// agents, skills, prompts, plugins, charts) as one multi-document YAML
+// stream that POST /v0/apply — and so `arctl registry import` — accepts
+// unchanged, for moving a catalog between registry instances.
+//
+// Mutable objects (Runtimes, Deployments, Webhooks) are left out: they
+// describe the environment a registry runs in, and replaying a Deployment
+// into another registry would deploy it there.
+package export
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/danielgtaylor/huma/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// FileName is the Content-Disposition file name of an export.
+const FileName = "agentregistry-export.yaml"
+
+// listPageSize is the store page size used while walking each kind.
+const listPageSize = 200
+
+// Store is the narrow surface this handler needs from each kind's store.
+// *v1alpha1store.Store satisfies it; tests supply a fake.
+type Store interface {
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	// Stores maps kind to store. Only tagged artifact kinds are exported;
+	// other entries are ignored.
+	Stores map[string]Store
+	// IsAdmin gates the endpoint. An export spans every namespace and
+	// bypasses the per-kind list filters, so there is no per-resource
+	// authz to fall back to. nil denies.
+	IsAdmin func(ctx context.Context) bool
+}
+
+type exportInput struct {
+	Namespace string `query:"namespace" doc:"Export only this namespace. Empty exports every namespace."`
+}
+
+type exportOutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	Body               []byte
+}
+
+// document is one exported object: identity, labels, annotations and spec.
+// Server-managed fields (uid, timestamps, status) are dropped; the
+// importing registry assigns its own.
+type document struct {
+	v1alpha1.TypeMeta
+	Metadata v1alpha1.ObjectMeta `json:"metadata"`
+	Spec     json.RawMessage     `json:"spec"`
+}
+
+// Register wires GET {basePrefix}/export.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "export-registry",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/export",
+		Summary:     "Export every tagged artifact as a multi-document YAML stream for POST /v0/apply",
+		Tags:        []string{"admin"},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Multi-document YAML stream",
+				Content: map[string]*huma.MediaType{
+					"application/yaml": {Schema: &huma.Schema{Type: "string"}},
+				},
+			},
+		},
+	}, func(ctx context.Context, in *exportInput) (*exportOutput, error) {
+		if cfg.IsAdmin == nil || !cfg.IsAdmin(ctx) {
+			return nil, huma.Error403Forbidden("registry admin permission required")
+		}
+		body, err := Export(ctx, cfg.Stores, in.Namespace)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("export registry", err)
+		}
+		return &exportOutput{
+			ContentType:        "application/yaml",
+			ContentDisposition: fmt.Sprintf(`attachment; filename=%q`, FileName),
+			Body:               body,
+		}, nil
+	})
+}
+
+// Export renders the tagged artifacts in stores as a multi-document YAML
+// stream. Agents come last so the references they carry resolve when the
+// stream is applied in order. An empty namespace exports every namespace.
+func Export(ctx context.Context, stores map[string]Store, namespace string) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, kind := range exportKinds(stores) {
+		cursor := ""
+		for {
+			rows, next, err := stores[kind].List(ctx, v1alpha1store.ListOpts{
+				Namespace: namespace,
+				Limit:     listPageSize,
+				Cursor:    cursor,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("list %s: %w", kind, err)
+			}
+			for _, row := range rows {
+				if err := writeDocument(&buf, kind, row); err != nil {
+					return nil, err
+				}
+			}
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// exportKinds returns the tagged artifact kinds present in stores, sorted,
+// with Agent moved to the end.
+func exportKinds(stores map[string]Store) []string {
+	var kinds []string
+	for kind := range stores {
+		if v1alpha1.IsTaggedArtifactKind(kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	slices.SortFunc(kinds, func(a, b string) int {
+		switch {
+		case a == v1alpha1.KindAgent:
+			return 1
+		case b == v1alpha1.KindAgent:
+			return -1
+		}
+		return cmp.Compare(a, b)
+	})
+	return kinds
+}
+
+func writeDocument(buf *bytes.Buffer, kind string, row *v1alpha1.RawObject) error {
+	meta := row.Metadata
+	data, err := yaml.Marshal(document{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: kind},
+		Metadata: v1alpha1.ObjectMeta{
+			Namespace:   meta.Namespace,
+			Name:        meta.Name,
+			Tag:         meta.Tag,
+			Labels:      meta.Labels,
+			Annotations: meta.Annotations,
+		},
+		Spec: row.Spec,
+	})
+	if err != nil {
+		return fmt.Errorf("encode %s %s/%s@%s: %w", kind, meta.Namespace, meta.Name, meta.Tag, err)
+	}
+	if buf.Len() > 0 {
+		buf.WriteString("---\n")
+	}
+	buf.Write(data)
+	return nil
+}
@@ -0,0 +1,161 @@
+package export_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/export"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// fakeStore pages its rows one at a time so the export has to follow
+// cursors.
+type fakeStore struct {
+	rows []*v1alpha1.RawObject
+	err  error
+}
+
+func (f fakeStore) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	if f.err != nil {
+		return nil, "", f.err
+	}
+	var matched []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		if opts.Namespace == "" || row.Metadata.Namespace == opts.Namespace {
+			matched = append(matched, row)
+		}
+	}
+	start := 0
+	if opts.Cursor != "" {
+		start, _ = strconv.Atoi(opts.Cursor)
+	}
+	if start >= len(matched) {
+		return nil, "", nil
+	}
+	next := ""
+	if start+1 < len(matched) {
+		next = strconv.Itoa(start + 1)
+	}
+	return matched[start : start+1], next, nil
+}
+
+func raw(t *testing.T, namespace, name, tag string, spec any) *v1alpha1.RawObject {
+	t.Helper()
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{
+		Metadata: v1alpha1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Tag:       tag,
+			UID:       "uid-" + name,
+			Labels:    map[string]string{"team": "search"},
+			CreatedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Spec: data,
+	}
+}
+
+func testStores(t *testing.T) map[string]export.Store {
+	return map[string]export.Store{
+		v1alpha1.KindAgent: fakeStore{rows: []*v1alpha1.RawObject{
+			raw(t, "default", "reporter", "1.0.0", v1alpha1.AgentSpec{
+				Title:      "Reporter",
+				MCPServers: []v1alpha1.ResourceRef{{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "1.0.0"}},
+			}),
+		}},
+		v1alpha1.KindMCPServer: fakeStore{rows: []*v1alpha1.RawObject{
+			raw(t, "default", "weather", "1.0.0", v1alpha1.MCPServerSpec{Title: "Weather"}),
+			raw(t, "team-a", "weather", "2.0.0", v1alpha1.MCPServerSpec{Title: "Weather"}),
+		}},
+		v1alpha1.KindSkill: fakeStore{rows: []*v1alpha1.RawObject{
+			raw(t, "default", "summarize", "latest", v1alpha1.SkillSpec{Title: "Summarize"}),
+		}},
+		// Mutable kinds are never exported.
+		v1alpha1.KindRuntime: fakeStore{err: errors.New("runtime store must not be listed")},
+	}
+}
+
+func TestExport(t *testing.T) {
+	data, err := export.Export(context.Background(), testStores(t), "")
+	require.NoError(t, err)
+
+	docs, err := v1alpha1.Default.DecodeMulti(data)
+	require.NoError(t, err)
+	var got []string
+	for _, doc := range docs {
+		obj := doc.(v1alpha1.Object)
+		meta := obj.GetMetadata()
+		got = append(got, obj.GetKind()+"/"+meta.NamespaceOrDefault()+"/"+meta.Name+"@"+meta.Tag)
+		require.Empty(t, meta.UID)
+		require.True(t, meta.CreatedAt.IsZero())
+		require.Equal(t, "search", meta.Labels["team"])
+	}
+	require.Equal(t, []string{
+		"MCPServer/default/weather@1.0.0",
+		"MCPServer/team-a/weather@2.0.0",
+		"Skill/default/summarize@latest",
+		"Agent/default/reporter@1.0.0",
+	}, got)
+
+	agent := docs[3].(*v1alpha1.Agent)
+	require.Equal(t, "Reporter", agent.Spec.Title)
+	require.Equal(t, "weather", agent.Spec.MCPServers[0].Name)
+}
+
+func TestExportNamespace(t *testing.T) {
+	data, err := export.Export(context.Background(), testStores(t), "team-a")
+	require.NoError(t, err)
+	docs, err := v1alpha1.Default.DecodeMulti(data)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "team-a", docs[0].(v1alpha1.Object).GetMetadata().Namespace)
+}
+
+func TestRegisterExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		stores   map[string]export.Store
+		isAdmin  func(context.Context) bool
+		wantCode int
+	}{
+		{"admin exports", testStores(t), func(context.Context) bool { return true }, http.StatusOK},
+		{"non-admin forbidden", testStores(t), func(context.Context) bool { return false }, http.StatusForbidden},
+		{"nil gate denies", testStores(t), nil, http.StatusForbidden},
+		{
+			"store failure",
+			map[string]export.Store{v1alpha1.KindSkill: fakeStore{err: errors.New("boom")}},
+			func(context.Context) bool { return true },
+			http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, api := humatest.New(t)
+			export.Register(api, export.Config{
+				BasePrefix: "/v0",
+				Stores:     tt.stores,
+				IsAdmin:    tt.isAdmin,
+			})
+			resp := api.Get("/v0/export")
+			require.Equal(t, tt.wantCode, resp.Code, resp.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			require.Equal(t, "application/yaml", resp.Header().Get("Content-Type"))
+			require.Contains(t, resp.Header().Get("Content-Disposition"), export.FileName)
+			docs, err := v1alpha1.Default.DecodeMulti(resp.Body.Bytes())
+			require.NoError(t, err)
+			require.Len(t, docs, 4)
+		})
+	}
+}
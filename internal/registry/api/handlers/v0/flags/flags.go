@@ -0,0 +1,98 @@
+// Package flags owns FeatureFlag evaluation: `GET /v0/flags:evaluate`.
+// FeatureFlag CRUD rides the generic resource handler at /v0/flags; this
+// endpoint resolves every flag in a namespace for one agent so a deployed
+// agent can poll a single URL (handed to it as AGENT_REGISTRY_FLAGS_URL)
+// and pick up toggles without a redeploy.
+package flags
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// pageSize is the FeatureFlag list page size used while walking the
+// namespace.
+const pageSize = 200
+
+// Store is the narrow read surface this handler needs from the FeatureFlag
+// store. *v1alpha1store.Store satisfies it; tests supply a fake.
+type Store interface {
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// Authorize and ListFilter gate the evaluation exactly like
+	// GET /v0/flags (verb "list"). nil means no gate.
+	Authorize  func(ctx context.Context, in resource.AuthorizeInput) error
+	ListFilter func(ctx context.Context, in resource.AuthorizeInput) (string, []any, error)
+}
+
+type evaluateInput struct {
+	Namespace string `query:"namespace" doc:"Namespace of the flags and the agent (defaults to 'default')."`
+	Agent     string `query:"agent" required:"true" minLength:"1" doc:"Name of the agent to evaluate the flags for."`
+}
+
+type evaluateOutput struct {
+	Body arv0.FlagEvaluation
+}
+
+// Register wires GET {basePrefix}/flags:evaluate.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "evaluate-feature-flags",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/flags:evaluate",
+		Summary:     "Evaluate every feature flag in a namespace for one agent",
+	}, func(ctx context.Context, in *evaluateInput) (*evaluateOutput, error) {
+		ns := in.Namespace
+		if ns == "" {
+			ns = v1alpha1.DefaultNamespace
+		}
+		authz := resource.AuthorizeInput{Verb: "list", Kind: v1alpha1.KindFeatureFlag, Namespace: ns}
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, authz); err != nil {
+				return nil, err
+			}
+		}
+		opts := v1alpha1store.ListOpts{Namespace: ns, Limit: pageSize}
+		if cfg.ListFilter != nil {
+			extra, args, err := cfg.ListFilter(ctx, authz)
+			if err != nil {
+				return nil, err
+			}
+			opts.ExtraWhere, opts.ExtraArgs = extra, args
+		}
+
+		out := arv0.FlagEvaluation{Namespace: ns, Agent: in.Agent, Flags: map[string]bool{}}
+		for {
+			rows, next, err := cfg.Store.List(ctx, opts)
+			if err != nil {
+				return nil, huma.Error500InternalServerError("list feature flags", err)
+			}
+			for _, row := range rows {
+				flag, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.FeatureFlag { return &v1alpha1.FeatureFlag{} }, row, v1alpha1.KindFeatureFlag)
+				if err != nil {
+					return nil, huma.Error500InternalServerError("decode feature flag", err)
+				}
+				out.Flags[flag.Metadata.Name] = flag.Spec.EvaluateFor(in.Agent)
+			}
+			if next == "" {
+				break
+			}
+			opts.Cursor = next
+		}
+		return &evaluateOutput{Body: out}, nil
+	})
+}
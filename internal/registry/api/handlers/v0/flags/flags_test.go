@@ -0,0 +1,129 @@
+package flags_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/flags"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// fakeStore pages one row at a time so the handler's cursor walk is
+// exercised.
+type fakeStore struct {
+	rows []*v1alpha1.RawObject
+}
+
+func (f *fakeStore) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	var out []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		if row.Metadata.Namespace == opts.Namespace {
+			out = append(out, row)
+		}
+	}
+	start := 0
+	if opts.Cursor != "" {
+		start = int(opts.Cursor[0] - '0')
+	}
+	if start >= len(out) {
+		return nil, "", nil
+	}
+	next := ""
+	if start+1 < len(out) {
+		next = string(rune('0' + start + 1))
+	}
+	return out[start : start+1], next, nil
+}
+
+func flag(t *testing.T, ns, name string, spec v1alpha1.FeatureFlagSpec) *v1alpha1.RawObject {
+	t.Helper()
+	specJSON, err := json.Marshal(spec)
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindFeatureFlag},
+		Metadata: v1alpha1.ObjectMeta{Namespace: ns, Name: name},
+		Spec:     specJSON,
+	}
+}
+
+func newAPI(t *testing.T, cfg flags.Config) humatest.TestAPI {
+	t.Helper()
+	cfg.BasePrefix = "/v0"
+	cfg.Store = &fakeStore{rows: []*v1alpha1.RawObject{
+		flag(t, "default", "new-search-tool", v1alpha1.FeatureFlagSpec{Agents: map[string]bool{"summarizer": true}}),
+		flag(t, "default", "streaming", v1alpha1.FeatureFlagSpec{Enabled: true, Agents: map[string]bool{"summarizer": false}}),
+		flag(t, "default", "verbose", v1alpha1.FeatureFlagSpec{Enabled: true}),
+		flag(t, "team-a", "verbose", v1alpha1.FeatureFlagSpec{}),
+	}}
+	_, api := humatest.New(t)
+	flags.Register(api, cfg)
+	return api
+}
+
+func TestEvaluate(t *testing.T) {
+	api := newAPI(t, flags.Config{})
+
+	cases := []struct {
+		target string
+		want   arv0.FlagEvaluation
+	}{
+		{
+			target: "/v0/flags:evaluate?agent=summarizer",
+			want: arv0.FlagEvaluation{Namespace: "default", Agent: "summarizer", Flags: map[string]bool{
+				"new-search-tool": true, "streaming": false, "verbose": true,
+			}},
+		},
+		{
+			target: "/v0/flags:evaluate?agent=translator",
+			want: arv0.FlagEvaluation{Namespace: "default", Agent: "translator", Flags: map[string]bool{
+				"new-search-tool": false, "streaming": true, "verbose": true,
+			}},
+		},
+		{
+			target: "/v0/flags:evaluate?agent=summarizer&namespace=team-a",
+			want:   arv0.FlagEvaluation{Namespace: "team-a", Agent: "summarizer", Flags: map[string]bool{"verbose": false}},
+		},
+		{
+			target: "/v0/flags:evaluate?agent=summarizer&namespace=empty",
+			want:   arv0.FlagEvaluation{Namespace: "empty", Agent: "summarizer", Flags: map[string]bool{}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.target, func(t *testing.T) {
+			resp := api.Get(tc.target)
+			require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+			var got arv0.FlagEvaluation
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestEvaluate_RequiresAgent(t *testing.T) {
+	api := newAPI(t, flags.Config{})
+	resp := api.Get("/v0/flags:evaluate")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+}
+
+func TestEvaluate_Authorize(t *testing.T) {
+	var got resource.AuthorizeInput
+	api := newAPI(t, flags.Config{
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			got = in
+			return huma.Error403Forbidden("denied", errors.New("no"))
+		},
+	})
+	resp := api.Get("/v0/flags:evaluate?agent=summarizer&namespace=team-a")
+	require.Equal(t, http.StatusForbidden, resp.Code)
+	require.Equal(t, resource.AuthorizeInput{Verb: "list", Kind: v1alpha1.KindFeatureFlag, Namespace: "team-a"}, got)
+}
@@ -0,0 +1,61 @@
+// Package gc owns the admin version garbage collection endpoint:
+// `POST /v0/admin/gc`. It runs the registry's version retention policy
+// (internal/registry/gc) on demand, or with `?dryRun=true` previews what
+// the policy would delete.
+package gc
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/gc"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+// Collector is the only GC capability needed by this handler.
+// *gc.Collector satisfies it.
+type Collector interface {
+	Collect(ctx context.Context, opts gc.Options) (arv0.GCReport, error)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Collector  Collector
+	// IsAdmin gates the endpoint. GC reads and deletes versions in every
+	// namespace, so there is no per-resource authz to fall back to. nil
+	// denies.
+	IsAdmin func(ctx context.Context) bool
+}
+
+type gcInput struct {
+	DryRun    bool   `query:"dryRun" doc:"Report what would be deleted without deleting it."`
+	Namespace string `query:"namespace" doc:"Collect only this namespace. Empty collects every namespace."`
+}
+
+type gcOutput struct {
+	Body arv0.GCReport
+}
+
+// Register wires POST {basePrefix}/admin/gc.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "collect-versions",
+		Method:      http.MethodPost,
+		Path:        cfg.BasePrefix + "/admin/gc",
+		Summary:     "Delete artifact versions the retention policy does not keep",
+		Description: "Deletes the versions of every tagged artifact that are not among its newest `keepVersions`, not younger than `keepWithin`, not its `latest` tag or newest version, and not pinned by a Deployment, Agent or Skill. Deleted versions stay restorable for the deleted-artifact retention period.",
+		Tags:        []string{"admin"},
+	}, func(ctx context.Context, in *gcInput) (*gcOutput, error) {
+		if cfg.IsAdmin == nil || !cfg.IsAdmin(ctx) {
+			return nil, huma.Error403Forbidden("registry admin permission required")
+		}
+		report, err := cfg.Collector.Collect(ctx, gc.Options{Namespace: in.Namespace, DryRun: in.DryRun})
+		if err != nil {
+			return nil, huma.Error500InternalServerError("collect versions", err)
+		}
+		return &gcOutput{Body: report}, nil
+	})
+}
@@ -0,0 +1,59 @@
+package gc_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	v0gc "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/gc"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/gc"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+type fakeCollector struct {
+	opts *gc.Options
+}
+
+func (f *fakeCollector) Collect(_ context.Context, opts gc.Options) (arv0.GCReport, error) {
+	f.opts = &opts
+	return arv0.GCReport{DryRun: opts.DryRun, Scanned: 3, Kept: 2, Deleted: []arv0.GCVersion{
+		{Kind: "MCPServer", Namespace: "default", Name: "weather", Tag: "1.0.0"},
+	}}, nil
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		isAdmin  func(context.Context) bool
+		wantCode int
+		want     *gc.Options
+	}{
+		{"admin collects", "", func(context.Context) bool { return true }, http.StatusOK, &gc.Options{}},
+		{"dry run in namespace", "?dryRun=true&namespace=team-a", func(context.Context) bool { return true }, http.StatusOK, &gc.Options{Namespace: "team-a", DryRun: true}},
+		{"non-admin forbidden", "", func(context.Context) bool { return false }, http.StatusForbidden, nil},
+		{"nil gate denies", "", nil, http.StatusForbidden, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := &fakeCollector{}
+			_, api := humatest.New(t)
+			v0gc.Register(api, v0gc.Config{BasePrefix: "/v0", Collector: collector, IsAdmin: tt.isAdmin})
+
+			resp := api.Post("/v0/admin/gc" + tt.query)
+			require.Equal(t, tt.wantCode, resp.Code, resp.Body.String())
+			require.Equal(t, tt.want, collector.opts)
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var report arv0.GCReport
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+			require.Equal(t, tt.want.DryRun, report.DryRun)
+			require.Len(t, report.Deleted, 1)
+		})
+	}
+}
@@ -0,0 +1,306 @@
+// Package icons owns the artifact icon subresource:
+// `PUT/GET/DELETE /v0/{plural}/{name}/icon` for MCPServers, Agents and
+// Skills. An icon is a small image uploaded for the artifact name, so it
+// outlives the tag it was uploaded with. GET serves it with an ETag for
+// revalidation, and falls back to redirecting to spec.icon of the latest
+// tag when nothing was uploaded.
+package icons
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// ContentTypes are the image types icons are accepted as.
+var ContentTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp", "image/svg+xml"}
+
+// Kinds are the artifact kinds that carry icons.
+var Kinds = []string{v1alpha1.KindMCPServer, v1alpha1.KindAgent, v1alpha1.KindSkill}
+
+// cacheControl lets browsers and dashboards reuse an icon for a few
+// minutes before revalidating it against its ETag.
+const cacheControl = "max-age=300"
+
+// svgPolicy keeps scripts in an uploaded SVG from running when the icon
+// URL is opened directly.
+const svgPolicy = "default-src 'none'; style-src 'unsafe-inline'; sandbox"
+
+// Store reads and writes icons. *v1alpha1store.ArtifactIconStore
+// satisfies it; tests supply a fake.
+type Store interface {
+	Put(ctx context.Context, icon v1alpha1store.ArtifactIcon) error
+	Get(ctx context.Context, kind, namespace, name string) (*v1alpha1store.ArtifactIcon, error)
+	Delete(ctx context.Context, kind, namespace, name string) error
+}
+
+var _ Store = (*v1alpha1store.ArtifactIconStore)(nil)
+
+// Artifacts lists the tags of the artifact an icon belongs to, newest
+// first. *v1alpha1store.Store satisfies it.
+type Artifacts interface {
+	ListTags(ctx context.Context, namespace, name string) ([]*v1alpha1.RawObject, error)
+}
+
+var _ Artifacts = (*v1alpha1store.Store)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	// Kind is the artifact kind served; Artifacts is its store.
+	Kind      string
+	Artifacts Artifacts
+	Store     Store
+	// Authorize gates reads with verb "get" and uploads and deletes with
+	// verb "apply", the same as the artifact itself. nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type iconInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+}
+
+type getIconInput struct {
+	Namespace   string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name        string `path:"name"`
+	IfNoneMatch string `header:"If-None-Match"`
+}
+
+type putIconInput struct {
+	Namespace   string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name        string `path:"name"`
+	ContentType string `header:"Content-Type"`
+	RawBody     []byte `contentType:"image/*" doc:"The icon image: PNG, JPEG, GIF, WebP or SVG."`
+}
+
+type iconOutput struct {
+	Status                int
+	ContentType           string `header:"Content-Type"`
+	ETag                  string `header:"ETag"`
+	CacheControl          string `header:"Cache-Control"`
+	LastModified          string `header:"Last-Modified"`
+	Location              string `header:"Location"`
+	ContentSecurityPolicy string `header:"Content-Security-Policy"`
+	ContentTypeOptions    string `header:"X-Content-Type-Options"`
+	Body                  []byte
+}
+
+// Register wires PUT, GET and DELETE {basePrefix}/{plural}/{name}/icon
+// ?namespace=default. All three answer 404 when the artifact has no live
+// tag.
+func Register(api huma.API, cfg Config) {
+	plural := v1alpha1.PluralFor(cfg.Kind)
+	path := cfg.BasePrefix + "/" + plural + "/{name}/icon"
+	kind := strings.ToLower(cfg.Kind)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "put-" + kind + "-icon",
+		Method:        http.MethodPut,
+		Path:          path,
+		Summary:       fmt.Sprintf("Upload the icon of a %s", cfg.Kind),
+		Description:   fmt.Sprintf("Replaces the icon. At most %d bytes of %s.", v1alpha1.MaxIconBytes, strings.Join(ContentTypes, ", ")),
+		DefaultStatus: http.StatusNoContent,
+		MaxBodyBytes:  v1alpha1.MaxIconBytes,
+	}, func(ctx context.Context, in *putIconInput) (*struct{}, error) {
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "apply", ns, name); err != nil {
+			return nil, err
+		}
+		contentType, err := validate(in.ContentType, in.RawBody)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := latest(ctx, cfg, ns, name); err != nil {
+			return nil, err
+		}
+		if err := cfg.Store.Put(ctx, v1alpha1store.ArtifactIcon{
+			Kind:        cfg.Kind,
+			Namespace:   ns,
+			Name:        name,
+			ContentType: contentType,
+			Content:     in.RawBody,
+		}); err != nil {
+			return nil, huma.Error500InternalServerError("store icon", err)
+		}
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-" + kind + "-icon",
+		Method:      http.MethodGet,
+		Path:        path,
+		Summary:     fmt.Sprintf("Get the icon of a %s", cfg.Kind),
+		Description: "Serves the uploaded icon, or redirects to spec.icon of the latest tag when none was uploaded.",
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "The icon image",
+				Content: map[string]*huma.MediaType{
+					"image/*": {Schema: &huma.Schema{Type: "string", Format: "binary"}},
+				},
+			},
+			"302": {Description: "Redirect to the URL in spec.icon"},
+			"304": {Description: "The icon matches If-None-Match"},
+		},
+	}, func(ctx context.Context, in *getIconInput) (*iconOutput, error) {
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "get", ns, name); err != nil {
+			return nil, err
+		}
+		row, err := latest(ctx, cfg, ns, name)
+		if err != nil {
+			return nil, err
+		}
+		icon, err := cfg.Store.Get(ctx, cfg.Kind, ns, name)
+		switch {
+		case err == nil:
+			out := &iconOutput{
+				Status:       http.StatusOK,
+				ETag:         `"` + icon.Digest + `"`,
+				CacheControl: cacheControl,
+				LastModified: icon.UpdatedAt.UTC().Format(http.TimeFormat),
+			}
+			if matches(in.IfNoneMatch, out.ETag) {
+				out.Status = http.StatusNotModified
+				return out, nil
+			}
+			out.ContentType, out.Body = icon.ContentType, icon.Content
+			out.ContentTypeOptions = "nosniff"
+			if icon.ContentType == "image/svg+xml" {
+				out.ContentSecurityPolicy = svgPolicy
+			}
+			return out, nil
+		case !errors.Is(err, pkgdb.ErrNotFound):
+			return nil, huma.Error500InternalServerError("fetch icon", err)
+		}
+		if u := specIcon(row); u != "" {
+			return &iconOutput{Status: http.StatusFound, Location: u, CacheControl: cacheControl}, nil
+		}
+		return nil, huma.Error404NotFound(fmt.Sprintf("%s %q/%q has no icon", cfg.Kind, ns, name))
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-" + kind + "-icon",
+		Method:        http.MethodDelete,
+		Path:          path,
+		Summary:       fmt.Sprintf("Delete the uploaded icon of a %s", cfg.Kind),
+		Description:   "GET falls back to spec.icon afterwards.",
+		DefaultStatus: http.StatusNoContent,
+	}, func(ctx context.Context, in *iconInput) (*struct{}, error) {
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "apply", ns, name); err != nil {
+			return nil, err
+		}
+		if err := cfg.Store.Delete(ctx, cfg.Kind, ns, name); err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("%s %q/%q has no uploaded icon", cfg.Kind, ns, name))
+			}
+			return nil, huma.Error500InternalServerError("delete icon", err)
+		}
+		return nil, nil
+	})
+}
+
+// validate checks an upload is a non-empty image of one of ContentTypes
+// whose bytes match the declared type, and returns the bare media type.
+func validate(declared string, body []byte) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(declared)
+	if err != nil || !slices.Contains(ContentTypes, mediaType) {
+		return "", huma.Error415UnsupportedMediaType(fmt.Sprintf("icon Content-Type %q is not one of %s", declared, strings.Join(ContentTypes, ", ")))
+	}
+	if len(body) == 0 {
+		return "", huma.Error400BadRequest("icon is empty")
+	}
+	if mediaType == "image/svg+xml" {
+		// SVG is text; sniffing reports it as XML or plain text.
+		if !utf8.Valid(body) || !bytes.Contains(bytes.ToLower(body), []byte("<svg")) {
+			return "", huma.Error400BadRequest("icon is not an SVG image")
+		}
+		return mediaType, nil
+	}
+	if sniffed := http.DetectContentType(body); sniffed != mediaType {
+		return "", huma.Error400BadRequest(fmt.Sprintf("icon content is %s, not %s", sniffed, mediaType))
+	}
+	return mediaType, nil
+}
+
+// matches reports whether an If-None-Match header lists etag.
+func matches(ifNoneMatch, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func parse(namespace, rawName string) (ns, name string, err error) {
+	ns = namespace
+	if ns == "" {
+		ns = v1alpha1.DefaultNamespace
+	}
+	if name, err = resource.UnescapeName(rawName); err != nil {
+		return "", "", err
+	}
+	return ns, name, nil
+}
+
+func authorize(ctx context.Context, cfg Config, verb, ns, name string) error {
+	if cfg.Authorize == nil {
+		return nil
+	}
+	return cfg.Authorize(ctx, resource.AuthorizeInput{
+		Verb: verb, Kind: cfg.Kind,
+		Namespace: ns, Name: name,
+	})
+}
+
+// latest returns the artifact's newest live tag.
+func latest(ctx context.Context, cfg Config, ns, name string) (*v1alpha1.RawObject, error) {
+	rows, err := cfg.Artifacts.ListTags(ctx, ns, name)
+	if err != nil && !errors.Is(err, pkgdb.ErrNotFound) {
+		return nil, huma.Error500InternalServerError("fetch "+cfg.Kind, err)
+	}
+	if len(rows) == 0 {
+		return nil, huma.Error404NotFound(fmt.Sprintf("%s %q/%q not found", cfg.Kind, ns, name))
+	}
+	return rows[0], nil
+}
+
+// specIcon returns the icon URL the artifact's spec declares.
+func specIcon(row *v1alpha1.RawObject) string {
+	if len(row.Spec) == 0 {
+		return ""
+	}
+	var spec struct {
+		Icon string `json:"icon"`
+	}
+	if err := json.Unmarshal(row.Spec, &spec); err != nil {
+		return ""
+	}
+	return spec.Icon
+}
@@ -0,0 +1,168 @@
+package icons_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/icons"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// pngHeader is enough of a PNG for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type fakeArtifacts map[string][]*v1alpha1.RawObject
+
+func (f fakeArtifacts) ListTags(_ context.Context, namespace, name string) ([]*v1alpha1.RawObject, error) {
+	return f[namespace+"/"+name], nil
+}
+
+type fakeStore map[string]v1alpha1store.ArtifactIcon
+
+func (f fakeStore) Put(_ context.Context, icon v1alpha1store.ArtifactIcon) error {
+	icon.Digest = "d1"
+	icon.UpdatedAt = time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	f[icon.Kind+"/"+icon.Namespace+"/"+icon.Name] = icon
+	return nil
+}
+
+func (f fakeStore) Get(_ context.Context, kind, namespace, name string) (*v1alpha1store.ArtifactIcon, error) {
+	if icon, ok := f[kind+"/"+namespace+"/"+name]; ok {
+		return &icon, nil
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+func (f fakeStore) Delete(_ context.Context, kind, namespace, name string) error {
+	key := kind + "/" + namespace + "/" + name
+	if _, ok := f[key]; !ok {
+		return pkgdb.ErrNotFound
+	}
+	delete(f, key)
+	return nil
+}
+
+func row(t *testing.T, namespace, name, tag, icon string) *v1alpha1.RawObject {
+	t.Helper()
+	spec, err := json.Marshal(v1alpha1.SkillSpec{Icon: icon})
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{Metadata: v1alpha1.ObjectMeta{Namespace: namespace, Name: name, Tag: tag}, Spec: spec}
+}
+
+func register(t *testing.T, artifacts fakeArtifacts, store fakeStore) humatest.TestAPI {
+	t.Helper()
+	_, api := humatest.New(t)
+	icons.Register(api, icons.Config{
+		BasePrefix: "/v0",
+		Kind:       v1alpha1.KindSkill,
+		Artifacts:  artifacts,
+		Store:      store,
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			if in.Namespace == "team-a" && in.Verb == "apply" {
+				return huma.Error403Forbidden("denied")
+			}
+			return nil
+		},
+	})
+	return api
+}
+
+func TestPutGetDeleteIcon(t *testing.T) {
+	artifacts := fakeArtifacts{
+		"default/summarize": {
+			row(t, "default", "summarize", "2.0.0", "https://cdn.example.com/summarize.png"),
+			row(t, "default", "summarize", "1.0.0", "https://cdn.example.com/old.png"),
+		},
+	}
+	store := fakeStore{}
+	api := register(t, artifacts, store)
+
+	// Nothing uploaded: redirect to the latest tag's spec.icon.
+	resp := api.Get("/v0/skills/summarize/icon")
+	require.Equal(t, http.StatusFound, resp.Code, resp.Body.String())
+	require.Equal(t, "https://cdn.example.com/summarize.png", resp.Header().Get("Location"))
+
+	resp = api.Put("/v0/skills/summarize/icon", "Content-Type: image/png", bytes.NewReader(pngHeader))
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	require.Equal(t, "image/png", store["Skill/default/summarize"].ContentType)
+
+	resp = api.Get("/v0/skills/summarize/icon")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, pngHeader, resp.Body.Bytes())
+	require.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+	require.Equal(t, `"d1"`, resp.Header().Get("ETag"))
+	require.Equal(t, "max-age=300", resp.Header().Get("Cache-Control"))
+	require.Equal(t, "Sun, 18 Oct 2026 09:30:00 GMT", resp.Header().Get("Last-Modified"))
+	require.Equal(t, "nosniff", resp.Header().Get("X-Content-Type-Options"))
+	require.Empty(t, resp.Header().Get("Location"))
+
+	resp = api.Get("/v0/skills/summarize/icon", `If-None-Match: "d0", "d1"`)
+	require.Equal(t, http.StatusNotModified, resp.Code, resp.Body.String())
+	require.Empty(t, resp.Body.Bytes())
+
+	resp = api.Delete("/v0/skills/summarize/icon")
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	resp = api.Delete("/v0/skills/summarize/icon")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	resp = api.Get("/v0/skills/summarize/icon")
+	require.Equal(t, http.StatusFound, resp.Code, resp.Body.String())
+}
+
+func TestGetIcon_NotFound(t *testing.T) {
+	api := register(t, fakeArtifacts{"default/plain": {row(t, "default", "plain", "1.0.0", "")}}, fakeStore{})
+
+	resp := api.Get("/v0/skills/plain/icon")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	resp = api.Get("/v0/skills/missing/icon")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	resp = api.Put("/v0/skills/missing/icon", "Content-Type: image/png", bytes.NewReader(pngHeader))
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
+
+func TestPutIcon_Validation(t *testing.T) {
+	artifacts := fakeArtifacts{
+		"default/summarize": {row(t, "default", "summarize", "1.0.0", "")},
+		"team-a/summarize":  {row(t, "team-a", "summarize", "1.0.0", "")},
+	}
+	store := fakeStore{}
+	api := register(t, artifacts, store)
+
+	for _, tc := range []struct {
+		contentType string
+		body        []byte
+		want        int
+	}{
+		{"image/bmp", []byte("BM"), http.StatusUnsupportedMediaType},
+		{"text/plain", []byte("hello"), http.StatusUnsupportedMediaType},
+		{"image/png", []byte("GIF89a"), http.StatusBadRequest},
+		{"image/svg+xml", []byte("<html></html>"), http.StatusBadRequest},
+		{"image/png", bytes.Repeat([]byte("x"), v1alpha1.MaxIconBytes+1), http.StatusRequestEntityTooLarge},
+	} {
+		resp := api.Put("/v0/skills/summarize/icon", "Content-Type: "+tc.contentType, bytes.NewReader(tc.body))
+		require.Equal(t, tc.want, resp.Code, "%s: %s", tc.contentType, resp.Body.String())
+	}
+	require.Empty(t, store)
+
+	svg := `<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"/>`
+	resp := api.Put("/v0/skills/summarize/icon", "Content-Type: image/svg+xml; charset=utf-8", strings.NewReader(svg))
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	require.Equal(t, "image/svg+xml", store["Skill/default/summarize"].ContentType)
+	resp = api.Get("/v0/skills/summarize/icon")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Header().Get("Content-Security-Policy"), "default-src 'none'")
+
+	resp = api.Put("/v0/skills/summarize/icon?namespace=team-a", "Content-Type: image/png", bytes.NewReader(pngHeader))
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+}
@@ -0,0 +1,41 @@
+// Package testapi builds the humatest APIs the v0 handler tests register
+// their endpoints on.
+package testapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+)
+
+// Admin is the subject IsAdmin treats as a registry admin.
+const Admin = "admin"
+
+type session string
+
+func (s session) Principal() auth.Principal { return auth.Principal{Subject: string(s)} }
+
+// New returns a test API behind a middleware that authenticates the
+// X-Subject header: a request with "X-Subject: alice" runs as alice, and a
+// request without the header is anonymous.
+func New(t *testing.T) humatest.TestAPI {
+	t.Helper()
+	_, api := humatest.New(t)
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		if subject := ctx.Header("X-Subject"); subject != "" {
+			ctx = huma.WithContext(ctx, auth.AuthSessionTo(ctx.Context(), session(subject)))
+		}
+		next(ctx)
+	})
+	return api
+}
+
+// IsAdmin reports whether the request runs as Admin. Handlers take it as
+// their Config.IsAdmin.
+func IsAdmin(ctx context.Context) bool {
+	return auth.SubjectFrom(ctx) == Admin
+}
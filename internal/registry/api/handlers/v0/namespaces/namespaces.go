@@ -0,0 +1,292 @@
+// Package namespaces owns the namespace ownership endpoints under
+// `/v0/namespaces`: claiming a namespace, verifying the claim, and managing
+// its members. Enforcement of a verified claim on publishes lives in
+// internal/registry/ownership; this package only records claims.
+//
+// A caller claims a namespace and becomes its owner. The claim restricts
+// nothing until it is verified, either by the owner publishing a DNS TXT
+// record on the namespace's reversed domain (io.github.acme is verified on
+// _agentregistry.acme.github.io) or by a registry admin. Pending claims
+// left unverified for a week may be taken over by another caller, so an
+// abandoned claim can't squat a namespace.
+package namespaces
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/ownership"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// pendingClaimTTL is how long an unverified claim holds a namespace
+// against other claimants.
+const pendingClaimTTL = 7 * 24 * time.Hour
+
+// Store is the namespace claim capability the endpoints need.
+// *v1alpha1store.NamespaceStore satisfies it.
+type Store interface {
+	Get(ctx context.Context, name string) (*v1alpha1store.Namespace, error)
+	List(ctx context.Context) ([]*v1alpha1store.Namespace, error)
+	Claim(ctx context.Context, name, owner, token string, staleBefore time.Time) (*v1alpha1store.Namespace, error)
+	Verify(ctx context.Context, name string) (*v1alpha1store.Namespace, error)
+	SetMembers(ctx context.Context, name string, members []string) (*v1alpha1store.Namespace, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// IsAdmin lets registry admins verify claims without DNS and manage
+	// any claim. nil grants nobody admin.
+	IsAdmin func(ctx context.Context) bool
+	// LookupTXT resolves verification records. nil uses
+	// net.DefaultResolver.
+	LookupTXT func(ctx context.Context, name string) ([]string, error)
+}
+
+type nameInput struct {
+	Name string `path:"name" doc:"Namespace name, e.g. io.github.acme"`
+}
+
+type membersInput struct {
+	Name string `path:"name" doc:"Namespace name, e.g. io.github.acme"`
+	Body arv0.NamespaceMembers
+}
+
+type namespaceOutput struct {
+	Body arv0.Namespace
+}
+
+type listOutput struct {
+	Body arv0.NamespaceList
+}
+
+// Register wires the /v0/namespaces endpoints.
+func Register(api huma.API, cfg Config) {
+	isAdmin := func(ctx context.Context) bool { return cfg.IsAdmin != nil && cfg.IsAdmin(ctx) }
+	lookupTXT := cfg.LookupTXT
+	if lookupTXT == nil {
+		lookupTXT = net.DefaultResolver.LookupTXT
+	}
+	base := cfg.BasePrefix + "/namespaces"
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-namespaces",
+		Method:      http.MethodGet,
+		Path:        base,
+		Summary:     "List namespace claims",
+		Tags:        []string{"namespaces"},
+	}, func(ctx context.Context, _ *struct{}) (*listOutput, error) {
+		rows, err := cfg.Store.List(ctx)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list namespaces", err)
+		}
+		out := &listOutput{Body: arv0.NamespaceList{Namespaces: make([]arv0.Namespace, 0, len(rows))}}
+		for _, ns := range rows {
+			out.Body.Namespaces = append(out.Body.Namespaces, toWire(ctx, ns, isAdmin))
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-namespace",
+		Method:      http.MethodGet,
+		Path:        base + "/{name}",
+		Summary:     "Get a namespace claim",
+		Tags:        []string{"namespaces"},
+	}, func(ctx context.Context, in *nameInput) (*namespaceOutput, error) {
+		ns, err := getClaim(ctx, cfg.Store, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		return &namespaceOutput{Body: toWire(ctx, ns, isAdmin)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "claim-namespace",
+		Method:      http.MethodPost,
+		Path:        base + "/{name}/claim",
+		Summary:     "Claim a namespace for the caller, pending verification",
+		Tags:        []string{"namespaces"},
+	}, func(ctx context.Context, in *nameInput) (*namespaceOutput, error) {
+		if err := v1alpha1.ValidateNamespace(in.Name); err != nil {
+			return nil, huma.Error400BadRequest("invalid namespace: " + err.Error())
+		}
+		subject := auth.SubjectFrom(ctx)
+		if subject == "" {
+			return nil, huma.Error401Unauthorized("claiming a namespace requires an authenticated caller")
+		}
+		token, err := newVerificationToken()
+		if err != nil {
+			return nil, huma.Error500InternalServerError("generate verification token", err)
+		}
+		ns, err := cfg.Store.Claim(ctx, in.Name, subject, token, time.Now().Add(-pendingClaimTTL))
+		switch {
+		case errors.Is(err, pkgdb.ErrAlreadyExists):
+			return nil, huma.Error409Conflict(err.Error())
+		case err != nil:
+			return nil, huma.Error500InternalServerError("claim namespace", err)
+		}
+		return &namespaceOutput{Body: toWire(ctx, ns, isAdmin)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "verify-namespace",
+		Method:      http.MethodPost,
+		Path:        base + "/{name}/verify",
+		Summary:     "Verify a namespace claim by its DNS TXT record, or directly as a registry admin",
+		Tags:        []string{"namespaces"},
+	}, func(ctx context.Context, in *nameInput) (*namespaceOutput, error) {
+		ns, err := getManagedClaim(ctx, cfg.Store, in.Name, isAdmin)
+		if err != nil {
+			return nil, err
+		}
+		if !isAdmin(ctx) && !ns.Verified() {
+			if err := checkTXTRecord(ctx, lookupTXT, ns); err != nil {
+				return nil, err
+			}
+		}
+		ns, err = cfg.Store.Verify(ctx, in.Name)
+		if err != nil {
+			return nil, mapStoreError(err, in.Name, "verify namespace")
+		}
+		return &namespaceOutput{Body: toWire(ctx, ns, isAdmin)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "set-namespace-members",
+		Method:      http.MethodPut,
+		Path:        base + "/{name}/members",
+		Summary:     "Replace the members of a namespace",
+		Tags:        []string{"namespaces"},
+	}, func(ctx context.Context, in *membersInput) (*namespaceOutput, error) {
+		if _, err := getManagedClaim(ctx, cfg.Store, in.Name, isAdmin); err != nil {
+			return nil, err
+		}
+		members := make([]string, 0, len(in.Body.Members))
+		for _, m := range in.Body.Members {
+			m = strings.TrimSpace(m)
+			if m == "" {
+				return nil, huma.Error400BadRequest("members must not be empty")
+			}
+			if !slices.Contains(members, m) {
+				members = append(members, m)
+			}
+		}
+		ns, err := cfg.Store.SetMembers(ctx, in.Name, members)
+		if err != nil {
+			return nil, mapStoreError(err, in.Name, "set namespace members")
+		}
+		return &namespaceOutput{Body: toWire(ctx, ns, isAdmin)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-namespace",
+		Method:        http.MethodDelete,
+		Path:          base + "/{name}",
+		Summary:       "Release a namespace claim",
+		Tags:          []string{"namespaces"},
+		DefaultStatus: http.StatusNoContent,
+	}, func(ctx context.Context, in *nameInput) (*struct{}, error) {
+		if _, err := getManagedClaim(ctx, cfg.Store, in.Name, isAdmin); err != nil {
+			return nil, err
+		}
+		if err := cfg.Store.Delete(ctx, in.Name); err != nil {
+			return nil, mapStoreError(err, in.Name, "delete namespace")
+		}
+		return nil, nil
+	})
+}
+
+func getClaim(ctx context.Context, store Store, name string) (*v1alpha1store.Namespace, error) {
+	ns, err := store.Get(ctx, name)
+	if err != nil {
+		return nil, mapStoreError(err, name, "get namespace")
+	}
+	return ns, nil
+}
+
+// getManagedClaim returns the claim on name when the caller owns it or is
+// a registry admin.
+func getManagedClaim(ctx context.Context, store Store, name string, isAdmin func(context.Context) bool) (*v1alpha1store.Namespace, error) {
+	ns, err := getClaim(ctx, store, name)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin(ctx) && (ns.Owner == "" || ns.Owner != auth.SubjectFrom(ctx)) {
+		return nil, huma.Error403Forbidden("only the owner of namespace " + name + " or a registry admin may manage it")
+	}
+	return ns, nil
+}
+
+// checkTXTRecord confirms the claim's verification record is published.
+func checkTXTRecord(ctx context.Context, lookupTXT func(context.Context, string) ([]string, error), ns *v1alpha1store.Namespace) error {
+	record, value, ok := ownership.VerificationRecord(ns.Name, ns.VerificationToken)
+	if !ok {
+		return huma.Error422UnprocessableEntity(fmt.Sprintf(
+			"namespace %s has no DNS domain to verify against; ask a registry admin to verify it", ns.Name))
+	}
+	values, err := lookupTXT(ctx, record)
+	if err != nil {
+		return huma.Error422UnprocessableEntity(fmt.Sprintf("look up TXT record %s: %v", record, err))
+	}
+	if !slices.Contains(values, value) {
+		return huma.Error422UnprocessableEntity(fmt.Sprintf("TXT record %s does not contain %q", record, value))
+	}
+	return nil
+}
+
+func mapStoreError(err error, name, op string) error {
+	if errors.Is(err, pkgdb.ErrNotFound) {
+		return huma.Error404NotFound("namespace " + name + " is not claimed")
+	}
+	return huma.Error500InternalServerError(op, err)
+}
+
+// toWire converts a claim to its API shape, including the verification
+// record only for its owner and registry admins.
+func toWire(ctx context.Context, ns *v1alpha1store.Namespace, isAdmin func(context.Context) bool) arv0.Namespace {
+	members := ns.Members
+	if members == nil {
+		members = []string{}
+	}
+	out := arv0.Namespace{
+		Name:       ns.Name,
+		Owner:      ns.Owner,
+		Members:    members,
+		Verified:   ns.Verified(),
+		VerifiedAt: ns.VerifiedAt,
+		CreatedAt:  ns.CreatedAt,
+	}
+	subject := auth.SubjectFrom(ctx)
+	if (subject != "" && subject == ns.Owner) || isAdmin(ctx) {
+		if record, value, ok := ownership.VerificationRecord(ns.Name, ns.VerificationToken); ok {
+			out.Verification = &arv0.NamespaceVerification{Record: record, Value: value}
+		}
+	}
+	return out
+}
+
+func newVerificationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,187 @@
+package namespaces_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/internal/testapi"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/namespaces"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore map[string]*v1alpha1store.Namespace
+
+func (f fakeStore) Get(_ context.Context, name string) (*v1alpha1store.Namespace, error) {
+	if ns, ok := f[name]; ok {
+		return ns, nil
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+func (f fakeStore) List(context.Context) ([]*v1alpha1store.Namespace, error) {
+	var out []*v1alpha1store.Namespace
+	for _, ns := range f {
+		out = append(out, ns)
+	}
+	slices.SortFunc(out, func(a, b *v1alpha1store.Namespace) int { return len(a.Name) - len(b.Name) })
+	return out, nil
+}
+
+func (f fakeStore) Claim(_ context.Context, name, owner, token string, _ time.Time) (*v1alpha1store.Namespace, error) {
+	if ns, ok := f[name]; ok {
+		if ns.Owner != owner {
+			return nil, pkgdb.ErrAlreadyExists
+		}
+		return ns, nil
+	}
+	f[name] = &v1alpha1store.Namespace{Name: name, Owner: owner, VerificationToken: token, CreatedAt: time.Now()}
+	return f[name], nil
+}
+
+func (f fakeStore) Verify(_ context.Context, name string) (*v1alpha1store.Namespace, error) {
+	ns, ok := f[name]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	now := time.Now()
+	ns.VerifiedAt = &now
+	return ns, nil
+}
+
+func (f fakeStore) SetMembers(_ context.Context, name string, members []string) (*v1alpha1store.Namespace, error) {
+	ns, ok := f[name]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	ns.Members = members
+	return ns, nil
+}
+
+func (f fakeStore) Delete(_ context.Context, name string) error {
+	if _, ok := f[name]; !ok {
+		return pkgdb.ErrNotFound
+	}
+	delete(f, name)
+	return nil
+}
+
+// newAPI registers the endpoints on a testapi.New API. The subject "admin"
+// is a registry admin.
+func newAPI(t *testing.T, store fakeStore, txt map[string][]string) humatest.TestAPI {
+	api := testapi.New(t)
+	namespaces.Register(api, namespaces.Config{
+		BasePrefix: "/v0",
+		Store:      store,
+		IsAdmin:    testapi.IsAdmin,
+		LookupTXT: func(_ context.Context, name string) ([]string, error) {
+			if values, ok := txt[name]; ok {
+				return values, nil
+			}
+			return nil, errors.New("no such host")
+		},
+	})
+	return api
+}
+
+func decode(t *testing.T, body []byte) arv0.Namespace {
+	t.Helper()
+	var ns arv0.Namespace
+	require.NoError(t, json.Unmarshal(body, &ns))
+	return ns
+}
+
+func TestClaimAndVerifyByDNS(t *testing.T) {
+	store := fakeStore{}
+	txt := map[string][]string{}
+	api := newAPI(t, store, txt)
+
+	resp := api.Post("/v0/namespaces/io.github.acme/claim")
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/namespaces/io.github.acme/claim", "X-Subject: alice")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	claimed := decode(t, resp.Body.Bytes())
+	require.Equal(t, "alice", claimed.Owner)
+	require.False(t, claimed.Verified)
+	require.NotNil(t, claimed.Verification)
+	require.Equal(t, "_agentregistry.acme.github.io", claimed.Verification.Record)
+
+	resp = api.Post("/v0/namespaces/io.github.acme/claim", "X-Subject: mallory")
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body.String())
+
+	// Others see the claim but not its verification record.
+	resp = api.Get("/v0/namespaces/io.github.acme", "X-Subject: mallory")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Nil(t, decode(t, resp.Body.Bytes()).Verification)
+
+	resp = api.Post("/v0/namespaces/io.github.acme/verify", "X-Subject: mallory")
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/namespaces/io.github.acme/verify", "X-Subject: alice")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+
+	txt[claimed.Verification.Record] = []string{"v=spf1 -all", claimed.Verification.Value}
+	resp = api.Post("/v0/namespaces/io.github.acme/verify", "X-Subject: alice")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.True(t, decode(t, resp.Body.Bytes()).Verified)
+}
+
+func TestAdminVerifiesWithoutDNS(t *testing.T) {
+	store := fakeStore{"team-a": {Name: "team-a", Owner: "alice", VerificationToken: "tok"}}
+	api := newAPI(t, store, nil)
+
+	// Single-label namespaces have no domain, so only an admin can verify.
+	resp := api.Post("/v0/namespaces/team-a/verify", "X-Subject: alice")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/namespaces/team-a/verify", "X-Subject: admin")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.True(t, decode(t, resp.Body.Bytes()).Verified)
+
+	resp = api.Post("/v0/namespaces/missing/verify", "X-Subject: admin")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
+
+func TestMembersAndRelease(t *testing.T) {
+	store := fakeStore{"io.github.acme": {Name: "io.github.acme", Owner: "alice", VerificationToken: "tok"}}
+	api := newAPI(t, store, nil)
+
+	body := map[string]any{"members": []string{"bob", " bob ", "carol"}}
+	resp := api.Put("/v0/namespaces/io.github.acme/members", "X-Subject: bob", body)
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = api.Put("/v0/namespaces/io.github.acme/members", "X-Subject: alice", body)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, []string{"bob", "carol"}, decode(t, resp.Body.Bytes()).Members)
+
+	resp = api.Put("/v0/namespaces/io.github.acme/members", "X-Subject: alice", map[string]any{"members": []string{""}})
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	resp = api.Get("/v0/namespaces")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var list arv0.NamespaceList
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Len(t, list.Namespaces, 1)
+
+	resp = api.Delete("/v0/namespaces/io.github.acme", "X-Subject: bob")
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+	resp = api.Delete("/v0/namespaces/io.github.acme", "X-Subject: alice")
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	require.Empty(t, store)
+}
+
+func TestClaimRejectsInvalidName(t *testing.T) {
+	api := newAPI(t, fakeStore{}, nil)
+	resp := api.Post("/v0/namespaces/Not_Valid/claim", "X-Subject: alice")
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+}
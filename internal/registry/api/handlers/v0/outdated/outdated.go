@@ -0,0 +1,406 @@
+// Package outdated owns the upgrade-planning report:
+// `GET /v0/deployments/outdated`. It walks every Deployment in scope, follows
+// its pinned target (and, for Agents, the pinned MCP servers, skills,
+// plugins, charts and instructions the agent references), and reports
+// references whose artifact has newer versions or whose pinned version is
+// deprecated, with a severity derived from semver distance and the
+// changelog annotations of the newer versions.
+//
+// References without a tag (or tagged "latest") follow the moving "latest"
+// row and are never outdated; they are still reported when that row is
+// deprecated.
+package outdated
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"golang.org/x/mod/semver"
+
+	"github.com/agentregistry-dev/agentregistry/internal/version"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// pageSize is the Deployment list page size used while walking the
+// namespace.
+const pageSize = 200
+
+// Store is the narrow read surface this handler needs from each kind's
+// store. *v1alpha1store.Store satisfies it; tests supply a fake.
+type Store interface {
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+	Get(ctx context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error)
+	ListTags(ctx context.Context, namespace, name string) ([]*v1alpha1.RawObject, error)
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	// Stores maps kind to store. The Deployment store is required; artifact
+	// kinds missing from the map are skipped.
+	Stores map[string]Store
+	// Authorize and ListFilter gate the Deployment walk exactly like
+	// GET /v0/deployments (verb "list"). Artifact version metadata is read
+	// without per-artifact checks: the report only names versions of
+	// artifacts the caller's Deployments already reference. nil means no
+	// gate.
+	Authorize  func(ctx context.Context, in resource.AuthorizeInput) error
+	ListFilter func(ctx context.Context, in resource.AuthorizeInput) (string, []any, error)
+}
+
+type outdatedInput struct {
+	Namespace   string `query:"namespace" doc:"Namespace (defaults to 'default'); 'all' walks every namespace."`
+	MinSeverity string `query:"minSeverity" enum:"low,medium,high" default:"low" doc:"Only report deployments at or above this severity."`
+}
+
+type outdatedOutput struct {
+	Body arv0.OutdatedReport
+}
+
+// Register wires GET {basePrefix}/deployments/outdated. The literal segment
+// wins over GET {basePrefix}/deployments/{name}, so a Deployment named
+// "outdated" cannot be fetched by name (list and apply still reach it).
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-outdated-deployments",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/deployments/outdated",
+		Summary:     "List deployments whose pinned artifacts have newer or deprecated versions",
+	}, func(ctx context.Context, in *outdatedInput) (*outdatedOutput, error) {
+		ns := in.Namespace
+		switch ns {
+		case "":
+			ns = v1alpha1.DefaultNamespace
+		case "all":
+			ns = ""
+		}
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, resource.AuthorizeInput{Verb: "list", Kind: v1alpha1.KindDeployment, Namespace: ns}); err != nil {
+				return nil, err
+			}
+		}
+		report, err := newScanner(cfg).scan(ctx, ns, severityRank(in.MinSeverity))
+		if err != nil {
+			return nil, err
+		}
+		return &outdatedOutput{Body: report}, nil
+	})
+}
+
+type artifactKey struct {
+	kind, namespace, name string
+}
+
+// scanner holds the per-request tag cache: deployments commonly share
+// artifacts, so each (kind, namespace, name) is listed once.
+type scanner struct {
+	cfg  Config
+	tags map[artifactKey][]*v1alpha1.RawObject
+}
+
+func newScanner(cfg Config) *scanner {
+	return &scanner{cfg: cfg, tags: map[artifactKey][]*v1alpha1.RawObject{}}
+}
+
+func (s *scanner) scan(ctx context.Context, namespace string, minRank int) (arv0.OutdatedReport, error) {
+	report := arv0.OutdatedReport{Deployments: []arv0.OutdatedDeployment{}}
+	store := s.cfg.Stores[v1alpha1.KindDeployment]
+	if store == nil {
+		return report, huma.Error503ServiceUnavailable("deployment store is not configured")
+	}
+	opts := v1alpha1store.ListOpts{Namespace: namespace, Limit: pageSize}
+	if s.cfg.ListFilter != nil {
+		extra, args, err := s.cfg.ListFilter(ctx, resource.AuthorizeInput{Verb: "list", Kind: v1alpha1.KindDeployment, Namespace: namespace})
+		if err != nil {
+			return report, err
+		}
+		opts.ExtraWhere, opts.ExtraArgs = extra, args
+	}
+	for {
+		rows, next, err := store.List(ctx, opts)
+		if err != nil {
+			return report, huma.Error500InternalServerError("list deployments", err)
+		}
+		for _, row := range rows {
+			dep, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} }, row, v1alpha1.KindDeployment)
+			if err != nil {
+				return report, huma.Error500InternalServerError("decode deployment", err)
+			}
+			entry, err := s.deployment(ctx, dep)
+			if err != nil {
+				return report, huma.Error500InternalServerError("scan deployment "+dep.Metadata.Name, err)
+			}
+			if len(entry.Artifacts) == 0 || severityRank(entry.Severity) < minRank {
+				continue
+			}
+			report.Deployments = append(report.Deployments, entry)
+			switch entry.Severity {
+			case arv0.OutdatedSeverityHigh:
+				report.Summary.High++
+			case arv0.OutdatedSeverityMedium:
+				report.Summary.Medium++
+			default:
+				report.Summary.Low++
+			}
+		}
+		if next == "" {
+			break
+		}
+		opts.Cursor = next
+	}
+	slices.SortStableFunc(report.Deployments, func(a, b arv0.OutdatedDeployment) int {
+		if c := cmp.Compare(severityRank(b.Severity), severityRank(a.Severity)); c != 0 {
+			return c
+		}
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return report, nil
+}
+
+// deployment checks the Deployment's target and, for Agent targets, the
+// references on the pinned agent version.
+func (s *scanner) deployment(ctx context.Context, dep *v1alpha1.Deployment) (arv0.OutdatedDeployment, error) {
+	ns := dep.Metadata.NamespaceOrDefault()
+	target := dep.Spec.TargetRef
+	entry := arv0.OutdatedDeployment{
+		Namespace: ns,
+		Name:      dep.Metadata.Name,
+		Target:    fmt.Sprintf("%s/%s@%s", target.Kind, target.Name, tagOrLatest(target.Tag)),
+	}
+	refs := []fieldRef{{field: "spec.targetRef", ref: target, namespace: ns}}
+
+	if target.Kind == v1alpha1.KindAgent {
+		agent, err := s.agent(ctx, target, ns)
+		if err != nil {
+			return entry, err
+		}
+		if agent != nil {
+			refs = append(refs, agentRefs(agent)...)
+		}
+	}
+	for _, fr := range refs {
+		art, ok, err := s.check(ctx, fr)
+		if err != nil {
+			return entry, err
+		}
+		if !ok {
+			continue
+		}
+		entry.Artifacts = append(entry.Artifacts, art)
+		if severityRank(art.Severity) > severityRank(entry.Severity) {
+			entry.Severity = art.Severity
+		}
+	}
+	return entry, nil
+}
+
+// agent loads the Agent version the Deployment pins. A dangling target is
+// not this report's concern (the Deployment's own status covers it).
+func (s *scanner) agent(ctx context.Context, ref v1alpha1.ResourceRef, defaultNS string) (*v1alpha1.Agent, error) {
+	store := s.cfg.Stores[v1alpha1.KindAgent]
+	if store == nil {
+		return nil, nil
+	}
+	row, err := store.Get(ctx, cmp.Or(ref.Namespace, defaultNS), ref.Name, tagOrLatest(ref.Tag))
+	if errors.Is(err, pkgdb.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Agent { return &v1alpha1.Agent{} }, row, v1alpha1.KindAgent)
+}
+
+type fieldRef struct {
+	field     string
+	ref       v1alpha1.ResourceRef
+	namespace string
+}
+
+func agentRefs(agent *v1alpha1.Agent) []fieldRef {
+	ns := agent.Metadata.NamespaceOrDefault()
+	var out []fieldRef
+	add := func(field, kind string, refs []v1alpha1.ResourceRef) {
+		for i, ref := range refs {
+			// Peer-registry refs resolve elsewhere; nothing local to compare.
+			if ref.Registry != "" {
+				continue
+			}
+			if ref.Kind == "" {
+				ref.Kind = kind
+			}
+			out = append(out, fieldRef{field: fmt.Sprintf("%s[%d]", field, i), ref: ref, namespace: ns})
+		}
+	}
+	add("spec.mcpServers", v1alpha1.KindMCPServer, agent.Spec.MCPServers)
+	add("spec.skills", v1alpha1.KindSkill, agent.Spec.Skills)
+	add("spec.plugins", v1alpha1.KindPlugin, agent.Spec.Plugins)
+	add("spec.charts", v1alpha1.KindChart, agent.Spec.Charts)
+	if agent.Spec.Instructions != nil {
+		ref := *agent.Spec.Instructions
+		if ref.Kind == "" {
+			ref.Kind = v1alpha1.KindPrompt
+		}
+		out = append(out, fieldRef{field: "spec.instructions", ref: ref, namespace: ns})
+	}
+	return out
+}
+
+// check reports whether the referenced version is outdated or deprecated.
+func (s *scanner) check(ctx context.Context, fr fieldRef) (arv0.OutdatedArtifact, bool, error) {
+	key := artifactKey{kind: fr.ref.Kind, namespace: cmp.Or(fr.ref.Namespace, fr.namespace), name: fr.ref.Name}
+	tags, err := s.listTags(ctx, key)
+	if err != nil {
+		return arv0.OutdatedArtifact{}, false, err
+	}
+	pinnedTag := tagOrLatest(fr.ref.Tag)
+	// A version constraint pins whichever tag it currently resolves to.
+	if v1alpha1.IsTagConstraint(fr.ref.Tag) {
+		constraint, err := v1alpha1.ParseTagConstraint(fr.ref.Tag)
+		if err != nil {
+			return arv0.OutdatedArtifact{}, false, nil
+		}
+		names := make([]string, 0, len(tags))
+		for _, row := range tags {
+			names = append(names, row.Metadata.Tag)
+		}
+		if pinnedTag, _ = constraint.Best(names); pinnedTag == "" {
+			return arv0.OutdatedArtifact{}, false, nil
+		}
+	}
+	idx := slices.IndexFunc(tags, func(row *v1alpha1.RawObject) bool { return row.Metadata.Tag == pinnedTag })
+	if idx < 0 {
+		return arv0.OutdatedArtifact{}, false, nil
+	}
+	pinned := tags[idx]
+
+	art := arv0.OutdatedArtifact{
+		Field:      fr.field,
+		Kind:       key.kind,
+		Namespace:  key.namespace,
+		Name:       key.name,
+		Pinned:     pinnedTag,
+		Deprecated: pinned.Metadata.Annotations[v1alpha1.DeprecatedAnnotation],
+	}
+	var newer []*v1alpha1.RawObject
+	if pinnedTag != v1alpha1store.DefaultTag() {
+		newer = newerVersions(pinned, tags)
+	}
+	for _, row := range newer {
+		art.Newer = append(art.Newer, row.Metadata.Tag)
+		if note := row.Metadata.Annotations[v1alpha1.ChangelogAnnotation]; note != "" {
+			art.Changelog = append(art.Changelog, arv0.OutdatedChange{Tag: row.Metadata.Tag, Note: note})
+		}
+	}
+	if len(newer) > 0 {
+		art.Latest = newer[0].Metadata.Tag
+	}
+	if len(newer) == 0 && art.Deprecated == "" {
+		return arv0.OutdatedArtifact{}, false, nil
+	}
+	art.Severity, art.Reason = severity(art)
+	return art, true, nil
+}
+
+func (s *scanner) listTags(ctx context.Context, key artifactKey) ([]*v1alpha1.RawObject, error) {
+	if tags, ok := s.tags[key]; ok {
+		return tags, nil
+	}
+	store := s.cfg.Stores[key.kind]
+	if store == nil {
+		return nil, nil
+	}
+	tags, err := store.ListTags(ctx, key.namespace, key.name)
+	if err != nil {
+		return nil, fmt.Errorf("list %s %s/%s tags: %w", key.kind, key.namespace, key.name, err)
+	}
+	s.tags[key] = tags
+	return tags, nil
+}
+
+// newerVersions returns the versions after pinned, newest first. Semver
+// tags are ordered by precedence (prereleases of a newer version count);
+// otherwise a tag is newer when its row was created later. The floating
+// "latest" tag is never a version of its own.
+func newerVersions(pinned *v1alpha1.RawObject, tags []*v1alpha1.RawObject) []*v1alpha1.RawObject {
+	pinnedSemver := version.EnsureVPrefix(pinned.Metadata.Tag)
+	useSemver := semver.IsValid(pinnedSemver)
+	var out []*v1alpha1.RawObject
+	for _, row := range tags {
+		tag := row.Metadata.Tag
+		if tag == v1alpha1store.DefaultTag() || tag == pinned.Metadata.Tag {
+			continue
+		}
+		if useSemver {
+			v := version.EnsureVPrefix(tag)
+			if semver.IsValid(v) && semver.Compare(v, pinnedSemver) > 0 {
+				out = append(out, row)
+			}
+			continue
+		}
+		if row.Metadata.CreatedAt.After(pinned.Metadata.CreatedAt) {
+			out = append(out, row)
+		}
+	}
+	slices.SortFunc(out, func(a, b *v1alpha1.RawObject) int {
+		if useSemver {
+			return semver.Compare(version.EnsureVPrefix(b.Metadata.Tag), version.EnsureVPrefix(a.Metadata.Tag))
+		}
+		return b.Metadata.CreatedAt.Compare(a.Metadata.CreatedAt)
+	})
+	return out
+}
+
+// severity derives the upgrade urgency: deprecation and breaking changes
+// first, then semver distance to the newest version.
+func severity(art arv0.OutdatedArtifact) (string, string) {
+	if art.Deprecated != "" {
+		return arv0.OutdatedSeverityHigh, "pinned version is deprecated: " + art.Deprecated
+	}
+	for _, change := range art.Changelog {
+		if strings.Contains(change.Note, "BREAKING") {
+			return arv0.OutdatedSeverityHigh, fmt.Sprintf("%s changelog flags a breaking change", change.Tag)
+		}
+	}
+	pinned, latest := version.EnsureVPrefix(art.Pinned), version.EnsureVPrefix(art.Latest)
+	if !semver.IsValid(pinned) || !semver.IsValid(latest) {
+		return arv0.OutdatedSeverityLow, fmt.Sprintf("%d newer tag(s) published since %s", len(art.Newer), art.Pinned)
+	}
+	switch {
+	case semver.Major(pinned) != semver.Major(latest):
+		return arv0.OutdatedSeverityHigh, fmt.Sprintf("major version behind (%s -> %s)", art.Pinned, art.Latest)
+	case semver.MajorMinor(pinned) != semver.MajorMinor(latest):
+		return arv0.OutdatedSeverityMedium, fmt.Sprintf("minor version behind (%s -> %s)", art.Pinned, art.Latest)
+	default:
+		return arv0.OutdatedSeverityLow, fmt.Sprintf("patch version behind (%s -> %s)", art.Pinned, art.Latest)
+	}
+}
+
+func severityRank(s string) int {
+	switch s {
+	case arv0.OutdatedSeverityHigh:
+		return 3
+	case arv0.OutdatedSeverityMedium:
+		return 2
+	case arv0.OutdatedSeverityLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func tagOrLatest(tag string) string {
+	return cmp.Or(tag, v1alpha1store.DefaultTag())
+}
@@ -0,0 +1,188 @@
+package outdated_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/outdated"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore struct {
+	rows []*v1alpha1.RawObject
+}
+
+func (f *fakeStore) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	var out []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		if opts.Namespace == "" || row.Metadata.Namespace == opts.Namespace {
+			out = append(out, row)
+		}
+	}
+	return out, "", nil
+}
+
+func (f *fakeStore) Get(_ context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	for _, row := range f.rows {
+		if row.Metadata.Namespace == namespace && row.Metadata.Name == name && row.Metadata.Tag == tag {
+			return row, nil
+		}
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+func (f *fakeStore) ListTags(_ context.Context, namespace, name string) ([]*v1alpha1.RawObject, error) {
+	var out []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		if row.Metadata.Namespace == namespace && row.Metadata.Name == name {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func raw(t *testing.T, kind, ns, name, tag string, age int, annotations map[string]string, spec any) *v1alpha1.RawObject {
+	t.Helper()
+	specJSON, err := json.Marshal(spec)
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: kind},
+		Metadata: v1alpha1.ObjectMeta{
+			Namespace: ns, Name: name, Tag: tag, Annotations: annotations,
+			CreatedAt: epoch.Add(time.Duration(age) * time.Hour),
+		},
+		Spec: specJSON,
+	}
+}
+
+func deployment(t *testing.T, ns, name string, target v1alpha1.ResourceRef) *v1alpha1.RawObject {
+	return raw(t, v1alpha1.KindDeployment, ns, name, "", 0, nil, v1alpha1.DeploymentSpec{
+		TargetRef:  target,
+		RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "local"},
+	})
+}
+
+func newAPI(t *testing.T, cfg outdated.Config) humatest.TestAPI {
+	t.Helper()
+	agentSpec := v1alpha1.AgentSpec{
+		Skills: []v1alpha1.ResourceRef{
+			{Name: "summarize", Tag: "1.2.0"},
+			{Name: "translate", Tag: "2.0.0"},
+		},
+		MCPServers: []v1alpha1.ResourceRef{{Name: "weather"}},
+	}
+	mcpSpec := v1alpha1.MCPServerSpec{Title: "Weather"}
+	skillSpec := v1alpha1.SkillSpec{Title: "Skill"}
+
+	stores := map[string]outdated.Store{
+		v1alpha1.KindDeployment: &fakeStore{rows: []*v1alpha1.RawObject{
+			deployment(t, "default", "summarizer-prod", v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "summarizer", Tag: "1.0.0"}),
+			deployment(t, "default", "weather-prod", v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "nightly-1"}),
+			deployment(t, "default", "current", v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather"}),
+			deployment(t, "team-a", "legacy", v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "old"}),
+		}},
+		v1alpha1.KindAgent: &fakeStore{rows: []*v1alpha1.RawObject{
+			raw(t, v1alpha1.KindAgent, "default", "summarizer", "1.0.0", 0, nil, agentSpec),
+			raw(t, v1alpha1.KindAgent, "default", "summarizer", "1.0.3", 1, nil, agentSpec),
+		}},
+		v1alpha1.KindSkill: &fakeStore{rows: []*v1alpha1.RawObject{
+			raw(t, v1alpha1.KindSkill, "default", "summarize", "1.2.0", 0, nil, skillSpec),
+			raw(t, v1alpha1.KindSkill, "default", "summarize", "1.4.0", 2, map[string]string{v1alpha1.ChangelogAnnotation: "faster chunking"}, skillSpec),
+			raw(t, v1alpha1.KindSkill, "default", "summarize", "latest", 2, nil, skillSpec),
+			raw(t, v1alpha1.KindSkill, "default", "translate", "2.0.0", 0, nil, skillSpec),
+		}},
+		v1alpha1.KindMCPServer: &fakeStore{rows: []*v1alpha1.RawObject{
+			raw(t, v1alpha1.KindMCPServer, "default", "weather", "nightly-1", 0, nil, mcpSpec),
+			raw(t, v1alpha1.KindMCPServer, "default", "weather", "nightly-2", 1, nil, mcpSpec),
+			raw(t, v1alpha1.KindMCPServer, "default", "weather", "latest", 1, nil, mcpSpec),
+			raw(t, v1alpha1.KindMCPServer, "team-a", "weather", "old", 0, map[string]string{v1alpha1.DeprecatedAnnotation: "CVE-2026-0001"}, mcpSpec),
+		}},
+	}
+	cfg.BasePrefix = "/v0"
+	cfg.Stores = stores
+	_, api := humatest.New(t)
+	outdated.Register(api, cfg)
+	return api
+}
+
+func decode(t *testing.T, body []byte) arv0.OutdatedReport {
+	t.Helper()
+	var report arv0.OutdatedReport
+	require.NoError(t, json.Unmarshal(body, &report))
+	return report
+}
+
+func TestOutdated_DefaultNamespace(t *testing.T) {
+	api := newAPI(t, outdated.Config{})
+
+	resp := api.Get("/v0/deployments/outdated")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	report := decode(t, resp.Body.Bytes())
+
+	require.Len(t, report.Deployments, 2, "unpinned and up-to-date deployments are not reported")
+	require.Equal(t, arv0.OutdatedSummary{Medium: 1, Low: 1}, report.Summary)
+
+	agentDep := report.Deployments[0]
+	require.Equal(t, "summarizer-prod", agentDep.Name)
+	require.Equal(t, "Agent/summarizer@1.0.0", agentDep.Target)
+	require.Equal(t, arv0.OutdatedSeverityMedium, agentDep.Severity)
+	require.Len(t, agentDep.Artifacts, 2)
+
+	target := agentDep.Artifacts[0]
+	require.Equal(t, "spec.targetRef", target.Field)
+	require.Equal(t, "1.0.3", target.Latest)
+	require.Equal(t, arv0.OutdatedSeverityLow, target.Severity)
+
+	skill := agentDep.Artifacts[1]
+	require.Equal(t, "spec.skills[0]", skill.Field)
+	require.Equal(t, v1alpha1.KindSkill, skill.Kind)
+	require.Equal(t, []string{"1.4.0"}, skill.Newer, "the floating latest tag is not a version")
+	require.Equal(t, arv0.OutdatedSeverityMedium, skill.Severity)
+	require.Equal(t, []arv0.OutdatedChange{{Tag: "1.4.0", Note: "faster chunking"}}, skill.Changelog)
+
+	mcp := report.Deployments[1]
+	require.Equal(t, "weather-prod", mcp.Name)
+	require.Equal(t, "nightly-2", mcp.Artifacts[0].Latest, "non-semver tags order by publish time")
+	require.Equal(t, arv0.OutdatedSeverityLow, mcp.Severity)
+}
+
+func TestOutdated_DeprecatedAndFilters(t *testing.T) {
+	api := newAPI(t, outdated.Config{})
+
+	resp := api.Get("/v0/deployments/outdated?namespace=all&minSeverity=high")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	report := decode(t, resp.Body.Bytes())
+
+	require.Len(t, report.Deployments, 1)
+	legacy := report.Deployments[0]
+	require.Equal(t, "team-a", legacy.Namespace)
+	require.Equal(t, arv0.OutdatedSeverityHigh, legacy.Severity)
+	require.Equal(t, "CVE-2026-0001", legacy.Artifacts[0].Deprecated)
+	require.Empty(t, legacy.Artifacts[0].Latest)
+	require.Equal(t, arv0.OutdatedSummary{High: 1}, report.Summary)
+}
+
+func TestOutdated_AuthorizeDenies(t *testing.T) {
+	api := newAPI(t, outdated.Config{
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			require.Equal(t, v1alpha1.KindDeployment, in.Kind)
+			require.Equal(t, "list", in.Verb)
+			return huma.Error403Forbidden("no")
+		},
+	})
+	resp := api.Get("/v0/deployments/outdated")
+	require.Equal(t, http.StatusForbidden, resp.Code)
+}
@@ -0,0 +1,239 @@
+// Package promptevals owns the Prompt evaluation subresource:
+// `GET /v0/prompts/{name}/evaluations` lists a Prompt's score history and
+// `POST /v0/prompts/{name}/{tag}/evaluations` scores a tag on demand. Tags
+// are also scored when they are published (see internal/registry/prompteval);
+// each entry is compared with the newest earlier score of another tag, so
+// a regression shows before a tag is promoted.
+package promptevals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/prompteval"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+// Store reads the score history.
+// *v1alpha1store.PromptEvaluationStore satisfies it; tests supply a fake.
+type Store interface {
+	History(ctx context.Context, namespace, name, tag string, limit int) ([]v1alpha1store.PromptEvaluation, error)
+}
+
+var _ Store = (*v1alpha1store.PromptEvaluationStore)(nil)
+
+// Runner scores a Prompt tag and records the result. *prompteval.Evaluator
+// satisfies it.
+type Runner interface {
+	Run(ctx context.Context, namespace, name, tag, trigger, requestedBy string) (*v1alpha1store.PromptEvaluation, error)
+}
+
+var _ Runner = (*prompteval.Evaluator)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// Runner scores tags on demand. nil, when no evaluation model is
+	// configured, leaves the POST answering 501.
+	Runner Runner
+	// Authorize gates reading scores with verb "get" and running an
+	// evaluation with verb "apply", the same as the Prompt itself. nil
+	// means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type listInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Tag       string `query:"tag" doc:"Only evaluations of this tag."`
+	Limit     int    `query:"limit" doc:"Max evaluations, newest first; 0 means 50, at most 500."`
+}
+
+type runInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Tag       string `path:"tag"`
+}
+
+type listOutput struct {
+	Body arv0.PromptEvalRunList
+}
+
+type runOutput struct {
+	Body arv0.PromptEvalRun
+}
+
+// Register wires GET {basePrefix}/prompts/{name}/evaluations and
+// POST {basePrefix}/prompts/{name}/{tag}/evaluations ?namespace=default.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-prompt-evaluations",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/prompts/{name}/evaluations",
+		Summary:     "List a prompt's evaluation scores, newest first",
+		Description: "Each evaluation carries its change from the newest earlier score of another tag; a negative change is a regression.",
+	}, func(ctx context.Context, in *listInput) (*listOutput, error) {
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "get", ns, name); err != nil {
+			return nil, err
+		}
+		limit := in.Limit
+		if limit <= 0 {
+			limit = defaultLimit
+		}
+		if limit > maxLimit {
+			limit = maxLimit
+		}
+		// Read every row when filtering by tag: the comparison needs the
+		// other tags' scores.
+		fetch := limit
+		if in.Tag != "" {
+			fetch = 0
+		}
+		history, err := cfg.Store.History(ctx, ns, name, "", fetch)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("fetch prompt evaluations", err)
+		}
+		evaluations := withChanges(history)
+		out := &listOutput{Body: arv0.PromptEvalRunList{Evaluations: []arv0.PromptEvalRun{}}}
+		for _, e := range evaluations {
+			if in.Tag != "" && e.Tag != in.Tag {
+				continue
+			}
+			if len(out.Body.Evaluations) == limit {
+				break
+			}
+			out.Body.Evaluations = append(out.Body.Evaluations, e)
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "run-prompt-evaluation",
+		Method:        http.MethodPost,
+		Path:          cfg.BasePrefix + "/prompts/{name}/{tag}/evaluations",
+		Summary:       "Score a prompt tag against the evaluation model",
+		Description:   "Runs the tag's spec.evaluation now and records the result. Answers once every case has run.",
+		DefaultStatus: http.StatusCreated,
+	}, func(ctx context.Context, in *runInput) (*runOutput, error) {
+		if cfg.Runner == nil {
+			return nil, huma.Error501NotImplemented("prompt evaluation is not configured on this registry")
+		}
+		ns, name, err := parse(in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "apply", ns, name); err != nil {
+			return nil, err
+		}
+		run, err := cfg.Runner.Run(ctx, ns, name, in.Tag, v1alpha1store.PromptEvalTriggerManual, auth.SubjectFrom(ctx))
+		switch {
+		case errors.Is(err, pkgdb.ErrNotFound):
+			return nil, huma.Error404NotFound(fmt.Sprintf("Prompt %q/%q tag %q not found", ns, name, in.Tag))
+		case errors.Is(err, prompteval.ErrNoEvaluation):
+			return nil, huma.Error422UnprocessableEntity(err.Error())
+		case err != nil:
+			return nil, huma.Error500InternalServerError("evaluate prompt", err)
+		}
+		// Compare with the stored history, which now starts with run.
+		history, err := cfg.Store.History(ctx, ns, name, "", 0)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("fetch prompt evaluations", err)
+		}
+		for _, e := range withChanges(history) {
+			if e.ID == run.ID {
+				return &runOutput{Body: e}, nil
+			}
+		}
+		return &runOutput{Body: toWire(*run)}, nil
+	})
+}
+
+// withChanges converts a newest-first history to the API shape, comparing
+// every scored run with the newest earlier scored run of another tag.
+func withChanges(history []v1alpha1store.PromptEvaluation) []arv0.PromptEvalRun {
+	out := make([]arv0.PromptEvalRun, len(history))
+	for i, e := range history {
+		out[i] = toWire(e)
+		if e.Score == nil {
+			continue
+		}
+		for _, older := range history[i+1:] {
+			if older.Tag != e.Tag && older.Score != nil {
+				change := *e.Score - *older.Score
+				out[i].ChangeFrom, out[i].Change = older.Tag, &change
+				break
+			}
+		}
+	}
+	return out
+}
+
+func toWire(e v1alpha1store.PromptEvaluation) arv0.PromptEvalRun {
+	out := arv0.PromptEvalRun{
+		ID:          e.ID,
+		Namespace:   e.Namespace,
+		Name:        e.Name,
+		Tag:         e.Tag,
+		Model:       e.Model,
+		Trigger:     e.Trigger,
+		RequestedBy: e.RequestedBy,
+		CreatedAt:   e.CreatedAt.UTC(),
+		Score:       e.Score,
+		Error:       e.Error,
+		Cases:       make([]arv0.PromptCaseResult, 0, len(e.Results)),
+	}
+	for _, c := range e.Results {
+		checks := make([]arv0.PromptCheckResult, 0, len(c.Checks))
+		for _, check := range c.Checks {
+			checks = append(checks, arv0.PromptCheckResult{Check: check.Check, Score: check.Score, Detail: check.Detail})
+		}
+		out.Cases = append(out.Cases, arv0.PromptCaseResult{
+			Name:   c.Name,
+			Score:  c.Score,
+			Answer: c.Answer,
+			Checks: checks,
+			Error:  c.Error,
+		})
+	}
+	return out
+}
+
+func parse(namespace, rawName string) (ns, name string, err error) {
+	ns = namespace
+	if ns == "" {
+		ns = v1alpha1.DefaultNamespace
+	}
+	if name, err = resource.UnescapeName(rawName); err != nil {
+		return "", "", err
+	}
+	return ns, name, nil
+}
+
+func authorize(ctx context.Context, cfg Config, verb, ns, name string) error {
+	if cfg.Authorize == nil {
+		return nil
+	}
+	return cfg.Authorize(ctx, resource.AuthorizeInput{
+		Verb: verb, Kind: v1alpha1.KindPrompt,
+		Namespace: ns, Name: name,
+	})
+}
@@ -0,0 +1,166 @@
+package promptevals_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/promptevals"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/prompteval"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore struct {
+	rows []v1alpha1store.PromptEvaluation // oldest first
+}
+
+func (f *fakeStore) History(_ context.Context, namespace, name, tag string, limit int) ([]v1alpha1store.PromptEvaluation, error) {
+	var out []v1alpha1store.PromptEvaluation
+	for i := len(f.rows) - 1; i >= 0; i-- {
+		e := f.rows[i]
+		if e.Namespace != namespace || e.Name != name || (tag != "" && e.Tag != tag) {
+			continue
+		}
+		if limit > 0 && len(out) == limit {
+			break
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (f *fakeStore) add(tag string, score *float64) v1alpha1store.PromptEvaluation {
+	e := v1alpha1store.PromptEvaluation{
+		ID:        int64(len(f.rows) + 1),
+		Namespace: "default",
+		Name:      "summarizer",
+		Tag:       tag,
+		Model:     "fake",
+		Trigger:   v1alpha1store.PromptEvalTriggerPublish,
+		Score:     score,
+		CreatedAt: time.Now(),
+	}
+	if score == nil {
+		e.Error = "no case reached the model"
+	}
+	f.rows = append(f.rows, e)
+	return e
+}
+
+// fakeRunner records a run of tag 2.0.0 scoring 70 and refuses others.
+type fakeRunner struct {
+	store *fakeStore
+	calls []string
+}
+
+func (r *fakeRunner) Run(_ context.Context, namespace, name, tag, trigger, requestedBy string) (*v1alpha1store.PromptEvaluation, error) {
+	r.calls = append(r.calls, namespace+"/"+name+"@"+tag+" "+trigger)
+	switch tag {
+	case "2.0.0":
+		e := r.store.add(tag, score(70))
+		return &e, nil
+	case "plain":
+		return nil, prompteval.ErrNoEvaluation
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+func score(f float64) *float64 { return &f }
+
+func newAPI(t *testing.T, store *fakeStore, runner promptevals.Runner) humatest.TestAPI {
+	t.Helper()
+	_, api := humatest.New(t)
+	promptevals.Register(api, promptevals.Config{
+		BasePrefix: "/v0",
+		Store:      store,
+		Runner:     runner,
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			if in.Namespace == "team-a" {
+				return huma.Error403Forbidden("denied")
+			}
+			return nil
+		},
+	})
+	return api
+}
+
+func list(t *testing.T, api humatest.TestAPI, path string) []arv0.PromptEvalRun {
+	t.Helper()
+	resp := api.Get(path)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var out arv0.PromptEvalRunList
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &out))
+	return out.Evaluations
+}
+
+func TestList_ComparesWithPreviousTag(t *testing.T) {
+	store := &fakeStore{}
+	store.add("1.0.0", score(80))
+	store.add("1.1.0", nil)
+	store.add("1.1.0", score(90))
+	store.add("1.2.0", score(60))
+	api := newAPI(t, store, nil)
+
+	got := list(t, api, "/v0/prompts/summarizer/evaluations")
+	require.Len(t, got, 4)
+	require.Equal(t, "1.2.0", got[0].Tag)
+	require.Equal(t, "1.1.0", got[0].ChangeFrom)
+	require.Equal(t, -30.0, *got[0].Change)
+	require.Equal(t, "1.0.0", got[1].ChangeFrom)
+	require.Equal(t, 10.0, *got[1].Change)
+	require.Nil(t, got[2].Change, "failed runs are not compared")
+	require.Nil(t, got[3].Change, "the first tag has nothing to compare with")
+
+	got = list(t, api, "/v0/prompts/summarizer/evaluations?tag=1.1.0&limit=1")
+	require.Len(t, got, 1)
+	require.Equal(t, int64(3), got[0].ID)
+	require.Equal(t, 10.0, *got[0].Change)
+
+	resp := api.Get("/v0/prompts/summarizer/evaluations?namespace=team-a")
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+}
+
+func TestRun(t *testing.T) {
+	store := &fakeStore{}
+	store.add("1.0.0", score(80))
+	runner := &fakeRunner{store: store}
+	api := newAPI(t, store, runner)
+
+	resp := api.Post("/v0/prompts/summarizer/2.0.0/evaluations")
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	var got arv0.PromptEvalRun
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	require.Equal(t, "2.0.0", got.Tag)
+	require.Equal(t, 70.0, *got.Score)
+	require.Equal(t, "1.0.0", got.ChangeFrom)
+	require.Equal(t, -10.0, *got.Change)
+	require.Equal(t, []string{"default/summarizer@2.0.0 manual"}, runner.calls)
+
+	cases := []struct {
+		path string
+		want int
+	}{
+		{"/v0/prompts/summarizer/9.9.9/evaluations", http.StatusNotFound},
+		{"/v0/prompts/summarizer/plain/evaluations", http.StatusUnprocessableEntity},
+		{"/v0/prompts/summarizer/2.0.0/evaluations?namespace=team-a", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		resp := api.Post(tc.path)
+		require.Equal(t, tc.want, resp.Code, tc.path)
+	}
+}
+
+func TestRun_NotConfigured(t *testing.T) {
+	api := newAPI(t, &fakeStore{}, nil)
+	resp := api.Post("/v0/prompts/summarizer/1.0.0/evaluations")
+	require.Equal(t, http.StatusNotImplemented, resp.Code, resp.Body.String())
+}
@@ -0,0 +1,239 @@
+// Package public owns the read-only mirror surface under `/v0/public`: an
+// unauthenticated, cacheable view of one namespace's tagged artifacts
+// (agents, MCP servers, skills, prompts, plugins, charts) designed to sit behind a
+// CDN for high-traffic public catalogs.
+//
+// Surface, per tagged-artifact kind:
+//   - GET /v0/public/{plural}              list ("latest" tags unless ?tag=)
+//   - GET /v0/public/{plural}/{name}/{tag} one version
+//
+// Every 200 carries `Cache-Control: public, max-age=N` and a strong ETag
+// derived from the response bytes, so replicas serving the same rows emit the
+// same validator; a matching If-None-Match gets a bodyless 304. Mutations
+// and every other namespace stay on the authenticated origin paths. The
+// per-kind Authorize/ListFilter hooks are deliberately not consulted: the
+// operator designates the mirrored namespace public by enabling the surface.
+package public
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// PathSegment is the segment appended to the base prefix for every mirror
+// route. The authn middleware skips paths under it.
+const PathSegment = "/public"
+
+// maxLimit caps the page size a client can request so one cache entry stays
+// bounded. Zero (unset) falls through to the store default.
+const maxLimit = 100
+
+// Store is the narrow read surface this handler needs from a kind's store.
+// *v1alpha1store.Store satisfies it; tests supply a fake.
+type Store interface {
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+	Get(ctx context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error)
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	// Stores maps kind to store. Only tagged-artifact kinds are mirrored;
+	// mutable objects (Runtime, Deployment) are operational state, not
+	// catalog content.
+	Stores map[string]Store
+	// Namespace is the single namespace the mirror exposes.
+	Namespace string
+	// MaxAge is the Cache-Control max-age sent with every response.
+	MaxAge time.Duration
+}
+
+type listInput struct {
+	Limit       int    `query:"limit" doc:"Max items to return (capped at 100)."`
+	Cursor      string `query:"cursor" doc:"Opaque pagination cursor from a prior response."`
+	Tag         string `query:"tag" doc:"Only this tag. Defaults to 'latest'."`
+	IfNoneMatch string `header:"If-None-Match"`
+}
+
+type getInput struct {
+	Name        string `path:"name"`
+	Tag         string `path:"tag"`
+	IfNoneMatch string `header:"If-None-Match"`
+}
+
+type cachedOutput struct {
+	Status       int
+	ContentType  string `header:"Content-Type"`
+	CacheControl string `header:"Cache-Control"`
+	ETag         string `header:"ETag"`
+	Body         []byte
+}
+
+type listBody struct {
+	Items      []v1alpha1.Object `json:"items"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+// Register wires the mirror routes for every tagged-artifact kind present
+// in cfg.Stores.
+func Register(api huma.API, cfg Config) {
+	base := cfg.BasePrefix + PathSegment
+	for _, desc := range v1alpha1.KindDescriptors() {
+		if desc.Storage != v1alpha1.KindStorageTaggedArtifact {
+			continue
+		}
+		store, ok := cfg.Stores[desc.Kind]
+		if !ok || store == nil {
+			continue
+		}
+		h := handler{cfg: cfg, store: store, desc: desc}
+
+		huma.Register(api, huma.Operation{
+			OperationID: "public-list-" + desc.Plural,
+			Method:      http.MethodGet,
+			Path:        base + "/" + desc.Plural,
+			Summary:     fmt.Sprintf("List public %s (cacheable mirror)", desc.Kind),
+			Description: "Unauthenticated, read-only listing of the mirrored namespace. Responses carry Cache-Control and ETag; send If-None-Match to revalidate.",
+			Responses:   cachedResponses(),
+		}, h.list)
+
+		huma.Register(api, huma.Operation{
+			OperationID: "public-get-" + strings.ToLower(desc.Kind),
+			Method:      http.MethodGet,
+			Path:        base + "/" + desc.Plural + "/{name}/{tag}",
+			Summary:     fmt.Sprintf("Get a public %s version (cacheable mirror)", desc.Kind),
+			Responses:   cachedResponses(),
+		}, h.get)
+	}
+}
+
+func cachedResponses() map[string]*huma.Response {
+	return map[string]*huma.Response{
+		"200": {
+			Description: "JSON body, cacheable",
+			Content: map[string]*huma.MediaType{
+				"application/json": {Schema: &huma.Schema{Type: "object"}},
+			},
+		},
+		"304": {Description: "Not modified (If-None-Match matched the ETag)"},
+	}
+}
+
+type handler struct {
+	cfg   Config
+	store Store
+	desc  v1alpha1.KindDescriptor
+}
+
+func (h handler) list(ctx context.Context, in *listInput) (*cachedOutput, error) {
+	opts := v1alpha1store.ListOpts{
+		Namespace: h.cfg.Namespace,
+		Limit:     min(max(in.Limit, 0), maxLimit),
+		Cursor:    in.Cursor,
+	}
+	if in.Tag == "" {
+		opts.LatestOnly = true
+	} else {
+		opts.Tag = in.Tag
+	}
+	rows, next, err := h.store.List(ctx, opts)
+	if err != nil {
+		if errors.Is(err, v1alpha1store.ErrInvalidCursor) {
+			return nil, huma.Error400BadRequest("invalid cursor")
+		}
+		return nil, huma.Error500InternalServerError("list "+h.desc.Kind, err)
+	}
+	body := listBody{Items: make([]v1alpha1.Object, 0, len(rows)), NextCursor: next}
+	for _, row := range rows {
+		obj, err := h.envelope(row)
+		if err != nil {
+			return nil, err
+		}
+		body.Items = append(body.Items, obj)
+	}
+	return h.respond(body, in.IfNoneMatch)
+}
+
+func (h handler) get(ctx context.Context, in *getInput) (*cachedOutput, error) {
+	name, err := resource.UnescapeName(in.Name)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := resource.UnescapePath("tag", in.Tag)
+	if err != nil {
+		return nil, err
+	}
+	row, err := h.store.Get(ctx, h.cfg.Namespace, name, tag)
+	if err != nil {
+		if errors.Is(err, pkgdb.ErrNotFound) {
+			return nil, huma.Error404NotFound(fmt.Sprintf("%s %q@%q not found", h.desc.Kind, name, tag))
+		}
+		return nil, huma.Error500InternalServerError("get "+h.desc.Kind, err)
+	}
+	obj, err := h.envelope(row)
+	if err != nil {
+		return nil, err
+	}
+	return h.respond(obj, in.IfNoneMatch)
+}
+
+func (h handler) envelope(row *v1alpha1.RawObject) (v1alpha1.Object, error) {
+	newObj := func() v1alpha1.Object { return h.desc.NewObject().(v1alpha1.Object) }
+	obj, err := v1alpha1.EnvelopeFromRaw(newObj, row, h.desc.Kind)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("decode "+h.desc.Kind, err)
+	}
+	return obj, nil
+}
+
+// respond serializes body and attaches the cache headers. The ETag hashes
+// the exact bytes sent, so it is identical on every replica that reads the
+// same rows and changes whenever anything visible in the response does.
+func (h handler) respond(body any, ifNoneMatch string) (*cachedOutput, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("encode response", err)
+	}
+	sum := sha256.Sum256(data)
+	out := &cachedOutput{
+		Status:       http.StatusOK,
+		ContentType:  "application/json",
+		CacheControl: fmt.Sprintf("public, max-age=%d", int(h.cfg.MaxAge.Seconds())),
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		Body:         data,
+	}
+	if etagMatches(ifNoneMatch, out.ETag) {
+		out.Status = http.StatusNotModified
+		out.ContentType = ""
+		out.Body = nil
+	}
+	return out, nil
+}
+
+// etagMatches implements the weak comparison If-None-Match calls for:
+// any listed validator (or "*") equal to etag, ignoring a W/ prefix.
+func etagMatches(header, etag string) bool {
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package public_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/public"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore struct {
+	rows     []*v1alpha1.RawObject
+	lastList v1alpha1store.ListOpts
+}
+
+func (f *fakeStore) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	f.lastList = opts
+	var out []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		if row.Metadata.Namespace == opts.Namespace && (opts.Tag == "" || row.Metadata.Tag == opts.Tag) &&
+			(!opts.LatestOnly || row.Metadata.Tag == "latest") {
+			out = append(out, row)
+		}
+	}
+	return out, "", nil
+}
+
+func (f *fakeStore) Get(_ context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	for _, row := range f.rows {
+		if row.Metadata.Namespace == namespace && row.Metadata.Name == name && row.Metadata.Tag == tag {
+			return row, nil
+		}
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+func newAPI(t *testing.T) (humatest.TestAPI, *fakeStore) {
+	t.Helper()
+	spec, err := json.Marshal(v1alpha1.MCPServerSpec{Title: "Weather"})
+	require.NoError(t, err)
+	row := func(ns, tag string) *v1alpha1.RawObject {
+		return &v1alpha1.RawObject{
+			TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+			Metadata: v1alpha1.ObjectMeta{Namespace: ns, Name: "acme/weather", Tag: tag},
+			Spec:     spec,
+		}
+	}
+	store := &fakeStore{rows: []*v1alpha1.RawObject{
+		row("default", "latest"), row("default", "1.0.0"), row("private", "latest"),
+	}}
+	_, api := humatest.New(t)
+	public.Register(api, public.Config{
+		BasePrefix: "/v0",
+		Stores: map[string]public.Store{
+			v1alpha1.KindMCPServer: store,
+			// Mutable kinds are operational state and never mirrored.
+			v1alpha1.KindRuntime: &fakeStore{},
+		},
+		Namespace: "default",
+		MaxAge:    time.Hour,
+	})
+	return api, store
+}
+
+func TestPublicMirror(t *testing.T) {
+	api, store := newAPI(t)
+
+	resp := api.Get("/v0/public/mcpservers")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, "public, max-age=3600", resp.Header().Get("Cache-Control"))
+	etag := resp.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	require.True(t, store.lastList.LatestOnly)
+
+	var body struct {
+		Items []v1alpha1.MCPServer `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Items, 1, "only the mirrored namespace's latest row")
+	require.Equal(t, "latest", body.Items[0].Metadata.Tag)
+
+	again := api.Get("/v0/public/mcpservers")
+	require.Equal(t, etag, again.Header().Get("ETag"), "ETag is stable for identical content")
+
+	notModified := api.Get("/v0/public/mcpservers", "If-None-Match: W/"+etag)
+	require.Equal(t, http.StatusNotModified, notModified.Code)
+	require.Empty(t, notModified.Body.Bytes())
+	require.Equal(t, etag, notModified.Header().Get("ETag"))
+
+	tagged := api.Get("/v0/public/mcpservers?tag=1.0.0")
+	require.Equal(t, http.StatusOK, tagged.Code)
+	require.NotEqual(t, etag, tagged.Header().Get("ETag"))
+
+	one := api.Get("/v0/public/mcpservers/acme%2Fweather/1.0.0")
+	require.Equal(t, http.StatusOK, one.Code, one.Body.String())
+	require.NotEmpty(t, one.Header().Get("ETag"))
+
+	require.Equal(t, http.StatusNotFound, api.Get("/v0/public/mcpservers/acme%2Fweather/9.9.9").Code)
+	require.Equal(t, http.StatusNotFound, api.Get("/v0/public/runtimes").Code)
+}
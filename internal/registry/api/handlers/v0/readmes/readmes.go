@@ -0,0 +1,213 @@
+// Package readmes owns the artifact README subresource:
+// `PUT/GET /v0/{plural}/{name}/{tag}/readme` for Agents, MCPServers,
+// Skills and Prompts. READMEs are Markdown kept beside the artifact tag
+// rather than in its spec, so publishers can revise them without
+// re-publishing. An MCPServer with no uploaded README serves its
+// spec.readme instead.
+package readmes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// ContentType is the media type READMEs are accepted and served as.
+const ContentType = "text/markdown; charset=utf-8"
+
+// Store reads and writes READMEs. *v1alpha1store.ArtifactReadmeStore
+// satisfies it; tests supply a fake.
+type Store interface {
+	Put(ctx context.Context, r v1alpha1store.ArtifactReadme) error
+	Get(ctx context.Context, kind, namespace, name, tag string) (*v1alpha1store.ArtifactReadme, error)
+}
+
+var _ Store = (*v1alpha1store.ArtifactReadmeStore)(nil)
+
+// Artifacts resolves the artifact tag a README belongs to.
+// *v1alpha1store.Store satisfies it.
+type Artifacts interface {
+	Get(ctx context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error)
+}
+
+var _ Artifacts = (*v1alpha1store.Store)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	// Kind is the artifact kind served; Artifacts is its store.
+	Kind      string
+	Artifacts Artifacts
+	Store     Store
+	// Authorize gates reads with verb "get" and uploads with verb "apply",
+	// the same as the artifact itself. nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type readmeInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Tag       string `path:"tag"`
+}
+
+type putReadmeInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Tag       string `path:"tag"`
+	RawBody   []byte `contentType:"text/markdown" doc:"README as Markdown."`
+}
+
+type readmeOutput struct {
+	ContentType  string `header:"Content-Type"`
+	LastModified string `header:"Last-Modified"`
+	Body         []byte
+}
+
+// Register wires PUT and GET {basePrefix}/{plural}/{name}/{tag}/readme
+// ?namespace=default. Both answer 404 when the artifact tag does not
+// exist; GET also answers 404 when the tag has no README.
+func Register(api huma.API, cfg Config) {
+	plural := v1alpha1.PluralFor(cfg.Kind)
+	path := cfg.BasePrefix + "/" + plural + "/{name}/{tag}/readme"
+	kind := strings.ToLower(cfg.Kind)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "put-" + kind + "-readme",
+		Method:        http.MethodPut,
+		Path:          path,
+		Summary:       fmt.Sprintf("Upload the README for a %s tag", cfg.Kind),
+		Description:   fmt.Sprintf("Replaces the tag's Markdown README. At most %d bytes of UTF-8.", v1alpha1.MaxReadmeLength),
+		DefaultStatus: http.StatusNoContent,
+		MaxBodyBytes:  v1alpha1.MaxReadmeLength,
+	}, func(ctx context.Context, in *putReadmeInput) (*struct{}, error) {
+		ns, name, tag, err := parse(readmeInput{Namespace: in.Namespace, Name: in.Name, Tag: in.Tag})
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "apply", ns, name, tag); err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(in.RawBody) {
+			return nil, huma.Error400BadRequest("README must be UTF-8 text")
+		}
+		row, err := getArtifact(ctx, cfg, ns, name, tag)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.Store.Put(ctx, v1alpha1store.ArtifactReadme{
+			Kind:        cfg.Kind,
+			Namespace:   ns,
+			Name:        name,
+			Tag:         tag,
+			ArtifactUID: row.Metadata.UID,
+			Content:     string(in.RawBody),
+		}); err != nil {
+			return nil, huma.Error500InternalServerError("store README", err)
+		}
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-" + kind + "-readme",
+		Method:      http.MethodGet,
+		Path:        path,
+		Summary:     fmt.Sprintf("Get the README for a %s tag", cfg.Kind),
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "README as Markdown",
+				Content: map[string]*huma.MediaType{
+					"text/markdown": {Schema: &huma.Schema{Type: "string"}},
+				},
+			},
+		},
+	}, func(ctx context.Context, in *readmeInput) (*readmeOutput, error) {
+		ns, name, tag, err := parse(*in)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, cfg, "get", ns, name, tag); err != nil {
+			return nil, err
+		}
+		row, err := getArtifact(ctx, cfg, ns, name, tag)
+		if err != nil {
+			return nil, err
+		}
+		rec, err := cfg.Store.Get(ctx, cfg.Kind, ns, name, tag)
+		switch {
+		case err == nil && rec.ArtifactUID == row.Metadata.UID:
+			return &readmeOutput{
+				ContentType:  ContentType,
+				LastModified: rec.UpdatedAt.UTC().Format(http.TimeFormat),
+				Body:         []byte(rec.Content),
+			}, nil
+		case err != nil && !errors.Is(err, pkgdb.ErrNotFound):
+			return nil, huma.Error500InternalServerError("fetch README", err)
+		}
+		// Nothing uploaded for this incarnation of the tag.
+		if content := specReadme(cfg.Kind, row); content != "" {
+			return &readmeOutput{ContentType: ContentType, Body: []byte(content)}, nil
+		}
+		return nil, huma.Error404NotFound(fmt.Sprintf("%s %q/%q@%q has no README", cfg.Kind, ns, name, tag))
+	})
+}
+
+func parse(in readmeInput) (ns, name, tag string, err error) {
+	ns = in.Namespace
+	if ns == "" {
+		ns = v1alpha1.DefaultNamespace
+	}
+	if name, err = resource.UnescapeName(in.Name); err != nil {
+		return "", "", "", err
+	}
+	if tag, err = resource.UnescapePath("tag", in.Tag); err != nil {
+		return "", "", "", err
+	}
+	return ns, name, tag, nil
+}
+
+func authorize(ctx context.Context, cfg Config, verb, ns, name, tag string) error {
+	if cfg.Authorize == nil {
+		return nil
+	}
+	return cfg.Authorize(ctx, resource.AuthorizeInput{
+		Verb: verb, Kind: cfg.Kind,
+		Namespace: ns, Name: name, Tag: tag,
+	})
+}
+
+func getArtifact(ctx context.Context, cfg Config, ns, name, tag string) (*v1alpha1.RawObject, error) {
+	row, err := cfg.Artifacts.Get(ctx, ns, name, tag)
+	if err != nil {
+		if errors.Is(err, pkgdb.ErrNotFound) {
+			return nil, huma.Error404NotFound(fmt.Sprintf("%s %q/%q@%q not found", cfg.Kind, ns, name, tag))
+		}
+		return nil, huma.Error500InternalServerError("fetch "+cfg.Kind, err)
+	}
+	return row, nil
+}
+
+// specReadme returns the README an MCPServer carries inline in
+// spec.readme; other kinds have none.
+func specReadme(kind string, row *v1alpha1.RawObject) string {
+	if kind != v1alpha1.KindMCPServer || len(row.Spec) == 0 {
+		return ""
+	}
+	var spec struct {
+		Readme string `json:"readme"`
+	}
+	if err := json.Unmarshal(row.Spec, &spec); err != nil {
+		return ""
+	}
+	return spec.Readme
+}
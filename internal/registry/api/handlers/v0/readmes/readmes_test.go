@@ -0,0 +1,121 @@
+package readmes_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/readmes"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeArtifacts map[string]*v1alpha1.RawObject
+
+func (f fakeArtifacts) Get(_ context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	if row, ok := f[namespace+"/"+name+"@"+tag]; ok {
+		return row, nil
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+type fakeStore map[string]v1alpha1store.ArtifactReadme
+
+func (f fakeStore) Put(_ context.Context, r v1alpha1store.ArtifactReadme) error {
+	r.UpdatedAt = time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	f[r.Kind+"/"+r.Namespace+"/"+r.Name+"@"+r.Tag] = r
+	return nil
+}
+
+func (f fakeStore) Get(_ context.Context, kind, namespace, name, tag string) (*v1alpha1store.ArtifactReadme, error) {
+	if r, ok := f[kind+"/"+namespace+"/"+name+"@"+tag]; ok {
+		return &r, nil
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+func register(t *testing.T, kind string, artifacts fakeArtifacts, store fakeStore) humatest.TestAPI {
+	t.Helper()
+	_, api := humatest.New(t)
+	readmes.Register(api, readmes.Config{
+		BasePrefix: "/v0",
+		Kind:       kind,
+		Artifacts:  artifacts,
+		Store:      store,
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			if in.Namespace == "team-a" && in.Verb == "apply" {
+				return huma.Error403Forbidden("denied")
+			}
+			return nil
+		},
+	})
+	return api
+}
+
+func TestPutGetReadme(t *testing.T) {
+	artifacts := fakeArtifacts{
+		"default/bot@v1": {Metadata: v1alpha1.ObjectMeta{Name: "bot", Tag: "v1", UID: "uid-1"}},
+		"team-a/bot@v1":  {Metadata: v1alpha1.ObjectMeta{Namespace: "team-a", Name: "bot", Tag: "v1", UID: "uid-2"}},
+	}
+	store := fakeStore{}
+	api := register(t, v1alpha1.KindAgent, artifacts, store)
+
+	resp := api.Get("/v0/agents/bot/v1/readme")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	resp = api.Put("/v0/agents/bot/v1/readme", "Content-Type: text/markdown", strings.NewReader("# Bot\n"))
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	require.Equal(t, "uid-1", store["Agent/default/bot@v1"].ArtifactUID)
+
+	resp = api.Get("/v0/agents/bot/v1/readme")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, readmes.ContentType, resp.Header().Get("Content-Type"))
+	require.Equal(t, "Sun, 18 Oct 2026 09:30:00 GMT", resp.Header().Get("Last-Modified"))
+	require.Equal(t, "# Bot\n", resp.Body.String())
+
+	resp = api.Put("/v0/agents/missing/v1/readme", "Content-Type: text/markdown", strings.NewReader("# Missing\n"))
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	resp = api.Put("/v0/agents/bot/v1/readme?namespace=team-a", "Content-Type: text/markdown", strings.NewReader("# Bot\n"))
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = api.Put("/v0/agents/bot/v1/readme", "Content-Type: text/markdown", strings.NewReader("\xff\xfe"))
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+}
+
+func TestGetReadme_IgnoresPurgedIncarnation(t *testing.T) {
+	artifacts := fakeArtifacts{"default/summarize@v1": {Metadata: v1alpha1.ObjectMeta{Name: "summarize", Tag: "v1", UID: "uid-new"}}}
+	store := fakeStore{"Skill/default/summarize@v1": {
+		Kind: v1alpha1.KindSkill, Namespace: "default", Name: "summarize", Tag: "v1", ArtifactUID: "uid-old", Content: "# Old\n",
+	}}
+	api := register(t, v1alpha1.KindSkill, artifacts, store)
+
+	resp := api.Get("/v0/skills/summarize/v1/readme")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
+
+func TestGetReadme_MCPServerFallsBackToSpec(t *testing.T) {
+	spec, err := json.Marshal(v1alpha1.MCPServerSpec{Readme: "# Weather\n"})
+	require.NoError(t, err)
+	artifacts := fakeArtifacts{"default/weather@latest": {Metadata: v1alpha1.ObjectMeta{Name: "weather", Tag: "latest", UID: "uid-1"}, Spec: spec}}
+	store := fakeStore{}
+	api := register(t, v1alpha1.KindMCPServer, artifacts, store)
+
+	resp := api.Get("/v0/mcpservers/weather/latest/readme")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, "# Weather\n", resp.Body.String())
+
+	resp = api.Put("/v0/mcpservers/weather/latest/readme", "Content-Type: text/markdown", strings.NewReader("# Weather v2\n"))
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	resp = api.Get("/v0/mcpservers/weather/latest/readme")
+	require.Equal(t, "# Weather v2\n", resp.Body.String())
+}
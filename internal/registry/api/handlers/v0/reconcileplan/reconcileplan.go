@@ -0,0 +1,54 @@
+// Package reconcileplan owns the admin dry-run reconcile endpoint:
+// `POST /v0/admin/reconcile:plan`. It asks the Deployment controller what a
+// full reconcile would create/update/delete per Runtime without touching any
+// runtime, which operators need before pointing the registry at a cluster
+// that already runs workloads.
+package reconcileplan
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+// Planner is the only controller capability needed by this handler.
+type Planner interface {
+	Plan(ctx context.Context) (arv0.ReconcilePlan, error)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Planner    Planner
+	// IsAdmin gates the endpoint. The plan enumerates every Deployment in
+	// every namespace, so there is no per-resource authz to fall back to;
+	// the gate lives here rather than in the DB layer. nil denies.
+	IsAdmin func(ctx context.Context) bool
+}
+
+type reconcilePlanOutput struct {
+	Body arv0.ReconcilePlan
+}
+
+// Register wires POST {basePrefix}/admin/reconcile:plan.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "plan-reconcile",
+		Method:      http.MethodPost,
+		Path:        cfg.BasePrefix + "/admin/reconcile:plan",
+		Summary:     "Report what a full Deployment reconcile would do without touching runtimes",
+		Tags:        []string{"admin"},
+	}, func(ctx context.Context, _ *struct{}) (*reconcilePlanOutput, error) {
+		if cfg.IsAdmin == nil || !cfg.IsAdmin(ctx) {
+			return nil, huma.Error403Forbidden("registry admin permission required")
+		}
+		plan, err := cfg.Planner.Plan(ctx)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("plan reconcile", err)
+		}
+		return &reconcilePlanOutput{Body: plan}, nil
+	})
+}
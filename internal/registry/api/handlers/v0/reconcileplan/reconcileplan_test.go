@@ -0,0 +1,70 @@
+package reconcileplan_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/reconcileplan"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+type fakePlanner struct {
+	plan arv0.ReconcilePlan
+	err  error
+}
+
+func (f fakePlanner) Plan(context.Context) (arv0.ReconcilePlan, error) {
+	return f.plan, f.err
+}
+
+func TestRegisterReconcilePlan(t *testing.T) {
+	plan := arv0.ReconcilePlan{
+		Runtimes: []arv0.RuntimeReconcilePlan{{
+			Namespace: "default",
+			Name:      "local",
+			Type:      "Local",
+			Deployments: []arv0.DeploymentPlanEntry{{
+				Namespace: "default",
+				Name:      "weather",
+				Action:    arv0.ReconcilePlanActionCreate,
+			}},
+		}},
+		Summary: arv0.ReconcilePlanSummary{Create: 1},
+	}
+
+	tests := []struct {
+		name     string
+		planner  fakePlanner
+		isAdmin  func(context.Context) bool
+		wantCode int
+	}{
+		{"admin gets plan", fakePlanner{plan: plan}, func(context.Context) bool { return true }, http.StatusOK},
+		{"non-admin forbidden", fakePlanner{plan: plan}, func(context.Context) bool { return false }, http.StatusForbidden},
+		{"nil gate denies", fakePlanner{plan: plan}, nil, http.StatusForbidden},
+		{"planner failure", fakePlanner{err: errors.New("boom")}, func(context.Context) bool { return true }, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, api := humatest.New(t)
+			reconcileplan.Register(api, reconcileplan.Config{
+				BasePrefix: "/v0",
+				Planner:    tt.planner,
+				IsAdmin:    tt.isAdmin,
+			})
+			resp := api.Post("/v0/admin/reconcile:plan")
+			require.Equal(t, tt.wantCode, resp.Code, resp.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var got arv0.ReconcilePlan
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+			require.Equal(t, plan, got)
+		})
+	}
+}
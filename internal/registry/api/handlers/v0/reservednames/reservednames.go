@@ -0,0 +1,288 @@
+// Package reservednames owns the reserved name prefix endpoints:
+// `/v0/reserved-prefixes`, where registry admins reserve prefixes such as
+// official/ or trademarked names, and `/v0/reserved-prefix-requests`,
+// where callers ask to be allocated one and admins approve or reject the
+// request. Enforcement on publish lives in internal/registry/reservednames;
+// this package only records reservations and requests.
+package reservednames
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/reservednames"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Store is the reserved prefix capability the endpoints need.
+// *v1alpha1store.ReservedPrefixStore satisfies it.
+type Store interface {
+	List(ctx context.Context) ([]*v1alpha1store.ReservedPrefix, error)
+	Put(ctx context.Context, prefix, reason string, owners []string) (*v1alpha1store.ReservedPrefix, error)
+	Delete(ctx context.Context, prefix string) error
+	CreateRequest(ctx context.Context, prefix, requester, justification string) (*v1alpha1store.PrefixRequest, error)
+	ListRequests(ctx context.Context, requester string) ([]*v1alpha1store.PrefixRequest, error)
+	DecideRequest(ctx context.Context, id int64, approve bool, decidedBy string) (*v1alpha1store.PrefixRequest, error)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// Configured are the prefixes the server configuration reserves. They
+	// are listed alongside the admin-managed ones but cannot be removed
+	// through the API.
+	Configured []string
+	// IsAdmin lets registry admins manage prefixes and decide requests.
+	// nil grants nobody admin.
+	IsAdmin func(ctx context.Context) bool
+}
+
+type prefixInput struct {
+	Body arv0.ReservedPrefixInput
+}
+
+type deleteInput struct {
+	Prefix string `query:"prefix" required:"true" doc:"Reserved prefix to remove, e.g. acme/"`
+}
+
+type claimInput struct {
+	Body arv0.ReservedPrefixClaim
+}
+
+type requestIDInput struct {
+	ID int64 `path:"id" doc:"Request ID"`
+}
+
+type prefixOutput struct {
+	Body arv0.ReservedPrefix
+}
+
+type listOutput struct {
+	Body arv0.ReservedPrefixList
+}
+
+type requestOutput struct {
+	Body arv0.ReservedPrefixRequest
+}
+
+type requestListOutput struct {
+	Body arv0.ReservedPrefixRequestList
+}
+
+// Register wires the /v0/reserved-prefixes and
+// /v0/reserved-prefix-requests endpoints.
+func Register(api huma.API, cfg Config) {
+	isAdmin := func(ctx context.Context) bool { return cfg.IsAdmin != nil && cfg.IsAdmin(ctx) }
+	requireAdmin := func(ctx context.Context) error {
+		if !isAdmin(ctx) {
+			return huma.Error403Forbidden("only registry admins may manage reserved prefixes")
+		}
+		return nil
+	}
+	guard := reservednames.New(cfg.Store, cfg.Configured, nil)
+	base := cfg.BasePrefix + "/reserved-prefixes"
+	requestsBase := cfg.BasePrefix + "/reserved-prefix-requests"
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-reserved-prefixes",
+		Method:      http.MethodGet,
+		Path:        base,
+		Summary:     "List reserved name prefixes",
+		Tags:        []string{"reserved-names"},
+	}, func(ctx context.Context, _ *struct{}) (*listOutput, error) {
+		prefixes, err := guard.Prefixes(ctx)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list reserved prefixes", err)
+		}
+		out := &listOutput{Body: arv0.ReservedPrefixList{Prefixes: make([]arv0.ReservedPrefix, 0, len(prefixes))}}
+		for _, p := range prefixes {
+			out.Body.Prefixes = append(out.Body.Prefixes, toWire(p))
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-reserved-prefix",
+		Method:      http.MethodPut,
+		Path:        base,
+		Summary:     "Reserve a name prefix, or replace its reason and owners",
+		Tags:        []string{"reserved-names"},
+	}, func(ctx context.Context, in *prefixInput) (*prefixOutput, error) {
+		if err := requireAdmin(ctx); err != nil {
+			return nil, err
+		}
+		prefix := reservednames.NormalizePrefix(in.Body.Prefix)
+		if prefix == "" {
+			return nil, huma.Error400BadRequest("prefix must not be empty")
+		}
+		owners := make([]string, 0, len(in.Body.Owners))
+		for _, o := range in.Body.Owners {
+			o = strings.TrimSpace(o)
+			if o == "" {
+				return nil, huma.Error400BadRequest("owners must not be empty")
+			}
+			if !slices.Contains(owners, o) {
+				owners = append(owners, o)
+			}
+		}
+		row, err := cfg.Store.Put(ctx, prefix, in.Body.Reason, owners)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("reserve prefix", err)
+		}
+		return &prefixOutput{Body: toWire(reservednames.Merge(configuredMatching(cfg.Configured, prefix), []*v1alpha1store.ReservedPrefix{row})[0])}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-reserved-prefix",
+		Method:        http.MethodDelete,
+		Path:          base,
+		Summary:       "Release an admin-reserved name prefix",
+		Tags:          []string{"reserved-names"},
+		DefaultStatus: http.StatusNoContent,
+	}, func(ctx context.Context, in *deleteInput) (*struct{}, error) {
+		if err := requireAdmin(ctx); err != nil {
+			return nil, err
+		}
+		prefix := reservednames.NormalizePrefix(in.Prefix)
+		if len(configuredMatching(cfg.Configured, prefix)) > 0 {
+			return nil, huma.Error409Conflict(fmt.Sprintf(
+				"prefix %q is reserved by the server configuration; remove it from RESERVED_NAME_PREFIXES instead", prefix))
+		}
+		if err := cfg.Store.Delete(ctx, prefix); err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("prefix %q is not reserved", prefix))
+			}
+			return nil, huma.Error500InternalServerError("release prefix", err)
+		}
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "request-reserved-prefix",
+		Method:      http.MethodPost,
+		Path:        requestsBase,
+		Summary:     "Ask to be allocated a reserved name prefix",
+		Tags:        []string{"reserved-names"},
+	}, func(ctx context.Context, in *claimInput) (*requestOutput, error) {
+		subject := auth.SubjectFrom(ctx)
+		if subject == "" {
+			return nil, huma.Error401Unauthorized("requesting a reserved prefix requires an authenticated caller")
+		}
+		prefixes, err := guard.Prefixes(ctx)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list reserved prefixes", err)
+		}
+		prefix := reservednames.NormalizePrefix(in.Body.Prefix)
+		i := slices.IndexFunc(prefixes, func(p reservednames.Prefix) bool { return p.Prefix == prefix })
+		if i < 0 {
+			return nil, huma.Error404NotFound(fmt.Sprintf("prefix %q is not reserved; names under it can be published without an allocation", prefix))
+		}
+		if slices.Contains(prefixes[i].Owners, subject) {
+			return nil, huma.Error409Conflict(fmt.Sprintf("%s is already allocated prefix %q", subject, prefix))
+		}
+		req, err := cfg.Store.CreateRequest(ctx, prefix, subject, in.Body.Justification)
+		switch {
+		case errors.Is(err, pkgdb.ErrAlreadyExists):
+			return nil, huma.Error409Conflict(err.Error())
+		case err != nil:
+			return nil, huma.Error500InternalServerError("request reserved prefix", err)
+		}
+		return &requestOutput{Body: requestToWire(req)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-reserved-prefix-requests",
+		Method:      http.MethodGet,
+		Path:        requestsBase,
+		Summary:     "List reserved prefix requests: every request for registry admins, the caller's own otherwise",
+		Tags:        []string{"reserved-names"},
+	}, func(ctx context.Context, _ *struct{}) (*requestListOutput, error) {
+		requester := ""
+		if !isAdmin(ctx) {
+			requester = auth.SubjectFrom(ctx)
+			if requester == "" {
+				return nil, huma.Error401Unauthorized("listing reserved prefix requests requires an authenticated caller")
+			}
+		}
+		reqs, err := cfg.Store.ListRequests(ctx, requester)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list reserved prefix requests", err)
+		}
+		out := &requestListOutput{Body: arv0.ReservedPrefixRequestList{Requests: make([]arv0.ReservedPrefixRequest, 0, len(reqs))}}
+		for _, req := range reqs {
+			out.Body.Requests = append(out.Body.Requests, requestToWire(req))
+		}
+		return out, nil
+	})
+
+	for _, decision := range []struct {
+		verb    string
+		approve bool
+		summary string
+	}{
+		{"approve", true, "Approve a reserved prefix request, allocating the prefix to its requester"},
+		{"reject", false, "Reject a reserved prefix request"},
+	} {
+		huma.Register(api, huma.Operation{
+			OperationID: decision.verb + "-reserved-prefix-request",
+			Method:      http.MethodPost,
+			Path:        requestsBase + "/{id}/" + decision.verb,
+			Summary:     decision.summary,
+			Tags:        []string{"reserved-names"},
+		}, func(ctx context.Context, in *requestIDInput) (*requestOutput, error) {
+			if err := requireAdmin(ctx); err != nil {
+				return nil, err
+			}
+			req, err := cfg.Store.DecideRequest(ctx, in.ID, decision.approve, auth.SubjectFrom(ctx))
+			switch {
+			case errors.Is(err, pkgdb.ErrNotFound):
+				return nil, huma.Error404NotFound(fmt.Sprintf("no pending reserved prefix request %d", in.ID))
+			case err != nil:
+				return nil, huma.Error500InternalServerError(decision.verb+" reserved prefix request", err)
+			}
+			return &requestOutput{Body: requestToWire(req)}, nil
+		})
+	}
+}
+
+// configuredMatching returns the configured prefixes equal to prefix once
+// normalized: none or one.
+func configuredMatching(configured []string, prefix string) []string {
+	for _, p := range configured {
+		if reservednames.NormalizePrefix(p) == prefix {
+			return []string{p}
+		}
+	}
+	return nil
+}
+
+func toWire(p reservednames.Prefix) arv0.ReservedPrefix {
+	owners := p.Owners
+	if owners == nil {
+		owners = []string{}
+	}
+	return arv0.ReservedPrefix{Prefix: p.Prefix, Reason: p.Reason, Owners: owners, Source: p.Source}
+}
+
+func requestToWire(req *v1alpha1store.PrefixRequest) arv0.ReservedPrefixRequest {
+	return arv0.ReservedPrefixRequest{
+		ID:            req.ID,
+		Prefix:        req.Prefix,
+		Requester:     req.Requester,
+		Justification: req.Justification,
+		Status:        req.Status,
+		DecidedBy:     req.DecidedBy,
+		DecidedAt:     req.DecidedAt,
+		CreatedAt:     req.CreatedAt,
+	}
+}
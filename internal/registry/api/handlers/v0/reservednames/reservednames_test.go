@@ -0,0 +1,203 @@
+package reservednames_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/internal/testapi"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/reservednames"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore struct {
+	prefixes map[string]*v1alpha1store.ReservedPrefix
+	requests []*v1alpha1store.PrefixRequest
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{prefixes: map[string]*v1alpha1store.ReservedPrefix{}}
+}
+
+func (f *fakeStore) List(context.Context) ([]*v1alpha1store.ReservedPrefix, error) {
+	var out []*v1alpha1store.ReservedPrefix
+	for _, p := range f.prefixes {
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func (f *fakeStore) Put(_ context.Context, prefix, reason string, owners []string) (*v1alpha1store.ReservedPrefix, error) {
+	f.prefixes[prefix] = &v1alpha1store.ReservedPrefix{Prefix: prefix, Reason: reason, Owners: owners}
+	return f.prefixes[prefix], nil
+}
+
+func (f *fakeStore) Delete(_ context.Context, prefix string) error {
+	if _, ok := f.prefixes[prefix]; !ok {
+		return pkgdb.ErrNotFound
+	}
+	delete(f.prefixes, prefix)
+	return nil
+}
+
+func (f *fakeStore) CreateRequest(_ context.Context, prefix, requester, justification string) (*v1alpha1store.PrefixRequest, error) {
+	for _, r := range f.requests {
+		if r.Prefix == prefix && r.Requester == requester && r.Status == v1alpha1store.PrefixRequestPending {
+			return nil, pkgdb.ErrAlreadyExists
+		}
+	}
+	req := &v1alpha1store.PrefixRequest{
+		ID:            int64(len(f.requests) + 1),
+		Prefix:        prefix,
+		Requester:     requester,
+		Justification: justification,
+		Status:        v1alpha1store.PrefixRequestPending,
+		CreatedAt:     time.Now(),
+	}
+	f.requests = append(f.requests, req)
+	return req, nil
+}
+
+func (f *fakeStore) ListRequests(_ context.Context, requester string) ([]*v1alpha1store.PrefixRequest, error) {
+	var out []*v1alpha1store.PrefixRequest
+	for _, r := range f.requests {
+		if requester == "" || r.Requester == requester {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) DecideRequest(_ context.Context, id int64, approve bool, decidedBy string) (*v1alpha1store.PrefixRequest, error) {
+	i := slices.IndexFunc(f.requests, func(r *v1alpha1store.PrefixRequest) bool {
+		return r.ID == id && r.Status == v1alpha1store.PrefixRequestPending
+	})
+	if i < 0 {
+		return nil, pkgdb.ErrNotFound
+	}
+	req := f.requests[i]
+	req.Status, req.DecidedBy = v1alpha1store.PrefixRequestRejected, decidedBy
+	if approve {
+		req.Status = v1alpha1store.PrefixRequestApproved
+		row, ok := f.prefixes[req.Prefix]
+		if !ok {
+			row = &v1alpha1store.ReservedPrefix{Prefix: req.Prefix}
+			f.prefixes[req.Prefix] = row
+		}
+		row.Owners = append(row.Owners, req.Requester)
+	}
+	return req, nil
+}
+
+// newAPI registers the endpoints on a testapi.New API. The subject "admin"
+// is a registry admin.
+func newAPI(t *testing.T, store *fakeStore) humatest.TestAPI {
+	api := testapi.New(t)
+	reservednames.Register(api, reservednames.Config{
+		BasePrefix: "/v0",
+		Store:      store,
+		Configured: []string{"official/*", "mcp/"},
+		IsAdmin:    testapi.IsAdmin,
+	})
+	return api
+}
+
+func listPrefixes(t *testing.T, api humatest.TestAPI) []arv0.ReservedPrefix {
+	t.Helper()
+	resp := api.Get("/v0/reserved-prefixes")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var list arv0.ReservedPrefixList
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	return list.Prefixes
+}
+
+func TestManageReservedPrefixes(t *testing.T) {
+	store := newFakeStore()
+	api := newAPI(t, store)
+
+	prefixes := listPrefixes(t, api)
+	require.Len(t, prefixes, 2)
+	require.Equal(t, arv0.ReservedPrefix{Prefix: "mcp/", Owners: []string{}, Source: "config"}, prefixes[0])
+
+	body := map[string]any{"prefix": "Acme*", "reason": "trademark", "owners": []string{"alice", " alice "}}
+	resp := api.Put("/v0/reserved-prefixes", "X-Subject: mallory", body)
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = api.Put("/v0/reserved-prefixes", "X-Subject: admin", body)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var put arv0.ReservedPrefix
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &put))
+	require.Equal(t, arv0.ReservedPrefix{Prefix: "acme", Reason: "trademark", Owners: []string{"alice"}, Source: "admin"}, put)
+	require.Len(t, listPrefixes(t, api), 3)
+
+	resp = api.Delete("/v0/reserved-prefixes?prefix=official/", "X-Subject: admin")
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body.String())
+	resp = api.Delete("/v0/reserved-prefixes?prefix=acme", "X-Subject: mallory")
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+	resp = api.Delete("/v0/reserved-prefixes?prefix=acme", "X-Subject: admin")
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	resp = api.Delete("/v0/reserved-prefixes?prefix=acme", "X-Subject: admin")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
+
+func TestRequestAndApproveAllocation(t *testing.T) {
+	store := newFakeStore()
+	api := newAPI(t, store)
+
+	claim := map[string]any{"prefix": "official/", "justification": "we run the registry"}
+	resp := api.Post("/v0/reserved-prefix-requests", claim)
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/reserved-prefix-requests", "X-Subject: alice", map[string]any{"prefix": "unreserved/"})
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/reserved-prefix-requests", "X-Subject: alice", claim)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var req arv0.ReservedPrefixRequest
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &req))
+	require.Equal(t, "pending", req.Status)
+	resp = api.Post("/v0/reserved-prefix-requests", "X-Subject: alice", claim)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/reserved-prefix-requests", "X-Subject: bob", map[string]any{"prefix": "mcp/"})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	// Callers see their own requests; admins see every request.
+	resp = api.Get("/v0/reserved-prefix-requests", "X-Subject: alice")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, 1, strings.Count(resp.Body.String(), `"requester"`))
+	resp = api.Get("/v0/reserved-prefix-requests", "X-Subject: admin")
+	require.Equal(t, 2, strings.Count(resp.Body.String(), `"requester"`))
+	resp = api.Get("/v0/reserved-prefix-requests")
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/reserved-prefix-requests/1/approve", "X-Subject: alice")
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+	resp = api.Post("/v0/reserved-prefix-requests/1/approve", "X-Subject: admin")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &req))
+	require.Equal(t, "approved", req.Status)
+	require.Equal(t, "admin", req.DecidedBy)
+	resp = api.Post("/v0/reserved-prefix-requests/1/reject", "X-Subject: admin")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	resp = api.Post("/v0/reserved-prefix-requests/2/reject", "X-Subject: admin")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	prefixes := listPrefixes(t, api)
+	i := slices.IndexFunc(prefixes, func(p arv0.ReservedPrefix) bool { return p.Prefix == "official/" })
+	require.Equal(t, []string{"alice"}, prefixes[i].Owners)
+	require.Equal(t, "config", prefixes[i].Source)
+
+	// An owner has nothing left to request.
+	resp = api.Post("/v0/reserved-prefix-requests", "X-Subject: alice", claim)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body.String())
+}
@@ -0,0 +1,152 @@
+// Package runtimegrants owns `/v0/runtime-grants`, where registry admins
+// restrict who may deploy to a Runtime (e.g. only ops may deploy to
+// prod-k8s). Enforcement on deploy, undeploy and delete lives in
+// internal/registry/runtimeaccess; this package only records grants.
+package runtimegrants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Store is the Runtime grant capability the endpoints need.
+// *v1alpha1store.RuntimeGrantStore satisfies it.
+type Store interface {
+	List(ctx context.Context, namespace string) ([]*v1alpha1store.RuntimeGrant, error)
+	Put(ctx context.Context, namespace, runtime string, deployers []string) (*v1alpha1store.RuntimeGrant, error)
+	Delete(ctx context.Context, namespace, runtime string) error
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// IsAdmin lets registry admins manage grants. nil grants nobody
+	// admin.
+	IsAdmin func(ctx context.Context) bool
+}
+
+type listInput struct {
+	Namespace string `query:"namespace" doc:"Only list grants of Runtimes in this namespace"`
+}
+
+type grantInput struct {
+	Body arv0.RuntimeGrantInput
+}
+
+type deleteInput struct {
+	Namespace string `query:"namespace" doc:"Namespace of the Runtime; defaults to the default namespace"`
+	Runtime   string `query:"runtime" required:"true" doc:"Runtime whose grant to remove, e.g. prod-k8s"`
+}
+
+type grantOutput struct {
+	Body arv0.RuntimeGrant
+}
+
+type listOutput struct {
+	Body arv0.RuntimeGrantList
+}
+
+// Register wires the /v0/runtime-grants endpoints.
+func Register(api huma.API, cfg Config) {
+	requireAdmin := func(ctx context.Context) error {
+		if cfg.IsAdmin == nil || !cfg.IsAdmin(ctx) {
+			return huma.Error403Forbidden("only registry admins may manage runtime grants")
+		}
+		return nil
+	}
+	base := cfg.BasePrefix + "/runtime-grants"
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-runtime-grants",
+		Method:      http.MethodGet,
+		Path:        base,
+		Summary:     "List the Runtimes restricted to granted deployers",
+		Tags:        []string{"runtime-grants"},
+	}, func(ctx context.Context, in *listInput) (*listOutput, error) {
+		grants, err := cfg.Store.List(ctx, in.Namespace)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list runtime grants", err)
+		}
+		out := &listOutput{Body: arv0.RuntimeGrantList{Grants: make([]arv0.RuntimeGrant, 0, len(grants))}}
+		for _, g := range grants {
+			out.Body.Grants = append(out.Body.Grants, toWire(g))
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-runtime-grant",
+		Method:      http.MethodPut,
+		Path:        base,
+		Summary:     "Restrict deploys to a Runtime, or replace its deployers",
+		Tags:        []string{"runtime-grants"},
+	}, func(ctx context.Context, in *grantInput) (*grantOutput, error) {
+		if err := requireAdmin(ctx); err != nil {
+			return nil, err
+		}
+		runtime := strings.TrimSpace(in.Body.Runtime)
+		if runtime == "" {
+			return nil, huma.Error400BadRequest("runtime must not be empty")
+		}
+		deployers := make([]string, 0, len(in.Body.Deployers))
+		for _, d := range in.Body.Deployers {
+			d = strings.TrimSpace(d)
+			if d == "" {
+				return nil, huma.Error400BadRequest("deployers must not be empty")
+			}
+			if !slices.Contains(deployers, d) {
+				deployers = append(deployers, d)
+			}
+		}
+		grant, err := cfg.Store.Put(ctx, namespaceOrDefault(in.Body.Namespace), runtime, deployers)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("grant runtime", err)
+		}
+		return &grantOutput{Body: toWire(grant)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-runtime-grant",
+		Method:        http.MethodDelete,
+		Path:          base,
+		Summary:       "Lift a Runtime's deploy restriction",
+		Tags:          []string{"runtime-grants"},
+		DefaultStatus: http.StatusNoContent,
+	}, func(ctx context.Context, in *deleteInput) (*struct{}, error) {
+		if err := requireAdmin(ctx); err != nil {
+			return nil, err
+		}
+		namespace := namespaceOrDefault(in.Namespace)
+		if err := cfg.Store.Delete(ctx, namespace, in.Runtime); err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("Runtime %s/%s has no grant", namespace, in.Runtime))
+			}
+			return nil, huma.Error500InternalServerError("delete runtime grant", err)
+		}
+		return nil, nil
+	})
+}
+
+func namespaceOrDefault(namespace string) string {
+	return v1alpha1.ObjectMeta{Namespace: strings.TrimSpace(namespace)}.NamespaceOrDefault()
+}
+
+func toWire(g *v1alpha1store.RuntimeGrant) arv0.RuntimeGrant {
+	deployers := g.Deployers
+	if deployers == nil {
+		deployers = []string{}
+	}
+	return arv0.RuntimeGrant{Namespace: g.Namespace, Runtime: g.Runtime, Deployers: deployers, UpdatedAt: g.UpdatedAt}
+}
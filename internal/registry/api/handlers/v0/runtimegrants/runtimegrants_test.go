@@ -0,0 +1,101 @@
+package runtimegrants_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/internal/testapi"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/runtimegrants"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore map[string]*v1alpha1store.RuntimeGrant
+
+func (f fakeStore) List(_ context.Context, namespace string) ([]*v1alpha1store.RuntimeGrant, error) {
+	var out []*v1alpha1store.RuntimeGrant
+	for _, g := range f {
+		if namespace == "" || g.Namespace == namespace {
+			out = append(out, g)
+		}
+	}
+	slices.SortFunc(out, func(a, b *v1alpha1store.RuntimeGrant) int {
+		return strings.Compare(a.Namespace+"/"+a.Runtime, b.Namespace+"/"+b.Runtime)
+	})
+	return out, nil
+}
+
+func (f fakeStore) Put(_ context.Context, namespace, runtime string, deployers []string) (*v1alpha1store.RuntimeGrant, error) {
+	f[namespace+"/"+runtime] = &v1alpha1store.RuntimeGrant{Namespace: namespace, Runtime: runtime, Deployers: deployers}
+	return f[namespace+"/"+runtime], nil
+}
+
+func (f fakeStore) Delete(_ context.Context, namespace, runtime string) error {
+	if _, ok := f[namespace+"/"+runtime]; !ok {
+		return pkgdb.ErrNotFound
+	}
+	delete(f, namespace+"/"+runtime)
+	return nil
+}
+
+// newAPI registers the endpoints on a testapi.New API. The subject "admin"
+// is a registry admin.
+func newAPI(t *testing.T, store fakeStore) humatest.TestAPI {
+	api := testapi.New(t)
+	runtimegrants.Register(api, runtimegrants.Config{
+		BasePrefix: "/v0",
+		Store:      store,
+		IsAdmin:    testapi.IsAdmin,
+	})
+	return api
+}
+
+func listGrants(t *testing.T, api humatest.TestAPI, query string) []arv0.RuntimeGrant {
+	t.Helper()
+	resp := api.Get("/v0/runtime-grants" + query)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var list arv0.RuntimeGrantList
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	return list.Grants
+}
+
+func TestManageRuntimeGrants(t *testing.T) {
+	api := newAPI(t, fakeStore{})
+	require.Empty(t, listGrants(t, api, ""))
+
+	body := map[string]any{"runtime": "prod-k8s", "deployers": []string{"ops", " ops "}}
+	resp := api.Put("/v0/runtime-grants", "X-Subject: dev", body)
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = api.Put("/v0/runtime-grants", "X-Subject: admin", body)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var put arv0.RuntimeGrant
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &put))
+	require.Equal(t, "default", put.Namespace)
+	require.Equal(t, []string{"ops"}, put.Deployers)
+
+	resp = api.Put("/v0/runtime-grants", "X-Subject: admin", map[string]any{"namespace": "team-a", "runtime": "prod-k8s", "deployers": []string{}})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	resp = api.Put("/v0/runtime-grants", "X-Subject: admin", map[string]any{"runtime": "prod-k8s", "deployers": []string{""}})
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	require.Len(t, listGrants(t, api, ""), 2)
+	teamA := listGrants(t, api, "?namespace=team-a")
+	require.Len(t, teamA, 1)
+	require.Equal(t, []string{}, teamA[0].Deployers)
+
+	resp = api.Delete("/v0/runtime-grants?runtime=prod-k8s", "X-Subject: dev")
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+	resp = api.Delete("/v0/runtime-grants?runtime=prod-k8s", "X-Subject: admin")
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	resp = api.Delete("/v0/runtime-grants?runtime=prod-k8s", "X-Subject: admin")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
@@ -0,0 +1,139 @@
+// Package runtimesecrets owns `PUT /v0/runtimes/{name}/secrets/{key}`,
+// which stores one credential in a Runtime's spec.config without it ever
+// appearing in a manifest. The value is sealed (encrypted) before it is
+// written, and reads of the Runtime show it as REDACTED.
+package runtimesecrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/secrets"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Store reads and writes Runtimes. *v1alpha1store.Store satisfies it.
+type Store interface {
+	GetLatest(ctx context.Context, namespace, name string) (*v1alpha1.RawObject, error)
+	Upsert(ctx context.Context, obj v1alpha1.Object, opts ...v1alpha1store.UpsertOpts) (v1alpha1store.UpsertResult, error)
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Sealer encrypts secret values. *secrets.Sealer satisfies it.
+type Sealer interface {
+	SealValue(ctx context.Context, value string) (string, error)
+}
+
+var _ Sealer = (*secrets.Sealer)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	Sealer     Sealer
+	// PostUpsert runs after the Runtime is written, as it does after an
+	// apply. nil means no hook.
+	PostUpsert func(ctx context.Context, obj v1alpha1.Object) error
+	// Authorize gates writes with verb "apply" on the Runtime. nil means
+	// no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+// keyPattern matches the config keys runtime adapters read.
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,253}$`)
+
+type setSecretInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Key       string `path:"key" doc:"spec.config key to store the value under."`
+	Body      arv0.RuntimeSecretInput
+}
+
+type setSecretOutput struct {
+	Body arv0.RuntimeSecret
+}
+
+// Register wires PUT {basePrefix}/runtimes/{name}/secrets/{key}
+// ?namespace=default.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "set-runtime-secret",
+		Method:      http.MethodPut,
+		Path:        cfg.BasePrefix + "/runtimes/{name}/secrets/{key}",
+		Summary:     "Store a credential in a Runtime's config",
+		Description: "Encrypts the value and stores it as spec.config.{key} of the Runtime, replacing any value there. Reads of the Runtime show it as `REDACTED`. Answers 501 when the registry has no secrets master key.",
+	}, func(ctx context.Context, in *setSecretInput) (*setSecretOutput, error) {
+		ns := in.Namespace
+		if ns == "" {
+			ns = v1alpha1.DefaultNamespace
+		}
+		name, err := resource.UnescapeName(in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !keyPattern.MatchString(in.Key) {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("key %q must be 1-253 letters, digits, '_', '.' or '-'", in.Key))
+		}
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, resource.AuthorizeInput{
+				Verb: "apply", Kind: v1alpha1.KindRuntime,
+				Namespace: ns, Name: name,
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		raw, err := cfg.Store.GetLatest(ctx, ns, name)
+		if err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("Runtime %q/%q not found", ns, name))
+			}
+			return nil, huma.Error500InternalServerError("fetch Runtime", err)
+		}
+		runtime, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Runtime { return &v1alpha1.Runtime{} }, raw, v1alpha1.KindRuntime)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("decode Runtime", err)
+		}
+		sealed, err := cfg.Sealer.SealValue(ctx, in.Body.Value)
+		if err != nil {
+			if errors.Is(err, secrets.ErrNotConfigured) {
+				return nil, huma.Error501NotImplemented(err.Error())
+			}
+			return nil, huma.Error500InternalServerError("encrypt secret", err)
+		}
+		if runtime.Spec.Config == nil {
+			runtime.Spec.Config = map[string]any{}
+		}
+		runtime.Spec.Config[in.Key] = sealed
+
+		up, err := cfg.Store.Upsert(ctx, runtime)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("upsert Runtime", err)
+		}
+		if cfg.PostUpsert != nil {
+			meta := runtime.GetMetadata()
+			meta.Generation = up.Generation
+			meta.UID = up.UID
+			runtime.SetMetadata(*meta)
+			if err := cfg.PostUpsert(ctx, runtime); err != nil {
+				return nil, huma.Error500InternalServerError("Runtime post-upsert", err)
+			}
+		}
+		return &setSecretOutput{Body: arv0.RuntimeSecret{
+			Namespace:  ns,
+			Name:       name,
+			Key:        in.Key,
+			Generation: up.Generation,
+		}}, nil
+	})
+}
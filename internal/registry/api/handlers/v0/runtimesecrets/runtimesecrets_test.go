@@ -0,0 +1,143 @@
+package runtimesecrets_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/runtimesecrets"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/secrets"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore struct {
+	runtimes map[string]*v1alpha1.Runtime
+	upserts  int
+}
+
+func (f *fakeStore) GetLatest(_ context.Context, namespace, name string) (*v1alpha1.RawObject, error) {
+	rt, ok := f.runtimes[namespace+"/"+name]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	spec, err := json.Marshal(rt.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.RawObject{Metadata: rt.Metadata, Spec: spec}, nil
+}
+
+func (f *fakeStore) Upsert(_ context.Context, obj v1alpha1.Object, _ ...v1alpha1store.UpsertOpts) (v1alpha1store.UpsertResult, error) {
+	rt := obj.(*v1alpha1.Runtime)
+	f.upserts++
+	rt.Metadata.Generation++
+	f.runtimes[rt.Metadata.NamespaceOrDefault()+"/"+rt.Metadata.Name] = rt
+	return v1alpha1store.UpsertResult{Generation: rt.Metadata.Generation}, nil
+}
+
+func newSealer(t *testing.T) *secrets.Sealer {
+	t.Helper()
+	cipher, err := secrets.NewAESGCM([]byte(strings.Repeat("k", secrets.MasterKeySize)))
+	require.NoError(t, err)
+	return secrets.NewSealer(cipher)
+}
+
+func newAPI(t *testing.T, store *fakeStore, sealer *secrets.Sealer, applied *[]*v1alpha1.Runtime) humatest.TestAPI {
+	t.Helper()
+	_, api := humatest.New(t)
+	runtimesecrets.Register(api, runtimesecrets.Config{
+		BasePrefix: "/v0",
+		Store:      store,
+		Sealer:     sealer,
+		PostUpsert: func(_ context.Context, obj v1alpha1.Object) error {
+			*applied = append(*applied, obj.(*v1alpha1.Runtime))
+			return nil
+		},
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			if in.Namespace == "team-a" {
+				return huma.Error403Forbidden("denied")
+			}
+			return nil
+		},
+	})
+	return api
+}
+
+func storeWith(rt *v1alpha1.Runtime) *fakeStore {
+	return &fakeStore{runtimes: map[string]*v1alpha1.Runtime{
+		rt.Metadata.NamespaceOrDefault() + "/" + rt.Metadata.Name: rt,
+	}}
+}
+
+func TestSetSecret_SealsValueAndRunsPostUpsert(t *testing.T) {
+	store := storeWith(&v1alpha1.Runtime{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "prod", Generation: 3},
+		Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes, Config: map[string]any{"namespace": "agents"}},
+	})
+	sealer := newSealer(t)
+	var applied []*v1alpha1.Runtime
+	api := newAPI(t, store, sealer, &applied)
+
+	resp := api.Put("/v0/runtimes/prod/secrets/kubeconfig", map[string]any{"value": "apiVersion: v1"})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var out arv0.RuntimeSecret
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &out))
+	require.Equal(t, arv0.RuntimeSecret{Namespace: "default", Name: "prod", Key: "kubeconfig", Generation: 4}, out)
+
+	stored := store.runtimes["default/prod"].Spec.Config
+	require.Equal(t, "agents", stored["namespace"])
+	require.True(t, secrets.IsSealed(stored["kubeconfig"]))
+	require.NotContains(t, stored["kubeconfig"], "apiVersion")
+	plaintext, err := sealer.OpenValue(context.Background(), stored["kubeconfig"].(string))
+	require.NoError(t, err)
+	require.Equal(t, "apiVersion: v1", plaintext)
+	require.Len(t, applied, 1)
+}
+
+func TestSetSecret_Errors(t *testing.T) {
+	runtime := func() *v1alpha1.Runtime {
+		return &v1alpha1.Runtime{
+			Metadata: v1alpha1.ObjectMeta{Namespace: "team-a", Name: "prod"},
+			Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes},
+		}
+	}
+	cases := []struct {
+		name   string
+		sealer *secrets.Sealer
+		path   string
+		body   map[string]any
+		want   int
+	}{
+		{"missing runtime", newSealer(t), "/v0/runtimes/missing/secrets/kubeconfig", map[string]any{"value": "x"}, http.StatusNotFound},
+		{"forbidden", newSealer(t), "/v0/runtimes/prod/secrets/kubeconfig?namespace=team-a", map[string]any{"value": "x"}, http.StatusForbidden},
+		{"bad key", newSealer(t), "/v0/runtimes/prod/secrets/a%20b", map[string]any{"value": "x"}, http.StatusBadRequest},
+		{"empty value", newSealer(t), "/v0/runtimes/prod/secrets/kubeconfig", map[string]any{"value": ""}, http.StatusUnprocessableEntity},
+		{"no master key", secrets.NewSealer(nil), "/v0/runtimes/prod/secrets/kubeconfig", map[string]any{"value": "x"}, http.StatusNotImplemented},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := runtime()
+			if tc.name != "forbidden" {
+				rt.Metadata.Namespace = "default"
+			}
+			store := storeWith(rt)
+			var applied []*v1alpha1.Runtime
+			api := newAPI(t, store, tc.sealer, &applied)
+
+			resp := api.Put(tc.path, tc.body)
+			require.Equal(t, tc.want, resp.Code, resp.Body.String())
+			require.Zero(t, store.upserts)
+			require.Empty(t, applied)
+		})
+	}
+}
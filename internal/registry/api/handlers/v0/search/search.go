@@ -0,0 +1,469 @@
+// Package search owns the unified artifact search endpoint:
+// `GET /v0/search?q=...&types=server,agent,skill,prompt`. It blends
+// rankings with reciprocal rank fusion (RRF): Postgres full-text rank over
+// each artifact's name, title, description and README, a name-match
+// ranking that keeps partial words like "weath" findable, when
+// Config.Tools is set a ranking of MCP servers by the names and
+// descriptions of their tools, and, when Config.Semantic is set, a
+// semantic ranking. Results carry their type, a highlighted excerpt of the
+// text that matched and the server tools that matched. `mode=semantic`
+// ranks by meaning alone.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/embeddings"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// rrfK damps the weight of top ranks in reciprocal rank fusion: a result
+// at rank r in a ranking scores 1/(rrfK+r). 60 is the constant from the
+// original RRF paper and works well without tuning.
+const rrfK = 60
+
+// maxLimit caps the number of results a client can request.
+const maxLimit = 100
+
+// Types maps the `types` query values to the kinds they search, in the
+// order results of equal score are listed.
+var Types = map[string]string{
+	"server": v1alpha1.KindMCPServer,
+	"agent":  v1alpha1.KindAgent,
+	"skill":  v1alpha1.KindSkill,
+	"prompt": v1alpha1.KindPrompt,
+}
+
+// typeOrder is the default `types` value.
+var typeOrder = []string{"server", "agent", "skill", "prompt"}
+
+// Store is the narrow surface this handler needs from each kind's store.
+// *v1alpha1store.Store satisfies it; tests supply a fake.
+type Store interface {
+	Search(ctx context.Context, opts v1alpha1store.SearchOpts) ([]v1alpha1store.SearchHit, error)
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// ToolSearcher matches MCP server tools by name and description.
+// *v1alpha1store.ServerToolStore satisfies it.
+type ToolSearcher interface {
+	SearchTools(ctx context.Context, opts v1alpha1store.SearchOpts) ([]v1alpha1store.ToolHit, error)
+}
+
+var _ ToolSearcher = (*v1alpha1store.ServerToolStore)(nil)
+
+// Search modes accepted by the `mode` query parameter.
+const (
+	modeHybrid   = "hybrid"
+	modeSemantic = "semantic"
+)
+
+// toolHitsPerResult is how many tool hits are read per requested result,
+// as one server's tools can fill the top of the tool ranking.
+const toolHitsPerResult = 5
+
+// SearchHitRecorder counts searches that returned an artifact.
+// *usagestats.Recorder satisfies it.
+type SearchHitRecorder interface {
+	RecordSearchHit(kind, namespace, name string)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	// Stores are the stores of the searchable kinds, keyed by kind. Kinds
+	// without a store are not searched.
+	Stores map[string]Store
+	// ListFilters scope each kind's results the same way its list endpoint
+	// is scoped. A nil entry means no filter.
+	ListFilters map[string]func(ctx context.Context, in resource.AuthorizeInput) (string, []any, error)
+	// Tools, when set, ranks MCP servers by their best matching tool.
+	Tools ToolSearcher
+	// Semantic, when set, contributes a semantic ranking. Hits it returns
+	// that the caller may not list are dropped; when it fails, results are
+	// ranked without it, except in semantic mode, which answers 503 when
+	// the embeddings provider is unavailable.
+	Semantic embeddings.Ranker
+	// Usage, when set, counts one search hit per result returned.
+	Usage SearchHitRecorder
+}
+
+type searchInput struct {
+	Q                 string   `query:"q" required:"true" minLength:"1" maxLength:"256" doc:"Free text. Bare words must all match; \"quoted phrases\" match in order; 'or' and '-word' work as on web search engines."`
+	Types             []string `query:"types" doc:"Comma-separated artifact types to search: server, agent, skill, prompt. Defaults to all."`
+	Namespace         string   `query:"namespace" doc:"Only search this namespace. Empty searches every namespace."`
+	Limit             int      `query:"limit" minimum:"0" maximum:"100" doc:"Max results (default 20)."`
+	IncludeUnverified bool     `query:"includeUnverified" doc:"Also return MCP servers whose remote URL did not answer the registry's liveness probe."`
+	Mode              string   `query:"mode" enum:"hybrid,semantic" default:"hybrid" doc:"hybrid blends text, name, tool and, when enabled, semantic rankings. semantic ranks by meaning alone and answers 503 while the embeddings provider is unavailable."`
+}
+
+type searchOutput struct {
+	Body arv0.SearchResults
+}
+
+// Register wires GET {basePrefix}/search.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "search-artifacts",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/search",
+		Summary:     "Search MCP servers, agents, skills and prompts",
+		Description: "Matches the latest tag of each artifact by full text over its name, title, description and README, by name substring, and MCP servers by the names and descriptions of their tools, and merges the rankings with reciprocal rank fusion. With `mode=semantic` it ranks by meaning alone, answering 503 while the embeddings provider is unavailable.",
+	}, func(ctx context.Context, in *searchInput) (*searchOutput, error) {
+		types, err := parseTypes(in.Types)
+		if err != nil {
+			return nil, err
+		}
+		limit := in.Limit
+		if limit <= 0 {
+			limit = 20
+		}
+		if limit > maxLimit {
+			limit = maxLimit
+		}
+		semanticOnly := in.Mode == modeSemantic
+		if semanticOnly && cfg.Semantic == nil {
+			return nil, huma.Error501NotImplemented("semantic search is not enabled on this registry")
+		}
+
+		cands := map[embeddings.Ref]*candidate{}
+		var lexical, names, tools, semantic []embeddings.Ref
+		kinds := make([]string, 0, len(types))
+		for _, typ := range types {
+			kind := Types[typ]
+			store := cfg.Stores[kind]
+			if store == nil {
+				continue
+			}
+			kinds = append(kinds, kind)
+			if semanticOnly {
+				continue
+			}
+			where, args, err := listFilter(ctx, cfg, kind, in.Namespace, in.IncludeUnverified)
+			if err != nil {
+				return nil, err
+			}
+			// Every ranking draws from the top limit of each kind, so a
+			// page of results never needs more than that.
+			hits, err := store.Search(ctx, v1alpha1store.SearchOpts{
+				Query: in.Q, Namespace: in.Namespace, Limit: limit,
+				ExtraWhere: where, ExtraArgs: args,
+			})
+			if err != nil {
+				return nil, huma.Error500InternalServerError("search "+kind, err)
+			}
+			for _, hit := range hits {
+				ref := embeddings.Ref{Kind: kind, Namespace: hit.Object.Metadata.Namespace, Name: hit.Object.Metadata.Name}
+				cands[ref] = &candidate{typ: typ, hit: hit}
+			}
+		}
+		for ref, c := range cands {
+			if c.hit.Rank > 0 {
+				lexical = append(lexical, ref)
+			}
+			if c.hit.NameMatch {
+				names = append(names, ref)
+			}
+		}
+		sortRefs(lexical, func(a, b embeddings.Ref) bool { return cands[a].hit.Rank > cands[b].hit.Rank })
+		sortRefs(names, func(a, b embeddings.Ref) bool { return nameCloser(in.Q, a.Name, b.Name) })
+
+		toolNames := map[embeddings.Ref][]string{}
+		if cfg.Tools != nil && !semanticOnly && slices.Contains(kinds, v1alpha1.KindMCPServer) {
+			where, args, err := listFilter(ctx, cfg, v1alpha1.KindMCPServer, in.Namespace, in.IncludeUnverified)
+			if err != nil {
+				return nil, err
+			}
+			hits, err := cfg.Tools.SearchTools(ctx, v1alpha1store.SearchOpts{
+				Query: in.Q, Namespace: in.Namespace, Limit: limit * toolHitsPerResult,
+				ExtraWhere: where, ExtraArgs: args,
+			})
+			if err != nil {
+				return nil, huma.Error500InternalServerError("search tools", err)
+			}
+			// Hits arrive best first, so a server ranks by its best tool.
+			var refs []embeddings.Ref
+			for _, hit := range hits {
+				ref := embeddings.Ref{Kind: v1alpha1.KindMCPServer, Namespace: hit.Namespace, Name: hit.Name}
+				if _, ok := toolNames[ref]; !ok {
+					refs = append(refs, ref)
+				}
+				toolNames[ref] = append(toolNames[ref], hit.Tool)
+			}
+			if err := fetchMissing(ctx, cfg, in.Namespace, in.IncludeUnverified, refs, cands); err != nil {
+				return nil, err
+			}
+			for _, ref := range refs {
+				if _, ok := cands[ref]; ok {
+					tools = append(tools, ref)
+				}
+			}
+		}
+
+		if cfg.Semantic != nil && len(kinds) > 0 {
+			// An unreachable embeddings provider costs the semantic
+			// ranking, not the search, unless it is the only ranking.
+			ranked, err := cfg.Semantic.Rank(ctx, in.Q, kinds, limit)
+			switch {
+			case err == nil:
+			case semanticOnly:
+				return nil, semanticError(err)
+			case errors.Is(err, embeddings.ErrCircuitOpen):
+				// The breaker logged when it opened.
+			default:
+				slog.Warn("semantic search failed; ranking by text only", "error", err)
+			}
+			refs := slices.DeleteFunc(ranked, func(ref embeddings.Ref) bool { return !slices.Contains(kinds, ref.Kind) })
+			if err := fetchMissing(ctx, cfg, in.Namespace, in.IncludeUnverified, refs, cands); err != nil {
+				return nil, err
+			}
+			for _, ref := range refs {
+				if _, ok := cands[ref]; ok {
+					semantic = append(semantic, ref)
+				}
+			}
+		}
+
+		scores := fuse(lexical, names, tools, semantic)
+		ranked := make([]embeddings.Ref, 0, len(scores))
+		for ref := range scores {
+			ranked = append(ranked, ref)
+		}
+		sortRefs(ranked, func(a, b embeddings.Ref) bool { return scores[a] > scores[b] })
+		if len(ranked) > limit {
+			ranked = ranked[:limit]
+		}
+
+		out := &searchOutput{Body: arv0.SearchResults{Query: in.Q, Results: make([]arv0.SearchResult, 0, len(ranked))}}
+		for _, ref := range ranked {
+			c := cands[ref]
+			meta := c.hit.Object.Metadata
+			title, description := describe(c.hit.Object)
+			out.Body.Results = append(out.Body.Results, arv0.SearchResult{
+				Type:        c.typ,
+				Kind:        ref.Kind,
+				Namespace:   meta.Namespace,
+				Name:        meta.Name,
+				Tag:         meta.Tag,
+				Title:       title,
+				Description: description,
+				Highlight:   c.hit.Highlight,
+				ReadmeMatch: c.hit.ReadmeMatch,
+				Tools:       toolNames[ref],
+				Score:       scores[ref],
+			})
+			if cfg.Usage != nil {
+				cfg.Usage.RecordSearchHit(ref.Kind, meta.Namespace, meta.Name)
+			}
+		}
+		return out, nil
+	})
+}
+
+// semanticError answers a semantic-mode search whose ranking failed: 503
+// when the embeddings provider is unavailable, with Retry-After while its
+// circuit breaker is open.
+func semanticError(err error) error {
+	var unavailable *embeddings.UnavailableError
+	if !errors.As(err, &unavailable) {
+		return huma.Error500InternalServerError("semantic search", err)
+	}
+	resp := huma.Error503ServiceUnavailable("semantic search is unavailable: " + unavailable.Error())
+	if !errors.Is(unavailable, embeddings.ErrCircuitOpen) {
+		return resp
+	}
+	wait := max(1, int(math.Ceil(unavailable.RetryAfter.Seconds())))
+	return huma.ErrorWithHeaders(resp, http.Header{"Retry-After": []string{strconv.Itoa(wait)}})
+}
+
+// candidate is one artifact some ranking returned.
+type candidate struct {
+	typ string
+	hit v1alpha1store.SearchHit
+}
+
+func parseTypes(values []string) ([]string, error) {
+	if len(values) == 0 {
+		return typeOrder, nil
+	}
+	var out []string
+	for _, v := range values {
+		for _, typ := range strings.Split(v, ",") {
+			typ = strings.ToLower(strings.TrimSpace(typ))
+			if typ == "" {
+				continue
+			}
+			if _, ok := Types[typ]; !ok {
+				return nil, huma.Error400BadRequest(fmt.Sprintf("unknown type %q (want server, agent, skill or prompt)", typ))
+			}
+			if !slices.Contains(out, typ) {
+				out = append(out, typ)
+			}
+		}
+	}
+	if len(out) == 0 {
+		return typeOrder, nil
+	}
+	return out, nil
+}
+
+// listFilter returns the kind's list filter, narrowed for MCP servers to
+// those whose remote passed its liveness probe unless includeUnverified.
+func listFilter(ctx context.Context, cfg Config, kind, namespace string, includeUnverified bool) (string, []any, error) {
+	var (
+		where string
+		args  []any
+	)
+	if filter := cfg.ListFilters[kind]; filter != nil {
+		var err error
+		where, args, err = filter(ctx, resource.AuthorizeInput{Verb: "list", Kind: kind, Namespace: namespace})
+		if err != nil {
+			return "", nil, huma.Error500InternalServerError("authz list filter", err)
+		}
+	}
+	if kind != v1alpha1.KindMCPServer || includeUnverified {
+		return where, args, nil
+	}
+	args = append(args, v1alpha1.RemoteUnverifiedAnnotation)
+	pred := fmt.Sprintf("NOT (annotations ? $%d)", len(args))
+	if where != "" {
+		pred = "(" + where + ") AND " + pred
+	}
+	return pred, args, nil
+}
+
+// fetchMissing loads the latest tag of tool and semantic hits no lexical
+// ranking returned, through each kind's list filter so nothing the caller
+// may not list is added.
+func fetchMissing(ctx context.Context, cfg Config, namespace string, includeUnverified bool, refs []embeddings.Ref, cands map[embeddings.Ref]*candidate) error {
+	missing := map[string][]embeddings.Ref{}
+	for _, ref := range refs {
+		if _, ok := cands[ref]; ok {
+			continue
+		}
+		if namespace != "" && ref.Namespace != namespace {
+			continue
+		}
+		missing[ref.Kind] = append(missing[ref.Kind], ref)
+	}
+	for kind, refs := range missing {
+		where, args, err := listFilter(ctx, cfg, kind, namespace, includeUnverified)
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(refs))
+		for _, ref := range refs {
+			keys = append(keys, ref.Namespace+"/"+ref.Name)
+		}
+		args = append(args, keys)
+		pred := fmt.Sprintf("namespace || '/' || name = ANY($%d)", len(args))
+		if where != "" {
+			pred = "(" + where + ") AND " + pred
+		}
+		rows, _, err := cfg.Stores[kind].List(ctx, v1alpha1store.ListOpts{
+			LatestOnly: true, Limit: len(refs), ExtraWhere: pred, ExtraArgs: args,
+		})
+		if err != nil {
+			return huma.Error500InternalServerError("fetch search hits", err)
+		}
+		typ := typeOf(kind)
+		for _, row := range rows {
+			ref := embeddings.Ref{Kind: kind, Namespace: row.Metadata.Namespace, Name: row.Metadata.Name}
+			cands[ref] = &candidate{typ: typ, hit: v1alpha1store.SearchHit{Object: row}}
+		}
+	}
+	return nil
+}
+
+// fuse scores every ref that appears in any ranking by reciprocal rank
+// fusion: the sum over rankings of 1/(rrfK+rank), rank counting from 1.
+func fuse(rankings ...[]embeddings.Ref) map[embeddings.Ref]float64 {
+	scores := map[embeddings.Ref]float64{}
+	for _, ranking := range rankings {
+		for i, ref := range ranking {
+			scores[ref] += 1 / float64(rrfK+i+1)
+		}
+	}
+	return scores
+}
+
+// sortRefs orders refs by less, breaking ties by type order, namespace and
+// name so results are stable.
+func sortRefs(refs []embeddings.Ref, less func(a, b embeddings.Ref) bool) {
+	sort.SliceStable(refs, func(i, j int) bool {
+		a, b := refs[i], refs[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		if ai, bi := slices.Index(typeOrder, typeOf(a.Kind)), slices.Index(typeOrder, typeOf(b.Kind)); ai != bi {
+			return ai < bi
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+}
+
+// nameCloser reports whether name a matches query more closely than b: an
+// exact match beats a prefix match, which beats a match elsewhere in the
+// name; among equals the shorter name wins.
+func nameCloser(query, a, b string) bool {
+	closeness := func(name string) int {
+		name, q := strings.ToLower(name), strings.ToLower(query)
+		switch {
+		case name == q:
+			return 0
+		case strings.HasPrefix(name, q):
+			return 1
+		default:
+			return 2
+		}
+	}
+	if ca, cb := closeness(a), closeness(b); ca != cb {
+		return ca < cb
+	}
+	return len(a) < len(b)
+}
+
+func typeOf(kind string) string {
+	for typ, k := range Types {
+		if k == kind {
+			return typ
+		}
+	}
+	return ""
+}
+
+// describe returns the title and description every searchable kind's spec
+// carries.
+func describe(row *v1alpha1.RawObject) (title, description string) {
+	var spec struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	if len(row.Spec) > 0 {
+		_ = json.Unmarshal(row.Spec, &spec)
+	}
+	return spec.Title, spec.Description
+}
@@ -0,0 +1,283 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/search"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/embeddings"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// fakeStore returns canned hits in order and serves List from rows.
+type fakeStore struct {
+	hits  []v1alpha1store.SearchHit
+	rows  []*v1alpha1.RawObject
+	where []string
+}
+
+func (f *fakeStore) Search(_ context.Context, opts v1alpha1store.SearchOpts) ([]v1alpha1store.SearchHit, error) {
+	f.where = append(f.where, opts.ExtraWhere)
+	return f.hits, nil
+}
+
+func (f *fakeStore) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	f.where = append(f.where, opts.ExtraWhere)
+	keys := opts.ExtraArgs[len(opts.ExtraArgs)-1].([]string)
+	var out []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		for _, key := range keys {
+			if key == row.Metadata.Namespace+"/"+row.Metadata.Name {
+				out = append(out, row)
+			}
+		}
+	}
+	return out, "", nil
+}
+
+func row(name, description string) *v1alpha1.RawObject {
+	spec, _ := json.Marshal(map[string]string{"description": description})
+	return &v1alpha1.RawObject{Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: name, Tag: "latest"}, Spec: spec}
+}
+
+type semantic []embeddings.Ref
+
+func (s semantic) Rank(context.Context, string, []string, int) ([]embeddings.Ref, error) {
+	return s, nil
+}
+
+type failingSemantic struct{}
+
+func (failingSemantic) Rank(context.Context, string, []string, int) ([]embeddings.Ref, error) {
+	return nil, errors.New("connection refused")
+}
+
+// unavailableSemantic fails like a Semantic whose provider is
+// unavailable.
+type unavailableSemantic struct{ err *embeddings.UnavailableError }
+
+func (s unavailableSemantic) Rank(context.Context, string, []string, int) ([]embeddings.Ref, error) {
+	return nil, fmt.Errorf("embed query: %w", s.err)
+}
+
+type searchHits []string
+
+func (s *searchHits) RecordSearchHit(kind, namespace, name string) {
+	*s = append(*s, kind+"/"+namespace+"/"+name)
+}
+
+func get(t *testing.T, api humatest.TestAPI, path string) arv0.SearchResults {
+	t.Helper()
+	resp := api.Get(path)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var out arv0.SearchResults
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &out))
+	return out
+}
+
+func names(results []arv0.SearchResult) []string {
+	out := make([]string, 0, len(results))
+	for _, r := range results {
+		out = append(out, r.Type+":"+r.Name)
+	}
+	return out
+}
+
+func TestSearch_FusesRankings(t *testing.T) {
+	servers := &fakeStore{hits: []v1alpha1store.SearchHit{
+		{Object: row("forecasts", "Weather forecasts."), Rank: 0.6, Highlight: "<mark>Weather</mark> forecasts."},
+		{Object: row("weather", "Current conditions."), Rank: 0.2, NameMatch: true, ReadmeMatch: true},
+	}}
+	agents := &fakeStore{
+		hits: []v1alpha1store.SearchHit{{Object: row("weatherman", "Chats about the sky."), NameMatch: true}},
+		rows: []*v1alpha1.RawObject{row("planner", "Plans trips around rain.")},
+	}
+	skills := &fakeStore{}
+	var hits searchHits
+	_, api := humatest.New(t)
+	search.Register(api, search.Config{
+		BasePrefix: "/v0",
+		Stores: map[string]search.Store{
+			v1alpha1.KindMCPServer: servers,
+			v1alpha1.KindAgent:     agents,
+			v1alpha1.KindSkill:     skills,
+		},
+		ListFilters: map[string]func(context.Context, resource.AuthorizeInput) (string, []any, error){
+			v1alpha1.KindAgent: func(context.Context, resource.AuthorizeInput) (string, []any, error) {
+				return "namespace = $1", []any{"default"}, nil
+			},
+		},
+		Semantic: semantic{
+			{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "forecasts"},
+			{Kind: v1alpha1.KindAgent, Namespace: "default", Name: "planner"},
+			{Kind: v1alpha1.KindAgent, Namespace: "default", Name: "hidden"},
+		},
+		Usage: &hits,
+	})
+
+	got := get(t, api, "/v0/search?q=weather")
+	// forecasts leads full text and the semantic ranking; weather leads the
+	// name ranking. planner, found only semantically, ties weatherman.
+	require.Equal(t, []string{"server:forecasts", "server:weather", "agent:planner", "agent:weatherman"}, names(got.Results))
+	require.Equal(t, v1alpha1.KindMCPServer, got.Results[0].Kind)
+	require.Equal(t, "Weather forecasts.", got.Results[0].Description)
+	require.Equal(t, "<mark>Weather</mark> forecasts.", got.Results[0].Highlight)
+	require.Greater(t, got.Results[0].Score, got.Results[1].Score)
+	require.False(t, got.Results[0].ReadmeMatch)
+	require.True(t, got.Results[1].ReadmeMatch)
+	require.Equal(t, []string{"namespace = $1", "(namespace = $1) AND namespace || '/' || name = ANY($2)"}, agents.where)
+	require.Len(t, hits, 4)
+
+	got = get(t, api, "/v0/search?q=weather&types=agent&limit=1")
+	require.Equal(t, []string{"agent:planner"}, names(got.Results))
+
+	resp := api.Get("/v0/search?q=weather&types=server,plugin")
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	require.True(t, strings.Contains(resp.Body.String(), "plugin"))
+
+	resp = api.Get("/v0/search")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+}
+
+func TestSearch_SemanticFailureFallsBackToText(t *testing.T) {
+	servers := &fakeStore{hits: []v1alpha1store.SearchHit{{Object: row("weather", "Forecasts."), Rank: 0.5}}}
+	_, api := humatest.New(t)
+	search.Register(api, search.Config{
+		BasePrefix: "/v0",
+		Stores:     map[string]search.Store{v1alpha1.KindMCPServer: servers},
+		Semantic:   failingSemantic{},
+	})
+
+	got := get(t, api, "/v0/search?q=weather")
+	require.Equal(t, []string{"server:weather"}, names(got.Results))
+}
+
+func TestSearch_SemanticModeRanksByMeaningAlone(t *testing.T) {
+	servers := &fakeStore{
+		hits: []v1alpha1store.SearchHit{{Object: row("weather", "Forecasts."), Rank: 0.5}},
+		rows: []*v1alpha1.RawObject{row("almanac", "Tomorrow's sky.")},
+	}
+	_, api := humatest.New(t)
+	search.Register(api, search.Config{
+		BasePrefix: "/v0",
+		Stores:     map[string]search.Store{v1alpha1.KindMCPServer: servers},
+		Semantic:   semantic{{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "almanac"}},
+	})
+
+	got := get(t, api, "/v0/search?q=weather&mode=semantic")
+	require.Equal(t, []string{"server:almanac"}, names(got.Results))
+	require.Len(t, servers.where, 1, "semantic mode skips the full text search")
+
+	resp := api.Get("/v0/search?q=weather&mode=fuzzy")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+}
+
+func TestSearch_SemanticModeAnswers503WhileProviderUnavailable(t *testing.T) {
+	servers := &fakeStore{hits: []v1alpha1store.SearchHit{{Object: row("weather", "Forecasts."), Rank: 0.5}}}
+	register := func(ranker embeddings.Ranker) humatest.TestAPI {
+		_, api := humatest.New(t)
+		search.Register(api, search.Config{
+			BasePrefix: "/v0",
+			Stores:     map[string]search.Store{v1alpha1.KindMCPServer: servers},
+			Semantic:   ranker,
+		})
+		return api
+	}
+
+	api := register(unavailableSemantic{&embeddings.UnavailableError{Err: embeddings.ErrCircuitOpen, RetryAfter: 2500 * time.Millisecond}})
+	resp := api.Get("/v0/search?q=weather&mode=semantic")
+	require.Equal(t, http.StatusServiceUnavailable, resp.Code, resp.Body.String())
+	require.Equal(t, "3", resp.Header().Get("Retry-After"))
+	// The default mode still ranks by text.
+	require.Equal(t, []string{"server:weather"}, names(get(t, api, "/v0/search?q=weather").Results))
+
+	api = register(unavailableSemantic{&embeddings.UnavailableError{Err: errors.New("connection refused")}})
+	resp = api.Get("/v0/search?q=weather&mode=semantic")
+	require.Equal(t, http.StatusServiceUnavailable, resp.Code, resp.Body.String())
+	require.Empty(t, resp.Header().Get("Retry-After"))
+
+	api = register(nil)
+	resp = api.Get("/v0/search?q=weather&mode=semantic")
+	require.Equal(t, http.StatusNotImplemented, resp.Code, resp.Body.String())
+}
+
+// toolSearcher returns canned tool hits in order.
+type toolSearcher struct {
+	hits  []v1alpha1store.ToolHit
+	where []string
+}
+
+func (f *toolSearcher) SearchTools(_ context.Context, opts v1alpha1store.SearchOpts) ([]v1alpha1store.ToolHit, error) {
+	f.where = append(f.where, opts.ExtraWhere)
+	return f.hits, nil
+}
+
+func TestSearch_MatchesServerTools(t *testing.T) {
+	servers := &fakeStore{
+		hits: []v1alpha1store.SearchHit{{Object: row("weather", "Forecasts."), Rank: 0.5}},
+		rows: []*v1alpha1.RawObject{row("github", "GitHub API.")},
+	}
+	agents := &fakeStore{hits: []v1alpha1store.SearchHit{{Object: row("triage", "Triages issues."), Rank: 0.4}}}
+	tools := &toolSearcher{hits: []v1alpha1store.ToolHit{
+		{Namespace: "default", Name: "github", Tag: "latest", Tool: "create_issue", Rank: 0.9},
+		{Namespace: "default", Name: "github", Tag: "latest", Tool: "list_issues", Rank: 0.7},
+		{Namespace: "default", Name: "hidden", Tag: "latest", Tool: "close_issue", Rank: 0.6},
+	}}
+	_, api := humatest.New(t)
+	search.Register(api, search.Config{
+		BasePrefix: "/v0",
+		Stores: map[string]search.Store{
+			v1alpha1.KindMCPServer: servers,
+			v1alpha1.KindAgent:     agents,
+		},
+		ListFilters: map[string]func(context.Context, resource.AuthorizeInput) (string, []any, error){
+			v1alpha1.KindMCPServer: func(context.Context, resource.AuthorizeInput) (string, []any, error) {
+				return "namespace = $1", []any{"default"}, nil
+			},
+		},
+		Tools: tools,
+	})
+
+	got := get(t, api, "/v0/search?q=issue")
+	// github, found only by its tools, ties weather; hidden is not
+	// listable and is dropped.
+	require.Equal(t, []string{"server:github", "server:weather", "agent:triage"}, names(got.Results))
+	require.Equal(t, []string{"create_issue", "list_issues"}, got.Results[0].Tools)
+	require.Equal(t, "GitHub API.", got.Results[0].Description)
+	require.Empty(t, got.Results[1].Tools)
+	require.Equal(t, []string{"(namespace = $1) AND NOT (annotations ? $2)"}, tools.where)
+
+	got = get(t, api, "/v0/search?q=issue&types=agent")
+	require.Equal(t, []string{"agent:triage"}, names(got.Results))
+	require.Len(t, tools.where, 1, "tools are only searched for servers")
+}
+
+func TestSearch_LeavesOutUnverifiedRemotes(t *testing.T) {
+	servers := &fakeStore{hits: []v1alpha1store.SearchHit{{Object: row("weather", "Forecasts."), Rank: 0.5}}}
+	agents := &fakeStore{}
+	_, api := humatest.New(t)
+	search.Register(api, search.Config{
+		BasePrefix: "/v0",
+		Stores: map[string]search.Store{
+			v1alpha1.KindMCPServer: servers,
+			v1alpha1.KindAgent:     agents,
+		},
+	})
+
+	get(t, api, "/v0/search?q=weather")
+	get(t, api, "/v0/search?q=weather&includeUnverified=true")
+	require.Equal(t, []string{"NOT (annotations ? $1)", ""}, servers.where)
+	require.Equal(t, []string{"", ""}, agents.where, "only MCP servers are probed")
+}
@@ -0,0 +1,469 @@
+// Package security owns vulnerability propagation for MCPServer and Skill
+// versions:
+//
+//   - GET    /v0/security/impact/{name}@{tag}           affected Agents and Deployments
+//   - PUT    /v0/security/vulnerabilities/{name}@{tag}  mark a version vulnerable
+//   - DELETE /v0/security/vulnerabilities/{name}@{tag}  clear the mark
+//
+// Scanners call PUT when they find a vulnerable version. The handler stamps
+// the advisory on the version, flags every Agent version that references it
+// and every active Deployment that runs it (directly or through one of
+// those Agents), and notifies the owning namespaces of newly flagged
+// objects. The impact is computed from the current references on every
+// call; the flags are a durable marker for other tooling, not the source of
+// truth.
+//
+// A reference without a tag follows the "latest" row, so it is affected
+// only when "latest" itself is marked.
+package security
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Store is the narrow surface this handler needs from each kind's store.
+// *v1alpha1store.Store satisfies it; tests supply a fake.
+type Store interface {
+	Get(ctx context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error)
+	FindReferrers(ctx context.Context, pathJSON json.RawMessage, opts v1alpha1store.FindReferrersOpts) ([]*v1alpha1.RawObject, error)
+	PatchAnnotations(ctx context.Context, namespace, name, tag string, mutate func(map[string]string) map[string]string) error
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Notifier delivers vulnerability notifications. VulnerabilityFlagged is
+// called once per namespace with newly flagged objects; affected lists them
+// as "Agent/name@tag" and "Deployment/name". Implementations must not
+// block the request.
+type Notifier interface {
+	VulnerabilityFlagged(ctx context.Context, artifact v1alpha1.ResourceRef, advisory, namespace string, affected []string)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	// Stores maps kind to store. The MCPServer, Skill, Agent and Deployment
+	// stores are used; missing kinds are skipped.
+	Stores map[string]Store
+	// Authorizers are the per-kind read gates. The impact report requires
+	// "get" on the artifact and omits Agents and Deployments the caller may
+	// not "get". nil entries allow.
+	Authorizers map[string]func(ctx context.Context, in resource.AuthorizeInput) error
+	// IsAdmin gates marking and clearing: both write annotations across
+	// every namespace. nil denies.
+	IsAdmin func(ctx context.Context) bool
+	// Notifier, when set, receives the namespaces newly flagged by a mark.
+	Notifier Notifier
+}
+
+type refInput struct {
+	Ref       string `path:"ref" doc:"Artifact version as name@tag; a bare name means the 'latest' tag."`
+	Kind      string `query:"kind" enum:"MCPServer,Skill" doc:"Artifact kind. Optional unless an MCPServer and a Skill share the name and tag."`
+	Namespace string `query:"namespace" doc:"Artifact namespace (defaults to 'default')."`
+}
+
+type markInput struct {
+	Ref       string `path:"ref" doc:"Artifact version as name@tag; a bare name means the 'latest' tag."`
+	Kind      string `query:"kind" enum:"MCPServer,Skill" doc:"Artifact kind. Optional unless an MCPServer and a Skill share the name and tag."`
+	Namespace string `query:"namespace" doc:"Artifact namespace (defaults to 'default')."`
+	Body      arv0.VulnerabilityMark
+}
+
+type impactOutput struct {
+	Body arv0.VulnerabilityImpact
+}
+
+// Register wires the impact report and the mark/clear endpoints.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-vulnerability-impact",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/security/impact/{ref}",
+		Summary:     "List the Agents and active Deployments that reach an MCPServer or Skill version",
+		Tags:        []string{"security"},
+	}, func(ctx context.Context, in *refInput) (*impactOutput, error) {
+		h := handler{cfg: cfg}
+		row, err := h.resolve(ctx, in.Ref, in.Kind, in.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if authz := cfg.Authorizers[row.Kind]; authz != nil {
+			if err := authz(ctx, resource.AuthorizeInput{
+				Verb: "get", Kind: row.Kind, Namespace: row.Metadata.Namespace, Name: row.Metadata.Name, Tag: row.Metadata.Tag,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		impact, err := h.impact(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		h.filter(ctx, &impact)
+		return &impactOutput{Body: impact}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "mark-vulnerable",
+		Method:      http.MethodPut,
+		Path:        cfg.BasePrefix + "/security/vulnerabilities/{ref}",
+		Summary:     "Mark an MCPServer or Skill version vulnerable and flag everything that reaches it",
+		Tags:        []string{"security"},
+	}, func(ctx context.Context, in *markInput) (*impactOutput, error) {
+		if cfg.IsAdmin == nil || !cfg.IsAdmin(ctx) {
+			return nil, huma.Error403Forbidden("registry admin permission required")
+		}
+		h := handler{cfg: cfg}
+		row, err := h.resolve(ctx, in.Ref, in.Kind, in.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		impact, err := h.mark(ctx, row, in.Body.Advisory)
+		if err != nil {
+			return nil, err
+		}
+		return &impactOutput{Body: impact}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "clear-vulnerable",
+		Method:      http.MethodDelete,
+		Path:        cfg.BasePrefix + "/security/vulnerabilities/{ref}",
+		Summary:     "Clear the vulnerability mark on an MCPServer or Skill version and its flags",
+		Tags:        []string{"security"},
+	}, func(ctx context.Context, in *refInput) (*impactOutput, error) {
+		if cfg.IsAdmin == nil || !cfg.IsAdmin(ctx) {
+			return nil, huma.Error403Forbidden("registry admin permission required")
+		}
+		h := handler{cfg: cfg}
+		row, err := h.resolve(ctx, in.Ref, in.Kind, in.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		impact, err := h.clear(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		return &impactOutput{Body: impact}, nil
+	})
+}
+
+type handler struct {
+	cfg Config
+}
+
+// resolve loads the artifact version named by the request. Without a kind
+// both MCPServer and Skill are tried; a name@tag that exists as both is
+// ambiguous.
+func (h handler) resolve(ctx context.Context, ref, wantKind, namespace string) (*v1alpha1.RawObject, error) {
+	name, tag, _ := strings.Cut(ref, "@")
+	if name == "" {
+		return nil, huma.Error400BadRequest("ref must be name@tag")
+	}
+	tag = cmp.Or(tag, v1alpha1store.DefaultTag())
+	ns := cmp.Or(namespace, v1alpha1.DefaultNamespace)
+
+	kinds := []string{v1alpha1.KindMCPServer, v1alpha1.KindSkill}
+	if wantKind != "" {
+		kinds = []string{wantKind}
+	}
+	var found *v1alpha1.RawObject
+	for _, kind := range kinds {
+		store := h.cfg.Stores[kind]
+		if store == nil {
+			continue
+		}
+		row, err := store.Get(ctx, ns, name, tag)
+		if errors.Is(err, pkgdb.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("get "+kind, err)
+		}
+		if found != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("%s@%s exists as both MCPServer and Skill; set kind", name, tag))
+		}
+		row.Kind = kind
+		found = row
+	}
+	if found == nil {
+		return nil, huma.Error404NotFound(fmt.Sprintf("%s/%s@%s not found", ns, name, tag))
+	}
+	return found, nil
+}
+
+// impact finds the Agent versions whose MCP server or skill references
+// resolve to row, then the active Deployments targeting row or one of those
+// Agents.
+func (h handler) impact(ctx context.Context, row *v1alpha1.RawObject) (arv0.VulnerabilityImpact, error) {
+	meta := row.Metadata
+	impact := arv0.VulnerabilityImpact{
+		Kind:        row.Kind,
+		Namespace:   meta.Namespace,
+		Name:        meta.Name,
+		Tag:         meta.Tag,
+		Advisory:    meta.Annotations[v1alpha1.VulnerableAnnotation],
+		Agents:      []arv0.ImpactedAgent{},
+		Deployments: []arv0.ImpactedDeployment{},
+	}
+
+	if store := h.cfg.Stores[v1alpha1.KindAgent]; store != nil {
+		field := "mcpServers"
+		if row.Kind == v1alpha1.KindSkill {
+			field = "skills"
+		}
+		agents, err := findReferrers(ctx, store, map[string]any{field: []any{map[string]string{"name": meta.Name}}})
+		if err != nil {
+			return impact, err
+		}
+		for _, agentRow := range agents {
+			agent, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Agent { return &v1alpha1.Agent{} }, agentRow, v1alpha1.KindAgent)
+			if err != nil {
+				return impact, huma.Error500InternalServerError("decode agent", err)
+			}
+			refs := agent.Spec.MCPServers
+			if row.Kind == v1alpha1.KindSkill {
+				refs = agent.Spec.Skills
+			}
+			idx := slices.IndexFunc(refs, func(ref v1alpha1.ResourceRef) bool {
+				return resolvesTo(ref, agent.Metadata.NamespaceOrDefault(), meta)
+			})
+			if idx < 0 {
+				continue
+			}
+			impact.Agents = append(impact.Agents, arv0.ImpactedAgent{
+				Namespace: agent.Metadata.NamespaceOrDefault(),
+				Name:      agent.Metadata.Name,
+				Tag:       agent.Metadata.Tag,
+				Field:     fmt.Sprintf("spec.%s[%d]", field, idx),
+			})
+		}
+	}
+
+	if store := h.cfg.Stores[v1alpha1.KindDeployment]; store != nil {
+		targets := []v1alpha1.ObjectMeta{meta}
+		kinds := []string{row.Kind}
+		for _, agent := range impact.Agents {
+			targets = append(targets, v1alpha1.ObjectMeta{Namespace: agent.Namespace, Name: agent.Name, Tag: agent.Tag})
+			kinds = append(kinds, v1alpha1.KindAgent)
+		}
+		// One lookup per target name; agent versions share it.
+		deployments := map[string][]*v1alpha1.RawObject{}
+		seen := map[string]bool{}
+		for i, target := range targets {
+			key := kinds[i] + "/" + target.Name
+			rows, ok := deployments[key]
+			if !ok {
+				var err error
+				rows, err = findReferrers(ctx, store, map[string]any{"targetRef": map[string]string{"kind": kinds[i], "name": target.Name}})
+				if err != nil {
+					return impact, err
+				}
+				deployments[key] = rows
+			}
+			for _, depRow := range rows {
+				dep, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} }, depRow, v1alpha1.KindDeployment)
+				if err != nil {
+					return impact, huma.Error500InternalServerError("decode deployment", err)
+				}
+				ns := dep.Metadata.NamespaceOrDefault()
+				if dep.Spec.DesiredState == v1alpha1.DesiredStateUndeployed || seen[ns+"/"+dep.Metadata.Name] {
+					continue
+				}
+				if !resolvesTo(dep.Spec.TargetRef, ns, target) {
+					continue
+				}
+				seen[ns+"/"+dep.Metadata.Name] = true
+				impact.Deployments = append(impact.Deployments, arv0.ImpactedDeployment{
+					Namespace: ns,
+					Name:      dep.Metadata.Name,
+					Target:    fmt.Sprintf("%s/%s@%s", kinds[i], target.Name, target.Tag),
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(impact.Agents, func(a, b arv0.ImpactedAgent) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name), cmp.Compare(a.Tag, b.Tag))
+	})
+	slices.SortFunc(impact.Deployments, func(a, b arv0.ImpactedDeployment) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return impact, nil
+}
+
+// filter drops the Agents and Deployments the caller may not read.
+func (h handler) filter(ctx context.Context, impact *arv0.VulnerabilityImpact) {
+	if authz := h.cfg.Authorizers[v1alpha1.KindAgent]; authz != nil {
+		impact.Agents = slices.DeleteFunc(impact.Agents, func(a arv0.ImpactedAgent) bool {
+			return authz(ctx, resource.AuthorizeInput{Verb: "get", Kind: v1alpha1.KindAgent, Namespace: a.Namespace, Name: a.Name, Tag: a.Tag}) != nil
+		})
+	}
+	if authz := h.cfg.Authorizers[v1alpha1.KindDeployment]; authz != nil {
+		impact.Deployments = slices.DeleteFunc(impact.Deployments, func(d arv0.ImpactedDeployment) bool {
+			return authz(ctx, resource.AuthorizeInput{Verb: "get", Kind: v1alpha1.KindDeployment, Namespace: d.Namespace, Name: d.Name}) != nil
+		})
+	}
+}
+
+// mark stamps the advisory on row, flags the impacted objects and notifies
+// each namespace that gained a flag. Re-marking is idempotent: objects
+// already flagged for row are not notified again.
+func (h handler) mark(ctx context.Context, row *v1alpha1.RawObject, advisory string) (arv0.VulnerabilityImpact, error) {
+	meta := row.Metadata
+	if err := h.cfg.Stores[row.Kind].PatchAnnotations(ctx, meta.Namespace, meta.Name, meta.Tag, func(a map[string]string) map[string]string {
+		a[v1alpha1.VulnerableAnnotation] = advisory
+		return a
+	}); err != nil {
+		return arv0.VulnerabilityImpact{}, huma.Error500InternalServerError("mark vulnerable", err)
+	}
+	row.Metadata.Annotations = setAnnotation(row.Metadata.Annotations, v1alpha1.VulnerableAnnotation, advisory)
+
+	impact, err := h.impact(ctx, row)
+	if err != nil {
+		return impact, err
+	}
+	ref := artifactRef(row)
+	flagged := map[string][]string{}
+	for _, agent := range impact.Agents {
+		added, err := h.flag(ctx, v1alpha1.KindAgent, agent.Namespace, agent.Name, agent.Tag, ref, true)
+		if err != nil {
+			return impact, err
+		}
+		if added {
+			flagged[agent.Namespace] = append(flagged[agent.Namespace], fmt.Sprintf("%s/%s@%s", v1alpha1.KindAgent, agent.Name, agent.Tag))
+		}
+	}
+	for _, dep := range impact.Deployments {
+		added, err := h.flag(ctx, v1alpha1.KindDeployment, dep.Namespace, dep.Name, "", ref, true)
+		if err != nil {
+			return impact, err
+		}
+		if added {
+			flagged[dep.Namespace] = append(flagged[dep.Namespace], v1alpha1.KindDeployment+"/"+dep.Name)
+		}
+	}
+	if h.cfg.Notifier != nil {
+		artifact := v1alpha1.ResourceRef{Kind: row.Kind, Namespace: meta.Namespace, Name: meta.Name, Tag: meta.Tag}
+		for _, ns := range slices.Sorted(maps.Keys(flagged)) {
+			h.cfg.Notifier.VulnerabilityFlagged(ctx, artifact, advisory, ns, flagged[ns])
+		}
+	}
+	return impact, nil
+}
+
+// clear removes the advisory from row and row's entry from the flags of
+// the objects that currently reach it.
+func (h handler) clear(ctx context.Context, row *v1alpha1.RawObject) (arv0.VulnerabilityImpact, error) {
+	meta := row.Metadata
+	if err := h.cfg.Stores[row.Kind].PatchAnnotations(ctx, meta.Namespace, meta.Name, meta.Tag, func(a map[string]string) map[string]string {
+		delete(a, v1alpha1.VulnerableAnnotation)
+		return a
+	}); err != nil {
+		return arv0.VulnerabilityImpact{}, huma.Error500InternalServerError("clear vulnerable", err)
+	}
+	delete(row.Metadata.Annotations, v1alpha1.VulnerableAnnotation)
+
+	impact, err := h.impact(ctx, row)
+	if err != nil {
+		return impact, err
+	}
+	ref := artifactRef(row)
+	for _, agent := range impact.Agents {
+		if _, err := h.flag(ctx, v1alpha1.KindAgent, agent.Namespace, agent.Name, agent.Tag, ref, false); err != nil {
+			return impact, err
+		}
+	}
+	for _, dep := range impact.Deployments {
+		if _, err := h.flag(ctx, v1alpha1.KindDeployment, dep.Namespace, dep.Name, "", ref, false); err != nil {
+			return impact, err
+		}
+	}
+	return impact, nil
+}
+
+// flag adds (or removes) ref in the object's vulnerable-dependencies
+// annotation and reports whether an add changed it.
+func (h handler) flag(ctx context.Context, kind, namespace, name, tag, ref string, add bool) (bool, error) {
+	store := h.cfg.Stores[kind]
+	changed := false
+	err := store.PatchAnnotations(ctx, namespace, name, tag, func(a map[string]string) map[string]string {
+		var refs []string
+		if current := a[v1alpha1.VulnerableDependenciesAnnotation]; current != "" {
+			refs = strings.Split(current, ",")
+		}
+		has := slices.Contains(refs, ref)
+		switch {
+		case add && !has:
+			refs = append(refs, ref)
+			slices.Sort(refs)
+			changed = true
+		case !add && has:
+			refs = slices.DeleteFunc(refs, func(r string) bool { return r == ref })
+		default:
+			return a
+		}
+		if len(refs) == 0 {
+			delete(a, v1alpha1.VulnerableDependenciesAnnotation)
+		} else {
+			a[v1alpha1.VulnerableDependenciesAnnotation] = strings.Join(refs, ",")
+		}
+		return a
+	})
+	// The object may have been deleted since the impact was computed.
+	if errors.Is(err, pkgdb.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, huma.Error500InternalServerError(fmt.Sprintf("flag %s %s/%s", kind, namespace, name), err)
+	}
+	return changed, nil
+}
+
+func findReferrers(ctx context.Context, store Store, path map[string]any) ([]*v1alpha1.RawObject, error) {
+	pathJSON, err := json.Marshal(path)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("encode referrer path", err)
+	}
+	rows, err := store.FindReferrers(ctx, pathJSON, v1alpha1store.FindReferrersOpts{})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("find referrers", err)
+	}
+	return rows, nil
+}
+
+// resolvesTo reports whether ref, written in namespace defaultNS, points at
+// the version identified by target.
+func resolvesTo(ref v1alpha1.ResourceRef, defaultNS string, target v1alpha1.ObjectMeta) bool {
+	return ref.Registry == "" &&
+		ref.Name == target.Name &&
+		cmp.Or(ref.Namespace, defaultNS) == target.Namespace &&
+		cmp.Or(ref.Tag, v1alpha1store.DefaultTag()) == target.Tag
+}
+
+func artifactRef(row *v1alpha1.RawObject) string {
+	return fmt.Sprintf("%s/%s/%s@%s", row.Kind, row.Metadata.Namespace, row.Metadata.Name, row.Metadata.Tag)
+}
+
+func setAnnotation(annotations map[string]string, key, value string) map[string]string {
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	return annotations
+}
@@ -0,0 +1,284 @@
+package security_test
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/security"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore struct {
+	rows []*v1alpha1.RawObject
+}
+
+func (f *fakeStore) find(namespace, name, tag string) *v1alpha1.RawObject {
+	for _, row := range f.rows {
+		if row.Metadata.Namespace == namespace && row.Metadata.Name == name && row.Metadata.Tag == tag {
+			return row
+		}
+	}
+	return nil
+}
+
+func (f *fakeStore) Get(_ context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	if row := f.find(namespace, name, tag); row != nil {
+		out := *row
+		out.Metadata.Annotations = maps.Clone(row.Metadata.Annotations)
+		return &out, nil
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+// FindReferrers emulates the JSONB @> containment query.
+func (f *fakeStore) FindReferrers(_ context.Context, pathJSON json.RawMessage, _ v1alpha1store.FindReferrersOpts) ([]*v1alpha1.RawObject, error) {
+	var want any
+	if err := json.Unmarshal(pathJSON, &want); err != nil {
+		return nil, err
+	}
+	var out []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		var spec any
+		if err := json.Unmarshal(row.Spec, &spec); err != nil {
+			return nil, err
+		}
+		if contains(spec, want) {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) PatchAnnotations(_ context.Context, namespace, name, tag string, mutate func(map[string]string) map[string]string) error {
+	row := f.find(namespace, name, tag)
+	if row == nil {
+		return pkgdb.ErrNotFound
+	}
+	if row.Metadata.Annotations == nil {
+		row.Metadata.Annotations = map[string]string{}
+	}
+	row.Metadata.Annotations = mutate(row.Metadata.Annotations)
+	return nil
+}
+
+func contains(have, want any) bool {
+	switch w := want.(type) {
+	case map[string]any:
+		h, ok := have.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, v := range w {
+			if !contains(h[k], v) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		h, ok := have.([]any)
+		if !ok {
+			return false
+		}
+		for _, wv := range w {
+			found := false
+			for _, hv := range h {
+				if contains(hv, wv) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(have, want)
+	}
+}
+
+func raw(t *testing.T, ns, name, tag string, spec any) *v1alpha1.RawObject {
+	t.Helper()
+	specJSON, err := json.Marshal(spec)
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{
+		Metadata: v1alpha1.ObjectMeta{Namespace: ns, Name: name, Tag: tag},
+		Spec:     specJSON,
+	}
+}
+
+type notification struct {
+	artifact  v1alpha1.ResourceRef
+	advisory  string
+	namespace string
+	affected  []string
+}
+
+type fakeNotifier struct {
+	got []notification
+}
+
+func (f *fakeNotifier) VulnerabilityFlagged(_ context.Context, artifact v1alpha1.ResourceRef, advisory, namespace string, affected []string) {
+	f.got = append(f.got, notification{artifact: artifact, advisory: advisory, namespace: namespace, affected: affected})
+}
+
+type fixture struct {
+	api         humatest.TestAPI
+	agents      *fakeStore
+	deployments *fakeStore
+	skills      *fakeStore
+	notifier    *fakeNotifier
+}
+
+func newFixture(t *testing.T, cfg security.Config) *fixture {
+	t.Helper()
+	skillRefs := func(refs ...v1alpha1.ResourceRef) v1alpha1.AgentSpec {
+		return v1alpha1.AgentSpec{Skills: refs}
+	}
+	deploy := func(ns, name string, target v1alpha1.ResourceRef, state string) *v1alpha1.RawObject {
+		return raw(t, ns, name, "", v1alpha1.DeploymentSpec{
+			TargetRef:    target,
+			RuntimeRef:   v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "local"},
+			DesiredState: state,
+		})
+	}
+	f := &fixture{
+		skills: &fakeStore{rows: []*v1alpha1.RawObject{
+			raw(t, "default", "summarize", "1.2.0", v1alpha1.SkillSpec{Title: "Summarize"}),
+			raw(t, "default", "summarize", "latest", v1alpha1.SkillSpec{Title: "Summarize"}),
+		}},
+		agents: &fakeStore{rows: []*v1alpha1.RawObject{
+			raw(t, "default", "helper", "1.0.0", skillRefs(v1alpha1.ResourceRef{Name: "lint"}, v1alpha1.ResourceRef{Name: "summarize", Tag: "1.2.0"})),
+			raw(t, "default", "helper", "2.0.0", skillRefs(v1alpha1.ResourceRef{Name: "summarize", Tag: "1.3.0"})),
+			raw(t, "team-a", "reporter", "1.0.0", skillRefs(v1alpha1.ResourceRef{Namespace: "default", Name: "summarize", Tag: "1.2.0"})),
+			raw(t, "team-b", "reporter", "1.0.0", skillRefs(v1alpha1.ResourceRef{Name: "summarize", Tag: "1.2.0"})),
+			raw(t, "default", "floating", "1.0.0", skillRefs(v1alpha1.ResourceRef{Name: "summarize"})),
+		}},
+		deployments: &fakeStore{rows: []*v1alpha1.RawObject{
+			deploy("default", "helper-prod", v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "helper", Tag: "1.0.0"}, ""),
+			deploy("default", "helper-next", v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "helper", Tag: "2.0.0"}, ""),
+			deploy("default", "helper-old", v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "helper", Tag: "1.0.0"}, v1alpha1.DesiredStateUndeployed),
+			deploy("team-a", "reports", v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "reporter", Tag: "1.0.0"}, v1alpha1.DesiredStateDeployed),
+		}},
+		notifier: &fakeNotifier{},
+	}
+	cfg.BasePrefix = "/v0"
+	cfg.Stores = map[string]security.Store{
+		v1alpha1.KindSkill:      f.skills,
+		v1alpha1.KindAgent:      f.agents,
+		v1alpha1.KindDeployment: f.deployments,
+	}
+	cfg.Notifier = f.notifier
+	_, api := humatest.New(t)
+	security.Register(api, cfg)
+	f.api = api
+	return f
+}
+
+func decode(t *testing.T, body []byte) arv0.VulnerabilityImpact {
+	t.Helper()
+	var impact arv0.VulnerabilityImpact
+	require.NoError(t, json.Unmarshal(body, &impact))
+	return impact
+}
+
+func TestImpact(t *testing.T) {
+	f := newFixture(t, security.Config{})
+
+	resp := f.api.Get("/v0/security/impact/summarize@1.2.0")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	impact := decode(t, resp.Body.Bytes())
+
+	require.Equal(t, v1alpha1.KindSkill, impact.Kind)
+	require.Empty(t, impact.Advisory)
+	require.Equal(t, []arv0.ImpactedAgent{
+		{Namespace: "default", Name: "helper", Tag: "1.0.0", Field: "spec.skills[1]"},
+		{Namespace: "team-a", Name: "reporter", Tag: "1.0.0", Field: "spec.skills[0]"},
+	}, impact.Agents, "team-b resolves the ref in its own namespace; floating follows latest")
+	require.Equal(t, []arv0.ImpactedDeployment{
+		{Namespace: "default", Name: "helper-prod", Target: "Agent/helper@1.0.0"},
+		{Namespace: "team-a", Name: "reports", Target: "Agent/reporter@1.0.0"},
+	}, impact.Deployments, "undeployed and other-version deployments are not affected")
+
+	resp = f.api.Get("/v0/security/impact/summarize")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	latest := decode(t, resp.Body.Bytes())
+	require.Equal(t, "latest", latest.Tag)
+	require.Equal(t, []arv0.ImpactedAgent{{Namespace: "default", Name: "floating", Tag: "1.0.0", Field: "spec.skills[0]"}}, latest.Agents)
+
+	resp = f.api.Get("/v0/security/impact/summarize@9.9.9")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestImpact_FiltersUnreadable(t *testing.T) {
+	f := newFixture(t, security.Config{
+		Authorizers: map[string]func(context.Context, resource.AuthorizeInput) error{
+			v1alpha1.KindDeployment: func(_ context.Context, in resource.AuthorizeInput) error {
+				if in.Namespace != "default" {
+					return huma.Error403Forbidden("no")
+				}
+				return nil
+			},
+		},
+	})
+	resp := f.api.Get("/v0/security/impact/summarize@1.2.0?kind=Skill")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	impact := decode(t, resp.Body.Bytes())
+	require.Len(t, impact.Agents, 2)
+	require.Len(t, impact.Deployments, 1)
+	require.Equal(t, "helper-prod", impact.Deployments[0].Name)
+}
+
+func TestMarkAndClear(t *testing.T) {
+	f := newFixture(t, security.Config{IsAdmin: func(context.Context) bool { return true }})
+	body := map[string]any{"advisory": "CVE-2026-1234"}
+
+	resp := f.api.Put("/v0/security/vulnerabilities/summarize@1.2.0", body)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	impact := decode(t, resp.Body.Bytes())
+	require.Equal(t, "CVE-2026-1234", impact.Advisory)
+
+	require.Equal(t, "CVE-2026-1234", f.skills.find("default", "summarize", "1.2.0").Metadata.Annotations[v1alpha1.VulnerableAnnotation])
+	const ref = "Skill/default/summarize@1.2.0"
+	require.Equal(t, ref, f.agents.find("team-a", "reporter", "1.0.0").Metadata.Annotations[v1alpha1.VulnerableDependenciesAnnotation])
+	require.Equal(t, ref, f.deployments.find("default", "helper-prod", "").Metadata.Annotations[v1alpha1.VulnerableDependenciesAnnotation])
+	require.Empty(t, f.deployments.find("default", "helper-old", "").Metadata.Annotations)
+
+	artifact := v1alpha1.ResourceRef{Kind: v1alpha1.KindSkill, Namespace: "default", Name: "summarize", Tag: "1.2.0"}
+	require.Equal(t, []notification{
+		{artifact: artifact, advisory: "CVE-2026-1234", namespace: "default", affected: []string{"Agent/helper@1.0.0", "Deployment/helper-prod"}},
+		{artifact: artifact, advisory: "CVE-2026-1234", namespace: "team-a", affected: []string{"Agent/reporter@1.0.0", "Deployment/reports"}},
+	}, f.notifier.got)
+
+	// Re-marking is idempotent and does not notify again.
+	resp = f.api.Put("/v0/security/vulnerabilities/summarize@1.2.0", body)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Len(t, f.notifier.got, 2)
+
+	resp = f.api.Delete("/v0/security/vulnerabilities/summarize@1.2.0")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Empty(t, decode(t, resp.Body.Bytes()).Advisory)
+	require.NotContains(t, f.skills.find("default", "summarize", "1.2.0").Metadata.Annotations, v1alpha1.VulnerableAnnotation)
+	require.NotContains(t, f.agents.find("team-a", "reporter", "1.0.0").Metadata.Annotations, v1alpha1.VulnerableDependenciesAnnotation)
+}
+
+func TestMark_RequiresAdmin(t *testing.T) {
+	f := newFixture(t, security.Config{})
+	resp := f.api.Put("/v0/security/vulnerabilities/summarize@1.2.0", map[string]any{"advisory": "x"})
+	require.Equal(t, http.StatusForbidden, resp.Code)
+	require.True(t, strings.Contains(resp.Body.String(), "admin"))
+	require.Empty(t, f.skills.find("default", "summarize", "1.2.0").Metadata.Annotations)
+}
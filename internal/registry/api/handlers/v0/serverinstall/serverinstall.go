@@ -0,0 +1,282 @@
+// Package serverinstall owns the MCPServer client-configuration endpoint:
+// `GET /v0/mcpservers/{name}/install?client=claude|cursor|vscode`. It
+// renders the entry a Claude Desktop, Cursor or VS Code config file needs to
+// use one MCPServer version, from its remote or its stdio package, so users
+// don't hand-write it.
+//
+// Remotes become url entries; Claude Desktop only launches local commands,
+// so it reaches remotes through the mcp-remote npm bridge. npm and PyPI
+// packages run through npx and uvx, OCI packages through `docker run`.
+// Packages served over http have no client-side launch and are reported as
+// not installable: deploy them and point the client at the deployment.
+package serverinstall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"path"
+	"slices"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/utils"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Clients an MCPServer can be rendered for.
+const (
+	ClientClaude = "claude"
+	ClientCursor = "cursor"
+	ClientVSCode = "vscode"
+)
+
+// Clients lists the supported clients.
+var Clients = []string{ClientClaude, ClientCursor, ClientVSCode}
+
+// ErrNotInstallable is returned by Render for a server that has neither a
+// remote nor a stdio package.
+var ErrNotInstallable = errors.New("not installable")
+
+// Store is the narrow read surface this handler needs from the MCPServer
+// store. *v1alpha1store.Store satisfies it; tests supply a fake.
+type Store interface {
+	Get(ctx context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error)
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// Authorize gates the read the same way the regular MCPServer GET
+	// handler does (verb "get"). nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type installInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Tag       string `query:"tag" doc:"Tag to render (defaults to 'latest')."`
+	Client    string `query:"client" required:"true" enum:"claude,cursor,vscode" doc:"MCP client to render the configuration for."`
+}
+
+type installOutput struct {
+	Body arv0.MCPServerInstall
+}
+
+// Register wires GET {basePrefix}/mcpservers/{name}/install. The literal
+// segment wins over GET {basePrefix}/mcpservers/{name}/{tag}, so a tag
+// named "install" cannot be fetched directly (list and apply still reach
+// it).
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-mcpserver-install",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/mcpservers/{name}/install",
+		Summary:     "Render the MCP client configuration for an MCPServer version",
+		Description: "Returns the entry a Claude Desktop, Cursor or VS Code config file needs to use the server, " +
+			"rendered from its remote or stdio package. Answers 422 for a server with neither.",
+	}, func(ctx context.Context, in *installInput) (*installOutput, error) {
+		ns := in.Namespace
+		if ns == "" {
+			ns = v1alpha1.DefaultNamespace
+		}
+		tag := in.Tag
+		if tag == "" {
+			tag = v1alpha1store.DefaultTag()
+		}
+		name, err := resource.UnescapeName(in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, resource.AuthorizeInput{
+				Verb: "get", Kind: v1alpha1.KindMCPServer,
+				Namespace: ns, Name: name, Tag: tag,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		row, err := cfg.Store.Get(ctx, ns, name, tag)
+		if err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("MCPServer %q/%q@%q not found", ns, name, tag))
+			}
+			return nil, huma.Error500InternalServerError("fetch MCPServer", err)
+		}
+		server, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.MCPServer { return &v1alpha1.MCPServer{} }, row, v1alpha1.KindMCPServer)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("decode MCPServer", err)
+		}
+		entry, missing, err := Render(in.Client, server.Spec)
+		if err != nil {
+			if errors.Is(err, ErrNotInstallable) {
+				return nil, huma.Error422UnprocessableEntity(fmt.Sprintf("MCPServer %q@%q is %v", name, tag, err))
+			}
+			return nil, huma.Error500InternalServerError("render MCPServer", err)
+		}
+		key := ConfigKey(in.Client)
+		serverKey := path.Base(name)
+		return &installOutput{Body: arv0.MCPServerInstall{
+			Namespace: ns,
+			Name:      name,
+			Tag:       tag,
+			Client:    in.Client,
+			Key:       key,
+			ServerKey: serverKey,
+			Entry:     entry,
+			Config:    map[string]map[string]arv0.MCPClientServer{key: {serverKey: entry}},
+			Missing:   missing,
+		}}, nil
+	})
+}
+
+// ConfigKey returns the top-level field of client's config file that maps
+// server keys to entries.
+func ConfigKey(client string) string {
+	if client == ClientVSCode {
+		return "servers"
+	}
+	return "mcpServers"
+}
+
+// Render returns client's config entry for an MCPServer spec, and the
+// environment variables and headers it leaves empty for the user to fill
+// in. A remote takes precedence over a package. Render returns an error
+// wrapping ErrNotInstallable when the spec has neither a remote nor a
+// stdio package.
+func Render(client string, spec v1alpha1.MCPServerSpec) (arv0.MCPClientServer, []string, error) {
+	if !slices.Contains(Clients, client) {
+		return arv0.MCPClientServer{}, nil, fmt.Errorf("unsupported client %q", client)
+	}
+	if spec.Remote != nil {
+		entry, missing := renderRemote(client, spec.Remote)
+		return entry, missing, nil
+	}
+	if spec.Source == nil || spec.Source.Package == nil {
+		return arv0.MCPClientServer{}, nil, fmt.Errorf("%w: it has neither a remote nor a package", ErrNotInstallable)
+	}
+	pkg := spec.Source.Package
+	if pkg.Transport.Type != "stdio" {
+		return arv0.MCPClientServer{}, nil, fmt.Errorf("%w: its package is served over %s; deploy it and configure the client with the deployment's URL",
+			ErrNotInstallable, pkg.Transport.Type)
+	}
+	return renderPackage(client, pkg)
+}
+
+// renderRemote renders a remote as a url entry, or for Claude Desktop as an
+// mcp-remote bridge command. Header values that read registry secrets are
+// left empty: the client has no access to them.
+func renderRemote(client string, remote *v1alpha1.MCPRemote) (arv0.MCPClientServer, []string) {
+	var (
+		headers map[string]string
+		missing []string
+	)
+	for _, h := range remote.Headers {
+		value := h.Value
+		if refs, err := v1alpha1.HeaderSecretRefs(value); err != nil || len(refs) > 0 || value == "" {
+			value = ""
+			missing = append(missing, h.Name)
+		}
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		headers[h.Name] = value
+	}
+
+	switch client {
+	case ClientClaude:
+		args := []string{"-y", "mcp-remote", remote.URL}
+		if remote.Type == "sse" {
+			args = append(args, "--transport", "sse-only")
+		}
+		for _, h := range remote.Headers {
+			args = append(args, "--header", h.Name+":"+headers[h.Name])
+		}
+		return arv0.MCPClientServer{Command: "npx", Args: args}, missing
+	case ClientVSCode:
+		typ := "http"
+		if remote.Type == "sse" {
+			typ = "sse"
+		}
+		return arv0.MCPClientServer{Type: typ, URL: remote.URL, Headers: headers}, missing
+	default:
+		return arv0.MCPClientServer{URL: remote.URL, Headers: headers}, missing
+	}
+}
+
+// renderPackage renders a stdio package as a local command. Launch, when
+// set, owns the command and arguments as it does for deployments; without
+// it npm and PyPI packages use the deployment defaults and OCI images run
+// their entrypoint.
+func renderPackage(client string, pkg *v1alpha1.MCPPackage) (arv0.MCPClientServer, []string, error) {
+	defaults, defaultArgs, err := utils.GetRegistryConfig(pkg.Origin)
+	if err != nil {
+		return arv0.MCPClientServer{}, nil, err
+	}
+
+	var (
+		env     map[string]string
+		missing []string
+	)
+	command, args := defaults.Command, defaultArgs
+	if launch := pkg.Launch; launch != nil {
+		command, args = launch.Command, launchArgs(launch.Args)
+		for _, e := range launch.Env {
+			if env == nil {
+				env = map[string]string{}
+			}
+			env[e.Name] = e.Value
+			if e.Value == "" {
+				missing = append(missing, e.Name)
+			}
+		}
+	}
+
+	if defaults.IsOCI {
+		run := []string{"run", "-i", "--rm"}
+		// docker passes each named variable through from the env the
+		// client starts it with.
+		for _, name := range slices.Sorted(maps.Keys(env)) {
+			run = append(run, "-e", name)
+		}
+		if command != "" {
+			run = append(run, "--entrypoint", command)
+		}
+		command, args = "docker", append(append(run, defaults.Image), args...)
+	}
+
+	entry := arv0.MCPClientServer{Command: command, Args: args, Env: env}
+	if client == ClientVSCode {
+		entry.Type = "stdio"
+	}
+	return entry, missing, nil
+}
+
+// launchArgs flattens a package's declared arguments the way deployments
+// do: positional values first, then each named argument and its value.
+func launchArgs(declared []v1alpha1.MCPArgument) []string {
+	var args []string
+	for _, arg := range declared {
+		if arg.Type == v1alpha1.MCPArgumentTypePositional && arg.Value != "" {
+			args = append(args, arg.Value)
+		}
+	}
+	for _, arg := range declared {
+		if arg.Type == v1alpha1.MCPArgumentTypeNamed {
+			args = append(args, arg.Name)
+			if arg.Value != "" {
+				args = append(args, arg.Value)
+			}
+		}
+	}
+	return args
+}
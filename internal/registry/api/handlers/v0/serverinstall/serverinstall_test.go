@@ -0,0 +1,222 @@
+package serverinstall_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/serverinstall"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+)
+
+type fakeStore map[string]*v1alpha1.RawObject
+
+func (f fakeStore) Get(_ context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	row, ok := f[namespace+"/"+name+"@"+tag]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	return row, nil
+}
+
+func row(t *testing.T, tag string, spec v1alpha1.MCPServerSpec) *v1alpha1.RawObject {
+	t.Helper()
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "acme/weather", Tag: tag},
+		Spec:     data,
+	}
+}
+
+func npmPackage(launch *v1alpha1.MCPPackageLaunch) *v1alpha1.MCPServerSource {
+	return &v1alpha1.MCPServerSource{Package: &v1alpha1.MCPPackage{
+		Origin: v1alpha1.MCPPackageOrigin{
+			Type:       v1alpha1.MCPPackageOriginTypeNPM,
+			Identifier: "@acme/weather-mcp",
+			NPM:        &v1alpha1.MCPPackageOriginNPM{Version: "1.2.0", ServerName: "acme/weather"},
+		},
+		Launch:    launch,
+		Transport: v1alpha1.MCPTransport{Type: "stdio"},
+	}}
+}
+
+func TestRender(t *testing.T) {
+	remote := v1alpha1.MCPServerSpec{Remote: &v1alpha1.MCPRemote{
+		Type: "sse",
+		URL:  "https://weather.example.com/sse",
+		Headers: []v1alpha1.HTTPHeader{
+			{Name: "X-Region", Value: "eu"},
+			{Name: "Authorization", Value: `Bearer {{secret "weather-token"}}`},
+		},
+	}}
+	launched := v1alpha1.MCPServerSpec{Source: npmPackage(&v1alpha1.MCPPackageLaunch{
+		Command: "node",
+		Args: []v1alpha1.MCPArgument{
+			{Type: v1alpha1.MCPArgumentTypeNamed, Name: "--units", Value: "metric"},
+			{Type: v1alpha1.MCPArgumentTypePositional, Value: "server.js"},
+		},
+		Env: []v1alpha1.MCPKeyValueInput{{Name: "LOG_LEVEL", Value: "info"}, {Name: "API_KEY", IsRequired: true}},
+	})}
+	oci := v1alpha1.MCPServerSpec{Source: &v1alpha1.MCPServerSource{Package: &v1alpha1.MCPPackage{
+		Origin: v1alpha1.MCPPackageOrigin{
+			Type:       v1alpha1.MCPPackageOriginTypeOCI,
+			Identifier: "ghcr.io/acme/weather:1.2.0",
+			OCI:        &v1alpha1.MCPPackageOriginOCI{ServerName: "acme/weather"},
+		},
+		Launch: &v1alpha1.MCPPackageLaunch{
+			Args: []v1alpha1.MCPArgument{{Type: v1alpha1.MCPArgumentTypePositional, Value: "--stdio"}},
+			Env:  []v1alpha1.MCPKeyValueInput{{Name: "TOKEN"}, {Name: "REGION", Value: "eu"}},
+		},
+		Transport: v1alpha1.MCPTransport{Type: "stdio"},
+	}}}
+
+	tests := []struct {
+		name        string
+		client      string
+		spec        v1alpha1.MCPServerSpec
+		want        arv0.MCPClientServer
+		wantMissing []string
+	}{
+		{
+			name:   "remote for cursor",
+			client: serverinstall.ClientCursor,
+			spec:   remote,
+			want: arv0.MCPClientServer{
+				URL:     "https://weather.example.com/sse",
+				Headers: map[string]string{"X-Region": "eu", "Authorization": ""},
+			},
+			wantMissing: []string{"Authorization"},
+		},
+		{
+			name:   "remote for vscode",
+			client: serverinstall.ClientVSCode,
+			spec:   remote,
+			want: arv0.MCPClientServer{
+				Type:    "sse",
+				URL:     "https://weather.example.com/sse",
+				Headers: map[string]string{"X-Region": "eu", "Authorization": ""},
+			},
+			wantMissing: []string{"Authorization"},
+		},
+		{
+			name:   "remote for claude bridges through mcp-remote",
+			client: serverinstall.ClientClaude,
+			spec:   remote,
+			want: arv0.MCPClientServer{
+				Command: "npx",
+				Args: []string{"-y", "mcp-remote", "https://weather.example.com/sse", "--transport", "sse-only",
+					"--header", "X-Region:eu", "--header", "Authorization:"},
+			},
+			wantMissing: []string{"Authorization"},
+		},
+		{
+			name:   "npm defaults",
+			client: serverinstall.ClientClaude,
+			spec:   v1alpha1.MCPServerSpec{Source: npmPackage(nil)},
+			want:   arv0.MCPClientServer{Command: "npx", Args: []string{"-y", "@acme/weather-mcp@1.2.0"}},
+		},
+		{
+			name:   "npm launch for vscode",
+			client: serverinstall.ClientVSCode,
+			spec:   launched,
+			want: arv0.MCPClientServer{
+				Type:    "stdio",
+				Command: "node",
+				Args:    []string{"server.js", "--units", "metric"},
+				Env:     map[string]string{"LOG_LEVEL": "info", "API_KEY": ""},
+			},
+			wantMissing: []string{"API_KEY"},
+		},
+		{
+			name:   "oci runs through docker",
+			client: serverinstall.ClientCursor,
+			spec:   oci,
+			want: arv0.MCPClientServer{
+				Command: "docker",
+				Args:    []string{"run", "-i", "--rm", "-e", "REGION", "-e", "TOKEN", "ghcr.io/acme/weather:1.2.0", "--stdio"},
+				Env:     map[string]string{"TOKEN": "", "REGION": "eu"},
+			},
+			wantMissing: []string{"TOKEN"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, missing, err := serverinstall.Render(tc.client, tc.spec)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+			require.Equal(t, tc.wantMissing, missing)
+		})
+	}
+
+	httpPackage := v1alpha1.MCPServerSpec{Source: npmPackage(nil)}
+	httpPackage.Source.Package.Transport = v1alpha1.MCPTransport{Type: "http", Port: 8080, Path: "/mcp"}
+	_, _, err := serverinstall.Render(serverinstall.ClientCursor, httpPackage)
+	require.ErrorIs(t, err, serverinstall.ErrNotInstallable)
+	_, _, err = serverinstall.Render(serverinstall.ClientCursor, v1alpha1.MCPServerSpec{})
+	require.ErrorIs(t, err, serverinstall.ErrNotInstallable)
+	_, _, err = serverinstall.Render("zed", remote)
+	require.EqualError(t, err, `unsupported client "zed"`)
+}
+
+func TestRegister(t *testing.T) {
+	httpPackage := v1alpha1.MCPServerSpec{Source: npmPackage(nil)}
+	httpPackage.Source.Package.Transport = v1alpha1.MCPTransport{Type: "http", Port: 8080, Path: "/mcp"}
+	store := fakeStore{
+		"default/acme/weather@latest": row(t, "latest", v1alpha1.MCPServerSpec{Source: npmPackage(nil)}),
+		"default/acme/weather@0.1.0":  row(t, "0.1.0", httpPackage),
+	}
+	var authorized []resource.AuthorizeInput
+	_, api := humatest.New(t)
+	serverinstall.Register(api, serverinstall.Config{
+		BasePrefix: "/v0",
+		Store:      store,
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			authorized = append(authorized, in)
+			return nil
+		},
+	})
+
+	resp := api.Get("/v0/mcpservers/acme%2Fweather/install?client=vscode")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var install arv0.MCPServerInstall
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &install))
+	entry := arv0.MCPClientServer{Type: "stdio", Command: "npx", Args: []string{"-y", "@acme/weather-mcp@1.2.0"}}
+	require.Equal(t, arv0.MCPServerInstall{
+		Namespace: "default",
+		Name:      "acme/weather",
+		Tag:       "latest",
+		Client:    "vscode",
+		Key:       "servers",
+		ServerKey: "weather",
+		Entry:     entry,
+		Config:    map[string]map[string]arv0.MCPClientServer{"servers": {"weather": entry}},
+	}, install)
+	require.Equal(t, resource.AuthorizeInput{
+		Verb: "get", Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "acme/weather", Tag: "latest",
+	}, authorized[0])
+
+	// Names are looked up in canonical form.
+	resp = api.Get("/v0/mcpservers/Acme%2FWeather/install?client=vscode")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	resp = api.Get("/v0/mcpservers/acme%2Fweather/install?client=cursor&tag=0.1.0")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "served over http")
+
+	resp = api.Get("/v0/mcpservers/acme%2Fweather/install?client=cursor&tag=9.9.9")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = api.Get("/v0/mcpservers/acme%2Fweather/install?client=zed")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	resp = api.Get("/v0/mcpservers/acme%2Fweather/install")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
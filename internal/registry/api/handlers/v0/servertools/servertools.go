@@ -0,0 +1,83 @@
+// Package servertools owns the MCPServer tools subresource:
+// `GET /v0/mcpservers/{name}/{tag}/tools`. It lists the tools a tag
+// declares in spec.tools — names, descriptions and input schemas — as
+// recorded in the server_tools table, so clients can see what a server
+// offers without fetching and unpacking its spec.
+package servertools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Store is the narrow read surface this handler needs.
+// *v1alpha1store.ServerToolStore satisfies it; tests supply a fake.
+type Store interface {
+	Tools(ctx context.Context, namespace, name, tag string) ([]v1alpha1.MCPTool, error)
+}
+
+var _ Store = (*v1alpha1store.ServerToolStore)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	// Authorize gates the read the same way the regular MCPServer GET
+	// handler does (verb "get"). nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+type toolsInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Tag       string `path:"tag"`
+}
+
+type toolsOutput struct {
+	Body arv0.MCPServerToolList
+}
+
+// Register wires GET {basePrefix}/mcpservers/{name}/{tag}/tools.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-mcpserver-tools",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/mcpservers/{name}/{tag}/tools",
+		Summary:     "List the tools an MCPServer tag declares",
+	}, func(ctx context.Context, in *toolsInput) (*toolsOutput, error) {
+		ns := in.Namespace
+		if ns == "" {
+			ns = v1alpha1.DefaultNamespace
+		}
+		name, err := resource.UnescapeName(in.Name)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, resource.AuthorizeInput{
+				Verb: "get", Kind: v1alpha1.KindMCPServer,
+				Namespace: ns, Name: name, Tag: in.Tag,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		tools, err := cfg.Store.Tools(ctx, ns, name, in.Tag)
+		if err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("MCPServer %q/%q@%q not found", ns, name, in.Tag))
+			}
+			return nil, huma.Error500InternalServerError("list MCPServer tools", err)
+		}
+		return &toolsOutput{Body: arv0.MCPServerToolList{Namespace: ns, Name: name, Tag: in.Tag, Tools: tools}}, nil
+	})
+}
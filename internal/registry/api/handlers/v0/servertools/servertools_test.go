@@ -0,0 +1,71 @@
+package servertools_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/servertools"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+)
+
+type fakeStore map[string][]v1alpha1.MCPTool
+
+func (f fakeStore) Tools(_ context.Context, namespace, name, tag string) ([]v1alpha1.MCPTool, error) {
+	tools, ok := f[namespace+"/"+name+"@"+tag]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	return tools, nil
+}
+
+func TestRegisterServerTools(t *testing.T) {
+	store := fakeStore{
+		"default/acme/weather@1.0.0": {
+			{Name: "get_forecast", Description: "Forecast for a city.", InputSchema: map[string]any{"type": "object"}},
+			{Name: "get_alerts"},
+		},
+		"default/acme/weather@0.1.0": {},
+	}
+	var authorized []resource.AuthorizeInput
+	_, api := humatest.New(t)
+	servertools.Register(api, servertools.Config{
+		BasePrefix: "/v0",
+		Store:      store,
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			authorized = append(authorized, in)
+			if in.Tag == "secret" {
+				return huma.Error403Forbidden("forbidden")
+			}
+			return nil
+		},
+	})
+
+	resp := api.Get("/v0/mcpservers/acme%2Fweather/1.0.0/tools")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var list arv0.MCPServerToolList
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Equal(t, "acme/weather", list.Name)
+	require.Equal(t, "1.0.0", list.Tag)
+	require.Equal(t, []string{"get_forecast", "get_alerts"}, []string{list.Tools[0].Name, list.Tools[1].Name})
+	require.Equal(t, "Forecast for a city.", list.Tools[0].Description)
+	require.Equal(t, map[string]any{"type": "object"}, list.Tools[0].InputSchema)
+	require.Equal(t, resource.AuthorizeInput{
+		Verb: "get", Kind: v1alpha1.KindMCPServer, Namespace: v1alpha1.DefaultNamespace, Name: "acme/weather", Tag: "1.0.0",
+	}, authorized[0])
+
+	resp = api.Get("/v0/mcpservers/acme%2Fweather/0.1.0/tools")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Body.String(), `"tools":[]`, "a tag without tools lists none rather than null")
+
+	require.Equal(t, http.StatusNotFound, api.Get("/v0/mcpservers/acme%2Fweather/9.9.9/tools").Code)
+	require.Equal(t, http.StatusForbidden, api.Get("/v0/mcpservers/acme%2Fweather/secret/tools").Code)
+}
@@ -0,0 +1,198 @@
+// Package snapshots owns `/v0/snapshots`, where registry admins take named
+// catalog snapshots (e.g. 2024-06-release) that Deployments pin with
+// spec.snapshot. Capturing and resolving snapshots lives in
+// internal/registry/snapshots; this package only exposes them.
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Taker captures a new snapshot. *snapshots.Catalog satisfies it.
+type Taker interface {
+	Take(ctx context.Context, snapshot v1alpha1store.CatalogSnapshot) (*v1alpha1store.CatalogSnapshot, error)
+}
+
+// Store reads and deletes snapshots. *v1alpha1store.CatalogSnapshotStore
+// satisfies it.
+type Store interface {
+	Get(ctx context.Context, name string) (*v1alpha1store.CatalogSnapshot, error)
+	List(ctx context.Context) ([]*v1alpha1store.CatalogSnapshot, error)
+	Delete(ctx context.Context, name string) error
+	Entries(ctx context.Context, snapshot string) ([]v1alpha1store.CatalogSnapshotEntry, error)
+}
+
+var _ Store = (*v1alpha1store.CatalogSnapshotStore)(nil)
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Taker      Taker
+	Store      Store
+	// IsAdmin lets registry admins take and delete snapshots and list
+	// what they hold: a snapshot copies artifacts of every namespace, so
+	// there is no per-resource authz to fall back to. nil grants nobody
+	// admin.
+	IsAdmin func(ctx context.Context) bool
+}
+
+type createInput struct {
+	Body arv0.CatalogSnapshotInput
+}
+
+type nameInput struct {
+	Name string `path:"name" doc:"Snapshot name, e.g. 2024-06-release"`
+}
+
+type snapshotOutput struct {
+	Body arv0.CatalogSnapshot
+}
+
+type listOutput struct {
+	Body arv0.CatalogSnapshotList
+}
+
+// Register wires the /v0/snapshots endpoints.
+func Register(api huma.API, cfg Config) {
+	isAdmin := func(ctx context.Context) bool {
+		return cfg.IsAdmin != nil && cfg.IsAdmin(ctx)
+	}
+	requireAdmin := func(ctx context.Context) error {
+		if !isAdmin(ctx) {
+			return huma.Error403Forbidden("registry admin permission required")
+		}
+		return nil
+	}
+	base := cfg.BasePrefix + "/snapshots"
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-snapshot",
+		Method:        http.MethodPost,
+		Path:          base,
+		Summary:       "Take a catalog snapshot",
+		Description:   "Copies every live tag of every tagged artifact, in one namespace or in all of them, into a named snapshot. Deployments with `spec.snapshot` resolve their target and its refs from the copy, whatever is published later. Answers 409 when the name is taken; snapshots are never overwritten.",
+		Tags:          []string{"admin"},
+		DefaultStatus: http.StatusCreated,
+	}, func(ctx context.Context, in *createInput) (*snapshotOutput, error) {
+		if err := requireAdmin(ctx); err != nil {
+			return nil, err
+		}
+		name := strings.TrimSpace(in.Body.Name)
+		if err := v1alpha1.ValidateSnapshotName(name); err != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("name: %v", err))
+		}
+		namespace := strings.TrimSpace(in.Body.Namespace)
+		if namespace != "" {
+			if err := v1alpha1.ValidateNamespace(namespace); err != nil {
+				return nil, huma.Error400BadRequest(fmt.Sprintf("namespace: %v", err))
+			}
+		}
+		snapshot, err := cfg.Taker.Take(ctx, v1alpha1store.CatalogSnapshot{
+			Name:        name,
+			Namespace:   namespace,
+			Description: in.Body.Description,
+			CreatedBy:   auth.SubjectFrom(ctx),
+		})
+		if err != nil {
+			if errors.Is(err, pkgdb.ErrAlreadyExists) {
+				return nil, huma.Error409Conflict(fmt.Sprintf("catalog snapshot %q already exists", name))
+			}
+			return nil, huma.Error500InternalServerError("take catalog snapshot", err)
+		}
+		return &snapshotOutput{Body: toWire(snapshot)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-snapshots",
+		Method:      http.MethodGet,
+		Path:        base,
+		Summary:     "List catalog snapshots, newest first",
+		Tags:        []string{"admin"},
+	}, func(ctx context.Context, _ *struct{}) (*listOutput, error) {
+		snapshots, err := cfg.Store.List(ctx)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list catalog snapshots", err)
+		}
+		out := &listOutput{Body: arv0.CatalogSnapshotList{Snapshots: make([]arv0.CatalogSnapshot, 0, len(snapshots))}}
+		for _, snapshot := range snapshots {
+			out.Body.Snapshots = append(out.Body.Snapshots, toWire(snapshot))
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-snapshot",
+		Method:      http.MethodGet,
+		Path:        base + "/{name}",
+		Summary:     "Get a catalog snapshot",
+		Description: "Registry admins also get the artifact tags the snapshot holds.",
+		Tags:        []string{"admin"},
+	}, func(ctx context.Context, in *nameInput) (*snapshotOutput, error) {
+		snapshot, err := cfg.Store.Get(ctx, in.Name)
+		if err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("catalog snapshot %q not found", in.Name))
+			}
+			return nil, huma.Error500InternalServerError("get catalog snapshot", err)
+		}
+		out := &snapshotOutput{Body: toWire(snapshot)}
+		if !isAdmin(ctx) {
+			return out, nil
+		}
+		entries, err := cfg.Store.Entries(ctx, in.Name)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list catalog snapshot entries", err)
+		}
+		out.Body.Entries = make([]arv0.CatalogSnapshotEntry, 0, len(entries))
+		for _, entry := range entries {
+			out.Body.Entries = append(out.Body.Entries, arv0.CatalogSnapshotEntry{
+				Kind: entry.Kind, Namespace: entry.Namespace, Name: entry.Name, Tag: entry.Tag,
+			})
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-snapshot",
+		Method:        http.MethodDelete,
+		Path:          base + "/{name}",
+		Summary:       "Delete a catalog snapshot",
+		Description:   "Deployments still pinned to the snapshot block on a dangling reference at their next reconcile.",
+		Tags:          []string{"admin"},
+		DefaultStatus: http.StatusNoContent,
+	}, func(ctx context.Context, in *nameInput) (*struct{}, error) {
+		if err := requireAdmin(ctx); err != nil {
+			return nil, err
+		}
+		if err := cfg.Store.Delete(ctx, in.Name); err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("catalog snapshot %q not found", in.Name))
+			}
+			return nil, huma.Error500InternalServerError("delete catalog snapshot", err)
+		}
+		return nil, nil
+	})
+}
+
+func toWire(s *v1alpha1store.CatalogSnapshot) arv0.CatalogSnapshot {
+	return arv0.CatalogSnapshot{
+		Name:        s.Name,
+		Namespace:   s.Namespace,
+		Description: s.Description,
+		CreatedBy:   s.CreatedBy,
+		CreatedAt:   s.CreatedAt,
+		EntryCount:  s.Entries,
+	}
+}
@@ -0,0 +1,126 @@
+package snapshots_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/internal/testapi"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/snapshots"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// fakeStore takes every snapshot with the same single entry.
+type fakeStore map[string]*v1alpha1store.CatalogSnapshot
+
+func (f fakeStore) Take(_ context.Context, snapshot v1alpha1store.CatalogSnapshot) (*v1alpha1store.CatalogSnapshot, error) {
+	if _, ok := f[snapshot.Name]; ok {
+		return nil, pkgdb.ErrAlreadyExists
+	}
+	snapshot.CreatedAt = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	snapshot.Entries = 1
+	f[snapshot.Name] = &snapshot
+	return &snapshot, nil
+}
+
+func (f fakeStore) Get(_ context.Context, name string) (*v1alpha1store.CatalogSnapshot, error) {
+	snapshot, ok := f[name]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	return snapshot, nil
+}
+
+func (f fakeStore) List(context.Context) ([]*v1alpha1store.CatalogSnapshot, error) {
+	var out []*v1alpha1store.CatalogSnapshot
+	for _, snapshot := range f {
+		out = append(out, snapshot)
+	}
+	return out, nil
+}
+
+func (f fakeStore) Delete(_ context.Context, name string) error {
+	if _, ok := f[name]; !ok {
+		return pkgdb.ErrNotFound
+	}
+	delete(f, name)
+	return nil
+}
+
+func (f fakeStore) Entries(context.Context, string) ([]v1alpha1store.CatalogSnapshotEntry, error) {
+	return []v1alpha1store.CatalogSnapshotEntry{{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "weather", Tag: "1.0.0"}}, nil
+}
+
+// newAPI registers the endpoints on a testapi.New API. The subject "admin"
+// is a registry admin.
+func newAPI(t *testing.T, store fakeStore) humatest.TestAPI {
+	api := testapi.New(t)
+	snapshots.Register(api, snapshots.Config{
+		BasePrefix: "/v0",
+		Taker:      store,
+		Store:      store,
+		IsAdmin:    testapi.IsAdmin,
+	})
+	return api
+}
+
+func TestManageSnapshots(t *testing.T) {
+	api := newAPI(t, fakeStore{})
+
+	body := map[string]any{"name": "2024-06-release", "description": "June release"}
+	resp := api.Post("/v0/snapshots", "X-Subject: dev", body)
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = api.Post("/v0/snapshots", "X-Subject: admin", body)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	var created arv0.CatalogSnapshot
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+	require.Equal(t, arv0.CatalogSnapshot{
+		Name:        "2024-06-release",
+		Description: "June release",
+		CreatedBy:   "admin",
+		CreatedAt:   time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		EntryCount:  1,
+	}, created)
+
+	resp = api.Post("/v0/snapshots", "X-Subject: admin", body)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body.String())
+	resp = api.Post("/v0/snapshots", "X-Subject: admin", map[string]any{"name": "June Release"})
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	resp = api.Post("/v0/snapshots", "X-Subject: admin", map[string]any{"name": "team-a", "namespace": "Team A"})
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	resp = api.Get("/v0/snapshots", "X-Subject: dev")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var list arv0.CatalogSnapshotList
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Len(t, list.Snapshots, 1)
+
+	// Only admins see what a snapshot holds.
+	var got arv0.CatalogSnapshot
+	resp = api.Get("/v0/snapshots/2024-06-release", "X-Subject: dev")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	require.Empty(t, got.Entries)
+	resp = api.Get("/v0/snapshots/2024-06-release", "X-Subject: admin")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	require.Equal(t, []arv0.CatalogSnapshotEntry{{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "weather", Tag: "1.0.0"}}, got.Entries)
+	resp = api.Get("/v0/snapshots/2024-07-release")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	resp = api.Delete("/v0/snapshots/2024-06-release", "X-Subject: dev")
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+	resp = api.Delete("/v0/snapshots/2024-06-release", "X-Subject: admin")
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	resp = api.Delete("/v0/snapshots/2024-06-release", "X-Subject: admin")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
@@ -0,0 +1,62 @@
+// Package usage owns the admin request-volume report:
+// `GET /v0/admin/usage/top`. It ranks namespaces and callers by request count
+// and error rate over a trailing window so operators can find who is driving
+// load without querying Prometheus.
+package usage
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+// Source is the only telemetry capability needed by this handler.
+type Source interface {
+	Top(window time.Duration, limit int) arv0.UsageTopReport
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	// Source supplies the tallies. nil (metrics disabled) answers 503.
+	Source Source
+	// IsAdmin gates the endpoint. Caller identities and cross-namespace
+	// volume are operator data; nil denies.
+	IsAdmin func(ctx context.Context) bool
+}
+
+type usageTopInput struct {
+	Window string `query:"window" default:"1h" doc:"Trailing window as a Go duration (e.g. 15m, 1h). Clamped to 1m..24h."`
+	Limit  int    `query:"limit" minimum:"1" maximum:"1000" default:"10" doc:"Max namespaces and callers to return (default 10)."`
+}
+
+type usageTopOutput struct {
+	Body arv0.UsageTopReport
+}
+
+// Register wires GET {basePrefix}/admin/usage/top.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-usage-top",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/admin/usage/top",
+		Summary:     "Rank namespaces and callers by request count and error rate",
+		Tags:        []string{"admin"},
+	}, func(ctx context.Context, input *usageTopInput) (*usageTopOutput, error) {
+		if cfg.IsAdmin == nil || !cfg.IsAdmin(ctx) {
+			return nil, huma.Error403Forbidden("registry admin permission required")
+		}
+		window, err := time.ParseDuration(input.Window)
+		if err != nil || window <= 0 {
+			return nil, huma.Error400BadRequest("window must be a positive duration such as 15m or 1h")
+		}
+		if cfg.Source == nil {
+			return nil, huma.Error503ServiceUnavailable("request metrics are not enabled")
+		}
+		return &usageTopOutput{Body: cfg.Source.Top(window, input.Limit)}, nil
+	})
+}
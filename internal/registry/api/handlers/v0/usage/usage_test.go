@@ -0,0 +1,75 @@
+package usage_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/usage"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+type fakeSource struct {
+	window *time.Duration
+	limit  *int
+}
+
+func (f fakeSource) Top(window time.Duration, limit int) arv0.UsageTopReport {
+	*f.window, *f.limit = window, limit
+	return arv0.UsageTopReport{
+		Namespaces: []arv0.UsageStats{{Key: "team-a", Requests: 4}},
+		Callers:    []arv0.UsageStats{{Key: "ci-bot", Requests: 4, Errors: 1, ErrorRate: 0.25}},
+	}
+}
+
+func TestRegisterUsageTop(t *testing.T) {
+	admin := func(context.Context) bool { return true }
+	tests := []struct {
+		name       string
+		noSource   bool
+		isAdmin    func(context.Context) bool
+		query      string
+		wantCode   int
+		wantWindow time.Duration
+		wantLimit  int
+	}{
+		{"defaults", false, admin, "", http.StatusOK, time.Hour, 10},
+		{"explicit window and limit", false, admin, "?window=15m&limit=3", http.StatusOK, 15 * time.Minute, 3},
+		{"bad window", false, admin, "?window=soon", http.StatusBadRequest, 0, 0},
+		{"limit out of range", false, admin, "?limit=0", http.StatusUnprocessableEntity, 0, 0},
+		{"non-admin forbidden", false, func(context.Context) bool { return false }, "", http.StatusForbidden, 0, 0},
+		{"nil gate denies", false, nil, "", http.StatusForbidden, 0, 0},
+		{"metrics disabled", true, admin, "", http.StatusServiceUnavailable, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, api := humatest.New(t)
+			var (
+				gotWindow time.Duration
+				gotLimit  int
+			)
+			cfg := usage.Config{BasePrefix: "/v0", IsAdmin: tt.isAdmin}
+			if !tt.noSource {
+				cfg.Source = fakeSource{window: &gotWindow, limit: &gotLimit}
+			}
+			usage.Register(api, cfg)
+
+			resp := api.Get("/v0/admin/usage/top" + tt.query)
+			require.Equal(t, tt.wantCode, resp.Code, resp.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			require.Equal(t, tt.wantWindow, gotWindow)
+			require.Equal(t, tt.wantLimit, gotLimit)
+			var got arv0.UsageTopReport
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+			require.Equal(t, "ci-bot", got.Callers[0].Key)
+			require.InDelta(t, 0.25, got.Callers[0].ErrorRate, 1e-9)
+		})
+	}
+}
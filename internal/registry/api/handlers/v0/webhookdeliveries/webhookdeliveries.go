@@ -0,0 +1,230 @@
+// Package webhookdeliveries owns the Webhook delivery-log subresources:
+// `/v0/webhooks/{name}/deliveries` lists recorded delivery attempts,
+// newest first, so webhook owners can see what was sent and how their
+// endpoint answered, and `/v0/webhooks/{name}/dead-letters` lists the
+// deliveries whose attempts all failed and replays them. The Webhook CRUD
+// surface lives in crud.
+package webhookdeliveries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Store fetches the Webhook row so unknown webhooks 404.
+// *v1alpha1store.Store satisfies it.
+type Store interface {
+	GetLatest(ctx context.Context, namespace, name string) (*v1alpha1.RawObject, error)
+}
+
+// Deliveries lists recorded attempts. *v1alpha1store.WebhookDeliveryStore
+// satisfies it.
+type Deliveries interface {
+	List(ctx context.Context, namespace, webhook string, before int64, limit int) ([]v1alpha1store.WebhookDelivery, error)
+}
+
+// DeadLetters lists parked deliveries.
+// *v1alpha1store.WebhookDeadLetterStore satisfies it.
+type DeadLetters interface {
+	List(ctx context.Context, namespace, webhook, state string, limit int) ([]v1alpha1store.WebhookDeadLetter, error)
+}
+
+// Replayer sends a dead letter again. *webhooks.Dispatcher satisfies it;
+// an unknown dead letter returns pkgdb.ErrNotFound.
+type Replayer interface {
+	Replay(ctx context.Context, namespace, webhook, deliveryID string) (v1alpha1store.WebhookDelivery, error)
+}
+
+// Config bundles the inputs for Register.
+type Config struct {
+	BasePrefix string
+	Store      Store
+	Deliveries Deliveries
+	// DeadLetters and Replayer back the dead-letter endpoints. Nil leaves
+	// them unregistered.
+	DeadLetters DeadLetters
+	Replayer    Replayer
+	// Authorize gates the request the same way the regular Webhook GET
+	// handler does (verb "get"). Payloads name the artifacts published in
+	// the namespace, so wire it from PerKindHooks.Authorizers[KindWebhook].
+	// A replay sends to the webhook's URL, so it is gated like an apply
+	// (verb "apply"). nil means no gate.
+	Authorize func(ctx context.Context, in resource.AuthorizeInput) error
+}
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+type deliveriesInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	Limit     int    `query:"limit" doc:"Max attempts to return (default 50, max 200)."`
+	Before    int64  `query:"before" doc:"Return attempts older than this attempt id (the previous page's next value)."`
+}
+
+type deliveriesOutput struct {
+	Body struct {
+		Deliveries []v1alpha1store.WebhookDelivery `json:"deliveries"`
+		// Next is the before value for the following page; 0 on the last page.
+		Next int64 `json:"next,omitempty"`
+	}
+}
+
+type deadLettersInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name      string `path:"name"`
+	State     string `query:"state" enum:"pending,exhausted,delivered" doc:"Return only dead letters in this state."`
+	Limit     int    `query:"limit" doc:"Max dead letters to return (default 50, max 200)."`
+}
+
+type deadLettersOutput struct {
+	Body struct {
+		DeadLetters []v1alpha1store.WebhookDeadLetter `json:"deadLetters"`
+	}
+}
+
+type replayInput struct {
+	Namespace  string `query:"namespace" doc:"Namespace (defaults to 'default')."`
+	Name       string `path:"name"`
+	DeliveryID string `path:"deliveryId"`
+}
+
+type replayOutput struct {
+	Body v1alpha1store.WebhookDelivery
+}
+
+// Register wires GET {basePrefix}/webhooks/{name}/deliveries and, when
+// cfg.DeadLetters and cfg.Replayer are set, GET
+// {basePrefix}/webhooks/{name}/dead-letters and POST
+// {basePrefix}/webhooks/{name}/dead-letters/{deliveryId}/replay.
+func Register(api huma.API, cfg Config) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-webhook-deliveries",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/webhooks/{name}/deliveries",
+		Summary:     "List a Webhook's delivery attempts, newest first",
+	}, func(ctx context.Context, in *deliveriesInput) (*deliveriesOutput, error) {
+		limit, err := pageLimit(in.Limit)
+		if err != nil {
+			return nil, err
+		}
+		ns, name, err := cfg.webhook(ctx, "get", in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		deliveries, err := cfg.Deliveries.List(ctx, ns, name, in.Before, limit)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list webhook deliveries", err)
+		}
+		out := &deliveriesOutput{}
+		out.Body.Deliveries = deliveries
+		if out.Body.Deliveries == nil {
+			out.Body.Deliveries = []v1alpha1store.WebhookDelivery{}
+		}
+		if len(deliveries) == limit {
+			out.Body.Next = deliveries[len(deliveries)-1].ID
+		}
+		return out, nil
+	})
+
+	if cfg.DeadLetters == nil || cfg.Replayer == nil {
+		return
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-webhook-dead-letters",
+		Method:      http.MethodGet,
+		Path:        cfg.BasePrefix + "/webhooks/{name}/dead-letters",
+		Summary:     "List a Webhook's failed deliveries, newest first",
+	}, func(ctx context.Context, in *deadLettersInput) (*deadLettersOutput, error) {
+		limit, err := pageLimit(in.Limit)
+		if err != nil {
+			return nil, err
+		}
+		ns, name, err := cfg.webhook(ctx, "get", in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		dead, err := cfg.DeadLetters.List(ctx, ns, name, in.State, limit)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list webhook dead letters", err)
+		}
+		out := &deadLettersOutput{}
+		out.Body.DeadLetters = dead
+		if out.Body.DeadLetters == nil {
+			out.Body.DeadLetters = []v1alpha1store.WebhookDeadLetter{}
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "replay-webhook-dead-letter",
+		Method:      http.MethodPost,
+		Path:        cfg.BasePrefix + "/webhooks/{name}/dead-letters/{deliveryId}/replay",
+		Summary:     "Send a failed delivery to its Webhook again",
+		Description: "Sends the dead letter's payload once more, now, to the webhook's current URL and returns the attempt. A successful attempt marks the dead letter delivered; a failed one leaves it as it was.",
+	}, func(ctx context.Context, in *replayInput) (*replayOutput, error) {
+		ns, name, err := cfg.webhook(ctx, "apply", in.Namespace, in.Name)
+		if err != nil {
+			return nil, err
+		}
+		attempt, err := cfg.Replayer.Replay(ctx, ns, name, in.DeliveryID)
+		if errors.Is(err, pkgdb.ErrNotFound) {
+			return nil, huma.Error404NotFound(fmt.Sprintf("dead letter %q of Webhook %q/%q not found", in.DeliveryID, ns, name))
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("replay webhook dead letter", err)
+		}
+		return &replayOutput{Body: attempt}, nil
+	})
+}
+
+// pageLimit validates a limit query value, defaulting zero.
+func pageLimit(limit int) (int, error) {
+	switch {
+	case limit < 0 || limit > maxLimit:
+		return 0, huma.Error400BadRequest(fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+	case limit == 0:
+		return defaultLimit, nil
+	}
+	return limit, nil
+}
+
+// webhook resolves the namespace and name of the request's Webhook,
+// authorizes verb on it and checks it exists.
+func (cfg Config) webhook(ctx context.Context, verb, namespace, rawName string) (string, string, error) {
+	ns := namespace
+	if ns == "" {
+		ns = v1alpha1.DefaultNamespace
+	}
+	name, err := resource.UnescapeName(rawName)
+	if err != nil {
+		return "", "", err
+	}
+	if cfg.Authorize != nil {
+		if err := cfg.Authorize(ctx, resource.AuthorizeInput{
+			Verb: verb, Kind: v1alpha1.KindWebhook,
+			Namespace: ns, Name: name,
+		}); err != nil {
+			return "", "", err
+		}
+	}
+	if _, err := cfg.Store.GetLatest(ctx, ns, name); err != nil {
+		if errors.Is(err, pkgdb.ErrNotFound) {
+			return "", "", huma.Error404NotFound(fmt.Sprintf("Webhook %q/%q not found", ns, name))
+		}
+		return "", "", huma.Error500InternalServerError("fetch Webhook", err)
+	}
+	return ns, name, nil
+}
@@ -0,0 +1,187 @@
+package webhookdeliveries_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/webhookdeliveries"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore map[string]bool
+
+func (f fakeStore) GetLatest(_ context.Context, namespace, name string) (*v1alpha1.RawObject, error) {
+	if !f[namespace+"/"+name] {
+		return nil, pkgdb.ErrNotFound
+	}
+	return &v1alpha1.RawObject{Metadata: v1alpha1.ObjectMeta{Namespace: namespace, Name: name}}, nil
+}
+
+// fakeDeliveries holds attempts with IDs 1..n for default/ci.
+type fakeDeliveries int
+
+func (n fakeDeliveries) List(_ context.Context, namespace, webhook string, before int64, limit int) ([]v1alpha1store.WebhookDelivery, error) {
+	var out []v1alpha1store.WebhookDelivery
+	for id := int64(n); id > 0 && len(out) < limit; id-- {
+		if namespace != "default" || webhook != "ci" || (before > 0 && id >= before) {
+			continue
+		}
+		out = append(out, v1alpha1store.WebhookDelivery{ID: id, Namespace: namespace, Webhook: webhook, Attempt: 1})
+	}
+	return out, nil
+}
+
+type listBody struct {
+	Deliveries []v1alpha1store.WebhookDelivery `json:"deliveries"`
+	Next       int64                           `json:"next"`
+}
+
+func TestListDeliveriesPages(t *testing.T) {
+	_, api := humatest.New(t)
+	webhookdeliveries.Register(api, webhookdeliveries.Config{
+		BasePrefix: "/v0",
+		Store:      fakeStore{"default/ci": true},
+		Deliveries: fakeDeliveries(3),
+	})
+
+	resp := api.Get("/v0/webhooks/ci/deliveries?limit=2")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var page listBody
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &page))
+	require.Len(t, page.Deliveries, 2)
+	require.EqualValues(t, 3, page.Deliveries[0].ID)
+	require.EqualValues(t, 2, page.Next)
+
+	resp = api.Get("/v0/webhooks/ci/deliveries?limit=2&before=2")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	page = listBody{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &page))
+	require.Len(t, page.Deliveries, 1)
+	require.Zero(t, page.Next)
+}
+
+func TestListDeliveriesErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		authorize func(context.Context, resource.AuthorizeInput) error
+		wantCode  int
+	}{
+		{"unknown webhook", "/v0/webhooks/missing/deliveries", nil, http.StatusNotFound},
+		{"other namespace", "/v0/webhooks/ci/deliveries?namespace=team-b", nil, http.StatusNotFound},
+		{"limit too large", "/v0/webhooks/ci/deliveries?limit=500", nil, http.StatusBadRequest},
+		{
+			"authorize denies",
+			"/v0/webhooks/ci/deliveries",
+			func(_ context.Context, in resource.AuthorizeInput) error {
+				require.Equal(t, "get", in.Verb)
+				require.Equal(t, v1alpha1.KindWebhook, in.Kind)
+				require.Equal(t, "ci", in.Name)
+				return huma.Error403Forbidden("denied")
+			},
+			http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, api := humatest.New(t)
+			webhookdeliveries.Register(api, webhookdeliveries.Config{
+				BasePrefix: "/v0",
+				Store:      fakeStore{"default/ci": true},
+				Deliveries: fakeDeliveries(1),
+				Authorize:  tt.authorize,
+			})
+			resp := api.Get(tt.path)
+			require.Equal(t, tt.wantCode, resp.Code, resp.Body.String())
+		})
+	}
+}
+
+// fakeDeadLetters holds one exhausted and one pending dead letter for
+// default/ci.
+type fakeDeadLetters struct{}
+
+func (fakeDeadLetters) List(_ context.Context, namespace, webhook, state string, limit int) ([]v1alpha1store.WebhookDeadLetter, error) {
+	var out []v1alpha1store.WebhookDeadLetter
+	for _, d := range []v1alpha1store.WebhookDeadLetter{
+		{DeliveryID: "d2", Namespace: "default", Webhook: "ci", State: v1alpha1store.WebhookDeadLetterPending},
+		{DeliveryID: "d1", Namespace: "default", Webhook: "ci", State: v1alpha1store.WebhookDeadLetterExhausted},
+	} {
+		if d.Namespace == namespace && d.Webhook == webhook && (state == "" || d.State == state) && len(out) < limit {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+type fakeReplayer struct{}
+
+func (fakeReplayer) Replay(_ context.Context, namespace, webhook, deliveryID string) (v1alpha1store.WebhookDelivery, error) {
+	if deliveryID != "d1" {
+		return v1alpha1store.WebhookDelivery{}, pkgdb.ErrNotFound
+	}
+	return v1alpha1store.WebhookDelivery{
+		ID: 7, DeliveryID: deliveryID, Namespace: namespace, Webhook: webhook,
+		Attempt: 6, StatusCode: http.StatusOK, Status: v1alpha1store.WebhookDeliverySucceeded,
+	}, nil
+}
+
+func TestDeadLetters(t *testing.T) {
+	var verbs []string
+	_, api := humatest.New(t)
+	webhookdeliveries.Register(api, webhookdeliveries.Config{
+		BasePrefix:  "/v0",
+		Store:       fakeStore{"default/ci": true},
+		Deliveries:  fakeDeliveries(0),
+		DeadLetters: fakeDeadLetters{},
+		Replayer:    fakeReplayer{},
+		Authorize: func(_ context.Context, in resource.AuthorizeInput) error {
+			verbs = append(verbs, in.Verb)
+			return nil
+		},
+	})
+
+	resp := api.Get("/v0/webhooks/ci/dead-letters?state=exhausted")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var list struct {
+		DeadLetters []v1alpha1store.WebhookDeadLetter `json:"deadLetters"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Len(t, list.DeadLetters, 1)
+	require.Equal(t, "d1", list.DeadLetters[0].DeliveryID)
+
+	resp = api.Post("/v0/webhooks/ci/dead-letters/d1/replay")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var attempt v1alpha1store.WebhookDelivery
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &attempt))
+	require.Equal(t, 6, attempt.Attempt)
+	require.Equal(t, v1alpha1store.WebhookDeliverySucceeded, attempt.Status)
+	require.Equal(t, []string{"get", "apply"}, verbs)
+
+	resp = api.Post("/v0/webhooks/ci/dead-letters/nope/replay")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	resp = api.Post("/v0/webhooks/missing/dead-letters/d1/replay")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	resp = api.Get("/v0/webhooks/ci/dead-letters?state=lost")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+}
+
+func TestDeadLettersUnregisteredWithoutStore(t *testing.T) {
+	_, api := humatest.New(t)
+	webhookdeliveries.Register(api, webhookdeliveries.Config{
+		BasePrefix: "/v0",
+		Store:      fakeStore{"default/ci": true},
+		Deliveries: fakeDeliveries(0),
+	})
+	resp := api.Get("/v0/webhooks/ci/dead-letters")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
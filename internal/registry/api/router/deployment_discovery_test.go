@@ -40,12 +40,14 @@ func TestDeploymentListFiltersPersistedDiscoveredRows(t *testing.T) {
 		"/v0",
 		stores,
 		nil,
+		nil,
 		crud.PerKindHooks{},
 		nil,
 		nil,
 		nil,
 		nil,
 		nil,
+		nil,
 	)
 
 	all := listDeploymentsForDiscoveryTest(t, api, "/v0/deployments")
@@ -4,6 +4,7 @@ package router
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -13,11 +14,16 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentshares"
+	v0public "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/public"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/config"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/ratelimit"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/telemetry"
 	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	"github.com/agentregistry-dev/agentregistry/pkg/logging"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
 )
 
 // Middleware configuration options
@@ -62,6 +68,7 @@ func MetricTelemetryMiddleware(metrics *telemetry.Metrics, options ...Middleware
 		start := time.Now()
 		method := ctx.Method()
 		routePath := getRoutePath(ctx)
+		namespace := requestNamespace(ctx)
 
 		next(ctx)
 
@@ -73,8 +80,11 @@ func MetricTelemetryMiddleware(metrics *telemetry.Metrics, options ...Middleware
 			attribute.String("method", method),
 			attribute.String("path", routePath),
 			attribute.Int("status_code", statusCode),
+			attribute.String("namespace", namespace),
 		}
 
+		metrics.Usage.Record(namespace, requestCaller(ctx), statusCode)
+
 		// Record metrics
 		metrics.Requests.Add(ctx.Context(), 1, metric.WithAttributes(attrs...))
 
@@ -86,6 +96,36 @@ func MetricTelemetryMiddleware(metrics *telemetry.Metrics, options ...Middleware
 	}
 }
 
+// requestNamespace returns the namespace a request targets for metric
+// attribution. Namespaced routes take it from the ?namespace= query; requests
+// without one act on the default namespace.
+func requestNamespace(ctx huma.Context) string {
+	if ns := strings.TrimSpace(ctx.Query("namespace")); ns != "" {
+		return ns
+	}
+	return v1alpha1.DefaultNamespace
+}
+
+// requestCaller identifies the caller for the usage report: the auth subject
+// when the provider names one, else the first X-Forwarded-For hop when the
+// registry sits behind a proxy, else the connection's remote host.
+func requestCaller(ctx huma.Context) string {
+	if subject := auth.SubjectFrom(ctx.Context()); subject != "" {
+		return subject
+	}
+	if forwarded := ctx.Header("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		if first = strings.TrimSpace(first); first != "" {
+			return first
+		}
+	}
+	remote := ctx.RemoteAddr()
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
+
 // WithSkipPaths allows skipping instrumentation for specific paths
 func WithSkipPaths(paths ...string) MiddlewareOption {
 	return func(c *middlewareConfig) {
@@ -95,6 +135,24 @@ func WithSkipPaths(paths ...string) MiddlewareOption {
 	}
 }
 
+// rateLimitConfig builds the API rate limits from cfg.
+func rateLimitConfig(cfg *config.Config, metrics *telemetry.Metrics) (ratelimit.Config, error) {
+	perIP, err := ratelimit.ParseRate(cfg.RateLimitPerIP)
+	if err != nil {
+		return ratelimit.Config{}, fmt.Errorf("rate limit per IP: %w", err)
+	}
+	perCaller, err := ratelimit.ParseRate(cfg.RateLimitPerCaller)
+	if err != nil {
+		return ratelimit.Config{}, fmt.Errorf("rate limit per caller: %w", err)
+	}
+	return ratelimit.Config{
+		PerIP:             perIP,
+		PerCaller:         perCaller,
+		TrustForwardedFor: cfg.RateLimitTrustForwardedFor,
+		Throttled:         metrics.Throttled,
+	}, nil
+}
+
 // handle404 returns a helpful 404 error with suggestions for common mistakes
 func handle404(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/problem+json")
@@ -129,6 +187,30 @@ func handle404(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+const (
+	// openAPIPath serves the live spec as openAPIPath + .json/.yaml.
+	openAPIPath = "/openapi"
+	schemasPath = "/schemas"
+)
+
+// NewHumaConfig returns the Huma configuration of the registry API. The
+// server serves the spec of the routes it registered at /openapi.json and
+// /openapi.yaml and renders it at /docs, so the spec reflects the
+// optional routes and extensions of that instance; gen-openapi uses the
+// same configuration for the published openapi.yaml.
+func NewHumaConfig(version string) huma.Config {
+	humaConfig := huma.DefaultConfig("AgentRegistry", version)
+	humaConfig.Info.Description = "AgentRegistry API for managing MCP servers, agents, skills, and deployments."
+	humaConfig.OpenAPIPath = openAPIPath
+	humaConfig.DocsPath = "/docs"
+	humaConfig.SchemasPath = schemasPath
+	// Disable $schema property in responses: https://github.com/danielgtaylor/huma/issues/230
+	humaConfig.CreateHooks = []func(huma.Config) huma.Config{}
+	// ?fields= on list and get routes trims bodies before serialization.
+	humaConfig.Transformers = append(humaConfig.Transformers, resource.SelectFields)
+	return humaConfig
+}
+
 // NewHumaAPI creates a new Huma API with all routes registered.
 // Returns an error when RegisterRoutes rejects the supplied
 // RouteOptions (e.g. Stores missing).
@@ -143,21 +225,30 @@ func NewHumaAPI(
 	authnProvider auth.AuthnProvider,
 	routeOpts *RouteOptions,
 ) (huma.API, error) {
-	// Create Huma API configuration
-	humaConfig := huma.DefaultConfig("Official MCP Registry", "1.0.0")
-	humaConfig.Info.Description = "A community driven registry service for Model Context Protocol (MCP) servers.\n\n[GitHub repository](https://github.com/modelcontextprotocol/registry) | [Documentation](https://github.com/modelcontextprotocol/registry/tree/main/docs)"
-	// Disable $schema property in responses: https://github.com/danielgtaylor/huma/issues/230
-	humaConfig.CreateHooks = []func(huma.Config) huma.Config{}
-
+	apiVersion := "dev"
+	if versionInfo != nil && versionInfo.Version != "" {
+		apiVersion = versionInfo.Version
+	}
 	// Create a new API using humago adapter for standard library
-	api := humago.New(mux, humaConfig)
+	api := humago.New(mux, NewHumaConfig(apiVersion))
 
 	// Add authn middleware if configured
 	if authnProvider != nil {
-		api.UseMiddleware(auth.AuthnMiddleware(authnProvider,
+		authnOpts := []auth.MiddlewareOption{
 			// don't authenticate on public paths
-			auth.WithSkipPaths("/health", "/metrics", "/ping", "/docs", "/version")),
-		)
+			auth.WithSkipPaths("/health", "/metrics", "/ping", "/docs", "/version"),
+			// The spec the docs page renders, so client generators can
+			// fetch it without a token.
+			auth.WithSkipPathPrefixes(openAPIPath, schemasPath+"/"),
+		}
+		if cfg.PublicMirrorEnabled {
+			authnOpts = append(authnOpts, auth.WithSkipPathPrefixes("/v0"+v0public.PathSegment+"/"))
+		}
+		// Share link routes authenticate by the token in their path.
+		if routeOpts != nil && routeOpts.DeploymentShares != nil {
+			authnOpts = append(authnOpts, auth.WithSkipPathPrefixes("/v0"+deploymentshares.PathSegment+"/"))
+		}
+		api.UseMiddleware(auth.AuthnMiddleware(authnProvider, authnOpts...))
 	}
 
 	// Add OpenAPI tag metadata with descriptions
@@ -202,9 +293,19 @@ func NewHumaAPI(
 
 	// Add metrics middleware with options
 	api.UseMiddleware(MetricTelemetryMiddleware(metrics,
-		WithSkipPaths("/health", "/metrics", "/ping", "/docs", "/logging"),
+		WithSkipPaths("/health", "/metrics", "/ping", "/docs", "/logging", "/openapi.json", "/openapi.yaml"),
 	))
 
+	// Rate limits run after authn, which names the caller, and after the
+	// metrics middleware, so throttled requests are counted as 429s.
+	limits, err := rateLimitConfig(cfg, metrics)
+	if err != nil {
+		return nil, err
+	}
+	if limits.Enabled() {
+		api.UseMiddleware(ratelimit.Middleware(api, limits))
+	}
+
 	// Register all API routes under /v0
 	if err := RegisterRoutes(api, cfg, metrics, versionInfo, routeOpts); err != nil {
 		return nil, err
@@ -227,7 +328,9 @@ func NewHumaAPI(
 				r.URL.Path == "/ping" ||
 				r.URL.Path == "/metrics" ||
 				r.URL.Path == "/logging" ||
-				strings.HasPrefix(r.URL.Path, "/docs") {
+				strings.HasPrefix(r.URL.Path, "/docs") ||
+				strings.HasPrefix(r.URL.Path, openAPIPath) ||
+				strings.HasPrefix(r.URL.Path, schemasPath+"/") {
 				handle404(w, r)
 				return
 			}
@@ -0,0 +1,65 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/config"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/telemetry"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// denyAll rejects every request it is asked to authenticate.
+type denyAll struct{}
+
+func (denyAll) Authenticate(context.Context, func(string) string, url.Values) (auth.Session, error) {
+	return nil, errors.New("no credentials")
+}
+
+func TestNewHumaAPI_ServesLiveSpec(t *testing.T) {
+	metrics, err := telemetry.NewMetrics(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+	cfg := &config.Config{JWTPrivateKey: "0000000000000000000000000000000000000000000000000000000000000000"}
+	mux := http.NewServeMux()
+	_, err = NewHumaAPI(cfg, mux, metrics, &arv0.VersionBody{Version: "1.2.3"}, nil, denyAll{}, &RouteOptions{
+		Stores: v1alpha1store.NewStores(nil, pkgdb.OSSSchemaRegistry()),
+	})
+	require.NoError(t, err)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/openapi.json")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var spec struct {
+		Info struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	require.Equal(t, "AgentRegistry", spec.Info.Title)
+	require.Equal(t, "1.2.3", spec.Info.Version)
+	require.Contains(t, spec.Paths, "/v0/agents")
+	require.NotContains(t, spec.Paths, "/v0.1/servers", "disabled optional routes are not in the spec")
+
+	require.Equal(t, http.StatusOK, get("/openapi.yaml").Code)
+	require.Equal(t, http.StatusOK, get("/docs").Code)
+	require.Equal(t, http.StatusUnauthorized, get("/v0/agents").Code)
+	require.Equal(t, http.StatusUnauthorized, get("/v0/agents/openapi.json").Code, "only the top-level spec is public")
+}
@@ -7,18 +7,56 @@ import (
 
 	"github.com/danielgtaylor/huma/v2"
 
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
 	mcpregistrycompat "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/mcpregistry"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/agentcard"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/apikeys"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/artifactstats"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/backstage"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/bundle"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/capabilitydiff"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/crud"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentdryrun"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentevents"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentlogs"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentmanifests"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentnotes"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymentshares"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/deploymenttraffic"
+	v0embeddings "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/embeddings"
+	v0export "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/export"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/flags"
+	v0gc "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/gc"
 	v0health "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/health"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/icons"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/namespaces"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/outdated"
 	v0ping "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/ping"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/promptevals"
+	v0public "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/public"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/readmes"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/reconcileplan"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/reservednames"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/runtimegrants"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/runtimesecrets"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/search"
+	v0security "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/security"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/serverinstall"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/servertools"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/snapshots"
+	v0usage "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/usage"
 	v0version "github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/version"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/webhookdeliveries"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/config"
 	internaldb "github.com/agentregistry-dev/agentregistry/internal/registry/database"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/embeddings"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/mcptraffic"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/telemetry"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/usagestats"
 	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1/registries"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
 	"github.com/agentregistry-dev/agentregistry/pkg/types"
@@ -52,6 +90,134 @@ type RouteOptions struct {
 	// CRUD hook wiring.
 	DeploymentLogResolver deploymentlogs.LogResolver
 
+	// DeploymentLogStore serves the logs log shipping retained. Nil reads
+	// Deployment logs from the runtime adapter alone.
+	DeploymentLogStore deploymentlogs.LogStore
+
+	// VersionGC backs the admin version garbage collection endpoint. Nil
+	// leaves POST /v0/admin/gc unregistered.
+	VersionGC v0gc.Collector
+
+	// Snapshots and SnapshotTaker back the catalog snapshot endpoints.
+	// Nil leaves /v0/snapshots unregistered.
+	Snapshots     snapshots.Store
+	SnapshotTaker snapshots.Taker
+
+	// ReconcilePlanner backs the admin dry-run reconcile endpoint. Nil
+	// leaves POST /v0/admin/reconcile:plan unregistered.
+	ReconcilePlanner reconcileplan.Planner
+
+	// WebhookDeliveries backs the Webhook delivery-log subresource. Nil
+	// leaves GET /v0/webhooks/{name}/deliveries unregistered.
+	WebhookDeliveries webhookdeliveries.Deliveries
+	// WebhookDeadLetters and WebhookReplayer back the dead-letter
+	// subresource. Nil leaves /v0/webhooks/{name}/dead-letters unregistered.
+	WebhookDeadLetters webhookdeliveries.DeadLetters
+	WebhookReplayer    webhookdeliveries.Replayer
+
+	// Namespaces backs the namespace ownership endpoints. Nil leaves
+	// /v0/namespaces unregistered.
+	Namespaces namespaces.Store
+
+	// APIKeys backs the API key endpoints. Nil leaves /v0/apikeys
+	// unregistered.
+	APIKeys apikeys.Store
+
+	// ReservedPrefixes backs the reserved name prefix endpoints. Nil
+	// leaves /v0/reserved-prefixes and /v0/reserved-prefix-requests
+	// unregistered.
+	ReservedPrefixes reservednames.Store
+	// ReservedNamePrefixes are the prefixes the server configuration
+	// reserves, listed alongside ReservedPrefixes.
+	ReservedNamePrefixes []string
+
+	// RuntimeGrants backs the Runtime deploy grant endpoints. Nil leaves
+	// /v0/runtime-grants unregistered.
+	RuntimeGrants runtimegrants.Store
+
+	// DeploymentManifests backs the Deployment manifests subresource. Nil
+	// leaves GET /v0/deployments/{name}/manifests unregistered.
+	DeploymentManifests deploymentmanifests.Store
+
+	// DeploymentNotes backs the Deployment notes subresource. Nil leaves
+	// GET/PATCH /v0/deployments/{name}/notes unregistered.
+	DeploymentNotes deploymentnotes.Store
+
+	// DeploymentShares backs Deployment share links. Nil leaves
+	// /v0/deployments/{name}/share, .../shares and /v0/shared/{token}
+	// unregistered.
+	DeploymentShares deploymentshares.Store
+	// DeploymentShareAuditor receives share link creations and
+	// revocations when it implements types.ShareAuditor.
+	DeploymentShareAuditor types.Auditor
+
+	// MCPTraffic records the MCP traffic of Deployments in traffic debug
+	// mode. Nil, or a nil DeploymentMCPEndpoints, leaves
+	// POST /v0/deployments/{name}/mcp and GET .../traffic unregistered.
+	MCPTraffic *mcptraffic.Recorder
+	// DeploymentMCPEndpoints finds the gateway endpoint a Deployment's
+	// MCP traffic is relayed to.
+	DeploymentMCPEndpoints deploymenttraffic.EndpointResolver
+
+	// PromptEvaluations backs the Prompt score history. Nil leaves
+	// /v0/prompts/{name}/evaluations unregistered.
+	PromptEvaluations promptevals.Store
+	// PromptEvaluator scores Prompt tags on demand. Nil, when no
+	// evaluation model is configured, leaves
+	// POST /v0/prompts/{name}/{tag}/evaluations answering 501.
+	PromptEvaluator promptevals.Runner
+
+	// RuntimeSecrets seals credentials stored through the Runtime secrets
+	// subresource. Nil leaves PUT /v0/runtimes/{name}/secrets/{key}
+	// unregistered.
+	RuntimeSecrets runtimesecrets.Sealer
+
+	// Readmes backs the artifact README subresource of Agents, MCPServers,
+	// Skills and Prompts. Nil leaves PUT/GET /v0/{plural}/{name}/{tag}/readme
+	// unregistered.
+	Readmes readmes.Store
+
+	// Icons backs the icon subresource of MCPServers, Agents and Skills.
+	// Nil leaves PUT/GET/DELETE /v0/{plural}/{name}/icon unregistered.
+	Icons icons.Store
+
+	// ServerTools backs the MCPServer tools subresource and matches
+	// servers by their tools in GET /v0/search. Nil leaves
+	// GET /v0/mcpservers/{name}/{tag}/tools unregistered and searches
+	// servers without their tools.
+	ServerTools *v1alpha1store.ServerToolStore
+
+	// SemanticSearch adds a semantic ranking to GET /v0/search. Nil ranks
+	// by full text and name match only.
+	SemanticSearch embeddings.Ranker
+
+	// EmbeddingIndex is the index behind SemanticSearch. Nil leaves
+	// POST /v0/admin/embeddings:reindex unregistered.
+	EmbeddingIndex embeddings.Index
+
+	// DeploymentRenderer backs the Deployment dry run. Nil leaves
+	// POST /v0/deployments:dryRun unregistered.
+	DeploymentRenderer deploymentdryrun.Renderer
+
+	// Usage counts artifact downloads and MCP registry search hits and
+	// backs the list `usage` map, `?sort=popularity` and the Agent /
+	// MCPServer / Skill stats subresource. Nil disables all four and
+	// leaves GET /v0/{plural}/{name}/stats unregistered.
+	Usage *usagestats.Recorder
+
+	// VulnerabilityNotifier receives the namespaces flagged when a version
+	// is marked vulnerable. Nil skips notifications.
+	VulnerabilityNotifier v0security.Notifier
+
+	// IsRegistryAdmin gates admin-scope endpoints that have no
+	// per-resource authz (e.g. the reconcile plan). Nil denies.
+	IsRegistryAdmin func(ctx context.Context) bool
+
+	// CheckPermission asks the authz provider whether the caller may take
+	// verb on res, for endpoints that grant permissions onward (API key
+	// creation). Nil allows.
+	CheckPermission func(ctx context.Context, verb auth.PermissionAction, res auth.Resource) error
+
 	// PerKindHooks injects per-kind Authorize + ListFilter
 	// callbacks into the generic resource handler. Downstream integrations
 	// thread their RBAC engine through here so reader / publisher /
@@ -126,14 +292,281 @@ func RegisterRoutes(
 		pathPrefix,
 		opts.Stores,
 		opts.DeploymentLogResolver,
+		opts.DeploymentLogStore,
 		opts.PerKindHooks,
 		opts.RegistryValidator,
 		opts.Admission,
 		opts.DeleteAdmission,
+		opts.Usage,
 		opts.ResolverWrapper,
 		opts.ExtraResourceRoutes,
 	)
 
+	if opts.VersionGC != nil {
+		v0gc.Register(api, v0gc.Config{
+			BasePrefix: pathPrefix,
+			Collector:  opts.VersionGC,
+			IsAdmin:    opts.IsRegistryAdmin,
+		})
+	}
+
+	if opts.Snapshots != nil && opts.SnapshotTaker != nil {
+		snapshots.Register(api, snapshots.Config{
+			BasePrefix: pathPrefix,
+			Taker:      opts.SnapshotTaker,
+			Store:      opts.Snapshots,
+			IsAdmin:    opts.IsRegistryAdmin,
+		})
+	}
+
+	if opts.ReconcilePlanner != nil {
+		reconcileplan.Register(api, reconcileplan.Config{
+			BasePrefix: pathPrefix,
+			Planner:    opts.ReconcilePlanner,
+			IsAdmin:    opts.IsRegistryAdmin,
+		})
+	}
+
+	if store := opts.Stores[v1alpha1.KindWebhook]; store != nil && opts.WebhookDeliveries != nil {
+		webhookdeliveries.Register(api, webhookdeliveries.Config{
+			BasePrefix:  pathPrefix,
+			Store:       store,
+			Deliveries:  opts.WebhookDeliveries,
+			DeadLetters: opts.WebhookDeadLetters,
+			Replayer:    opts.WebhookReplayer,
+			Authorize:   opts.PerKindHooks.Authorizers[v1alpha1.KindWebhook],
+		})
+	}
+
+	if opts.DeploymentManifests != nil {
+		deploymentmanifests.Register(api, deploymentmanifests.Config{
+			BasePrefix: pathPrefix,
+			Store:      opts.DeploymentManifests,
+			Authorize:  opts.PerKindHooks.Authorizers[v1alpha1.KindDeployment],
+		})
+	}
+
+	if store := opts.Stores[v1alpha1.KindDeployment]; opts.DeploymentNotes != nil && store != nil {
+		deploymentnotes.Register(api, deploymentnotes.Config{
+			BasePrefix:  pathPrefix,
+			Deployments: store,
+			Store:       opts.DeploymentNotes,
+			Authorize:   opts.PerKindHooks.Authorizers[v1alpha1.KindDeployment],
+		})
+	}
+
+	if store := opts.Stores[v1alpha1.KindDeployment]; opts.DeploymentShares != nil && store != nil {
+		deploymentshares.Register(api, deploymentshares.Config{
+			BasePrefix:  pathPrefix,
+			Deployments: store,
+			Store:       opts.DeploymentShares,
+			LogResolver: opts.DeploymentLogResolver,
+			Client:      httpclient.New(deploymentshares.ChatTimeout),
+			Auditor:     opts.DeploymentShareAuditor,
+			Authorize:   opts.PerKindHooks.Authorizers[v1alpha1.KindDeployment],
+		})
+	}
+
+	if store := opts.Stores[v1alpha1.KindDeployment]; opts.MCPTraffic != nil && opts.DeploymentMCPEndpoints != nil && store != nil {
+		deploymenttraffic.Register(api, deploymenttraffic.Config{
+			BasePrefix:  pathPrefix,
+			Deployments: store,
+			Endpoints:   opts.DeploymentMCPEndpoints,
+			Recorder:    opts.MCPTraffic,
+			Client:      httpclient.New(deploymenttraffic.RelayTimeout),
+			Authorize:   opts.PerKindHooks.Authorizers[v1alpha1.KindDeployment],
+		})
+	}
+
+	if opts.PromptEvaluations != nil && opts.Stores[v1alpha1.KindPrompt] != nil {
+		promptevals.Register(api, promptevals.Config{
+			BasePrefix: pathPrefix,
+			Store:      opts.PromptEvaluations,
+			Runner:     opts.PromptEvaluator,
+			Authorize:  opts.PerKindHooks.Authorizers[v1alpha1.KindPrompt],
+		})
+	}
+
+	if store := opts.Stores[v1alpha1.KindRuntime]; opts.RuntimeSecrets != nil && store != nil {
+		runtimesecrets.Register(api, runtimesecrets.Config{
+			BasePrefix: pathPrefix,
+			Store:      store,
+			Sealer:     opts.RuntimeSecrets,
+			PostUpsert: opts.PerKindHooks.PostUpserts[v1alpha1.KindRuntime],
+			Authorize:  opts.PerKindHooks.Authorizers[v1alpha1.KindRuntime],
+		})
+	}
+
+	if opts.ServerTools != nil && opts.Stores[v1alpha1.KindMCPServer] != nil {
+		servertools.Register(api, servertools.Config{
+			BasePrefix: pathPrefix,
+			Store:      opts.ServerTools,
+			Authorize:  opts.PerKindHooks.Authorizers[v1alpha1.KindMCPServer],
+		})
+	}
+
+	if opts.Readmes != nil {
+		for _, kind := range []string{v1alpha1.KindAgent, v1alpha1.KindMCPServer, v1alpha1.KindSkill, v1alpha1.KindPrompt} {
+			if store := opts.Stores[kind]; store != nil {
+				readmes.Register(api, readmes.Config{
+					BasePrefix: pathPrefix,
+					Kind:       kind,
+					Artifacts:  store,
+					Store:      opts.Readmes,
+					Authorize:  opts.PerKindHooks.Authorizers[kind],
+				})
+			}
+		}
+	}
+
+	if opts.Icons != nil {
+		for _, kind := range icons.Kinds {
+			if store := opts.Stores[kind]; store != nil {
+				icons.Register(api, icons.Config{
+					BasePrefix: pathPrefix,
+					Kind:       kind,
+					Artifacts:  store,
+					Store:      opts.Icons,
+					Authorize:  opts.PerKindHooks.Authorizers[kind],
+				})
+			}
+		}
+	}
+
+	searchCfg := search.Config{
+		BasePrefix:  pathPrefix,
+		Stores:      map[string]search.Store{},
+		ListFilters: opts.PerKindHooks.ListFilters,
+		Semantic:    opts.SemanticSearch,
+	}
+	for _, kind := range search.Types {
+		if store := opts.Stores[kind]; store != nil {
+			searchCfg.Stores[kind] = store
+		}
+	}
+	// A nil *Recorder must not reach search as a non-nil interface.
+	if opts.Usage != nil {
+		searchCfg.Usage = opts.Usage
+	}
+	if opts.ServerTools != nil {
+		searchCfg.Tools = opts.ServerTools
+	}
+	search.Register(api, searchCfg)
+
+	backstageCfg := backstage.Config{
+		BasePrefix:  pathPrefix,
+		Stores:      map[string]backstage.Store{},
+		ListFilters: opts.PerKindHooks.ListFilters,
+	}
+	for _, kind := range backstage.Kinds {
+		if store := opts.Stores[kind]; store != nil {
+			backstageCfg.Stores[kind] = store
+		}
+	}
+	backstage.Register(api, backstageCfg)
+
+	if opts.EmbeddingIndex != nil {
+		embeddingsCfg := v0embeddings.Config{
+			BasePrefix: pathPrefix,
+			Stores:     map[string]embeddings.Lister{},
+			Index:      opts.EmbeddingIndex,
+			IsAdmin:    opts.IsRegistryAdmin,
+		}
+		for _, kind := range search.Types {
+			if store := opts.Stores[kind]; store != nil {
+				embeddingsCfg.Stores[kind] = store
+			}
+		}
+		v0embeddings.Register(api, embeddingsCfg)
+	}
+
+	if opts.DeploymentRenderer != nil {
+		deploymentdryrun.Register(api, deploymentdryrun.Config{
+			BasePrefix: pathPrefix,
+			Renderer:   opts.DeploymentRenderer,
+			Authorize:  opts.PerKindHooks.Authorizers[v1alpha1.KindDeployment],
+		})
+	}
+
+	if opts.Namespaces != nil {
+		namespaces.Register(api, namespaces.Config{
+			BasePrefix: pathPrefix,
+			Store:      opts.Namespaces,
+			IsAdmin:    opts.IsRegistryAdmin,
+		})
+	}
+
+	if opts.APIKeys != nil {
+		apikeys.Register(api, apikeys.Config{
+			BasePrefix: pathPrefix,
+			Store:      opts.APIKeys,
+			IsAdmin:    opts.IsRegistryAdmin,
+			Check:      opts.CheckPermission,
+		})
+	}
+
+	if opts.ReservedPrefixes != nil {
+		reservednames.Register(api, reservednames.Config{
+			BasePrefix: pathPrefix,
+			Store:      opts.ReservedPrefixes,
+			Configured: opts.ReservedNamePrefixes,
+			IsAdmin:    opts.IsRegistryAdmin,
+		})
+	}
+
+	if opts.RuntimeGrants != nil {
+		runtimegrants.Register(api, runtimegrants.Config{
+			BasePrefix: pathPrefix,
+			Store:      opts.RuntimeGrants,
+			IsAdmin:    opts.IsRegistryAdmin,
+		})
+	}
+
+	exportStores := make(map[string]v0export.Store, len(opts.Stores))
+	for kind, store := range opts.Stores {
+		exportStores[kind] = store
+	}
+	v0export.Register(api, v0export.Config{
+		BasePrefix: pathPrefix,
+		Stores:     exportStores,
+		IsAdmin:    opts.IsRegistryAdmin,
+	})
+
+	// Vulnerability propagation: impact report plus the admin mark/clear
+	// endpoints scanners call.
+	securityStores := make(map[string]v0security.Store, len(opts.Stores))
+	for kind, store := range opts.Stores {
+		securityStores[kind] = store
+	}
+	v0security.Register(api, v0security.Config{
+		BasePrefix:  pathPrefix,
+		Stores:      securityStores,
+		Authorizers: opts.PerKindHooks.Authorizers,
+		IsAdmin:     opts.IsRegistryAdmin,
+		Notifier:    opts.VulnerabilityNotifier,
+	})
+
+	usageCfg := v0usage.Config{BasePrefix: pathPrefix, IsAdmin: opts.IsRegistryAdmin}
+	if metrics != nil && metrics.Usage != nil {
+		usageCfg.Source = metrics.Usage
+	}
+	v0usage.Register(api, usageCfg)
+
+	// Read-only cacheable mirror of one namespace for CDN fronting. Anonymous
+	// by design: NewHumaAPI adds its prefix to the authn skip list.
+	if cfg.PublicMirrorEnabled {
+		mirrorStores := make(map[string]v0public.Store, len(opts.Stores))
+		for kind, store := range opts.Stores {
+			mirrorStores[kind] = store
+		}
+		v0public.Register(api, v0public.Config{
+			BasePrefix: pathPrefix,
+			Stores:     mirrorStores,
+			Namespace:  cfg.PublicMirrorNamespace,
+			MaxAge:     cfg.PublicMirrorMaxAge,
+		})
+	}
+
 	if opts.ExtraRoutes != nil {
 		opts.ExtraRoutes(api, pathPrefix)
 	}
@@ -153,12 +586,16 @@ func RegisterRoutes(
 			// an authn provider is configured, the middleware must run so the
 			// caller's session reaches ListFilter/Authorize for per-caller
 			// scoping. OSS configures no authn provider, so they're anonymous.
-			mcpregistrycompat.Register(api, mcpregistrycompat.Config{
+			compatCfg := mcpregistrycompat.Config{
 				PathPrefix: cfg.MCPRegistryCompatPathPrefix,
 				Store:      store,
 				ListFilter: opts.PerKindHooks.ListFilters[v1alpha1.KindMCPServer],
 				Authorize:  opts.PerKindHooks.Authorizers[v1alpha1.KindMCPServer],
-			})
+			}
+			if opts.Usage != nil {
+				compatCfg.Usage = opts.Usage
+			}
+			mcpregistrycompat.Register(api, compatCfg)
 		}
 	}
 	return nil
@@ -177,10 +614,12 @@ func registerKindRoutes(
 	basePrefix string,
 	stores Stores,
 	logResolver deploymentlogs.LogResolver,
+	logStore deploymentlogs.LogStore,
 	perKind crud.PerKindHooks,
 	registryValidator v1alpha1.RegistryValidatorFunc,
 	admission types.Admission,
 	deleteAdmission types.DeleteAdmission,
+	usage *usagestats.Recorder,
 	resolverWrapper func(v1alpha1.ResolverFunc) v1alpha1.ResolverFunc,
 	extraResourceRoutes func(api huma.API, pathPrefix string, ctx types.ResourceRouteContext),
 ) resource.ApplyConfig {
@@ -193,7 +632,27 @@ func registerKindRoutes(
 	}
 	// Per-kind CRUD endpoints — one call per built-in kind, hidden
 	// inside crud.Register.
-	crud.Register(api, basePrefix, stores, resolver, registryValidator, perKind, deleteAdmission)
+	// A nil *Recorder must not reach crud as a non-nil interface.
+	var usageTracker resource.UsageTracker
+	if usage != nil {
+		usageTracker = usage
+	}
+	crud.Register(api, basePrefix, stores, resolver, registryValidator, perKind, deleteAdmission, usageTracker)
+
+	// Usage counters of the tracked artifact kinds.
+	if usage != nil {
+		for _, kind := range []string{v1alpha1.KindAgent, v1alpha1.KindMCPServer, v1alpha1.KindSkill} {
+			if store, ok := stores[kind]; ok {
+				artifactstats.Register(api, artifactstats.Config{
+					BasePrefix: basePrefix,
+					Kind:       kind,
+					Store:      store,
+					Usage:      usage,
+					Authorize:  perKind.Authorizers[kind],
+				})
+			}
+		}
+	}
 
 	// Deployment-specific endpoints: logs stream (cancel is subsumed
 	// by DesiredState=undeployed + DELETE in the v1alpha1 lifecycle).
@@ -202,10 +661,76 @@ func registerKindRoutes(
 			BasePrefix:  basePrefix,
 			Store:       stores[v1alpha1.KindDeployment],
 			LogResolver: logResolver,
+			Retained:    logStore,
 			Authorize:   perKind.Authorizers[v1alpha1.KindDeployment],
 		})
 	}
 
+	// Progress stream for `arctl apply --watch`.
+	if store, ok := stores[v1alpha1.KindDeployment]; ok {
+		deploymentevents.Register(api, deploymentevents.Config{
+			BasePrefix: basePrefix,
+			Store:      store,
+			Authorize:  perKind.Authorizers[v1alpha1.KindDeployment],
+		})
+	}
+
+	// Upgrade planning: deployments whose pinned artifacts have newer or
+	// deprecated versions.
+	if _, ok := stores[v1alpha1.KindDeployment]; ok {
+		outdatedStores := make(map[string]outdated.Store, len(stores))
+		for kind, store := range stores {
+			outdatedStores[kind] = store
+		}
+		outdated.Register(api, outdated.Config{
+			BasePrefix: basePrefix,
+			Stores:     outdatedStores,
+			Authorize:  perKind.Authorizers[v1alpha1.KindDeployment],
+			ListFilter: perKind.ListFilters[v1alpha1.KindDeployment],
+		})
+	}
+
+	// Feature flag evaluation for deployed agents.
+	if store, ok := stores[v1alpha1.KindFeatureFlag]; ok {
+		flags.Register(api, flags.Config{
+			BasePrefix: basePrefix,
+			Store:      store,
+			Authorize:  perKind.Authorizers[v1alpha1.KindFeatureFlag],
+			ListFilter: perKind.ListFilters[v1alpha1.KindFeatureFlag],
+		})
+	}
+
+	// MCPServer archive: one tagged version packaged as an OCI artifact.
+	if store, ok := stores[v1alpha1.KindMCPServer]; ok {
+		bundle.Register(api, bundle.Config{
+			BasePrefix: basePrefix,
+			Store:      store,
+			Authorize:  perKind.Authorizers[v1alpha1.KindMCPServer],
+		})
+		capabilitydiff.Register(api, capabilitydiff.Config{
+			BasePrefix: basePrefix,
+			Store:      store,
+			Authorize:  perKind.Authorizers[v1alpha1.KindMCPServer],
+		})
+		serverinstall.Register(api, serverinstall.Config{
+			BasePrefix: basePrefix,
+			Store:      store,
+			Authorize:  perKind.Authorizers[v1alpha1.KindMCPServer],
+		})
+	}
+
+	// A2A agent cards of deployed agents.
+	agents, hasAgents := stores[v1alpha1.KindAgent]
+	deployments, hasDeployments := stores[v1alpha1.KindDeployment]
+	if hasAgents && hasDeployments {
+		agentcard.Register(api, agentcard.Config{
+			BasePrefix:  basePrefix,
+			Agents:      agents,
+			Deployments: deployments,
+			Authorizers: perKind.Authorizers,
+		})
+	}
+
 	// Multi-doc YAML batch apply at POST {basePrefix}/apply shares the
 	// same per-kind hook table populated above, so Deployment reconciliation
 	// and any caller-supplied PostUpsert/PostDelete fire identically on
@@ -13,9 +13,12 @@ import (
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/rs/cors"
+	"go.opentelemetry.io/otel"
 
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/api/router"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/config"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/shadow"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/telemetry"
 	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
@@ -190,18 +193,34 @@ func NewServer(
 			http.MethodGet,
 			http.MethodPost,
 			http.MethodPut,
+			http.MethodPatch,
 			http.MethodDelete,
 			http.MethodOptions,
 		},
 		AllowedHeaders:   []string{"*"},
-		ExposedHeaders:   []string{"Content-Type", "Content-Length"},
+		ExposedHeaders:   []string{"Content-Type", "Content-Length", "ETag"},
 		AllowCredentials: false, // Must be false when AllowedOrigins is "*"
 		MaxAge:           86400, // 24 hours
 	})
 
 	// Wrap the mux with middleware stack
-	// Order: TrailingSlash -> CORS -> Mux
-	handler := TrailingSlashMiddleware(corsHandler.Handler(mux))
+	// Order: TrailingSlash -> Shadow (when configured) -> CORS -> Mux
+	handler := corsHandler.Handler(mux)
+	if cfg.ShadowURL != "" {
+		mirror, err := shadow.New(cfg.ShadowURL, cfg.ShadowPercent,
+			httpclient.New(cfg.ShadowTimeout), otel.Meter(telemetry.Namespace))
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("shadow mode enabled", "target", cfg.ShadowURL, "percent", cfg.ShadowPercent)
+		handler = mirror.Middleware(handler)
+	}
+	handler = TrailingSlashMiddleware(handler)
+
+	tlsConfig, err := ServerTLSConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("configure server TLS: %w", err)
+	}
 
 	server := &Server{
 		config:  cfg,
@@ -211,6 +230,7 @@ func NewServer(
 			Addr:              cfg.ServerAddress,
 			Handler:           handler,
 			ReadHeaderTimeout: 10 * time.Second,
+			TLSConfig:         tlsConfig,
 		},
 	}
 
@@ -218,9 +238,18 @@ func NewServer(
 }
 
 func (s *Server) Start() error {
-	slog.Info("HTTP server starting", "address", s.config.ServerAddress)
-	slog.Info("web UI available", "url", fmt.Sprintf("http://localhost%s/", s.config.ServerAddress))
-	slog.Info("API documentation available", "url", fmt.Sprintf("http://localhost%s/docs", s.config.ServerAddress))
+	scheme := "http"
+	if s.server.TLSConfig != nil {
+		scheme = "https"
+	}
+	slog.Info("HTTP server starting", "address", s.config.ServerAddress, "tls", s.server.TLSConfig != nil)
+	slog.Info("web UI available", "url", fmt.Sprintf("%s://localhost%s/", scheme, s.config.ServerAddress))
+	slog.Info("API documentation available", "url", fmt.Sprintf("%s://localhost%s/docs", scheme, s.config.ServerAddress))
+	slog.Info("OpenAPI spec available", "url", fmt.Sprintf("%s://localhost%s/openapi.json", scheme, s.config.ServerAddress))
+	if s.server.TLSConfig != nil {
+		// Certificates are already loaded into TLSConfig.
+		return s.server.ListenAndServeTLS("", "")
+	}
 	return s.server.ListenAndServe()
 }
 
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/config"
+)
+
+// ServerTLSConfig builds the listener TLS config from cfg. It returns
+// (nil, nil) when TLS is not configured so callers fall back to plain HTTP.
+// Setting TLSClientCAFile turns on mTLS: every client must present a
+// certificate signed by that bundle.
+func ServerTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	if cfg == nil || (cfg.TLSCertFile == "" && cfg.TLSKeyFile == "" && cfg.TLSClientCAFile == "") {
+		return nil, nil
+	}
+	if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+		return nil, errors.New("TLS certificate and key files must both be set")
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if cfg.TLSClientCAFile != "" {
+		pool, err := httpclient.LoadCertPool(cfg.TLSClientCAFile, false)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
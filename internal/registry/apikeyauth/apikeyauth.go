@@ -0,0 +1,446 @@
+// Package apikeyauth authenticates and scopes API keys: long-lived
+// credentials for automation such as CI pipelines, created through
+// /v0/apikeys. A key acts on behalf of its owner but only within its
+// scopes: artifact kinds, name prefixes and actions (read, push, deploy).
+//
+// The Authenticator accepts `Authorization: Bearer arreg_…` tokens and
+// defers every other credential to the wrapped provider. Scopes are
+// enforced twice: Authorize wraps the per-kind resource authorizers, so
+// the dedicated routes and /v0/apply see them, and Authz wraps the
+// AuthzProvider, so a key is never a registry admin and Check honors the
+// same scopes.
+//
+// A key never does more than its owner: CheckGrant refuses to mint scopes
+// the owner does not hold, and Check asks the wrapped provider about the
+// owner as well as the key's scopes. The owner's permissions are recorded
+// on the key when it is created, for providers that read permissions from
+// the session rather than resolving them by subject.
+package apikeyauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// TokenPrefix starts every API key token, so the Authenticator can tell
+// keys from JWTs and secret scanners can spot leaked keys.
+const TokenPrefix = "arreg_"
+
+// Key actions.
+const (
+	// ActionRead gets and lists artifacts and Deployments.
+	ActionRead = "read"
+	// ActionPush applies and deletes artifacts.
+	ActionPush = "push"
+	// ActionDeploy applies and deletes Deployments of artifacts in scope.
+	ActionDeploy = "deploy"
+)
+
+// touchInterval throttles last-used bookkeeping to one write per key per
+// interval.
+const touchInterval = time.Minute
+
+// Actions returns the actions a key can be scoped to.
+func Actions() []string {
+	return []string{ActionRead, ActionPush, ActionDeploy}
+}
+
+// Kinds returns the artifact kinds a key can be scoped to.
+func Kinds() []string {
+	return []string{v1alpha1.KindAgent, v1alpha1.KindMCPServer, v1alpha1.KindSkill, v1alpha1.KindPrompt}
+}
+
+// ParseKind resolves a kind name as users type it ("agent", "mcpserver",
+// "server", "MCPServer") to its canonical artifact kind.
+func ParseKind(name string) (string, bool) {
+	if strings.EqualFold(name, "server") {
+		return v1alpha1.KindMCPServer, true
+	}
+	for _, kind := range Kinds() {
+		if strings.EqualFold(name, kind) {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+// NewToken generates a key ID and secret and returns them with the token
+// that carries both.
+func NewToken() (id, secret, token string, err error) {
+	idBytes := make([]byte, 8)
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", "", err
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+	id, secret = hex.EncodeToString(idBytes), hex.EncodeToString(secretBytes)
+	return id, secret, TokenPrefix + id + "_" + secret, nil
+}
+
+// ParseToken splits a token into its key ID and secret.
+func ParseToken(token string) (id, secret string, ok bool) {
+	rest, ok := strings.CutPrefix(token, TokenPrefix)
+	if !ok {
+		return "", "", false
+	}
+	id, secret, ok = strings.Cut(rest, "_")
+	return id, secret, ok && id != "" && secret != ""
+}
+
+// HashSecret returns the stored form of a key secret.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Allows reports whether key may take action on name of kind. An empty
+// name (a list) is only checked against the kind and action.
+func Allows(key *v1alpha1store.APIKey, action, kind, name string) bool {
+	if !slices.Contains(key.Actions, action) {
+		return false
+	}
+	if len(key.Kinds) > 0 && !slices.Contains(key.Kinds, kind) {
+		return false
+	}
+	if name == "" || len(key.Prefixes) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(key.Prefixes, func(prefix string) bool { return strings.HasPrefix(name, prefix) })
+}
+
+// Session is the auth.Session of a request authenticated by an API key.
+type Session struct {
+	Key *v1alpha1store.APIKey
+}
+
+// Principal names the key's owner, so ownership checks treat the key as
+// its owner, and lists as permissions the part of the key's scopes its
+// owner's permissions also reach, for providers that match on them.
+func (s *Session) Principal() auth.Principal {
+	var perms []auth.Permission
+	for _, action := range s.Key.Actions {
+		for _, verb := range permissionActions[action] {
+			for _, pattern := range patterns(s.Key) {
+				for _, owned := range s.Key.OwnerPermissions {
+					if auth.PermissionAction(owned.Action) != verb {
+						continue
+					}
+					if narrow, ok := intersect(pattern, owned.Resource); ok {
+						perms = append(perms, auth.Permission{Action: verb, ResourcePattern: narrow})
+					}
+				}
+			}
+		}
+	}
+	return auth.Principal{Subject: s.Key.Owner, User: auth.User{Permissions: perms}}
+}
+
+// ownerSession is the session of a key's owner as recorded on the key.
+// Check hands it to the wrapped provider so a key is refused whatever its
+// owner would be refused.
+type ownerSession struct {
+	key *v1alpha1store.APIKey
+}
+
+func (s ownerSession) Principal() auth.Principal {
+	perms := make([]auth.Permission, 0, len(s.key.OwnerPermissions))
+	for _, p := range s.key.OwnerPermissions {
+		perms = append(perms, auth.Permission{Action: auth.PermissionAction(p.Action), ResourcePattern: p.Resource})
+	}
+	return auth.Principal{Subject: s.key.Owner, User: auth.User{Permissions: perms}}
+}
+
+// OwnerPermissions converts the permissions of the session creating a key
+// to the form recorded on it.
+func OwnerPermissions(s auth.Session) []v1alpha1store.APIKeyPermission {
+	if s == nil {
+		return nil
+	}
+	var out []v1alpha1store.APIKeyPermission
+	for _, p := range s.Principal().User.Permissions {
+		out = append(out, v1alpha1store.APIKeyPermission{Action: string(p.Action), Resource: p.ResourcePattern})
+	}
+	return out
+}
+
+// CheckGrant returns an error matching auth.ErrForbidden unless check
+// allows the caller every permission key's scopes would carry: each
+// action on each kind in scope, for each name prefix (or every name). Nil
+// check allows everything.
+func CheckGrant(ctx context.Context, key *v1alpha1store.APIKey, check func(ctx context.Context, verb auth.PermissionAction, res auth.Resource) error) error {
+	if check == nil {
+		return nil
+	}
+	kinds := key.Kinds
+	if len(kinds) == 0 {
+		kinds = Kinds()
+	}
+	for _, action := range key.Actions {
+		for _, verb := range permissionActions[action] {
+			for _, kind := range kinds {
+				for _, pattern := range patterns(key) {
+					res := auth.Resource{Name: pattern, Type: kindArtifactTypes[kind]}
+					if err := check(ctx, verb, res); err != nil {
+						return fmt.Errorf("%w: %s on %s %q is beyond your own permissions", auth.ErrForbidden, verb, kind, pattern)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// patterns returns key's name prefixes as permission resource patterns.
+func patterns(key *v1alpha1store.APIKey) []string {
+	if len(key.Prefixes) == 0 {
+		return []string{"*"}
+	}
+	out := make([]string, 0, len(key.Prefixes))
+	for _, prefix := range key.Prefixes {
+		out = append(out, prefix+"*")
+	}
+	return out
+}
+
+// intersect returns the resource pattern both a and b match, when one of
+// them covers the other.
+func intersect(a, b string) (string, bool) {
+	switch {
+	case covers(a, b):
+		return b, true
+	case covers(b, a):
+		return a, true
+	}
+	return "", false
+}
+
+// covers reports whether every name pattern q matches also matches p,
+// using the trailing-* semantics of auth permission patterns.
+func covers(p, q string) bool {
+	if prefix, ok := strings.CutSuffix(p, "*"); ok {
+		return strings.HasPrefix(q, prefix)
+	}
+	return p == q
+}
+
+// SessionFrom returns the API key session on ctx, if the request was
+// authenticated by a key.
+func SessionFrom(ctx context.Context) (*Session, bool) {
+	session, ok := auth.AuthSessionFrom(ctx)
+	if !ok {
+		return nil, false
+	}
+	s, ok := session.(*Session)
+	return s, ok
+}
+
+// permissionActions maps key actions to the auth permission actions they
+// grant.
+var permissionActions = map[string][]auth.PermissionAction{
+	ActionRead:   {auth.PermissionActionRead},
+	ActionPush:   {auth.PermissionActionPublish, auth.PermissionActionEdit, auth.PermissionActionDelete},
+	ActionDeploy: {auth.PermissionActionDeploy},
+}
+
+// Store reads API keys. *v1alpha1store.APIKeyStore satisfies it.
+type Store interface {
+	Get(ctx context.Context, id string) (*v1alpha1store.APIKey, error)
+	Touch(ctx context.Context, id string, at time.Time) error
+}
+
+// Authenticator authenticates API key tokens and defers every other
+// request to next.
+type Authenticator struct {
+	store Store
+	// next authenticates non-key credentials. Nil leaves them
+	// unauthenticated.
+	next auth.AuthnProvider
+	now  func() time.Time
+}
+
+var _ auth.AuthnProvider = (*Authenticator)(nil)
+
+// NewAuthenticator constructs an Authenticator reading keys from store.
+func NewAuthenticator(store Store, next auth.AuthnProvider) *Authenticator {
+	return &Authenticator{store: store, next: next, now: time.Now}
+}
+
+// Authenticate returns a *Session for a valid, active key token and an
+// error for any other token carrying TokenPrefix.
+func (a *Authenticator) Authenticate(ctx context.Context, reqHeaders func(name string) string, query url.Values) (auth.Session, error) {
+	const bearerPrefix = "Bearer "
+	header := reqHeaders("Authorization")
+	if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) ||
+		!strings.HasPrefix(header[len(bearerPrefix):], TokenPrefix) {
+		if a.next == nil {
+			return nil, nil
+		}
+		return a.next.Authenticate(ctx, reqHeaders, query)
+	}
+	id, secret, ok := ParseToken(header[len(bearerPrefix):])
+	if !ok {
+		return nil, huma.Error401Unauthorized("malformed API key")
+	}
+	key, err := a.store.Get(ctx, id)
+	switch {
+	case errors.Is(err, pkgdb.ErrNotFound):
+		return nil, huma.Error401Unauthorized("invalid API key")
+	case err != nil:
+		return nil, fmt.Errorf("look up API key: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(HashSecret(secret)), []byte(key.SecretHash)) != 1 {
+		return nil, huma.Error401Unauthorized("invalid API key")
+	}
+	now := a.now()
+	if !key.Active(now) {
+		return nil, huma.Error401Unauthorized("API key is revoked or expired")
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= touchInterval {
+		if err := a.store.Touch(ctx, key.ID, now); err != nil {
+			slog.Warn("failed to record API key use", "key", key.ID, "error", err)
+		}
+	}
+	return &Session{Key: key}, nil
+}
+
+// ScopeError reports a request outside the scopes of the API key that
+// authenticated it. It renders as 403 and matches auth.ErrForbidden and
+// pkgdb.ErrForbidden.
+type ScopeError struct {
+	*huma.ErrorModel
+}
+
+func newScopeError(key *v1alpha1store.APIKey, format string, args ...any) *ScopeError {
+	return &ScopeError{ErrorModel: &huma.ErrorModel{
+		Status: http.StatusForbidden,
+		Title:  http.StatusText(http.StatusForbidden),
+		Detail: fmt.Sprintf("API key %s: ", key.ID) + fmt.Sprintf(format, args...),
+	}}
+}
+
+// Is makes errors.Is(err, auth.ErrForbidden) and
+// errors.Is(err, pkgdb.ErrForbidden) hold.
+func (e *ScopeError) Is(target error) bool {
+	return target == auth.ErrForbidden || target == pkgdb.ErrForbidden
+}
+
+// Authorize returns a per-kind resource authorizer that refuses API key
+// requests outside the key's scopes and otherwise defers to next. Wire it
+// for every kind the registry serves: keys reach Agents, MCPServers,
+// Skills and Prompts (read, push), and Deployments (read, deploy); every
+// other kind is refused to them.
+func Authorize(next func(ctx context.Context, in resource.AuthorizeInput) error) func(ctx context.Context, in resource.AuthorizeInput) error {
+	return func(ctx context.Context, in resource.AuthorizeInput) error {
+		if s, ok := SessionFrom(ctx); ok {
+			if err := checkResource(s.Key, in); err != nil {
+				return err
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		return next(ctx, in)
+	}
+}
+
+func checkResource(key *v1alpha1store.APIKey, in resource.AuthorizeInput) error {
+	write := in.Verb == "apply" || in.Verb == "delete"
+	switch {
+	case slices.Contains(Kinds(), in.Kind):
+		action := ActionRead
+		if write {
+			action = ActionPush
+		}
+		if !Allows(key, action, in.Kind, in.Name) {
+			return newScopeError(key, "may not %s %s %q", in.Verb, in.Kind, in.Name)
+		}
+	case in.Kind == v1alpha1.KindDeployment && write:
+		kind, name := "", ""
+		if d, ok := in.Object.(*v1alpha1.Deployment); ok {
+			kind, name = d.Spec.TargetRef.Kind, d.Spec.TargetRef.Name
+		}
+		if !slices.Contains(key.Actions, ActionDeploy) || (kind != "" && !Allows(key, ActionDeploy, kind, name)) {
+			return newScopeError(key, "may not %s Deployment %q", in.Verb, in.Name)
+		}
+	case in.Kind == v1alpha1.KindDeployment:
+		if !slices.Contains(key.Actions, ActionRead) && !slices.Contains(key.Actions, ActionDeploy) {
+			return newScopeError(key, "may not read Deployments")
+		}
+	default:
+		return newScopeError(key, "API keys cannot access %s resources", in.Kind)
+	}
+	return nil
+}
+
+// Authz wraps next so API key sessions are never registry admins and
+// Check refuses them anything outside their scopes, then asks next
+// whether the key's owner may do it.
+func Authz(next auth.AuthzProvider) auth.AuthzProvider {
+	return &scopedAuthz{next: next}
+}
+
+type scopedAuthz struct {
+	next auth.AuthzProvider
+}
+
+// artifactTypeKinds maps authz resource types to the artifact kinds keys
+// are scoped to.
+var artifactTypeKinds = map[auth.PermissionArtifactType]string{
+	auth.PermissionArtifactTypeAgent:  v1alpha1.KindAgent,
+	auth.PermissionArtifactTypeServer: v1alpha1.KindMCPServer,
+	auth.PermissionArtifactTypeSkill:  v1alpha1.KindSkill,
+	auth.PermissionArtifactTypePrompt: v1alpha1.KindPrompt,
+}
+
+// kindArtifactTypes inverts artifactTypeKinds.
+var kindArtifactTypes = map[string]auth.PermissionArtifactType{
+	v1alpha1.KindAgent:     auth.PermissionArtifactTypeAgent,
+	v1alpha1.KindMCPServer: auth.PermissionArtifactTypeServer,
+	v1alpha1.KindSkill:     auth.PermissionArtifactTypeSkill,
+	v1alpha1.KindPrompt:    auth.PermissionArtifactTypePrompt,
+}
+
+func (a *scopedAuthz) Check(ctx context.Context, s auth.Session, verb auth.PermissionAction, res auth.Resource) error {
+	if ks, ok := s.(*Session); ok {
+		kind, known := artifactTypeKinds[res.Type]
+		action := ""
+		for keyAction, verbs := range permissionActions {
+			if slices.Contains(verbs, verb) {
+				action = keyAction
+			}
+		}
+		if !known || action == "" || !Allows(ks.Key, action, kind, res.Name) {
+			return auth.ErrForbidden
+		}
+		return a.next.Check(ctx, ownerSession{key: ks.Key}, verb, res)
+	}
+	return a.next.Check(ctx, s, verb, res)
+}
+
+func (a *scopedAuthz) IsRegistryAdmin(ctx context.Context, s auth.Session) bool {
+	if _, ok := s.(*Session); ok {
+		return false
+	}
+	return a.next.IsRegistryAdmin(ctx, s)
+}
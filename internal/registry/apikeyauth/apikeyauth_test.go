@@ -0,0 +1,288 @@
+package apikeyauth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/apikeyauth"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeStore struct {
+	keys    map[string]*v1alpha1store.APIKey
+	touched []string
+}
+
+func (f *fakeStore) Get(_ context.Context, id string) (*v1alpha1store.APIKey, error) {
+	if key, ok := f.keys[id]; ok {
+		return key, nil
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+func (f *fakeStore) Touch(_ context.Context, id string, at time.Time) error {
+	f.touched = append(f.touched, id)
+	f.keys[id].LastUsedAt = &at
+	return nil
+}
+
+type fixedSession string
+
+func (s fixedSession) Principal() auth.Principal { return auth.Principal{Subject: string(s)} }
+
+type fakeAuthn struct{}
+
+func (fakeAuthn) Authenticate(context.Context, func(string) string, url.Values) (auth.Session, error) {
+	return fixedSession("jwt-user"), nil
+}
+
+func bearer(token string) func(string) string {
+	return func(name string) string {
+		if name == "Authorization" {
+			return "Bearer " + token
+		}
+		return ""
+	}
+}
+
+func ciKey() *v1alpha1store.APIKey {
+	return &v1alpha1store.APIKey{
+		ID:       "k1",
+		Owner:    "alice",
+		Kinds:    []string{v1alpha1.KindAgent, v1alpha1.KindMCPServer},
+		Prefixes: []string{"acme/"},
+		Actions:  []string{apikeyauth.ActionRead, apikeyauth.ActionPush},
+		OwnerPermissions: []v1alpha1store.APIKeyPermission{
+			{Action: "read", Resource: "*"},
+			{Action: "publish", Resource: "acme/*"},
+			{Action: "edit", Resource: "acme/*"},
+			{Action: "delete", Resource: "acme/bot"},
+		},
+	}
+}
+
+func keyCtx(key *v1alpha1store.APIKey) context.Context {
+	return auth.AuthSessionTo(context.Background(), &apikeyauth.Session{Key: key})
+}
+
+func TestTokenRoundTrip(t *testing.T) {
+	id, secret, token, err := apikeyauth.NewToken()
+	require.NoError(t, err)
+	gotID, gotSecret, ok := apikeyauth.ParseToken(token)
+	require.True(t, ok)
+	require.Equal(t, id, gotID)
+	require.Equal(t, secret, gotSecret)
+
+	_, _, ok = apikeyauth.ParseToken("eyJhbGciOi")
+	require.False(t, ok)
+	_, _, ok = apikeyauth.ParseToken(apikeyauth.TokenPrefix + "nosecret")
+	require.False(t, ok)
+
+	kind, ok := apikeyauth.ParseKind("server")
+	require.True(t, ok)
+	require.Equal(t, v1alpha1.KindMCPServer, kind)
+	_, ok = apikeyauth.ParseKind("runtime")
+	require.False(t, ok)
+}
+
+func TestAuthenticate(t *testing.T) {
+	id, secret, token, err := apikeyauth.NewToken()
+	require.NoError(t, err)
+	key := ciKey()
+	key.ID, key.SecretHash = id, apikeyauth.HashSecret(secret)
+	store := &fakeStore{keys: map[string]*v1alpha1store.APIKey{id: key}}
+	authn := apikeyauth.NewAuthenticator(store, fakeAuthn{})
+	ctx := context.Background()
+
+	session, err := authn.Authenticate(ctx, bearer(token), nil)
+	require.NoError(t, err)
+	require.Equal(t, "alice", session.Principal().Subject)
+	require.Equal(t, []string{id}, store.touched)
+	// Use within the touch interval is not recorded again.
+	_, err = authn.Authenticate(ctx, bearer(token), nil)
+	require.NoError(t, err)
+	require.Len(t, store.touched, 1)
+
+	_, err = authn.Authenticate(ctx, bearer(apikeyauth.TokenPrefix+id+"_wrong"), nil)
+	require.Error(t, err)
+	_, err = authn.Authenticate(ctx, bearer(apikeyauth.TokenPrefix+"missing_secret"), nil)
+	require.Error(t, err)
+
+	revoked := time.Now()
+	key.RevokedAt = &revoked
+	_, err = authn.Authenticate(ctx, bearer(token), nil)
+	require.Error(t, err)
+
+	// Other credentials go to the wrapped provider.
+	session, err = authn.Authenticate(ctx, bearer("eyJhbGciOi"), nil)
+	require.NoError(t, err)
+	require.Equal(t, "jwt-user", session.Principal().Subject)
+	session, err = apikeyauth.NewAuthenticator(store, nil).Authenticate(ctx, bearer("eyJhbGciOi"), nil)
+	require.NoError(t, err)
+	require.Nil(t, session)
+}
+
+func TestAuthorize(t *testing.T) {
+	var nextCalls int
+	authorize := apikeyauth.Authorize(func(context.Context, resource.AuthorizeInput) error {
+		nextCalls++
+		return nil
+	})
+	ctx := keyCtx(ciKey())
+
+	require.NoError(t, authorize(ctx, resource.AuthorizeInput{Verb: "apply", Kind: v1alpha1.KindAgent, Name: "acme/bot"}))
+	require.NoError(t, authorize(ctx, resource.AuthorizeInput{Verb: "list", Kind: v1alpha1.KindMCPServer}))
+	require.Equal(t, 2, nextCalls)
+
+	err := authorize(ctx, resource.AuthorizeInput{Verb: "apply", Kind: v1alpha1.KindAgent, Name: "other/bot"})
+	require.ErrorIs(t, err, auth.ErrForbidden)
+	require.ErrorIs(t, err, pkgdb.ErrForbidden)
+	var status huma.StatusError
+	require.True(t, errors.As(err, &status))
+	require.Equal(t, http.StatusForbidden, status.GetStatus())
+
+	require.ErrorIs(t, authorize(ctx, resource.AuthorizeInput{Verb: "get", Kind: v1alpha1.KindSkill, Name: "acme/s"}), auth.ErrForbidden)
+	require.ErrorIs(t, authorize(ctx, resource.AuthorizeInput{Verb: "get", Kind: v1alpha1.KindRuntime, Name: "local"}), auth.ErrForbidden)
+	require.ErrorIs(t, authorize(ctx, resource.AuthorizeInput{Verb: "apply", Kind: v1alpha1.KindDeployment, Name: "d"}), auth.ErrForbidden)
+	require.NoError(t, authorize(ctx, resource.AuthorizeInput{Verb: "get", Kind: v1alpha1.KindDeployment, Name: "d"}))
+
+	// Deploy keys deploy targets in scope.
+	deployer := ciKey()
+	deployer.Actions = []string{apikeyauth.ActionDeploy}
+	deployment := func(target string) resource.AuthorizeInput {
+		return resource.AuthorizeInput{Verb: "apply", Kind: v1alpha1.KindDeployment, Name: "d", Object: &v1alpha1.Deployment{
+			Spec: v1alpha1.DeploymentSpec{TargetRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: target}},
+		}}
+	}
+	require.NoError(t, authorize(keyCtx(deployer), deployment("acme/bot")))
+	require.ErrorIs(t, authorize(keyCtx(deployer), deployment("other/bot")), auth.ErrForbidden)
+	require.ErrorIs(t, authorize(keyCtx(deployer), resource.AuthorizeInput{Verb: "apply", Kind: v1alpha1.KindAgent, Name: "acme/bot"}), auth.ErrForbidden)
+
+	// Requests not authenticated by a key only see next.
+	require.NoError(t, authorize(context.Background(), resource.AuthorizeInput{Verb: "apply", Kind: v1alpha1.KindRuntime, Name: "local"}))
+	require.NoError(t, apikeyauth.Authorize(nil)(ctx, resource.AuthorizeInput{Verb: "get", Kind: v1alpha1.KindAgent, Name: "acme/bot"}))
+}
+
+func TestAuthz(t *testing.T) {
+	authz := apikeyauth.Authz(auth.NewPublicAuthzProvider(nil))
+	key := &apikeyauth.Session{Key: ciKey()}
+	ctx := context.Background()
+
+	require.False(t, authz.IsRegistryAdmin(ctx, key))
+	require.True(t, authz.IsRegistryAdmin(ctx, fixedSession("alice")))
+
+	require.NoError(t, authz.Check(ctx, key, auth.PermissionActionPublish, auth.Resource{Name: "acme/bot", Type: auth.PermissionArtifactTypeAgent}))
+	require.ErrorIs(t, authz.Check(ctx, key, auth.PermissionActionDeploy, auth.Resource{Name: "acme/bot", Type: auth.PermissionArtifactTypeAgent}), auth.ErrForbidden)
+	require.ErrorIs(t, authz.Check(ctx, key, auth.PermissionActionRead, auth.Resource{Name: "acme/x", Type: auth.PermissionArtifactTypeSkill}), auth.ErrForbidden)
+	require.ErrorIs(t, authz.Check(ctx, key, auth.PermissionActionRead, auth.Resource{Name: "local", Type: auth.PermissionArtifactTypeRuntime}), auth.ErrForbidden)
+	require.NoError(t, authz.Check(ctx, fixedSession("alice"), auth.PermissionActionDeploy, auth.Resource{Name: "local", Type: auth.PermissionArtifactTypeRuntime}))
+
+	// Principal lists where the key's scopes and its owner's permissions
+	// overlap.
+	perms := key.Principal().User.Permissions
+	require.Contains(t, perms, auth.Permission{Action: auth.PermissionActionRead, ResourcePattern: "acme/*"})
+	require.Contains(t, perms, auth.Permission{Action: auth.PermissionActionEdit, ResourcePattern: "acme/*"})
+	require.Contains(t, perms, auth.Permission{Action: auth.PermissionActionDelete, ResourcePattern: "acme/bot"})
+	require.NotContains(t, perms, auth.Permission{Action: auth.PermissionActionRead, ResourcePattern: "*"})
+	require.NotContains(t, perms, auth.Permission{Action: auth.PermissionActionDeploy, ResourcePattern: "acme/*"})
+}
+
+// permAuthz allows what the session's permissions match, like the JWT
+// provider, and makes nobody an admin.
+type permAuthz struct{}
+
+func (permAuthz) Check(_ context.Context, s auth.Session, verb auth.PermissionAction, res auth.Resource) error {
+	for _, p := range s.Principal().User.Permissions {
+		prefix, wildcard := strings.CutSuffix(p.ResourcePattern, "*")
+		if p.Action == verb && (p.ResourcePattern == res.Name || wildcard && strings.HasPrefix(res.Name, prefix)) {
+			return nil
+		}
+	}
+	return auth.ErrForbidden
+}
+
+func (permAuthz) IsRegistryAdmin(context.Context, auth.Session) bool { return false }
+
+func TestAuthzRequiresOwnerPermissions(t *testing.T) {
+	authz := apikeyauth.Authz(permAuthz{})
+	key := &apikeyauth.Session{Key: ciKey()}
+	ctx := context.Background()
+	agent := func(name string) auth.Resource {
+		return auth.Resource{Name: name, Type: auth.PermissionArtifactTypeAgent}
+	}
+
+	require.NoError(t, authz.Check(ctx, key, auth.PermissionActionPublish, agent("acme/bot")))
+	require.NoError(t, authz.Check(ctx, key, auth.PermissionActionDelete, agent("acme/bot")))
+	// In the key's scopes, but its owner may not delete acme/other.
+	require.ErrorIs(t, authz.Check(ctx, key, auth.PermissionActionDelete, agent("acme/other")), auth.ErrForbidden)
+	// The owner may read everything, but the key only reaches acme/.
+	require.ErrorIs(t, authz.Check(ctx, key, auth.PermissionActionRead, agent("other/bot")), auth.ErrForbidden)
+
+	// A key recorded without owner permissions reaches nothing.
+	bare := ciKey()
+	bare.OwnerPermissions = nil
+	require.ErrorIs(t, authz.Check(ctx, &apikeyauth.Session{Key: bare}, auth.PermissionActionRead, agent("acme/bot")), auth.ErrForbidden)
+}
+
+func TestCheckGrant(t *testing.T) {
+	// alice may do anything to names under acme/.
+	owner := ownerWith(fixedSession("alice"), []v1alpha1store.APIKeyPermission{
+		{Action: "read", Resource: "acme/*"},
+		{Action: "publish", Resource: "acme/*"},
+		{Action: "edit", Resource: "acme/*"},
+		{Action: "delete", Resource: "acme/*"},
+	})
+	check := func(ctx context.Context, verb auth.PermissionAction, res auth.Resource) error {
+		return permAuthz{}.Check(ctx, owner, verb, res)
+	}
+	ctx := context.Background()
+
+	require.NoError(t, apikeyauth.CheckGrant(ctx, ciKey(), check))
+	require.NoError(t, apikeyauth.CheckGrant(ctx, ciKey(), nil))
+
+	broad := ciKey()
+	broad.Prefixes = nil
+	require.ErrorIs(t, apikeyauth.CheckGrant(ctx, broad, check), auth.ErrForbidden)
+
+	deploy := ciKey()
+	deploy.Actions = append(deploy.Actions, apikeyauth.ActionDeploy)
+	require.ErrorIs(t, apikeyauth.CheckGrant(ctx, deploy, check), auth.ErrForbidden)
+
+	require.Equal(t, []v1alpha1store.APIKeyPermission{
+		{Action: "read", Resource: "acme/*"},
+		{Action: "publish", Resource: "acme/*"},
+		{Action: "edit", Resource: "acme/*"},
+		{Action: "delete", Resource: "acme/*"},
+	}, apikeyauth.OwnerPermissions(owner))
+}
+
+type permSession struct {
+	auth.Session
+	perms []auth.Permission
+}
+
+func (s permSession) Principal() auth.Principal {
+	p := s.Session.Principal()
+	p.User.Permissions = s.perms
+	return p
+}
+
+func ownerWith(s auth.Session, recorded []v1alpha1store.APIKeyPermission) auth.Session {
+	var perms []auth.Permission
+	for _, p := range recorded {
+		perms = append(perms, auth.Permission{Action: auth.PermissionAction(p.Action), ResourcePattern: p.Resource})
+	}
+	return permSession{Session: s, perms: perms}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -21,6 +22,26 @@ type Config struct {
 	JWTPrivateKey string `env:"JWT_PRIVATE_KEY" envDefault:""`
 	LogLevel      string `env:"LOG_LEVEL" envDefault:"info"`
 
+	// TLS for the API and MCP listeners. Setting TLSCertFile/TLSKeyFile
+	// serves HTTPS; additionally setting TLSClientCAFile requires clients to
+	// present a certificate signed by that bundle (mTLS).
+	TLSCertFile     string `env:"TLS_CERT_FILE" envDefault:""`
+	TLSKeyFile      string `env:"TLS_KEY_FILE" envDefault:""`
+	TLSClientCAFile string `env:"TLS_CLIENT_CA_FILE" envDefault:""`
+
+	// Outbound trust for calls the server makes (package validators, OCI
+	// registries, upstream registries). OutboundCAFile is appended to the
+	// system roots; the client cert/key pair is presented to upstreams that
+	// require mTLS.
+	OutboundCAFile         string `env:"OUTBOUND_CA_FILE" envDefault:""`
+	OutboundClientCertFile string `env:"OUTBOUND_CLIENT_CERT_FILE" envDefault:""`
+	OutboundClientKeyFile  string `env:"OUTBOUND_CLIENT_KEY_FILE" envDefault:""`
+	// OutboundProxyURL routes outbound calls through an http(s):// or
+	// socks5:// proxy. Empty honors the standard HTTP_PROXY/HTTPS_PROXY/
+	// NO_PROXY variables. OutboundNoProxy lists hosts that bypass it.
+	OutboundProxyURL string `env:"OUTBOUND_PROXY_URL" envDefault:""`
+	OutboundNoProxy  string `env:"OUTBOUND_NO_PROXY" envDefault:""`
+
 	// Platform mode: "docker" or "kubernetes". Controls which deployment
 	// provider IDs are available in the UI. Defaults to "kubernetes" so
 	// Helm/K8s deployments work without extra config; docker-compose.yml
@@ -30,8 +51,9 @@ type Config struct {
 	// Agent Gateway Configuration
 	AgentGatewayPort uint16 `env:"AGENT_GATEWAY_PORT" envDefault:"8081"`
 
-	// Runtime Configuration
-	RuntimeDir string `env:"RUNTIME_DIR" envDefault:"/tmp/arctl-runtime"`
+	// Runtime Configuration. RuntimeDir defaults to arctl-runtime under
+	// the OS temp directory (/tmp, %TEMP%).
+	RuntimeDir string `env:"RUNTIME_DIR"`
 	Verbose    bool   `env:"VERBOSE" envDefault:"false"`
 
 	// MCP Registry compatibility (read-only)
@@ -52,8 +74,139 @@ type Config struct {
 	// configured base.
 	MCPRegistryCompatPathPrefix string `env:"MCP_REGISTRY_COMPAT_PATH_PREFIX" envDefault:""`
 
+	// AgentFrameworksFile points at a YAML file describing the runtime
+	// image layout of agent frameworks beyond the built-in ADK (Python)
+	// one. Empty uses only the built-ins. See
+	// internal/registry/runtimes/frameworks for the file format.
+	AgentFrameworksFile string `env:"AGENT_FRAMEWORKS_FILE" envDefault:""`
+
+	// PublishTriggersFile points at a YAML file of CI/CD triggers (GitHub
+	// Actions workflow_dispatch, GitLab pipeline triggers, generic webhooks)
+	// fired whenever a new version is published. Empty disables them. See
+	// internal/registry/pipelines for the file format.
+	PublishTriggersFile string `env:"PUBLISH_TRIGGERS_FILE" envDefault:""`
+
+	// Webhooks
+	//
+	// WebhookMaxAttempts caps delivery attempts per event and webhook;
+	// WebhookBackoff is the first retry delay and doubles per attempt. Zero
+	// uses the defaults.
+	// WebhookDeliveryRetention is how long the delivery log keeps attempts
+	// (0 keeps them forever).
+	// WebhookMaxRetries caps the automatic retries of a delivery whose
+	// attempts all failed; WebhookRetryBackoff is the delay before the first
+	// and doubles per retry up to WebhookMaxRetryBackoff. Zero uses the
+	// defaults.
+	WebhookMaxAttempts       int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"5"`
+	WebhookBackoff           time.Duration `env:"WEBHOOK_BACKOFF" envDefault:"1s"`
+	WebhookDeliveryRetention time.Duration `env:"WEBHOOK_DELIVERY_RETENTION" envDefault:"168h"`
+	WebhookMaxRetries        int           `env:"WEBHOOK_MAX_RETRIES" envDefault:"10"`
+	WebhookRetryBackoff      time.Duration `env:"WEBHOOK_RETRY_BACKOFF" envDefault:"1m"`
+	WebhookMaxRetryBackoff   time.Duration `env:"WEBHOOK_MAX_RETRY_BACKOFF" envDefault:"1h"`
+
+	// Semantic search
+	//
+	// EmbeddingsProvider turns on the built-in semantic index and names the
+	// provider that embeds artifacts: "local" embeds through an Ollama
+	// server, so air-gapped registries need no outside API. Empty leaves
+	// search to full text and name match. EmbeddingsURL and EmbeddingsModel
+	// override the provider's endpoint and model, and EmbeddingsTimeout
+	// bounds each embedding request, search queries included. A failed
+	// request is retried EmbeddingsMaxRetries times, backing off from
+	// EmbeddingsRetryBackoff; after EmbeddingsBreakerThreshold failed calls
+	// in a row the provider is left alone for EmbeddingsBreakerCooldown,
+	// during which publishes are not embedded and semantic-only searches
+	// answer 503.
+	EmbeddingsProvider         string        `env:"EMBEDDINGS_PROVIDER" envDefault:""`
+	EmbeddingsURL              string        `env:"EMBEDDINGS_URL" envDefault:""`
+	EmbeddingsModel            string        `env:"EMBEDDINGS_MODEL" envDefault:""`
+	EmbeddingsTimeout          time.Duration `env:"EMBEDDINGS_TIMEOUT" envDefault:"30s"`
+	EmbeddingsMaxRetries       int           `env:"EMBEDDINGS_MAX_RETRIES" envDefault:"2"`
+	EmbeddingsRetryBackoff     time.Duration `env:"EMBEDDINGS_RETRY_BACKOFF" envDefault:"250ms"`
+	EmbeddingsBreakerThreshold int           `env:"EMBEDDINGS_BREAKER_THRESHOLD" envDefault:"5"`
+	EmbeddingsBreakerCooldown  time.Duration `env:"EMBEDDINGS_BREAKER_COOLDOWN" envDefault:"30s"`
+
+	// Prompt evaluation
+	//
+	// PromptEvalProvider turns on scoring of Prompts that declare
+	// spec.evaluation and names the provider of the model they run
+	// against: "local" for an Ollama server, "openai" for an
+	// OpenAI-compatible API, which needs PromptEvalModel and usually
+	// PromptEvalAPIKey. Empty disables scoring. PromptEvalURL overrides the
+	// provider's endpoint and PromptEvalTimeout bounds each model call.
+	PromptEvalProvider string        `env:"PROMPT_EVAL_PROVIDER" envDefault:""`
+	PromptEvalURL      string        `env:"PROMPT_EVAL_URL" envDefault:""`
+	PromptEvalModel    string        `env:"PROMPT_EVAL_MODEL" envDefault:""`
+	PromptEvalAPIKey   string        `env:"PROMPT_EVAL_API_KEY" envDefault:""`
+	PromptEvalTimeout  time.Duration `env:"PROMPT_EVAL_TIMEOUT" envDefault:"2m"`
+
+	// Rate limits and quotas
+	//
+	// Rates are COUNT/UNIT (e.g. "600/m"); empty disables the limit.
+	// RateLimitPerIP limits unauthenticated requests per client IP and
+	// RateLimitPerCaller authenticated requests per caller.
+	// RateLimitTrustForwardedFor keys the IP limit on the first
+	// X-Forwarded-For hop; set it only behind a proxy that overwrites the
+	// header. PublishQuotaPerNamespace caps Agent, MCPServer, Skill and
+	// Prompt publishes per namespace. Registry admins are exempt from the
+	// quota. Limits are enforced per replica.
+	RateLimitPerIP             string `env:"RATE_LIMIT_PER_IP" envDefault:""`
+	RateLimitPerCaller         string `env:"RATE_LIMIT_PER_CALLER" envDefault:""`
+	RateLimitTrustForwardedFor bool   `env:"RATE_LIMIT_TRUST_FORWARDED_FOR" envDefault:"false"`
+	PublishQuotaPerNamespace   string `env:"PUBLISH_QUOTA_PER_NAMESPACE" envDefault:""`
+
+	// Public mirror (read-only, cacheable)
+	//
+	// PublicMirrorEnabled mounts GET /v0/public/{plural}[/{name}/{tag}], an
+	// unauthenticated view of PublicMirrorNamespace's tagged artifacts with
+	// Cache-Control and ETag headers, meant to sit behind a CDN. It skips
+	// authn and per-kind RBAC for that namespace, so it is OFF by default.
+	PublicMirrorEnabled   bool   `env:"PUBLIC_MIRROR_ENABLED" envDefault:"false"`
+	PublicMirrorNamespace string `env:"PUBLIC_MIRROR_NAMESPACE" envDefault:"default"`
+	// PublicMirrorMaxAge is the Cache-Control max-age on mirror responses.
+	PublicMirrorMaxAge time.Duration `env:"PUBLIC_MIRROR_MAX_AGE" envDefault:"1h"`
+
+	// Shadow mode (upgrade testing)
+	//
+	// ShadowURL, when set, mirrors ShadowPercent (0-100) of GET/HEAD /v0
+	// requests to a candidate registry at that base URL and compares status
+	// codes and body digests, reporting divergences on the
+	// agent_registry.shadow.requests metric. Callers' headers, including
+	// Authorization, are forwarded. Empty disables shadowing.
+	ShadowURL     string        `env:"SHADOW_URL" envDefault:""`
+	ShadowPercent float64       `env:"SHADOW_PERCENT" envDefault:"10"`
+	ShadowTimeout time.Duration `env:"SHADOW_TIMEOUT" envDefault:"10s"`
+
+	// Peer registries (multi-registry dependency resolution)
+	//
+	// PeerRegistries maps a peer name to a registry base URL
+	// (name=url,name=url). Agent spec.mcpServers entries naming a peer via
+	// `registry:` are fetched from it at translation time and cached for
+	// PeerCacheTTL; a failed refresh keeps serving the cached copy.
+	PeerRegistries map[string]string `env:"PEER_REGISTRIES" envKeyValSeparator:"=" envDefault:""`
+	PeerCacheTTL   time.Duration     `env:"PEER_CACHE_TTL" envDefault:"5m"`
+
+	// Uniqueness rules (cross-artifact conflicts)
+	//
+	// UniquenessRules lists the rules applies are checked against:
+	// mcpserver-package (one MCPServer per package origin),
+	// mcpserver-remote-url (one MCPServer per remote URL) and agent-image
+	// (one Agent per container image), each within a namespace. A
+	// conflicting apply answers 409 naming the artifact that holds the
+	// value. "none" disables every rule.
+	UniquenessRules []string `env:"UNIQUENESS_RULES" envDefault:"mcpserver-package,mcpserver-remote-url,agent-image"`
+
+	// ReservedNamePrefixes lists name prefixes (a trailing "*" is ignored)
+	// that only their allocated owners and registry admins may publish
+	// Agents, MCPServers, Skills and Prompts under. Registry admins add more
+	// at runtime through /v0/reserved-prefixes. Empty reserves nothing
+	// beyond those.
+	ReservedNamePrefixes []string `env:"RESERVED_NAME_PREFIXES" envDefault:"official/,mcp/"`
+
 	// ControllerEventRetention is how long handled control-plane events remain
-	// available for checkpoint replay. Set to 0 to disable event pruning.
+	// available for checkpoint replay. Day partitions wholly outside the window
+	// are dropped; rows in the default partition are deleted in batches. Set to
+	// 0 to disable event pruning.
 	ControllerEventRetention time.Duration `env:"CONTROLLER_EVENT_RETENTION" envDefault:"24h"`
 	// ControllerEventKeepAfterRevision preserves control-plane events newer than
 	// this Postgres revision even when they are older than ControllerEventRetention.
@@ -61,6 +214,51 @@ type Config struct {
 	// ControllerRetentionPruneBatchLimit caps rows removed per retention pass so
 	// pruning cannot monopolize the database during startup or repair loops.
 	ControllerRetentionPruneBatchLimit int `env:"CONTROLLER_RETENTION_PRUNE_BATCH_LIMIT" envDefault:"500"`
+	// DeletedArtifactRetention is how long a deleted agent, MCP server,
+	// skill or prompt tag stays restorable before the retention pass purges
+	// it. Set to 0 to delete tags immediately.
+	DeletedArtifactRetention time.Duration `env:"DELETED_ARTIFACT_RETENTION" envDefault:"168h"`
+	// VersionGCInterval runs version garbage collection on this interval,
+	// deleting the artifact versions the retention policy below does not
+	// keep. Set to 0 to only collect through POST /v0/admin/gc.
+	VersionGCInterval time.Duration `env:"VERSION_GC_INTERVAL" envDefault:"0"`
+	// VersionGCKeepVersions keeps the newest versions of each artifact and
+	// VersionGCKeepWithin every version younger than it. The "latest" tag,
+	// the newest version and versions in use are always kept.
+	VersionGCKeepVersions int           `env:"VERSION_GC_KEEP_VERSIONS" envDefault:"10"`
+	VersionGCKeepWithin   time.Duration `env:"VERSION_GC_KEEP_WITHIN" envDefault:"720h"`
+	// RemoteProbeEnabled probes the remote URL of every MCPServer published
+	// with one. Versions whose URL does not answer are still published but
+	// annotated remote-unverified and left out of default search.
+	RemoteProbeEnabled bool `env:"REMOTE_PROBE_ENABLED" envDefault:"false"`
+	// RemoteProbeInterval re-probes unverified versions on this interval,
+	// clearing the annotation of those that answer. Set to 0 to only probe
+	// on publish.
+	RemoteProbeInterval time.Duration `env:"REMOTE_PROBE_INTERVAL" envDefault:"1h"`
+	// ReadmeFetchEnabled fills in the empty spec.readme of MCPServers
+	// published with a github.com source repository from that repository's
+	// README, unless the publisher sets the skip-readme-fetch annotation.
+	ReadmeFetchEnabled bool `env:"README_FETCH_ENABLED" envDefault:"false"`
+	// ReadmeFetchGitHubToken authenticates README fetches, raising GitHub's
+	// rate limit and reaching private repositories.
+	ReadmeFetchGitHubToken string `env:"README_FETCH_GITHUB_TOKEN" envDefault:""`
+	// ReadmeFetchGitHubAPIURL is the GitHub API base URL, e.g.
+	// https://github.example.com/api/v3 for GitHub Enterprise.
+	ReadmeFetchGitHubAPIURL string `env:"README_FETCH_GITHUB_API_URL" envDefault:"https://api.github.com"`
+	// MCPTrafficFrames is how many MCP frames are kept in memory for each
+	// Deployment in traffic debug mode; older ones are dropped.
+	MCPTrafficFrames int `env:"MCP_TRAFFIC_FRAMES" envDefault:"200"`
+	// DeploymentLogShippingEnabled copies the logs runtime adapters report
+	// for each Deployment into Postgres every DeploymentLogShipInterval, so
+	// the logs endpoint still answers after containers restart.
+	DeploymentLogShippingEnabled bool          `env:"DEPLOYMENT_LOG_SHIPPING_ENABLED" envDefault:"false"`
+	DeploymentLogShipInterval    time.Duration `env:"DEPLOYMENT_LOG_SHIP_INTERVAL" envDefault:"30s"`
+	// DeploymentLogRetention is how long shipped log lines are kept. Set to
+	// 0 to keep them until DeploymentLogMaxBytes pushes them out.
+	DeploymentLogRetention time.Duration `env:"DEPLOYMENT_LOG_RETENTION" envDefault:"168h"`
+	// DeploymentLogMaxBytes caps the shipped log text kept per Deployment;
+	// the oldest lines go first. Set to 0 for no cap.
+	DeploymentLogMaxBytes int64 `env:"DEPLOYMENT_LOG_MAX_BYTES" envDefault:"10485760"`
 	// ControllerDiscoveryInterval is how often provider discovery snapshots are
 	// materialized into discovered Deployment rows. Provider-specific cache
 	// refreshes may have separate intervals.
@@ -72,6 +270,43 @@ type Config struct {
 	// ControllerDiscoveryDeleteAfterMisses is how many consecutive successful
 	// discovery polls may omit a discovered Deployment before it is deleted.
 	ControllerDiscoveryDeleteAfterMisses int `env:"CONTROLLER_DISCOVERY_DELETE_AFTER_MISSES" envDefault:"5"`
+	// ControllerWorkers is how many Deployment reconciles may run in parallel.
+	ControllerWorkers int `env:"CONTROLLER_WORKERS" envDefault:"4"`
+	// ControllerRuntimeConcurrency caps parallel reconciles against a single
+	// Runtime so reconcile storms do not saturate one Docker daemon or
+	// Kubernetes API server.
+	ControllerRuntimeConcurrency int `env:"CONTROLLER_RUNTIME_CONCURRENCY" envDefault:"2"`
+	// DeploymentDefault* fill in the CPU and memory requests and limits of
+	// deployed agents and MCP servers whose Deployment and Agent set none,
+	// in Kubernetes quantity notation ("500m", "512Mi"). Empty sets none.
+	DeploymentDefaultCPURequest    string `env:"DEPLOYMENT_DEFAULT_CPU_REQUEST" envDefault:""`
+	DeploymentDefaultMemoryRequest string `env:"DEPLOYMENT_DEFAULT_MEMORY_REQUEST" envDefault:""`
+	DeploymentDefaultCPULimit      string `env:"DEPLOYMENT_DEFAULT_CPU_LIMIT" envDefault:""`
+	DeploymentDefaultMemoryLimit   string `env:"DEPLOYMENT_DEFAULT_MEMORY_LIMIT" envDefault:""`
+	// DeploymentMaxCPU and DeploymentMaxMemory cap every request and limit;
+	// applies over them are refused, and a missing limit is set to the
+	// maximum. Empty means no cap.
+	DeploymentMaxCPU    string `env:"DEPLOYMENT_MAX_CPU" envDefault:""`
+	DeploymentMaxMemory string `env:"DEPLOYMENT_MAX_MEMORY" envDefault:""`
+
+	// SecretsMasterKey is the base64-encoded 32-byte AES-256 key the
+	// registry encrypts Runtime credentials with at rest. Generate one with
+	// `openssl rand -base64 32`. Empty refuses Runtimes carrying
+	// credentials, unless AppOptions.SecretsCipher supplies a cipher such
+	// as a KMS or SecretsAllowPlaintext is set.
+	SecretsMasterKey string `env:"SECRETS_MASTER_KEY" envDefault:""`
+
+	// SecretsAllowPlaintext stores Runtime credentials unencrypted when
+	// there is neither a master key nor a cipher. Insecure; meant for
+	// local development. A warning is logged at startup.
+	SecretsAllowPlaintext bool `env:"SECRETS_ALLOW_PLAINTEXT" envDefault:"false"`
+
+	// DatabaseRLSEnabled turns on Postgres row-level security as a second
+	// line of tenant isolation: each pool checkout is scoped to the
+	// namespaces the caller's principal is confined to, and the policies
+	// from migration 011 hide every other namespace's rows. Requires the
+	// registry to connect as a role that is neither superuser nor BYPASSRLS.
+	DatabaseRLSEnabled bool `env:"DATABASE_RLS_ENABLED" envDefault:"false"`
 
 	// SkipMigrations gates the server's Postgres migrator at startup.
 	// Set true when migrations are applied out-of-band (e.g. by
@@ -119,6 +354,7 @@ func NewConfig() *Config {
 	// explicit override via the AGENT_REGISTRY_RUNTIME_DIR env var. This
 	// prevents concurrent runs from sharing the same directory.
 	if os.Getenv("AGENT_REGISTRY_RUNTIME_DIR") == "" {
+		cfg.RuntimeDir = filepath.Join(os.TempDir(), "arctl-runtime")
 		suffix, err := randomHex(8)
 		if err != nil {
 			slog.Error("failed to generate random runtime dir suffix", "error", err)
@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -13,7 +15,7 @@ func TestNewConfig_RuntimeDirHasRandomSuffix(t *testing.T) {
 
 	cfg := NewConfig()
 
-	base := "/tmp/arctl-runtime-"
+	base := filepath.Join(os.TempDir(), "arctl-runtime-")
 	if !strings.HasPrefix(cfg.RuntimeDir, base) {
 		t.Fatalf("RuntimeDir should start with %q, got %q", base, cfg.RuntimeDir)
 	}
@@ -103,3 +105,88 @@ func TestNewConfig_SkipMigrationsEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfig_PeerRegistriesEnv(t *testing.T) {
+	t.Setenv("AGENT_REGISTRY_PEER_REGISTRIES", "upstream-public=https://registry.example.com,team=http://team:12121/v0")
+	t.Setenv("AGENT_REGISTRY_PEER_CACHE_TTL", "30s")
+
+	cfg := NewConfig()
+
+	want := map[string]string{
+		"upstream-public": "https://registry.example.com",
+		"team":            "http://team:12121/v0",
+	}
+	if !reflect.DeepEqual(cfg.PeerRegistries, want) {
+		t.Fatalf("peer registries = %v, want %v", cfg.PeerRegistries, want)
+	}
+	if cfg.PeerCacheTTL != 30*time.Second {
+		t.Fatalf("peer cache TTL = %s, want 30s", cfg.PeerCacheTTL)
+	}
+}
+
+func TestNewConfig_UniquenessRulesEnv(t *testing.T) {
+	cfg := NewConfig()
+	want := []string{"mcpserver-package", "mcpserver-remote-url", "agent-image"}
+	if !reflect.DeepEqual(cfg.UniquenessRules, want) {
+		t.Fatalf("default uniqueness rules = %v, want %v", cfg.UniquenessRules, want)
+	}
+
+	t.Setenv("AGENT_REGISTRY_UNIQUENESS_RULES", "none")
+	cfg = NewConfig()
+	if !reflect.DeepEqual(cfg.UniquenessRules, []string{"none"}) {
+		t.Fatalf("uniqueness rules = %v, want [none]", cfg.UniquenessRules)
+	}
+}
+
+func TestValidate_DeploymentResources(t *testing.T) {
+	cases := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr string
+	}{
+		{"unset", func(*Config) {}, ""},
+		{"defaults within max", func(c *Config) {
+			c.DeploymentDefaultCPURequest, c.DeploymentDefaultCPULimit, c.DeploymentMaxCPU = "250m", "1", "2"
+			c.DeploymentDefaultMemoryRequest, c.DeploymentMaxMemory = "256Mi", "1Gi"
+		}, ""},
+		{"malformed", func(c *Config) { c.DeploymentMaxMemory = "lots" }, "DEPLOYMENT_MAX_MEMORY"},
+		{"default over max", func(c *Config) {
+			c.DeploymentDefaultCPULimit, c.DeploymentMaxCPU = "4", "2"
+		}, "DEPLOYMENT_DEFAULT_CPU_LIMIT 4 exceeds DEPLOYMENT_MAX_CPU 2"},
+		{"request over limit", func(c *Config) {
+			c.DeploymentDefaultMemoryRequest, c.DeploymentDefaultMemoryLimit = "1Gi", "512Mi"
+		}, "DEPLOYMENT_DEFAULT_MEMORY_REQUEST 1Gi exceeds DEPLOYMENT_DEFAULT_MEMORY_LIMIT 512Mi"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{}
+			tc.mutate(cfg)
+			err := Validate(cfg)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("Validate() = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_ModelTimeouts(t *testing.T) {
+	cfg := &Config{EmbeddingsProvider: "local", PromptEvalProvider: "local", EmbeddingsTimeout: time.Second, PromptEvalTimeout: time.Second}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+	cfg.EmbeddingsTimeout = 0
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "embeddings timeout") {
+		t.Fatalf("Validate() = %v, want embeddings timeout error", err)
+	}
+	cfg.EmbeddingsTimeout = time.Second
+	cfg.PromptEvalTimeout = 0
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "prompt evaluation timeout") {
+		t.Fatalf("Validate() = %v, want prompt evaluation timeout error", err)
+	}
+}
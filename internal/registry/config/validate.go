@@ -1,6 +1,12 @@
 package config
 
-import "fmt"
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
 
 // Validate performs runtime validations on the loaded configuration.
 func Validate(cfg *Config) error {
@@ -16,5 +22,152 @@ func Validate(cfg *Config) error {
 	if cfg.ControllerRetentionPruneBatchLimit < 0 {
 		return fmt.Errorf("controller retention prune batch limit must be non-negative")
 	}
+	if cfg.DeletedArtifactRetention < 0 {
+		return fmt.Errorf("deleted artifact retention must be non-negative")
+	}
+	if cfg.VersionGCInterval < 0 || cfg.VersionGCKeepVersions < 0 || cfg.VersionGCKeepWithin < 0 {
+		return fmt.Errorf("version gc interval, keep versions and keep within must be non-negative")
+	}
+	if cfg.DeploymentLogShipInterval < 0 {
+		return fmt.Errorf("deployment log ship interval must be non-negative")
+	}
+	if cfg.DeploymentLogRetention < 0 {
+		return fmt.Errorf("deployment log retention must be non-negative")
+	}
+	if cfg.DeploymentLogMaxBytes < 0 {
+		return fmt.Errorf("deployment log max bytes must be non-negative")
+	}
+	if cfg.ControllerWorkers < 0 {
+		return fmt.Errorf("controller workers must be non-negative")
+	}
+	if cfg.ControllerRuntimeConcurrency < 0 {
+		return fmt.Errorf("controller runtime concurrency must be non-negative")
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return fmt.Errorf("TLS cert file and key file must be set together")
+	}
+	if cfg.TLSClientCAFile != "" && cfg.TLSCertFile == "" {
+		return fmt.Errorf("TLS client CA file requires a server TLS cert and key")
+	}
+	if (cfg.OutboundClientCertFile == "") != (cfg.OutboundClientKeyFile == "") {
+		return fmt.Errorf("outbound client cert file and key file must be set together")
+	}
+	if cfg.WebhookMaxAttempts < 0 {
+		return fmt.Errorf("webhook max attempts must be non-negative")
+	}
+	if cfg.WebhookBackoff < 0 {
+		return fmt.Errorf("webhook backoff must be non-negative")
+	}
+	if cfg.WebhookDeliveryRetention < 0 {
+		return fmt.Errorf("webhook delivery retention must be non-negative")
+	}
+	if cfg.WebhookMaxRetries < 0 {
+		return fmt.Errorf("webhook max retries must be non-negative")
+	}
+	if cfg.WebhookRetryBackoff < 0 || cfg.WebhookMaxRetryBackoff < 0 {
+		return fmt.Errorf("webhook retry backoff must be non-negative")
+	}
+	switch cfg.EmbeddingsProvider {
+	case "", "local":
+	default:
+		return fmt.Errorf("embeddings provider must be empty or %q, got %q", "local", cfg.EmbeddingsProvider)
+	}
+	if cfg.EmbeddingsProvider != "" && cfg.EmbeddingsTimeout <= 0 {
+		return fmt.Errorf("embeddings timeout must be positive")
+	}
+	if cfg.EmbeddingsMaxRetries < 0 {
+		return fmt.Errorf("embeddings max retries must be non-negative")
+	}
+	if cfg.EmbeddingsRetryBackoff < 0 || cfg.EmbeddingsBreakerCooldown < 0 {
+		return fmt.Errorf("embeddings retry backoff and breaker cooldown must be non-negative")
+	}
+	if cfg.EmbeddingsBreakerThreshold < 0 {
+		return fmt.Errorf("embeddings breaker threshold must be non-negative")
+	}
+	if cfg.PromptEvalProvider != "" && cfg.PromptEvalTimeout <= 0 {
+		return fmt.Errorf("prompt evaluation timeout must be positive")
+	}
+	if cfg.PublicMirrorEnabled {
+		if cfg.PublicMirrorNamespace == "" {
+			return fmt.Errorf("public mirror namespace must be set when the public mirror is enabled")
+		}
+		if cfg.PublicMirrorMaxAge <= 0 {
+			return fmt.Errorf("public mirror max age must be positive")
+		}
+	}
+	if cfg.ShadowURL != "" {
+		if cfg.ShadowPercent < 0 || cfg.ShadowPercent > 100 {
+			return fmt.Errorf("shadow percent must be between 0 and 100")
+		}
+		if cfg.ShadowTimeout <= 0 {
+			return fmt.Errorf("shadow timeout must be positive")
+		}
+	}
+	if len(cfg.PeerRegistries) > 0 && cfg.PeerCacheTTL <= 0 {
+		return fmt.Errorf("peer cache TTL must be positive")
+	}
+	if cfg.SecretsMasterKey != "" {
+		if key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(cfg.SecretsMasterKey)); err != nil || len(key) != 32 {
+			return fmt.Errorf("secrets master key must be a base64-encoded 32-byte key")
+		}
+	}
+	if err := validateDeploymentResources(cfg); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateDeploymentResources checks the deployment resource defaults and
+// maxima parse and that no default exceeds its maximum.
+func validateDeploymentResources(cfg *Config) error {
+	checks := []struct {
+		name, value, maxName, max string
+		parse                     func(string) (int64, error)
+	}{
+		{"DEPLOYMENT_DEFAULT_CPU_REQUEST", cfg.DeploymentDefaultCPURequest, "DEPLOYMENT_MAX_CPU", cfg.DeploymentMaxCPU, v1alpha1.ParseCPU},
+		{"DEPLOYMENT_DEFAULT_CPU_LIMIT", cfg.DeploymentDefaultCPULimit, "DEPLOYMENT_MAX_CPU", cfg.DeploymentMaxCPU, v1alpha1.ParseCPU},
+		{"DEPLOYMENT_DEFAULT_MEMORY_REQUEST", cfg.DeploymentDefaultMemoryRequest, "DEPLOYMENT_MAX_MEMORY", cfg.DeploymentMaxMemory, v1alpha1.ParseMemory},
+		{"DEPLOYMENT_DEFAULT_MEMORY_LIMIT", cfg.DeploymentDefaultMemoryLimit, "DEPLOYMENT_MAX_MEMORY", cfg.DeploymentMaxMemory, v1alpha1.ParseMemory},
+		{"DEPLOYMENT_MAX_CPU", cfg.DeploymentMaxCPU, "", "", v1alpha1.ParseCPU},
+		{"DEPLOYMENT_MAX_MEMORY", cfg.DeploymentMaxMemory, "", "", v1alpha1.ParseMemory},
+	}
+	for _, c := range checks {
+		if c.value == "" {
+			continue
+		}
+		v, err := c.parse(c.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+		if v <= 0 {
+			return fmt.Errorf("%s must be positive", c.name)
+		}
+		if c.max == "" {
+			continue
+		}
+		bound, err := c.parse(c.max)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.maxName, err)
+		}
+		if v > bound {
+			return fmt.Errorf("%s %s exceeds %s %s", c.name, c.value, c.maxName, c.max)
+		}
+	}
+	if err := checkDefaultRequestAtMostLimit("CPU", cfg.DeploymentDefaultCPURequest, cfg.DeploymentDefaultCPULimit, v1alpha1.ParseCPU); err != nil {
+		return err
+	}
+	return checkDefaultRequestAtMostLimit("MEMORY", cfg.DeploymentDefaultMemoryRequest, cfg.DeploymentDefaultMemoryLimit, v1alpha1.ParseMemory)
+}
+
+func checkDefaultRequestAtMostLimit(resource, request, limit string, parse func(string) (int64, error)) error {
+	if request == "" || limit == "" {
+		return nil
+	}
+	// Both parsed in validateDeploymentResources already.
+	r, _ := parse(request)
+	l, _ := parse(limit)
+	if r > l {
+		return fmt.Errorf("DEPLOYMENT_DEFAULT_%s_REQUEST %s exceeds DEPLOYMENT_DEFAULT_%s_LIMIT %s", resource, request, resource, limit)
+	}
 	return nil
 }
@@ -7,8 +7,10 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/metric"
 	"k8s.io/client-go/util/workqueue"
 
+	"github.com/agentregistry-dev/agentregistry/internal/registry/resourcelimits"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
@@ -45,12 +47,49 @@ type DeploymentController struct {
 	Wakeups    <-chan struct{}
 	Queue      workqueue.TypedRateLimitingInterface[deploymentQueueKey]
 
+	// Workers is how many queue workers Run starts. RuntimeConcurrency caps
+	// in-flight reconciles that target the same Runtime across all workers;
+	// a Deployment whose Runtime is at the cap is requeued shortly rather
+	// than holding a worker. Values <= 0 use the package defaults.
+	Workers            int
+	RuntimeConcurrency int
+	// Meter, when set, receives queue depth and reconcile latency metrics.
+	Meter metric.Meter
+	// FailureNotifier, when set, is told once per Deployment generation
+	// that the runtime adapter failed to apply it.
+	FailureNotifier DeploymentFailureNotifier
+	// Locks, when set, keeps two reconciles of the same target on the same
+	// Runtime from running at once, across replicas. A Deployment whose
+	// lock is held elsewhere is requeued with backoff.
+	Locks DeploymentLocker
+	// Manifests, when set, records the rendered manifests of each
+	// successful apply and drops them once the Deployment is removed.
+	Manifests DeploymentManifestRecorder
+	// Usage, when set, counts each successful apply as a deploy of the
+	// target artifact.
+	Usage DeployRecorder
+	// Resources, when set, supplies the server's CPU and memory defaults
+	// and maxima for deployed containers. Nil still merges an Agent
+	// target's spec.resources under the Deployment's.
+	Resources *resourcelimits.Policy
+	// Snapshots, when set, resolves the refs of Deployments pinned to a
+	// catalog snapshot (spec.snapshot). Nil leaves those Deployments
+	// blocked on a dangling reference.
+	Snapshots SnapshotResolver
+
 	mu         sync.RWMutex
 	checkpoint int64
 	ready      bool
 	lastErr    error
 
 	queueMu sync.Mutex
+
+	failedMu          sync.Mutex
+	failedGenerations map[deploymentQueueKey]int64
+
+	throttleOnce sync.Once
+	limiter      *runtimeLimiter
+	metrics      *reconcileMetrics
 }
 
 // SyncResult describes one controller replay pass.
@@ -122,16 +161,27 @@ func (c *DeploymentController) FullReconcile(ctx context.Context) (int, error) {
 // dependency events because their resolved material can change Deployment apply
 // fingerprints.
 func (c *DeploymentController) HandleEvent(ctx context.Context, event v1alpha1store.ControlPlaneEvent) (int, error) {
-	switch event.Key.Kind {
-	case v1alpha1.KindDeployment:
+	switch {
+	case event.Key.Kind == v1alpha1.KindDeployment:
 		return c.reconcileDeployment(ctx, event.Key)
-	case v1alpha1.KindRuntime, v1alpha1.KindAgent, v1alpha1.KindMCPServer, v1alpha1.KindPlugin, v1alpha1.KindSkill, v1alpha1.KindPrompt:
+	case isDependencyEventKind(event.Key.Kind):
 		return c.FullReconcile(ctx)
 	default:
 		return 0, nil
 	}
 }
 
+// isDependencyEventKind reports whether a change to kind can alter the
+// desired state of any Deployment and therefore requires a full scan.
+func isDependencyEventKind(kind string) bool {
+	switch kind {
+	case v1alpha1.KindRuntime, v1alpha1.KindAgent, v1alpha1.KindMCPServer, v1alpha1.KindPlugin, v1alpha1.KindSkill, v1alpha1.KindPrompt, v1alpha1.KindChart:
+		return true
+	default:
+		return false
+	}
+}
+
 // Refresh performs a full repair pass. It captures the durable event high-water
 // mark before rebuilding Deployment work, then replays anything newer so writes
 // racing the refresh are not skipped.
@@ -180,10 +230,16 @@ func (c *DeploymentController) Run(ctx context.Context, resyncInterval time.Dura
 	queue := c.workQueue()
 	defer queue.ShutDown()
 
-	workerErrs := make(chan error, 1)
-	go func() {
-		workerErrs <- c.RunWorker(ctx)
-	}()
+	workers := c.Workers
+	if workers <= 0 {
+		workers = defaultControllerWorkers
+	}
+	workerErrs := make(chan error, workers)
+	for range workers {
+		go func() {
+			workerErrs <- c.RunWorker(ctx)
+		}()
+	}
 
 	var ticker *time.Ticker
 	var ticks <-chan time.Time
@@ -210,7 +266,9 @@ func (c *DeploymentController) Run(ctx context.Context, resyncInterval time.Dura
 	}
 }
 
-// RunWorker processes queued Deployment keys until the queue is shut down.
+// RunWorker processes queued Deployment keys until the queue is shut down. It
+// is safe to run several workers against the same controller; the workqueue
+// never hands the same key to two workers at once.
 func (c *DeploymentController) RunWorker(ctx context.Context) error {
 	if err := c.validateReconciler(); err != nil {
 		return err
@@ -352,6 +410,10 @@ func (c *DeploymentController) fullRefreshAndReplay(ctx context.Context) (SyncRe
 	return replayed, nil
 }
 
+// replay handles every retained event after checkpoint. Dependency events are
+// coalesced: a bulk import that touches hundreds of Agents or Runtimes causes a
+// single full Deployment scan once the backlog is drained instead of one scan
+// per event. The checkpoint only advances when that scan succeeds.
 func (c *DeploymentController) replay(ctx context.Context, checkpoint int64) (SyncResult, error) {
 	limit := c.BatchLimit
 	if limit <= 0 {
@@ -359,15 +421,27 @@ func (c *DeploymentController) replay(ctx context.Context, checkpoint int64) (Sy
 	}
 	next := checkpoint
 	applied := 0
+	fullReconcilePending := false
 	for {
 		events, err := c.Events.ListAfter(ctx, next, limit)
 		if err != nil {
 			return SyncResult{}, err
 		}
 		if len(events) == 0 {
+			if fullReconcilePending {
+				if _, err := c.FullReconcile(ctx); err != nil {
+					return SyncResult{}, fmt.Errorf("deployment controller coalesced full reconcile: %w", err)
+				}
+			}
 			return SyncResult{Checkpoint: next, Events: applied}, nil
 		}
 		for _, event := range events {
+			if isDependencyEventKind(event.Key.Kind) {
+				fullReconcilePending = true
+				next = event.Revision
+				applied++
+				continue
+			}
 			if _, err := c.HandleEvent(ctx, event); err != nil {
 				return SyncResult{}, fmt.Errorf("deployment controller handle revision %d: %w", event.Revision, err)
 			}
@@ -403,6 +477,26 @@ func (c *DeploymentController) workQueue() workqueue.TypedRateLimitingInterface[
 	return c.Queue
 }
 
+// QueueDepth reports how many Deployment keys are waiting to be reconciled.
+func (c *DeploymentController) QueueDepth() int {
+	if c == nil {
+		return 0
+	}
+	return c.workQueue().Len()
+}
+
+func (c *DeploymentController) initThrottle() {
+	c.throttleOnce.Do(func() {
+		c.limiter = newRuntimeLimiter(c.RuntimeConcurrency)
+		metrics, err := newReconcileMetrics(c.Meter, c.QueueDepth)
+		if err != nil {
+			logger.Error("deployment controller metrics disabled", "error", err)
+			return
+		}
+		c.metrics = metrics
+	})
+}
+
 func (c *DeploymentController) markReady(checkpoint int64) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
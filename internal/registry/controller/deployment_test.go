@@ -6,7 +6,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/agentregistry-dev/agentregistry/internal/registry/resourcelimits"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
 )
 
 func TestDeploymentActionDefaultsToApply(t *testing.T) {
@@ -38,6 +40,44 @@ func TestDeploymentActionRejectsInvalidDesiredState(t *testing.T) {
 	require.ErrorContains(t, err, "unsupported deployment desiredState")
 }
 
+func TestWithDeploymentDefaultsMergesUnderSpec(t *testing.T) {
+	deployment := deploymentFixture(v1alpha1.DesiredStateDeployed)
+	deployment.Spec.Env = map[string]string{"LOG_LEVEL": "debug"}
+	runtime := &v1alpha1.Runtime{Spec: v1alpha1.RuntimeSpec{
+		Type: v1alpha1.TypeLocal,
+		DeploymentDefaults: &v1alpha1.DeploymentDefaults{
+			Env: map[string]string{"LOG_LEVEL": "info", "HTTPS_PROXY": "http://proxy:3128"},
+		},
+	}}
+
+	merged := withDeploymentDefaults(deployment, runtime)
+	require.Equal(t, map[string]string{"LOG_LEVEL": "debug", "HTTPS_PROXY": "http://proxy:3128"}, merged.Spec.Env)
+	require.Equal(t, map[string]string{"LOG_LEVEL": "debug"}, deployment.Spec.Env)
+
+	runtime.Spec.DeploymentDefaults = nil
+	require.Same(t, deployment, withDeploymentDefaults(deployment, runtime))
+}
+
+func TestWithResourcesAppliesPolicy(t *testing.T) {
+	deployment := deploymentFixture(v1alpha1.DesiredStateDeployed)
+	c := &DeploymentController{Resources: &resourcelimits.Policy{
+		Defaults: v1alpha1.ResourceRequirements{Requests: &v1alpha1.ComputeResources{Memory: "128Mi"}},
+		Max:      v1alpha1.ComputeResources{CPU: "2"},
+	}}
+
+	merged, err := c.withResources(deployment, &v1alpha1.MCPServer{})
+	require.NoError(t, err)
+	require.Equal(t, &v1alpha1.ResourceRequirements{
+		Requests: &v1alpha1.ComputeResources{Memory: "128Mi"},
+		Limits:   &v1alpha1.ComputeResources{CPU: "2"},
+	}, merged.Spec.Resources)
+	require.Nil(t, deployment.Spec.Resources)
+
+	deployment.Spec.Resources = &v1alpha1.ResourceRequirements{Limits: &v1alpha1.ComputeResources{CPU: "4"}}
+	_, err = c.withResources(deployment, &v1alpha1.MCPServer{})
+	require.ErrorIs(t, err, pkgdb.ErrInvalidInput)
+}
+
 func deploymentFixture(desiredState string) *v1alpha1.Deployment {
 	return &v1alpha1.Deployment{
 		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindDeployment},
@@ -42,6 +42,9 @@ type DeploymentDiscoveryController struct {
 	Adapters          map[string]types.DeploymentAdapter
 	StaleAfterMisses  int
 	DeleteAfterMisses int
+	// OpenRuntime, when set, decrypts a Runtime's sealed config before it
+	// is handed to an adapter; see ControllerConfig.OpenRuntime.
+	OpenRuntime func(ctx context.Context, runtime *v1alpha1.Runtime) (*v1alpha1.Runtime, error)
 }
 
 // DeploymentDiscoverySyncResult summarizes one discovery materialization pass.
@@ -114,6 +117,16 @@ func (c *DeploymentDiscoveryController) Sync(ctx context.Context) (DeploymentDis
 		if !ok {
 			continue
 		}
+		if c.OpenRuntime != nil {
+			opened, err := c.OpenRuntime(ctx, runtime)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("discover runtime %s/%s: %w", runtime.Metadata.NamespaceOrDefault(), runtime.Metadata.Name, err)
+				}
+				continue
+			}
+			runtime = opened
+		}
 		discovered, err := source.Discover(ctx, types.DiscoverInput{Runtime: runtime})
 		if err != nil {
 			if firstErr == nil {
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+var (
+	// ErrDryRunUnsupported is returned by DryRun when the Deployment's
+	// runtime adapter cannot render manifests without applying them.
+	ErrDryRunUnsupported = errors.New("runtime adapter does not support dry run")
+	// ErrRenderFailed wraps adapter translation failures in DryRun, the
+	// errors a real apply would hit before touching the runtime.
+	ErrRenderFailed = errors.New("render deployment")
+)
+
+// DryRun resolves deployment's target and runtime and renders the manifests
+// the adapter would apply, exactly as a reconcile would, without touching
+// the runtime or writing anything. deployment need not be stored.
+func (c *DeploymentController) DryRun(ctx context.Context, deployment *v1alpha1.Deployment) ([]types.RenderedManifest, error) {
+	target, err := c.resolveTarget(ctx, deployment)
+	if err != nil {
+		return nil, err
+	}
+	runtime, err := c.resolveRuntime(ctx, deployment)
+	if err != nil {
+		return nil, err
+	}
+	adapter, err := c.resolveAdapter(runtime.Spec.Type)
+	if err != nil {
+		return nil, err
+	}
+	if !adapterSupportsKind(adapter, target.GetKind()) {
+		return nil, fmt.Errorf("%w: adapter %q does not support target kind %q",
+			pkgdb.ErrInvalidInput, adapter.Type(), target.GetKind())
+	}
+	renderer, ok := adapter.(types.DeploymentRenderer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrDryRunUnsupported, adapter.Type())
+	}
+	withResources, err := c.withResources(withDeploymentDefaults(deployment, runtime), target)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := renderer.Render(ctx, types.ApplyInput{
+		Deployment: withResources,
+		Target:     target,
+		Runtime:    runtime,
+		Getter:     c.getterFor(deployment),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: adapter %q: %w", ErrRenderFailed, adapter.Type(), err)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/noop"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+type renderingAdapter struct {
+	*noop.Adapter
+	got     types.ApplyInput
+	applied int
+}
+
+func (a *renderingAdapter) Apply(ctx context.Context, in types.ApplyInput) (*types.ApplyResult, error) {
+	a.applied++
+	return a.Adapter.Apply(ctx, in)
+}
+
+func (a *renderingAdapter) Render(_ context.Context, in types.ApplyInput) ([]types.RenderedManifest, error) {
+	a.got = in
+	return []types.RenderedManifest{{Name: "docker-compose.yaml", Content: "services: {}\n"}}, nil
+}
+
+func TestDeploymentControllerDryRunRendersWithoutApplying(t *testing.T) {
+	runtime := &v1alpha1.Runtime{
+		Metadata: v1alpha1.ObjectMeta{Namespace: v1alpha1.DefaultNamespace, Name: "local"},
+		Spec: v1alpha1.RuntimeSpec{
+			Type:               noop.RuntimeType,
+			DeploymentDefaults: &v1alpha1.DeploymentDefaults{Env: map[string]string{"LOG_LEVEL": "info"}},
+		},
+	}
+	target := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Namespace: v1alpha1.DefaultNamespace, Name: "weather", Tag: "stable"},
+	}
+	getter := func(_ context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		if ref.Kind == v1alpha1.KindRuntime {
+			return runtime, nil
+		}
+		return target, nil
+	}
+	adapter := &renderingAdapter{Adapter: noop.New()}
+	c := &DeploymentController{
+		Getter:   getter,
+		Adapters: map[string]types.DeploymentAdapter{noop.RuntimeType: adapter},
+	}
+
+	manifests, err := c.DryRun(context.Background(), deploymentFixture(v1alpha1.DesiredStateDeployed))
+	require.NoError(t, err)
+	require.Equal(t, []types.RenderedManifest{{Name: "docker-compose.yaml", Content: "services: {}\n"}}, manifests)
+	require.Same(t, target, adapter.got.Target)
+	require.Equal(t, "info", adapter.got.Deployment.Spec.Env["LOG_LEVEL"])
+	require.Zero(t, adapter.applied)
+
+	c.Adapters[noop.RuntimeType] = noop.New()
+	_, err = c.DryRun(context.Background(), deploymentFixture(v1alpha1.DesiredStateDeployed))
+	require.ErrorIs(t, err, ErrDryRunUnsupported)
+}
+
+type fakeSnapshots map[string]v1alpha1.Object
+
+func (f fakeSnapshots) Getter(snapshot string, next v1alpha1.GetterFunc) v1alpha1.GetterFunc {
+	return func(ctx context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		if ref.Kind != v1alpha1.KindMCPServer {
+			return next(ctx, ref)
+		}
+		obj, ok := f[snapshot]
+		if !ok {
+			return nil, v1alpha1.ErrDanglingRef
+		}
+		return obj, nil
+	}
+}
+
+func TestDeploymentControllerDryRunResolvesFromSnapshot(t *testing.T) {
+	runtime := &v1alpha1.Runtime{
+		Metadata: v1alpha1.ObjectMeta{Namespace: v1alpha1.DefaultNamespace, Name: "local"},
+		Spec:     v1alpha1.RuntimeSpec{Type: noop.RuntimeType},
+	}
+	live := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Namespace: v1alpha1.DefaultNamespace, Name: "weather", Tag: "stable"},
+		Spec:     v1alpha1.MCPServerSpec{Description: "republished"},
+	}
+	pinned := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Namespace: v1alpha1.DefaultNamespace, Name: "weather", Tag: "stable"},
+		Spec:     v1alpha1.MCPServerSpec{Description: "as released"},
+	}
+	getter := func(_ context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		if ref.Kind == v1alpha1.KindRuntime {
+			return runtime, nil
+		}
+		return live, nil
+	}
+	adapter := &renderingAdapter{Adapter: noop.New()}
+	c := &DeploymentController{
+		Getter:   getter,
+		Adapters: map[string]types.DeploymentAdapter{noop.RuntimeType: adapter},
+	}
+	deployment := deploymentFixture(v1alpha1.DesiredStateDeployed)
+	deployment.Spec.Snapshot = "2024-06-release"
+
+	_, err := c.DryRun(context.Background(), deployment)
+	require.ErrorIs(t, err, v1alpha1.ErrDanglingRef, "snapshots are not enabled")
+
+	c.Snapshots = fakeSnapshots{"2024-06-release": pinned}
+	_, err = c.DryRun(context.Background(), deployment)
+	require.NoError(t, err)
+	require.Same(t, pinned, adapter.got.Target)
+	got, err := adapter.got.Getter(context.Background(), v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather"})
+	require.NoError(t, err)
+	require.Same(t, pinned, got, "adapters resolve the target's refs from the snapshot too")
+
+	deployment.Spec.Snapshot = "2024-07-release"
+	_, err = c.DryRun(context.Background(), deployment)
+	require.ErrorIs(t, err, v1alpha1.ErrDanglingRef)
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+const (
+	defaultLogShipInterval = 30 * time.Second
+	// logShipTailLines bounds how much backlog one pass asks each adapter
+	// for. Lines a chatty workload wrote beyond it between two passes are
+	// not retained.
+	logShipTailLines = 1000
+)
+
+// DeploymentLogSource reads a Deployment's logs from its runtime adapter.
+// *deployment.AdapterResolver satisfies it.
+type DeploymentLogSource interface {
+	Logs(ctx context.Context, deployment *v1alpha1.Deployment, in types.LogsInput) (<-chan types.LogLine, error)
+}
+
+// DeploymentLogSink retains shipped lines. *v1alpha1store.DeploymentLogStore
+// satisfies it.
+type DeploymentLogSink interface {
+	Latest(ctx context.Context, namespace, name string) (time.Time, error)
+	Append(ctx context.Context, namespace, name string, lines []v1alpha1store.DeploymentLogLine) error
+}
+
+// DeploymentLogShipper periodically copies the log lines runtime adapters
+// report for each Deployment into a retained store, so logs survive
+// container restarts and log rotation. Only lines newer than the newest
+// retained one are copied; lines without a timestamp can't be ordered
+// against what is retained and are skipped.
+type DeploymentLogShipper struct {
+	Stores map[string]*v1alpha1store.Store
+	Source DeploymentLogSource
+	Sink   DeploymentLogSink
+}
+
+// DeploymentLogShipResult summarizes one shipping pass.
+type DeploymentLogShipResult struct {
+	Deployments int
+	Lines       int
+	Failed      int
+}
+
+// Run ships logs every interval until ctx is done.
+func (s *DeploymentLogShipper) Run(ctx context.Context, interval time.Duration) error {
+	if s == nil {
+		return errors.New("deployment log shipper: shipper is required")
+	}
+	if interval <= 0 {
+		interval = defaultLogShipInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		result, err := s.Ship(ctx)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error("deployment log shipping failed", "error", err)
+			}
+		} else if result.Lines > 0 || result.Failed > 0 {
+			logger.Debug("deployment logs shipped", "deployments", result.Deployments, "lines", result.Lines, "failed", result.Failed)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Ship runs one pass over every live Deployment. A Deployment whose logs
+// can't be read or stored counts as Failed without stopping the pass;
+// the error is reserved for listing Deployments.
+func (s *DeploymentLogShipper) Ship(ctx context.Context) (DeploymentLogShipResult, error) {
+	var result DeploymentLogShipResult
+	store := s.Stores[v1alpha1.KindDeployment]
+	if store == nil || s.Source == nil || s.Sink == nil {
+		return result, errors.New("deployment log shipper: Deployment store, source and sink are required")
+	}
+	opts := v1alpha1store.ListOpts{Limit: defaultControllerListPageSize}
+	for {
+		rows, cursor, err := store.List(ctx, opts)
+		if err != nil {
+			return result, fmt.Errorf("deployment log shipper: list Deployments: %w", err)
+		}
+		for _, raw := range rows {
+			deployment, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Deployment {
+				return &v1alpha1.Deployment{}
+			}, raw, v1alpha1.KindDeployment)
+			if err != nil {
+				return result, fmt.Errorf("deployment log shipper: decode Deployment: %w", err)
+			}
+			result.Deployments++
+			n, err := s.shipDeployment(ctx, deployment)
+			if err != nil {
+				if ctx.Err() != nil {
+					return result, ctx.Err()
+				}
+				result.Failed++
+				logger.Debug("deployment log shipping skipped deployment",
+					"namespace", deployment.Metadata.NamespaceOrDefault(), "name", deployment.Metadata.Name, "error", err)
+				continue
+			}
+			result.Lines += n
+		}
+		if cursor == "" {
+			return result, nil
+		}
+		opts.Cursor = cursor
+	}
+}
+
+func (s *DeploymentLogShipper) shipDeployment(ctx context.Context, deployment *v1alpha1.Deployment) (int, error) {
+	namespace, name := deployment.Metadata.NamespaceOrDefault(), deployment.Metadata.Name
+	latest, err := s.Sink.Latest(ctx, namespace, name)
+	if err != nil {
+		return 0, err
+	}
+	ch, err := s.Source.Logs(ctx, deployment, types.LogsInput{TailLines: logShipTailLines})
+	if err != nil {
+		return 0, err
+	}
+	var lines []v1alpha1store.DeploymentLogLine
+	for line := range ch {
+		if line.Timestamp.IsZero() || !line.Timestamp.After(latest) {
+			continue
+		}
+		lines = append(lines, v1alpha1store.DeploymentLogLine{
+			Timestamp: line.Timestamp.UTC(),
+			Stream:    line.Stream,
+			Line:      line.Line,
+		})
+	}
+	if err := s.Sink.Append(ctx, namespace, name, lines); err != nil {
+		return 0, err
+	}
+	return len(lines), nil
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+type fakeLogSource struct {
+	lines []types.LogLine
+	err   error
+}
+
+func (f *fakeLogSource) Logs(context.Context, *v1alpha1.Deployment, types.LogsInput) (<-chan types.LogLine, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	ch := make(chan types.LogLine, len(f.lines))
+	for _, line := range f.lines {
+		ch <- line
+	}
+	close(ch)
+	return ch, nil
+}
+
+type fakeLogSink struct {
+	latest   time.Time
+	appended []v1alpha1store.DeploymentLogLine
+}
+
+func (f *fakeLogSink) Latest(context.Context, string, string) (time.Time, error) {
+	return f.latest, nil
+}
+
+func (f *fakeLogSink) Append(_ context.Context, _, _ string, lines []v1alpha1store.DeploymentLogLine) error {
+	f.appended = append(f.appended, lines...)
+	return nil
+}
+
+func TestDeploymentLogShipperShipsOnlyNewTimestampedLines(t *testing.T) {
+	base := time.Date(2026, 4, 2, 8, 0, 0, 0, time.UTC)
+	source := &fakeLogSource{lines: []types.LogLine{
+		{Timestamp: base, Stream: "stdout", Line: "already shipped"},
+		{Timestamp: base.Add(time.Second), Stream: "stdout", Line: "new"},
+		{Stream: "stderr", Line: "no timestamp"},
+		{Timestamp: base.Add(2 * time.Second), Stream: "stderr", Line: "newer"},
+	}}
+	sink := &fakeLogSink{latest: base}
+	shipper := &DeploymentLogShipper{Source: source, Sink: sink}
+	deployment := &v1alpha1.Deployment{Metadata: v1alpha1.ObjectMeta{Name: "bot"}}
+
+	n, err := shipper.shipDeployment(context.Background(), deployment)
+	if err != nil {
+		t.Fatalf("shipDeployment returned error: %v", err)
+	}
+	if n != 2 || len(sink.appended) != 2 {
+		t.Fatalf("shipped %d lines (%+v), want 2", n, sink.appended)
+	}
+	if sink.appended[0].Line != "new" || sink.appended[1].Line != "newer" {
+		t.Fatalf("shipped lines = %+v", sink.appended)
+	}
+
+	source.err = errors.New("unsupported runtime")
+	if _, err := shipper.shipDeployment(context.Background(), deployment); err == nil {
+		t.Fatal("shipDeployment swallowed the adapter error")
+	}
+}
+
+func TestRunRetentionPruneBoundsDeploymentLogs(t *testing.T) {
+	now := time.Date(2026, 4, 2, 8, 0, 0, 0, time.UTC)
+	logs := &fakeLogPruner{purged: 3, trimmed: 2}
+
+	result, err := RunRetentionPrune(context.Background(), PruneStores{DeploymentLogs: logs},
+		RetentionPolicy{DeploymentLogs: 24 * time.Hour, DeploymentLogMaxBytes: 1024}, now)
+	if err != nil {
+		t.Fatalf("RunRetentionPrune returned error: %v", err)
+	}
+	if result.DeploymentLogs != 5 {
+		t.Fatalf("DeploymentLogs = %d, want 5", result.DeploymentLogs)
+	}
+	if logs.before != now.Add(-24*time.Hour) || logs.maxBytes != 1024 {
+		t.Fatalf("log prune args = before %s max %d", logs.before, logs.maxBytes)
+	}
+
+	logs.before, logs.maxBytes = time.Time{}, 0
+	if _, err := RunRetentionPrune(context.Background(), PruneStores{DeploymentLogs: logs}, RetentionPolicy{}, now); err != nil {
+		t.Fatalf("RunRetentionPrune returned error: %v", err)
+	}
+	if !logs.before.IsZero() || logs.maxBytes != 0 {
+		t.Fatal("deployment logs were pruned with retention disabled")
+	}
+}
+
+type fakeLogPruner struct {
+	before   time.Time
+	maxBytes int64
+	purged   int64
+	trimmed  int64
+}
+
+func (f *fakeLogPruner) PurgeBefore(_ context.Context, before time.Time) (int64, error) {
+	f.before = before
+	return f.purged, nil
+}
+
+func (f *fakeLogPruner) TrimToBytes(_ context.Context, maxBytes int64) (int64, error) {
+	f.maxBytes = maxBytes
+	return f.trimmed, nil
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/telemetry"
+)
+
+// reconcileMetrics records queue depth and per-item reconcile latency for the
+// Deployment controller. A nil *reconcileMetrics is valid and records nothing,
+// so tests and callers without a Meter do not need to special-case it.
+type reconcileMetrics struct {
+	duration metric.Float64Histogram
+	total    metric.Int64Counter
+}
+
+func newReconcileMetrics(meter metric.Meter, queueDepth func() int) (*reconcileMetrics, error) {
+	if meter == nil {
+		return nil, nil
+	}
+	duration, err := meter.Float64Histogram(
+		telemetry.Namespace+".controller.reconcile.duration",
+		metric.WithDescription("Duration of Deployment reconciles in seconds"),
+		metric.WithExplicitBucketBoundaries(
+			0.01, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0, 60.0,
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reconcile duration histogram: %w", err)
+	}
+	total, err := meter.Int64Counter(
+		telemetry.Namespace+".controller.reconciles",
+		metric.WithDescription("Total number of Deployment reconciles by outcome"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reconcile counter: %w", err)
+	}
+	_, err = meter.Int64ObservableGauge(
+		telemetry.Namespace+".controller.queue.depth",
+		metric.WithDescription("Number of Deployment keys waiting in the reconcile queue"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(int64(queueDepth()))
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create queue depth gauge: %w", err)
+	}
+	return &reconcileMetrics{duration: duration, total: total}, nil
+}
+
+func (m *reconcileMetrics) observe(ctx context.Context, outcome string, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	if outcome == "" {
+		outcome = "none"
+	}
+	attrs := metric.WithAttributes(attribute.String("outcome", outcome))
+	m.duration.Record(ctx, elapsed.Seconds(), attrs)
+	m.total.Add(ctx, 1, attrs)
+}
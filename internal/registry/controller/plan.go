@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// Plan reports what a full reconcile would do for every Deployment, grouped by
+// Runtime, without calling any adapter Apply/Remove and without writing status.
+// Target/Runtime resolution and desired-state fingerprinting run exactly as in
+// the real reconcile so the plan matches what the workers would decide.
+func (c *DeploymentController) Plan(ctx context.Context) (arv0.ReconcilePlan, error) {
+	if err := c.validateReconciler(); err != nil {
+		return arv0.ReconcilePlan{}, err
+	}
+	deployments, err := c.listDeployments(ctx)
+	if err != nil {
+		return arv0.ReconcilePlan{}, err
+	}
+
+	byRuntime := map[string]*arv0.RuntimeReconcilePlan{}
+	var plan arv0.ReconcilePlan
+	for _, deployment := range deployments {
+		ref := deployment.Spec.RuntimeRef
+		runtimeNamespace := refNamespace(ref.Namespace, deployment.Metadata.NamespaceOrDefault())
+		key := runtimeNamespace + "/" + ref.Name
+		group, ok := byRuntime[key]
+		if !ok {
+			group = &arv0.RuntimeReconcilePlan{Namespace: runtimeNamespace, Name: ref.Name}
+			if runtime, err := c.resolveRuntime(ctx, deployment); err == nil {
+				group.Type = runtime.Spec.Type
+			}
+			byRuntime[key] = group
+		}
+		entry := c.planDeployment(ctx, deployment)
+		group.Deployments = append(group.Deployments, entry)
+		countPlanAction(&plan.Summary, entry.Action)
+	}
+
+	plan.Runtimes = make([]arv0.RuntimeReconcilePlan, 0, len(byRuntime))
+	for _, group := range byRuntime {
+		sort.Slice(group.Deployments, func(i, j int) bool {
+			if group.Deployments[i].Namespace != group.Deployments[j].Namespace {
+				return group.Deployments[i].Namespace < group.Deployments[j].Namespace
+			}
+			return group.Deployments[i].Name < group.Deployments[j].Name
+		})
+		plan.Runtimes = append(plan.Runtimes, *group)
+	}
+	sort.Slice(plan.Runtimes, func(i, j int) bool {
+		if plan.Runtimes[i].Namespace != plan.Runtimes[j].Namespace {
+			return plan.Runtimes[i].Namespace < plan.Runtimes[j].Namespace
+		}
+		return plan.Runtimes[i].Name < plan.Runtimes[j].Name
+	})
+	return plan, nil
+}
+
+func (c *DeploymentController) planDeployment(ctx context.Context, deployment *v1alpha1.Deployment) arv0.DeploymentPlanEntry {
+	target := deployment.Spec.TargetRef
+	entry := arv0.DeploymentPlanEntry{
+		Namespace: deployment.Metadata.NamespaceOrDefault(),
+		Name:      deployment.Metadata.Name,
+		Target:    target.Kind + "/" + refNamespace(target.Namespace, deployment.Metadata.NamespaceOrDefault()) + "/" + target.Name,
+	}
+	planned := func(action, reason string) arv0.DeploymentPlanEntry {
+		entry.Action = action
+		entry.Reason = reason
+		return entry
+	}
+
+	if v1alpha1.IsDiscoveredDeployment(deployment) {
+		return planned(arv0.ReconcilePlanActionUnmanaged, "provider-observed workload is not managed by the registry")
+	}
+	action, err := deploymentAction(deployment)
+	if err != nil {
+		return planned(arv0.ReconcilePlanActionError, err.Error())
+	}
+	previous, err := appliedDetails(deployment)
+	if err != nil {
+		return planned(arv0.ReconcilePlanActionError, err.Error())
+	}
+
+	if action == ReconcileActionDelete {
+		if previous.LastAppliedFingerprint == "" {
+			return planned(arv0.ReconcilePlanActionNoop, "deployment was never applied")
+		}
+		if deployment.Metadata.DeletionTimestamp != nil {
+			return planned(arv0.ReconcilePlanActionDelete, "deployment is being deleted")
+		}
+		return planned(arv0.ReconcilePlanActionDelete, "desiredState is undeployed")
+	}
+
+	fingerprint, err := c.planFingerprint(ctx, deployment)
+	if err != nil {
+		if errors.Is(err, v1alpha1.ErrDanglingRef) {
+			return planned(arv0.ReconcilePlanActionBlocked, err.Error())
+		}
+		return planned(arv0.ReconcilePlanActionError, err.Error())
+	}
+	skip, err := shouldSkipApply(deployment, fingerprint, deploymentForceToken(deployment))
+	if err != nil {
+		return planned(arv0.ReconcilePlanActionError, err.Error())
+	}
+	switch {
+	case skip:
+		return planned(arv0.ReconcilePlanActionNoop, "deployment desired input unchanged")
+	case previous.LastAppliedFingerprint == "":
+		return planned(arv0.ReconcilePlanActionCreate, "deployment has not been applied")
+	case previous.LastAppliedFingerprint != fingerprint:
+		return planned(arv0.ReconcilePlanActionUpdate, "deployment desired input changed")
+	default:
+		return planned(arv0.ReconcilePlanActionUpdate, "force annotation requests re-apply")
+	}
+}
+
+// planFingerprint resolves everything apply would resolve and returns the
+// desired-state fingerprint, stopping short of the adapter side effect.
+func (c *DeploymentController) planFingerprint(ctx context.Context, deployment *v1alpha1.Deployment) (string, error) {
+	target, err := c.resolveTarget(ctx, deployment)
+	if err != nil {
+		return "", err
+	}
+	runtime, err := c.resolveRuntime(ctx, deployment)
+	if err != nil {
+		return "", err
+	}
+	adapter, err := c.resolveAdapter(runtime.Spec.Type)
+	if err != nil {
+		return "", err
+	}
+	if !adapterSupportsKind(adapter, target.GetKind()) {
+		return "", fmt.Errorf("adapter %q does not support target kind %q", adapter.Type(), target.GetKind())
+	}
+	withResources, err := c.withResources(withDeploymentDefaults(deployment, runtime), target)
+	if err != nil {
+		return "", err
+	}
+	result, err := desiredApplyFingerprint(ctx, adapter, types.ApplyInput{
+		Deployment: withResources,
+		Target:     target,
+		Runtime:    runtime,
+		Getter:     c.getterFor(deployment),
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.Fingerprint, nil
+}
+
+func appliedDetails(deployment *v1alpha1.Deployment) (deploymentControllerDetails, error) {
+	var details deploymentControllerDetails
+	if _, err := deployment.Status.GetDetailsKey(deploymentControllerDetailsKey, &details); err != nil {
+		return deploymentControllerDetails{}, err
+	}
+	return details, nil
+}
+
+func countPlanAction(summary *arv0.ReconcilePlanSummary, action string) {
+	switch action {
+	case arv0.ReconcilePlanActionCreate:
+		summary.Create++
+	case arv0.ReconcilePlanActionUpdate:
+		summary.Update++
+	case arv0.ReconcilePlanActionDelete:
+		summary.Delete++
+	case arv0.ReconcilePlanActionNoop:
+		summary.Noop++
+	case arv0.ReconcilePlanActionBlocked:
+		summary.Blocked++
+	case arv0.ReconcilePlanActionUnmanaged:
+		summary.Unmanaged++
+	case arv0.ReconcilePlanActionError:
+		summary.Error++
+	}
+}
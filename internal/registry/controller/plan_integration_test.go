@@ -0,0 +1,46 @@
+//go:build integration
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+func TestDeploymentController_PlanReportsActionsWithoutAdapterCalls(t *testing.T) {
+	ctx := context.Background()
+	stores := newControllerTestStores(t)
+	seedRuntime(t, stores, "local")
+	seedMCPServer(t, stores, "weather")
+	seedDeployment(t, stores, "weather-new", v1alpha1.DesiredStateDeployed)
+	seedDeployment(t, stores, "weather-applied", v1alpha1.DesiredStateDeployed)
+
+	adapter := &recordingDeploymentAdapter{}
+	controller := newDeploymentTestController(stores, adapter)
+	_, err := controller.reconcileDeployment(ctx, v1alpha1store.ResourceKey{Kind: v1alpha1.KindDeployment, Namespace: "default", Name: "weather-applied"})
+	require.NoError(t, err)
+	_, err = controller.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), adapter.applyCalls.Load())
+
+	plan, err := controller.Plan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), adapter.applyCalls.Load(), "plan must not call adapter Apply")
+	require.Len(t, plan.Runtimes, 1)
+	require.Equal(t, "local", plan.Runtimes[0].Name)
+
+	actions := map[string]string{}
+	for _, entry := range plan.Runtimes[0].Deployments {
+		actions[entry.Name] = entry.Action
+	}
+	require.Equal(t, arv0.ReconcilePlanActionCreate, actions["weather-new"])
+	require.Equal(t, arv0.ReconcilePlanActionNoop, actions["weather-applied"])
+	require.Equal(t, 1, plan.Summary.Create)
+	require.Equal(t, 1, plan.Summary.Noop)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"maps"
 	"slices"
+	"time"
 
 	"k8s.io/client-go/util/workqueue"
 
@@ -35,13 +36,33 @@ func (c *DeploymentController) processQueueItem(
 	key deploymentQueueKey,
 ) {
 	defer queue.Done(key)
+	c.initThrottle()
+	started := time.Now()
 	outcome, message, err := c.reconcileKey(ctx, key)
+	if errors.Is(err, errRuntimeBusy) {
+		// The Runtime is at capacity; free this worker for other
+		// Runtimes and retry shortly, without counting it as a failure.
+		c.metrics.observe(ctx, "throttled", time.Since(started))
+		logger.Debug("deployment reconcile deferred", "namespace", key.Namespace, "name", key.Name, "reason", err)
+		queue.AddAfter(key, runtimeSlotRetryDelay)
+		return
+	}
+	if errors.Is(err, pkgdb.ErrConflict) {
+		// Another reconcile holds the target + runtime lock; retry once
+		// it has had time to finish.
+		c.metrics.observe(ctx, "locked", time.Since(started))
+		logger.Debug("deployment reconcile deferred", "namespace", key.Namespace, "name", key.Name, "reason", err)
+		queue.AddRateLimited(key)
+		return
+	}
 	if err != nil {
+		c.metrics.observe(ctx, "error", time.Since(started))
 		logger.Error("deployment reconcile failed", "namespace", key.Namespace, "name", key.Name, "error", err)
 		queue.AddRateLimited(key)
 		return
 	}
 	queue.Forget(key)
+	c.metrics.observe(ctx, outcome, time.Since(started))
 	if outcome != "" {
 		logger.Debug("deployment reconciled", "namespace", key.Namespace, "name", key.Name, "outcome", outcome, "message", message)
 	}
@@ -62,6 +83,16 @@ func (c *DeploymentController) reconcileKey(ctx context.Context, key deploymentQ
 	if err != nil {
 		return "", "", err
 	}
+	release, err := c.acquireRuntimeSlot(deployment)
+	if err != nil {
+		return "", "", err
+	}
+	defer release()
+	unlock, err := c.acquireDeploymentLock(ctx, deployment, action)
+	if err != nil {
+		return "", "", err
+	}
+	defer unlock()
 
 	switch action {
 	case ReconcileActionApply:
@@ -96,11 +127,15 @@ func (c *DeploymentController) apply(ctx context.Context, deployment *v1alpha1.D
 		return "", "", fmt.Errorf("%w: adapter %q does not support target kind %q",
 			pkgdb.ErrInvalidInput, adapter.Type(), target.GetKind())
 	}
+	withResources, err := c.withResources(withDeploymentDefaults(deployment, runtime), target)
+	if err != nil {
+		return "", "", err
+	}
 	input := types.ApplyInput{
-		Deployment: deployment,
+		Deployment: withResources,
 		Target:     target,
 		Runtime:    runtime,
-		Getter:     c.Getter,
+		Getter:     c.getterFor(deployment),
 	}
 	fingerprintResult, err := desiredApplyFingerprint(ctx, adapter, input)
 	if err != nil {
@@ -116,19 +151,135 @@ func (c *DeploymentController) apply(ctx context.Context, deployment *v1alpha1.D
 	} else if skip {
 		return "unchanged", "deployment desired input unchanged", nil
 	}
+	if agent, ok := target.(*v1alpha1.Agent); ok {
+		if err := c.syncSubAgentDeployments(ctx, deployment, agent); err != nil {
+			if errors.Is(err, v1alpha1.ErrDanglingRef) {
+				return c.blockReference(ctx, deployment, err)
+			}
+			return "", "", err
+		}
+	}
 	result, err := adapter.Apply(ctx, input)
 	if err != nil {
 		if errors.Is(err, v1alpha1.ErrDanglingRef) {
 			return c.blockReference(ctx, deployment, err)
 		}
-		return "", "", fmt.Errorf("adapter %q apply: %w", adapter.Type(), err)
+		err = fmt.Errorf("adapter %q apply: %w", adapter.Type(), err)
+		c.notifyApplyFailed(ctx, deployment, err)
+		return "", "", err
+	}
+	c.clearApplyFailed(deployment)
+	if agent, ok := target.(*v1alpha1.Agent); ok {
+		if result, err = c.pinResolvedTags(ctx, input.Getter, agent, result); err != nil {
+			return "", "", err
+		}
 	}
 	if err := c.persistApplyResult(ctx, deployment, result, fingerprint, forceToken, fingerprintResult.Dependencies); err != nil {
 		return "", "", err
 	}
+	c.recordManifests(ctx, deployment, adapter.Type(), result)
+	if c.Usage != nil {
+		meta := target.GetMetadata()
+		c.Usage.RecordDeploy(target.GetKind(), meta.Namespace, meta.Name)
+	}
 	return "success", "deployment applied", nil
 }
 
+// pinResolvedTags adds to result the tags the agent's version-constraint
+// MCP server refs resolved to through getter, recorded in
+// v1alpha1.DeploymentResolvedTagsAnnotation, or clears the annotation when
+// the agent has none.
+func (c *DeploymentController) pinResolvedTags(ctx context.Context, getter v1alpha1.GetterFunc, agent *v1alpha1.Agent, result *types.ApplyResult) (*types.ApplyResult, error) {
+	resolved, err := v1alpha1.ResolveAgentTagConstraints(ctx, getter, agent)
+	if err != nil {
+		return nil, fmt.Errorf("pin resolved tags: %w", err)
+	}
+	value := ""
+	if len(resolved) > 0 {
+		data, err := json.Marshal(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("pin resolved tags: %w", err)
+		}
+		value = string(data)
+	}
+	if result == nil {
+		result = &types.ApplyResult{}
+	}
+	metadata := maps.Clone(result.RuntimeMetadata)
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	metadata[v1alpha1.DeploymentResolvedTagsAnnotation] = value
+	result.RuntimeMetadata = metadata
+	return result, nil
+}
+
+// DeployRecorder counts deploys of an artifact. *usagestats.Recorder
+// satisfies it.
+type DeployRecorder interface {
+	RecordDeploy(kind, namespace, name string)
+}
+
+// withDeploymentDefaults returns deployment with its Runtime's
+// deploymentDefaults merged under its spec, as adapters should see it. The
+// stored deployment is left untouched so status writes go to the original.
+func withDeploymentDefaults(deployment *v1alpha1.Deployment, runtime *v1alpha1.Runtime) *v1alpha1.Deployment {
+	if runtime == nil || runtime.Spec.DeploymentDefaults == nil {
+		return deployment
+	}
+	merged := *deployment
+	merged.Spec = v1alpha1.MergeDeploymentDefaults(deployment.Spec, runtime.Spec.DeploymentDefaults)
+	return &merged
+}
+
+// withResources returns deployment with spec.resources set to the
+// requests and limits its container gets under the Resources policy. A
+// policy violation matches pkgdb.ErrInvalidInput.
+func (c *DeploymentController) withResources(deployment *v1alpha1.Deployment, target v1alpha1.Object) (*v1alpha1.Deployment, error) {
+	resources, err := c.Resources.Resolve(deployment.Spec.Resources, target)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", pkgdb.ErrInvalidInput, err)
+	}
+	merged := *deployment
+	merged.Spec.Resources = resources
+	return &merged, nil
+}
+
+// DeploymentFailureNotifier receives Deployment apply failures.
+// DeploymentFailed is called synchronously from the reconcile worker, so
+// implementations must not block.
+type DeploymentFailureNotifier interface {
+	DeploymentFailed(ctx context.Context, namespace, name string, generation int64, reason string)
+}
+
+// notifyApplyFailed reports an adapter apply failure to FailureNotifier.
+// Failed applies are retried with backoff, so the notification is sent only
+// for the first failure of each generation.
+func (c *DeploymentController) notifyApplyFailed(ctx context.Context, deployment *v1alpha1.Deployment, err error) {
+	if c.FailureNotifier == nil {
+		return
+	}
+	key := deploymentQueueKey{Namespace: deployment.Metadata.Namespace, Name: deployment.Metadata.Name}
+	generation := deployment.Metadata.Generation
+	c.failedMu.Lock()
+	if c.failedGenerations == nil {
+		c.failedGenerations = map[deploymentQueueKey]int64{}
+	}
+	if last, ok := c.failedGenerations[key]; ok && last == generation {
+		c.failedMu.Unlock()
+		return
+	}
+	c.failedGenerations[key] = generation
+	c.failedMu.Unlock()
+	c.FailureNotifier.DeploymentFailed(ctx, key.Namespace, key.Name, generation, err.Error())
+}
+
+func (c *DeploymentController) clearApplyFailed(deployment *v1alpha1.Deployment) {
+	c.failedMu.Lock()
+	delete(c.failedGenerations, deploymentQueueKey{Namespace: deployment.Metadata.Namespace, Name: deployment.Metadata.Name})
+	c.failedMu.Unlock()
+}
+
 func (c *DeploymentController) remove(ctx context.Context, deployment *v1alpha1.Deployment) (string, string, error) {
 	runtime, err := c.resolveRuntime(ctx, deployment)
 	if err != nil {
@@ -148,6 +299,10 @@ func (c *DeploymentController) remove(ctx context.Context, deployment *v1alpha1.
 	if err := c.persistRemoveResult(ctx, deployment, result); err != nil {
 		return "", "", err
 	}
+	if err := c.deleteSubAgentDeployments(ctx, deployment); err != nil {
+		return "", "", err
+	}
+	c.forgetManifests(ctx, deployment)
 	if deployment.Metadata.DeletionTimestamp != nil {
 		if err := c.finalizeDeletedDeployment(ctx, deployment); err != nil {
 			return "", "", err
@@ -167,6 +322,7 @@ func (c *DeploymentController) handleRemoveRuntimeError(
 	if deployment.Metadata.DeletionTimestamp == nil {
 		return c.blockReference(ctx, deployment, cause)
 	}
+	c.forgetManifests(ctx, deployment)
 	if err := c.finalizeDeletedDeployment(ctx, deployment); err != nil {
 		return "", "", err
 	}
@@ -215,13 +371,99 @@ func (c *DeploymentController) loadDeployment(ctx context.Context, key deploymen
 	return deployment, true, nil
 }
 
+// acquireRuntimeSlot takes a reconcile slot on the Deployment's Runtime,
+// or returns an error matching errRuntimeBusy when it has none free. The
+// limit is keyed by runtimeRef so many Deployments landing on one Docker
+// daemon or cluster are serialized without starving other Runtimes.
+func (c *DeploymentController) acquireRuntimeSlot(deployment *v1alpha1.Deployment) (func(), error) {
+	c.initThrottle()
+	ref := deployment.Spec.RuntimeRef
+	key := refNamespace(ref.Namespace, deployment.Metadata.NamespaceOrDefault()) + "/" + ref.Name
+	release, ok := c.limiter.tryAcquire(key)
+	if !ok {
+		return nil, fmt.Errorf("runtime %s: %w", key, errRuntimeBusy)
+	}
+	return release, nil
+}
+
+// DeploymentLocker serializes adapter work per Deployment target +
+// runtime. Acquire must not wait: it returns an error matching
+// pkgdb.ErrConflict when another reconcile holds the lock.
+// *v1alpha1store.DeploymentLocks satisfies it.
+type DeploymentLocker interface {
+	Acquire(ctx context.Context, deployment *v1alpha1.Deployment, operation string) (release func(), err error)
+}
+
+var _ DeploymentLocker = (*v1alpha1store.DeploymentLocks)(nil)
+
+// DeploymentManifestRecorder keeps the manifests each Deployment's last
+// successful apply produced. *v1alpha1store.DeploymentManifestStore
+// satisfies it.
+type DeploymentManifestRecorder interface {
+	Record(ctx context.Context, m v1alpha1store.DeploymentManifests) error
+	Delete(ctx context.Context, namespace, name string) error
+}
+
+var _ DeploymentManifestRecorder = (*v1alpha1store.DeploymentManifestStore)(nil)
+
+// recordManifests stores the manifests an apply returned. The runtime
+// already holds the new state, so a failed write is logged rather than
+// failing the reconcile; the next apply overwrites the record.
+func (c *DeploymentController) recordManifests(ctx context.Context, deployment *v1alpha1.Deployment, runtimeType string, result *types.ApplyResult) {
+	if c.Manifests == nil || result == nil || result.Manifests == nil {
+		return
+	}
+	manifests := make([]v1alpha1store.DeploymentManifest, 0, len(result.Manifests))
+	for _, m := range result.Manifests {
+		manifests = append(manifests, v1alpha1store.DeploymentManifest{Name: m.Name, Content: m.Content})
+	}
+	if err := c.Manifests.Record(ctx, v1alpha1store.DeploymentManifests{
+		Namespace:  deployment.Metadata.Namespace,
+		Name:       deployment.Metadata.Name,
+		Generation: deployment.Metadata.Generation,
+		Runtime:    runtimeType,
+		Manifests:  manifests,
+	}); err != nil {
+		logger.Warn("record deployment manifests failed",
+			"namespace", deployment.Metadata.Namespace, "name", deployment.Metadata.Name, "error", err)
+	}
+}
+
+// forgetManifests drops a removed Deployment's recorded manifests.
+func (c *DeploymentController) forgetManifests(ctx context.Context, deployment *v1alpha1.Deployment) {
+	if c.Manifests == nil {
+		return
+	}
+	if err := c.Manifests.Delete(ctx, deployment.Metadata.Namespace, deployment.Metadata.Name); err != nil {
+		logger.Warn("delete deployment manifests failed",
+			"namespace", deployment.Metadata.Namespace, "name", deployment.Metadata.Name, "error", err)
+	}
+}
+
+// acquireDeploymentLock takes the Deployment's target + runtime lock for
+// action. Without Locks it is a no-op.
+func (c *DeploymentController) acquireDeploymentLock(ctx context.Context, deployment *v1alpha1.Deployment, action ReconcileAction) (func(), error) {
+	if c.Locks == nil {
+		return func() {}, nil
+	}
+	operation := v1alpha1store.DeploymentLockApply
+	if action == ReconcileActionDelete {
+		operation = v1alpha1store.DeploymentLockRemove
+	}
+	release, err := c.Locks.Acquire(ctx, deployment, operation)
+	if err != nil {
+		return nil, fmt.Errorf("lock deployment %s/%s: %w", deployment.Metadata.NamespaceOrDefault(), deployment.Metadata.Name, err)
+	}
+	return release, nil
+}
+
 func (c *DeploymentController) resolveTarget(ctx context.Context, deployment *v1alpha1.Deployment) (v1alpha1.Object, error) {
 	if c.Getter == nil {
 		return nil, errors.New("deployment controller: getter is nil")
 	}
 	ref := deployment.Spec.TargetRef
 	ref.Namespace = refNamespace(ref.Namespace, deployment.Metadata.NamespaceOrDefault())
-	obj, err := c.Getter(ctx, ref)
+	obj, err := c.getterFor(deployment)(ctx, ref)
 	if err != nil {
 		return nil, fmt.Errorf("resolve targetRef %s/%s@%s: %w", ref.Namespace, ref.Name, ref.Tag, err)
 	}
@@ -231,6 +473,28 @@ func (c *DeploymentController) resolveTarget(ctx context.Context, deployment *v1
 	return obj, nil
 }
 
+// SnapshotResolver resolves refs from a catalog snapshot.
+// *snapshots.Catalog satisfies it.
+type SnapshotResolver interface {
+	Getter(snapshot string, next v1alpha1.GetterFunc) v1alpha1.GetterFunc
+}
+
+// getterFor returns the getter deployment's refs resolve through: c.Getter,
+// or for a Deployment pinned to a catalog snapshot, the snapshot's, which
+// falls back to c.Getter for the refs the snapshot doesn't pin.
+func (c *DeploymentController) getterFor(deployment *v1alpha1.Deployment) v1alpha1.GetterFunc {
+	snapshot := deployment.Spec.Snapshot
+	if snapshot == "" {
+		return c.Getter
+	}
+	if c.Snapshots == nil {
+		return func(context.Context, v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+			return nil, fmt.Errorf("%w: catalog snapshot %q: snapshots are not enabled", v1alpha1.ErrDanglingRef, snapshot)
+		}
+	}
+	return c.Snapshots.Getter(snapshot, c.Getter)
+}
+
 func (c *DeploymentController) resolveRuntime(ctx context.Context, deployment *v1alpha1.Deployment) (*v1alpha1.Runtime, error) {
 	if c.Getter == nil {
 		return nil, errors.New("deployment controller: getter is nil")
@@ -284,7 +548,13 @@ func (c *DeploymentController) persistApplyResult(
 			if annotations == nil {
 				annotations = map[string]string{}
 			}
-			maps.Copy(annotations, result.RuntimeMetadata)
+			for key, value := range result.RuntimeMetadata {
+				if value == "" {
+					delete(annotations, key)
+					continue
+				}
+				annotations[key] = value
+			}
 			return annotations
 		}
 	}
@@ -269,6 +269,45 @@ func TestDeploymentController_ReappliesAgentDeploymentWhenReferencedMCPServerCha
 		"dependency material hash should change after the referenced MCPServer spec changes")
 }
 
+func TestDeploymentController_ManagesSubAgentDeployments(t *testing.T) {
+	ctx := context.Background()
+	stores := newControllerTestStores(t)
+	seedRuntime(t, stores, "local")
+	seedAgent(t, stores, "writer", nil)
+	_, err := stores[v1alpha1.KindAgent].Upsert(ctx, &v1alpha1.Agent{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "planner"},
+		Spec: v1alpha1.AgentSpec{
+			Title:     "planner",
+			SubAgents: []v1alpha1.ResourceRef{{Kind: v1alpha1.KindAgent, Name: "writer"}},
+		},
+	})
+	require.NoError(t, err)
+	seedAgentDeployment(t, stores, "planner-deploy", "planner", v1alpha1.DesiredStateDeployed)
+
+	adapter := &recordingDeploymentAdapter{}
+	controller := newDeploymentTestController(stores, adapter)
+	_, err = controller.FullReconcile(ctx)
+	require.NoError(t, err)
+	_, err = controller.RunOnce(ctx)
+	require.NoError(t, err)
+
+	child := loadDeployment(t, stores, "planner-deploy-writer")
+	require.Equal(t, "planner-deploy", child.Metadata.Annotations[v1alpha1.DeploymentParentAnnotation])
+	require.Equal(t, "writer", child.Spec.TargetRef.Name)
+	require.Equal(t, v1alpha1.KindAgent, child.Spec.TargetRef.Kind)
+	require.Equal(t, "local", child.Spec.RuntimeRef.Name)
+	require.Contains(t, loadDeploymentFinalizers(t, stores, "planner-deploy-writer"), DeploymentControllerFinalizer)
+
+	require.NoError(t, stores[v1alpha1.KindDeployment].Delete(ctx, "default", "planner-deploy", ""))
+	require.Eventually(t, func() bool {
+		_, err := controller.RunOnce(ctx)
+		return err == nil && adapter.removeCalls.Load() >= 1
+	}, time.Second, 10*time.Millisecond)
+	requireDeploymentMissing(t, stores, "planner-deploy")
+	require.NotNil(t, loadDeployment(t, stores, "planner-deploy-writer").Metadata.DeletionTimestamp,
+		"deleting the parent deletes its sub-agent Deployments")
+}
+
 func TestDeploymentController_DeleteWaitsForRemoveThenPurgesFinalizedRow(t *testing.T) {
 	ctx := context.Background()
 	stores := newControllerTestStores(t)
@@ -336,6 +375,30 @@ func TestDeploymentController_RemoveFailureKeepsFinalizerAndRetries(t *testing.T
 	requireDeploymentMissing(t, stores, deployment.Metadata.Name)
 }
 
+func TestDeploymentController_ApplyFailureNotifiesOncePerGeneration(t *testing.T) {
+	ctx := context.Background()
+	stores := newControllerTestStores(t)
+	seedRuntime(t, stores, "local")
+	seedMCPServer(t, stores, "weather")
+	deployment := seedDeployment(t, stores, "apply-fails", v1alpha1.DesiredStateDeployed)
+
+	adapter := &recordingDeploymentAdapter{applyErr: errors.New("image pull failed")}
+	notifier := &recordingFailureNotifier{}
+	controller := newDeploymentTestController(stores, adapter)
+	controller.FailureNotifier = notifier
+
+	for range 2 {
+		_, err := controller.FullReconcile(ctx)
+		require.NoError(t, err)
+		_, err = controller.RunOnce(ctx)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(2), adapter.applyCalls.Load())
+	require.Equal(t, []string{"apply-fails"}, notifier.names)
+	require.Equal(t, deployment.Metadata.Generation, notifier.generations[0])
+	require.Contains(t, notifier.reasons[0], "image pull failed")
+}
+
 func TestDeploymentController_DeleteAbandonsPendingApplyWork(t *testing.T) {
 	ctx := context.Background()
 	stores := newControllerTestStores(t)
@@ -429,6 +492,139 @@ func TestDeploymentController_QueuedDeploymentUsesLatestGeneration(t *testing.T)
 	require.Equal(t, latest.Metadata.Generation, adapter.lastApplyGeneration.Load())
 }
 
+func TestDeploymentController_DefersApplyWhileDeploymentLockHeld(t *testing.T) {
+	ctx := context.Background()
+	pool := v1alpha1store.NewTestPool(t)
+	stores := v1alpha1store.NewStores(pool, v1alpha1store.TestSchemaRegistry())
+	seedRuntime(t, stores, "local")
+	seedMCPServer(t, stores, "weather")
+	deployment := seedDeployment(t, stores, "weather-deploy", v1alpha1.DesiredStateDeployed)
+
+	// Another replica is mid-rollout of the same server on the same runtime.
+	release, err := v1alpha1store.NewDeploymentLocks(pool, v1alpha1store.TestSchema(), "replica-b").
+		Acquire(ctx, deployment, v1alpha1store.DeploymentLockApply)
+	require.NoError(t, err)
+
+	adapter := &recordingDeploymentAdapter{}
+	controller := newDeploymentTestController(stores, adapter)
+	controller.Locks = v1alpha1store.NewDeploymentLocks(pool, v1alpha1store.TestSchema(), "replica-a")
+	_, err = controller.FullReconcile(ctx)
+	require.NoError(t, err)
+	processed, err := controller.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, processed)
+	require.Zero(t, adapter.applyCalls.Load(), "a held lock defers the apply")
+
+	release()
+	require.Eventually(t, func() bool {
+		_, err := controller.RunOnce(ctx)
+		return err == nil && adapter.applyCalls.Load() == 1
+	}, 5*time.Second, 10*time.Millisecond, "the deferred apply is retried once the lock is free")
+}
+
+func TestDeploymentController_SaturatedRuntimeDoesNotBlockOthers(t *testing.T) {
+	ctx := context.Background()
+	stores := newControllerTestStores(t)
+	seedRuntime(t, stores, "local")
+	seedRuntime(t, stores, "local-b")
+	seedMCPServer(t, stores, "weather")
+	onA := seedDeployment(t, stores, "weather-a", v1alpha1.DesiredStateDeployed)
+	_, err := stores[v1alpha1.KindDeployment].Upsert(ctx, &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather-b"},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:    v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: v1alpha1store.DefaultTag()},
+			RuntimeRef:   v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "local-b"},
+			DesiredState: v1alpha1.DesiredStateDeployed,
+		},
+	}, v1alpha1store.UpsertOpts{InitialFinalizers: []string{DeploymentControllerFinalizer}})
+	require.NoError(t, err)
+
+	adapter := &recordingDeploymentAdapter{}
+	controller := newDeploymentTestController(stores, adapter)
+	controller.RuntimeConcurrency = 1
+	// Another worker holds runtime A's only slot.
+	controller.initThrottle()
+	releaseA, ok := controller.limiter.tryAcquire("default/local")
+	require.True(t, ok)
+
+	_, err = controller.FullReconcile(ctx)
+	require.NoError(t, err)
+	// One worker drains the queue: A's Deployment is requeued instead of
+	// holding it, so B's is applied.
+	for range 2 {
+		processed, err := controller.RunOnce(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, processed)
+	}
+	require.Equal(t, int32(1), adapter.applyCalls.Load())
+	require.NotNil(t, loadDeployment(t, stores, "weather-b").Status.GetCondition("Ready"))
+	require.Nil(t, loadDeployment(t, stores, onA.Metadata.Name).Status.GetCondition("Ready"))
+
+	releaseA()
+	require.Eventually(t, func() bool {
+		_, err := controller.RunOnce(ctx)
+		return err == nil && adapter.applyCalls.Load() == 2
+	}, 5*time.Second, 10*time.Millisecond, "runtime A's Deployment is retried once a slot frees up")
+}
+
+func TestDeploymentController_RecordsManifestsUntilRemoved(t *testing.T) {
+	ctx := context.Background()
+	pool := v1alpha1store.NewTestPool(t)
+	stores := v1alpha1store.NewStores(pool, v1alpha1store.TestSchemaRegistry())
+	seedRuntime(t, stores, "local")
+	seedMCPServer(t, stores, "weather")
+	deployment := seedDeployment(t, stores, "weather-deploy", v1alpha1.DesiredStateDeployed)
+	manifests := v1alpha1store.NewDeploymentManifestStore(pool, v1alpha1store.TestSchema())
+
+	adapter := &recordingDeploymentAdapter{manifests: []types.RenderedManifest{{Name: "docker-compose.yaml", Content: "services: {}\n"}}}
+	controller := newDeploymentTestController(stores, adapter)
+	controller.Manifests = manifests
+	_, err := controller.FullReconcile(ctx)
+	require.NoError(t, err)
+	_, err = controller.RunOnce(ctx)
+	require.NoError(t, err)
+
+	got, err := manifests.Get(ctx, "default", deployment.Metadata.Name)
+	require.NoError(t, err)
+	require.Equal(t, "Local", got.Runtime)
+	require.Equal(t, []v1alpha1store.DeploymentManifest{{Name: "docker-compose.yaml", Content: "services: {}\n"}}, got.Manifests)
+
+	require.NoError(t, stores[v1alpha1.KindDeployment].Delete(ctx, "default", deployment.Metadata.Name, ""))
+	_, err = controller.FullReconcile(ctx)
+	require.NoError(t, err)
+	_, err = controller.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), adapter.removeCalls.Load())
+	_, err = manifests.Get(ctx, "default", deployment.Metadata.Name)
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+}
+
+type deployCounts map[string]int
+
+func (d deployCounts) RecordDeploy(kind, namespace, name string) {
+	d[kind+" "+namespace+"/"+name]++
+}
+
+func TestDeploymentController_CountsDeploysOfTarget(t *testing.T) {
+	ctx := context.Background()
+	stores := newControllerTestStores(t)
+	seedRuntime(t, stores, "local")
+	seedMCPServer(t, stores, "weather")
+	seedDeployment(t, stores, "weather-deploy", v1alpha1.DesiredStateDeployed)
+
+	deploys := deployCounts{}
+	controller := newDeploymentTestController(stores, nil)
+	controller.Usage = deploys
+	for range 2 {
+		_, err := controller.FullReconcile(ctx)
+		require.NoError(t, err)
+		_, err = controller.RunOnce(ctx)
+		require.NoError(t, err)
+	}
+	require.Equal(t, deployCounts{"MCPServer default/weather": 1}, deploys,
+		"an unchanged re-reconcile is not another deploy")
+}
+
 func newControllerTestStores(t *testing.T) map[string]*v1alpha1store.Store {
 	t.Helper()
 	pool := v1alpha1store.NewTestPool(t)
@@ -564,6 +760,7 @@ type recordingDeploymentAdapter struct {
 	lastApplyGeneration atomic.Int64
 	applyErr            error
 	removeErr           error
+	manifests           []types.RenderedManifest
 }
 
 func (a *recordingDeploymentAdapter) Type() string { return "Local" }
@@ -587,6 +784,7 @@ func (a *recordingDeploymentAdapter) Apply(_ context.Context, input types.ApplyI
 			Reason:             "Applied",
 			ObservedGeneration: 1,
 		}},
+		Manifests: a.manifests,
 	}, nil
 }
 
@@ -609,3 +807,15 @@ func (a *recordingDeploymentAdapter) Logs(context.Context, types.LogsInput) (<-c
 	close(ch)
 	return ch, nil
 }
+
+type recordingFailureNotifier struct {
+	names       []string
+	generations []int64
+	reasons     []string
+}
+
+func (n *recordingFailureNotifier) DeploymentFailed(_ context.Context, _, name string, generation int64, reason string) {
+	n.names = append(n.names, name)
+	n.generations = append(n.generations, generation)
+	n.reasons = append(n.reasons, reason)
+}
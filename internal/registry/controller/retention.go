@@ -5,21 +5,32 @@ import (
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
 )
 
 const defaultRetentionPruneInterval = time.Hour
 
 // RetentionPolicy is the bounded-history contract for the controller event
-// replay log. Durations <= 0 disable pruning.
+// replay log, soft-deleted artifact tags and retained deployment logs.
+// Durations and sizes <= 0 disable pruning.
 type RetentionPolicy struct {
 	ControlPlaneEvents time.Duration
 	EventKeepAfterRev  int64
 	BatchLimit         int
+	// DeletedArtifacts is how long a deleted artifact tag stays restorable
+	// before it is purged.
+	DeletedArtifacts time.Duration
+	// DeploymentLogs is how long shipped deployment log lines are kept.
+	DeploymentLogs time.Duration
+	// DeploymentLogMaxBytes caps the log text kept per Deployment; its
+	// oldest lines beyond the cap are dropped.
+	DeploymentLogMaxBytes int64
 }
 
-// Enabled reports whether the policy prunes the controller event log.
+// Enabled reports whether the policy prunes anything.
 func (p RetentionPolicy) Enabled() bool {
-	return p.ControlPlaneEvents > 0
+	return p.ControlPlaneEvents > 0 || p.DeletedArtifacts > 0 || p.DeploymentLogs > 0 || p.DeploymentLogMaxBytes > 0
 }
 
 // PruneStores groups the store surfaces needed by RunRetentionPrune. Keeping
@@ -29,16 +40,37 @@ type PruneStores struct {
 	ControlPlaneEvents interface {
 		PruneBefore(ctx context.Context, before time.Time, keepAfterRevision int64, limit int) (int64, error)
 	}
+	// Partitions keeps the day partitions of the append-only event logs
+	// created ahead of time. It runs on every pass, whether or not the
+	// policy prunes anything.
+	Partitions interface {
+		EnsurePartitions(ctx context.Context, now time.Time, daysAhead int) (int, error)
+	}
+	// DeletedArtifacts are the tagged-artifact stores whose soft-deleted
+	// tags are purged once RetentionPolicy.DeletedArtifacts has passed.
+	DeletedArtifacts []interface {
+		PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+	}
+	// DeploymentLogs holds shipped deployment logs, purged by age and
+	// trimmed to RetentionPolicy.DeploymentLogMaxBytes per Deployment.
+	DeploymentLogs interface {
+		PurgeBefore(ctx context.Context, before time.Time) (int64, error)
+		TrimToBytes(ctx context.Context, maxBytes int64) (int64, error)
+	}
 }
 
-// RetentionPruneResult reports how many event rows were removed in one
-// maintenance pass.
+// RetentionPruneResult reports how many event rows, deleted artifact
+// tags and deployment log lines were removed and how many day partitions
+// were created in one maintenance pass.
 type RetentionPruneResult struct {
 	ControlPlaneEvents int64
+	PartitionsCreated  int
+	DeletedArtifacts   int64
+	DeploymentLogs     int64
 }
 
 // RetentionPruner owns the periodic maintenance loop for controller event
-// replay rows.
+// replay rows and event-log partitions.
 type RetentionPruner struct {
 	Stores PruneStores
 	Policy RetentionPolicy
@@ -46,7 +78,7 @@ type RetentionPruner struct {
 }
 
 func (p *RetentionPruner) Enabled() bool {
-	return p != nil && p.Policy.Enabled()
+	return p != nil && (p.Policy.Enabled() || p.Stores.Partitions != nil)
 }
 
 func (p *RetentionPruner) RunOnce(ctx context.Context) (RetentionPruneResult, error) {
@@ -96,13 +128,18 @@ func (p *RetentionPruner) runOnceLogged(ctx context.Context) {
 		logger.Info(
 			"deployment controller retention pruned bookkeeping rows",
 			"control_plane_events", result.ControlPlaneEvents,
+			"partitions_created", result.PartitionsCreated,
+			"deleted_artifacts", result.DeletedArtifacts,
+			"deployment_logs", result.DeploymentLogs,
 		)
 	}
 }
 
-// RunRetentionPrune applies a RetentionPolicy to the controller event log.
-// Canonical resource tables remain the source of truth, so controllers can
-// full-reconcile if their checkpoint falls behind the retained event range.
+// RunRetentionPrune creates upcoming event-log partitions and applies a
+// RetentionPolicy to the controller event log, soft-deleted artifact
+// tags and retained deployment logs. Canonical resource tables
+// remain the source of truth, so controllers can full-reconcile if their
+// checkpoint falls behind the retained event range.
 func RunRetentionPrune(ctx context.Context, stores PruneStores, policy RetentionPolicy, now time.Time) (RetentionPruneResult, error) {
 	if now.IsZero() {
 		now = time.Now().UTC()
@@ -113,11 +150,33 @@ func RunRetentionPrune(ctx context.Context, stores PruneStores, policy Retention
 		errs   error
 	)
 
+	if stores.Partitions != nil {
+		n, err := stores.Partitions.EnsurePartitions(ctx, now, v1alpha1store.PartitionDaysAhead)
+		result.PartitionsCreated = n
+		errs = errors.Join(errs, wrapRetentionErr("create event-log partitions", err))
+	}
 	if stores.ControlPlaneEvents != nil && policy.ControlPlaneEvents > 0 {
 		n, err := stores.ControlPlaneEvents.PruneBefore(ctx, now.Add(-policy.ControlPlaneEvents), policy.EventKeepAfterRev, limit)
 		result.ControlPlaneEvents = n
 		errs = errors.Join(errs, wrapRetentionErr("prune control-plane events", err))
 	}
+	if policy.DeletedArtifacts > 0 {
+		for _, store := range stores.DeletedArtifacts {
+			n, err := store.PurgeDeletedBefore(ctx, now.Add(-policy.DeletedArtifacts))
+			result.DeletedArtifacts += n
+			errs = errors.Join(errs, wrapRetentionErr("purge deleted artifacts", err))
+		}
+	}
+	if stores.DeploymentLogs != nil && policy.DeploymentLogs > 0 {
+		n, err := stores.DeploymentLogs.PurgeBefore(ctx, now.Add(-policy.DeploymentLogs))
+		result.DeploymentLogs += n
+		errs = errors.Join(errs, wrapRetentionErr("purge deployment logs", err))
+	}
+	if stores.DeploymentLogs != nil && policy.DeploymentLogMaxBytes > 0 {
+		n, err := stores.DeploymentLogs.TrimToBytes(ctx, policy.DeploymentLogMaxBytes)
+		result.DeploymentLogs += n
+		errs = errors.Join(errs, wrapRetentionErr("trim deployment logs", err))
+	}
 	return result, errs
 }
 
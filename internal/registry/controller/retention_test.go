@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
 )
 
 func TestRunRetentionPruneAppliesConfiguredEventCutoff(t *testing.T) {
@@ -48,6 +50,32 @@ func TestRunRetentionPruneSkipsDisabledPolicies(t *testing.T) {
 	}
 }
 
+func TestRunRetentionPruneEnsuresPartitionsWithoutPolicy(t *testing.T) {
+	now := time.Date(2026, 10, 17, 23, 30, 0, 0, time.UTC)
+	events := &fakeEventPruner{}
+	partitions := &fakePartitioner{created: 8}
+
+	result, err := RunRetentionPrune(context.Background(), PruneStores{
+		ControlPlaneEvents: events,
+		Partitions:         partitions,
+	}, RetentionPolicy{}, now)
+	if err != nil {
+		t.Fatalf("RunRetentionPrune returned error: %v", err)
+	}
+	if result != (RetentionPruneResult{PartitionsCreated: 8}) {
+		t.Fatalf("result = %+v, want 8 partitions created", result)
+	}
+	if partitions.now != now || partitions.daysAhead != v1alpha1store.PartitionDaysAhead {
+		t.Fatalf("partition args = now %s days ahead %d", partitions.now, partitions.daysAhead)
+	}
+	if events.called {
+		t.Fatal("event pruner was called for disabled retention")
+	}
+	if !(&RetentionPruner{Stores: PruneStores{Partitions: partitions}}).Enabled() {
+		t.Fatal("pruner with partitions must run without a retention policy")
+	}
+}
+
 func TestRunRetentionPruneReturnsContextualErrors(t *testing.T) {
 	_, err := RunRetentionPrune(context.Background(), PruneStores{
 		ControlPlaneEvents: &fakeEventPruner{err: errors.New("events failed")},
@@ -61,6 +89,43 @@ func TestRunRetentionPruneReturnsContextualErrors(t *testing.T) {
 	if !strings.Contains(msg, "prune control-plane events") {
 		t.Fatalf("error = %q, want contextual event prune error", msg)
 	}
+
+	_, err = RunRetentionPrune(context.Background(), PruneStores{
+		Partitions: &fakePartitioner{err: errors.New("attach failed")},
+	}, RetentionPolicy{}, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "create event-log partitions") {
+		t.Fatalf("error = %v, want contextual partition error", err)
+	}
+}
+
+func TestRunRetentionPrunePurgesDeletedArtifacts(t *testing.T) {
+	now := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	agents := &fakeDeletedPurger{purged: 2}
+	skills := &fakeDeletedPurger{purged: 3}
+	stores := PruneStores{DeletedArtifacts: []interface {
+		PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+	}{agents, skills}}
+
+	result, err := RunRetentionPrune(context.Background(), stores, RetentionPolicy{DeletedArtifacts: 7 * 24 * time.Hour}, now)
+	if err != nil {
+		t.Fatalf("RunRetentionPrune returned error: %v", err)
+	}
+	if result.DeletedArtifacts != 5 {
+		t.Fatalf("DeletedArtifacts = %d, want 5", result.DeletedArtifacts)
+	}
+	for _, purger := range []*fakeDeletedPurger{agents, skills} {
+		if purger.before != now.Add(-7*24*time.Hour) {
+			t.Fatalf("purge cutoff = %s, want %s", purger.before, now.Add(-7*24*time.Hour))
+		}
+	}
+
+	agents.before, skills.before = time.Time{}, time.Time{}
+	if _, err := RunRetentionPrune(context.Background(), stores, RetentionPolicy{}, now); err != nil {
+		t.Fatalf("RunRetentionPrune returned error: %v", err)
+	}
+	if !agents.before.IsZero() || !skills.before.IsZero() {
+		t.Fatal("deleted artifacts were purged with retention disabled")
+	}
 }
 
 func TestRetentionPolicyEnabled(t *testing.T) {
@@ -72,6 +137,8 @@ func TestRetentionPolicyEnabled(t *testing.T) {
 		{name: "empty", policy: RetentionPolicy{}, want: false},
 		{name: "events", policy: RetentionPolicy{ControlPlaneEvents: time.Hour}, want: true},
 		{name: "revision bound alone does not enable age pruning", policy: RetentionPolicy{EventKeepAfterRev: 42}, want: false},
+		{name: "deleted artifacts", policy: RetentionPolicy{DeletedArtifacts: time.Hour}, want: true},
+		{name: "deployment log size cap", policy: RetentionPolicy{DeploymentLogMaxBytes: 1 << 20}, want: true},
 	}
 
 	for _, tt := range tests {
@@ -124,3 +191,26 @@ func (f *fakeEventPruner) PruneBefore(_ context.Context, before time.Time, keepA
 	f.limit = limit
 	return f.deleted, f.err
 }
+
+type fakePartitioner struct {
+	now       time.Time
+	daysAhead int
+	created   int
+	err       error
+}
+
+func (f *fakePartitioner) EnsurePartitions(_ context.Context, now time.Time, daysAhead int) (int, error) {
+	f.now = now
+	f.daysAhead = daysAhead
+	return f.created, f.err
+}
+
+type fakeDeletedPurger struct {
+	before time.Time
+	purged int64
+}
+
+func (f *fakeDeletedPurger) PurgeDeletedBefore(_ context.Context, before time.Time) (int64, error) {
+	f.before = before
+	return f.purged, nil
+}
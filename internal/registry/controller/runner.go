@@ -4,11 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
 
 	internaldb "github.com/agentregistry-dev/agentregistry/internal/registry/database"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/resourcelimits"
+	deploymentsvc "github.com/agentregistry-dev/agentregistry/internal/registry/service/deployment"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/telemetry"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	"github.com/agentregistry-dev/agentregistry/pkg/logging"
 	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
@@ -32,6 +38,8 @@ type ControllerHandle struct {
 	Controller *DeploymentController
 	Discovery  *DeploymentDiscoveryController
 	Retention  *RetentionPruner
+	// Logs is nil unless ControllerConfig.ShipLogs is set.
+	Logs *DeploymentLogShipper
 }
 
 // ControllerConfig controls optional controller maintenance loops.
@@ -40,6 +48,32 @@ type ControllerConfig struct {
 	DiscoveryInterval          time.Duration
 	DiscoveryStaleAfterMisses  int
 	DiscoveryDeleteAfterMisses int
+	// Workers and RuntimeConcurrency bound reconcile parallelism overall and
+	// per Runtime. Values <= 0 use the controller defaults.
+	Workers            int
+	RuntimeConcurrency int
+	// FailureNotifier, when set, receives Deployment apply failures.
+	FailureNotifier DeploymentFailureNotifier
+	// Usage, when set, counts successful applies as artifact deploys.
+	Usage DeployRecorder
+	// GetterWrapper decorates the controller's ResourceRef getter, e.g. to
+	// resolve peer-registry refs. Nil leaves the store-backed getter as is.
+	GetterWrapper func(v1alpha1.GetterFunc) v1alpha1.GetterFunc
+	// OpenRuntime decrypts the sealed config of each Runtime discovery
+	// polls before adapters see it. Runtimes resolved by reference are
+	// opened by a GetterWrapper instead. Nil hands Runtimes over as stored.
+	OpenRuntime func(ctx context.Context, runtime *v1alpha1.Runtime) (*v1alpha1.Runtime, error)
+	// Resources is the CPU and memory policy for deployed containers. Nil
+	// applies no server defaults or maxima.
+	Resources *resourcelimits.Policy
+	// Snapshots resolves the refs of Deployments pinned to a catalog
+	// snapshot. Nil leaves them blocked.
+	Snapshots SnapshotResolver
+	// ShipLogs copies each Deployment's adapter logs into Postgres every
+	// LogShipInterval so they outlive container restarts. The Retention
+	// policy's DeploymentLogs and DeploymentLogMaxBytes bound what is kept.
+	ShipLogs        bool
+	LogShipInterval time.Duration
 }
 
 // StartDeploymentController constructs the Deployment controller, runs the
@@ -59,12 +93,27 @@ func StartDeploymentController(
 		return nil, errors.New("deployment controller: stores are required")
 	}
 
-	controlPlaneEventStore := v1alpha1store.NewControlPlaneEventStore(pool, pkgdb.MustNewSchema(pkgdb.OSSSchema))
+	getter := internaldb.NewGetter(stores)
+	if config.GetterWrapper != nil {
+		getter = config.GetterWrapper(getter)
+	}
+	ossSchema := pkgdb.MustNewSchema(pkgdb.OSSSchema)
+	controlPlaneEventStore := v1alpha1store.NewControlPlaneEventStore(pool, ossSchema)
 	controller := &DeploymentController{
 		Stores:   stores,
 		Adapters: adapters,
-		Getter:   internaldb.NewGetter(stores),
+		Getter:   getter,
 		Events:   controlPlaneEventStore,
+
+		Workers:            config.Workers,
+		RuntimeConcurrency: config.RuntimeConcurrency,
+		Meter:              otel.Meter(telemetry.Namespace),
+		FailureNotifier:    config.FailureNotifier,
+		Locks:              v1alpha1store.NewDeploymentLocks(pool, ossSchema, LockHolderName()),
+		Manifests:          v1alpha1store.NewDeploymentManifestStore(pool, ossSchema),
+		Usage:              config.Usage,
+		Resources:          config.Resources,
+		Snapshots:          config.Snapshots,
 	}
 	if _, err := controller.Refresh(ctx); err != nil {
 		return nil, fmt.Errorf("deployment controller initial refresh: %w", err)
@@ -75,15 +124,31 @@ func StartDeploymentController(
 		Adapters:          adapters,
 		StaleAfterMisses:  config.DiscoveryStaleAfterMisses,
 		DeleteAfterMisses: config.DiscoveryDeleteAfterMisses,
+		OpenRuntime:       config.OpenRuntime,
 	}
 
 	retention := &RetentionPruner{
 		Stores: PruneStores{
 			ControlPlaneEvents: controlPlaneEventStore,
+			Partitions:         v1alpha1store.NewPartitionManager(pool, ossSchema),
 		},
 		Policy: config.Retention,
 	}
+	for _, store := range stores {
+		if store.Behavior() == v1alpha1store.TaggedArtifactStore {
+			retention.Stores.DeletedArtifacts = append(retention.Stores.DeletedArtifacts, store)
+		}
+	}
 	handle := &ControllerHandle{Controller: controller, Discovery: discovery, Retention: retention}
+	if config.ShipLogs {
+		logStore := v1alpha1store.NewDeploymentLogStore(pool, ossSchema)
+		retention.Stores.DeploymentLogs = logStore
+		handle.Logs = &DeploymentLogShipper{
+			Stores: stores,
+			Source: deploymentsvc.NewAdapterResolver(deploymentsvc.ResolverDependencies{Adapters: adapters, Getter: getter}),
+			Sink:   logStore,
+		}
+	}
 
 	go func() {
 		if err := controller.Run(ctx, defaultControllerResyncInterval); err != nil && !errors.Is(err, context.Canceled) {
@@ -95,6 +160,13 @@ func StartDeploymentController(
 			logger.Error("deployment discovery controller stopped", "error", err)
 		}
 	}()
+	if handle.Logs != nil {
+		go func() {
+			if err := handle.Logs.Run(ctx, config.LogShipInterval); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("deployment log shipper stopped", "error", err)
+			}
+		}()
+	}
 	if retention.Enabled() {
 		go func() {
 			if err := retention.Run(ctx, defaultRetentionPruneInterval); err != nil && !errors.Is(err, context.Canceled) {
@@ -105,6 +177,16 @@ func StartDeploymentController(
 	return handle, nil
 }
 
+// LockHolderName identifies this registry process in deployment lock
+// records: its hostname, which is the pod name on Kubernetes, and pid.
+func LockHolderName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "agentregistry"
+	}
+	return fmt.Sprintf("%s/%d", host, os.Getpid())
+}
+
 func controlPlaneWakeups(ctx context.Context, pool *pgxpool.Pool) <-chan struct{} {
 	ch := make(chan struct{}, 1)
 	go runControlPlaneWakeupLoop(ctx, ch, func(ctx context.Context, wakeups chan<- struct{}) error {
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// syncSubAgentDeployments makes the Deployments that run agent's sub-agents
+// for deployment match agent's spec.subAgents: one Deployment per sub-agent,
+// on the parent's Runtime with the parent's env and catalog snapshot, and
+// none for sub-agents the agent no longer declares. Each is reconciled like
+// any other Deployment, so a sub-agent's own sub-agents follow in turn.
+func (c *DeploymentController) syncSubAgentDeployments(ctx context.Context, deployment *v1alpha1.Deployment, agent *v1alpha1.Agent) error {
+	subAgents, err := v1alpha1.ResolveSubAgents(ctx, c.getterFor(deployment), agent)
+	if err != nil {
+		return err
+	}
+	existing, err := c.listSubAgentDeployments(ctx, deployment)
+	if err != nil {
+		return err
+	}
+	desired := map[string]bool{}
+	for _, sub := range subAgents {
+		child := subAgentDeployment(deployment, sub)
+		desired[child.Metadata.Name] = true
+		if _, err := c.deploymentStore().Upsert(ctx, child, v1alpha1store.UpsertOpts{
+			InitialFinalizers: []string{DeploymentControllerFinalizer},
+		}); err != nil {
+			return fmt.Errorf("upsert sub-agent Deployment %s/%s: %w", child.Metadata.Namespace, child.Metadata.Name, err)
+		}
+	}
+	for _, child := range existing {
+		if desired[child.Metadata.Name] {
+			continue
+		}
+		if err := c.deleteDeployment(ctx, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteSubAgentDeployments deletes every Deployment created for
+// deployment's sub-agents.
+func (c *DeploymentController) deleteSubAgentDeployments(ctx context.Context, deployment *v1alpha1.Deployment) error {
+	children, err := c.listSubAgentDeployments(ctx, deployment)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := c.deleteDeployment(ctx, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *DeploymentController) listSubAgentDeployments(ctx context.Context, deployment *v1alpha1.Deployment) ([]*v1alpha1.Deployment, error) {
+	var out []*v1alpha1.Deployment
+	opts := v1alpha1store.ListOpts{
+		Namespace:  deployment.Metadata.NamespaceOrDefault(),
+		Limit:      defaultControllerListPageSize,
+		ExtraWhere: "annotations ->> '" + v1alpha1.DeploymentParentAnnotation + "' = $1",
+		ExtraArgs:  []any{deployment.Metadata.Name},
+	}
+	for {
+		rows, cursor, err := c.deploymentStore().List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("list sub-agent Deployments of %s: %w", deployment.Metadata.Name, err)
+		}
+		for _, raw := range rows {
+			child, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Deployment {
+				return &v1alpha1.Deployment{}
+			}, raw, v1alpha1.KindDeployment)
+			if err != nil {
+				return nil, fmt.Errorf("decode sub-agent Deployment: %w", err)
+			}
+			out = append(out, child)
+		}
+		if cursor == "" {
+			return out, nil
+		}
+		opts.Cursor = cursor
+	}
+}
+
+// deleteDeployment marks deployment for deletion; its own reconcile tears
+// it down. A concurrent deletion is treated as success.
+func (c *DeploymentController) deleteDeployment(ctx context.Context, deployment *v1alpha1.Deployment) error {
+	namespace := deployment.Metadata.NamespaceOrDefault()
+	if err := c.deploymentStore().Delete(ctx, namespace, deployment.Metadata.Name, ""); err != nil && !errors.Is(err, pkgdb.ErrNotFound) {
+		return fmt.Errorf("delete sub-agent Deployment %s/%s: %w", namespace, deployment.Metadata.Name, err)
+	}
+	return nil
+}
+
+// subAgentDeployment is the Deployment that runs sub for parent.
+func subAgentDeployment(parent *v1alpha1.Deployment, sub *v1alpha1.Agent) *v1alpha1.Deployment {
+	namespace := parent.Metadata.NamespaceOrDefault()
+	targetRef := v1alpha1.ResourceRef{
+		Kind: v1alpha1.KindAgent,
+		Name: sub.Metadata.Name,
+		Tag:  sub.Metadata.Tag,
+	}
+	if subNamespace := sub.Metadata.NamespaceOrDefault(); subNamespace != namespace {
+		targetRef.Namespace = subNamespace
+	}
+	return &v1alpha1.Deployment{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindDeployment},
+		Metadata: v1alpha1.ObjectMeta{
+			Namespace: namespace,
+			Name:      v1alpha1.SubAgentDeploymentName(parent.Metadata.Name, sub.Metadata.Name),
+			Annotations: map[string]string{
+				v1alpha1.DeploymentOriginAnnotation: v1alpha1.DeploymentOriginManaged,
+				v1alpha1.DeploymentParentAnnotation: parent.Metadata.Name,
+			},
+		},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:    targetRef,
+			RuntimeRef:   parent.Spec.RuntimeRef,
+			DesiredState: v1alpha1.DesiredStateDeployed,
+			Env:          maps.Clone(parent.Spec.Env),
+			Snapshot:     parent.Spec.Snapshot,
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultControllerWorkers is how many queue workers reconcile Deployments
+	// in parallel when the controller does not set Workers explicitly.
+	defaultControllerWorkers = 4
+	// defaultRuntimeConcurrency caps in-flight adapter calls against a single
+	// Runtime so reconcile storms (bulk imports, dependency fan-out) cannot
+	// saturate one Docker daemon or Kubernetes API server.
+	defaultRuntimeConcurrency = 2
+	// runtimeSlotRetryDelay is how long a Deployment whose Runtime is at
+	// capacity waits in the queue before it tries again.
+	runtimeSlotRetryDelay = 500 * time.Millisecond
+)
+
+// errRuntimeBusy reports that every reconcile slot of a Deployment's
+// Runtime is taken. The queue worker requeues the Deployment after
+// runtimeSlotRetryDelay and moves on to other work.
+var errRuntimeBusy = errors.New("runtime reconcile slots are all in use")
+
+// runtimeLimiter hands out per-Runtime slots. Each Runtime key owns a
+// buffered channel sized to the configured limit. Acquiring never waits,
+// so a saturated Runtime cannot tie up the queue workers other Runtimes'
+// Deployments need.
+type runtimeLimiter struct {
+	limit int
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newRuntimeLimiter(limit int) *runtimeLimiter {
+	if limit <= 0 {
+		limit = defaultRuntimeConcurrency
+	}
+	return &runtimeLimiter{limit: limit, slots: map[string]chan struct{}{}}
+}
+
+// tryAcquire takes a slot for key if one is free. The returned release
+// func must be called exactly once; ok is false, and release nil, when
+// every slot is held.
+func (l *runtimeLimiter) tryAcquire(key string) (release func(), ok bool) {
+	sem := l.semaphore(key)
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, true
+	default:
+		return nil, false
+	}
+}
+
+// inFlight reports how many slots are currently held for key.
+func (l *runtimeLimiter) inFlight(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sem, ok := l.slots[key]
+	if !ok {
+		return 0
+	}
+	return len(sem)
+}
+
+func (l *runtimeLimiter) semaphore(key string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sem, ok := l.slots[key]
+	if !ok {
+		sem = make(chan struct{}, l.limit)
+		l.slots[key] = sem
+	}
+	return sem
+}
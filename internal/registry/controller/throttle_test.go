@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+func TestRuntimeLimiterCapsPerRuntime(t *testing.T) {
+	limiter := newRuntimeLimiter(1)
+
+	release, ok := limiter.tryAcquire("default/local")
+	require.True(t, ok)
+	require.Equal(t, 1, limiter.inFlight("default/local"))
+
+	other, ok := limiter.tryAcquire("default/kind")
+	require.True(t, ok, "a different runtime must not share the slot")
+	other()
+
+	_, ok = limiter.tryAcquire("default/local")
+	require.False(t, ok, "a saturated runtime refuses rather than waits")
+
+	release()
+	require.Equal(t, 0, limiter.inFlight("default/local"))
+	again, ok := limiter.tryAcquire("default/local")
+	require.True(t, ok)
+	again()
+}
+
+func TestDeploymentControllerReplayCoalescesDependencyEvents(t *testing.T) {
+	reader := fakeEventReader{
+		events: []v1alpha1store.ControlPlaneEvent{
+			{Revision: 1, Key: v1alpha1store.ResourceKey{Kind: v1alpha1.KindAgent, Namespace: "default", Name: "a"}},
+			{Revision: 2, Key: v1alpha1store.ResourceKey{Kind: v1alpha1.KindRuntime, Namespace: "default", Name: "r"}},
+			{Revision: 3, Key: v1alpha1store.ResourceKey{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "m"}},
+		},
+	}
+	controller := &DeploymentController{Events: reader, BatchLimit: 2}
+
+	_, err := controller.Sync(context.Background(), 0)
+	require.ErrorContains(t, err, "coalesced full reconcile")
+	require.ErrorContains(t, err, "no Deployment store registered")
+}
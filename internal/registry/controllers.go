@@ -0,0 +1,48 @@
+package registry
+
+import (
+	"github.com/agentregistry-dev/agentregistry/internal/registry/config"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/controller"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/resourcelimits"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+func deploymentControllerConfig(cfg *config.Config) controller.ControllerConfig {
+	out := controller.ControllerConfig{
+		Retention: controller.RetentionPolicy{
+			ControlPlaneEvents: cfg.ControllerEventRetention,
+			EventKeepAfterRev:  cfg.ControllerEventKeepAfterRevision,
+			BatchLimit:         cfg.ControllerRetentionPruneBatchLimit,
+			DeletedArtifacts:   cfg.DeletedArtifactRetention,
+		},
+		DiscoveryInterval:          cfg.ControllerDiscoveryInterval,
+		DiscoveryStaleAfterMisses:  cfg.ControllerDiscoveryStaleAfterMisses,
+		DiscoveryDeleteAfterMisses: cfg.ControllerDiscoveryDeleteAfterMisses,
+		Workers:                    cfg.ControllerWorkers,
+		RuntimeConcurrency:         cfg.ControllerRuntimeConcurrency,
+		Resources:                  deploymentResourcePolicy(cfg),
+	}
+	if cfg.DeploymentLogShippingEnabled {
+		out.ShipLogs = true
+		out.LogShipInterval = cfg.DeploymentLogShipInterval
+		out.Retention.DeploymentLogs = cfg.DeploymentLogRetention
+		out.Retention.DeploymentLogMaxBytes = cfg.DeploymentLogMaxBytes
+	}
+	return out
+}
+
+// deploymentResourcePolicy returns the configured resource defaults and
+// maxima, or nil when none are set.
+func deploymentResourcePolicy(cfg *config.Config) *resourcelimits.Policy {
+	policy := &resourcelimits.Policy{
+		Defaults: v1alpha1.ResourceRequirements{
+			Requests: &v1alpha1.ComputeResources{CPU: cfg.DeploymentDefaultCPURequest, Memory: cfg.DeploymentDefaultMemoryRequest},
+			Limits:   &v1alpha1.ComputeResources{CPU: cfg.DeploymentDefaultCPULimit, Memory: cfg.DeploymentDefaultMemoryLimit},
+		},
+		Max: v1alpha1.ComputeResources{CPU: cfg.DeploymentMaxCPU, Memory: cfg.DeploymentMaxMemory},
+	}
+	if policy.Defaults.IsZero() && policy.Max.IsZero() {
+		return nil
+	}
+	return policy
+}
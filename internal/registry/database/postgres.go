@@ -13,6 +13,7 @@ import (
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/database"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/database/legacymigrate"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/database/orchestrator"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
 )
 
 // PostgreSQL is the root PostgreSQL-backed store. It owns the connection
@@ -31,7 +32,12 @@ type PostgreSQL struct {
 // when migrations have been applied out-of-band by `arctl db migrate
 // up`. The pool is still parsed, opened, and pinged so a
 // misconfigured DB fails fast.
-func NewPostgreSQL(ctx context.Context, connectionURI string, authz auth.Authorizer, skipMigrations bool) (*PostgreSQL, error) {
+func NewPostgreSQL(ctx context.Context, connectionURI string, authz auth.Authorizer, skipMigrations bool, opts ...Option) (*PostgreSQL, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	config, err := pgxpool.ParseConfig(connectionURI)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse PostgreSQL config: %w", err)
@@ -60,6 +66,9 @@ func NewPostgreSQL(ctx context.Context, connectionURI string, authz auth.Authori
 		_, err := conn.Exec(ctx, "SET search_path TO "+ossSchema.Quoted())
 		return err
 	}
+	if o.namespaceRLS {
+		config.PrepareConn = prepareNamespaceScope
+	}
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
@@ -85,6 +94,17 @@ func NewPostgreSQL(ctx context.Context, connectionURI string, authz auth.Authori
 		return nil, fmt.Errorf("failed to run startup migrations: %w", err)
 	}
 
+	// Create today's and the coming days' event-log partitions now rather
+	// than waiting for the controller's first maintenance pass. Rows that
+	// migration 015 parked in the DEFAULT partition move into them.
+	created, err := v1alpha1store.NewPartitionManager(pool, ossSchema).EnsurePartitions(ctx, time.Now(), v1alpha1store.PartitionDaysAhead)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event-log partitions: %w", err)
+	}
+	if created > 0 {
+		slog.Info("created event-log partitions", "count", created)
+	}
+
 	return &PostgreSQL{pool: pool, authz: authz}, nil
 }
 
@@ -16,14 +16,18 @@ import (
 //
 // Dangling references return v1alpha1.ErrDanglingRef so callers can
 // distinguish "row missing" from "database unavailable"; unknown
-// kinds return wrapped v1alpha1.ErrInvalidRef.
+// kinds and peer-registry refs (see internal/registry/peers) return
+// wrapped v1alpha1.ErrInvalidRef.
 func NewResolver(stores map[string]*v1alpha1store.Store) v1alpha1.ResolverFunc {
 	return func(ctx context.Context, ref v1alpha1.ResourceRef) error {
+		if ref.Registry != "" {
+			return fmt.Errorf("%w: peer registry %q is not configured", v1alpha1.ErrInvalidRef, ref.Registry)
+		}
 		store, ok := stores[ref.Kind]
 		if !ok {
 			return fmt.Errorf("%w: unknown kind %q", v1alpha1.ErrInvalidRef, ref.Kind)
 		}
-		_, err := store.GetByRef(ctx, ref.Namespace, ref.Name, ref.Tag)
+		_, err := getByRef(ctx, store, ref)
 		if err != nil {
 			if errors.Is(err, pkgdb.ErrNotFound) {
 				return v1alpha1.ErrDanglingRef
@@ -34,51 +38,94 @@ func NewResolver(stores map[string]*v1alpha1store.Store) v1alpha1.ResolverFunc {
 	}
 }
 
+// getByRef loads ref from store. A tag that is a version constraint (see
+// v1alpha1.TagConstraint) loads the highest live tag matching it. The
+// ref's namespace and name are canonicalized, so a ref written as
+// `Acme/Weather` resolves to `acme/weather`.
+func getByRef(ctx context.Context, store *v1alpha1store.Store, ref v1alpha1.ResourceRef) (*v1alpha1.RawObject, error) {
+	ref.Namespace, ref.Name = v1alpha1.CanonicalName(ref.Namespace), v1alpha1.CanonicalName(ref.Name)
+	if !v1alpha1.IsTagConstraint(ref.Tag) {
+		return store.GetByRef(ctx, ref.Namespace, ref.Name, ref.Tag)
+	}
+	constraint, err := v1alpha1.ParseTagConstraint(ref.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", v1alpha1.ErrInvalidRef, err)
+	}
+	rows, err := store.ListTags(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, row.Metadata.Tag)
+	}
+	best, ok := constraint.Best(tags)
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	for _, row := range rows {
+		if row.Metadata.Tag == best {
+			return row, nil
+		}
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
 // NewGetter returns a v1alpha1.GetterFunc that dispatches a
 // cross-kind ResourceRef fetch against the supplied Stores map and
 // decodes the RawObject into its typed envelope via v1alpha1.Default.
 // Consumers: reconcilers / runtime adapters that need the referenced
 // object's Spec (not just an existence check).
 //
-// Dangling references return v1alpha1.ErrDanglingRef; unknown kinds
+// Dangling references, including version constraints no tag matches,
+// return v1alpha1.ErrDanglingRef; unknown kinds and peer-registry refs
 // return wrapped v1alpha1.ErrInvalidRef.
 func NewGetter(stores map[string]*v1alpha1store.Store) v1alpha1.GetterFunc {
 	return func(ctx context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		if ref.Registry != "" {
+			return nil, fmt.Errorf("%w: peer registry %q is not configured", v1alpha1.ErrInvalidRef, ref.Registry)
+		}
 		store, ok := stores[ref.Kind]
 		if !ok {
 			return nil, fmt.Errorf("%w: unknown kind %q", v1alpha1.ErrInvalidRef, ref.Kind)
 		}
-		raw, err := store.GetByRef(ctx, ref.Namespace, ref.Name, ref.Tag)
+		raw, err := getByRef(ctx, store, ref)
 		if err != nil {
 			if errors.Is(err, pkgdb.ErrNotFound) {
 				return nil, v1alpha1.ErrDanglingRef
 			}
 			return nil, err
 		}
-		_, newObj, ok := v1alpha1.Default.Lookup(ref.Kind)
-		if !ok {
-			return nil, fmt.Errorf("%w: unknown kind %q in scheme", v1alpha1.ErrInvalidRef, ref.Kind)
-		}
-		obj, ok := newObj().(v1alpha1.Object)
-		if !ok {
-			return nil, fmt.Errorf("scheme constructor for %q did not return v1alpha1.Object", ref.Kind)
-		}
-		// scanRow leaves RawObject.TypeMeta zero (apiVersion/kind aren't
-		// persisted as columns — they're implicit per table), so pin them
-		// from the ref + scheme defaults. Adapters rely on GetKind() to
-		// dispatch.
-		obj.SetTypeMeta(v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: ref.Kind})
-		obj.SetMetadata(raw.Metadata)
-		if len(raw.Status) > 0 {
-			if err := obj.UnmarshalStatus(raw.Status); err != nil {
-				return nil, fmt.Errorf("decode %s status: %w", ref.Kind, err)
-			}
+		return DecodeObject(ref.Kind, raw)
+	}
+}
+
+// DecodeObject decodes raw, a row of kind's store, into the kind's typed
+// Object.
+func DecodeObject(kind string, raw *v1alpha1.RawObject) (v1alpha1.Object, error) {
+	_, newObj, ok := v1alpha1.Default.Lookup(kind)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown kind %q in scheme", v1alpha1.ErrInvalidRef, kind)
+	}
+	obj, ok := newObj().(v1alpha1.Object)
+	if !ok {
+		return nil, fmt.Errorf("scheme constructor for %q did not return v1alpha1.Object", kind)
+	}
+	// scanRow leaves RawObject.TypeMeta zero (apiVersion/kind aren't
+	// persisted as columns — they're implicit per table), so pin them
+	// from the kind + scheme defaults. Adapters rely on GetKind() to
+	// dispatch.
+	obj.SetTypeMeta(v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: kind})
+	obj.SetMetadata(raw.Metadata)
+	if len(raw.Status) > 0 {
+		if err := obj.UnmarshalStatus(raw.Status); err != nil {
+			return nil, fmt.Errorf("decode %s status: %w", kind, err)
 		}
-		if len(raw.Spec) > 0 {
-			if err := obj.UnmarshalSpec(raw.Spec); err != nil {
-				return nil, fmt.Errorf("decode %s spec: %w", ref.Kind, err)
-			}
+	}
+	if len(raw.Spec) > 0 {
+		if err := obj.UnmarshalSpec(raw.Spec); err != nil {
+			return nil, fmt.Errorf("decode %s spec: %w", kind, err)
 		}
-		return obj, nil
 	}
+	return obj, nil
 }
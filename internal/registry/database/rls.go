@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+)
+
+// namespaceScopeSetting is the session setting read by the namespace_scope
+// RLS policies (migration 011). Empty admits every row.
+const namespaceScopeSetting = "registry.namespace_scope"
+
+// Option configures NewPostgreSQL.
+type Option func(*options)
+
+type options struct {
+	namespaceRLS bool
+}
+
+// WithNamespaceRLS scopes every pool checkout to the namespaces the
+// caller's principal is confined to, so the Postgres RLS policies enforce
+// tenant isolation underneath the application's own authz checks.
+func WithNamespaceRLS() Option {
+	return func(o *options) { o.namespaceRLS = true }
+}
+
+// namespaceScope renders the RLS scope for ctx: the principal's namespaces
+// joined with commas, or "" for unconfined callers.
+func namespaceScope(ctx context.Context) string {
+	return strings.Join(auth.NamespacesFrom(ctx), ",")
+}
+
+// prepareNamespaceScope is a pgxpool PrepareConn hook. It (re)writes the
+// scope on every checkout, so a value left by the previous borrower never
+// leaks, and it covers single-statement queries as well as transactions
+// (which inherit the session value for their whole lifetime).
+func prepareNamespaceScope(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	if _, err := conn.Exec(ctx, "SELECT set_config($1, $2, false)", namespaceScopeSetting, namespaceScope(ctx)); err != nil {
+		// Drop the connection: its scope is unknown.
+		return false, fmt.Errorf("failed to set namespace scope: %w", err)
+	}
+	return true, nil
+}
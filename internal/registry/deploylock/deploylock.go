@@ -0,0 +1,59 @@
+// Package deploylock refuses Deployment writes that would race a running
+// reconcile. The deployment controller holds a lock per target + runtime
+// while it drives a runtime adapter (v1alpha1store.DeploymentLocks); a
+// deploy, undeploy or delete of a Deployment on the same target + runtime
+// made meanwhile fails with 409 naming the reconcile that holds the lock,
+// instead of queueing a second rollout behind the first.
+//
+// The hooks only probe the lock, so they never block a write behind a
+// slow adapter.
+package deploylock
+
+import (
+	"context"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// Checker reports whether a Deployment's lock is held, returning an error
+// matching pkgdb.ErrConflict if so. *v1alpha1store.DeploymentLocks
+// satisfies it.
+type Checker interface {
+	Check(ctx context.Context, deployment *v1alpha1.Deployment) error
+}
+
+// Prepare returns a Deployment Prepare hook that runs next, then refuses
+// the write while the Deployment's lock is held.
+func Prepare(locks Checker, next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		deployment, ok := obj.(*v1alpha1.Deployment)
+		if !ok {
+			return nil
+		}
+		return locks.Check(ctx, deployment)
+	}
+}
+
+// DeleteAdmission returns a delete admission that refuses Deployment
+// deletes while the Deployment's lock is held and otherwise defers to
+// next, or to resource.ProductionDeleteAdmission when next is nil.
+func DeleteAdmission(locks Checker, next types.DeleteAdmission) types.DeleteAdmission {
+	if next == nil {
+		next = resource.ProductionDeleteAdmission
+	}
+	return func(ctx context.Context, in types.DeleteAdmissionInput) (types.DeleteAdmissionResult, error) {
+		if deployment, ok := in.Object.(*v1alpha1.Deployment); ok && in.Kind == v1alpha1.KindDeployment {
+			if err := locks.Check(ctx, deployment); err != nil {
+				return types.DeleteAdmissionResult{}, err
+			}
+		}
+		return next(ctx, in)
+	}
+}
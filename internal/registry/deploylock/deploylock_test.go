@@ -0,0 +1,81 @@
+package deploylock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/deploylock"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// heldLocks reports every Deployment named in held as locked.
+type heldLocks map[string]bool
+
+func (h heldLocks) Check(_ context.Context, deployment *v1alpha1.Deployment) error {
+	if !h[deployment.Metadata.Name] {
+		return nil
+	}
+	return &v1alpha1store.DeploymentLockedError{
+		Target: v1alpha1store.DeploymentLockTargetFor(deployment),
+		Holder: &v1alpha1store.DeploymentLockHolder{Namespace: "default", Name: "bot-prod", Operation: "apply", Holder: "replica-a"},
+	}
+}
+
+func deployment(name string) *v1alpha1.Deployment {
+	return &v1alpha1.Deployment{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindDeployment},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "bot", Tag: "1.0.0"},
+			RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "k8s"},
+		},
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	ctx := context.Background()
+	var nextCalls int
+	prepare := deploylock.Prepare(heldLocks{"bot-canary": true}, func(context.Context, v1alpha1.Object) error {
+		nextCalls++
+		return nil
+	})
+
+	require.NoError(t, prepare(ctx, deployment("bot-staging")))
+	err := prepare(ctx, deployment("bot-canary"))
+	require.ErrorIs(t, err, pkgdb.ErrConflict)
+	require.Contains(t, err.Error(), "apply of Deployment default/bot-prod by replica-a")
+	require.NoError(t, prepare(ctx, &v1alpha1.Agent{Metadata: v1alpha1.ObjectMeta{Name: "bot-canary"}}))
+	require.Equal(t, 3, nextCalls)
+
+	failing := deploylock.Prepare(heldLocks{}, func(context.Context, v1alpha1.Object) error {
+		return errors.New("boom")
+	})
+	require.EqualError(t, failing(ctx, deployment("bot-staging")), "boom")
+}
+
+func TestDeleteAdmission(t *testing.T) {
+	ctx := context.Background()
+	var deleted []string
+	admit := deploylock.DeleteAdmission(heldLocks{"bot-canary": true}, func(_ context.Context, in types.DeleteAdmissionInput) (types.DeleteAdmissionResult, error) {
+		deleted = append(deleted, in.Name)
+		return types.DeleteAdmissionResult{Status: arv0.ApplyStatusDeleted}, nil
+	})
+
+	_, err := admit(ctx, types.DeleteAdmissionInput{Kind: v1alpha1.KindDeployment, Name: "bot-canary", Object: deployment("bot-canary")})
+	require.ErrorIs(t, err, pkgdb.ErrConflict)
+
+	res, err := admit(ctx, types.DeleteAdmissionInput{Kind: v1alpha1.KindDeployment, Name: "bot-staging", Object: deployment("bot-staging")})
+	require.NoError(t, err)
+	require.Equal(t, arv0.ApplyStatusDeleted, res.Status)
+
+	_, err = admit(ctx, types.DeleteAdmissionInput{Kind: v1alpha1.KindAgent, Name: "bot-canary"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"bot-staging", "bot-canary"}, deleted)
+}
@@ -0,0 +1,35 @@
+// Package docscore records an MCPServer's documentation score when it is
+// published. The score (v1alpha1.ScoreMCPServerDocs) is kept in the
+// v1alpha1.DocScoreAnnotation annotation, where the MCP Registry
+// compatibility endpoints read it for the official _meta block and the
+// minDocScore filter.
+package docscore
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// Prepare returns an MCPServer Prepare hook that runs next, then stamps the
+// server's documentation score over whatever the publisher supplied.
+func Prepare(next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		server, ok := obj.(*v1alpha1.MCPServer)
+		if !ok {
+			return nil
+		}
+		if server.Metadata.Annotations == nil {
+			server.Metadata.Annotations = map[string]string{}
+		}
+		score := v1alpha1.ScoreMCPServerDocs(&server.Spec)
+		server.Metadata.Annotations[v1alpha1.DocScoreAnnotation] = strconv.Itoa(score.Score)
+		return nil
+	}
+}
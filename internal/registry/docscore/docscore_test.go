@@ -0,0 +1,40 @@
+package docscore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/docscore"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+func TestPrepare_StampsScore(t *testing.T) {
+	server := &v1alpha1.MCPServer{
+		Metadata: v1alpha1.ObjectMeta{
+			Name:        "weather",
+			Annotations: map[string]string{v1alpha1.DocScoreAnnotation: "100"},
+		},
+		Spec: v1alpha1.MCPServerSpec{
+			Title:  "Weather",
+			Remote: &v1alpha1.MCPRemote{Type: "streamable-http", URL: "https://weather.example/mcp"},
+		},
+	}
+	require.NoError(t, docscore.Prepare(nil)(context.Background(), server))
+	// Title, the remote's repository points and the (empty) inputs only.
+	require.Equal(t, "30", server.Metadata.Annotations[v1alpha1.DocScoreAnnotation])
+
+	skill := &v1alpha1.Skill{Metadata: v1alpha1.ObjectMeta{Name: "summarize"}}
+	require.NoError(t, docscore.Prepare(nil)(context.Background(), skill))
+	require.Empty(t, skill.Metadata.Annotations)
+}
+
+func TestPrepare_RunsNextFirst(t *testing.T) {
+	errNext := errors.New("next failed")
+	server := &v1alpha1.MCPServer{Metadata: v1alpha1.ObjectMeta{Name: "weather"}}
+	hook := docscore.Prepare(func(context.Context, v1alpha1.Object) error { return errNext })
+	require.ErrorIs(t, hook(context.Background(), server), errNext)
+	require.Empty(t, server.Metadata.Annotations)
+}
@@ -0,0 +1,137 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+)
+
+// Provider turns text into embedding vectors. Vectors from different
+// models are not comparable, so Model names the one a provider uses.
+type Provider interface {
+	Model() string
+	// Embed returns one vector per text, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Provider names accepted by NewProvider.
+const (
+	// ProviderLocal embeds through a model served next to the registry by
+	// Ollama, so air-gapped registries need no outside API.
+	ProviderLocal = "local"
+)
+
+// Default settings of the local provider.
+const (
+	DefaultLocalURL   = "http://localhost:11434"
+	DefaultLocalModel = "nomic-embed-text"
+)
+
+// DefaultTimeout bounds one Embed call when ProviderConfig.Timeout is
+// unset. Search embeds the query inline, so a provider that hangs must
+// not hold the request for long.
+const DefaultTimeout = 30 * time.Second
+
+// ProviderConfig selects and configures a Provider.
+type ProviderConfig struct {
+	// Name is the provider name. Empty means no provider.
+	Name string
+	// URL is the provider's base URL. Empty uses the provider's default.
+	URL string
+	// Model is the embedding model. Empty uses the provider's default.
+	Model string
+	// Timeout bounds one Embed call. Zero uses DefaultTimeout.
+	Timeout time.Duration
+}
+
+// NewProvider returns the provider cfg names, or nil when cfg.Name is
+// empty. client carries the outbound transport; nil uses
+// httpclient.New with the provider's timeout.
+func NewProvider(cfg ProviderConfig, client *http.Client) (Provider, error) {
+	switch cfg.Name {
+	case "":
+		return nil, nil
+	case ProviderLocal:
+		url, model := cfg.URL, cfg.Model
+		if url == "" {
+			url = DefaultLocalURL
+		}
+		if model == "" {
+			model = DefaultLocalModel
+		}
+		o := NewOllama(url, model, client)
+		if cfg.Timeout > 0 {
+			o.timeout = cfg.Timeout
+			if client == nil {
+				o.client = httpclient.New(cfg.Timeout)
+			}
+		}
+		return o, nil
+	}
+	return nil, fmt.Errorf("unknown embeddings provider %q (want %q)", cfg.Name, ProviderLocal)
+}
+
+// Ollama embeds through an Ollama server's /api/embed endpoint. The model
+// must have been pulled on the server (`ollama pull nomic-embed-text`).
+type Ollama struct {
+	url     string
+	model   string
+	client  *http.Client
+	timeout time.Duration
+}
+
+var _ Provider = (*Ollama)(nil)
+
+// NewOllama returns a provider that embeds with model on the Ollama server
+// at baseURL, giving each Embed call DefaultTimeout. A nil client uses
+// httpclient.New(DefaultTimeout).
+func NewOllama(baseURL, model string, client *http.Client) *Ollama {
+	if client == nil {
+		client = httpclient.New(DefaultTimeout)
+	}
+	return &Ollama{url: strings.TrimRight(baseURL, "/") + "/api/embed", model: model, client: client, timeout: DefaultTimeout}
+}
+
+// Model implements Provider.
+func (o *Ollama) Model() string { return o.model }
+
+// Embed implements Provider.
+func (o *Ollama) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+	body, err := json.Marshal(map[string]any{"model": o.model, "input": texts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama embed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return nil, fmt.Errorf("ollama embed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var out struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("ollama embed: decode response: %w", err)
+	}
+	if len(out.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama embed: got %d embeddings for %d texts", len(out.Embeddings), len(texts))
+	}
+	return out.Embeddings, nil
+}
@@ -0,0 +1,236 @@
+// Package embeddings keeps a semantic search index in step with the
+// registry. Embeddings are generated when an artifact is published, so
+// rows published before the index existed, or whose text changed since,
+// have none or a stale one; Reindex walks the latest tag of every artifact
+// and embeds those, at a bounded rate.
+//
+// Semantic is the built-in index: it embeds with a Provider, such as a
+// local Ollama model, and keeps vectors in Postgres. A build with an index
+// of its own supplies an Index, the write side of its Ranker.
+package embeddings
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// DefaultRate is the number of embeddings Reindex generates per second
+// when Options.Rate is zero. Embedding providers rate-limit per minute,
+// and a backfill should not starve publish-time embedding.
+const DefaultRate = 5
+
+// listPageSize is the store page size used while walking each kind.
+const listPageSize = 200
+
+// Ref names the artifact an embedding belongs to, or one a Ranker
+// returned. Embeddings are kept for the latest tag only.
+type Ref struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Index stores embeddings.
+type Index interface {
+	// Checksum returns the checksum of the text ref's embedding was
+	// generated from, or "" if ref has none.
+	Checksum(ctx context.Context, ref Ref) (string, error)
+	// Embed generates ref's embedding from text and stores it with
+	// checksum, replacing any previous one.
+	Embed(ctx context.Context, ref Ref, text, checksum string) error
+}
+
+// Lister lists the rows of one kind. *v1alpha1store.Store satisfies it.
+type Lister interface {
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+}
+
+var _ Lister = (*v1alpha1store.Store)(nil)
+
+// Options tune a Reindex run.
+type Options struct {
+	// Kinds are the kinds to walk, in order. Kinds without a store are
+	// skipped.
+	Kinds []string
+	// Namespace limits the run to one namespace. Empty walks every
+	// namespace.
+	Namespace string
+	// DryRun reports what would be embedded without embedding it.
+	DryRun bool
+	// Force re-embeds artifacts whose embedding is up to date.
+	Force bool
+	// Rate caps embeddings per second. Zero means DefaultRate.
+	Rate float64
+}
+
+// Reindex embeds the latest tag of every artifact of opts.Kinds in stores
+// whose embedding in index is missing or was generated from other text,
+// calling emit with a "planned" event, an "item" event per artifact and a
+// "done" event. A failed embedding is reported and skipped; errors
+// listing the stores, reading checksums or from emit end the run, as does
+// the provider's circuit breaker opening (ErrCircuitOpen). An
+// interrupted run can simply be repeated: what it embedded is up to date.
+func Reindex(ctx context.Context, stores map[string]Lister, index Index, opts Options, emit func(arv0.EmbeddingsReindexEvent) error) error {
+	type job struct {
+		ref            Ref
+		text, checksum string
+		reason         string
+	}
+	var jobs []job
+	planned := arv0.EmbeddingsReindexEvent{Type: arv0.EmbeddingsReindexPlanned, DryRun: opts.DryRun}
+	for _, kind := range opts.Kinds {
+		store := stores[kind]
+		if store == nil {
+			continue
+		}
+		err := listLatest(ctx, store, kind, opts.Namespace, func(row *v1alpha1.RawObject) error {
+			planned.Scanned++
+			ref := Ref{Kind: kind, Namespace: row.Metadata.NamespaceOrDefault(), Name: row.Metadata.Name}
+			text, err := Document(row)
+			if err != nil {
+				return fmt.Errorf("read %s %s/%s: %w", kind, ref.Namespace, ref.Name, err)
+			}
+			checksum := Checksum(text)
+			stored, err := index.Checksum(ctx, ref)
+			if err != nil {
+				return fmt.Errorf("read embedding checksum of %s %s/%s: %w", kind, ref.Namespace, ref.Name, err)
+			}
+			reason := ""
+			switch {
+			case stored == "":
+				reason = arv0.EmbeddingsReasonMissing
+			case stored != checksum:
+				reason = arv0.EmbeddingsReasonStale
+			case opts.Force:
+				reason = arv0.EmbeddingsReasonForced
+			default:
+				planned.UpToDate++
+				return nil
+			}
+			jobs = append(jobs, job{ref: ref, text: text, checksum: checksum, reason: reason})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	planned.Total = len(jobs)
+	if err := emit(planned); err != nil {
+		return err
+	}
+
+	rate := opts.Rate
+	if rate <= 0 {
+		rate = DefaultRate
+	}
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+	defer ticker.Stop()
+
+	done := planned
+	done.Type = arv0.EmbeddingsReindexDone
+	for i, j := range jobs {
+		item := arv0.EmbeddingsReindexEvent{
+			Type:      arv0.EmbeddingsReindexItem,
+			Kind:      j.ref.Kind,
+			Namespace: j.ref.Namespace,
+			Name:      j.ref.Name,
+			Reason:    j.reason,
+			Processed: i + 1,
+			Total:     len(jobs),
+			DryRun:    opts.DryRun,
+		}
+		if !opts.DryRun {
+			if i > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-ticker.C:
+				}
+			}
+			if err := index.Embed(ctx, j.ref, j.text, j.checksum); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if errors.Is(err, ErrCircuitOpen) {
+					return fmt.Errorf("stopped after %d of %d embeddings: %w", i, len(jobs), err)
+				}
+				item.Error = err.Error()
+				done.Failed++
+			}
+		}
+		if err := emit(item); err != nil {
+			return err
+		}
+		done.Processed = i + 1
+	}
+	return emit(done)
+}
+
+// Document returns the text an artifact is embedded from: its name,
+// title, description, the names of the tools an MCP server lists and
+// README, one per line.
+func Document(row *v1alpha1.RawObject) (string, error) {
+	var spec struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Tools       []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+		Readme string `json:"readme"`
+	}
+	if len(row.Spec) > 0 {
+		if err := json.Unmarshal(row.Spec, &spec); err != nil {
+			return "", err
+		}
+	}
+	var tools string
+	if len(spec.Tools) > 0 {
+		names := make([]string, 0, len(spec.Tools))
+		for _, tool := range spec.Tools {
+			names = append(names, tool.Name)
+		}
+		tools = "Tools: " + strings.Join(names, ", ")
+	}
+	var parts []string
+	for _, part := range []string{row.Metadata.Name, spec.Title, spec.Description, tools, spec.Readme} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+// Checksum returns the checksum Index stores with an embedding of text.
+func Checksum(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func listLatest(ctx context.Context, store Lister, kind, namespace string, fn func(*v1alpha1.RawObject) error) error {
+	opts := v1alpha1store.ListOpts{Namespace: namespace, LatestOnly: true, Limit: listPageSize}
+	for {
+		rows, next, err := store.List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("list %ss: %w", kind, err)
+		}
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		opts.Cursor = next
+	}
+}
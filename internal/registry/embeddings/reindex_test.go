@@ -0,0 +1,165 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeLister struct {
+	rows []*v1alpha1.RawObject
+}
+
+func (f fakeLister) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	var out []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		if opts.LatestOnly && row.Metadata.Tag != "latest" {
+			continue
+		}
+		if opts.Namespace == "" || row.Metadata.Namespace == opts.Namespace {
+			out = append(out, row)
+		}
+	}
+	return out, "", nil
+}
+
+// fakeIndex stores checksums and fails to embed the names in fail, with
+// failWith when set.
+type fakeIndex struct {
+	checksums map[Ref]string
+	embedded  []Ref
+	fail      map[string]bool
+	failWith  error
+}
+
+func (f *fakeIndex) Checksum(_ context.Context, ref Ref) (string, error) {
+	return f.checksums[ref], nil
+}
+
+func (f *fakeIndex) Embed(_ context.Context, ref Ref, _, checksum string) error {
+	if f.fail[ref.Name] {
+		if f.failWith != nil {
+			return f.failWith
+		}
+		return errors.New("quota exceeded")
+	}
+	f.embedded = append(f.embedded, ref)
+	f.checksums[ref] = checksum
+	return nil
+}
+
+func row(t *testing.T, name, tag string, spec any) *v1alpha1.RawObject {
+	t.Helper()
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: name, Tag: tag}, Spec: data}
+}
+
+func TestReindex(t *testing.T) {
+	current := row(t, "current", "latest", v1alpha1.MCPServerSpec{Title: "Current"})
+	currentText, err := Document(current)
+	require.NoError(t, err)
+
+	stores := map[string]Lister{
+		v1alpha1.KindMCPServer: fakeLister{rows: []*v1alpha1.RawObject{
+			current,
+			row(t, "stale", "latest", v1alpha1.MCPServerSpec{Title: "Stale", Readme: "# New readme"}),
+			row(t, "stale", "1.0.0", v1alpha1.MCPServerSpec{Title: "Old"}),
+		}},
+		v1alpha1.KindAgent: fakeLister{rows: []*v1alpha1.RawObject{
+			row(t, "missing", "latest", v1alpha1.AgentSpec{Description: "Reports."}),
+			row(t, "broken", "latest", v1alpha1.AgentSpec{}),
+		}},
+	}
+	newIndex := func() *fakeIndex {
+		return &fakeIndex{
+			checksums: map[Ref]string{
+				{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "current"}: Checksum(currentText),
+				{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "stale"}:   Checksum("stale"),
+			},
+			fail: map[string]bool{"broken": true},
+		}
+	}
+	opts := Options{Kinds: []string{v1alpha1.KindMCPServer, v1alpha1.KindAgent}, Rate: 1000}
+	run := func(index *fakeIndex, opts Options) []arv0.EmbeddingsReindexEvent {
+		var events []arv0.EmbeddingsReindexEvent
+		require.NoError(t, Reindex(context.Background(), stores, index, opts, func(e arv0.EmbeddingsReindexEvent) error {
+			events = append(events, e)
+			return nil
+		}))
+		return events
+	}
+
+	index := newIndex()
+	events := run(index, opts)
+	require.Len(t, events, 5)
+	require.Equal(t, arv0.EmbeddingsReindexEvent{Type: arv0.EmbeddingsReindexPlanned, Total: 3, Scanned: 4, UpToDate: 1}, events[0])
+	require.Equal(t, arv0.EmbeddingsReindexEvent{
+		Type: arv0.EmbeddingsReindexItem, Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "stale",
+		Reason: arv0.EmbeddingsReasonStale, Processed: 1, Total: 3,
+	}, events[1])
+	require.Equal(t, arv0.EmbeddingsReasonMissing, events[2].Reason)
+	require.Equal(t, "quota exceeded", events[3].Error)
+	require.Equal(t, arv0.EmbeddingsReindexEvent{Type: arv0.EmbeddingsReindexDone, Processed: 3, Total: 3, Scanned: 4, UpToDate: 1, Failed: 1}, events[4])
+	require.Equal(t, []Ref{
+		{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "stale"},
+		{Kind: v1alpha1.KindAgent, Namespace: "default", Name: "missing"},
+	}, index.embedded)
+
+	// A second run only retries the failure.
+	events = run(index, opts)
+	require.Equal(t, 1, events[0].Total)
+	require.Equal(t, "broken", events[1].Name)
+
+	// A dry run embeds nothing; force includes up-to-date artifacts.
+	index = newIndex()
+	opts.DryRun, opts.Force = true, true
+	events = run(index, opts)
+	require.Equal(t, 4, events[0].Total)
+	require.Equal(t, arv0.EmbeddingsReasonForced, events[1].Reason)
+	require.Zero(t, events[len(events)-1].Failed)
+	require.Empty(t, index.embedded)
+}
+
+func TestReindex_StopsWhileProviderUnavailable(t *testing.T) {
+	stores := map[string]Lister{
+		v1alpha1.KindAgent: fakeLister{rows: []*v1alpha1.RawObject{
+			row(t, "first", "latest", v1alpha1.AgentSpec{Description: "One."}),
+			row(t, "second", "latest", v1alpha1.AgentSpec{Description: "Two."}),
+		}},
+	}
+	index := &fakeIndex{
+		checksums: map[Ref]string{},
+		fail:      map[string]bool{"first": true, "second": true},
+		failWith:  &UnavailableError{Err: ErrCircuitOpen},
+	}
+	var events []arv0.EmbeddingsReindexEvent
+	err := Reindex(context.Background(), stores, index, Options{Kinds: []string{v1alpha1.KindAgent}, Rate: 1000},
+		func(e arv0.EmbeddingsReindexEvent) error {
+			events = append(events, e)
+			return nil
+		})
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.ErrorContains(t, err, "stopped after 0 of 2")
+	require.Len(t, events, 1, "only the plan is reported")
+}
+
+func TestDocument(t *testing.T) {
+	text, err := Document(row(t, "weather", "latest", v1alpha1.MCPServerSpec{Title: "Weather", Readme: " # Weather \n"}))
+	require.NoError(t, err)
+	require.Equal(t, "weather\nWeather\n# Weather", text)
+
+	text, err = Document(row(t, "weather", "latest", v1alpha1.MCPServerSpec{
+		Description: "Forecasts.",
+		Tools:       []v1alpha1.MCPTool{{Name: "get_forecast"}, {Name: "get_alerts"}},
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "weather\nForecasts.\nTools: get_forecast, get_alerts", text)
+}
@@ -0,0 +1,288 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/telemetry"
+)
+
+// Default settings of a Resilient provider.
+const (
+	DefaultRetries          = 2
+	DefaultRetryBackoff     = 250 * time.Millisecond
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// Outcomes recorded on the provider request counter.
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+	// OutcomeRejected counts calls refused without reaching the provider
+	// because the circuit breaker was open.
+	OutcomeRejected = "rejected"
+)
+
+// ErrCircuitOpen is the cause of an UnavailableError returned without
+// calling the provider, because its recent calls kept failing.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// UnavailableError reports that a Resilient provider could not embed:
+// every attempt failed, or the circuit breaker is open and the provider
+// was not called. RetryAfter is how long the breaker stays open.
+type UnavailableError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *UnavailableError) Error() string {
+	return "embeddings provider unavailable: " + e.Err.Error()
+}
+
+func (e *UnavailableError) Unwrap() error { return e.Err }
+
+// ResilienceConfig tunes a Resilient provider.
+type ResilienceConfig struct {
+	// Retries is how many times a failed Embed call is retried, waiting
+	// Backoff before the first retry and twice as long before each next.
+	Retries int
+	// Backoff is the wait before the first retry. Zero uses
+	// DefaultRetryBackoff.
+	Backoff time.Duration
+	// BreakerThreshold is the number of consecutive failed calls that
+	// opens the circuit breaker. Zero uses DefaultBreakerThreshold.
+	BreakerThreshold int
+	// BreakerCooldown is how long the breaker stays open before one call
+	// is let through to probe the provider. Zero uses
+	// DefaultBreakerCooldown.
+	BreakerCooldown time.Duration
+	// Meter, when set, records provider latency, outcomes and breaker
+	// state.
+	Meter metric.Meter
+}
+
+// Resilient wraps a Provider with retries and a circuit breaker. After
+// BreakerThreshold consecutive calls fail, it refuses calls for
+// BreakerCooldown with an UnavailableError wrapping ErrCircuitOpen, so
+// a provider that is down is not hammered by every publish and search
+// and semantic queries fail fast. The first call after the cooldown
+// probes the provider: success closes the breaker, failure reopens it.
+type Resilient struct {
+	next    Provider
+	cfg     ResilienceConfig
+	metrics *providerMetrics
+	// now and sleep are swapped in tests.
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) bool
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+var _ Provider = (*Resilient)(nil)
+
+// NewResilient returns next wrapped with the retries and circuit breaker
+// cfg describes.
+func NewResilient(next Provider, cfg ResilienceConfig) (*Resilient, error) {
+	if cfg.Retries < 0 {
+		cfg.Retries = 0
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = DefaultRetryBackoff
+	}
+	if cfg.BreakerThreshold <= 0 {
+		cfg.BreakerThreshold = DefaultBreakerThreshold
+	}
+	if cfg.BreakerCooldown <= 0 {
+		cfg.BreakerCooldown = DefaultBreakerCooldown
+	}
+	r := &Resilient{next: next, cfg: cfg, now: time.Now, sleep: sleep}
+	metrics, err := newProviderMetrics(cfg.Meter, r.Open)
+	if err != nil {
+		return nil, err
+	}
+	r.metrics = metrics
+	return r, nil
+}
+
+// Model implements Provider.
+func (r *Resilient) Model() string { return r.next.Model() }
+
+// Open reports whether the circuit breaker is refusing calls.
+func (r *Resilient) Open() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.openUntil.IsZero()
+}
+
+// Embed implements Provider. A call the caller cancels is neither retried
+// nor counted against the provider.
+func (r *Resilient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	probe, err := r.admit()
+	if err != nil {
+		r.metrics.reject(ctx)
+		return nil, err
+	}
+	backoff := r.cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		vectors, err := r.next.Embed(ctx, texts)
+		r.metrics.observe(ctx, err, time.Since(start))
+		if err == nil {
+			r.record(probe, nil)
+			return vectors, nil
+		}
+		// A probe is not retried: one failure is enough to reopen.
+		if ctx.Err() == nil && !probe && attempt < r.cfg.Retries && r.sleep(ctx, backoff) {
+			backoff *= 2
+			continue
+		}
+		if ctx.Err() != nil {
+			r.release(probe)
+			return nil, ctx.Err()
+		}
+		r.record(probe, err)
+		return nil, &UnavailableError{Err: err}
+	}
+}
+
+// admit lets a call through while the breaker is closed, and the first
+// call after the cooldown as a probe.
+func (r *Resilient) admit() (probe bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.openUntil.IsZero() {
+		return false, nil
+	}
+	wait := r.openUntil.Sub(r.now())
+	if wait > 0 || r.probing {
+		return false, &UnavailableError{Err: ErrCircuitOpen, RetryAfter: max(wait, 0)}
+	}
+	r.probing = true
+	return true, nil
+}
+
+// record counts the outcome of an admitted call, opening the breaker on
+// the failure that reaches the threshold or on a failed probe.
+func (r *Resilient) record(probe bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if probe {
+		r.probing = false
+	}
+	if err == nil {
+		if !r.openUntil.IsZero() {
+			slog.Info("embeddings provider recovered; circuit breaker closed", "model", r.next.Model())
+		}
+		r.failures = 0
+		r.openUntil = time.Time{}
+		return
+	}
+	r.failures++
+	if !probe && r.failures < r.cfg.BreakerThreshold {
+		return
+	}
+	if r.openUntil.IsZero() || probe {
+		slog.Warn("embeddings provider failing; circuit breaker open",
+			"model", r.next.Model(), "failures", r.failures, "cooldown", r.cfg.BreakerCooldown, "error", err)
+	}
+	r.openUntil = r.now().Add(r.cfg.BreakerCooldown)
+}
+
+// release frees the probe slot of a call the caller cancelled, leaving
+// the breaker as it was.
+func (r *Resilient) release(probe bool) {
+	if !probe {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.probing = false
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// providerMetrics records embedding provider latency and outcomes. A nil
+// *providerMetrics is valid and records nothing.
+type providerMetrics struct {
+	duration metric.Float64Histogram
+	requests metric.Int64Counter
+}
+
+func newProviderMetrics(meter metric.Meter, open func() bool) (*providerMetrics, error) {
+	if meter == nil {
+		return nil, nil
+	}
+	duration, err := meter.Float64Histogram(
+		telemetry.Namespace+".embeddings.provider.duration",
+		metric.WithDescription("Duration of embedding provider calls in seconds"),
+		metric.WithExplicitBucketBoundaries(
+			0.01, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0,
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings provider duration histogram: %w", err)
+	}
+	requests, err := meter.Int64Counter(
+		telemetry.Namespace+".embeddings.provider.requests",
+		metric.WithDescription("Embedding provider calls by outcome"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings provider request counter: %w", err)
+	}
+	_, err = meter.Int64ObservableGauge(
+		telemetry.Namespace+".embeddings.breaker.open",
+		metric.WithDescription("1 while the embedding provider's circuit breaker is open, else 0"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			var v int64
+			if open() {
+				v = 1
+			}
+			o.Observe(v)
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings breaker gauge: %w", err)
+	}
+	return &providerMetrics{duration: duration, requests: requests}, nil
+}
+
+func (m *providerMetrics) observe(ctx context.Context, err error, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+	attrs := metric.WithAttributes(attribute.String("outcome", outcome))
+	m.duration.Record(ctx, elapsed.Seconds(), attrs)
+	m.requests.Add(ctx, 1, attrs)
+}
+
+func (m *providerMetrics) reject(ctx context.Context) {
+	if m == nil {
+		return
+	}
+	m.requests.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", OutcomeRejected)))
+}
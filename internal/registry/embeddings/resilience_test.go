@@ -0,0 +1,158 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// flakyProvider fails its first failures calls, then embeds every text
+// as a one-element vector.
+type flakyProvider struct {
+	failures int
+	calls    int
+}
+
+func (p *flakyProvider) Model() string { return "flaky" }
+
+func (p *flakyProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	p.calls++
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if p.calls <= p.failures {
+		return nil, errors.New("connection refused")
+	}
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i] = []float32{1}
+	}
+	return out, nil
+}
+
+// newTestResilient returns a Resilient over p with a fake clock it
+// returns, recording the backoffs it would have slept.
+func newTestResilient(t *testing.T, p Provider, cfg ResilienceConfig) (*Resilient, *time.Time, *[]time.Duration) {
+	t.Helper()
+	r, err := NewResilient(p, cfg)
+	require.NoError(t, err)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	r.now = func() time.Time { return now }
+	r.sleep = func(ctx context.Context, d time.Duration) bool {
+		slept = append(slept, d)
+		return ctx.Err() == nil
+	}
+	return r, &now, &slept
+}
+
+func TestResilient_RetriesWithBackoff(t *testing.T) {
+	p := &flakyProvider{failures: 2}
+	r, _, slept := newTestResilient(t, p, ResilienceConfig{Retries: 2, Backoff: 100 * time.Millisecond})
+
+	vectors, err := r.Embed(context.Background(), []string{"weather"})
+	require.NoError(t, err)
+	require.Len(t, vectors, 1)
+	require.Equal(t, 3, p.calls)
+	require.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *slept)
+
+	p = &flakyProvider{failures: 3}
+	r, _, _ = newTestResilient(t, p, ResilienceConfig{Retries: 2})
+	_, err = r.Embed(context.Background(), []string{"weather"})
+	var unavailable *UnavailableError
+	require.ErrorAs(t, err, &unavailable)
+	require.NotErrorIs(t, err, ErrCircuitOpen)
+	require.ErrorContains(t, err, "connection refused")
+	require.Equal(t, 3, p.calls)
+}
+
+func TestResilient_BreakerOpensAndProbes(t *testing.T) {
+	p := &flakyProvider{failures: 3}
+	r, now, _ := newTestResilient(t, p, ResilienceConfig{BreakerThreshold: 2, BreakerCooldown: 10 * time.Second})
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := r.Embed(ctx, []string{"weather"})
+		require.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+	require.True(t, r.Open())
+
+	_, err := r.Embed(ctx, []string{"weather"})
+	var unavailable *UnavailableError
+	require.ErrorAs(t, err, &unavailable)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, 10*time.Second, unavailable.RetryAfter)
+	require.Equal(t, 2, p.calls, "an open breaker does not call the provider")
+
+	// The probe after the cooldown fails and reopens the breaker.
+	*now = now.Add(11 * time.Second)
+	_, err = r.Embed(ctx, []string{"weather"})
+	require.NotErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, 3, p.calls, "a failed probe is not retried")
+	_, err = r.Embed(ctx, []string{"weather"})
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	// The next probe succeeds and closes it.
+	*now = now.Add(11 * time.Second)
+	_, err = r.Embed(ctx, []string{"weather"})
+	require.NoError(t, err)
+	require.False(t, r.Open())
+	_, err = r.Embed(ctx, []string{"weather"})
+	require.NoError(t, err)
+}
+
+func TestResilient_CancelledCallsDoNotTripBreaker(t *testing.T) {
+	p := &flakyProvider{}
+	r, _, slept := newTestResilient(t, p, ResilienceConfig{BreakerThreshold: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := r.Embed(ctx, []string{"weather"})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, *slept)
+	require.False(t, r.Open())
+}
+
+func TestResilient_RecordsMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+	p := &flakyProvider{failures: 2}
+	r, _, _ := newTestResilient(t, p, ResilienceConfig{Retries: 1, BreakerThreshold: 1, Meter: meter})
+
+	_, err := r.Embed(context.Background(), []string{"weather"})
+	require.Error(t, err)
+	_, err = r.Embed(context.Background(), []string{"weather"})
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	counts := map[string]int64{}
+	var open int64 = -1
+	var latencies uint64
+	for _, sm := range rm.ScopeMetrics {
+		for _, md := range sm.Metrics {
+			switch data := md.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					outcome, _ := dp.Attributes.Value(attribute.Key("outcome"))
+					counts[outcome.AsString()] += dp.Value
+				}
+			case metricdata.Gauge[int64]:
+				open = data.DataPoints[0].Value
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					latencies += dp.Count
+				}
+			}
+		}
+	}
+	require.Equal(t, map[string]int64{OutcomeError: 2, OutcomeRejected: 1}, counts)
+	require.Equal(t, uint64(2), latencies)
+	require.Equal(t, int64(1), open)
+}
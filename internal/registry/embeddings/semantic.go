@@ -0,0 +1,204 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// VectorStore keeps embeddings. *v1alpha1store.ArtifactEmbeddingStore
+// satisfies it.
+type VectorStore interface {
+	Put(ctx context.Context, e v1alpha1store.ArtifactEmbedding) error
+	Get(ctx context.Context, kind, namespace, name string) (*v1alpha1store.ArtifactEmbedding, error)
+	List(ctx context.Context, kinds []string, model string) ([]v1alpha1store.ArtifactEmbedding, error)
+}
+
+var _ VectorStore = (*v1alpha1store.ArtifactEmbeddingStore)(nil)
+
+// Ranker ranks artifacts by meaning rather than wording, best first, for
+// GET /v0/search. Semantic is the built-in one.
+type Ranker interface {
+	Rank(ctx context.Context, query string, kinds []string, limit int) ([]Ref, error)
+}
+
+// Semantic is the built-in semantic index: it embeds artifacts with a
+// Provider, keeps the vectors in a VectorStore and ranks them by cosine
+// similarity to the query. Ranking scans every vector of the searched
+// kinds, which is fine at registry scale.
+type Semantic struct {
+	provider Provider
+	store    VectorStore
+}
+
+var (
+	_ Index  = (*Semantic)(nil)
+	_ Ranker = (*Semantic)(nil)
+)
+
+// NewSemantic returns a semantic index over store that embeds with
+// provider.
+func NewSemantic(provider Provider, store VectorStore) *Semantic {
+	return &Semantic{provider: provider, store: store}
+}
+
+// Checksum implements Index. An embedding produced by another model
+// counts as missing, so changing the model re-embeds everything.
+func (s *Semantic) Checksum(ctx context.Context, ref Ref) (string, error) {
+	e, err := s.store.Get(ctx, ref.Kind, ref.Namespace, ref.Name)
+	if errors.Is(err, pkgdb.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if e.Model != s.provider.Model() {
+		return "", nil
+	}
+	return e.Checksum, nil
+}
+
+// Embed implements Index.
+func (s *Semantic) Embed(ctx context.Context, ref Ref, text, checksum string) error {
+	vectors, err := s.provider.Embed(ctx, []string{text})
+	if err != nil {
+		return err
+	}
+	return s.store.Put(ctx, v1alpha1store.ArtifactEmbedding{
+		Kind:      ref.Kind,
+		Namespace: ref.Namespace,
+		Name:      ref.Name,
+		Model:     s.provider.Model(),
+		Checksum:  checksum,
+		Vector:    vectors[0],
+	})
+}
+
+// Rank implements Ranker.
+func (s *Semantic) Rank(ctx context.Context, query string, kinds []string, limit int) ([]Ref, error) {
+	vectors, err := s.provider.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	rows, err := s.store.List(ctx, kinds, s.provider.Model())
+	if err != nil {
+		return nil, err
+	}
+	type scored struct {
+		ref   Ref
+		score float64
+	}
+	ranked := make([]scored, 0, len(rows))
+	for _, row := range rows {
+		ranked = append(ranked, scored{
+			ref:   Ref{Kind: row.Kind, Namespace: row.Namespace, Name: row.Name},
+			score: Cosine(vectors[0], row.Vector),
+		})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	out := make([]Ref, len(ranked))
+	for i, r := range ranked {
+		out[i] = r.ref
+	}
+	return out, nil
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 when their
+// lengths differ or either is zero.
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// Getter reads the latest tag of an artifact. *v1alpha1store.Store
+// satisfies it.
+type Getter interface {
+	GetLatest(ctx context.Context, namespace, name string) (*v1alpha1.RawObject, error)
+}
+
+// Embedder keeps an Index current as artifacts are published: on every new
+// tag it embeds the artifact's latest tag if its text changed. It
+// implements types.Auditor; embedding runs in the background and never
+// fails the publish. While the provider's circuit breaker is open
+// publishes are not embedded; a reindex catches them up.
+type Embedder struct {
+	index  Index
+	stores map[string]Getter
+	wg     sync.WaitGroup
+}
+
+// NewEmbedder returns an Embedder for the kinds in stores.
+func NewEmbedder(index Index, stores map[string]Getter) *Embedder {
+	return &Embedder{index: index, stores: stores}
+}
+
+// ResourceTagCreated implements types.Auditor.
+func (e *Embedder) ResourceTagCreated(ctx context.Context, kind, namespace, name, _ string) {
+	store := e.stores[kind]
+	if store == nil {
+		return
+	}
+	// Detach from the request and read regardless of the caller's scope.
+	ctx = auth.WithSystemContext(context.WithoutCancel(ctx))
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		err := e.embedLatest(ctx, store, Ref{Kind: kind, Namespace: namespace, Name: name})
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrCircuitOpen):
+			slog.Warn("embedding on publish skipped while the embeddings provider is unavailable; reindex to catch up",
+				"kind", kind, "namespace", namespace, "name", name)
+		default:
+			slog.Error("embedding on publish failed", "kind", kind, "namespace", namespace, "name", name, "error", err)
+		}
+	}()
+}
+
+// Wait blocks until in-flight embeddings finish. Tests use it.
+func (e *Embedder) Wait() {
+	e.wg.Wait()
+}
+
+func (e *Embedder) embedLatest(ctx context.Context, store Getter, ref Ref) error {
+	row, err := store.GetLatest(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return err
+	}
+	text, err := Document(row)
+	if err != nil {
+		return err
+	}
+	checksum := Checksum(text)
+	stored, err := e.index.Checksum(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if stored == checksum {
+		return nil
+	}
+	return e.index.Embed(ctx, ref, text, checksum)
+}
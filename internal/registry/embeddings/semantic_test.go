@@ -0,0 +1,197 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// wordProvider embeds a text as the counts of a fixed vocabulary.
+type wordProvider struct{ model string }
+
+var vocabulary = []string{"weather", "forecast", "maps", "route"}
+
+func (p wordProvider) Model() string { return p.model }
+
+func (p wordProvider) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = make([]float32, len(vocabulary))
+		for j, word := range vocabulary {
+			out[i][j] = float32(strings.Count(strings.ToLower(text), word))
+		}
+	}
+	return out, nil
+}
+
+type fakeVectors struct {
+	mu   sync.Mutex
+	rows map[Ref]v1alpha1store.ArtifactEmbedding
+}
+
+func (f *fakeVectors) Put(_ context.Context, e v1alpha1store.ArtifactEmbedding) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.rows == nil {
+		f.rows = map[Ref]v1alpha1store.ArtifactEmbedding{}
+	}
+	f.rows[Ref{Kind: e.Kind, Namespace: e.Namespace, Name: e.Name}] = e
+	return nil
+}
+
+func (f *fakeVectors) Get(_ context.Context, kind, namespace, name string) (*v1alpha1store.ArtifactEmbedding, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, ok := f.rows[Ref{Kind: kind, Namespace: namespace, Name: name}]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	return &e, nil
+}
+
+func (f *fakeVectors) List(_ context.Context, kinds []string, model string) ([]v1alpha1store.ArtifactEmbedding, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []v1alpha1store.ArtifactEmbedding
+	for _, e := range f.rows {
+		for _, kind := range kinds {
+			if e.Kind == kind && e.Model == model {
+				out = append(out, e)
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestSemantic(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeVectors{}
+	index := NewSemantic(wordProvider{model: "words"}, store)
+
+	weather := Ref{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "weather"}
+	maps := Ref{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "maps"}
+	planner := Ref{Kind: v1alpha1.KindAgent, Namespace: "default", Name: "planner"}
+	require.NoError(t, index.Embed(ctx, weather, "weather forecast", "c1"))
+	require.NoError(t, index.Embed(ctx, maps, "maps route", "c2"))
+	require.NoError(t, index.Embed(ctx, planner, "route weather", "c3"))
+
+	got, err := index.Checksum(ctx, weather)
+	require.NoError(t, err)
+	require.Equal(t, "c1", got)
+	got, err = index.Checksum(ctx, Ref{Kind: v1alpha1.KindSkill, Name: "missing"})
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	ranked, err := index.Rank(ctx, "forecast of the weather", []string{v1alpha1.KindMCPServer, v1alpha1.KindAgent}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []Ref{
+		{Kind: weather.Kind, Namespace: weather.Namespace, Name: weather.Name},
+		{Kind: planner.Kind, Namespace: planner.Namespace, Name: planner.Name},
+	}, ranked)
+
+	ranked, err = index.Rank(ctx, "route", []string{v1alpha1.KindMCPServer}, 10)
+	require.NoError(t, err)
+	require.Equal(t, "maps", ranked[0].Name)
+
+	// Another model's embeddings count as missing and are never ranked.
+	other := NewSemantic(wordProvider{model: "other"}, store)
+	got, err = other.Checksum(ctx, weather)
+	require.NoError(t, err)
+	require.Empty(t, got)
+	ranked, err = other.Rank(ctx, "weather", []string{v1alpha1.KindMCPServer}, 10)
+	require.NoError(t, err)
+	require.Empty(t, ranked)
+}
+
+type fakeGetter map[string]*v1alpha1.RawObject
+
+func (f fakeGetter) GetLatest(_ context.Context, _, name string) (*v1alpha1.RawObject, error) {
+	if row, ok := f[name]; ok {
+		return row, nil
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+func TestEmbedderEmbedsOnPublish(t *testing.T) {
+	index := &fakeIndex{checksums: map[Ref]string{}}
+	e := NewEmbedder(index, map[string]Getter{
+		v1alpha1.KindMCPServer: fakeGetter{"weather": row(t, "weather", "latest", v1alpha1.MCPServerSpec{Description: "Forecasts."})},
+	})
+
+	e.ResourceTagCreated(context.Background(), v1alpha1.KindMCPServer, "default", "weather", "1.0.0")
+	e.Wait()
+	ref := Ref{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "weather"}
+	require.Equal(t, []Ref{ref}, index.embedded)
+
+	// An unchanged latest tag is not embedded again, and kinds without a
+	// store are ignored.
+	e.ResourceTagCreated(context.Background(), v1alpha1.KindMCPServer, "default", "weather", "0.9.0")
+	e.ResourceTagCreated(context.Background(), v1alpha1.KindAgent, "default", "planner", "1.0.0")
+	e.Wait()
+	require.Len(t, index.embedded, 1)
+}
+
+func TestOllama(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/embed", r.URL.Path)
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Model != "nomic-embed-text" {
+			http.Error(w, `{"error":"model not found"}`, http.StatusNotFound)
+			return
+		}
+		out := map[string][][]float32{"embeddings": {}}
+		for i := range req.Input {
+			out["embeddings"] = append(out["embeddings"], []float32{float32(i), 1})
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	provider, err := NewProvider(ProviderConfig{Name: ProviderLocal, URL: srv.URL + "/"}, srv.Client())
+	require.NoError(t, err)
+	require.Equal(t, DefaultLocalModel, provider.Model())
+	vectors, err := provider.Embed(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{0, 1}, {1, 1}}, vectors)
+
+	_, err = NewOllama(srv.URL, "missing", srv.Client()).Embed(context.Background(), []string{"a"})
+	require.ErrorContains(t, err, "404")
+}
+
+func TestOllamaEmbedTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	provider, err := NewProvider(ProviderConfig{Name: ProviderLocal, URL: srv.URL, Timeout: 50 * time.Millisecond}, nil)
+	require.NoError(t, err)
+	_, err = provider.Embed(context.Background(), []string{"a"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(ProviderConfig{}, nil)
+	require.NoError(t, err)
+	require.Nil(t, provider)
+
+	_, err = NewProvider(ProviderConfig{Name: "onnx"}, nil)
+	require.ErrorContains(t, err, `unknown embeddings provider "onnx"`)
+}
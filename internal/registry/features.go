@@ -0,0 +1,308 @@
+package registry
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/router"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/config"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/controller"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/deploylock"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/embeddings"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/gc"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/mcptraffic"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/ownership"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/pipelines"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/prompteval"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimeaccess"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/snapshots"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/telemetry"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/usagestats"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/webhooks"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// features holds the optional subsystems App wires from config. The setup
+// methods leave a feature's fields nil when it is turned off or there is no
+// database (the noop path gen-openapi runs).
+type features struct {
+	cfg    *config.Config
+	pool   *pgxpool.Pool
+	stores map[string]*v1alpha1store.Store
+
+	triggers           *pipelines.Dispatcher
+	webhooks           *webhooks.Dispatcher
+	webhookDeliveries  *v1alpha1store.WebhookDeliveryStore
+	webhookDeadLetters *v1alpha1store.WebhookDeadLetterStore
+	semantic           *embeddings.Semantic
+	promptEvaluator    *prompteval.Evaluator
+	usage              *usagestats.Recorder
+	snapshotStore      *v1alpha1store.CatalogSnapshotStore
+	catalog            *snapshots.Catalog
+
+	deploymentLocks  *v1alpha1store.DeploymentLocks
+	namespaceClaims  *v1alpha1store.NamespaceStore
+	namespaceGuard   *ownership.Guard
+	reservedPrefixes *v1alpha1store.ReservedPrefixStore
+	runtimeGrants    *v1alpha1store.RuntimeGrantStore
+	runtimeGuard     *runtimeaccess.Guard
+	apiKeys          *v1alpha1store.APIKeyStore
+}
+
+// ossSchema is the schema every feature table lives in.
+func ossSchema() pkgdb.Schema {
+	return pkgdb.MustNewSchema(pkgdb.OSSSchema)
+}
+
+// setupAuditors wires the features that listen on the audit seam and
+// returns auditor wrapped with them. They must be set up before the stores
+// are built, since the stores report to the returned auditor.
+func (f *features) setupAuditors(ctx context.Context, auditor types.Auditor) (types.Auditor, error) {
+	auditor, err := f.setupPublishTriggers(auditor)
+	if err != nil {
+		return nil, err
+	}
+	auditor = f.setupWebhooks(ctx, auditor)
+	if auditor, err = f.setupSemanticSearch(auditor); err != nil {
+		return nil, err
+	}
+	return f.setupPromptEvaluation(auditor)
+}
+
+func (f *features) setupPublishTriggers(auditor types.Auditor) (types.Auditor, error) {
+	if f.cfg.PublishTriggersFile == "" {
+		return auditor, nil
+	}
+	triggers, err := pipelines.LoadFile(f.cfg.PublishTriggersFile)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("publish triggers enabled", "count", len(triggers.Triggers))
+	f.triggers = pipelines.NewDispatcher(*triggers, httpclient.New(0))
+	return types.MultiAuditor(auditor, f.triggers), nil
+}
+
+// setupWebhooks gives Webhook resources publish and deployment events from
+// the audit seam, plus apply failures from the Deployment controller. The
+// dispatcher reads webhooks through its own store so it does not depend on
+// the audited stores it is plugged into.
+func (f *features) setupWebhooks(ctx context.Context, auditor types.Auditor) types.Auditor {
+	if f.pool == nil {
+		return auditor
+	}
+	f.webhookDeliveries = v1alpha1store.NewWebhookDeliveryStore(f.pool, ossSchema())
+	f.webhookDeadLetters = v1alpha1store.NewWebhookDeadLetterStore(f.pool, ossSchema())
+	f.webhooks = webhooks.NewDispatcher(
+		v1alpha1store.NewMutableObjectStore(f.pool, ossSchema(), "webhooks", v1alpha1store.WithKind(v1alpha1.KindWebhook)),
+		f.webhookDeliveries,
+		httpclient.New(0),
+		webhooks.Config{
+			MaxAttempts:     f.cfg.WebhookMaxAttempts,
+			Backoff:         f.cfg.WebhookBackoff,
+			Retention:       f.cfg.WebhookDeliveryRetention,
+			DeadLetters:     f.webhookDeadLetters,
+			MaxRetries:      f.cfg.WebhookMaxRetries,
+			RetryBackoff:    f.cfg.WebhookRetryBackoff,
+			MaxRetryBackoff: f.cfg.WebhookMaxRetryBackoff,
+		},
+	)
+	go f.webhooks.RunRetries(ctx)
+	return types.MultiAuditor(auditor, f.webhooks)
+}
+
+// setupSemanticSearch has the built-in semantic index embed each
+// artifact's latest tag as it is published, reading rows through its own
+// stores like the webhook dispatcher does.
+func (f *features) setupSemanticSearch(auditor types.Auditor) (types.Auditor, error) {
+	if f.pool == nil {
+		return auditor, nil
+	}
+	provider, err := embeddings.NewProvider(embeddings.ProviderConfig{
+		Name:    f.cfg.EmbeddingsProvider,
+		URL:     f.cfg.EmbeddingsURL,
+		Model:   f.cfg.EmbeddingsModel,
+		Timeout: f.cfg.EmbeddingsTimeout,
+	}, httpclient.New(f.cfg.EmbeddingsTimeout))
+	if err != nil || provider == nil {
+		return auditor, err
+	}
+	resilient, err := embeddings.NewResilient(provider, embeddings.ResilienceConfig{
+		Retries:          f.cfg.EmbeddingsMaxRetries,
+		Backoff:          f.cfg.EmbeddingsRetryBackoff,
+		BreakerThreshold: f.cfg.EmbeddingsBreakerThreshold,
+		BreakerCooldown:  f.cfg.EmbeddingsBreakerCooldown,
+		Meter:            otel.Meter(telemetry.Namespace),
+	})
+	if err != nil {
+		return auditor, err
+	}
+	f.semantic = embeddings.NewSemantic(resilient, v1alpha1store.NewArtifactEmbeddingStore(f.pool, ossSchema()))
+	latest := v1alpha1store.NewStores(f.pool, pkgdb.OSSSchemaRegistry())
+	getters := map[string]embeddings.Getter{}
+	for _, kind := range []string{v1alpha1.KindMCPServer, v1alpha1.KindAgent, v1alpha1.KindSkill, v1alpha1.KindPrompt} {
+		if store := latest[kind]; store != nil {
+			getters[kind] = store
+		}
+	}
+	slog.Info("semantic search enabled", "provider", f.cfg.EmbeddingsProvider, "model", provider.Model())
+	return types.MultiAuditor(auditor, embeddings.NewEmbedder(f.semantic, getters)), nil
+}
+
+// setupPromptEvaluation scores Prompts that declare spec.evaluation
+// against the configured model as each tag is published.
+func (f *features) setupPromptEvaluation(auditor types.Auditor) (types.Auditor, error) {
+	if f.pool == nil {
+		return auditor, nil
+	}
+	model, err := prompteval.NewModel(prompteval.ProviderConfig{
+		Name:   f.cfg.PromptEvalProvider,
+		URL:    f.cfg.PromptEvalURL,
+		Model:  f.cfg.PromptEvalModel,
+		APIKey: f.cfg.PromptEvalAPIKey,
+	}, httpclient.New(f.cfg.PromptEvalTimeout))
+	if err != nil || model == nil {
+		return auditor, err
+	}
+	prompts := v1alpha1store.NewStores(f.pool, pkgdb.OSSSchemaRegistry())[v1alpha1.KindPrompt]
+	f.promptEvaluator = prompteval.NewEvaluator(model, prompts, v1alpha1store.NewPromptEvaluationStore(f.pool, ossSchema()))
+	slog.Info("prompt evaluation enabled", "provider", f.cfg.PromptEvalProvider, "model", model.Name())
+	return types.MultiAuditor(auditor, f.promptEvaluator), nil
+}
+
+// setupUsage buffers artifact downloads, deploys and search hits per
+// replica and flushes them to usage_stats in the background.
+func (f *features) setupUsage(ctx context.Context) {
+	if f.pool == nil {
+		return
+	}
+	f.usage = usagestats.New(v1alpha1store.NewUsageStatsStore(f.pool, ossSchema()))
+	go f.usage.Run(ctx)
+}
+
+// setupSnapshots lets Deployments pin to the artifact versions a catalog
+// snapshot captured at one point in time.
+func (f *features) setupSnapshots() {
+	if f.pool == nil {
+		return
+	}
+	f.snapshotStore = v1alpha1store.NewCatalogSnapshotStore(f.pool, ossSchema())
+	f.catalog = catalogSnapshots(f.snapshotStore, f.stores)
+}
+
+// configureController hands the Deployment controller the features it
+// reports to or resolves through.
+func (f *features) configureController(cc *controller.ControllerConfig) {
+	if f.webhooks != nil {
+		cc.FailureNotifier = f.webhooks
+	}
+	if f.usage != nil {
+		cc.Usage = f.usage
+	}
+	if f.catalog != nil {
+		cc.Snapshots = f.catalog
+	}
+}
+
+// configureRoutes registers the features' stores and services with the
+// API routes, and starts the version collector when it runs on a timer.
+func (f *features) configureRoutes(ctx context.Context, routeOpts *router.RouteOptions, auditor types.Auditor) {
+	if f.deploymentLocks != nil {
+		routeOpts.DeleteAdmission = deploylock.DeleteAdmission(f.deploymentLocks, routeOpts.DeleteAdmission)
+	}
+	if f.namespaceGuard != nil {
+		routeOpts.DeleteAdmission = f.namespaceGuard.DeleteAdmission(routeOpts.DeleteAdmission)
+		routeOpts.Namespaces = f.namespaceClaims
+	}
+	if f.apiKeys != nil {
+		routeOpts.APIKeys = f.apiKeys
+	}
+	if f.runtimeGuard != nil {
+		routeOpts.DeleteAdmission = f.runtimeGuard.DeleteAdmission(routeOpts.DeleteAdmission)
+		routeOpts.RuntimeGrants = f.runtimeGrants
+	}
+	if f.reservedPrefixes != nil {
+		routeOpts.ReservedPrefixes = f.reservedPrefixes
+		routeOpts.ReservedNamePrefixes = f.cfg.ReservedNamePrefixes
+	}
+	if f.triggers != nil {
+		routeOpts.VulnerabilityNotifier = f.triggers
+	}
+	if f.webhookDeliveries != nil {
+		routeOpts.WebhookDeliveries = f.webhookDeliveries
+		routeOpts.WebhookDeadLetters = f.webhookDeadLetters
+		routeOpts.WebhookReplayer = f.webhooks
+	}
+	if f.semantic != nil {
+		routeOpts.SemanticSearch = f.semantic
+		routeOpts.EmbeddingIndex = f.semantic
+	}
+	routeOpts.MCPTraffic = mcptraffic.NewRecorder(f.cfg.MCPTrafficFrames)
+	if f.pool == nil {
+		return
+	}
+	routeOpts.DeploymentManifests = v1alpha1store.NewDeploymentManifestStore(f.pool, ossSchema())
+	routeOpts.DeploymentNotes = v1alpha1store.NewDeploymentNoteStore(f.pool, ossSchema())
+	routeOpts.DeploymentShares = v1alpha1store.NewDeploymentShareStore(f.pool, ossSchema())
+	routeOpts.DeploymentShareAuditor = auditor
+	routeOpts.PromptEvaluations = v1alpha1store.NewPromptEvaluationStore(f.pool, ossSchema())
+	if f.promptEvaluator != nil {
+		routeOpts.PromptEvaluator = f.promptEvaluator
+	}
+	routeOpts.Readmes = v1alpha1store.NewArtifactReadmeStore(f.pool, ossSchema())
+	routeOpts.ServerTools = v1alpha1store.NewServerToolStore(f.pool, ossSchema())
+	routeOpts.Icons = v1alpha1store.NewArtifactIconStore(f.pool, ossSchema())
+	routeOpts.Usage = f.usage
+	routeOpts.Snapshots = f.snapshotStore
+	routeOpts.SnapshotTaker = f.catalog
+	collector := versionGCCollector(f.cfg, f.stores)
+	routeOpts.VersionGC = collector
+	if f.cfg.VersionGCInterval > 0 {
+		go collector.Run(ctx, f.cfg.VersionGCInterval)
+		slog.Info("version gc enabled", "interval", f.cfg.VersionGCInterval,
+			"keep_versions", f.cfg.VersionGCKeepVersions, "keep_within", f.cfg.VersionGCKeepWithin)
+	}
+	if f.cfg.DeploymentLogShippingEnabled {
+		routeOpts.DeploymentLogStore = v1alpha1store.NewDeploymentLogStore(f.pool, ossSchema())
+	}
+}
+
+// versionGCCollector collects every tagged-artifact store, keeping the
+// versions Agents, Skills and Deployments pin.
+func versionGCCollector(cfg *config.Config, stores map[string]*v1alpha1store.Store) *gc.Collector {
+	collector := &gc.Collector{
+		Stores:    map[string]gc.Store{},
+		Referrers: map[string]gc.Lister{},
+		Policy: gc.Policy{
+			KeepVersions: cfg.VersionGCKeepVersions,
+			KeepWithin:   cfg.VersionGCKeepWithin,
+		},
+	}
+	for kind, store := range stores {
+		if store == nil {
+			continue
+		}
+		collector.Referrers[kind] = store
+		if store.Behavior() == v1alpha1store.TaggedArtifactStore {
+			collector.Stores[kind] = store
+		}
+	}
+	return collector
+}
+
+// catalogSnapshots captures every tagged-artifact store into store.
+func catalogSnapshots(store *v1alpha1store.CatalogSnapshotStore, stores map[string]*v1alpha1store.Store) *snapshots.Catalog {
+	catalog := &snapshots.Catalog{Store: store, Stores: map[string]snapshots.Lister{}}
+	for kind, s := range stores {
+		if s != nil && s.Behavior() == v1alpha1store.TaggedArtifactStore {
+			catalog.Stores[kind] = s
+		}
+	}
+	return catalog
+}
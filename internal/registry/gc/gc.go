@@ -0,0 +1,302 @@
+// Package gc deletes artifact versions the registry no longer needs. Every
+// publish adds a tag row, so an artifact built from CI accumulates
+// versions without bound; a retention Policy decides which of them stay:
+//
+//   - the newest Policy.KeepVersions versions of each artifact,
+//   - every version younger than Policy.KeepWithin,
+//   - the literal "latest" tag and the most recently published version,
+//     whatever the policy, and
+//   - every version a Deployment, Agent or Skill pins, by tag or by a
+//     version constraint.
+//
+// Everything else is deleted through the store, so under
+// DELETED_ARTIFACT_RETENTION a collected version stays restorable until
+// the controller's retention pass purges it. Collector runs on demand,
+// through POST /v0/admin/gc, and on a schedule.
+package gc
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// listPageSize is the store page size used while walking each kind.
+const listPageSize = 200
+
+// Lister lists the rows of one kind. *v1alpha1store.Store satisfies it.
+type Lister interface {
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+}
+
+// Store is a tagged-artifact store GC collects from. *v1alpha1store.Store
+// satisfies it.
+type Store interface {
+	Lister
+	Delete(ctx context.Context, namespace, name, tag string) error
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Policy is the version retention policy. A version is kept when either
+// rule keeps it; with both zero only the versions every policy keeps
+// remain.
+type Policy struct {
+	// KeepVersions keeps the newest KeepVersions versions of each artifact.
+	KeepVersions int
+	// KeepWithin keeps every version published less than KeepWithin ago.
+	KeepWithin time.Duration
+}
+
+// referrers decode the kinds whose refs pin versions.
+var referrers = map[string]func(*v1alpha1.RawObject) (v1alpha1.Object, error){
+	v1alpha1.KindAgent: func(row *v1alpha1.RawObject) (v1alpha1.Object, error) {
+		return v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Agent { return &v1alpha1.Agent{} }, row, v1alpha1.KindAgent)
+	},
+	v1alpha1.KindSkill: func(row *v1alpha1.RawObject) (v1alpha1.Object, error) {
+		return v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Skill { return &v1alpha1.Skill{} }, row, v1alpha1.KindSkill)
+	},
+	v1alpha1.KindDeployment: func(row *v1alpha1.RawObject) (v1alpha1.Object, error) {
+		return v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} }, row, v1alpha1.KindDeployment)
+	},
+}
+
+// Collector applies a Policy.
+type Collector struct {
+	// Stores are the tagged-artifact stores to collect, keyed by kind.
+	Stores map[string]Store
+	// Referrers are the stores of the kinds whose refs keep versions in
+	// use, keyed by kind. Only Agents, Skills and Deployments are read.
+	Referrers map[string]Lister
+	Policy    Policy
+	Now       func() time.Time
+}
+
+// Options tune one Collect run.
+type Options struct {
+	// Namespace limits the run to one namespace. Empty collects every
+	// namespace. Refs are read from every namespace either way.
+	Namespace string
+	// DryRun reports what would be deleted without deleting it.
+	DryRun bool
+}
+
+// Collect deletes the versions c.Policy does not keep and reports them. A
+// failed deletion is reported and skipped; errors reading the stores end
+// the run.
+func (c *Collector) Collect(ctx context.Context, opts Options) (arv0.GCReport, error) {
+	report := arv0.GCReport{
+		DryRun:       opts.DryRun,
+		KeepVersions: c.Policy.KeepVersions,
+		KeepWithin:   c.Policy.KeepWithin.String(),
+		Deleted:      []arv0.GCVersion{},
+	}
+	pins, err := c.pins(ctx)
+	if err != nil {
+		return report, err
+	}
+	now := c.now()
+	for _, kind := range slices.Sorted(maps.Keys(c.Stores)) {
+		store := c.Stores[kind]
+		versions := map[artifactKey][]*v1alpha1.RawObject{}
+		var order []artifactKey
+		err := list(ctx, store, v1alpha1store.ListOpts{Namespace: opts.Namespace}, func(row *v1alpha1.RawObject) error {
+			key := artifactKey{kind: kind, namespace: row.Metadata.NamespaceOrDefault(), name: row.Metadata.Name}
+			if _, ok := versions[key]; !ok {
+				order = append(order, key)
+			}
+			versions[key] = append(versions[key], row)
+			return nil
+		})
+		if err != nil {
+			return report, err
+		}
+		for _, key := range order {
+			for _, row := range c.plan(key, versions[key], pins, now, &report) {
+				version := arv0.GCVersion{
+					Kind:      key.kind,
+					Namespace: key.namespace,
+					Name:      key.name,
+					Tag:       row.Metadata.Tag,
+					CreatedAt: row.Metadata.CreatedAt,
+				}
+				if !opts.DryRun {
+					err := store.Delete(ctx, key.namespace, key.name, row.Metadata.Tag)
+					if errors.Is(err, pkgdb.ErrNotFound) {
+						// Deleted meanwhile, by a user or another replica.
+						continue
+					}
+					if err != nil {
+						if ctx.Err() != nil {
+							return report, ctx.Err()
+						}
+						version.Error = err.Error()
+						report.Failed++
+					}
+				}
+				report.Deleted = append(report.Deleted, version)
+			}
+		}
+	}
+	return report, nil
+}
+
+// plan returns the versions of one artifact the policy does not keep,
+// oldest first, and counts the rest into report.
+func (c *Collector) plan(key artifactKey, rows []*v1alpha1.RawObject, pins *pinSet, now time.Time, report *arv0.GCReport) []*v1alpha1.RawObject {
+	// Newest first; the "latest" tag is not a version of its own.
+	slices.SortFunc(rows, func(a, b *v1alpha1.RawObject) int {
+		return cmp.Or(b.Metadata.CreatedAt.Compare(a.Metadata.CreatedAt), cmp.Compare(b.Metadata.Tag, a.Metadata.Tag))
+	})
+	var collect []*v1alpha1.RawObject
+	rank := 0
+	for _, row := range rows {
+		report.Scanned++
+		if row.Metadata.Tag == v1alpha1store.DefaultTag() {
+			report.Kept++
+			continue
+		}
+		rank++
+		switch {
+		case rank == 1, rank <= c.Policy.KeepVersions:
+		case c.Policy.KeepWithin > 0 && now.Sub(row.Metadata.CreatedAt) < c.Policy.KeepWithin:
+		case pins.pinned(key, row.Metadata.Tag):
+			report.InUse++
+		default:
+			collect = append(collect, row)
+			continue
+		}
+		report.Kept++
+	}
+	slices.Reverse(collect)
+	return collect
+}
+
+// Run collects every interval until ctx is done, logging each run.
+func (c *Collector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := c.Collect(ctx, Options{})
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					slog.Warn("version gc failed", "error", err)
+				}
+				continue
+			}
+			if len(report.Deleted) > 0 || report.Failed > 0 {
+				slog.Info("version gc collected unused versions",
+					"deleted", len(report.Deleted)-report.Failed, "failed", report.Failed,
+					"scanned", report.Scanned, "in_use", report.InUse)
+			}
+		}
+	}
+}
+
+func (c *Collector) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+type artifactKey struct {
+	kind, namespace, name string
+}
+
+// pinSet holds the versions refs keep: exact tags, and the version
+// constraints of refs that pick their tag at deploy time.
+type pinSet struct {
+	tags        map[artifactKey]map[string]bool
+	constraints map[artifactKey][]v1alpha1.TagConstraint
+}
+
+func (p *pinSet) pinned(key artifactKey, tag string) bool {
+	if p.tags[key][tag] {
+		return true
+	}
+	for _, constraint := range p.constraints[key] {
+		if constraint.Matches(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// pins reads the refs of every live Agent, Skill and Deployment.
+func (c *Collector) pins(ctx context.Context) (*pinSet, error) {
+	pins := &pinSet{tags: map[artifactKey]map[string]bool{}, constraints: map[artifactKey][]v1alpha1.TagConstraint{}}
+	record := func(_ context.Context, ref v1alpha1.ResourceRef) error {
+		// Peer refs name another registry's versions, and untagged refs
+		// follow "latest", which is always kept.
+		if ref.Registry != "" || ref.Tag == "" {
+			return nil
+		}
+		key := artifactKey{kind: ref.Kind, namespace: cmp.Or(ref.Namespace, v1alpha1.DefaultNamespace), name: ref.Name}
+		if v1alpha1.IsTagConstraint(ref.Tag) {
+			if constraint, err := v1alpha1.ParseTagConstraint(ref.Tag); err == nil {
+				pins.constraints[key] = append(pins.constraints[key], constraint)
+			}
+			return nil
+		}
+		if pins.tags[key] == nil {
+			pins.tags[key] = map[string]bool{}
+		}
+		pins.tags[key][ref.Tag] = true
+		return nil
+	}
+	for _, kind := range slices.Sorted(maps.Keys(referrers)) {
+		store := c.Referrers[kind]
+		if store == nil {
+			continue
+		}
+		decode := referrers[kind]
+		err := list(ctx, store, v1alpha1store.ListOpts{}, func(row *v1alpha1.RawObject) error {
+			obj, err := decode(row)
+			if err != nil {
+				return fmt.Errorf("read %s %s/%s: %w", kind, row.Metadata.NamespaceOrDefault(), row.Metadata.Name, err)
+			}
+			// Every ResolveRefs reports each ref it checks to the
+			// resolver; recording them never fails the call.
+			_ = v1alpha1.ResolveObjectRefs(ctx, obj, record)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pins, nil
+}
+
+func list(ctx context.Context, store Lister, opts v1alpha1store.ListOpts, fn func(*v1alpha1.RawObject) error) error {
+	opts.Limit = listPageSize
+	for {
+		rows, next, err := store.List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("list: %w", err)
+		}
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		opts.Cursor = next
+	}
+}
@@ -0,0 +1,178 @@
+package gc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/gc"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+var now = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+type fakeStore struct {
+	rows    []*v1alpha1.RawObject
+	failing map[string]bool
+	deleted []string
+}
+
+func (f *fakeStore) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	var out []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		if opts.Namespace == "" || row.Metadata.Namespace == opts.Namespace {
+			out = append(out, row)
+		}
+	}
+	return out, "", nil
+}
+
+func (f *fakeStore) Delete(_ context.Context, namespace, name, tag string) error {
+	if f.failing[tag] {
+		return errors.New("boom")
+	}
+	for _, row := range f.rows {
+		if row.Metadata.Namespace == namespace && row.Metadata.Name == name && row.Metadata.Tag == tag {
+			f.deleted = append(f.deleted, namespace+"/"+name+":"+tag)
+			return nil
+		}
+	}
+	return pkgdb.ErrNotFound
+}
+
+// versions returns one row per tag of default/name, the first published
+// ageDays[0] days ago and so on.
+func versions(name string, tags []string, ageDays []int) []*v1alpha1.RawObject {
+	rows := make([]*v1alpha1.RawObject, len(tags))
+	for i, tag := range tags {
+		rows[i] = &v1alpha1.RawObject{Metadata: v1alpha1.ObjectMeta{
+			Namespace: "default", Name: name, Tag: tag,
+			CreatedAt: now.Add(-time.Duration(ageDays[i]) * 24 * time.Hour),
+		}}
+	}
+	return rows
+}
+
+func agent(t *testing.T, name string, servers ...v1alpha1.ResourceRef) *v1alpha1.RawObject {
+	t.Helper()
+	spec, err := json.Marshal(v1alpha1.AgentSpec{MCPServers: servers})
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: name, Tag: "latest"}, Spec: spec}
+}
+
+func tags(report arv0.GCReport) []string {
+	var out []string
+	for _, v := range report.Deleted {
+		out = append(out, v.Name+":"+v.Tag)
+	}
+	return out
+}
+
+func TestCollect(t *testing.T) {
+	// weather has five versions, 1.0.0 the oldest; "latest" is the oldest
+	// row of all but is never collected.
+	weather := versions("weather",
+		[]string{"latest", "1.0.0", "1.1.0", "1.2.0", "2.0.0", "2.1.0"},
+		[]int{400, 300, 200, 100, 20, 10})
+
+	tests := []struct {
+		name   string
+		policy gc.Policy
+		refs   []*v1alpha1.RawObject
+		want   []string
+		inUse  int
+	}{
+		{
+			name:   "keep newest versions",
+			policy: gc.Policy{KeepVersions: 2},
+			want:   []string{"weather:1.0.0", "weather:1.1.0", "weather:1.2.0"},
+		},
+		{
+			name:   "keep recent versions",
+			policy: gc.Policy{KeepWithin: 150 * 24 * time.Hour},
+			want:   []string{"weather:1.0.0", "weather:1.1.0"},
+		},
+		{
+			name:   "newest version always kept",
+			policy: gc.Policy{},
+			want:   []string{"weather:1.0.0", "weather:1.1.0", "weather:1.2.0", "weather:2.0.0"},
+		},
+		{
+			name:   "pinned by tag",
+			policy: gc.Policy{KeepVersions: 2},
+			refs:   []*v1alpha1.RawObject{agent(t, "reporter", v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "1.1.0"})},
+			want:   []string{"weather:1.0.0", "weather:1.2.0"},
+			inUse:  1,
+		},
+		{
+			name:   "pinned by constraint",
+			policy: gc.Policy{KeepVersions: 2},
+			refs:   []*v1alpha1.RawObject{agent(t, "reporter", v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "^1.1.0"})},
+			want:   []string{"weather:1.0.0"},
+			inUse:  2,
+		},
+		{
+			name:   "peer refs pin nothing here",
+			policy: gc.Policy{KeepVersions: 2},
+			refs:   []*v1alpha1.RawObject{agent(t, "reporter", v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "1.1.0", Registry: "upstream"})},
+			want:   []string{"weather:1.0.0", "weather:1.1.0", "weather:1.2.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servers := &fakeStore{rows: weather}
+			c := &gc.Collector{
+				Stores:    map[string]gc.Store{v1alpha1.KindMCPServer: servers},
+				Referrers: map[string]gc.Lister{v1alpha1.KindAgent: &fakeStore{rows: tt.refs}},
+				Policy:    tt.policy,
+				Now:       func() time.Time { return now },
+			}
+			report, err := c.Collect(context.Background(), gc.Options{})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, tags(report))
+			require.Len(t, servers.deleted, len(tt.want))
+			require.Equal(t, 6, report.Scanned)
+			require.Equal(t, 6-len(tt.want), report.Kept)
+			require.Equal(t, tt.inUse, report.InUse)
+		})
+	}
+}
+
+func TestCollect_DryRun(t *testing.T) {
+	servers := &fakeStore{rows: versions("weather", []string{"1.0.0", "2.0.0"}, []int{20, 10})}
+	c := &gc.Collector{
+		Stores: map[string]gc.Store{v1alpha1.KindMCPServer: servers},
+		Now:    func() time.Time { return now },
+	}
+	report, err := c.Collect(context.Background(), gc.Options{DryRun: true})
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, []string{"weather:1.0.0"}, tags(report))
+	require.Empty(t, servers.deleted)
+}
+
+func TestCollect_NamespaceAndFailures(t *testing.T) {
+	rows := versions("weather", []string{"1.0.0", "1.1.0", "2.0.0"}, []int{30, 20, 10})
+	other := versions("weather", []string{"1.0.0", "2.0.0"}, []int{20, 10})
+	for _, row := range other {
+		row.Metadata.Namespace = "team-a"
+	}
+	servers := &fakeStore{rows: append(rows, other...), failing: map[string]bool{"1.1.0": true}}
+	c := &gc.Collector{
+		Stores: map[string]gc.Store{v1alpha1.KindMCPServer: servers},
+		Now:    func() time.Time { return now },
+	}
+	report, err := c.Collect(context.Background(), gc.Options{Namespace: "default"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"weather:1.0.0", "weather:1.1.0"}, tags(report))
+	require.Equal(t, []string{"default/weather:1.0.0"}, servers.deleted)
+	require.Equal(t, 1, report.Failed)
+	require.Equal(t, "boom", report.Deleted[1].Error)
+}
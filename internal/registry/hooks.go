@@ -0,0 +1,155 @@
+package registry
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/crud"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/controller"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/secrets"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// crudPerKindHooks adapts the AppOptions per-kind authorizer +
+// list-filter maps (which use the public pkg/types signatures) into
+// the internal crud.PerKindHooks struct (which uses the
+// resource.AuthorizeInput type the generic resource handler
+// dispatches on). Field-for-field copy across the two
+// AuthorizeInput-shaped structs.
+//
+// Runtime adapters are handed Runtimes opened by sealer, so they see
+// credentials in plaintext.
+func crudPerKindHooks(options types.AppOptions, sealer *secrets.Sealer) crud.PerKindHooks {
+	hooks := crud.PerKindHooks{}
+	if len(options.Authorizers) > 0 {
+		hooks.Authorizers = make(map[string]func(ctx context.Context, in resource.AuthorizeInput) error, len(options.Authorizers))
+		for kind, fn := range options.Authorizers {
+			f := fn
+			hooks.Authorizers[kind] = func(ctx context.Context, in resource.AuthorizeInput) error {
+				return f(ctx, types.AuthorizeInput{
+					Verb: in.Verb, Kind: in.Kind, Namespace: in.Namespace,
+					Name: in.Name, Tag: in.Tag,
+				})
+			}
+		}
+	}
+	if len(options.ListFilters) > 0 {
+		hooks.ListFilters = make(map[string]func(ctx context.Context, in resource.AuthorizeInput) (string, []any, error), len(options.ListFilters))
+		for kind, fn := range options.ListFilters {
+			f := fn
+			hooks.ListFilters[kind] = func(ctx context.Context, in resource.AuthorizeInput) (string, []any, error) {
+				return f(ctx, types.AuthorizeInput{
+					Verb: in.Verb, Kind: in.Kind, Namespace: in.Namespace,
+					Name: in.Name, Tag: in.Tag,
+				})
+			}
+		}
+	}
+	// PostUpserts / PostDeletes are already (ctx, v1alpha1.Object) →
+	// error so they pass through verbatim — no adapter needed.
+	if len(options.PostUpserts) > 0 {
+		hooks.PostUpserts = make(map[string]func(ctx context.Context, obj v1alpha1.Object) error, len(options.PostUpserts))
+		for kind, fn := range options.PostUpserts {
+			hooks.PostUpserts[kind] = fn
+		}
+	}
+	if len(options.PostDeletes) > 0 {
+		hooks.PostDeletes = make(map[string]func(ctx context.Context, obj v1alpha1.Object) error, len(options.PostDeletes))
+		for kind, fn := range options.PostDeletes {
+			hooks.PostDeletes[kind] = fn
+		}
+	}
+	if len(options.Prepares) > 0 {
+		hooks.Prepares = make(map[string]func(ctx context.Context, obj v1alpha1.Object) error, len(options.Prepares))
+		for kind, fn := range options.Prepares {
+			hooks.Prepares[kind] = fn
+		}
+	}
+	if len(options.InitialFinalizers) > 0 {
+		hooks.InitialFinalizers = make(map[string]func(obj v1alpha1.Object) []string, len(options.InitialFinalizers))
+		maps.Copy(hooks.InitialFinalizers, options.InitialFinalizers)
+	}
+	if hooks.InitialFinalizers == nil {
+		hooks.InitialFinalizers = map[string]func(obj v1alpha1.Object) []string{}
+	}
+	previousDeploymentFinalizers := hooks.InitialFinalizers[v1alpha1.KindDeployment]
+	hooks.InitialFinalizers[v1alpha1.KindDeployment] = func(obj v1alpha1.Object) []string {
+		var finalizers []string
+		if previousDeploymentFinalizers != nil {
+			finalizers = previousDeploymentFinalizers(obj)
+		}
+		if deployment, ok := obj.(*v1alpha1.Deployment); ok && v1alpha1.IsDiscoveredDeployment(deployment) {
+			return finalizers
+		}
+		if slices.Contains(finalizers, controller.DeploymentControllerFinalizer) {
+			return finalizers
+		}
+		return append(finalizers, controller.DeploymentControllerFinalizer)
+	}
+	// RuntimeAdapters map dispatches the KindRuntime PostUpsert /
+	// PostDelete by Spec.Type → adapter. A Runtime whose type has
+	// no registered adapter is a no-op (matches the OSS default
+	// where AppOptions.RuntimeAdapters is empty). When both an
+	// explicit PostUpserts[KindRuntime] and RuntimeAdapters are
+	// present, the dispatcher chains: caller hook first, then the
+	// runtime adapter.
+	if len(options.RuntimeAdapters) > 0 {
+		adapters := make(map[string]types.RuntimeAdapter, len(options.RuntimeAdapters))
+		maps.Copy(adapters, options.RuntimeAdapters)
+		if hooks.PostUpserts == nil {
+			hooks.PostUpserts = map[string]func(ctx context.Context, obj v1alpha1.Object) error{}
+		}
+		if hooks.PostDeletes == nil {
+			hooks.PostDeletes = map[string]func(ctx context.Context, obj v1alpha1.Object) error{}
+		}
+		hooks.PostUpserts[v1alpha1.KindRuntime] = runtimeAdapterDispatcher(
+			hooks.PostUpserts[v1alpha1.KindRuntime], adapters,
+			func(ctx context.Context, r *v1alpha1.Runtime, a types.RuntimeAdapter) error {
+				opened, err := sealer.Open(ctx, r)
+				if err != nil {
+					return err
+				}
+				return a.ApplyRuntime(ctx, opened)
+			},
+		)
+		hooks.PostDeletes[v1alpha1.KindRuntime] = runtimeAdapterDispatcher(
+			hooks.PostDeletes[v1alpha1.KindRuntime], adapters,
+			func(ctx context.Context, r *v1alpha1.Runtime, a types.RuntimeAdapter) error {
+				return a.RemoveRuntime(ctx, r.Metadata.Name)
+			},
+		)
+	}
+	return hooks
+}
+
+// runtimeAdapterDispatcher wraps a (kind=Runtime) hook so the caller
+// hook (if any) runs first, then dispatches to the per-type adapter
+// matching runtime.Spec.Type. Spec.Type is canonicalized at admission
+// time (Runtime.Validate), so the lookup is exact-match against
+// adapter.Type(). A Runtime with no registered adapter is a no-op so
+// the hook stays safe for partial wiring.
+func runtimeAdapterDispatcher(
+	caller func(ctx context.Context, obj v1alpha1.Object) error,
+	adapters map[string]types.RuntimeAdapter,
+	dispatch func(ctx context.Context, r *v1alpha1.Runtime, a types.RuntimeAdapter) error,
+) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if caller != nil {
+			if err := caller(ctx, obj); err != nil {
+				return err
+			}
+		}
+		runtime, ok := obj.(*v1alpha1.Runtime)
+		if !ok || runtime == nil {
+			return nil
+		}
+		adapter, ok := adapters[runtime.Spec.Type]
+		if !ok {
+			return nil
+		}
+		return dispatch(ctx, runtime, adapter)
+	}
+}
@@ -0,0 +1,90 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	mcpregistry "github.com/agentregistry-dev/agentregistry/internal/mcp/registryserver"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/config"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// startMCPServer wires the MCP HTTP bridge on cfg.MCPPort and launches it
+// in a background goroutine. Returns nil when MCP is disabled (no port
+// configured, or v1alpha1 Stores not wired — MCP is a consumer of the
+// v1alpha1 data model and has nothing to serve without it). The returned
+// *http.Server, when non-nil, should be shut down alongside the main
+// server on quit.
+func startMCPServer(
+	cfg *config.Config,
+	stores map[string]*v1alpha1store.Store,
+	authnProvider auth.AuthnProvider,
+) (*http.Server, error) {
+	if cfg.MCPPort <= 0 {
+		return nil, nil
+	}
+	// The MCP listener shares the API listener's TLS/mTLS settings.
+	tlsConfig, err := api.ServerTLSConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("configure MCP server TLS: %w", err)
+	}
+	mcpServer := mcpregistry.NewServer(stores)
+	var handler http.Handler = mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server {
+		return mcpServer
+	}, &mcp.StreamableHTTPOptions{})
+	if authnProvider != nil {
+		handler = mcpAuthnMiddleware(authnProvider)(handler)
+	}
+	addr := ":" + strconv.Itoa(int(cfg.MCPPort))
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig:         tlsConfig,
+	}
+	go func() {
+		slog.Info("MCP HTTP server starting", "address", addr, "tls", tlsConfig != nil)
+		var err error
+		if tlsConfig != nil {
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("failed to start MCP server", "error", err)
+			os.Exit(1)
+		}
+	}()
+	return srv, nil
+}
+
+// mcpAuthnMiddleware uses the AuthnProvider to attach a session to the
+// request context on successful authentication. On auth error or missing
+// session, the request continues with an unauthenticated context — the
+// AuthzProvider downstream decides whether the request is allowed (the
+// OSS default `PublicAuthzProvider` permits read-only access; downstream
+// authz can reject). Failing-open here is intentional so the MCP bridge
+// works for anonymous `list_servers` / `get_server` traffic while still
+// letting authenticated callers pick up privileged operations.
+func mcpAuthnMiddleware(authn auth.AuthnProvider) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			session, err := authn.Authenticate(ctx, r.Header.Get, r.URL.Query())
+			if err == nil && session != nil {
+				ctx = auth.AuthSessionTo(ctx, session)
+				r = r.WithContext(ctx)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
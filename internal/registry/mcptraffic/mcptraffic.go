@@ -0,0 +1,204 @@
+// Package mcptraffic records the MCP JSON-RPC frames the registry relays to
+// Deployments in traffic debug mode, so a failing tool call can be
+// inspected without a packet capture.
+//
+// Frames are redacted before they are kept and live in a fixed-size ring
+// per Deployment, in memory: each registry replica records the traffic it
+// relayed and forgets it on restart.
+package mcptraffic
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/utils"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+// DefaultCapacity is the number of frames kept per Deployment when
+// NewRecorder is given none.
+const DefaultCapacity = 200
+
+// MaxFrameBody caps the redacted body kept for one frame. Larger messages
+// are recorded without a body and marked truncated.
+const MaxFrameBody = 16 * 1024
+
+// Recorder keeps the most recent frames of every Deployment. It is safe
+// for concurrent use.
+type Recorder struct {
+	capacity int
+
+	mu    sync.Mutex
+	rings map[string]*ring
+}
+
+// NewRecorder returns a Recorder keeping capacity frames per Deployment.
+// capacity <= 0 means DefaultCapacity.
+func NewRecorder(capacity int) *Recorder {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Recorder{capacity: capacity, rings: map[string]*ring{}}
+}
+
+// Capacity is the number of frames kept per Deployment.
+func (r *Recorder) Capacity() int {
+	return r.capacity
+}
+
+// Record appends frames to the Deployment's ring, dropping the oldest ones
+// once it is full. Frames without a time are stamped now.
+func (r *Recorder) Record(namespace, name string, frames ...arv0.MCPFrame) {
+	if len(frames) == 0 {
+		return
+	}
+	now := time.Now().UTC()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := namespace + "/" + name
+	rg, ok := r.rings[key]
+	if !ok {
+		rg = &ring{frames: make([]arv0.MCPFrame, 0, r.capacity)}
+		r.rings[key] = rg
+	}
+	for _, f := range frames {
+		if f.Time.IsZero() {
+			f.Time = now
+		}
+		rg.push(f)
+	}
+}
+
+// Frames returns the Deployment's recorded frames, oldest first. limit > 0
+// keeps only the newest limit frames.
+func (r *Recorder) Frames(namespace, name string, limit int) []arv0.MCPFrame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rg, ok := r.rings[namespace+"/"+name]
+	if !ok {
+		return []arv0.MCPFrame{}
+	}
+	out := rg.ordered()
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out
+}
+
+// ring is a fixed-capacity frame buffer; next is where the following frame
+// is written once frames is full.
+type ring struct {
+	frames []arv0.MCPFrame
+	next   int
+}
+
+func (rg *ring) push(f arv0.MCPFrame) {
+	if len(rg.frames) < cap(rg.frames) {
+		rg.frames = append(rg.frames, f)
+		return
+	}
+	rg.frames[rg.next] = f
+	rg.next = (rg.next + 1) % len(rg.frames)
+}
+
+func (rg *ring) ordered() []arv0.MCPFrame {
+	out := make([]arv0.MCPFrame, 0, len(rg.frames))
+	out = append(out, rg.frames[rg.next:]...)
+	return append(out, rg.frames[:rg.next]...)
+}
+
+// Decode turns a JSON-RPC message or batch into redacted frames. A body
+// that is not JSON yields one frame carrying the decode error.
+func Decode(direction string, body []byte) []arv0.MCPFrame {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil
+	}
+	var batch []json.RawMessage
+	if body[0] == '[' {
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return []arv0.MCPFrame{{Direction: direction, Error: "invalid JSON-RPC batch: " + err.Error()}}
+		}
+	} else {
+		batch = []json.RawMessage{body}
+	}
+	frames := make([]arv0.MCPFrame, 0, len(batch))
+	for _, msg := range batch {
+		frames = append(frames, decodeMessage(direction, msg))
+	}
+	return frames
+}
+
+// DecodeEventStream decodes the data of every event in a server-sent event
+// stream, as MCP servers answer requests they stream.
+func DecodeEventStream(direction string, stream []byte) []arv0.MCPFrame {
+	var frames []arv0.MCPFrame
+	var data bytes.Buffer
+	flush := func() {
+		frames = append(frames, Decode(direction, data.Bytes())...)
+		data.Reset()
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(stream))
+	scanner.Buffer(make([]byte, 64*1024), len(stream)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(value, " "))
+		}
+	}
+	flush()
+	return frames
+}
+
+func decodeMessage(direction string, msg json.RawMessage) arv0.MCPFrame {
+	frame := arv0.MCPFrame{Direction: direction}
+	var envelope struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	var tree any
+	if err := json.Unmarshal(msg, &tree); err != nil {
+		frame.Error = "invalid JSON-RPC message: " + err.Error()
+		return frame
+	}
+	if err := json.Unmarshal(msg, &envelope); err == nil {
+		frame.Method = envelope.Method
+		frame.ID = idString(envelope.ID)
+		if envelope.Error != nil {
+			frame.Error = envelope.Error.Message
+		}
+	}
+	redacted, err := json.Marshal(utils.Redact(tree))
+	if err != nil || len(redacted) > MaxFrameBody {
+		frame.Truncated = true
+		return frame
+	}
+	frame.Body = redacted
+	return frame
+}
+
+// idString renders a JSON-RPC id: strings unquoted, numbers as written.
+func idString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,164 @@
+// Package ownership enforces namespace ownership at apply and delete time.
+// A namespace claimed through /v0/namespaces and verified restricts
+// publishing and deleting Agents, MCPServers and Skills in it to the
+// claim's owner and members; registry admins and internal system calls
+// are exempt. Unclaimed namespaces and claims still pending verification
+// restrict nothing, so turning the feature on never locks out existing
+// publishers until someone proves they own the namespace.
+//
+// The Guard is wired in as a Prepare hook and a delete admission, so it
+// runs on the dedicated PUT/DELETE routes, the batch /v0/apply endpoint
+// and dry-runs alike.
+package ownership
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// Verification TXT record naming. A claim on io.github.acme is verified by
+// a TXT record on _agentregistry.acme.github.io whose value is
+// VerificationValuePrefix followed by the claim's token.
+const (
+	VerificationRecordPrefix = "_agentregistry."
+	VerificationValuePrefix  = "agentregistry-verification="
+)
+
+// Kinds returns the kinds whose writes are restricted to namespace members.
+func Kinds() []string {
+	return []string{v1alpha1.KindAgent, v1alpha1.KindMCPServer, v1alpha1.KindSkill}
+}
+
+// VerificationDomain returns the DNS domain whose owner may verify a claim
+// on namespace: its dot-separated labels reversed, so io.github.acme maps
+// to acme.github.io. ok is false for single-label namespaces such as
+// "default", which have no domain to verify against.
+func VerificationDomain(namespace string) (domain string, ok bool) {
+	labels := strings.Split(namespace, ".")
+	if len(labels) < 2 {
+		return "", false
+	}
+	slices.Reverse(labels)
+	return strings.Join(labels, "."), true
+}
+
+// VerificationRecord returns the TXT record name and value that verify a
+// claim on namespace holding token. ok is false when the namespace has no
+// verification domain.
+func VerificationRecord(namespace, token string) (name, value string, ok bool) {
+	domain, ok := VerificationDomain(namespace)
+	if !ok {
+		return "", "", false
+	}
+	return VerificationRecordPrefix + domain, VerificationValuePrefix + token, true
+}
+
+// Store reads namespace claims. *v1alpha1store.NamespaceStore satisfies
+// it.
+type Store interface {
+	Get(ctx context.Context, name string) (*v1alpha1store.Namespace, error)
+}
+
+// NotMemberError reports a write into a verified namespace by a caller
+// who is neither its owner nor a member. It matches pkgdb.ErrForbidden so
+// apply handlers answer 403.
+type NotMemberError struct {
+	Namespace string
+	Subject   string
+}
+
+func (e *NotMemberError) Error() string {
+	if e.Subject == "" {
+		return fmt.Sprintf("namespace %s is owned; only its members may publish in it", e.Namespace)
+	}
+	return fmt.Sprintf("namespace %s is owned; %s is not a member", e.Namespace, e.Subject)
+}
+
+// Is makes errors.Is(err, pkgdb.ErrForbidden) hold.
+func (e *NotMemberError) Is(target error) bool {
+	return target == pkgdb.ErrForbidden
+}
+
+// Guard checks callers against namespace claims.
+type Guard struct {
+	store Store
+	// isAdmin exempts registry admins. Nil exempts nobody.
+	isAdmin func(ctx context.Context) bool
+}
+
+// New constructs a Guard reading claims from store. isAdmin, typically
+// auth.Authorizer.IsRegistryAdmin, exempts registry admins.
+func New(store Store, isAdmin func(ctx context.Context) bool) *Guard {
+	return &Guard{store: store, isAdmin: isAdmin}
+}
+
+// Check returns a *NotMemberError when namespace has a verified claim and
+// the caller on ctx is not one of its members.
+func (g *Guard) Check(ctx context.Context, namespace string) error {
+	if session, ok := auth.AuthSessionFrom(ctx); ok && auth.IsSystemSession(session) {
+		return nil
+	}
+	if g.isAdmin != nil && g.isAdmin(ctx) {
+		return nil
+	}
+	ns, err := g.store.Get(ctx, namespace)
+	switch {
+	case errors.Is(err, pkgdb.ErrNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("look up namespace %s: %w", namespace, err)
+	}
+	if !ns.Verified() {
+		return nil
+	}
+	subject := auth.SubjectFrom(ctx)
+	if ns.IsMember(subject) {
+		return nil
+	}
+	return &NotMemberError{Namespace: namespace, Subject: subject}
+}
+
+// Prepare returns a Prepare hook that runs next, then refuses the write
+// when the object's namespace is owned by someone else. Wire it for each
+// of Kinds().
+func (g *Guard) Prepare(next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		return g.Check(ctx, obj.GetMetadata().NamespaceOrDefault())
+	}
+}
+
+// DeleteAdmission returns a delete admission that refuses deletes of
+// guarded kinds in a namespace owned by someone else and otherwise defers
+// to next, or to resource.ProductionDeleteAdmission when next is nil.
+func (g *Guard) DeleteAdmission(next types.DeleteAdmission) types.DeleteAdmission {
+	if next == nil {
+		next = resource.ProductionDeleteAdmission
+	}
+	return func(ctx context.Context, in types.DeleteAdmissionInput) (types.DeleteAdmissionResult, error) {
+		if slices.Contains(Kinds(), in.Kind) {
+			namespace := in.Namespace
+			if namespace == "" {
+				namespace = v1alpha1.DefaultNamespace
+			}
+			if err := g.Check(ctx, namespace); err != nil {
+				return types.DeleteAdmissionResult{}, err
+			}
+		}
+		return next(ctx, in)
+	}
+}
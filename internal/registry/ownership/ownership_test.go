@@ -0,0 +1,114 @@
+package ownership_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/ownership"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+type claims map[string]*v1alpha1store.Namespace
+
+func (c claims) Get(_ context.Context, name string) (*v1alpha1store.Namespace, error) {
+	if ns, ok := c[name]; ok {
+		return ns, nil
+	}
+	return nil, pkgdb.ErrNotFound
+}
+
+type session string
+
+func (s session) Principal() auth.Principal { return auth.Principal{Subject: string(s)} }
+
+func as(subject string) context.Context {
+	return auth.AuthSessionTo(context.Background(), session(subject))
+}
+
+func testClaims() claims {
+	verified := time.Now()
+	return claims{
+		"io.github.acme":    {Name: "io.github.acme", Owner: "alice", Members: []string{"bob"}, VerifiedAt: &verified},
+		"io.github.pending": {Name: "io.github.pending", Owner: "alice"},
+	}
+}
+
+func agent(namespace string) *v1alpha1.Agent {
+	return &v1alpha1.Agent{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindAgent},
+		Metadata: v1alpha1.ObjectMeta{Namespace: namespace, Name: "bot"},
+	}
+}
+
+func TestVerificationRecord(t *testing.T) {
+	name, value, ok := ownership.VerificationRecord("io.github.acme", "tok")
+	require.True(t, ok)
+	require.Equal(t, "_agentregistry.acme.github.io", name)
+	require.Equal(t, "agentregistry-verification=tok", value)
+
+	_, _, ok = ownership.VerificationRecord("default", "tok")
+	require.False(t, ok)
+}
+
+func TestCheck(t *testing.T) {
+	guard := ownership.New(testClaims(), nil)
+
+	require.NoError(t, guard.Check(as("alice"), "io.github.acme"))
+	require.NoError(t, guard.Check(as("bob"), "io.github.acme"))
+	err := guard.Check(as("mallory"), "io.github.acme")
+	require.ErrorIs(t, err, pkgdb.ErrForbidden)
+	require.EqualError(t, err, "namespace io.github.acme is owned; mallory is not a member")
+	require.ErrorIs(t, guard.Check(context.Background(), "io.github.acme"), pkgdb.ErrForbidden)
+
+	// Pending claims and unclaimed namespaces stay open.
+	require.NoError(t, guard.Check(as("mallory"), "io.github.pending"))
+	require.NoError(t, guard.Check(as("mallory"), "default"))
+
+	// System calls and registry admins are exempt.
+	require.NoError(t, guard.Check(auth.WithSystemContext(context.Background()), "io.github.acme"))
+	admin := ownership.New(testClaims(), func(context.Context) bool { return true })
+	require.NoError(t, admin.Check(as("mallory"), "io.github.acme"))
+}
+
+func TestPrepare(t *testing.T) {
+	guard := ownership.New(testClaims(), nil)
+	prepare := guard.Prepare(nil)
+
+	require.NoError(t, prepare(as("bob"), agent("io.github.acme")))
+	require.ErrorIs(t, prepare(as("mallory"), agent("io.github.acme")), pkgdb.ErrForbidden)
+
+	failing := guard.Prepare(func(context.Context, v1alpha1.Object) error {
+		return errors.New("boom")
+	})
+	require.EqualError(t, failing(as("bob"), agent("io.github.acme")), "boom")
+}
+
+func TestDeleteAdmission(t *testing.T) {
+	guard := ownership.New(testClaims(), nil)
+	var deleted []string
+	admit := guard.DeleteAdmission(func(_ context.Context, in types.DeleteAdmissionInput) (types.DeleteAdmissionResult, error) {
+		deleted = append(deleted, in.Kind)
+		return types.DeleteAdmissionResult{Status: arv0.ApplyStatusDeleted}, nil
+	})
+
+	_, err := admit(as("mallory"), types.DeleteAdmissionInput{Kind: v1alpha1.KindSkill, Namespace: "io.github.acme", Name: "s"})
+	require.ErrorIs(t, err, pkgdb.ErrForbidden)
+
+	res, err := admit(as("alice"), types.DeleteAdmissionInput{Kind: v1alpha1.KindSkill, Namespace: "io.github.acme", Name: "s"})
+	require.NoError(t, err)
+	require.Equal(t, arv0.ApplyStatusDeleted, res.Status)
+
+	// Unguarded kinds pass through.
+	_, err = admit(as("mallory"), types.DeleteAdmissionInput{Kind: v1alpha1.KindRuntime, Namespace: "io.github.acme", Name: "k8s"})
+	require.NoError(t, err)
+	require.Equal(t, []string{v1alpha1.KindSkill, v1alpha1.KindRuntime}, deleted)
+}
@@ -0,0 +1,183 @@
+// Package peers resolves ResourceRefs that name a peer registry
+// (ResourceRef.Registry) against that registry's /v0 API, so an Agent can
+// compose MCPServers published elsewhere without importing them locally.
+//
+// Peers are configured by name (AGENT_REGISTRY_PEER_REGISTRIES). Apply-time
+// ref resolution only checks that the named peer is configured — a peer
+// being briefly unreachable must not block writes. The referenced object is
+// fetched at translation time through the Getter, with results cached for
+// the configured TTL. When a refresh fails the last good copy keeps serving,
+// so a running deployment doesn't flap with the peer's availability.
+package peers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agentregistry-dev/agentregistry/internal/client"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// ErrUnknownPeer is returned for refs naming a registry that isn't configured.
+var ErrUnknownPeer = errors.New("unknown peer registry")
+
+// Registry dispatches peer refs to their configured registries. A nil
+// *Registry has no peers: its Getter and Resolver reject every peer ref.
+type Registry struct {
+	clients map[string]*client.Client
+	ttl     time.Duration
+	// now is swapped in tests.
+	now func() time.Time
+
+	mu    sync.Mutex
+	cache map[cacheKey]cacheEntry
+}
+
+type cacheKey struct {
+	registry, kind, namespace, name, tag string
+}
+
+type cacheEntry struct {
+	obj     v1alpha1.Object
+	fetched time.Time
+}
+
+// New returns a Registry for peers, a map of peer name to base URL. The
+// base URL may include the /v0 prefix. ttl is how long a fetched object is
+// served before the peer is asked again.
+func New(peers map[string]string, ttl time.Duration) (*Registry, error) {
+	if len(peers) > 0 && ttl <= 0 {
+		return nil, fmt.Errorf("peer cache TTL must be positive, got %v", ttl)
+	}
+	r := &Registry{
+		clients: make(map[string]*client.Client, len(peers)),
+		ttl:     ttl,
+		now:     time.Now,
+		cache:   map[cacheKey]cacheEntry{},
+	}
+	for name, base := range peers {
+		if err := v1alpha1.ValidatePeerRegistryName(name); err != nil {
+			return nil, fmt.Errorf("peer registry %q: %w", name, err)
+		}
+		u, err := url.Parse(base)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("peer registry %q must be an absolute http(s) URL, got %q", name, base)
+		}
+		r.clients[name] = client.NewClient(base, "")
+	}
+	return r, nil
+}
+
+// Names returns the configured peer names, sorted.
+func (r *Registry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolver wraps local so refs naming a peer registry resolve when the peer
+// is configured; everything else falls through to local.
+func (r *Registry) Resolver(local v1alpha1.ResolverFunc) v1alpha1.ResolverFunc {
+	return func(ctx context.Context, ref v1alpha1.ResourceRef) error {
+		if ref.Registry == "" {
+			return local(ctx, ref)
+		}
+		if _, err := r.client(ref.Registry); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+// Getter wraps local so refs naming a peer registry are fetched from that
+// peer; everything else falls through to local.
+func (r *Registry) Getter(local v1alpha1.GetterFunc) v1alpha1.GetterFunc {
+	return func(ctx context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		if ref.Registry == "" {
+			return local(ctx, ref)
+		}
+		return r.get(ctx, ref)
+	}
+}
+
+func (r *Registry) client(name string) (*client.Client, error) {
+	if r != nil {
+		if c, ok := r.clients[name]; ok {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %w %q", v1alpha1.ErrInvalidRef, ErrUnknownPeer, name)
+}
+
+func (r *Registry) get(ctx context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+	c, err := r.client(ref.Registry)
+	if err != nil {
+		return nil, err
+	}
+	key := cacheKey{ref.Registry, ref.Kind, ref.Namespace, ref.Name, ref.Tag}
+
+	r.mu.Lock()
+	cached, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && r.now().Sub(cached.fetched) < r.ttl {
+		return cached.obj, nil
+	}
+
+	obj, err := fetch(ctx, c, ref)
+	if err != nil {
+		// A missing object is authoritative; anything else is the peer
+		// being unhealthy, which the last good copy rides out.
+		if ok && !errors.Is(err, v1alpha1.ErrDanglingRef) {
+			return cached.obj, nil
+		}
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[key] = cacheEntry{obj: obj, fetched: r.now()}
+	r.mu.Unlock()
+	return obj, nil
+}
+
+func fetch(ctx context.Context, c *client.Client, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+	var (
+		raw *v1alpha1.RawObject
+		err error
+	)
+	if ref.Tag == "" {
+		raw, err = c.GetLatest(ctx, ref.Kind, ref.Namespace, ref.Name)
+	} else {
+		raw, err = c.Get(ctx, ref.Kind, ref.Namespace, ref.Name, ref.Tag)
+	}
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			return nil, v1alpha1.ErrDanglingRef
+		}
+		return nil, fmt.Errorf("peer registry %q: %w", ref.Registry, err)
+	}
+	_, newObj, ok := v1alpha1.Default.Lookup(ref.Kind)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown kind %q in scheme", v1alpha1.ErrInvalidRef, ref.Kind)
+	}
+	if _, ok := newObj().(v1alpha1.Object); !ok {
+		return nil, fmt.Errorf("scheme constructor for %q did not return v1alpha1.Object", ref.Kind)
+	}
+	obj, err := v1alpha1.EnvelopeFromRaw(func() v1alpha1.Object {
+		return newObj().(v1alpha1.Object)
+	}, raw, ref.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("peer registry %q: decode %s: %w", ref.Registry, ref.Kind, err)
+	}
+	return obj, nil
+}
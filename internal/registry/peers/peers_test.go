@@ -0,0 +1,152 @@
+package peers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// fakePeer serves one MCPServer at /v0/mcpservers/weather/1.0.0 and 404s
+// everything else. Setting down makes every request fail with a 503.
+type fakePeer struct {
+	hits atomic.Int32
+	down atomic.Bool
+}
+
+func (p *fakePeer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.hits.Add(1)
+	if p.down.Load() {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if r.URL.Path != "/v0/mcpservers/weather/1.0.0" {
+		http.NotFound(w, r)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(v1alpha1.RawObject{
+		Metadata: v1alpha1.ObjectMeta{Namespace: v1alpha1.DefaultNamespace, Name: "weather", Tag: "1.0.0"},
+		Spec:     json.RawMessage(`{"title":"Weather"}`),
+	})
+}
+
+func newTestRegistry(t *testing.T, peer *fakePeer) (*Registry, *time.Time) {
+	t.Helper()
+	srv := httptest.NewServer(peer)
+	t.Cleanup(srv.Close)
+	r, err := New(map[string]string{"upstream-public": srv.URL}, time.Minute)
+	require.NoError(t, err)
+	now := time.Unix(0, 0)
+	r.now = func() time.Time { return now }
+	return r, &now
+}
+
+func peerRef(name string) v1alpha1.ResourceRef {
+	return v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: name, Tag: "1.0.0", Registry: "upstream-public"}
+}
+
+func TestGetterFetchesAndCachesPeerRefs(t *testing.T) {
+	peer := &fakePeer{}
+	r, now := newTestRegistry(t, peer)
+	get := r.Getter(func(context.Context, v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		t.Fatal("peer ref fell through to the local getter")
+		return nil, nil
+	})
+
+	obj, err := get(context.Background(), peerRef("weather"))
+	require.NoError(t, err)
+	server, ok := obj.(*v1alpha1.MCPServer)
+	require.True(t, ok)
+	require.Equal(t, "Weather", server.Spec.Title)
+	require.Equal(t, v1alpha1.KindMCPServer, server.Kind)
+
+	_, err = get(context.Background(), peerRef("weather"))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), peer.hits.Load(), "second fetch within the TTL should be cached")
+
+	*now = now.Add(2 * time.Minute)
+	_, err = get(context.Background(), peerRef("weather"))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), peer.hits.Load(), "expired entry should be refetched")
+}
+
+func TestGetterServesStaleWhenPeerIsDown(t *testing.T) {
+	peer := &fakePeer{}
+	r, now := newTestRegistry(t, peer)
+	get := r.Getter(nil)
+
+	_, err := get(context.Background(), peerRef("weather"))
+	require.NoError(t, err)
+
+	peer.down.Store(true)
+	*now = now.Add(2 * time.Minute)
+	obj, err := get(context.Background(), peerRef("weather"))
+	require.NoError(t, err)
+	require.Equal(t, "weather", obj.GetMetadata().Name)
+
+	_, err = get(context.Background(), peerRef("uncached"))
+	require.Error(t, err)
+	require.False(t, errors.Is(err, v1alpha1.ErrDanglingRef))
+}
+
+func TestGetterMapsNotFoundToDanglingRef(t *testing.T) {
+	r, _ := newTestRegistry(t, &fakePeer{})
+
+	_, err := r.Getter(nil)(context.Background(), peerRef("missing"))
+	require.ErrorIs(t, err, v1alpha1.ErrDanglingRef)
+}
+
+func TestGetterAndResolverRejectUnknownPeers(t *testing.T) {
+	r, _ := newTestRegistry(t, &fakePeer{})
+	ref := peerRef("weather")
+	ref.Registry = "elsewhere"
+
+	_, err := r.Getter(nil)(context.Background(), ref)
+	require.ErrorIs(t, err, ErrUnknownPeer)
+	require.ErrorIs(t, err, v1alpha1.ErrInvalidRef)
+
+	err = r.Resolver(nil)(context.Background(), ref)
+	require.ErrorIs(t, err, ErrUnknownPeer)
+
+	var none *Registry
+	err = none.Resolver(nil)(context.Background(), peerRef("weather"))
+	require.ErrorIs(t, err, ErrUnknownPeer)
+}
+
+func TestResolverAcceptsConfiguredPeersWithoutFetching(t *testing.T) {
+	peer := &fakePeer{}
+	r, _ := newTestRegistry(t, peer)
+	var localCalls int
+	resolve := r.Resolver(func(context.Context, v1alpha1.ResourceRef) error {
+		localCalls++
+		return nil
+	})
+
+	require.NoError(t, resolve(context.Background(), peerRef("missing")))
+	require.Zero(t, peer.hits.Load())
+
+	require.NoError(t, resolve(context.Background(), v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "local"}))
+	require.Equal(t, 1, localCalls)
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	_, err := New(map[string]string{"upstream": "ftp://example.com"}, time.Minute)
+	require.Error(t, err)
+
+	_, err = New(map[string]string{"Bad_Name": "https://example.com"}, time.Minute)
+	require.Error(t, err)
+
+	_, err = New(map[string]string{"upstream": "https://example.com"}, 0)
+	require.Error(t, err)
+
+	_, err = New(nil, 0)
+	require.NoError(t, err, "no peers needs no TTL")
+}
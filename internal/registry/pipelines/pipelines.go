@@ -0,0 +1,329 @@
+// Package pipelines triggers external CI/CD pipelines when a new version is
+// published. It plugs into the store's Auditor seam: every new tag row
+// (types.Auditor.ResourceTagCreated, fired after the write commits) is
+// matched against the configured triggers, and each match is dispatched
+// asynchronously as a GitHub Actions workflow_dispatch, a GitLab pipeline
+// trigger, or a generic JSON webhook carrying the artifact context.
+//
+// Triggers are loaded from a YAML file (AGENT_REGISTRY_PUBLISH_TRIGGERS_FILE):
+//
+//	registryURL: https://registry.example.com
+//	triggers:
+//	  - name: mcp-smoke-tests
+//	    type: github
+//	    kinds: [MCPServer]
+//	    repository: acme/registry-ci
+//	    workflow: smoke.yml
+//	    ref: main
+//	    tokenEnv: GITHUB_TOKEN
+//
+// Secrets never live in the file: tokenEnv names the environment variable
+// holding the GitHub token, the GitLab trigger token, or the webhook HMAC
+// secret.
+//
+// The same triggers can subscribe to vulnerability events (events:
+// [vulnerability]), fired when a scanner marks an MCPServer or Skill version
+// vulnerable. Each namespace with newly flagged Agents or Deployments gets
+// one event, matched against the trigger's namespaces filter, so a trigger
+// scoped to a namespace reaches that namespace's owners.
+package pipelines
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// Trigger types.
+const (
+	TypeGitHub  = "github"
+	TypeGitLab  = "gitlab"
+	TypeWebhook = "webhook"
+)
+
+const (
+	defaultGitHubAPIURL = "https://api.github.com"
+	defaultGitLabURL    = "https://gitlab.com"
+
+	// dispatchTimeout bounds one delivery attempt; maxAttempts retries
+	// network errors and 5xx/429 responses with linear backoff.
+	dispatchTimeout = 30 * time.Second
+	maxAttempts     = 3
+)
+
+// Config is the on-disk trigger file.
+type Config struct {
+	// RegistryURL is the externally reachable registry base URL. When set,
+	// events carry an absolute link to the published version.
+	RegistryURL string    `json:"registryURL,omitempty"`
+	Triggers    []Trigger `json:"triggers"`
+}
+
+// Trigger is one pipeline to start on publish.
+type Trigger struct {
+	Name string `json:"name"`
+	// Type is github, gitlab, or webhook.
+	Type string `json:"type"`
+	// Events lists the event types the trigger fires on: publish (the
+	// default) and vulnerability.
+	Events []string `json:"events,omitempty"`
+	// Kinds and Namespaces narrow which artifacts fire the trigger. Empty
+	// matches everything. Vulnerability events match Namespaces against the
+	// affected namespace.
+	Kinds      []string `json:"kinds,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	// URL is the webhook target, or the API base for github (default
+	// https://api.github.com) and gitlab (default https://gitlab.com).
+	URL string `json:"url,omitempty"`
+	// Repository ("owner/repo") and Workflow (file name or ID) select the
+	// GitHub Actions workflow.
+	Repository string `json:"repository,omitempty"`
+	Workflow   string `json:"workflow,omitempty"`
+	// Project is the GitLab project ID or full path.
+	Project string `json:"project,omitempty"`
+	// Ref is the branch or tag the pipeline runs on (github, gitlab).
+	Ref string `json:"ref,omitempty"`
+	// TokenEnv names the env var holding the credential: GitHub token,
+	// GitLab trigger token, or webhook HMAC secret (optional for webhooks).
+	TokenEnv string `json:"tokenEnv,omitempty"`
+
+	token string
+}
+
+// Event is the artifact context delivered to every trigger.
+type Event struct {
+	Event     string    `json:"event"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Tag       string    `json:"tag"`
+	Path      string    `json:"path"`
+	URL       string    `json:"url,omitempty"`
+	Subject   string    `json:"subject,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Advisory, AffectedNamespace and Affected are set on vulnerability
+	// events. Affected lists the newly flagged objects in AffectedNamespace
+	// as "Agent/name@tag" and "Deployment/name".
+	Advisory          string   `json:"advisory,omitempty"`
+	AffectedNamespace string   `json:"affectedNamespace,omitempty"`
+	Affected          []string `json:"affected,omitempty"`
+}
+
+// Event types.
+const (
+	// EventPublish: a new tag was published.
+	EventPublish = "publish"
+	// EventVulnerability: a version was marked vulnerable and Agents or
+	// Deployments that reach it were flagged.
+	EventVulnerability = "vulnerability"
+)
+
+// LoadFile reads and validates a trigger file, resolving each trigger's
+// token from the environment.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read publish triggers %s: %w", path, err)
+	}
+	var cfg Config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse publish triggers %s: %w", path, err)
+	}
+	for i := range cfg.Triggers {
+		if err := cfg.Triggers[i].init(); err != nil {
+			return nil, fmt.Errorf("publish trigger %d (%q): %w", i, cfg.Triggers[i].Name, err)
+		}
+	}
+	return &cfg, nil
+}
+
+func (t *Trigger) init() error {
+	if len(t.Events) == 0 {
+		t.Events = []string{EventPublish}
+	}
+	for _, ev := range t.Events {
+		if ev != EventPublish && ev != EventVulnerability {
+			return fmt.Errorf("unknown event %q (want publish or vulnerability)", ev)
+		}
+	}
+	if t.TokenEnv != "" {
+		t.token = os.Getenv(t.TokenEnv)
+		if t.token == "" {
+			return fmt.Errorf("env %s is empty", t.TokenEnv)
+		}
+	}
+	switch t.Type {
+	case TypeGitHub:
+		if t.Repository == "" || t.Workflow == "" || t.Ref == "" {
+			return errors.New("github triggers require repository, workflow and ref")
+		}
+		// workflow_dispatch inputs are declared by the workflow; there is no
+		// room for the advisory without breaking existing publish workflows.
+		if slices.Contains(t.Events, EventVulnerability) {
+			return errors.New("github triggers support only publish events; use a webhook or gitlab trigger")
+		}
+		if t.token == "" {
+			return errors.New("github triggers require tokenEnv")
+		}
+		if t.URL == "" {
+			t.URL = defaultGitHubAPIURL
+		}
+	case TypeGitLab:
+		if t.Project == "" || t.Ref == "" {
+			return errors.New("gitlab triggers require project and ref")
+		}
+		if t.token == "" {
+			return errors.New("gitlab triggers require tokenEnv")
+		}
+		if t.URL == "" {
+			t.URL = defaultGitLabURL
+		}
+	case TypeWebhook:
+		if t.URL == "" {
+			return errors.New("webhook triggers require url")
+		}
+	default:
+		return fmt.Errorf("unknown type %q (want github, gitlab or webhook)", t.Type)
+	}
+	if _, err := url.Parse(t.URL); err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	return nil
+}
+
+func (t *Trigger) matches(ev Event) bool {
+	namespace := cmp.Or(ev.AffectedNamespace, ev.Namespace)
+	return slices.Contains(t.Events, ev.Event) &&
+		(len(t.Kinds) == 0 || slices.Contains(t.Kinds, ev.Kind)) &&
+		(len(t.Namespaces) == 0 || slices.Contains(t.Namespaces, namespace))
+}
+
+// Dispatcher is a types.Auditor that fires matching triggers on every
+// published tag. Deliveries run in the background so publishing never waits
+// on (or fails because of) an external CI system; failures are logged.
+type Dispatcher struct {
+	cfg    Config
+	client *http.Client
+	now    func() time.Time
+	wg     sync.WaitGroup
+}
+
+var _ types.Auditor = (*Dispatcher)(nil)
+
+// NewDispatcher builds a Dispatcher over a loaded Config. client carries
+// the outbound transport (proxy, custom CAs).
+func NewDispatcher(cfg Config, client *http.Client) *Dispatcher {
+	return &Dispatcher{cfg: cfg, client: client, now: time.Now}
+}
+
+// ResourceTagCreated implements types.Auditor.
+func (d *Dispatcher) ResourceTagCreated(ctx context.Context, kind, namespace, name, tag string) {
+	d.dispatch(ctx, d.event(ctx, EventPublish, kind, namespace, name, tag))
+}
+
+// VulnerabilityFlagged implements security.Notifier.
+func (d *Dispatcher) VulnerabilityFlagged(ctx context.Context, artifact v1alpha1.ResourceRef, advisory, namespace string, affected []string) {
+	ev := d.event(ctx, EventVulnerability, artifact.Kind, artifact.Namespace, artifact.Name, artifact.Tag)
+	ev.Advisory = advisory
+	ev.AffectedNamespace = namespace
+	ev.Affected = affected
+	d.dispatch(ctx, ev)
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, ev Event) {
+	// Detach from the request: it ends as soon as the write responds.
+	ctx = context.WithoutCancel(ctx)
+	for i := range d.cfg.Triggers {
+		t := &d.cfg.Triggers[i]
+		if !t.matches(ev) {
+			continue
+		}
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			if err := d.deliver(ctx, t, ev); err != nil {
+				slog.Error("pipeline trigger failed", "trigger", t.Name, "type", t.Type, "event", ev.Event,
+					"kind", ev.Kind, "namespace", ev.Namespace, "name", ev.Name, "tag", ev.Tag, "error", err)
+				return
+			}
+			slog.Info("pipeline trigger fired", "trigger", t.Name, "type", t.Type, "event", ev.Event,
+				"kind", ev.Kind, "namespace", ev.Namespace, "name", ev.Name, "tag", ev.Tag)
+		}()
+	}
+}
+
+// Wait blocks until in-flight deliveries finish.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) event(ctx context.Context, event, kind, namespace, name, tag string) Event {
+	path := fmt.Sprintf("/v0/%s/%s/%s?namespace=%s",
+		v1alpha1.PluralFor(kind), url.PathEscape(name), url.PathEscape(tag), url.QueryEscape(namespace))
+	ev := Event{
+		Event:     event,
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Tag:       tag,
+		Path:      path,
+		Subject:   auth.SubjectFrom(ctx),
+		Timestamp: d.now().UTC(),
+	}
+	if d.cfg.RegistryURL != "" {
+		ev.URL = d.cfg.RegistryURL + path
+	}
+	return ev
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, t *Trigger, ev Event) error {
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * time.Second)
+		}
+		retry, err := d.attempt(ctx, t, ev)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, t *Trigger, ev Event) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, dispatchTimeout)
+	defer cancel()
+	req, err := newRequest(ctx, t, ev)
+	if err != nil {
+		return false, err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("%s %s: unexpected status %s", req.Method, req.URL.Redacted(), resp.Status)
+}
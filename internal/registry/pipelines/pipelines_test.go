@@ -0,0 +1,193 @@
+package pipelines_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/pipelines"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+type captured struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func newServer(t *testing.T, status int) (*httptest.Server, func() []captured) {
+	t.Helper()
+	var (
+		mu   sync.Mutex
+		reqs []captured
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		reqs = append(reqs, captured{path: r.URL.EscapedPath(), header: r.Header.Clone(), body: body})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []captured {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]captured(nil), reqs...)
+	}
+}
+
+func loadConfig(t *testing.T, body string) *pipelines.Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "triggers.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	cfg, err := pipelines.LoadFile(path)
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestDispatcher_Targets(t *testing.T) {
+	srv, requests := newServer(t, http.StatusNoContent)
+	t.Setenv("TEST_CI_TOKEN", "s3cret")
+	cfg := loadConfig(t, `
+registryURL: https://registry.example.com
+triggers:
+  - name: gh
+    type: github
+    url: `+srv.URL+`
+    repository: acme/ci
+    workflow: smoke.yml
+    ref: main
+    tokenEnv: TEST_CI_TOKEN
+  - name: gl
+    type: gitlab
+    url: `+srv.URL+`
+    project: acme/ci
+    ref: main
+    tokenEnv: TEST_CI_TOKEN
+  - name: hook
+    type: webhook
+    url: `+srv.URL+`/hook
+    tokenEnv: TEST_CI_TOKEN
+  - name: agents-only
+    type: webhook
+    kinds: [Agent]
+    url: `+srv.URL+`/agents
+`)
+
+	d := pipelines.NewDispatcher(*cfg, srv.Client())
+	d.ResourceTagCreated(context.Background(), "MCPServer", "default", "acme/weather", "1.0.0")
+	d.Wait()
+
+	byPath := map[string]captured{}
+	for _, r := range requests() {
+		byPath[r.path] = r
+	}
+	require.Len(t, byPath, 3, "the Agent-only trigger must not fire")
+
+	gh, ok := byPath["/repos/acme/ci/actions/workflows/smoke.yml/dispatches"]
+	require.True(t, ok)
+	require.Equal(t, "Bearer s3cret", gh.header.Get("Authorization"))
+	var ghBody struct {
+		Ref    string            `json:"ref"`
+		Inputs map[string]string `json:"inputs"`
+	}
+	require.NoError(t, json.Unmarshal(gh.body, &ghBody))
+	require.Equal(t, "main", ghBody.Ref)
+	require.Equal(t, map[string]string{"kind": "MCPServer", "namespace": "default", "name": "acme/weather", "tag": "1.0.0"}, ghBody.Inputs)
+
+	gl, ok := byPath["/api/v4/projects/acme%2Fci/trigger/pipeline"]
+	require.True(t, ok)
+	form, err := url.ParseQuery(string(gl.body))
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", form.Get("token"))
+	require.Equal(t, "acme/weather", form.Get("variables[AR_NAME]"))
+	require.Equal(t, "https://registry.example.com/v0/mcpservers/acme%2Fweather/1.0.0?namespace=default", form.Get("variables[AR_URL]"))
+
+	hook, ok := byPath["/hook"]
+	require.True(t, ok)
+	require.Equal(t, pipelines.EventPublish, hook.header.Get(pipelines.HeaderEvent))
+	require.Equal(t, pipelines.Sign([]byte("s3cret"), hook.body), hook.header.Get(pipelines.HeaderSignature))
+	var ev pipelines.Event
+	require.NoError(t, json.Unmarshal(hook.body, &ev))
+	require.Equal(t, "1.0.0", ev.Tag)
+	require.Equal(t, "/v0/mcpservers/acme%2Fweather/1.0.0?namespace=default", ev.Path)
+}
+
+func TestDispatcher_RetriesServerErrors(t *testing.T) {
+	srv, requests := newServer(t, http.StatusBadGateway)
+	cfg := loadConfig(t, "triggers:\n  - name: hook\n    type: webhook\n    url: "+srv.URL+"\n")
+
+	d := pipelines.NewDispatcher(*cfg, srv.Client())
+	d.ResourceTagCreated(context.Background(), "Skill", "default", "lint", "v1")
+	d.Wait()
+	require.Len(t, requests(), 3)
+}
+
+func TestDispatcher_Vulnerability(t *testing.T) {
+	srv, requests := newServer(t, http.StatusNoContent)
+	cfg := loadConfig(t, `
+triggers:
+  - name: publish-only
+    type: webhook
+    url: `+srv.URL+`/publish
+  - name: team-a-security
+    type: webhook
+    events: [vulnerability]
+    namespaces: [team-a]
+    url: `+srv.URL+`/team-a
+  - name: team-b-security
+    type: webhook
+    events: [vulnerability]
+    namespaces: [team-b]
+    url: `+srv.URL+`/team-b
+`)
+
+	d := pipelines.NewDispatcher(*cfg, srv.Client())
+	artifact := v1alpha1.ResourceRef{Kind: v1alpha1.KindSkill, Namespace: "default", Name: "summarize", Tag: "1.2.0"}
+	d.VulnerabilityFlagged(context.Background(), artifact, "CVE-2026-1234", "team-a", []string{"Agent/reporter@1.0.0", "Deployment/reports"})
+	d.Wait()
+
+	reqs := requests()
+	require.Len(t, reqs, 1, "only the trigger subscribed to the affected namespace fires")
+	require.Equal(t, "/team-a", reqs[0].path)
+	require.Equal(t, pipelines.EventVulnerability, reqs[0].header.Get(pipelines.HeaderEvent))
+	var ev pipelines.Event
+	require.NoError(t, json.Unmarshal(reqs[0].body, &ev))
+	require.Equal(t, "default", ev.Namespace)
+	require.Equal(t, "team-a", ev.AffectedNamespace)
+	require.Equal(t, "CVE-2026-1234", ev.Advisory)
+	require.Equal(t, []string{"Agent/reporter@1.0.0", "Deployment/reports"}, ev.Affected)
+}
+
+func TestLoadFile_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "unknown type", body: "triggers:\n  - type: jenkins\n", wantErr: "unknown type"},
+		{name: "github missing workflow", body: "triggers:\n  - type: github\n    repository: a/b\n    ref: main\n", wantErr: "require repository, workflow and ref"},
+		{name: "gitlab missing token", body: "triggers:\n  - type: gitlab\n    project: a/b\n    ref: main\n", wantErr: "require tokenEnv"},
+		{name: "unset token env", body: "triggers:\n  - type: webhook\n    url: http://x\n    tokenEnv: TEST_UNSET_TOKEN\n", wantErr: "TEST_UNSET_TOKEN is empty"},
+		{name: "unknown event", body: "triggers:\n  - type: webhook\n    url: http://x\n    events: [delete]\n", wantErr: "unknown event"},
+		{name: "github vulnerability", body: "triggers:\n  - type: github\n    repository: a/b\n    workflow: w.yml\n    ref: main\n    events: [vulnerability]\n", wantErr: "only publish events"},
+		{name: "unknown field", body: "triggers:\n  - type: webhook\n    url: http://x\n    secret: inline\n", wantErr: "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "triggers.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.body), 0o600))
+			_, err := pipelines.LoadFile(path)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package pipelines
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Webhook headers. The signature is an HMAC-SHA256 of the raw body keyed by
+// the trigger's secret, formatted "sha256=<hex>" like GitHub's.
+const (
+	HeaderEvent     = "X-Agentregistry-Event"
+	HeaderSignature = "X-Agentregistry-Signature"
+)
+
+// newRequest renders ev as the trigger type's API call.
+func newRequest(ctx context.Context, t *Trigger, ev Event) (*http.Request, error) {
+	switch t.Type {
+	case TypeGitHub:
+		return githubRequest(ctx, t, ev)
+	case TypeGitLab:
+		return gitlabRequest(ctx, t, ev)
+	default:
+		return webhookRequest(ctx, t, ev)
+	}
+}
+
+// githubRequest starts a workflow_dispatch run. The workflow must declare
+// the kind, namespace, name and tag inputs; GitHub rejects undeclared ones.
+func githubRequest(ctx context.Context, t *Trigger, ev Event) (*http.Request, error) {
+	body, err := json.Marshal(map[string]any{
+		"ref": t.Ref,
+		"inputs": map[string]string{
+			"kind":      ev.Kind,
+			"namespace": ev.Namespace,
+			"name":      ev.Name,
+			"tag":       ev.Tag,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/actions/workflows/%s/dispatches",
+		strings.TrimSuffix(t.URL, "/"), t.Repository, url.PathEscape(t.Workflow))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return req, nil
+}
+
+// gitlabRequest runs a pipeline through the trigger-token API, passing the
+// artifact context as AR_* CI/CD variables.
+func gitlabRequest(ctx context.Context, t *Trigger, ev Event) (*http.Request, error) {
+	form := url.Values{}
+	form.Set("token", t.token)
+	form.Set("ref", t.Ref)
+	for key, value := range map[string]string{
+		"AR_EVENT":     ev.Event,
+		"AR_KIND":      ev.Kind,
+		"AR_NAMESPACE": ev.Namespace,
+		"AR_NAME":      ev.Name,
+		"AR_TAG":       ev.Tag,
+		"AR_PATH":      ev.Path,
+		"AR_URL":       ev.URL,
+		// Vulnerability events only.
+		"AR_ADVISORY":           ev.Advisory,
+		"AR_AFFECTED_NAMESPACE": ev.AffectedNamespace,
+		"AR_AFFECTED":           strings.Join(ev.Affected, ","),
+	} {
+		if value != "" {
+			form.Set("variables["+key+"]", value)
+		}
+	}
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/trigger/pipeline",
+		strings.TrimSuffix(t.URL, "/"), url.PathEscape(t.Project))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+// webhookRequest POSTs the event as JSON, signed when a secret is set.
+func webhookRequest(ctx context.Context, t *Trigger, ev Event) (*http.Request, error) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, ev.Event)
+	if t.token != "" {
+		req.Header.Set(HeaderSignature, Sign([]byte(t.token), body))
+	}
+	return req, nil
+}
+
+// Sign returns the HeaderSignature value for body under secret. Receivers
+// recompute it over the raw request body and compare with hmac.Equal.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,166 @@
+package prompteval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Message is one chat message sent to a Model.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Model answers chat messages. Name identifies the model in stored scores,
+// since scores from different models are not comparable.
+type Model interface {
+	Name() string
+	Chat(ctx context.Context, messages []Message) (string, error)
+}
+
+// Provider names accepted by NewModel.
+const (
+	// ProviderLocal runs prompts on a model served by Ollama next to the
+	// registry.
+	ProviderLocal = "local"
+	// ProviderOpenAI runs prompts through an OpenAI-compatible
+	// /chat/completions API.
+	ProviderOpenAI = "openai"
+)
+
+// Default settings of the providers. The OpenAI provider has no default
+// model.
+const (
+	DefaultLocalURL   = "http://localhost:11434"
+	DefaultLocalModel = "llama3.2"
+	DefaultOpenAIURL  = "https://api.openai.com/v1"
+)
+
+// ProviderConfig selects and configures a Model.
+type ProviderConfig struct {
+	// Name is the provider name. Empty means no model.
+	Name string
+	// URL is the provider's base URL. Empty uses the provider's default.
+	URL string
+	// Model is the model name. Empty uses the provider's default.
+	Model string
+	// APIKey authenticates to the OpenAI provider.
+	APIKey string
+}
+
+// NewModel returns the model cfg names, or nil when cfg.Name is empty.
+// client carries the outbound transport and bounds each model call; build
+// it with httpclient.New so it honors the outbound TLS and proxy settings.
+// It is required whenever cfg.Name is set.
+func NewModel(cfg ProviderConfig, client *http.Client) (Model, error) {
+	if cfg.Name == "" {
+		return nil, nil
+	}
+	if client == nil {
+		return nil, fmt.Errorf("prompt evaluation provider %q needs an HTTP client", cfg.Name)
+	}
+	url, model := cfg.URL, cfg.Model
+	switch cfg.Name {
+	case ProviderLocal:
+		if url == "" {
+			url = DefaultLocalURL
+		}
+		if model == "" {
+			model = DefaultLocalModel
+		}
+		return &ollama{url: strings.TrimRight(url, "/") + "/api/chat", model: model, client: client}, nil
+	case ProviderOpenAI:
+		if url == "" {
+			url = DefaultOpenAIURL
+		}
+		if model == "" {
+			return nil, fmt.Errorf("prompt evaluation provider %q needs a model", cfg.Name)
+		}
+		return &openAI{url: strings.TrimRight(url, "/") + "/chat/completions", model: model, apiKey: cfg.APIKey, client: client}, nil
+	}
+	return nil, fmt.Errorf("unknown prompt evaluation provider %q (want %q or %q)", cfg.Name, ProviderLocal, ProviderOpenAI)
+}
+
+// ollama chats through an Ollama server's /api/chat endpoint. The model
+// must have been pulled on the server.
+type ollama struct {
+	url    string
+	model  string
+	client *http.Client
+}
+
+func (o *ollama) Name() string { return o.model }
+
+func (o *ollama) Chat(ctx context.Context, messages []Message) (string, error) {
+	var out struct {
+		Message Message `json:"message"`
+	}
+	body := map[string]any{"model": o.model, "messages": messages, "stream": false}
+	if err := postJSON(ctx, o.client, o.url, nil, body, &out); err != nil {
+		return "", fmt.Errorf("ollama chat: %w", err)
+	}
+	return out.Message.Content, nil
+}
+
+// openAI chats through an OpenAI-compatible /chat/completions endpoint.
+type openAI struct {
+	url    string
+	model  string
+	apiKey string
+	client *http.Client
+}
+
+func (o *openAI) Name() string { return o.model }
+
+func (o *openAI) Chat(ctx context.Context, messages []Message) (string, error) {
+	var out struct {
+		Choices []struct {
+			Message Message `json:"message"`
+		} `json:"choices"`
+	}
+	header := http.Header{}
+	if o.apiKey != "" {
+		header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+	body := map[string]any{"model": o.model, "messages": messages}
+	if err := postJSON(ctx, o.client, o.url, header, body, &out); err != nil {
+		return "", fmt.Errorf("openai chat: %w", err)
+	}
+	if len(out.Choices) == 0 {
+		return "", fmt.Errorf("openai chat: response has no choices")
+	}
+	return out.Choices[0].Message.Content, nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+// Package prompteval scores Prompts against a model. A Prompt's
+// spec.evaluation lists cases: variables to render the prompt with, an
+// optional user input, and assertions or a rubric the answer is checked
+// against. Every published tag with an evaluation is run in the
+// background and its score stored, so a team can compare a new tag with
+// the one it would replace before promoting it.
+package prompteval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// ErrNoEvaluation is returned by Run for a Prompt tag without
+// spec.evaluation.
+var ErrNoEvaluation = errors.New("prompt has no spec.evaluation")
+
+// Getter reads one tag of a Prompt. *v1alpha1store.Store satisfies it.
+type Getter interface {
+	Get(ctx context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error)
+}
+
+// Store records evaluations. *v1alpha1store.PromptEvaluationStore
+// satisfies it.
+type Store interface {
+	Record(ctx context.Context, e v1alpha1store.PromptEvaluation) (*v1alpha1store.PromptEvaluation, error)
+}
+
+var _ Store = (*v1alpha1store.PromptEvaluationStore)(nil)
+
+// Evaluator runs Prompt evaluations and records their scores. It
+// implements types.Auditor to evaluate every Prompt tag as it is
+// published; that run is in the background and never fails the publish.
+type Evaluator struct {
+	model   Model
+	prompts Getter
+	store   Store
+	wg      sync.WaitGroup
+}
+
+// NewEvaluator returns an Evaluator that runs Prompts read through prompts
+// against model and records the scores in store.
+func NewEvaluator(model Model, prompts Getter, store Store) *Evaluator {
+	return &Evaluator{model: model, prompts: prompts, store: store}
+}
+
+// ResourceTagCreated implements types.Auditor.
+func (e *Evaluator) ResourceTagCreated(ctx context.Context, kind, namespace, name, tag string) {
+	if kind != v1alpha1.KindPrompt {
+		return
+	}
+	requestedBy := auth.SubjectFrom(ctx)
+	// Detach from the request and read regardless of the caller's scope.
+	ctx = auth.WithSystemContext(context.WithoutCancel(ctx))
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		_, err := e.Run(ctx, namespace, name, tag, v1alpha1store.PromptEvalTriggerPublish, requestedBy)
+		if err != nil && !errors.Is(err, ErrNoEvaluation) {
+			slog.Error("prompt evaluation on publish failed", "namespace", namespace, "name", name, "tag", tag, "error", err)
+		}
+	}()
+}
+
+// Wait blocks until in-flight evaluations finish. Tests use it.
+func (e *Evaluator) Wait() {
+	e.wg.Wait()
+}
+
+// Run evaluates the Prompt tag and records the result. A model that
+// cannot be reached for any case is recorded as a failed run without a
+// score rather than as a score of 0, so an outage does not read as a
+// regression. Errors reading the Prompt or recording the result are
+// returned; ErrNoEvaluation when the tag has no evaluation.
+func (e *Evaluator) Run(ctx context.Context, namespace, name, tag, trigger, requestedBy string) (*v1alpha1store.PromptEvaluation, error) {
+	row, err := e.prompts.Get(ctx, namespace, name, tag)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Prompt { return &v1alpha1.Prompt{} }, row, v1alpha1.KindPrompt)
+	if err != nil {
+		return nil, err
+	}
+	if prompt.Spec.Evaluation == nil || len(prompt.Spec.Evaluation.Cases) == 0 {
+		return nil, ErrNoEvaluation
+	}
+	result := v1alpha1store.PromptEvaluation{
+		Namespace:   namespace,
+		Name:        name,
+		Tag:         tag,
+		Model:       e.model.Name(),
+		Trigger:     trigger,
+		RequestedBy: requestedBy,
+	}
+	score, cases, err := Evaluate(ctx, e.model, prompt.Spec.Content, prompt.Spec.Evaluation)
+	result.Results = cases
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Score = &score
+	}
+	return e.store.Record(ctx, result)
+}
+
+// Evaluate runs every case of eval against model and returns the mean
+// case score from 0 to 100 with each case's result. It fails only when no
+// case reached the model.
+func Evaluate(ctx context.Context, model Model, content string, eval *v1alpha1.PromptEvaluation) (float64, []v1alpha1store.PromptCaseResult, error) {
+	results := make([]v1alpha1store.PromptCaseResult, 0, len(eval.Cases))
+	var total float64
+	var firstErr error
+	for _, c := range eval.Cases {
+		result := evaluateCase(ctx, model, content, c)
+		if result.Error != "" && firstErr == nil {
+			firstErr = fmt.Errorf("case %s: %s", c.Name, result.Error)
+		}
+		total += result.Score
+		results = append(results, result)
+	}
+	if firstErr != nil && allFailed(results) {
+		return 0, results, fmt.Errorf("no case reached the model: %w", firstErr)
+	}
+	return round(100 * total / float64(len(results))), results, nil
+}
+
+func allFailed(results []v1alpha1store.PromptCaseResult) bool {
+	for _, r := range results {
+		if r.Error == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func evaluateCase(ctx context.Context, model Model, content string, c v1alpha1.PromptEvalCase) v1alpha1store.PromptCaseResult {
+	result := v1alpha1store.PromptCaseResult{Name: c.Name}
+	rendered := Render(content, c.Variables)
+	messages := []Message{{Role: "user", Content: rendered}}
+	if c.Input != "" {
+		messages = []Message{{Role: "system", Content: rendered}, {Role: "user", Content: c.Input}}
+	}
+	answer, err := model.Chat(ctx, messages)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Answer = answer
+
+	for _, a := range c.Assertions {
+		result.Checks = append(result.Checks, checkAssertion(a, answer))
+	}
+	if c.Rubric != "" {
+		result.Checks = append(result.Checks, grade(ctx, model, c.Rubric, answer))
+	}
+	var sum float64
+	for _, check := range result.Checks {
+		sum += check.Score
+	}
+	if len(result.Checks) > 0 {
+		result.Score = round(sum / float64(len(result.Checks)))
+	}
+	return result
+}
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// Render replaces every {{name}} in content with vars[name]. Placeholders
+// without a variable are left as they are.
+func Render(content string, vars map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(content, func(m string) string {
+		if v, ok := vars[variablePattern.FindStringSubmatch(m)[1]]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+func checkAssertion(a v1alpha1.PromptAssertion, answer string) v1alpha1store.PromptCheckResult {
+	var check string
+	var pass bool
+	switch {
+	case a.Contains != "":
+		check = "contains " + strconv.Quote(a.Contains)
+		pass = strings.Contains(strings.ToLower(answer), strings.ToLower(a.Contains))
+	case a.NotContains != "":
+		check = "notContains " + strconv.Quote(a.NotContains)
+		pass = !strings.Contains(strings.ToLower(answer), strings.ToLower(a.NotContains))
+	case a.Matches != "":
+		check = "matches " + strconv.Quote(a.Matches)
+		re, err := regexp.Compile(a.Matches)
+		if err != nil {
+			return v1alpha1store.PromptCheckResult{Check: check, Detail: err.Error()}
+		}
+		pass = re.MatchString(answer)
+	case a.MaxLength > 0:
+		check = "maxLength " + strconv.Itoa(a.MaxLength)
+		n := utf8.RuneCountInString(answer)
+		pass = n <= a.MaxLength
+		if !pass {
+			return v1alpha1store.PromptCheckResult{Check: check, Detail: fmt.Sprintf("%d characters", n)}
+		}
+	}
+	if pass {
+		return v1alpha1store.PromptCheckResult{Check: check, Score: 1}
+	}
+	return v1alpha1store.PromptCheckResult{Check: check}
+}
+
+const graderInstructions = "You grade answers against a rubric. Reply with a single integer from 0 (fails the rubric) to 10 (fully meets it) and nothing else."
+
+var gradePattern = regexp.MustCompile(`\b(10|[0-9])\b`)
+
+// grade has model score answer against rubric from 0 to 10 and returns
+// the grade as a fraction. A reply without a grade scores 0.
+func grade(ctx context.Context, model Model, rubric, answer string) v1alpha1store.PromptCheckResult {
+	check := v1alpha1store.PromptCheckResult{Check: "rubric"}
+	reply, err := model.Chat(ctx, []Message{
+		{Role: "system", Content: graderInstructions},
+		{Role: "user", Content: "Rubric:\n" + rubric + "\n\nAnswer:\n" + answer},
+	})
+	if err != nil {
+		check.Detail = "grading failed: " + err.Error()
+		return check
+	}
+	m := gradePattern.FindStringSubmatch(reply)
+	if m == nil {
+		check.Detail = "no grade in reply: " + strconv.Quote(truncate(reply, 80))
+		return check
+	}
+	n, _ := strconv.Atoi(m[1])
+	check.Score = float64(n) / 10
+	check.Detail = m[1] + "/10"
+	return check
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
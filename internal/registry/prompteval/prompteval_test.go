@@ -0,0 +1,217 @@
+package prompteval_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/prompteval"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// fakeModel answers with answer, or grade when asked to grade, and
+// records what it was sent.
+type fakeModel struct {
+	answer string
+	grade  string
+	err    error
+	sent   [][]prompteval.Message
+}
+
+func (m *fakeModel) Name() string { return "fake" }
+
+func (m *fakeModel) Chat(_ context.Context, messages []prompteval.Message) (string, error) {
+	m.sent = append(m.sent, messages)
+	if m.err != nil {
+		return "", m.err
+	}
+	if strings.HasPrefix(messages[len(messages)-1].Content, "Rubric:") {
+		return m.grade, nil
+	}
+	return m.answer, nil
+}
+
+type session string
+
+func (s session) Principal() auth.Principal { return auth.Principal{Subject: string(s)} }
+
+type fakePrompts map[string]*v1alpha1.Prompt
+
+func (f fakePrompts) Get(_ context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	p, ok := f[namespace+"/"+name+"@"+tag]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	spec, err := json.Marshal(p.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.RawObject{Metadata: p.Metadata, Spec: spec}, nil
+}
+
+type fakeStore struct {
+	recorded []v1alpha1store.PromptEvaluation
+}
+
+func (f *fakeStore) Record(_ context.Context, e v1alpha1store.PromptEvaluation) (*v1alpha1store.PromptEvaluation, error) {
+	e.ID = int64(len(f.recorded) + 1)
+	f.recorded = append(f.recorded, e)
+	return &e, nil
+}
+
+func summarizer(eval *v1alpha1.PromptEvaluation) *v1alpha1.Prompt {
+	return &v1alpha1.Prompt{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "summarizer", Tag: "1.0.0"},
+		Spec:     v1alpha1.PromptSpec{Content: "Summarize for {{ audience }}: {{article}}", Evaluation: eval},
+	}
+}
+
+func TestRender(t *testing.T) {
+	require.Equal(t, "Summarize for execs: {{article}}",
+		prompteval.Render("Summarize for {{ audience }}: {{article}}", map[string]string{"audience": "execs"}))
+}
+
+func TestEvaluate_ScoresAssertionsAndRubric(t *testing.T) {
+	model := &fakeModel{answer: "Summary: revenue grew 4%.", grade: "8"}
+	eval := &v1alpha1.PromptEvaluation{Cases: []v1alpha1.PromptEvalCase{
+		{
+			Name:      "checks",
+			Variables: map[string]string{"audience": "execs", "article": "Q3 report"},
+			Assertions: []v1alpha1.PromptAssertion{
+				{Contains: "SUMMARY"},
+				{NotContains: "lorem"},
+				{Matches: `\d+%`},
+				{MaxLength: 5},
+			},
+		},
+		{Name: "graded", Input: "Q3 report", Rubric: "Mentions the growth figure."},
+	}}
+
+	score, results, err := prompteval.Evaluate(context.Background(), model, summarizer(nil).Spec.Content, eval)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, 0.75, results[0].Score)
+	require.Equal(t, "maxLength 5", results[0].Checks[3].Check)
+	require.Zero(t, results[0].Checks[3].Score)
+	require.Equal(t, 0.8, results[1].Score)
+	require.Equal(t, 77.5, score)
+
+	require.Equal(t, []prompteval.Message{{Role: "user", Content: "Summarize for execs: Q3 report"}}, model.sent[0])
+	require.Equal(t, "system", model.sent[1][0].Role)
+	require.Equal(t, prompteval.Message{Role: "user", Content: "Q3 report"}, model.sent[1][1])
+}
+
+func TestEvaluate_UnreachableModelHasNoScore(t *testing.T) {
+	model := &fakeModel{err: errors.New("connection refused")}
+	eval := &v1alpha1.PromptEvaluation{Cases: []v1alpha1.PromptEvalCase{{Name: "a", Rubric: "x"}}}
+	_, results, err := prompteval.Evaluate(context.Background(), model, "hi", eval)
+	require.ErrorContains(t, err, "connection refused")
+	require.Equal(t, "connection refused", results[0].Error)
+}
+
+func TestEvaluator_RunsOnPublish(t *testing.T) {
+	eval := &v1alpha1.PromptEvaluation{Cases: []v1alpha1.PromptEvalCase{
+		{Name: "a", Assertions: []v1alpha1.PromptAssertion{{Contains: "summary"}}},
+	}}
+	prompts := fakePrompts{
+		"default/summarizer@1.0.0": summarizer(eval),
+		"default/plain@1.0.0":      {Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "plain", Tag: "1.0.0"}},
+	}
+	store := &fakeStore{}
+	evaluator := prompteval.NewEvaluator(&fakeModel{answer: "A summary."}, prompts, store)
+
+	ctx := auth.AuthSessionTo(context.Background(), session("alice"))
+	evaluator.ResourceTagCreated(ctx, v1alpha1.KindPrompt, "default", "summarizer", "1.0.0")
+	evaluator.ResourceTagCreated(ctx, v1alpha1.KindPrompt, "default", "plain", "1.0.0")
+	evaluator.ResourceTagCreated(ctx, v1alpha1.KindSkill, "default", "summarizer", "1.0.0")
+	evaluator.Wait()
+
+	require.Len(t, store.recorded, 1)
+	got := store.recorded[0]
+	require.Equal(t, "1.0.0", got.Tag)
+	require.Equal(t, "fake", got.Model)
+	require.Equal(t, v1alpha1store.PromptEvalTriggerPublish, got.Trigger)
+	require.Equal(t, "alice", got.RequestedBy)
+	require.NotNil(t, got.Score)
+	require.Equal(t, 100.0, *got.Score)
+}
+
+func TestEvaluator_Run(t *testing.T) {
+	store := &fakeStore{}
+	evaluator := prompteval.NewEvaluator(&fakeModel{err: errors.New("down")},
+		fakePrompts{"default/summarizer@1.0.0": summarizer(&v1alpha1.PromptEvaluation{Cases: []v1alpha1.PromptEvalCase{{Name: "a", Rubric: "x"}}})},
+		store)
+
+	got, err := evaluator.Run(context.Background(), "default", "summarizer", "1.0.0", v1alpha1store.PromptEvalTriggerManual, "")
+	require.NoError(t, err)
+	require.Nil(t, got.Score)
+	require.Contains(t, got.Error, "down")
+
+	_, err = evaluator.Run(context.Background(), "default", "summarizer", "2.0.0", v1alpha1store.PromptEvalTriggerManual, "")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+}
+
+func TestNewModel(t *testing.T) {
+	model, err := prompteval.NewModel(prompteval.ProviderConfig{}, nil)
+	require.NoError(t, err)
+	require.Nil(t, model)
+	client := httpclient.New(time.Second)
+	_, err = prompteval.NewModel(prompteval.ProviderConfig{Name: prompteval.ProviderLocal}, nil)
+	require.ErrorContains(t, err, "needs an HTTP client")
+	_, err = prompteval.NewModel(prompteval.ProviderConfig{Name: prompteval.ProviderOpenAI}, client)
+	require.ErrorContains(t, err, "needs a model")
+	_, err = prompteval.NewModel(prompteval.ProviderConfig{Name: "other"}, client)
+	require.ErrorContains(t, err, "unknown prompt evaluation provider")
+}
+
+func TestModels_Chat(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		switch r.URL.Path {
+		case "/api/chat":
+			_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"from ollama"}}`))
+		case "/v1/chat/completions":
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"from openai"}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	messages := []prompteval.Message{{Role: "user", Content: "hi"}}
+
+	local, err := prompteval.NewModel(prompteval.ProviderConfig{Name: prompteval.ProviderLocal, URL: srv.URL}, srv.Client())
+	require.NoError(t, err)
+	require.Equal(t, prompteval.DefaultLocalModel, local.Name())
+	answer, err := local.Chat(context.Background(), messages)
+	require.NoError(t, err)
+	require.Equal(t, "from ollama", answer)
+	require.Equal(t, false, gotBody["stream"])
+
+	openai, err := prompteval.NewModel(prompteval.ProviderConfig{Name: prompteval.ProviderOpenAI, URL: srv.URL + "/v1", Model: "gpt-test", APIKey: "sk-test"}, srv.Client())
+	require.NoError(t, err)
+	answer, err = openai.Chat(context.Background(), messages)
+	require.NoError(t, err)
+	require.Equal(t, "from openai", answer)
+	require.Equal(t, "/v1/chat/completions", gotPath)
+	require.Equal(t, "Bearer sk-test", gotAuth)
+	require.Equal(t, "gpt-test", gotBody["model"])
+
+	broken, err := prompteval.NewModel(prompteval.ProviderConfig{Name: prompteval.ProviderLocal, URL: srv.URL + "/missing"}, srv.Client())
+	require.NoError(t, err)
+	_, err = broken.Chat(context.Background(), messages)
+	require.ErrorContains(t, err, "404")
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+)
+
+// Scopes reported in the scope attribute of the throttled metric.
+const (
+	ScopeIP      = "ip"
+	ScopeCaller  = "caller"
+	ScopePublish = "publish"
+)
+
+// Config tunes Middleware.
+type Config struct {
+	// PerIP limits unauthenticated requests per client IP.
+	PerIP Rate
+	// PerCaller limits authenticated requests per caller (auth subject),
+	// whichever token or address they arrive from.
+	PerCaller Rate
+	// TrustForwardedFor takes the client IP from the first X-Forwarded-For
+	// hop. Set it only behind a proxy that overwrites the header; otherwise
+	// clients pick their own bucket.
+	TrustForwardedFor bool
+	// Throttled counts refused requests. Nil counts nothing.
+	Throttled metric.Int64Counter
+}
+
+// Enabled reports whether cfg limits anything.
+func (cfg Config) Enabled() bool {
+	return !cfg.PerIP.IsZero() || !cfg.PerCaller.IsZero()
+}
+
+// Middleware returns Huma middleware enforcing cfg. It must run after the
+// authn middleware, which names the caller. Health and ping checks are
+// never limited.
+func Middleware(api huma.API, cfg Config) func(huma.Context, func(huma.Context)) {
+	perIP, perCaller := NewLimiter(cfg.PerIP), NewLimiter(cfg.PerCaller)
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if path := ctx.Operation().Path; strings.HasSuffix(path, "/health") || strings.HasSuffix(path, "/ping") {
+			next(ctx)
+			return
+		}
+		scope, limiter, key := ScopeIP, perIP, clientIP(ctx, cfg.TrustForwardedFor)
+		if subject := auth.SubjectFrom(ctx.Context()); subject != "" {
+			scope, limiter, key = ScopeCaller, perCaller, subject
+		}
+		ok, wait := limiter.Allow(key)
+		if ok {
+			next(ctx)
+			return
+		}
+		countThrottled(ctx.Context(), cfg.Throttled, scope)
+		ctx.SetHeader("Retry-After", retryAfter(wait))
+		_ = huma.WriteErr(api, ctx, http.StatusTooManyRequests,
+			fmt.Sprintf("rate limit exceeded; retry in %ss", retryAfter(wait)))
+	}
+}
+
+// clientIP returns the request's client address without its port.
+func clientIP(ctx huma.Context, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if forwarded := ctx.Header("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			if first = strings.TrimSpace(first); first != "" {
+				return first
+			}
+		}
+	}
+	remote := ctx.RemoteAddr()
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
+
+func countThrottled(ctx context.Context, counter metric.Int64Counter, scope string) {
+	if counter != nil {
+		counter.Add(ctx, 1, metric.WithAttributes(attribute.String("scope", scope)))
+	}
+}
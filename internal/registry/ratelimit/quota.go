@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+)
+
+// QuotaError refuses a publish over its namespace's quota. It carries a
+// 429 status and a Retry-After header through Huma.
+type QuotaError struct {
+	Namespace  string
+	RetryAfter time.Duration
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("publish quota of namespace %q exceeded; retry in %ss", e.Namespace, retryAfter(e.RetryAfter))
+}
+
+// GetStatus implements huma.StatusError.
+func (e *QuotaError) GetStatus() int { return http.StatusTooManyRequests }
+
+// GetHeaders implements huma.HeadersError.
+func (e *QuotaError) GetHeaders() http.Header {
+	return http.Header{"Retry-After": []string{retryAfter(e.RetryAfter)}}
+}
+
+// PublishQuota caps publishes per namespace. Every apply of a quota kind
+// takes a token, including unchanged re-applies and dry runs, so a client
+// cannot probe past the quota. Registry admins and internal system calls
+// are exempt.
+type PublishQuota struct {
+	limiter   *Limiter
+	isAdmin   func(ctx context.Context) bool
+	throttled metric.Int64Counter
+}
+
+// NewPublishQuota returns a quota of rate publishes per namespace.
+// throttled, when set, counts refused publishes.
+func NewPublishQuota(rate Rate, isAdmin func(ctx context.Context) bool, throttled metric.Int64Counter) *PublishQuota {
+	return &PublishQuota{limiter: NewLimiter(rate), isAdmin: isAdmin, throttled: throttled}
+}
+
+// Kinds returns the kinds whose publishes count against the quota.
+func Kinds() []string {
+	return []string{v1alpha1.KindAgent, v1alpha1.KindMCPServer, v1alpha1.KindSkill, v1alpha1.KindPrompt}
+}
+
+// Check takes a token from namespace's quota, returning a *QuotaError
+// when it is used up.
+func (q *PublishQuota) Check(ctx context.Context, namespace string) error {
+	if session, ok := auth.AuthSessionFrom(ctx); ok && auth.IsSystemSession(session) {
+		return nil
+	}
+	if q.isAdmin != nil && q.isAdmin(ctx) {
+		return nil
+	}
+	ok, wait := q.limiter.Allow(namespace)
+	if ok {
+		return nil
+	}
+	countThrottled(ctx, q.throttled, ScopePublish)
+	return &QuotaError{Namespace: namespace, RetryAfter: wait}
+}
+
+// Prepare returns a Prepare hook that runs next, then charges the object's
+// namespace one publish. Wire it for each of Kinds.
+func (q *PublishQuota) Prepare(next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		return q.Check(ctx, obj.GetMetadata().NamespaceOrDefault())
+	}
+}
@@ -0,0 +1,152 @@
+// Package ratelimit protects the API from scraping and publish floods.
+// Middleware applies token-bucket limits per client IP and per
+// authenticated caller to every Huma operation; PublishQuota caps
+// publishes of Agents, MCPServers, Skills and Prompts per namespace. Both
+// answer 429 Too Many Requests with a Retry-After header and count the
+// throttled request in the agent_registry.http.throttled metric.
+//
+// Buckets live in process memory, so each replica enforces its own
+// limits: behind N replicas a client gets up to N times the configured
+// rate.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate is Count events per Per. As a token bucket it refills Count tokens
+// evenly over Per and holds at most Count, so a client that was idle can
+// burst the whole allowance at once.
+type Rate struct {
+	Count int
+	Per   time.Duration
+}
+
+// IsZero reports whether r is unset, meaning no limit.
+func (r Rate) IsZero() bool {
+	return r.Count <= 0 || r.Per <= 0
+}
+
+func (r Rate) String() string {
+	if r.IsZero() {
+		return ""
+	}
+	switch r.Per {
+	case time.Second:
+		return fmt.Sprintf("%d/s", r.Count)
+	case time.Minute:
+		return fmt.Sprintf("%d/m", r.Count)
+	case time.Hour:
+		return fmt.Sprintf("%d/h", r.Count)
+	}
+	return fmt.Sprintf("%d/%s", r.Count, r.Per)
+}
+
+// ParseRate parses "COUNT/UNIT", where UNIT is s, m, h or a Go duration
+// such as 10m: "20/s", "600/m", "100/1h". An empty string is the zero
+// Rate.
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Rate{}, nil
+	}
+	count, unit, ok := strings.Cut(s, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("rate %q: want COUNT/UNIT, e.g. 600/m", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || n <= 0 {
+		return Rate{}, fmt.Errorf("rate %q: count must be a positive integer", s)
+	}
+	var per time.Duration
+	switch unit = strings.TrimSpace(unit); unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		per, err = time.ParseDuration(unit)
+		if err != nil || per <= 0 {
+			return Rate{}, fmt.Errorf("rate %q: unit must be s, m, h or a positive duration", s)
+		}
+	}
+	return Rate{Count: n, Per: per}, nil
+}
+
+// sweepInterval is how often a Limiter drops buckets that have refilled,
+// so clients seen once do not hold memory forever.
+const sweepInterval = time.Minute
+
+// Limiter keeps one token bucket per key.
+type Limiter struct {
+	rate  Rate
+	now   func() time.Time
+	mu    sync.Mutex
+	byKey map[string]*bucket
+	swept time.Time
+}
+
+type bucket struct {
+	tokens float64
+	at     time.Time
+}
+
+// NewLimiter returns a Limiter that allows each key rate. A zero rate
+// allows everything.
+func NewLimiter(rate Rate) *Limiter {
+	return &Limiter{rate: rate, now: time.Now, byKey: map[string]*bucket{}}
+}
+
+// Allow takes a token from key's bucket. When the bucket is empty it
+// returns false and how long until a token is available.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l == nil || l.rate.IsZero() {
+		return true, 0
+	}
+	now := l.now()
+	perToken := l.rate.Per.Seconds() / float64(l.rate.Count)
+	burst := float64(l.rate.Count)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.swept) >= sweepInterval {
+		l.sweep(now, perToken, burst)
+	}
+	b, ok := l.byKey[key]
+	if !ok {
+		b = &bucket{tokens: burst, at: now}
+		l.byKey[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.at).Seconds()/perToken)
+	b.at = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) * perToken * float64(time.Second))
+	return false, wait
+}
+
+// sweep drops buckets that have refilled; a new bucket starts full, so
+// forgetting them changes nothing.
+func (l *Limiter) sweep(now time.Time, perToken, burst float64) {
+	l.swept = now
+	for key, b := range l.byKey {
+		if b.tokens+now.Sub(b.at).Seconds()/perToken >= burst {
+			delete(l.byKey, key)
+		}
+	}
+}
+
+// retryAfter renders wait as a Retry-After value: whole seconds, rounded
+// up, at least 1.
+func retryAfter(wait time.Duration) string {
+	return strconv.Itoa(max(1, int(math.Ceil(wait.Seconds()))))
+}
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+)
+
+func TestParseRate(t *testing.T) {
+	for in, want := range map[string]Rate{
+		"":       {},
+		"20/s":   {Count: 20, Per: time.Second},
+		" 600/m": {Count: 600, Per: time.Minute},
+		"100/h":  {Count: 100, Per: time.Hour},
+		"5/10m":  {Count: 5, Per: 10 * time.Minute},
+	} {
+		got, err := ParseRate(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"600", "0/m", "-1/s", "x/s", "10/day", "10/-1s"} {
+		_, err := ParseRate(in)
+		require.Error(t, err, in)
+	}
+	require.Equal(t, "600/m", Rate{Count: 600, Per: time.Minute}.String())
+}
+
+func TestLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter(Rate{Count: 2, Per: time.Second})
+	l.now = func() time.Time { return now }
+
+	for range 2 {
+		ok, _ := l.Allow("a")
+		require.True(t, ok)
+	}
+	ok, wait := l.Allow("a")
+	require.False(t, ok)
+	require.Equal(t, 500*time.Millisecond, wait)
+
+	// Other keys have their own bucket.
+	ok, _ = l.Allow("b")
+	require.True(t, ok)
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = l.Allow("a")
+	require.True(t, ok)
+
+	// Refilled buckets are swept.
+	now = now.Add(time.Minute)
+	ok, _ = l.Allow("c")
+	require.True(t, ok)
+	require.Len(t, l.byKey, 1)
+
+	ok, _ = NewLimiter(Rate{}).Allow("a")
+	require.True(t, ok)
+}
+
+type session string
+
+func (s session) Principal() auth.Principal { return auth.Principal{Subject: string(s)} }
+
+func TestMiddleware(t *testing.T) {
+	_, api := humatest.New(t)
+	// Stands in for the authn middleware.
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		if subject := ctx.Header("X-Test-Subject"); subject != "" {
+			ctx = huma.WithContext(ctx, auth.AuthSessionTo(ctx.Context(), session(subject)))
+		}
+		next(ctx)
+	})
+	api.UseMiddleware(Middleware(api, Config{
+		PerIP:     Rate{Count: 1, Per: time.Minute},
+		PerCaller: Rate{Count: 2, Per: time.Minute},
+	}))
+	huma.Get(api, "/v0/things", func(context.Context, *struct{}) (*struct{}, error) { return nil, nil })
+	huma.Get(api, "/v0/health", func(context.Context, *struct{}) (*struct{}, error) { return nil, nil })
+
+	require.Equal(t, http.StatusNoContent, api.Get("/v0/things").Code)
+	resp := api.Get("/v0/things")
+	require.Equal(t, http.StatusTooManyRequests, resp.Code)
+	require.Equal(t, "60", resp.Header().Get("Retry-After"))
+	require.Contains(t, resp.Body.String(), "rate limit exceeded")
+
+	// Callers are limited by subject, not by address.
+	require.Equal(t, http.StatusNoContent, api.Get("/v0/things", "X-Test-Subject: alice").Code)
+	require.Equal(t, http.StatusNoContent, api.Get("/v0/things", "X-Test-Subject: alice").Code)
+	require.Equal(t, http.StatusTooManyRequests, api.Get("/v0/things", "X-Test-Subject: alice").Code)
+	require.Equal(t, http.StatusNoContent, api.Get("/v0/things", "X-Test-Subject: bob").Code)
+
+	require.Equal(t, http.StatusNoContent, api.Get("/v0/health").Code)
+}
+
+func TestPublishQuota(t *testing.T) {
+	admin := false
+	q := NewPublishQuota(Rate{Count: 1, Per: time.Hour}, func(context.Context) bool { return admin }, nil)
+	prepare := q.Prepare(nil)
+	agent := func(namespace string) v1alpha1.Object {
+		return &v1alpha1.Agent{Metadata: v1alpha1.ObjectMeta{Namespace: namespace, Name: "bot"}}
+	}
+	ctx := context.Background()
+
+	require.NoError(t, prepare(ctx, agent("team-a")))
+	err := prepare(ctx, agent("team-a"))
+	var quota *QuotaError
+	require.True(t, errors.As(err, &quota))
+	require.Equal(t, "team-a", quota.Namespace)
+	require.Equal(t, http.StatusTooManyRequests, quota.GetStatus())
+	require.Equal(t, "3600", quota.GetHeaders().Get("Retry-After"))
+
+	require.NoError(t, prepare(ctx, agent("team-b")))
+	require.NoError(t, prepare(auth.WithSystemContext(ctx), agent("team-a")))
+	admin = true
+	require.NoError(t, prepare(ctx, agent("team-a")))
+}
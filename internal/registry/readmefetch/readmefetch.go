@@ -0,0 +1,196 @@
+// Package readmefetch fills in the README of MCPServers published from a
+// GitHub repository. When README fetching is enabled, every publish of an
+// MCPServer whose spec.readme is empty and whose spec.source.repository.url
+// points at github.com fetches the repository's README through the GitHub
+// API, sanitizes it and stores it as spec.readme, which the readme
+// subresource serves. Publishers opt a version out with
+// v1alpha1.SkipReadmeFetchAnnotation (`arctl publish --skip-readme-fetch`).
+//
+// Fetching never fails a publish: a repository without a README, a rate
+// limit or a network error is logged and the server is published without
+// one.
+package readmefetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// DefaultAPIURL is the GitHub REST API base URL.
+const DefaultAPIURL = "https://api.github.com"
+
+// DefaultTimeout bounds one fetch.
+const DefaultTimeout = 10 * time.Second
+
+// Fetcher fetches READMEs from the GitHub API.
+type Fetcher struct {
+	Client *http.Client
+	// APIURL is the GitHub API base URL; empty means DefaultAPIURL. GitHub
+	// Enterprise serves it under https://HOST/api/v3.
+	APIURL string
+	// Token authenticates requests, which raises GitHub's rate limit and
+	// reaches private repositories. Empty sends them anonymously.
+	Token string
+	// Timeout bounds each fetch. Zero means DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Prepare returns an MCPServer Prepare hook that runs next, then fills in
+// spec.readme from the server's GitHub repository when it is empty.
+func (f *Fetcher) Prepare(next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		server, ok := obj.(*v1alpha1.MCPServer)
+		if !ok || server.Spec.Readme != "" || server.Spec.Source == nil || server.Spec.Source.Repository == nil {
+			return nil
+		}
+		if server.Metadata.Annotations[v1alpha1.SkipReadmeFetchAnnotation] == "true" {
+			return nil
+		}
+		repo := server.Spec.Source.Repository
+		owner, name, ok := ParseRepository(repo.URL)
+		if !ok {
+			return nil
+		}
+		readme, err := f.Fetch(ctx, owner, name, repo)
+		if err != nil {
+			slog.Warn("readme fetch failed", "namespace", server.Metadata.Namespace, "name", server.Metadata.Name,
+				"tag", server.Metadata.Tag, "repository", repo.URL, "error", err)
+			return nil
+		}
+		server.Spec.Readme = Sanitize(readme)
+		return nil
+	}
+}
+
+// Fetch returns the raw README of owner/name at the commit (or branch) and
+// subfolder repo names.
+func (f *Fetcher) Fetch(ctx context.Context, owner, name string, repo *v1alpha1.Repository) (string, error) {
+	endpoint := strings.TrimSuffix(f.APIURL, "/")
+	if endpoint == "" {
+		endpoint = DefaultAPIURL
+	}
+	endpoint += "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name) + "/readme"
+	if dir := strings.Trim(path.Clean("/"+repo.Subfolder), "/"); dir != "" {
+		endpoint += "/" + (&url.URL{Path: dir}).EscapedPath()
+	}
+	if ref := repo.Commit; ref != "" || repo.Branch != "" {
+		if ref == "" {
+			ref = repo.Branch
+		}
+		endpoint += "?" + url.Values{"ref": {ref}}.Encode()
+	}
+
+	timeout := f.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github.raw")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if f.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+f.Token)
+	}
+	client := f.Client
+	if client == nil {
+		client = httpclient.New(timeout)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: %s", endpoint, resp.Status)
+	}
+	// Sanitize truncates; the cap only keeps a huge file out of memory.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4*v1alpha1.MaxReadmeLength))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ParseRepository returns the owner and repository name of a github.com
+// repository URL: https://github.com/OWNER/REPO[.git][/...] or
+// git@github.com:OWNER/REPO[.git]. ok is false for any other URL.
+func ParseRepository(rawURL string) (owner, name string, ok bool) {
+	var p string
+	if rest, found := strings.CutPrefix(rawURL, "git@github.com:"); found {
+		p = rest
+	} else {
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http" && u.Scheme != "ssh") {
+			return "", "", false
+		}
+		if host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www."); host != "github.com" {
+			return "", "", false
+		}
+		p = u.Path
+	}
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	if len(parts) < 2 {
+		return "", "", false
+	}
+	owner, name = parts[0], strings.TrimSuffix(parts[1], ".git")
+	if owner == "" || name == "" {
+		return "", "", false
+	}
+	return owner, name, true
+}
+
+var (
+	htmlComment   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	unsafeElement = regexp.MustCompile(`(?is)<(script|style|iframe|object|embed|form)\b.*?(</\s*(script|style|iframe|object|embed|form)\s*>|\z)`)
+	unsafeTag     = regexp.MustCompile(`(?i)</?\s*(script|style|iframe|object|embed|form|input|button|meta|link|base)\b[^>]*>`)
+	htmlTag       = regexp.MustCompile(`<[a-zA-Z][^>]*>`)
+	eventAttr     = regexp.MustCompile(`(?i)\s+on[a-z]+\s*=\s*("[^"]*"|'[^']*'|[^\s>]+)`)
+	tagURL        = regexp.MustCompile(`(?i)((?:href|src)\s*=\s*["']?\s*)(javascript|vbscript|data)\s*:`)
+	markdownURL   = regexp.MustCompile(`(?i)(\]\(\s*<?)(javascript|vbscript|data)\s*:`)
+)
+
+// Sanitize makes a fetched README safe to serve: it drops HTML comments,
+// active elements such as script, style and iframe (with their content) and
+// event-handler attributes, defuses javascript:, vbscript: and data: link
+// and image URLs, and truncates the result to v1alpha1.MaxReadmeLength
+// bytes on a UTF-8 boundary. Markdown and inert HTML are kept.
+func Sanitize(readme string) string {
+	readme = strings.ToValidUTF8(readme, "")
+	readme = htmlComment.ReplaceAllString(readme, "")
+	readme = unsafeElement.ReplaceAllString(readme, "")
+	readme = unsafeTag.ReplaceAllString(readme, "")
+	readme = htmlTag.ReplaceAllStringFunc(readme, func(tag string) string {
+		tag = eventAttr.ReplaceAllString(tag, "")
+		return tagURL.ReplaceAllString(tag, "${1}unsafe-${2}:")
+	})
+	readme = markdownURL.ReplaceAllString(readme, "${1}unsafe-${2}:")
+	if len(readme) > v1alpha1.MaxReadmeLength {
+		cut := v1alpha1.MaxReadmeLength
+		for cut > 0 && !utf8.RuneStart(readme[cut]) {
+			cut--
+		}
+		readme = readme[:cut]
+	}
+	return strings.TrimSpace(readme)
+}
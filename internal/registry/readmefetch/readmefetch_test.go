@@ -0,0 +1,128 @@
+package readmefetch_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/readmefetch"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+func server(name string, repo *v1alpha1.Repository) *v1alpha1.MCPServer {
+	s := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: name, Tag: "1.0.0"},
+	}
+	if repo != nil {
+		s.Spec.Source = &v1alpha1.MCPServerSource{Repository: repo}
+	}
+	return s
+}
+
+func TestPrepare(t *testing.T) {
+	ctx := context.Background()
+	var requests []string
+	gh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		require.Equal(t, "Bearer ghp_test", r.Header.Get("Authorization"))
+		require.Equal(t, "application/vnd.github.raw", r.Header.Get("Accept"))
+		switch r.URL.Path {
+		case "/repos/acme/weather/readme", "/repos/acme/monorepo/readme/servers/weather":
+			_, _ = w.Write([]byte("# Weather\n<script>alert(1)</script>\nForecasts.\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gh.Close()
+	fetcher := &readmefetch.Fetcher{Client: gh.Client(), APIURL: gh.URL, Token: "ghp_test"}
+	prepare := fetcher.Prepare(nil)
+
+	fetched := server("weather", &v1alpha1.Repository{URL: "https://github.com/acme/weather.git", Commit: "abc123"})
+	require.NoError(t, prepare(ctx, fetched))
+	require.Equal(t, "# Weather\n\nForecasts.", fetched.Spec.Readme)
+
+	sub := server("weather", &v1alpha1.Repository{URL: "git@github.com:acme/monorepo.git", Branch: "main", Subfolder: "servers/weather"})
+	require.NoError(t, prepare(ctx, sub))
+	require.Equal(t, "# Weather\n\nForecasts.", sub.Spec.Readme)
+
+	missing := server("missing", &v1alpha1.Repository{URL: "https://github.com/acme/missing"})
+	require.NoError(t, prepare(ctx, missing), "a missing README does not fail the publish")
+	require.Empty(t, missing.Spec.Readme)
+
+	require.Equal(t, []string{
+		"/repos/acme/weather/readme?ref=abc123",
+		"/repos/acme/monorepo/readme/servers/weather?ref=main",
+		"/repos/acme/missing/readme",
+	}, requests)
+
+	own := server("own", &v1alpha1.Repository{URL: "https://github.com/acme/weather"})
+	own.Spec.Readme = "Mine."
+	require.NoError(t, prepare(ctx, own))
+	require.Equal(t, "Mine.", own.Spec.Readme)
+
+	skipped := server("skipped", &v1alpha1.Repository{URL: "https://github.com/acme/weather"})
+	skipped.Metadata.Annotations = map[string]string{v1alpha1.SkipReadmeFetchAnnotation: "true"}
+	require.NoError(t, prepare(ctx, skipped))
+	require.Empty(t, skipped.Spec.Readme)
+
+	require.NoError(t, prepare(ctx, server("gitlab", &v1alpha1.Repository{URL: "https://gitlab.com/acme/weather"})))
+	require.NoError(t, prepare(ctx, server("none", nil)))
+	require.Len(t, requests, 3, "only github.com repositories without a README or opt-out are fetched")
+
+	failing := fetcher.Prepare(func(context.Context, v1alpha1.Object) error { return errors.New("boom") })
+	require.EqualError(t, failing(ctx, server("weather", nil)), "boom")
+}
+
+func TestParseRepository(t *testing.T) {
+	for _, tc := range []struct {
+		url         string
+		owner, name string
+		ok          bool
+	}{
+		{"https://github.com/acme/weather", "acme", "weather", true},
+		{"https://github.com/acme/weather.git", "acme", "weather", true},
+		{"https://www.github.com/acme/weather/tree/main/servers", "acme", "weather", true},
+		{"git@github.com:acme/weather.git", "acme", "weather", true},
+		{"ssh://git@github.com/acme/weather.git", "acme", "weather", true},
+		{"https://github.com/acme", "", "", false},
+		{"https://gitlab.com/acme/weather", "", "", false},
+		{"https://github.com.evil.example/acme/weather", "", "", false},
+		{"file:///github.com/acme/weather", "", "", false},
+	} {
+		owner, name, ok := readmefetch.ParseRepository(tc.url)
+		require.Equal(t, tc.ok, ok, tc.url)
+		require.Equal(t, tc.owner, owner, tc.url)
+		require.Equal(t, tc.name, name, tc.url)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	for _, tc := range []struct {
+		name, in, want string
+	}{
+		{"markdown", "# Title\n\nSet `one=2` and metadata: x.", "# Title\n\nSet `one=2` and metadata: x."},
+		{"comment", "a<!-- hidden -->b", "ab"},
+		{"script", "a<SCRIPT src=x>steal()</script>b", "ab"},
+		{"unclosed", "a<iframe src=x>rest", "a"},
+		{"stray tag", "a<meta http-equiv=refresh content=0>b", "ab"},
+		{"event handler", `<img src="logo.png" onerror="steal()">`, `<img src="logo.png">`},
+		{"html link", `<a href="javascript:steal()">x</a>`, `<a href="unsafe-javascript:steal()">x</a>`},
+		{"markdown link", "[x](javascript:steal())", "[x](unsafe-javascript:steal())"},
+		{"data image", "![x](data:image/svg+xml;base64,AAAA)", "![x](unsafe-data:image/svg+xml;base64,AAAA)"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, readmefetch.Sanitize(tc.in))
+		})
+	}
+
+	long := readmefetch.Sanitize(strings.Repeat("é", v1alpha1.MaxReadmeLength))
+	require.LessOrEqual(t, len(long), v1alpha1.MaxReadmeLength)
+	require.True(t, strings.HasPrefix(long, "éé"))
+	require.NotContains(t, long, "�")
+}
@@ -9,34 +9,28 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"slices"
-	"strconv"
-	"strings"
 	"syscall"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel"
 
-	mcpregistry "github.com/agentregistry-dev/agentregistry/internal/mcp/registryserver"
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/api"
-	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/crud"
-	"github.com/agentregistry-dev/agentregistry/internal/registry/api/router"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/apikeyauth"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/config"
 	controller "github.com/agentregistry-dev/agentregistry/internal/registry/controller"
-	internaldb "github.com/agentregistry-dev/agentregistry/internal/registry/database"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/peers"
 	pluginsource "github.com/agentregistry-dev/agentregistry/internal/registry/plugins/source"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/frameworks"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/kubernetes"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/local"
-	deploymentsvc "github.com/agentregistry-dev/agentregistry/internal/registry/service/deployment"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/secrets"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/telemetry"
 	"github.com/agentregistry-dev/agentregistry/internal/version"
 	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	"github.com/agentregistry-dev/agentregistry/pkg/logging"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
-	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
-	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
 	"github.com/agentregistry-dev/agentregistry/pkg/types"
 )
@@ -57,6 +51,13 @@ func App(ctx context.Context, opts ...types.AppOptions) error {
 
 	setupLogging(cfg.LogLevel)
 
+	// Outbound trust and proxy settings apply to every HTTP client the server
+	// builds through internal/httpclient (package validators, OCI fetches,
+	// upstream registries).
+	if err := httpclient.Configure(outboundHTTPOptions(cfg)); err != nil {
+		return fmt.Errorf("configure outbound HTTP: %w", err)
+	}
+
 	// Build auth providers from options (before database creation)
 	// Only create jwtManager if JWT is configured
 	var jwtManager *auth.JWTManager
@@ -76,6 +77,9 @@ func App(ctx context.Context, opts ...types.AppOptions) error {
 		slog.Info("using public authz provider")
 		authzProvider = auth.NewPublicAuthzProvider(jwtManager)
 	}
+	// API key sessions are never registry admins and stay within their
+	// scopes, whichever provider decides everything else.
+	authzProvider = apikeyauth.Authz(authzProvider)
 	authz := auth.Authorizer{Authz: authzProvider}
 
 	// Effective SkipMigrations: AppOptions wins when set, otherwise the
@@ -99,18 +103,61 @@ func App(ctx context.Context, opts ...types.AppOptions) error {
 
 	// v1alpha1 DeploymentAdapter map consumed by the Deployment controller and
 	// adjacent adapter resolver surfaces.
-	// Built OSS-side from the local + kubernetes ports; enterprise extends
-	// via AppOptions.DeploymentAdapters. Keys are the canonical CamelCase
+	// Built OSS-side from the local + kubernetes ports, which run
+	// AppOptions.ManifestMutators; enterprise extends via
+	// AppOptions.DeploymentAdapters. Keys are the canonical CamelCase
 	// Spec.Type values; Runtime.Validate canonicalizes user-supplied case
 	// at admission so adapter lookup can use exact-match.
+	// A nil registry knows the built-in framework descriptors only.
+	var agentFrameworks *frameworks.Registry
+	if cfg.AgentFrameworksFile != "" {
+		if agentFrameworks, err = frameworks.LoadFile(cfg.AgentFrameworksFile); err != nil {
+			return err
+		}
+		slog.Info("agent frameworks loaded", "count", agentFrameworks.Len())
+	}
 	deploymentAdapters := map[string]types.DeploymentAdapter{
-		v1alpha1.TypeLocal:      local.NewLocalDeploymentAdapter(cfg.RuntimeDir, cfg.AgentGatewayPort),
-		v1alpha1.TypeKubernetes: kubernetes.NewKubernetesDeploymentAdapter(),
+		v1alpha1.TypeLocal:      local.NewLocalDeploymentAdapter(cfg.RuntimeDir, cfg.AgentGatewayPort, options.ManifestMutators...).WithFrameworks(agentFrameworks),
+		v1alpha1.TypeKubernetes: kubernetes.NewKubernetesDeploymentAdapter(options.ManifestMutators...).WithFrameworks(agentFrameworks),
 	}
 	maps.Copy(deploymentAdapters, options.DeploymentAdapters)
 	pool := db.Pool()
-	stores := buildStores(pool, options.V1Alpha1StoreTables, options.V1Alpha1MutableStoreKinds, options.Auditor)
-	if _, err := controller.StartDeploymentController(ctx, pool, stores, deploymentAdapters, deploymentControllerConfig(cfg)); err != nil {
+	// Optional features are wired in stages: audit listeners before the
+	// stores they listen to, then controller inputs, admission hooks and
+	// routes (features.go, admission.go).
+	f := &features{cfg: cfg, pool: pool}
+	auditor, err := f.setupAuditors(ctx, options.Auditor)
+	if err != nil {
+		return err
+	}
+	stores := buildStores(pool, options.V1Alpha1StoreTables, options.V1Alpha1MutableStoreKinds, auditor,
+		v1alpha1store.WithDeletedRetention(cfg.DeletedArtifactRetention))
+	f.stores = stores
+	// Peer registries resolve Agent spec.mcpServers refs that name another
+	// registry; both the controller and the log resolver translate Agents.
+	peerRegistry, err := peers.New(cfg.PeerRegistries, cfg.PeerCacheTTL)
+	if err != nil {
+		return fmt.Errorf("configure peer registries: %w", err)
+	}
+	if names := peerRegistry.Names(); len(names) > 0 {
+		slog.Info("peer registries enabled", "peers", names)
+	}
+	// Sensitive Runtime config values are sealed at rest; everything that
+	// hands a Runtime to an adapter opens it first.
+	sealer, err := runtimeSealer(cfg, options)
+	if err != nil {
+		return err
+	}
+	f.setupUsage(ctx)
+	f.setupSnapshots()
+	controllerConfig := deploymentControllerConfig(cfg)
+	controllerConfig.GetterWrapper = func(getter v1alpha1.GetterFunc) v1alpha1.GetterFunc {
+		return sealer.Getter(peerRegistry.Getter(getter))
+	}
+	controllerConfig.OpenRuntime = sealer.Open
+	f.configureController(&controllerConfig)
+	controllerHandle, err := controller.StartDeploymentController(ctx, pool, stores, deploymentAdapters, controllerConfig)
+	if err != nil {
 		return fmt.Errorf("start deployment controller: %w", err)
 	}
 	// The Plugin controller resolves each plugin's pinned source pointer to a
@@ -160,8 +207,36 @@ func App(ctx context.Context, opts ...types.AppOptions) error {
 			slog.Error("failed to shutdown telemetry", "error", err)
 		}
 	}()
+	if f.usage != nil {
+		if err := f.usage.RegisterMetrics(otel.Meter(telemetry.Namespace)); err != nil {
+			return err
+		}
+	}
 
-	routeOpts := buildRouteOptions(options, stores, deploymentAdapters, crudPerKindHooks(options))
+	perKindHooks := crudPerKindHooks(options, sealer)
+	authnProvider, err = f.setupAdmission(ctx, admission{
+		hooks:     &perKindHooks,
+		isAdmin:   authz.IsRegistryAdmin,
+		resources: controllerConfig.Resources,
+		peers:     peerRegistry,
+		sealer:    sealer,
+		throttled: metrics.Throttled,
+	}, authnProvider)
+	if err != nil {
+		return err
+	}
+
+	routeOpts := buildRouteOptions(options, stores, deploymentAdapters, perKindHooks, peerRegistry, sealer)
+	routeOpts.RuntimeSecrets = sealer
+	// The reconcile plan enumerates every Deployment regardless of
+	// namespace, so it is gated on registry admin at the API layer.
+	if controllerHandle != nil && controllerHandle.Controller != nil {
+		routeOpts.ReconcilePlanner = controllerHandle.Controller
+		routeOpts.DeploymentRenderer = controllerHandle.Controller
+	}
+	routeOpts.IsRegistryAdmin = authz.IsRegistryAdmin
+	routeOpts.CheckPermission = authz.Check
+	f.configureRoutes(ctx, routeOpts, auditor)
 
 	// Initialize HTTP server
 	baseServer, err := api.NewServer(cfg, metrics, versionInfo, options.UIHandler, authnProvider, routeOpts)
@@ -180,7 +255,10 @@ func App(ctx context.Context, opts ...types.AppOptions) error {
 		options.OnHTTPServerCreated(server)
 	}
 
-	mcpHTTPServer := startMCPServer(cfg, stores, authnProvider)
+	mcpHTTPServer, err := startMCPServer(cfg, stores, authnProvider)
+	if err != nil {
+		return err
+	}
 
 	// Start server in a goroutine so it doesn't block signal handling
 	go func() {
@@ -215,341 +293,48 @@ func App(ctx context.Context, opts ...types.AppOptions) error {
 	return nil
 }
 
-// resolveExtraStoreSchema resolves an extra-store table value to its schema
-// and bare table name. A bare "table" stays in ossSchema; a qualified
-// "schema.table" resolves to that schema. Panics via MustNewSchema if the
-// schema segment is not a valid identifier (see
-// types.AppOptions.V1Alpha1StoreTables).
-func resolveExtraStoreSchema(table string, ossSchema pkgdb.Schema) (pkgdb.Schema, string) {
-	if s, t, ok := strings.Cut(table, "."); ok {
-		return pkgdb.MustNewSchema(s), t
-	}
-	return ossSchema, table
-}
-
-func buildStores(pool *pgxpool.Pool, extraStoreTables map[string]string, mutableExtraKinds map[string]bool, auditor types.Auditor) map[string]*v1alpha1store.Store {
-	if auditor == nil {
-		auditor = types.NoopAuditor
-	}
-	// Resolve schemas once and inject them, so the stores qualify their
-	// tables explicitly rather than depend on the connection's
-	// search_path.
-	schemas := pkgdb.OSSSchemaRegistry()
-	ossSchema := schemas.MustGet(pkgdb.OSSSourceName)
-	stores := v1alpha1store.NewStores(pool, schemas, v1alpha1store.WithAuditor(auditor))
-	for kind, table := range extraStoreTables {
-		if kind == "" || table == "" {
-			slog.Warn("skipping v1alpha1 extra store with empty kind or table", "kind", kind, "table", table)
-			continue
-		}
-		// Honor a qualified "schema.table" so a kind registered in its own
-		// schema resolves there (see V1Alpha1StoreTables); a bare "table"
-		// stays in the OSS schema.
-		sch, tbl := resolveExtraStoreSchema(table, ossSchema)
-		if tbl == "" {
-			slog.Warn("skipping v1alpha1 extra store with empty table after schema qualifier", "kind", kind, "table", table)
-			continue
+// runtimeSealer returns the Sealer for Runtime credentials: the embedder's
+// SecretsCipher, else AES-GCM under SECRETS_MASTER_KEY, else, when
+// SECRETS_ALLOW_PLAINTEXT opts in, a Sealer that only redacts, else one
+// that refuses secret values.
+func runtimeSealer(cfg *config.Config, options types.AppOptions) (*secrets.Sealer, error) {
+	if options.SecretsCipher != nil {
+		slog.Info("runtime secrets encryption enabled", "key", options.SecretsCipher.KeyID())
+		return secrets.NewSealer(options.SecretsCipher), nil
+	}
+	if cfg.SecretsMasterKey == "" {
+		if cfg.SecretsAllowPlaintext {
+			slog.Warn("INSECURE: runtime secrets are stored unencrypted because SECRETS_ALLOW_PLAINTEXT is set; set SECRETS_MASTER_KEY to encrypt them")
+			return secrets.NewInsecureSealer(), nil
 		}
-		opts := []v1alpha1store.StoreOption{v1alpha1store.WithKind(kind), v1alpha1store.WithAuditor(auditor)}
-		if mutableExtraKinds[kind] {
-			stores[kind] = v1alpha1store.NewMutableObjectStore(pool, sch, tbl, opts...)
-			continue
-		}
-		stores[kind] = v1alpha1store.NewStore(pool, sch, tbl, opts...)
-	}
-
-	// pool == nil is the noop/DatabaseFactory path used by gen-openapi
-	// and the release-openapi make target. Routes still register so the
-	// generated OpenAPI captures every endpoint, but actual queries
-	// would crash on the nil pool — that's fine because the noop path
-	// never serves real traffic.
-	if pool == nil {
-		slog.Info("v1alpha1 routes registered against nil pool: query path will panic if exercised (likely noop/DatabaseFactory)")
-		return stores
-	}
-
-	slog.Info("v1alpha1 routes enabled")
-	return stores
-}
-
-func deploymentControllerConfig(cfg *config.Config) controller.ControllerConfig {
-	return controller.ControllerConfig{
-		Retention: controller.RetentionPolicy{
-			ControlPlaneEvents: cfg.ControllerEventRetention,
-			EventKeepAfterRev:  cfg.ControllerEventKeepAfterRevision,
-			BatchLimit:         cfg.ControllerRetentionPruneBatchLimit,
-		},
-		DiscoveryInterval:          cfg.ControllerDiscoveryInterval,
-		DiscoveryStaleAfterMisses:  cfg.ControllerDiscoveryStaleAfterMisses,
-		DiscoveryDeleteAfterMisses: cfg.ControllerDiscoveryDeleteAfterMisses,
+		slog.Info("runtime secrets encryption disabled; Runtimes carrying credentials are refused until SECRETS_MASTER_KEY is set")
+		return secrets.NewSealer(nil), nil
 	}
-}
-
-func buildRouteOptions(
-	options types.AppOptions,
-	stores map[string]*v1alpha1store.Store,
-	adapters map[string]types.DeploymentAdapter,
-	perKindHooks crud.PerKindHooks,
-) *router.RouteOptions {
-	routeOpts := &router.RouteOptions{
-		ExtraRoutes:         options.ExtraRoutes,
-		Stores:              stores,
-		PerKindHooks:        perKindHooks,
-		RegistryValidator:   options.RegistryValidator,
-		Admission:           options.Admission,
-		DeleteAdmission:     options.DeleteAdmission,
-		ResolverWrapper:     options.ResolverWrapper,
-		ExtraResourceRoutes: options.ExtraResourceRoutes,
-	}
-
-	if stores != nil {
-		adapterResolver := deploymentsvc.NewAdapterResolver(deploymentsvc.ResolverDependencies{
-			Adapters: adapters,
-			Getter:   internaldb.NewGetter(stores),
-		})
-		routeOpts.DeploymentLogResolver = adapterResolver
-	}
-
-	return routeOpts
-}
-
-// crudPerKindHooks adapts the AppOptions per-kind authorizer +
-// list-filter maps (which use the public pkg/types signatures) into
-// the internal crud.PerKindHooks struct (which uses the
-// resource.AuthorizeInput type the generic resource handler
-// dispatches on). Field-for-field copy across the two
-// AuthorizeInput-shaped structs.
-func crudPerKindHooks(options types.AppOptions) crud.PerKindHooks {
-	hooks := crud.PerKindHooks{}
-	if len(options.Authorizers) > 0 {
-		hooks.Authorizers = make(map[string]func(ctx context.Context, in resource.AuthorizeInput) error, len(options.Authorizers))
-		for kind, fn := range options.Authorizers {
-			f := fn
-			hooks.Authorizers[kind] = func(ctx context.Context, in resource.AuthorizeInput) error {
-				return f(ctx, types.AuthorizeInput{
-					Verb: in.Verb, Kind: in.Kind, Namespace: in.Namespace,
-					Name: in.Name, Tag: in.Tag,
-				})
-			}
-		}
-	}
-	if len(options.ListFilters) > 0 {
-		hooks.ListFilters = make(map[string]func(ctx context.Context, in resource.AuthorizeInput) (string, []any, error), len(options.ListFilters))
-		for kind, fn := range options.ListFilters {
-			f := fn
-			hooks.ListFilters[kind] = func(ctx context.Context, in resource.AuthorizeInput) (string, []any, error) {
-				return f(ctx, types.AuthorizeInput{
-					Verb: in.Verb, Kind: in.Kind, Namespace: in.Namespace,
-					Name: in.Name, Tag: in.Tag,
-				})
-			}
-		}
-	}
-	// PostUpserts / PostDeletes are already (ctx, v1alpha1.Object) →
-	// error so they pass through verbatim — no adapter needed.
-	if len(options.PostUpserts) > 0 {
-		hooks.PostUpserts = make(map[string]func(ctx context.Context, obj v1alpha1.Object) error, len(options.PostUpserts))
-		for kind, fn := range options.PostUpserts {
-			hooks.PostUpserts[kind] = fn
-		}
-	}
-	if len(options.PostDeletes) > 0 {
-		hooks.PostDeletes = make(map[string]func(ctx context.Context, obj v1alpha1.Object) error, len(options.PostDeletes))
-		for kind, fn := range options.PostDeletes {
-			hooks.PostDeletes[kind] = fn
-		}
-	}
-	if len(options.Prepares) > 0 {
-		hooks.Prepares = make(map[string]func(ctx context.Context, obj v1alpha1.Object) error, len(options.Prepares))
-		for kind, fn := range options.Prepares {
-			hooks.Prepares[kind] = fn
-		}
-	}
-	if len(options.InitialFinalizers) > 0 {
-		hooks.InitialFinalizers = make(map[string]func(obj v1alpha1.Object) []string, len(options.InitialFinalizers))
-		maps.Copy(hooks.InitialFinalizers, options.InitialFinalizers)
-	}
-	if hooks.InitialFinalizers == nil {
-		hooks.InitialFinalizers = map[string]func(obj v1alpha1.Object) []string{}
-	}
-	previousDeploymentFinalizers := hooks.InitialFinalizers[v1alpha1.KindDeployment]
-	hooks.InitialFinalizers[v1alpha1.KindDeployment] = func(obj v1alpha1.Object) []string {
-		var finalizers []string
-		if previousDeploymentFinalizers != nil {
-			finalizers = previousDeploymentFinalizers(obj)
-		}
-		if deployment, ok := obj.(*v1alpha1.Deployment); ok && v1alpha1.IsDiscoveredDeployment(deployment) {
-			return finalizers
-		}
-		if slices.Contains(finalizers, controller.DeploymentControllerFinalizer) {
-			return finalizers
-		}
-		return append(finalizers, controller.DeploymentControllerFinalizer)
-	}
-	// RuntimeAdapters map dispatches the KindRuntime PostUpsert /
-	// PostDelete by Spec.Type → adapter. A Runtime whose type has
-	// no registered adapter is a no-op (matches the OSS default
-	// where AppOptions.RuntimeAdapters is empty). When both an
-	// explicit PostUpserts[KindRuntime] and RuntimeAdapters are
-	// present, the dispatcher chains: caller hook first, then the
-	// runtime adapter.
-	if len(options.RuntimeAdapters) > 0 {
-		adapters := make(map[string]types.RuntimeAdapter, len(options.RuntimeAdapters))
-		maps.Copy(adapters, options.RuntimeAdapters)
-		if hooks.PostUpserts == nil {
-			hooks.PostUpserts = map[string]func(ctx context.Context, obj v1alpha1.Object) error{}
-		}
-		if hooks.PostDeletes == nil {
-			hooks.PostDeletes = map[string]func(ctx context.Context, obj v1alpha1.Object) error{}
-		}
-		hooks.PostUpserts[v1alpha1.KindRuntime] = runtimeAdapterDispatcher(
-			hooks.PostUpserts[v1alpha1.KindRuntime], adapters,
-			func(ctx context.Context, r *v1alpha1.Runtime, a types.RuntimeAdapter) error {
-				return a.ApplyRuntime(ctx, r)
-			},
-		)
-		hooks.PostDeletes[v1alpha1.KindRuntime] = runtimeAdapterDispatcher(
-			hooks.PostDeletes[v1alpha1.KindRuntime], adapters,
-			func(ctx context.Context, r *v1alpha1.Runtime, a types.RuntimeAdapter) error {
-				return a.RemoveRuntime(ctx, r.Metadata.Name)
-			},
-		)
-	}
-	return hooks
-}
-
-// runtimeAdapterDispatcher wraps a (kind=Runtime) hook so the caller
-// hook (if any) runs first, then dispatches to the per-type adapter
-// matching runtime.Spec.Type. Spec.Type is canonicalized at admission
-// time (Runtime.Validate), so the lookup is exact-match against
-// adapter.Type(). A Runtime with no registered adapter is a no-op so
-// the hook stays safe for partial wiring.
-func runtimeAdapterDispatcher(
-	caller func(ctx context.Context, obj v1alpha1.Object) error,
-	adapters map[string]types.RuntimeAdapter,
-	dispatch func(ctx context.Context, r *v1alpha1.Runtime, a types.RuntimeAdapter) error,
-) func(ctx context.Context, obj v1alpha1.Object) error {
-	return func(ctx context.Context, obj v1alpha1.Object) error {
-		if caller != nil {
-			if err := caller(ctx, obj); err != nil {
-				return err
-			}
-		}
-		runtime, ok := obj.(*v1alpha1.Runtime)
-		if !ok || runtime == nil {
-			return nil
-		}
-		adapter, ok := adapters[runtime.Spec.Type]
-		if !ok {
-			return nil
-		}
-		return dispatch(ctx, runtime, adapter)
-	}
-}
-
-// openDatabase selects and constructs the base Store (plus any
-// DatabaseFactory wrap) and returns it. Two paths:
-//   - DATABASE_URL="noop" requires options.DatabaseFactory to supply the
-//     Store entirely (e.g. in-memory or custom backend). Used by tests
-//     and noop runs.
-//   - Otherwise connect to PostgreSQL; if a DatabaseFactory is set, it
-//     wraps the base pool so implementors can run additional migrations
-//     and layer authz/caching on top.
-//
-// On factory failure the base pool is closed before returning the wrap
-// error so we don't leak connections into the caller's error path.
-func openDatabase(
-	appCtx, dbCtx context.Context,
-	cfg *config.Config,
-	options types.AppOptions,
-	authz auth.Authorizer,
-	skipMigrations bool,
-) (pkgdb.Store, error) {
-	if cfg.DatabaseURL == "noop" {
-		if options.DatabaseFactory == nil {
-			return nil, fmt.Errorf("DATABASE_URL=noop requires DatabaseFactory to be set in AppOptions")
-		}
-		slog.Info("using DatabaseFactory to create database", "mode", "noop")
-		db, err := options.DatabaseFactory(appCtx, "", nil, authz)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create database via factory: %w", err)
-		}
-		return db, nil
-	}
-
-	baseDB, err := internaldb.NewPostgreSQL(dbCtx, cfg.DatabaseURL, authz, skipMigrations)
+	key, err := secrets.ParseMasterKey(cfg.SecretsMasterKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
-	}
-	if options.DatabaseFactory == nil {
-		return baseDB, nil
+		return nil, err
 	}
-	wrapped, err := options.DatabaseFactory(appCtx, cfg.DatabaseURL, baseDB, authz)
+	cipher, err := secrets.NewAESGCM(key)
 	if err != nil {
-		if closeErr := baseDB.Close(); closeErr != nil {
-			slog.Error("error closing base database connection", "error", closeErr)
-		}
-		return nil, fmt.Errorf("failed to create extended database: %w", err)
+		return nil, fmt.Errorf("configure secrets encryption: %w", err)
 	}
-	return wrapped, nil
-}
-
-// startMCPServer wires the MCP HTTP bridge on cfg.MCPPort and launches it
-// in a background goroutine. Returns nil when MCP is disabled (no port
-// configured, or v1alpha1 Stores not wired — MCP is a consumer of the
-// v1alpha1 data model and has nothing to serve without it). The returned
-// *http.Server, when non-nil, should be shut down alongside the main
-// server on quit.
-func startMCPServer(
-	cfg *config.Config,
-	stores map[string]*v1alpha1store.Store,
-	authnProvider auth.AuthnProvider,
-) *http.Server {
-	if cfg.MCPPort <= 0 {
-		return nil
-	}
-	mcpServer := mcpregistry.NewServer(stores)
-	var handler http.Handler = mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server {
-		return mcpServer
-	}, &mcp.StreamableHTTPOptions{})
-	if authnProvider != nil {
-		handler = mcpAuthnMiddleware(authnProvider)(handler)
-	}
-	addr := ":" + strconv.Itoa(int(cfg.MCPPort))
-	srv := &http.Server{
-		Addr:              addr,
-		Handler:           handler,
-		ReadHeaderTimeout: 10 * time.Second,
-	}
-	go func() {
-		slog.Info("MCP HTTP server starting", "address", addr)
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			slog.Error("failed to start MCP server", "error", err)
-			os.Exit(1)
-		}
-	}()
-	return srv
+	slog.Info("runtime secrets encryption enabled", "key", cipher.KeyID())
+	return secrets.NewSealer(cipher), nil
 }
 
-// mcpAuthnMiddleware uses the AuthnProvider to attach a session to the
-// request context on successful authentication. On auth error or missing
-// session, the request continues with an unauthenticated context — the
-// AuthzProvider downstream decides whether the request is allowed (the
-// OSS default `PublicAuthzProvider` permits read-only access; downstream
-// authz can reject). Failing-open here is intentional so the MCP bridge
-// works for anonymous `list_servers` / `get_server` traffic while still
-// letting authenticated callers pick up privileged operations.
-func mcpAuthnMiddleware(authn auth.AuthnProvider) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ctx := r.Context()
-			session, err := authn.Authenticate(ctx, r.Header.Get, r.URL.Query())
-			if err == nil && session != nil {
-				ctx = auth.AuthSessionTo(ctx, session)
-				r = r.WithContext(ctx)
-			}
-			next.ServeHTTP(w, r)
-		})
+// outboundHTTPOptions maps the server's outbound trust and proxy settings
+// onto the shared transport options.
+func outboundHTTPOptions(cfg *config.Config) httpclient.Options {
+	return httpclient.Options{
+		TLS: httpclient.TLSOptions{
+			CAFile:   cfg.OutboundCAFile,
+			CertFile: cfg.OutboundClientCertFile,
+			KeyFile:  cfg.OutboundClientKeyFile,
+		},
+		Proxy: httpclient.ProxyOptions{
+			URL:     cfg.OutboundProxyURL,
+			NoProxy: cfg.OutboundNoProxy,
+		},
 	}
 }
 
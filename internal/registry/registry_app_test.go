@@ -15,6 +15,7 @@ func TestDeploymentControllerConfigMapsRetentionSettings(t *testing.T) {
 		ControllerEventRetention:             2 * time.Hour,
 		ControllerEventKeepAfterRevision:     42,
 		ControllerRetentionPruneBatchLimit:   17,
+		DeletedArtifactRetention:             72 * time.Hour,
 		ControllerDiscoveryInterval:          15 * time.Second,
 		ControllerDiscoveryStaleAfterMisses:  2,
 		ControllerDiscoveryDeleteAfterMisses: 4,
@@ -25,9 +26,22 @@ func TestDeploymentControllerConfigMapsRetentionSettings(t *testing.T) {
 	require.Equal(t, 2*time.Hour, got.Retention.ControlPlaneEvents)
 	require.Equal(t, int64(42), got.Retention.EventKeepAfterRev)
 	require.Equal(t, 17, got.Retention.BatchLimit)
+	require.Equal(t, 72*time.Hour, got.Retention.DeletedArtifacts)
 	require.Equal(t, 15*time.Second, got.DiscoveryInterval)
 	require.Equal(t, 2, got.DiscoveryStaleAfterMisses)
 	require.Equal(t, 4, got.DiscoveryDeleteAfterMisses)
+	require.False(t, got.ShipLogs)
+	require.Zero(t, got.Retention.DeploymentLogs)
+
+	cfg.DeploymentLogShippingEnabled = true
+	cfg.DeploymentLogShipInterval = 10 * time.Second
+	cfg.DeploymentLogRetention = 24 * time.Hour
+	cfg.DeploymentLogMaxBytes = 1 << 20
+	got = deploymentControllerConfig(cfg)
+	require.True(t, got.ShipLogs)
+	require.Equal(t, 10*time.Second, got.LogShipInterval)
+	require.Equal(t, 24*time.Hour, got.Retention.DeploymentLogs)
+	require.Equal(t, int64(1<<20), got.Retention.DeploymentLogMaxBytes)
 }
 
 func TestBuildStoresAddsExtraStoreTables(t *testing.T) {
@@ -0,0 +1,201 @@
+// Package remoteprobe checks that a published MCPServer's remote URL is
+// alive, so dead endpoints do not pollute search. When remote probing is
+// enabled, every publish of an MCPServer with a remote probes its URL;
+// a version whose URL does not answer is stamped with
+// v1alpha1.RemoteUnverifiedAnnotation, which /v0/search leaves out by
+// default. The publish itself still succeeds. Verifier.Run re-probes the
+// unverified versions on a schedule and clears the annotation once a
+// check passes.
+//
+// Liveness is deliberately loose: any HTTP response below 500 counts,
+// including the 401 of a server that wants a token and the 405 a
+// streamable-http endpoint answers a plain GET with. Connection errors,
+// timeouts and 5xx answers do not.
+package remoteprobe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// DefaultTimeout bounds one probe.
+const DefaultTimeout = 5 * time.Second
+
+// listPageSize is the store page size used while rechecking.
+const listPageSize = 200
+
+// Probe returns an error when the remote does not answer.
+type Probe func(ctx context.Context, remote *v1alpha1.MCPRemote) error
+
+// HTTPProbe returns a Probe that sends a GET to the remote URL. Headers
+// are not sent: they may carry credentials, and liveness does not need
+// them.
+func HTTPProbe(client *http.Client) Probe {
+	if client == nil {
+		client = httpclient.New(DefaultTimeout)
+	}
+	return func(ctx context.Context, remote *v1alpha1.MCPRemote) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, remote.URL, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		// SSE endpoints keep the body open; the status line is enough.
+		_ = resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("GET %s: %s", remote.URL, resp.Status)
+		}
+		return nil
+	}
+}
+
+// Store reads and patches MCPServer rows. *v1alpha1store.Store satisfies
+// it.
+type Store interface {
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+	ApplyPatch(ctx context.Context, namespace, name, tag string, patch v1alpha1store.PatchOpts) error
+}
+
+var _ Store = (*v1alpha1store.Store)(nil)
+
+// Verifier probes MCPServer remotes on publish and rechecks the versions
+// that failed.
+type Verifier struct {
+	Probe Probe
+	// Store is the MCPServer store Recheck walks. Prepare does not need
+	// it.
+	Store Store
+	// Timeout bounds each probe. Zero means DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Prepare returns an MCPServer Prepare hook that runs next, then probes
+// the server's remote and sets or clears RemoteUnverifiedAnnotation, over
+// whatever the publisher supplied.
+func (v *Verifier) Prepare(next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		server, ok := obj.(*v1alpha1.MCPServer)
+		if !ok {
+			return nil
+		}
+		delete(server.Metadata.Annotations, v1alpha1.RemoteUnverifiedAnnotation)
+		if err := v.probe(ctx, server.Spec.Remote); err != nil {
+			if server.Metadata.Annotations == nil {
+				server.Metadata.Annotations = map[string]string{}
+			}
+			server.Metadata.Annotations[v1alpha1.RemoteUnverifiedAnnotation] = err.Error()
+		}
+		return nil
+	}
+}
+
+// RecheckReport counts the outcome of one Recheck.
+type RecheckReport struct {
+	Checked  int
+	Verified int
+}
+
+// Recheck re-probes every unverified MCPServer version and clears the
+// annotation of those that answer now. A version whose row cannot be
+// decoded or patched is logged and skipped; errors listing the store end
+// the run.
+func (v *Verifier) Recheck(ctx context.Context) (RecheckReport, error) {
+	var report RecheckReport
+	opts := v1alpha1store.ListOpts{
+		Limit:      listPageSize,
+		ExtraWhere: "annotations ? $1",
+		ExtraArgs:  []any{v1alpha1.RemoteUnverifiedAnnotation},
+	}
+	for {
+		rows, next, err := v.Store.List(ctx, opts)
+		if err != nil {
+			return report, fmt.Errorf("list unverified MCPServers: %w", err)
+		}
+		for _, row := range rows {
+			meta := row.Metadata
+			server, err := v1alpha1.EnvelopeFromRaw(func() *v1alpha1.MCPServer { return &v1alpha1.MCPServer{} }, row, v1alpha1.KindMCPServer)
+			if err != nil {
+				slog.Warn("remote recheck: decode MCPServer", "namespace", meta.Namespace, "name", meta.Name, "tag", meta.Tag, "error", err)
+				continue
+			}
+			report.Checked++
+			probeErr := v.probe(ctx, server.Spec.Remote)
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			if probeErr != nil {
+				continue
+			}
+			err = v.Store.ApplyPatch(ctx, meta.NamespaceOrDefault(), meta.Name, meta.Tag, v1alpha1store.PatchOpts{
+				Annotations: func(a map[string]string) map[string]string {
+					delete(a, v1alpha1.RemoteUnverifiedAnnotation)
+					return a
+				},
+			})
+			if err != nil {
+				slog.Warn("remote recheck: clear annotation", "namespace", meta.Namespace, "name", meta.Name, "tag", meta.Tag, "error", err)
+				continue
+			}
+			report.Verified++
+		}
+		if next == "" {
+			return report, nil
+		}
+		opts.Cursor = next
+	}
+}
+
+// Run rechecks unverified versions every interval until ctx is done.
+func (v *Verifier) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := v.Recheck(ctx)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					slog.Warn("remote recheck failed", "error", err)
+				}
+				continue
+			}
+			if report.Verified > 0 {
+				slog.Info("remote recheck verified MCPServer versions",
+					"verified", report.Verified, "still_unverified", report.Checked-report.Verified)
+			}
+		}
+	}
+}
+
+// probe checks remote, which passes when nil or without a URL.
+func (v *Verifier) probe(ctx context.Context, remote *v1alpha1.MCPRemote) error {
+	if remote == nil || remote.URL == "" {
+		return nil
+	}
+	timeout := v.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return v.Probe(ctx, remote)
+}
@@ -0,0 +1,115 @@
+package remoteprobe_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/remoteprobe"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// deadURLs fails the probe of every remote URL it lists.
+type deadURLs map[string]bool
+
+func (d deadURLs) probe(_ context.Context, remote *v1alpha1.MCPRemote) error {
+	if d[remote.URL] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func server(name, url string) *v1alpha1.MCPServer {
+	s := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: name, Tag: "1.0.0"},
+	}
+	if url != "" {
+		s.Spec.Remote = &v1alpha1.MCPRemote{Type: "streamable-http", URL: url}
+	}
+	return s
+}
+
+func TestPrepare(t *testing.T) {
+	ctx := context.Background()
+	verifier := &remoteprobe.Verifier{Probe: deadURLs{"https://dead.example/mcp": true}.probe}
+	prepare := verifier.Prepare(nil)
+
+	dead := server("dead", "https://dead.example/mcp")
+	require.NoError(t, prepare(ctx, dead), "a dead remote does not fail the publish")
+	require.Equal(t, "connection refused", dead.Metadata.Annotations[v1alpha1.RemoteUnverifiedAnnotation])
+
+	live := server("live", "https://live.example/mcp")
+	live.Metadata.Annotations = map[string]string{v1alpha1.RemoteUnverifiedAnnotation: "set by the publisher"}
+	require.NoError(t, prepare(ctx, live))
+	require.NotContains(t, live.Metadata.Annotations, v1alpha1.RemoteUnverifiedAnnotation)
+
+	packaged := server("packaged", "")
+	require.NoError(t, prepare(ctx, packaged))
+	require.Empty(t, packaged.Metadata.Annotations)
+
+	failing := verifier.Prepare(func(context.Context, v1alpha1.Object) error { return errors.New("boom") })
+	require.EqualError(t, failing(ctx, server("dead", "https://dead.example/mcp")), "boom")
+}
+
+func TestHTTPProbe(t *testing.T) {
+	status := http.StatusMethodNotAllowed
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	probe := remoteprobe.HTTPProbe(srv.Client())
+	ctx := context.Background()
+
+	require.NoError(t, probe(ctx, &v1alpha1.MCPRemote{URL: srv.URL}), "any answer below 500 is alive")
+	status = http.StatusBadGateway
+	require.ErrorContains(t, probe(ctx, &v1alpha1.MCPRemote{URL: srv.URL}), "502 Bad Gateway")
+	srv.Close()
+	require.Error(t, probe(ctx, &v1alpha1.MCPRemote{URL: srv.URL}))
+}
+
+// fakeStore serves the unverified rows and records the patched ones.
+type fakeStore struct {
+	rows    []*v1alpha1.RawObject
+	patched []string
+}
+
+func (f *fakeStore) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	if opts.ExtraWhere != "annotations ? $1" || opts.ExtraArgs[0] != v1alpha1.RemoteUnverifiedAnnotation {
+		return nil, "", errors.New("unexpected filter")
+	}
+	return f.rows, "", nil
+}
+
+func (f *fakeStore) ApplyPatch(_ context.Context, _, name, tag string, patch v1alpha1store.PatchOpts) error {
+	annotations := patch.Annotations(map[string]string{v1alpha1.RemoteUnverifiedAnnotation: "down"})
+	if _, ok := annotations[v1alpha1.RemoteUnverifiedAnnotation]; ok {
+		return errors.New("annotation not cleared")
+	}
+	f.patched = append(f.patched, name+":"+tag)
+	return nil
+}
+
+func raw(t *testing.T, s *v1alpha1.MCPServer) *v1alpha1.RawObject {
+	spec, err := json.Marshal(s.Spec)
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{Metadata: s.Metadata, Spec: spec}
+}
+
+func TestRecheck(t *testing.T) {
+	store := &fakeStore{rows: []*v1alpha1.RawObject{
+		raw(t, server("dead", "https://dead.example/mcp")),
+		raw(t, server("revived", "https://revived.example/mcp")),
+	}}
+	verifier := &remoteprobe.Verifier{Probe: deadURLs{"https://dead.example/mcp": true}.probe, Store: store}
+
+	report, err := verifier.Recheck(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, remoteprobe.RecheckReport{Checked: 2, Verified: 1}, report)
+	require.Equal(t, []string{"revived:1.0.0"}, store.patched)
+}
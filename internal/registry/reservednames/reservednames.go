@@ -0,0 +1,188 @@
+// Package reservednames keeps callers from squatting reserved name
+// prefixes such as official/ or mcp/. A prefix is reserved by the server
+// configuration (RESERVED_NAME_PREFIXES) or by a registry admin through
+// /v0/reserved-prefixes; publishing an Agent, MCPServer, Skill or Prompt
+// whose name or namespace falls under one is refused unless the caller
+// has been allocated the prefix. Allocation goes through a request that a
+// registry admin approves. Registry admins and internal system calls are
+// exempt.
+//
+// The Guard is wired in as a Prepare hook, so it runs on the dedicated PUT
+// routes, the batch /v0/apply endpoint and dry-runs alike. Deletes are not
+// guarded: removing a squatted name is exactly what an admin wants.
+package reservednames
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Reserved prefix sources.
+const (
+	SourceConfig = "config"
+	SourceAdmin  = "admin"
+)
+
+// Kinds returns the kinds whose names are checked against reserved
+// prefixes.
+func Kinds() []string {
+	return []string{v1alpha1.KindAgent, v1alpha1.KindMCPServer, v1alpha1.KindSkill, v1alpha1.KindPrompt}
+}
+
+// NormalizePrefix returns prefix in its stored form: trimmed, lower-cased
+// and without a trailing "*", so "Official/*" and "official/" are the same
+// prefix.
+func NormalizePrefix(prefix string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(prefix), "*"))
+}
+
+// Matches reports whether value (a name or namespace) falls under the
+// normalized prefix: it starts with the prefix, or equals the prefix
+// without its trailing "/" so reserving "mcp/" also reserves the
+// namespace "mcp". Matching is case-insensitive.
+func Matches(prefix, value string) bool {
+	if prefix == "" {
+		return false
+	}
+	value = strings.ToLower(value)
+	return strings.HasPrefix(value, prefix) || value == strings.TrimSuffix(prefix, "/")
+}
+
+// Prefix is one reserved prefix as the guard sees it: the union of a
+// configured prefix and its admin-managed row.
+type Prefix struct {
+	Prefix string
+	Reason string
+	Owners []string
+	// Source is SourceConfig when the server configuration reserves the
+	// prefix, SourceAdmin otherwise.
+	Source string
+}
+
+// Merge unions the configured prefixes with the admin-managed rows,
+// ordered by prefix. A configured prefix with a row takes the row's
+// reason and owners.
+func Merge(configured []string, rows []*v1alpha1store.ReservedPrefix) []Prefix {
+	byPrefix := map[string]*Prefix{}
+	for _, p := range configured {
+		p = NormalizePrefix(p)
+		if p != "" {
+			byPrefix[p] = &Prefix{Prefix: p, Source: SourceConfig}
+		}
+	}
+	for _, row := range rows {
+		entry, ok := byPrefix[row.Prefix]
+		if !ok {
+			entry = &Prefix{Prefix: row.Prefix, Source: SourceAdmin}
+			byPrefix[row.Prefix] = entry
+		}
+		entry.Reason = row.Reason
+		entry.Owners = row.Owners
+	}
+	out := make([]Prefix, 0, len(byPrefix))
+	for _, p := range byPrefix {
+		out = append(out, *p)
+	}
+	slices.SortFunc(out, func(a, b Prefix) int { return strings.Compare(a.Prefix, b.Prefix) })
+	return out
+}
+
+// Store reads admin-managed reserved prefixes.
+// *v1alpha1store.ReservedPrefixStore satisfies it.
+type Store interface {
+	List(ctx context.Context) ([]*v1alpha1store.ReservedPrefix, error)
+}
+
+// ReservedError reports a publish under a reserved prefix by a caller who
+// has not been allocated it. It matches pkgdb.ErrForbidden so apply
+// handlers answer 403.
+type ReservedError struct {
+	Name   string
+	Prefix string
+}
+
+func (e *ReservedError) Error() string {
+	return fmt.Sprintf("%s is under reserved prefix %q; request an allocation through /v0/reserved-prefixes", e.Name, e.Prefix)
+}
+
+// Is makes errors.Is(err, pkgdb.ErrForbidden) hold.
+func (e *ReservedError) Is(target error) bool {
+	return target == pkgdb.ErrForbidden
+}
+
+// Guard checks published names against reserved prefixes.
+type Guard struct {
+	store      Store
+	configured []string
+	// isAdmin exempts registry admins. Nil exempts nobody.
+	isAdmin func(ctx context.Context) bool
+}
+
+// New constructs a Guard reserving the configured prefixes plus the rows
+// in store. isAdmin, typically auth.Authorizer.IsRegistryAdmin, exempts
+// registry admins.
+func New(store Store, configured []string, isAdmin func(ctx context.Context) bool) *Guard {
+	return &Guard{store: store, configured: configured, isAdmin: isAdmin}
+}
+
+// Prefixes returns every reserved prefix.
+func (g *Guard) Prefixes(ctx context.Context) ([]Prefix, error) {
+	rows, err := g.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list reserved prefixes: %w", err)
+	}
+	return Merge(g.configured, rows), nil
+}
+
+// Check returns a *ReservedError when namespace or name falls under a
+// reserved prefix the caller on ctx has not been allocated. When several
+// prefixes match, the longest decides, so owning official/acme/ is enough
+// to publish under it even though official/ is reserved too.
+func (g *Guard) Check(ctx context.Context, namespace, name string) error {
+	if session, ok := auth.AuthSessionFrom(ctx); ok && auth.IsSystemSession(session) {
+		return nil
+	}
+	if g.isAdmin != nil && g.isAdmin(ctx) {
+		return nil
+	}
+	prefixes, err := g.Prefixes(ctx)
+	if err != nil {
+		return err
+	}
+	subject := auth.SubjectFrom(ctx)
+	for _, value := range []string{namespace, name} {
+		var longest *Prefix
+		for i := range prefixes {
+			if Matches(prefixes[i].Prefix, value) && (longest == nil || len(prefixes[i].Prefix) > len(longest.Prefix)) {
+				longest = &prefixes[i]
+			}
+		}
+		if longest == nil || (subject != "" && slices.Contains(longest.Owners, subject)) {
+			continue
+		}
+		return &ReservedError{Name: value, Prefix: longest.Prefix}
+	}
+	return nil
+}
+
+// Prepare returns a Prepare hook that runs next, then refuses the write
+// when the object's name or namespace is reserved for someone else. Wire
+// it for each of Kinds().
+func (g *Guard) Prepare(next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		meta := obj.GetMetadata()
+		return g.Check(ctx, meta.NamespaceOrDefault(), meta.Name)
+	}
+}
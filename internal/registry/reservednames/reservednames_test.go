@@ -0,0 +1,88 @@
+package reservednames_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/reservednames"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type rows []*v1alpha1store.ReservedPrefix
+
+func (r rows) List(context.Context) ([]*v1alpha1store.ReservedPrefix, error) {
+	return r, nil
+}
+
+type session string
+
+func (s session) Principal() auth.Principal { return auth.Principal{Subject: string(s)} }
+
+func as(subject string) context.Context {
+	return auth.AuthSessionTo(context.Background(), session(subject))
+}
+
+func testRows() rows {
+	return rows{
+		{Prefix: "official/", Reason: "reserved for the registry"},
+		{Prefix: "official/acme/", Owners: []string{"alice"}},
+		{Prefix: "acme", Reason: "trademark", Owners: []string{"alice"}},
+	}
+}
+
+func TestNormalizeAndMatch(t *testing.T) {
+	require.Equal(t, "official/", reservednames.NormalizePrefix(" Official/* "))
+	require.True(t, reservednames.Matches("mcp/", "mcp/weather"))
+	require.True(t, reservednames.Matches("mcp/", "MCP"), "the prefix without its slash reserves the namespace")
+	require.False(t, reservednames.Matches("mcp/", "mcpx"))
+	require.True(t, reservednames.Matches("acme", "acme-tools"))
+	require.False(t, reservednames.Matches("", "anything"))
+}
+
+func TestMerge(t *testing.T) {
+	got := reservednames.Merge([]string{"mcp/*", "Official/"}, testRows())
+	require.Len(t, got, 4)
+	require.Equal(t, reservednames.Prefix{Prefix: "acme", Reason: "trademark", Owners: []string{"alice"}, Source: reservednames.SourceAdmin}, got[0])
+	require.Equal(t, reservednames.Prefix{Prefix: "mcp/", Source: reservednames.SourceConfig}, got[1])
+	require.Equal(t, reservednames.Prefix{Prefix: "official/", Reason: "reserved for the registry", Source: reservednames.SourceConfig}, got[2])
+	require.Equal(t, reservednames.SourceAdmin, got[3].Source)
+}
+
+func TestCheck(t *testing.T) {
+	guard := reservednames.New(testRows(), []string{"mcp/", "official/"}, nil)
+
+	require.NoError(t, guard.Check(as("mallory"), "default", "weather"))
+	err := guard.Check(as("mallory"), "default", "mcp/weather")
+	require.ErrorIs(t, err, pkgdb.ErrForbidden)
+	require.EqualError(t, err, `mcp/weather is under reserved prefix "mcp/"; request an allocation through /v0/reserved-prefixes`)
+	require.ErrorIs(t, guard.Check(as("mallory"), "mcp", "weather"), pkgdb.ErrForbidden)
+
+	// Owners publish under their prefix; the longest matching prefix decides.
+	require.NoError(t, guard.Check(as("alice"), "acme", "tools"))
+	require.NoError(t, guard.Check(as("alice"), "default", "official/acme/weather"))
+	require.ErrorIs(t, guard.Check(as("alice"), "default", "official/weather"), pkgdb.ErrForbidden)
+	require.ErrorIs(t, guard.Check(context.Background(), "acme", "tools"), pkgdb.ErrForbidden)
+
+	// System calls and registry admins are exempt.
+	require.NoError(t, guard.Check(auth.WithSystemContext(context.Background()), "default", "mcp/weather"))
+	admin := reservednames.New(testRows(), []string{"mcp/"}, func(context.Context) bool { return true })
+	require.NoError(t, admin.Check(as("mallory"), "default", "mcp/weather"))
+}
+
+func TestPrepare(t *testing.T) {
+	guard := reservednames.New(rows{}, []string{"official/"}, nil)
+	prepare := guard.Prepare(nil)
+
+	server := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Name: "official/weather"},
+	}
+	require.ErrorIs(t, prepare(as("mallory"), server), pkgdb.ErrForbidden)
+	server.Metadata.Name = "weather"
+	require.NoError(t, prepare(as("mallory"), server))
+}
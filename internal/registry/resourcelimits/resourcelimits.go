@@ -0,0 +1,126 @@
+// Package resourcelimits applies the registry's CPU and memory policy to
+// Deployments. A deployed container gets the requests and limits of its
+// Deployment's spec.resources, then of an Agent target's spec.resources,
+// then the server defaults; the result may not exceed the server maxima.
+// A missing limit is capped at the maximum, so with maxima configured no
+// container runs unbounded.
+package resourcelimits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// Policy holds the server defaults and maxima. The zero Policy, and a nil
+// *Policy, only merge the Deployment's and target's values.
+type Policy struct {
+	// Defaults fill in requests and limits neither the Deployment nor its
+	// target sets.
+	Defaults v1alpha1.ResourceRequirements
+	// Max caps every request and limit.
+	Max v1alpha1.ComputeResources
+}
+
+// Resolve returns the requirements a Deployment of target gets when it
+// asks for own in its spec.resources, or nil when nothing is set. Values
+// over Max, and requests over their limit, are errors naming the value.
+func (p *Policy) Resolve(own *v1alpha1.ResourceRequirements, target v1alpha1.Object) (*v1alpha1.ResourceRequirements, error) {
+	layers := []*v1alpha1.ResourceRequirements{own}
+	if agent, ok := target.(*v1alpha1.Agent); ok && agent.Spec.Resources != nil {
+		layers = append(layers, &v1alpha1.ResourceRequirements{
+			Requests: agent.Spec.Resources.Requests,
+			Limits:   agent.Spec.Resources.Limits,
+		})
+	}
+	if p == nil {
+		return v1alpha1.MergeResourceRequirements(layers...), nil
+	}
+	layers = append(layers, &p.Defaults, &v1alpha1.ResourceRequirements{Limits: &p.Max})
+	out := v1alpha1.MergeResourceRequirements(layers...)
+	if out == nil {
+		return nil, nil
+	}
+
+	var requests, limits v1alpha1.ComputeResources
+	if out.Requests != nil {
+		requests = *out.Requests
+	}
+	if out.Limits != nil {
+		limits = *out.Limits
+	}
+	checks := []struct {
+		resource, field, value, boundName, bound string
+		parse                                    func(string) (int64, error)
+	}{
+		{"cpu", "requests", requests.CPU, "server maximum", p.Max.CPU, v1alpha1.ParseCPU},
+		{"memory", "requests", requests.Memory, "server maximum", p.Max.Memory, v1alpha1.ParseMemory},
+		{"cpu", "limits", limits.CPU, "server maximum", p.Max.CPU, v1alpha1.ParseCPU},
+		{"memory", "limits", limits.Memory, "server maximum", p.Max.Memory, v1alpha1.ParseMemory},
+		{"cpu", "requests", requests.CPU, "limit", limits.CPU, v1alpha1.ParseCPU},
+		{"memory", "requests", requests.Memory, "limit", limits.Memory, v1alpha1.ParseMemory},
+	}
+	for _, c := range checks {
+		if err := checkAtMost(c.resource, c.value, c.field, c.bound, c.boundName, c.parse); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// checkAtMost fails when value is above bound. Either may be unset.
+func checkAtMost(resource, value, field, bound, boundName string, parse func(string) (int64, error)) error {
+	if value == "" || bound == "" {
+		return nil
+	}
+	v, err := parse(value)
+	if err != nil {
+		return err
+	}
+	b, err := parse(bound)
+	if err != nil {
+		return err
+	}
+	if v > b {
+		return fmt.Errorf("resources.%s.%s %s exceeds the %s %s", field, resource, value, boundName, bound)
+	}
+	return nil
+}
+
+// Prepare returns a Deployment Prepare hook that runs next, then resolves
+// the Deployment's target through getter and refuses requirements Resolve
+// rejects with 422. Deployments whose target does not resolve are left to
+// the reference checks.
+func (p *Policy) Prepare(getter v1alpha1.GetterFunc, next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		deployment, ok := obj.(*v1alpha1.Deployment)
+		if !ok || v1alpha1.IsDiscoveredDeployment(deployment) || deployment.Spec.TargetRef.Kind == v1alpha1.KindChart {
+			return nil
+		}
+		ref := deployment.Spec.TargetRef
+		if ref.Namespace == "" {
+			ref.Namespace = deployment.Metadata.NamespaceOrDefault()
+		}
+		target, err := getter(ctx, ref)
+		if err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) || errors.Is(err, v1alpha1.ErrDanglingRef) {
+				return nil
+			}
+			return fmt.Errorf("resolve targetRef %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		if _, err := p.Resolve(deployment.Spec.Resources, target); err != nil {
+			return huma.Error422UnprocessableEntity(err.Error())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,100 @@
+package resourcelimits_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/resourcelimits"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+func agentWith(resources *v1alpha1.AgentResources) *v1alpha1.Agent {
+	return &v1alpha1.Agent{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "alice", Tag: "stable"},
+		Spec:     v1alpha1.AgentSpec{Resources: resources},
+	}
+}
+
+func TestResolve_Layers(t *testing.T) {
+	policy := &resourcelimits.Policy{
+		Defaults: v1alpha1.ResourceRequirements{
+			Requests: &v1alpha1.ComputeResources{CPU: "100m", Memory: "128Mi"},
+		},
+		Max: v1alpha1.ComputeResources{CPU: "2", Memory: "2Gi"},
+	}
+	agent := agentWith(&v1alpha1.AgentResources{
+		Requests: &v1alpha1.ComputeResources{Memory: "512Mi"},
+		Limits:   &v1alpha1.ComputeResources{Memory: "1Gi"},
+	})
+	own := &v1alpha1.ResourceRequirements{Limits: &v1alpha1.ComputeResources{CPU: "1"}}
+
+	got, err := policy.Resolve(own, agent)
+	require.NoError(t, err)
+	require.Equal(t, &v1alpha1.ResourceRequirements{
+		Requests: &v1alpha1.ComputeResources{CPU: "100m", Memory: "512Mi"},
+		Limits:   &v1alpha1.ComputeResources{CPU: "1", Memory: "1Gi"},
+	}, got)
+
+	// Without a Deployment or Agent value, limits are capped at the maximum.
+	got, err = policy.Resolve(nil, &v1alpha1.MCPServer{})
+	require.NoError(t, err)
+	require.Equal(t, &v1alpha1.ComputeResources{CPU: "2", Memory: "2Gi"}, got.Limits)
+}
+
+func TestResolve_Errors(t *testing.T) {
+	policy := &resourcelimits.Policy{Max: v1alpha1.ComputeResources{CPU: "2", Memory: "2Gi"}}
+
+	_, err := policy.Resolve(&v1alpha1.ResourceRequirements{Limits: &v1alpha1.ComputeResources{CPU: "4"}}, nil)
+	require.ErrorContains(t, err, "resources.limits.cpu 4 exceeds the server maximum 2")
+
+	_, err = policy.Resolve(&v1alpha1.ResourceRequirements{Requests: &v1alpha1.ComputeResources{Memory: "3Gi"}}, nil)
+	require.ErrorContains(t, err, "resources.requests.memory 3Gi exceeds the server maximum 2Gi")
+
+	// A Deployment's request may not exceed the limit its Agent sets.
+	agent := agentWith(&v1alpha1.AgentResources{Limits: &v1alpha1.ComputeResources{CPU: "500m"}})
+	var none *resourcelimits.Policy
+	_, err = none.Resolve(&v1alpha1.ResourceRequirements{Requests: &v1alpha1.ComputeResources{CPU: "1"}}, agent)
+	require.NoError(t, err, "a nil policy only merges")
+	_, err = policy.Resolve(&v1alpha1.ResourceRequirements{Requests: &v1alpha1.ComputeResources{CPU: "1"}}, agent)
+	require.ErrorContains(t, err, "resources.requests.cpu 1 exceeds the limit 500m")
+}
+
+func TestPrepare(t *testing.T) {
+	ctx := context.Background()
+	policy := &resourcelimits.Policy{Max: v1alpha1.ComputeResources{Memory: "1Gi"}}
+	getter := func(_ context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		if ref.Namespace == "default" && ref.Name == "alice" {
+			return agentWith(nil), nil
+		}
+		return nil, pkgdb.ErrNotFound
+	}
+	var nextRan bool
+	prepare := policy.Prepare(getter, func(context.Context, v1alpha1.Object) error {
+		nextRan = true
+		return nil
+	})
+	deployment := func(target string, memory string) *v1alpha1.Deployment {
+		return &v1alpha1.Deployment{
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "prod"},
+			Spec: v1alpha1.DeploymentSpec{
+				TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: target, Tag: "stable"},
+				RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "local"},
+				Resources:  &v1alpha1.ResourceRequirements{Limits: &v1alpha1.ComputeResources{Memory: memory}},
+			},
+		}
+	}
+
+	require.NoError(t, prepare(ctx, deployment("alice", "512Mi")))
+	require.True(t, nextRan)
+	require.NoError(t, prepare(ctx, deployment("missing", "4Gi")), "unresolved targets are left to the reference checks")
+
+	err := prepare(ctx, deployment("alice", "4Gi"))
+	var se huma.StatusError
+	require.ErrorAs(t, err, &se)
+	require.Equal(t, http.StatusUnprocessableEntity, se.GetStatus())
+}
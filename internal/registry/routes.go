@@ -0,0 +1,52 @@
+package registry
+
+import (
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/handlers/v0/crud"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/api/router"
+	internaldb "github.com/agentregistry-dev/agentregistry/internal/registry/database"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/peers"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/secrets"
+	deploymentsvc "github.com/agentregistry-dev/agentregistry/internal/registry/service/deployment"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+func buildRouteOptions(
+	options types.AppOptions,
+	stores map[string]*v1alpha1store.Store,
+	adapters map[string]types.DeploymentAdapter,
+	perKindHooks crud.PerKindHooks,
+	peerRegistry *peers.Registry,
+	sealer *secrets.Sealer,
+) *router.RouteOptions {
+	routeOpts := &router.RouteOptions{
+		ExtraRoutes:       options.ExtraRoutes,
+		Stores:            stores,
+		PerKindHooks:      perKindHooks,
+		RegistryValidator: options.RegistryValidator,
+		Admission:         options.Admission,
+		DeleteAdmission:   options.DeleteAdmission,
+		// Peer refs are settled before any caller-supplied wrapper sees
+		// the resolver.
+		ResolverWrapper: func(resolver v1alpha1.ResolverFunc) v1alpha1.ResolverFunc {
+			resolver = peerRegistry.Resolver(resolver)
+			if options.ResolverWrapper != nil {
+				resolver = options.ResolverWrapper(resolver)
+			}
+			return resolver
+		},
+		ExtraResourceRoutes: options.ExtraResourceRoutes,
+	}
+
+	if stores != nil {
+		adapterResolver := deploymentsvc.NewAdapterResolver(deploymentsvc.ResolverDependencies{
+			Adapters: adapters,
+			Getter:   sealer.Getter(peerRegistry.Getter(internaldb.NewGetter(stores))),
+		})
+		routeOpts.DeploymentLogResolver = adapterResolver
+		routeOpts.DeploymentMCPEndpoints = adapterResolver
+	}
+
+	return routeOpts
+}
@@ -0,0 +1,180 @@
+// Package runtimeaccess restricts who may deploy to a Runtime. A registry
+// admin grants a Runtime (e.g. prod-k8s) to a list of deployers through
+// /v0/runtime-grants; from then on only those subjects may deploy to it,
+// undeploy from it or delete a Deployment on it. Runtimes without a grant
+// stay open to anyone the Deployment authorizer lets through. Registry
+// admins and internal system calls are exempt.
+//
+// The Guard complements the per-kind Deployment authorizer, which only
+// sees the Deployment itself: it runs as a Prepare hook, after the
+// scheduler has resolved a runtimeSelector to a runtimeRef, and as a
+// delete admission.
+package runtimeaccess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// Store reads Runtime grants. *v1alpha1store.RuntimeGrantStore satisfies
+// it.
+type Store interface {
+	Get(ctx context.Context, namespace, runtime string) (*v1alpha1store.RuntimeGrant, error)
+}
+
+// NotGrantedError reports a deploy to a granted Runtime by a caller who is
+// not one of its deployers. It matches pkgdb.ErrForbidden so apply
+// handlers answer 403.
+type NotGrantedError struct {
+	Namespace string
+	Runtime   string
+	Subject   string
+}
+
+func (e *NotGrantedError) Error() string {
+	who := e.Subject
+	if who == "" {
+		who = "anonymous callers"
+	}
+	return fmt.Sprintf("%s may not deploy to Runtime %s/%s; ask a registry admin for a grant through /v0/runtime-grants", who, e.Namespace, e.Runtime)
+}
+
+// Is makes errors.Is(err, pkgdb.ErrForbidden) hold.
+func (e *NotGrantedError) Is(target error) bool {
+	return target == pkgdb.ErrForbidden
+}
+
+// Guard checks Deployment writes against Runtime grants.
+type Guard struct {
+	store Store
+	// isAdmin exempts registry admins. Nil exempts nobody.
+	isAdmin func(ctx context.Context) bool
+	// getter loads the stored Deployment, so moving one off a granted
+	// Runtime is checked too. Nil skips that check.
+	getter v1alpha1.GetterFunc
+}
+
+// New constructs a Guard. isAdmin, typically
+// auth.Authorizer.IsRegistryAdmin, exempts registry admins; getter loads
+// the Deployment a write replaces.
+func New(store Store, isAdmin func(ctx context.Context) bool, getter v1alpha1.GetterFunc) *Guard {
+	return &Guard{store: store, isAdmin: isAdmin, getter: getter}
+}
+
+// Check returns a *NotGrantedError when the Runtime namespace/runtime has
+// a grant that does not list the caller on ctx.
+func (g *Guard) Check(ctx context.Context, namespace, runtime string) error {
+	if session, ok := auth.AuthSessionFrom(ctx); ok && auth.IsSystemSession(session) {
+		return nil
+	}
+	if g.isAdmin != nil && g.isAdmin(ctx) {
+		return nil
+	}
+	grant, err := g.store.Get(ctx, namespace, runtime)
+	if errors.Is(err, pkgdb.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get runtime grant %s/%s: %w", namespace, runtime, err)
+	}
+	subject := auth.SubjectFrom(ctx)
+	if grant.IsDeployer(subject) {
+		return nil
+	}
+	return &NotGrantedError{Namespace: namespace, Runtime: runtime, Subject: subject}
+}
+
+// checkDeployment checks the Runtime deployment runs on. A Deployment
+// whose Runtime is still unresolved has nothing to check.
+func (g *Guard) checkDeployment(ctx context.Context, deployment *v1alpha1.Deployment) error {
+	namespace, runtime, ok := runtimeOf(deployment)
+	if !ok {
+		return nil
+	}
+	return g.Check(ctx, namespace, runtime)
+}
+
+// Prepare returns a Deployment Prepare hook that runs next, then refuses
+// the write unless the caller may deploy to the Deployment's Runtime.
+// Deploys and undeploys are both writes, so both are checked; moving a
+// Deployment to another Runtime also needs the Runtime it leaves.
+func (g *Guard) Prepare(next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		deployment, ok := obj.(*v1alpha1.Deployment)
+		if !ok {
+			return nil
+		}
+		if err := g.checkDeployment(ctx, deployment); err != nil {
+			return err
+		}
+		if g.getter == nil {
+			return nil
+		}
+		existing, err := g.getter(ctx, v1alpha1.ResourceRef{
+			Kind:      v1alpha1.KindDeployment,
+			Namespace: deployment.Metadata.NamespaceOrDefault(),
+			Name:      deployment.Metadata.Name,
+		})
+		if errors.Is(err, v1alpha1.ErrDanglingRef) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("get deployment %s: %w", deployment.Metadata.Name, err)
+		}
+		previous, ok := existing.(*v1alpha1.Deployment)
+		if !ok {
+			return nil
+		}
+		prevNamespace, prevRuntime, ok := runtimeOf(previous)
+		newNamespace, newRuntime, _ := runtimeOf(deployment)
+		if !ok || (prevNamespace == newNamespace && prevRuntime == newRuntime) {
+			return nil
+		}
+		return g.Check(ctx, prevNamespace, prevRuntime)
+	}
+}
+
+// DeleteAdmission returns a delete admission that refuses Deployment
+// deletes unless the caller may deploy to the Deployment's Runtime, and
+// otherwise defers to next, or to resource.ProductionDeleteAdmission when
+// next is nil.
+func (g *Guard) DeleteAdmission(next types.DeleteAdmission) types.DeleteAdmission {
+	if next == nil {
+		next = resource.ProductionDeleteAdmission
+	}
+	return func(ctx context.Context, in types.DeleteAdmissionInput) (types.DeleteAdmissionResult, error) {
+		if deployment, ok := in.Object.(*v1alpha1.Deployment); ok && in.Kind == v1alpha1.KindDeployment {
+			if err := g.checkDeployment(ctx, deployment); err != nil {
+				return types.DeleteAdmissionResult{}, err
+			}
+		}
+		return next(ctx, in)
+	}
+}
+
+// runtimeOf returns the namespace and name of the Runtime deployment
+// references, defaulting the namespace to the Deployment's.
+func runtimeOf(deployment *v1alpha1.Deployment) (namespace, runtime string, ok bool) {
+	ref := deployment.Spec.RuntimeRef
+	if ref.Name == "" || ref.Registry != "" {
+		return "", "", false
+	}
+	namespace = ref.Namespace
+	if namespace == "" {
+		namespace = deployment.Metadata.NamespaceOrDefault()
+	}
+	return namespace, ref.Name, true
+}
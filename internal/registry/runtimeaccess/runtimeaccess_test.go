@@ -0,0 +1,126 @@
+package runtimeaccess_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimeaccess"
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// grants maps namespace/runtime to its deployers.
+type grants map[string][]string
+
+func (g grants) Get(_ context.Context, namespace, runtime string) (*v1alpha1store.RuntimeGrant, error) {
+	deployers, ok := g[namespace+"/"+runtime]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	return &v1alpha1store.RuntimeGrant{Namespace: namespace, Runtime: runtime, Deployers: deployers}, nil
+}
+
+type session string
+
+func (s session) Principal() auth.Principal { return auth.Principal{Subject: string(s)} }
+
+func as(subject string) context.Context {
+	return auth.AuthSessionTo(context.Background(), session(subject))
+}
+
+func testGrants() grants {
+	return grants{"default/prod-k8s": {"ops"}, "default/locked": nil}
+}
+
+func deployment(name, runtime string) *v1alpha1.Deployment {
+	return &v1alpha1.Deployment{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindDeployment},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "bot", Tag: "1.0.0"},
+			RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: runtime},
+		},
+	}
+}
+
+// stored returns a getter serving the given Deployments by name.
+func stored(deployments ...*v1alpha1.Deployment) v1alpha1.GetterFunc {
+	return func(_ context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		for _, d := range deployments {
+			if d.Metadata.Name == ref.Name {
+				return d, nil
+			}
+		}
+		return nil, v1alpha1.ErrDanglingRef
+	}
+}
+
+func TestCheck(t *testing.T) {
+	guard := runtimeaccess.New(testGrants(), nil, nil)
+
+	require.NoError(t, guard.Check(as("dev"), "default", "dev-k8s"), "runtimes without a grant are open")
+	require.NoError(t, guard.Check(as("ops"), "default", "prod-k8s"))
+	err := guard.Check(as("dev"), "default", "prod-k8s")
+	require.ErrorIs(t, err, pkgdb.ErrForbidden)
+	require.EqualError(t, err, "dev may not deploy to Runtime default/prod-k8s; ask a registry admin for a grant through /v0/runtime-grants")
+	require.ErrorIs(t, guard.Check(as("ops"), "default", "locked"), pkgdb.ErrForbidden, "an empty grant leaves the runtime to admins")
+	require.ErrorIs(t, guard.Check(context.Background(), "default", "prod-k8s"), pkgdb.ErrForbidden)
+
+	// System calls and registry admins are exempt.
+	require.NoError(t, guard.Check(auth.WithSystemContext(context.Background()), "default", "locked"))
+	admin := runtimeaccess.New(testGrants(), func(context.Context) bool { return true }, nil)
+	require.NoError(t, admin.Check(as("dev"), "default", "locked"))
+}
+
+func TestPrepare(t *testing.T) {
+	var nextCalls int
+	next := func(context.Context, v1alpha1.Object) error {
+		nextCalls++
+		return nil
+	}
+	guard := runtimeaccess.New(testGrants(), nil, stored(deployment("bot-prod", "prod-k8s"), deployment("bot-dev", "dev-k8s")))
+	prepare := guard.Prepare(next)
+
+	require.NoError(t, prepare(as("dev"), deployment("bot-new", "dev-k8s")))
+	require.ErrorIs(t, prepare(as("dev"), deployment("bot-new", "prod-k8s")), pkgdb.ErrForbidden)
+	require.NoError(t, prepare(as("ops"), deployment("bot-new", "prod-k8s")))
+
+	undeploy := deployment("bot-prod", "prod-k8s")
+	undeploy.Spec.DesiredState = v1alpha1.DesiredStateUndeployed
+	require.ErrorIs(t, prepare(as("dev"), undeploy), pkgdb.ErrForbidden)
+
+	// Moving a Deployment needs the Runtime it leaves as well.
+	require.ErrorIs(t, prepare(as("dev"), deployment("bot-prod", "dev-k8s")), pkgdb.ErrForbidden)
+	require.NoError(t, prepare(as("ops"), deployment("bot-prod", "dev-k8s")))
+	require.ErrorIs(t, prepare(as("dev"), deployment("bot-dev", "prod-k8s")), pkgdb.ErrForbidden)
+
+	// Unresolved runtimes and other kinds pass through.
+	require.NoError(t, prepare(as("dev"), deployment("bot-new", "")))
+	require.NoError(t, prepare(as("dev"), &v1alpha1.Agent{Metadata: v1alpha1.ObjectMeta{Name: "bot"}}))
+	require.Equal(t, 9, nextCalls)
+}
+
+func TestDeleteAdmission(t *testing.T) {
+	var deleted []string
+	admit := runtimeaccess.New(testGrants(), nil, nil).DeleteAdmission(func(_ context.Context, in types.DeleteAdmissionInput) (types.DeleteAdmissionResult, error) {
+		deleted = append(deleted, in.Name)
+		return types.DeleteAdmissionResult{Status: arv0.ApplyStatusDeleted}, nil
+	})
+
+	_, err := admit(as("dev"), types.DeleteAdmissionInput{Kind: v1alpha1.KindDeployment, Name: "bot-prod", Object: deployment("bot-prod", "prod-k8s")})
+	require.ErrorIs(t, err, pkgdb.ErrForbidden)
+
+	res, err := admit(as("ops"), types.DeleteAdmissionInput{Kind: v1alpha1.KindDeployment, Name: "bot-prod", Object: deployment("bot-prod", "prod-k8s")})
+	require.NoError(t, err)
+	require.Equal(t, arv0.ApplyStatusDeleted, res.Status)
+
+	_, err = admit(as("dev"), types.DeleteAdmissionInput{Kind: v1alpha1.KindRuntime, Name: "prod-k8s"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"bot-prod", "prod-k8s"}, deleted)
+}
@@ -0,0 +1,208 @@
+// Package frameworks describes how the runtime image of each agent
+// framework is laid out, so the local (compose) and kubernetes (kagent)
+// translators can run agents of any framework without framework-specific
+// code. Agents name their framework through the v1alpha1.AgentFrameworkLabel
+// and v1alpha1.AgentLanguageLabel labels `arctl apply` injects from
+// arctl.yaml; agents without them, or with a framework no descriptor
+// matches, run with the built-in ADK (Python) layout.
+//
+// Further descriptors are loaded from a YAML file
+// (AGENT_REGISTRY_AGENT_FRAMEWORKS_FILE) and take precedence over the
+// built-in ones:
+//
+//	frameworks:
+//	  - framework: langgraph
+//	    language: python
+//	    image: ghcr.io/acme/langgraph-runtime:1.4
+//	    command: ["python", "-m", "runtime"]
+//	    args: ["--agent", "{{.Name}}", "--port", "{{.Port}}"]
+//	    port: 8000
+//	    configMountPath: /app/config
+//	    env:
+//	      PYTHONUNBUFFERED: "1"
+//
+// command, args and localArgs are Go templates over {{.Name}} (the Agent
+// name) and {{.Port}} (the port the agent listens on).
+package frameworks
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"text/template"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// DefaultConfigMountPath is where agent config files such as prompts.json
+// are mounted when a descriptor does not say otherwise.
+const DefaultConfigMountPath = "/config"
+
+// Descriptor is the runtime image layout of one framework and language.
+type Descriptor struct {
+	Framework string `json:"framework"`
+	Language  string `json:"language"`
+	// Image runs agents that declare no spec.source.image, for frameworks
+	// whose runtime image loads the agent from its config.
+	Image string `json:"image,omitempty"`
+	// Command replaces the image entrypoint. Empty keeps it.
+	Command []string `json:"command,omitempty"`
+	// Args are passed to the entrypoint on every runtime.
+	Args []string `json:"args,omitempty"`
+	// LocalArgs replace Args on the local runtime, where the agent runs
+	// without a kagent controller. nil falls back to Args.
+	LocalArgs []string `json:"localArgs,omitempty"`
+	// Port is the port the agent serves A2A on. 0 means 8080.
+	Port uint16 `json:"port,omitempty"`
+	// ConfigMountPath is where agent config files are mounted. Empty means
+	// DefaultConfigMountPath.
+	ConfigMountPath string `json:"configMountPath,omitempty"`
+	// Env are defaults for the agent's environment; Deployment env and the
+	// variables the registry sets take precedence.
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// ADK is the layout of kagent's ADK (Python) runtime images, which every
+// agent used before frameworks could be described.
+var ADK = Descriptor{
+	Framework: "adk",
+	Language:  "python",
+	LocalArgs: []string{"{{.Name}}", "--local", "--port", "{{.Port}}"},
+	Port:      8080,
+}
+
+// Registry looks up descriptors by framework and language.
+type Registry struct {
+	descriptors map[string]Descriptor
+}
+
+// File is the format of the descriptor file.
+type File struct {
+	Frameworks []Descriptor `json:"frameworks"`
+}
+
+// NewRegistry returns a Registry of the built-in descriptors overlaid with
+// extra, which replace built-ins of the same framework and language.
+func NewRegistry(extra ...Descriptor) (*Registry, error) {
+	r := &Registry{descriptors: map[string]Descriptor{key(ADK.Framework, ADK.Language): ADK}}
+	seen := map[string]bool{}
+	for i, d := range extra {
+		if d.Framework == "" || d.Language == "" {
+			return nil, fmt.Errorf("framework %d: framework and language are required", i)
+		}
+		k := key(d.Framework, d.Language)
+		if seen[k] {
+			return nil, fmt.Errorf("framework %s/%s is described twice", d.Framework, d.Language)
+		}
+		seen[k] = true
+		for field, args := range map[string][]string{"command": d.Command, "args": d.Args, "localArgs": d.LocalArgs} {
+			if _, err := render(args, "", 0); err != nil {
+				return nil, fmt.Errorf("framework %s/%s: %s: %w", d.Framework, d.Language, field, err)
+			}
+		}
+		r.descriptors[k] = d
+	}
+	return r, nil
+}
+
+// LoadFile reads descriptors from a YAML file into a Registry.
+func LoadFile(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read agent frameworks %s: %w", path, err)
+	}
+	var f File
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("parse agent frameworks %s: %w", path, err)
+	}
+	r, err := NewRegistry(f.Frameworks...)
+	if err != nil {
+		return nil, fmt.Errorf("agent frameworks %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Len is the number of descriptors, built-in ones included.
+func (r *Registry) Len() int {
+	if r == nil {
+		return 1
+	}
+	return len(r.descriptors)
+}
+
+// For returns the descriptor of the framework the Agent's labels name, or
+// ADK when they name none this Registry knows. A nil Registry knows only
+// the built-in descriptors.
+func (r *Registry) For(meta v1alpha1.ObjectMeta) Descriptor {
+	if r == nil {
+		return ADK
+	}
+	if d, ok := r.descriptors[key(meta.Labels[v1alpha1.AgentFrameworkLabel], meta.Labels[v1alpha1.AgentLanguageLabel])]; ok {
+		return d
+	}
+	return ADK
+}
+
+// OrDefault returns *d, or ADK when d is nil.
+func OrDefault(d *Descriptor) Descriptor {
+	if d == nil {
+		return ADK
+	}
+	return *d
+}
+
+// AgentPort is the port agents of the framework listen on.
+func (d Descriptor) AgentPort() uint16 {
+	if d.Port == 0 {
+		return ADK.Port
+	}
+	return d.Port
+}
+
+// ConfigPath is where agent config files are mounted.
+func (d Descriptor) ConfigPath() string {
+	if d.ConfigMountPath == "" {
+		return DefaultConfigMountPath
+	}
+	return d.ConfigMountPath
+}
+
+// Entrypoint renders Command for the named agent.
+func (d Descriptor) Entrypoint(name string, port uint16) ([]string, error) {
+	return render(d.Command, name, port)
+}
+
+// Arguments renders the entrypoint arguments for the named agent. local
+// selects LocalArgs when the descriptor has them.
+func (d Descriptor) Arguments(name string, port uint16, local bool) ([]string, error) {
+	if local && d.LocalArgs != nil {
+		return render(d.LocalArgs, name, port)
+	}
+	return render(d.Args, name, port)
+}
+
+func render(args []string, name string, port uint16) ([]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	vars := map[string]any{"Name": name, "Port": port}
+	out := make([]string, 0, len(args))
+	for _, arg := range args {
+		tmpl, err := template.New("arg").Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("parse %q: %w", arg, err)
+		}
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, vars); err != nil {
+			return nil, fmt.Errorf("render %q: %w", arg, err)
+		}
+		out = append(out, b.String())
+	}
+	return out, nil
+}
+
+func key(framework, language string) string {
+	return framework + "/" + language
+}
@@ -0,0 +1,76 @@
+package frameworks_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/frameworks"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+func labels(framework, language string) v1alpha1.ObjectMeta {
+	return v1alpha1.ObjectMeta{Labels: map[string]string{
+		v1alpha1.AgentFrameworkLabel: framework,
+		v1alpha1.AgentLanguageLabel:  language,
+	}}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "frameworks.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`frameworks:
+  - framework: langgraph
+    language: python
+    command: ["python", "-m", "runtime"]
+    args: ["--agent", "{{.Name}}", "--port", "{{.Port}}"]
+    port: 8000
+    configMountPath: /app/config
+`), 0o600))
+	r, err := frameworks.LoadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, r.Len())
+
+	d := r.For(labels("langgraph", "python"))
+	require.Equal(t, uint16(8000), d.AgentPort())
+	require.Equal(t, "/app/config", d.ConfigPath())
+	entrypoint, err := d.Entrypoint("planner", d.AgentPort())
+	require.NoError(t, err)
+	require.Equal(t, []string{"python", "-m", "runtime"}, entrypoint)
+	args, err := d.Arguments("planner", d.AgentPort(), true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"--agent", "planner", "--port", "8000"}, args)
+
+	require.Equal(t, frameworks.ADK, r.For(labels("langgraph", "go")))
+	require.Equal(t, frameworks.ADK, r.For(v1alpha1.ObjectMeta{}))
+}
+
+func TestADK(t *testing.T) {
+	var r *frameworks.Registry
+	d := r.For(labels("adk", "python"))
+	require.Equal(t, "/config", d.ConfigPath())
+	local, err := d.Arguments("summarizer", d.AgentPort(), true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"summarizer", "--local", "--port", "8080"}, local)
+	remote, err := d.Arguments("summarizer", d.AgentPort(), false)
+	require.NoError(t, err)
+	require.Empty(t, remote, "kagent runs ADK images with their own entrypoint")
+}
+
+func TestNewRegistryErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		descriptors []frameworks.Descriptor
+		err         string
+	}{
+		"no language": {[]frameworks.Descriptor{{Framework: "langgraph"}}, "framework and language are required"},
+		"duplicate":   {[]frameworks.Descriptor{{Framework: "a", Language: "go"}, {Framework: "a", Language: "go"}}, "a/go is described twice"},
+		"bad args":    {[]frameworks.Descriptor{{Framework: "a", Language: "go", Args: []string{"{{.Agent}}"}}}, `args: render "{{.Agent}}"`},
+		"bad command": {[]frameworks.Descriptor{{Framework: "a", Language: "go", Command: []string{"{{"}}}, "command: parse"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := frameworks.NewRegistry(tc.descriptors...)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
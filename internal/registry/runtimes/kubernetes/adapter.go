@@ -3,10 +3,15 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"maps"
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	"github.com/agentregistry-dev/agentregistry/internal/constants"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/frameworks"
 	runtimetypes "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/types"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/utils"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
@@ -17,23 +22,37 @@ import (
 // Kubernetes cluster. Stateless — each Apply/Remove builds a fresh
 // controller-runtime client from the supplied v1alpha1.Runtime's Spec.Config
 // map.
-type kubernetesDeploymentAdapter struct{}
+type kubernetesDeploymentAdapter struct {
+	mutators   []types.ManifestMutator
+	frameworks *frameworks.Registry
+	helm       helmClient
+}
 
 // NewKubernetesDeploymentAdapter constructs an adapter that resolves
 // every per-call target cluster from the supplied v1alpha1.Runtime's
-// Spec.Config map.
-func NewKubernetesDeploymentAdapter() *kubernetesDeploymentAdapter {
-	return &kubernetesDeploymentAdapter{}
+// Spec.Config map. mutators edit the translated resources, in order,
+// before they are applied.
+func NewKubernetesDeploymentAdapter(mutators ...types.ManifestMutator) *kubernetesDeploymentAdapter {
+	return &kubernetesDeploymentAdapter{mutators: mutators, helm: helmCLI{binary: "helm"}}
+}
+
+// WithFrameworks sets the descriptors the adapter picks agent image
+// layouts from. Without it only the built-in ones are known.
+func (a *kubernetesDeploymentAdapter) WithFrameworks(r *frameworks.Registry) *kubernetesDeploymentAdapter {
+	a.frameworks = r
+	return a
 }
 
 func (a *kubernetesDeploymentAdapter) Type() string { return v1alpha1.TypeKubernetes }
 
 // SupportedTargetKinds reports the v1alpha1 Kinds this adapter can
-// deploy: Agent and MCPServer (bundled or remote via Spec.Remote).
+// deploy: Agent, MCPServer (bundled or remote via Spec.Remote), and Chart
+// (installed as a Helm release).
 func (a *kubernetesDeploymentAdapter) SupportedTargetKinds() []string {
 	return []string{
 		v1alpha1.KindAgent,
 		v1alpha1.KindMCPServer,
+		v1alpha1.KindChart,
 	}
 }
 
@@ -48,21 +67,51 @@ func (a *kubernetesDeploymentAdapter) Apply(ctx context.Context, in types.ApplyI
 	}
 	namespace := namespaceFromV1Alpha1(in.Deployment, in.Runtime)
 
-	desired, err := a.buildDesiredStateFromV1Alpha1(ctx, in, namespace)
+	var releases []helmRelease
+	switch target := in.Target.(type) {
+	case *v1alpha1.Chart:
+		return a.applyChart(ctx, in, namespace, target)
+	case *v1alpha1.Agent:
+		// Supporting infrastructure goes in first so the agent starts
+		// against a ready dependency set.
+		var err error
+		if releases, err = a.installAgentCharts(ctx, in, namespace, target); err != nil {
+			return nil, err
+		}
+	}
+
+	// The recorded manifests carry redacted secrets; only a Deployment that
+	// reads secrets is translated a second time with their values.
+	var readsSecrets bool
+	cfg, err := a.translate(ctx, in, namespace, func(_ context.Context, ref v1alpha1.HeaderSecretRef) (string, error) {
+		readsSecrets = true
+		return redactedSecret(ref), nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	cfg, err := kubernetesTranslateRuntimeConfig(ctx, desired)
+	// Render before applying: the client overwrites the objects with
+	// server state, dropping TypeMeta and adding managed fields.
+	manifests, err := renderKubernetesManifests(cfg, releases)
 	if err != nil {
-		return nil, fmt.Errorf("translate kubernetes runtime config: %w", err)
+		return nil, err
 	}
-	if cfg == nil {
-		return nil, fmt.Errorf("kubernetes runtime config is required")
+	if readsSecrets {
+		if cfg, err = a.translate(ctx, in, namespace, kubernetesSecretLookup(in.Runtime, namespace)); err != nil {
+			return nil, err
+		}
 	}
 	if err := kubernetesApplyRuntimeConfig(ctx, in.Runtime, cfg, false); err != nil {
 		return nil, fmt.Errorf("apply kubernetes runtime config: %w", err)
 	}
 
+	metadata := utils.CostAllocationMetadata(v1alpha1.CostAllocationTags(in.Deployment, in.Runtime))
+	endpoint := ""
+	if agent, ok := in.Target.(*v1alpha1.Agent); ok {
+		endpoint = kubernetesAgentURL(agent, in.Deployment.Metadata.Name, namespace)
+	}
+	maps.Copy(metadata, utils.EndpointMetadata(endpoint))
+
 	now := time.Now().UTC()
 	gen := in.Deployment.Metadata.Generation
 	return &types.ApplyResult{
@@ -81,13 +130,72 @@ func (a *kubernetesDeploymentAdapter) Apply(ctx context.Context, in types.ApplyI
 			LastTransitionTime: now,
 			ObservedGeneration: gen,
 		}},
+		RuntimeMetadata: metadata,
+		Manifests:       manifests,
 	}, nil
 }
 
+// Render translates the Deployment exactly as Apply does and returns the
+// kagent/kmcp resources and Helm releases it would submit. Nothing is sent
+// to the cluster.
+func (a *kubernetesDeploymentAdapter) Render(ctx context.Context, in types.ApplyInput) ([]types.RenderedManifest, error) {
+	if in.Deployment == nil {
+		return nil, fmt.Errorf("render: deployment is required")
+	}
+	namespace := namespaceFromV1Alpha1(in.Deployment, in.Runtime)
+
+	var releases []helmRelease
+	switch target := in.Target.(type) {
+	case *v1alpha1.Chart:
+		release, err := deploymentChartRelease(in.Deployment, namespace, target)
+		if err != nil {
+			return nil, err
+		}
+		return renderKubernetesManifests(nil, []helmRelease{release})
+	case *v1alpha1.Agent:
+		var err error
+		if releases, err = agentChartReleases(ctx, in, namespace, target); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg, err := a.translate(ctx, in, namespace, func(_ context.Context, ref v1alpha1.HeaderSecretRef) (string, error) {
+		return redactedSecret(ref), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return renderKubernetesManifests(cfg, releases)
+}
+
+// translate builds the kagent/kmcp resources for an Agent or MCPServer
+// target, filling in remote header secrets through secrets, labels them
+// with the Deployment's cost-allocation tags and runs the adapter's
+// manifest mutators on them.
+func (a *kubernetesDeploymentAdapter) translate(ctx context.Context, in types.ApplyInput, namespace string, secrets utils.SecretLookup) (*runtimetypes.KubernetesRuntimeConfig, error) {
+	desired, err := a.buildDesiredStateFromV1Alpha1(ctx, in, namespace, secrets)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := kubernetesTranslateRuntimeConfig(ctx, desired)
+	if err != nil {
+		return nil, fmt.Errorf("translate kubernetes runtime config: %w", err)
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("kubernetes runtime config is required")
+	}
+	kubernetesApplyCostTags(cfg, v1alpha1.CostAllocationTags(in.Deployment, in.Runtime))
+	if err := utils.MutateKubernetesObjects(ctx, a.mutators, in, kubernetesRuntimeObjects(cfg)); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 // Remove deletes every kagent/kmcp resource owned by this Deployment (agent
-// + mcp + remote-mcp kinds) via the shared deploymentID label selector. Both
-// target kinds are swept because RemoveInput doesn't carry the resolved
-// target; sweep-both is cheap and idempotent.
+// + mcp + remote-mcp kinds) via the shared deploymentID label selector, then
+// uninstalls the Helm releases carrying the same label. Every kind is swept
+// because RemoveInput doesn't carry the resolved target; the sweep is cheap
+// and idempotent.
 func (a *kubernetesDeploymentAdapter) Remove(ctx context.Context, in types.RemoveInput) (*types.RemoveResult, error) {
 	if in.Deployment == nil {
 		return nil, fmt.Errorf("remove: deployment is required")
@@ -101,6 +209,9 @@ func (a *kubernetesDeploymentAdapter) Remove(ctx context.Context, in types.Remov
 			return nil, fmt.Errorf("remove %s resources: %w", resourceType, err)
 		}
 	}
+	if err := a.helm.UninstallByLabels(ctx, in.Runtime, namespace, kubernetesDeploymentManagedLabels(deploymentID)); err != nil {
+		return nil, fmt.Errorf("remove helm releases: %w", err)
+	}
 
 	now := time.Now().UTC()
 	gen := in.Deployment.Metadata.Generation
@@ -116,12 +227,137 @@ func (a *kubernetesDeploymentAdapter) Remove(ctx context.Context, in types.Remov
 	}, nil
 }
 
-// Logs is not yet implemented for the kubernetes adapter. Returns an
-// immediately-closed channel so callers don't block.
-func (a *kubernetesDeploymentAdapter) Logs(ctx context.Context, in types.LogsInput) (<-chan types.LogLine, error) {
-	ch := make(chan types.LogLine)
-	close(ch)
-	return ch, nil
+// applyChart installs a Chart target as a Helm release. Deployment override
+// values under spec.runtimeConfig.values are deep-merged over the chart's
+// defaults and checked against its values schema before helm runs. The
+// release is reported Ready once helm records it deployed; helm is not asked
+// to wait on the chart's workloads.
+func (a *kubernetesDeploymentAdapter) applyChart(
+	ctx context.Context,
+	in types.ApplyInput,
+	namespace string,
+	chart *v1alpha1.Chart,
+) (*types.ApplyResult, error) {
+	release, err := deploymentChartRelease(in.Deployment, namespace, chart)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.helm.UpgradeInstall(ctx, in.Runtime, release); err != nil {
+		return nil, fmt.Errorf("install chart %s: %w", chart.Metadata.Name, err)
+	}
+	manifests, err := renderKubernetesManifests(nil, []helmRelease{release})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	gen := in.Deployment.Metadata.Generation
+	return &types.ApplyResult{
+		Conditions: []v1alpha1.Condition{{
+			Type:               "Ready",
+			Status:             v1alpha1.ConditionTrue,
+			Reason:             "Installed",
+			Message:            fmt.Sprintf("helm release %s/%s deployed", namespace, release.Name),
+			LastTransitionTime: now,
+			ObservedGeneration: gen,
+		}, {
+			Type:               "RuntimeConfigured",
+			Status:             v1alpha1.ConditionTrue,
+			Reason:             "KubernetesRuntime",
+			Message:            "kubernetes runtime reachable",
+			LastTransitionTime: now,
+			ObservedGeneration: gen,
+		}},
+		Manifests: manifests,
+	}, nil
+}
+
+// installAgentCharts installs every release agentChartReleases builds,
+// labeled for the agent's Deployment so Remove sweeps them with the agent.
+// It returns the installed releases.
+func (a *kubernetesDeploymentAdapter) installAgentCharts(
+	ctx context.Context,
+	in types.ApplyInput,
+	namespace string,
+	agent *v1alpha1.Agent,
+) ([]helmRelease, error) {
+	releases, err := agentChartReleases(ctx, in, namespace, agent)
+	if err != nil {
+		return nil, err
+	}
+	for i, release := range releases {
+		if err := a.helm.UpgradeInstall(ctx, in.Runtime, release); err != nil {
+			return nil, fmt.Errorf("spec.charts[%d]: install chart %s: %w", i, agent.Spec.Charts[i].Name, err)
+		}
+	}
+	return releases, nil
+}
+
+// agentChartReleases resolves every spec.charts ref through in.Getter and
+// builds its release with the chart's default values.
+func agentChartReleases(
+	ctx context.Context,
+	in types.ApplyInput,
+	namespace string,
+	agent *v1alpha1.Agent,
+) ([]helmRelease, error) {
+	deploymentID := in.Deployment.Metadata.Name
+	releases := make([]helmRelease, 0, len(agent.Spec.Charts))
+	for i, ref := range agent.Spec.Charts {
+		if ref.Kind == "" {
+			ref.Kind = v1alpha1.KindChart
+		}
+		if ref.Namespace == "" {
+			ref.Namespace = agent.Metadata.Namespace
+		}
+		if in.Getter == nil {
+			return nil, fmt.Errorf("spec.charts[%d]: getter required to resolve ref", i)
+		}
+		obj, err := in.Getter(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("spec.charts[%d] resolve %s/%s: %w", i, ref.Namespace, ref.Name, err)
+		}
+		chart, ok := obj.(*v1alpha1.Chart)
+		if !ok || chart == nil {
+			return nil, fmt.Errorf("spec.charts[%d]: getter returned unexpected type for %s/%s", i, ref.Namespace, ref.Name)
+		}
+		release, err := chartRelease(chart, deploymentID, namespace, nil)
+		if err != nil {
+			return nil, fmt.Errorf("spec.charts[%d]: %w", i, err)
+		}
+		releases = append(releases, release)
+	}
+	return releases, nil
+}
+
+// deploymentChartRelease builds the release for a Chart target, with the
+// Deployment's spec.runtimeConfig.values deep-merged over the chart's
+// defaults.
+func deploymentChartRelease(deployment *v1alpha1.Deployment, namespace string, chart *v1alpha1.Chart) (helmRelease, error) {
+	var overrides map[string]any
+	if raw, ok := deployment.Spec.RuntimeConfig["values"]; ok && raw != nil {
+		overrides, ok = raw.(map[string]any)
+		if !ok {
+			return helmRelease{}, fmt.Errorf("apply: spec.runtimeConfig.values must be an object, got %T", raw)
+		}
+	}
+	return chartRelease(chart, deployment.Metadata.Name, namespace, overrides)
+}
+
+// chartRelease builds the Helm release for chart owned by deploymentID,
+// validating the merged values against the chart's schema.
+func chartRelease(chart *v1alpha1.Chart, deploymentID, namespace string, overrides map[string]any) (helmRelease, error) {
+	values := mergeHelmValues(chart.Spec.Values, overrides)
+	if err := v1alpha1.ValidateChartValues(&chart.Spec, values); err != nil {
+		return helmRelease{}, fmt.Errorf("chart %s values: %w", chart.Metadata.Name, err)
+	}
+	return helmRelease{
+		Name:      kubernetesHelmReleaseName(chart.Metadata.Name, deploymentID),
+		Namespace: namespace,
+		Chart:     chart.Spec,
+		Values:    values,
+		Labels:    kubernetesDeploymentManagedLabels(deploymentID),
+	}, nil
 }
 
 // buildDesiredStateFromV1Alpha1 constructs a *runtimetypes.DesiredState from
@@ -132,6 +368,7 @@ func (a *kubernetesDeploymentAdapter) buildDesiredStateFromV1Alpha1(
 	ctx context.Context,
 	in types.ApplyInput,
 	namespace string,
+	secrets utils.SecretLookup,
 ) (*runtimetypes.DesiredState, error) {
 	if in.Target == nil {
 		return nil, fmt.Errorf("apply: target is required")
@@ -147,15 +384,18 @@ func (a *kubernetesDeploymentAdapter) buildDesiredStateFromV1Alpha1(
 			EnvValues:    envValues,
 			ArgValues:    argValues,
 			HeaderValues: headerValues,
+			Secrets:      secrets,
+			Resources:    in.Deployment.Spec.Resources,
 		})
 		if err != nil {
 			return nil, err
 		}
 		return &runtimetypes.DesiredState{MCPServers: []*runtimetypes.MCPServer{server}}, nil
 	case *v1alpha1.Agent:
-		var telemetryEndpoint string
+		var telemetryEndpoint, registryURL string
 		if in.Runtime != nil {
 			telemetryEndpoint = in.Runtime.Spec.TelemetryEndpoint
+			registryURL = in.Runtime.Spec.RegistryURL
 		}
 		agent, servers, err := utils.SpecToRuntimeAgent(ctx, target.Metadata, target.Spec, utils.AgentTranslateOpts{
 			DeploymentID:      deploymentID,
@@ -163,8 +403,15 @@ func (a *kubernetesDeploymentAdapter) buildDesiredStateFromV1Alpha1(
 			KagentURL:         "http://kagent-controller.kagent.svc.cluster.local",
 			DeploymentEnv:     envValues,
 			TelemetryEndpoint: telemetryEndpoint,
+			RegistryURL:       registryURL,
 			HeaderValues:      headerValues,
+			Secrets:           secrets,
 			Getter:            in.Getter,
+			SubAgentURL: func(agent *v1alpha1.Agent, deploymentID string) string {
+				return kubernetesAgentURL(agent, deploymentID, namespace)
+			},
+			Resources:  in.Deployment.Spec.Resources,
+			Frameworks: a.frameworks,
 		})
 		if err != nil {
 			return nil, err
@@ -178,6 +425,42 @@ func (a *kubernetesDeploymentAdapter) buildDesiredStateFromV1Alpha1(
 	}
 }
 
+// kubernetesSecretLookup reads header secrets from the Secrets in the
+// Deployment's namespace on the runtime's cluster.
+func kubernetesSecretLookup(runtime *v1alpha1.Runtime, namespace string) utils.SecretLookup {
+	var c client.Client
+	return func(ctx context.Context, ref v1alpha1.HeaderSecretRef) (string, error) {
+		if c == nil {
+			var err error
+			if c, err = kubernetesGetClient(runtime); err != nil {
+				return "", err
+			}
+		}
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Secret}, secret); err != nil {
+			return "", fmt.Errorf("read Secret %s/%s: %w", namespace, ref.Secret, err)
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			return "", fmt.Errorf("secret %s/%s has no key %q", namespace, ref.Secret, ref.Key)
+		}
+		return string(value), nil
+	}
+}
+
+// redactedSecret stands in for a header secret in rendered manifests.
+func redactedSecret(ref v1alpha1.HeaderSecretRef) string {
+	return "redacted:" + ref.String()
+}
+
+// kubernetesAgentURL is the in-cluster address of the Service kagent
+// creates for an agent's Agent resource. Sub-agent Deployments share their
+// parent's Runtime and env, so they land in the parent's namespace.
+func kubernetesAgentURL(agent *v1alpha1.Agent, deploymentID, namespace string) string {
+	name := kubernetesAgentResourceName(agent.Metadata.Name, agent.Metadata.Tag, deploymentID)
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", name, namespace, kagentAgentPort)
+}
+
 // namespaceFromV1Alpha1 picks the target kubernetes namespace:
 //  1. Deployment.Spec.Env[KAGENT_NAMESPACE] (user override).
 //  2. Runtime.Spec.Config.namespace.
@@ -194,6 +477,55 @@ func namespaceFromV1Alpha1(deployment *v1alpha1.Deployment, runtime *v1alpha1.Ru
 	return kubernetesDefaultNamespace()
 }
 
-// Compile-time assertion that the kubernetes adapter satisfies the v1alpha1
-// DeploymentAdapter contract.
-var _ types.DeploymentAdapter = (*kubernetesDeploymentAdapter)(nil)
+// Compile-time assertions that the kubernetes adapter satisfies the
+// v1alpha1 DeploymentAdapter contract and can render dry runs.
+var (
+	_ types.DeploymentAdapter  = (*kubernetesDeploymentAdapter)(nil)
+	_ types.DeploymentRenderer = (*kubernetesDeploymentAdapter)(nil)
+)
+
+// renderKubernetesManifests renders every resource in cfg and every Helm
+// release, named Kind/namespace/name. Releases render as their chart
+// coordinates plus the merged install values.
+func renderKubernetesManifests(cfg *runtimetypes.KubernetesRuntimeConfig, releases []helmRelease) ([]types.RenderedManifest, error) {
+	var objects []client.Object
+	if cfg != nil {
+		for _, o := range cfg.ConfigMaps {
+			objects = append(objects, o)
+		}
+		for _, o := range cfg.Agents {
+			objects = append(objects, o)
+		}
+		for _, o := range cfg.RemoteMCPServers {
+			objects = append(objects, o)
+		}
+		for _, o := range cfg.MCPServers {
+			objects = append(objects, o)
+		}
+	}
+	manifests := make([]types.RenderedManifest, 0, len(objects)+len(releases))
+	for _, o := range objects {
+		name := fmt.Sprintf("%s/%s/%s", o.GetObjectKind().GroupVersionKind().Kind, o.GetNamespace(), o.GetName())
+		m, err := utils.RenderManifest(name, o)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	for _, r := range releases {
+		m, err := utils.RenderManifest(fmt.Sprintf("HelmRelease/%s/%s", r.Namespace, r.Name), map[string]any{
+			"name":       r.Name,
+			"namespace":  r.Namespace,
+			"repository": r.Chart.Repository,
+			"chart":      r.Chart.Chart,
+			"version":    r.Chart.Version,
+			"labels":     r.Labels,
+			"values":     r.Values,
+		})
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
@@ -2,17 +2,26 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
+	"maps"
+	"strings"
 	"testing"
+	"time"
 
 	v1alpha2 "github.com/kagent-dev/kagent/go/api/v1alpha2"
 	kmcpv1alpha1 "github.com/kagent-dev/kmcp/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/mutators"
 	adapterpkgtypes "github.com/agentregistry-dev/agentregistry/pkg/types"
 )
 
@@ -92,6 +101,78 @@ func TestK8sV1Alpha1Apply_MCPServerTarget_CreatesResource(t *testing.T) {
 	if remoteMCPs.Items[0].Namespace != "kagent" {
 		t.Fatalf("RemoteMCPServer namespace = %q, want kagent", remoteMCPs.Items[0].Namespace)
 	}
+
+	want := "RemoteMCPServer/kagent/" + remoteMCPs.Items[0].Name
+	if len(res.Manifests) != 1 || res.Manifests[0].Name != want {
+		t.Fatalf("Manifests = %+v, want one named %s", res.Manifests, want)
+	}
+	if !strings.Contains(res.Manifests[0].Content, "https://api.weather.example/mcp") {
+		t.Fatalf("manifest missing URL:\n%s", res.Manifests[0].Content)
+	}
+}
+
+func TestK8sV1Alpha1Apply_LabelsCostAllocationTags(t *testing.T) {
+	fakeClient := withFakeKubeClient(t)
+
+	runtime := &v1alpha1.Runtime{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "kube-local"},
+		Spec: v1alpha1.RuntimeSpec{
+			Type:   v1alpha1.TypeKubernetes,
+			Config: map[string]any{"namespace": "kagent"},
+			CostAllocation: &v1alpha1.CostAllocation{
+				Tags:             map[string]string{"cost-center": "ml"},
+				DeploymentLabels: []string{"team", "project"},
+			},
+		},
+	}
+	target := &v1alpha1.MCPServer{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather"},
+		Spec: v1alpha1.MCPServerSpec{
+			Remote: &v1alpha1.MCPRemote{Type: "streamable-http", URL: "https://api.weather.example/mcp"},
+		},
+	}
+	deployment := &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather-kube", Labels: map[string]string{"team": "payments", "tier": "gold"}},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather"},
+			RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "kube-local"},
+		},
+	}
+
+	res, err := NewKubernetesDeploymentAdapter().Apply(context.Background(), adapterpkgtypes.ApplyInput{
+		Deployment: deployment,
+		Target:     target,
+		Runtime:    runtime,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	remoteMCPs := &v1alpha2.RemoteMCPServerList{}
+	if err := fakeClient.List(context.Background(), remoteMCPs); err != nil {
+		t.Fatalf("list RemoteMCPServers: %v", err)
+	}
+	if len(remoteMCPs.Items) != 1 {
+		t.Fatalf("expected 1 RemoteMCPServer, got %d", len(remoteMCPs.Items))
+	}
+	want := map[string]string{
+		kubernetesManagedLabelKey:              "true",
+		kubernetesDeploymentIDLabelKey:         "weather-kube",
+		"cost-center":                          "ml",
+		"team":                                 "payments",
+		v1alpha1.CostAllocationDeploymentIDTag: "weather-kube",
+	}
+	if got := remoteMCPs.Items[0].Labels; !maps.Equal(got, want) {
+		t.Fatalf("labels = %v, want %v", got, want)
+	}
+
+	annotation := res.RuntimeMetadata[v1alpha1.DeploymentCostAllocationAnnotation]
+	if annotation != `{"cost-center":"ml","deployment-id":"weather-kube","team":"payments"}` {
+		t.Fatalf("cost allocation annotation = %q", annotation)
+	}
+	if endpoint, ok := res.RuntimeMetadata[v1alpha1.DeploymentEndpointAnnotation]; !ok || endpoint != "" {
+		t.Fatalf("MCPServer deployments must clear the endpoint annotation, got %q (present %v)", endpoint, ok)
+	}
 }
 
 func TestK8sV1Alpha1Remove_DeletesResourcesByDeploymentID(t *testing.T) {
@@ -110,7 +191,7 @@ func TestK8sV1Alpha1Remove_DeletesResourcesByDeploymentID(t *testing.T) {
 	}
 	fakeClient := withFakeKubeClient(t, seedAgent, seedMCP)
 
-	adapter := NewKubernetesDeploymentAdapter()
+	adapter, _ := newFakeHelmAdapter()
 
 	runtime := &v1alpha1.Runtime{
 		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "kube-local"},
@@ -150,6 +231,7 @@ func TestK8sV1Alpha1SupportedTargetKinds(t *testing.T) {
 	want := map[string]bool{
 		v1alpha1.KindAgent:     false,
 		v1alpha1.KindMCPServer: false,
+		v1alpha1.KindChart:     false,
 	}
 	for _, k := range kinds {
 		if _, ok := want[k]; ok {
@@ -163,13 +245,363 @@ func TestK8sV1Alpha1SupportedTargetKinds(t *testing.T) {
 	}
 }
 
-func TestK8sV1Alpha1Logs_ReturnsClosedChannel(t *testing.T) {
-	adapter := NewKubernetesDeploymentAdapter()
-	ch, err := adapter.Logs(context.Background(), adapterpkgtypes.LogsInput{})
+func TestK8sV1Alpha1Logs_StreamsLabelledPods(t *testing.T) {
+	withFakeKubeClient(t)
+	clientset := k8sfake.NewClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "weather-abc", Namespace: "agents",
+			Labels: map[string]string{kubernetesDeploymentIDLabelKey: "weather-kube"},
+		}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "other-xyz", Namespace: "agents",
+			Labels: map[string]string{kubernetesDeploymentIDLabelKey: "other"},
+		}},
+	)
+	original := kubernetesNewClientsetForConfig
+	t.Cleanup(func() { kubernetesNewClientsetForConfig = original })
+	kubernetesNewClientsetForConfig = func(*rest.Config) (kubernetes.Interface, error) {
+		return clientset, nil
+	}
+
+	deployment := &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Name: "weather-kube"},
+		Spec:     v1alpha1.DeploymentSpec{Env: map[string]string{"KAGENT_NAMESPACE": "agents"}},
+	}
+	ch, err := NewKubernetesDeploymentAdapter().Logs(context.Background(), adapterpkgtypes.LogsInput{
+		Deployment: deployment,
+		Follow:     true,
+		TailLines:  20,
+	})
 	if err != nil {
 		t.Fatalf("Logs: %v", err)
 	}
-	if _, open := <-ch; open {
-		t.Fatalf("expected closed channel")
+	var lines []adapterpkgtypes.LogLine
+	for line := range ch {
+		lines = append(lines, line)
+	}
+	// The fake clientset answers every log request with "fake logs".
+	if len(lines) != 1 || lines[0].Stream != "weather-abc" || lines[0].Line != "fake logs" {
+		t.Fatalf("lines = %+v", lines)
+	}
+
+	var opts *corev1.PodLogOptions
+	for _, action := range clientset.Actions() {
+		if action.GetSubresource() == "log" {
+			opts = action.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions)
+		}
+	}
+	if opts == nil || !opts.Follow || !opts.Timestamps || opts.TailLines == nil || *opts.TailLines != 20 {
+		t.Fatalf("pod log options = %+v", opts)
+	}
+}
+
+func TestParsePodLogLine(t *testing.T) {
+	line := parsePodLogLine("2026-05-01T10:00:00.5Z listening on :8080")
+	want := time.Date(2026, 5, 1, 10, 0, 0, 500_000_000, time.UTC)
+	if !line.Timestamp.Equal(want) || line.Line != "listening on :8080" {
+		t.Fatalf("parsePodLogLine() = %+v", line)
+	}
+	if line := parsePodLogLine("no timestamp here"); !line.Timestamp.IsZero() || line.Line != "no timestamp here" {
+		t.Fatalf("parsePodLogLine() = %+v", line)
+	}
+}
+
+type fakeHelm struct {
+	installed   []helmRelease
+	uninstalled []map[string]string
+}
+
+func (f *fakeHelm) UpgradeInstall(_ context.Context, _ *v1alpha1.Runtime, rel helmRelease) error {
+	f.installed = append(f.installed, rel)
+	return nil
+}
+
+func (f *fakeHelm) UninstallByLabels(_ context.Context, _ *v1alpha1.Runtime, _ string, labels map[string]string) error {
+	f.uninstalled = append(f.uninstalled, labels)
+	return nil
+}
+
+// newFakeHelmAdapter returns an adapter whose Helm releases go to the
+// returned fake.
+func newFakeHelmAdapter() (*kubernetesDeploymentAdapter, *fakeHelm) {
+	fake := &fakeHelm{}
+	adapter := NewKubernetesDeploymentAdapter()
+	adapter.helm = fake
+	return adapter, fake
+}
+
+func testChart() *v1alpha1.Chart {
+	return &v1alpha1.Chart{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindChart},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "qdrant", Tag: "1"},
+		Spec: v1alpha1.ChartSpec{
+			Repository: "https://qdrant.github.io/qdrant-helm",
+			Chart:      "qdrant",
+			Version:    "1.12.0",
+			ValuesSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"replicaCount": map[string]any{"type": "integer", "minimum": 1},
+				},
+			},
+			Values: map[string]any{"replicaCount": 1, "persistence": map[string]any{"size": "1Gi"}},
+		},
+	}
+}
+
+func TestK8sV1Alpha1Apply_ChartTarget_InstallsRelease(t *testing.T) {
+	adapter, helm := newFakeHelmAdapter()
+	runtime := &v1alpha1.Runtime{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "kube-local"},
+		Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes, Config: map[string]any{"namespace": "infra"}},
+	}
+	deployment := &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "qdrant-prod", Generation: 2},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:     v1alpha1.ResourceRef{Kind: v1alpha1.KindChart, Name: "qdrant"},
+			RuntimeRef:    v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "kube-local"},
+			RuntimeConfig: map[string]any{"values": map[string]any{"replicaCount": 3, "persistence": map[string]any{"class": "ssd"}}},
+		},
+	}
+
+	res, err := adapter.Apply(context.Background(), adapterpkgtypes.ApplyInput{
+		Deployment: deployment,
+		Target:     testChart(),
+		Runtime:    runtime,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(res.Conditions) == 0 || res.Conditions[0].Type != "Ready" || res.Conditions[0].Status != v1alpha1.ConditionTrue {
+		t.Fatalf("Ready condition unexpected: %+v", res.Conditions)
+	}
+	if len(helm.installed) != 1 {
+		t.Fatalf("expected 1 release, got %d", len(helm.installed))
+	}
+	rel := helm.installed[0]
+	if rel.Name != "qdrant-qdrant-prod" || rel.Namespace != "infra" {
+		t.Fatalf("release = %s/%s, want infra/qdrant-qdrant-prod", rel.Namespace, rel.Name)
+	}
+	if rel.Labels[kubernetesDeploymentIDLabelKey] != "qdrant-prod" {
+		t.Fatalf("release labels = %v", rel.Labels)
+	}
+	if rel.Values["replicaCount"] != 3 {
+		t.Fatalf("replicaCount = %v, want override 3", rel.Values["replicaCount"])
+	}
+	persistence, _ := rel.Values["persistence"].(map[string]any)
+	if persistence["size"] != "1Gi" || persistence["class"] != "ssd" {
+		t.Fatalf("persistence not deep-merged: %v", persistence)
+	}
+}
+
+func TestK8sV1Alpha1Apply_ChartTarget_RejectsValuesOutsideSchema(t *testing.T) {
+	adapter, helm := newFakeHelmAdapter()
+	deployment := &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "qdrant-prod"},
+		Spec: v1alpha1.DeploymentSpec{
+			RuntimeConfig: map[string]any{"values": map[string]any{"replicaCount": 0}},
+		},
+	}
+
+	_, err := adapter.Apply(context.Background(), adapterpkgtypes.ApplyInput{
+		Deployment: deployment,
+		Target:     testChart(),
+		Runtime:    &v1alpha1.Runtime{Spec: v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes}},
+	})
+	if err == nil {
+		t.Fatalf("expected schema violation error")
+	}
+	if len(helm.installed) != 0 {
+		t.Fatalf("helm should not run on invalid values, got %d installs", len(helm.installed))
+	}
+}
+
+func TestK8sV1Alpha1Apply_AgentTarget_InstallsChartDependencies(t *testing.T) {
+	withFakeKubeClient(t)
+	adapter, helm := newFakeHelmAdapter()
+	runtime := &v1alpha1.Runtime{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "kube-local"},
+		Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes, Config: map[string]any{"namespace": "kagent"}},
+	}
+	agent := &v1alpha1.Agent{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindAgent},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "rag-agent", Tag: "1"},
+		Spec: v1alpha1.AgentSpec{
+			Source: &v1alpha1.AgentSource{Image: "ghcr.io/acme/rag-agent:1"},
+			Charts: []v1alpha1.ResourceRef{{Name: "qdrant", Tag: "1"}},
+		},
+	}
+	deployment := &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "rag-prod"},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "rag-agent"},
+			RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "kube-local"},
+		},
+	}
+	var gotRef v1alpha1.ResourceRef
+	getter := func(_ context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		gotRef = ref
+		return testChart(), nil
+	}
+
+	res, err := adapter.Apply(context.Background(), adapterpkgtypes.ApplyInput{
+		Deployment: deployment,
+		Target:     agent,
+		Runtime:    runtime,
+		Getter:     getter,
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if endpoint := res.RuntimeMetadata[v1alpha1.DeploymentEndpointAnnotation]; !strings.HasSuffix(endpoint, ".kagent.svc.cluster.local:8080") {
+		t.Fatalf("endpoint annotation = %q", endpoint)
+	}
+	if gotRef.Kind != v1alpha1.KindChart || gotRef.Namespace != "default" {
+		t.Fatalf("chart ref not normalized: %+v", gotRef)
+	}
+	if len(helm.installed) != 1 || helm.installed[0].Name != "qdrant-rag-prod" || helm.installed[0].Namespace != "kagent" {
+		t.Fatalf("unexpected releases: %+v", helm.installed)
+	}
+}
+
+func TestK8sV1Alpha1Apply_AgentTarget_ResolvesHeaderSecrets(t *testing.T) {
+	fakeClient := withFakeKubeClient(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kagent", Name: "github-token"},
+		Data:       map[string][]byte{"token": []byte("ghp_123")},
+	})
+	runtime := &v1alpha1.Runtime{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "kube-local"},
+		Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes, Config: map[string]any{"namespace": "kagent"}},
+	}
+	github := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "github", Tag: "1"},
+		Spec: v1alpha1.MCPServerSpec{
+			Remote: &v1alpha1.MCPRemote{
+				Type:    "streamable-http",
+				URL:     "https://api.github.example/mcp",
+				Headers: []v1alpha1.HTTPHeader{{Name: "Authorization", Value: `Bearer {{secret "github-token/token"}}`}},
+			},
+		},
+	}
+	agent := &v1alpha1.Agent{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindAgent},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "triage", Tag: "1"},
+		Spec: v1alpha1.AgentSpec{
+			Source:     &v1alpha1.AgentSource{Image: "ghcr.io/acme/triage:1"},
+			MCPServers: []v1alpha1.ResourceRef{{Kind: v1alpha1.KindMCPServer, Name: "github", Tag: "1"}},
+		},
+	}
+	deployment := &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "triage-prod"},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "triage"},
+			RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "kube-local"},
+		},
+	}
+
+	res, err := NewKubernetesDeploymentAdapter().Apply(context.Background(), adapterpkgtypes.ApplyInput{
+		Deployment: deployment,
+		Target:     agent,
+		Runtime:    runtime,
+		Getter: func(context.Context, v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+			return github, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	agents := &v1alpha2.AgentList{}
+	if err := fakeClient.List(context.Background(), agents); err != nil {
+		t.Fatalf("list Agents: %v", err)
+	}
+	if len(agents.Items) != 1 {
+		t.Fatalf("expected 1 Agent, got %d", len(agents.Items))
+	}
+	var serversConfig string
+	for _, env := range agents.Items[0].Spec.BYO.Deployment.Env {
+		if env.Name == "MCP_SERVERS_CONFIG" {
+			serversConfig = env.Value
+		}
+	}
+	if !strings.Contains(serversConfig, "Bearer ghp_123") {
+		t.Fatalf("MCP_SERVERS_CONFIG missing the resolved secret: %s", serversConfig)
+	}
+	redacted := false
+	for _, m := range res.Manifests {
+		if strings.Contains(m.Content, "ghp_123") {
+			t.Fatalf("manifest %s leaks the secret:\n%s", m.Name, m.Content)
+		}
+		redacted = redacted || strings.Contains(m.Content, "redacted:github-token/token")
+	}
+	if !redacted {
+		t.Fatalf("no manifest carries the redacted header: %+v", res.Manifests)
+	}
+}
+
+func TestK8sV1Alpha1Remove_UninstallsHelmReleases(t *testing.T) {
+	withFakeKubeClient(t)
+	adapter, helm := newFakeHelmAdapter()
+
+	_, err := adapter.Remove(context.Background(), adapterpkgtypes.RemoveInput{
+		Deployment: &v1alpha1.Deployment{Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "qdrant-prod"}},
+		Runtime:    &v1alpha1.Runtime{Spec: v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes, Config: map[string]any{"namespace": "infra"}}},
+	})
+	if err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if len(helm.uninstalled) != 1 || helm.uninstalled[0][kubernetesDeploymentIDLabelKey] != "qdrant-prod" {
+		t.Fatalf("unexpected uninstall selectors: %v", helm.uninstalled)
+	}
+}
+
+type failingMutator struct{}
+
+func (failingMutator) Name() string { return "policy" }
+
+func (failingMutator) MutateKubernetesObject(context.Context, adapterpkgtypes.ApplyInput, client.Object) error {
+	return errors.New("sidecar image not allowed")
+}
+
+func TestK8sV1Alpha1Render_RunsManifestMutators(t *testing.T) {
+	in := adapterpkgtypes.ApplyInput{
+		Deployment: &v1alpha1.Deployment{
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather-kube"},
+			Spec: v1alpha1.DeploymentSpec{
+				TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather"},
+				RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "kube-local"},
+			},
+		},
+		Target: &v1alpha1.MCPServer{
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather"},
+			Spec: v1alpha1.MCPServerSpec{
+				Remote: &v1alpha1.MCPRemote{Type: "streamable-http", URL: "https://api.weather.example/mcp"},
+			},
+		},
+		Runtime: &v1alpha1.Runtime{
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "kube-local"},
+			Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes, Config: map[string]any{"namespace": "kagent"}},
+		},
+	}
+
+	adapter := NewKubernetesDeploymentAdapter(
+		mutators.Labels{Labels: map[string]string{"org": "acme", kubernetesManagedLabelKey: "false"}},
+		mutators.Labels{Annotations: map[string]string{"policy": "v2"}},
+	)
+	manifests, err := adapter.Render(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if len(manifests) != 1 || !strings.Contains(manifests[0].Content, "org: acme") || !strings.Contains(manifests[0].Content, "policy: v2") {
+		t.Fatalf("Manifests = %+v, want the mutators' label and annotation", manifests)
+	}
+	if !strings.Contains(manifests[0].Content, kubernetesManagedLabelKey+`: "true"`) {
+		t.Fatalf("mutator overwrote the registry's managed label:\n%s", manifests[0].Content)
+	}
+
+	_, err = NewKubernetesDeploymentAdapter(failingMutator{}).Render(context.Background(), in)
+	if err == nil || !strings.Contains(err.Error(), `manifest mutator "policy"`) {
+		t.Fatalf("Render error = %v, want the mutator's error", err)
 	}
 }
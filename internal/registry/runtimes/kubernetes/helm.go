@@ -0,0 +1,225 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// maxHelmReleaseNameLength is Helm's release-name limit (stricter than the
+// 63-character Kubernetes name limit because Helm derives object names from
+// it).
+const maxHelmReleaseNameLength = 53
+
+// helmRelease is one Chart installed (or upgraded) as a Helm release.
+type helmRelease struct {
+	Name      string
+	Namespace string
+	Chart     v1alpha1.ChartSpec
+	Values    map[string]any
+	// Labels are stored on the release so Remove can find every release a
+	// Deployment owns without knowing which charts it installed.
+	Labels map[string]string
+}
+
+// helmClient is the seam between the adapter and Helm. Implementations
+// resolve the target cluster from the Runtime's Spec.Config the same way
+// kubernetesRESTConfig does.
+type helmClient interface {
+	UpgradeInstall(ctx context.Context, runtime *v1alpha1.Runtime, rel helmRelease) error
+	UninstallByLabels(ctx context.Context, runtime *v1alpha1.Runtime, namespace string, labels map[string]string) error
+}
+
+// helmCLI drives the helm binary found on PATH. Every value taken from a
+// Chart or Deployment is passed either as a flag value or after "--", so
+// none can be read as a flag.
+type helmCLI struct {
+	binary string
+}
+
+func (h helmCLI) UpgradeInstall(ctx context.Context, runtime *v1alpha1.Runtime, rel helmRelease) error {
+	globals, cleanup, err := helmGlobalArgs(runtime)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	valuesFile, err := writeTempFile("helm-values-*.json", rel.Values)
+	if err != nil {
+		return fmt.Errorf("write values for release %s: %w", rel.Name, err)
+	}
+	defer func() { _ = os.Remove(valuesFile) }()
+
+	chart, chartFlags := helmChartArgs(rel.Chart)
+	args := []string{"upgrade", "--install"}
+	args = append(args, chartFlags...)
+	args = append(args,
+		"--version", rel.Chart.Version,
+		"--namespace", rel.Namespace,
+		"--create-namespace",
+		"--values", valuesFile,
+	)
+	if len(rel.Labels) > 0 {
+		args = append(args, "--labels", helmLabelSelector(rel.Labels))
+	}
+	args = append(args, globals...)
+	_, err = h.run(ctx, append(args, "--", rel.Name, chart)...)
+	return err
+}
+
+func (h helmCLI) UninstallByLabels(ctx context.Context, runtime *v1alpha1.Runtime, namespace string, labels map[string]string) error {
+	globals, cleanup, err := helmGlobalArgs(runtime)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	args := []string{"list", "--namespace", namespace, "--selector", helmLabelSelector(labels), "--short", "--all"}
+	out, err := h.run(ctx, append(args, globals...)...)
+	if err != nil {
+		return err
+	}
+	for name := range strings.FieldsSeq(out) {
+		args := append([]string{"uninstall", "--namespace", namespace}, globals...)
+		if _, err := h.run(ctx, append(args, "--", name)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run executes helm with args. A missing binary is an error rather than
+// a skipped step, so a Deployment's releases are never silently left
+// installed or uninstalled.
+func (h helmCLI) run(ctx context.Context, args ...string) (string, error) {
+	if _, err := exec.LookPath(h.binary); err != nil {
+		return "", fmt.Errorf("helm %s: the %q binary is required to manage Helm releases: %w", args[0], h.binary, err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("helm %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// helmChartArgs renders the chart reference and the flags locating it:
+// oci:// references are passed as-is (with Chart appended as the last
+// segment when set); http(s) repositories go through --repo.
+func helmChartArgs(spec v1alpha1.ChartSpec) (chart string, flags []string) {
+	if strings.HasPrefix(spec.Repository, "oci://") {
+		ref := strings.TrimSuffix(spec.Repository, "/")
+		if spec.Chart != "" {
+			ref += "/" + spec.Chart
+		}
+		return ref, nil
+	}
+	return spec.Chart, []string{"--repo", spec.Repository}
+}
+
+// helmGlobalArgs maps the runtime's kubeconfig settings onto helm flags. An
+// inline kubeconfig is written to a private temp file that cleanup removes.
+func helmGlobalArgs(runtime *v1alpha1.Runtime) ([]string, func(), error) {
+	cleanup := func() {}
+	runtimeCfg, err := kubernetesRuntimeConfig(runtime)
+	if err != nil {
+		return nil, cleanup, err
+	}
+	var args []string
+	if kubeconfig := strings.TrimSpace(runtimeCfg.Kubeconfig); kubeconfig != "" {
+		f, err := os.CreateTemp("", "kubeconfig-*")
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("write kubeconfig: %w", err)
+		}
+		cleanup = func() { _ = os.Remove(f.Name()) }
+		_, err = f.WriteString(kubeconfig)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("write kubeconfig: %w", err)
+		}
+		args = append(args, "--kubeconfig", f.Name())
+	} else if kubeconfigPath := strings.TrimSpace(runtimeCfg.KubeconfigPath); kubeconfigPath != "" {
+		args = append(args, "--kubeconfig", kubeconfigPath)
+	}
+	if contextName := strings.TrimSpace(runtimeCfg.Context); contextName != "" {
+		args = append(args, "--kube-context", contextName)
+	}
+	return args, cleanup, nil
+}
+
+func writeTempFile(pattern string, v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// helmLabelSelector renders labels as "k=v,k=v" in a stable order; helm
+// accepts the same syntax for --labels and --selector.
+func helmLabelSelector(labels map[string]string) string {
+	keys := slices.Sorted(maps.Keys(labels))
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// kubernetesHelmReleaseName scopes a chart's release to the Deployment that
+// owns it, within Helm's 53-character limit.
+func kubernetesHelmReleaseName(chartName, deploymentID string) string {
+	name := kubernetesDeploymentScopedName(chartName, deploymentID)
+	if len(name) <= maxHelmReleaseNameLength {
+		return name
+	}
+	suffix := kubernetesDeploymentIDSuffix(deploymentID)
+	if suffix == "" {
+		return truncateKubernetesNamePart(name, maxHelmReleaseNameLength)
+	}
+	base := truncateKubernetesNamePart(sanitizeKubernetesName(chartName), maxHelmReleaseNameLength-len(suffix)-1)
+	return base + "-" + suffix
+}
+
+// mergeHelmValues deep-merges override onto base without mutating either:
+// nested maps merge key by key, every other value in override replaces the
+// base value.
+func mergeHelmValues(base, override map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(override))
+	maps.Copy(out, base)
+	for k, v := range override {
+		if ov, ok := v.(map[string]any); ok {
+			if bv, ok := out[k].(map[string]any); ok {
+				out[k] = mergeHelmValues(bv, ov)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,161 @@
+package kubernetes
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+func TestHelmChartArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      v1alpha1.ChartSpec
+		wantChart string
+		wantFlags []string
+	}{
+		{
+			name:      "http repository uses --repo",
+			spec:      v1alpha1.ChartSpec{Repository: "https://charts.example.com", Chart: "qdrant"},
+			wantChart: "qdrant",
+			wantFlags: []string{"--repo", "https://charts.example.com"},
+		},
+		{
+			name:      "oci reference with chart appended",
+			spec:      v1alpha1.ChartSpec{Repository: "oci://ghcr.io/acme/charts/", Chart: "gateway"},
+			wantChart: "oci://ghcr.io/acme/charts/gateway",
+		},
+		{
+			name:      "oci reference as-is",
+			spec:      v1alpha1.ChartSpec{Repository: "oci://ghcr.io/acme/charts/gateway"},
+			wantChart: "oci://ghcr.io/acme/charts/gateway",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chart, flags := helmChartArgs(tt.spec)
+			if chart != tt.wantChart || !slices.Equal(flags, tt.wantFlags) {
+				t.Fatalf("helmChartArgs() = %q, %v, want %q, %v", chart, flags, tt.wantChart, tt.wantFlags)
+			}
+		})
+	}
+}
+
+// fakeHelmBinary writes a helm stand-in that appends its arguments, one
+// per line, to the returned log and prints stdout.
+func fakeHelmBinary(t *testing.T, stdout string) (binary, log string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake helm is a shell script")
+	}
+	dir := t.TempDir()
+	binary, log = filepath.Join(dir, "helm"), filepath.Join(dir, "args.log")
+	script := "#!/bin/sh\nfor a in \"$@\"; do echo \"$a\" >> " + log + "; done\necho >> " + log + "\nprintf '%s' '" + stdout + "'\n"
+	if err := os.WriteFile(binary, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return binary, log
+}
+
+func helmInvocations(t *testing.T, log string) [][]string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out [][]string
+	for call := range strings.SplitSeq(strings.TrimSuffix(string(data), "\n\n"), "\n\n") {
+		out = append(out, strings.Split(call, "\n"))
+	}
+	return out
+}
+
+func TestHelmCLI_PositionalsFollowDoubleDash(t *testing.T) {
+	binary, log := fakeHelmBinary(t, "rag-prod-qdrant\n")
+	h := helmCLI{binary: binary}
+	rt := &v1alpha1.Runtime{Spec: v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes}}
+	rel := helmRelease{
+		Name:      "rag-prod-qdrant",
+		Namespace: "infra",
+		Chart:     v1alpha1.ChartSpec{Repository: "https://charts.example.com", Chart: "qdrant", Version: "1.12.0"},
+	}
+	if err := h.UpgradeInstall(context.Background(), rt, rel); err != nil {
+		t.Fatalf("UpgradeInstall: %v", err)
+	}
+	if err := h.UninstallByLabels(context.Background(), rt, "infra", map[string]string{"app": "rag"}); err != nil {
+		t.Fatalf("UninstallByLabels: %v", err)
+	}
+
+	calls := helmInvocations(t, log)
+	if len(calls) != 3 {
+		t.Fatalf("helm ran %d times, want 3: %q", len(calls), calls)
+	}
+	for _, tc := range []struct {
+		call []string
+		tail []string
+	}{
+		{calls[0], []string{"--", "rag-prod-qdrant", "qdrant"}},
+		{calls[2], []string{"--", "rag-prod-qdrant"}},
+	} {
+		if got := tc.call[len(tc.call)-len(tc.tail):]; !slices.Equal(got, tc.tail) {
+			t.Fatalf("helm %s ends with %q, want %q", tc.call[0], got, tc.tail)
+		}
+		if slices.Index(tc.call, "--") != len(tc.call)-len(tc.tail) {
+			t.Fatalf("helm %s has flags after --: %q", tc.call[0], tc.call)
+		}
+	}
+}
+
+func TestHelmCLI_MissingBinaryFails(t *testing.T) {
+	h := helmCLI{binary: filepath.Join(t.TempDir(), "no-helm")}
+	rt := &v1alpha1.Runtime{Spec: v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes}}
+	err := h.UninstallByLabels(context.Background(), rt, "infra", map[string]string{"app": "rag"})
+	if err == nil || !strings.Contains(err.Error(), "binary is required") {
+		t.Fatalf("UninstallByLabels without helm = %v, want a missing binary error", err)
+	}
+}
+
+func TestHelmGlobalArgs(t *testing.T) {
+	runtime := &v1alpha1.Runtime{Spec: v1alpha1.RuntimeSpec{Config: map[string]any{
+		"kubeconfigPath": "/etc/kube/config",
+		"context":        "prod",
+	}}}
+	args, cleanup, err := helmGlobalArgs(runtime)
+	if err != nil {
+		t.Fatalf("helmGlobalArgs: %v", err)
+	}
+	defer cleanup()
+	want := []string{"--kubeconfig", "/etc/kube/config", "--kube-context", "prod"}
+	if !slices.Equal(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+}
+
+func TestKubernetesHelmReleaseName(t *testing.T) {
+	if got := kubernetesHelmReleaseName("acme/qdrant", "rag-prod"); got != "acme-qdrant-rag-prod" {
+		t.Fatalf("release name = %q", got)
+	}
+	long := kubernetesHelmReleaseName(strings.Repeat("vector-store-", 6), "rag-prod")
+	if len(long) > maxHelmReleaseNameLength || !strings.HasSuffix(long, "-rag-prod") {
+		t.Fatalf("long release name = %q (len %d)", long, len(long))
+	}
+}
+
+func TestMergeHelmValues(t *testing.T) {
+	base := map[string]any{"replicas": 1, "image": map[string]any{"tag": "1.0", "pullPolicy": "IfNotPresent"}}
+	override := map[string]any{"replicas": 3, "image": map[string]any{"tag": "1.1"}}
+
+	got := mergeHelmValues(base, override)
+	image := got["image"].(map[string]any)
+	if got["replicas"] != 3 || image["tag"] != "1.1" || image["pullPolicy"] != "IfNotPresent" {
+		t.Fatalf("merged = %v", got)
+	}
+	if base["image"].(map[string]any)["tag"] != "1.0" {
+		t.Fatalf("base mutated: %v", base)
+	}
+}
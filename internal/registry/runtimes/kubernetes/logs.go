@@ -0,0 +1,112 @@
+package kubernetes
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// kubernetesNewClientsetForConfig builds the typed clientset Logs streams
+// pod logs through; the controller-runtime client cannot. A package var so
+// tests can substitute a fake clientset.
+var kubernetesNewClientsetForConfig = func(restConfig *rest.Config) (kubernetes.Interface, error) {
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// Logs reads the logs of the pods kagent and kmcp run for the Deployment,
+// found by the deployment-id label translate puts on them, following them
+// while ctx lives when in.Follow is set. Each line's Stream is the pod it
+// came from. Pods are read concurrently, so lines of different pods
+// interleave in arrival order. A Deployment without pods (a remote MCP
+// server, a Helm chart, or one applied before its pods were labelled) has
+// no logs.
+func (a *kubernetesDeploymentAdapter) Logs(ctx context.Context, in types.LogsInput) (<-chan types.LogLine, error) {
+	if in.Deployment == nil {
+		return nil, fmt.Errorf("logs: deployment is required")
+	}
+	ch := make(chan types.LogLine)
+	deploymentID := strings.TrimSpace(in.Deployment.Metadata.Name)
+	if deploymentID == "" {
+		close(ch)
+		return ch, nil
+	}
+
+	restConfig, err := kubernetesRESTConfig(in.Runtime)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetesNewClientsetForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+	namespace := namespaceFromV1Alpha1(in.Deployment, in.Runtime)
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{kubernetesDeploymentIDLabelKey: deploymentID}).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list pods for deployment %s: %w", deploymentID, err)
+	}
+
+	opts := &corev1.PodLogOptions{Follow: in.Follow, Timestamps: true}
+	if in.TailLines > 0 {
+		tail := int64(in.TailLines)
+		opts.TailLines = &tail
+	}
+	streams := make(map[string]io.ReadCloser, len(pods.Items))
+	for _, pod := range pods.Items {
+		stream, err := clientset.CoreV1().Pods(namespace).GetLogs(pod.Name, opts).Stream(ctx)
+		if err != nil {
+			for _, opened := range streams {
+				_ = opened.Close()
+			}
+			return nil, fmt.Errorf("stream logs of pod %s: %w", pod.Name, err)
+		}
+		streams[pod.Name] = stream
+	}
+
+	var wg sync.WaitGroup
+	for podName, stream := range streams {
+		wg.Go(func() {
+			defer func() { _ = stream.Close() }()
+			scanner := bufio.NewScanner(stream)
+			scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+			for scanner.Scan() {
+				line := parsePodLogLine(scanner.Text())
+				line.Stream = podName
+				select {
+				case ch <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// parsePodLogLine splits a line read with PodLogOptions.Timestamps,
+// "2026-05-01T10:00:00.123456789Z text", into its timestamp and text.
+func parsePodLogLine(raw string) types.LogLine {
+	if ts, text, ok := strings.Cut(raw, " "); ok {
+		if at, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			return types.LogLine{Timestamp: at, Line: text}
+		}
+	}
+	return types.LogLine{Line: raw}
+}
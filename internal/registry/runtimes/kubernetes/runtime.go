@@ -11,6 +11,7 @@ import (
 	v1alpha2 "github.com/kagent-dev/kagent/go/api/v1alpha2"
 	kmcpv1alpha1 "github.com/kagent-dev/kmcp/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
@@ -23,6 +24,7 @@ import (
 
 	"github.com/agentregistry-dev/agentregistry/internal/cli/common/gitutil"
 	"github.com/agentregistry-dev/agentregistry/internal/constants"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/frameworks"
 	runtimetypes "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/types"
 	runtimeutils "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/utils"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
@@ -280,12 +282,56 @@ func kubernetesTranslateRuntimeConfig(ctx context.Context, desired *runtimetypes
 	}, nil
 }
 
+// kagentAgentPort is the port of the Service kagent puts in front of each
+// Agent resource; it serves the agent's A2A endpoint.
+const kagentAgentPort = 8080
+
+// kubernetesGPUResource is the extended resource the NVIDIA device plugin
+// advertises.
+const kubernetesGPUResource corev1.ResourceName = "nvidia.com/gpu"
+
+// kubernetesResourceRequirements converts CPU and memory requests and
+// limits to a container's resource requirements, nil when none are set.
+func kubernetesResourceRequirements(r *v1alpha1.ResourceRequirements) (*corev1.ResourceRequirements, error) {
+	if r.IsZero() {
+		return nil, nil
+	}
+	requests, err := kubernetesResourceList(r.Requests)
+	if err != nil {
+		return nil, fmt.Errorf("resources.requests: %w", err)
+	}
+	limits, err := kubernetesResourceList(r.Limits)
+	if err != nil {
+		return nil, fmt.Errorf("resources.limits: %w", err)
+	}
+	return &corev1.ResourceRequirements{Requests: requests, Limits: limits}, nil
+}
+
+func kubernetesResourceList(c *v1alpha1.ComputeResources) (corev1.ResourceList, error) {
+	if c.IsZero() {
+		return nil, nil
+	}
+	out := corev1.ResourceList{}
+	for name, value := range map[corev1.ResourceName]string{corev1.ResourceCPU: c.CPU, corev1.ResourceMemory: c.Memory} {
+		if value == "" {
+			continue
+		}
+		quantity, err := apiresource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s %q: %w", name, value, err)
+		}
+		out[name] = quantity
+	}
+	return out, nil
+}
+
 func kubernetesTranslateAgent(agent *runtimetypes.Agent) (*v1alpha2.Agent, error) {
 	if agent.Deployment.Image == "" {
 		return nil, fmt.Errorf("image must be specified for Agent %s", agent.Name)
 	}
 
 	namespace := agent.Deployment.Env[constants.EnvKagentNamespace]
+	framework := frameworks.OrDefault(agent.Framework)
 
 	envVars := make([]corev1.EnvVar, 0, len(agent.Deployment.Env))
 	if len(agent.Deployment.Env) > 0 {
@@ -295,11 +341,43 @@ func kubernetesTranslateAgent(agent *runtimetypes.Agent) (*v1alpha2.Agent, error
 		}
 		slices.Sort(keys)
 		for _, key := range keys {
-			envVars = append(envVars, corev1.EnvVar{Name: key, Value: agent.Deployment.Env[key]})
+			value := agent.Deployment.Env[key]
+			if secret, secretKey, ok := v1alpha1.ParseSecretRef(value); ok {
+				envVars = append(envVars, corev1.EnvVar{Name: key, ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+						Key:                  secretKey,
+					},
+				}})
+				continue
+			}
+			envVars = append(envVars, corev1.EnvVar{Name: key, Value: value})
 		}
 	}
 
-	sharedSpec := v1alpha2.SharedDeploymentSpec{Env: envVars}
+	// The deployment-id label on the pods is what Logs selects them by.
+	sharedSpec := v1alpha2.SharedDeploymentSpec{
+		Labels: kubernetesDeploymentManagedLabels(agent.DeploymentID),
+		Env:    envVars,
+	}
+	resources, err := kubernetesResourceRequirements(agent.Deployment.Resources)
+	if err != nil {
+		return nil, fmt.Errorf("Agent %s: %w", agent.Name, err)
+	}
+	if agent.Deployment.GPUs > 0 {
+		// Extended resources are requested through limits; the scheduler
+		// places the pod on a node whose NVIDIA device plugin advertises
+		// enough GPUs. Host device mappings have no Kubernetes equivalent
+		// and are ignored.
+		if resources == nil {
+			resources = &corev1.ResourceRequirements{}
+		}
+		if resources.Limits == nil {
+			resources.Limits = corev1.ResourceList{}
+		}
+		resources.Limits[kubernetesGPUResource] = *apiresource.NewQuantity(int64(agent.Deployment.GPUs), apiresource.DecimalSI)
+	}
+	sharedSpec.Resources = resources
 	// MCP server config is now injected via MCP_SERVERS_CONFIG env var (set by ResolveAgent).
 	// ConfigMap volume mount is only needed for prompts.json.
 	if len(agent.ResolvedPrompts) > 0 {
@@ -319,19 +397,34 @@ func kubernetesTranslateAgent(agent *runtimetypes.Agent) (*v1alpha2.Agent, error
 		}}
 		sharedSpec.VolumeMounts = []corev1.VolumeMount{{
 			Name:      volumeName,
-			MountPath: "/config",
+			MountPath: framework.ConfigPath(),
 			ReadOnly:  true,
 		}}
 	}
+	entrypoint, err := framework.Entrypoint(agent.Name, agent.Deployment.Port)
+	if err != nil {
+		return nil, fmt.Errorf("Agent %s: framework %s/%s command: %w", agent.Name, framework.Framework, framework.Language, err)
+	}
+	args, err := framework.Arguments(agent.Name, agent.Deployment.Port, false)
+	if err != nil {
+		return nil, fmt.Errorf("Agent %s: framework %s/%s args: %w", agent.Name, framework.Framework, framework.Language, err)
+	}
+	byo := &v1alpha2.ByoDeploymentSpec{
+		Image:                agent.Deployment.Image,
+		Args:                 args,
+		SharedDeploymentSpec: sharedSpec,
+	}
+	// kagent takes the executable and its leading arguments apart.
+	if len(entrypoint) > 0 {
+		byo.Cmd = &entrypoint[0]
+		byo.Args = append(entrypoint[1:], args...)
+	}
 
 	agentSpec := v1alpha2.AgentSpec{
 		Description: agent.Name,
 		Type:        v1alpha2.AgentType_BYO,
 		BYO: &v1alpha2.BYOAgentSpec{
-			Deployment: &v1alpha2.ByoDeploymentSpec{
-				Image:                agent.Deployment.Image,
-				SharedDeploymentSpec: sharedSpec,
-			},
+			Deployment: byo,
 		},
 	}
 	if len(agent.Skills) > 0 {
@@ -465,7 +558,15 @@ func kubernetesTranslateLocalMCPServer(server *runtimetypes.MCPServer) (*kmcpv1a
 		Cmd:   server.Local.Deployment.Cmd,
 		Args:  server.Local.Deployment.Args,
 		Env:   server.Local.Deployment.Env,
+		// The deployment-id label on the pod is what Logs selects it by.
+		Labels: kubernetesDeploymentManagedLabels(server.DeploymentID),
+	}
+
+	resources, err := kubernetesResourceRequirements(server.Local.Deployment.Resources)
+	if err != nil {
+		return nil, fmt.Errorf("MCPServer %s: %w", server.Name, err)
 	}
+	deployment.Resources = resources
 
 	spec := kmcpv1alpha1.MCPServerSpec{Deployment: deployment}
 	switch server.Local.TransportType {
@@ -563,6 +664,55 @@ func kubernetesDeploymentManagedLabels(deploymentID string) map[string]string {
 	return labels
 }
 
+// kubernetesApplyCostTags adds tags to the labels of every resource in cfg
+// and of the pods kagent and kmcp run for them. Labels the registry sets
+// win over tags with the same key.
+func kubernetesApplyCostTags(cfg *runtimetypes.KubernetesRuntimeConfig, tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+	for _, configMap := range cfg.ConfigMaps {
+		configMap.Labels = kubernetesWithCostTags(configMap.Labels, tags)
+	}
+	for _, agent := range cfg.Agents {
+		agent.Labels = kubernetesWithCostTags(agent.Labels, tags)
+		if byo := agent.Spec.BYO; byo != nil && byo.Deployment != nil {
+			byo.Deployment.Labels = kubernetesWithCostTags(byo.Deployment.Labels, tags)
+		}
+	}
+	for _, server := range cfg.RemoteMCPServers {
+		server.Labels = kubernetesWithCostTags(server.Labels, tags)
+	}
+	for _, server := range cfg.MCPServers {
+		server.Labels = kubernetesWithCostTags(server.Labels, tags)
+		server.Spec.Deployment.Labels = kubernetesWithCostTags(server.Spec.Deployment.Labels, tags)
+	}
+}
+
+// kubernetesRuntimeObjects lists every resource in cfg, in apply order.
+func kubernetesRuntimeObjects(cfg *runtimetypes.KubernetesRuntimeConfig) []client.Object {
+	var objs []client.Object
+	for _, configMap := range cfg.ConfigMaps {
+		objs = append(objs, configMap)
+	}
+	for _, agent := range cfg.Agents {
+		objs = append(objs, agent)
+	}
+	for _, server := range cfg.RemoteMCPServers {
+		objs = append(objs, server)
+	}
+	for _, server := range cfg.MCPServers {
+		objs = append(objs, server)
+	}
+	return objs
+}
+
+func kubernetesWithCostTags(labels, tags map[string]string) map[string]string {
+	out := maps.Clone(tags)
+	maps.Copy(out, labels)
+	return out
+}
+
 func kubernetesDeploymentManagedAnnotations(deploymentID string) map[string]string {
 	if deploymentID == "" {
 		return nil
@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/frameworks"
 	runtimetypes "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/types"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 )
@@ -51,6 +52,126 @@ func TestKubernetesTranslateRuntimeConfig_AgentOnly(t *testing.T) {
 	}
 }
 
+func TestKubernetesTranslateRuntimeConfig_AgentSecretRefs(t *testing.T) {
+	desired := &runtimetypes.DesiredState{
+		Agents: []*runtimetypes.Agent{{
+			Name: "test-agent",
+			Tag:  "v1",
+			Deployment: runtimetypes.AgentDeployment{
+				Image: "agent-image:latest",
+				Env: map[string]string{
+					"PLAIN":          "value",
+					"OPENAI_API_KEY": "secretRef:agent-keys/openai",
+				},
+			},
+		}},
+	}
+
+	config, err := kubernetesTranslateRuntimeConfig(context.Background(), desired)
+	if err != nil {
+		t.Fatalf("kubernetesTranslateRuntimeConfig failed: %v", err)
+	}
+	envs := map[string]corev1.EnvVar{}
+	for _, env := range config.Agents[0].Spec.BYO.Deployment.Env {
+		envs[env.Name] = env
+	}
+	if envs["PLAIN"].Value != "value" || envs["PLAIN"].ValueFrom != nil {
+		t.Errorf("PLAIN = %+v, want inline value", envs["PLAIN"])
+	}
+	ref := envs["OPENAI_API_KEY"]
+	if ref.Value != "" || ref.ValueFrom == nil || ref.ValueFrom.SecretKeyRef == nil {
+		t.Fatalf("OPENAI_API_KEY = %+v, want secretKeyRef", ref)
+	}
+	if ref.ValueFrom.SecretKeyRef.Name != "agent-keys" || ref.ValueFrom.SecretKeyRef.Key != "openai" {
+		t.Errorf("secretKeyRef = %+v, want agent-keys/openai", ref.ValueFrom.SecretKeyRef)
+	}
+}
+
+func TestKubernetesTranslateRuntimeConfig_AgentGPUs(t *testing.T) {
+	desired := &runtimetypes.DesiredState{
+		Agents: []*runtimetypes.Agent{{
+			Name: "llm-agent",
+			Tag:  "v1",
+			Deployment: runtimetypes.AgentDeployment{
+				Image:   "llm-agent:latest",
+				GPUs:    2,
+				Devices: []string{"/dev/dri"},
+			},
+		}},
+	}
+
+	config, err := kubernetesTranslateRuntimeConfig(context.Background(), desired)
+	if err != nil {
+		t.Fatalf("kubernetesTranslateRuntimeConfig failed: %v", err)
+	}
+	resources := config.Agents[0].Spec.BYO.Deployment.Resources
+	if resources == nil {
+		t.Fatal("expected resource requirements")
+	}
+	gpus := resources.Limits[kubernetesGPUResource]
+	if gpus.Value() != 2 {
+		t.Errorf("nvidia.com/gpu limit = %s, want 2", gpus.String())
+	}
+	if len(resources.Limits) != 1 || len(resources.Requests) != 0 {
+		t.Errorf("resources = %+v, want only the GPU limit", resources)
+	}
+}
+
+func TestKubernetesTranslateRuntimeConfig_ComputeResources(t *testing.T) {
+	desired := &runtimetypes.DesiredState{
+		Agents: []*runtimetypes.Agent{{
+			Name: "sized-agent",
+			Tag:  "v1",
+			Deployment: runtimetypes.AgentDeployment{
+				Image: "sized-agent:latest",
+				GPUs:  1,
+				Resources: &v1alpha1.ResourceRequirements{
+					Requests: &v1alpha1.ComputeResources{CPU: "250m", Memory: "256Mi"},
+					Limits:   &v1alpha1.ComputeResources{CPU: "1", Memory: "1Gi"},
+				},
+			},
+		}},
+		MCPServers: []*runtimetypes.MCPServer{{
+			Name:          "sized-server",
+			MCPServerType: runtimetypes.MCPServerTypeLocal,
+			Local: &runtimetypes.LocalMCPServer{
+				TransportType: runtimetypes.TransportTypeStdio,
+				Deployment: runtimetypes.MCPServerDeployment{
+					Image:     "sized-server:latest",
+					Resources: &v1alpha1.ResourceRequirements{Limits: &v1alpha1.ComputeResources{Memory: "512Mi"}},
+				},
+			},
+		}},
+	}
+
+	config, err := kubernetesTranslateRuntimeConfig(context.Background(), desired)
+	if err != nil {
+		t.Fatalf("kubernetesTranslateRuntimeConfig failed: %v", err)
+	}
+	agent := config.Agents[0].Spec.BYO.Deployment.Resources
+	if agent == nil {
+		t.Fatal("expected agent resource requirements")
+	}
+	for name, want := range map[corev1.ResourceName]string{corev1.ResourceCPU: "250m", corev1.ResourceMemory: "256Mi"} {
+		if got := agent.Requests[name]; got.String() != want {
+			t.Errorf("agent request %s = %s, want %s", name, got.String(), want)
+		}
+	}
+	for name, want := range map[corev1.ResourceName]string{corev1.ResourceCPU: "1", corev1.ResourceMemory: "1Gi", kubernetesGPUResource: "1"} {
+		if got := agent.Limits[name]; got.String() != want {
+			t.Errorf("agent limit %s = %s, want %s", name, got.String(), want)
+		}
+	}
+
+	server := config.MCPServers[0].Spec.Deployment.Resources
+	if server == nil || len(server.Requests) != 0 || len(server.Limits) != 1 {
+		t.Fatalf("MCP server resources = %+v, want only a memory limit", server)
+	}
+	if got := server.Limits[corev1.ResourceMemory]; got.String() != "512Mi" {
+		t.Errorf("MCP server memory limit = %s, want 512Mi", got.String())
+	}
+}
+
 func TestKubernetesTranslateRuntimeConfig_RemoteMCP(t *testing.T) {
 	ctx := context.Background()
 
@@ -656,3 +777,41 @@ func TestKubernetesDeploymentScopedName_TruncatesLongBaseButPreservesSuffix(t *t
 		t.Fatalf("expected uuid short suffix to be preserved, got %s", got)
 	}
 }
+
+func TestKubernetesTranslateRuntimeConfig_FrameworkDescriptor(t *testing.T) {
+	desired := &runtimetypes.DesiredState{
+		Agents: []*runtimetypes.Agent{{
+			Name: "planner",
+			Tag:  "v1",
+			Deployment: runtimetypes.AgentDeployment{
+				Image: "planner:latest",
+				Port:  8000,
+				Env:   map[string]string{"KAGENT_NAMESPACE": "test-ns"},
+			},
+			ResolvedPrompts: []runtimetypes.ResolvedPrompt{{Name: "system", Content: "Plan."}},
+			Framework: &frameworks.Descriptor{
+				Framework:       "langgraph",
+				Language:        "python",
+				Command:         []string{"python", "-m", "runtime"},
+				Args:            []string{"--agent", "{{.Name}}"},
+				LocalArgs:       []string{"--local"},
+				ConfigMountPath: "/app/config",
+			},
+		}},
+	}
+
+	config, err := kubernetesTranslateRuntimeConfig(context.Background(), desired)
+	if err != nil {
+		t.Fatalf("kubernetesTranslateRuntimeConfig failed: %v", err)
+	}
+	byo := config.Agents[0].Spec.BYO.Deployment
+	if byo.Cmd == nil || *byo.Cmd != "python" {
+		t.Errorf("cmd = %v, want python", byo.Cmd)
+	}
+	if got := strings.Join(byo.Args, " "); got != "-m runtime --agent planner" {
+		t.Errorf("args = %q, want %q", got, "-m runtime --agent planner")
+	}
+	if got := byo.VolumeMounts[0].MountPath; got != "/app/config" {
+		t.Errorf("config mount = %q, want /app/config", got)
+	}
+}
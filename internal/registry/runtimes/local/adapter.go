@@ -2,9 +2,14 @@ package local
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/frameworks"
 	runtimetypes "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/types"
 	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/utils"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
@@ -18,26 +23,38 @@ import (
 type localDeploymentAdapter struct {
 	runtimeDir       string
 	agentGatewayPort uint16
+	mutators         []types.ManifestMutator
+	frameworks       *frameworks.Registry
 }
 
-// runLocalComposeUp / runLocalComposeDown are package vars rather than
-// direct calls so adapter_test.go can stub the docker-compose shell-out
-// without spinning up a real compose stack.
+// runLocalComposeUp / runLocalComposeDown / scanLocalContainers are package
+// vars rather than direct calls so adapter_test.go can stub the docker
+// shell-outs without spinning up a real compose stack.
 var (
 	runLocalComposeUp   = ComposeUpLocalRuntime
 	runLocalComposeDown = ComposeDownLocalRuntime
+	scanLocalContainers = ScanMCPContainers
 )
 
 // NewLocalDeploymentAdapter constructs an adapter pinned to a runtime
 // directory (docker-compose.yaml + agent-gateway.yaml live here) and the
-// port the agentgateway service binds.
-func NewLocalDeploymentAdapter(runtimeDir string, agentGatewayPort uint16) *localDeploymentAdapter {
+// port the agentgateway service binds. mutators edit the compose services
+// of each Deployment, in order, before they are written.
+func NewLocalDeploymentAdapter(runtimeDir string, agentGatewayPort uint16, mutators ...types.ManifestMutator) *localDeploymentAdapter {
 	return &localDeploymentAdapter{
 		runtimeDir:       runtimeDir,
 		agentGatewayPort: agentGatewayPort,
+		mutators:         mutators,
 	}
 }
 
+// WithFrameworks sets the descriptors the adapter picks agent image
+// layouts from. Without it only the built-in ones are known.
+func (a *localDeploymentAdapter) WithFrameworks(r *frameworks.Registry) *localDeploymentAdapter {
+	a.frameworks = r
+	return a
+}
+
 func (a *localDeploymentAdapter) Type() string { return v1alpha1.TypeLocal }
 
 // SupportedTargetKinds reports the v1alpha1 Kinds this adapter can deploy:
@@ -58,17 +75,17 @@ func (a *localDeploymentAdapter) Apply(ctx context.Context, in types.ApplyInput)
 	if in.Deployment == nil {
 		return nil, fmt.Errorf("apply: deployment is required")
 	}
-	desired, err := a.buildDesiredStateFromV1Alpha1(ctx, in)
+	cfg, err := a.build(ctx, in)
 	if err != nil {
 		return nil, err
 	}
-	cfg, err := BuildLocalRuntimeConfig(ctx, a.runtimeDir, a.agentGatewayPort, "", desired)
-	if err != nil {
-		return nil, fmt.Errorf("build local runtime config: %w", err)
-	}
 	if err := a.mergeAndApplyLocalRuntime(ctx, cfg, false); err != nil {
 		return nil, fmt.Errorf("apply local runtime: %w", err)
 	}
+	manifests, err := renderLocalManifests(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	now := time.Now().UTC()
 	gen := in.Deployment.Metadata.Generation
@@ -88,9 +105,81 @@ func (a *localDeploymentAdapter) Apply(ctx context.Context, in types.ApplyInput)
 			LastTransitionTime: now,
 			ObservedGeneration: gen,
 		}},
+		RuntimeMetadata: utils.EndpointMetadata(a.agentEndpoint(in)),
+		Manifests:       manifests,
 	}, nil
 }
 
+// Render translates the Deployment exactly as Apply does and returns the
+// compose services and gateway config without writing them or starting
+// anything.
+func (a *localDeploymentAdapter) Render(ctx context.Context, in types.ApplyInput) ([]types.RenderedManifest, error) {
+	if in.Deployment == nil {
+		return nil, fmt.Errorf("render: deployment is required")
+	}
+	cfg, err := a.build(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return renderLocalManifests(cfg)
+}
+
+// build translates the Deployment into the compose services and gateway
+// config it contributes and runs the adapter's manifest mutators on the
+// services.
+func (a *localDeploymentAdapter) build(ctx context.Context, in types.ApplyInput) (*runtimetypes.LocalRuntimeConfig, error) {
+	desired, err := a.buildDesiredStateFromV1Alpha1(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := BuildLocalRuntimeConfig(ctx, a.runtimeDir, a.agentGatewayPort, "", desired)
+	if err != nil {
+		return nil, fmt.Errorf("build local runtime config: %w", err)
+	}
+	if len(a.mutators) == 0 {
+		return cfg, nil
+	}
+	services := cfg.DockerCompose.Services
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		// The gateway service is shared by every local Deployment.
+		if name == localAgentGatewayServiceName {
+			continue
+		}
+		service := services[name]
+		if err := utils.MutateComposeService(ctx, a.mutators, in, &service); err != nil {
+			return nil, err
+		}
+		services[name] = service
+	}
+	return cfg, nil
+}
+
+// renderLocalManifests renders the compose services and gateway config
+// this Deployment contributed, not the merged files on disk, which also
+// hold every other local Deployment.
+func renderLocalManifests(cfg *runtimetypes.LocalRuntimeConfig) ([]types.RenderedManifest, error) {
+	var manifests []types.RenderedManifest
+	if cfg.DockerCompose != nil {
+		compose, err := cfg.DockerCompose.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("render docker compose config: %w", err)
+		}
+		m, err := utils.RenderManifest(localComposeFileName, json.RawMessage(compose))
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	if cfg.AgentGateway != nil {
+		m, err := utils.RenderManifest(localAgentGatewayFileName, cfg.AgentGateway)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
+
 // Remove tears down compose services attributed to this deployment.
 // Idempotent: if no services match the deployment name, the gateway
 // routes are still scrubbed and the method succeeds. Row lifetime is
@@ -119,14 +208,6 @@ func (a *localDeploymentAdapter) Remove(ctx context.Context, in types.RemoveInpu
 	}, nil
 }
 
-// Logs is not yet implemented for the local adapter. Returns an
-// immediately-closed channel so callers don't block.
-func (a *localDeploymentAdapter) Logs(ctx context.Context, in types.LogsInput) (<-chan types.LogLine, error) {
-	ch := make(chan types.LogLine)
-	close(ch)
-	return ch, nil
-}
-
 // buildDesiredStateFromV1Alpha1 constructs a *runtimetypes.DesiredState from
 // the v1alpha1 ApplyInput. The target dispatches by Kind:
 //   - MCPServer → one-shot translate; no ref walk.
@@ -149,23 +230,34 @@ func (a *localDeploymentAdapter) buildDesiredStateFromV1Alpha1(
 			EnvValues:    envValues,
 			ArgValues:    argValues,
 			HeaderValues: headerValues,
+			Resources:    in.Deployment.Spec.Resources,
 		})
 		if err != nil {
 			return nil, err
 		}
 		return &runtimetypes.DesiredState{MCPServers: []*runtimetypes.MCPServer{server}}, nil
 	case *v1alpha1.Agent:
-		var telemetryEndpoint string
+		for key, value := range envValues {
+			if strings.HasPrefix(value, v1alpha1.SecretRefPrefix) {
+				return nil, fmt.Errorf("spec.env.%s: the local runtime has no secret store; pass the value inline", key)
+			}
+		}
+		var telemetryEndpoint, registryURL string
 		if in.Runtime != nil {
 			telemetryEndpoint = in.Runtime.Spec.TelemetryEndpoint
+			registryURL = in.Runtime.Spec.RegistryURL
 		}
 		agent, servers, err := utils.SpecToRuntimeAgent(ctx, target.Metadata, target.Spec, utils.AgentTranslateOpts{
 			DeploymentID:      deploymentID,
 			KagentURL:         "http://localhost",
 			DeploymentEnv:     envValues,
 			TelemetryEndpoint: telemetryEndpoint,
+			RegistryURL:       registryURL,
 			HeaderValues:      headerValues,
 			Getter:            in.Getter,
+			SubAgentURL:       a.subAgentURL,
+			Resources:         in.Deployment.Spec.Resources,
+			Frameworks:        a.frameworks,
 		})
 		if err != nil {
 			return nil, err
@@ -179,6 +271,35 @@ func (a *localDeploymentAdapter) buildDesiredStateFromV1Alpha1(
 	}
 }
 
-// Compile-time assertion that the local adapter satisfies the v1alpha1
-// DeploymentAdapter contract.
-var _ types.DeploymentAdapter = (*localDeploymentAdapter)(nil)
+// agentEndpoint is the agent gateway URL a deployed Agent answers A2A on
+// from the host, empty for other targets.
+func (a *localDeploymentAdapter) agentEndpoint(in types.ApplyInput) string {
+	agent, ok := in.Target.(*v1alpha1.Agent)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("http://localhost:%d%s", a.agentGatewayPort, AgentRoutePrefix(agent.Metadata.Name, in.Deployment.Metadata.Name))
+}
+
+// MCPEndpoint implements types.MCPEndpointSource: the agent gateway route
+// that multiplexes every MCP server of the local runtime, the same one
+// local agents dial for their tools.
+func (a *localDeploymentAdapter) MCPEndpoint(_ context.Context, _ types.MCPEndpointInput) (string, error) {
+	return fmt.Sprintf("http://localhost:%d%s", a.agentGatewayPort, MCPRoutePrefix), nil
+}
+
+// subAgentURL is the address a sub-agent's container answers A2A on
+// inside the runtime's compose network.
+func (a *localDeploymentAdapter) subAgentURL(agent *v1alpha1.Agent, deploymentID string) string {
+	return fmt.Sprintf("http://%s:%d", utils.GenerateInternalNameForDeployment(agent.Metadata.Name, deploymentID), a.frameworks.For(agent.Metadata).AgentPort())
+}
+
+// Compile-time assertions that the local adapter satisfies the v1alpha1
+// DeploymentAdapter contract, can render dry runs, discovers containers and
+// exposes its MCP gateway route.
+var (
+	_ types.DeploymentAdapter         = (*localDeploymentAdapter)(nil)
+	_ types.DeploymentRenderer        = (*localDeploymentAdapter)(nil)
+	_ types.DeploymentDiscoverySource = (*localDeploymentAdapter)(nil)
+	_ types.MCPEndpointSource         = (*localDeploymentAdapter)(nil)
+)
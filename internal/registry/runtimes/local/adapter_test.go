@@ -3,13 +3,30 @@ package local
 import (
 	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
 
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/mutators"
 	"github.com/agentregistry-dev/agentregistry/pkg/types"
 )
 
+func TestAgentEndpoint_IsTheGatewayRoute(t *testing.T) {
+	adapter := NewLocalDeploymentAdapter(t.TempDir(), 21212)
+	got := adapter.agentEndpoint(types.ApplyInput{
+		Deployment: &v1alpha1.Deployment{Metadata: v1alpha1.ObjectMeta{Name: "summarizer-local"}},
+		Target:     &v1alpha1.Agent{Metadata: v1alpha1.ObjectMeta{Name: "summarizer"}},
+	})
+	if want := "http://localhost:21212" + AgentRoutePrefix("summarizer", "summarizer-local"); got != want {
+		t.Fatalf("agentEndpoint() = %q, want %q", got, want)
+	}
+}
+
 func TestV1Alpha1Apply_MCPServerTarget_WritesComposeAndMarksProgressing(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -91,6 +108,9 @@ func TestV1Alpha1Apply_MCPServerTarget_WritesComposeAndMarksProgressing(t *testi
 	if gotProgressing.ObservedGeneration != 7 {
 		t.Fatalf("Progressing.ObservedGeneration = %d, want 7", gotProgressing.ObservedGeneration)
 	}
+	if endpoint, ok := res.RuntimeMetadata[v1alpha1.DeploymentEndpointAnnotation]; !ok || endpoint != "" {
+		t.Fatalf("MCPServer deployments must clear the endpoint annotation, got %q (present %v)", endpoint, ok)
+	}
 
 	composePath := filepath.Join(tmpDir, "docker-compose.yaml")
 	if _, err := os.Stat(composePath); err != nil {
@@ -103,6 +123,13 @@ func TestV1Alpha1Apply_MCPServerTarget_WritesComposeAndMarksProgressing(t *testi
 	if !containsAll(string(contents), "ghcr.io/example/weather:v1", "agent_gateway") {
 		t.Fatalf("compose file missing expected content:\n%s", contents)
 	}
+
+	if len(res.Manifests) != 2 || res.Manifests[0].Name != "docker-compose.yaml" || res.Manifests[1].Name != "agent-gateway.yaml" {
+		t.Fatalf("Manifests = %+v, want docker-compose.yaml and agent-gateway.yaml", res.Manifests)
+	}
+	if !containsAll(res.Manifests[0].Content, "ghcr.io/example/weather:v1") {
+		t.Fatalf("compose manifest missing image:\n%s", res.Manifests[0].Content)
+	}
 }
 
 func TestV1Alpha1Remove_CallsComposeDown(t *testing.T) {
@@ -143,6 +170,51 @@ func TestV1Alpha1Remove_CallsComposeDown(t *testing.T) {
 	}
 }
 
+func TestV1Alpha1Render_WritesNothing(t *testing.T) {
+	tmpDir := t.TempDir()
+	adapter := NewLocalDeploymentAdapter(tmpDir, 21212)
+
+	manifests, err := adapter.Render(context.Background(), types.ApplyInput{
+		Deployment: &v1alpha1.Deployment{
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather-local"},
+			Spec: v1alpha1.DeploymentSpec{
+				TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather"},
+				RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "local"},
+			},
+		},
+		Target: &v1alpha1.MCPServer{
+			TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather"},
+			Spec: v1alpha1.MCPServerSpec{
+				Source: &v1alpha1.MCPServerSource{
+					Package: &v1alpha1.MCPPackage{
+						Origin: v1alpha1.MCPPackageOrigin{
+							Type:       v1alpha1.MCPPackageOriginTypeOCI,
+							Identifier: "ghcr.io/example/weather:v1",
+							OCI:        &v1alpha1.MCPPackageOriginOCI{ServerName: "weather"},
+						},
+						Transport: v1alpha1.MCPTransport{Type: "stdio"},
+					},
+				},
+			},
+		},
+		Runtime: &v1alpha1.Runtime{Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "local"}},
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if len(manifests) != 2 || !containsAll(manifests[0].Content, "ghcr.io/example/weather:v1") {
+		t.Fatalf("Manifests = %+v, want compose file with the weather image and gateway config", manifests)
+	}
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("read runtime dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("Render wrote %d files to the runtime dir, want none", len(entries))
+	}
+}
+
 func TestV1Alpha1SupportedTargetKinds(t *testing.T) {
 	adapter := NewLocalDeploymentAdapter(t.TempDir(), 21212)
 	kinds := adapter.SupportedTargetKinds()
@@ -159,9 +231,11 @@ func TestV1Alpha1SupportedTargetKinds(t *testing.T) {
 	}
 }
 
-func TestV1Alpha1Logs_ReturnsClosedChannel(t *testing.T) {
+func TestV1Alpha1Logs_NoServicesIsEmpty(t *testing.T) {
 	adapter := NewLocalDeploymentAdapter(t.TempDir(), 21212)
-	ch, err := adapter.Logs(context.Background(), types.LogsInput{})
+	ch, err := adapter.Logs(context.Background(), types.LogsInput{
+		Deployment: &v1alpha1.Deployment{Metadata: v1alpha1.ObjectMeta{Name: "weather-local"}},
+	})
 	if err != nil {
 		t.Fatalf("Logs: %v", err)
 	}
@@ -170,6 +244,49 @@ func TestV1Alpha1Logs_ReturnsClosedChannel(t *testing.T) {
 	}
 }
 
+func TestV1Alpha1Logs_ReadsComposeLogs(t *testing.T) {
+	tmpDir := t.TempDir()
+	compose := "services:\n  agent_gateway:\n    image: gateway\n  weather-weather-local:\n    image: weather\n  other-other-local:\n    image: other\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, localComposeFileName), []byte(compose), 0o644); err != nil {
+		t.Fatalf("write compose file: %v", err)
+	}
+	var gotArgs []string
+	original := localComposeLogsCommand
+	t.Cleanup(func() { localComposeLogsCommand = original })
+	localComposeLogsCommand = func(ctx context.Context, _ string, args ...string) *exec.Cmd {
+		gotArgs = args
+		return exec.CommandContext(ctx, "printf", "%s\\n",
+			"weather-1  | 2026-05-01T10:00:00.5Z listening on :3000",
+			"weather-1  | no timestamp")
+	}
+
+	adapter := NewLocalDeploymentAdapter(tmpDir, 21212)
+	ch, err := adapter.Logs(context.Background(), types.LogsInput{
+		Deployment: &v1alpha1.Deployment{Metadata: v1alpha1.ObjectMeta{Name: "weather-local"}},
+		Follow:     true,
+		TailLines:  50,
+	})
+	if err != nil {
+		t.Fatalf("Logs: %v", err)
+	}
+	var lines []types.LogLine
+	for line := range ch {
+		lines = append(lines, line)
+	}
+
+	wantArgs := []string{"--no-color", "--timestamps", "--follow", "--tail", "50", "weather-weather-local"}
+	if !slices.Equal(gotArgs, wantArgs) {
+		t.Fatalf("docker compose logs args = %v, want %v", gotArgs, wantArgs)
+	}
+	want := []types.LogLine{
+		{Timestamp: time.Date(2026, 5, 1, 10, 0, 0, 500_000_000, time.UTC), Stream: "weather-1", Line: "listening on :3000"},
+		{Stream: "weather-1", Line: "no timestamp"},
+	}
+	if !slices.Equal(lines, want) {
+		t.Fatalf("lines = %+v, want %+v", lines, want)
+	}
+}
+
 func containsAll(s string, needles ...string) bool {
 	for _, n := range needles {
 		if !contains(s, n) {
@@ -187,3 +304,52 @@ func contains(s, sub string) bool {
 	}
 	return false
 }
+
+// recordingMutator records the compose services it is given and labels
+// them.
+type recordingMutator struct {
+	services []string
+}
+
+func (*recordingMutator) Name() string { return "recording" }
+
+func (m *recordingMutator) MutateComposeService(_ context.Context, in types.ApplyInput, service *composetypes.ServiceConfig) error {
+	m.services = append(m.services, service.Name)
+	service.Labels = service.Labels.Add("deployment", in.Deployment.Metadata.Name)
+	return nil
+}
+
+func TestV1Alpha1Render_RunsManifestMutators(t *testing.T) {
+	recorder := &recordingMutator{}
+	adapter := NewLocalDeploymentAdapter(t.TempDir(), 21212, recorder, mutators.Proxy{HTTPSProxy: "http://proxy.corp:3128"})
+
+	manifests, err := adapter.Render(context.Background(), types.ApplyInput{
+		Deployment: &v1alpha1.Deployment{
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather-local"},
+		},
+		Target: &v1alpha1.MCPServer{
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather"},
+			Spec: v1alpha1.MCPServerSpec{
+				Source: &v1alpha1.MCPServerSource{
+					Package: &v1alpha1.MCPPackage{
+						Origin: v1alpha1.MCPPackageOrigin{
+							Type:       v1alpha1.MCPPackageOriginTypeOCI,
+							Identifier: "ghcr.io/example/weather:v1",
+							OCI:        &v1alpha1.MCPPackageOriginOCI{ServerName: "weather"},
+						},
+						Transport: v1alpha1.MCPTransport{Type: "stdio"},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if len(recorder.services) != 1 || recorder.services[0] == localAgentGatewayServiceName {
+		t.Fatalf("mutated services = %v, want only the weather service", recorder.services)
+	}
+	if !containsAll(manifests[0].Content, "deployment: weather-local", "HTTPS_PROXY: http://proxy.corp:3128", "https_proxy: http://proxy.corp:3128") {
+		t.Fatalf("compose manifest missing mutations:\n%s", manifests[0].Content)
+	}
+}
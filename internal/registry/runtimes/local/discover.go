@@ -0,0 +1,238 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+const (
+	// MCPServerNameLabel is the OCI label an MCP server image declares its
+	// registry name with; containers carrying it are discovery candidates.
+	MCPServerNameLabel = "io.modelcontextprotocol.server.name"
+	// DiscoveredContainersConfigKey is the Local Runtime spec.config key
+	// listing the running containers the runtime reports as discovered
+	// Deployments. `arctl mcp discover --docker` maintains it.
+	DiscoveredContainersConfigKey = "discoveredContainers"
+
+	composeProjectLabel = "com.docker.compose.project"
+)
+
+// MCPContainer is one running docker container that may serve MCP.
+type MCPContainer struct {
+	ID    string
+	Name  string
+	Image string
+	// ServerName is the container's MCPServerNameLabel; empty when unlabeled.
+	ServerName string
+	// Transport is "http" when the container publishes a TCP port and
+	// "stdio" when it has none but keeps stdin open; empty otherwise.
+	Transport string
+	// Ports maps each published container TCP port to its host port.
+	Ports map[uint16]uint16
+}
+
+// HostPort returns the host port published for containerPort, or for the
+// lowest published container port when containerPort is 0. Zero means
+// nothing is published.
+func (c MCPContainer) HostPort(containerPort uint16) uint16 {
+	if containerPort != 0 {
+		return c.Ports[containerPort]
+	}
+	keys := make([]uint16, 0, len(c.Ports))
+	for port := range c.Ports {
+		keys = append(keys, port)
+	}
+	if len(keys) == 0 {
+		return 0
+	}
+	return c.Ports[slices.Min(keys)]
+}
+
+// DiscoveredContainer is one spec.config.discoveredContainers entry: a
+// running container reported as a discovered Deployment of an MCPServer.
+type DiscoveredContainer struct {
+	Container string `json:"container" yaml:"container"`
+	MCPServer string `json:"mcpServer" yaml:"mcpServer"`
+	Tag       string `json:"tag,omitempty" yaml:"tag,omitempty"`
+}
+
+// DiscoveredContainers reads the discoveredContainers entries of a Local
+// Runtime's spec.config.
+func DiscoveredContainers(runtime *v1alpha1.Runtime) ([]DiscoveredContainer, error) {
+	if runtime == nil {
+		return nil, nil
+	}
+	raw, ok := runtime.Spec.Config[DiscoveredContainersConfigKey]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("encode spec.config.%s: %w", DiscoveredContainersConfigKey, err)
+	}
+	var out []DiscoveredContainer
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("spec.config.%s must be a list of {container, mcpServer, tag}: %w", DiscoveredContainersConfigKey, err)
+	}
+	return out, nil
+}
+
+// SetDiscoveredContainers replaces the discoveredContainers entries of a
+// Local Runtime's spec.config, leaving its other keys alone.
+func SetDiscoveredContainers(runtime *v1alpha1.Runtime, entries []DiscoveredContainer) {
+	if runtime.Spec.Config == nil {
+		runtime.Spec.Config = map[string]any{}
+	}
+	list := make([]any, 0, len(entries))
+	for _, e := range entries {
+		entry := map[string]any{"container": e.Container, "mcpServer": e.MCPServer}
+		if e.Tag != "" {
+			entry["tag"] = e.Tag
+		}
+		list = append(list, entry)
+	}
+	runtime.Spec.Config[DiscoveredContainersConfigKey] = list
+}
+
+// ScanMCPContainers lists the running docker containers outside the
+// runtime's own compose project. It returns every such container; callers
+// decide which ones serve MCP (by ServerName or a known image).
+func ScanMCPContainers(ctx context.Context) ([]MCPContainer, error) {
+	ids, err := dockerOutput(ctx, "ps", "--quiet", "--no-trunc")
+	if err != nil {
+		return nil, err
+	}
+	args := append([]string{"inspect"}, strings.Fields(string(ids))...)
+	if len(args) == 1 {
+		return nil, nil
+	}
+	data, err := dockerOutput(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseDockerInspect(data)
+}
+
+func dockerOutput(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	var stderrBuf bytes.Buffer
+	cmd.Stderr = &stderrBuf
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderrBuf.String()))
+	}
+	return out, nil
+}
+
+// dockerInspect is the subset of `docker inspect` output discovery reads.
+type dockerInspect struct {
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Config struct {
+		Image     string            `json:"Image"`
+		Labels    map[string]string `json:"Labels"`
+		OpenStdin bool              `json:"OpenStdin"`
+	} `json:"Config"`
+	NetworkSettings struct {
+		Ports map[string][]struct {
+			HostPort string `json:"HostPort"`
+		} `json:"Ports"`
+	} `json:"NetworkSettings"`
+}
+
+func parseDockerInspect(data []byte) ([]MCPContainer, error) {
+	var inspected []dockerInspect
+	if err := json.Unmarshal(data, &inspected); err != nil {
+		return nil, fmt.Errorf("decode docker inspect: %w", err)
+	}
+	out := make([]MCPContainer, 0, len(inspected))
+	for _, c := range inspected {
+		if c.Config.Labels[composeProjectLabel] == defaultLocalProjectName {
+			continue
+		}
+		container := MCPContainer{
+			ID:         c.ID,
+			Name:       strings.TrimPrefix(c.Name, "/"),
+			Image:      c.Config.Image,
+			ServerName: c.Config.Labels[MCPServerNameLabel],
+			Ports:      map[uint16]uint16{},
+		}
+		for spec, bindings := range c.NetworkSettings.Ports {
+			port, proto, _ := strings.Cut(spec, "/")
+			if proto != "tcp" || len(bindings) == 0 {
+				continue
+			}
+			containerPort, err := strconv.ParseUint(port, 10, 16)
+			if err != nil {
+				continue
+			}
+			hostPort, err := strconv.ParseUint(bindings[0].HostPort, 10, 16)
+			if err != nil {
+				continue
+			}
+			container.Ports[uint16(containerPort)] = uint16(hostPort)
+		}
+		switch {
+		case len(container.Ports) > 0:
+			container.Transport = "http"
+		case c.Config.OpenStdin:
+			container.Transport = "stdio"
+		}
+		out = append(out, container)
+	}
+	slices.SortFunc(out, func(a, b MCPContainer) int { return strings.Compare(a.Name, b.Name) })
+	return out, nil
+}
+
+// Discover reports the running containers listed in the Runtime's
+// spec.config.discoveredContainers as MCPServer workloads, so the discovery
+// controller keeps an origin=discovered Deployment for each while it runs.
+// Listed containers that are not running are left out and go stale.
+func (a *localDeploymentAdapter) Discover(ctx context.Context, in types.DiscoverInput) ([]types.DiscoveryResult, error) {
+	entries, err := DiscoveredContainers(in.Runtime)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	containers, err := scanLocalContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	running := make(map[string]MCPContainer, len(containers))
+	for _, c := range containers {
+		running[c.Name] = c
+	}
+	var out []types.DiscoveryResult
+	for _, entry := range entries {
+		c, ok := running[entry.Container]
+		if !ok || entry.MCPServer == "" {
+			continue
+		}
+		metadata := map[string]string{
+			"containerId":   c.ID,
+			"containerName": c.Name,
+			"image":         c.Image,
+		}
+		if c.Transport != "" {
+			metadata["transport"] = c.Transport
+		}
+		if port := c.HostPort(0); port != 0 {
+			metadata["port"] = strconv.Itoa(int(port))
+		}
+		out = append(out, types.DiscoveryResult{
+			TargetKind:      v1alpha1.KindMCPServer,
+			Name:            entry.MCPServer,
+			Tag:             entry.Tag,
+			RuntimeMetadata: metadata,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,118 @@
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+const inspectFixture = `[
+  {
+    "Id": "aaa111",
+    "Name": "/weather",
+    "Config": {
+      "Image": "ghcr.io/acme/weather-mcp:1.2.0",
+      "Labels": {"io.modelcontextprotocol.server.name": "io.github.acme/weather"}
+    },
+    "NetworkSettings": {"Ports": {
+      "8080/tcp": [{"HostIp": "0.0.0.0", "HostPort": "18080"}],
+      "9090/tcp": null,
+      "53/udp": [{"HostIp": "0.0.0.0", "HostPort": "5353"}]
+    }}
+  },
+  {
+    "Id": "bbb222",
+    "Name": "/files",
+    "Config": {"Image": "mcp/filesystem:latest", "Labels": {}, "OpenStdin": true},
+    "NetworkSettings": {"Ports": {}}
+  },
+  {
+    "Id": "ccc333",
+    "Name": "/agentregistry_runtime-agent_gateway-1",
+    "Config": {
+      "Image": "agentgateway:latest",
+      "Labels": {"com.docker.compose.project": "agentregistry_runtime", "io.modelcontextprotocol.server.name": "managed"}
+    },
+    "NetworkSettings": {"Ports": {"8080/tcp": [{"HostPort": "21212"}]}}
+  }
+]`
+
+func TestParseDockerInspect(t *testing.T) {
+	got, err := parseDockerInspect([]byte(inspectFixture))
+	if err != nil {
+		t.Fatalf("parseDockerInspect: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d containers, want 2 (managed compose project skipped): %+v", len(got), got)
+	}
+	files, weather := got[0], got[1]
+	if weather.Name != "weather" || weather.ServerName != "io.github.acme/weather" || weather.Transport != "http" {
+		t.Fatalf("weather = %+v", weather)
+	}
+	if weather.HostPort(0) != 18080 || weather.HostPort(8080) != 18080 || len(weather.Ports) != 1 {
+		t.Fatalf("weather ports = %+v", weather.Ports)
+	}
+	if files.Name != "files" || files.ServerName != "" || files.Transport != "stdio" || files.HostPort(0) != 0 {
+		t.Fatalf("files = %+v", files)
+	}
+}
+
+func TestDiscoveredContainersRoundTrip(t *testing.T) {
+	rt := &v1alpha1.Runtime{Spec: v1alpha1.RuntimeSpec{Type: v1alpha1.TypeLocal, Config: map[string]any{"other": true}}}
+	want := []DiscoveredContainer{{Container: "weather", MCPServer: "io.github.acme/weather", Tag: "1.2.0"}}
+	SetDiscoveredContainers(rt, want)
+	got, err := DiscoveredContainers(rt)
+	if err != nil {
+		t.Fatalf("DiscoveredContainers: %v", err)
+	}
+	if len(got) != 1 || got[0] != want[0] || rt.Spec.Config["other"] != true {
+		t.Fatalf("got %+v, config %+v", got, rt.Spec.Config)
+	}
+
+	rt.Spec.Config[DiscoveredContainersConfigKey] = "weather"
+	if _, err := DiscoveredContainers(rt); err == nil {
+		t.Fatal("expected an error for a malformed entry list")
+	}
+}
+
+func TestV1Alpha1Discover_ReportsListedRunningContainers(t *testing.T) {
+	original := scanLocalContainers
+	t.Cleanup(func() { scanLocalContainers = original })
+	var scans int
+	scanLocalContainers = func(context.Context) ([]MCPContainer, error) {
+		scans++
+		return parseDockerInspect([]byte(inspectFixture))
+	}
+
+	adapter := NewLocalDeploymentAdapter(t.TempDir(), 21212)
+	rt := &v1alpha1.Runtime{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "local"},
+		Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeLocal},
+	}
+
+	got, err := adapter.Discover(context.Background(), types.DiscoverInput{Runtime: rt})
+	if err != nil || len(got) != 0 || scans != 0 {
+		t.Fatalf("Discover without entries = %+v, %v after %d scans; want nothing and no scan", got, err, scans)
+	}
+
+	SetDiscoveredContainers(rt, []DiscoveredContainer{
+		{Container: "weather", MCPServer: "weather", Tag: "1.2.0"},
+		{Container: "stopped", MCPServer: "gone"},
+	})
+	got, err = adapter.Discover(context.Background(), types.DiscoverInput{Runtime: rt})
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d results, want only the running container: %+v", len(got), got)
+	}
+	r := got[0]
+	if r.TargetKind != v1alpha1.KindMCPServer || r.Name != "weather" || r.Tag != "1.2.0" {
+		t.Fatalf("result = %+v", r)
+	}
+	if r.RuntimeMetadata["containerId"] != "aaa111" || r.RuntimeMetadata["transport"] != "http" || r.RuntimeMetadata["port"] != "18080" {
+		t.Fatalf("runtime metadata = %+v", r.RuntimeMetadata)
+	}
+}
@@ -0,0 +1,115 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// localComposeLogsCommand builds the `docker compose logs` invocation; a
+// package var so tests can substitute a canned command.
+var localComposeLogsCommand = func(ctx context.Context, runtimeDir string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"compose", "logs"}, args...)...)
+	cmd.Dir = runtimeDir
+	return cmd
+}
+
+// Logs reads the Deployment's compose services through `docker compose
+// logs`, following them while ctx lives when in.Follow is set. Each
+// line's Stream is the container it came from; compose merges stdout and
+// stderr. A Deployment without compose services of its own (a remote MCP
+// server, or one not applied yet) has no logs.
+func (a *localDeploymentAdapter) Logs(ctx context.Context, in types.LogsInput) (<-chan types.LogLine, error) {
+	if in.Deployment == nil {
+		return nil, fmt.Errorf("logs: deployment is required")
+	}
+	services, err := a.deploymentServices(in.Deployment.Metadata.Name)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan types.LogLine)
+	if len(services) == 0 {
+		close(ch)
+		return ch, nil
+	}
+
+	args := []string{"--no-color", "--timestamps"}
+	if in.Follow {
+		args = append(args, "--follow")
+	}
+	if in.TailLines > 0 {
+		args = append(args, "--tail", strconv.Itoa(in.TailLines))
+	}
+	cmd := localComposeLogsCommand(ctx, a.runtimeDir, append(args, services...)...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("docker compose logs: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("docker compose logs: %w", err)
+	}
+	go func() {
+		defer close(ch)
+		// Wait reaps the process; its exit status says nothing the lines
+		// did not, and a cancelled follow always exits non-zero.
+		defer func() { _ = cmd.Wait() }()
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+		for scanner.Scan() {
+			select {
+			case ch <- parseComposeLogLine(scanner.Text()):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// deploymentServices returns the compose services Apply wrote for the
+// Deployment, found the way Remove finds them: by the deployment id in
+// their name.
+func (a *localDeploymentAdapter) deploymentServices(deploymentID string) ([]string, error) {
+	deploymentID = strings.TrimSpace(deploymentID)
+	if deploymentID == "" {
+		return nil, nil
+	}
+	composeCfg, err := LoadLocalDockerComposeConfig(a.runtimeDir)
+	if err != nil {
+		return nil, err
+	}
+	var services []string
+	for name := range composeCfg.Services {
+		if name != localAgentGatewayServiceName && strings.Contains(name, deploymentID) {
+			services = append(services, name)
+		}
+	}
+	slices.Sort(services)
+	return services, nil
+}
+
+// parseComposeLogLine splits a `docker compose logs --timestamps` line,
+// "weather-1  | 2026-05-01T10:00:00.123456789Z text", into its container,
+// timestamp and text. Parts that are missing are left empty.
+func parseComposeLogLine(raw string) types.LogLine {
+	var line types.LogLine
+	if container, rest, ok := strings.Cut(raw, " | "); ok {
+		line.Stream = strings.TrimSpace(container)
+		raw = rest
+	}
+	if ts, text, ok := strings.Cut(raw, " "); ok {
+		if at, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			line.Timestamp = at
+			raw = text
+		}
+	}
+	line.Line = raw
+	return line
+}
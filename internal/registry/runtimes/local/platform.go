@@ -0,0 +1,51 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// localHostPlatform is the platform the docker daemon runs containers for
+// natively. Docker Desktop on macOS and Windows runs a Linux VM of the
+// host's architecture, so this is linux/<GOARCH> everywhere unless
+// DOCKER_DEFAULT_PLATFORM, which docker itself honors, says otherwise.
+var localHostPlatform = func() string {
+	if platform := os.Getenv("DOCKER_DEFAULT_PLATFORM"); platform != "" {
+		return platform
+	}
+	return "linux/" + runtime.GOARCH
+}
+
+// selectLocalPlatform picks the platform to run image as from the ones it
+// is published for. No platforms leaves the choice to docker. A native
+// platform wins; otherwise the first linux one runs emulated, as amd64-only
+// images do on Apple Silicon.
+func selectLocalPlatform(image string, platforms []string) (string, error) {
+	if len(platforms) == 0 {
+		return "", nil
+	}
+	host := platformArch(localHostPlatform())
+	for _, platform := range platforms {
+		if platformArch(platform) == host {
+			return platform, nil
+		}
+	}
+	for _, platform := range platforms {
+		if strings.HasPrefix(platform, "linux/") {
+			return platform, nil
+		}
+	}
+	return "", fmt.Errorf("image %s is published only for %s; the local runtime runs linux containers",
+		image, strings.Join(platforms, ", "))
+}
+
+// platformArch drops the variant from an os/arch[/variant] platform.
+func platformArch(platform string) string {
+	parts := strings.SplitN(platform, "/", 3)
+	if len(parts) < 2 {
+		return platform
+	}
+	return parts[0] + "/" + parts[1]
+}
@@ -0,0 +1,40 @@
+package local
+
+import (
+	"testing"
+)
+
+func TestSelectLocalPlatform(t *testing.T) {
+	original := localHostPlatform
+	t.Cleanup(func() { localHostPlatform = original })
+	localHostPlatform = func() string { return "linux/arm64" }
+
+	tests := []struct {
+		name      string
+		platforms []string
+		want      string
+		wantErr   bool
+	}{
+		{name: "any platform", platforms: nil, want: ""},
+		{name: "native", platforms: []string{"linux/amd64", "linux/arm64/v8"}, want: "linux/arm64/v8"},
+		{name: "emulated", platforms: []string{"windows/amd64", "linux/amd64"}, want: "linux/amd64"},
+		{name: "no linux image", platforms: []string{"windows/amd64"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectLocalPlatform("ghcr.io/acme/agent:1", tt.platforms)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectLocalPlatform: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("selectLocalPlatform = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,9 +17,11 @@ import (
 	composetypes "github.com/compose-spec/compose-go/v2/types"
 	"go.yaml.in/yaml/v3"
 
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/frameworks"
 	runtimetypes "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/types"
 	runtimeutils "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/utils"
 	"github.com/agentregistry-dev/agentregistry/internal/version"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 )
 
 const (
@@ -26,6 +30,9 @@ const (
 	localAgentGatewayFileName = "agent-gateway.yaml"
 	defaultLocalProjectName   = "agentregistry_runtime"
 	localOCIServerPort        = 3000
+	// localAgentGatewayServiceName is the compose service of the
+	// agentgateway every local Deployment routes through.
+	localAgentGatewayServiceName = "agent_gateway"
 )
 
 func BuildLocalRuntimeConfig(
@@ -46,7 +53,7 @@ func BuildLocalRuntimeConfig(
 	}
 
 	dockerComposeServices := map[string]composetypes.ServiceConfig{
-		"agent_gateway": *agentGatewayService,
+		localAgentGatewayServiceName: *agentGatewayService,
 	}
 
 	for _, mcpServer := range desired.MCPServers {
@@ -293,6 +300,28 @@ func localAgentServiceName(agent *runtimetypes.Agent) string {
 	return runtimeutils.GenerateInternalNameForDeployment(agent.Name, agent.DeploymentID)
 }
 
+// MCPRoutePrefix is the agent gateway path that multiplexes every local MCP
+// server.
+const MCPRoutePrefix = "/mcp"
+
+// OAuthMCPRoutePrefix is the agent gateway path an OAuth protected remote
+// MCP server is served under. Such servers get a route of their own rather
+// than a target on the multiplexed MCPRoutePrefix route, so callers' tokens
+// are only ever forwarded to the server they were issued for.
+func OAuthMCPRoutePrefix(server *runtimetypes.MCPServer) string {
+	return MCPRoutePrefix + "/" + localMCPServiceName(server)
+}
+
+// oauthProtectedResourcePath is the RFC 9728 well-known prefix the gateway
+// serves protected resource metadata under.
+const oauthProtectedResourcePath = "/.well-known/oauth-protected-resource"
+
+// AgentRoutePrefix is the agent gateway path the Local Deployment named
+// deploymentName serves agentName under.
+func AgentRoutePrefix(agentName, deploymentName string) string {
+	return "/agents/" + runtimeutils.GenerateInternalNameForDeployment(agentName, deploymentName)
+}
+
 func translateLocalAgentGatewayService(runtimeDir string, port uint16) (*composetypes.ServiceConfig, error) {
 	if port == 0 {
 		return nil, fmt.Errorf("agent gateway port must be specified")
@@ -300,7 +329,7 @@ func translateLocalAgentGatewayService(runtimeDir string, port uint16) (*compose
 
 	image := fmt.Sprintf("%s/agentregistry-dev/agentregistry/arctl-agentgateway:%s", version.DockerRegistry, version.Version)
 	return &composetypes.ServiceConfig{
-		Name:    "agent_gateway",
+		Name:    localAgentGatewayServiceName,
 		Image:   image,
 		Command: []string{"-f", "/config/agent-gateway.yaml"},
 		Ports: []composetypes.ServicePortConfig{{
@@ -335,11 +364,21 @@ func translateLocalMCPServerToServiceConfig(server *runtimetypes.MCPServer) (*co
 	}
 	slices.SortStableFunc(envValues, func(a, b string) int { return cmp.Compare(a, b) })
 
+	platform, err := selectLocalPlatform(image, server.Local.Deployment.Platforms)
+	if err != nil {
+		return nil, err
+	}
+	deploy, err := localDeployConfig(server.Local.Deployment.Resources, 0)
+	if err != nil {
+		return nil, fmt.Errorf("MCPServer %s: %w", server.Name, err)
+	}
 	return &composetypes.ServiceConfig{
 		Name:        localMCPServiceName(server),
 		Image:       image,
+		Platform:    platform,
 		Command:     cmd,
 		Environment: composetypes.NewMappingWithEquals(envValues),
+		Deploy:      deploy,
 	}, nil
 }
 
@@ -348,6 +387,10 @@ func translateLocalAgentToServiceConfig(runtimeDir string, agent *runtimetypes.A
 	if image == "" {
 		return nil, fmt.Errorf("image must be specified for Agent %s", agent.Name)
 	}
+	platform, err := selectLocalPlatform(image, agent.Deployment.Platforms)
+	if err != nil {
+		return nil, err
+	}
 
 	var envValues []string
 	for k, v := range agent.Deployment.Env {
@@ -368,10 +411,22 @@ func translateLocalAgentToServiceConfig(runtimeDir string, agent *runtimetypes.A
 		agentConfigDir = filepath.Join(runtimeDir, agent.Name)
 	}
 
-	return &composetypes.ServiceConfig{
+	framework := frameworks.OrDefault(agent.Framework)
+	entrypoint, err := framework.Entrypoint(agent.Name, port)
+	if err != nil {
+		return nil, fmt.Errorf("Agent %s: framework %s/%s command: %w", agent.Name, framework.Framework, framework.Language, err)
+	}
+	args, err := framework.Arguments(agent.Name, port, true)
+	if err != nil {
+		return nil, fmt.Errorf("Agent %s: framework %s/%s args: %w", agent.Name, framework.Framework, framework.Language, err)
+	}
+
+	service := &composetypes.ServiceConfig{
 		Name:        localAgentServiceName(agent),
 		Image:       image,
-		Command:     []string{agent.Name, "--local", "--port", fmt.Sprintf("%d", port)},
+		Platform:    platform,
+		Entrypoint:  entrypoint,
+		Command:     args,
 		Environment: composetypes.NewMappingWithEquals(envValues),
 		Ports: []composetypes.ServicePortConfig{{
 			Target:    uint32(port),
@@ -380,9 +435,90 @@ func translateLocalAgentToServiceConfig(runtimeDir string, agent *runtimetypes.A
 		Volumes: []composetypes.ServiceVolumeConfig{{
 			Type:   composetypes.VolumeTypeBind,
 			Source: agentConfigDir,
-			Target: "/config",
+			Target: framework.ConfigPath(),
 		}},
-	}, nil
+		Devices: localDeviceMappings(agent.Deployment.Devices),
+	}
+	if service.Deploy, err = localDeployConfig(agent.Deployment.Resources, agent.Deployment.GPUs); err != nil {
+		return nil, fmt.Errorf("Agent %s: %w", agent.Name, err)
+	}
+	return service, nil
+}
+
+// localDeployConfig maps CPU and memory requests to compose
+// deploy.resources reservations, limits to limits, and gpus to an NVIDIA
+// device reservation. It returns nil when there is nothing to reserve.
+func localDeployConfig(resources *v1alpha1.ResourceRequirements, gpus int) (*composetypes.DeployConfig, error) {
+	if resources.IsZero() && gpus == 0 {
+		return nil, nil
+	}
+	var out composetypes.Resources
+	if resources != nil {
+		var err error
+		if out.Reservations, err = localResource(resources.Requests); err != nil {
+			return nil, err
+		}
+		if out.Limits, err = localResource(resources.Limits); err != nil {
+			return nil, err
+		}
+	}
+	if gpus > 0 {
+		// Compose reserves GPUs through the NVIDIA container toolkit;
+		// `arctl doctor` reports whether the host has it.
+		if out.Reservations == nil {
+			out.Reservations = &composetypes.Resource{}
+		}
+		out.Reservations.Devices = []composetypes.DeviceRequest{{
+			Driver:       "nvidia",
+			Count:        composetypes.DeviceCount(gpus),
+			Capabilities: []string{"gpu"},
+		}}
+	}
+	return &composetypes.DeployConfig{Resources: out}, nil
+}
+
+func localResource(c *v1alpha1.ComputeResources) (*composetypes.Resource, error) {
+	if c.IsZero() {
+		return nil, nil
+	}
+	out := &composetypes.Resource{}
+	if c.CPU != "" {
+		millicores, err := v1alpha1.ParseCPU(c.CPU)
+		if err != nil {
+			return nil, err
+		}
+		out.NanoCPUs = composetypes.NanoCPUs(float32(millicores) / 1000)
+	}
+	if c.Memory != "" {
+		bytes, err := v1alpha1.ParseMemory(c.Memory)
+		if err != nil {
+			return nil, err
+		}
+		out.MemoryBytes = composetypes.UnitBytes(bytes)
+	}
+	return out, nil
+}
+
+// localDeviceMappings converts validated HOST[:CONTAINER[:PERMISSIONS]]
+// entries to compose device mappings. The container path defaults to the
+// host path and permissions to rwm, as in `docker run --device`.
+func localDeviceMappings(devices []string) []composetypes.DeviceMapping {
+	if len(devices) == 0 {
+		return nil
+	}
+	mappings := make([]composetypes.DeviceMapping, 0, len(devices))
+	for _, device := range devices {
+		parts := strings.SplitN(device, ":", 3)
+		mapping := composetypes.DeviceMapping{Source: parts[0], Target: parts[0], Permissions: "rwm"}
+		if len(parts) > 1 {
+			mapping.Target = parts[1]
+		}
+		if len(parts) > 2 {
+			mapping.Permissions = parts[2]
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings
 }
 
 func sanitizeVersion(version string) string {
@@ -408,6 +544,7 @@ func sanitizeVersion(version string) string {
 
 func translateLocalAgentGatewayConfig(agentGatewayPort uint16, servers []*runtimetypes.MCPServer, agents []*runtimetypes.Agent) (*runtimetypes.AgentGatewayConfig, error) {
 	var targets []runtimetypes.MCPTarget
+	var agentRoutes []runtimetypes.LocalRoute
 
 	for _, server := range servers {
 		targetName := localMCPServiceName(server)
@@ -418,6 +555,14 @@ func translateLocalAgentGatewayConfig(agentGatewayPort uint16, servers []*runtim
 			mcpTarget.MCP = &runtimetypes.MCPTargetSpec{
 				Host: runtimeutils.BuildRemoteMCPURL(server.Remote),
 			}
+			if server.Remote.OAuth != nil {
+				routes, err := oauthMCPRoutes(server, mcpTarget)
+				if err != nil {
+					return nil, err
+				}
+				agentRoutes = append(agentRoutes, routes...)
+				continue
+			}
 		case runtimetypes.MCPServerTypeLocal:
 			switch server.Local.TransportType {
 			case runtimetypes.TransportTypeStdio:
@@ -450,14 +595,13 @@ func translateLocalAgentGatewayConfig(agentGatewayPort uint16, servers []*runtim
 		targets = append(targets, mcpTarget)
 	}
 
-	var agentRoutes []runtimetypes.LocalRoute
 	for _, agent := range agents {
 		agentServiceName := localAgentServiceName(agent)
 		route := runtimetypes.LocalRoute{
 			RouteName: fmt.Sprintf("%s_route", agentServiceName),
 			Matches: []runtimetypes.RouteMatch{{
 				Path: runtimetypes.PathMatch{
-					PathPrefix: fmt.Sprintf("/agents/%s", agentServiceName),
+					PathPrefix: AgentRoutePrefix(agent.Name, agent.DeploymentID),
 				},
 			}},
 			Backends: []runtimetypes.RouteBackend{{
@@ -484,7 +628,7 @@ func translateLocalAgentGatewayConfig(agentGatewayPort uint16, servers []*runtim
 	mcpRoute := runtimetypes.LocalRoute{
 		RouteName: localMCPRouteName,
 		Matches: []runtimetypes.RouteMatch{{
-			Path: runtimetypes.PathMatch{PathPrefix: "/mcp"},
+			Path: runtimetypes.PathMatch{PathPrefix: MCPRoutePrefix},
 		}},
 		Backends: []runtimetypes.RouteBackend{{
 			Weight: 100,
@@ -513,6 +657,55 @@ func translateLocalAgentGatewayConfig(agentGatewayPort uint16, servers []*runtim
 	}, nil
 }
 
+// oauthMCPRoutes serves an OAuth protected remote MCP server on its own
+// route, forwarding the caller's bearer token, and publishes its protected
+// resource metadata at the matching well-known path so clients can find
+// the authorization server. The metadata names the remote's own resource
+// indicator: a forwarded token must have been issued for the remote.
+func oauthMCPRoutes(server *runtimetypes.MCPServer, target runtimetypes.MCPTarget) ([]runtimetypes.LocalRoute, error) {
+	oauth := server.Remote.OAuth
+	scopes := oauth.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	metadata, err := json.Marshal(struct {
+		Resource               string   `json:"resource"`
+		AuthorizationServers   []string `json:"authorization_servers"`
+		ScopesSupported        []string `json:"scopes_supported"`
+		BearerMethodsSupported []string `json:"bearer_methods_supported"`
+	}{oauth.Resource, []string{oauth.Issuer}, scopes, []string{"header"}})
+	if err != nil {
+		return nil, fmt.Errorf("encode protected resource metadata for %s: %w", server.Name, err)
+	}
+
+	name := localMCPServiceName(server)
+	prefix := OAuthMCPRoutePrefix(server)
+	return []runtimetypes.LocalRoute{
+		{
+			RouteName: name + "_mcp_route",
+			Matches:   []runtimetypes.RouteMatch{{Path: runtimetypes.PathMatch{PathPrefix: prefix}}},
+			Policies: &runtimetypes.FilterOrPolicy{
+				BackendAuth: &runtimetypes.BackendAuth{Passthrough: &struct{}{}},
+			},
+			Backends: []runtimetypes.RouteBackend{{
+				Weight: 100,
+				MCP:    &runtimetypes.MCPBackend{Targets: []runtimetypes.MCPTarget{target}},
+			}},
+		},
+		{
+			RouteName: name + "_oauth_metadata_route",
+			Matches:   []runtimetypes.RouteMatch{{Path: runtimetypes.PathMatch{Exact: oauthProtectedResourcePath + prefix}}},
+			Policies: &runtimetypes.FilterOrPolicy{
+				DirectResponse: &runtimetypes.DirectResponse{
+					Status:  http.StatusOK,
+					Body:    string(metadata),
+					Headers: map[string]string{"Content-Type": "application/json"},
+				},
+			},
+		},
+	}, nil
+}
+
 func defaultAgentPort(agent *runtimetypes.Agent) uint16 {
 	if agent == nil || agent.Deployment.Port == 0 {
 		return runtimeutils.DefaultLocalAgentPort
@@ -526,7 +719,7 @@ func extractServiceNames(config *runtimetypes.LocalRuntimeConfig) []string {
 	}
 	names := make([]string, 0, len(config.DockerCompose.Services))
 	for name := range config.DockerCompose.Services {
-		if name == "agent_gateway" {
+		if name == localAgentGatewayServiceName {
 			continue
 		}
 		names = append(names, name)
@@ -639,7 +832,7 @@ func mergeAgentGatewayConfig(
 		routes = append(routes, runtimetypes.LocalRoute{
 			RouteName: localMCPRouteName,
 			Matches: []runtimetypes.RouteMatch{{
-				Path: runtimetypes.PathMatch{PathPrefix: "/mcp"},
+				Path: runtimetypes.PathMatch{PathPrefix: MCPRoutePrefix},
 			}},
 			Backends: []runtimetypes.RouteBackend{{
 				Weight: 100,
@@ -2,10 +2,15 @@ package local
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/frameworks"
 	runtimetypes "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/types"
 	runtimeutils "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/utils"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 )
 
 func TestBuildLocalRuntimeConfig_UsesDefaultAgentPortInGatewayRoute(t *testing.T) {
@@ -49,3 +54,171 @@ func TestDefaultAgentPort(t *testing.T) {
 		t.Fatalf("defaultAgentPort(custom) = %d, want 9090", got)
 	}
 }
+
+func TestTranslateLocalAgent_GPUsAndDevices(t *testing.T) {
+	service, err := translateLocalAgentToServiceConfig("/tmp/test-runtime", &runtimetypes.Agent{
+		Name: "llm-agent",
+		Deployment: runtimetypes.AgentDeployment{
+			Image:   "llm-agent:latest",
+			GPUs:    2,
+			Devices: []string{"/dev/dri", "/dev/snd:/dev/audio:r"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("translateLocalAgentToServiceConfig() unexpected error: %v", err)
+	}
+	if service.Deploy == nil || service.Deploy.Resources.Reservations == nil {
+		t.Fatal("expected a GPU reservation")
+	}
+	requests := service.Deploy.Resources.Reservations.Devices
+	if len(requests) != 1 || requests[0].Driver != "nvidia" || requests[0].Count != 2 ||
+		len(requests[0].Capabilities) != 1 || requests[0].Capabilities[0] != "gpu" {
+		t.Fatalf("device requests = %+v, want 2 nvidia gpus", requests)
+	}
+	want := []composetypes.DeviceMapping{
+		{Source: "/dev/dri", Target: "/dev/dri", Permissions: "rwm"},
+		{Source: "/dev/snd", Target: "/dev/audio", Permissions: "r"},
+	}
+	if !reflect.DeepEqual(service.Devices, want) {
+		t.Fatalf("devices = %+v, want %+v", service.Devices, want)
+	}
+
+	service, err = translateLocalAgentToServiceConfig("/tmp/test-runtime", &runtimetypes.Agent{
+		Name:       "plain-agent",
+		Deployment: runtimetypes.AgentDeployment{Image: "plain-agent:latest"},
+	})
+	if err != nil {
+		t.Fatalf("translateLocalAgentToServiceConfig() unexpected error: %v", err)
+	}
+	if service.Deploy != nil || service.Devices != nil {
+		t.Fatalf("agent without resources got deploy=%+v devices=%+v", service.Deploy, service.Devices)
+	}
+}
+
+func TestTranslateLocalAgent_ComputeResources(t *testing.T) {
+	service, err := translateLocalAgentToServiceConfig("/tmp/test-runtime", &runtimetypes.Agent{
+		Name: "sized-agent",
+		Deployment: runtimetypes.AgentDeployment{
+			Image: "sized-agent:latest",
+			GPUs:  1,
+			Resources: &v1alpha1.ResourceRequirements{
+				Requests: &v1alpha1.ComputeResources{CPU: "250m", Memory: "256Mi"},
+				Limits:   &v1alpha1.ComputeResources{CPU: "1.5", Memory: "1G"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("translateLocalAgentToServiceConfig() unexpected error: %v", err)
+	}
+	if service.Deploy == nil || service.Deploy.Resources.Limits == nil || service.Deploy.Resources.Reservations == nil {
+		t.Fatalf("deploy = %+v, want limits and reservations", service.Deploy)
+	}
+	limits, reservations := service.Deploy.Resources.Limits, service.Deploy.Resources.Reservations
+	if limits.NanoCPUs != 1.5 || limits.MemoryBytes != 1e9 {
+		t.Fatalf("limits = %+v, want 1.5 cpus and 1G", limits)
+	}
+	if reservations.NanoCPUs != 0.25 || reservations.MemoryBytes != 256<<20 || len(reservations.Devices) != 1 {
+		t.Fatalf("reservations = %+v, want 0.25 cpus, 256Mi and the GPU", reservations)
+	}
+}
+
+func TestTranslateLocalAgentGatewayConfig_OAuthRemoteGetsOwnRoute(t *testing.T) {
+	cfg, err := translateLocalAgentGatewayConfig(8081, []*runtimetypes.MCPServer{
+		{
+			Name:          "plain",
+			MCPServerType: runtimetypes.MCPServerTypeRemote,
+			Remote:        &runtimetypes.RemoteMCPTarget{Scheme: "https", Host: "plain.example", Port: 443, Path: "/mcp"},
+		},
+		{
+			Name:          "github",
+			MCPServerType: runtimetypes.MCPServerTypeRemote,
+			Remote: &runtimetypes.RemoteMCPTarget{
+				Scheme: "https", Host: "api.github.example", Port: 443, Path: "/mcp",
+				OAuth: &runtimetypes.RemoteOAuth{
+					Issuer:   "https://github.example/login/oauth",
+					Scopes:   []string{"repo"},
+					Resource: "https://api.github.example/mcp",
+				},
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("translateLocalAgentGatewayConfig() unexpected error: %v", err)
+	}
+
+	routes := cfg.Binds[0].Listeners[0].Routes
+	var names []string
+	for _, route := range routes {
+		names = append(names, route.RouteName)
+	}
+	wantNames := []string{localMCPRouteName, "github_mcp_route", "github_oauth_metadata_route"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("route names = %v, want %v", names, wantNames)
+	}
+
+	shared := routes[0].Backends[0].MCP.Targets
+	if len(shared) != 1 || shared[0].Name != "plain" {
+		t.Fatalf("shared MCP targets = %+v, want only plain", shared)
+	}
+
+	dedicated := routes[1]
+	if got := dedicated.Matches[0].Path.PathPrefix; got != "/mcp/github" {
+		t.Fatalf("dedicated route prefix = %q, want /mcp/github", got)
+	}
+	if dedicated.Policies == nil || dedicated.Policies.BackendAuth == nil || dedicated.Policies.BackendAuth.Passthrough == nil {
+		t.Fatalf("dedicated route policies = %+v, want backend auth passthrough", dedicated.Policies)
+	}
+	if targets := dedicated.Backends[0].MCP.Targets; len(targets) != 1 || targets[0].Name != "github" {
+		t.Fatalf("dedicated route targets = %+v, want only github", targets)
+	}
+
+	metadata := routes[2]
+	if got := metadata.Matches[0].Path.Exact; got != "/.well-known/oauth-protected-resource/mcp/github" {
+		t.Fatalf("metadata route path = %q", got)
+	}
+	want := `{"resource":"https://api.github.example/mcp","authorization_servers":["https://github.example/login/oauth"],"scopes_supported":["repo"],"bearer_methods_supported":["header"]}`
+	if got := metadata.Policies.DirectResponse.Body; got != want {
+		t.Fatalf("metadata body = %s, want %s", got, want)
+	}
+}
+
+func TestTranslateLocalAgent_FrameworkDescriptor(t *testing.T) {
+	service, err := translateLocalAgentToServiceConfig("/tmp/test-runtime", &runtimetypes.Agent{
+		Name:       "planner",
+		Deployment: runtimetypes.AgentDeployment{Image: "planner:latest", Port: 8000},
+		Framework: &frameworks.Descriptor{
+			Framework:       "langgraph",
+			Language:        "python",
+			Command:         []string{"python", "-m", "runtime"},
+			Args:            []string{"--agent", "{{.Name}}", "--port", "{{.Port}}"},
+			ConfigMountPath: "/app/config",
+		},
+	})
+	if err != nil {
+		t.Fatalf("translateLocalAgentToServiceConfig() unexpected error: %v", err)
+	}
+	if want := (composetypes.ShellCommand{"python", "-m", "runtime"}); !reflect.DeepEqual(service.Entrypoint, want) {
+		t.Errorf("entrypoint = %v, want %v", service.Entrypoint, want)
+	}
+	if want := (composetypes.ShellCommand{"--agent", "planner", "--port", "8000"}); !reflect.DeepEqual(service.Command, want) {
+		t.Errorf("command = %v, want %v", service.Command, want)
+	}
+	if service.Volumes[0].Target != "/app/config" {
+		t.Errorf("config mount = %q, want /app/config", service.Volumes[0].Target)
+	}
+
+	// Without a descriptor agents keep the ADK layout.
+	service, err = translateLocalAgentToServiceConfig("/tmp/test-runtime", &runtimetypes.Agent{
+		Name:       "summarizer",
+		Deployment: runtimetypes.AgentDeployment{Image: "summarizer:latest"},
+	})
+	if err != nil {
+		t.Fatalf("translateLocalAgentToServiceConfig() unexpected error: %v", err)
+	}
+	if want := (composetypes.ShellCommand{"summarizer", "--local", "--port", "8080"}); !reflect.DeepEqual(service.Command, want) || service.Entrypoint != nil {
+		t.Errorf("entrypoint, command = %v, %v, want image entrypoint and %v", service.Entrypoint, service.Command, want)
+	}
+	if service.Volumes[0].Target != "/config" {
+		t.Errorf("config mount = %q, want /config", service.Volumes[0].Target)
+	}
+}
@@ -279,8 +279,8 @@ type BackendTLS struct {
 }
 
 type BackendAuth struct {
-	Type   string `json:"type" yaml:"type"`
-	Config any    `json:"config,omitempty" yaml:"config,omitempty"`
+	// Passthrough forwards the caller's bearer token to the backend.
+	Passthrough *struct{} `json:"passthrough,omitempty" yaml:"passthrough,omitempty"`
 }
 
 type TimeoutPolicy struct {
@@ -5,6 +5,9 @@ import (
 	v1alpha2 "github.com/kagent-dev/kagent/go/api/v1alpha2"
 	kmcpv1alpha1 "github.com/kagent-dev/kmcp/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/frameworks"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 )
 
 type DesiredState struct {
@@ -20,6 +23,9 @@ type Agent struct {
 	ResolvedMCPServers []ResolvedMCPServerConfig `json:"resolvedMCPServers,omitempty"`
 	ResolvedPrompts    []ResolvedPrompt          `json:"resolvedPrompts,omitempty"`
 	Skills             []AgentSkillRef           `json:"skills,omitempty"`
+	// Framework is the image layout the translators run the agent with.
+	// nil means frameworks.ADK.
+	Framework *frameworks.Descriptor `json:"framework,omitempty"`
 }
 
 type AgentSkillRef struct {
@@ -37,6 +43,13 @@ type ResolvedMCPServerConfig struct {
 	Headers map[string]string `json:"headers,omitempty"`
 }
 
+// ResolvedSubAgent is one entry of the A2A_SUB_AGENTS_CONFIG env var: a
+// sub-agent and the A2A URL its Deployment serves it at.
+type ResolvedSubAgent struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
 type ResolvedPrompt struct {
 	Name    string `json:"name"`
 	Content string `json:"content"`
@@ -68,6 +81,16 @@ type RemoteMCPTarget struct {
 	Port    uint32
 	Path    string
 	Headers []HeaderValue
+	// OAuth is set when the remote is an OAuth protected resource; the
+	// gateway forwards callers' tokens to it.
+	OAuth *RemoteOAuth
+}
+
+// RemoteOAuth is the protected resource metadata of a remote MCP server.
+type RemoteOAuth struct {
+	Issuer   string
+	Scopes   []string
+	Resource string
 }
 
 type HeaderValue struct {
@@ -94,16 +117,27 @@ const (
 )
 
 type MCPServerDeployment struct {
-	Image string            `json:"image,omitempty"`
-	Cmd   string            `json:"cmd,omitempty"`
-	Args  []string          `json:"args,omitempty"`
-	Env   map[string]string `json:"env,omitempty"`
+	Image string `json:"image,omitempty"`
+	// Platforms are the platforms Image is published for; empty means any.
+	Platforms []string          `json:"platforms,omitempty"`
+	Cmd       string            `json:"cmd,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	// Resources are the server's CPU and memory requests and limits.
+	Resources *v1alpha1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 type AgentDeployment struct {
-	Image string            `json:"image,omitempty"`
-	Env   map[string]string `json:"env,omitempty"`
-	Port  uint16            `json:"port,omitempty"`
+	Image string `json:"image,omitempty"`
+	// Platforms are the platforms Image is published for; empty means any.
+	Platforms []string          `json:"platforms,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	Port      uint16            `json:"port,omitempty"`
+	// GPUs and Devices carry the agent's spec.resources request.
+	GPUs    int      `json:"gpus,omitempty"`
+	Devices []string `json:"devices,omitempty"`
+	// Resources are the agent's CPU and memory requests and limits.
+	Resources *v1alpha1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 type KubernetesRuntimeConfig struct {
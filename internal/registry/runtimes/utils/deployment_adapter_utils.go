@@ -48,8 +48,15 @@ type MCPServerRunRequest struct {
 	// HeaderValues are per-deployment header overrides resolved against
 	// Spec.Remote.Headers when the server is remote. Ignored for bundled.
 	HeaderValues map[string]string
+	// Secrets fills in {{secret "..."}} placeholders in remote header
+	// values. Nil means the runtime has no secret store, and a header that
+	// reads a secret fails the translation.
+	Secrets SecretLookup
 }
 
+// SecretLookup reads one secret from a runtime's secret store.
+type SecretLookup func(ctx context.Context, ref v1alpha1.HeaderSecretRef) (string, error)
+
 // TranslateMCPServer maps a v1alpha1 MCPServerSpec onto the runtime-internal
 // MCPServer. Dispatches on Spec.Source (bundled → local transport) vs
 // Spec.Remote (pre-running → remote transport). Validation enforces exactly
@@ -59,7 +66,7 @@ func TranslateMCPServer(ctx context.Context, req *MCPServerRunRequest) (*runtime
 		return nil, fmt.Errorf("mcp server run request is required")
 	}
 	if req.Spec.Remote != nil {
-		return translateRemoteMCPServer(req.Name, req.Spec.Remote, req.DeploymentID, req.HeaderValues)
+		return translateRemoteMCPServer(ctx, req.Name, req.Spec.Remote, req.DeploymentID, req.HeaderValues, req.Secrets)
 	}
 	if req.Spec.Source == nil || req.Spec.Source.Package == nil {
 		return nil, fmt.Errorf("no valid deployment method found for server: %s (no package or remote)", req.Name)
@@ -70,8 +77,16 @@ func TranslateMCPServer(ctx context.Context, req *MCPServerRunRequest) (*runtime
 // translateRemoteMCPServer emits a runtimetypes.MCPServer for a
 // pre-running remote endpoint. Header overrides resolve against the
 // remote's declared headers, with overrides taking precedence over
-// spec values.
-func translateRemoteMCPServer(name string, remote *v1alpha1.MCPRemote, deploymentID string, headerValues map[string]string) (*runtimetypes.MCPServer, error) {
+// spec values. Secret placeholders in the result are filled in through
+// secrets.
+func translateRemoteMCPServer(
+	ctx context.Context,
+	name string,
+	remote *v1alpha1.MCPRemote,
+	deploymentID string,
+	headerValues map[string]string,
+	secrets SecretLookup,
+) (*runtimetypes.MCPServer, error) {
 	if remote.URL == "" {
 		return nil, fmt.Errorf("remote mcp server %s has no URL", name)
 	}
@@ -79,7 +94,16 @@ func translateRemoteMCPServer(name string, remote *v1alpha1.MCPRemote, deploymen
 	headersMap := processHeaders(remote.Headers, headerValues)
 	headers := make([]runtimetypes.HeaderValue, 0, len(headersMap))
 	for k, v := range headersMap {
-		headers = append(headers, runtimetypes.HeaderValue{Name: k, Value: v})
+		value, err := v1alpha1.ExpandHeaderTemplate(v, func(ref v1alpha1.HeaderSecretRef) (string, error) {
+			if secrets == nil {
+				return "", fmt.Errorf("the runtime has no secret store; pass the header as a HEADER_%s deployment env value instead", k)
+			}
+			return secrets(ctx, ref)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", k, err)
+		}
+		headers = append(headers, runtimetypes.HeaderValue{Name: k, Value: value})
 	}
 
 	u, err := parseURL(remote.URL)
@@ -87,17 +111,26 @@ func translateRemoteMCPServer(name string, remote *v1alpha1.MCPRemote, deploymen
 		return nil, fmt.Errorf("failed to parse remote server url: %v", err)
 	}
 
+	target := &runtimetypes.RemoteMCPTarget{
+		Scheme:  u.scheme,
+		Host:    u.host,
+		Port:    u.port,
+		Path:    u.path,
+		Headers: headers,
+	}
+	if remote.OAuth != nil {
+		target.OAuth = &runtimetypes.RemoteOAuth{
+			Issuer:   remote.OAuth.Issuer,
+			Scopes:   slices.Clone(remote.OAuth.Scopes),
+			Resource: remote.ResourceIndicator(),
+		}
+	}
+
 	return &runtimetypes.MCPServer{
 		Name:          generateInternalName(name),
 		DeploymentID:  deploymentID,
 		MCPServerType: runtimetypes.MCPServerTypeRemote,
-		Remote: &runtimetypes.RemoteMCPTarget{
-			Scheme:  u.scheme,
-			Host:    u.host,
-			Port:    u.port,
-			Path:    u.path,
-			Headers: headers,
-		},
+		Remote:        target,
 	}, nil
 }
 
@@ -165,10 +198,11 @@ func translateLocalMCPServer(
 		MCPServerType: runtimetypes.MCPServerTypeLocal,
 		Local: &runtimetypes.LocalMCPServer{
 			Deployment: runtimetypes.MCPServerDeployment{
-				Image: config.Image,
-				Cmd:   cmd,
-				Args:  args,
-				Env:   envValues,
+				Image:     config.Image,
+				Platforms: config.Platforms,
+				Cmd:       cmd,
+				Args:      args,
+				Env:       envValues,
 			},
 			TransportType: transportType,
 			HTTP:          httpTransport,
@@ -338,6 +372,9 @@ type RegistryConfig struct {
 	Image   string
 	Command string
 	IsOCI   bool
+	// Platforms are the platforms Image is published for; empty for the
+	// multi-platform npm and PyPI runner images.
+	Platforms []string
 }
 
 // processArguments appends a package's argument list onto the running
@@ -477,8 +514,9 @@ func GetRegistryConfig(origin v1alpha1.MCPPackageOrigin) (RegistryConfig, []stri
 		}, []string{ref}, nil
 	case origin.OCI != nil:
 		return RegistryConfig{
-			Image: origin.Identifier,
-			IsOCI: true,
+			Image:     origin.Identifier,
+			IsOCI:     true,
+			Platforms: origin.OCI.Platforms,
 		}, nil, nil
 	default:
 		return RegistryConfig{}, nil, fmt.Errorf("unsupported MCPPackage origin: no sub-struct (NPM/PyPI/OCI) is set; Origin.Type=%q", origin.Type)
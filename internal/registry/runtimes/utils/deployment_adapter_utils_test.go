@@ -2,6 +2,7 @@ package utils
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	runtimetypes "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/types"
@@ -49,6 +50,60 @@ func TestTranslateMCPServer_RemoteAppliesHeaderOverridesAndDefaults(t *testing.T
 	}
 }
 
+func TestTranslateMCPServer_RemoteCarriesOAuth(t *testing.T) {
+	server, err := TranslateMCPServer(context.Background(), &MCPServerRunRequest{
+		Name: "github",
+		Spec: v1alpha1.MCPServerSpec{
+			Remote: &v1alpha1.MCPRemote{
+				Type:  "streamable-http",
+				URL:   "https://api.github.example/mcp",
+				OAuth: &v1alpha1.MCPRemoteOAuth{Issuer: "https://github.example/login/oauth", Scopes: []string{"repo"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("TranslateMCPServer() unexpected error: %v", err)
+	}
+	oauth := server.Remote.OAuth
+	if oauth == nil {
+		t.Fatal("expected remote OAuth config")
+	}
+	if oauth.Issuer != "https://github.example/login/oauth" || len(oauth.Scopes) != 1 || oauth.Scopes[0] != "repo" {
+		t.Fatalf("unexpected OAuth config: %+v", oauth)
+	}
+	// Without an explicit resource indicator the remote URL is the resource.
+	if oauth.Resource != "https://api.github.example/mcp" {
+		t.Fatalf("Resource = %q, want the remote URL", oauth.Resource)
+	}
+}
+
+func TestTranslateMCPServer_RemoteExpandsSecretHeaders(t *testing.T) {
+	req := &MCPServerRunRequest{
+		Name: "github",
+		Spec: v1alpha1.MCPServerSpec{
+			Remote: &v1alpha1.MCPRemote{
+				Type:    "streamable-http",
+				URL:     "https://api.github.example/mcp",
+				Headers: []v1alpha1.HTTPHeader{{Name: "Authorization", Value: `Bearer {{secret "github-token/token"}}`}},
+			},
+		},
+	}
+	if _, err := TranslateMCPServer(context.Background(), req); err == nil || !strings.Contains(err.Error(), "no secret store") {
+		t.Fatalf("expected a missing secret store error, got %v", err)
+	}
+
+	req.Secrets = func(_ context.Context, ref v1alpha1.HeaderSecretRef) (string, error) {
+		return "value-of-" + ref.String(), nil
+	}
+	server, err := TranslateMCPServer(context.Background(), req)
+	if err != nil {
+		t.Fatalf("TranslateMCPServer() unexpected error: %v", err)
+	}
+	if len(server.Remote.Headers) != 1 || server.Remote.Headers[0].Value != "Bearer value-of-github-token/token" {
+		t.Fatalf("unexpected headers: %+v", server.Remote.Headers)
+	}
+}
+
 func TestTranslateMCPServer_LocalDerivesDefaultsWhenLaunchNil(t *testing.T) {
 	server, err := TranslateMCPServer(context.Background(), &MCPServerRunRequest{
 		Name: "test/server",
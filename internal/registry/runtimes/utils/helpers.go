@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"net/url"
 	"strings"
 
 	"github.com/agentregistry-dev/agentregistry/internal/constants"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/frameworks"
 	runtimetypes "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/types"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 )
@@ -23,6 +25,12 @@ type MCPServerTranslateOpts struct {
 	EnvValues    map[string]string
 	ArgValues    map[string]string
 	HeaderValues map[string]string
+	// Secrets fills in secret placeholders in remote header values; see
+	// MCPServerRunRequest.Secrets.
+	Secrets SecretLookup
+	// Resources are the Deployment's resolved CPU and memory requirements.
+	// Only bundled servers run a container to apply them to.
+	Resources *v1alpha1.ResourceRequirements
 }
 
 // SpecToRuntimeMCPServer translates a v1alpha1 MCPServer envelope into the
@@ -42,11 +50,15 @@ func SpecToRuntimeMCPServer(
 		EnvValues:    nonNilStringMap(opts.EnvValues),
 		ArgValues:    nonNilStringMap(opts.ArgValues),
 		HeaderValues: nonNilStringMap(opts.HeaderValues),
+		Secrets:      opts.Secrets,
 	}
 	runtimeServer, err := TranslateMCPServer(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("translate mcp server %s@%s: %w", meta.Name, meta.Tag, err)
 	}
+	if runtimeServer.Local != nil {
+		runtimeServer.Local.Deployment.Resources = opts.Resources
+	}
 	if opts.Namespace != "" {
 		runtimeServer.Namespace = opts.Namespace
 	} else if meta.Namespace != "" && runtimeServer.Namespace == "" {
@@ -75,31 +87,65 @@ type AgentTranslateOpts struct {
 	// it lands as OTEL_EXPORTER_OTLP_ENDPOINT on the agent process. Explicit
 	// entries in DeploymentEnv take precedence.
 	TelemetryEndpoint string
+	// RegistryURL is Runtime.Spec.RegistryURL. When non-empty the agent's
+	// FeatureFlag evaluation URL lands as AGENT_REGISTRY_FLAGS_URL. Explicit
+	// entries in DeploymentEnv take precedence.
+	RegistryURL string
 	// HeaderValues are per-deployment header overrides for remote MCPServer
 	// refs (MCPServer.Spec.Remote.Headers), already split from
 	// Deployment.Spec.Env by the adapter via the HEADER_ prefix convention.
 	HeaderValues map[string]string
 	// Getter resolves AgentSpec.MCPServers refs to v1alpha1.MCPServer objects.
 	Getter v1alpha1.GetterFunc
+	// Secrets fills in secret placeholders in the headers of remote
+	// MCPServer refs; see MCPServerRunRequest.Secrets.
+	Secrets SecretLookup
+	// SubAgentURL returns the A2A URL the runtime serves agent at when it
+	// runs as the Deployment named deploymentID. Required when the agent
+	// declares spec.subAgents.
+	SubAgentURL func(agent *v1alpha1.Agent, deploymentID string) string
+	// Resources are the Deployment's resolved CPU and memory requirements
+	// (see resourcelimits), applied to the agent's own container.
+	Resources *v1alpha1.ResourceRequirements
+	// Frameworks picks the agent's image layout by its framework labels.
+	// nil knows only the built-in descriptors.
+	Frameworks *frameworks.Registry
 }
 
 // SpecToRuntimeAgent translates a v1alpha1 Agent envelope + Deployment
 // overrides into the runtime-internal *runtimetypes.Agent plus the set of
 // resolved MCPServers that should be deployed alongside it. Nested
-// AgentSpec.MCPServers refs are fetched via opts.Getter; dangling refs
-// surface as v1alpha1.ErrDanglingRef.
+// AgentSpec.MCPServers and AgentSpec.Skills refs, and the skills and MCP
+// servers those skills depend on, are fetched via opts.Getter; dangling refs
+// surface as v1alpha1.ErrDanglingRef and conflicting versions as
+// v1alpha1.ErrDependencyConflict. AgentSpec.SubAgents refs resolve to the
+// A2A URLs of the Deployments the controller runs them as.
 func SpecToRuntimeAgent(
 	ctx context.Context,
 	agentMeta v1alpha1.ObjectMeta,
 	agentSpec v1alpha1.AgentSpec,
 	opts AgentTranslateOpts,
 ) (*runtimetypes.Agent, []*runtimetypes.MCPServer, error) {
+	if err := ValidateAgentSecrets(agentSpec.Secrets, opts.DeploymentEnv); err != nil {
+		return nil, nil, err
+	}
+	framework := opts.Frameworks.For(agentMeta)
 	envValues := nonNilStringMap(opts.DeploymentEnv)
+	for k, v := range framework.Env {
+		if _, set := envValues[k]; !set {
+			envValues[k] = v
+		}
+	}
 	if opts.TelemetryEndpoint != "" {
 		if _, set := envValues["OTEL_EXPORTER_OTLP_ENDPOINT"]; !set {
 			envValues["OTEL_EXPORTER_OTLP_ENDPOINT"] = opts.TelemetryEndpoint
 		}
 	}
+	if opts.RegistryURL != "" {
+		if _, set := envValues[constants.EnvFlagsURL]; !set {
+			envValues[constants.EnvFlagsURL] = FlagsURL(opts.RegistryURL, agentMeta.NamespaceOrDefault(), agentMeta.Name)
+		}
+	}
 	if envValues[constants.EnvKagentNamespace] == "" {
 		switch {
 		case opts.Namespace != "":
@@ -122,35 +168,26 @@ func SpecToRuntimeAgent(
 		resolvedServers []*runtimetypes.MCPServer
 		resolvedConfigs []runtimetypes.ResolvedMCPServerConfig
 	)
-	for i, ref := range agentSpec.MCPServers {
-		normalized := ref
-		if normalized.Kind == "" {
-			normalized.Kind = v1alpha1.KindMCPServer
-		}
-		if normalized.Namespace == "" {
-			normalized.Namespace = agentMeta.Namespace
-		}
+	var deps v1alpha1.AgentDependencies
+	if len(agentSpec.MCPServers) > 0 || len(agentSpec.Skills) > 0 {
 		if opts.Getter == nil {
-			return nil, nil, fmt.Errorf("spec.mcpServers[%d]: getter required to resolve ref", i)
+			return nil, nil, fmt.Errorf("getter required to resolve spec.mcpServers and spec.skills refs")
 		}
-		obj, err := opts.Getter(ctx, normalized)
+		resolved, err := v1alpha1.ResolveAgentDependencies(ctx, opts.Getter, &v1alpha1.Agent{Metadata: agentMeta, Spec: agentSpec})
 		if err != nil {
-			return nil, nil, fmt.Errorf("spec.mcpServers[%d] resolve %s/%s: %w", i, normalized.Namespace, normalized.Name, err)
-		}
-		if normalized.Kind != v1alpha1.KindMCPServer {
-			return nil, nil, fmt.Errorf("spec.mcpServers[%d]: unsupported ref kind %q", i, normalized.Kind)
-		}
-		mcp, ok := obj.(*v1alpha1.MCPServer)
-		if !ok || mcp == nil {
-			return nil, nil, fmt.Errorf("spec.mcpServers[%d]: getter returned unexpected type for %s/%s", i, normalized.Namespace, normalized.Name)
+			return nil, nil, err
 		}
+		deps = *resolved
+	}
+	for _, mcp := range deps.MCPServers {
 		runtimeServer, err := SpecToRuntimeMCPServer(ctx, mcp.Metadata, mcp.Spec, MCPServerTranslateOpts{
 			DeploymentID: opts.DeploymentID,
 			Namespace:    opts.Namespace,
 			HeaderValues: opts.HeaderValues,
+			Secrets:      opts.Secrets,
 		})
 		if err != nil {
-			return nil, nil, fmt.Errorf("spec.mcpServers[%d]: %w", i, err)
+			return nil, nil, fmt.Errorf("MCPServer %s/%s: %w", mcp.Metadata.NamespaceOrDefault(), mcp.Metadata.Name, err)
 		}
 		resolvedServers = append(resolvedServers, runtimeServer)
 		if mcp.Spec.Remote != nil {
@@ -168,24 +205,88 @@ func SpecToRuntimeAgent(
 		envValues[constants.EnvMCPServersConfig] = string(encoded)
 	}
 
-	var image string
+	if len(agentSpec.SubAgents) > 0 {
+		subAgents, err := resolveSubAgents(ctx, agentMeta, agentSpec, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		encoded, err := json.Marshal(subAgents)
+		if err != nil {
+			return nil, nil, fmt.Errorf("marshal sub-agents config: %w", err)
+		}
+		envValues[constants.EnvSubAgentsConfig] = string(encoded)
+	}
+
+	var (
+		image     string
+		platforms []string
+	)
 	if agentSpec.Source != nil {
 		image = agentSpec.Source.Image
+		platforms = agentSpec.Source.Platforms
+	}
+	if image == "" {
+		image = framework.Image
 	}
 	agent := &runtimetypes.Agent{
 		Name:         agentMeta.Name,
 		Tag:          agentMeta.Tag,
 		DeploymentID: opts.DeploymentID,
 		Deployment: runtimetypes.AgentDeployment{
-			Image: image,
-			Env:   envValues,
-			Port:  DefaultLocalAgentPort,
+			Image:     image,
+			Platforms: platforms,
+			Env:       envValues,
+			Port:      framework.AgentPort(),
+			Resources: opts.Resources,
 		},
 		ResolvedMCPServers: resolvedConfigs,
+		Skills:             skillRefs(deps.Skills),
+		Framework:          &framework,
+	}
+	if agentSpec.Resources != nil {
+		agent.Deployment.GPUs = agentSpec.Resources.GPUs
+		agent.Deployment.Devices = agentSpec.Resources.Devices
 	}
 	return agent, resolvedServers, nil
 }
 
+// resolveSubAgents resolves the agent's spec.subAgents and pairs each with
+// the URL of the Deployment the controller runs it as
+// (v1alpha1.SubAgentDeploymentName).
+func resolveSubAgents(ctx context.Context, agentMeta v1alpha1.ObjectMeta, agentSpec v1alpha1.AgentSpec, opts AgentTranslateOpts) ([]runtimetypes.ResolvedSubAgent, error) {
+	if opts.Getter == nil || opts.SubAgentURL == nil {
+		return nil, fmt.Errorf("getter and sub-agent URL required to resolve spec.subAgents refs")
+	}
+	subAgents, err := v1alpha1.ResolveSubAgents(ctx, opts.Getter, &v1alpha1.Agent{Metadata: agentMeta, Spec: agentSpec})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]runtimetypes.ResolvedSubAgent, 0, len(subAgents))
+	for _, sub := range subAgents {
+		out = append(out, runtimetypes.ResolvedSubAgent{
+			Name: sub.Metadata.Name,
+			URL:  opts.SubAgentURL(sub, v1alpha1.SubAgentDeploymentName(opts.DeploymentID, sub.Metadata.Name)),
+		})
+	}
+	return out, nil
+}
+
+// ValidateAgentSecrets reports every Required secret the agent declares
+// that env leaves unset or empty. A secretRef value counts as provided; the
+// runtime resolves it.
+func ValidateAgentSecrets(secrets []v1alpha1.AgentSecret, env map[string]string) error {
+	var missing []string
+	for _, secret := range secrets {
+		if secret.Required && env[secret.Name] == "" {
+			missing = append(missing, secret.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required secrets: %s (set them in the Deployment's spec.env)", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // SplitDeploymentRuntimeInputs splits a Deployment.Spec.Env map into env /
 // arg / header buckets via the ARG_/HEADER_ prefix convention. Prefix-free
 // keys are plain env; ARG_<name> and HEADER_<name> route to arg and header
@@ -222,6 +323,34 @@ func mcpServerConfigFromSpec(name string, _ v1alpha1.MCPServerSpec, deploymentID
 	}
 }
 
+// skillRefs builds the runtime skill refs for the agent's resolved skills.
+// A skill materializes from the commit the Skill controller pinned, falling
+// back to the commit or branch its source names; skills without a git
+// source have nothing to materialize and are left out.
+func skillRefs(skills []*v1alpha1.Skill) []runtimetypes.AgentSkillRef {
+	var out []runtimetypes.AgentSkillRef
+	for _, skill := range skills {
+		if skill.Spec.Source == nil || skill.Spec.Source.Repository == nil || skill.Spec.Source.Repository.URL == "" {
+			continue
+		}
+		repo := skill.Spec.Source.Repository
+		ref := repo.Commit
+		if ref == "" {
+			ref = repo.Branch
+		}
+		if skill.Status.ResolvedSource != nil && skill.Status.ResolvedSource.Commit != "" {
+			ref = skill.Status.ResolvedSource.Commit
+		}
+		out = append(out, runtimetypes.AgentSkillRef{
+			Name:    skill.Metadata.Name,
+			RepoURL: repo.URL,
+			Ref:     ref,
+			Path:    repo.Subfolder,
+		})
+	}
+	return out
+}
+
 // remoteMCPServerConfig builds the per-server entry for an agent that
 // references a remote MCPServer (Spec.Remote set). Type is always "remote".
 // Headers come from the translated runtime server so required/default/
@@ -250,3 +379,32 @@ func nonNilStringMap(in map[string]string) map[string]string {
 	maps.Copy(out, in)
 	return out
 }
+
+// FlagsURL is the FeatureFlag evaluation URL for the agent name in
+// namespace on the registry at registryURL.
+func FlagsURL(registryURL, namespace, name string) string {
+	q := url.Values{}
+	q.Set("namespace", namespace)
+	q.Set("agent", name)
+	return strings.TrimSuffix(registryURL, "/") + "/v0/flags:evaluate?" + q.Encode()
+}
+
+// CostAllocationMetadata returns the ApplyResult.RuntimeMetadata entry
+// recording tags in v1alpha1.DeploymentCostAllocationAnnotation. Without
+// tags the entry is empty, which removes a previously recorded set.
+func CostAllocationMetadata(tags map[string]string) map[string]string {
+	value := ""
+	if len(tags) > 0 {
+		// A map of strings always marshals.
+		data, _ := json.Marshal(tags)
+		value = string(data)
+	}
+	return map[string]string{v1alpha1.DeploymentCostAllocationAnnotation: value}
+}
+
+// EndpointMetadata returns the ApplyResult.RuntimeMetadata entry recording
+// endpoint in v1alpha1.DeploymentEndpointAnnotation. An empty endpoint
+// removes a previously recorded one.
+func EndpointMetadata(endpoint string) map[string]string {
+	return map[string]string{v1alpha1.DeploymentEndpointAnnotation: endpoint}
+}
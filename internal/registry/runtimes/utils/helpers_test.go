@@ -3,8 +3,11 @@ package utils
 import (
 	"context"
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/frameworks"
 	runtimetypes "github.com/agentregistry-dev/agentregistry/internal/registry/runtimes/types"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 )
@@ -176,6 +179,45 @@ func TestSpecToRuntimeAgent_ResolvesMCPServerRefs(t *testing.T) {
 	}
 }
 
+func TestSpecToRuntimeAgent_WiresSubAgentURLs(t *testing.T) {
+	writer := &v1alpha1.Agent{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindAgent},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "writer", Tag: "1.0.0"},
+	}
+	getter := func(ctx context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		return writer, nil
+	}
+	agentSpec := v1alpha1.AgentSpec{
+		Source:    &v1alpha1.AgentSource{Image: "ghcr.io/example/planner:v1"},
+		SubAgents: []v1alpha1.ResourceRef{{Kind: v1alpha1.KindAgent, Name: "writer", Tag: "1.0.0"}},
+	}
+	meta := v1alpha1.ObjectMeta{Namespace: "default", Name: "planner", Tag: "1.0.0"}
+
+	_, _, err := SpecToRuntimeAgent(context.Background(), meta, agentSpec, AgentTranslateOpts{DeploymentID: "prod", Getter: getter})
+	if err == nil {
+		t.Fatal("expected an error without SubAgentURL")
+	}
+
+	agent, _, err := SpecToRuntimeAgent(context.Background(), meta, agentSpec, AgentTranslateOpts{
+		DeploymentID: "prod",
+		Getter:       getter,
+		SubAgentURL: func(agent *v1alpha1.Agent, deploymentID string) string {
+			return "http://" + agent.Metadata.Name + "." + deploymentID
+		},
+	})
+	if err != nil {
+		t.Fatalf("SpecToRuntimeAgent: %v", err)
+	}
+	var decoded []runtimetypes.ResolvedSubAgent
+	if err := json.Unmarshal([]byte(agent.Deployment.Env["A2A_SUB_AGENTS_CONFIG"]), &decoded); err != nil {
+		t.Fatalf("decode A2A_SUB_AGENTS_CONFIG: %v", err)
+	}
+	want := []runtimetypes.ResolvedSubAgent{{Name: "writer", URL: "http://writer.prod-writer"}}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Fatalf("A2A_SUB_AGENTS_CONFIG = %+v, want %+v", decoded, want)
+	}
+}
+
 func TestSpecToRuntimeAgent_ResolvesRemoteMCPServerHeaders(t *testing.T) {
 	remote := &v1alpha1.MCPServer{
 		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
@@ -234,6 +276,53 @@ func TestSpecToRuntimeAgent_ResolvesRemoteMCPServerHeaders(t *testing.T) {
 	}
 }
 
+func TestSpecToRuntimeAgent_ResolvesSkillDependencies(t *testing.T) {
+	tools := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "tools", Tag: "1.0.0"},
+		Spec: v1alpha1.MCPServerSpec{
+			Remote: &v1alpha1.MCPRemote{Type: "streamable-http", URL: "https://tools.example/mcp"},
+		},
+	}
+	research := &v1alpha1.Skill{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindSkill},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "research", Tag: "1.0.0"},
+		Spec: v1alpha1.SkillSpec{
+			Source: &v1alpha1.SkillSource{Repository: &v1alpha1.Repository{
+				URL: "https://github.com/acme/skills", Branch: "main", Subfolder: "research",
+			}},
+			MCPServers: []v1alpha1.ResourceRef{{Name: "tools", Tag: "1.0.0"}},
+		},
+		Status: v1alpha1.SkillStatus{ResolvedSource: &v1alpha1.SkillResolvedSource{Commit: "abc123"}},
+	}
+	getter := func(ctx context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		switch ref.Kind {
+		case v1alpha1.KindSkill:
+			return research, nil
+		case v1alpha1.KindMCPServer:
+			return tools, nil
+		}
+		return nil, v1alpha1.ErrDanglingRef
+	}
+
+	agent, servers, err := SpecToRuntimeAgent(
+		context.Background(),
+		v1alpha1.ObjectMeta{Namespace: "default", Name: "alice", Tag: "1.0.0"},
+		v1alpha1.AgentSpec{Skills: []v1alpha1.ResourceRef{{Kind: v1alpha1.KindSkill, Name: "research", Tag: "1.0.0"}}},
+		AgentTranslateOpts{DeploymentID: "dep-skills", Getter: getter},
+	)
+	if err != nil {
+		t.Fatalf("SpecToRuntimeAgent: %v", err)
+	}
+	if len(servers) != 1 || servers[0].Remote == nil {
+		t.Fatalf("resolved servers = %+v, want the skill's tools server", servers)
+	}
+	want := []runtimetypes.AgentSkillRef{{Name: "research", RepoURL: "https://github.com/acme/skills", Ref: "abc123", Path: "research"}}
+	if !reflect.DeepEqual(agent.Skills, want) {
+		t.Fatalf("agent skills = %+v, want %+v", agent.Skills, want)
+	}
+}
+
 func TestSpecToRuntimeAgent_NamespaceOptWinsOverMeta(t *testing.T) {
 	getter := func(ctx context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
 		t.Fatalf("getter should not be called when no refs; got %+v", ref)
@@ -253,6 +342,50 @@ func TestSpecToRuntimeAgent_NamespaceOptWinsOverMeta(t *testing.T) {
 	}
 }
 
+func TestSpecToRuntimeAgent_AppliesFrameworkDescriptor(t *testing.T) {
+	registry, err := frameworks.NewRegistry(frameworks.Descriptor{
+		Framework: "langgraph",
+		Language:  "python",
+		Image:     "ghcr.io/acme/langgraph-runtime:1.4",
+		Port:      8000,
+		Env:       map[string]string{"PYTHONUNBUFFERED": "1", "LOG_LEVEL": "info", "KAGENT_NAME": "ignored"},
+	})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	agentMeta := v1alpha1.ObjectMeta{Namespace: "default", Name: "planner", Tag: "1.0.0", Labels: map[string]string{
+		v1alpha1.AgentFrameworkLabel: "langgraph",
+		v1alpha1.AgentLanguageLabel:  "python",
+	}}
+	agent, _, err := SpecToRuntimeAgent(context.Background(), agentMeta, v1alpha1.AgentSpec{}, AgentTranslateOpts{
+		DeploymentEnv: map[string]string{"LOG_LEVEL": "debug"},
+		Frameworks:    registry,
+	})
+	if err != nil {
+		t.Fatalf("SpecToRuntimeAgent: %v", err)
+	}
+	if agent.Framework == nil || agent.Framework.Framework != "langgraph" {
+		t.Fatalf("framework = %+v, want langgraph", agent.Framework)
+	}
+	if agent.Deployment.Image != "ghcr.io/acme/langgraph-runtime:1.4" || agent.Deployment.Port != 8000 {
+		t.Fatalf("image, port = %q, %d, want the framework's", agent.Deployment.Image, agent.Deployment.Port)
+	}
+	env := agent.Deployment.Env
+	if env["PYTHONUNBUFFERED"] != "1" || env["LOG_LEVEL"] != "debug" || env["KAGENT_NAME"] != "planner" {
+		t.Fatalf("env = %v, want framework defaults under deployment and registry values", env)
+	}
+
+	// Unknown frameworks fall back to the ADK layout.
+	agentMeta.Labels[v1alpha1.AgentFrameworkLabel] = "crewai"
+	agent, _, err = SpecToRuntimeAgent(context.Background(), agentMeta, v1alpha1.AgentSpec{Source: &v1alpha1.AgentSource{Image: "planner:1.0.0"}}, AgentTranslateOpts{Frameworks: registry})
+	if err != nil {
+		t.Fatalf("SpecToRuntimeAgent: %v", err)
+	}
+	if agent.Framework.Framework != "adk" || agent.Deployment.Port != DefaultLocalAgentPort || agent.Deployment.Image != "planner:1.0.0" {
+		t.Fatalf("framework, port, image = %s, %d, %q, want adk defaults", agent.Framework.Framework, agent.Deployment.Port, agent.Deployment.Image)
+	}
+}
+
 func TestSpecToRuntimeAgent_DanglingRefPropagates(t *testing.T) {
 	getter := func(ctx context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
 		return nil, v1alpha1.ErrDanglingRef
@@ -269,6 +402,62 @@ func TestSpecToRuntimeAgent_DanglingRefPropagates(t *testing.T) {
 	}
 }
 
+func TestSpecToRuntimeAgent_MissingRequiredSecrets(t *testing.T) {
+	agentMeta := v1alpha1.ObjectMeta{Namespace: "default", Name: "alice", Tag: "1.0.0"}
+	agentSpec := v1alpha1.AgentSpec{
+		Secrets: []v1alpha1.AgentSecret{
+			{Name: "OPENAI_API_KEY", Required: true},
+			{Name: "SLACK_TOKEN", Required: true},
+			{Name: "OPTIONAL_TOKEN"},
+		},
+	}
+	_, _, err := SpecToRuntimeAgent(context.Background(), agentMeta, agentSpec, AgentTranslateOpts{
+		DeploymentEnv: map[string]string{"SLACK_TOKEN": ""},
+	})
+	if err == nil || !strings.Contains(err.Error(), "missing required secrets: OPENAI_API_KEY, SLACK_TOKEN") {
+		t.Fatalf("err = %v, want missing OPENAI_API_KEY, SLACK_TOKEN", err)
+	}
+
+	agent, _, err := SpecToRuntimeAgent(context.Background(), agentMeta, agentSpec, AgentTranslateOpts{
+		DeploymentEnv: map[string]string{
+			"OPENAI_API_KEY": "sk-test",
+			"SLACK_TOKEN":    "secretRef:alice/slack",
+		},
+	})
+	if err != nil {
+		t.Fatalf("SpecToRuntimeAgent: %v", err)
+	}
+	if agent.Deployment.Env["SLACK_TOKEN"] != "secretRef:alice/slack" {
+		t.Fatalf("SLACK_TOKEN = %q, want the secretRef passed through", agent.Deployment.Env["SLACK_TOKEN"])
+	}
+}
+
+func TestSpecToRuntimeAgent_FlagsURL(t *testing.T) {
+	agentMeta := v1alpha1.ObjectMeta{Namespace: "team-a", Name: "alice", Tag: "1.0.0"}
+	agent, _, err := SpecToRuntimeAgent(context.Background(), agentMeta, v1alpha1.AgentSpec{}, AgentTranslateOpts{
+		Namespace:   "kagent",
+		RegistryURL: "http://agentregistry.agentregistry.svc:12121/",
+	})
+	if err != nil {
+		t.Fatalf("SpecToRuntimeAgent: %v", err)
+	}
+	want := "http://agentregistry.agentregistry.svc:12121/v0/flags:evaluate?agent=alice&namespace=team-a"
+	if got := agent.Deployment.Env["AGENT_REGISTRY_FLAGS_URL"]; got != want {
+		t.Fatalf("AGENT_REGISTRY_FLAGS_URL = %q, want %q", got, want)
+	}
+
+	agent, _, err = SpecToRuntimeAgent(context.Background(), agentMeta, v1alpha1.AgentSpec{}, AgentTranslateOpts{
+		RegistryURL:   "http://agentregistry:12121",
+		DeploymentEnv: map[string]string{"AGENT_REGISTRY_FLAGS_URL": "http://flags.internal"},
+	})
+	if err != nil {
+		t.Fatalf("SpecToRuntimeAgent: %v", err)
+	}
+	if got := agent.Deployment.Env["AGENT_REGISTRY_FLAGS_URL"]; got != "http://flags.internal" {
+		t.Fatalf("AGENT_REGISTRY_FLAGS_URL = %q, want the Deployment's explicit value", got)
+	}
+}
+
 func TestSplitDeploymentRuntimeInputs_V1Alpha1Helper(t *testing.T) {
 	in := map[string]string{
 		"ENV_A":    "a",
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// RedactedValue replaces secret values in rendered manifests.
+const RedactedValue = "REDACTED"
+
+// secretKeyRegex matches keys whose string values are treated as secrets:
+// environment variables, headers and config fields such as API_KEY,
+// Authorization or dbPassword.
+var secretKeyRegex = regexp.MustCompile(`(?i)(secret|token|passw|api_?key|credential|private_?key|authorization|access_?key|cookie)`)
+
+// RenderManifest renders obj as a YAML manifest named name, with secret
+// values redacted. obj is encoded through encoding/json first, so JSON
+// tags decide field names; pass a json.RawMessage for types whose
+// MarshalJSON does not satisfy json.Marshaler.
+func RenderManifest(name string, obj any) (types.RenderedManifest, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return types.RenderedManifest{}, fmt.Errorf("render %s: %w", name, err)
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return types.RenderedManifest{}, fmt.Errorf("render %s: %w", name, err)
+	}
+	content, err := yaml.Marshal(Redact(tree))
+	if err != nil {
+		return types.RenderedManifest{}, fmt.Errorf("render %s: %w", name, err)
+	}
+	return types.RenderedManifest{Name: name, Content: string(content)}, nil
+}
+
+// Redact walks a decoded JSON tree and replaces secret values in place:
+//   - string values under secret-looking keys (environment maps, headers)
+//   - the value of {name, value} entries with a secret-looking name
+//     (Kubernetes env vars)
+//   - "KEY=value" list items with a secret-looking KEY (compose env lists)
+//   - passwords embedded in URLs
+//
+// Keys ending in "name" or "ref" are left alone: they point at a secret
+// (secretName, secretKeyRef) rather than hold one.
+func Redact(node any) any {
+	switch v := node.(type) {
+	case map[string]any:
+		if name, ok := v["name"].(string); ok && isSecretKey(name) {
+			if _, ok := v["value"].(string); ok {
+				v["value"] = RedactedValue
+			}
+		}
+		for key, child := range v {
+			if s, ok := child.(string); ok && isSecretKey(key) && s != "" {
+				v[key] = RedactedValue
+				continue
+			}
+			v[key] = Redact(child)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			if s, ok := child.(string); ok {
+				if key, _, found := strings.Cut(s, "="); found && isSecretKey(key) {
+					v[i] = key + "=" + RedactedValue
+					continue
+				}
+			}
+			v[i] = Redact(child)
+		}
+		return v
+	case string:
+		return redactURLPassword(v)
+	default:
+		return v
+	}
+}
+
+func isSecretKey(key string) bool {
+	lower := strings.ToLower(key)
+	if strings.HasSuffix(lower, "name") || strings.HasSuffix(lower, "ref") {
+		return false
+	}
+	return secretKeyRegex.MatchString(key)
+}
+
+func redactURLPassword(s string) string {
+	if !strings.Contains(s, "://") || !strings.Contains(s, "@") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); !ok {
+		return s
+	}
+	u.User = url.UserPassword(u.User.Username(), RedactedValue)
+	return u.String()
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderManifest_RedactsSecrets(t *testing.T) {
+	obj := map[string]any{
+		"services": map[string]any{
+			"bot": map[string]any{
+				"image": "ghcr.io/acme/bot:1.0.0",
+				"environment": map[string]any{
+					"OPENAI_API_KEY": "sk-live-123",
+					"LOG_LEVEL":      "debug",
+					"DATABASE_URL":   "postgres://bot:hunter2@db:5432/bot",
+				},
+				"command": []any{"run", "GITHUB_TOKEN=ghp_abc", "MODE=fast"},
+			},
+		},
+		"env": []any{
+			map[string]any{"name": "X-Token", "value": "supersecret"},
+			map[string]any{"name": "REGION", "value": "eu-west-1"},
+			map[string]any{"name": "DB_PASSWORD", "valueFrom": map[string]any{
+				"secretKeyRef": map[string]any{"name": "db", "key": "password"},
+			}},
+		},
+		"secretName": "bot-tls",
+	}
+
+	got, err := RenderManifest("docker-compose.yaml", obj)
+	if err != nil {
+		t.Fatalf("RenderManifest: %v", err)
+	}
+	if got.Name != "docker-compose.yaml" {
+		t.Errorf("Name = %q", got.Name)
+	}
+	for _, leaked := range []string{"sk-live-123", "hunter2", "ghp_abc", "supersecret"} {
+		if strings.Contains(got.Content, leaked) {
+			t.Errorf("manifest leaks %q:\n%s", leaked, got.Content)
+		}
+	}
+	for _, kept := range []string{
+		"image: ghcr.io/acme/bot:1.0.0",
+		"LOG_LEVEL: debug",
+		"OPENAI_API_KEY: REDACTED",
+		"GITHUB_TOKEN=REDACTED",
+		"MODE=fast",
+		"value: eu-west-1",
+		"key: password",
+		"secretName: bot-tls",
+		"postgres://bot:REDACTED@db:5432/bot",
+	} {
+		if !strings.Contains(got.Content, kept) {
+			t.Errorf("manifest missing %q:\n%s", kept, got.Content)
+		}
+	}
+}
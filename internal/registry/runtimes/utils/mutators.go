@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// MutateComposeService runs every ComposeServiceMutator in mutators, in
+// order, on service.
+func MutateComposeService(ctx context.Context, mutators []types.ManifestMutator, in types.ApplyInput, service *composetypes.ServiceConfig) error {
+	for _, m := range mutators {
+		cm, ok := m.(types.ComposeServiceMutator)
+		if !ok {
+			continue
+		}
+		if err := cm.MutateComposeService(ctx, in, service); err != nil {
+			return fmt.Errorf("manifest mutator %q: service %s: %w", m.Name(), service.Name, err)
+		}
+	}
+	return nil
+}
+
+// MutateKubernetesObjects runs every KubernetesObjectMutator in mutators,
+// in order, on each of objs.
+func MutateKubernetesObjects(ctx context.Context, mutators []types.ManifestMutator, in types.ApplyInput, objs []client.Object) error {
+	for _, m := range mutators {
+		km, ok := m.(types.KubernetesObjectMutator)
+		if !ok {
+			continue
+		}
+		for _, obj := range objs {
+			if err := km.MutateKubernetesObject(ctx, in, obj); err != nil {
+				return fmt.Errorf("manifest mutator %q: %s %s: %w", m.Name(), obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+// Package scheduler places Deployments that select their Runtime
+// (v1alpha1.DeploymentSpec.RuntimeSelector) instead of naming one. It is
+// wired in as a Deployment Prepare hook, so a Deployment is placed before
+// it is written: the stored row carries a concrete spec.runtimeRef that the
+// deployment controller reconciles like any other, and the decision is
+// recorded in the v1alpha1.DeploymentPlacementAnnotation annotation.
+//
+// Among the Runtimes in the Deployment's namespace that match the selector
+// and have room under their spec.capacity, the one serving the fewest
+// Deployments wins, ties broken by name. A Deployment that already sits on
+// a matching Runtime stays there.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+const listPageSize = 200
+
+// Lister lists the rows of one kind. *v1alpha1store.Store satisfies it.
+type Lister interface {
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+}
+
+// NoRuntimeError reports a Deployment its RuntimeSelector can't place. It
+// matches pkgdb.ErrConflict so apply handlers answer 409.
+type NoRuntimeError struct {
+	Namespace string
+	Selector  string
+	// Matched counts the Runtimes that match the selector but are full.
+	Matched int
+}
+
+func (e *NoRuntimeError) Error() string {
+	if e.Matched == 0 {
+		return fmt.Sprintf("no Runtime in namespace %s matches runtimeSelector %s", e.Namespace, e.Selector)
+	}
+	return fmt.Sprintf("all %d Runtimes in namespace %s matching runtimeSelector %s are at capacity", e.Matched, e.Namespace, e.Selector)
+}
+
+// Is makes errors.Is(err, pkgdb.ErrConflict) hold.
+func (e *NoRuntimeError) Is(target error) bool {
+	return target == pkgdb.ErrConflict
+}
+
+// Scheduler places Deployments on Runtimes.
+type Scheduler struct {
+	runtimes    Lister
+	deployments Lister
+}
+
+// New returns a Scheduler reading Runtimes and Deployments from the given
+// stores.
+func New(runtimes, deployments Lister) *Scheduler {
+	return &Scheduler{runtimes: runtimes, deployments: deployments}
+}
+
+// Prepare returns a Deployment Prepare hook that runs next, then places
+// the Deployment if it selects its Runtime.
+func (s *Scheduler) Prepare(next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		deployment, ok := obj.(*v1alpha1.Deployment)
+		if !ok {
+			return nil
+		}
+		return s.Place(ctx, deployment)
+	}
+}
+
+// Place fills in spec.runtimeRef and the placement annotation of a
+// Deployment that sets spec.runtimeSelector without naming a Runtime.
+// Other Deployments are left alone.
+func (s *Scheduler) Place(ctx context.Context, deployment *v1alpha1.Deployment) error {
+	selector := deployment.Spec.RuntimeSelector
+	if selector == nil || deployment.Spec.RuntimeRef.Name != "" {
+		return nil
+	}
+	namespace := deployment.Metadata.NamespaceOrDefault()
+
+	runtimes, err := listAll(ctx, s.runtimes, v1alpha1store.ListOpts{
+		Namespace:     namespace,
+		LabelSelector: selector.MatchLabels,
+	}, v1alpha1.KindRuntime, func() *v1alpha1.Runtime { return &v1alpha1.Runtime{} })
+	if err != nil {
+		return err
+	}
+	deployments, err := listAll(ctx, s.deployments, v1alpha1store.ListOpts{
+		Namespace: namespace,
+	}, v1alpha1.KindDeployment, func() *v1alpha1.Deployment { return &v1alpha1.Deployment{} })
+	if err != nil {
+		return err
+	}
+
+	// Load counts the other live Deployments on each Runtime of the
+	// namespace; previous is this Deployment as stored, if it is.
+	load := map[string]int{}
+	var previous *v1alpha1.Deployment
+	for _, d := range deployments {
+		if d.Metadata.Name == deployment.Metadata.Name {
+			previous = d
+			continue
+		}
+		ref := d.Spec.RuntimeRef
+		if d.Spec.DesiredState == v1alpha1.DesiredStateUndeployed || (ref.Namespace != "" && ref.Namespace != namespace) {
+			continue
+		}
+		load[ref.Name]++
+	}
+
+	var candidates []*v1alpha1.Runtime
+	for _, runtime := range runtimes {
+		if !selector.Matches(runtime) {
+			continue
+		}
+		if previous != nil && previous.Spec.RuntimeRef.Name == runtime.Metadata.Name &&
+			(previous.Spec.RuntimeRef.Namespace == "" || previous.Spec.RuntimeRef.Namespace == namespace) {
+			place(deployment, runtime.Metadata.Name, previous.Metadata.Annotations[v1alpha1.DeploymentPlacementAnnotation])
+			return nil
+		}
+		candidates = append(candidates, runtime)
+	}
+
+	var chosen *v1alpha1.Runtime
+	withRoom := 0
+	for _, runtime := range candidates {
+		if !hasRoom(runtime, load[runtime.Metadata.Name]) {
+			continue
+		}
+		withRoom++
+		if chosen == nil || load[runtime.Metadata.Name] < load[chosen.Metadata.Name] ||
+			(load[runtime.Metadata.Name] == load[chosen.Metadata.Name] && runtime.Metadata.Name < chosen.Metadata.Name) {
+			chosen = runtime
+		}
+	}
+	if chosen == nil {
+		return &NoRuntimeError{Namespace: namespace, Selector: describe(selector), Matched: len(candidates)}
+	}
+	place(deployment, chosen.Metadata.Name, fmt.Sprintf("placed on %s, the least loaded of %d Runtimes matching %s with room (%s)",
+		chosen.Metadata.Name, withRoom, describe(selector), describeLoad(chosen, load[chosen.Metadata.Name])))
+	return nil
+}
+
+// hasRoom reports whether runtime can take one more Deployment on top of
+// load.
+func hasRoom(runtime *v1alpha1.Runtime, load int) bool {
+	c := runtime.Spec.Capacity
+	return c == nil || c.MaxDeployments == 0 || load < c.MaxDeployments
+}
+
+func place(deployment *v1alpha1.Deployment, runtime, decision string) {
+	deployment.Spec.RuntimeRef = v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: runtime}
+	if decision == "" {
+		return
+	}
+	if deployment.Metadata.Annotations == nil {
+		deployment.Metadata.Annotations = map[string]string{}
+	}
+	deployment.Metadata.Annotations[v1alpha1.DeploymentPlacementAnnotation] = decision
+}
+
+// describe renders a selector as type=...,key=value in a stable order.
+func describe(selector *v1alpha1.RuntimeSelector) string {
+	var parts []string
+	if selector.Type != "" {
+		parts = append(parts, "type="+selector.Type)
+	}
+	for _, key := range slices.Sorted(maps.Keys(selector.MatchLabels)) {
+		parts = append(parts, key+"="+selector.MatchLabels[key])
+	}
+	if len(parts) == 0 {
+		return "{}"
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func describeLoad(runtime *v1alpha1.Runtime, load int) string {
+	if c := runtime.Spec.Capacity; c != nil && c.MaxDeployments > 0 {
+		return fmt.Sprintf("%d of %d Deployments", load, c.MaxDeployments)
+	}
+	return fmt.Sprintf("%d Deployments", load)
+}
+
+func listAll[T v1alpha1.Object](ctx context.Context, lister Lister, opts v1alpha1store.ListOpts, kind string, newObj func() T) ([]T, error) {
+	opts.Limit = listPageSize
+	var out []T
+	for {
+		rows, cursor, err := lister.List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("list %ss: %w", kind, err)
+		}
+		for _, raw := range rows {
+			obj, err := v1alpha1.EnvelopeFromRaw(newObj, raw, kind)
+			if err != nil {
+				return nil, fmt.Errorf("decode %s: %w", kind, err)
+			}
+			out = append(out, obj)
+		}
+		if cursor == "" {
+			return out, nil
+		}
+		opts.Cursor = cursor
+	}
+}
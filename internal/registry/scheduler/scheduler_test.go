@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// fakeLister answers List with the rows in opts.Namespace whose labels
+// contain opts.LabelSelector, in a single page.
+type fakeLister struct {
+	rows []*v1alpha1.RawObject
+}
+
+func (f fakeLister) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	var out []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		if row.Metadata.Namespace != opts.Namespace {
+			continue
+		}
+		matches := true
+		for key, value := range opts.LabelSelector {
+			matches = matches && row.Metadata.Labels[key] == value
+		}
+		if matches {
+			out = append(out, row)
+		}
+	}
+	return out, "", nil
+}
+
+func row(t *testing.T, kind string, meta v1alpha1.ObjectMeta, spec any) *v1alpha1.RawObject {
+	t.Helper()
+	raw, err := json.Marshal(spec)
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: kind},
+		Metadata: meta,
+		Spec:     raw,
+	}
+}
+
+func runtimeRow(t *testing.T, name string, labels map[string]string, maxDeployments int) *v1alpha1.RawObject {
+	spec := v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes}
+	if maxDeployments > 0 {
+		spec.Capacity = &v1alpha1.RuntimeCapacity{MaxDeployments: maxDeployments}
+	}
+	return row(t, v1alpha1.KindRuntime, v1alpha1.ObjectMeta{Namespace: "default", Name: name, Labels: labels}, spec)
+}
+
+func deploymentRow(t *testing.T, name, runtime, desiredState string) *v1alpha1.RawObject {
+	return row(t, v1alpha1.KindDeployment, v1alpha1.ObjectMeta{Namespace: "default", Name: name}, v1alpha1.DeploymentSpec{
+		TargetRef:    v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "triage"},
+		RuntimeRef:   v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: runtime},
+		DesiredState: desiredState,
+	})
+}
+
+func selecting(name string, selector *v1alpha1.RuntimeSelector) *v1alpha1.Deployment {
+	return &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:       v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "triage"},
+			RuntimeSelector: selector,
+		},
+	}
+}
+
+func TestPlace(t *testing.T) {
+	eu := map[string]string{"region": "eu"}
+	runtimes := fakeLister{rows: []*v1alpha1.RawObject{
+		runtimeRow(t, "eu-1", eu, 2),
+		runtimeRow(t, "eu-2", eu, 0),
+		runtimeRow(t, "eu-3", map[string]string{"region": "eu", "gpu": "true"}, 1),
+		runtimeRow(t, "us-1", map[string]string{"region": "us"}, 0),
+	}}
+	deployments := fakeLister{rows: []*v1alpha1.RawObject{
+		deploymentRow(t, "a", "eu-1", ""),
+		deploymentRow(t, "b", "eu-2", ""),
+		deploymentRow(t, "c", "eu-2", ""),
+		deploymentRow(t, "d", "eu-3", v1alpha1.DesiredStateUndeployed),
+		deploymentRow(t, "sticky", "eu-2", ""),
+	}}
+	s := New(runtimes, deployments)
+
+	// eu-3's only Deployment is undeployed, so it serves none against
+	// eu-1's one and eu-2's three (counting sticky).
+	d := selecting("new", &v1alpha1.RuntimeSelector{MatchLabels: eu})
+	require.NoError(t, s.Place(context.Background(), d))
+	require.Equal(t, v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "eu-3"}, d.Spec.RuntimeRef)
+	require.Equal(t, "placed on eu-3, the least loaded of 3 Runtimes matching {region=eu} with room (0 of 1 Deployments)",
+		d.Metadata.Annotations[v1alpha1.DeploymentPlacementAnnotation])
+
+	// Without eu-3, eu-1 is the least loaded Runtime with room.
+	d = selecting("new", &v1alpha1.RuntimeSelector{MatchLabels: eu})
+	withoutGPU := New(fakeLister{rows: runtimes.rows[:2]}, deployments)
+	require.NoError(t, withoutGPU.Place(context.Background(), d))
+	require.Equal(t, "eu-1", d.Spec.RuntimeRef.Name)
+
+	// A Deployment already on a matching Runtime stays there.
+	d = selecting("sticky", &v1alpha1.RuntimeSelector{MatchLabels: eu})
+	require.NoError(t, s.Place(context.Background(), d))
+	require.Equal(t, "eu-2", d.Spec.RuntimeRef.Name)
+
+	// A named Runtime is left alone.
+	d = selecting("pinned", &v1alpha1.RuntimeSelector{MatchLabels: eu})
+	d.Spec.RuntimeRef = v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "us-1"}
+	require.NoError(t, s.Place(context.Background(), d))
+	require.Equal(t, "us-1", d.Spec.RuntimeRef.Name)
+	require.Empty(t, d.Metadata.Annotations)
+}
+
+func TestPlace_NoRuntime(t *testing.T) {
+	runtimes := fakeLister{rows: []*v1alpha1.RawObject{
+		runtimeRow(t, "eu-1", map[string]string{"region": "eu"}, 1),
+	}}
+	deployments := fakeLister{rows: []*v1alpha1.RawObject{deploymentRow(t, "a", "eu-1", "")}}
+	s := New(runtimes, deployments)
+
+	err := s.Place(context.Background(), selecting("new", &v1alpha1.RuntimeSelector{MatchLabels: map[string]string{"region": "eu"}}))
+	require.ErrorIs(t, err, pkgdb.ErrConflict)
+	require.EqualError(t, err, "all 1 Runtimes in namespace default matching runtimeSelector {region=eu} are at capacity")
+
+	err = s.Place(context.Background(), selecting("new", &v1alpha1.RuntimeSelector{Type: v1alpha1.TypeLocal}))
+	require.ErrorIs(t, err, pkgdb.ErrConflict)
+	require.EqualError(t, err, "no Runtime in namespace default matches runtimeSelector {type=Local}")
+}
@@ -0,0 +1,83 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// MasterKeySize is the length of an AES-256 master key in bytes.
+const MasterKeySize = 32
+
+// AESGCM is a types.SecretsCipher using AES-256-GCM with one master key.
+type AESGCM struct {
+	aead  cipher.AEAD
+	keyID string
+}
+
+var _ types.SecretsCipher = (*AESGCM)(nil)
+
+// ParseMasterKey decodes a base64-encoded 32-byte master key, as
+// `openssl rand -base64 32` prints one.
+func ParseMasterKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("secrets master key is not base64: %w", err)
+	}
+	if len(key) != MasterKeySize {
+		return nil, fmt.Errorf("secrets master key must be %d bytes, got %d", MasterKeySize, len(key))
+	}
+	return key, nil
+}
+
+// NewAESGCM returns a cipher encrypting with key. Its KeyID is derived
+// from the key, so values sealed under another key fail to open with a
+// clear error instead of an authentication failure.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(key)
+	return &AESGCM{aead: aead, keyID: "aes-" + hex.EncodeToString(sum[:4])}, nil
+}
+
+// KeyID implements types.SecretsCipher.
+func (c *AESGCM) KeyID() string {
+	return c.keyID
+}
+
+// Encrypt implements types.SecretsCipher. The random nonce is prepended
+// to the ciphertext.
+func (c *AESGCM) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt implements types.SecretsCipher.
+func (c *AESGCM) Decrypt(_ context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	if keyID != c.keyID {
+		return nil, fmt.Errorf("unknown key, the master key is %q", c.keyID)
+	}
+	n := c.aead.NonceSize()
+	if len(ciphertext) < n {
+		return nil, errors.New("ciphertext too short")
+	}
+	return c.aead.Open(nil, ciphertext[:n], ciphertext[n:], nil)
+}
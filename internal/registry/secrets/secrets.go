@@ -0,0 +1,260 @@
+// Package secrets keeps Runtime credentials out of plaintext storage. The
+// spec.config keys v1alpha1.RuntimeSecretConfigKeys marks, and any key set
+// through the Runtime secrets endpoint, are stored sealed: encrypted with
+// a types.SecretsCipher and written as
+//
+//	sealed:v1:<key id>:<base64 ciphertext>
+//
+// in place of the value. API responses show Redacted instead, applying
+// Redacted back keeps the stored value, and runtime adapters only ever
+// see opened copies.
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// Redacted replaces secret values in API responses, the same placeholder
+// rendered manifests use.
+const Redacted = "REDACTED"
+
+const sealedPrefix = "sealed:v1:"
+
+// ErrNotConfigured reports that a value must be sealed or opened but the
+// registry has no cipher.
+var ErrNotConfigured = errors.New("secrets encryption is not configured: the registry has no secrets master key")
+
+// Sealer seals and opens Runtime config values. A Sealer without a cipher
+// refuses to store secret values, unless it was built by
+// NewInsecureSealer.
+type Sealer struct {
+	cipher types.SecretsCipher
+	// plaintext stores secret values as given when there is no cipher.
+	plaintext bool
+}
+
+// NewSealer returns a Sealer encrypting with cipher. A nil cipher makes
+// every apply carrying a secret value fail with ErrNotConfigured.
+func NewSealer(cipher types.SecretsCipher) *Sealer {
+	return &Sealer{cipher: cipher}
+}
+
+// NewInsecureSealer returns a Sealer without a cipher that stores secret
+// values as given, still redacting them in API responses. It backs the
+// SECRETS_ALLOW_PLAINTEXT opt-in.
+func NewInsecureSealer() *Sealer {
+	return &Sealer{plaintext: true}
+}
+
+// Enabled reports whether the Sealer can encrypt.
+func (s *Sealer) Enabled() bool {
+	return s != nil && s.cipher != nil
+}
+
+// SealValue encrypts value into its stored form.
+func (s *Sealer) SealValue(ctx context.Context, value string) (string, error) {
+	if !s.Enabled() {
+		return "", ErrNotConfigured
+	}
+	ciphertext, err := s.cipher.Encrypt(ctx, []byte(value))
+	if err != nil {
+		return "", fmt.Errorf("encrypt secret: %w", err)
+	}
+	return sealedPrefix + s.cipher.KeyID() + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// OpenValue decrypts a value SealValue produced.
+func (s *Sealer) OpenValue(ctx context.Context, sealed string) (string, error) {
+	keyID, ciphertext, ok := parseSealed(sealed)
+	if !ok {
+		return "", errors.New("malformed sealed value")
+	}
+	if !s.Enabled() {
+		return "", ErrNotConfigured
+	}
+	plaintext, err := s.cipher.Decrypt(ctx, keyID, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("decrypt secret sealed with key %q: %w", keyID, err)
+	}
+	return string(plaintext), nil
+}
+
+// IsSealed reports whether v is a sealed config value.
+func IsSealed(v any) bool {
+	s, ok := v.(string)
+	return ok && strings.HasPrefix(s, sealedPrefix)
+}
+
+func parseSealed(v string) (keyID string, ciphertext []byte, ok bool) {
+	rest, ok := strings.CutPrefix(v, sealedPrefix)
+	if !ok {
+		return "", nil, false
+	}
+	// Key IDs may contain colons (KMS ARNs); base64 never does.
+	i := strings.LastIndex(rest, ":")
+	if i <= 0 {
+		return "", nil, false
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(rest[i+1:])
+	if err != nil {
+		return "", nil, false
+	}
+	return rest[:i], ciphertext, true
+}
+
+// Seal prepares runtime's spec.config for storage. previous is the stored
+// Runtime, nil on create. A Redacted value keeps the previous value of
+// its key, so a Runtime read from the API can be applied back unchanged.
+// Plaintext values of secret keys are encrypted, and sealed values the
+// caller supplies must open. Errors are huma 400s naming the key, or 501
+// when a secret value arrives and the Sealer can neither encrypt it nor
+// store it as given.
+func (s *Sealer) Seal(ctx context.Context, runtime, previous *v1alpha1.Runtime) error {
+	for _, key := range slices.Sorted(maps.Keys(runtime.Spec.Config)) {
+		value := runtime.Spec.Config[key]
+		field := "spec.config." + key
+		if value == Redacted {
+			prev, ok := previousValue(previous, key)
+			if !ok {
+				return huma.Error400BadRequest(fmt.Sprintf("%s is %s but no value is stored for it", field, Redacted))
+			}
+			value = prev
+		} else if IsSealed(value) {
+			if _, err := s.OpenValue(ctx, value.(string)); err != nil {
+				return huma.Error400BadRequest(fmt.Sprintf("%s: %v", field, err))
+			}
+		}
+		if runtime.IsSecretConfigKey(key) && !IsSealed(value) {
+			plaintext, ok := value.(string)
+			if !ok {
+				return huma.Error400BadRequest(fmt.Sprintf("%s holds a credential and must be a string", field))
+			}
+			switch {
+			case s.Enabled():
+				sealed, err := s.SealValue(ctx, plaintext)
+				if err != nil {
+					return err
+				}
+				value = sealed
+			case s == nil || !s.plaintext:
+				return huma.Error501NotImplemented(fmt.Sprintf("%s holds a credential: %v", field, ErrNotConfigured))
+			}
+		}
+		runtime.Spec.Config[key] = value
+	}
+	return nil
+}
+
+func previousValue(previous *v1alpha1.Runtime, key string) (any, bool) {
+	if previous == nil {
+		return nil, false
+	}
+	v, ok := previous.Spec.Config[key]
+	return v, ok && v != Redacted
+}
+
+// Open returns a copy of runtime with every sealed config value
+// decrypted, for handing to runtime adapters. runtime is not modified.
+func (s *Sealer) Open(ctx context.Context, runtime *v1alpha1.Runtime) (*v1alpha1.Runtime, error) {
+	if runtime == nil || !hasSealed(runtime.Spec.Config) {
+		return runtime, nil
+	}
+	out := *runtime
+	out.Spec.Config = maps.Clone(runtime.Spec.Config)
+	for key, value := range out.Spec.Config {
+		if !IsSealed(value) {
+			continue
+		}
+		plaintext, err := s.OpenValue(ctx, value.(string))
+		if err != nil {
+			return nil, fmt.Errorf("runtime %s/%s spec.config.%s: %w",
+				runtime.Metadata.NamespaceOrDefault(), runtime.Metadata.Name, key, err)
+		}
+		out.Spec.Config[key] = plaintext
+	}
+	return &out, nil
+}
+
+func hasSealed(config map[string]any) bool {
+	for _, value := range config {
+		if IsSealed(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact replaces the secret and sealed config values of a Runtime with
+// Redacted, in place. Other objects are left alone.
+func Redact(obj v1alpha1.Object) {
+	runtime, ok := obj.(*v1alpha1.Runtime)
+	if !ok || runtime == nil {
+		return
+	}
+	for key, value := range runtime.Spec.Config {
+		if IsSealed(value) || runtime.IsSecretConfigKey(key) {
+			runtime.Spec.Config[key] = Redacted
+		}
+	}
+}
+
+// Getter reads the stored Runtime an apply replaces. *v1alpha1store.Store
+// satisfies it.
+type Getter interface {
+	GetLatest(ctx context.Context, namespace, name string) (*v1alpha1.RawObject, error)
+}
+
+// Prepare returns a Runtime Prepare hook that runs next, then seals the
+// Runtime's config against the stored one in runtimes.
+func (s *Sealer) Prepare(runtimes Getter, next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		runtime, ok := obj.(*v1alpha1.Runtime)
+		if !ok || len(runtime.Spec.Config) == 0 {
+			return nil
+		}
+		var previous *v1alpha1.Runtime
+		raw, err := runtimes.GetLatest(ctx, runtime.Metadata.NamespaceOrDefault(), runtime.Metadata.Name)
+		switch {
+		case err == nil:
+			previous, err = v1alpha1.EnvelopeFromRaw(func() *v1alpha1.Runtime { return &v1alpha1.Runtime{} }, raw, v1alpha1.KindRuntime)
+			if err != nil {
+				return fmt.Errorf("decode stored Runtime: %w", err)
+			}
+		case !errors.Is(err, pkgdb.ErrNotFound):
+			return fmt.Errorf("read stored Runtime: %w", err)
+		}
+		return s.Seal(ctx, runtime, previous)
+	}
+}
+
+// Getter returns next with every Runtime it resolves opened, so
+// controllers and adapters reading Runtimes by reference see plaintext.
+func (s *Sealer) Getter(next v1alpha1.GetterFunc) v1alpha1.GetterFunc {
+	return func(ctx context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		obj, err := next(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		if runtime, ok := obj.(*v1alpha1.Runtime); ok {
+			return s.Open(ctx, runtime)
+		}
+		return obj, nil
+	}
+}
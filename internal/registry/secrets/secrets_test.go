@@ -0,0 +1,155 @@
+package secrets_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/secrets"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+func newSealer(t *testing.T, fill string) *secrets.Sealer {
+	t.Helper()
+	cipher, err := secrets.NewAESGCM([]byte(strings.Repeat(fill, secrets.MasterKeySize)))
+	require.NoError(t, err)
+	return secrets.NewSealer(cipher)
+}
+
+func kubernetesRuntime(config map[string]any) *v1alpha1.Runtime {
+	return &v1alpha1.Runtime{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "prod"},
+		Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes, Config: config},
+	}
+}
+
+func requireStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var se huma.StatusError
+	require.ErrorAs(t, err, &se)
+	require.Equal(t, status, se.GetStatus(), err.Error())
+}
+
+func TestParseMasterKey(t *testing.T) {
+	_, err := secrets.ParseMasterKey("not base64!")
+	require.Error(t, err)
+	_, err = secrets.ParseMasterKey("c2hvcnQ=")
+	require.ErrorContains(t, err, "must be 32 bytes")
+	key, err := secrets.ParseMasterKey("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=\n")
+	require.NoError(t, err)
+	require.Len(t, key, secrets.MasterKeySize)
+}
+
+func TestSealAndOpen(t *testing.T) {
+	ctx := context.Background()
+	sealer := newSealer(t, "a")
+	rt := kubernetesRuntime(map[string]any{"kubeconfig": "apiVersion: v1", "namespace": "agents"})
+
+	require.NoError(t, sealer.Seal(ctx, rt, nil))
+	require.True(t, secrets.IsSealed(rt.Spec.Config["kubeconfig"]))
+	require.Equal(t, "agents", rt.Spec.Config["namespace"])
+
+	opened, err := sealer.Open(ctx, rt)
+	require.NoError(t, err)
+	require.Equal(t, "apiVersion: v1", opened.Spec.Config["kubeconfig"])
+	require.True(t, secrets.IsSealed(rt.Spec.Config["kubeconfig"]), "Open must not modify its input")
+
+	_, err = newSealer(t, "b").Open(ctx, rt)
+	require.ErrorContains(t, err, "spec.config.kubeconfig")
+	_, err = secrets.NewSealer(nil).Open(ctx, rt)
+	require.ErrorIs(t, err, secrets.ErrNotConfigured)
+}
+
+func TestSeal_RedactedKeepsStoredValue(t *testing.T) {
+	ctx := context.Background()
+	sealer := newSealer(t, "a")
+	stored := kubernetesRuntime(map[string]any{"kubeconfig": "apiVersion: v1"})
+	require.NoError(t, sealer.Seal(ctx, stored, nil))
+
+	// A Runtime read back from the API and applied unchanged.
+	read := kubernetesRuntime(map[string]any{"kubeconfig": "apiVersion: v1", "namespace": "agents"})
+	require.NoError(t, sealer.Seal(ctx, read, nil))
+	secrets.Redact(read)
+	require.Equal(t, secrets.Redacted, read.Spec.Config["kubeconfig"])
+
+	require.NoError(t, sealer.Seal(ctx, read, stored))
+	require.Equal(t, stored.Spec.Config["kubeconfig"], read.Spec.Config["kubeconfig"])
+
+	requireStatus(t, sealer.Seal(ctx, kubernetesRuntime(map[string]any{"kubeconfig": secrets.Redacted}), nil), http.StatusBadRequest)
+}
+
+func TestSeal_RejectsForeignAndNonStringValues(t *testing.T) {
+	ctx := context.Background()
+	foreign := kubernetesRuntime(map[string]any{"kubeconfig": "apiVersion: v1"})
+	require.NoError(t, newSealer(t, "b").Seal(ctx, foreign, nil))
+
+	requireStatus(t, newSealer(t, "a").Seal(ctx, foreign, nil), http.StatusBadRequest)
+	requireStatus(t, newSealer(t, "a").Seal(ctx, kubernetesRuntime(map[string]any{"kubeconfig": map[string]any{"a": 1}}), nil), http.StatusBadRequest)
+}
+
+func TestSeal_WithoutCipherRefusesSecrets(t *testing.T) {
+	ctx := context.Background()
+	rt := kubernetesRuntime(map[string]any{"kubeconfig": "apiVersion: v1"})
+	err := secrets.NewSealer(nil).Seal(ctx, rt, nil)
+	requireStatus(t, err, http.StatusNotImplemented)
+	require.ErrorContains(t, err, "spec.config.kubeconfig")
+
+	// Carrying a stored plaintext value over is refused too.
+	stored := kubernetesRuntime(map[string]any{"kubeconfig": "apiVersion: v1"})
+	requireStatus(t, secrets.NewSealer(nil).Seal(ctx, kubernetesRuntime(map[string]any{"kubeconfig": secrets.Redacted}), stored), http.StatusNotImplemented)
+
+	// Config without credentials is unaffected.
+	require.NoError(t, secrets.NewSealer(nil).Seal(ctx, kubernetesRuntime(map[string]any{"context": "prod"}), nil))
+}
+
+func TestSeal_InsecureSealerStoresPlaintextButRedacts(t *testing.T) {
+	rt := kubernetesRuntime(map[string]any{"kubeconfig": "apiVersion: v1"})
+	require.NoError(t, secrets.NewInsecureSealer().Seal(context.Background(), rt, nil))
+	require.Equal(t, "apiVersion: v1", rt.Spec.Config["kubeconfig"])
+
+	secrets.Redact(rt)
+	require.Equal(t, secrets.Redacted, rt.Spec.Config["kubeconfig"])
+}
+
+type fakeRuntimes map[string]*v1alpha1.Runtime
+
+func (f fakeRuntimes) GetLatest(_ context.Context, namespace, name string) (*v1alpha1.RawObject, error) {
+	rt, ok := f[namespace+"/"+name]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	spec, err := json.Marshal(rt.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.RawObject{Metadata: rt.Metadata, Spec: spec}, nil
+}
+
+func TestPrepare(t *testing.T) {
+	ctx := context.Background()
+	sealer := newSealer(t, "a")
+	stored := kubernetesRuntime(map[string]any{"kubeconfig": "apiVersion: v1"})
+	require.NoError(t, sealer.Seal(ctx, stored, nil))
+
+	var nextRan bool
+	prepare := sealer.Prepare(fakeRuntimes{"default/prod": stored}, func(context.Context, v1alpha1.Object) error {
+		nextRan = true
+		return nil
+	})
+
+	applied := kubernetesRuntime(map[string]any{"kubeconfig": secrets.Redacted})
+	require.NoError(t, prepare(ctx, applied))
+	require.True(t, nextRan)
+	require.Equal(t, stored.Spec.Config["kubeconfig"], applied.Spec.Config["kubeconfig"])
+
+	created := kubernetesRuntime(map[string]any{"kubeconfig": "apiVersion: v1"})
+	created.Metadata.Name = "staging"
+	require.NoError(t, prepare(ctx, created))
+	require.True(t, secrets.IsSealed(created.Spec.Config["kubeconfig"]))
+}
@@ -57,9 +57,33 @@ func (r *AdapterResolver) Logs(ctx context.Context, deployment *v1alpha1.Deploym
 		return nil, err
 	}
 	in.Deployment = deployment
+	in.Runtime = runtime
 	return adapter.Logs(ctx, in)
 }
 
+// MCPEndpoint returns the URL the runtime's gateway serves the
+// deployment's MCP servers on. Returns an UnsupportedDeploymentRuntimeError
+// if no adapter matches the runtime or the adapter does not implement
+// types.MCPEndpointSource.
+func (r *AdapterResolver) MCPEndpoint(ctx context.Context, deployment *v1alpha1.Deployment) (string, error) {
+	if deployment == nil {
+		return "", fmt.Errorf("%w: deployment is required", pkgdb.ErrInvalidInput)
+	}
+	runtime, err := r.resolveRuntime(ctx, deployment)
+	if err != nil {
+		return "", err
+	}
+	adapter, err := r.resolveAdapter(runtime.Spec.Type)
+	if err != nil {
+		return "", err
+	}
+	source, ok := adapter.(types.MCPEndpointSource)
+	if !ok {
+		return "", &UnsupportedDeploymentRuntimeError{Type: runtime.Spec.Type}
+	}
+	return source.MCPEndpoint(ctx, types.MCPEndpointInput{Deployment: deployment, Runtime: runtime})
+}
+
 func (r *AdapterResolver) resolveRuntime(ctx context.Context, deployment *v1alpha1.Deployment) (*v1alpha1.Runtime, error) {
 	if r == nil || r.getter == nil {
 		return nil, fmt.Errorf("%w: deployment adapter resolver getter is nil", pkgdb.ErrInvalidInput)
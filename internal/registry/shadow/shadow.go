@@ -0,0 +1,221 @@
+// Package shadow mirrors a sample of the registry's read traffic to a
+// candidate registry instance and compares the answers, so an upgrade can be
+// exercised with production requests before it takes any traffic.
+//
+// The primary response is always served first and never waits on the
+// candidate: the mirrored request runs in the background with the caller's
+// headers (including Authorization, so the candidate must trust the same
+// identity provider) and its status code and SHA-256 body digest are compared
+// with the primary's. Outcomes are counted on the
+// agent_registry.shadow.requests metric; divergences are also logged.
+package shadow
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/telemetry"
+)
+
+// Outcomes recorded on the shadow request counter.
+const (
+	OutcomeMatch          = "match"
+	OutcomeStatusMismatch = "status_mismatch"
+	OutcomeBodyMismatch   = "body_mismatch"
+	OutcomeError          = "error"
+	// OutcomeDropped counts sampled requests skipped because maxInFlight
+	// mirrored requests were already outstanding.
+	OutcomeDropped = "dropped"
+)
+
+// Header marks mirrored requests. A candidate that itself runs in shadow
+// mode never mirrors a request carrying it.
+const Header = "X-Agentregistry-Shadow"
+
+// maxInFlight bounds concurrent mirrored requests so a slow candidate can't
+// pile up goroutines on the primary.
+const maxInFlight = 64
+
+// hopHeaders are connection-scoped and not forwarded to the candidate.
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// Mirror is the shadowing middleware. Build it with New.
+type Mirror struct {
+	target *url.URL
+	client *http.Client
+
+	requests metric.Int64Counter
+	// sample reports whether a request is mirrored; swapped in tests.
+	sample func() bool
+
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+// New returns a Mirror sending percent (0-100) of GET and HEAD requests
+// under /v0 to the registry at target.
+func New(target string, percent float64, client *http.Client, meter metric.Meter) (*Mirror, error) {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("shadow target must be an absolute http(s) URL, got %q", target)
+	}
+	if percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("shadow percent must be between 0 and 100, got %v", percent)
+	}
+	requests, err := meter.Int64Counter(
+		telemetry.Namespace+".shadow.requests",
+		metric.WithDescription("Mirrored read requests by comparison outcome"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shadow request counter: %w", err)
+	}
+	return &Mirror{
+		target:   u,
+		client:   client,
+		requests: requests,
+		sample:   func() bool { return rand.Float64()*100 < percent },
+		slots:    make(chan struct{}, maxInFlight),
+	}, nil
+}
+
+// Middleware serves every request from next and mirrors the sampled ones.
+func (m *Mirror) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.eligible(r) || !m.sample() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK, digest: sha256.New()}
+		next.ServeHTTP(rec, r)
+
+		select {
+		case m.slots <- struct{}{}:
+		default:
+			m.record(r, OutcomeDropped)
+			return
+		}
+		req, err := m.mirrorRequest(r)
+		if err != nil {
+			<-m.slots
+			slog.Warn("shadow: building mirrored request", "path", r.URL.Path, "error", err)
+			m.record(r, OutcomeError)
+			return
+		}
+		primaryStatus, primaryDigest := rec.status, rec.digest.Sum(nil)
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			defer func() { <-m.slots }()
+			m.record(r, m.compare(req, primaryStatus, primaryDigest))
+		}()
+	})
+}
+
+// eligible limits mirroring to API reads that didn't come from another
+// shadowing registry.
+func (m *Mirror) eligible(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if r.Header.Get(Header) != "" {
+		return false
+	}
+	return strings.HasPrefix(r.URL.Path, "/v0/") || strings.HasPrefix(r.URL.Path, "/v0.")
+}
+
+// mirrorRequest copies r onto the candidate. It is detached from r's
+// context, which is cancelled once the primary response is written.
+func (m *Mirror) mirrorRequest(r *http.Request) (*http.Request, error) {
+	u := *m.target
+	u.Path = strings.TrimSuffix(u.Path, "/") + r.URL.Path
+	u.RawQuery = r.URL.RawQuery
+	req, err := http.NewRequestWithContext(context.WithoutCancel(r.Context()), r.Method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = r.Header.Clone()
+	for _, h := range hopHeaders {
+		req.Header.Del(h)
+	}
+	req.Header.Set(Header, "1")
+	return req, nil
+}
+
+func (m *Mirror) compare(req *http.Request, primaryStatus int, primaryDigest []byte) string {
+	resp, err := m.client.Do(req)
+	if err != nil {
+		slog.Warn("shadow: candidate request failed", "path", req.URL.Path, "error", err)
+		return OutcomeError
+	}
+	defer resp.Body.Close()
+	digest := sha256.New()
+	if _, err := io.Copy(digest, resp.Body); err != nil {
+		slog.Warn("shadow: reading candidate response", "path", req.URL.Path, "error", err)
+		return OutcomeError
+	}
+	if resp.StatusCode != primaryStatus {
+		slog.Info("shadow: status divergence", "method", req.Method, "path", req.URL.Path,
+			"primary", primaryStatus, "candidate", resp.StatusCode)
+		return OutcomeStatusMismatch
+	}
+	if !bytes.Equal(digest.Sum(nil), primaryDigest) {
+		slog.Info("shadow: body divergence", "method", req.Method, "path", req.URL.Path, "status", primaryStatus)
+		return OutcomeBodyMismatch
+	}
+	return OutcomeMatch
+}
+
+func (m *Mirror) record(r *http.Request, outcome string) {
+	m.requests.Add(context.WithoutCancel(r.Context()), 1, metric.WithAttributes(
+		attribute.String("method", r.Method),
+		attribute.String("outcome", outcome),
+	))
+}
+
+// Wait blocks until every mirrored request in flight has been compared.
+func (m *Mirror) Wait() {
+	m.wg.Wait()
+}
+
+// recorder passes the primary response through while hashing the body.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	digest      hash.Hash
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.digest.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
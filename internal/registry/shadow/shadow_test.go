@@ -0,0 +1,127 @@
+package shadow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMirror(t *testing.T) {
+	primary := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/missing":
+			http.NotFound(w, r)
+		default:
+			_, _ = w.Write([]byte(`{"name":"acme"}`))
+		}
+	})
+
+	var mu sync.Mutex
+	seen := map[string]*http.Request{}
+	candidate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path] = r
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/base/v0/agents/acme":
+			_, _ = w.Write([]byte(`{"name":"acme"}`))
+		case "/base/v0/skills/acme":
+			_, _ = w.Write([]byte(`{"name":"acme","tag":"v2"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(candidate.Close)
+
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+	m, err := New(candidate.URL+"/base/", 100, candidate.Client(), meter)
+	require.NoError(t, err)
+	handler := m.Middleware(primary)
+
+	serve := func(method, target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "/v0/agents/acme?namespace=team-a", http.Header{"Authorization": {"Bearer t"}})
+	require.Equal(t, `{"name":"acme"}`, rec.Body.String())
+	serve(http.MethodGet, "/v0/skills/acme", nil)
+	serve(http.MethodGet, "/v0/missing", nil)
+	serve(http.MethodPost, "/v0/agents/acme", nil)
+	serve(http.MethodGet, "/health", nil)
+	serve(http.MethodGet, "/v0/agents/acme", http.Header{Header: {"1"}})
+	m.Wait()
+
+	require.Len(t, seen, 3, "only eligible reads are mirrored")
+	agent := seen["/base/v0/agents/acme"]
+	require.NotNil(t, agent)
+	require.Equal(t, "namespace=team-a", agent.URL.RawQuery)
+	require.Equal(t, "Bearer t", agent.Header.Get("Authorization"))
+	require.Equal(t, "1", agent.Header.Get(Header))
+
+	require.Equal(t, map[string]int64{
+		OutcomeMatch:          1,
+		OutcomeBodyMismatch:   1,
+		OutcomeStatusMismatch: 1,
+	}, outcomeCounts(t, reader))
+}
+
+func TestMirrorSampling(t *testing.T) {
+	candidate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unsampled request mirrored: %s", r.URL.Path)
+	}))
+	t.Cleanup(candidate.Close)
+
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+	m, err := New(candidate.URL, 0, candidate.Client(), meter)
+	require.NoError(t, err)
+
+	handler := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for range 20 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v0/agents", nil))
+	}
+	m.Wait()
+	require.Empty(t, outcomeCounts(t, reader))
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	meter := sdkmetric.NewMeterProvider().Meter("test")
+	_, err := New("registry.internal:8080", 10, http.DefaultClient, meter)
+	require.ErrorContains(t, err, "absolute http(s) URL")
+	_, err = New("http://registry.internal:8080", 101, http.DefaultClient, meter)
+	require.ErrorContains(t, err, "between 0 and 100")
+}
+
+func outcomeCounts(t *testing.T, reader sdkmetric.Reader) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, md := range sm.Metrics {
+			sum, ok := md.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				outcome, _ := dp.Attributes.Value(attribute.Key("outcome"))
+				counts[outcome.AsString()] += dp.Value
+			}
+		}
+	}
+	return counts
+}
@@ -0,0 +1,37 @@
+// Package skilldeps refuses Skill publishes whose dependency graph an Agent
+// could not deploy. A Skill's spec.skills and spec.mcpServers are pulled
+// into every Agent using it, transitively; a publish that closes a cycle,
+// or that reaches one skill, MCP server or server package at two versions,
+// fails with 409 instead of failing every later deploy.
+//
+// That the dependencies exist is checked earlier, with the other refs.
+package skilldeps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// Prepare returns a Skill Prepare hook that runs next, then walks the
+// Skill's dependency graph through getter with
+// v1alpha1.CheckSkillDependencies. Failures match pkgdb.ErrConflict.
+func Prepare(getter v1alpha1.GetterFunc, next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		skill, ok := obj.(*v1alpha1.Skill)
+		if !ok {
+			return nil
+		}
+		if err := v1alpha1.CheckSkillDependencies(ctx, getter, skill); err != nil {
+			return fmt.Errorf("%w: skill dependencies: %w", pkgdb.ErrConflict, err)
+		}
+		return nil
+	}
+}
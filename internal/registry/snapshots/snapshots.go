@@ -0,0 +1,172 @@
+// Package snapshots takes catalog snapshots and resolves refs from them.
+// Every tag of an artifact can be republished in place, "latest" included,
+// so a ref alone does not name fixed content. A snapshot copies every live
+// tag of every tagged artifact at one point in time; a Deployment with
+// spec.snapshot resolves its target, and every tagged artifact the target
+// refers to, from that copy:
+//
+//   - a tag resolves to the content it had when the snapshot was taken,
+//   - an untagged ref resolves to the snapshot's "latest", and
+//   - a version constraint picks the best of the snapshot's tags,
+//
+// so environments deployed from the same snapshot run identical versions
+// whatever is published later. Refs the snapshot doesn't hold are
+// dangling. Runtimes, Deployments and other mutable kinds, and refs to
+// peer registries, resolve as usual.
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	internaldb "github.com/agentregistry-dev/agentregistry/internal/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// listPageSize is the store page size used while capturing each kind.
+const listPageSize = 200
+
+// Store persists snapshots. *v1alpha1store.CatalogSnapshotStore satisfies
+// it.
+type Store interface {
+	Create(ctx context.Context, snapshot *v1alpha1store.CatalogSnapshot, objects []*v1alpha1.RawObject) (*v1alpha1store.CatalogSnapshot, error)
+	Get(ctx context.Context, name string) (*v1alpha1store.CatalogSnapshot, error)
+	Entry(ctx context.Context, snapshot, kind, namespace, name, tag string) (*v1alpha1.RawObject, error)
+	Tags(ctx context.Context, snapshot, kind, namespace, name string) ([]string, error)
+}
+
+var _ Store = (*v1alpha1store.CatalogSnapshotStore)(nil)
+
+// Lister lists the rows of one kind. *v1alpha1store.Store satisfies it.
+type Lister interface {
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+}
+
+// Catalog takes snapshots of Stores and resolves refs from them.
+type Catalog struct {
+	Store Store
+	// Stores are the tagged-artifact stores a snapshot captures, keyed by
+	// kind.
+	Stores map[string]Lister
+}
+
+// Take captures every live tag of c.Stores into a new snapshot described
+// by snapshot: those in snapshot.Namespace, or in every namespace when it
+// is empty. A snapshot of the same name matches pkgdb.ErrAlreadyExists.
+func (c *Catalog) Take(ctx context.Context, snapshot v1alpha1store.CatalogSnapshot) (*v1alpha1store.CatalogSnapshot, error) {
+	var objects []*v1alpha1.RawObject
+	for _, kind := range slices.Sorted(maps.Keys(c.Stores)) {
+		opts := v1alpha1store.ListOpts{Namespace: snapshot.Namespace, Limit: listPageSize}
+		for {
+			rows, next, err := c.Stores[kind].List(ctx, opts)
+			if err != nil {
+				return nil, fmt.Errorf("list %s: %w", kind, err)
+			}
+			for _, row := range rows {
+				row.TypeMeta = v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: kind}
+				objects = append(objects, row)
+			}
+			if next == "" {
+				break
+			}
+			opts.Cursor = next
+		}
+	}
+	return c.Store.Create(ctx, &snapshot, objects)
+}
+
+// Getter returns a getter that resolves the tagged-artifact refs of this
+// registry from snapshot, and passes every other ref to next. A ref the
+// snapshot doesn't hold, or a snapshot that doesn't exist, returns
+// v1alpha1.ErrDanglingRef.
+func (c *Catalog) Getter(snapshot string, next v1alpha1.GetterFunc) v1alpha1.GetterFunc {
+	return func(ctx context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		if ref.Registry != "" || !v1alpha1.IsTaggedArtifactKind(ref.Kind) {
+			if next == nil {
+				return nil, fmt.Errorf("%w: %s refs are not resolvable here", v1alpha1.ErrInvalidRef, ref.Kind)
+			}
+			return next(ctx, ref)
+		}
+		raw, err := c.entry(ctx, snapshot, ref)
+		if errors.Is(err, pkgdb.ErrNotFound) {
+			return nil, fmt.Errorf("%w: not in catalog snapshot %q", v1alpha1.ErrDanglingRef, snapshot)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return internaldb.DecodeObject(ref.Kind, raw)
+	}
+}
+
+// Prepare returns a Deployment Prepare hook that runs next, then checks
+// that a Deployment's spec.snapshot exists and holds its target, so a
+// deploy pinned to a typo or to a snapshot taken before the target was
+// published answers 422 instead of stalling in the controller.
+func (c *Catalog) Prepare(next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		deployment, ok := obj.(*v1alpha1.Deployment)
+		if !ok || deployment.Spec.Snapshot == "" {
+			return nil
+		}
+		snapshot := deployment.Spec.Snapshot
+		if _, err := c.Store.Get(ctx, snapshot); err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return huma.Error422UnprocessableEntity(fmt.Sprintf("spec.snapshot: catalog snapshot %q does not exist", snapshot))
+			}
+			return err
+		}
+		ref := deployment.Spec.TargetRef
+		if ref.Namespace == "" {
+			ref.Namespace = deployment.Metadata.NamespaceOrDefault()
+		}
+		if _, err := c.entry(ctx, snapshot, ref); err != nil {
+			if errors.Is(err, pkgdb.ErrNotFound) {
+				return huma.Error422UnprocessableEntity(fmt.Sprintf("spec.targetRef: %s %s/%s@%s is not in catalog snapshot %q",
+					ref.Kind, ref.Namespace, ref.Name, ref.Tag, snapshot))
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// entry returns the snapshot's row for ref, choosing among its tags for a
+// version constraint.
+func (c *Catalog) entry(ctx context.Context, snapshot string, ref v1alpha1.ResourceRef) (*v1alpha1.RawObject, error) {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = v1alpha1.DefaultNamespace
+	}
+	tag := ref.Tag
+	switch {
+	case tag == "":
+		tag = v1alpha1store.DefaultTag()
+	case v1alpha1.IsTagConstraint(tag):
+		constraint, err := v1alpha1.ParseTagConstraint(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", v1alpha1.ErrInvalidRef, err)
+		}
+		tags, err := c.Store.Tags(ctx, snapshot, ref.Kind, namespace, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		best, ok := constraint.Best(tags)
+		if !ok {
+			return nil, pkgdb.ErrNotFound
+		}
+		tag = best
+	}
+	return c.Store.Entry(ctx, snapshot, ref.Kind, namespace, ref.Name, tag)
+}
@@ -0,0 +1,228 @@
+package snapshots_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/snapshots"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type entryKey struct {
+	snapshot, kind, namespace, name, tag string
+}
+
+type fakeStore struct {
+	snapshots map[string]*v1alpha1store.CatalogSnapshot
+	entries   map[entryKey]*v1alpha1.RawObject
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{snapshots: map[string]*v1alpha1store.CatalogSnapshot{}, entries: map[entryKey]*v1alpha1.RawObject{}}
+}
+
+func (f *fakeStore) Create(_ context.Context, snapshot *v1alpha1store.CatalogSnapshot, objects []*v1alpha1.RawObject) (*v1alpha1store.CatalogSnapshot, error) {
+	if _, ok := f.snapshots[snapshot.Name]; ok {
+		return nil, pkgdb.ErrAlreadyExists
+	}
+	out := *snapshot
+	out.Entries = len(objects)
+	f.snapshots[snapshot.Name] = &out
+	for _, obj := range objects {
+		// Copy like the database does, so later changes to the live row
+		// don't reach the snapshot.
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		var stored v1alpha1.RawObject
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return nil, err
+		}
+		f.entries[entryKey{snapshot.Name, obj.Kind, obj.Metadata.NamespaceOrDefault(), obj.Metadata.Name, obj.Metadata.Tag}] = &stored
+	}
+	return &out, nil
+}
+
+func (f *fakeStore) Get(_ context.Context, name string) (*v1alpha1store.CatalogSnapshot, error) {
+	snapshot, ok := f.snapshots[name]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	return snapshot, nil
+}
+
+func (f *fakeStore) Entry(_ context.Context, snapshot, kind, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	obj, ok := f.entries[entryKey{snapshot, kind, namespace, name, tag}]
+	if !ok {
+		return nil, pkgdb.ErrNotFound
+	}
+	return obj, nil
+}
+
+func (f *fakeStore) Tags(_ context.Context, snapshot, kind, namespace, name string) ([]string, error) {
+	var tags []string
+	for key := range f.entries {
+		if key.snapshot == snapshot && key.kind == kind && key.namespace == namespace && key.name == name {
+			tags = append(tags, key.tag)
+		}
+	}
+	return tags, nil
+}
+
+// fakeLister serves rows in pages of one.
+type fakeLister []*v1alpha1.RawObject
+
+func (f fakeLister) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	var rows []*v1alpha1.RawObject
+	for _, row := range f {
+		if opts.Namespace == "" || row.Metadata.Namespace == opts.Namespace {
+			rows = append(rows, row)
+		}
+	}
+	start, _ := strconv.Atoi(opts.Cursor)
+	if start >= len(rows) {
+		return nil, "", nil
+	}
+	next := ""
+	if start+1 < len(rows) {
+		next = strconv.Itoa(start + 1)
+	}
+	return rows[start : start+1], next, nil
+}
+
+func serverRow(t *testing.T, namespace, tag, description string) *v1alpha1.RawObject {
+	t.Helper()
+	spec, err := json.Marshal(v1alpha1.MCPServerSpec{Description: description})
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{
+		Metadata: v1alpha1.ObjectMeta{Namespace: namespace, Name: "weather", Tag: tag},
+		Spec:     spec,
+	}
+}
+
+func newCatalog(t *testing.T) (*snapshots.Catalog, fakeLister) {
+	t.Helper()
+	servers := fakeLister{
+		serverRow(t, "default", "latest", "v1.1"),
+		serverRow(t, "default", "1.0.0", "v1.0"),
+		serverRow(t, "default", "1.1.0", "v1.1"),
+		serverRow(t, "team-a", "latest", "team"),
+	}
+	catalog := &snapshots.Catalog{
+		Store:  newFakeStore(),
+		Stores: map[string]snapshots.Lister{v1alpha1.KindMCPServer: servers},
+	}
+	return catalog, servers
+}
+
+func TestTake(t *testing.T) {
+	catalog, _ := newCatalog(t)
+	ctx := context.Background()
+
+	taken, err := catalog.Take(ctx, v1alpha1store.CatalogSnapshot{Name: "all"})
+	require.NoError(t, err)
+	require.Equal(t, 4, taken.Entries)
+
+	taken, err = catalog.Take(ctx, v1alpha1store.CatalogSnapshot{Name: "team-a", Namespace: "team-a"})
+	require.NoError(t, err)
+	require.Equal(t, 1, taken.Entries)
+
+	_, err = catalog.Take(ctx, v1alpha1store.CatalogSnapshot{Name: "all"})
+	require.ErrorIs(t, err, pkgdb.ErrAlreadyExists)
+}
+
+func TestGetter(t *testing.T) {
+	catalog, servers := newCatalog(t)
+	ctx := context.Background()
+	_, err := catalog.Take(ctx, v1alpha1store.CatalogSnapshot{Name: "2024-06-release"})
+	require.NoError(t, err)
+
+	// Later publishes don't reach the snapshot.
+	spec, err := json.Marshal(v1alpha1.MCPServerSpec{Description: "v2.0"})
+	require.NoError(t, err)
+	servers[0].Spec = spec
+
+	var passed []v1alpha1.ResourceRef
+	next := func(_ context.Context, ref v1alpha1.ResourceRef) (v1alpha1.Object, error) {
+		passed = append(passed, ref)
+		return &v1alpha1.Runtime{}, nil
+	}
+	getter := catalog.Getter("2024-06-release", next)
+
+	description := func(ref v1alpha1.ResourceRef) string {
+		t.Helper()
+		obj, err := getter(ctx, ref)
+		require.NoError(t, err)
+		server, ok := obj.(*v1alpha1.MCPServer)
+		require.True(t, ok)
+		require.Equal(t, v1alpha1.KindMCPServer, server.Kind)
+		return server.Spec.Description
+	}
+	require.Equal(t, "v1.1", description(v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather"}))
+	require.Equal(t, "v1.0", description(v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "1.0.0"}))
+	require.Equal(t, "v1.1", description(v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "^1.0.0"}))
+	require.Equal(t, "team", description(v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Namespace: "team-a", Name: "weather"}))
+
+	_, err = getter(ctx, v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "2.0.0"})
+	require.ErrorIs(t, err, v1alpha1.ErrDanglingRef)
+	_, err = getter(ctx, v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: "^2.0.0"})
+	require.ErrorIs(t, err, v1alpha1.ErrDanglingRef)
+	_, err = catalog.Getter("missing", next)(ctx, v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather"})
+	require.ErrorIs(t, err, v1alpha1.ErrDanglingRef)
+
+	runtime := v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "local"}
+	peer := v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Registry: "upstream"}
+	_, err = getter(ctx, runtime)
+	require.NoError(t, err)
+	_, err = getter(ctx, peer)
+	require.NoError(t, err)
+	require.Equal(t, []v1alpha1.ResourceRef{runtime, peer}, passed)
+}
+
+func TestPrepare(t *testing.T) {
+	catalog, _ := newCatalog(t)
+	ctx := context.Background()
+	_, err := catalog.Take(ctx, v1alpha1store.CatalogSnapshot{Name: "2024-06-release"})
+	require.NoError(t, err)
+
+	nextErr := errors.New("next failed")
+	prepare := catalog.Prepare(nil)
+	deployment := func(snapshot, tag string) *v1alpha1.Deployment {
+		return &v1alpha1.Deployment{
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather"},
+			Spec: v1alpha1.DeploymentSpec{
+				TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindMCPServer, Name: "weather", Tag: tag},
+				RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "local"},
+				Snapshot:   snapshot,
+			},
+		}
+	}
+
+	require.NoError(t, prepare(ctx, deployment("", "9.9.9")))
+	require.NoError(t, prepare(ctx, deployment("2024-06-release", "")))
+	require.NoError(t, prepare(ctx, deployment("2024-06-release", "~1.0.0")))
+
+	var statusErr huma.StatusError
+	err = prepare(ctx, deployment("2024-07-release", ""))
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusUnprocessableEntity, statusErr.GetStatus())
+	require.Contains(t, err.Error(), `catalog snapshot "2024-07-release" does not exist`)
+
+	err = prepare(ctx, deployment("2024-06-release", "2.0.0"))
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusUnprocessableEntity, statusErr.GetStatus())
+	require.Contains(t, err.Error(), "is not in catalog snapshot")
+
+	failing := catalog.Prepare(func(context.Context, v1alpha1.Object) error { return nextErr })
+	require.ErrorIs(t, failing(ctx, deployment("2024-06-release", "")), nextErr)
+}
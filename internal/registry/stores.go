@@ -0,0 +1,127 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/config"
+	internaldb "github.com/agentregistry-dev/agentregistry/internal/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// resolveExtraStoreSchema resolves an extra-store table value to its schema
+// and bare table name. A bare "table" stays in ossSchema; a qualified
+// "schema.table" resolves to that schema. Panics via MustNewSchema if the
+// schema segment is not a valid identifier (see
+// types.AppOptions.V1Alpha1StoreTables).
+func resolveExtraStoreSchema(table string, ossSchema pkgdb.Schema) (pkgdb.Schema, string) {
+	if s, t, ok := strings.Cut(table, "."); ok {
+		return pkgdb.MustNewSchema(s), t
+	}
+	return ossSchema, table
+}
+
+func buildStores(pool *pgxpool.Pool, extraStoreTables map[string]string, mutableExtraKinds map[string]bool, auditor types.Auditor, storeOpts ...v1alpha1store.StoreOption) map[string]*v1alpha1store.Store {
+	if auditor == nil {
+		auditor = types.NoopAuditor
+	}
+	storeOpts = append([]v1alpha1store.StoreOption{v1alpha1store.WithAuditor(auditor)}, storeOpts...)
+	// Resolve schemas once and inject them, so the stores qualify their
+	// tables explicitly rather than depend on the connection's
+	// search_path.
+	schemas := pkgdb.OSSSchemaRegistry()
+	ossSchema := schemas.MustGet(pkgdb.OSSSourceName)
+	stores := v1alpha1store.NewStores(pool, schemas, storeOpts...)
+	for kind, table := range extraStoreTables {
+		if kind == "" || table == "" {
+			slog.Warn("skipping v1alpha1 extra store with empty kind or table", "kind", kind, "table", table)
+			continue
+		}
+		// Honor a qualified "schema.table" so a kind registered in its own
+		// schema resolves there (see V1Alpha1StoreTables); a bare "table"
+		// stays in the OSS schema.
+		sch, tbl := resolveExtraStoreSchema(table, ossSchema)
+		if tbl == "" {
+			slog.Warn("skipping v1alpha1 extra store with empty table after schema qualifier", "kind", kind, "table", table)
+			continue
+		}
+		opts := append([]v1alpha1store.StoreOption{v1alpha1store.WithKind(kind)}, storeOpts...)
+		if mutableExtraKinds[kind] {
+			stores[kind] = v1alpha1store.NewMutableObjectStore(pool, sch, tbl, opts...)
+			continue
+		}
+		stores[kind] = v1alpha1store.NewStore(pool, sch, tbl, opts...)
+	}
+
+	// pool == nil is the noop/DatabaseFactory path used by gen-openapi
+	// and the release-openapi make target. Routes still register so the
+	// generated OpenAPI captures every endpoint, but actual queries
+	// would crash on the nil pool — that's fine because the noop path
+	// never serves real traffic.
+	if pool == nil {
+		slog.Info("v1alpha1 routes registered against nil pool: query path will panic if exercised (likely noop/DatabaseFactory)")
+		return stores
+	}
+
+	slog.Info("v1alpha1 routes enabled")
+	return stores
+}
+
+// openDatabase selects and constructs the base Store (plus any
+// DatabaseFactory wrap) and returns it. Two paths:
+//   - DATABASE_URL="noop" requires options.DatabaseFactory to supply the
+//     Store entirely (e.g. in-memory or custom backend). Used by tests
+//     and noop runs.
+//   - Otherwise connect to PostgreSQL; if a DatabaseFactory is set, it
+//     wraps the base pool so implementors can run additional migrations
+//     and layer authz/caching on top.
+//
+// On factory failure the base pool is closed before returning the wrap
+// error so we don't leak connections into the caller's error path.
+func openDatabase(
+	appCtx, dbCtx context.Context,
+	cfg *config.Config,
+	options types.AppOptions,
+	authz auth.Authorizer,
+	skipMigrations bool,
+) (pkgdb.Store, error) {
+	if cfg.DatabaseURL == "noop" {
+		if options.DatabaseFactory == nil {
+			return nil, fmt.Errorf("DATABASE_URL=noop requires DatabaseFactory to be set in AppOptions")
+		}
+		slog.Info("using DatabaseFactory to create database", "mode", "noop")
+		db, err := options.DatabaseFactory(appCtx, "", nil, authz)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create database via factory: %w", err)
+		}
+		return db, nil
+	}
+
+	var dbOpts []internaldb.Option
+	if cfg.DatabaseRLSEnabled {
+		slog.Info("enabling Postgres row-level security for namespace scoping")
+		dbOpts = append(dbOpts, internaldb.WithNamespaceRLS())
+	}
+	baseDB, err := internaldb.NewPostgreSQL(dbCtx, cfg.DatabaseURL, authz, skipMigrations, dbOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+	if options.DatabaseFactory == nil {
+		return baseDB, nil
+	}
+	wrapped, err := options.DatabaseFactory(appCtx, cfg.DatabaseURL, baseDB, authz)
+	if err != nil {
+		if closeErr := baseDB.Close(); closeErr != nil {
+			slog.Error("error closing base database connection", "error", closeErr)
+		}
+		return nil, fmt.Errorf("failed to create extended database: %w", err)
+	}
+	return wrapped, nil
+}
@@ -32,6 +32,14 @@ type Metrics struct {
 
 	// Up tracks the health of the service
 	Up metric.Int64Gauge
+
+	// Throttled counts requests and publishes refused by rate limits and
+	// quotas, by scope.
+	Throttled metric.Int64Counter
+
+	// Usage tallies requests per namespace and caller for the admin usage
+	// report.
+	Usage *Usage
 }
 
 // ShutdownFunc is a delegate that shuts down the OpenTelemetry components.
@@ -73,11 +81,21 @@ func NewMetrics(meter metric.Meter) (*Metrics, error) {
 		return nil, fmt.Errorf("failed to create service up gauge: %w", err)
 	}
 
+	throttled, err := meter.Int64Counter(
+		Namespace+".http.throttled",
+		metric.WithDescription("Total number of requests refused by rate limits and publish quotas"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create throttled counter: %w", err)
+	}
+
 	return &Metrics{
 		Requests:        req,
 		RequestDuration: reqDuration,
 		ErrorCount:      errCount,
 		Up:              up,
+		Throttled:       throttled,
+		Usage:           NewUsage(),
 	}, nil
 }
 
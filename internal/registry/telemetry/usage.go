@@ -0,0 +1,170 @@
+package telemetry
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
+)
+
+const (
+	// UsageBucket is the granularity of usage windows.
+	UsageBucket = time.Minute
+	// MaxUsageWindow is how far back usage reports can look.
+	MaxUsageWindow = 24 * time.Hour
+	// maxUsageKeys bounds each per-bucket tally so a scan from many source
+	// addresses cannot grow memory without limit. Keys beyond the cap are
+	// folded into UsageOverflowKey.
+	maxUsageKeys = 1000
+	// UsageOverflowKey collects requests from keys seen after the cap.
+	UsageOverflowKey = "(other)"
+)
+
+type usageCount struct {
+	requests int64
+	errors   int64
+}
+
+type usageBucket struct {
+	start      time.Time
+	namespaces map[string]*usageCount
+	callers    map[string]*usageCount
+}
+
+// Usage keeps per-minute request tallies by namespace and caller for the
+// admin usage report. The Prometheus series carry the per-namespace data for
+// long-term storage; callers stay in memory because token subjects and client
+// addresses are unbounded label values.
+type Usage struct {
+	mu      sync.Mutex
+	started time.Time
+	buckets []usageBucket
+	now     func() time.Time
+}
+
+// NewUsage returns an empty tracker.
+func NewUsage() *Usage {
+	return newUsage(time.Now)
+}
+
+func newUsage(now func() time.Time) *Usage {
+	return &Usage{
+		started: now().UTC().Truncate(UsageBucket),
+		buckets: make([]usageBucket, MaxUsageWindow/UsageBucket),
+		now:     now,
+	}
+}
+
+// Record attributes one request to namespace and caller. A nil *Usage
+// records nothing.
+func (u *Usage) Record(namespace, caller string, statusCode int) {
+	if u == nil {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	bucket := u.bucket(u.now().UTC().Truncate(UsageBucket))
+	failed := statusCode >= 400
+	count(bucket.namespaces, namespace, failed)
+	count(bucket.callers, caller, failed)
+}
+
+// Top returns the limit busiest namespaces and callers over the trailing
+// window, clamped to MaxUsageWindow. limit <= 0 returns every key.
+func (u *Usage) Top(window time.Duration, limit int) arv0.UsageTopReport {
+	if u == nil {
+		return arv0.UsageTopReport{Namespaces: []arv0.UsageStats{}, Callers: []arv0.UsageStats{}}
+	}
+	window = min(max(window, UsageBucket), MaxUsageWindow)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := u.now().UTC()
+	since := now.Add(-window).Truncate(UsageBucket)
+	if since.Before(u.started) {
+		since = u.started
+	}
+	namespaces := map[string]*usageCount{}
+	callers := map[string]*usageCount{}
+	for i := range u.buckets {
+		b := &u.buckets[i]
+		if b.start.IsZero() || b.start.Before(since) {
+			continue
+		}
+		merge(namespaces, b.namespaces)
+		merge(callers, b.callers)
+	}
+	return arv0.UsageTopReport{
+		Window:     now.Sub(since).Truncate(time.Second).String(),
+		Since:      since,
+		Namespaces: ranked(namespaces, limit),
+		Callers:    ranked(callers, limit),
+	}
+}
+
+// bucket returns the ring slot for start, recycling it when it still holds
+// an older minute.
+func (u *Usage) bucket(start time.Time) *usageBucket {
+	b := &u.buckets[(start.Unix()/int64(UsageBucket/time.Second))%int64(len(u.buckets))]
+	if !b.start.Equal(start) {
+		*b = usageBucket{
+			start:      start,
+			namespaces: map[string]*usageCount{},
+			callers:    map[string]*usageCount{},
+		}
+	}
+	return b
+}
+
+func count(m map[string]*usageCount, key string, failed bool) {
+	c, ok := m[key]
+	if !ok {
+		if len(m) >= maxUsageKeys {
+			key = UsageOverflowKey
+			c = m[key]
+		}
+		if c == nil {
+			c = &usageCount{}
+			m[key] = c
+		}
+	}
+	c.requests++
+	if failed {
+		c.errors++
+	}
+}
+
+func merge(dst, src map[string]*usageCount) {
+	for key, c := range src {
+		d, ok := dst[key]
+		if !ok {
+			d = &usageCount{}
+			dst[key] = d
+		}
+		d.requests += c.requests
+		d.errors += c.errors
+	}
+}
+
+func ranked(m map[string]*usageCount, limit int) []arv0.UsageStats {
+	out := make([]arv0.UsageStats, 0, len(m))
+	for key, c := range m {
+		out = append(out, arv0.UsageStats{
+			Key:       key,
+			Requests:  c.requests,
+			Errors:    c.errors,
+			ErrorRate: float64(c.errors) / float64(c.requests),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Requests != out[j].Requests {
+			return out[i].Requests > out[j].Requests
+		}
+		return out[i].Key < out[j].Key
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
@@ -0,0 +1,66 @@
+package telemetry
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageTop(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	u := newUsage(func() time.Time { return now })
+
+	u.Record("team-a", "ci-bot", 200)
+	now = now.Add(30 * time.Minute)
+	u.Record("team-a", "ci-bot", 500)
+	u.Record("team-b", "10.0.0.2", 404)
+	u.Record("default", "ci-bot", 200)
+
+	report := u.Top(time.Hour, 2)
+	require.Equal(t, "30m0s", report.Window)
+	require.Len(t, report.Namespaces, 2)
+	require.Equal(t, "team-a", report.Namespaces[0].Key)
+	require.Equal(t, int64(2), report.Namespaces[0].Requests)
+	require.InDelta(t, 0.5, report.Namespaces[0].ErrorRate, 1e-9)
+	// Ties break by key so the report is stable.
+	require.Equal(t, "default", report.Namespaces[1].Key)
+	require.Equal(t, "ci-bot", report.Callers[0].Key)
+	require.Equal(t, int64(3), report.Callers[0].Requests)
+
+	// The first request falls outside a 10 minute window.
+	recent := u.Top(10*time.Minute, 0)
+	require.Len(t, recent.Namespaces, 3)
+	require.Equal(t, int64(1), recent.Namespaces[0].Requests)
+}
+
+func TestUsageRecyclesExpiredBuckets(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	u := newUsage(func() time.Time { return now })
+	u.Record("team-a", "ci-bot", 200)
+
+	now = now.Add(MaxUsageWindow)
+	u.Record("team-b", "ci-bot", 200)
+
+	report := u.Top(MaxUsageWindow, 0)
+	require.Len(t, report.Namespaces, 1)
+	require.Equal(t, "team-b", report.Namespaces[0].Key)
+}
+
+func TestUsageCapsTrackedKeys(t *testing.T) {
+	u := NewUsage()
+	for i := range maxUsageKeys + 5 {
+		u.Record("default", fmt.Sprintf("client-%d", i), 200)
+	}
+	report := u.Top(time.Hour, 0)
+	require.Len(t, report.Callers, maxUsageKeys+1)
+	require.Equal(t, UsageOverflowKey, report.Callers[0].Key)
+	require.Equal(t, int64(5), report.Callers[0].Requests)
+}
+
+func TestUsageNilSafe(t *testing.T) {
+	var u *Usage
+	u.Record("default", "ci-bot", 200)
+	require.Empty(t, u.Top(time.Hour, 10).Callers)
+}
@@ -0,0 +1,234 @@
+// Package uniqueness enforces cross-artifact uniqueness rules at apply
+// time: two artifacts must not claim the same package, remote endpoint or
+// image. Each rule extracts one value from a kind's spec; an apply whose
+// value is already used by another artifact of that kind in the same
+// namespace fails with a ConflictError naming the artifact that holds it.
+//
+// Tags of one artifact share their values freely, so publishing a new tag
+// of the same server never conflicts with itself. Rules are selected with
+// AGENT_REGISTRY_UNIQUENESS_RULES and wired in as Prepare hooks, so they
+// run on the dedicated PUT routes, the batch /v0/apply endpoint and
+// dry-runs alike.
+package uniqueness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Rule names accepted by New.
+const (
+	RuleMCPServerPackage   = "mcpserver-package"
+	RuleMCPServerRemoteURL = "mcpserver-remote-url"
+	RuleAgentImage         = "agent-image"
+)
+
+// DisableAll is the rule list value that turns every rule off.
+const DisableAll = "none"
+
+// Rule is one uniqueness constraint on a kind's spec.
+type Rule struct {
+	Name string
+	Kind string
+	// Field names the constrained value in conflict messages.
+	Field string
+	// Value returns the constrained value and a spec fragment matching
+	// every artifact that holds it. ok is false when the object doesn't
+	// set the value.
+	Value func(obj v1alpha1.Object) (value string, match map[string]any, ok bool)
+}
+
+// Rules returns the built-in rules, in the order they are checked.
+func Rules() []Rule {
+	return []Rule{
+		{
+			Name:  RuleMCPServerPackage,
+			Kind:  v1alpha1.KindMCPServer,
+			Field: "spec.source.package.origin",
+			Value: func(obj v1alpha1.Object) (string, map[string]any, bool) {
+				server, ok := obj.(*v1alpha1.MCPServer)
+				if !ok || server.Spec.Source == nil || server.Spec.Source.Package == nil ||
+					server.Spec.Source.Package.Origin.Identifier == "" {
+					return "", nil, false
+				}
+				origin := server.Spec.Source.Package.Origin
+				return fmt.Sprintf("%s %s", origin.Type, origin.Identifier), map[string]any{
+					"source": map[string]any{"package": map[string]any{"origin": map[string]any{
+						"type":       origin.Type,
+						"identifier": origin.Identifier,
+					}}},
+				}, true
+			},
+		},
+		{
+			Name:  RuleMCPServerRemoteURL,
+			Kind:  v1alpha1.KindMCPServer,
+			Field: "spec.remote.url",
+			Value: func(obj v1alpha1.Object) (string, map[string]any, bool) {
+				server, ok := obj.(*v1alpha1.MCPServer)
+				if !ok || server.Spec.Remote == nil || server.Spec.Remote.URL == "" {
+					return "", nil, false
+				}
+				url := server.Spec.Remote.URL
+				return url, map[string]any{"remote": map[string]any{"url": url}}, true
+			},
+		},
+		{
+			Name:  RuleAgentImage,
+			Kind:  v1alpha1.KindAgent,
+			Field: "spec.source.image",
+			Value: func(obj v1alpha1.Object) (string, map[string]any, bool) {
+				agent, ok := obj.(*v1alpha1.Agent)
+				if !ok || agent.Spec.Source == nil || agent.Spec.Source.Image == "" {
+					return "", nil, false
+				}
+				image := agent.Spec.Source.Image
+				return image, map[string]any{"source": map[string]any{"image": image}}, true
+			},
+		},
+	}
+}
+
+// Finder is the store surface a Checker reads. *v1alpha1store.Store
+// satisfies it; tests supply a fake.
+type Finder interface {
+	FindReferrers(ctx context.Context, pathJSON json.RawMessage, opts v1alpha1store.FindReferrersOpts) ([]*v1alpha1.RawObject, error)
+}
+
+// ConflictError reports the artifact already holding a value another
+// artifact tried to claim. It matches pkgdb.ErrAlreadyExists so apply
+// handlers answer 409.
+type ConflictError struct {
+	Rule  string
+	Field string
+	Value string
+	// Kind, Namespace, Name and Tag identify the conflicting artifact.
+	Kind, Namespace, Name, Tag string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %q is already used by %s %s/%s@%s (uniqueness rule %s)",
+		e.Field, e.Value, e.Kind, e.Namespace, e.Name, e.Tag, e.Rule)
+}
+
+// Is makes errors.Is(err, pkgdb.ErrAlreadyExists) hold.
+func (e *ConflictError) Is(target error) bool {
+	return target == pkgdb.ErrAlreadyExists
+}
+
+// Checker evaluates the enabled rules against the stores. A nil *Checker
+// enforces nothing.
+type Checker struct {
+	rules  []Rule
+	stores map[string]Finder
+}
+
+// New returns a Checker for the named rules. An empty list or the single
+// entry DisableAll enables none; an unknown name is an error.
+func New(names []string, stores map[string]Finder) (*Checker, error) {
+	if len(names) == 0 || (len(names) == 1 && names[0] == DisableAll) {
+		return nil, nil
+	}
+	all := Rules()
+	c := &Checker{stores: stores}
+	for _, name := range names {
+		i := slices.IndexFunc(all, func(r Rule) bool { return r.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown uniqueness rule %q", name)
+		}
+		if !slices.ContainsFunc(c.rules, func(r Rule) bool { return r.Name == name }) {
+			c.rules = append(c.rules, all[i])
+		}
+	}
+	return c, nil
+}
+
+// RuleNames returns the enabled rule names.
+func (c *Checker) RuleNames() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.rules))
+	for _, r := range c.rules {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// Kinds returns the kinds at least one enabled rule constrains.
+func (c *Checker) Kinds() []string {
+	if c == nil {
+		return nil
+	}
+	var kinds []string
+	for _, r := range c.rules {
+		if !slices.Contains(kinds, r.Kind) {
+			kinds = append(kinds, r.Kind)
+		}
+	}
+	return kinds
+}
+
+// Check returns a *ConflictError when obj claims a value another artifact
+// in its namespace already holds. Terminating rows don't count.
+func (c *Checker) Check(ctx context.Context, obj v1alpha1.Object) error {
+	if c == nil {
+		return nil
+	}
+	meta := obj.GetMetadata()
+	for _, rule := range c.rules {
+		if rule.Kind != obj.GetKind() {
+			continue
+		}
+		store, ok := c.stores[rule.Kind]
+		if !ok {
+			continue
+		}
+		value, match, ok := rule.Value(obj)
+		if !ok {
+			continue
+		}
+		path, err := json.Marshal(match)
+		if err != nil {
+			return fmt.Errorf("uniqueness rule %s: %w", rule.Name, err)
+		}
+		rows, err := store.FindReferrers(ctx, path, v1alpha1store.FindReferrersOpts{Namespace: meta.NamespaceOrDefault()})
+		if err != nil {
+			return fmt.Errorf("uniqueness rule %s: %w", rule.Name, err)
+		}
+		for _, row := range rows {
+			if row.Metadata.Name == meta.Name {
+				continue
+			}
+			return &ConflictError{
+				Rule:      rule.Name,
+				Field:     rule.Field,
+				Value:     value,
+				Kind:      rule.Kind,
+				Namespace: row.Metadata.Namespace,
+				Name:      row.Metadata.Name,
+				Tag:       row.Metadata.Tag,
+			}
+		}
+	}
+	return nil
+}
+
+// Prepare chains Check after next, so it sees the object as it will be
+// persisted. next may be nil.
+func (c *Checker) Prepare(next func(ctx context.Context, obj v1alpha1.Object) error) func(ctx context.Context, obj v1alpha1.Object) error {
+	return func(ctx context.Context, obj v1alpha1.Object) error {
+		if next != nil {
+			if err := next(ctx, obj); err != nil {
+				return err
+			}
+		}
+		return c.Check(ctx, obj)
+	}
+}
@@ -0,0 +1,188 @@
+package uniqueness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// fakeFinder answers FindReferrers with the rows whose spec contains the
+// fragment, using JSON containment on a map decode like Postgres' @>.
+type fakeFinder struct {
+	rows []*v1alpha1.RawObject
+}
+
+func (f fakeFinder) FindReferrers(_ context.Context, pathJSON json.RawMessage, opts v1alpha1store.FindReferrersOpts) ([]*v1alpha1.RawObject, error) {
+	var want map[string]any
+	if err := json.Unmarshal(pathJSON, &want); err != nil {
+		return nil, err
+	}
+	var out []*v1alpha1.RawObject
+	for _, row := range f.rows {
+		var spec map[string]any
+		if err := json.Unmarshal(row.Spec, &spec); err != nil {
+			return nil, err
+		}
+		if row.Metadata.Namespace == opts.Namespace && contains(spec, want) {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func contains(have, want map[string]any) bool {
+	for k, w := range want {
+		h, ok := have[k]
+		if !ok {
+			return false
+		}
+		if wm, ok := w.(map[string]any); ok {
+			hm, ok := h.(map[string]any)
+			if !ok || !contains(hm, wm) {
+				return false
+			}
+			continue
+		}
+		if h != w {
+			return false
+		}
+	}
+	return true
+}
+
+func row(t *testing.T, kind, namespace, name, tag string, spec any) *v1alpha1.RawObject {
+	t.Helper()
+	raw, err := json.Marshal(spec)
+	require.NoError(t, err)
+	return &v1alpha1.RawObject{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: kind},
+		Metadata: v1alpha1.ObjectMeta{Namespace: namespace, Name: name, Tag: tag},
+		Spec:     raw,
+	}
+}
+
+func TestChecker(t *testing.T) {
+	pkg := func(id string) *v1alpha1.MCPServerSource {
+		return &v1alpha1.MCPServerSource{Package: &v1alpha1.MCPPackage{
+			Origin: v1alpha1.MCPPackageOrigin{Type: v1alpha1.MCPPackageOriginTypeOCI, Identifier: id},
+		}}
+	}
+	servers := fakeFinder{rows: []*v1alpha1.RawObject{
+		row(t, v1alpha1.KindMCPServer, "default", "weather", "1.0.0", v1alpha1.MCPServerSpec{Source: pkg("ghcr.io/acme/weather:1.0.0")}),
+		row(t, v1alpha1.KindMCPServer, "default", "search", "1.0.0", v1alpha1.MCPServerSpec{Remote: &v1alpha1.MCPRemote{Type: "streamable-http", URL: "https://search.example.com/mcp"}}),
+	}}
+	agents := fakeFinder{rows: []*v1alpha1.RawObject{
+		row(t, v1alpha1.KindAgent, "team-a", "reporter", "2.0.0", v1alpha1.AgentSpec{Source: &v1alpha1.AgentSource{Image: "ghcr.io/acme/reporter:2.0.0"}}),
+	}}
+	stores := map[string]Finder{v1alpha1.KindMCPServer: servers, v1alpha1.KindAgent: agents}
+
+	server := func(name string, spec v1alpha1.MCPServerSpec) *v1alpha1.MCPServer {
+		return &v1alpha1.MCPServer{
+			TypeMeta: v1alpha1.TypeMeta{Kind: v1alpha1.KindMCPServer},
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: name, Tag: "2.0.0"},
+			Spec:     spec,
+		}
+	}
+	agent := func(namespace, name string) *v1alpha1.Agent {
+		return &v1alpha1.Agent{
+			TypeMeta: v1alpha1.TypeMeta{Kind: v1alpha1.KindAgent},
+			Metadata: v1alpha1.ObjectMeta{Namespace: namespace, Name: name, Tag: "1.0.0"},
+			Spec:     v1alpha1.AgentSpec{Source: &v1alpha1.AgentSource{Image: "ghcr.io/acme/reporter:2.0.0"}},
+		}
+	}
+
+	checker, err := New([]string{RuleMCPServerPackage, RuleMCPServerRemoteURL, RuleAgentImage}, stores)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		obj  v1alpha1.Object
+		want *ConflictError
+	}{
+		{
+			name: "package held by another server",
+			obj:  server("weather-fork", v1alpha1.MCPServerSpec{Source: pkg("ghcr.io/acme/weather:1.0.0")}),
+			want: &ConflictError{
+				Rule: RuleMCPServerPackage, Field: "spec.source.package.origin", Value: "oci ghcr.io/acme/weather:1.0.0",
+				Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "weather", Tag: "1.0.0",
+			},
+		},
+		{
+			name: "new tag of the same server",
+			obj:  server("weather", v1alpha1.MCPServerSpec{Source: pkg("ghcr.io/acme/weather:1.0.0")}),
+		},
+		{
+			name: "remote URL held by another server",
+			obj:  server("search-2", v1alpha1.MCPServerSpec{Remote: &v1alpha1.MCPRemote{Type: "streamable-http", URL: "https://search.example.com/mcp"}}),
+			want: &ConflictError{
+				Rule: RuleMCPServerRemoteURL, Field: "spec.remote.url", Value: "https://search.example.com/mcp",
+				Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "search", Tag: "1.0.0",
+			},
+		},
+		{
+			name: "image held by another agent",
+			obj:  agent("team-a", "reporter-copy"),
+			want: &ConflictError{
+				Rule: RuleAgentImage, Field: "spec.source.image", Value: "ghcr.io/acme/reporter:2.0.0",
+				Kind: v1alpha1.KindAgent, Namespace: "team-a", Name: "reporter", Tag: "2.0.0",
+			},
+		},
+		{
+			name: "same image in another namespace",
+			obj:  agent("team-b", "reporter-copy"),
+		},
+		{
+			name: "unconstrained server",
+			obj:  server("bare", v1alpha1.MCPServerSpec{Title: "Bare"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checker.Check(ctx, tt.obj)
+			if tt.want == nil {
+				require.NoError(t, err)
+				return
+			}
+			var conflict *ConflictError
+			require.ErrorAs(t, err, &conflict)
+			require.Equal(t, tt.want, conflict)
+			require.ErrorIs(t, err, pkgdb.ErrAlreadyExists)
+		})
+	}
+
+	t.Run("disabled rules are skipped", func(t *testing.T) {
+		only, err := New([]string{RuleAgentImage}, stores)
+		require.NoError(t, err)
+		require.Equal(t, []string{v1alpha1.KindAgent}, only.Kinds())
+		require.NoError(t, only.Check(ctx, server("weather-fork", v1alpha1.MCPServerSpec{Source: pkg("ghcr.io/acme/weather:1.0.0")})))
+	})
+
+	t.Run("prepare runs the previous hook first", func(t *testing.T) {
+		hookErr := errors.New("hook failed")
+		prepare := checker.Prepare(func(context.Context, v1alpha1.Object) error { return hookErr })
+		require.ErrorIs(t, prepare(ctx, agent("team-b", "x")), hookErr)
+		require.Error(t, checker.Prepare(nil)(ctx, agent("team-a", "reporter-copy")))
+	})
+}
+
+func TestNew(t *testing.T) {
+	c, err := New([]string{DisableAll}, nil)
+	require.NoError(t, err)
+	require.Nil(t, c)
+	require.NoError(t, c.Check(context.Background(), &v1alpha1.Agent{}))
+
+	_, err = New([]string{"skill-image"}, nil)
+	require.ErrorContains(t, err, `unknown uniqueness rule "skill-image"`)
+
+	c, err = New([]string{RuleAgentImage, RuleAgentImage}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{RuleAgentImage}, c.RuleNames())
+}
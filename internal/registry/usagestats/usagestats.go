@@ -0,0 +1,216 @@
+// Package usagestats counts how Agents, MCPServers and Skills are used:
+// GETs of the artifact (downloads), applied Deployments of it (deploys) and
+// MCP registry searches that return it (search hits). Counts are buffered
+// in memory and added to the usage_stats table in periodic batches, so a
+// hot read path never waits on a write. The registry-wide totals per kind
+// are exported to Prometheus as agent_registry_artifact_usage_total.
+package usagestats
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/telemetry"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+const (
+	// DefaultFlushInterval is how often buffered counts are written out.
+	DefaultFlushInterval = 10 * time.Second
+	// flushTimeout bounds the final flush on shutdown.
+	flushTimeout = 5 * time.Second
+)
+
+// kinds are the artifact kinds whose use is counted.
+var kinds = map[string]bool{
+	v1alpha1.KindAgent:     true,
+	v1alpha1.KindMCPServer: true,
+	v1alpha1.KindSkill:     true,
+}
+
+// Tracked reports whether use of kind is counted.
+func Tracked(kind string) bool {
+	return kinds[kind]
+}
+
+// Store persists the counters. *v1alpha1store.UsageStatsStore satisfies it.
+type Store interface {
+	Add(ctx context.Context, deltas map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts) error
+	Get(ctx context.Context, key v1alpha1store.UsageKey) (v1alpha1store.UsageCounts, error)
+	GetMany(ctx context.Context, keys []v1alpha1store.UsageKey) (map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts, error)
+	Top(ctx context.Context, kind, namespace string, limit int) ([]v1alpha1store.UsageKey, error)
+	Totals(ctx context.Context) (map[string]v1alpha1store.UsageCounts, error)
+}
+
+var _ Store = (*v1alpha1store.UsageStatsStore)(nil)
+
+// Recorder buffers usage counts and flushes them to a Store. Reads go
+// straight to the Store, so they trail live traffic by up to one flush
+// interval. A nil *Recorder records nothing.
+type Recorder struct {
+	store Store
+	// FlushInterval overrides DefaultFlushInterval.
+	FlushInterval time.Duration
+
+	mu      sync.Mutex
+	pending map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts
+	// totals are the Store's per-kind totals as of the last flush.
+	totals map[string]v1alpha1store.UsageCounts
+}
+
+// New returns a Recorder writing to store.
+func New(store Store) *Recorder {
+	return &Recorder{
+		store:   store,
+		pending: map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts{},
+		totals:  map[string]v1alpha1store.UsageCounts{},
+	}
+}
+
+// RecordDownload counts one GET of an artifact.
+func (r *Recorder) RecordDownload(kind, namespace, name string) {
+	r.record(kind, namespace, name, func(c *v1alpha1store.UsageCounts) { c.Downloads++ })
+}
+
+// RecordDeploy counts one applied Deployment of an artifact.
+func (r *Recorder) RecordDeploy(kind, namespace, name string) {
+	r.record(kind, namespace, name, func(c *v1alpha1store.UsageCounts) { c.Deploys++ })
+}
+
+// RecordSearchHit counts one search that returned an artifact.
+func (r *Recorder) RecordSearchHit(kind, namespace, name string) {
+	r.record(kind, namespace, name, func(c *v1alpha1store.UsageCounts) { c.SearchHits++ })
+}
+
+func (r *Recorder) record(kind, namespace, name string, inc func(*v1alpha1store.UsageCounts)) {
+	if r == nil || !Tracked(kind) {
+		return
+	}
+	key := v1alpha1store.UsageKey{Kind: kind, Namespace: namespace, Name: name}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.pending[key]
+	inc(&c)
+	r.pending[key] = c
+}
+
+// Flush writes the buffered counts and refreshes the totals. Counts that
+// fail to write stay buffered for the next flush.
+func (r *Recorder) Flush(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	batch := r.pending
+	r.pending = map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts{}
+	r.mu.Unlock()
+
+	if err := r.store.Add(ctx, batch); err != nil {
+		r.mu.Lock()
+		for key, c := range batch {
+			p := r.pending[key]
+			p.Downloads += c.Downloads
+			p.Deploys += c.Deploys
+			p.SearchHits += c.SearchHits
+			r.pending[key] = p
+		}
+		r.mu.Unlock()
+		return err
+	}
+	totals, err := r.store.Totals(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.totals = totals
+	r.mu.Unlock()
+	return nil
+}
+
+// Run flushes every FlushInterval until ctx is done, then flushes once more.
+func (r *Recorder) Run(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	interval := r.FlushInterval
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	if err := r.Flush(ctx); err != nil {
+		slog.Warn("flush usage stats failed", "error", err)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), flushTimeout)
+			defer cancel()
+			if err := r.Flush(flushCtx); err != nil {
+				slog.Warn("flush usage stats failed", "error", err)
+			}
+			return
+		case <-ticker.C:
+			if err := r.Flush(ctx); err != nil {
+				slog.Warn("flush usage stats failed", "error", err)
+			}
+		}
+	}
+}
+
+// Get returns the counters of one artifact.
+func (r *Recorder) Get(ctx context.Context, key v1alpha1store.UsageKey) (v1alpha1store.UsageCounts, error) {
+	return r.store.Get(ctx, key)
+}
+
+// Counts returns the counters of each key with recorded use.
+func (r *Recorder) Counts(ctx context.Context, keys []v1alpha1store.UsageKey) (map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts, error) {
+	return r.store.GetMany(ctx, keys)
+}
+
+// Top returns up to limit artifacts of kind, most popular first.
+func (r *Recorder) Top(ctx context.Context, kind, namespace string, limit int) ([]v1alpha1store.UsageKey, error) {
+	return r.store.Top(ctx, kind, namespace, limit)
+}
+
+// RegisterMetrics exports the registry-wide totals per kind and event as
+// agent_registry.artifact.usage. Every replica reports the shared totals,
+// so aggregate across replicas with max rather than sum.
+func (r *Recorder) RegisterMetrics(meter metric.Meter) error {
+	_, err := meter.Int64ObservableCounter(
+		telemetry.Namespace+".artifact.usage",
+		metric.WithDescription("Total downloads, deploys and search hits of registry artifacts"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			byKind := make(map[string]v1alpha1store.UsageCounts, len(r.totals))
+			for kind, c := range r.totals {
+				byKind[kind] = c
+			}
+			for key, c := range r.pending {
+				t := byKind[key.Kind]
+				t.Downloads += c.Downloads
+				t.Deploys += c.Deploys
+				t.SearchHits += c.SearchHits
+				byKind[key.Kind] = t
+			}
+			for kind, c := range byKind {
+				o.Observe(c.Downloads, metric.WithAttributes(attribute.String("kind", kind), attribute.String("event", "download")))
+				o.Observe(c.Deploys, metric.WithAttributes(attribute.String("kind", kind), attribute.String("event", "deploy")))
+				o.Observe(c.SearchHits, metric.WithAttributes(attribute.String("kind", kind), attribute.String("event", "search_hit")))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create artifact usage counter: %w", err)
+	}
+	return nil
+}
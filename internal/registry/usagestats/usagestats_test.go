@@ -0,0 +1,84 @@
+package usagestats_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/usagestats"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// memStore keeps counters in a map; addErr fails the next Add.
+type memStore struct {
+	rows   map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts
+	addErr error
+}
+
+func (s *memStore) Add(_ context.Context, deltas map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts) error {
+	if err := s.addErr; err != nil {
+		s.addErr = nil
+		return err
+	}
+	for key, c := range deltas {
+		r := s.rows[key]
+		r.Downloads += c.Downloads
+		r.Deploys += c.Deploys
+		r.SearchHits += c.SearchHits
+		s.rows[key] = r
+	}
+	return nil
+}
+
+func (s *memStore) Get(_ context.Context, key v1alpha1store.UsageKey) (v1alpha1store.UsageCounts, error) {
+	return s.rows[key], nil
+}
+
+func (s *memStore) GetMany(context.Context, []v1alpha1store.UsageKey) (map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts, error) {
+	return s.rows, nil
+}
+
+func (s *memStore) Top(context.Context, string, string, int) ([]v1alpha1store.UsageKey, error) {
+	return nil, nil
+}
+
+func (s *memStore) Totals(context.Context) (map[string]v1alpha1store.UsageCounts, error) {
+	out := map[string]v1alpha1store.UsageCounts{}
+	for key, c := range s.rows {
+		t := out[key.Kind]
+		t.Downloads += c.Downloads
+		out[key.Kind] = t
+	}
+	return out, nil
+}
+
+func TestRecorderFlush(t *testing.T) {
+	ctx := context.Background()
+	store := &memStore{rows: map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts{}}
+	r := usagestats.New(store)
+	tools := v1alpha1store.UsageKey{Kind: v1alpha1.KindMCPServer, Namespace: "default", Name: "tools"}
+
+	r.RecordDownload(tools.Kind, tools.Namespace, tools.Name)
+	r.RecordDownload(tools.Kind, tools.Namespace, tools.Name)
+	r.RecordSearchHit(tools.Kind, tools.Namespace, tools.Name)
+	r.RecordDeploy(v1alpha1.KindRuntime, "default", "local")
+
+	store.addErr = errors.New("connection refused")
+	require.Error(t, r.Flush(ctx))
+	require.Empty(t, store.rows, "a failed flush writes nothing")
+
+	r.RecordDeploy(tools.Kind, tools.Namespace, tools.Name)
+	require.NoError(t, r.Flush(ctx))
+	got, err := r.Get(ctx, tools)
+	require.NoError(t, err)
+	require.Equal(t, v1alpha1store.UsageCounts{Downloads: 2, Deploys: 1, SearchHits: 1}, got,
+		"counts from the failed flush are kept for the next one")
+	require.Len(t, store.rows, 1, "untracked kinds are not counted")
+
+	var nilRecorder *usagestats.Recorder
+	nilRecorder.RecordDownload(tools.Kind, tools.Namespace, tools.Name)
+	require.NoError(t, nilRecorder.Flush(ctx))
+}
@@ -0,0 +1,590 @@
+// Package webhooks delivers registry lifecycle events to the Webhook
+// resources subscribed in the event's namespace.
+//
+// Events come from three seams:
+//
+//   - types.Auditor.ResourceTagCreated: a new MCPServer, Agent or Skill tag
+//     (server.published, agent.published, skill.published);
+//   - types.ObjectAuditor.ObjectCreated: a new Deployment row
+//     (deployment.created);
+//   - controller.DeploymentFailureNotifier: the runtime adapter failed to
+//     apply a Deployment generation (deployment.failed).
+//
+// Each event is POSTed as JSON to every matching webhook in the background.
+// Requests carry X-Agentregistry-Event and X-Agentregistry-Delivery headers
+// and, when the webhook names a secret, the same HMAC signature header the
+// publish triggers use (see pipelines.Sign). Network errors and 5xx/429
+// responses are retried with exponential backoff; every attempt is written
+// to the webhook_deliveries log.
+//
+// A delivery that still fails is parked with its payload as a dead letter
+// (Config.DeadLetters) rather than dropped. RunRetries sends pending dead
+// letters again on a slower schedule whose backoff is capped at
+// Config.MaxRetryBackoff, until one succeeds or Config.MaxRetries run out;
+// Replay sends one on demand.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/controller"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/pipelines"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/auth"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+// HeaderDelivery carries the delivery ID, shared by every attempt of one
+// delivery so receivers can deduplicate retries.
+const HeaderDelivery = "X-Agentregistry-Delivery"
+
+const (
+	// deliveryTimeout bounds one attempt.
+	deliveryTimeout = 30 * time.Second
+	// pruneInterval spaces delivery-log retention sweeps.
+	pruneInterval = time.Hour
+	// listPageSize is the page size used to load a namespace's webhooks.
+	listPageSize = 200
+
+	defaultMaxAttempts = 5
+	defaultBackoff     = time.Second
+
+	defaultMaxRetries      = 10
+	defaultRetryBackoff    = time.Minute
+	defaultMaxRetryBackoff = time.Hour
+	defaultRetryInterval   = 30 * time.Second
+	// retryBatch caps the dead letters claimed per poll; retryLease is how
+	// long a claim keeps other replicas off them.
+	retryBatch = 50
+	retryLease = 5 * time.Minute
+)
+
+// publishEvents maps tagged kinds to their publish event.
+var publishEvents = map[string]string{
+	v1alpha1.KindMCPServer: v1alpha1.WebhookEventServerPublished,
+	v1alpha1.KindAgent:     v1alpha1.WebhookEventAgentPublished,
+	v1alpha1.KindSkill:     v1alpha1.WebhookEventSkillPublished,
+}
+
+// Lister loads Webhook rows. *v1alpha1store.Store satisfies it.
+type Lister interface {
+	List(ctx context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error)
+}
+
+// DeliveryLog records delivery attempts. *v1alpha1store.WebhookDeliveryStore
+// satisfies it.
+type DeliveryLog interface {
+	Record(ctx context.Context, d v1alpha1store.WebhookDelivery) error
+	PruneBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DeadLetters parks failed deliveries. *v1alpha1store.WebhookDeadLetterStore
+// satisfies it.
+type DeadLetters interface {
+	Put(ctx context.Context, d v1alpha1store.WebhookDeadLetter) error
+	Get(ctx context.Context, namespace, webhook, deliveryID string) (*v1alpha1store.WebhookDeadLetter, error)
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]v1alpha1store.WebhookDeadLetter, error)
+	PruneResolvedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Config tunes delivery.
+type Config struct {
+	// MaxAttempts caps attempts per delivery (default 5). Backoff is the
+	// delay before the first retry (default 1s); it doubles per attempt.
+	MaxAttempts int
+	Backoff     time.Duration
+	// Retention is how long delivery attempts, and dead letters once
+	// delivered or exhausted, are kept. Zero keeps them forever.
+	Retention time.Duration
+
+	// DeadLetters parks deliveries whose attempts all failed. Nil drops
+	// them once the failure is logged.
+	DeadLetters DeadLetters
+	// MaxRetries caps the automatic retries of a dead letter (default 10).
+	// RetryBackoff is the delay before the first (default 1m); it doubles
+	// per retry up to MaxRetryBackoff (default 1h). RetryInterval is how
+	// often RunRetries looks for due dead letters (default 30s).
+	MaxRetries      int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	RetryInterval   time.Duration
+}
+
+// Event is the JSON body delivered to webhooks.
+type Event struct {
+	// ID identifies the event; it is the same for every webhook it is
+	// delivered to.
+	ID        string `json:"id"`
+	Event     string `json:"event"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag,omitempty"`
+	// Path is the registry API path of the resource.
+	Path      string    `json:"path"`
+	Subject   string    `json:"subject,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// Generation and Reason are set on deployment.failed.
+	Generation int64  `json:"generation,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// Dispatcher fans events out to subscribed webhooks. Deliveries run in the
+// background so writes never wait on (or fail because of) a receiver.
+type Dispatcher struct {
+	webhooks Lister
+	log      DeliveryLog
+	client   *http.Client
+	cfg      Config
+	now      func() time.Time
+	wg       sync.WaitGroup
+
+	pruneMu   sync.Mutex
+	lastPrune time.Time
+}
+
+var (
+	_ types.Auditor                        = (*Dispatcher)(nil)
+	_ types.ObjectAuditor                  = (*Dispatcher)(nil)
+	_ controller.DeploymentFailureNotifier = (*Dispatcher)(nil)
+)
+
+// NewDispatcher builds a Dispatcher. client carries the outbound transport
+// (proxy, custom CAs).
+func NewDispatcher(webhooks Lister, log DeliveryLog, client *http.Client, cfg Config) *Dispatcher {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = defaultBackoff
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+	if cfg.MaxRetryBackoff <= 0 {
+		cfg.MaxRetryBackoff = defaultMaxRetryBackoff
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defaultRetryInterval
+	}
+	return &Dispatcher{webhooks: webhooks, log: log, client: client, cfg: cfg, now: time.Now}
+}
+
+// ResourceTagCreated implements types.Auditor.
+func (d *Dispatcher) ResourceTagCreated(ctx context.Context, kind, namespace, name, tag string) {
+	event, ok := publishEvents[kind]
+	if !ok {
+		return
+	}
+	path := fmt.Sprintf("/v0/%s/%s/%s?namespace=%s",
+		v1alpha1.PluralFor(kind), url.PathEscape(name), url.PathEscape(tag), url.QueryEscape(namespace))
+	d.dispatch(ctx, d.event(ctx, event, kind, namespace, name, tag, path))
+}
+
+// ObjectCreated implements types.ObjectAuditor.
+func (d *Dispatcher) ObjectCreated(ctx context.Context, kind, namespace, name string) {
+	if kind != v1alpha1.KindDeployment {
+		return
+	}
+	d.dispatch(ctx, d.event(ctx, v1alpha1.WebhookEventDeploymentCreated, kind, namespace, name, "", deploymentPath(namespace, name)))
+}
+
+// DeploymentFailed implements controller.DeploymentFailureNotifier.
+func (d *Dispatcher) DeploymentFailed(ctx context.Context, namespace, name string, generation int64, reason string) {
+	ev := d.event(ctx, v1alpha1.WebhookEventDeploymentFailed, v1alpha1.KindDeployment, namespace, name, "", deploymentPath(namespace, name))
+	ev.Generation = generation
+	ev.Reason = reason
+	d.dispatch(ctx, ev)
+}
+
+// Wait blocks until in-flight deliveries finish.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func deploymentPath(namespace, name string) string {
+	return fmt.Sprintf("/v0/deployments/%s?namespace=%s", url.PathEscape(name), url.QueryEscape(namespace))
+}
+
+func (d *Dispatcher) event(ctx context.Context, event, kind, namespace, name, tag, path string) Event {
+	return Event{
+		ID:        newID(),
+		Event:     event,
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Tag:       tag,
+		Path:      path,
+		Subject:   auth.SubjectFrom(ctx),
+		Timestamp: d.now().UTC(),
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, ev Event) {
+	// Detach from the request, which ends as soon as the write responds,
+	// and read webhooks regardless of the caller's namespace scope.
+	ctx = auth.WithSystemContext(context.WithoutCancel(ctx))
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		hooks, err := d.subscribers(ctx, ev)
+		if err != nil {
+			slog.Error("webhook lookup failed", "event", ev.Event, "namespace", ev.Namespace, "error", err)
+			return
+		}
+		body, err := json.Marshal(ev)
+		if err != nil {
+			slog.Error("webhook event encode failed", "event", ev.Event, "error", err)
+			return
+		}
+		for _, hook := range hooks {
+			d.wg.Add(1)
+			go func() {
+				defer d.wg.Done()
+				d.deliver(ctx, hook, ev, body)
+			}()
+		}
+		d.maybePrune(ctx)
+	}()
+}
+
+// subscribers returns the webhooks in ev's namespace subscribed to ev.
+func (d *Dispatcher) subscribers(ctx context.Context, ev Event) ([]*v1alpha1.Webhook, error) {
+	var (
+		out    []*v1alpha1.Webhook
+		cursor string
+	)
+	for {
+		rows, next, err := d.webhooks.List(ctx, v1alpha1store.ListOpts{Namespace: ev.Namespace, Limit: listPageSize, Cursor: cursor})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			hook := &v1alpha1.Webhook{Metadata: row.Metadata}
+			if err := hook.UnmarshalSpec(row.Spec); err != nil {
+				slog.Warn("skipping malformed webhook", "namespace", row.Metadata.Namespace, "name", row.Metadata.Name, "error", err)
+				continue
+			}
+			if hook.Spec.Subscribes(ev.Event) {
+				out = append(out, hook)
+			}
+		}
+		if next == "" {
+			return out, nil
+		}
+		cursor = next
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, hook *v1alpha1.Webhook, ev Event, body []byte) {
+	record := v1alpha1store.WebhookDelivery{
+		DeliveryID: newID(),
+		Namespace:  hook.Metadata.Namespace,
+		Webhook:    hook.Metadata.Name,
+		Event:      ev.Event,
+		URL:        hook.Spec.URL,
+		Payload:    body,
+	}
+	secret, err := webhookSecret(hook)
+	if err != nil {
+		record.Attempt = 1
+		record.Error = err.Error()
+		d.record(ctx, record)
+		d.park(ctx, record, true)
+		return
+	}
+	retry := false
+	for attempt := 1; attempt <= d.cfg.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(d.cfg.Backoff << (attempt - 2))
+		}
+		record.Attempt = attempt
+		record.StatusCode, record.Error = 0, ""
+		retry = d.attempt(ctx, secret, &record)
+		d.record(ctx, record)
+		if !retry {
+			break
+		}
+	}
+	if record.Error != "" {
+		slog.Error("webhook delivery failed", "webhook", hook.Metadata.Name, "namespace", hook.Metadata.Namespace,
+			"event", ev.Event, "attempts", record.Attempt, "error", record.Error)
+		d.park(ctx, record, retry)
+	}
+}
+
+// webhookSecret returns the HMAC secret hook signs with, "" when it signs
+// nothing.
+func webhookSecret(hook *v1alpha1.Webhook) (string, error) {
+	env := hook.Spec.SecretEnv
+	if env == "" {
+		return "", nil
+	}
+	if secret := os.Getenv(env); secret != "" {
+		return secret, nil
+	}
+	return "", fmt.Errorf("secret env %s is empty", env)
+}
+
+// park records the failed delivery whose last attempt is record as a dead
+// letter, pending an automatic retry when the failure is worth retrying.
+func (d *Dispatcher) park(ctx context.Context, record v1alpha1store.WebhookDelivery, retry bool) {
+	if d.cfg.DeadLetters == nil {
+		return
+	}
+	dead := v1alpha1store.WebhookDeadLetter{
+		DeliveryID: record.DeliveryID,
+		Namespace:  record.Namespace,
+		Webhook:    record.Webhook,
+		Event:      record.Event,
+		URL:        record.URL,
+		Payload:    record.Payload,
+		State:      v1alpha1store.WebhookDeadLetterExhausted,
+		Attempts:   record.Attempt,
+		StatusCode: record.StatusCode,
+		Error:      record.Error,
+	}
+	if retry {
+		dead.State = v1alpha1store.WebhookDeadLetterPending
+		next := d.now().Add(d.cfg.RetryBackoff)
+		dead.NextAttemptAt = &next
+	}
+	d.putDeadLetter(ctx, dead)
+}
+
+func (d *Dispatcher) putDeadLetter(ctx context.Context, dead v1alpha1store.WebhookDeadLetter) {
+	if err := d.cfg.DeadLetters.Put(ctx, dead); err != nil {
+		slog.Error("webhook dead letter write failed", "webhook", dead.Webhook, "namespace", dead.Namespace,
+			"delivery", dead.DeliveryID, "error", err)
+	}
+}
+
+// RunRetries retries due dead letters every Config.RetryInterval until ctx
+// is done. It returns at once when there are no dead letters to retry.
+func (d *Dispatcher) RunRetries(ctx context.Context) {
+	if d.cfg.DeadLetters == nil {
+		return
+	}
+	ctx = auth.WithSystemContext(ctx)
+	ticker := time.NewTicker(d.cfg.RetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := d.RetryDue(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("webhook dead letter retry failed", "error", err)
+		}
+	}
+}
+
+// RetryDue sends every pending dead letter whose retry is due once more. A
+// dead letter that fails again is rescheduled, with the delay doubled up to
+// Config.MaxRetryBackoff, or exhausted after Config.MaxRetries.
+func (d *Dispatcher) RetryDue(ctx context.Context) error {
+	for {
+		due, err := d.cfg.DeadLetters.ClaimDue(ctx, d.now(), retryBatch, retryLease)
+		if err != nil {
+			return err
+		}
+		for _, dead := range due {
+			record, retry := d.resend(ctx, dead)
+			dead.Retries++
+			dead.Attempts, dead.StatusCode, dead.Error = record.Attempt, record.StatusCode, record.Error
+			switch {
+			case record.Succeeded():
+				dead.State, dead.NextAttemptAt = v1alpha1store.WebhookDeadLetterDelivered, nil
+			case retry && dead.Retries < d.cfg.MaxRetries:
+				next := d.now().Add(d.retryDelay(dead.Retries))
+				dead.NextAttemptAt = &next
+			default:
+				dead.State, dead.NextAttemptAt = v1alpha1store.WebhookDeadLetterExhausted, nil
+			}
+			d.putDeadLetter(ctx, dead)
+		}
+		if len(due) < retryBatch {
+			return nil
+		}
+	}
+}
+
+// retryDelay is the delay after a dead letter's nth failed retry.
+func (d *Dispatcher) retryDelay(retries int) time.Duration {
+	delay := d.cfg.RetryBackoff
+	for i := 0; i < retries && delay < d.cfg.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.cfg.MaxRetryBackoff)
+}
+
+// ErrNoDeadLetters reports a Replay on a Dispatcher without
+// Config.DeadLetters.
+var ErrNoDeadLetters = errors.New("webhook dead letters are not enabled")
+
+// Replay sends one dead letter of a webhook again, now, and returns the
+// attempt. Success marks it delivered; a failure leaves its state and
+// schedule alone. A dead letter of any state can be replayed. Unknown dead
+// letters return pkgdb.ErrNotFound.
+func (d *Dispatcher) Replay(ctx context.Context, namespace, webhook, deliveryID string) (v1alpha1store.WebhookDelivery, error) {
+	if d.cfg.DeadLetters == nil {
+		return v1alpha1store.WebhookDelivery{}, ErrNoDeadLetters
+	}
+	dead, err := d.cfg.DeadLetters.Get(ctx, namespace, webhook, deliveryID)
+	if err != nil {
+		return v1alpha1store.WebhookDelivery{}, err
+	}
+	record, _ := d.resend(ctx, *dead)
+	dead.Attempts, dead.StatusCode, dead.Error = record.Attempt, record.StatusCode, record.Error
+	if record.Succeeded() {
+		dead.State, dead.NextAttemptAt = v1alpha1store.WebhookDeadLetterDelivered, nil
+	}
+	if err := d.cfg.DeadLetters.Put(ctx, *dead); err != nil {
+		return record, err
+	}
+	return record, nil
+}
+
+// resend makes one more attempt of dead to its webhook's current URL and
+// secret, records it in the delivery log and returns it, with whether a
+// failure is worth retrying.
+func (d *Dispatcher) resend(ctx context.Context, dead v1alpha1store.WebhookDeadLetter) (v1alpha1store.WebhookDelivery, bool) {
+	record := v1alpha1store.WebhookDelivery{
+		DeliveryID: dead.DeliveryID,
+		Namespace:  dead.Namespace,
+		Webhook:    dead.Webhook,
+		Event:      dead.Event,
+		URL:        dead.URL,
+		Attempt:    dead.Attempts + 1,
+		Payload:    dead.Payload,
+	}
+	retry := false
+	hook, err := d.webhookNamed(ctx, dead.Namespace, dead.Webhook)
+	switch {
+	case errors.Is(err, pkgdb.ErrNotFound):
+		record.Error = fmt.Sprintf("webhook %s/%s no longer exists", dead.Namespace, dead.Webhook)
+	case err != nil:
+		record.Error = fmt.Sprintf("load webhook: %v", err)
+		retry = true
+	default:
+		record.URL = hook.Spec.URL
+		secret, err := webhookSecret(hook)
+		if err != nil {
+			record.Error = err.Error()
+			retry = true
+			break
+		}
+		retry = d.attempt(ctx, secret, &record)
+	}
+	d.record(ctx, record)
+	return record, retry
+}
+
+// webhookNamed loads one webhook, or returns pkgdb.ErrNotFound.
+func (d *Dispatcher) webhookNamed(ctx context.Context, namespace, name string) (*v1alpha1.Webhook, error) {
+	cursor := ""
+	for {
+		rows, next, err := d.webhooks.List(ctx, v1alpha1store.ListOpts{Namespace: namespace, Limit: listPageSize, Cursor: cursor})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if row.Metadata.Name != name {
+				continue
+			}
+			hook := &v1alpha1.Webhook{Metadata: row.Metadata}
+			if err := hook.UnmarshalSpec(row.Spec); err != nil {
+				return nil, err
+			}
+			return hook, nil
+		}
+		if next == "" {
+			return nil, pkgdb.ErrNotFound
+		}
+		cursor = next
+	}
+}
+
+// attempt sends record's payload once and fills in the outcome. It reports
+// whether the failure is worth retrying.
+func (d *Dispatcher) attempt(ctx context.Context, secret string, record *v1alpha1store.WebhookDelivery) (retry bool) {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, record.URL, bytes.NewReader(record.Payload))
+	if err != nil {
+		record.Error = err.Error()
+		return false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(pipelines.HeaderEvent, record.Event)
+	req.Header.Set(HeaderDelivery, record.DeliveryID)
+	if secret != "" {
+		req.Header.Set(pipelines.HeaderSignature, pipelines.Sign([]byte(secret), record.Payload))
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		record.Error = err.Error()
+		return true
+	}
+	_ = resp.Body.Close()
+	record.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false
+	}
+	record.Error = resp.Status
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+}
+
+func (d *Dispatcher) record(ctx context.Context, record v1alpha1store.WebhookDelivery) {
+	if err := d.log.Record(ctx, record); err != nil {
+		slog.Error("webhook delivery log write failed", "webhook", record.Webhook, "namespace", record.Namespace, "error", err)
+	}
+}
+
+// maybePrune drops delivery attempts and resolved dead letters older than
+// the retention window, at most once per pruneInterval.
+func (d *Dispatcher) maybePrune(ctx context.Context) {
+	if d.cfg.Retention <= 0 {
+		return
+	}
+	now := d.now()
+	d.pruneMu.Lock()
+	if now.Sub(d.lastPrune) < pruneInterval {
+		d.pruneMu.Unlock()
+		return
+	}
+	d.lastPrune = now
+	d.pruneMu.Unlock()
+	if _, err := d.log.PruneBefore(ctx, now.Add(-d.cfg.Retention)); err != nil {
+		slog.Error("webhook delivery log prune failed", "error", err)
+	}
+	if d.cfg.DeadLetters != nil {
+		if _, err := d.cfg.DeadLetters.PruneResolvedBefore(ctx, now.Add(-d.cfg.Retention)); err != nil {
+			slog.Error("webhook dead letter prune failed", "error", err)
+		}
+	}
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,350 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/internal/registry/pipelines"
+	"github.com/agentregistry-dev/agentregistry/internal/registry/webhooks"
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+type fakeLister struct {
+	hooks []v1alpha1.Webhook
+}
+
+func (f fakeLister) List(_ context.Context, opts v1alpha1store.ListOpts) ([]*v1alpha1.RawObject, string, error) {
+	var out []*v1alpha1.RawObject
+	for _, hook := range f.hooks {
+		if hook.Metadata.Namespace != opts.Namespace {
+			continue
+		}
+		spec, err := hook.MarshalSpec()
+		if err != nil {
+			return nil, "", err
+		}
+		out = append(out, &v1alpha1.RawObject{Metadata: hook.Metadata, Spec: spec})
+	}
+	return out, "", nil
+}
+
+type fakeLog struct {
+	mu         sync.Mutex
+	deliveries []v1alpha1store.WebhookDelivery
+}
+
+func (f *fakeLog) Record(_ context.Context, d v1alpha1store.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deliveries = append(f.deliveries, d)
+	return nil
+}
+
+func (f *fakeLog) PruneBefore(context.Context, time.Time) (int64, error) { return 0, nil }
+
+func (f *fakeLog) all() []v1alpha1store.WebhookDelivery {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]v1alpha1store.WebhookDelivery(nil), f.deliveries...)
+}
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver answers with the given statuses in order, then 200.
+func newReceiver(t *testing.T, statuses ...int) (*httptest.Server, func() []received) {
+	t.Helper()
+	var (
+		mu   sync.Mutex
+		reqs []received
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		reqs = append(reqs, received{header: r.Header.Clone(), body: body})
+		status := http.StatusOK
+		if len(reqs) <= len(statuses) {
+			status = statuses[len(reqs)-1]
+		}
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []received {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]received(nil), reqs...)
+	}
+}
+
+func webhook(namespace, name, url string, events ...string) v1alpha1.Webhook {
+	return v1alpha1.Webhook{
+		Metadata: v1alpha1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:     v1alpha1.WebhookSpec{URL: url, Events: events},
+	}
+}
+
+func TestDispatcher_PublishDeliversToSubscribers(t *testing.T) {
+	srv, reqs := newReceiver(t)
+	other, otherReqs := newReceiver(t)
+	t.Setenv(v1alpha1.WebhookSecretEnvPrefix+"CI", "s3cret")
+
+	signed := webhook("team-a", "ci", srv.URL, v1alpha1.WebhookEventServerPublished)
+	signed.Spec.SecretEnv = v1alpha1.WebhookSecretEnvPrefix + "CI"
+	lister := fakeLister{hooks: []v1alpha1.Webhook{
+		signed,
+		webhook("team-a", "agents-only", other.URL, v1alpha1.WebhookEventAgentPublished),
+		webhook("team-b", "elsewhere", other.URL),
+	}}
+	log := &fakeLog{}
+	d := webhooks.NewDispatcher(lister, log, srv.Client(), webhooks.Config{})
+
+	d.ResourceTagCreated(context.Background(), v1alpha1.KindMCPServer, "team-a", "weather", "v1")
+	d.ResourceTagCreated(context.Background(), v1alpha1.KindPrompt, "team-a", "ignored", "v1")
+	d.Wait()
+
+	require.Empty(t, otherReqs())
+	got := reqs()
+	require.Len(t, got, 1)
+	require.Equal(t, v1alpha1.WebhookEventServerPublished, got[0].header.Get(pipelines.HeaderEvent))
+	require.Equal(t, pipelines.Sign([]byte("s3cret"), got[0].body), got[0].header.Get(pipelines.HeaderSignature))
+	require.NotEmpty(t, got[0].header.Get(webhooks.HeaderDelivery))
+
+	var ev webhooks.Event
+	require.NoError(t, json.Unmarshal(got[0].body, &ev))
+	require.Equal(t, v1alpha1.KindMCPServer, ev.Kind)
+	require.Equal(t, "weather", ev.Name)
+	require.Equal(t, "v1", ev.Tag)
+	require.Equal(t, "/v0/mcpservers/weather/v1?namespace=team-a", ev.Path)
+
+	logged := log.all()
+	require.Len(t, logged, 1)
+	require.Equal(t, "ci", logged[0].Webhook)
+	require.Equal(t, 1, logged[0].Attempt)
+	require.Equal(t, http.StatusOK, logged[0].StatusCode)
+	require.Empty(t, logged[0].Error)
+}
+
+func TestDispatcher_RetriesAndLogsEveryAttempt(t *testing.T) {
+	srv, reqs := newReceiver(t, http.StatusBadGateway, http.StatusTooManyRequests)
+	log := &fakeLog{}
+	d := webhooks.NewDispatcher(fakeLister{hooks: []v1alpha1.Webhook{webhook("default", "ci", srv.URL)}},
+		log, srv.Client(), webhooks.Config{Backoff: time.Millisecond})
+
+	d.ObjectCreated(context.Background(), v1alpha1.KindDeployment, "default", "weather")
+	d.ObjectCreated(context.Background(), v1alpha1.KindRuntime, "default", "local")
+	d.Wait()
+
+	got := reqs()
+	require.Len(t, got, 3)
+	require.Equal(t, got[0].header.Get(webhooks.HeaderDelivery), got[2].header.Get(webhooks.HeaderDelivery))
+
+	logged := log.all()
+	require.Len(t, logged, 3)
+	for i, want := range []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK} {
+		require.Equal(t, i+1, logged[i].Attempt)
+		require.Equal(t, want, logged[i].StatusCode)
+		require.Equal(t, v1alpha1.WebhookEventDeploymentCreated, logged[i].Event)
+	}
+	require.Empty(t, logged[2].Error)
+}
+
+func TestDispatcher_DoesNotRetryClientErrors(t *testing.T) {
+	srv, reqs := newReceiver(t, http.StatusGone)
+	log := &fakeLog{}
+	d := webhooks.NewDispatcher(fakeLister{hooks: []v1alpha1.Webhook{webhook("default", "ci", srv.URL)}},
+		log, srv.Client(), webhooks.Config{Backoff: time.Millisecond})
+
+	d.DeploymentFailed(context.Background(), "default", "weather", 3, "adapter \"Local\" apply: image pull failed")
+	d.Wait()
+
+	got := reqs()
+	require.Len(t, got, 1)
+	var ev webhooks.Event
+	require.NoError(t, json.Unmarshal(got[0].body, &ev))
+	require.Equal(t, v1alpha1.WebhookEventDeploymentFailed, ev.Event)
+	require.EqualValues(t, 3, ev.Generation)
+	require.Contains(t, ev.Reason, "image pull failed")
+
+	logged := log.all()
+	require.Len(t, logged, 1)
+	require.Equal(t, http.StatusGone, logged[0].StatusCode)
+	require.NotEmpty(t, logged[0].Error)
+}
+
+func TestDispatcher_MissingSecretSkipsDelivery(t *testing.T) {
+	srv, reqs := newReceiver(t)
+	hook := webhook("default", "ci", srv.URL)
+	hook.Spec.SecretEnv = v1alpha1.WebhookSecretEnvPrefix + "UNSET"
+	log := &fakeLog{}
+	d := webhooks.NewDispatcher(fakeLister{hooks: []v1alpha1.Webhook{hook}}, log, srv.Client(), webhooks.Config{})
+
+	d.ResourceTagCreated(context.Background(), v1alpha1.KindSkill, "default", "summarize", "latest")
+	d.Wait()
+
+	require.Empty(t, reqs(), "unsigned payloads must not go out when a secret is configured")
+	logged := log.all()
+	require.Len(t, logged, 1)
+	require.Contains(t, logged[0].Error, "is empty")
+}
+
+type fakeDeadLetters struct {
+	mu   sync.Mutex
+	rows map[string]v1alpha1store.WebhookDeadLetter
+}
+
+func (f *fakeDeadLetters) Put(_ context.Context, d v1alpha1store.WebhookDeadLetter) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.rows == nil {
+		f.rows = map[string]v1alpha1store.WebhookDeadLetter{}
+	}
+	f.rows[d.DeliveryID] = d
+	return nil
+}
+
+func (f *fakeDeadLetters) Get(_ context.Context, namespace, webhook, deliveryID string) (*v1alpha1store.WebhookDeadLetter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, ok := f.rows[deliveryID]
+	if !ok || d.Namespace != namespace || d.Webhook != webhook {
+		return nil, pkgdb.ErrNotFound
+	}
+	return &d, nil
+}
+
+func (f *fakeDeadLetters) ClaimDue(_ context.Context, now time.Time, limit int, lease time.Duration) ([]v1alpha1store.WebhookDeadLetter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []v1alpha1store.WebhookDeadLetter
+	for id, d := range f.rows {
+		if d.State != v1alpha1store.WebhookDeadLetterPending || d.NextAttemptAt.After(now) || len(out) == limit {
+			continue
+		}
+		out = append(out, d)
+		leased := now.Add(lease)
+		d.NextAttemptAt = &leased
+		f.rows[id] = d
+	}
+	return out, nil
+}
+
+func (f *fakeDeadLetters) PruneResolvedBefore(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+// only returns the single dead letter.
+func (f *fakeDeadLetters) only(t *testing.T) v1alpha1store.WebhookDeadLetter {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	require.Len(t, f.rows, 1)
+	for _, d := range f.rows {
+		return d
+	}
+	return v1alpha1store.WebhookDeadLetter{}
+}
+
+func TestDispatcher_ParksAndRetriesFailedDeliveries(t *testing.T) {
+	srv, reqs := newReceiver(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	log := &fakeLog{}
+	dead := &fakeDeadLetters{}
+	d := webhooks.NewDispatcher(fakeLister{hooks: []v1alpha1.Webhook{webhook("default", "ci", srv.URL)}},
+		log, srv.Client(), webhooks.Config{
+			MaxAttempts:  2,
+			Backoff:      time.Millisecond,
+			DeadLetters:  dead,
+			RetryBackoff: time.Nanosecond,
+		})
+
+	d.ObjectCreated(context.Background(), v1alpha1.KindDeployment, "default", "weather")
+	d.Wait()
+
+	parked := dead.only(t)
+	require.Equal(t, v1alpha1store.WebhookDeadLetterPending, parked.State)
+	require.Equal(t, 2, parked.Attempts)
+	require.Equal(t, http.StatusServiceUnavailable, parked.StatusCode)
+	require.NotNil(t, parked.NextAttemptAt)
+	require.Equal(t, log.all()[0].Payload, parked.Payload)
+
+	// The first retry fails again and is rescheduled; the second lands.
+	require.NoError(t, d.RetryDue(context.Background()))
+	retried := dead.only(t)
+	require.Equal(t, v1alpha1store.WebhookDeadLetterPending, retried.State)
+	require.Equal(t, 1, retried.Retries)
+	require.Equal(t, 3, retried.Attempts)
+
+	time.Sleep(time.Millisecond)
+	require.NoError(t, d.RetryDue(context.Background()))
+	delivered := dead.only(t)
+	require.Equal(t, v1alpha1store.WebhookDeadLetterDelivered, delivered.State)
+	require.Nil(t, delivered.NextAttemptAt)
+	require.Equal(t, 4, delivered.Attempts)
+
+	got := reqs()
+	require.Len(t, got, 4)
+	require.Equal(t, got[0].header.Get(webhooks.HeaderDelivery), got[3].header.Get(webhooks.HeaderDelivery))
+	logged := log.all()
+	require.Len(t, logged, 4)
+	require.Equal(t, 4, logged[3].Attempt)
+	require.True(t, logged[3].Succeeded())
+}
+
+func TestDispatcher_ExhaustsAndReplays(t *testing.T) {
+	srv, reqs := newReceiver(t, http.StatusGone)
+	log := &fakeLog{}
+	dead := &fakeDeadLetters{}
+	lister := fakeLister{hooks: []v1alpha1.Webhook{webhook("default", "ci", srv.URL)}}
+	d := webhooks.NewDispatcher(lister, log, srv.Client(), webhooks.Config{DeadLetters: dead})
+
+	d.ResourceTagCreated(context.Background(), v1alpha1.KindAgent, "default", "reporter", "v1")
+	d.Wait()
+
+	// A client error is not retried automatically.
+	parked := dead.only(t)
+	require.Equal(t, v1alpha1store.WebhookDeadLetterExhausted, parked.State)
+	require.Nil(t, parked.NextAttemptAt)
+	require.NoError(t, d.RetryDue(context.Background()))
+	require.Len(t, reqs(), 1)
+
+	_, err := d.Replay(context.Background(), "default", "other", parked.DeliveryID)
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+
+	attempt, err := d.Replay(context.Background(), "default", "ci", parked.DeliveryID)
+	require.NoError(t, err)
+	require.Equal(t, 2, attempt.Attempt)
+	require.Equal(t, http.StatusOK, attempt.StatusCode)
+	require.Equal(t, v1alpha1store.WebhookDeadLetterDelivered, dead.only(t).State)
+	require.Len(t, reqs(), 2)
+}
+
+func TestDispatcher_RetryOfDeletedWebhookExhausts(t *testing.T) {
+	next := time.Now().Add(-time.Minute)
+	dead := &fakeDeadLetters{}
+	require.NoError(t, dead.Put(context.Background(), v1alpha1store.WebhookDeadLetter{
+		DeliveryID: "d1", Namespace: "default", Webhook: "gone", Event: v1alpha1.WebhookEventDeploymentCreated,
+		URL: "http://127.0.0.1:1", Payload: []byte(`{}`), State: v1alpha1store.WebhookDeadLetterPending,
+		Attempts: 5, NextAttemptAt: &next,
+	}))
+	log := &fakeLog{}
+	d := webhooks.NewDispatcher(fakeLister{}, log, http.DefaultClient, webhooks.Config{DeadLetters: dead})
+
+	require.NoError(t, d.RetryDue(context.Background()))
+	got := dead.only(t)
+	require.Equal(t, v1alpha1store.WebhookDeadLetterExhausted, got.State)
+	require.Equal(t, 6, got.Attempts)
+	require.Contains(t, got.Error, "no longer exists")
+	require.Len(t, log.all(), 1)
+}
@@ -0,0 +1,50 @@
+package v0
+
+import "time"
+
+// APIKey is a scoped credential for automation such as CI pipelines. A key
+// acts on behalf of its Owner, but only on the listed Kinds, on names
+// starting with one of Prefixes, and for the listed Actions. Returned by
+// the /v0/apikeys endpoints; the secret is never returned after creation.
+type APIKey struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Owner string `json:"owner"`
+	// Kinds are the artifact kinds the key reaches (Agent, MCPServer,
+	// Skill, Prompt). Empty means all of them.
+	Kinds []string `json:"kinds"`
+	// Prefixes are the name prefixes the key reaches. Empty means every
+	// name.
+	Prefixes []string `json:"prefixes"`
+	// Actions are any of read (get and list), push (apply and delete
+	// artifacts) and deploy (apply and delete Deployments of artifacts in
+	// scope).
+	Actions    []string   `json:"actions"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// APIKeyInput is the body of POST /v0/apikeys.
+type APIKeyInput struct {
+	Name string `json:"name" minLength:"1" maxLength:"255"`
+	// Kinds accepts kind names case-insensitively ("agent", "mcpserver").
+	Kinds    []string `json:"kinds,omitempty"`
+	Prefixes []string `json:"prefixes,omitempty"`
+	Actions  []string `json:"actions" minItems:"1"`
+	// ExpiresAt ends the key's validity. Omitted keys never expire.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// APIKeyCreated is returned by POST /v0/apikeys. Token is shown only
+// once; send it as `Authorization: Bearer <token>`.
+type APIKeyCreated struct {
+	Key   APIKey `json:"key"`
+	Token string `json:"token"`
+}
+
+// APIKeyList is returned by GET /v0/apikeys.
+type APIKeyList struct {
+	Keys []APIKey `json:"keys"`
+}
@@ -0,0 +1,46 @@
+package v0
+
+import "github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+
+// MCPServerToolList is the tools one MCPServer tag declares in spec.tools,
+// in the order the server lists them. Returned by
+// GET /v0/mcpservers/{name}/{tag}/tools.
+type MCPServerToolList struct {
+	Namespace string             `json:"namespace"`
+	Name      string             `json:"name"`
+	Tag       string             `json:"tag"`
+	Tools     []v1alpha1.MCPTool `json:"tools"`
+}
+
+// CapabilityDiff compares the tools two versions of an MCPServer report in
+// spec.tools. Returned by GET /v0/mcpservers/{name}/capability-diff.
+type CapabilityDiff struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	// Breaking is true when a client of From may fail against To: a tool
+	// was removed or renamed, or a tool's input schema changed in a way
+	// existing calls no longer satisfy.
+	Breaking bool         `json:"breaking"`
+	Added    []string     `json:"added"`
+	Removed  []string     `json:"removed"`
+	Renamed  []ToolRename `json:"renamed"`
+	Changed  []ToolChange `json:"changed"`
+}
+
+// ToolRename is a tool that disappeared under one name and reappeared under
+// another with the same input schema or description.
+type ToolRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ToolChange is a tool present in both versions whose input schema differs.
+type ToolChange struct {
+	Name     string `json:"name"`
+	Breaking bool   `json:"breaking"`
+	// Changes describes each difference, e.g. `required property "units"
+	// added`.
+	Changes []string `json:"changes"`
+}
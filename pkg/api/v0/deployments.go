@@ -0,0 +1,232 @@
+package v0
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Deployment event types carried in DeploymentEvent.Type.
+const (
+	// DeploymentEventCondition reports a status condition that appeared or
+	// changed. Adapter progress, rollout status and reconcile errors all
+	// surface as conditions.
+	DeploymentEventCondition = "condition"
+	// DeploymentEventTerminating reports that deletion of the Deployment
+	// started.
+	DeploymentEventTerminating = "terminating"
+	// DeploymentEventDeleted reports that the Deployment is gone. It is the
+	// last event on a stream.
+	DeploymentEventDeleted = "deleted"
+)
+
+// DeploymentEvent is one server-sent event on
+// GET /v0/deployments/{name}/events.
+type DeploymentEvent struct {
+	Type      string `json:"type" enum:"condition,terminating,deleted"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Generation is the Deployment's metadata.generation when the event was
+	// observed.
+	Generation int64 `json:"generation,omitempty"`
+	// Condition, Status, Reason and Message describe the condition of a
+	// "condition" event.
+	Condition string `json:"condition,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+	// Time is the condition's last transition time, or when the server
+	// observed the event.
+	Time time.Time `json:"time"`
+}
+
+// DeploymentManifests is the body of GET /v0/deployments/{name}/manifests:
+// the runtime artifacts the controller last applied for the Deployment,
+// with secret values replaced by "REDACTED".
+type DeploymentManifests struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Generation is the Deployment generation that was applied.
+	Generation int64 `json:"generation"`
+	// Runtime is the runtime type the manifests were applied to, e.g.
+	// "Local" or "Kubernetes".
+	Runtime   string               `json:"runtime"`
+	AppliedAt time.Time            `json:"appliedAt"`
+	Manifests []DeploymentManifest `json:"manifests"`
+}
+
+// DeploymentManifest is one rendered artifact: a compose file, gateway
+// config, Kubernetes resource or Helm release.
+type DeploymentManifest struct {
+	// Name identifies the artifact, e.g. "docker-compose.yaml" or
+	// "Agent/kagent/bot-prod".
+	Name string `json:"name"`
+	// Content is the artifact as YAML.
+	Content string `json:"content"`
+}
+
+// DeploymentDryRun is the body of POST /v0/deployments:dryRun: the runtime
+// artifacts the controller would apply for the submitted Deployment, with
+// secret values replaced by "REDACTED". Nothing is applied or stored.
+type DeploymentDryRun struct {
+	Namespace string               `json:"namespace"`
+	Name      string               `json:"name"`
+	Manifests []DeploymentManifest `json:"manifests"`
+}
+
+// DeploymentLogs is the body of GET /v0/deployments/{name}/logs.
+type DeploymentLogs struct {
+	Lines []DeploymentLogLine `json:"lines"`
+}
+
+// DeploymentLogLine is one log record: an element of DeploymentLogs.Lines,
+// or the data of one server-sent event of GET
+// /v0/deployments/{name}/logs?follow=true.
+type DeploymentLogLine struct {
+	Timestamp string `json:"timestamp,omitempty" doc:"RFC3339 timestamp."`
+	Stream    string `json:"stream,omitempty"     doc:"stdout | stderr | runtime-specific."`
+	Line      string `json:"line"                 doc:"Single log record."`
+}
+
+// DeploymentNotes is the body of GET and PATCH
+// /v0/deployments/{name}/notes: the context operators attached to the
+// Deployment, and every earlier version of it.
+type DeploymentNotes struct {
+	Namespace string           `json:"namespace"`
+	Name      string           `json:"name"`
+	Note      string           `json:"note"`
+	Links     []DeploymentLink `json:"links"`
+	// UpdatedBy and UpdatedAt describe the latest edit; both are empty
+	// when the Deployment has never been annotated.
+	UpdatedBy string     `json:"updatedBy,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	// History lists every revision, newest first; the first is the current
+	// note and links.
+	History []DeploymentNotesRevision `json:"history"`
+}
+
+// DeploymentNotesRevision is one saved edit of a Deployment's notes.
+type DeploymentNotesRevision struct {
+	Revision  int64            `json:"revision"`
+	Note      string           `json:"note"`
+	Links     []DeploymentLink `json:"links"`
+	Author    string           `json:"author,omitempty"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+// DeploymentLink is a titled URL attached to a Deployment, such as its
+// runbook or dashboard.
+type DeploymentLink struct {
+	Title string `json:"title" minLength:"1" maxLength:"255"`
+	URL   string `json:"url" minLength:"1" maxLength:"2048" doc:"http or https URL."`
+}
+
+// DeploymentNotesPatch is the body of PATCH /v0/deployments/{name}/notes.
+// Omitted fields keep their current value; an empty note or links list
+// clears it.
+type DeploymentNotesPatch struct {
+	Note  *string          `json:"note,omitempty" maxLength:"8192"`
+	Links []DeploymentLink `json:"links,omitempty" maxItems:"20"`
+}
+
+// DeploymentShareInput is the body of POST /v0/deployments/{name}/share.
+type DeploymentShareInput struct {
+	// ExpiresIn is how long the link works, as a Go duration ("24h",
+	// "90m"). Omitted means 24h; at most 720h (30 days).
+	ExpiresIn string `json:"expiresIn,omitempty" doc:"How long the link works, e.g. 24h; defaults to 24h, at most 720h."`
+}
+
+// DeploymentShare is a share link of a Deployment: an expiring token that
+// lets anyone holding it read the Deployment's status and logs and chat
+// with it under /v0/shared/{token}. The token is never returned after
+// creation.
+type DeploymentShare struct {
+	ID        string     `json:"id"`
+	Namespace string     `json:"namespace"`
+	Name      string     `json:"name"`
+	CreatedBy string     `json:"createdBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	RevokedBy string     `json:"revokedBy,omitempty"`
+	// UseCount and LastUsedAt record the requests the token authenticated.
+	UseCount   int64      `json:"useCount"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// DeploymentShareCreated is returned by POST /v0/deployments/{name}/share.
+// Token is shown only once; Path is the shared view relative to the
+// registry URL.
+type DeploymentShareCreated struct {
+	Share DeploymentShare `json:"share"`
+	Token string          `json:"token"`
+	Path  string          `json:"path"`
+}
+
+// DeploymentShareList is returned by GET /v0/deployments/{name}/shares.
+type DeploymentShareList struct {
+	Shares []DeploymentShare `json:"shares"`
+}
+
+// SharedDeployment is the body of GET /v0/shared/{token}: the read-only
+// view of a Deployment a share link grants.
+type SharedDeployment struct {
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	TargetKind   string `json:"targetKind"`
+	TargetName   string `json:"targetName"`
+	TargetTag    string `json:"targetTag,omitempty"`
+	DesiredState string `json:"desiredState,omitempty"`
+	// Conditions are the Deployment's status conditions.
+	Conditions []DeploymentCondition `json:"conditions"`
+	// Chat reports whether POST /v0/shared/{token}/chat reaches the
+	// deployed agent.
+	Chat      bool      `json:"chat"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// DeploymentCondition is one status condition of a SharedDeployment.
+type DeploymentCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime,omitzero"`
+}
+
+// MCP traffic frame directions, as carried in MCPFrame.Direction.
+const (
+	MCPFrameRequest  = "request"
+	MCPFrameResponse = "response"
+)
+
+// MCPFrame is one MCP JSON-RPC message relayed through
+// POST /v0/deployments/{name}/mcp, with secret values replaced by
+// "REDACTED".
+type MCPFrame struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"direction" enum:"request,response"`
+	SessionID string    `json:"sessionId,omitempty" doc:"Mcp-Session-Id of the exchange."`
+	Method    string    `json:"method,omitempty" doc:"JSON-RPC method; on responses, the method of the request answered."`
+	ID        string    `json:"id,omitempty" doc:"JSON-RPC id."`
+	// Status is the HTTP status of the response that carried the frame.
+	Status int `json:"status,omitempty"`
+	// Error is the JSON-RPC error message, or why the relay failed.
+	Error string `json:"error,omitempty"`
+	// Body is the redacted message. It is left out, and Truncated set,
+	// when the message is too large to keep.
+	Body      json.RawMessage `json:"body,omitempty"`
+	Truncated bool            `json:"truncated,omitempty"`
+}
+
+// DeploymentTraffic is the body of GET /v0/deployments/{name}/traffic: the
+// MCP frames the registry recorded for the Deployment, oldest first.
+type DeploymentTraffic struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Recording reports whether the Deployment is in traffic debug mode.
+	Recording bool `json:"recording"`
+	// Capacity is how many frames the registry keeps per Deployment; older
+	// ones are dropped.
+	Capacity int        `json:"capacity"`
+	Frames   []MCPFrame `json:"frames"`
+}
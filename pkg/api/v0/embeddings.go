@@ -0,0 +1,51 @@
+package v0
+
+// Embeddings reindex event types carried in EmbeddingsReindexEvent.Type.
+const (
+	// EmbeddingsReindexPlanned reports how many artifacts the reindex
+	// will embed. It is the first event on a stream.
+	EmbeddingsReindexPlanned = "planned"
+	// EmbeddingsReindexItem reports one artifact embedded, or that would
+	// be on a dry run, or that failed.
+	EmbeddingsReindexItem = "item"
+	// EmbeddingsReindexDone carries the totals. It is the last event on a
+	// stream that ran to completion.
+	EmbeddingsReindexDone = "done"
+)
+
+// Reasons an artifact is (re)embedded, carried in
+// EmbeddingsReindexEvent.Reason.
+const (
+	EmbeddingsReasonMissing = "missing"
+	EmbeddingsReasonStale   = "stale"
+	EmbeddingsReasonForced  = "forced"
+)
+
+// EmbeddingsReindexEvent is one server-sent event on
+// POST /v0/admin/embeddings:reindex.
+type EmbeddingsReindexEvent struct {
+	Type string `json:"type" enum:"planned,item,done"`
+	// Kind, Namespace and Name identify the artifact of an "item" event,
+	// at its latest tag.
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// Reason is why the artifact is embedded: "missing", "stale" or
+	// "forced".
+	Reason string `json:"reason,omitempty"`
+	// Error is set on an "item" event whose embedding failed. The
+	// reindex carries on with the next artifact.
+	Error string `json:"error,omitempty"`
+	// Processed counts the artifacts handled so far and Total those the
+	// reindex will embed.
+	Processed int `json:"processed"`
+	Total     int `json:"total"`
+	// Scanned, UpToDate and Failed are totals, set on "planned" and
+	// "done" events: the artifacts looked at, those whose embedding
+	// matched their content, and those whose embedding failed.
+	Scanned  int `json:"scanned,omitempty"`
+	UpToDate int `json:"upToDate,omitempty"`
+	Failed   int `json:"failed,omitempty"`
+	// DryRun is true when nothing was embedded.
+	DryRun bool `json:"dryRun,omitempty"`
+}
@@ -0,0 +1,11 @@
+package v0
+
+// FlagEvaluation is every FeatureFlag in a namespace resolved for one agent.
+// Returned by GET /v0/flags:evaluate.
+type FlagEvaluation struct {
+	Namespace string `json:"namespace"`
+	Agent     string `json:"agent"`
+	// Flags maps flag name to its value for Agent: the per-agent override
+	// when the flag has one, otherwise the flag's default.
+	Flags map[string]bool `json:"flags"`
+}
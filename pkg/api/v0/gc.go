@@ -0,0 +1,36 @@
+package v0
+
+import "time"
+
+// GCReport is the result of POST /v0/admin/gc: the artifact versions the
+// retention policy deleted, or would delete on a dry run.
+type GCReport struct {
+	// DryRun is true when nothing was deleted.
+	DryRun bool `json:"dryRun,omitempty"`
+	// KeepVersions and KeepWithin are the retention policy applied: the
+	// newest KeepVersions versions of each artifact, and every version
+	// younger than KeepWithin, are kept.
+	KeepVersions int    `json:"keepVersions"`
+	KeepWithin   string `json:"keepWithin"`
+	// Scanned counts the versions looked at, Kept those the policy keeps,
+	// InUse those kept only because a Deployment or another artifact
+	// pins them, and Failed the deletions that failed.
+	Scanned int `json:"scanned"`
+	Kept    int `json:"kept"`
+	InUse   int `json:"inUse"`
+	Failed  int `json:"failed,omitempty"`
+	// Deleted lists the versions deleted, oldest first per artifact.
+	Deleted []GCVersion `json:"deleted"`
+}
+
+// GCVersion is one artifact version collected by GC.
+type GCVersion struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Tag       string    `json:"tag"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Error is set when deleting the version failed. GC carries on with
+	// the next one.
+	Error string `json:"error,omitempty"`
+}
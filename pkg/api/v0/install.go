@@ -0,0 +1,41 @@
+package v0
+
+// MCPServerInstall is the configuration an MCP client needs to use one
+// MCPServer version. Returned by GET /v0/mcpservers/{name}/install.
+type MCPServerInstall struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag"`
+	// Client is the client the configuration is for: claude (Claude
+	// Desktop), cursor or vscode.
+	Client string `json:"client"`
+	// Key is the top-level field of the client's config file that maps
+	// server keys to entries: mcpServers for Claude Desktop and Cursor,
+	// servers for VS Code.
+	Key string `json:"key"`
+	// ServerKey is the key the entry is suggested under: the last segment
+	// of the server name.
+	ServerKey string `json:"serverKey"`
+	// Entry is the server's entry in the client's config file.
+	Entry MCPClientServer `json:"entry"`
+	// Config is a config file holding only this server, ready to paste:
+	// {Key: {ServerKey: Entry}}.
+	Config map[string]map[string]MCPClientServer `json:"config"`
+	// Missing names the environment variables and headers left empty in
+	// Entry because the server declares no usable value for them. The
+	// user fills them in before starting the client.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// MCPClientServer is one server entry in an MCP client's config file.
+// Local servers set Command; remote servers set URL.
+type MCPClientServer struct {
+	// Type is the VS Code transport: stdio, http or sse. Other clients
+	// leave it empty.
+	Type    string            `json:"type,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
@@ -0,0 +1,39 @@
+package v0
+
+import "time"
+
+// Namespace is a claim on a namespace (e.g. `io.github.acme`). Once
+// verified, only Owner and Members may publish or delete Agents,
+// MCPServers and Skills in it. Returned by the /v0/namespaces endpoints.
+type Namespace struct {
+	Name       string     `json:"name"`
+	Owner      string     `json:"owner"`
+	Members    []string   `json:"members"`
+	Verified   bool       `json:"verified"`
+	VerifiedAt *time.Time `json:"verifiedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	// Verification is the DNS TXT record that verifies the claim. Shown
+	// only to the owner and registry admins, and only for namespaces with a
+	// reverse-DNS name.
+	Verification *NamespaceVerification `json:"verification,omitempty"`
+}
+
+// NamespaceVerification is the DNS TXT record that proves ownership of a
+// claimed namespace: publish a TXT record named Record with value Value,
+// then call POST /v0/namespaces/{name}/verify.
+type NamespaceVerification struct {
+	Record string `json:"record"`
+	Value  string `json:"value"`
+}
+
+// NamespaceList is returned by GET /v0/namespaces.
+type NamespaceList struct {
+	Namespaces []Namespace `json:"namespaces"`
+}
+
+// NamespaceMembers is the body of PUT /v0/namespaces/{name}/members.
+type NamespaceMembers struct {
+	// Members replaces the namespace's member list. The owner is always a
+	// member and need not be listed.
+	Members []string `json:"members"`
+}
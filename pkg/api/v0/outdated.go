@@ -0,0 +1,71 @@
+package v0
+
+// OutdatedReport lists Deployments whose pinned artifacts have newer
+// versions or are deprecated, for planning upgrades. Returned by
+// GET /v0/deployments/outdated.
+type OutdatedReport struct {
+	Deployments []OutdatedDeployment `json:"deployments"`
+	Summary     OutdatedSummary      `json:"summary"`
+}
+
+// OutdatedDeployment is one Deployment with at least one outdated or
+// deprecated pinned artifact.
+type OutdatedDeployment struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Target is the "Kind/name@tag" the Deployment points at.
+	Target string `json:"target"`
+	// Severity is the highest severity across Artifacts.
+	Severity  string             `json:"severity"`
+	Artifacts []OutdatedArtifact `json:"artifacts"`
+}
+
+// OutdatedArtifact is one pinned reference that lags behind its newest
+// version or points at a deprecated version.
+type OutdatedArtifact struct {
+	// Field locates the reference: "spec.targetRef" on the Deployment, or
+	// the Agent field (e.g. "spec.skills[0]") for agent dependencies.
+	Field     string `json:"field"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Pinned    string `json:"pinned"`
+	// Latest is the newest available version; empty when the pinned version
+	// is already the newest and only deprecated.
+	Latest string `json:"latest,omitempty"`
+	// Newer lists every version newer than Pinned, newest first.
+	Newer []string `json:"newer,omitempty"`
+	// Deprecated is the deprecation notice on the pinned version, if any.
+	Deprecated string `json:"deprecated,omitempty"`
+	// Changelog carries the changelog annotation of each newer version that
+	// has one, newest first.
+	Changelog []OutdatedChange `json:"changelog,omitempty"`
+	Severity  string           `json:"severity"`
+	// Reason explains the severity in one line.
+	Reason string `json:"reason"`
+}
+
+// OutdatedChange is the changelog entry published with one version.
+type OutdatedChange struct {
+	Tag  string `json:"tag"`
+	Note string `json:"note"`
+}
+
+// OutdatedSeverity* rank how urgently a Deployment should be upgraded.
+//   - high: the pinned version is deprecated, a newer major version exists,
+//     or a newer version's changelog flags a BREAKING change.
+//   - medium: a newer minor version exists.
+//   - low: only newer patch versions exist, or the tags are not semver and
+//     newer tags were published since.
+const (
+	OutdatedSeverityHigh   = "high"
+	OutdatedSeverityMedium = "medium"
+	OutdatedSeverityLow    = "low"
+)
+
+// OutdatedSummary counts reported Deployments by severity.
+type OutdatedSummary struct {
+	High   int `json:"high"`
+	Medium int `json:"medium"`
+	Low    int `json:"low"`
+}
@@ -0,0 +1,55 @@
+package v0
+
+import "time"
+
+// PromptEvalRun is one run of a Prompt tag's spec.evaluation, returned
+// by GET /v0/prompts/{name}/evaluations and
+// POST /v0/prompts/{name}/{tag}/evaluations.
+type PromptEvalRun struct {
+	ID        int64  `json:"id"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag"`
+	// Model is the model the prompt ran against.
+	Model string `json:"model"`
+	// Trigger is "publish" for runs started by publishing the tag and
+	// "manual" for runs asked for through the API.
+	Trigger     string    `json:"trigger" enum:"publish,manual"`
+	RequestedBy string    `json:"requestedBy,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	// Score is the mean case score from 0 to 100. It is absent when the
+	// run failed with Error.
+	Score *float64 `json:"score,omitempty"`
+	Error string   `json:"error,omitempty"`
+	// ChangeFrom is the tag this run is compared with: the newest earlier
+	// scored run of another tag. Change is Score minus that run's score,
+	// so a negative Change is a regression.
+	ChangeFrom string             `json:"changeFrom,omitempty"`
+	Change     *float64           `json:"change,omitempty"`
+	Cases      []PromptCaseResult `json:"cases"`
+}
+
+// PromptCaseResult is how one evaluation case scored, from 0 to 1.
+type PromptCaseResult struct {
+	Name   string              `json:"name"`
+	Score  float64             `json:"score"`
+	Answer string              `json:"answer,omitempty"`
+	Checks []PromptCheckResult `json:"checks,omitempty"`
+	// Error is set when the model could not be reached for the case; the
+	// case then scores 0.
+	Error string `json:"error,omitempty"`
+}
+
+// PromptCheckResult is one assertion or rubric of a case. An assertion
+// scores 0 or 1 and a rubric its grade over 10.
+type PromptCheckResult struct {
+	Check  string  `json:"check"`
+	Score  float64 `json:"score"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// PromptEvalRunList is returned by GET /v0/prompts/{name}/evaluations,
+// newest first.
+type PromptEvalRunList struct {
+	Evaluations []PromptEvalRun `json:"evaluations"`
+}
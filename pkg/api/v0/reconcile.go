@@ -0,0 +1,54 @@
+package v0
+
+// ReconcilePlan is the dry-run output of the Deployment controller: what a
+// full reconcile would do per Runtime without calling any runtime adapter.
+// Returned by POST /v0/admin/reconcile:plan.
+type ReconcilePlan struct {
+	Runtimes []RuntimeReconcilePlan `json:"runtimes"`
+	Summary  ReconcilePlanSummary   `json:"summary"`
+}
+
+// RuntimeReconcilePlan groups the planned Deployment actions that target one
+// Runtime (the provider the adapter would talk to).
+type RuntimeReconcilePlan struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Type is the Runtime's Spec.Type (e.g. Local, Kubernetes). Empty when
+	// the Runtime could not be resolved.
+	Type        string                `json:"type,omitempty"`
+	Deployments []DeploymentPlanEntry `json:"deployments"`
+}
+
+// DeploymentPlanEntry is the planned action for a single Deployment.
+type DeploymentPlanEntry struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Target is the "Kind/namespace/name" the Deployment points at.
+	Target string `json:"target,omitempty"`
+	// Action is one of: create, update, delete, noop, blocked, unmanaged,
+	// error.
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReconcilePlanAction* are the well-known Action values on DeploymentPlanEntry.
+const (
+	ReconcilePlanActionCreate    = "create"
+	ReconcilePlanActionUpdate    = "update"
+	ReconcilePlanActionDelete    = "delete"
+	ReconcilePlanActionNoop      = "noop"
+	ReconcilePlanActionBlocked   = "blocked"
+	ReconcilePlanActionUnmanaged = "unmanaged"
+	ReconcilePlanActionError     = "error"
+)
+
+// ReconcilePlanSummary counts planned actions across every Runtime.
+type ReconcilePlanSummary struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Delete    int `json:"delete"`
+	Noop      int `json:"noop"`
+	Blocked   int `json:"blocked"`
+	Unmanaged int `json:"unmanaged"`
+	Error     int `json:"error"`
+}
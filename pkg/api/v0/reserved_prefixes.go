@@ -0,0 +1,60 @@
+package v0
+
+import "time"
+
+// ReservedPrefix is a name prefix (e.g. `official/`) that only its Owners
+// may publish Agents, MCPServers, Skills and Prompts under. A prefix
+// matches names and namespaces that start with it, and the namespace equal
+// to it without its trailing slash. Returned by the /v0/reserved-prefixes
+// endpoints.
+type ReservedPrefix struct {
+	Prefix string   `json:"prefix"`
+	Reason string   `json:"reason,omitempty"`
+	Owners []string `json:"owners"`
+	// Source is "config" when the server configuration reserves the prefix
+	// (it cannot be removed through the API) and "admin" when a registry
+	// admin does.
+	Source string `json:"source" enum:"config,admin"`
+}
+
+// ReservedPrefixList is returned by GET /v0/reserved-prefixes.
+type ReservedPrefixList struct {
+	Prefixes []ReservedPrefix `json:"prefixes"`
+}
+
+// ReservedPrefixInput is the body of PUT /v0/reserved-prefixes.
+type ReservedPrefixInput struct {
+	// Prefix is the prefix to reserve. A trailing `*` is ignored and the
+	// prefix is stored lower-cased.
+	Prefix string `json:"prefix" minLength:"1"`
+	Reason string `json:"reason,omitempty"`
+	// Owners replaces the subjects allocated the prefix.
+	Owners []string `json:"owners,omitempty"`
+}
+
+// ReservedPrefixRequest is one caller's request to be allocated a reserved
+// prefix. Returned by the /v0/reserved-prefix-requests endpoints.
+type ReservedPrefixRequest struct {
+	ID            int64      `json:"id"`
+	Prefix        string     `json:"prefix"`
+	Requester     string     `json:"requester"`
+	Justification string     `json:"justification,omitempty"`
+	Status        string     `json:"status" enum:"pending,approved,rejected"`
+	DecidedBy     string     `json:"decidedBy,omitempty"`
+	DecidedAt     *time.Time `json:"decidedAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// ReservedPrefixRequestList is returned by GET
+// /v0/reserved-prefix-requests.
+type ReservedPrefixRequestList struct {
+	Requests []ReservedPrefixRequest `json:"requests"`
+}
+
+// ReservedPrefixClaim is the body of POST /v0/reserved-prefix-requests.
+type ReservedPrefixClaim struct {
+	Prefix string `json:"prefix" minLength:"1"`
+	// Justification tells the reviewing admin why the caller should own
+	// the prefix, e.g. the trademark or organization it belongs to.
+	Justification string `json:"justification,omitempty"`
+}
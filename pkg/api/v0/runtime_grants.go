@@ -0,0 +1,30 @@
+package v0
+
+import "time"
+
+// RuntimeGrant restricts who may deploy to a Runtime: only its Deployers
+// (and registry admins) may create, undeploy or delete Deployments whose
+// runtimeRef names it. A Runtime without a grant is unrestricted. Returned
+// by the /v0/runtime-grants endpoints.
+type RuntimeGrant struct {
+	Namespace string `json:"namespace"`
+	Runtime   string `json:"runtime"`
+	// Deployers are the subjects allowed to deploy to the Runtime. Empty
+	// leaves the Runtime to registry admins.
+	Deployers []string  `json:"deployers"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// RuntimeGrantList is returned by GET /v0/runtime-grants.
+type RuntimeGrantList struct {
+	Grants []RuntimeGrant `json:"grants"`
+}
+
+// RuntimeGrantInput is the body of PUT /v0/runtime-grants.
+type RuntimeGrantInput struct {
+	// Namespace of the Runtime; empty means the default namespace.
+	Namespace string `json:"namespace,omitempty"`
+	Runtime   string `json:"runtime" minLength:"1"`
+	// Deployers replaces the subjects allowed to deploy to the Runtime.
+	Deployers []string `json:"deployers"`
+}
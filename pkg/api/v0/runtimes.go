@@ -0,0 +1,15 @@
+package v0
+
+// RuntimeSecretInput is the body of PUT /v0/runtimes/{name}/secrets/{key}.
+type RuntimeSecretInput struct {
+	Value string `json:"value" minLength:"1" maxLength:"65536" doc:"Plaintext value. It is encrypted before it is stored and never returned."`
+}
+
+// RuntimeSecret reports a secret stored in a Runtime's spec.config.
+type RuntimeSecret struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+	// Generation is the Runtime's generation after the write.
+	Generation int64 `json:"generation"`
+}
@@ -0,0 +1,33 @@
+package v0
+
+// SearchResults is the ranked answer to GET /v0/search.
+type SearchResults struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+// SearchResult is one artifact matched by a search, at its latest tag.
+type SearchResult struct {
+	// Type is the search type the artifact belongs to: "server", "agent",
+	// "skill" or "prompt".
+	Type        string `json:"type"`
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Tag         string `json:"tag"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Highlight is an excerpt of the description or README with the
+	// matched terms wrapped in <mark></mark>. Empty when only the name or
+	// the semantic ranking matched.
+	Highlight string `json:"highlight,omitempty"`
+	// ReadmeMatch is true when the artifact's README alone matches the
+	// query, e.g. a usage example mentioning the searched words.
+	ReadmeMatch bool `json:"readmeMatch,omitempty"`
+	// Tools names the tools of an MCP server whose name or description
+	// matched the query, best match first.
+	Tools []string `json:"tools,omitempty"`
+	// Score is the reciprocal rank fusion score the results are ordered
+	// by. It is only meaningful relative to the other results.
+	Score float64 `json:"score"`
+}
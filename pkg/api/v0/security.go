@@ -0,0 +1,41 @@
+package v0
+
+// VulnerabilityImpact lists the Agents and active Deployments that reach
+// one MCPServer or Skill version. Returned by
+// GET /v0/security/impact/{name}@{tag} and by the mark/clear endpoints under
+// /v0/security/vulnerabilities.
+type VulnerabilityImpact struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag"`
+	// Advisory is the vulnerability notice on the version; empty when the
+	// version is not marked vulnerable.
+	Advisory    string               `json:"advisory,omitempty"`
+	Agents      []ImpactedAgent      `json:"agents"`
+	Deployments []ImpactedDeployment `json:"deployments"`
+}
+
+// ImpactedAgent is one Agent version that references the artifact.
+type ImpactedAgent struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag"`
+	// Field locates the reference on the Agent, e.g. "spec.skills[0]".
+	Field string `json:"field"`
+}
+
+// ImpactedDeployment is one Deployment that runs the artifact, directly
+// or through an impacted Agent. Undeployed and terminating Deployments are
+// not reported.
+type ImpactedDeployment struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Target is the "Kind/name@tag" the Deployment points at.
+	Target string `json:"target"`
+}
+
+// VulnerabilityMark is the request body for marking a version vulnerable.
+type VulnerabilityMark struct {
+	Advisory string `json:"advisory" minLength:"1" doc:"Vulnerability notice, e.g. 'CVE-2026-1234: path traversal in file tool'."`
+}
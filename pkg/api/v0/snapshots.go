@@ -0,0 +1,43 @@
+package v0
+
+import "time"
+
+// CatalogSnapshot is a named, immutable copy of the catalog's tagged
+// artifacts at one point in time. Deployments with spec.snapshot resolve
+// their target and its refs from it. Returned by the /v0/snapshots
+// endpoints.
+type CatalogSnapshot struct {
+	Name string `json:"name"`
+	// Namespace is the namespace captured; empty means every namespace.
+	Namespace   string    `json:"namespace,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   string    `json:"createdBy,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	// EntryCount is the number of artifact tags captured.
+	EntryCount int `json:"entryCount"`
+	// Entries lists the captured artifact tags. Only GET
+	// /v0/snapshots/{name} fills it in.
+	Entries []CatalogSnapshotEntry `json:"entries,omitempty"`
+}
+
+// CatalogSnapshotEntry is one artifact tag a snapshot holds.
+type CatalogSnapshotEntry struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag"`
+}
+
+// CatalogSnapshotList is returned by GET /v0/snapshots.
+type CatalogSnapshotList struct {
+	Snapshots []CatalogSnapshot `json:"snapshots"`
+}
+
+// CatalogSnapshotInput is the body of POST /v0/snapshots.
+type CatalogSnapshotInput struct {
+	Name string `json:"name" minLength:"1"`
+	// Namespace limits the snapshot to one namespace; empty captures every
+	// namespace.
+	Namespace   string `json:"namespace,omitempty"`
+	Description string `json:"description,omitempty"`
+}
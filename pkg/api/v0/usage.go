@@ -0,0 +1,45 @@
+package v0
+
+import "time"
+
+// UsageTopReport ranks request volume by namespace and by caller over a
+// trailing window. Returned by GET /v0/admin/usage/top.
+type UsageTopReport struct {
+	// Window is the span actually covered, which is shorter than requested
+	// when the server has not been up that long.
+	Window     string       `json:"window"`
+	Since      time.Time    `json:"since"`
+	Namespaces []UsageStats `json:"namespaces"`
+	Callers    []UsageStats `json:"callers"`
+}
+
+// UsageStats aggregates the requests attributed to one namespace or caller.
+// Callers are keyed by auth subject when the provider names one, otherwise by
+// client address.
+type UsageStats struct {
+	Key       string  `json:"key"`
+	Requests  int64   `json:"requests"`
+	Errors    int64   `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+}
+
+// ArtifactUsage counts how an Agent, MCPServer or Skill is used, across all
+// of its tags: GETs of the artifact, applied Deployments of it, and MCP
+// registry searches that returned it.
+type ArtifactUsage struct {
+	Downloads  int64 `json:"downloads"`
+	Deploys    int64 `json:"deploys"`
+	SearchHits int64 `json:"searchHits"`
+}
+
+// ArtifactStats is the usage of one artifact. Returned by
+// GET /v0/{plural}/{name}/stats.
+type ArtifactStats struct {
+	Kind      string        `json:"kind"`
+	Namespace string        `json:"namespace"`
+	Name      string        `json:"name"`
+	Usage     ArtifactUsage `json:"usage"`
+	// UpdatedAt is when the counters last changed; omitted for an artifact
+	// with no recorded use.
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
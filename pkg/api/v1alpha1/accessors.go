@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 )
 
 func (tm *TypeMeta) GetAPIVersion() string { return tm.APIVersion }
@@ -17,9 +18,9 @@ func (m *ObjectMeta) SetMetadata(meta ObjectMeta) {
 }
 
 // Object is the minimal interface satisfied by every typed v1alpha1 envelope
-// (Agent, MCPServer, Skill, Prompt, Runtime, Deployment; extension kinds
-// opt in too). It lets generic code operate on any resource without
-// reflection.
+// (Agent, MCPServer, Skill, Prompt, Chart, Runtime, Deployment,
+// DeploymentTemplate, Webhook, FeatureFlag; extension kinds opt in too). It lets generic code operate on
+// any resource without reflection.
 //
 // Status is intentionally exchanged as json.RawMessage on this interface.
 // The envelope itself stays agnostic to per-kind status schemas:
@@ -63,12 +64,24 @@ type RegistryValidatable interface {
 	ValidateRegistries(ctx context.Context, v RegistryValidatorFunc) error
 }
 
-// ValidateObject runs structural validation when obj opts into it.
+// ValidateObject runs structural validation when obj opts into it, plus
+// the MaxSpecBytes check every kind is subject to.
 func ValidateObject(obj Object) error {
+	var errs FieldErrors
 	if v, ok := any(obj).(StructuralValidator); ok {
-		return v.Validate()
+		if err := v.Validate(); err != nil {
+			var fe FieldErrors
+			if !errors.As(err, &fe) {
+				return err
+			}
+			errs = append(errs, fe...)
+		}
 	}
-	return nil
+	errs = append(errs, validateSpecSize(obj)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
 }
 
 // ResolveObjectRefs validates cross-resource refs when obj carries them.
@@ -249,6 +262,49 @@ func (p *Prompt) UnmarshalStatus(data json.RawMessage) error {
 	return UnmarshalStatusFromStorage(data, &p.Status)
 }
 
+func (c *Chart) GetMetadata() *ObjectMeta { return &c.Metadata }
+func (c *Chart) SetMetadata(meta ObjectMeta) {
+	c.Metadata = meta
+}
+func (c *Chart) MarshalSpec() (json.RawMessage, error) { return json.Marshal(c.Spec) }
+func (c *Chart) UnmarshalSpec(data json.RawMessage) error {
+	return json.Unmarshal(data, &c.Spec)
+}
+func (c *Chart) MarshalStatus() (json.RawMessage, error) { return MarshalStatusForStorage(c.Status) }
+func (c *Chart) UnmarshalStatus(data json.RawMessage) error {
+	return UnmarshalStatusFromStorage(data, &c.Status)
+}
+
+func (f *FeatureFlag) GetMetadata() *ObjectMeta { return &f.Metadata }
+func (f *FeatureFlag) SetMetadata(meta ObjectMeta) {
+	f.Metadata = meta
+}
+func (f *FeatureFlag) MarshalSpec() (json.RawMessage, error) { return json.Marshal(f.Spec) }
+func (f *FeatureFlag) UnmarshalSpec(data json.RawMessage) error {
+	return json.Unmarshal(data, &f.Spec)
+}
+func (f *FeatureFlag) MarshalStatus() (json.RawMessage, error) {
+	return MarshalStatusForStorage(f.Status)
+}
+func (f *FeatureFlag) UnmarshalStatus(data json.RawMessage) error {
+	return UnmarshalStatusFromStorage(data, &f.Status)
+}
+
+func (t *DeploymentTemplate) GetMetadata() *ObjectMeta { return &t.Metadata }
+func (t *DeploymentTemplate) SetMetadata(meta ObjectMeta) {
+	t.Metadata = meta
+}
+func (t *DeploymentTemplate) MarshalSpec() (json.RawMessage, error) { return json.Marshal(t.Spec) }
+func (t *DeploymentTemplate) UnmarshalSpec(data json.RawMessage) error {
+	return json.Unmarshal(data, &t.Spec)
+}
+func (t *DeploymentTemplate) MarshalStatus() (json.RawMessage, error) {
+	return MarshalStatusForStorage(t.Status)
+}
+func (t *DeploymentTemplate) UnmarshalStatus(data json.RawMessage) error {
+	return UnmarshalStatusFromStorage(data, &t.Status)
+}
+
 func (r *Runtime) GetMetadata() *ObjectMeta { return &r.Metadata }
 func (r *Runtime) SetMetadata(meta ObjectMeta) {
 	r.Metadata = meta
@@ -278,3 +334,18 @@ func (d *Deployment) MarshalStatus() (json.RawMessage, error) {
 func (d *Deployment) UnmarshalStatus(data json.RawMessage) error {
 	return UnmarshalStatusFromStorage(data, &d.Status)
 }
+
+func (w *Webhook) GetMetadata() *ObjectMeta { return &w.Metadata }
+func (w *Webhook) SetMetadata(meta ObjectMeta) {
+	w.Metadata = meta
+}
+func (w *Webhook) MarshalSpec() (json.RawMessage, error) { return json.Marshal(w.Spec) }
+func (w *Webhook) UnmarshalSpec(data json.RawMessage) error {
+	return json.Unmarshal(data, &w.Spec)
+}
+func (w *Webhook) MarshalStatus() (json.RawMessage, error) {
+	return MarshalStatusForStorage(w.Status)
+}
+func (w *Webhook) UnmarshalStatus(data json.RawMessage) error {
+	return UnmarshalStatusFromStorage(data, &w.Status)
+}
@@ -12,6 +12,19 @@ func init() {
 	MustRegisterKind[*Agent, AgentSpec](KindAgent)
 }
 
+// AgentEvalAnnotation records the outcome of the last `arctl agent eval
+// --publish` run against an Agent version, as a JSON summary of the suite
+// and its passed and failed cases.
+const AgentEvalAnnotation = "agentregistry.solo.io/eval-results"
+
+// AgentFrameworkLabel and AgentLanguageLabel name the framework an Agent
+// is built with, e.g. adk and python. `arctl apply` copies them from the
+// project's arctl.yaml; runtimes pick the agent's image layout by them.
+const (
+	AgentFrameworkLabel = "arctl.dev/framework"
+	AgentLanguageLabel  = "arctl.dev/language"
+)
+
 // AgentSpec is the agent resource's declarative body.
 //
 // References to other resources (MCP servers) are pure ResourceRefs — no
@@ -24,6 +37,10 @@ type AgentSpec struct {
 	ModelProvider string `json:"modelProvider,omitempty" yaml:"modelProvider,omitempty"`
 	ModelName     string `json:"modelName,omitempty" yaml:"modelName,omitempty"`
 
+	// Icon is the URL of the logo catalogs show for the agent. An icon
+	// uploaded through PUT /v0/agents/{name}/icon takes precedence.
+	Icon string `json:"icon,omitempty" yaml:"icon,omitempty" maxLength:"2048"`
+
 	// Source declares where the agent comes from — Image (the runtime
 	// container) and/or Repository (the source code).
 	Source *AgentSource `json:"source,omitempty" yaml:"source,omitempty"`
@@ -41,10 +58,58 @@ type AgentSpec struct {
 	// and remain available to any other runtime that supports MCP. Each ref's
 	// Kind defaults to the field's resource kind; empty Tag means "resolve
 	// latest at reference time".
-	Plugins      []ResourceRef `json:"plugins,omitempty" yaml:"plugins,omitempty"`
-	Skills       []ResourceRef `json:"skills,omitempty" yaml:"skills,omitempty"`
+	Plugins      []ResourceRef `json:"plugins,omitempty" yaml:"plugins,omitempty" maxItems:"100"`
+	Skills       []ResourceRef `json:"skills,omitempty" yaml:"skills,omitempty" maxItems:"100"`
 	Instructions *ResourceRef  `json:"instructions,omitempty" yaml:"instructions,omitempty"`
-	MCPServers   []ResourceRef `json:"mcpServers,omitempty" yaml:"mcpServers,omitempty"`
+	MCPServers   []ResourceRef `json:"mcpServers,omitempty" yaml:"mcpServers,omitempty" maxItems:"100"`
+
+	// SubAgents are the Agents this agent delegates to over A2A. Deploying
+	// the agent deploys each sub-agent alongside it, on the same Runtime, as
+	// a Deployment of its own, and hands the agent their A2A URLs through
+	// the A2A_SUB_AGENTS_CONFIG env var.
+	SubAgents []ResourceRef `json:"subAgents,omitempty" yaml:"subAgents,omitempty" maxItems:"100"`
+
+	// Charts are supporting-infrastructure dependencies (vector databases,
+	// gateways). Kubernetes runtimes install each as a Helm release ahead of
+	// the agent; other runtimes ignore them.
+	Charts []ResourceRef `json:"charts,omitempty" yaml:"charts,omitempty" maxItems:"100"`
+
+	// Secrets declares the sensitive env values the agent needs at runtime
+	// (e.g. SLACK_BOT_TOKEN). Deployments supply them through Spec.Env,
+	// either inline or as a SecretRefPrefix reference the runtime resolves.
+	Secrets []AgentSecret `json:"secrets,omitempty" yaml:"secrets,omitempty" maxItems:"100"`
+
+	// Resources requests the CPU, memory and hardware the agent needs to
+	// run, e.g. a GPU for an agent serving a local model.
+	Resources *AgentResources `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// AgentResources requests CPU, memory, accelerators and host devices for
+// an agent.
+type AgentResources struct {
+	// Requests and Limits are the agent's CPU and memory requirements. A
+	// Deployment's spec.resources overrides them value by value.
+	Requests *ComputeResources `json:"requests,omitempty" yaml:"requests,omitempty"`
+	Limits   *ComputeResources `json:"limits,omitempty" yaml:"limits,omitempty"`
+	// GPUs is the number of NVIDIA GPUs to reserve. Local runtimes reserve
+	// them through the NVIDIA container toolkit; Kubernetes runtimes request
+	// them as the nvidia.com/gpu extended resource.
+	GPUs int `json:"gpus,omitempty" yaml:"gpus,omitempty" minimum:"0" maximum:"16"`
+	// Devices maps host devices into the container, in docker's
+	// HOST[:CONTAINER[:PERMISSIONS]] form (e.g. /dev/dri:/dev/dri:rw).
+	// Only Local runtimes honor them; Kubernetes exposes devices through
+	// device plugins, so request those as GPUs instead.
+	Devices []string `json:"devices,omitempty" yaml:"devices,omitempty" maxItems:"16"`
+}
+
+// AgentSecret is one declared secret. Deploying an agent fails while a
+// Required secret has no value in the Deployment's Spec.Env; arctl prompts
+// for missing ones.
+type AgentSecret struct {
+	// Name is the env var the agent reads the secret from.
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool   `json:"required,omitempty" yaml:"required,omitempty"`
 }
 
 // AgentSource is the distribution origin of a bring-your-own container/source
@@ -55,6 +120,10 @@ type AgentSource struct {
 	// Format: <registry>/<name>:<tag> (e.g. ghcr.io/owner/agent:1.0.0).
 	Image string `json:"image,omitempty" yaml:"image,omitempty"`
 
+	// Platforms lists the os/arch[/variant] pairs Image is published for,
+	// e.g. linux/amd64 and linux/arm64. Empty means any platform.
+	Platforms []string `json:"platforms,omitempty" yaml:"platforms,omitempty" maxItems:"20"`
+
 	// Repository links to the source code the image was built from.
 	Repository *Repository `json:"repository,omitempty" yaml:"repository,omitempty"`
 }
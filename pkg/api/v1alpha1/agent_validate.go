@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 // Validate runs structural validation on the Agent envelope: ObjectMeta
@@ -30,6 +31,8 @@ func (a *Agent) ResolveRefs(ctx context.Context, resolver ResolverFunc) error {
 	errs = append(errs, resolveResourceRefs(ctx, resolver, ns, "spec.mcpServers", a.Spec.MCPServers, KindMCPServer)...)
 	errs = append(errs, resolveResourceRefs(ctx, resolver, ns, "spec.plugins", a.Spec.Plugins, KindPlugin)...)
 	errs = append(errs, resolveResourceRefs(ctx, resolver, ns, "spec.skills", a.Spec.Skills, KindSkill)...)
+	errs = append(errs, resolveResourceRefs(ctx, resolver, ns, "spec.charts", a.Spec.Charts, KindChart)...)
+	errs = append(errs, resolveResourceRefs(ctx, resolver, ns, "spec.subAgents", a.Spec.SubAgents, KindAgent)...)
 	if a.Spec.Instructions != nil {
 		errs = append(errs, resolveResourceRefs(ctx, resolver, ns, "spec.instructions", []ResourceRef{*a.Spec.Instructions}, KindPrompt)...)
 	}
@@ -47,7 +50,9 @@ func resolveResourceRefs(ctx context.Context, resolver ResolverFunc, ns, path st
 		if ref.Kind == "" {
 			ref.Kind = defaultKind
 		}
-		if ref.Namespace == "" {
+		// Peer refs keep a blank namespace: it means the peer's default,
+		// not the agent's namespace here.
+		if ref.Namespace == "" && ref.Registry == "" {
 			ref.Namespace = ns
 		}
 		errs = append(errs, resolveRefWith(ctx, resolver, ref, fmt.Sprintf("%s[%d]", path, i))...)
@@ -62,10 +67,12 @@ func validateAgentSpec(s *AgentSpec) FieldErrors {
 	var errs FieldErrors
 
 	errs.Append("spec.title", validateTitle(s.Title))
+	validateIcon(&errs, "spec.icon", s.Icon)
 	if s.Source != nil {
 		for _, e := range validateRepository(s.Source.Repository) {
 			errs.Append("spec.source."+e.Path, e.Cause)
 		}
+		validatePlatforms(&errs, "spec.source.platforms", s.Source.Platforms)
 	}
 	errs = append(errs, validateHarnessCompatibility(s.CompatibleHarnesses)...)
 
@@ -76,6 +83,8 @@ func validateAgentSpec(s *AgentSpec) FieldErrors {
 	errs = append(errs, validateResourceRefs("spec.mcpServers", s.MCPServers, KindMCPServer)...)
 	errs = append(errs, validateResourceRefs("spec.plugins", s.Plugins, KindPlugin)...)
 	errs = append(errs, validateResourceRefs("spec.skills", s.Skills, KindSkill)...)
+	errs = append(errs, validateResourceRefs("spec.charts", s.Charts, KindChart)...)
+	errs = append(errs, validateResourceRefs("spec.subAgents", s.SubAgents, KindAgent)...)
 	if s.Instructions != nil {
 		if s.Instructions.Kind == "" {
 			s.Instructions.Kind = KindPrompt
@@ -83,6 +92,11 @@ func validateAgentSpec(s *AgentSpec) FieldErrors {
 		errs = append(errs, validateResourceRefs("spec.instructions", []ResourceRef{*s.Instructions}, KindPrompt)...)
 	}
 
+	errs = append(errs, validateAgentSecrets(s.Secrets)...)
+	if s.Resources != nil {
+		errs = append(errs, validateAgentResources(s.Resources)...)
+	}
+
 	// Plugins/skills/instructions only apply to harness-compatible agents — a
 	// prebuilt Image cannot consume injected files by itself.
 	if (len(s.Plugins) > 0 || len(s.Skills) > 0 || s.Instructions != nil) &&
@@ -93,6 +107,53 @@ func validateAgentSpec(s *AgentSpec) FieldErrors {
 	return errs
 }
 
+func validateAgentSecrets(secrets []AgentSecret) FieldErrors {
+	var errs FieldErrors
+	validateMaxItems(&errs, "spec.secrets", len(secrets), MaxRefs)
+	seen := map[string]struct{}{}
+	for i, secret := range secrets {
+		path := fmt.Sprintf("spec.secrets[%d]", i)
+		if secret.Name == "" {
+			errs.Append(path+".name", fmt.Errorf("%w", ErrRequiredField))
+			continue
+		}
+		if !envNameRegex.MatchString(secret.Name) {
+			errs.Append(path+".name", fmt.Errorf("%w: must match %s", ErrInvalidFormat, envNameRegex.String()))
+			continue
+		}
+		if _, ok := seen[secret.Name]; ok {
+			errs.Append(path+".name", fmt.Errorf("%w: duplicate secret %q", ErrInvalidFormat, secret.Name))
+			continue
+		}
+		seen[secret.Name] = struct{}{}
+	}
+	return errs
+}
+
+func validateAgentResources(r *AgentResources) FieldErrors {
+	var errs FieldErrors
+	if r.GPUs < 0 {
+		errs.Append("spec.resources.gpus", fmt.Errorf("%w: must not be negative", ErrInvalidFormat))
+	} else if r.GPUs > MaxAgentGPUs {
+		errs.Append("spec.resources.gpus", fmt.Errorf("%w: %d GPUs (max %d)", ErrLimitExceeded, r.GPUs, MaxAgentGPUs))
+	}
+	validateResourceRequirements(&errs, "spec.resources", &ResourceRequirements{Requests: r.Requests, Limits: r.Limits})
+	validateMaxItems(&errs, "spec.resources.devices", len(r.Devices), MaxAgentDevices)
+	for i, device := range r.Devices {
+		path := fmt.Sprintf("spec.resources.devices[%d]", i)
+		parts := strings.Split(device, ":")
+		if len(parts) > 3 || !strings.HasPrefix(parts[0], "/") ||
+			(len(parts) > 1 && !strings.HasPrefix(parts[1], "/")) {
+			errs.Append(path, fmt.Errorf("%w: want /host/path[:/container/path[:permissions]]", ErrInvalidFormat))
+			continue
+		}
+		if len(parts) == 3 && (parts[2] == "" || strings.Trim(parts[2], "rwm") != "") {
+			errs.Append(path, fmt.Errorf("%w: permissions must be a combination of r, w and m", ErrInvalidFormat))
+		}
+	}
+	return errs
+}
+
 func validateHarnessCompatibility(harnesses []HarnessCompatibility) FieldErrors {
 	var errs FieldErrors
 	seen := map[string]struct{}{}
@@ -119,6 +180,7 @@ func validateHarnessCompatibility(harnesses []HarnessCompatibility) FieldErrors
 // slice field.
 func validateResourceRefs(path string, refs []ResourceRef, expectKind string) FieldErrors {
 	var errs FieldErrors
+	validateMaxItems(&errs, path, len(refs), MaxRefs)
 	for i := range refs {
 		if refs[i].Kind == "" {
 			refs[i].Kind = expectKind
@@ -127,7 +189,25 @@ func validateResourceRefs(path string, refs []ResourceRef, expectKind string) Fi
 			errs.Append(fmt.Sprintf("%s[%d].kind", path, i),
 				fmt.Errorf("%w: must be %q, got %q", ErrInvalidRef, expectKind, refs[i].Kind))
 		}
-		for _, e := range validateRef(refs[i]) {
+		ref := refs[i]
+		// MCP server refs may pin a semver constraint instead of a tag;
+		// deploys resolve it to the highest matching tag.
+		if expectKind == KindMCPServer && IsTagConstraint(ref.Tag) {
+			if ref.Registry != "" {
+				errs.Append(fmt.Sprintf("%s[%d].tag", path, i),
+					fmt.Errorf("%w: version constraints are not supported on peer registry refs", ErrInvalidRef))
+			} else if _, err := ParseTagConstraint(ref.Tag); err != nil {
+				errs.Append(fmt.Sprintf("%s[%d].tag", path, i), err)
+			}
+			ref.Tag = ""
+		}
+		if ref.Registry != "" && expectKind == KindMCPServer {
+			if err := ValidatePeerRegistryName(ref.Registry); err != nil {
+				errs.Append(fmt.Sprintf("%s[%d].registry", path, i), err)
+			}
+			ref.Registry = ""
+		}
+		for _, e := range validateRef(ref) {
 			errs.Append(fmt.Sprintf("%s[%d].%s", path, i, e.Path), e.Cause)
 		}
 	}
@@ -0,0 +1,40 @@
+package v1alpha1
+
+// Chart is the typed envelope for kind=Chart resources.
+type Chart struct {
+	TypeMeta `json:",inline" yaml:",inline"`
+	Metadata ObjectMeta `json:"metadata" yaml:"metadata"`
+	Spec     ChartSpec  `json:"spec" yaml:"spec"`
+	Status   Status     `json:"status,omitzero" yaml:"status,omitempty"`
+}
+
+func init() {
+	MustRegisterKind[*Chart, ChartSpec](KindChart)
+}
+
+// ChartSpec registers a Helm chart for supporting infrastructure (vector
+// databases, gateways, ...) that agents depend on. The registry stores the
+// chart reference, not the chart itself. A Deployment targeting the Chart
+// installs it as a Helm release on a kubernetes Runtime. Agents list charts
+// under spec.charts, and the kubernetes runtime installs them ahead of the
+// agent.
+type ChartSpec struct {
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Repository is where the chart is fetched from: an http(s):// chart
+	// repository URL, or an oci:// registry reference.
+	Repository string `json:"repository" yaml:"repository"`
+	// Chart is the chart name inside an http(s) Repository. For oci://
+	// repositories it is optional and appended as the last path segment.
+	Chart string `json:"chart,omitempty" yaml:"chart,omitempty"`
+	// Version pins the chart version to install.
+	Version string `json:"version" yaml:"version"`
+
+	// ValuesSchema is a JSON Schema the install values must satisfy. Values
+	// and each Deployment's override values are checked against it.
+	ValuesSchema map[string]any `json:"valuesSchema,omitempty" yaml:"valuesSchema,omitempty"`
+	// Values are the default install values. Deployments targeting the chart
+	// deep-merge spec.runtimeConfig.values over them.
+	Values map[string]any `json:"values,omitempty" yaml:"values,omitempty"`
+}
@@ -0,0 +1,118 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// chartNamePattern matches a Helm chart name: one path segment that
+// starts with a letter or digit, so it is never read as a flag.
+var chartNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,249}$`)
+
+// chartVersionPattern matches an exact chart version: semver, optionally
+// v-prefixed, with minor and patch optional as Helm allows ("1", "1.2").
+var chartVersionPattern = regexp.MustCompile(`^v?[0-9]+(\.[0-9]+){0,2}(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+func (c *Chart) Validate() error {
+	var errs FieldErrors
+	errs = append(errs, ValidateObjectMeta(c.Metadata)...)
+	errs = append(errs, validateChartSpec(&c.Spec)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateChartSpec(s *ChartSpec) FieldErrors {
+	var errs FieldErrors
+	errs.Append("spec.title", validateTitle(s.Title))
+
+	if s.Repository == "" {
+		errs.Append("spec.repository", fmt.Errorf("%w", ErrRequiredField))
+	} else if u, err := url.Parse(s.Repository); err != nil || u.Host == "" {
+		errs.Append("spec.repository", fmt.Errorf("%w: %q", ErrInvalidURL, s.Repository))
+	} else {
+		switch u.Scheme {
+		case "oci":
+		case "http", "https":
+			if s.Chart == "" {
+				errs.Append("spec.chart", fmt.Errorf("%w: required for http(s) repositories", ErrRequiredField))
+			}
+		default:
+			errs.Append("spec.repository", fmt.Errorf("%w: scheme must be oci, http or https", ErrInvalidURL))
+		}
+	}
+	if s.Chart != "" && !chartNamePattern.MatchString(s.Chart) {
+		errs.Append("spec.chart", fmt.Errorf("%w: %q is not a chart name", ErrInvalidFormat, s.Chart))
+	}
+	if s.Version == "" {
+		errs.Append("spec.version", fmt.Errorf("%w", ErrRequiredField))
+	} else if !chartVersionPattern.MatchString(s.Version) {
+		errs.Append("spec.version", fmt.Errorf("%w: %q is not a semantic version", ErrInvalidFormat, s.Version))
+	}
+
+	if len(s.ValuesSchema) > 0 {
+		if _, err := resolveValuesSchema(s.ValuesSchema); err != nil {
+			errs.Append("spec.valuesSchema", fmt.Errorf("%w: %v", ErrInvalidFormat, err))
+		} else if err := ValidateChartValues(s, s.Values); err != nil {
+			errs.Append("spec.values", err)
+		}
+	}
+	return errs
+}
+
+// ValidateChartValues checks values against the chart's ValuesSchema. A
+// chart without a schema accepts any values.
+func ValidateChartValues(s *ChartSpec, values map[string]any) error {
+	if s == nil || len(s.ValuesSchema) == 0 {
+		return nil
+	}
+	resolved, err := resolveValuesSchema(s.ValuesSchema)
+	if err != nil {
+		return fmt.Errorf("%w: values schema: %v", ErrInvalidFormat, err)
+	}
+	// The validator expects plain JSON values (float64 numbers, map[string]any
+	// objects); round-trip so values built in Go validate like decoded ones.
+	instance, err := toJSONValue(values)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	if instance == nil {
+		instance = map[string]any{}
+	}
+	if err := resolved.Validate(instance); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	return nil
+}
+
+func resolveValuesSchema(raw map[string]any) (*jsonschema.Resolved, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	return schema.Resolve(nil)
+}
+
+func toJSONValue(v map[string]any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
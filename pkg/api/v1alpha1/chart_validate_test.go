@@ -0,0 +1,111 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChartValidate(t *testing.T) {
+	replicaSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"replicaCount": map[string]any{"type": "integer", "minimum": 1},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		spec    ChartSpec
+		wantErr string // substring; empty means valid
+	}{
+		{
+			name: "valid http repository",
+			spec: ChartSpec{Repository: "https://qdrant.github.io/qdrant-helm", Chart: "qdrant", Version: "1.12.0"},
+		},
+		{
+			name: "valid oci reference without chart name",
+			spec: ChartSpec{Repository: "oci://ghcr.io/acme/charts/gateway", Version: "0.3.1"},
+		},
+		{
+			name: "values satisfy schema",
+			spec: ChartSpec{Repository: "oci://ghcr.io/acme/qdrant", Version: "1", ValuesSchema: replicaSchema, Values: map[string]any{"replicaCount": 2}},
+		},
+		{
+			name:    "missing repository",
+			spec:    ChartSpec{Chart: "qdrant", Version: "1"},
+			wantErr: "spec.repository",
+		},
+		{
+			name:    "unsupported scheme",
+			spec:    ChartSpec{Repository: "git://github.com/acme/charts", Chart: "qdrant", Version: "1"},
+			wantErr: "scheme must be oci, http or https",
+		},
+		{
+			name:    "http repository without chart name",
+			spec:    ChartSpec{Repository: "https://charts.example.com", Version: "1"},
+			wantErr: "spec.chart",
+		},
+		{
+			name:    "missing version",
+			spec:    ChartSpec{Repository: "oci://ghcr.io/acme/qdrant"},
+			wantErr: "spec.version",
+		},
+		{
+			name:    "chart name that reads as a flag",
+			spec:    ChartSpec{Repository: "https://charts.example.com", Chart: "--post-renderer=/bin/sh", Version: "1"},
+			wantErr: "spec.chart",
+		},
+		{
+			name:    "chart name with a path",
+			spec:    ChartSpec{Repository: "oci://ghcr.io/acme", Chart: "../qdrant", Version: "1"},
+			wantErr: "spec.chart",
+		},
+		{
+			name:    "version that reads as a flag",
+			spec:    ChartSpec{Repository: "oci://ghcr.io/acme/qdrant", Version: "-1"},
+			wantErr: "spec.version",
+		},
+		{
+			name:    "version range",
+			spec:    ChartSpec{Repository: "oci://ghcr.io/acme/qdrant", Version: ">=1.0 --devel"},
+			wantErr: "spec.version",
+		},
+		{
+			name: "prerelease version",
+			spec: ChartSpec{Repository: "oci://ghcr.io/acme/qdrant", Version: "v1.12.0-rc.1+build.5"},
+		},
+		{
+			name:    "invalid schema",
+			spec:    ChartSpec{Repository: "oci://ghcr.io/acme/qdrant", Version: "1", ValuesSchema: map[string]any{"type": 7}},
+			wantErr: "spec.valuesSchema",
+		},
+		{
+			name:    "values violate schema",
+			spec:    ChartSpec{Repository: "oci://ghcr.io/acme/qdrant", Version: "1", ValuesSchema: replicaSchema, Values: map[string]any{"replicaCount": 0}},
+			wantErr: "spec.values",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Chart{
+				TypeMeta: TypeMeta{APIVersion: GroupVersion, Kind: KindChart},
+				Metadata: ObjectMeta{Namespace: "default", Name: "qdrant", Tag: "v1"},
+				Spec:     tt.spec,
+			}
+			err := c.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected valid, got: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error %q does not contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
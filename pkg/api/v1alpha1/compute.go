@@ -0,0 +1,163 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+// ComputeResources is an amount of CPU and memory in Kubernetes quantity
+// notation: CPU in cores ("2", "0.5") or millicores ("500m"), memory in
+// bytes with an optional decimal (k, M, G, T) or binary (Ki, Mi, Gi, Ti)
+// suffix ("512Mi", "1G").
+type ComputeResources struct {
+	CPU    string `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty" yaml:"memory,omitempty"`
+}
+
+// IsZero reports whether neither CPU nor memory is set.
+func (c *ComputeResources) IsZero() bool {
+	return c == nil || (c.CPU == "" && c.Memory == "")
+}
+
+// ResourceRequirements are the CPU and memory a deployed container is
+// guaranteed (Requests) and capped at (Limits). Local runtimes map them to
+// compose deploy.resources reservations and limits; Kubernetes runtimes to
+// the container's resource requests and limits.
+type ResourceRequirements struct {
+	Requests *ComputeResources `json:"requests,omitempty" yaml:"requests,omitempty"`
+	Limits   *ComputeResources `json:"limits,omitempty" yaml:"limits,omitempty"`
+}
+
+// IsZero reports whether no request or limit is set.
+func (r *ResourceRequirements) IsZero() bool {
+	return r == nil || (r.Requests.IsZero() && r.Limits.IsZero())
+}
+
+// MergeResourceRequirements returns the requirements layers describe
+// together: each request and limit comes from the first layer that sets
+// it, so earlier layers win value by value. Nil layers are skipped, and
+// the result is nil when no layer sets anything.
+func MergeResourceRequirements(layers ...*ResourceRequirements) *ResourceRequirements {
+	var requests, limits ComputeResources
+	for _, layer := range layers {
+		if layer == nil {
+			continue
+		}
+		fillCompute(&requests, layer.Requests)
+		fillCompute(&limits, layer.Limits)
+	}
+	out := &ResourceRequirements{}
+	if !requests.IsZero() {
+		out.Requests = &requests
+	}
+	if !limits.IsZero() {
+		out.Limits = &limits
+	}
+	if out.IsZero() {
+		return nil
+	}
+	return out
+}
+
+func fillCompute(dst, src *ComputeResources) {
+	if src == nil {
+		return
+	}
+	if dst.CPU == "" {
+		dst.CPU = src.CPU
+	}
+	if dst.Memory == "" {
+		dst.Memory = src.Memory
+	}
+}
+
+var (
+	cpuPattern    = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)(m?)$`)
+	memoryPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)(|k|M|G|T|Ki|Mi|Gi|Ti)$`)
+)
+
+var memoryMultipliers = map[string]float64{
+	"": 1, "k": 1e3, "M": 1e6, "G": 1e9, "T": 1e12,
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40,
+}
+
+// ParseCPU returns a CPU quantity in millicores.
+func ParseCPU(s string) (int64, error) {
+	m := cpuPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("%w: cpu %q is not a number of cores or millicores (e.g. 2, 0.5, 500m)", ErrInvalidFormat, s)
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: cpu %q: %v", ErrInvalidFormat, s, err)
+	}
+	if m[2] == "" {
+		n *= 1000
+	}
+	if n != math.Trunc(n) {
+		return 0, fmt.Errorf("%w: cpu %q is finer than one millicore", ErrInvalidFormat, s)
+	}
+	return int64(n), nil
+}
+
+// ParseMemory returns a memory quantity in bytes.
+func ParseMemory(s string) (int64, error) {
+	m := memoryPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("%w: memory %q is not a number of bytes with an optional k, M, G, T, Ki, Mi, Gi or Ti suffix", ErrInvalidFormat, s)
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: memory %q: %v", ErrInvalidFormat, s, err)
+	}
+	return int64(math.Ceil(n * memoryMultipliers[m[2]])), nil
+}
+
+// validateResourceRequirements checks the requirements at path: every
+// quantity well-formed and no request above its limit.
+func validateResourceRequirements(errs *FieldErrors, path string, r *ResourceRequirements) {
+	if r == nil {
+		return
+	}
+	requests := validateCompute(errs, path+".requests", r.Requests)
+	limits := validateCompute(errs, path+".limits", r.Limits)
+	if requests.cpu > 0 && limits.cpu > 0 && requests.cpu > limits.cpu {
+		errs.Append(path+".requests.cpu", fmt.Errorf("%w: %s exceeds the limit %s", ErrInvalidFormat, r.Requests.CPU, r.Limits.CPU))
+	}
+	if requests.memory > 0 && limits.memory > 0 && requests.memory > limits.memory {
+		errs.Append(path+".requests.memory", fmt.Errorf("%w: %s exceeds the limit %s", ErrInvalidFormat, r.Requests.Memory, r.Limits.Memory))
+	}
+}
+
+type parsedCompute struct {
+	cpu    int64
+	memory int64
+}
+
+func validateCompute(errs *FieldErrors, path string, c *ComputeResources) parsedCompute {
+	var out parsedCompute
+	if c == nil {
+		return out
+	}
+	if c.CPU != "" {
+		cpu, err := ParseCPU(c.CPU)
+		if err != nil {
+			errs.Append(path+".cpu", err)
+		} else if cpu == 0 {
+			errs.Append(path+".cpu", fmt.Errorf("%w: must be positive", ErrInvalidFormat))
+		}
+		out.cpu = cpu
+	}
+	if c.Memory != "" {
+		memory, err := ParseMemory(c.Memory)
+		if err != nil {
+			errs.Append(path+".memory", err)
+		} else if memory == 0 {
+			errs.Append(path+".memory", fmt.Errorf("%w: must be positive", ErrInvalidFormat))
+		}
+		out.memory = memory
+	}
+	return out
+}
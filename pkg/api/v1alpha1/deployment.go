@@ -1,5 +1,7 @@
 package v1alpha1
 
+import "strings"
+
 // Deployment is the typed envelope for kind=Deployment resources.
 //
 // Deployment's metadata.name is independent from the thing it deploys
@@ -31,6 +33,43 @@ const (
 	DeploymentOriginDiscovered                = "discovered"
 )
 
+// DeploymentParentAnnotation names the Deployment, in the same namespace,
+// that created this one to run a sub-agent of its Agent (AgentSpec.SubAgents).
+// The deployment controller owns such rows: it creates them, prunes them when
+// the parent's Agent drops the sub-agent and deletes them with the parent.
+const DeploymentParentAnnotation = "agentregistry.solo.io/parent-deployment"
+
+// DeploymentPlacementAnnotation records why the registry placed a
+// Deployment that selects its Runtime (DeploymentSpec.RuntimeSelector) on
+// the Runtime now in spec.runtimeRef.
+const DeploymentPlacementAnnotation = "agentregistry.solo.io/placement"
+
+// DeploymentCostAllocationAnnotation records, as a JSON object, the
+// cost-allocation tags (see CostAllocation) the runtime adapter last put on
+// what the Deployment created.
+const DeploymentCostAllocationAnnotation = "agentregistry.solo.io/cost-allocation"
+
+// DeploymentResolvedTagsAnnotation records, as a JSON list of ResolvedTag,
+// the tags the deployed Agent's version-constraint refs resolved to when
+// the Deployment was last applied.
+const DeploymentResolvedTagsAnnotation = "agentregistry.solo.io/resolved-tags"
+
+// DeploymentEndpointAnnotation records the URL the runtime adapter reported
+// the deployed Agent answering A2A on when the Deployment was last applied.
+// The registry serves it in the agent's card.
+const DeploymentEndpointAnnotation = "agentregistry.solo.io/endpoint"
+
+// DeploymentDebugTrafficAnnotation puts a Deployment in traffic debug
+// mode when set to "true": the registry then relays MCP requests for it on
+// POST /v0/deployments/{name}/mcp and records the frames, redacted, for
+// GET /v0/deployments/{name}/traffic.
+const DeploymentDebugTrafficAnnotation = "agentregistry.solo.io/debug-traffic"
+
+// DeploymentTemplateAnnotation records the DeploymentTemplate a Deployment
+// was created from. The template is applied once, at creation; the
+// annotation only says where the Deployment's defaults came from.
+const DeploymentTemplateAnnotation = "agentregistry.solo.io/deployment-template"
+
 // IsDiscoveredDeployment reports whether a Deployment row was materialized from
 // provider discovery rather than authored as registry-managed desired state.
 func IsDiscoveredDeployment(deployment *Deployment) bool {
@@ -47,6 +86,27 @@ const (
 	DesiredStateUndeployed = "undeployed"
 )
 
+// SecretRefPrefix marks a Spec.Env value as a reference into the runtime's
+// secret store instead of a literal: "secretRef:<secret>/<key>". Kubernetes
+// runtimes resolve it from a Secret in the agent's namespace; the local
+// runtime has no secret store and rejects it.
+const SecretRefPrefix = "secretRef:"
+
+// ParseSecretRef splits a SecretRefPrefix value into the secret name and
+// key. ok is false for literal values and for references missing either
+// part.
+func ParseSecretRef(value string) (secret, key string, ok bool) {
+	ref, found := strings.CutPrefix(value, SecretRefPrefix)
+	if !found {
+		return "", "", false
+	}
+	secret, key, found = strings.Cut(ref, "/")
+	if !found || secret == "" || key == "" {
+		return "", "", false
+	}
+	return secret, key, true
+}
+
 // DeploymentSpec is the deployment resource's declarative body.
 //
 // TargetRef is required and must name a top-level Agent or MCPServer. The
@@ -54,26 +114,65 @@ const (
 // Deployment contributes only runtime overrides (env, runtimeConfig) and
 // lifecycle intent (desiredState).
 //
-// RuntimeRef is required and must name a top-level Runtime. The Runtime
-// resolves how/where the target is executed (local daemon, kubernetes, etc.).
+// RuntimeRef must name a top-level Runtime. The Runtime resolves how/where
+// the target is executed (local daemon, kubernetes, etc.). It may be left
+// out when RuntimeSelector is set; the registry then picks a Runtime at
+// apply time and fills RuntimeRef in.
 type DeploymentSpec struct {
-	TargetRef    ResourceRef `json:"targetRef" yaml:"targetRef"`
-	RuntimeRef   ResourceRef `json:"runtimeRef" yaml:"runtimeRef"`
-	DesiredState string      `json:"desiredState,omitempty" yaml:"desiredState,omitempty"`
+	TargetRef  ResourceRef `json:"targetRef" yaml:"targetRef"`
+	RuntimeRef ResourceRef `json:"runtimeRef,omitzero" yaml:"runtimeRef,omitempty"`
+	// RuntimeSelector places the Deployment on one of the matching
+	// Runtimes in its namespace that still has capacity. See
+	// RuntimeSelector.
+	RuntimeSelector *RuntimeSelector `json:"runtimeSelector,omitempty" yaml:"runtimeSelector,omitempty"`
+	DesiredState    string           `json:"desiredState,omitempty" yaml:"desiredState,omitempty"`
+	// Snapshot pins the Deployment to a catalog snapshot: TargetRef and
+	// every tagged artifact the target refers to resolve from the versions
+	// the snapshot captured instead of the live catalog, so later publishes
+	// don't change what runs. Refs to peer registries are not pinned.
+	Snapshot string `json:"snapshot,omitempty" yaml:"snapshot,omitempty"`
 	// DeploymentRefs declaratively binds this Deployment to other
 	// Deployments — e.g. an Agent Deployment binding to the MCPServer
 	// Deployments whose status should feed its runtime config. Stored
 	// and structurally validated; binding semantics are owned by the
 	// kind's reconciler.
 	DeploymentRefs []DeploymentRef   `json:"deploymentRefs,omitempty" yaml:"deploymentRefs,omitempty"`
-	Env            map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	Env            map[string]string `json:"env,omitempty" yaml:"env,omitempty" maxProperties:"100"`
 	RuntimeConfig  map[string]any    `json:"runtimeConfig,omitempty" yaml:"runtimeConfig,omitempty"`
+	// Resources sets the CPU and memory requests and limits of the deployed
+	// container, overriding an Agent target's spec.resources value by
+	// value. Unset values fall back to the registry's defaults.
+	Resources *ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
 	// Harness selects a compatible harness for Agent deployments and configures
 	// rollout-specific harness policy. Omitted for BYO image/source Agent
 	// deployments and MCPServer deployments.
 	Harness *DeploymentHarness `json:"harness,omitempty" yaml:"harness,omitempty"`
 }
 
+// RuntimeSelector chooses the Runtimes a Deployment may be placed on: those
+// in the Deployment's namespace of Type (any type when empty) whose labels
+// include every MatchLabels entry, e.g. region, gpu or environment. Among
+// the matches with room under RuntimeSpec.Capacity, the Runtime serving the
+// fewest Deployments wins. A placement sticks across re-applies for as long
+// as its Runtime still matches.
+type RuntimeSelector struct {
+	Type        string            `json:"type,omitempty" yaml:"type,omitempty"`
+	MatchLabels map[string]string `json:"matchLabels,omitempty" yaml:"matchLabels,omitempty" maxProperties:"20"`
+}
+
+// Matches reports whether runtime satisfies the selector.
+func (s *RuntimeSelector) Matches(runtime *Runtime) bool {
+	if s.Type != "" && !strings.EqualFold(s.Type, runtime.Spec.Type) {
+		return false
+	}
+	for key, value := range s.MatchLabels {
+		if got, ok := runtime.Metadata.Labels[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
 // DeploymentHarness selects the concrete harness to run for one Deployment.
 // The target Agent declares compatibility; the Runtime supplies concrete
 // runner support such as container images.
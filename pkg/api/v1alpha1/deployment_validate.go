@@ -3,6 +3,8 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 )
 
@@ -39,11 +41,15 @@ func (d *Deployment) ResolveRefs(ctx context.Context, resolver ResolverFunc) err
 	}
 	errs = append(errs, resolveRefWith(ctx, resolver, target, "spec.targetRef")...)
 
-	runtime := d.Spec.RuntimeRef
-	if runtime.Namespace == "" {
-		runtime.Namespace = d.Metadata.Namespace
+	// A Deployment still waiting on its RuntimeSelector has no runtime
+	// to resolve yet.
+	if !d.Spec.selectsRuntime() {
+		runtime := d.Spec.RuntimeRef
+		if runtime.Namespace == "" {
+			runtime.Namespace = d.Metadata.Namespace
+		}
+		errs = append(errs, resolveRefWith(ctx, resolver, runtime, "spec.runtimeRef")...)
 	}
-	errs = append(errs, resolveRefWith(ctx, resolver, runtime, "spec.runtimeRef")...)
 
 	for i, ref := range d.Spec.DeploymentRefs {
 		probe := ResourceRef{Kind: KindDeployment, Namespace: ref.Namespace, Name: ref.Name}
@@ -63,22 +69,32 @@ func validateDeploymentSpec(s *DeploymentSpec) FieldErrors {
 	var errs FieldErrors
 
 	// TargetRef: required. Accepts the bundled lifecycle kinds (Agent,
-	// MCPServer). MCPServer covers both bundled (spec.source) and remote
-	// (spec.remote) variants under a single kind; adapters dispatch on
-	// whether Spec.Source or Spec.Remote is set.
-	for _, e := range validateRef(s.TargetRef, KindAgent, KindMCPServer) {
+	// MCPServer, Chart). MCPServer covers both bundled (spec.source) and
+	// remote (spec.remote) variants under a single kind; adapters dispatch on
+	// whether Spec.Source or Spec.Remote is set. Charts are only served by
+	// adapters that list KindChart in SupportedTargetKinds (kubernetes).
+	for _, e := range validateRef(s.TargetRef, KindAgent, KindMCPServer, KindChart) {
 		errs.Append("spec.targetRef."+e.Path, e.Cause)
 	}
-	// RuntimeRef: required, must name a Runtime.
-	for _, e := range validateRef(s.RuntimeRef, KindRuntime) {
-		errs.Append("spec.runtimeRef."+e.Path, e.Cause)
+	// RuntimeRef: required unless RuntimeSelector picks one, must name a
+	// Runtime.
+	if !s.selectsRuntime() {
+		for _, e := range validateRef(s.RuntimeRef, KindRuntime) {
+			errs.Append("spec.runtimeRef."+e.Path, e.Cause)
+		}
 	}
+	validateRuntimeSelector(&errs, "spec.runtimeSelector", s.RuntimeSelector)
 
 	if s.TargetRef.Tag != "" {
 		if err := validateTag(s.TargetRef.Tag); err != nil {
 			errs.Append("spec.targetRef.tag", err)
 		}
 	}
+	if s.Snapshot != "" {
+		if err := ValidateSnapshotName(s.Snapshot); err != nil {
+			errs.Append("spec.snapshot", err)
+		}
+	}
 	if s.Harness != nil {
 		if s.TargetRef.Kind != KindAgent {
 			errs.Append("spec.harness", fmt.Errorf("%w: harness selection is only valid for Agent deployments", ErrInvalidFormat))
@@ -98,6 +114,28 @@ func validateDeploymentSpec(s *DeploymentSpec) FieldErrors {
 				DesiredStateDeployed, DesiredStateUndeployed))
 	}
 
+	validateMaxItems(&errs, "spec.env", len(s.Env), MaxEnvVars)
+	for _, key := range slices.Sorted(maps.Keys(s.Env)) {
+		value := s.Env[key]
+		if !strings.HasPrefix(value, SecretRefPrefix) {
+			continue
+		}
+		if s.TargetRef.Kind != KindAgent {
+			errs.Append("spec.env."+key, fmt.Errorf("%w: secret references are only supported for Agent deployments", ErrInvalidFormat))
+			continue
+		}
+		if secret, _, ok := ParseSecretRef(value); !ok || validateNameField(secret) != nil {
+			errs.Append("spec.env."+key, fmt.Errorf("%w: secret references must be %s<secret>/<key>", ErrInvalidFormat, SecretRefPrefix))
+		}
+	}
+
+	if s.Resources != nil {
+		if s.TargetRef.Kind == KindChart {
+			errs.Append("spec.resources", fmt.Errorf("%w: resources are not supported for Chart deployments", ErrInvalidFormat))
+		}
+		validateResourceRequirements(&errs, "spec.resources", s.Resources)
+	}
+
 	for i, ref := range s.DeploymentRefs {
 		path := fmt.Sprintf("spec.deploymentRefs[%d]", i)
 		if err := validateNameField(ref.Name); err != nil {
@@ -110,3 +148,34 @@ func validateDeploymentSpec(s *DeploymentSpec) FieldErrors {
 
 	return errs
 }
+
+// validateRuntimeSelector checks sel, canonicalizing its type in place.
+// A nil selector is valid.
+func validateRuntimeSelector(errs *FieldErrors, path string, sel *RuntimeSelector) {
+	if sel == nil {
+		return
+	}
+	if sel.Type != "" {
+		if canonical, ok := canonicalRuntimeType(sel.Type); ok {
+			sel.Type = canonical
+		} else {
+			errs.Append(path+".type",
+				fmt.Errorf("%w: %q (known: %v)", ErrUnknownRuntimeType, sel.Type, knownRuntimeTypeNames()))
+		}
+	}
+	validateMaxItems(errs, path+".matchLabels", len(sel.MatchLabels), MaxRuntimeSelectorLabels)
+	for _, key := range slices.Sorted(maps.Keys(sel.MatchLabels)) {
+		if !labelKeyRegex.MatchString(key) {
+			errs.Append(path+".matchLabels["+key+"]", fmt.Errorf("%w: key %q", ErrInvalidLabel, key))
+		}
+		if value := sel.MatchLabels[key]; !labelValueRegex.MatchString(value) {
+			errs.Append(path+".matchLabels["+key+"]", fmt.Errorf("%w: value %q", ErrInvalidLabel, value))
+		}
+	}
+}
+
+// selectsRuntime reports whether the Deployment is left for its
+// RuntimeSelector to place: a selector is set and no Runtime is named.
+func (s *DeploymentSpec) selectsRuntime() bool {
+	return s.RuntimeSelector != nil && s.RuntimeRef.Name == ""
+}
@@ -0,0 +1,63 @@
+package v1alpha1
+
+import "maps"
+
+// DeploymentTemplate is the typed envelope for kind=DeploymentTemplate
+// resources.
+type DeploymentTemplate struct {
+	TypeMeta `json:",inline" yaml:",inline"`
+	Metadata ObjectMeta             `json:"metadata" yaml:"metadata"`
+	Spec     DeploymentTemplateSpec `json:"spec" yaml:"spec"`
+	Status   Status                 `json:"status,omitzero" yaml:"status,omitempty"`
+}
+
+func init() {
+	MustRegisterKind[*DeploymentTemplate, DeploymentTemplateSpec](KindDeploymentTemplate, WithMutableObjectStorage())
+}
+
+// DeploymentTemplateSpec is a named preset of Deployment settings, so a
+// team can keep the runtime placement, env, runtimeConfig and resources it
+// deploys with in the registry instead of passing them on every deploy.
+// Like Runtime it is unversioned: (namespace, name) is the identity.
+//
+// Templates are applied when a Deployment is created from one
+// (`arctl deployment create --template NAME`); the resulting Deployment
+// records its template in the DeploymentTemplateAnnotation but does not
+// follow later edits of it. Values the Deployment sets itself win; see
+// ApplyTo.
+type DeploymentTemplateSpec struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// RuntimeRef names the Runtime Deployments created from the template
+	// run on.
+	RuntimeRef ResourceRef `json:"runtimeRef,omitzero" yaml:"runtimeRef,omitempty"`
+	// RuntimeSelector places Deployments created from the template on one
+	// of the matching Runtimes instead. See RuntimeSelector.
+	RuntimeSelector *RuntimeSelector  `json:"runtimeSelector,omitempty" yaml:"runtimeSelector,omitempty"`
+	Env             map[string]string `json:"env,omitempty" yaml:"env,omitempty" maxProperties:"100"`
+	RuntimeConfig   map[string]any    `json:"runtimeConfig,omitempty" yaml:"runtimeConfig,omitempty"`
+	// Resources sets the CPU and memory requests and limits of the
+	// deployed container.
+	Resources *ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// ApplyTo returns spec with the template filled in under it: the template's
+// runtime placement when spec names neither a runtimeRef nor a
+// runtimeSelector, each env and top-level runtimeConfig key spec leaves
+// unset (as MergeDeploymentDefaults does), and each resource request and
+// limit spec leaves unset, except on Chart Deployments, which take no
+// resources. spec's maps are not modified.
+func (s DeploymentTemplateSpec) ApplyTo(spec DeploymentSpec) DeploymentSpec {
+	if spec.RuntimeRef.Name == "" && spec.RuntimeSelector == nil {
+		spec.RuntimeRef = s.RuntimeRef
+		if s.RuntimeSelector != nil {
+			sel := *s.RuntimeSelector
+			sel.MatchLabels = maps.Clone(sel.MatchLabels)
+			spec.RuntimeSelector = &sel
+		}
+	}
+	spec = MergeDeploymentDefaults(spec, &DeploymentDefaults{Env: s.Env, RuntimeConfig: s.RuntimeConfig})
+	if spec.TargetRef.Kind != KindChart {
+		spec.Resources = MergeResourceRequirements(spec.Resources, s.Resources)
+	}
+	return spec
+}
@@ -0,0 +1,20 @@
+package v1alpha1
+
+func (t *DeploymentTemplate) Validate() error {
+	var errs FieldErrors
+	errs = append(errs, ValidateObjectMeta(t.Metadata)...)
+	// RuntimeRef is optional: a template may leave placement to the
+	// Deployment, or to its RuntimeSelector.
+	if ref := t.Spec.RuntimeRef; ref != (ResourceRef{}) {
+		for _, e := range validateRef(ref, KindRuntime) {
+			errs.Append("spec.runtimeRef."+e.Path, e.Cause)
+		}
+	}
+	validateRuntimeSelector(&errs, "spec.runtimeSelector", t.Spec.RuntimeSelector)
+	validateDefaultEnv(&errs, "spec.env", t.Spec.Env)
+	validateResourceRequirements(&errs, "spec.resources", t.Spec.Resources)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,72 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    DeploymentTemplateSpec
+		wantErr string
+	}{
+		{name: "empty", spec: DeploymentTemplateSpec{}},
+		{name: "runtime and env", spec: DeploymentTemplateSpec{
+			RuntimeRef: ResourceRef{Kind: KindRuntime, Name: "gpu-pool"},
+			Env:        map[string]string{"LOG_LEVEL": "info", "OPENAI_API_KEY": SecretRefPrefix + "openai/key"},
+			Resources:  &ResourceRequirements{Limits: &ComputeResources{CPU: "2", Memory: "4Gi"}},
+		}},
+		{name: "selector", spec: DeploymentTemplateSpec{RuntimeSelector: &RuntimeSelector{Type: "kubernetes", MatchLabels: map[string]string{"gpu": "true"}}}},
+		{name: "runtimeRef of wrong kind", spec: DeploymentTemplateSpec{RuntimeRef: ResourceRef{Kind: KindAgent, Name: "gpu-pool"}}, wantErr: "spec.runtimeRef.kind"},
+		{name: "unknown selector type", spec: DeploymentTemplateSpec{RuntimeSelector: &RuntimeSelector{Type: "heroku"}}, wantErr: "spec.runtimeSelector.type"},
+		{name: "malformed secret ref", spec: DeploymentTemplateSpec{Env: map[string]string{"KEY": SecretRefPrefix + "openai"}}, wantErr: "spec.env.KEY"},
+		{name: "invalid resources", spec: DeploymentTemplateSpec{Resources: &ResourceRequirements{Limits: &ComputeResources{CPU: "lots"}}}, wantErr: "spec.resources.limits.cpu"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := &DeploymentTemplate{
+				TypeMeta: TypeMeta{APIVersion: GroupVersion, Kind: KindDeploymentTemplate},
+				Metadata: ObjectMeta{Namespace: "default", Name: "prod-gpu"},
+				Spec:     tt.spec,
+			}
+			err := tmpl.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDeploymentTemplateSpec_ApplyTo(t *testing.T) {
+	tmpl := DeploymentTemplateSpec{
+		RuntimeSelector: &RuntimeSelector{Type: TypeKubernetes, MatchLabels: map[string]string{"gpu": "true"}},
+		Env:             map[string]string{"LOG_LEVEL": "info", "REGION": "us-east-1", "TOKEN": SecretRefPrefix + "api/token"},
+		RuntimeConfig:   map[string]any{"replicas": 2},
+		Resources:       &ResourceRequirements{Limits: &ComputeResources{CPU: "2", Memory: "8Gi"}},
+	}
+	spec := DeploymentSpec{
+		TargetRef: ResourceRef{Kind: KindAgent, Name: "summarizer"},
+		Env:       map[string]string{"LOG_LEVEL": "debug"},
+		Resources: &ResourceRequirements{Limits: &ComputeResources{Memory: "16Gi"}},
+	}
+
+	got := tmpl.ApplyTo(spec)
+	require.Equal(t, tmpl.RuntimeSelector, got.RuntimeSelector)
+	require.Equal(t, map[string]string{"LOG_LEVEL": "debug", "REGION": "us-east-1", "TOKEN": SecretRefPrefix + "api/token"}, got.Env)
+	require.Equal(t, map[string]any{"replicas": 2}, got.RuntimeConfig)
+	require.Equal(t, &ComputeResources{CPU: "2", Memory: "16Gi"}, got.Resources.Limits)
+	require.Equal(t, map[string]string{"LOG_LEVEL": "debug"}, spec.Env, "the Deployment's own maps are untouched")
+
+	spec.RuntimeRef = ResourceRef{Kind: KindRuntime, Name: "local"}
+	got = tmpl.ApplyTo(spec)
+	require.Nil(t, got.RuntimeSelector, "an explicit runtimeRef keeps its placement")
+
+	chart := DeploymentSpec{TargetRef: ResourceRef{Kind: KindChart, Name: "observability"}}
+	got = tmpl.ApplyTo(chart)
+	require.Nil(t, got.Resources)
+	require.NotContains(t, got.Env, "TOKEN", "secret references only reach Agent Deployments")
+}
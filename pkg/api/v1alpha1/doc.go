@@ -1,8 +1,9 @@
 // Package v1alpha1 defines the Kubernetes-style API types for all agentregistry
 // resources.
 //
-// Every resource — Agent, MCPServer, Skill, Prompt, Deployment, Runtime —
-// uses the same envelope: apiVersion + kind + metadata + spec + status.
+// Every resource — Agent, MCPServer, Skill, Prompt, Chart, Deployment,
+// DeploymentTemplate, Runtime, Webhook, FeatureFlag — uses the same envelope: apiVersion + kind + metadata +
+// spec + status.
 // These types are the single wire/storage/API contract propagating from a YAML
 // manifest through the HTTP handler, Go client, service layer, and database
 // row (spec+status as JSONB; metadata columns promoted). No intermediate DTOs,
@@ -24,13 +25,17 @@ const GroupVersion = "ar.dev/v1alpha1"
 
 // Canonical Kind names.
 const (
-	KindAgent      = "Agent"
-	KindMCPServer  = "MCPServer"
-	KindSkill      = "Skill"
-	KindPlugin     = "Plugin"
-	KindPrompt     = "Prompt"
-	KindDeployment = "Deployment"
-	KindRuntime    = "Runtime"
+	KindAgent              = "Agent"
+	KindMCPServer          = "MCPServer"
+	KindSkill              = "Skill"
+	KindPlugin             = "Plugin"
+	KindPrompt             = "Prompt"
+	KindChart              = "Chart"
+	KindDeployment         = "Deployment"
+	KindDeploymentTemplate = "DeploymentTemplate"
+	KindRuntime            = "Runtime"
+	KindWebhook            = "Webhook"
+	KindFeatureFlag        = "FeatureFlag"
 )
 
 var (
@@ -53,9 +58,9 @@ func MustRegisterPlural(kind, plural string) {
 }
 
 // PluralFor returns the route-plural for a Kind (e.g. "mcpservers" for
-// KindMCPServer). By default it mirrors the convention the generic resource
-// handler uses when cfg.PluralKind is empty: ToLower(kind) + "s". Downstream
-// builds can override irregular plurals with RegisterPlural.
+// KindMCPServer): the kind's registered plural, else ToLower(kind) + "s".
+// The generic resource handler routes under it when cfg.PluralKind is empty.
+// Downstream builds can override irregular plurals with RegisterPlural.
 func PluralFor(kind string) string {
 	if descriptor, ok := KindDescriptorFor(kind); ok && descriptor.Plural != "" {
 		return descriptor.Plural
@@ -0,0 +1,103 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DocScoreAnnotation carries an MCPServer version's documentation score,
+// 0-100, as computed by ScoreMCPServerDocs. The registry sets it on every
+// publish, replacing any value the publisher supplied.
+const DocScoreAnnotation = "agentregistry.solo.io/doc-score"
+
+// Documentation thresholds ScoreMCPServerDocs checks against.
+const (
+	// MinReadmeLength is the README length, in characters, below which a
+	// README is scored as a stub.
+	MinReadmeLength = 400
+	// MinDescriptionLength is the description length, in characters, below
+	// which the card's description is scored as missing.
+	MinDescriptionLength = 40
+)
+
+// DocScore is the outcome of linting an MCP server's documentation: a score
+// from 0 to 100 and one suggestion per check that lost points.
+type DocScore struct {
+	Score       int      `json:"score"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// ScoreMCPServerDocs lints an MCP server's README and card (the fields
+// server.json clients show). Points are awarded for:
+//
+//   - a README (20) of at least MinReadmeLength characters (15)
+//   - a title (10) and a description of at least MinDescriptionLength
+//     characters (10)
+//   - a source repository link (10); remote servers have none and get
+//     these points
+//   - a tool list (10)
+//   - a usage example, i.e. a fenced code block in the README (15)
+//   - every environment variable and header the server takes named in the
+//     README (10)
+func ScoreMCPServerDocs(spec *MCPServerSpec) DocScore {
+	var d DocScore
+	check := func(ok bool, points int, suggestion string) {
+		if ok {
+			d.Score += points
+			return
+		}
+		d.Suggestions = append(d.Suggestions, suggestion)
+	}
+
+	readme := strings.TrimSpace(spec.Readme)
+	readmeLength := utf8.RuneCountInString(readme)
+	check(readme != "", 20, "add a README (spec.readme) describing what the server does and how to run it")
+	check(readmeLength >= MinReadmeLength, 15,
+		fmt.Sprintf("expand the README to at least %d characters (it has %d)", MinReadmeLength, readmeLength))
+
+	check(strings.TrimSpace(spec.Title) != "", 10, "set spec.title")
+	check(utf8.RuneCountInString(strings.TrimSpace(spec.Description)) >= MinDescriptionLength, 10,
+		fmt.Sprintf("write a spec.description of at least %d characters", MinDescriptionLength))
+	check(spec.Remote != nil || (spec.Source != nil && spec.Source.Repository != nil && spec.Source.Repository.URL != ""), 10,
+		"link the source code in spec.source.repository.url")
+	check(len(spec.Tools) > 0, 10, "list the tools the server exposes in spec.tools")
+
+	check(strings.Contains(readme, "```"), 15, "add a usage example to the README in a fenced code block")
+	var undocumented []string
+	for _, name := range docInputs(spec) {
+		if !strings.Contains(readme, name) {
+			undocumented = append(undocumented, name)
+		}
+	}
+	check(len(undocumented) == 0, 10, "document "+strings.Join(undocumented, ", ")+" in the README")
+	return d
+}
+
+// DocScoreOf returns the documentation score recorded on meta, and false
+// when there is none.
+func DocScoreOf(meta ObjectMeta) (int, bool) {
+	score, err := strconv.Atoi(meta.Annotations[DocScoreAnnotation])
+	if err != nil {
+		return 0, false
+	}
+	return score, true
+}
+
+// docInputs lists the environment variables and headers a user of the
+// server has to know about.
+func docInputs(spec *MCPServerSpec) []string {
+	var names []string
+	if spec.Source != nil && spec.Source.Package != nil && spec.Source.Package.Launch != nil {
+		for _, env := range spec.Source.Package.Launch.Env {
+			names = append(names, env.Name)
+		}
+	}
+	if spec.Remote != nil {
+		for _, h := range spec.Remote.Headers {
+			names = append(names, h.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,80 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreMCPServerDocs(t *testing.T) {
+	readme := "# Weather\n\nSet WEATHER_API_KEY to your key.\n\n```bash\nnpx -y @acme/weather\n```\n" + strings.Repeat("Forecasts. ", 40)
+	complete := MCPServerSpec{
+		Title:       "Weather",
+		Description: "Current conditions and forecasts for any city.",
+		Readme:      readme,
+		Tools:       []MCPTool{{Name: "forecast"}},
+		Source: &MCPServerSource{
+			Repository: &Repository{URL: "https://github.com/acme/weather"},
+			Package: &MCPPackage{
+				Origin: MCPPackageOrigin{Type: MCPPackageOriginTypeNPM, Identifier: "@acme/weather"},
+				Launch: &MCPPackageLaunch{Env: []MCPKeyValueInput{{Name: "WEATHER_API_KEY", IsRequired: true}}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*MCPServerSpec)
+		score  int
+		want   []string
+	}{
+		{name: "complete", mutate: func(*MCPServerSpec) {}, score: 100},
+		{
+			name:   "no readme",
+			mutate: func(s *MCPServerSpec) { s.Readme = "" },
+			score:  40,
+			want:   []string{"add a README", "expand the README", "add a usage example", "document WEATHER_API_KEY"},
+		},
+		{
+			name: "thin card",
+			mutate: func(s *MCPServerSpec) {
+				s.Title, s.Description, s.Tools, s.Source.Repository = "", "Weather.", nil, nil
+			},
+			score: 60,
+			want:  []string{"set spec.title", "spec.description of at least 40", "spec.source.repository.url", "spec.tools"},
+		},
+		{
+			name: "remote needs no repository",
+			mutate: func(s *MCPServerSpec) {
+				s.Source = nil
+				s.Remote = &MCPRemote{Type: "streamable-http", URL: "https://weather.example/mcp", Headers: []HTTPHeader{{Name: "X-Api-Key"}}}
+			},
+			score: 90,
+			want:  []string{"document X-Api-Key"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := complete
+			source := *complete.Source
+			spec.Source = &source
+			tt.mutate(&spec)
+			got := ScoreMCPServerDocs(&spec)
+			require.Equal(t, tt.score, got.Score, got.Suggestions)
+			require.Len(t, got.Suggestions, len(tt.want))
+			for i, want := range tt.want {
+				require.Contains(t, got.Suggestions[i], want)
+			}
+		})
+	}
+}
+
+func TestDocScoreOf(t *testing.T) {
+	score, ok := DocScoreOf(ObjectMeta{Annotations: map[string]string{DocScoreAnnotation: "72"}})
+	require.True(t, ok)
+	require.Equal(t, 72, score)
+
+	_, ok = DocScoreOf(ObjectMeta{})
+	require.False(t, ok)
+}
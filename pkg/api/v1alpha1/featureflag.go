@@ -0,0 +1,39 @@
+package v1alpha1
+
+// FeatureFlag is the typed envelope for kind=FeatureFlag resources.
+type FeatureFlag struct {
+	TypeMeta `json:",inline" yaml:",inline"`
+	Metadata ObjectMeta      `json:"metadata" yaml:"metadata"`
+	Spec     FeatureFlagSpec `json:"spec" yaml:"spec"`
+	Status   Status          `json:"status,omitzero" yaml:"status,omitempty"`
+}
+
+func init() {
+	MustRegisterKind[*FeatureFlag, FeatureFlagSpec](KindFeatureFlag, WithMutableObjectStorage(), WithPlural("flags"))
+}
+
+// FeatureFlagSpec is a boolean switch deployed agents read at runtime, so
+// operators can turn agent behavior (a new tool, a prompt variant) on or off
+// without a redeploy. Like Runtime it is unversioned: (namespace, name) is
+// the identity and an update takes effect on the agents' next evaluation.
+//
+// Agents evaluate every flag in their namespace through
+// GET /v0/flags:evaluate?namespace={ns}&agent={name}; runtimes whose
+// spec.registryURL is set hand each agent that URL as
+// AGENT_REGISTRY_FLAGS_URL.
+type FeatureFlagSpec struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Enabled is the flag's value for agents without an override.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Agents overrides Enabled for individual agents, keyed by the name of
+	// an Agent in the flag's namespace.
+	Agents map[string]bool `json:"agents,omitempty" yaml:"agents,omitempty" maxProperties:"100"`
+}
+
+// EvaluateFor returns the flag's value for the named agent.
+func (s FeatureFlagSpec) EvaluateFor(agent string) bool {
+	if v, ok := s.Agents[agent]; ok {
+		return v
+	}
+	return s.Enabled
+}
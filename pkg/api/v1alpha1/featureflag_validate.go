@@ -0,0 +1,16 @@
+package v1alpha1
+
+func (f *FeatureFlag) Validate() error {
+	var errs FieldErrors
+	errs = append(errs, ValidateObjectMeta(f.Metadata)...)
+	validateMaxItems(&errs, "spec.agents", len(f.Spec.Agents), MaxFlagOverrides)
+	for agent := range f.Spec.Agents {
+		if err := validateNameField(agent); err != nil {
+			errs.Append("spec.agents["+agent+"]", err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
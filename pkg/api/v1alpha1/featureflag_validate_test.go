@@ -0,0 +1,46 @@
+package v1alpha1
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlagValidate(t *testing.T) {
+	tooMany := make(map[string]bool, MaxFlagOverrides+1)
+	for i := range MaxFlagOverrides + 1 {
+		tooMany["agent-"+strconv.Itoa(i)] = true
+	}
+	tests := []struct {
+		name    string
+		spec    FeatureFlagSpec
+		wantErr string
+	}{
+		{name: "default only", spec: FeatureFlagSpec{Enabled: true}},
+		{name: "agent overrides", spec: FeatureFlagSpec{Agents: map[string]bool{"summarizer": true}}},
+		{name: "invalid agent name", spec: FeatureFlagSpec{Agents: map[string]bool{"Not A Name!": true}}, wantErr: "spec.agents[Not A Name!]"},
+		{name: "too many overrides", spec: FeatureFlagSpec{Agents: tooMany}, wantErr: "spec.agents"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &FeatureFlag{
+				TypeMeta: TypeMeta{APIVersion: GroupVersion, Kind: KindFeatureFlag},
+				Metadata: ObjectMeta{Namespace: "default", Name: "new-search-tool"},
+				Spec:     tt.spec,
+			}
+			err := f.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFeatureFlagSpec_EvaluateFor(t *testing.T) {
+	spec := FeatureFlagSpec{Enabled: true, Agents: map[string]bool{"summarizer": false}}
+	require.False(t, spec.EvaluateFor("summarizer"))
+	require.True(t, spec.EvaluateFor("translator"))
+}
@@ -0,0 +1,78 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultHeaderSecretKey is the key a header template reads when its
+// reference names only the secret: {{secret "github-token"}} reads
+// github-token/value.
+const DefaultHeaderSecretKey = "value"
+
+// headerSecretPattern matches one {{secret "<secret>[/<key>]"}} placeholder
+// in an HTTPHeader value.
+var headerSecretPattern = regexp.MustCompile(`\{\{\s*secret\s+"([^"]*)"\s*\}\}`)
+
+// secretKeyPattern is the key format Kubernetes accepts in a Secret's data.
+var secretKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// HeaderSecretRef is a secret an HTTPHeader value template reads.
+type HeaderSecretRef struct {
+	Secret string
+	Key    string
+}
+
+func (r HeaderSecretRef) String() string {
+	return r.Secret + "/" + r.Key
+}
+
+// HeaderSecretRefs returns the secrets a header value template reads, in
+// order. A value without placeholders reads none. Anything that opens a
+// template but isn't a well-formed {{secret "..."}} placeholder is an error,
+// so a typo fails at publish rather than reaching the remote verbatim.
+func HeaderSecretRefs(value string) ([]HeaderSecretRef, error) {
+	var refs []HeaderSecretRef
+	for _, m := range headerSecretPattern.FindAllStringSubmatch(value, -1) {
+		secret, key, found := strings.Cut(m[1], "/")
+		if !found {
+			key = DefaultHeaderSecretKey
+		}
+		if validateNameField(secret) != nil || !secretKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("%w: %q must reference <secret> or <secret>/<key>", ErrInvalidFormat, m[0])
+		}
+		refs = append(refs, HeaderSecretRef{Secret: secret, Key: key})
+	}
+	if strings.Contains(headerSecretPattern.ReplaceAllString(value, ""), "{{") {
+		return nil, fmt.Errorf(`%w: header templates support only {{secret "<secret>[/<key>]"}}`, ErrInvalidFormat)
+	}
+	return refs, nil
+}
+
+// ExpandHeaderTemplate replaces each {{secret "..."}} placeholder in value
+// with what lookup returns for it.
+func ExpandHeaderTemplate(value string, lookup func(HeaderSecretRef) (string, error)) (string, error) {
+	refs, err := HeaderSecretRefs(value)
+	if err != nil || len(refs) == 0 {
+		return value, err
+	}
+	i := 0
+	var lookupErr error
+	out := headerSecretPattern.ReplaceAllStringFunc(value, func(string) string {
+		ref := refs[i]
+		i++
+		if lookupErr != nil {
+			return ""
+		}
+		resolved, err := lookup(ref)
+		if err != nil {
+			lookupErr = fmt.Errorf("secret %s: %w", ref, err)
+		}
+		return resolved
+	})
+	if lookupErr != nil {
+		return "", lookupErr
+	}
+	return out, nil
+}
@@ -0,0 +1,49 @@
+package v1alpha1
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderSecretRefs(t *testing.T) {
+	refs, err := HeaderSecretRefs(`Bearer {{secret "github-token/token"}}; org={{ secret "github-org" }}`)
+	require.NoError(t, err)
+	require.Equal(t, []HeaderSecretRef{
+		{Secret: "github-token", Key: "token"},
+		{Secret: "github-org", Key: DefaultHeaderSecretKey},
+	}, refs)
+
+	refs, err = HeaderSecretRefs("Bearer static")
+	require.NoError(t, err)
+	require.Empty(t, refs)
+
+	for _, value := range []string{
+		`{{env "TOKEN"}}`,
+		`{{secret "Bad_Name/token"}}`,
+		`{{secret "github-token/bad key"}}`,
+		`Bearer {{secret "github-token"`,
+	} {
+		_, err := HeaderSecretRefs(value)
+		require.ErrorIs(t, err, ErrInvalidFormat, value)
+	}
+}
+
+func TestExpandHeaderTemplate(t *testing.T) {
+	secrets := map[string]string{"github-token/token": "ghp_123", "github-org/value": "acme"}
+	lookup := func(ref HeaderSecretRef) (string, error) {
+		value, ok := secrets[ref.String()]
+		if !ok {
+			return "", errors.New("not found")
+		}
+		return value, nil
+	}
+
+	got, err := ExpandHeaderTemplate(`Bearer {{secret "github-token/token"}} {{secret "github-org"}}`, lookup)
+	require.NoError(t, err)
+	require.Equal(t, "Bearer ghp_123 acme", got)
+
+	_, err = ExpandHeaderTemplate(`Bearer {{secret "missing"}}`, lookup)
+	require.ErrorContains(t, err, "secret missing/value: not found")
+}
@@ -0,0 +1,85 @@
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Size and count limits enforced on apply. Each one is also declared as a
+// maxItems / maxProperties / maxLength tag on the field it bounds so the
+// OpenAPI schema (and clients generated from it) carry the same limit;
+// TestLimitTagsMatchConstants keeps the two in sync.
+const (
+	// MaxSpecBytes caps the JSON encoding of any object's spec.
+	MaxSpecBytes = 512 << 10
+	// MaxEnvVars caps environment variables on an MCP package launch and
+	// on a Deployment.
+	MaxEnvVars = 100
+	// MaxArgs caps arguments on an MCP package launch.
+	MaxArgs = 100
+	// MaxHeaders caps headers on a remote MCP server.
+	MaxHeaders = 50
+	// MaxOAuthScopes caps the OAuth scopes a remote MCP server declares.
+	MaxOAuthScopes = 50
+	// MaxRefs caps each list of resource references on an Agent
+	// (mcpServers, plugins, skills, charts) and its declared secrets.
+	MaxRefs = 100
+	// MaxPromptContentLength caps a Prompt's inline content, in characters.
+	MaxPromptContentLength = 256 << 10
+	// MaxPromptEvalCases caps the cases of a Prompt's evaluation.
+	MaxPromptEvalCases = 50
+	// MaxPromptAssertions caps the assertions of one evaluation case.
+	MaxPromptAssertions = 20
+	// MaxReadmeLength caps an MCP server's README, in characters.
+	MaxReadmeLength = 64 << 10
+	// MaxIconURLLength caps the icon URL of an MCP server, agent or
+	// skill, in characters.
+	MaxIconURLLength = 2048
+	// MaxIconBytes caps an uploaded icon image.
+	MaxIconBytes = 256 << 10
+	// MaxFlagOverrides caps the per-agent overrides on a FeatureFlag.
+	MaxFlagOverrides = 100
+	// MaxAgentGPUs caps the GPUs one agent may reserve.
+	MaxAgentGPUs = 16
+	// MaxAgentDevices caps the host devices mapped into one agent.
+	MaxAgentDevices = 16
+	// MaxPlatforms caps the platforms an agent image or OCI package
+	// declares.
+	MaxPlatforms = 20
+	// MaxRuntimeSelectorLabels caps the labels a Deployment's
+	// runtimeSelector matches on.
+	MaxRuntimeSelectorLabels = 20
+	// MaxCostAllocationTags caps a Runtime's fixed cost-allocation tags
+	// and the Deployment labels it copies into them.
+	MaxCostAllocationTags = 20
+)
+
+// ErrLimitExceeded marks a FieldError raised by one of the limits above.
+// The resource handlers answer 422 when a validation pass contains one.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// validateMaxItems reports n > max at path.
+func validateMaxItems(errs *FieldErrors, path string, n, max int) {
+	if n > max {
+		errs.Append(path, fmt.Errorf("%w: %d items (max %d)", ErrLimitExceeded, n, max))
+	}
+}
+
+// validateMaxLength reports a string longer than max characters at path.
+func validateMaxLength(errs *FieldErrors, path, s string, max int) {
+	if n := utf8.RuneCountInString(s); n > max {
+		errs.Append(path, fmt.Errorf("%w: %d characters (max %d)", ErrLimitExceeded, n, max))
+	}
+}
+
+// validateSpecSize reports a spec whose JSON encoding exceeds MaxSpecBytes.
+func validateSpecSize(obj Object) FieldErrors {
+	spec, err := obj.MarshalSpec()
+	if err != nil || len(spec) <= MaxSpecBytes {
+		return nil
+	}
+	var errs FieldErrors
+	errs.Append("spec", fmt.Errorf("%w: %d bytes (max %d)", ErrLimitExceeded, len(spec), MaxSpecBytes))
+	return errs
+}
@@ -0,0 +1,136 @@
+package v1alpha1
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitTagsMatchConstants(t *testing.T) {
+	cases := []struct {
+		typ   any
+		field string
+		tag   string
+		want  int
+	}{
+		{AgentSpec{}, "MCPServers", "maxItems", MaxRefs},
+		{AgentSpec{}, "Plugins", "maxItems", MaxRefs},
+		{AgentSpec{}, "Skills", "maxItems", MaxRefs},
+		{AgentSpec{}, "Charts", "maxItems", MaxRefs},
+		{AgentSpec{}, "Secrets", "maxItems", MaxRefs},
+		{AgentResources{}, "GPUs", "maximum", MaxAgentGPUs},
+		{AgentResources{}, "Devices", "maxItems", MaxAgentDevices},
+		{MCPRemote{}, "Headers", "maxItems", MaxHeaders},
+		{MCPRemoteOAuth{}, "Scopes", "maxItems", MaxOAuthScopes},
+		{RuntimeSelector{}, "MatchLabels", "maxProperties", MaxRuntimeSelectorLabels},
+		{CostAllocation{}, "Tags", "maxProperties", MaxCostAllocationTags},
+		{CostAllocation{}, "DeploymentLabels", "maxItems", MaxCostAllocationTags},
+		{AgentSource{}, "Platforms", "maxItems", MaxPlatforms},
+		{MCPPackageOriginOCI{}, "Platforms", "maxItems", MaxPlatforms},
+		{MCPPackageLaunch{}, "Args", "maxItems", MaxArgs},
+		{MCPPackageLaunch{}, "Env", "maxItems", MaxEnvVars},
+		{DeploymentSpec{}, "Env", "maxProperties", MaxEnvVars},
+		{DeploymentDefaults{}, "Env", "maxProperties", MaxEnvVars},
+		{DeploymentTemplateSpec{}, "Env", "maxProperties", MaxEnvVars},
+		{PromptSpec{}, "Content", "maxLength", MaxPromptContentLength},
+		{PromptEvaluation{}, "Cases", "maxItems", MaxPromptEvalCases},
+		{PromptEvalCase{}, "Assertions", "maxItems", MaxPromptAssertions},
+		{MCPServerSpec{}, "Readme", "maxLength", MaxReadmeLength},
+		{MCPServerSpec{}, "Icon", "maxLength", MaxIconURLLength},
+		{AgentSpec{}, "Icon", "maxLength", MaxIconURLLength},
+		{SkillSpec{}, "Icon", "maxLength", MaxIconURLLength},
+		{FeatureFlagSpec{}, "Agents", "maxProperties", MaxFlagOverrides},
+	}
+	for _, tc := range cases {
+		f, ok := reflect.TypeOf(tc.typ).FieldByName(tc.field)
+		require.True(t, ok, "%T.%s", tc.typ, tc.field)
+		got, err := strconv.Atoi(f.Tag.Get(tc.tag))
+		require.NoError(t, err, "%T.%s missing %s tag", tc.typ, tc.field, tc.tag)
+		require.Equal(t, tc.want, got, "%T.%s %s", tc.typ, tc.field, tc.tag)
+	}
+}
+
+func TestValidateObject_Limits(t *testing.T) {
+	env := make(map[string]string, MaxEnvVars+1)
+	for i := range MaxEnvVars + 1 {
+		env["VAR_"+strconv.Itoa(i)] = "x"
+	}
+	headers := make([]HTTPHeader, MaxHeaders+1)
+	for i := range headers {
+		headers[i] = HTTPHeader{Name: "X-H" + strconv.Itoa(i)}
+	}
+
+	cases := []struct {
+		name string
+		obj  Object
+		want []string
+	}{
+		{
+			name: "deployment env",
+			obj: &Deployment{
+				Metadata: ObjectMeta{Namespace: "default", Name: "prod"},
+				Spec: DeploymentSpec{
+					TargetRef:  ResourceRef{Kind: KindAgent, Name: "alice"},
+					RuntimeRef: ResourceRef{Kind: KindRuntime, Name: "local"},
+					Env:        env,
+				},
+			},
+			want: []string{"spec.env"},
+		},
+		{
+			name: "remote headers",
+			obj: &MCPServer{
+				Metadata: ObjectMeta{Namespace: "default", Name: "weather"},
+				Spec:     MCPServerSpec{Remote: &MCPRemote{Type: "streamable-http", URL: "https://example.com/mcp", Headers: headers}},
+			},
+			want: []string{"spec.remote.headers"},
+		},
+		{
+			name: "agent refs",
+			obj: &Agent{
+				Metadata: ObjectMeta{Namespace: "default", Name: "alice"},
+				Spec:     AgentSpec{MCPServers: make([]ResourceRef, MaxRefs+1)},
+			},
+			want: []string{"spec.mcpServers"},
+		},
+		{
+			name: "prompt content",
+			obj: &Prompt{
+				Metadata: ObjectMeta{Namespace: "default", Name: "system"},
+				Spec:     PromptSpec{Content: strings.Repeat("é", MaxPromptContentLength+1)},
+			},
+			want: []string{"spec.content", "spec"},
+		},
+		{
+			name: "spec size",
+			obj: &Prompt{
+				Metadata: ObjectMeta{Namespace: "default", Name: "system"},
+				Spec:     PromptSpec{Description: strings.Repeat("x", MaxSpecBytes)},
+			},
+			want: []string{"spec"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateObject(tc.obj)
+			require.ErrorIs(t, err, ErrLimitExceeded)
+			var limited []string
+			var fe FieldErrors
+			require.ErrorAs(t, err, &fe)
+			for _, e := range fe {
+				if e.Cause != nil && strings.Contains(e.Cause.Error(), ErrLimitExceeded.Error()) {
+					limited = append(limited, e.Path)
+				}
+			}
+			require.ElementsMatch(t, tc.want, limited)
+		})
+	}
+
+	require.NoError(t, ValidateObject(&Prompt{
+		Metadata: ObjectMeta{Namespace: "default", Name: "system"},
+		Spec:     PromptSpec{Content: strings.Repeat("x", MaxPromptContentLength)},
+	}))
+}
@@ -17,6 +17,10 @@ type MCPServerSpec struct {
 	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 
+	// Icon is the URL of the logo catalogs show for the server. An icon
+	// uploaded through PUT /v0/mcpservers/{name}/icon takes precedence.
+	Icon string `json:"icon,omitempty" yaml:"icon,omitempty" maxLength:"2048"`
+
 	// Source declares where the bundled MCP server comes from — Package (the
 	// runnable distribution) and/or Repository (the source code).
 	Source *MCPServerSource `json:"source,omitempty" yaml:"source,omitempty"`
@@ -24,6 +28,22 @@ type MCPServerSpec struct {
 	// Remote declares a remote MCP server instead of a bundled one. These are pre-existing
 	// MCP servers that the registry does not deploy but can be referenced by Agents.
 	Remote *MCPRemote `json:"remote,omitempty" yaml:"remote,omitempty"`
+
+	// Tools lists the tools the server reports from tools/list, e.g. as
+	// recorded by `arctl mcp add-remote`. Informational: deployments don't
+	// check it against the running server.
+	Tools []MCPTool `json:"tools,omitempty" yaml:"tools,omitempty"`
+
+	// Readme is the server's Markdown documentation. It counts toward the
+	// documentation score (see ScoreMCPServerDocs) and is served as-is.
+	Readme string `json:"readme,omitempty" yaml:"readme,omitempty" maxLength:"65536"`
+}
+
+// MCPTool is one tool an MCP server exposes.
+type MCPTool struct {
+	Name        string         `json:"name" yaml:"name"`
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	InputSchema map[string]any `json:"inputSchema,omitempty" yaml:"inputSchema,omitempty"`
 }
 
 // MCPRemote describes a pre-running remote MCP server that the registry
@@ -33,10 +53,48 @@ type MCPServerSpec struct {
 type MCPRemote struct {
 	Type    string       `json:"type" yaml:"type"`
 	URL     string       `json:"url" yaml:"url"`
-	Headers []HTTPHeader `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Headers []HTTPHeader `json:"headers,omitempty" yaml:"headers,omitempty" maxItems:"50"`
+	// OAuth marks the server as an OAuth protected resource and carries its
+	// protected resource metadata (RFC 9728), so clients can discover where
+	// to get a token. Gateways forward the caller's bearer token to it.
+	OAuth *MCPRemoteOAuth `json:"oauth,omitempty" yaml:"oauth,omitempty"`
+}
+
+// RemoteUnverifiedAnnotation marks an MCPServer version whose remote URL
+// did not answer when the registry last probed it; the value is the probe
+// error. The registry sets and clears it when remote probing is enabled,
+// and /v0/search leaves annotated versions out by default.
+const RemoteUnverifiedAnnotation = "agentregistry.solo.io/remote-unverified"
+
+// SkipReadmeFetchAnnotation set to "true" keeps the registry from filling in
+// an empty spec.readme from the server's GitHub repository on publish.
+const SkipReadmeFetchAnnotation = "agentregistry.solo.io/skip-readme-fetch"
+
+// MCPRemoteOAuth is the OAuth protected resource metadata of a remote MCP
+// server.
+type MCPRemoteOAuth struct {
+	// Issuer is the authorization server's issuer identifier: an https URL
+	// without query or fragment.
+	Issuer string `json:"issuer" yaml:"issuer"`
+	// Scopes are the scopes the server accepts.
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty" maxItems:"50"`
+	// Resource is the resource indicator (RFC 8707) tokens are requested
+	// for. Defaults to the remote URL.
+	Resource string `json:"resource,omitempty" yaml:"resource,omitempty"`
+}
+
+// ResourceIndicator returns the remote's OAuth resource indicator, defaulting to the
+// remote URL.
+func (r *MCPRemote) ResourceIndicator() string {
+	if r.OAuth != nil && r.OAuth.Resource != "" {
+		return r.OAuth.Resource
+	}
+	return r.URL
 }
 
 // HTTPHeader is an HTTP header sent on requests to a remote MCP server.
+// Value may embed {{secret "<secret>[/<key>]"}} placeholders, which the
+// runtime fills in from its secret store when the server is deployed.
 type HTTPHeader struct {
 	Name  string `json:"name" yaml:"name"`
 	Value string `json:"value,omitempty" yaml:"value,omitempty"`
@@ -113,6 +171,9 @@ type MCPPackageOriginPyPI struct {
 // validator.
 type MCPPackageOriginOCI struct {
 	ServerName string `json:"serverName" yaml:"serverName"`
+	// Platforms lists the os/arch[/variant] pairs the image is published
+	// for, e.g. linux/amd64 and linux/arm64. Empty means any platform.
+	Platforms []string `json:"platforms,omitempty" yaml:"platforms,omitempty" maxItems:"20"`
 }
 
 // MCPPackageLaunch declares how to start the fetched package. If Launch
@@ -123,8 +184,8 @@ type MCPPackageOriginOCI struct {
 // for oci.
 type MCPPackageLaunch struct {
 	Command string             `json:"command,omitempty" yaml:"command,omitempty"`
-	Args    []MCPArgument      `json:"args,omitempty" yaml:"args,omitempty"`
-	Env     []MCPKeyValueInput `json:"env,omitempty" yaml:"env,omitempty"`
+	Args    []MCPArgument      `json:"args,omitempty" yaml:"args,omitempty" maxItems:"100"`
+	Env     []MCPKeyValueInput `json:"env,omitempty" yaml:"env,omitempty" maxItems:"100"`
 }
 
 // MCPArgument is one command-line argument.
@@ -1,6 +1,11 @@
 package v1alpha1
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
 
 // Validate runs structural validation on the MCPServer envelope.
 func (m *MCPServer) Validate() error {
@@ -33,6 +38,7 @@ func validateMCPPackageName(s string) error {
 func validateMCPServerSpec(s *MCPServerSpec) FieldErrors {
 	var errs FieldErrors
 	errs.Append("spec.title", validateTitle(s.Title))
+	validateIcon(&errs, "spec.icon", s.Icon)
 
 	// Source (bundled) and Remote (pre-running) are the two ways to describe
 	// an MCP server. Exactly one must be set.
@@ -46,10 +52,28 @@ func validateMCPServerSpec(s *MCPServerSpec) FieldErrors {
 	case s.Remote != nil:
 		errs = append(errs, validateMCPServerRemote(s.Remote)...)
 	}
+	errs = append(errs, validateMCPTools(s.Tools)...)
+	validateMaxLength(&errs, "spec.readme", s.Readme, MaxReadmeLength)
 
 	return errs
 }
 
+func validateMCPTools(tools []MCPTool) FieldErrors {
+	var errs FieldErrors
+	seen := make(map[string]bool, len(tools))
+	for i, tool := range tools {
+		path := fmt.Sprintf("spec.tools[%d].name", i)
+		switch {
+		case tool.Name == "":
+			errs.Append(path, fmt.Errorf("%w", ErrRequiredField))
+		case seen[tool.Name]:
+			errs.Append(path, fmt.Errorf("%w: duplicate tool %q", ErrInvalidFormat, tool.Name))
+		}
+		seen[tool.Name] = true
+	}
+	return errs
+}
+
 func validateMCPServerRemote(t *MCPRemote) FieldErrors {
 	var errs FieldErrors
 	if t.Type == "" {
@@ -62,9 +86,58 @@ func validateMCPServerRemote(t *MCPRemote) FieldErrors {
 	if err := validateWebsiteURL(t.URL); err != nil {
 		errs.Append("spec.remote.url", err)
 	}
+	validateMaxItems(&errs, "spec.remote.headers", len(t.Headers), MaxHeaders)
+	for i, h := range t.Headers {
+		if _, err := HeaderSecretRefs(h.Value); err != nil {
+			errs.Append(fmt.Sprintf("spec.remote.headers[%d].value", i), err)
+		}
+	}
+	if t.OAuth != nil {
+		errs = append(errs, validateMCPRemoteOAuth(t)...)
+	}
+	return errs
+}
+
+// validateMCPRemoteOAuth checks a remote's OAuth metadata. A static
+// Authorization header would be sent instead of the caller's token, so the
+// two are exclusive.
+func validateMCPRemoteOAuth(t *MCPRemote) FieldErrors {
+	var errs FieldErrors
+	o := t.OAuth
+	if o.Issuer == "" {
+		errs.Append("spec.remote.oauth.issuer", fmt.Errorf("%w", ErrRequiredField))
+	} else if u, err := url.Parse(o.Issuer); err != nil || u.Scheme != "https" || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		errs.Append("spec.remote.oauth.issuer", fmt.Errorf("%w: must be an https URL without query or fragment", ErrInvalidURL))
+	}
+	if o.Resource != "" {
+		if u, err := url.Parse(o.Resource); err != nil || !u.IsAbs() || u.Fragment != "" {
+			errs.Append("spec.remote.oauth.resource", fmt.Errorf("%w: must be an absolute URL without fragment", ErrInvalidURL))
+		}
+	}
+	validateMaxItems(&errs, "spec.remote.oauth.scopes", len(o.Scopes), MaxOAuthScopes)
+	seen := make(map[string]bool, len(o.Scopes))
+	for i, scope := range o.Scopes {
+		path := fmt.Sprintf("spec.remote.oauth.scopes[%d]", i)
+		switch {
+		case !oauthScopePattern.MatchString(scope):
+			errs.Append(path, fmt.Errorf("%w: scope %q must be printable ASCII without spaces, quotes or backslashes", ErrInvalidFormat, scope))
+		case seen[scope]:
+			errs.Append(path, fmt.Errorf("%w: duplicate scope %q", ErrInvalidFormat, scope))
+		}
+		seen[scope] = true
+	}
+	for i, h := range t.Headers {
+		if strings.EqualFold(h.Name, "Authorization") {
+			errs.Append(fmt.Sprintf("spec.remote.headers[%d]", i),
+				fmt.Errorf("%w: an OAuth remote receives the caller's token; drop the static Authorization header", ErrInvalidFormat))
+		}
+	}
 	return errs
 }
 
+// oauthScopePattern is the scope-token grammar of RFC 6749 section 3.3.
+var oauthScopePattern = regexp.MustCompile(`^[\x21\x23-\x5B\x5D-\x7E]+$`)
+
 func validateMCPServerSource(src *MCPServerSource) FieldErrors {
 	var errs FieldErrors
 	for _, e := range validateRepository(src.Repository) {
@@ -99,6 +172,10 @@ func validateMCPServerSource(src *MCPServerSource) FieldErrors {
 	}
 
 	errs = append(errs, validateMCPPackageOrigin(pkg.Origin)...)
+	if pkg.Launch != nil {
+		validateMaxItems(&errs, "spec.source.package.launch.args", len(pkg.Launch.Args), MaxArgs)
+		validateMaxItems(&errs, "spec.source.package.launch.env", len(pkg.Launch.Env), MaxEnvVars)
+	}
 	return errs
 }
 
@@ -172,6 +249,7 @@ func validateMCPPackageOrigin(o MCPPackageOrigin) FieldErrors {
 		if err := validateMCPPackageName(o.OCI.ServerName); err != nil {
 			errs.Append("spec.source.package.origin.oci.serverName", err)
 		}
+		validatePlatforms(&errs, "spec.source.package.origin.oci.platforms", o.OCI.Platforms)
 	case "":
 		// Already flagged as ErrRequiredField on origin.type — no further checks.
 	default:
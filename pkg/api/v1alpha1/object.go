@@ -18,6 +18,40 @@ type TypeMeta struct {
 // at rest. The string matches the Kubernetes convention for consistency.
 const DefaultNamespace = "default"
 
+// Version annotations publishers set on a tagged-artifact row to inform
+// upgrade planning (GET /v0/deployments/outdated). Both are free text;
+// re-applying the same tag with updated annotations replaces them.
+const (
+	// DeprecatedAnnotation marks a version deprecated; the value is the
+	// notice shown to owners of Deployments still pinned to it.
+	DeprecatedAnnotation = "agentregistry.solo.io/deprecated"
+	// ChangelogAnnotation summarizes what changed in a version. A note
+	// containing "BREAKING" raises the upgrade severity for anyone behind it.
+	ChangelogAnnotation = "agentregistry.solo.io/changelog"
+)
+
+// Security annotations maintained by the /v0/security/vulnerabilities
+// endpoints.
+const (
+	// VulnerableAnnotation marks an MCPServer or Skill version vulnerable;
+	// the value is the advisory.
+	VulnerableAnnotation = "agentregistry.solo.io/vulnerable"
+	// VulnerableDependenciesAnnotation flags Agent versions and Deployments
+	// that reach a vulnerable version. The value is a sorted, comma-separated
+	// list of "Kind/namespace/name@tag" references.
+	VulnerableDependenciesAnnotation = "agentregistry.solo.io/vulnerable-dependencies"
+)
+
+// Tunnel metadata `arctl deployment expose` sets on the tag it publishes
+// while a Local Deployment is reachable through a public tunnel.
+const (
+	// EphemeralLabel marks a tag that exists only while its publisher runs
+	// and is deleted when it exits.
+	EphemeralLabel = "agentregistry.solo.io/ephemeral"
+	// TunnelURLAnnotation is the public URL the exposed Deployment answers on.
+	TunnelURLAnnotation = "agentregistry.solo.io/tunnel-url"
+)
+
 // ObjectMeta is the metadata block common to every resource.
 //
 // Namespace, Name, Labels, Annotations, and Tag are user-settable. Tag is
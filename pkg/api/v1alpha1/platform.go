@@ -0,0 +1,28 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// platformPattern is an OCI image platform: os/arch with an optional
+// variant, lowercase, as in linux/amd64 or linux/arm/v7.
+var platformPattern = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+
+// validatePlatforms checks a list of image platforms at path: well-formed,
+// without duplicates and at most MaxPlatforms.
+func validatePlatforms(errs *FieldErrors, path string, platforms []string) {
+	validateMaxItems(errs, path, len(platforms), MaxPlatforms)
+	seen := make(map[string]struct{}, len(platforms))
+	for i, platform := range platforms {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		if !platformPattern.MatchString(platform) {
+			errs.Append(itemPath, fmt.Errorf("%w: %q is not an os/arch[/variant] platform", ErrInvalidFormat, platform))
+			continue
+		}
+		if _, dup := seen[platform]; dup {
+			errs.Append(itemPath, fmt.Errorf("%w: duplicate platform %q", ErrInvalidFormat, platform))
+		}
+		seen[platform] = struct{}{}
+	}
+}
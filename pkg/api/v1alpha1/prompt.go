@@ -17,5 +17,43 @@ func init() {
 // a Skill resource instead.
 type PromptSpec struct {
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
-	Content     string `json:"content,omitempty" yaml:"content,omitempty"`
+	Content     string `json:"content,omitempty" yaml:"content,omitempty" maxLength:"262144"`
+	// Evaluation, when set, has the registry run the prompt against its
+	// configured model each time a tag is published and score the answers,
+	// so a regression shows before the tag is promoted.
+	Evaluation *PromptEvaluation `json:"evaluation,omitempty" yaml:"evaluation,omitempty"`
+}
+
+// PromptEvaluation is the test suite a Prompt is scored against.
+type PromptEvaluation struct {
+	Cases []PromptEvalCase `json:"cases" yaml:"cases" maxItems:"50"`
+}
+
+// PromptEvalCase runs the prompt once. Every {{name}} in the content is
+// replaced by Variables[name]. With Input set, the rendered content is
+// sent as the system message and Input as the user message; without it,
+// the rendered content is the user message.
+//
+// The answer scores the fraction of Assertions it passes. A Rubric is
+// graded 0-10 by the same model and counts as one more check worth its
+// grade.
+type PromptEvalCase struct {
+	Name       string            `json:"name" yaml:"name"`
+	Variables  map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Input      string            `json:"input,omitempty" yaml:"input,omitempty"`
+	Assertions []PromptAssertion `json:"assertions,omitempty" yaml:"assertions,omitempty" maxItems:"20"`
+	Rubric     string            `json:"rubric,omitempty" yaml:"rubric,omitempty"`
+}
+
+// PromptAssertion checks an answer. Exactly one field is set.
+type PromptAssertion struct {
+	// Contains passes when the answer contains the text, ignoring case.
+	Contains string `json:"contains,omitempty" yaml:"contains,omitempty"`
+	// NotContains passes when the answer does not contain the text,
+	// ignoring case.
+	NotContains string `json:"notContains,omitempty" yaml:"notContains,omitempty"`
+	// Matches passes when the regular expression matches the answer.
+	Matches string `json:"matches,omitempty" yaml:"matches,omitempty"`
+	// MaxLength passes when the answer has at most this many characters.
+	MaxLength int `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
 }
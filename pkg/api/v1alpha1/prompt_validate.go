@@ -1,13 +1,74 @@
 package v1alpha1
 
+import (
+	"fmt"
+	"regexp"
+)
+
 func (p *Prompt) Validate() error {
 	var errs FieldErrors
 	errs = append(errs, ValidateObjectMeta(p.Metadata)...)
 	// PromptSpec has minimal structure (Description + Content). Content
 	// MAY be empty (a prompt can be purely descriptive), so we don't
 	// require it here.
+	validateMaxLength(&errs, "spec.content", p.Spec.Content, MaxPromptContentLength)
+	validatePromptEvaluation(&errs, "spec.evaluation", p.Spec.Evaluation)
 	if len(errs) == 0 {
 		return nil
 	}
 	return errs
 }
+
+// validatePromptEvaluation checks that every case is named uniquely and
+// checks something, and that every assertion sets exactly one field.
+func validatePromptEvaluation(errs *FieldErrors, path string, e *PromptEvaluation) {
+	if e == nil {
+		return
+	}
+	if len(e.Cases) == 0 {
+		errs.Append(path+".cases", fmt.Errorf("%w: at least one case", ErrRequiredField))
+		return
+	}
+	validateMaxItems(errs, path+".cases", len(e.Cases), MaxPromptEvalCases)
+	seen := map[string]bool{}
+	for i, c := range e.Cases {
+		casePath := fmt.Sprintf("%s.cases[%d]", path, i)
+		switch {
+		case c.Name == "":
+			errs.Append(casePath+".name", ErrRequiredField)
+		case seen[c.Name]:
+			errs.Append(casePath+".name", fmt.Errorf("%w: duplicate case name %q", ErrInvalidFormat, c.Name))
+		}
+		seen[c.Name] = true
+		if len(c.Assertions) == 0 && c.Rubric == "" {
+			errs.Append(casePath, fmt.Errorf("%w: assertions or a rubric", ErrRequiredField))
+		}
+		validateMaxItems(errs, casePath+".assertions", len(c.Assertions), MaxPromptAssertions)
+		for j, a := range c.Assertions {
+			if err := validatePromptAssertion(a); err != nil {
+				errs.Append(fmt.Sprintf("%s.assertions[%d]", casePath, j), err)
+			}
+		}
+	}
+}
+
+func validatePromptAssertion(a PromptAssertion) error {
+	set := 0
+	for _, ok := range []bool{a.Contains != "", a.NotContains != "", a.Matches != "", a.MaxLength != 0} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("%w: set exactly one of contains, notContains, matches, maxLength", ErrInvalidFormat)
+	}
+	if a.MaxLength < 0 {
+		return fmt.Errorf("%w: maxLength must be positive", ErrInvalidFormat)
+	}
+	if a.Matches != "" {
+		if _, err := regexp.Compile(a.Matches); err != nil {
+			return fmt.Errorf("%w: matches: %v", ErrInvalidFormat, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptValidate_Evaluation(t *testing.T) {
+	prompt := func(eval *PromptEvaluation) *Prompt {
+		return &Prompt{
+			Metadata: ObjectMeta{Namespace: "default", Name: "summarizer", Tag: "1.0.0"},
+			Spec:     PromptSpec{Content: "Summarize {{article}}", Evaluation: eval},
+		}
+	}
+	valid := &PromptEvaluation{Cases: []PromptEvalCase{
+		{Name: "short", Variables: map[string]string{"article": "..."}, Assertions: []PromptAssertion{{Contains: "summary"}, {MaxLength: 500}}},
+		{Name: "faithful", Rubric: "States only facts from the article."},
+	}}
+	require.NoError(t, prompt(nil).Validate())
+	require.NoError(t, prompt(valid).Validate())
+
+	cases := []struct {
+		name string
+		eval *PromptEvaluation
+		path string
+	}{
+		{"no cases", &PromptEvaluation{}, "spec.evaluation.cases"},
+		{"unnamed case", &PromptEvaluation{Cases: []PromptEvalCase{{Rubric: "x"}}}, "spec.evaluation.cases[0].name"},
+		{"duplicate name", &PromptEvaluation{Cases: []PromptEvalCase{{Name: "a", Rubric: "x"}, {Name: "a", Rubric: "y"}}}, "spec.evaluation.cases[1].name"},
+		{"nothing checked", &PromptEvaluation{Cases: []PromptEvalCase{{Name: "a"}}}, "spec.evaluation.cases[0]"},
+		{"two fields", &PromptEvaluation{Cases: []PromptEvalCase{{Name: "a", Assertions: []PromptAssertion{{Contains: "x", Matches: "y"}}}}}, "spec.evaluation.cases[0].assertions[0]"},
+		{"bad regex", &PromptEvaluation{Cases: []PromptEvalCase{{Name: "a", Assertions: []PromptAssertion{{Matches: "("}}}}}, "spec.evaluation.cases[0].assertions[0]"},
+		{"negative length", &PromptEvaluation{Cases: []PromptEvalCase{{Name: "a", Assertions: []PromptAssertion{{MaxLength: -1}}}}}, "spec.evaluation.cases[0].assertions[0]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := prompt(tc.eval).Validate()
+			require.Error(t, err)
+			var errs FieldErrors
+			require.ErrorAs(t, err, &errs)
+			require.Equal(t, tc.path, errs[0].Path, err.Error())
+		})
+	}
+}
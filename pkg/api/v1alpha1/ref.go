@@ -8,11 +8,17 @@ package v1alpha1
 // object" (the common case). Tag is optional: blank means "resolve to the
 // literal latest tag" for taggable artifacts or "resolve by namespace/name"
 // for mutable object kinds.
+//
+// Registry is optional and only honoured on Agent spec.mcpServers: it names
+// a configured peer registry the ref resolves against instead of this one.
+// Namespace on a peer ref is the peer's namespace; blank means the peer's
+// default.
 type ResourceRef struct {
 	Kind      string `json:"kind" yaml:"kind"`
 	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 	Name      string `json:"name" yaml:"name"`
 	Tag       string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	Registry  string `json:"registry,omitempty" yaml:"registry,omitempty"`
 }
 
 // DeploymentRef is a typed reference to another Deployment resource. Kind
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 )
 
@@ -42,7 +43,7 @@ func ValidateNPM(ctx context.Context, origin v1alpha1.MCPPackageOrigin, serverNa
 		mirror = DefaultURLNPM
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpclient.New(10 * time.Second)
 
 	requestURL := mirror + "/" + url.PathEscape(origin.Identifier) + "/" + url.PathEscape(origin.NPM.Version)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 )
 
@@ -45,7 +46,7 @@ func ValidatePyPI(ctx context.Context, origin v1alpha1.MCPPackageOrigin, serverN
 		mirror = DefaultURLPyPI
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpclient.New(10 * time.Second)
 
 	requestURL := fmt.Sprintf("%s/pypi/%s/%s/json", mirror, url.PathEscape(origin.Identifier), url.PathEscape(origin.PyPI.Version))
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
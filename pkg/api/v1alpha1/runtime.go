@@ -1,5 +1,11 @@
 package v1alpha1
 
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
 // Runtime is the typed envelope for kind=Runtime resources. A Runtime
 // describes an execution target (local docker daemon, a Kubernetes
 // cluster, a hosted agent runtime) that Deployment resources reference
@@ -26,14 +32,131 @@ const (
 	TypeKubernetes = "Kubernetes"
 )
 
+// RuntimeSecretConfigKeys lists, per canonical Runtime type, the
+// spec.config keys that hold credentials. The registry encrypts their
+// values at rest and redacts them in API responses. Downstream builds
+// that register a Runtime type may add its keys at init.
+var RuntimeSecretConfigKeys = map[string][]string{
+	TypeKubernetes: {"kubeconfig"},
+}
+
+// IsSecretConfigKey reports whether key of the Runtime's spec.config
+// holds a credential; see RuntimeSecretConfigKeys.
+func (r *Runtime) IsSecretConfigKey(key string) bool {
+	for runtimeType, keys := range RuntimeSecretConfigKeys {
+		if strings.EqualFold(runtimeType, r.Spec.Type) && slices.Contains(keys, key) {
+			return true
+		}
+	}
+	return false
+}
+
 // RuntimeSpec describes a deployment target. Type is the discriminator;
 // Config carries type-specific configuration that downstream adapters
 // (internal/registry/runtimes/...) interpret. TelemetryEndpoint, when
 // set, is exported to every Deployment served by this Runtime as
 // OTEL_EXPORTER_OTLP_ENDPOINT on the workload — telemetry is a property
-// of where things run, not of an individual Deployment.
+// of where things run, not of an individual Deployment. RegistryURL is
+// likewise the registry's address as workloads on this Runtime reach it;
+// when set, every Agent served here gets its FeatureFlag evaluation URL as
+// AGENT_REGISTRY_FLAGS_URL.
 type RuntimeSpec struct {
 	Type              string         `json:"type" yaml:"type"`
 	Config            map[string]any `json:"config,omitempty" yaml:"config,omitempty"`
 	TelemetryEndpoint string         `json:"telemetryEndpoint,omitempty" yaml:"telemetryEndpoint,omitempty"`
+	RegistryURL       string         `json:"registryURL,omitempty" yaml:"registryURL,omitempty"`
+
+	// DeploymentDefaults are inherited by every Deployment on this Runtime.
+	DeploymentDefaults *DeploymentDefaults `json:"deploymentDefaults,omitempty" yaml:"deploymentDefaults,omitempty"`
+
+	// Capacity bounds how many Deployments the registry places here on
+	// behalf of a DeploymentSpec.RuntimeSelector.
+	Capacity *RuntimeCapacity `json:"capacity,omitempty" yaml:"capacity,omitempty"`
+
+	// CostAllocation tags what Deployments on this Runtime create, so
+	// cloud billing can charge it back.
+	CostAllocation *CostAllocation `json:"costAllocation,omitempty" yaml:"costAllocation,omitempty"`
+}
+
+// CostAllocationDeploymentIDTag is the cost-allocation tag carrying the
+// Deployment's name.
+const CostAllocationDeploymentIDTag = "deployment-id"
+
+// CostAllocation configures the cost-allocation tags of a Runtime's
+// Deployments: Tags, then the DeploymentLabels the Deployment sets, then
+// CostAllocationDeploymentIDTag. Adapters put them on the cloud resources
+// and Kubernetes objects they create, as labels where the target has no
+// tags, and record them on the Deployment in
+// DeploymentCostAllocationAnnotation. Tags follow Kubernetes label syntax,
+// which cloud billing tags accept as well.
+type CostAllocation struct {
+	// Tags are set on every Deployment, e.g. {"cost-center": "ml"}.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" maxProperties:"20"`
+	// DeploymentLabels names metadata.labels copied from each Deployment
+	// that sets them, e.g. ["team", "project"].
+	DeploymentLabels []string `json:"deploymentLabels,omitempty" yaml:"deploymentLabels,omitempty" maxItems:"20"`
+}
+
+// CostAllocationTags returns the cost-allocation tags of deployment on
+// runtime, or nil when runtime configures none.
+func CostAllocationTags(deployment *Deployment, runtime *Runtime) map[string]string {
+	if deployment == nil || runtime == nil || runtime.Spec.CostAllocation == nil {
+		return nil
+	}
+	c := runtime.Spec.CostAllocation
+	tags := make(map[string]string, len(c.Tags)+len(c.DeploymentLabels)+1)
+	maps.Copy(tags, c.Tags)
+	for _, key := range c.DeploymentLabels {
+		if value, ok := deployment.Metadata.Labels[key]; ok {
+			tags[key] = value
+		}
+	}
+	tags[CostAllocationDeploymentIDTag] = deployment.Metadata.Name
+	return tags
+}
+
+// RuntimeCapacity is what a Runtime can take on. MaxDeployments counts the
+// Deployments in the Runtime's namespace that reference it and aren't
+// undeployed; zero means unbounded. Deployments that name the Runtime in spec.runtimeRef are
+// never refused, they only use up room.
+type RuntimeCapacity struct {
+	MaxDeployments int `json:"maxDeployments,omitempty" yaml:"maxDeployments,omitempty" minimum:"0"`
+}
+
+// DeploymentDefaults carries the env and runtimeConfig a platform team
+// wants on every Deployment served by a Runtime — proxy settings, telemetry
+// tokens, a default replica count. A Deployment's own spec.env and
+// spec.runtimeConfig win key by key; see MergeDeploymentDefaults.
+type DeploymentDefaults struct {
+	Env           map[string]string `json:"env,omitempty" yaml:"env,omitempty" maxProperties:"100"`
+	RuntimeConfig map[string]any    `json:"runtimeConfig,omitempty" yaml:"runtimeConfig,omitempty"`
+}
+
+// MergeDeploymentDefaults returns spec with defaults filled in under it:
+// each env key and each top-level runtimeConfig key the Deployment leaves
+// unset comes from defaults. secretRef env defaults only reach Agent
+// Deployments, the only kind that resolves them. spec's maps are not
+// modified.
+func MergeDeploymentDefaults(spec DeploymentSpec, defaults *DeploymentDefaults) DeploymentSpec {
+	if defaults == nil {
+		return spec
+	}
+	if len(defaults.Env) > 0 {
+		env := make(map[string]string, len(spec.Env)+len(defaults.Env))
+		for key, value := range defaults.Env {
+			if strings.HasPrefix(value, SecretRefPrefix) && spec.TargetRef.Kind != KindAgent {
+				continue
+			}
+			env[key] = value
+		}
+		maps.Copy(env, spec.Env)
+		spec.Env = env
+	}
+	if len(defaults.RuntimeConfig) > 0 {
+		cfg := make(map[string]any, len(spec.RuntimeConfig)+len(defaults.RuntimeConfig))
+		maps.Copy(cfg, defaults.RuntimeConfig)
+		maps.Copy(cfg, spec.RuntimeConfig)
+		spec.RuntimeConfig = cfg
+	}
+	return spec
 }
@@ -2,6 +2,9 @@ package v1alpha1
 
 import (
 	"fmt"
+	"maps"
+	"net/url"
+	"slices"
 	"strings"
 )
 
@@ -44,6 +47,34 @@ func (r *Runtime) Validate() error {
 		errs.Append("spec.type",
 			fmt.Errorf("%w: %q (known: %v)", ErrUnknownRuntimeType, r.Spec.Type, knownRuntimeTypeNames()))
 	}
+	if r.Spec.RegistryURL != "" {
+		if u, err := url.Parse(r.Spec.RegistryURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.Append("spec.registryURL", fmt.Errorf("%w: %q", ErrInvalidURL, r.Spec.RegistryURL))
+		}
+	}
+	if d := r.Spec.DeploymentDefaults; d != nil {
+		validateDefaultEnv(&errs, "spec.deploymentDefaults.env", d.Env)
+	}
+	if c := r.Spec.Capacity; c != nil && c.MaxDeployments < 0 {
+		errs.Append("spec.capacity.maxDeployments", fmt.Errorf("%w: must not be negative", ErrInvalidFormat))
+	}
+	if c := r.Spec.CostAllocation; c != nil {
+		validateMaxItems(&errs, "spec.costAllocation.tags", len(c.Tags), MaxCostAllocationTags)
+		validateMaxItems(&errs, "spec.costAllocation.deploymentLabels", len(c.DeploymentLabels), MaxCostAllocationTags)
+		for _, key := range slices.Sorted(maps.Keys(c.Tags)) {
+			if !labelKeyRegex.MatchString(key) {
+				errs.Append("spec.costAllocation.tags["+key+"]", fmt.Errorf("%w: key %q", ErrInvalidLabel, key))
+			}
+			if !labelValueRegex.MatchString(c.Tags[key]) {
+				errs.Append("spec.costAllocation.tags["+key+"]", fmt.Errorf("%w: value %q", ErrInvalidLabel, c.Tags[key]))
+			}
+		}
+		for i, key := range c.DeploymentLabels {
+			if !labelKeyRegex.MatchString(key) {
+				errs.Append(fmt.Sprintf("spec.costAllocation.deploymentLabels[%d]", i), fmt.Errorf("%w: key %q", ErrInvalidLabel, key))
+			}
+		}
+	}
 	if len(errs) == 0 {
 		return nil
 	}
@@ -74,3 +105,19 @@ func knownRuntimeTypeNames() []string {
 	}
 	return out
 }
+
+// validateDefaultEnv checks env defaults handed to Deployments. secretRef
+// values are allowed whatever the Deployments' target; they only reach
+// Agent Deployments (see MergeDeploymentDefaults).
+func validateDefaultEnv(errs *FieldErrors, path string, env map[string]string) {
+	validateMaxItems(errs, path, len(env), MaxEnvVars)
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		value := env[key]
+		if !strings.HasPrefix(value, SecretRefPrefix) {
+			continue
+		}
+		if secret, _, ok := ParseSecretRef(value); !ok || validateNameField(secret) != nil {
+			errs.Append(path+"."+key, fmt.Errorf("%w: secret references must be %s<secret>/<key>", ErrInvalidFormat, SecretRefPrefix))
+		}
+	}
+}
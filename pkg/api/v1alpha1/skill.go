@@ -17,6 +17,17 @@ type SkillSpec struct {
 	Title       string       `json:"title,omitempty" yaml:"title,omitempty"`
 	Description string       `json:"description,omitempty" yaml:"description,omitempty"`
 	Source      *SkillSource `json:"source,omitempty" yaml:"source,omitempty"`
+
+	// Icon is the URL of the logo catalogs show for the skill. An icon
+	// uploaded through PUT /v0/skills/{name}/icon takes precedence.
+	Icon string `json:"icon,omitempty" yaml:"icon,omitempty" maxLength:"2048"`
+
+	// Skills and MCPServers are the skill's dependencies. An Agent using
+	// the skill gets them at deploy time, transitively; see
+	// ResolveAgentDependencies. Refs default their Kind and, unless they
+	// point at a peer registry, their namespace to the skill's.
+	Skills     []ResourceRef `json:"skills,omitempty" yaml:"skills,omitempty" maxItems:"100"`
+	MCPServers []ResourceRef `json:"mcpServers,omitempty" yaml:"mcpServers,omitempty" maxItems:"100"`
 }
 
 // SkillSource is the distribution origin of a skill. Currently just a
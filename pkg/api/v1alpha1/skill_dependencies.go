@@ -0,0 +1,205 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AgentDependencies is the transitive closure of an Agent's skills and MCP
+// servers: its own refs plus everything those skills depend on, each
+// exactly once, in the order they were first reached.
+type AgentDependencies struct {
+	Skills     []*Skill
+	MCPServers []*MCPServer
+}
+
+// ResolveAgentDependencies fetches the Agent's spec.mcpServers and
+// spec.skills through getter and, through each skill's own spec.skills and
+// spec.mcpServers, every skill and MCP server they depend on. It returns
+// ErrDependencyConflict when the closure reaches one skill or MCP server at
+// two tags, or two MCP servers ship the same package (an OCI image, npm or
+// PyPI package) at different versions, and ErrDependencyCycle when skills
+// depend on each other in a loop.
+func ResolveAgentDependencies(ctx context.Context, getter GetterFunc, agent *Agent) (*AgentDependencies, error) {
+	if getter == nil {
+		return nil, fmt.Errorf("resolve agent dependencies: getter is required")
+	}
+	w := newDependencyWalker(getter)
+	by := "Agent " + objectID(agent.Metadata)
+	for i, ref := range agent.Spec.MCPServers {
+		if err := w.server(ctx, ref, agent.Metadata.Namespace, by); err != nil {
+			return nil, fmt.Errorf("spec.mcpServers[%d]: %w", i, err)
+		}
+	}
+	for i, ref := range agent.Spec.Skills {
+		if err := w.skill(ctx, ref, agent.Metadata.Namespace, by, nil); err != nil {
+			return nil, fmt.Errorf("spec.skills[%d]: %w", i, err)
+		}
+	}
+	return &AgentDependencies{Skills: w.skills, MCPServers: w.servers}, nil
+}
+
+// CheckSkillDependencies walks the dependency graph below skill the way
+// ResolveAgentDependencies would for an Agent using it, and reports the
+// same cycles and conflicts. skill itself is taken as given rather than
+// read through getter, so a re-apply that closes a loop is caught before
+// it is stored.
+func CheckSkillDependencies(ctx context.Context, getter GetterFunc, skill *Skill) error {
+	if getter == nil || (len(skill.Spec.Skills) == 0 && len(skill.Spec.MCPServers) == 0) {
+		return nil
+	}
+	w := newDependencyWalker(getter)
+	id := objectID(skill.Metadata)
+	w.seen[dependencyKey(KindSkill, "", skill.Metadata.NamespaceOrDefault(), skill.Metadata.Name)] = id
+	return w.skillDeps(ctx, skill, []string{id})
+}
+
+type dependencyWalker struct {
+	getter  GetterFunc
+	skills  []*Skill
+	servers []*MCPServer
+	// seen maps a version-less dependency key to the versioned id first
+	// reached; packages maps a package key to its version and the server
+	// shipping it.
+	seen     map[string]string
+	packages map[string][2]string
+}
+
+func newDependencyWalker(getter GetterFunc) *dependencyWalker {
+	return &dependencyWalker{getter: getter, seen: map[string]string{}, packages: map[string][2]string{}}
+}
+
+func (w *dependencyWalker) server(ctx context.Context, ref ResourceRef, namespace, by string) error {
+	ref = defaultRef(ref, KindMCPServer, namespace)
+	obj, err := w.getter(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", refID(ref), err)
+	}
+	server, ok := obj.(*MCPServer)
+	if !ok || server == nil {
+		return fmt.Errorf("resolve %s: got %T, want MCPServer", refID(ref), obj)
+	}
+	id := objectID(server.Metadata)
+	if first, err := w.visit(dependencyKey(KindMCPServer, ref.Registry, server.Metadata.NamespaceOrDefault(), server.Metadata.Name), id, by); err != nil || !first {
+		return err
+	}
+	if src := server.Spec.Source; src != nil && src.Package != nil {
+		key, version := packageIdentity(src.Package)
+		if prev, ok := w.packages[key]; ok && prev[0] != version {
+			return fmt.Errorf("%w: package %s is required at %s by MCPServer %s and at %s by MCPServer %s",
+				ErrDependencyConflict, key, prev[0], prev[1], version, id)
+		}
+		w.packages[key] = [2]string{version, id}
+	}
+	w.servers = append(w.servers, server)
+	return nil
+}
+
+func (w *dependencyWalker) skill(ctx context.Context, ref ResourceRef, namespace, by string, path []string) error {
+	ref = defaultRef(ref, KindSkill, namespace)
+	obj, err := w.getter(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", refID(ref), err)
+	}
+	skill, ok := obj.(*Skill)
+	if !ok || skill == nil {
+		return fmt.Errorf("resolve %s: got %T, want Skill", refID(ref), obj)
+	}
+	id := objectID(skill.Metadata)
+	if slices.Contains(path, id) {
+		return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(append(path, id), " -> "))
+	}
+	if first, err := w.visit(dependencyKey(KindSkill, ref.Registry, skill.Metadata.NamespaceOrDefault(), skill.Metadata.Name), id, by); err != nil || !first {
+		return err
+	}
+	w.skills = append(w.skills, skill)
+	return w.skillDeps(ctx, skill, append(slices.Clone(path), id))
+}
+
+func (w *dependencyWalker) skillDeps(ctx context.Context, skill *Skill, path []string) error {
+	by := "Skill " + path[len(path)-1]
+	for i, ref := range skill.Spec.MCPServers {
+		if err := w.server(ctx, ref, skill.Metadata.Namespace, by); err != nil {
+			return fmt.Errorf("%s spec.mcpServers[%d]: %w", by, i, err)
+		}
+	}
+	for i, ref := range skill.Spec.Skills {
+		if err := w.skill(ctx, ref, skill.Metadata.Namespace, by, path); err != nil {
+			return fmt.Errorf("%s spec.skills[%d]: %w", by, i, err)
+		}
+	}
+	return nil
+}
+
+// visit records id under key and reports whether it was reached for the
+// first time. Reaching key again at another version is a conflict.
+func (w *dependencyWalker) visit(key, id, by string) (bool, error) {
+	prev, ok := w.seen[key]
+	switch {
+	case !ok:
+		w.seen[key] = id
+		return true, nil
+	case prev != id:
+		return false, fmt.Errorf("%w: %s is required by %s but %s is already required", ErrDependencyConflict, id, by, prev)
+	default:
+		return false, nil
+	}
+}
+
+// defaultRef fills a dependency ref's Kind and, unless it points at a peer
+// registry, its namespace.
+func defaultRef(ref ResourceRef, kind, namespace string) ResourceRef {
+	if ref.Kind == "" {
+		ref.Kind = kind
+	}
+	if ref.Namespace == "" && ref.Registry == "" {
+		ref.Namespace = namespace
+		if ref.Namespace == "" {
+			ref.Namespace = DefaultNamespace
+		}
+	}
+	return ref
+}
+
+func dependencyKey(kind, registry, namespace, name string) string {
+	return kind + "/" + registry + "/" + namespace + "/" + name
+}
+
+func objectID(m ObjectMeta) string {
+	return m.NamespaceOrDefault() + "/" + m.Name + "@" + m.Tag
+}
+
+func refID(ref ResourceRef) string {
+	id := ref.Kind + " " + ref.Namespace + "/" + ref.Name
+	if ref.Tag != "" {
+		id += "@" + ref.Tag
+	}
+	if ref.Registry != "" {
+		id += " from " + ref.Registry
+	}
+	return id
+}
+
+// packageIdentity splits an MCP server package into what it is (origin
+// type and identifier, an OCI image without its tag or digest) and its
+// version.
+func packageIdentity(p *MCPPackage) (key, version string) {
+	key = string(p.Origin.Type) + ":" + p.Origin.Identifier
+	switch {
+	case p.Origin.NPM != nil:
+		version = p.Origin.NPM.Version
+	case p.Origin.PyPI != nil:
+		version = p.Origin.PyPI.Version
+	case p.Origin.Type == MCPPackageOriginTypeOCI:
+		repo := p.Origin.Identifier
+		if at := strings.LastIndex(repo, "@"); at != -1 {
+			repo, version = repo[:at], repo[at+1:]
+		} else if colon := strings.LastIndex(repo, ":"); colon > strings.LastIndex(repo, "/") {
+			repo, version = repo[:colon], repo[colon+1:]
+		}
+		key = string(p.Origin.Type) + ":" + repo
+	}
+	return key, version
+}
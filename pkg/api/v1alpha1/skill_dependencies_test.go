@@ -0,0 +1,169 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry is a GetterFunc over a fixed set of objects, resolving an
+// empty tag to "latest" like the stores do.
+type fakeRegistry map[string]Object
+
+func (r fakeRegistry) add(obj Object) {
+	meta := obj.GetMetadata()
+	r[obj.GetKind()+"/"+meta.NamespaceOrDefault()+"/"+meta.Name+"@"+meta.Tag] = obj
+}
+
+func (r fakeRegistry) get(_ context.Context, ref ResourceRef) (Object, error) {
+	tag := ref.Tag
+	if tag == "" {
+		tag = "latest"
+	}
+	obj, ok := r[ref.Kind+"/"+ref.Namespace+"/"+ref.Name+"@"+tag]
+	if !ok {
+		return nil, ErrDanglingRef
+	}
+	return obj, nil
+}
+
+func testSkill(name, tag string, skills, servers []ResourceRef) *Skill {
+	return &Skill{
+		TypeMeta: TypeMeta{APIVersion: GroupVersion, Kind: KindSkill},
+		Metadata: ObjectMeta{Namespace: DefaultNamespace, Name: name, Tag: tag},
+		Spec:     SkillSpec{Skills: skills, MCPServers: servers},
+	}
+}
+
+func testServer(name, tag, image string) *MCPServer {
+	return &MCPServer{
+		TypeMeta: TypeMeta{APIVersion: GroupVersion, Kind: KindMCPServer},
+		Metadata: ObjectMeta{Namespace: DefaultNamespace, Name: name, Tag: tag},
+		Spec: MCPServerSpec{Source: &MCPServerSource{Package: &MCPPackage{
+			Origin:    MCPPackageOrigin{Type: MCPPackageOriginTypeOCI, Identifier: image},
+			Transport: MCPTransport{Type: "stdio"},
+		}}},
+	}
+}
+
+func TestResolveAgentDependencies_Transitive(t *testing.T) {
+	reg := fakeRegistry{}
+	reg.add(testServer("search", "1.0.0", "ghcr.io/acme/search:1.0.0"))
+	reg.add(testServer("fetch", "2.0.0", "ghcr.io/acme/fetch:2.0.0"))
+	reg.add(testSkill("cite", "1.0.0", nil, []ResourceRef{{Name: "fetch", Tag: "2.0.0"}}))
+	reg.add(testSkill("research", "1.0.0",
+		[]ResourceRef{{Name: "cite", Tag: "1.0.0"}},
+		[]ResourceRef{{Name: "search", Tag: "1.0.0"}}))
+
+	agent := &Agent{
+		Metadata: ObjectMeta{Namespace: DefaultNamespace, Name: "bot", Tag: "1.0.0"},
+		Spec: AgentSpec{
+			MCPServers: []ResourceRef{{Name: "search", Tag: "1.0.0"}},
+			Skills:     []ResourceRef{{Name: "research", Tag: "1.0.0"}},
+		},
+	}
+	deps, err := ResolveAgentDependencies(context.Background(), reg.get, agent)
+	require.NoError(t, err)
+
+	var skills, servers []string
+	for _, s := range deps.Skills {
+		skills = append(skills, s.Metadata.Name)
+	}
+	for _, s := range deps.MCPServers {
+		servers = append(servers, s.Metadata.Name)
+	}
+	require.Equal(t, []string{"research", "cite"}, skills)
+	require.Equal(t, []string{"search", "fetch"}, servers)
+}
+
+func TestResolveAgentDependencies_Conflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		objects []Object
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name: "server at two tags",
+			objects: []Object{
+				testServer("search", "1.0.0", "ghcr.io/acme/search:1.0.0"),
+				testServer("search", "2.0.0", "ghcr.io/acme/search:2.0.0"),
+				testSkill("research", "1.0.0", nil, []ResourceRef{{Name: "search", Tag: "2.0.0"}}),
+			},
+			wantErr: ErrDependencyConflict,
+			wantMsg: "default/search@2.0.0 is required by Skill default/research@1.0.0 but default/search@1.0.0 is already required",
+		},
+		{
+			name: "image at two versions",
+			objects: []Object{
+				testServer("search", "1.0.0", "ghcr.io/acme/search:1.0.0"),
+				testServer("search-fork", "1.0.0", "ghcr.io/acme/search@sha256:abc"),
+				testSkill("research", "1.0.0", nil, []ResourceRef{{Name: "search-fork", Tag: "1.0.0"}}),
+			},
+			wantErr: ErrDependencyConflict,
+			wantMsg: "package oci:ghcr.io/acme/search is required at 1.0.0",
+		},
+		{
+			name: "cycle",
+			objects: []Object{
+				testServer("search", "1.0.0", "ghcr.io/acme/search:1.0.0"),
+				testSkill("research", "1.0.0", []ResourceRef{{Name: "cite", Tag: "1.0.0"}}, nil),
+				testSkill("cite", "1.0.0", []ResourceRef{{Name: "research", Tag: "1.0.0"}}, nil),
+			},
+			wantErr: ErrDependencyCycle,
+			wantMsg: "default/research@1.0.0 -> default/cite@1.0.0 -> default/research@1.0.0",
+		},
+		{
+			name: "missing dependency",
+			objects: []Object{
+				testServer("search", "1.0.0", "ghcr.io/acme/search:1.0.0"),
+				testSkill("research", "1.0.0", []ResourceRef{{Name: "cite"}}, nil),
+			},
+			wantErr: ErrDanglingRef,
+			wantMsg: "resolve Skill default/cite",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := fakeRegistry{}
+			for _, obj := range tt.objects {
+				reg.add(obj)
+			}
+			agent := &Agent{
+				Metadata: ObjectMeta{Namespace: DefaultNamespace, Name: "bot", Tag: "1.0.0"},
+				Spec: AgentSpec{
+					MCPServers: []ResourceRef{{Name: "search", Tag: "1.0.0"}},
+					Skills:     []ResourceRef{{Name: "research", Tag: "1.0.0"}},
+				},
+			}
+			_, err := ResolveAgentDependencies(context.Background(), reg.get, agent)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.ErrorContains(t, err, tt.wantMsg)
+		})
+	}
+}
+
+func TestCheckSkillDependencies_CycleThroughSelf(t *testing.T) {
+	reg := fakeRegistry{}
+	reg.add(testSkill("cite", "1.0.0", []ResourceRef{{Name: "research"}}, nil))
+	reg.add(testSkill("research", "latest", nil, nil))
+
+	// Re-applying research to depend on cite closes research -> cite ->
+	// research, even though the stored research has no dependencies.
+	skill := testSkill("research", "latest", []ResourceRef{{Name: "cite", Tag: "1.0.0"}}, nil)
+	err := CheckSkillDependencies(context.Background(), reg.get, skill)
+	require.ErrorIs(t, err, ErrDependencyCycle)
+
+	skill.Spec.Skills = nil
+	require.NoError(t, CheckSkillDependencies(context.Background(), reg.get, skill))
+}
+
+func TestSkillValidate_SelfDependency(t *testing.T) {
+	skill := testSkill("research", "1.0.0", []ResourceRef{{Name: "research", Tag: "2.0.0"}}, nil)
+	require.ErrorIs(t, skill.Validate(), ErrDependencyCycle)
+
+	skill.Spec.Skills = []ResourceRef{{Name: "cite"}}
+	require.NoError(t, skill.Validate())
+	require.Equal(t, KindSkill, skill.Spec.Skills[0].Kind)
+}
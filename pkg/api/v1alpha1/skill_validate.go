@@ -1,9 +1,37 @@
 package v1alpha1
 
+import (
+	"context"
+	"fmt"
+)
+
 func (s *Skill) Validate() error {
 	var errs FieldErrors
 	errs = append(errs, ValidateObjectMeta(s.Metadata)...)
 	errs = append(errs, validateSkillSpec(&s.Spec)...)
+	for i, ref := range s.Spec.Skills {
+		if ref.Registry == "" && ref.Name == s.Metadata.Name &&
+			(ref.Namespace == "" || ref.Namespace == s.Metadata.Namespace) {
+			errs.Append(fmt.Sprintf("spec.skills[%d]", i), fmt.Errorf("%w: a skill cannot depend on itself", ErrDependencyCycle))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ResolveRefs checks every skill and MCP server the Skill depends on
+// exists. Cycles through other skills need their specs, not just their
+// existence, and are checked by CheckSkillDependencyCycle.
+func (s *Skill) ResolveRefs(ctx context.Context, resolver ResolverFunc) error {
+	if resolver == nil {
+		return nil
+	}
+	var errs FieldErrors
+	ns := s.Metadata.Namespace
+	errs = append(errs, resolveResourceRefs(ctx, resolver, ns, "spec.skills", s.Spec.Skills, KindSkill)...)
+	errs = append(errs, resolveResourceRefs(ctx, resolver, ns, "spec.mcpServers", s.Spec.MCPServers, KindMCPServer)...)
 	if len(errs) == 0 {
 		return nil
 	}
@@ -13,10 +41,14 @@ func (s *Skill) Validate() error {
 func validateSkillSpec(s *SkillSpec) FieldErrors {
 	var errs FieldErrors
 	errs.Append("spec.title", validateTitle(s.Title))
+	validateIcon(&errs, "spec.icon", s.Icon)
 	if s.Source != nil {
 		for _, e := range validateRepository(s.Source.Repository) {
 			errs.Append("spec.source."+e.Path, e.Cause)
 		}
 	}
+	// Dependency refs default their Kind in place, like an Agent's.
+	errs = append(errs, validateResourceRefs("spec.skills", s.Skills, KindSkill)...)
+	errs = append(errs, validateResourceRefs("spec.mcpServers", s.MCPServers, KindMCPServer)...)
 	return errs
 }
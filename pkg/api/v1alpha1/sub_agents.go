@@ -0,0 +1,69 @@
+package v1alpha1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ResolveSubAgents fetches the Agent's spec.subAgents through getter. Every
+// sub-agent is deployed with sub-agents of its own, so the walk continues
+// below them and returns ErrDependencyCycle when agents delegate to each
+// other in a loop. Two sub-agents sharing a name would share a Deployment
+// and are reported as ErrDependencyConflict.
+func ResolveSubAgents(ctx context.Context, getter GetterFunc, agent *Agent) ([]*Agent, error) {
+	if len(agent.Spec.SubAgents) == 0 {
+		return nil, nil
+	}
+	if getter == nil {
+		return nil, fmt.Errorf("resolve sub-agents: getter is required")
+	}
+	path := []string{agent.Metadata.NamespaceOrDefault() + "/" + agent.Metadata.Name}
+	return resolveSubAgents(ctx, getter, agent, path)
+}
+
+func resolveSubAgents(ctx context.Context, getter GetterFunc, agent *Agent, path []string) ([]*Agent, error) {
+	var out []*Agent
+	byName := map[string]string{}
+	for i, ref := range agent.Spec.SubAgents {
+		ref = defaultRef(ref, KindAgent, agent.Metadata.Namespace)
+		obj, err := getter(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("spec.subAgents[%d]: resolve %s: %w", i, refID(ref), err)
+		}
+		sub, ok := obj.(*Agent)
+		if !ok || sub == nil {
+			return nil, fmt.Errorf("spec.subAgents[%d]: resolve %s: got %T, want Agent", i, refID(ref), obj)
+		}
+		key := sub.Metadata.NamespaceOrDefault() + "/" + sub.Metadata.Name
+		if slices.Contains(path, key) {
+			return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(append(path, key), " -> "))
+		}
+		id := objectID(sub.Metadata)
+		if prev, ok := byName[sub.Metadata.Name]; ok {
+			return nil, fmt.Errorf("%w: spec.subAgents[%d]: %s and %s share a name", ErrDependencyConflict, i, prev, id)
+		}
+		byName[sub.Metadata.Name] = id
+		if _, err := resolveSubAgents(ctx, getter, sub, append(slices.Clone(path), key)); err != nil {
+			return nil, fmt.Errorf("Agent %s %w", id, err)
+		}
+		out = append(out, sub)
+	}
+	return out, nil
+}
+
+// SubAgentDeploymentName is the name of the Deployment that runs the
+// sub-agent agentName for the Deployment parent. Names that would not fit
+// a DNS label are shortened and suffixed with a hash of both inputs.
+func SubAgentDeploymentName(parent, agentName string) string {
+	name := parent + "-" + agentName
+	if len(name) <= 63 {
+		return name
+	}
+	sum := sha256.Sum256([]byte(parent + "\x00" + agentName))
+	suffix := hex.EncodeToString(sum[:])[:10]
+	return strings.TrimRight(name[:63-len(suffix)-1], "-.") + "-" + suffix
+}
@@ -0,0 +1,66 @@
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testAgent(name, tag string, subAgents ...ResourceRef) *Agent {
+	return &Agent{
+		TypeMeta: TypeMeta{APIVersion: GroupVersion, Kind: KindAgent},
+		Metadata: ObjectMeta{Namespace: DefaultNamespace, Name: name, Tag: tag},
+		Spec:     AgentSpec{SubAgents: subAgents},
+	}
+}
+
+func TestResolveSubAgents(t *testing.T) {
+	reg := fakeRegistry{}
+	reg.add(testAgent("researcher", "1.0.0", ResourceRef{Name: "fetcher", Tag: "1.0.0"}))
+	reg.add(testAgent("fetcher", "1.0.0"))
+	reg.add(testAgent("writer", "latest"))
+
+	planner := testAgent("planner", "1.0.0", ResourceRef{Name: "researcher", Tag: "1.0.0"}, ResourceRef{Name: "writer"})
+	subAgents, err := ResolveSubAgents(context.Background(), reg.get, planner)
+	require.NoError(t, err)
+	require.Len(t, subAgents, 2)
+	require.Equal(t, "researcher", subAgents[0].Metadata.Name)
+	require.Equal(t, "writer", subAgents[1].Metadata.Name)
+
+	_, err = ResolveSubAgents(context.Background(), reg.get, testAgent("planner", "1.0.0", ResourceRef{Name: "missing"}))
+	require.ErrorIs(t, err, ErrDanglingRef)
+}
+
+func TestResolveSubAgents_Cycle(t *testing.T) {
+	reg := fakeRegistry{}
+	reg.add(testAgent("researcher", "1.0.0", ResourceRef{Name: "planner", Tag: "2.0.0"}))
+	reg.add(testAgent("planner", "2.0.0"))
+
+	planner := testAgent("planner", "1.0.0", ResourceRef{Name: "researcher", Tag: "1.0.0"})
+	_, err := ResolveSubAgents(context.Background(), reg.get, planner)
+	require.ErrorIs(t, err, ErrDependencyCycle)
+	require.Contains(t, err.Error(), "default/planner -> default/researcher -> default/planner")
+}
+
+func TestResolveSubAgents_SharedName(t *testing.T) {
+	reg := fakeRegistry{}
+	reg.add(testAgent("writer", "1.0.0"))
+	teamWriter := testAgent("writer", "latest")
+	teamWriter.Metadata.Namespace = "team"
+	reg.add(teamWriter)
+
+	planner := testAgent("planner", "1.0.0", ResourceRef{Name: "writer", Tag: "1.0.0"}, ResourceRef{Namespace: "team", Name: "writer"})
+	_, err := ResolveSubAgents(context.Background(), reg.get, planner)
+	require.ErrorIs(t, err, ErrDependencyConflict)
+}
+
+func TestSubAgentDeploymentName(t *testing.T) {
+	require.Equal(t, "planner-prod-writer", SubAgentDeploymentName("planner-prod", "writer"))
+
+	long := SubAgentDeploymentName(strings.Repeat("p", 60), "writer")
+	require.Len(t, long, 63)
+	require.NoError(t, validateNameField(long))
+	require.NotEqual(t, long, SubAgentDeploymentName(strings.Repeat("p", 60), "reader"))
+}
@@ -0,0 +1,198 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// tagConstraintOperators are the characters that make a ref tag a version
+// constraint. None of them is legal in a tag.
+const tagConstraintOperators = "<>=~^ ,"
+
+// IsTagConstraint reports whether a ref tag is a semver constraint, such
+// as ">=1.2 <2" or "~1.4", rather than a tag.
+func IsTagConstraint(tag string) bool {
+	return strings.ContainsAny(tag, tagConstraintOperators)
+}
+
+// TagConstraint is a parsed semver constraint: every term must hold. Terms
+// are separated by spaces or commas and take the forms
+//
+//	1.2.3, =1.2.3   exactly 1.2.3
+//	>1.2, >=1.2     greater than (or equal to) 1.2.0
+//	<2, <=2.1       less than (or equal to) 2.0.0 or 2.1.0
+//	~1.4            >=1.4.0 <1.5.0 (~1 is >=1.0.0 <2.0.0)
+//	^1.2.3          >=1.2.3 <2.0.0 (^0.2.3 is >=0.2.3 <0.3.0)
+//
+// Missing minor and patch numbers are zero, and a leading "v" is
+// optional. Only release tags match: tags that are not semver, or carry a
+// prerelease suffix, never do.
+type TagConstraint struct {
+	raw   string
+	terms []constraintTerm
+}
+
+type constraintTerm struct {
+	op      string
+	version string // canonical semver, with a leading "v"
+}
+
+// ParseTagConstraint parses s as a TagConstraint.
+func ParseTagConstraint(s string) (TagConstraint, error) {
+	c := TagConstraint{raw: strings.TrimSpace(s)}
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 0 {
+		return TagConstraint{}, fmt.Errorf("%w: empty version constraint", ErrInvalidFormat)
+	}
+	for i := 0; i < len(fields); i++ {
+		version := strings.TrimLeft(fields[i], "<>=~^")
+		op := fields[i][:len(fields[i])-len(version)]
+		// An operator set apart from its version: ">= 1.2".
+		if version == "" && i+1 < len(fields) {
+			i++
+			version = fields[i]
+		}
+		v, ok := constraintVersion(version)
+		if !ok {
+			return TagConstraint{}, fmt.Errorf("%w: version constraint %q: %q is not a version", ErrInvalidFormat, s, version)
+		}
+		switch op {
+		case "", "=", ">", ">=", "<", "<=":
+			if op == "" {
+				op = "="
+			}
+			c.terms = append(c.terms, constraintTerm{op: op, version: v})
+		case "~":
+			upper := fmt.Sprintf("v%d.%d.0", major(v), minor(v)+1)
+			if !strings.Contains(version, ".") {
+				upper = fmt.Sprintf("v%d.0.0", major(v)+1)
+			}
+			c.terms = append(c.terms, constraintTerm{op: ">=", version: v}, constraintTerm{op: "<", version: upper})
+		case "^":
+			var upper string
+			switch {
+			case major(v) > 0:
+				upper = fmt.Sprintf("v%d.0.0", major(v)+1)
+			case minor(v) > 0:
+				upper = fmt.Sprintf("v0.%d.0", minor(v)+1)
+			default:
+				upper = fmt.Sprintf("v0.0.%d", patch(v)+1)
+			}
+			c.terms = append(c.terms, constraintTerm{op: ">=", version: v}, constraintTerm{op: "<", version: upper})
+		default:
+			return TagConstraint{}, fmt.Errorf("%w: version constraint %q: unknown operator %q", ErrInvalidFormat, s, op)
+		}
+	}
+	return c, nil
+}
+
+// String returns the constraint as written.
+func (c TagConstraint) String() string { return c.raw }
+
+// Matches reports whether tag satisfies every term of c.
+func (c TagConstraint) Matches(tag string) bool {
+	v := tag
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	if !semver.IsValid(v) || semver.Prerelease(v) != "" {
+		return false
+	}
+	for _, term := range c.terms {
+		cmp := semver.Compare(v, term.version)
+		var ok bool
+		switch term.op {
+		case "=":
+			ok = cmp == 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return len(c.terms) > 0
+}
+
+// Best returns the highest of tags that c matches.
+func (c TagConstraint) Best(tags []string) (string, bool) {
+	best := ""
+	for _, tag := range tags {
+		if !c.Matches(tag) {
+			continue
+		}
+		if best == "" || semver.Compare("v"+strings.TrimPrefix(tag, "v"), "v"+strings.TrimPrefix(best, "v")) > 0 {
+			best = tag
+		}
+	}
+	return best, best != ""
+}
+
+// ResolvedTag records the tag a version-constraint ref resolved to.
+type ResolvedTag struct {
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
+	Tag        string `json:"tag"`
+}
+
+// ResolveAgentTagConstraints resolves, through getter, each of the Agent's
+// spec.mcpServers refs whose tag is a version constraint, and returns the
+// tag each resolved to, in spec order.
+func ResolveAgentTagConstraints(ctx context.Context, getter GetterFunc, agent *Agent) ([]ResolvedTag, error) {
+	var out []ResolvedTag
+	for i, ref := range agent.Spec.MCPServers {
+		if !IsTagConstraint(ref.Tag) {
+			continue
+		}
+		ref = defaultRef(ref, KindMCPServer, agent.Metadata.Namespace)
+		obj, err := getter(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("spec.mcpServers[%d]: resolve %s: %w", i, refID(ref), err)
+		}
+		out = append(out, ResolvedTag{
+			Kind:       ref.Kind,
+			Namespace:  ref.Namespace,
+			Name:       ref.Name,
+			Constraint: ref.Tag,
+			Tag:        obj.GetMetadata().Tag,
+		})
+	}
+	return out, nil
+}
+
+// constraintVersion canonicalizes a constraint version such as "1", "1.4"
+// or "v1.4.2" to full semver.
+func constraintVersion(s string) (string, bool) {
+	v := "v" + strings.TrimPrefix(s, "v")
+	if !semver.IsValid(v) || semver.Build(v) != "" {
+		return "", false
+	}
+	return semver.Canonical(v), true
+}
+
+func major(v string) int { return versionPart(v, 0) }
+func minor(v string) int { return versionPart(v, 1) }
+func patch(v string) int { return versionPart(v, 2) }
+
+// versionPart returns part i of canonical semver v.
+func versionPart(v string, i int) int {
+	core, _, _ := strings.Cut(strings.TrimPrefix(v, "v"), "-")
+	parts := strings.Split(core, ".")
+	if i >= len(parts) {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[i])
+	return n
+}
@@ -0,0 +1,98 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTagConstraint(t *testing.T) {
+	for tag, want := range map[string]bool{
+		"":           false,
+		"latest":     false,
+		"1.2.3":      false,
+		"v1.2.3-rc1": false,
+		">=1.2":      true,
+		"~1.4":       true,
+		"^0.3":       true,
+		"1.2, <2":    true,
+	} {
+		require.Equal(t, want, IsTagConstraint(tag), tag)
+	}
+}
+
+func TestTagConstraint_Matches(t *testing.T) {
+	cases := []struct {
+		constraint string
+		match      []string
+		noMatch    []string
+	}{
+		{">=1.2 <2", []string{"1.2.0", "v1.9.9"}, []string{"1.1.9", "2.0.0", "1.5.0-rc1", "latest"}},
+		{">= 1.2, < 2", []string{"1.2.0", "1.9.9"}, []string{"2.0.0"}},
+		{"~1.4", []string{"1.4.0", "1.4.7"}, []string{"1.3.9", "1.5.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"=1.2", []string{"1.2.0"}, []string{"1.2.1"}},
+		{">1.2,<=1.3", []string{"1.2.1", "1.3.0"}, []string{"1.2.0", "1.3.1"}},
+	}
+	for _, tc := range cases {
+		c, err := ParseTagConstraint(tc.constraint)
+		require.NoError(t, err, tc.constraint)
+		for _, tag := range tc.match {
+			require.True(t, c.Matches(tag), "%s should match %s", tc.constraint, tag)
+		}
+		for _, tag := range tc.noMatch {
+			require.False(t, c.Matches(tag), "%s should not match %s", tc.constraint, tag)
+		}
+	}
+}
+
+func TestParseTagConstraint_Invalid(t *testing.T) {
+	for _, s := range []string{"", " , ", ">=", ">=one", "=>1.2", "~>1.2", "1.2+build"} {
+		_, err := ParseTagConstraint(s)
+		require.ErrorIs(t, err, ErrInvalidFormat, s)
+	}
+}
+
+func TestTagConstraint_Best(t *testing.T) {
+	c, err := ParseTagConstraint("^1.2")
+	require.NoError(t, err)
+
+	best, ok := c.Best([]string{"latest", "1.2.0", "v1.10.0", "1.9.0", "2.0.0", "1.11.0-rc1"})
+	require.True(t, ok)
+	require.Equal(t, "v1.10.0", best)
+
+	_, ok = c.Best([]string{"latest", "2.0.0"})
+	require.False(t, ok)
+}
+
+func TestResolveAgentTagConstraints(t *testing.T) {
+	agent := &Agent{
+		Metadata: ObjectMeta{Namespace: "team-a", Name: "a"},
+		Spec: AgentSpec{
+			MCPServers: []ResourceRef{
+				{Name: "weather", Tag: "1.0.0"},
+				{Name: "search", Tag: ">=1.2 <2"},
+			},
+		},
+	}
+	var got []ResourceRef
+	getter := func(_ context.Context, ref ResourceRef) (Object, error) {
+		got = append(got, ref)
+		return &MCPServer{Metadata: ObjectMeta{Namespace: ref.Namespace, Name: ref.Name, Tag: "1.4.0"}}, nil
+	}
+
+	resolved, err := ResolveAgentTagConstraints(context.Background(), getter, agent)
+	require.NoError(t, err)
+	require.Equal(t, []ResolvedTag{{
+		Kind:       KindMCPServer,
+		Namespace:  "team-a",
+		Name:       "search",
+		Constraint: ">=1.2 <2",
+		Tag:        "1.4.0",
+	}}, resolved)
+	require.Len(t, got, 1, "only constraint refs are resolved")
+}
@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // Validation error sentinels. All validation errors are wrapped in a
@@ -25,6 +27,11 @@ var (
 	// referenced resource does not exist. Tests + callers identify
 	// dangling references via errors.Is(err, ErrDanglingRef).
 	ErrDanglingRef = errors.New("referenced resource not found")
+	// ErrDependencyCycle and ErrDependencyConflict are returned when a
+	// skill dependency graph loops back on itself, or reaches the same
+	// MCP server or skill, or the same server image, at two versions.
+	ErrDependencyCycle    = errors.New("dependency cycle")
+	ErrDependencyConflict = errors.New("dependency conflict")
 )
 
 // FieldError pins a validation failure to a dot-path inside the object.
@@ -60,6 +67,16 @@ func (fe FieldErrors) Error() string {
 	return strings.Join(msgs, "; ")
 }
 
+// Unwrap exposes each FieldError so errors.Is matches any cause in the
+// pass, e.g. errors.Is(err, ErrLimitExceeded).
+func (fe FieldErrors) Unwrap() []error {
+	errs := make([]error, len(fe))
+	for i, e := range fe {
+		errs[i] = e
+	}
+	return errs
+}
+
 // Append records a new field error under pathPrefix+path. If cause is
 // nil, it's a no-op.
 func (fe *FieldErrors) Append(path string, cause error) {
@@ -98,6 +115,9 @@ var labelValueRegex = regexp.MustCompile(`^([a-zA-Z0-9]([-a-zA-Z0-9._]{0,61}[a-z
 
 var tagRegex = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
 
+// envNameRegex: POSIX-portable environment variable name.
+var envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 // DNS-1123 subdomain form: lowercase alphanumeric, hyphens, and dots.
 // Must start and end with alphanumeric. Each dot-separated segment is a
 // DNS-1123 label (1-63 chars). Total length 1-253. Matches the rule
@@ -126,6 +146,16 @@ func validateNameField(name string) error {
 	return nil
 }
 
+// CanonicalName is the form resource names are published and looked up
+// in: Unicode NFKC, then lower case. Names that differ only in case or in
+// compatibility characters (e.g. full-width letters) have the same
+// canonical name and so name the same resource: `Weather-Bot` and
+// `weather-bot` are one agent. Namespaces follow the same rule; the
+// canonical form of a valid namespace is the namespace itself.
+func CanonicalName(name string) string {
+	return strings.ToLower(norm.NFKC.String(name))
+}
+
 // Upstream MCP-ecosystem catalogue name pattern. Accepts identifier-shaped
 // strings: alphanumeric plus `.`, `_`, `-`, `/`. The slash is optional so
 // single-segment names (e.g. `my-mcp`) and reverse-DNS namespace/name forms
@@ -205,6 +235,28 @@ func validateWebsiteURL(u string) error {
 	return nil
 }
 
+// validateIcon checks an optional icon URL: absolute http(s), within
+// MaxIconURLLength. Images are uploaded through the icon subresource, not
+// inlined as data: URLs.
+func validateIcon(errs *FieldErrors, path, u string) {
+	if u == "" {
+		return
+	}
+	if len(u) > MaxIconURLLength {
+		validateMaxLength(errs, path, u, MaxIconURLLength)
+		return
+	}
+	parsed, err := url.Parse(u)
+	switch {
+	case err != nil:
+		errs.Append(path, fmt.Errorf("%w: %v", ErrInvalidURL, err))
+	case parsed.Scheme != "https" && parsed.Scheme != "http":
+		errs.Append(path, fmt.Errorf("%w: scheme must be http or https", ErrInvalidURL))
+	case parsed.Host == "":
+		errs.Append(path, fmt.Errorf("%w: host is empty", ErrInvalidURL))
+	}
+}
+
 // validateTitle: optional; when set, must not be whitespace-only.
 func validateTitle(title string) error {
 	if title == "" {
@@ -255,6 +307,11 @@ func validateRef(r ResourceRef, allowedKinds ...string) FieldErrors {
 	if err := validateNameField(r.Name); err != nil {
 		errs.Append("name", err)
 	}
+	// Peer refs are only legal on Agent spec.mcpServers, which validates
+	// and strips Registry before calling here.
+	if r.Registry != "" {
+		errs.Append("registry", fmt.Errorf("%w: peer registry refs are only supported in agent spec.mcpServers", ErrInvalidRef))
+	}
 	// Tag is optional on content refs — blank means "resolve to latest".
 	if r.Tag != "" {
 		if !IsTaggedArtifactKind(r.Kind) {
@@ -266,6 +323,43 @@ func validateRef(r ResourceRef, allowedKinds ...string) FieldErrors {
 	return errs
 }
 
+// ValidateNamespace checks a namespace name, as used in metadata.namespace
+// and claimed through /v0/namespaces.
+func ValidateNamespace(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w", ErrRequiredField)
+	}
+	if !namespaceRegex.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidFormat, name)
+	}
+	return nil
+}
+
+// ValidatePeerRegistryName checks a peer registry name, as configured on
+// the server and named by ResourceRef.Registry. Same rules as a namespace.
+func ValidatePeerRegistryName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w", ErrRequiredField)
+	}
+	if !namespaceRegex.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidFormat, name)
+	}
+	return nil
+}
+
+// ValidateSnapshotName checks a catalog snapshot name, as given to
+// POST /v0/snapshots and DeploymentSpec.Snapshot. Same rules as a
+// namespace.
+func ValidateSnapshotName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w", ErrRequiredField)
+	}
+	if !namespaceRegex.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidFormat, name)
+	}
+	return nil
+}
+
 // resolveRefWith runs resolver against ref and prepends pathPrefix to any
 // reported error. Returns a FieldErrors slice (one entry if resolver failed,
 // empty otherwise) so callers can uniformly accumulate.
@@ -67,6 +67,19 @@ func TestValidateObjectMeta_AcceptsDottedName(t *testing.T) {
 	}
 }
 
+func TestCanonicalName(t *testing.T) {
+	for in, want := range map[string]string{
+		"io.github.acme.tool": "io.github.acme.tool",
+		"Io.GitHub.Acme.Tool": "io.github.acme.tool",
+		"ＷＥＡＴＨＥＲ":             "weather",
+		"ﬁle-tools":           "file-tools",
+	} {
+		require.Equal(t, want, CanonicalName(in), in)
+	}
+	// Canonicalizing a mixed-case name turns it valid.
+	require.Empty(t, ValidateObjectMeta(ObjectMeta{Namespace: "default", Name: CanonicalName("Weather-Bot")}))
+}
+
 func TestValidateObjectMeta_RejectsBadLabelKey(t *testing.T) {
 	errs := ValidateObjectMeta(ObjectMeta{
 		Namespace: "default", Name: "x",
@@ -104,6 +117,38 @@ func TestAgentValidate_RejectsWrongRefKind(t *testing.T) {
 	require.Contains(t, paths, "spec.mcpServers[0].kind")
 }
 
+func TestAgentValidate_PeerRegistryRefs(t *testing.T) {
+	a := &Agent{
+		Metadata: ObjectMeta{Namespace: "default", Name: "a"},
+		Spec: AgentSpec{
+			MCPServers: []ResourceRef{
+				{Kind: KindMCPServer, Name: "weather", Tag: "1.0.0", Registry: "upstream-public"},
+				{Kind: KindMCPServer, Name: "bad", Registry: "Not_A_Name"},
+			},
+			Charts: []ResourceRef{{Kind: KindChart, Name: "base", Registry: "upstream-public"}},
+		},
+	}
+	paths := failedFields(t, a.Validate())
+	require.ElementsMatch(t, []string{"spec.mcpServers[1].registry", "spec.charts[0].registry"}, paths)
+}
+
+func TestAgentValidate_MCPServerTagConstraints(t *testing.T) {
+	a := &Agent{
+		Metadata: ObjectMeta{Namespace: "default", Name: "a"},
+		Spec: AgentSpec{
+			MCPServers: []ResourceRef{
+				{Kind: KindMCPServer, Name: "ok", Tag: ">=1.2 <2"},
+				{Kind: KindMCPServer, Name: "caret", Tag: "^0.3"},
+				{Kind: KindMCPServer, Name: "bad", Tag: ">=one"},
+				{Kind: KindMCPServer, Name: "peer", Tag: "~1.4", Registry: "upstream-public"},
+			},
+			Charts: []ResourceRef{{Kind: KindChart, Name: "base", Tag: "~1.4"}},
+		},
+	}
+	paths := failedFields(t, a.Validate())
+	require.ElementsMatch(t, []string{"spec.mcpServers[2].tag", "spec.mcpServers[3].tag", "spec.charts[0].tag"}, paths)
+}
+
 func TestAgentValidate_AcceptsBlankOptionalFields(t *testing.T) {
 	a := &Agent{
 		Metadata: ObjectMeta{Namespace: "default", Name: "minimal"},
@@ -123,6 +168,31 @@ func TestAgentValidate_AccumulatesErrors(t *testing.T) {
 	require.Contains(t, paths, "spec.title")
 }
 
+func TestValidateIcon(t *testing.T) {
+	for _, tc := range []struct {
+		icon string
+		ok   bool
+	}{
+		{"", true},
+		{"https://cdn.example.com/logo.png", true},
+		{"http://intranet/logo.svg", true},
+		{"data:image/png;base64,iVBORw0KGgo=", false},
+		{"ftp://example.com/logo.png", false},
+		{"/logo.png", false},
+		{"https://example.com/" + strings.Repeat("a", MaxIconURLLength), false},
+	} {
+		a := &Agent{Metadata: ObjectMeta{Namespace: "default", Name: "a"}, Spec: AgentSpec{Icon: tc.icon}}
+		s := &Skill{Metadata: ObjectMeta{Namespace: "default", Name: "s"}, Spec: SkillSpec{Icon: tc.icon}}
+		if tc.ok {
+			require.NoError(t, a.Validate(), tc.icon)
+			require.NoError(t, s.Validate(), tc.icon)
+			continue
+		}
+		require.Equal(t, []string{"spec.icon"}, failedFields(t, a.Validate()), tc.icon)
+		require.Equal(t, []string{"spec.icon"}, failedFields(t, s.Validate()), tc.icon)
+	}
+}
+
 func TestAgentValidate_AcceptsRepositoryWithBranchAndCommit(t *testing.T) {
 	a := &Agent{
 		Metadata: ObjectMeta{Namespace: "default", Name: "a"},
@@ -217,6 +287,24 @@ func TestAgentResolveRefs_InheritsNamespace(t *testing.T) {
 	require.Equal(t, "shared", seen[1].Namespace)
 }
 
+func TestAgentResolveRefs_PeerRefsKeepBlankNamespace(t *testing.T) {
+	var seen []ResourceRef
+	resolver := func(ctx context.Context, ref ResourceRef) error {
+		seen = append(seen, ref)
+		return nil
+	}
+	a := &Agent{
+		Metadata: ObjectMeta{Namespace: "team-a", Name: "a", Tag: "v1"},
+		Spec: AgentSpec{
+			MCPServers: []ResourceRef{{Kind: KindMCPServer, Name: "weather", Registry: "upstream-public"}},
+		},
+	}
+	require.NoError(t, a.ResolveRefs(context.Background(), resolver))
+	require.Len(t, seen, 1)
+	require.Empty(t, seen[0].Namespace)
+	require.Equal(t, "upstream-public", seen[0].Registry)
+}
+
 func TestAgentResolveRefs_NilResolverIsNoOp(t *testing.T) {
 	a := &Agent{Metadata: ObjectMeta{Namespace: "default", Name: "a"}}
 	require.NoError(t, a.ResolveRefs(context.Background(), nil))
@@ -226,6 +314,140 @@ func TestAgentResolveRefs_NilResolverIsNoOp(t *testing.T) {
 // DeploymentSpec
 // -----------------------------------------------------------------------------
 
+func TestAgentValidate_Secrets(t *testing.T) {
+	a := &Agent{
+		Metadata: ObjectMeta{Namespace: "default", Name: "a"},
+		Spec: AgentSpec{
+			Secrets: []AgentSecret{
+				{Name: "OPENAI_API_KEY", Description: "model key", Required: true},
+				{Name: "SLACK_TOKEN"},
+			},
+		},
+	}
+	require.NoError(t, a.Validate())
+
+	a.Spec.Secrets = []AgentSecret{
+		{Name: ""},
+		{Name: "BAD-NAME"},
+		{Name: "DUP"},
+		{Name: "DUP"},
+	}
+	paths := failedFields(t, a.Validate())
+	require.ElementsMatch(t, []string{"spec.secrets[0].name", "spec.secrets[1].name", "spec.secrets[3].name"}, paths)
+}
+
+func TestAgentValidate_Resources(t *testing.T) {
+	a := &Agent{
+		Metadata: ObjectMeta{Namespace: "default", Name: "a"},
+		Spec: AgentSpec{
+			Resources: &AgentResources{
+				GPUs:    2,
+				Devices: []string{"/dev/dri", "/dev/kfd:/dev/kfd", "/dev/snd:/dev/snd:rw"},
+			},
+		},
+	}
+	require.NoError(t, a.Validate())
+
+	a.Spec.Resources = &AgentResources{
+		GPUs:    -1,
+		Devices: []string{"dev/dri", "/dev/kfd:kfd", "/dev/snd:/dev/snd:x", "/a:/b:r:w"},
+	}
+	paths := failedFields(t, a.Validate())
+	require.ElementsMatch(t, []string{
+		"spec.resources.gpus",
+		"spec.resources.devices[0]",
+		"spec.resources.devices[1]",
+		"spec.resources.devices[2]",
+		"spec.resources.devices[3]",
+	}, paths)
+
+	a.Spec.Resources = &AgentResources{GPUs: MaxAgentGPUs + 1}
+	require.ErrorIs(t, a.Validate(), ErrLimitExceeded)
+}
+
+func TestAgentValidate_ComputeResources(t *testing.T) {
+	a := &Agent{
+		Metadata: ObjectMeta{Namespace: "default", Name: "a"},
+		Spec: AgentSpec{
+			Resources: &AgentResources{
+				Requests: &ComputeResources{CPU: "250m", Memory: "256Mi"},
+				Limits:   &ComputeResources{CPU: "1", Memory: "1G"},
+			},
+		},
+	}
+	require.NoError(t, a.Validate())
+
+	a.Spec.Resources = &AgentResources{
+		Requests: &ComputeResources{CPU: "2", Memory: "0"},
+		Limits:   &ComputeResources{CPU: "1", Memory: "1 GB"},
+	}
+	paths := failedFields(t, a.Validate())
+	require.ElementsMatch(t, []string{
+		"spec.resources.requests.memory",
+		"spec.resources.limits.memory",
+		"spec.resources.requests.cpu",
+	}, paths)
+}
+
+func TestParseCPUAndMemory(t *testing.T) {
+	cpu := map[string]int64{"2": 2000, "0.5": 500, "500m": 500, "1.5": 1500}
+	for in, want := range cpu {
+		got, err := ParseCPU(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "-1", "1.0005", "1.5m", "1 core"} {
+		_, err := ParseCPU(in)
+		require.ErrorIs(t, err, ErrInvalidFormat, in)
+	}
+
+	memory := map[string]int64{"1024": 1024, "1k": 1000, "512Mi": 512 << 20, "1.5Gi": 3 << 29, "2G": 2e9}
+	for in, want := range memory {
+		got, err := ParseMemory(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "1KB", "1mi", "-1Gi"} {
+		_, err := ParseMemory(in)
+		require.ErrorIs(t, err, ErrInvalidFormat, in)
+	}
+}
+
+func TestMergeResourceRequirements(t *testing.T) {
+	require.Nil(t, MergeResourceRequirements(nil, &ResourceRequirements{}))
+
+	got := MergeResourceRequirements(
+		&ResourceRequirements{Limits: &ComputeResources{CPU: "2"}},
+		nil,
+		&ResourceRequirements{
+			Requests: &ComputeResources{Memory: "256Mi"},
+			Limits:   &ComputeResources{CPU: "1", Memory: "1Gi"},
+		},
+	)
+	require.Equal(t, &ResourceRequirements{
+		Requests: &ComputeResources{Memory: "256Mi"},
+		Limits:   &ComputeResources{CPU: "2", Memory: "1Gi"},
+	}, got)
+}
+
+func TestAgentValidate_Platforms(t *testing.T) {
+	a := &Agent{
+		Metadata: ObjectMeta{Namespace: "default", Name: "a"},
+		Spec: AgentSpec{
+			Source: &AgentSource{Image: "ghcr.io/acme/a:1", Platforms: []string{"linux/amd64", "linux/arm64/v8"}},
+		},
+	}
+	require.NoError(t, a.Validate())
+
+	a.Spec.Source.Platforms = []string{"linux", "Linux/amd64", "linux/amd64", "linux/amd64"}
+	paths := failedFields(t, a.Validate())
+	require.ElementsMatch(t, []string{
+		"spec.source.platforms[0]",
+		"spec.source.platforms[1]",
+		"spec.source.platforms[3]",
+	}, paths)
+}
+
 func TestDeploymentValidate_OK(t *testing.T) {
 	d := &Deployment{
 		Metadata: ObjectMeta{Namespace: "default", Name: "prod"},
@@ -238,6 +460,40 @@ func TestDeploymentValidate_OK(t *testing.T) {
 	require.NoError(t, d.Validate())
 }
 
+func TestDeploymentValidate_Resources(t *testing.T) {
+	d := &Deployment{
+		Metadata: ObjectMeta{Namespace: "default", Name: "prod"},
+		Spec: DeploymentSpec{
+			TargetRef:  ResourceRef{Kind: KindMCPServer, Name: "files", Tag: "stable"},
+			RuntimeRef: ResourceRef{Kind: KindRuntime, Name: "local"},
+			Resources:  &ResourceRequirements{Limits: &ComputeResources{CPU: "500m", Memory: "512Mi"}},
+		},
+	}
+	require.NoError(t, d.Validate())
+
+	d.Spec.Resources.Requests = &ComputeResources{Memory: "1Gi"}
+	require.Equal(t, []string{"spec.resources.requests.memory"}, failedFields(t, d.Validate()))
+
+	d.Spec.Resources.Requests = nil
+	d.Spec.TargetRef = ResourceRef{Kind: KindChart, Name: "chart", Tag: "stable"}
+	require.Contains(t, failedFields(t, d.Validate()), "spec.resources")
+}
+
+func TestDeploymentValidate_Snapshot(t *testing.T) {
+	d := &Deployment{
+		Metadata: ObjectMeta{Namespace: "default", Name: "prod"},
+		Spec: DeploymentSpec{
+			TargetRef:  ResourceRef{Kind: KindAgent, Name: "alice", Tag: "stable"},
+			RuntimeRef: ResourceRef{Kind: KindRuntime, Name: "local"},
+			Snapshot:   "2024-06-release",
+		},
+	}
+	require.NoError(t, d.Validate())
+
+	d.Spec.Snapshot = "June Release"
+	require.Equal(t, []string{"spec.snapshot"}, failedFields(t, d.Validate()))
+}
+
 func TestDeploymentValidate_HarnessSelectionOK(t *testing.T) {
 	d := &Deployment{
 		Metadata: ObjectMeta{Namespace: "default", Name: "prod"},
@@ -280,6 +536,57 @@ func TestDeploymentValidate_RejectsHarnessSelectionForMCPServer(t *testing.T) {
 	require.Contains(t, paths, "spec.harness")
 }
 
+func TestDeploymentValidate_RuntimeSelector(t *testing.T) {
+	d := &Deployment{
+		Metadata: ObjectMeta{Namespace: "default", Name: "prod"},
+		Spec: DeploymentSpec{
+			TargetRef:       ResourceRef{Kind: KindAgent, Name: "alice", Tag: "stable"},
+			RuntimeSelector: &RuntimeSelector{Type: "kubernetes", MatchLabels: map[string]string{"region": "eu"}},
+		},
+	}
+	require.NoError(t, d.Validate())
+	require.Equal(t, TypeKubernetes, d.Spec.RuntimeSelector.Type)
+
+	// ResolveRefs leaves the unplaced runtime alone.
+	var resolved []string
+	require.NoError(t, d.ResolveRefs(context.Background(), func(_ context.Context, ref ResourceRef) error {
+		resolved = append(resolved, ref.Kind)
+		return nil
+	}))
+	require.Equal(t, []string{KindAgent}, resolved)
+
+	d.Spec.RuntimeSelector = &RuntimeSelector{Type: "nomad", MatchLabels: map[string]string{"region": "eu west"}}
+	paths := failedFields(t, d.Validate())
+	require.ElementsMatch(t, []string{"spec.runtimeSelector.type", "spec.runtimeSelector.matchLabels[region]"}, paths)
+
+	d.Spec.RuntimeSelector = nil
+	paths = failedFields(t, d.Validate())
+	require.Contains(t, paths, "spec.runtimeRef.kind")
+}
+
+func TestDeploymentValidate_SecretRefs(t *testing.T) {
+	d := &Deployment{
+		Metadata: ObjectMeta{Namespace: "default", Name: "prod"},
+		Spec: DeploymentSpec{
+			TargetRef:  ResourceRef{Kind: KindAgent, Name: "alice", Tag: "stable"},
+			RuntimeRef: ResourceRef{Kind: KindRuntime, Name: "k8s"},
+			Env: map[string]string{
+				"OK":       "secretRef:alice-keys/openai",
+				"NO_KEY":   "secretRef:alice-keys",
+				"BAD_NAME": "secretRef:Alice_Keys/openai",
+				"PLAIN":    "value",
+			},
+		},
+	}
+	paths := failedFields(t, d.Validate())
+	require.ElementsMatch(t, []string{"spec.env.BAD_NAME", "spec.env.NO_KEY"}, paths)
+
+	d.Spec.TargetRef = ResourceRef{Kind: KindMCPServer, Name: "weather", Tag: "stable"}
+	d.Spec.Env = map[string]string{"TOKEN": "secretRef:weather/token"}
+	paths = failedFields(t, d.Validate())
+	require.Equal(t, []string{"spec.env.TOKEN"}, paths)
+}
+
 func TestDeploymentValidate_RejectsBadTargetKind(t *testing.T) {
 	d := &Deployment{
 		Metadata: ObjectMeta{Namespace: "default", Name: "prod"},
@@ -425,6 +732,19 @@ func TestRuntimeValidate_RejectsUnknownType(t *testing.T) {
 	require.Contains(t, err.Error(), "heroku")
 }
 
+func TestRuntimeValidate_RegistryURL(t *testing.T) {
+	r := &Runtime{
+		Metadata: ObjectMeta{Namespace: "default", Name: "k8s"},
+		Spec:     RuntimeSpec{Type: TypeKubernetes, RegistryURL: "http://agentregistry.agentregistry.svc:12121"},
+	}
+	require.NoError(t, r.Validate())
+
+	r.Spec.RegistryURL = "agentregistry:12121"
+	err := r.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "spec.registryURL")
+}
+
 // TestRuntimeValidate_CanonicalizesType ensures Validate rewrites
 // Spec.Type to its canonical CamelCase form regardless of input casing.
 // Downstream adapter dispatch relies on exact-match equality, so the
@@ -441,6 +761,92 @@ func TestRuntimeValidate_CanonicalizesType(t *testing.T) {
 	}
 }
 
+func TestRuntimeValidate_DeploymentDefaults(t *testing.T) {
+	r := &Runtime{
+		Metadata: ObjectMeta{Namespace: "default", Name: "local"},
+		Spec: RuntimeSpec{Type: TypeLocal, DeploymentDefaults: &DeploymentDefaults{
+			Env: map[string]string{
+				"HTTPS_PROXY": "http://proxy:3128",
+				"OTEL_TOKEN":  "secretRef:telemetry/token",
+			},
+		}},
+	}
+	require.NoError(t, r.Validate())
+
+	r.Spec.DeploymentDefaults.Env["BAD"] = "secretRef:telemetry"
+	err := r.Validate()
+	require.Error(t, err)
+	require.Equal(t, []string{"spec.deploymentDefaults.env.BAD"}, failedFields(t, err))
+}
+
+func TestRuntimeValidate_CostAllocation(t *testing.T) {
+	r := &Runtime{
+		Metadata: ObjectMeta{Namespace: "default", Name: "kube"},
+		Spec: RuntimeSpec{Type: TypeKubernetes, CostAllocation: &CostAllocation{
+			Tags:             map[string]string{"cost-center": "ml", "example.com/owner": "platform"},
+			DeploymentLabels: []string{"team", "project"},
+		}},
+	}
+	require.NoError(t, r.Validate())
+
+	r.Spec.CostAllocation.Tags["cost-center"] = "ml platform"
+	r.Spec.CostAllocation.DeploymentLabels = append(r.Spec.CostAllocation.DeploymentLabels, "-bad")
+	err := r.Validate()
+	require.Error(t, err)
+	require.ElementsMatch(t, []string{"spec.costAllocation.tags[cost-center]", "spec.costAllocation.deploymentLabels[2]"}, failedFields(t, err))
+}
+
+func TestCostAllocationTags(t *testing.T) {
+	deployment := &Deployment{Metadata: ObjectMeta{
+		Namespace: "default",
+		Name:      "summarizer-prod",
+		Labels:    map[string]string{"team": "payments", "tier": "gold"},
+	}}
+	runtime := &Runtime{Spec: RuntimeSpec{Type: TypeKubernetes}}
+	require.Nil(t, CostAllocationTags(deployment, runtime))
+
+	runtime.Spec.CostAllocation = &CostAllocation{
+		Tags:             map[string]string{"cost-center": "ml", "team": "unassigned"},
+		DeploymentLabels: []string{"team", "project"},
+	}
+	require.Equal(t, map[string]string{
+		"cost-center":                 "ml",
+		"team":                        "payments",
+		CostAllocationDeploymentIDTag: "summarizer-prod",
+	}, CostAllocationTags(deployment, runtime))
+}
+
+func TestMergeDeploymentDefaults(t *testing.T) {
+	defaults := &DeploymentDefaults{
+		Env: map[string]string{
+			"HTTPS_PROXY": "http://proxy:3128",
+			"LOG_LEVEL":   "info",
+			"OTEL_TOKEN":  "secretRef:telemetry/token",
+		},
+		RuntimeConfig: map[string]any{"replicas": 2, "namespace": "agents"},
+	}
+	spec := DeploymentSpec{
+		TargetRef:     ResourceRef{Kind: KindAgent, Name: "summarizer"},
+		Env:           map[string]string{"LOG_LEVEL": "debug"},
+		RuntimeConfig: map[string]any{"replicas": 1},
+	}
+
+	merged := MergeDeploymentDefaults(spec, defaults)
+	require.Equal(t, map[string]string{
+		"HTTPS_PROXY": "http://proxy:3128",
+		"LOG_LEVEL":   "debug",
+		"OTEL_TOKEN":  "secretRef:telemetry/token",
+	}, merged.Env)
+	require.Equal(t, map[string]any{"replicas": 1, "namespace": "agents"}, merged.RuntimeConfig)
+	require.Equal(t, map[string]string{"LOG_LEVEL": "debug"}, spec.Env, "input spec must not be modified")
+
+	spec.TargetRef.Kind = KindMCPServer
+	merged = MergeDeploymentDefaults(spec, defaults)
+	require.NotContains(t, merged.Env, "OTEL_TOKEN", "secretRef defaults only reach Agent deployments")
+
+	require.Equal(t, spec, MergeDeploymentDefaults(spec, nil))
+}
+
 // -----------------------------------------------------------------------------
 // MCPServer
 // -----------------------------------------------------------------------------
@@ -476,6 +882,70 @@ func TestMCPServerValidate_RejectsBadRemote(t *testing.T) {
 	require.Contains(t, paths, "spec.remote.url")
 }
 
+func TestMCPServerValidate_RemoteOAuth(t *testing.T) {
+	mk := func(oauth *MCPRemoteOAuth, headers ...HTTPHeader) *MCPServer {
+		return &MCPServer{
+			Metadata: ObjectMeta{Namespace: "default", Name: "tools", Tag: "v1"},
+			Spec: MCPServerSpec{
+				Remote: &MCPRemote{Type: "streamable-http", URL: "https://example.test/mcp", Headers: headers, OAuth: oauth},
+			},
+		}
+	}
+	require.NoError(t, mk(&MCPRemoteOAuth{
+		Issuer: "https://auth.example.test", Scopes: []string{"tools:read", "tools:write"},
+		Resource: "https://example.test/mcp",
+	}, HTTPHeader{Name: "X-Tenant", Value: "acme"}).Validate())
+
+	paths := failedFields(t, mk(&MCPRemoteOAuth{
+		Issuer:   "http://auth.example.test?tenant=acme",
+		Scopes:   []string{"read", "read", "has space"},
+		Resource: "/mcp",
+	}, HTTPHeader{Name: "authorization", Value: "Bearer static"}).Validate())
+	require.ElementsMatch(t, []string{
+		"spec.remote.oauth.issuer", "spec.remote.oauth.resource",
+		"spec.remote.oauth.scopes[1]", "spec.remote.oauth.scopes[2]",
+		"spec.remote.headers[0]",
+	}, paths)
+
+	paths = failedFields(t, mk(&MCPRemoteOAuth{}).Validate())
+	require.Equal(t, []string{"spec.remote.oauth.issuer"}, paths)
+
+	server := mk(nil)
+	require.Equal(t, "https://example.test/mcp", server.Spec.Remote.ResourceIndicator())
+	server.Spec.Remote.OAuth = &MCPRemoteOAuth{Resource: "https://example.test"}
+	require.Equal(t, "https://example.test", server.Spec.Remote.ResourceIndicator())
+}
+
+func TestMCPServerValidate_RemoteHeaderTemplates(t *testing.T) {
+	server := &MCPServer{
+		Metadata: ObjectMeta{Namespace: "default", Name: "github", Tag: "v1"},
+		Spec: MCPServerSpec{
+			Remote: &MCPRemote{Type: "streamable-http", URL: "https://api.github.example/mcp", Headers: []HTTPHeader{
+				{Name: "Authorization", Value: `Bearer {{secret "github-token/token"}}`},
+				{Name: "X-Org", Value: `{{env "GITHUB_ORG"}}`},
+			}},
+		},
+	}
+	paths := failedFields(t, server.Validate())
+	require.Equal(t, []string{"spec.remote.headers[1].value"}, paths)
+}
+
+func TestMCPServerValidate_Tools(t *testing.T) {
+	m := &MCPServer{
+		Metadata: ObjectMeta{Namespace: "default", Name: "tools", Tag: "v1"},
+		Spec: MCPServerSpec{
+			Remote: &MCPRemote{Type: "streamable-http", URL: "https://example.test/mcp"},
+			Tools: []MCPTool{
+				{Name: "forecast", InputSchema: map[string]any{"type": "object"}},
+				{Name: ""},
+				{Name: "forecast"},
+			},
+		},
+	}
+	paths := failedFields(t, m.Validate())
+	require.ElementsMatch(t, []string{"spec.tools[1].name", "spec.tools[2].name"}, paths)
+}
+
 func TestMCPServerValidate_RemoteAndSourceMutuallyExclusive(t *testing.T) {
 	m := &MCPServer{
 		Metadata: ObjectMeta{Namespace: "default", Name: "tools", Tag: "v1"},
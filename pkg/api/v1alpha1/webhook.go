@@ -0,0 +1,71 @@
+package v1alpha1
+
+// Webhook is the typed envelope for kind=Webhook resources. A Webhook
+// subscribes an HTTP endpoint to registry lifecycle events in its
+// namespace, so downstream systems (CI, catalogs, chat bots) can react to
+// publishes and deployments without polling.
+type Webhook struct {
+	TypeMeta `json:",inline" yaml:",inline"`
+	Metadata ObjectMeta  `json:"metadata" yaml:"metadata"`
+	Spec     WebhookSpec `json:"spec" yaml:"spec"`
+	Status   Status      `json:"status,omitzero" yaml:"status,omitempty"`
+}
+
+func init() {
+	MustRegisterKind[*Webhook, WebhookSpec](KindWebhook, WithMutableObjectStorage())
+}
+
+// Webhook event types.
+const (
+	WebhookEventServerPublished   = "server.published"
+	WebhookEventAgentPublished    = "agent.published"
+	WebhookEventSkillPublished    = "skill.published"
+	WebhookEventDeploymentCreated = "deployment.created"
+	WebhookEventDeploymentFailed  = "deployment.failed"
+)
+
+// WebhookEvents lists every event a Webhook can subscribe to.
+var WebhookEvents = []string{
+	WebhookEventServerPublished,
+	WebhookEventAgentPublished,
+	WebhookEventSkillPublished,
+	WebhookEventDeploymentCreated,
+	WebhookEventDeploymentFailed,
+}
+
+// WebhookSecretEnvPrefix is the required prefix of spec.secretEnv. Limiting
+// webhooks to dedicated variables keeps a manifest from signing payloads
+// with unrelated server credentials.
+const WebhookSecretEnvPrefix = "AGENT_REGISTRY_WEBHOOK_SECRET_"
+
+// WebhookSpec describes one webhook subscription. Events are delivered as
+// signed JSON POSTs to URL, retried with exponential backoff on network
+// errors and 5xx/429 responses; every attempt is recorded in the delivery
+// log served at /v0/webhooks/{name}/deliveries. Deliveries whose attempts
+// all failed are kept at /v0/webhooks/{name}/dead-letters, retried on a
+// slower schedule and can be replayed.
+type WebhookSpec struct {
+	// URL is the http(s) endpoint events are POSTed to.
+	URL string `json:"url" yaml:"url"`
+	// Events narrows which event types are delivered. Empty subscribes to
+	// every event in WebhookEvents.
+	Events []string `json:"events,omitempty" yaml:"events,omitempty"`
+	// SecretEnv names the registry-server environment variable holding the
+	// HMAC secret. When set, deliveries carry an
+	// X-Agentregistry-Signature: sha256=<hex> header over the raw body. It
+	// must start with WebhookSecretEnvPrefix.
+	SecretEnv string `json:"secretEnv,omitempty" yaml:"secretEnv,omitempty"`
+}
+
+// Subscribes reports whether the webhook receives event.
+func (s *WebhookSpec) Subscribes(event string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+func (w *Webhook) Validate() error {
+	var errs FieldErrors
+	errs = append(errs, ValidateObjectMeta(w.Metadata)...)
+	errs = append(errs, validateWebhookSpec(&w.Spec)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateWebhookSpec(s *WebhookSpec) FieldErrors {
+	var errs FieldErrors
+	if s.URL == "" {
+		errs.Append("spec.url", fmt.Errorf("%w", ErrRequiredField))
+	} else if u, err := url.Parse(s.URL); err != nil || u.Host == "" {
+		errs.Append("spec.url", fmt.Errorf("%w: %q", ErrInvalidURL, s.URL))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		errs.Append("spec.url", fmt.Errorf("%w: scheme must be http or https", ErrInvalidURL))
+	}
+
+	for i, event := range s.Events {
+		path := fmt.Sprintf("spec.events[%d]", i)
+		switch {
+		case !slices.Contains(WebhookEvents, event):
+			errs.Append(path, fmt.Errorf("%w: unknown event %q (want one of %s)",
+				ErrInvalidFormat, event, strings.Join(WebhookEvents, ", ")))
+		case slices.Contains(s.Events[:i], event):
+			errs.Append(path, fmt.Errorf("%w: duplicate event %q", ErrInvalidFormat, event))
+		}
+	}
+
+	if s.SecretEnv != "" && (!strings.HasPrefix(s.SecretEnv, WebhookSecretEnvPrefix) || s.SecretEnv == WebhookSecretEnvPrefix) {
+		errs.Append("spec.secretEnv", fmt.Errorf("%w: must start with %s", ErrInvalidFormat, WebhookSecretEnvPrefix))
+	}
+	return errs
+}
@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWebhookValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    WebhookSpec
+		wantErr string // substring; empty means valid
+	}{
+		{
+			name: "all events",
+			spec: WebhookSpec{URL: "https://ci.example.com/hooks/registry"},
+		},
+		{
+			name: "selected events with secret",
+			spec: WebhookSpec{
+				URL:       "http://ci.internal:8080/hook",
+				Events:    []string{WebhookEventServerPublished, WebhookEventDeploymentFailed},
+				SecretEnv: WebhookSecretEnvPrefix + "CI",
+			},
+		},
+		{
+			name:    "missing url",
+			spec:    WebhookSpec{},
+			wantErr: "spec.url",
+		},
+		{
+			name:    "unsupported scheme",
+			spec:    WebhookSpec{URL: "ftp://ci.example.com/hook"},
+			wantErr: "scheme must be http or https",
+		},
+		{
+			name:    "unknown event",
+			spec:    WebhookSpec{URL: "https://ci.example.com", Events: []string{"prompt.published"}},
+			wantErr: "spec.events[0]",
+		},
+		{
+			name:    "duplicate event",
+			spec:    WebhookSpec{URL: "https://ci.example.com", Events: []string{WebhookEventAgentPublished, WebhookEventAgentPublished}},
+			wantErr: "spec.events[1]",
+		},
+		{
+			name:    "secret env outside prefix",
+			spec:    WebhookSpec{URL: "https://ci.example.com", SecretEnv: "AGENT_REGISTRY_JWT_PRIVATE_KEY"},
+			wantErr: "spec.secretEnv",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Webhook{
+				TypeMeta: TypeMeta{APIVersion: GroupVersion, Kind: KindWebhook},
+				Metadata: ObjectMeta{Namespace: "default", Name: "ci"},
+				Spec:     tt.spec,
+			}
+			err := w.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected valid, got: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error %q does not contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -18,7 +18,7 @@ func NewCommand(sources ...migrate.Source) *cobra.Command {
 	}
 	cmd.AddCommand(migrate.NewCommand(sources...))
 
-	// Hide --registry-url and --registry-token from help across the
+	// Hide --registry-url, --registry-token and --context from help across the
 	// entire `db` subtree. They are persistent flags on the arctl root,
 	// but db commands talk to Postgres directly via --db-url.
 	//
@@ -28,7 +28,7 @@ func NewCommand(sources ...migrate.Source) *cobra.Command {
 	// restores it after. Children of `db` that don't set their own
 	// HelpFunc walk the parent chain and pick this one up.
 	cmd.SetHelpFunc(func(c *cobra.Command, args []string) {
-		for _, name := range []string{"registry-url", "registry-token"} {
+		for _, name := range []string{"registry-url", "registry-token", "context"} {
 			if f := c.InheritedFlags().Lookup(name); f != nil {
 				f.Hidden = true
 				defer func(f *pflag.Flag) { f.Hidden = false }(f)
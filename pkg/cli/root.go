@@ -1,15 +1,18 @@
 package cli
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	internalcli "github.com/agentregistry-dev/agentregistry/internal/cli"
+	cliconfig "github.com/agentregistry-dev/agentregistry/internal/cli/config"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/configure"
 	clidaemon "github.com/agentregistry-dev/agentregistry/internal/cli/daemon"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/declarative"
 	"github.com/agentregistry-dev/agentregistry/internal/cli/scheme"
+	"github.com/agentregistry-dev/agentregistry/internal/httpclient"
 	"github.com/agentregistry-dev/agentregistry/internal/version"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	"github.com/agentregistry-dev/agentregistry/pkg/cli/db"
@@ -37,15 +40,47 @@ func Root(cfg Config) *cobra.Command {
 	}
 	var registryURL string
 	var registryToken string
+	var contextName string
 	rt := cliruntime.New(cliruntime.Config{
 		Env:             cfg.Env,
 		Auth:            cfg.Auth,
 		RegistryURL:     &registryURL,
 		RegistryToken:   &registryToken,
+		Context:         &contextName,
 		OnTokenResolved: cfg.OnTokenResolved,
 	})
-	root.PersistentFlags().StringVar(&registryURL, "registry-url", cfg.Env.Getenv("ARCTL_API_BASE_URL"), "Registry URL (overrides ARCTL_API_BASE_URL env var; defaults to http://localhost:12121)")
+	// The registry URL and token default to empty rather than their env
+	// vars so the runtime can tell a flag from the environment: a context
+	// named with --context beats the env vars but not the flags.
+	root.PersistentFlags().StringVar(&registryURL, "registry-url", "", "Registry URL (overrides --context and ARCTL_API_BASE_URL env var; defaults to http://localhost:12121)")
 	root.PersistentFlags().StringVar(&registryToken, "registry-token", "", "Registry bearer token (defaults to value of ARCTL_API_TOKEN env var)")
+	root.PersistentFlags().StringVar(&contextName, "context", "", "arctl context to target (overrides ARCTL_CONTEXT env var and the current context; see 'arctl config')")
+	_ = root.RegisterFlagCompletionFunc("context", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		contexts, err := rt.Contexts().Load()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return contexts.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+	var tlsOpts httpclient.TLSOptions
+	root.PersistentFlags().StringVar(&tlsOpts.CAFile, "ca-file", cfg.Env.Getenv("ARCTL_CA_FILE"), "PEM CA bundle trusted in addition to system roots (overrides ARCTL_CA_FILE env var)")
+	root.PersistentFlags().StringVar(&tlsOpts.CertFile, "client-cert", cfg.Env.Getenv("ARCTL_CLIENT_CERT"), "Client certificate for mTLS (overrides ARCTL_CLIENT_CERT env var)")
+	root.PersistentFlags().StringVar(&tlsOpts.KeyFile, "client-key", cfg.Env.Getenv("ARCTL_CLIENT_KEY"), "Client private key for mTLS (overrides ARCTL_CLIENT_KEY env var)")
+	root.PersistentFlags().BoolVar(&tlsOpts.InsecureSkipVerify, "insecure-skip-tls-verify", false, "Skip TLS certificate verification (development only)")
+	var proxyOpts httpclient.ProxyOptions
+	root.PersistentFlags().StringVar(&proxyOpts.URL, "proxy", cfg.Env.Getenv("ARCTL_PROXY"), "HTTP(S) or SOCKS5 proxy URL for outbound calls (overrides ARCTL_PROXY; defaults to HTTP_PROXY/HTTPS_PROXY)")
+	root.PersistentFlags().StringVar(&proxyOpts.NoProxy, "no-proxy", cfg.Env.Getenv("ARCTL_NO_PROXY"), "Comma-separated hosts that bypass --proxy (overrides ARCTL_NO_PROXY)")
+	// Trust and proxy settings are installed process-wide before any command
+	// runs so the registry client and every other outbound call share them.
+	configureOutbound := func() error {
+		if err := httpclient.Configure(httpclient.Options{TLS: tlsOpts, Proxy: proxyOpts}); err != nil {
+			return fmt.Errorf("configuring outbound HTTP: %w", err)
+		}
+		return nil
+	}
+	root.PersistentPreRunE = func(*cobra.Command, []string) error {
+		return configureOutbound()
+	}
 
 	kinds := scheme.NewRegistry(scheme.All()...)
 	for _, kind := range cfg.DeclarativeKinds {
@@ -73,7 +108,10 @@ func Root(cfg Config) *cobra.Command {
 		Kinds:   kinds,
 	}
 	root.AddCommand(configure.NewCommand(deps))
+	root.AddCommand(cliconfig.NewCommand(deps))
 	root.AddCommand(internalcli.NewVersionCommand(deps))
+	root.AddCommand(internalcli.NewDoctorCommand(nil))
+	root.AddCommand(internalcli.NewCleanCommand())
 	root.AddCommand(clidaemon.NewCommand(dockercompose.NewManager(dockercompose.DefaultConfig())))
 	root.AddCommand(declarative.NewApplyCmd(deps))
 	root.AddCommand(declarative.NewGetCmd(deps))
@@ -82,7 +120,16 @@ func Root(cfg Config) *cobra.Command {
 	root.AddCommand(declarative.NewBuildCmd(deps))
 	root.AddCommand(declarative.NewRunCmd(deps))
 	root.AddCommand(declarative.NewPullCmd(deps))
+	root.AddCommand(declarative.NewPushCmd(deps))
+	root.AddCommand(declarative.NewPublishCmd(deps))
 	root.AddCommand(declarative.NewWaitCmd(deps))
+	root.AddCommand(declarative.NewDeploymentCmd(deps))
+	root.AddCommand(declarative.NewRuntimeCmd(deps))
+	root.AddCommand(declarative.NewMCPCmd(deps))
+	root.AddCommand(declarative.NewAgentCmd(deps))
+	root.AddCommand(declarative.NewPromptCmd(deps))
+	root.AddCommand(declarative.NewRegistryCmd(deps))
+	root.AddCommand(declarative.NewAuthCmd(deps))
 	migrationSources := append([]migrate.Source{legacymigrate.OSSSource()}, cfg.ExtraMigrationSources...)
 	root.AddCommand(db.NewCommand(migrationSources...))
 
@@ -94,10 +141,41 @@ func Root(cfg Config) *cobra.Command {
 		}
 		root.AddCommand(cmd)
 	}
+	configureOutboundFirst(root, configureOutbound)
 
 	return root
 }
 
+// configureOutboundFirst makes every subcommand of parent that defines its
+// own persistent pre-run hook configure outbound HTTP before running it.
+// Cobra runs only the closest PersistentPreRun(E), so such a command (an
+// ExtraCommands entry, say) would otherwise skip the root's.
+func configureOutboundFirst(parent *cobra.Command, configure func() error) {
+	for _, cmd := range parent.Commands() {
+		switch {
+		case cmd.PersistentPreRunE != nil:
+			next := cmd.PersistentPreRunE
+			cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+				if err := configure(); err != nil {
+					return err
+				}
+				return next(c, args)
+			}
+		case cmd.PersistentPreRun != nil:
+			next := cmd.PersistentPreRun
+			cmd.PersistentPreRun = nil
+			cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+				if err := configure(); err != nil {
+					return err
+				}
+				next(c, args)
+				return nil
+			}
+		}
+		configureOutboundFirst(cmd, configure)
+	}
+}
+
 func removeDisabledCommands(root *cobra.Command, disabled map[string]bool) {
 	for path, disabled := range disabled {
 		if !disabled {
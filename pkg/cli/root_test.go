@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -49,3 +51,26 @@ func childCommand(parent *cobra.Command, name string) *cobra.Command {
 	}
 	return nil
 }
+
+func TestRootConfiguresOutboundHTTPBeforeExtraCommandHooks(t *testing.T) {
+	var hookRan bool
+	cfg := DefaultConfig()
+	cfg.ExtraCommands = []*cobra.Command{{
+		Use: "enterprise",
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			hookRan = true
+			return nil
+		},
+		RunE: func(*cobra.Command, []string) error { return nil },
+	}}
+
+	root := Root(cfg)
+	root.SetArgs([]string{"enterprise", "--ca-file", filepath.Join(t.TempDir(), "missing.pem")})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "configuring outbound HTTP") {
+		t.Fatalf("Execute() error = %v, want outbound HTTP configuration error", err)
+	}
+	if hookRan {
+		t.Fatal("extra command hook ran before outbound HTTP was configured")
+	}
+}
@@ -1,18 +1,30 @@
 package runtime
 
 const (
+	CommandAgent      = "agent"
 	CommandApply      = "apply"
+	CommandAuth       = "auth"
 	CommandBuild      = "build"
+	CommandClean      = "clean"
 	CommandCompletion = "completion"
+	CommandConfig     = "config"
 	CommandConfigure  = "configure"
 	CommandDaemon     = "daemon"
 	CommandDB         = "db"
 	CommandDelete     = "delete"
+	CommandDoctor     = "doctor"
+	CommandDeployment = "deployment"
 	CommandGet        = "get"
 	CommandHelp       = "help"
 	CommandInit       = "init"
+	CommandMCP        = "mcp"
+	CommandPrompt     = "prompt"
+	CommandPublish    = "publish"
 	CommandPull       = "pull"
+	CommandPush       = "push"
+	CommandRegistry   = "registry"
 	CommandRun        = "run"
+	CommandRuntime    = "runtime"
 	CommandVersion    = "version"
 	CommandWait       = "wait"
 )
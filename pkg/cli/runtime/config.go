@@ -9,10 +9,15 @@ import (
 
 // Config contains the shared runtime dependencies used by command constructors.
 type Config struct {
-	Env             Env
-	Auth            AuthProvider
-	RegistryURL     *string
-	RegistryToken   *string
+	Env           Env
+	Auth          AuthProvider
+	RegistryURL   *string
+	RegistryToken *string
+	// Context is the --context flag; empty falls back to ARCTL_CONTEXT and
+	// then the current context.
+	Context *string
+	// ConfigDir overrides where contexts are kept; see ConfigDir.
+	ConfigDir       string
 	OnTokenResolved func(token string) error
 }
 
@@ -0,0 +1,224 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	contextsFile    = "config.yaml"
+	credentialsFile = "credentials.yaml"
+)
+
+// Context is a named registry the CLI can target, like a kubeconfig
+// context: operators keep one per environment (dev, staging, prod) and
+// switch between them with `arctl config use-context` or --context.
+type Context struct {
+	Name        string `yaml:"name"`
+	RegistryURL string `yaml:"registry-url,omitempty"`
+	// Runtime is the Runtime a Deployment applied through this context is
+	// placed on when it names neither a runtimeRef nor a runtimeSelector.
+	Runtime string `yaml:"runtime,omitempty"`
+}
+
+// ContextConfig is the contents of the contexts file.
+type ContextConfig struct {
+	CurrentContext string    `yaml:"current-context,omitempty"`
+	Contexts       []Context `yaml:"contexts,omitempty"`
+}
+
+// Lookup returns the context called name.
+func (c *ContextConfig) Lookup(name string) (*Context, bool) {
+	i := slices.IndexFunc(c.Contexts, func(ctx Context) bool { return ctx.Name == name })
+	if i < 0 {
+		return nil, false
+	}
+	return &c.Contexts[i], true
+}
+
+// Names returns the context names in file order.
+func (c *ContextConfig) Names() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for _, ctx := range c.Contexts {
+		names = append(names, ctx.Name)
+	}
+	return names
+}
+
+// Set adds ctx, or replaces the context with the same name.
+func (c *ContextConfig) Set(ctx Context) {
+	if existing, ok := c.Lookup(ctx.Name); ok {
+		*existing = ctx
+		return
+	}
+	c.Contexts = append(c.Contexts, ctx)
+}
+
+// Delete removes the context called name, clearing current-context when
+// it pointed at it, and reports whether it existed.
+func (c *ContextConfig) Delete(name string) bool {
+	n := len(c.Contexts)
+	c.Contexts = slices.DeleteFunc(c.Contexts, func(ctx Context) bool { return ctx.Name == name })
+	if c.CurrentContext == name {
+		c.CurrentContext = ""
+	}
+	return len(c.Contexts) != n
+}
+
+// ContextStore reads and writes the contexts file and the per-context
+// credentials kept next to it. Credentials live in their own file, readable
+// only by the owner, so the contexts file can be shared or checked in.
+type ContextStore struct {
+	// Dir holds config.yaml and credentials.yaml. An empty Dir stores
+	// nothing: loads return empty values and saves fail.
+	Dir string
+}
+
+// ConfigDir returns the directory arctl keeps contexts in. Honors
+// XDG_CONFIG_HOME; falls back to ~/.config/arctl, or "" when neither
+// XDG_CONFIG_HOME nor HOME is set.
+func ConfigDir(env Env) string {
+	if x := env.Getenv("XDG_CONFIG_HOME"); x != "" {
+		return filepath.Join(x, "arctl")
+	}
+	if home := env.Getenv("HOME"); home != "" {
+		return filepath.Join(home, ".config", "arctl")
+	}
+	return ""
+}
+
+// Path returns the location of the contexts file.
+func (s ContextStore) Path() string {
+	return filepath.Join(s.Dir, contextsFile)
+}
+
+// Load reads the contexts file. A missing file is an empty config.
+func (s ContextStore) Load() (*ContextConfig, error) {
+	cfg := &ContextConfig{}
+	if err := s.read(contextsFile, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Save writes the contexts file.
+func (s ContextStore) Save(cfg *ContextConfig) error {
+	return s.write(contextsFile, cfg, 0o644)
+}
+
+// Token returns the bearer token stored for the context called name, or
+// "" when none is stored.
+func (s ContextStore) Token(name string) (string, error) {
+	creds, err := s.credentials()
+	if err != nil {
+		return "", err
+	}
+	return creds[name].Token, nil
+}
+
+// SetToken stores token for the context called name; an empty token
+// removes the stored one.
+func (s ContextStore) SetToken(name, token string) error {
+	creds, err := s.credentials()
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		delete(creds, name)
+	} else {
+		creds[name] = contextCredentials{Token: token}
+	}
+	return s.write(credentialsFile, creds, 0o600)
+}
+
+type contextCredentials struct {
+	Token string `yaml:"token,omitempty"`
+}
+
+func (s ContextStore) credentials() (map[string]contextCredentials, error) {
+	creds := map[string]contextCredentials{}
+	if err := s.read(credentialsFile, &creds); err != nil {
+		return nil, err
+	}
+	if creds == nil {
+		creds = map[string]contextCredentials{}
+	}
+	return creds, nil
+}
+
+func (s ContextStore) read(file string, into any) error {
+	if s.Dir == "" {
+		return nil
+	}
+	path := filepath.Join(s.Dir, file)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, into); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return nil
+}
+
+func (s ContextStore) write(file string, v any, perm os.FileMode) error {
+	if s.Dir == "" {
+		return fmt.Errorf("no config directory: set XDG_CONFIG_HOME or HOME")
+	}
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", file, err)
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", s.Dir, err)
+	}
+	// Write to a temp file and rename, so an interrupted write never
+	// leaves a truncated config behind.
+	tmp, err := os.CreateTemp(s.Dir, file+".*")
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", file, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing %s: %w", file, err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing %s: %w", file, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", file, err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.Dir, file)); err != nil {
+		return fmt.Errorf("writing %s: %w", file, err)
+	}
+	return nil
+}
+
+type contextNameKey struct{}
+
+// WithContextName returns ctx carrying the name of the arctl context a
+// command targets. The runtime sets it before asking the AuthProvider for
+// a token, so providers that keep their own credential store can key it
+// by context.
+func WithContextName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextNameKey{}, name)
+}
+
+// ContextNameFrom returns the arctl context name set by WithContextName,
+// or "" when no context is in use.
+func ContextNameFrom(ctx context.Context) string {
+	name, _ := ctx.Value(contextNameKey{}).(string)
+	return name
+}
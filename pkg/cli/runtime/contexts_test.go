@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestContextStoreRoundTrip(t *testing.T) {
+	store := ContextStore{Dir: filepath.Join(t.TempDir(), "arctl")}
+
+	cfg, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() on a missing file error = %v", err)
+	}
+	if len(cfg.Contexts) != 0 {
+		t.Fatalf("Load() on a missing file = %+v, want empty", cfg)
+	}
+
+	cfg.Set(Context{Name: "dev", RegistryURL: "localhost:12121"})
+	cfg.Set(Context{Name: "prod", RegistryURL: "https://registry.example.com"})
+	cfg.Set(Context{Name: "dev", RegistryURL: "localhost:8080", Runtime: "local"})
+	cfg.CurrentContext = "dev"
+	if err := store.Save(cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.SetToken("prod", "secret"); err != nil {
+		t.Fatalf("SetToken() error = %v", err)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.CurrentContext != "dev" || len(got.Contexts) != 2 {
+		t.Fatalf("Load() = %+v", got)
+	}
+	if dev, _ := got.Lookup("dev"); dev.RegistryURL != "localhost:8080" || dev.Runtime != "local" {
+		t.Fatalf("dev = %+v, want the updated context", dev)
+	}
+	if token, _ := store.Token("prod"); token != "secret" {
+		t.Fatalf("Token(prod) = %q, want secret", token)
+	}
+	if token, _ := store.Token("dev"); token != "" {
+		t.Fatalf("Token(dev) = %q, want empty", token)
+	}
+	info, err := os.Stat(filepath.Join(store.Dir, credentialsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("credentials file mode = %o, want 600", perm)
+	}
+
+	if !got.Delete("dev") || got.CurrentContext != "" {
+		t.Fatalf("Delete(dev) left %+v", got)
+	}
+	if got.Delete("dev") {
+		t.Fatal("Delete(dev) twice reported true")
+	}
+	if err := store.SetToken("prod", ""); err != nil {
+		t.Fatal(err)
+	}
+	if token, _ := store.Token("prod"); token != "" {
+		t.Fatalf("Token(prod) after removal = %q", token)
+	}
+}
+
+func TestConfigDir(t *testing.T) {
+	if got := ConfigDir(mapEnv{"XDG_CONFIG_HOME": "/xdg", "HOME": "/home/me"}); got != filepath.Join("/xdg", "arctl") {
+		t.Fatalf("ConfigDir() = %q", got)
+	}
+	if got := ConfigDir(mapEnv{"HOME": "/home/me"}); got != filepath.Join("/home/me", ".config", "arctl") {
+		t.Fatalf("ConfigDir() = %q", got)
+	}
+	if got := ConfigDir(mapEnv{}); got != "" {
+		t.Fatalf("ConfigDir() = %q, want empty", got)
+	}
+}
@@ -16,12 +16,23 @@ import (
 type RegistryTarget struct {
 	BaseURL string
 	Token   string
+	// Context names the arctl context in effect, "" when none is.
+	Context string
+	// Runtime is the context's default Runtime for Deployments.
+	Runtime string
 }
 
 // Runtime is the command-facing runtime contract.
 type Runtime interface {
 	RegistryTarget() RegistryTarget
 	RegistryClient(ctx context.Context) (*client.Client, error)
+	// Context returns the arctl context in effect: the one named by
+	// --context or ARCTL_CONTEXT, else the current context. It returns nil
+	// when no context is in use.
+	Context() (*Context, error)
+	// Contexts returns the store the contexts and their credentials are
+	// kept in.
+	Contexts() ContextStore
 }
 
 // runtime owns per-root mutable state: flags, env-backed defaults, auth, and
@@ -39,42 +50,120 @@ func New(cfg Config) Runtime {
 	return &runtime{cfg: cfg}
 }
 
+func (r *runtime) Contexts() ContextStore {
+	dir := r.cfg.ConfigDir
+	if dir == "" {
+		dir = ConfigDir(r.cfg.Env)
+	}
+	return ContextStore{Dir: dir}
+}
+
+func (r *runtime) Context() (*Context, error) {
+	ctx, _, err := r.context()
+	return ctx, err
+}
+
+// context resolves the context in effect and whether it was selected
+// explicitly, by --context or ARCTL_CONTEXT, rather than by being current.
+func (r *runtime) context() (*Context, bool, error) {
+	var name string
+	if r.cfg.Context != nil {
+		name = *r.cfg.Context
+	}
+	if name == "" {
+		name = r.cfg.Env.Getenv("ARCTL_CONTEXT")
+	}
+	explicit := name != ""
+
+	cfg, err := r.Contexts().Load()
+	if err != nil {
+		return nil, explicit, err
+	}
+	if name == "" {
+		name = cfg.CurrentContext
+	}
+	if name == "" {
+		return nil, false, nil
+	}
+	ctx, ok := cfg.Lookup(name)
+	if !ok {
+		return nil, explicit, fmt.Errorf("context %q not found in %s", name, r.Contexts().Path())
+	}
+	return ctx, explicit, nil
+}
+
+// RegistryTarget resolves the registry URL and token. Flags always win. A
+// context selected with --context or ARCTL_CONTEXT comes next, so naming a
+// context is never silently overridden by ARCTL_API_BASE_URL or
+// ARCTL_API_TOKEN left in the shell; those in turn override the current
+// context. A context that cannot be resolved is ignored here;
+// RegistryClient reports it.
 func (r *runtime) RegistryTarget() RegistryTarget {
+	ctx, explicit, err := r.context()
+	if err != nil {
+		ctx = nil
+	}
+	var contextToken string
+	if ctx != nil {
+		contextToken, _ = r.Contexts().Token(ctx.Name)
+	}
+
 	var baseURL string
 	if r.cfg.RegistryURL != nil {
 		baseURL = *r.cfg.RegistryURL
 	}
+	if baseURL == "" && explicit && ctx != nil {
+		baseURL = ctx.RegistryURL
+	}
 	if baseURL == "" {
 		baseURL = r.cfg.Env.Getenv("ARCTL_API_BASE_URL")
 	}
+	if baseURL == "" && ctx != nil {
+		baseURL = ctx.RegistryURL
+	}
 
 	var token string
 	if r.cfg.RegistryToken != nil {
 		token = *r.cfg.RegistryToken
 	}
+	if token == "" && explicit {
+		token = contextToken
+	}
 	if token == "" {
 		token = r.cfg.Env.Getenv("ARCTL_API_TOKEN")
 	}
+	if token == "" {
+		token = contextToken
+	}
 
-	return RegistryTarget{
+	target := RegistryTarget{
 		BaseURL: normalizeBaseURL(baseURL),
 		Token:   token,
 	}
+	if ctx != nil {
+		target.Context, target.Runtime = ctx.Name, ctx.Runtime
+	}
+	return target
 }
 
 // RegistryClient returns the shared registry client for this CLI invocation.
 //
-// Client creation is lazy and best-effort: the runtime uses a flag/env token if
-// present, asks AuthProvider for a token when one is not already configured,
+// Client creation is lazy and best-effort: the runtime uses a flag, env or
+// context token if present, asks AuthProvider for a token (with the context
+// name attached, see ContextNameFrom) when one is not already configured,
 // and still returns an unauthenticated client when no token is found. Commands
 // that do not need the registry should not call this method. Commands that need
 // authenticated endpoints can rely on the server response if no usable token is
 // available.
 func (r *runtime) RegistryClient(ctx context.Context) (*client.Client, error) {
 	r.clientOnce.Do(func() {
+		if _, err := r.Context(); err != nil {
+			r.clientErr = err
+			return
+		}
 		target := r.RegistryTarget()
 		if target.Token == "" {
-			token, err := r.cfg.Auth.Token(ctx)
+			token, err := r.cfg.Auth.Token(WithContextName(ctx, target.Context))
 			if errors.Is(err, types.ErrCLINoStoredToken) {
 				token = ""
 				err = nil
@@ -54,3 +54,99 @@ func TestRegistryClientReturnsAuthProviderError(t *testing.T) {
 		t.Fatal("RegistryClient() returned client for auth error")
 	}
 }
+
+type mapEnv map[string]string
+
+func (e mapEnv) Getenv(key string) string { return e[key] }
+
+func TestRegistryTargetResolvesContexts(t *testing.T) {
+	dir := t.TempDir()
+	store := ContextStore{Dir: dir}
+	if err := store.Save(&ContextConfig{
+		CurrentContext: "dev",
+		Contexts: []Context{
+			{Name: "dev", RegistryURL: "dev.example.com"},
+			{Name: "prod", RegistryURL: "https://prod.example.com", Runtime: "prod-k8s"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetToken("prod", "prod-token"); err != nil {
+		t.Fatal(err)
+	}
+	envVars := mapEnv{"ARCTL_API_BASE_URL": "https://env.example.com", "ARCTL_API_TOKEN": "env-token"}
+
+	for _, tc := range []struct {
+		name        string
+		flagURL     string
+		flagContext string
+		env         mapEnv
+		want        RegistryTarget
+	}{
+		{
+			name: "current context",
+			env:  mapEnv{},
+			want: RegistryTarget{BaseURL: "http://dev.example.com", Context: "dev"},
+		},
+		{
+			name: "env beats current context",
+			env:  envVars,
+			want: RegistryTarget{BaseURL: "https://env.example.com", Token: "env-token", Context: "dev"},
+		},
+		{
+			name:        "--context beats env",
+			flagContext: "prod",
+			env:         envVars,
+			want:        RegistryTarget{BaseURL: "https://prod.example.com", Token: "prod-token", Context: "prod", Runtime: "prod-k8s"},
+		},
+		{
+			name: "ARCTL_CONTEXT beats env",
+			env:  mapEnv{"ARCTL_CONTEXT": "prod", "ARCTL_API_BASE_URL": "https://env.example.com"},
+			want: RegistryTarget{BaseURL: "https://prod.example.com", Token: "prod-token", Context: "prod", Runtime: "prod-k8s"},
+		},
+		{
+			name:        "flag beats --context",
+			flagURL:     "https://flag.example.com",
+			flagContext: "prod",
+			env:         mapEnv{},
+			want:        RegistryTarget{BaseURL: "https://flag.example.com", Token: "prod-token", Context: "prod", Runtime: "prod-k8s"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := New(Config{Env: tc.env, ConfigDir: dir, RegistryURL: &tc.flagURL, Context: &tc.flagContext})
+			if got := rt.RegistryTarget(); got != tc.want {
+				t.Fatalf("RegistryTarget() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRegistryClientUnknownContext(t *testing.T) {
+	name := "missing"
+	rt := New(Config{Env: mapEnv{}, ConfigDir: t.TempDir(), Context: &name})
+	if _, err := rt.RegistryClient(context.Background()); err == nil {
+		t.Fatal("RegistryClient() error = nil, want unknown context error")
+	}
+}
+
+func TestRegistryClientPassesContextToAuthProvider(t *testing.T) {
+	dir := t.TempDir()
+	if err := (ContextStore{Dir: dir}).Save(&ContextConfig{CurrentContext: "staging", Contexts: []Context{{Name: "staging"}}}); err != nil {
+		t.Fatal(err)
+	}
+	var gotContext string
+	rt := New(Config{
+		Env:       mapEnv{},
+		ConfigDir: dir,
+		Auth: authProviderFunc(func(ctx context.Context) (string, error) {
+			gotContext = ContextNameFrom(ctx)
+			return "", nil
+		}),
+	})
+	if _, err := rt.RegistryClient(context.Background()); err != nil {
+		t.Fatalf("RegistryClient() error = %v", err)
+	}
+	if gotContext != "staging" {
+		t.Fatalf("AuthProvider saw context %q, want staging", gotContext)
+	}
+}
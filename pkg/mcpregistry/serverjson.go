@@ -0,0 +1,181 @@
+package mcpregistry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// ParseServerJSON decodes a `server.json` document and translates it with
+// ToMCPServer.
+func ParseServerJSON(data []byte) (*v1alpha1.MCPServer, error) {
+	var detail ServerDetail
+	if err := json.Unmarshal(data, &detail); err != nil {
+		return nil, fmt.Errorf("decode server.json: %w", err)
+	}
+	return ToMCPServer(detail)
+}
+
+// ToMCPServer translates a `server.json` document into an MCPServer. It is
+// the write-side counterpart of FromMCPServer: the server name splits into
+// (namespace, name) via ParseServerName and the version becomes the tag. An
+// MCPServer carries a single distribution, so the first package wins and
+// remotes are only used when there is no package. Package registry types
+// other than npm, pypi and oci have no v1alpha1 origin and are rejected.
+func ToMCPServer(detail ServerDetail) (*v1alpha1.MCPServer, error) {
+	namespace, name, err := ParseServerName(detail.Name)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Version == "" {
+		return nil, fmt.Errorf("server %s: version is required", detail.Name)
+	}
+	server := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Namespace: namespace, Name: name, Tag: detail.Version},
+		Spec: v1alpha1.MCPServerSpec{
+			Title:       detail.Title,
+			Description: detail.Description,
+		},
+	}
+	var repository *v1alpha1.Repository
+	if detail.Repository != nil && detail.Repository.URL != "" {
+		repository = &v1alpha1.Repository{URL: detail.Repository.URL, Subfolder: detail.Repository.Subfolder}
+	}
+	switch {
+	case len(detail.Packages) > 0:
+		pkg, err := packageFrom(detail.Name, detail.Packages[0])
+		if err != nil {
+			return nil, fmt.Errorf("server %s: %w", detail.Name, err)
+		}
+		server.Spec.Source = &v1alpha1.MCPServerSource{Package: pkg, Repository: repository}
+	case len(detail.Remotes) > 0:
+		remote := detail.Remotes[0]
+		server.Spec.Remote = &v1alpha1.MCPRemote{Type: normalizeRemoteType(remote.Type), URL: remote.URL}
+		for _, h := range remote.Headers {
+			server.Spec.Remote.Headers = append(server.Spec.Remote.Headers, v1alpha1.HTTPHeader{Name: h.Name, Value: h.Value})
+		}
+	case repository != nil:
+		server.Spec.Source = &v1alpha1.MCPServerSource{Repository: repository}
+	}
+	return server, nil
+}
+
+// packageFrom is the inverse of packageOf.
+func packageFrom(serverName string, p ServerPackage) (*v1alpha1.MCPPackage, error) {
+	out := &v1alpha1.MCPPackage{Origin: v1alpha1.MCPPackageOrigin{
+		Type:       v1alpha1.MCPPackageOriginType(p.RegistryType),
+		Identifier: p.Identifier,
+	}}
+	switch out.Origin.Type {
+	case v1alpha1.MCPPackageOriginTypeNPM:
+		out.Origin.NPM = &v1alpha1.MCPPackageOriginNPM{Version: p.Version, Mirror: p.RegistryBaseURL, ServerName: serverName}
+	case v1alpha1.MCPPackageOriginTypePyPI:
+		out.Origin.PyPI = &v1alpha1.MCPPackageOriginPyPI{Version: p.Version, Mirror: p.RegistryBaseURL, ServerName: serverName}
+	case v1alpha1.MCPPackageOriginTypeOCI:
+		out.Origin.OCI = &v1alpha1.MCPPackageOriginOCI{ServerName: serverName}
+		if ociVersionFromIdentifier(p.Identifier) == "" && p.Version != "" {
+			out.Origin.Identifier = p.Identifier + ":" + p.Version
+		}
+	default:
+		return nil, fmt.Errorf("package registry type %q is not supported; want npm, pypi or oci", p.RegistryType)
+	}
+	transport, err := transportFrom(p.Transport)
+	if err != nil {
+		return nil, err
+	}
+	out.Transport = transport
+	out.Launch = launchFrom(out.Origin, p)
+	return out, nil
+}
+
+// transportFrom is the inverse of packageTransportOf: streamable-http and
+// sse become http listening on the port and path of the transport URL.
+func transportFrom(t ServerTransport) (v1alpha1.MCPTransport, error) {
+	switch t.Type {
+	case "", "stdio":
+		return v1alpha1.MCPTransport{Type: "stdio"}, nil
+	case "streamable-http", "sse":
+		u, err := url.Parse(t.URL)
+		if err != nil {
+			return v1alpha1.MCPTransport{}, fmt.Errorf("transport url %q: %w", t.URL, err)
+		}
+		port, err := strconv.ParseUint(u.Port(), 10, 16)
+		if err != nil || port == 0 {
+			return v1alpha1.MCPTransport{}, fmt.Errorf("transport url %q must carry a numeric port", t.URL)
+		}
+		return v1alpha1.MCPTransport{Type: "http", Port: uint16(port), Path: u.Path}, nil
+	default:
+		return v1alpha1.MCPTransport{}, fmt.Errorf("transport type %q is not supported", t.Type)
+	}
+}
+
+// launchFrom is the inverse of packageOf. Nothing to map leaves Launch nil,
+// so the resolver derives the command from the origin. Otherwise Launch owns
+// the whole command line: npm and pypi default their runtime like the
+// resolver does and get the package spec between runtime and package
+// arguments unless an argument already names it, which is how packageOf
+// emits it. OCI runtime hints and arguments describe the container runtime
+// rather than the entrypoint and are dropped.
+func launchFrom(origin v1alpha1.MCPPackageOrigin, p ServerPackage) *v1alpha1.MCPPackageLaunch {
+	if p.RuntimeHint == "" && len(p.RuntimeArguments) == 0 && len(p.PackageArguments) == 0 && len(p.EnvironmentVariables) == 0 {
+		return nil
+	}
+	launch := &v1alpha1.MCPPackageLaunch{}
+	var spec string
+	switch origin.Type {
+	case v1alpha1.MCPPackageOriginTypeNPM:
+		launch.Command, spec = "npx", p.Identifier+"@"+p.Version
+		if p.RuntimeHint == "" {
+			launch.Args = append(launch.Args, positional("-y"))
+		}
+	case v1alpha1.MCPPackageOriginTypePyPI:
+		launch.Command, spec = "uvx", p.Identifier+"=="+p.Version
+	}
+	if origin.Type != v1alpha1.MCPPackageOriginTypeOCI {
+		if p.RuntimeHint != "" {
+			launch.Command = p.RuntimeHint
+		}
+		launch.Args = append(launch.Args, argumentsFrom(p.RuntimeArguments)...)
+		if !namesPackage(p.PackageArguments, p.Identifier) {
+			launch.Args = append(launch.Args, positional(spec))
+		}
+	}
+	launch.Args = append(launch.Args, argumentsFrom(p.PackageArguments)...)
+	for _, e := range p.EnvironmentVariables {
+		launch.Env = append(launch.Env, v1alpha1.MCPKeyValueInput{Name: e.Name, Value: e.Value, IsRequired: e.IsRequired})
+	}
+	return launch
+}
+
+// namesPackage reports whether a positional argument already carries the
+// package identifier.
+func namesPackage(args []ServerArgument, identifier string) bool {
+	for _, a := range args {
+		if strings.EqualFold(a.Type, string(v1alpha1.MCPArgumentTypePositional)) && strings.HasPrefix(a.Value, identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+func positional(value string) v1alpha1.MCPArgument {
+	return v1alpha1.MCPArgument{Type: v1alpha1.MCPArgumentTypePositional, Value: value}
+}
+
+// argumentsFrom is the inverse of argumentsOf.
+func argumentsFrom(args []ServerArgument) []v1alpha1.MCPArgument {
+	out := make([]v1alpha1.MCPArgument, 0, len(args))
+	for _, a := range args {
+		out = append(out, v1alpha1.MCPArgument{
+			Type:  v1alpha1.MCPArgumentType(strings.ToLower(a.Type)),
+			Name:  a.Name,
+			Value: a.Value,
+		})
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package mcpregistry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/mcpregistry"
+)
+
+func TestParseServerJSON(t *testing.T) {
+	server, err := mcpregistry.ParseServerJSON([]byte(`{
+		"name": "io.github.acme/weather",
+		"description": "Weather lookups",
+		"version": "1.2.0",
+		"repository": {"url": "https://github.com/acme/weather", "source": "github"},
+		"packages": [{
+			"registryType": "npm",
+			"identifier": "@acme/weather",
+			"version": "1.2.0",
+			"runtimeHint": "npx",
+			"runtimeArguments": [{"type": "positional", "value": "-y"}],
+			"packageArguments": [{"type": "named", "name": "--units", "value": "metric"}],
+			"environmentVariables": [{"name": "WEATHER_API_KEY", "isRequired": true}],
+			"transport": {"type": "stdio"}
+		}]
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, v1alpha1.ObjectMeta{Namespace: "io.github.acme", Name: "weather", Tag: "1.2.0"}, server.Metadata)
+	assert.Equal(t, "Weather lookups", server.Spec.Description)
+	require.NotNil(t, server.Spec.Source)
+	assert.Equal(t, "https://github.com/acme/weather", server.Spec.Source.Repository.URL)
+
+	pkg := server.Spec.Source.Package
+	require.NotNil(t, pkg)
+	require.NotNil(t, pkg.Origin.NPM)
+	assert.Equal(t, "1.2.0", pkg.Origin.NPM.Version)
+	assert.Equal(t, "io.github.acme/weather", pkg.Origin.NPM.ServerName)
+	assert.Equal(t, "stdio", pkg.Transport.Type)
+	require.NotNil(t, pkg.Launch)
+	assert.Equal(t, "npx", pkg.Launch.Command)
+	assert.Equal(t, []v1alpha1.MCPArgument{
+		{Type: v1alpha1.MCPArgumentTypePositional, Value: "-y"},
+		{Type: v1alpha1.MCPArgumentTypePositional, Value: "@acme/weather@1.2.0"},
+		{Type: v1alpha1.MCPArgumentTypeNamed, Name: "--units", Value: "metric"},
+	}, pkg.Launch.Args)
+	assert.Equal(t, []v1alpha1.MCPKeyValueInput{{Name: "WEATHER_API_KEY", IsRequired: true}}, pkg.Launch.Env)
+	assert.NoError(t, server.Validate())
+}
+
+func TestToMCPServer(t *testing.T) {
+	t.Run("oci http package", func(t *testing.T) {
+		server, err := mcpregistry.ToMCPServer(mcpregistry.ServerDetail{
+			Name:    "team-a/fetch",
+			Version: "0.3.1",
+			Packages: []mcpregistry.ServerPackage{{
+				RegistryType: "oci",
+				Identifier:   "ghcr.io/acme/fetch",
+				Version:      "0.3.1",
+				Transport:    mcpregistry.ServerTransport{Type: "streamable-http", URL: "http://localhost:8080/mcp"},
+			}},
+		})
+		require.NoError(t, err)
+		pkg := server.Spec.Source.Package
+		assert.Equal(t, "ghcr.io/acme/fetch:0.3.1", pkg.Origin.Identifier)
+		assert.Equal(t, v1alpha1.MCPTransport{Type: "http", Port: 8080, Path: "/mcp"}, pkg.Transport)
+		assert.Nil(t, pkg.Launch)
+		assert.NoError(t, server.Validate())
+	})
+
+	t.Run("remote", func(t *testing.T) {
+		server, err := mcpregistry.ToMCPServer(mcpregistry.ServerDetail{
+			Name:    "team-a/hosted",
+			Version: "2.0.0",
+			Remotes: []mcpregistry.ServerTransport{{
+				Type:    "sse",
+				URL:     "https://mcp.example.com/sse",
+				Headers: []mcpregistry.ServerInput{{Name: "X-Tenant", Value: "acme"}},
+			}},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, server.Spec.Source)
+		assert.Equal(t, &v1alpha1.MCPRemote{
+			Type:    "sse",
+			URL:     "https://mcp.example.com/sse",
+			Headers: []v1alpha1.HTTPHeader{{Name: "X-Tenant", Value: "acme"}},
+		}, server.Spec.Remote)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		in := &v1alpha1.MCPServer{
+			TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+			Metadata: v1alpha1.ObjectMeta{Namespace: "team-a", Name: "tools", Tag: "4.5.6"},
+			Spec: v1alpha1.MCPServerSpec{
+				Description: "Tools",
+				Source: &v1alpha1.MCPServerSource{Package: &v1alpha1.MCPPackage{
+					Origin: v1alpha1.MCPPackageOrigin{
+						Type:       v1alpha1.MCPPackageOriginTypePyPI,
+						Identifier: "acme-tools",
+						PyPI:       &v1alpha1.MCPPackageOriginPyPI{Version: "4.5.6", ServerName: "team-a/tools"},
+					},
+					Launch: &v1alpha1.MCPPackageLaunch{
+						Command: "uvx",
+						Args:    []v1alpha1.MCPArgument{{Type: v1alpha1.MCPArgumentTypePositional, Value: "acme-tools==4.5.6"}},
+					},
+					Transport: v1alpha1.MCPTransport{Type: "stdio"},
+				}},
+			},
+		}
+		out, err := mcpregistry.ToMCPServer(mcpregistry.FromMCPServer(in).Server)
+		require.NoError(t, err)
+		assert.Equal(t, in, out)
+	})
+
+	for name, detail := range map[string]mcpregistry.ServerDetail{
+		"bad name":         {Name: "no-slash", Version: "1.0.0"},
+		"missing version":  {Name: "team-a/x"},
+		"unsupported type": {Name: "team-a/x", Version: "1.0.0", Packages: []mcpregistry.ServerPackage{{RegistryType: "nuget", Identifier: "x"}}},
+		"http without port": {Name: "team-a/x", Version: "1.0.0", Packages: []mcpregistry.ServerPackage{{
+			RegistryType: "oci", Identifier: "ghcr.io/a/x:1", Transport: mcpregistry.ServerTransport{Type: "sse", URL: "http://localhost/sse"},
+		}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := mcpregistry.ToMCPServer(detail)
+			assert.Error(t, err)
+		})
+	}
+}
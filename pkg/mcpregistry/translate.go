@@ -288,7 +288,7 @@ func headersOf(headers []v1alpha1.HTTPHeader) []ServerInput {
 // officialMetaOf builds the registry-managed _meta block. isLatest reflects
 // whether this row is the literal "latest" tag (the default list serves only
 // that tag; the versions endpoints can surface pinned older tags too). Status
-// reflects soft-deletion.
+// reflects soft-deletion. DocScore comes from v1alpha1.DocScoreAnnotation.
 func officialMetaOf(s *v1alpha1.MCPServer) *OfficialMeta {
 	tag := s.Metadata.Tag
 	m := &OfficialMeta{Status: "active", IsLatest: tag == "" || tag == "latest"}
@@ -302,5 +302,8 @@ func officialMetaOf(s *v1alpha1.MCPServer) *OfficialMeta {
 	if s.Metadata.DeletionTimestamp != nil {
 		m.Status = "deleted"
 	}
+	if score, ok := v1alpha1.DocScoreOf(s.Metadata); ok {
+		m.DocScore = &score
+	}
 	return m
 }
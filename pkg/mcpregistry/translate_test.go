@@ -258,6 +258,18 @@ func TestFromMCPServer(t *testing.T) {
 				assert.Equal(t, "deleted", r.Meta.Official.Status)
 			},
 		},
+		{
+			name: "doc score annotation surfaces in official meta",
+			mutate: func(s *v1alpha1.MCPServer) {
+				s.Metadata.Annotations = map[string]string{v1alpha1.DocScoreAnnotation: "85"}
+			},
+			check: func(t *testing.T, r mcpregistry.ServerResponse) {
+				require.NotNil(t, r.Meta)
+				require.NotNil(t, r.Meta.Official)
+				require.NotNil(t, r.Meta.Official.DocScore)
+				assert.Equal(t, 85, *r.Meta.Official.DocScore)
+			},
+		},
 	}
 
 	for _, tt := range tests {
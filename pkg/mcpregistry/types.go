@@ -14,8 +14,10 @@
 // The types here mirror the v0.1 frozen spec exactly — field names use the
 // camelCase casing emitted by registry.modelcontextprotocol.io, list items are
 // wrapped in {server, _meta}, and the registry-managed metadata lives under the
-// reverse-DNS `_meta` key. The translation is one-directional (v1alpha1 →
-// server.json) and read-only; there is no publish/write path here.
+// reverse-DNS `_meta` key. FromMCPServer projects v1alpha1 → server.json for
+// the read-only catalogue; ToMCPServer goes the other way so `server.json`
+// files can be published as MCPServer resources (`arctl publish
+// --from-manifest-dir`). Neither direction writes to the registry.
 package mcpregistry
 
 // SchemaURL is the `$schema` value emitted on every ServerDetail. It pins the
@@ -67,6 +69,9 @@ type OfficialMeta struct {
 	PublishedAt     string `json:"publishedAt,omitempty"`
 	UpdatedAt       string `json:"updatedAt,omitempty"`
 	IsLatest        bool   `json:"isLatest"`
+	// DocScore is the documentation score (0-100) computed when the version
+	// was published; absent for versions published before scoring existed.
+	DocScore *int `json:"docScore,omitempty"`
 }
 
 // ServerDetail is the core `server.json` document.
@@ -0,0 +1,151 @@
+// Package mutators holds ready-made types.ManifestMutator implementations
+// for AppOptions.ManifestMutators:
+//
+//   - Labels adds labels and annotations to every workload and resource.
+//   - Proxy points every workload at an outbound HTTP proxy.
+//
+// They double as examples for platform-specific mutators, e.g. one adding
+// a policy sidecar to kmcp MCPServers through Spec.Deployment.Sidecars.
+// Both fill in keys a Deployment does not set itself and leave the ones it
+// does alone, so the registry's own labels and a Deployment's explicit env
+// win.
+package mutators
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	v1alpha2 "github.com/kagent-dev/kagent/go/api/v1alpha2"
+	kmcpv1alpha1 "github.com/kagent-dev/kmcp/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+var (
+	_ types.ComposeServiceMutator   = Labels{}
+	_ types.KubernetesObjectMutator = Labels{}
+	_ types.ComposeServiceMutator   = Proxy{}
+	_ types.KubernetesObjectMutator = Proxy{}
+)
+
+// Labels adds Labels and Annotations to every compose service, to every
+// kagent/kmcp resource and ConfigMap, and to the pods kagent and kmcp run.
+type Labels struct {
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+func (Labels) Name() string { return "labels" }
+
+func (l Labels) MutateComposeService(_ context.Context, _ types.ApplyInput, service *composetypes.ServiceConfig) error {
+	service.Labels = withDefaults(service.Labels, l.Labels)
+	service.Annotations = withDefaults(service.Annotations, l.Annotations)
+	return nil
+}
+
+func (l Labels) MutateKubernetesObject(_ context.Context, _ types.ApplyInput, obj client.Object) error {
+	obj.SetLabels(withDefaults(obj.GetLabels(), l.Labels))
+	obj.SetAnnotations(withDefaults(obj.GetAnnotations(), l.Annotations))
+	switch obj := obj.(type) {
+	case *v1alpha2.Agent:
+		if pod := agentDeployment(obj); pod != nil {
+			pod.Labels = withDefaults(pod.Labels, l.Labels)
+			pod.Annotations = withDefaults(pod.Annotations, l.Annotations)
+		}
+	case *kmcpv1alpha1.MCPServer:
+		obj.Spec.Deployment.Labels = withDefaults(obj.Spec.Deployment.Labels, l.Labels)
+		obj.Spec.Deployment.Annotations = withDefaults(obj.Spec.Deployment.Annotations, l.Annotations)
+	}
+	return nil
+}
+
+// Proxy sets the proxy environment variables, in upper and lower case, on
+// every compose service and on the pods kagent and kmcp run. Empty fields
+// are not set.
+type Proxy struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
+func (Proxy) Name() string { return "proxy" }
+
+func (p Proxy) MutateComposeService(_ context.Context, _ types.ApplyInput, service *composetypes.ServiceConfig) error {
+	env := p.env()
+	if len(env) == 0 {
+		return nil
+	}
+	if service.Environment == nil {
+		service.Environment = composetypes.MappingWithEquals{}
+	}
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		if _, ok := service.Environment[name]; !ok {
+			value := env[name]
+			service.Environment[name] = &value
+		}
+	}
+	return nil
+}
+
+func (p Proxy) MutateKubernetesObject(_ context.Context, _ types.ApplyInput, obj client.Object) error {
+	env := p.env()
+	if len(env) == 0 {
+		return nil
+	}
+	switch obj := obj.(type) {
+	case *v1alpha2.Agent:
+		pod := agentDeployment(obj)
+		if pod == nil {
+			return nil
+		}
+		for _, name := range slices.Sorted(maps.Keys(env)) {
+			if !slices.ContainsFunc(pod.Env, func(e corev1.EnvVar) bool { return e.Name == name }) {
+				pod.Env = append(pod.Env, corev1.EnvVar{Name: name, Value: env[name]})
+			}
+		}
+	case *kmcpv1alpha1.MCPServer:
+		obj.Spec.Deployment.Env = withDefaults(obj.Spec.Deployment.Env, env)
+	}
+	return nil
+}
+
+func (p Proxy) env() map[string]string {
+	env := map[string]string{}
+	for name, value := range map[string]string{"HTTP_PROXY": p.HTTPProxy, "HTTPS_PROXY": p.HTTPSProxy, "NO_PROXY": p.NoProxy} {
+		if value != "" {
+			env[name] = value
+			env[strings.ToLower(name)] = value
+		}
+	}
+	return env
+}
+
+// agentDeployment returns the pod settings of a kagent Agent, nil when
+// it has none to edit.
+func agentDeployment(agent *v1alpha2.Agent) *v1alpha2.SharedDeploymentSpec {
+	switch {
+	case agent.Spec.BYO != nil && agent.Spec.BYO.Deployment != nil:
+		return &agent.Spec.BYO.Deployment.SharedDeploymentSpec
+	case agent.Spec.Declarative != nil:
+		if agent.Spec.Declarative.Deployment == nil {
+			agent.Spec.Declarative.Deployment = &v1alpha2.DeclarativeDeploymentSpec{}
+		}
+		return &agent.Spec.Declarative.Deployment.SharedDeploymentSpec
+	}
+	return nil
+}
+
+// withDefaults returns m with every key of defaults it lacks added.
+func withDefaults[M ~map[string]string](m M, defaults map[string]string) M {
+	if len(defaults) == 0 {
+		return m
+	}
+	out := M(maps.Clone(defaults))
+	maps.Copy(out, m)
+	return out
+}
@@ -0,0 +1,88 @@
+package mutators_test
+
+import (
+	"context"
+	"testing"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	v1alpha2 "github.com/kagent-dev/kagent/go/api/v1alpha2"
+	kmcpv1alpha1 "github.com/kagent-dev/kmcp/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/mutators"
+	"github.com/agentregistry-dev/agentregistry/pkg/types"
+)
+
+func byoAgent() *v1alpha2.Agent {
+	return &v1alpha2.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "reporter", Labels: map[string]string{"team": "registry"}},
+		Spec: v1alpha2.AgentSpec{
+			Type: v1alpha2.AgentType_BYO,
+			BYO: &v1alpha2.BYOAgentSpec{Deployment: &v1alpha2.ByoDeploymentSpec{
+				Image: "ghcr.io/example/reporter:1",
+				SharedDeploymentSpec: v1alpha2.SharedDeploymentSpec{
+					Env: []corev1.EnvVar{{Name: "NO_PROXY", Value: "internal.corp"}},
+				},
+			}},
+		},
+	}
+}
+
+func TestLabels(t *testing.T) {
+	m := mutators.Labels{
+		Labels:      map[string]string{"org": "acme", "team": "platform"},
+		Annotations: map[string]string{"policy": "v2"},
+	}
+	ctx := context.Background()
+
+	service := &composetypes.ServiceConfig{Name: "weather", Labels: composetypes.Labels{"team": "registry"}}
+	require.NoError(t, m.MutateComposeService(ctx, types.ApplyInput{}, service))
+	require.Equal(t, composetypes.Labels{"org": "acme", "team": "registry"}, service.Labels)
+	require.Equal(t, composetypes.Mapping{"policy": "v2"}, service.Annotations)
+
+	agent := byoAgent()
+	require.NoError(t, m.MutateKubernetesObject(ctx, types.ApplyInput{}, agent))
+	require.Equal(t, map[string]string{"org": "acme", "team": "registry"}, agent.Labels)
+	require.Equal(t, map[string]string{"policy": "v2"}, agent.Annotations)
+	require.Equal(t, map[string]string{"org": "acme", "team": "platform"}, agent.Spec.BYO.Deployment.Labels)
+
+	server := &kmcpv1alpha1.MCPServer{}
+	require.NoError(t, m.MutateKubernetesObject(ctx, types.ApplyInput{}, server))
+	require.Equal(t, map[string]string{"policy": "v2"}, server.Spec.Deployment.Annotations)
+
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, m.MutateKubernetesObject(ctx, types.ApplyInput{}, configMap))
+	require.Equal(t, "acme", configMap.Labels["org"])
+}
+
+func TestProxy(t *testing.T) {
+	m := mutators.Proxy{HTTPSProxy: "http://proxy.corp:3128", NoProxy: "localhost"}
+	ctx := context.Background()
+
+	explicit := "http://other:8080"
+	service := &composetypes.ServiceConfig{Environment: composetypes.MappingWithEquals{"HTTPS_PROXY": &explicit}}
+	require.NoError(t, m.MutateComposeService(ctx, types.ApplyInput{}, service))
+	require.Equal(t, "http://other:8080", *service.Environment["HTTPS_PROXY"])
+	require.Equal(t, "http://proxy.corp:3128", *service.Environment["https_proxy"])
+	require.Equal(t, "localhost", *service.Environment["NO_PROXY"])
+	require.NotContains(t, service.Environment, "HTTP_PROXY")
+
+	agent := byoAgent()
+	require.NoError(t, m.MutateKubernetesObject(ctx, types.ApplyInput{}, agent))
+	require.Equal(t, []corev1.EnvVar{
+		{Name: "NO_PROXY", Value: "internal.corp"},
+		{Name: "HTTPS_PROXY", Value: "http://proxy.corp:3128"},
+		{Name: "https_proxy", Value: "http://proxy.corp:3128"},
+		{Name: "no_proxy", Value: "localhost"},
+	}, agent.Spec.BYO.Deployment.Env)
+
+	server := &kmcpv1alpha1.MCPServer{}
+	require.NoError(t, m.MutateKubernetesObject(ctx, types.ApplyInput{}, server))
+	require.Equal(t, "http://proxy.corp:3128", server.Spec.Deployment.Env["HTTPS_PROXY"])
+
+	// Idempotent: a second pass adds nothing.
+	require.NoError(t, m.MutateKubernetesObject(ctx, types.ApplyInput{}, agent))
+	require.Len(t, agent.Spec.BYO.Deployment.Env, 4)
+}
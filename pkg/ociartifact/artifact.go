@@ -0,0 +1,181 @@
+// Package ociartifact packages registry metadata as OCI artifacts (image-spec
+// v1.1 artifact manifests) so it can be stored and distributed through
+// existing OCI registries and tooling such as ORAS.
+//
+// An artifact is a small set of named files. Each file becomes one layer
+// whose org.opencontainers.image.title annotation carries the file name, so
+// `oras pull` writes the files back out under their original names. The
+// config blob is the empty JSON object; the artifact type rides on both the
+// manifest's artifactType field and the config media type.
+package ociartifact
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// MediaTypeLayoutTar is the content type of a WriteLayout tarball.
+const MediaTypeLayoutTar = "application/vnd.oci.image.layout.v1+tar"
+
+// File is one named payload inside an artifact.
+type File struct {
+	Name      string
+	MediaType string
+	Data      []byte
+}
+
+// Artifact is the input to Build.
+type Artifact struct {
+	ArtifactType string
+	Files        []File
+	// Annotations land on the manifest.
+	Annotations map[string]string
+	// Subject, when set, makes the artifact a referrer of another manifest
+	// (typically the container image the metadata describes).
+	Subject *ocispec.Descriptor
+}
+
+// Built is an encoded artifact: its manifest, the manifest's descriptor,
+// and every blob the manifest references keyed by digest.
+type Built struct {
+	Manifest   []byte
+	Descriptor ocispec.Descriptor
+	Blobs      map[digest.Digest][]byte
+}
+
+// Build encodes a as an OCI image manifest plus blobs.
+func (a Artifact) Build() (Built, error) {
+	if a.ArtifactType == "" {
+		return Built{}, fmt.Errorf("artifact type is required")
+	}
+	if len(a.Files) == 0 {
+		return Built{}, fmt.Errorf("artifact has no files")
+	}
+	blobs := map[digest.Digest][]byte{
+		ocispec.DescriptorEmptyJSON.Digest: ocispec.DescriptorEmptyJSON.Data,
+	}
+	layers := make([]ocispec.Descriptor, 0, len(a.Files))
+	seen := map[string]bool{}
+	for _, f := range a.Files {
+		if f.Name == "" || f.MediaType == "" {
+			return Built{}, fmt.Errorf("artifact file needs a name and media type")
+		}
+		if seen[f.Name] {
+			return Built{}, fmt.Errorf("duplicate artifact file %q", f.Name)
+		}
+		seen[f.Name] = true
+		d := digest.FromBytes(f.Data)
+		blobs[d] = f.Data
+		layers = append(layers, ocispec.Descriptor{
+			MediaType:   f.MediaType,
+			Digest:      d,
+			Size:        int64(len(f.Data)),
+			Annotations: map[string]string{ocispec.AnnotationTitle: f.Name},
+		})
+	}
+
+	// The config blob is `{}` but typed as the artifact type: registries and
+	// referrers fallback indexes that predate the artifactType field derive
+	// the type from config.mediaType, so setting both keeps type filters
+	// (`oras discover --artifact-type`) working everywhere.
+	config := ocispec.Descriptor{
+		MediaType: a.ArtifactType,
+		Digest:    ocispec.DescriptorEmptyJSON.Digest,
+		Size:      ocispec.DescriptorEmptyJSON.Size,
+	}
+	manifest := ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: a.ArtifactType,
+		Config:       config,
+		Layers:       layers,
+		Subject:      a.Subject,
+		Annotations:  a.Annotations,
+	}
+	raw, err := json.Marshal(manifest)
+	if err != nil {
+		return Built{}, fmt.Errorf("encode artifact manifest: %w", err)
+	}
+	return Built{
+		Manifest: raw,
+		Descriptor: ocispec.Descriptor{
+			MediaType:    ocispec.MediaTypeImageManifest,
+			ArtifactType: a.ArtifactType,
+			Digest:       digest.FromBytes(raw),
+			Size:         int64(len(raw)),
+		},
+		Blobs: blobs,
+	}, nil
+}
+
+// WriteLayout writes b to w as a tar of an OCI image layout with a single
+// manifest tagged ref. ORAS and other OCI tools read this directly, e.g.
+// `oras copy --from-oci-layout bundle.tar:<ref> <registry>/<repo>:<ref>`.
+func WriteLayout(w io.Writer, b Built, ref string) error {
+	desc := b.Descriptor
+	if ref != "" {
+		desc.Annotations = map[string]string{ocispec.AnnotationRefName: ref}
+	}
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	index, err := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{desc},
+	})
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(w)
+	write := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+		return nil
+	}
+	if err := write(ocispec.ImageLayoutFile, layout); err != nil {
+		return err
+	}
+	if err := write(ocispec.ImageIndexFile, index); err != nil {
+		return err
+	}
+	if err := write(blobPath(b.Descriptor.Digest), b.Manifest); err != nil {
+		return err
+	}
+	digests := make([]digest.Digest, 0, len(b.Blobs))
+	for d := range b.Blobs {
+		digests = append(digests, d)
+	}
+	sort.Slice(digests, func(i, j int) bool { return digests[i] < digests[j] })
+	for _, d := range digests {
+		if err := write(blobPath(d), b.Blobs[d]); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// LayoutFileName is the suggested file name for a layout tarball of
+// name@tag. Slashes in the name become dashes so the result is a single path
+// element.
+func LayoutFileName(name, tag string) string {
+	return strings.ReplaceAll(name, "/", "-") + "-" + tag + ".tar"
+}
+
+func blobPath(d digest.Digest) string {
+	return ocispec.ImageBlobsDir + "/" + d.Algorithm().String() + "/" + d.Encoded()
+}
@@ -0,0 +1,127 @@
+package ociartifact_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/ociartifact"
+)
+
+func readTar(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	files := map[string][]byte{}
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = body
+	}
+}
+
+func blobPath(d digest.Digest) string {
+	return "blobs/" + d.Algorithm().String() + "/" + d.Encoded()
+}
+
+func TestBuildValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		artifact ociartifact.Artifact
+	}{
+		{"missing artifact type", ociartifact.Artifact{Files: []ociartifact.File{{Name: "a", MediaType: "text/plain"}}}},
+		{"no files", ociartifact.Artifact{ArtifactType: "application/x-test"}},
+		{"unnamed file", ociartifact.Artifact{ArtifactType: "application/x-test", Files: []ociartifact.File{{MediaType: "text/plain"}}}},
+		{"duplicate file", ociartifact.Artifact{ArtifactType: "application/x-test", Files: []ociartifact.File{
+			{Name: "a", MediaType: "text/plain"}, {Name: "a", MediaType: "text/plain"},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.artifact.Build()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestWriteLayout(t *testing.T) {
+	subject := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromString("image"),
+		Size:      5,
+	}
+	built, err := ociartifact.Artifact{
+		ArtifactType: "application/x-test",
+		Files:        []ociartifact.File{{Name: "hello.txt", MediaType: "text/plain", Data: []byte("hello")}},
+		Subject:      &subject,
+	}.Build()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, ociartifact.WriteLayout(&buf, built, "v1"))
+	files := readTar(t, buf.Bytes())
+
+	require.JSONEq(t, `{"imageLayoutVersion":"1.0.0"}`, string(files["oci-layout"]))
+
+	var index ocispec.Index
+	require.NoError(t, json.Unmarshal(files["index.json"], &index))
+	require.Len(t, index.Manifests, 1)
+	require.Equal(t, built.Descriptor.Digest, index.Manifests[0].Digest)
+	require.Equal(t, "v1", index.Manifests[0].Annotations[ocispec.AnnotationRefName])
+
+	var manifest ocispec.Manifest
+	require.NoError(t, json.Unmarshal(files[blobPath(built.Descriptor.Digest)], &manifest))
+	require.Equal(t, "application/x-test", manifest.ArtifactType)
+	require.Equal(t, "application/x-test", manifest.Config.MediaType)
+	require.Equal(t, []byte("{}"), files[blobPath(manifest.Config.Digest)])
+	require.Equal(t, subject.Digest, manifest.Subject.Digest)
+	require.Len(t, manifest.Layers, 1)
+	require.Equal(t, "hello.txt", manifest.Layers[0].Annotations[ocispec.AnnotationTitle])
+	require.Equal(t, []byte("hello"), files[blobPath(manifest.Layers[0].Digest)])
+}
+
+func TestForObjectMCPServer(t *testing.T) {
+	server := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "team-a", Name: "weather", Tag: "1.2.0"},
+		Spec:     v1alpha1.MCPServerSpec{Title: "Weather", Description: "Forecasts"},
+	}
+	artifact, err := ociartifact.ForObject(server)
+	require.NoError(t, err)
+	require.Equal(t, "application/vnd.agentregistry.mcpserver.v1", artifact.ArtifactType)
+	require.Equal(t, "team-a", artifact.Annotations[ociartifact.AnnotationNamespace])
+	require.Equal(t, "1.2.0", artifact.Annotations[ociartifact.AnnotationTag])
+
+	byName := map[string]ociartifact.File{}
+	for _, f := range artifact.Files {
+		byName[f.Name] = f
+	}
+	require.Contains(t, string(byName[ociartifact.ManifestFile].Data), "name: weather")
+	require.Contains(t, string(byName[ociartifact.ReadmeFile].Data), "# Weather\n\nForecasts")
+	var card map[string]any
+	require.NoError(t, json.Unmarshal(byName[ociartifact.CardFile].Data, &card))
+	require.Equal(t, "team-a/weather", card["name"])
+}
+
+func TestForObjectSkipsCardForOtherKinds(t *testing.T) {
+	skill := &v1alpha1.Skill{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindSkill},
+		Metadata: v1alpha1.ObjectMeta{Name: "summarize", Tag: "latest"},
+	}
+	artifact, err := ociartifact.ForObject(skill)
+	require.NoError(t, err)
+	require.Len(t, artifact.Files, 2)
+	require.Contains(t, string(artifact.Files[1].Data), "# summarize")
+}
@@ -0,0 +1,121 @@
+package ociartifact
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/mcpregistry"
+)
+
+// File names inside a registry metadata artifact.
+const (
+	ManifestFile = "manifest.yaml"
+	ReadmeFile   = "README.md"
+	CardFile     = "server.json"
+)
+
+// Media types of the files inside a registry metadata artifact.
+const (
+	MediaTypeManifest = "application/vnd.agentregistry.manifest.v1+yaml"
+	MediaTypeReadme   = "text/markdown"
+	// MediaTypeServerCard is the MCP Registry server.json shape, the same
+	// document the /v0.1 compatibility API serves.
+	MediaTypeServerCard = "application/vnd.mcp.server.v1+json"
+)
+
+// Manifest annotations identifying the registry object an artifact describes.
+const (
+	AnnotationKind      = "dev.agentregistry.kind"
+	AnnotationNamespace = "dev.agentregistry.namespace"
+	AnnotationName      = "dev.agentregistry.name"
+	AnnotationTag       = "dev.agentregistry.tag"
+)
+
+// ArtifactType returns the artifactType used for objects of kind, e.g.
+// application/vnd.agentregistry.mcpserver.v1.
+func ArtifactType(kind string) string {
+	return artifactTypePrefix + strings.ToLower(kind) + ".v1"
+}
+
+const artifactTypePrefix = "application/vnd.agentregistry."
+
+// ForObject packages obj's metadata: the applyable manifest, a README
+// rendered from its title and description and, for MCPServers, the
+// server.json card.
+func ForObject(obj v1alpha1.Object) (Artifact, error) {
+	meta := obj.GetMetadata()
+	manifest, err := yaml.Marshal(obj)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("encode %s manifest: %w", obj.GetKind(), err)
+	}
+	readme, err := renderReadme(obj)
+	if err != nil {
+		return Artifact{}, err
+	}
+	files := []File{
+		{Name: ManifestFile, MediaType: MediaTypeManifest, Data: manifest},
+		{Name: ReadmeFile, MediaType: MediaTypeReadme, Data: readme},
+	}
+	if server, ok := obj.(*v1alpha1.MCPServer); ok {
+		card, err := json.MarshalIndent(mcpregistry.FromMCPServer(server).Server, "", "  ")
+		if err != nil {
+			return Artifact{}, fmt.Errorf("encode server card: %w", err)
+		}
+		files = append(files, File{Name: CardFile, MediaType: MediaTypeServerCard, Data: card})
+	}
+
+	annotations := map[string]string{
+		AnnotationKind:          obj.GetKind(),
+		AnnotationNamespace:     meta.NamespaceOrDefault(),
+		AnnotationName:          meta.Name,
+		ocispec.AnnotationTitle: meta.Name,
+	}
+	if meta.Tag != "" {
+		annotations[AnnotationTag] = meta.Tag
+		annotations[ocispec.AnnotationVersion] = meta.Tag
+	}
+	return Artifact{
+		ArtifactType: ArtifactType(obj.GetKind()),
+		Files:        files,
+		Annotations:  annotations,
+	}, nil
+}
+
+// renderReadme builds a short Markdown summary. Registry objects carry no
+// README of their own, so this gives OCI registry UIs something to show.
+func renderReadme(obj v1alpha1.Object) ([]byte, error) {
+	raw, err := obj.MarshalSpec()
+	if err != nil {
+		return nil, fmt.Errorf("encode %s spec: %w", obj.GetKind(), err)
+	}
+	var spec struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return nil, fmt.Errorf("decode %s spec: %w", obj.GetKind(), err)
+	}
+	meta := obj.GetMetadata()
+	title := spec.Title
+	if title == "" {
+		title = meta.Name
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if spec.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", spec.Description)
+	}
+	fmt.Fprintf(&b, "- Kind: %s\n", obj.GetKind())
+	fmt.Fprintf(&b, "- Name: %s/%s\n", meta.NamespaceOrDefault(), meta.Name)
+	if meta.Tag != "" {
+		fmt.Fprintf(&b, "- Tag: %s\n", meta.Tag)
+	}
+	fmt.Fprintf(&b, "\nApply with `arctl apply -f %s`.\n", ManifestFile)
+	return []byte(b.String()), nil
+}
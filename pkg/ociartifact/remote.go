@@ -0,0 +1,142 @@
+package ociartifact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Attach pushes a into the repository of imageRef as a referrer of that
+// image: the artifact's subject is set to the image manifest, so OCI tools
+// (`oras discover`, the registry Referrers API) list the metadata next to the
+// image. Registries without the Referrers API get the fallback
+// `sha256-<digest>` tag index instead. Returns the artifact's digest
+// reference.
+func Attach(ctx context.Context, imageRef string, a Artifact, options ...remote.Option) (name.Digest, error) {
+	ref, err := name.ParseReference(imageRef)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("parse image reference %q: %w", imageRef, err)
+	}
+	options = append([]remote.Option{remote.WithContext(ctx)}, options...)
+
+	head, err := remote.Head(ref, options...)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("resolve image %s: %w", ref, err)
+	}
+	a.Subject = &ocispec.Descriptor{
+		MediaType: string(head.MediaType),
+		Digest:    digest.Digest(head.Digest.String()),
+		Size:      head.Size,
+	}
+	built, err := a.Build()
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	dst := ref.Context().Digest(built.Descriptor.Digest.String())
+	if err := upload(built, dst, options...); err != nil {
+		return name.Digest{}, err
+	}
+	return dst, nil
+}
+
+// Push uploads a to ref and tags it there, so the metadata can be copied,
+// mirrored and pulled like any other OCI artifact without an image to hang
+// off. A digest reference uploads the artifact untagged. Returns the
+// artifact's digest reference.
+func Push(ctx context.Context, ref string, a Artifact, options ...remote.Option) (name.Digest, error) {
+	dst, err := name.ParseReference(ref)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("parse reference %q: %w", ref, err)
+	}
+	options = append([]remote.Option{remote.WithContext(ctx)}, options...)
+
+	built, err := a.Build()
+	if err != nil {
+		return name.Digest{}, err
+	}
+	if err := upload(built, dst, options...); err != nil {
+		return name.Digest{}, err
+	}
+	return dst.Context().Digest(built.Descriptor.Digest.String()), nil
+}
+
+// PullManifest fetches the registry metadata artifact at ref and returns its
+// ManifestFile, the applyable YAML ForObject packaged. Artifacts that aren't
+// registry metadata are rejected.
+func PullManifest(ctx context.Context, ref string, options ...remote.Option) ([]byte, error) {
+	src, err := name.ParseReference(ref)
+	if err != nil {
+		return nil, fmt.Errorf("parse reference %q: %w", ref, err)
+	}
+	options = append([]remote.Option{remote.WithContext(ctx)}, options...)
+
+	desc, err := remote.Get(src, options...)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", src, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(desc.Manifest, &manifest); err != nil {
+		return nil, fmt.Errorf("decode manifest of %s: %w", src, err)
+	}
+	if !strings.HasPrefix(manifest.ArtifactType, artifactTypePrefix) {
+		return nil, fmt.Errorf("%s is not an agentregistry artifact (artifactType %q)", src, manifest.ArtifactType)
+	}
+	for _, layer := range manifest.Layers {
+		if layer.Annotations[ocispec.AnnotationTitle] != ManifestFile {
+			continue
+		}
+		blob, err := remote.Layer(src.Context().Digest(layer.Digest.String()), options...)
+		if err != nil {
+			return nil, fmt.Errorf("fetch %s: %w", ManifestFile, err)
+		}
+		rc, err := blob.Compressed()
+		if err != nil {
+			return nil, fmt.Errorf("fetch %s: %w", ManifestFile, err)
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", ManifestFile, err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("%s has no %s", src, ManifestFile)
+}
+
+// upload pushes built's blobs into dst's repository, then its manifest to
+// dst.
+func upload(built Built, dst name.Reference, options ...remote.Option) error {
+	repo := dst.Context()
+	for d, data := range built.Blobs {
+		// Blob uploads are addressed by digest alone; the media type lives
+		// on the manifest descriptors.
+		if err := remote.WriteLayer(repo, static.NewLayer(data, types.OCIUncompressedLayer), options...); err != nil {
+			return fmt.Errorf("push blob %s: %w", d, err)
+		}
+	}
+	if err := remote.Put(dst, rawManifest(built.Manifest), options...); err != nil {
+		return fmt.Errorf("push artifact manifest: %w", err)
+	}
+	return nil
+}
+
+// rawManifest adapts an encoded OCI image manifest to remote.Taggable.
+type rawManifest []byte
+
+func (m rawManifest) RawManifest() ([]byte, error) { return m, nil }
+
+func (m rawManifest) MediaType() (types.MediaType, error) {
+	return types.OCIManifestSchema1, nil
+}
+
+var _ remote.Taggable = rawManifest(nil)
@@ -0,0 +1,135 @@
+package ociartifact_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/ociartifact"
+)
+
+func TestAttach(t *testing.T) {
+	for _, referrers := range []bool{true, false} {
+		mode := "referrers API"
+		if !referrers {
+			mode = "fallback tag"
+		}
+		t.Run(mode, func(t *testing.T) {
+			srv := httptest.NewServer(registry.New(registry.WithReferrersSupport(referrers)))
+			t.Cleanup(srv.Close)
+			host := strings.TrimPrefix(srv.URL, "http://")
+
+			img, err := random.Image(64, 1)
+			require.NoError(t, err)
+			imageRef := host + "/acme/weather:1.0.0"
+			tag, err := name.NewTag(imageRef)
+			require.NoError(t, err)
+			require.NoError(t, remote.Write(tag, img))
+
+			artifact := ociartifact.Artifact{
+				ArtifactType: ociartifact.ArtifactType("MCPServer"),
+				Files:        []ociartifact.File{{Name: "README.md", MediaType: ociartifact.MediaTypeReadme, Data: []byte("# weather")}},
+			}
+			pushed, err := ociartifact.Attach(context.Background(), imageRef, artifact)
+			require.NoError(t, err)
+
+			imgDigest, err := img.Digest()
+			require.NoError(t, err)
+			index, err := remote.Referrers(tag.Context().Digest(imgDigest.String()))
+			require.NoError(t, err)
+			manifest, err := index.IndexManifest()
+			require.NoError(t, err)
+			require.Len(t, manifest.Manifests, 1)
+			require.Equal(t, pushed.DigestStr(), manifest.Manifests[0].Digest.String())
+
+			desc, err := remote.Get(pushed)
+			require.NoError(t, err)
+			require.Contains(t, string(desc.Manifest), `"subject"`)
+			layer, err := remote.Layer(tag.Context().Digest(digestOfFirstLayer(t, desc.Manifest)))
+			require.NoError(t, err)
+			rc, err := layer.Compressed()
+			require.NoError(t, err)
+			body, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			require.Equal(t, "# weather", string(body))
+		})
+	}
+}
+
+func TestAttachMissingImage(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	_, err := ociartifact.Attach(context.Background(), host+"/acme/missing:1.0.0", ociartifact.Artifact{
+		ArtifactType: "application/x-test",
+		Files:        []ociartifact.File{{Name: "a", MediaType: "text/plain"}},
+	})
+	require.ErrorContains(t, err, "resolve image")
+}
+
+func TestPushPullManifest(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	skill := &v1alpha1.Skill{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindSkill},
+		Metadata: v1alpha1.ObjectMeta{Name: "summarize", Tag: "1.0.0"},
+		Spec:     v1alpha1.SkillSpec{Description: "Summarize text"},
+	}
+	artifact, err := ociartifact.ForObject(skill)
+	require.NoError(t, err)
+
+	ref := host + "/acme/skills/summarize:1.0.0"
+	pushed, err := ociartifact.Push(context.Background(), ref, artifact)
+	require.NoError(t, err)
+
+	for _, src := range []string{ref, pushed.String()} {
+		manifest, err := ociartifact.PullManifest(context.Background(), src)
+		require.NoError(t, err)
+		var got v1alpha1.Skill
+		require.NoError(t, yaml.Unmarshal(manifest, &got))
+		require.Equal(t, skill.Metadata.Name, got.Metadata.Name)
+		require.Equal(t, skill.Spec.Description, got.Spec.Description)
+	}
+}
+
+func TestPullManifestRejectsForeignArtifacts(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	ref := host + "/acme/other:1.0.0"
+	_, err := ociartifact.Push(context.Background(), ref, ociartifact.Artifact{
+		ArtifactType: "application/x-test",
+		Files:        []ociartifact.File{{Name: "a", MediaType: "text/plain"}},
+	})
+	require.NoError(t, err)
+
+	_, err = ociartifact.PullManifest(context.Background(), ref)
+	require.ErrorContains(t, err, "not an agentregistry artifact")
+}
+
+func digestOfFirstLayer(t *testing.T, raw []byte) string {
+	t.Helper()
+	var m struct {
+		Layers []struct {
+			Digest string `json:"digest"`
+		} `json:"layers"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &m))
+	require.NotEmpty(t, m.Layers)
+	return m.Layers[0].Digest
+}
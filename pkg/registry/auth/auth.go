@@ -22,6 +22,14 @@ type User struct {
 // Authn
 type Principal struct {
 	User User
+	// Subject names the authenticated caller (token subject, service
+	// account, user id). Optional; providers that set it get per-caller
+	// attribution in usage reports and audit sinks.
+	Subject string
+	// Namespaces confines the caller to the listed namespaces (its tenant).
+	// Empty means unconfined. Application authz remains the primary check;
+	// when database RLS is enabled the same list is enforced by Postgres.
+	Namespaces []string
 }
 
 type Session interface {
@@ -49,10 +57,32 @@ func AuthSessionTo(ctx context.Context, session Session) context.Context {
 	return context.WithValue(ctx, sessionKey, session)
 }
 
+// SubjectFrom returns the Subject of the session on ctx, or "" when the
+// request is unauthenticated or the provider does not name callers.
+func SubjectFrom(ctx context.Context) string {
+	session, ok := AuthSessionFrom(ctx)
+	if !ok {
+		return ""
+	}
+	return session.Principal().Subject
+}
+
+// NamespacesFrom returns the namespaces the session on ctx is confined to,
+// or nil when the caller is unconfined (no session, system session, or a
+// provider that does not scope principals).
+func NamespacesFrom(ctx context.Context) []string {
+	session, ok := AuthSessionFrom(ctx)
+	if !ok || IsSystemSession(session) {
+		return nil
+	}
+	return session.Principal().Namespaces
+}
+
 // todo: the middleware config is redefined here and router. should be consolidated.
 // Middleware configuration options
 type middlewareConfig struct {
-	skipPaths map[string]bool
+	skipPaths    map[string]bool
+	skipPrefixes []string
 }
 
 type MiddlewareOption func(*middlewareConfig)
@@ -65,6 +95,15 @@ func WithSkipPaths(paths ...string) MiddlewareOption {
 	}
 }
 
+// WithSkipPathPrefixes skips authentication for every path under the given
+// prefixes (e.g. "/v0/public/"), for route families that are anonymous by
+// design.
+func WithSkipPathPrefixes(prefixes ...string) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.skipPrefixes = append(c.skipPrefixes, prefixes...)
+	}
+}
+
 func AuthnMiddleware(authn AuthnProvider, options ...MiddlewareOption) func(ctx huma.Context, next func(huma.Context)) {
 	config := &middlewareConfig{
 		skipPaths: make(map[string]bool),
@@ -79,7 +118,7 @@ func AuthnMiddleware(authn AuthnProvider, options ...MiddlewareOption) func(ctx
 		// extract the last part of the path to match against skipPaths
 		pathParts := strings.Split(path, "/")
 		pathToMatch := "/" + pathParts[len(pathParts)-1]
-		if config.skipPaths[pathToMatch] || config.skipPaths[path] {
+		if config.skipPaths[pathToMatch] || config.skipPaths[path] || hasAnyPrefix(path, config.skipPrefixes) {
 			next(ctx)
 			return
 		}
@@ -98,3 +137,12 @@ func AuthnMiddleware(authn AuthnProvider, options ...MiddlewareOption) func(ctx
 		next(ctx)
 	}
 }
+
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -147,11 +147,26 @@ type jwtSession struct {
 
 func (s *jwtSession) Principal() Principal {
 	return Principal{
+		Subject: s.claims.subject(),
 		User: User{
 			Permissions: s.claims.Permissions,
 		},
 	}
 }
+
+// subject names the caller the token was issued to: its registered "sub"
+// claim, or else the identity it authenticated with, qualified by method
+// ("github-at:alice") so identities from different methods never collide.
+// Anonymous tokens have none.
+func (c *JWTClaims) subject() string {
+	if c.Subject != "" {
+		return c.Subject
+	}
+	if c.AuthMethod == MethodNone || c.AuthMethodSubject == "" {
+		return ""
+	}
+	return string(c.AuthMethod) + ":" + c.AuthMethodSubject
+}
 func (j *JWTManager) Authenticate(ctx context.Context, reqHeaders func(name string) string, query url.Values) (Session, error) {
 	const bearerPrefix = "Bearer "
 	authHeader := reqHeaders("Authorization")
@@ -273,6 +273,32 @@ func TestJWTManager_HasPermission(t *testing.T) {
 	}
 }
 
+func TestJWTManager_AuthenticateNamesSubject(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	jwtManager := auth.NewJWTManager(&config.Config{JWTPrivateKey: hex.EncodeToString(testSeed)})
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name   string
+		claims auth.JWTClaims
+		want   string
+	}{
+		{"auth method subject", auth.JWTClaims{AuthMethod: auth.MethodGitHubAT, AuthMethodSubject: "alice"}, "github-at:alice"},
+		{"registered subject wins", auth.JWTClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: "user-42"}, AuthMethod: auth.MethodOIDC, AuthMethodSubject: "alice"}, "user-42"},
+		{"anonymous", auth.JWTClaims{AuthMethod: auth.MethodNone, AuthMethodSubject: "anonymous"}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := jwtManager.GenerateTokenResponse(ctx, tc.claims)
+			require.NoError(t, err)
+			session, err := jwtManager.Authenticate(ctx, func(string) string { return "Bearer " + token.RegistryToken }, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, session.Principal().Subject)
+		})
+	}
+}
+
 func TestNewJWTManager_InvalidKeySize(t *testing.T) {
 	// Test with invalid key size (should panic)
 	cfg := &config.Config{
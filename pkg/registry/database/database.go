@@ -21,6 +21,9 @@ var (
 	ErrAlreadyExists = errors.New("record already exists")
 	ErrInvalidInput  = errors.New("invalid input")
 	ErrDatabase      = errors.New("database error")
+	// ErrConflict reports a write refused because it contends with work
+	// already in flight on the same resource, such as a running deploy.
+	ErrConflict = errors.New("conflict")
 )
 
 // Store is the root persistence contract AppOptions.DatabaseFactory
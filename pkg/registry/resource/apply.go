@@ -2,6 +2,7 @@ package resource
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -9,6 +10,7 @@ import (
 
 	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
 	"github.com/agentregistry-dev/agentregistry/pkg/types"
 )
@@ -280,6 +282,12 @@ func resolveBatchTarget(cfg ApplyConfig, obj v1alpha1.Object, verb string) (*v1a
 		meta.Namespace = v1alpha1.DefaultNamespace
 		obj.SetMetadata(*meta)
 	}
+	// Canonicalize here too so results and deletes name the resource the
+	// way it is stored.
+	if ns, name := v1alpha1.CanonicalName(meta.Namespace), v1alpha1.CanonicalName(meta.Name); ns != meta.Namespace || name != meta.Name {
+		meta.Namespace, meta.Name = ns, name
+		obj.SetMetadata(*meta)
+	}
 
 	// Defense-in-depth: when any Authorizers are wired, a kind without
 	// an entry must DENY rather than silently allow. Callers that install
@@ -316,11 +324,23 @@ func failResult(res arv0.ApplyResult, ae *applyError) arv0.ApplyResult {
 	switch ae.Stage {
 	case stageAuth:
 		res.Error = "forbidden: " + ae.Err.Error()
+	case stagePrepare, stageAdmission:
+		switch {
+		case errors.Is(ae.Err, pkgdb.ErrAlreadyExists) || errors.Is(ae.Err, pkgdb.ErrConflict):
+			res.Error = "conflict: " + ae.Err.Error()
+		case errors.Is(ae.Err, pkgdb.ErrForbidden):
+			res.Error = "forbidden: " + ae.Err.Error()
+		default:
+			res.Error = ae.Error()
+		}
 	case stageUpsert:
-		if ae.Terminating {
+		switch {
+		case ae.Terminating:
 			res.Error = fmt.Sprintf("object %s/%s is terminating; delete + re-apply once GC purges the row",
 				res.Namespace, res.Name)
-		} else {
+		case errors.Is(ae.Err, pkgdb.ErrConflict):
+			res.Error = "conflict: " + ae.Err.Error()
+		default:
 			res.Error = "upsert: " + ae.Err.Error()
 		}
 	case stageDelete:
@@ -25,6 +25,9 @@ type applyOpts struct {
 	Admission         types.Admission
 	Source            string
 	Prepare           func(ctx context.Context, obj v1alpha1.Object) error
+	// IfContentHash makes the write conditional on the stored row's content
+	// hash. See types.AdmissionInput.IfContentHash.
+	IfContentHash string
 }
 
 // applyStage tags which step of the pipeline produced an error so
@@ -86,6 +89,14 @@ func applyCore(
 		obj.SetMetadata(*meta)
 	}
 
+	// Publish under the canonical namespace and name so names differing
+	// only in case or compatibility characters cannot become separate
+	// resources.
+	if ns, name := v1alpha1.CanonicalName(meta.Namespace), v1alpha1.CanonicalName(meta.Name); ns != meta.Namespace || name != meta.Name {
+		meta.Namespace, meta.Name = ns, name
+		obj.SetMetadata(*meta)
+	}
+
 	if v1alpha1.IsTaggedArtifactKind(kind) && meta.Tag == "" {
 		meta.Tag = v1alpha1store.DefaultTag()
 		obj.SetMetadata(*meta)
@@ -137,6 +148,7 @@ func applyCore(
 		Store:             store,
 		PostUpsert:        opts.PostUpsert,
 		InitialFinalizers: opts.InitialFinalizers,
+		IfContentHash:     opts.IfContentHash,
 	})
 	if err != nil {
 		if ae, ok := err.(*applyError); ok {
@@ -159,7 +171,7 @@ func ProductionAdmission(ctx context.Context, in types.AdmissionInput) (types.Ad
 		return types.AdmissionResult{}, errors.New("production store is required")
 	}
 
-	upsertOpts := v1alpha1store.UpsertOpts{}
+	upsertOpts := v1alpha1store.UpsertOpts{IfContentHash: in.IfContentHash}
 	if in.InitialFinalizers != nil {
 		upsertOpts.InitialFinalizers = in.InitialFinalizers(in.Object)
 	}
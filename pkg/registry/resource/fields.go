@@ -0,0 +1,183 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// FieldsParam is the sparse fieldset query parameter of list and get
+// routes. Embedding it in a route's input both documents ?fields= and opts
+// the route into SelectFields.
+type FieldsParam struct {
+	Fields string `query:"fields" doc:"Comma-separated fields to return, e.g. name,tag,description or metadata.labels. A dotted path selects a nested field; a bare name also matches that field one level down (metadata.name, spec.description). List responses apply the selection to each item. Unknown fields are left out."`
+}
+
+// fieldsParam is the query parameter FieldsParam declares.
+const fieldsParam = "fields"
+
+// listItemKeys are the response members holding the items of a list: the
+// v0 list envelope and the MCP Registry v0.1 server list.
+var listItemKeys = []string{"items", "servers"}
+
+// SelectFields is a huma.Transformer that trims successful responses of
+// routes embedding FieldsParam down to the fields named in ?fields=,
+// before the body is serialized. Other responses pass through unchanged.
+func SelectFields(ctx huma.Context, status string, v any) (any, error) {
+	raw := ctx.Query(fieldsParam)
+	if raw == "" || !strings.HasPrefix(status, "2") || v == nil || !declaresFields(ctx.Operation()) {
+		return v, nil
+	}
+	paths := parseFields(raw)
+	if len(paths) == 0 {
+		return v, nil
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(encoded))
+	dec.UseNumber()
+	var body any
+	if err := dec.Decode(&body); err != nil {
+		return nil, err
+	}
+	obj, ok := body.(map[string]any)
+	if !ok {
+		return v, nil
+	}
+	for _, key := range listItemKeys {
+		items, ok := obj[key].([]any)
+		if !ok {
+			continue
+		}
+		for i, item := range items {
+			items[i] = selectItemFields(item, paths)
+		}
+		return obj, nil
+	}
+	return selectItemFields(obj, paths), nil
+}
+
+func declaresFields(op *huma.Operation) bool {
+	if op == nil {
+		return false
+	}
+	return slices.ContainsFunc(op.Parameters, func(p *huma.Param) bool {
+		return p.In == "query" && p.Name == fieldsParam
+	})
+}
+
+// parseFields splits ?fields= into dotted paths, dropping empty entries.
+func parseFields(raw string) [][]string {
+	var paths [][]string
+	for _, field := range strings.Split(raw, ",") {
+		var path []string
+		for _, segment := range strings.Split(strings.TrimSpace(field), ".") {
+			if segment != "" {
+				path = append(path, segment)
+			}
+		}
+		if len(path) > 0 {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// selectItemFields returns the parts of item named by paths. A path of a
+// single name the item has no member for matches that member of each of
+// the item's object members instead.
+func selectItemFields(item any, paths [][]string) any {
+	obj, ok := item.(map[string]any)
+	if !ok {
+		return item
+	}
+	out := map[string]any{}
+	for _, path := range paths {
+		if picked, ok := pickPath(obj, path); ok {
+			mergeFields(out, picked)
+			continue
+		}
+		if len(path) != 1 {
+			continue
+		}
+		for key, child := range obj {
+			if picked, ok := pickPath(child, path); ok {
+				mergeFields(out, map[string]any{key: picked})
+			}
+		}
+	}
+	return out
+}
+
+// pickPath returns src reduced to path, keeping the enclosing objects. A
+// path crossing an array applies to each element.
+func pickPath(src any, path []string) (any, bool) {
+	if len(path) == 0 {
+		return src, true
+	}
+	switch s := src.(type) {
+	case map[string]any:
+		child, ok := s[path[0]]
+		if !ok {
+			return nil, false
+		}
+		picked, ok := pickPath(child, path[1:])
+		if !ok {
+			return nil, false
+		}
+		return map[string]any{path[0]: picked}, true
+	case []any:
+		out := make([]any, len(s))
+		var found bool
+		for i, elem := range s {
+			picked, ok := pickPath(elem, path)
+			if !ok {
+				picked = map[string]any{}
+			}
+			found = found || ok
+			out[i] = picked
+		}
+		return out, found
+	}
+	return nil, false
+}
+
+// mergeFields merges the picked object src into dst.
+func mergeFields(dst map[string]any, src any) {
+	obj, ok := src.(map[string]any)
+	if !ok {
+		return
+	}
+	for key, value := range obj {
+		existing, ok := dst[key]
+		if !ok {
+			dst[key] = value
+			continue
+		}
+		dst[key] = mergeValue(existing, value)
+	}
+}
+
+func mergeValue(a, b any) any {
+	switch av := a.(type) {
+	case map[string]any:
+		if _, ok := b.(map[string]any); ok {
+			mergeFields(av, b)
+			return av
+		}
+	case []any:
+		if bv, ok := b.([]any); ok && len(av) == len(bv) {
+			for i := range av {
+				av[i] = mergeValue(av[i], bv[i])
+			}
+			return av
+		}
+	}
+	return b
+}
@@ -0,0 +1,76 @@
+package resource_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+)
+
+func TestSelectFields(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.CreateHooks = nil
+	config.Transformers = append(config.Transformers, resource.SelectFields)
+	_, api := humatest.New(t, config)
+
+	server := &v1alpha1.MCPServer{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindMCPServer},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "weather", Tag: "1.0.0", Labels: map[string]string{"team": "a"}},
+		Spec: v1alpha1.MCPServerSpec{
+			Title:       "Weather",
+			Description: "Forecasts.",
+			Tools:       []v1alpha1.MCPTool{{Name: "get_forecast", Description: "Forecast for a city."}, {Name: "get_alerts"}},
+		},
+	}
+	type listOutput struct {
+		Body struct {
+			Items      []*v1alpha1.MCPServer `json:"items"`
+			NextCursor string                `json:"nextCursor,omitempty"`
+		}
+	}
+	type listInput struct {
+		resource.FieldsParam
+	}
+	huma.Get(api, "/servers", func(context.Context, *listInput) (*listOutput, error) {
+		out := &listOutput{}
+		out.Body.Items = []*v1alpha1.MCPServer{server}
+		out.Body.NextCursor = "next"
+		return out, nil
+	})
+	huma.Get(api, "/plain", func(context.Context, *struct{}) (*listOutput, error) {
+		out := &listOutput{}
+		out.Body.Items = []*v1alpha1.MCPServer{server}
+		return out, nil
+	})
+
+	get := func(path string) map[string]any {
+		resp := api.Get(path)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		return body
+	}
+
+	body := get("/servers?fields=name,tag,description,spec.tools.name")
+	require.Equal(t, "next", body["nextCursor"], "the list envelope is kept")
+	require.Equal(t, []any{map[string]any{
+		"metadata": map[string]any{"name": "weather", "tag": "1.0.0"},
+		"spec": map[string]any{
+			"description": "Forecasts.",
+			"tools":       []any{map[string]any{"name": "get_forecast"}, map[string]any{"name": "get_alerts"}},
+		},
+	}}, body["items"])
+
+	body = get("/servers?fields=metadata.labels,nope")
+	require.Equal(t, []any{map[string]any{"metadata": map[string]any{"labels": map[string]any{"team": "a"}}}}, body["items"])
+
+	body = get("/plain?fields=name")
+	require.Contains(t, body["items"].([]any)[0], "spec", "routes without FieldsParam are not trimmed")
+}
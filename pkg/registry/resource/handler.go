@@ -11,11 +11,13 @@
 //	GET    {basePrefix}/{pluralKind}/{name}/{tag}?namespace={ns}     get exact tag (tagged content kinds only)
 //	PUT    {basePrefix}/{pluralKind}/{name}?namespace={ns}           apply mutable object (Provider/Deployment/config)
 //	DELETE {basePrefix}/{pluralKind}/{name}?namespace={ns}           delete mutable object
+//	PATCH  {basePrefix}/{pluralKind}/{name}/{tag}?namespace={ns}     patch exact tag (tagged content kinds only)
 //	DELETE {basePrefix}/{pluralKind}/{name}/{tag}?namespace={ns}     delete exact tag (tagged content kinds only)
+//	POST   {basePrefix}/{pluralKind}/{name}/{tag}/restore?namespace={ns} restore a deleted tag (tagged content kinds only)
 //
 // Direct PUT is registered only for mutable object stores. Content-registry
 // artifact kinds (Agent, MCPServer, Skill, Prompt) use metadata.tag and are
-// written through POST /v0/apply.
+// written through POST /v0/apply, or changed in place with PATCH.
 package resource
 
 import (
@@ -23,32 +25,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
-	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
 
+	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
 	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
 	"github.com/agentregistry-dev/agentregistry/pkg/types"
 )
 
-// unescapePath URL-decodes a path segment captured by Huma. Resource
-// names allow `/` (DNS-subdomain-style like `ai.exa/exa`) so callers
-// pass them as `%2F`-escaped path segments. Huma keeps the raw path
-// captures, so the handler must unescape before consulting the Store —
-// otherwise rows stored as `ai.exa/exa` are unreachable via GET/DELETE.
-// Returns a 400 on decode failure (malformed escape sequence).
-func unescapePath(field, value string) (string, error) {
-	out, err := url.PathUnescape(value)
-	if err != nil {
-		return "", huma.Error400BadRequest(fmt.Sprintf("invalid %s path segment: %v", field, err))
-	}
-	return out, nil
-}
-
 // Config is the per-kind configuration for Register. Kind / BasePrefix /
 // Store are required; Resolver is optional (enables cross-kind ref
 // existence checks on apply).
@@ -56,7 +46,8 @@ type Config struct {
 	// Kind is the canonical Kind name (e.g. v1alpha1.KindAgent = "Agent").
 	Kind string
 	// PluralKind is the lowercase plural used in route paths (e.g. "agents",
-	// "mcpservers"). If empty, defaults to strings.ToLower(Kind) + "s".
+	// "mcpservers"). If empty, defaults to v1alpha1.PluralFor(Kind), the
+	// same plural the Go client routes to.
 	PluralKind string
 	// BasePrefix is the HTTP route prefix shared across kinds (e.g. "/v0").
 	// Routes extend it with `/{plural}/{name}` and, for tagged artifacts,
@@ -181,6 +172,40 @@ type Config struct {
 	// the caller can still force inclusion but never exclusion when
 	// the kind has opted in.
 	IncludeTerminatingByDefault bool
+
+	// Usage is optional; when set, successful GETs of an artifact are
+	// counted as downloads, list responses carry each listed name's usage
+	// counters, and the list endpoint accepts ?sort=popularity.
+	Usage UsageTracker
+
+	// ListSorts adds kind-specific ?sort= values to the list endpoint,
+	// mapped to the store order each one selects (e.g. "status" on
+	// Deployments).
+	ListSorts map[string]*v1alpha1store.ListSort
+
+	// Redact is optional; when set, every object a handler returns passes
+	// through it first, so the kind can blank out sensitive spec fields
+	// (e.g. Runtime credentials) it keeps in storage.
+	Redact func(obj v1alpha1.Object)
+}
+
+// redact runs cfg.Redact on obj when set.
+func (cfg Config) redact(obj v1alpha1.Object) {
+	if cfg.Redact != nil {
+		cfg.Redact(obj)
+	}
+}
+
+// UsageTracker counts artifact downloads and ranks artifacts by use. See
+// Config.Usage.
+type UsageTracker interface {
+	// RecordDownload counts one GET of an artifact. It must not block.
+	RecordDownload(kind, namespace, name string)
+	// Counts returns the counters of each key with recorded use.
+	Counts(ctx context.Context, keys []v1alpha1store.UsageKey) (map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts, error)
+	// Top returns up to limit artifacts of kind, most popular first; an
+	// empty namespace ranks across every namespace.
+	Top(ctx context.Context, kind, namespace string, limit int) ([]v1alpha1store.UsageKey, error)
 }
 
 // AuthorizeInput is the context passed to Config.Authorize on every handler
@@ -201,7 +226,8 @@ type AuthorizeInput struct {
 	// Tag is populated for exact tagged content resource operations.
 	// Batch delete leaves Tag empty when deleting every tag for a name.
 	Tag string
-	// Object is non-nil only when Verb == "apply"; it carries the decoded
+	// Object is non-nil only when Verb == "apply" (and nil for restore,
+	// which authorizes as "apply" with no body); it carries the decoded
 	// request body post-validation-stamping (path identity already merged
 	// into metadata), so the hook can inspect labels / annotations / spec
 	// in authz decisions.
@@ -224,7 +250,7 @@ const namespaceAll = "all"
 // ?namespace= query values: empty → DefaultNamespace, "all" → "" (the
 // Store interprets empty as cross-namespace for list operations).
 // Non-list callers (get/put/delete) still pass "" through as "default"
-// — they never accept "all".
+// — they never accept "all". Other values are canonicalized like names.
 func resolveNamespace(raw string, allowAll bool) string {
 	if allowAll && raw == namespaceAll {
 		return ""
@@ -232,23 +258,26 @@ func resolveNamespace(raw string, allowAll bool) string {
 	if raw == "" {
 		return v1alpha1.DefaultNamespace
 	}
-	return raw
+	return v1alpha1.CanonicalName(raw)
 }
 
 type getInput struct {
 	Namespace string `query:"namespace" doc:"Namespace (internal; defaults to 'default')."`
 	Name      string `path:"name"`
 	Tag       string `path:"tag"`
+	FieldsParam
 }
 
 type getLatestInput struct {
 	Namespace string `query:"namespace" doc:"Namespace (internal; defaults to 'default')."`
 	Name      string `path:"name"`
+	FieldsParam
 }
 
 type listTagsInput struct {
 	Namespace string `query:"namespace" doc:"Namespace (internal; defaults to 'default')."`
 	Name      string `path:"name"`
+	FieldsParam
 }
 
 type deleteInput struct {
@@ -276,8 +305,22 @@ type ListInput struct {
 	// IncludeTerminating surfaces soft-deleted rows (deletionTimestamp != nil)
 	// which are hidden by default.
 	IncludeTerminating bool `query:"includeTerminating" doc:"Include rows with a deletionTimestamp."`
+	// Sort orders the list. Empty and sortName keep the store's
+	// (namespace, name) order; sortPopularity is honored on kinds with
+	// Config.Usage, other values on kinds whose Config.ListSorts has them.
+	Sort string `query:"sort" doc:"'name' (the default) lists by namespace and name. 'updatedAt' lists the most recently updated first. 'status' (Deployments only) groups by status: deployed, deploying, failed, pending, terminating, undeployed. 'popularity' returns the most downloaded, deployed and searched artifacts first, one page of up to limit latest tags (Agents, MCP servers and skills only; no cursor). A cursor only continues the sort it was returned for."`
+	// IncludeTotal adds the number of items across all pages.
+	IncludeTotal bool `query:"includeTotal" doc:"Also return the total number of items matching the filters, across all pages."`
+	FieldsParam
 }
 
+// ?sort= values every list accepts.
+const (
+	sortName       = "name"
+	sortUpdatedAt  = "updatedAt"
+	sortPopularity = "popularity"
+)
+
 type listInput = ListInput
 
 type listWithOriginInput struct {
@@ -289,6 +332,8 @@ type listWithOriginInput struct {
 }
 
 type bodyOutput[T v1alpha1.Object] struct {
+	// ETag is set on tagged artifacts; PATCH accepts it in If-Match.
+	ETag string `header:"ETag"`
 	Body T
 }
 
@@ -296,6 +341,12 @@ type listOutput[T v1alpha1.Object] struct {
 	Body struct {
 		Items      []T    `json:"items"`
 		NextCursor string `json:"nextCursor,omitempty"`
+		// Total is the number of items across all pages, with
+		// ?includeTotal=true.
+		Total *int `json:"total,omitempty"`
+		// Usage maps "namespace/name" of each listed item with recorded
+		// use to its counters, on kinds with Config.Usage.
+		Usage map[string]arv0.ArtifactUsage `json:"usage,omitempty"`
 	}
 }
 
@@ -314,7 +365,7 @@ func Register[T v1alpha1.Object](api huma.API, cfg Config, newObj func() T) {
 	kind := cfg.Kind
 	plural := cfg.PluralKind
 	if plural == "" {
-		plural = strings.ToLower(kind) + "s"
+		plural = v1alpha1.PluralFor(kind)
 	}
 	base := strings.TrimRight(cfg.BasePrefix, "/")
 
@@ -350,7 +401,7 @@ func Register[T v1alpha1.Object](api huma.API, cfg Config, newObj func() T) {
 		Summary:     fmt.Sprintf("Get the latest %s", kind),
 	}, func(ctx context.Context, in *getLatestInput) (*bodyOutput[T], error) {
 		ns := resolveNamespace(in.Namespace, false)
-		name, err := unescapePath("name", in.Name)
+		name, err := UnescapeName(in.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -372,7 +423,15 @@ func Register[T v1alpha1.Object](api huma.API, cfg Config, newObj func() T) {
 		if err != nil {
 			return nil, huma.Error500InternalServerError("decode "+kind, err)
 		}
-		return &bodyOutput[T]{Body: obj}, nil
+		if cfg.Usage != nil {
+			cfg.Usage.RecordDownload(kind, ns, name)
+		}
+		cfg.redact(obj)
+		out := &bodyOutput[T]{Body: obj}
+		if v1alpha1.IsTaggedArtifactKind(kind) {
+			out.ETag = contentETag(row)
+		}
+		return out, nil
 	})
 
 	// List tags (name only; namespace via query). Tagged-artifact
@@ -387,7 +446,9 @@ func Register[T v1alpha1.Object](api huma.API, cfg Config, newObj func() T) {
 
 	if v1alpha1.IsTaggedArtifactKind(kind) {
 		registerGetTagged(api, cfg, newObj, kind, itemTagPath)
+		registerPatchTagged(api, cfg, newObj, kind, itemTagPath)
 		registerDeleteTagged(api, cfg, newObj, kind, itemTagPath)
+		registerRestoreTagged(api, cfg, newObj, kind, itemTagPath)
 	} else {
 		registerApplyMutable(api, cfg, newObj, kind, itemPath)
 		registerDeleteMutable(api, cfg, newObj, kind, itemPath)
@@ -402,11 +463,11 @@ func registerGetTagged[T v1alpha1.Object](api huma.API, cfg Config, newObj func(
 		Summary:     fmt.Sprintf("Get a %s by name and tag", kind),
 	}, func(ctx context.Context, in *getInput) (*bodyOutput[T], error) {
 		ns := resolveNamespace(in.Namespace, false)
-		name, err := unescapePath("name", in.Name)
+		name, err := UnescapeName(in.Name)
 		if err != nil {
 			return nil, err
 		}
-		tag, err := unescapePath("tag", in.Tag)
+		tag, err := UnescapePath("tag", in.Tag)
 		if err != nil {
 			return nil, err
 		}
@@ -423,7 +484,11 @@ func registerGetTagged[T v1alpha1.Object](api huma.API, cfg Config, newObj func(
 		if err != nil {
 			return nil, huma.Error500InternalServerError("decode "+kind, err)
 		}
-		return &bodyOutput[T]{Body: obj}, nil
+		if cfg.Usage != nil {
+			cfg.Usage.RecordDownload(kind, ns, name)
+		}
+		cfg.redact(obj)
+		return &bodyOutput[T]{ETag: contentETag(row), Body: obj}, nil
 	})
 }
 
@@ -438,7 +503,7 @@ func registerListTags[T v1alpha1.Object](api huma.API, cfg Config, newObj func()
 		Summary:     fmt.Sprintf("List all tags of a %s", kind),
 	}, func(ctx context.Context, in *listTagsInput) (*listOutput[T], error) {
 		ns := resolveNamespace(in.Namespace, false)
-		name, err := unescapePath("name", in.Name)
+		name, err := UnescapeName(in.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -457,6 +522,7 @@ func registerListTags[T v1alpha1.Object](api huma.API, cfg Config, newObj func()
 			if err != nil {
 				return nil, huma.Error500InternalServerError("decode "+kind, err)
 			}
+			cfg.redact(obj)
 			items = append(items, obj)
 		}
 		out := &listOutput[T]{}
@@ -475,7 +541,7 @@ func registerApplyMutable[T v1alpha1.Object](api huma.API, cfg Config, newObj fu
 		DefaultStatus: http.StatusOK,
 	}, func(ctx context.Context, in *putMutableInput[T]) (*bodyOutput[T], error) {
 		ns := resolveNamespace(in.Namespace, false)
-		name, err := unescapePath("name", in.Name)
+		name, err := UnescapeName(in.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -489,10 +555,10 @@ func registerApplyMutable[T v1alpha1.Object](api huma.API, cfg Config, newObj fu
 				"kind %q does not match endpoint kind %q", k, kind))
 		}
 		meta := body.GetMetadata()
-		if meta.Namespace != "" && meta.Namespace != ns {
+		if meta.Namespace != "" && v1alpha1.CanonicalName(meta.Namespace) != ns {
 			return nil, huma.Error400BadRequest("metadata.namespace does not match ?namespace=")
 		}
-		if meta.Name != "" && meta.Name != name {
+		if meta.Name != "" && v1alpha1.CanonicalName(meta.Name) != name {
 			return nil, huma.Error400BadRequest("metadata.name does not match path")
 		}
 
@@ -525,6 +591,7 @@ func registerApplyMutable[T v1alpha1.Object](api huma.API, cfg Config, newObj fu
 		if err != nil {
 			return nil, huma.Error500InternalServerError("decode "+kind, err)
 		}
+		cfg.redact(obj)
 		return &bodyOutput[T]{Body: obj}, nil
 	})
 }
@@ -548,11 +615,11 @@ func registerDelete[T v1alpha1.Object](api huma.API, cfg Config, newObj func() T
 	if tagged {
 		huma.Register(api, op, func(ctx context.Context, in *deleteInput) (*deleteOutput, error) {
 			ns := resolveNamespace(in.Namespace, false)
-			name, err := unescapePath("name", in.Name)
+			name, err := UnescapeName(in.Name)
 			if err != nil {
 				return nil, err
 			}
-			tag, err := unescapePath("tag", in.Tag)
+			tag, err := UnescapePath("tag", in.Tag)
 			if err != nil {
 				return nil, err
 			}
@@ -562,7 +629,7 @@ func registerDelete[T v1alpha1.Object](api huma.API, cfg Config, newObj func() T
 	}
 	huma.Register(api, op, func(ctx context.Context, in *deleteMutableInput) (*deleteOutput, error) {
 		ns := resolveNamespace(in.Namespace, false)
-		name, err := unescapePath("name", in.Name)
+		name, err := UnescapeName(in.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -641,6 +708,21 @@ func runDelete[T v1alpha1.Object](ctx context.Context, cfg Config, newObj func()
 	return &deleteOutput{}, nil
 }
 
+// limitError answers 422 for a validation pass that broke one of the
+// v1alpha1 size/count limits, listing every violation in the pass (not
+// just the limit ones) so a client can fix the document in one round trip.
+func limitError(err error) error {
+	var fe v1alpha1.FieldErrors
+	if !errors.As(err, &fe) {
+		return huma.Error422UnprocessableEntity("validation: " + err.Error())
+	}
+	details := make([]error, 0, len(fe))
+	for _, e := range fe {
+		details = append(details, &huma.ErrorDetail{Location: "body." + e.Path, Message: e.Cause.Error()})
+	}
+	return huma.Error422UnprocessableEntity("validation failed", details...)
+}
+
 // mapApplyErrorToHuma translates the stage-tagged applyError surface
 // from applyCore / deleteCore into the huma error shape the
 // single-resource handlers emit. Mirrors the per-stage HTTP-status
@@ -651,13 +733,40 @@ func mapApplyErrorToHuma(ae *applyError, kind, ns, name, tag string) error {
 		// Auth callbacks already return huma errors; propagate.
 		return ae.Err
 	case stageValidation:
+		if errors.Is(ae.Err, v1alpha1.ErrLimitExceeded) {
+			return limitError(ae.Err)
+		}
 		return huma.Error400BadRequest("validation: " + ae.Err.Error())
 	case stageRefs:
 		return huma.Error400BadRequest("refs: " + ae.Err.Error())
 	case stageRegistries:
 		return huma.Error400BadRequest("registries: " + ae.Err.Error())
 	case stageAdmission:
+		if errors.Is(ae.Err, pkgdb.ErrConflict) {
+			return huma.Error409Conflict(ae.Err.Error())
+		}
+		if errors.Is(ae.Err, pkgdb.ErrForbidden) {
+			return huma.Error403Forbidden(ae.Err.Error())
+		}
 		return ae.Err
+	case stagePrepare:
+		// Prepare hooks signal a clash with an existing artifact (e.g. a
+		// uniqueness rule) with pkgdb.ErrAlreadyExists, and with in-flight
+		// work (e.g. a running deploy) with pkgdb.ErrConflict. A write the
+		// caller may not make (e.g. into a namespace owned by someone else)
+		// is pkgdb.ErrForbidden. Hooks that pick their own status (e.g. a
+		// 429 from a publish quota) return a huma.StatusError.
+		var statusErr huma.StatusError
+		if errors.As(ae.Err, &statusErr) {
+			return ae.Err
+		}
+		if errors.Is(ae.Err, pkgdb.ErrAlreadyExists) || errors.Is(ae.Err, pkgdb.ErrConflict) {
+			return huma.Error409Conflict(ae.Err.Error())
+		}
+		if errors.Is(ae.Err, pkgdb.ErrForbidden) {
+			return huma.Error403Forbidden(ae.Err.Error())
+		}
+		return huma.Error500InternalServerError(kind+" prepare", ae.Err)
 	case stageMarshal:
 		return huma.Error400BadRequest("marshal spec: " + ae.Err.Error())
 	case stageUpsert:
@@ -666,6 +775,13 @@ func mapApplyErrorToHuma(ae *applyError, kind, ns, name, tag string) error {
 				"%s %s/%s/%s is terminating; delete + re-apply once GC purges the row",
 				kind, ns, name, tag))
 		}
+		if errors.Is(ae.Err, v1alpha1store.ErrPreconditionFailed) {
+			return huma.Error412PreconditionFailed(fmt.Sprintf(
+				"%s %s/%s/%s changed since it was read; fetch it again and retry", kind, ns, name, tag))
+		}
+		if errors.Is(ae.Err, pkgdb.ErrConflict) {
+			return huma.Error409Conflict(ae.Err.Error())
+		}
 		return huma.Error500InternalServerError("upsert "+kind, ae.Err)
 	case stagePostUpsert:
 		return huma.Error500InternalServerError(kind+" post-upsert", ae.Err)
@@ -693,6 +809,8 @@ type listParams struct {
 	LatestOnly         bool
 	IncludeTerminating bool
 	Origin             string
+	Sort               string
+	IncludeTotal       bool
 }
 
 func handleList[T v1alpha1.Object](
@@ -713,6 +831,8 @@ func handleList[T v1alpha1.Object](
 		LatestOnly:         in.LatestOnly,
 		IncludeTerminating: in.IncludeTerminating,
 		Origin:             origin,
+		Sort:               in.Sort,
+		IncludeTotal:       in.IncludeTotal,
 	})
 }
 
@@ -751,6 +871,35 @@ func runList[T v1alpha1.Object](
 		opts.ExtraArgs = extraArgs
 	}
 	applyOriginFilter(&opts, p.Origin)
+	var ranking []v1alpha1store.UsageKey
+	switch p.Sort {
+	case "", sortName:
+	case sortUpdatedAt:
+		opts.Sort = v1alpha1store.SortByUpdatedAt
+	case sortPopularity:
+		if cfg.Usage == nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("%s lists cannot be sorted by popularity", cfg.Kind))
+		}
+		if p.Cursor != "" {
+			return nil, huma.Error400BadRequest("cursor is not supported with sort=popularity")
+		}
+		var err error
+		ranking, err = popularityFilter(ctx, cfg, &opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(ranking) == 0 {
+			out := &listOutput[T]{}
+			out.Body.Items = []T{}
+			return out, nil
+		}
+	default:
+		sort, ok := cfg.ListSorts[p.Sort]
+		if !ok {
+			return nil, huma.Error400BadRequest("invalid sort: expected " + strings.Join(listSortNames(cfg), ", "))
+		}
+		opts.Sort = sort
+	}
 	rows, nextCursor, err := cfg.Store.List(ctx, opts)
 	if err != nil {
 		if errors.Is(err, v1alpha1store.ErrInvalidCursor) {
@@ -758,20 +907,119 @@ func runList[T v1alpha1.Object](
 		}
 		return nil, huma.Error500InternalServerError("list "+cfg.Kind, err)
 	}
+	if ranking != nil {
+		rows = sortByRanking(rows, ranking)
+		nextCursor = ""
+	}
 	items := make([]T, 0, len(rows))
 	for _, row := range rows {
 		obj, err := v1alpha1.EnvelopeFromRaw(newObj, row, cfg.Kind)
 		if err != nil {
 			return nil, huma.Error500InternalServerError("decode "+cfg.Kind, err)
 		}
+		cfg.redact(obj)
 		items = append(items, obj)
 	}
 	out := &listOutput[T]{}
 	out.Body.Items = items
 	out.Body.NextCursor = nextCursor
+	if p.IncludeTotal {
+		total, err := cfg.Store.Count(ctx, opts)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("count "+cfg.Kind, err)
+		}
+		out.Body.Total = &total
+	}
+	if cfg.Usage != nil {
+		usage, err := listUsage(ctx, cfg, rows)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("list "+cfg.Kind+" usage", err)
+		}
+		out.Body.Usage = usage
+	}
 	return out, nil
 }
 
+// listSortNames returns the ?sort= values cfg's list accepts.
+func listSortNames(cfg Config) []string {
+	names := []string{sortName, sortUpdatedAt}
+	if cfg.Usage != nil {
+		names = append(names, sortPopularity)
+	}
+	return append(names, slices.Sorted(maps.Keys(cfg.ListSorts))...)
+}
+
+// popularityFilter narrows opts to the latest tag of the opts.Limit most
+// used artifacts and returns their ranking. Filters already on opts (labels,
+// authz) still apply, so the page can come back shorter than the ranking.
+func popularityFilter(ctx context.Context, cfg Config, opts *v1alpha1store.ListOpts) ([]v1alpha1store.UsageKey, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	ranking, err := cfg.Usage.Top(ctx, cfg.Kind, opts.Namespace, limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("rank "+cfg.Kind, err)
+	}
+	if len(ranking) == 0 {
+		return ranking, nil
+	}
+	ids := make([]string, len(ranking))
+	for i, key := range ranking {
+		ids[i] = key.Namespace + "/" + key.Name
+	}
+	appendExtraWhere(opts, "namespace || '/' || name = ANY($%d)", ids)
+	if opts.Tag == "" {
+		opts.LatestOnly = true
+	}
+	opts.Limit = len(ranking)
+	return ranking, nil
+}
+
+// sortByRanking orders rows as their names appear in ranking.
+func sortByRanking(rows []*v1alpha1.RawObject, ranking []v1alpha1store.UsageKey) []*v1alpha1.RawObject {
+	rank := make(map[string]int, len(ranking))
+	for i, key := range ranking {
+		rank[key.Namespace+"/"+key.Name] = i
+	}
+	slices.SortStableFunc(rows, func(a, b *v1alpha1.RawObject) int {
+		return rank[a.Metadata.Namespace+"/"+a.Metadata.Name] - rank[b.Metadata.Namespace+"/"+b.Metadata.Name]
+	})
+	return rows
+}
+
+// listUsage returns the counters of each distinct name among rows.
+func listUsage(ctx context.Context, cfg Config, rows []*v1alpha1.RawObject) (map[string]arv0.ArtifactUsage, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	seen := make(map[v1alpha1store.UsageKey]bool, len(rows))
+	keys := make([]v1alpha1store.UsageKey, 0, len(rows))
+	for _, row := range rows {
+		key := v1alpha1store.UsageKey{Kind: cfg.Kind, Namespace: row.Metadata.Namespace, Name: row.Metadata.Name}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	counts, err := cfg.Usage.Counts(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	if len(counts) == 0 {
+		return nil, nil
+	}
+	usage := make(map[string]arv0.ArtifactUsage, len(counts))
+	for key, c := range counts {
+		usage[key.Namespace+"/"+key.Name] = arv0.ArtifactUsage{
+			Downloads:  c.Downloads,
+			Deploys:    c.Deploys,
+			SearchHits: c.SearchHits,
+		}
+	}
+	return usage, nil
+}
+
 func applyOriginFilter(opts *v1alpha1store.ListOpts, origin string) {
 	if opts == nil {
 		return
@@ -9,8 +9,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/humatest"
@@ -18,6 +20,7 @@ import (
 
 	arv0 "github.com/agentregistry-dev/agentregistry/pkg/api/v0"
 	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
 	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
 	"github.com/agentregistry-dev/agentregistry/pkg/types"
@@ -193,6 +196,52 @@ spec:
 	require.Empty(t, list.Items)
 }
 
+func TestResourceRegister_CanonicalNames(t *testing.T) {
+	pool := v1alpha1store.NewTestPool(t)
+	store := v1alpha1store.NewStore(pool, v1alpha1store.TestSchema(), "agents")
+
+	_, api := humatest.New(t)
+	registerAgent(api, store)
+
+	res := applyAgentYAML(t, api, `apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  name: Alice-Bot
+spec:
+  title: Alice
+  source:
+    image: ghcr.io/example/alice:1.0.0
+`)
+	require.Equal(t, arv0.ApplyStatusCreated, res.Status, res.Error)
+	require.Equal(t, "alice-bot", res.Name)
+
+	for _, path := range []string{"/v0/agents/alice-bot", "/v0/agents/Alice-Bot", "/v0/agents/ALICE-BOT/latest"} {
+		resp := api.Get(path)
+		require.Equal(t, http.StatusOK, resp.Code, path)
+		var got v1alpha1.Agent
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+		require.Equal(t, "alice-bot", got.Metadata.Name)
+	}
+
+	// Another spelling of the name, in another spelling of the namespace,
+	// updates the same resource.
+	res = applyAgentYAML(t, api, `apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  namespace: Default
+  name: ALICE-bot
+spec:
+  title: Alice Two
+  source:
+    image: ghcr.io/example/alice:1.0.0
+`)
+	require.Equal(t, arv0.ApplyStatusConfigured, res.Status, res.Error)
+	require.Equal(t, "alice-bot", res.Name)
+	resp := api.Get("/v0/agents/alice-bot?namespace=DEFAULT")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Body.String(), "Alice Two")
+}
+
 func TestResourceRegister_DeleteTaggedPassesTagToAuthorizer(t *testing.T) {
 	pool := v1alpha1store.NewTestPool(t)
 	store := v1alpha1store.NewStore(pool, v1alpha1store.TestSchema(), "agents")
@@ -377,6 +426,58 @@ spec:
 	require.Len(t, seen, 3)
 }
 
+func TestResourceRegister_AgentListSortAndTotal(t *testing.T) {
+	pool := v1alpha1store.NewTestPool(t)
+	store := v1alpha1store.NewStore(pool, v1alpha1store.TestSchema(), "agents")
+
+	_, api := humatest.New(t)
+	registerAgent(api, store)
+
+	for _, name := range []string{"one", "two", "three"} {
+		yaml := fmt.Sprintf(`apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  namespace: default
+  name: %s
+spec:
+  title: %s
+`, name, name)
+		res := applyAgentYAML(t, api, yaml)
+		require.Equal(t, arv0.ApplyStatusCreated, res.Status)
+	}
+
+	type page struct {
+		Items      []v1alpha1.Agent `json:"items"`
+		NextCursor string           `json:"nextCursor,omitempty"`
+		Total      *int             `json:"total,omitempty"`
+	}
+
+	resp := api.Get("/v0/agents?limit=2&sort=updatedAt&includeTotal=true")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var page1 page
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &page1))
+	require.Len(t, page1.Items, 2)
+	require.Equal(t, "three", page1.Items[0].Metadata.Name, "newest first")
+	require.Equal(t, "two", page1.Items[1].Metadata.Name)
+	require.NotNil(t, page1.Total)
+	require.Equal(t, 3, *page1.Total)
+
+	resp = api.Get("/v0/agents?limit=2&sort=updatedAt&cursor=" + url.QueryEscape(page1.NextCursor))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var page2 page
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &page2))
+	require.Len(t, page2.Items, 1)
+	require.Equal(t, "one", page2.Items[0].Metadata.Name)
+	require.Nil(t, page2.Total, "total is opt-in")
+
+	resp = api.Get("/v0/agents?limit=2&cursor=" + url.QueryEscape(page1.NextCursor))
+	require.Equal(t, http.StatusBadRequest, resp.Code, "a cursor only continues its own sort")
+
+	resp = api.Get("/v0/agents?sort=status")
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), "expected name, updatedAt")
+}
+
 // TestResourceRegister_AgentListTags pins the GET /v0/{plural}/{name}/tags
 // contract: every non-deleted tag row for (namespace, name) is returned.
 func TestResourceRegister_AgentListTags(t *testing.T) {
@@ -423,6 +524,150 @@ func TestResourceRegister_AgentListTags(t *testing.T) {
 	require.Empty(t, empty.Items)
 }
 
+// fakeUsage ranks artifacts by a fixed download count and counts GETs.
+type fakeUsage struct {
+	downloads map[string]int64
+}
+
+func (f *fakeUsage) RecordDownload(kind, namespace, name string) {
+	f.downloads[namespace+"/"+name]++
+}
+
+func (f *fakeUsage) Counts(_ context.Context, keys []v1alpha1store.UsageKey) (map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts, error) {
+	out := map[v1alpha1store.UsageKey]v1alpha1store.UsageCounts{}
+	for _, key := range keys {
+		if n := f.downloads[key.Namespace+"/"+key.Name]; n > 0 {
+			out[key] = v1alpha1store.UsageCounts{Downloads: n}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeUsage) Top(_ context.Context, kind, namespace string, limit int) ([]v1alpha1store.UsageKey, error) {
+	var out []v1alpha1store.UsageKey
+	for id := range f.downloads {
+		ns, name, _ := strings.Cut(id, "/")
+		out = append(out, v1alpha1store.UsageKey{Kind: kind, Namespace: ns, Name: name})
+	}
+	slices.SortFunc(out, func(a, b v1alpha1store.UsageKey) int {
+		return int(f.downloads[b.Namespace+"/"+b.Name] - f.downloads[a.Namespace+"/"+a.Name])
+	})
+	return out[:min(limit, len(out))], nil
+}
+
+// TestResourceRegister_AgentUsage pins download counting, the per-item
+// usage map on list responses and ?sort=popularity.
+func TestResourceRegister_AgentUsage(t *testing.T) {
+	pool := v1alpha1store.NewTestPool(t)
+	store := v1alpha1store.NewStore(pool, v1alpha1store.TestSchema(), "agents")
+	usage := &fakeUsage{downloads: map[string]int64{}}
+
+	_, api := humatest.New(t)
+	resource.Register[*v1alpha1.Agent](api, resource.Config{
+		Kind:       v1alpha1.KindAgent,
+		BasePrefix: "/v0",
+		Store:      store,
+		Usage:      usage,
+	}, func() *v1alpha1.Agent { return &v1alpha1.Agent{} })
+
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		_, err := store.Upsert(t.Context(), &v1alpha1.Agent{
+			TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindAgent},
+			Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: name, Tag: v1alpha1store.DefaultTag()},
+			Spec:     v1alpha1.AgentSpec{Title: name},
+		})
+		require.NoError(t, err)
+	}
+	for _, path := range []string{"/v0/agents/gamma", "/v0/agents/gamma/latest", "/v0/agents/beta"} {
+		resp := api.Get(path)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	}
+	require.Equal(t, map[string]int64{"default/gamma": 2, "default/beta": 1}, usage.downloads)
+
+	var list struct {
+		Items []v1alpha1.Agent              `json:"items"`
+		Usage map[string]arv0.ArtifactUsage `json:"usage"`
+	}
+	resp := api.Get("/v0/agents?sort=popularity")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Len(t, list.Items, 2, "artifacts without recorded use are not ranked")
+	require.Equal(t, "gamma", list.Items[0].Metadata.Name)
+	require.Equal(t, "beta", list.Items[1].Metadata.Name)
+	require.Equal(t, int64(2), list.Usage["default/gamma"].Downloads)
+
+	resp = api.Get("/v0/agents?sort=popularity&cursor=abc")
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	resp = api.Get("/v0/agents?sort=stars")
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+}
+
+func TestResourceRegister_AgentPatch(t *testing.T) {
+	pool := v1alpha1store.NewTestPool(t)
+	store := v1alpha1store.NewStore(pool, v1alpha1store.TestSchema(), "agents")
+
+	_, api := humatest.New(t)
+	registerAgent(api, store)
+	_, err := store.Upsert(t.Context(), &v1alpha1.Agent{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindAgent},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "alpha", Tag: "1.0.0"},
+		Spec:     v1alpha1.AgentSpec{Title: "Alpha", Description: "first"},
+	})
+	require.NoError(t, err)
+
+	resp := api.Get("/v0/agents/alpha/1.0.0")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	etag := resp.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// Merge patch with a matching If-Match.
+	resp = api.Patch("/v0/agents/alpha/1.0.0",
+		"Content-Type: application/merge-patch+json", "If-Match: "+etag,
+		strings.NewReader(`{"spec":{"description":"fixed"}}`))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var got v1alpha1.Agent
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	require.Equal(t, "Alpha", got.Spec.Title)
+	require.Equal(t, "fixed", got.Spec.Description)
+	require.NotEqual(t, etag, resp.Header().Get("ETag"))
+
+	// The old ETag is stale now.
+	resp = api.Patch("/v0/agents/alpha/1.0.0",
+		"Content-Type: application/merge-patch+json", "If-Match: "+etag,
+		strings.NewReader(`{"spec":{"description":"lost update"}}`))
+	require.Equal(t, http.StatusPreconditionFailed, resp.Code, resp.Body.String())
+
+	// JSON Patch, including a failing test op.
+	resp = api.Patch("/v0/agents/alpha/1.0.0",
+		"Content-Type: application/json-patch+json",
+		strings.NewReader(`[{"op":"test","path":"/spec/description","value":"first"}]`))
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body.String())
+	resp = api.Patch("/v0/agents/alpha/1.0.0",
+		"Content-Type: application/json-patch+json",
+		strings.NewReader(`[{"op":"replace","path":"/spec/title","value":"Alpha Two"}]`))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	row, err := store.Get(t.Context(), "default", "alpha", "1.0.0")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"title":"Alpha Two","description":"fixed"}`, string(row.Spec))
+
+	// Identity is fixed, the result must validate, and the media type must
+	// be a patch.
+	resp = api.Patch("/v0/agents/alpha/1.0.0",
+		"Content-Type: application/merge-patch+json",
+		strings.NewReader(`{"metadata":{"tag":"2.0.0"}}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	resp = api.Patch("/v0/agents/alpha/1.0.0",
+		"Content-Type: application/merge-patch+json",
+		strings.NewReader(`{"spec":{"title":"   "}}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	resp = api.Patch("/v0/agents/alpha/1.0.0",
+		"Content-Type: text/plain", strings.NewReader(`{}`))
+	require.Equal(t, http.StatusUnsupportedMediaType, resp.Code, resp.Body.String())
+	resp = api.Patch("/v0/agents/missing/1.0.0",
+		"Content-Type: application/merge-patch+json", strings.NewReader(`{}`))
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
+
 func TestResourceRegister_AgentListRejectsInvalidCursor(t *testing.T) {
 	pool := v1alpha1store.NewTestPool(t)
 	store := v1alpha1store.NewStore(pool, v1alpha1store.TestSchema(), "agents")
@@ -602,6 +847,111 @@ func TestResourceRegister_MutableObjectUsesNameOnlyRoute(t *testing.T) {
 	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
 }
 
+func TestResourceRegister_LimitViolationsAre422(t *testing.T) {
+	pool := v1alpha1store.NewTestPool(t)
+	store := v1alpha1store.NewMutableObjectStore(pool, v1alpha1store.TestSchema(), "runtimes")
+
+	_, api := humatest.New(t)
+	registerProvider(api, store)
+
+	runtime := v1alpha1.Runtime{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindRuntime},
+		Metadata: v1alpha1.ObjectMeta{Name: "huge"},
+		Spec: v1alpha1.RuntimeSpec{
+			Type:   v1alpha1.TypeLocal,
+			Config: map[string]any{"blob": strings.Repeat("x", v1alpha1.MaxSpecBytes)},
+		},
+	}
+	resp := api.Put("/v0/runtimes/huge", runtime)
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+
+	var body huma.ErrorModel
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	locations := make([]string, 0, len(body.Errors))
+	for _, e := range body.Errors {
+		locations = append(locations, e.Location)
+	}
+	require.Equal(t, []string{"body.spec"}, locations)
+
+	resp = api.Get("/v0/runtimes/huge")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+// TestResourceRegister_PrepareConflictIs409 pins the Prepare conflict
+// contract: a hook error matching pkgdb.ErrAlreadyExists answers 409 on the
+// PUT route and a "conflict:" result on POST /v0/apply, and nothing is
+// written.
+func TestResourceRegister_PrepareConflictIs409(t *testing.T) {
+	pool := v1alpha1store.NewTestPool(t)
+	runtimes := v1alpha1store.NewMutableObjectStore(pool, v1alpha1store.TestSchema(), "runtimes")
+	agents := v1alpha1store.NewStore(pool, v1alpha1store.TestSchema(), "agents")
+	conflict := func(ctx context.Context, obj v1alpha1.Object) error {
+		return fmt.Errorf("image already used by Agent default/other@1.0.0: %w", pkgdb.ErrAlreadyExists)
+	}
+
+	_, api := humatest.New(t)
+	resource.Register[*v1alpha1.Runtime](api, resource.Config{
+		Kind:       v1alpha1.KindRuntime,
+		BasePrefix: "/v0",
+		Store:      runtimes,
+		Prepare:    conflict,
+	}, func() *v1alpha1.Runtime { return &v1alpha1.Runtime{} })
+	resource.RegisterApply(api, resource.ApplyConfig{
+		BasePrefix: "/v0",
+		Stores:     map[string]*v1alpha1store.Store{v1alpha1.KindAgent: agents},
+		Prepare:    conflict,
+	})
+
+	resp := api.Put("/v0/runtimes/local-test", v1alpha1.Runtime{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindRuntime},
+		Metadata: v1alpha1.ObjectMeta{Name: "local-test"},
+		Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeLocal},
+	})
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "Agent default/other@1.0.0")
+	resp = api.Get("/v0/runtimes/local-test")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+
+	res := applyAgentYAML(t, api, `apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  name: dup
+spec:
+  title: Dup
+`)
+	require.Equal(t, arv0.ApplyStatusFailed, res.Status)
+	require.True(t, strings.HasPrefix(res.Error, "conflict: "), res.Error)
+}
+
+// TestResourceRegister_DeleteAdmissionConflictIs409 pins that a delete
+// admission error matching pkgdb.ErrConflict (e.g. a running deploy)
+// answers 409 and leaves the row in place.
+func TestResourceRegister_DeleteAdmissionConflictIs409(t *testing.T) {
+	pool := v1alpha1store.NewTestPool(t)
+	runtimes := v1alpha1store.NewMutableObjectStore(pool, v1alpha1store.TestSchema(), "runtimes")
+	_, err := runtimes.Upsert(context.Background(), &v1alpha1.Runtime{
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "local-test"},
+		Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeLocal},
+	})
+	require.NoError(t, err)
+
+	_, api := humatest.New(t)
+	resource.Register[*v1alpha1.Runtime](api, resource.Config{
+		Kind:       v1alpha1.KindRuntime,
+		BasePrefix: "/v0",
+		Store:      runtimes,
+		DeleteAdmission: func(context.Context, types.DeleteAdmissionInput) (types.DeleteAdmissionResult, error) {
+			return types.DeleteAdmissionResult{}, fmt.Errorf("rollout in progress: %w", pkgdb.ErrConflict)
+		},
+	}, func() *v1alpha1.Runtime { return &v1alpha1.Runtime{} })
+
+	resp := api.Delete("/v0/runtimes/local-test")
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "rollout in progress")
+	resp = api.Get("/v0/runtimes/local-test")
+	require.Equal(t, http.StatusOK, resp.Code)
+}
+
 func TestResourceRegister_ResolverDetectsDanglingRef(t *testing.T) {
 	pool := v1alpha1store.NewTestPool(t)
 	agentStore := v1alpha1store.NewStore(pool, v1alpha1store.TestSchema(), "agents")
@@ -712,6 +1062,50 @@ spec:
 	require.Equal(t, v1alpha1store.DefaultTag(), row.Metadata.Tag)
 }
 
+func TestResourceRegister_RestoreDeletedTag(t *testing.T) {
+	pool := v1alpha1store.NewTestPool(t)
+	store := v1alpha1store.NewStore(pool, v1alpha1store.TestSchema(), "agents",
+		v1alpha1store.WithDeletedRetention(time.Hour))
+
+	_, api := humatest.New(t)
+	registerAgent(api, store)
+
+	res := applyAgentYAML(t, api, `apiVersion: ar.dev/v1alpha1
+kind: Agent
+metadata:
+  namespace: default
+  name: kept
+spec:
+  title: Kept
+`)
+	require.Equal(t, arv0.ApplyStatusCreated, res.Status)
+
+	// Restoring a live tag is a 404: there is nothing to undo.
+	resp := api.Post("/v0/agents/kept/latest/restore", map[string]any{})
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	resp = api.Delete("/v0/agents/kept/latest")
+	require.Equal(t, http.StatusNoContent, resp.Code)
+	resp = api.Get("/v0/agents/kept/latest")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+
+	// The deleted tag is still listed when terminating rows are asked for.
+	resp = api.Get("/v0/agents?includeTerminating=true")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), `"kept"`)
+
+	resp = api.Post("/v0/agents/kept/latest/restore", map[string]any{})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.NotEmpty(t, resp.Header().Get("ETag"))
+	var restored v1alpha1.Agent
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &restored))
+	require.Equal(t, "Kept", restored.Spec.Title)
+	require.Nil(t, restored.Metadata.DeletionTimestamp)
+
+	resp = api.Get("/v0/agents/kept/latest")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+}
+
 // TestResourceRegister_PostUpsertFailureLeavesPersistedRow pins the
 // documented controller-foundation contract: when PostUpsert returns an
 // error, Store.Upsert has already committed and the row is persisted;
@@ -0,0 +1,195 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/v1alpha1store"
+)
+
+// Patch media types accepted by PATCH. Plain application/json is treated
+// as a merge patch.
+const (
+	contentTypeJSONPatch  = "application/json-patch+json"
+	contentTypeMergePatch = "application/merge-patch+json"
+)
+
+type patchInput struct {
+	Namespace   string `query:"namespace" doc:"Namespace (internal; defaults to 'default')."`
+	Name        string `path:"name"`
+	Tag         string `path:"tag"`
+	IfMatch     string `header:"If-Match" doc:"ETag from a previous GET; the patch is refused with 412 if the artifact changed since."`
+	ContentType string `header:"Content-Type"`
+	RawBody     []byte `contentType:"application/json-patch+json"`
+}
+
+// contentETag is the strong ETag of a tagged artifact row: its content
+// hash, which changes whenever labels, annotations or spec do. Empty when
+// the row cannot be hashed.
+func contentETag(row *v1alpha1.RawObject) string {
+	hash, err := v1alpha1store.ContentHash(&row.Metadata, row.Spec)
+	if err != nil {
+		return ""
+	}
+	return `"` + hash + `"`
+}
+
+// etagMatches reports whether an If-Match header value names etag. "*"
+// matches any existing artifact; weak validators never match, as
+// RFC 9110 requires strong comparison for If-Match.
+func etagMatches(ifMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// registerPatchTagged wires PATCH for one tag of an artifact. The patch
+// is applied to the document GET returns and the result goes through the
+// same validation, authorization and hooks as an apply. The write is
+// conditional on the row the patch was computed from, so a concurrent
+// apply fails the PATCH instead of being overwritten.
+func registerPatchTagged[T v1alpha1.Object](api huma.API, cfg Config, newObj func() T, kind, itemTagPath string) {
+	huma.Register(api, huma.Operation{
+		OperationID: "patch-" + strings.ToLower(kind),
+		Method:      http.MethodPatch,
+		Path:        itemTagPath,
+		Summary:     fmt.Sprintf("Patch a %s by name and tag", kind),
+		Description: "Accepts an RFC 6902 JSON Patch (application/json-patch+json) or an RFC 7396 merge patch " +
+			"(application/merge-patch+json) against the document GET returns. metadata.namespace, name and tag " +
+			"cannot change; status is ignored. Send the GET's ETag in If-Match to refuse the patch (412) when " +
+			"the artifact changed in between.",
+		RequestBody: &huma.RequestBody{
+			Content: map[string]*huma.MediaType{
+				contentTypeMergePatch: {Schema: &huma.Schema{Type: huma.TypeObject}},
+			},
+		},
+	}, func(ctx context.Context, in *patchInput) (*bodyOutput[T], error) {
+		ns := resolveNamespace(in.Namespace, false)
+		name, err := UnescapeName(in.Name)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := UnescapePath("tag", in.Tag)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, AuthorizeInput{Verb: "get", Kind: kind, Namespace: ns, Name: name, Tag: tag}); err != nil {
+				return nil, err
+			}
+		}
+		row, err := cfg.Store.Get(ctx, ns, name, tag)
+		if err != nil {
+			return nil, mapNotFound(err, kind, ns, name, tag)
+		}
+		etag := contentETag(row)
+		if etag == "" {
+			return nil, huma.Error500InternalServerError("hash "+kind, errors.New("content hash failed"))
+		}
+		if in.IfMatch != "" && !etagMatches(in.IfMatch, etag) {
+			return nil, huma.Error412PreconditionFailed(fmt.Sprintf(
+				"%s %s/%s/%s has ETag %s", kind, ns, name, tag, etag))
+		}
+
+		current, err := v1alpha1.EnvelopeFromRaw(newObj, row, kind)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("decode "+kind, err)
+		}
+		doc, err := json.Marshal(current)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("encode "+kind, err)
+		}
+		patched, err := applyPatch(in.ContentType, doc, in.RawBody)
+		if err != nil {
+			return nil, err
+		}
+		obj := newObj()
+		if err := json.Unmarshal(patched, obj); err != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("patched document is not a valid %s: %v", kind, err))
+		}
+		if obj.GetAPIVersion() != v1alpha1.GroupVersion || obj.GetKind() != kind {
+			return nil, huma.Error400BadRequest("apiVersion and kind cannot be patched")
+		}
+		meta := obj.GetMetadata()
+		if (meta.Namespace != "" && meta.Namespace != ns) || meta.Name != name || meta.Tag != tag {
+			return nil, huma.Error400BadRequest("metadata.namespace, metadata.name and metadata.tag cannot be patched")
+		}
+		meta.Namespace = ns
+		obj.SetMetadata(*meta)
+
+		if _, ae := applyCore(ctx, cfg.Store, obj, applyOpts{
+			Authorize:         cfg.Authorize,
+			Resolver:          cfg.Resolver,
+			RegistryValidator: cfg.RegistryValidator,
+			PostUpsert:        cfg.PostUpsert,
+			InitialFinalizers: cfg.InitialFinalizers,
+			Prepare:           cfg.Prepare,
+			IfContentHash:     strings.Trim(etag, `"`),
+		}, false); ae != nil {
+			// Without If-Match the caller asked for no precondition: losing
+			// the race with another writer is a conflict to retry.
+			if in.IfMatch == "" && errors.Is(ae.Err, v1alpha1store.ErrPreconditionFailed) {
+				return nil, huma.Error409Conflict(fmt.Sprintf(
+					"%s %s/%s/%s changed while the patch was applied; retry", kind, ns, name, tag))
+			}
+			return nil, mapApplyErrorToHuma(ae, kind, ns, name, tag)
+		}
+
+		row, err = cfg.Store.Get(ctx, ns, name, tag)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("read back "+kind, err)
+		}
+		out, err := v1alpha1.EnvelopeFromRaw(newObj, row, kind)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("decode "+kind, err)
+		}
+		cfg.redact(out)
+		return &bodyOutput[T]{ETag: contentETag(row), Body: out}, nil
+	})
+}
+
+// applyPatch applies patch to doc according to the request media type.
+func applyPatch(contentType string, doc, patch []byte) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil && contentType != "" {
+		return nil, huma.Error415UnsupportedMediaType(fmt.Sprintf("invalid Content-Type %q", contentType))
+	}
+	switch mediaType {
+	case contentTypeJSONPatch:
+		ops, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid JSON patch: %v", err))
+		}
+		out, err := ops.Apply(doc)
+		if err != nil {
+			// A failed "test" op is the patch's own precondition.
+			if errors.Is(err, jsonpatch.ErrTestFailed) {
+				return nil, huma.Error409Conflict(fmt.Sprintf("JSON patch test failed: %v", err))
+			}
+			return nil, huma.Error422UnprocessableEntity(fmt.Sprintf("apply JSON patch: %v", err))
+		}
+		return out, nil
+	case contentTypeMergePatch, "application/json", "":
+		out, err := jsonpatch.MergePatch(doc, patch)
+		if err != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid merge patch: %v", err))
+		}
+		return out, nil
+	default:
+		return nil, huma.Error415UnsupportedMediaType(fmt.Sprintf(
+			"unsupported Content-Type %q; use %s or %s", mediaType, contentTypeJSONPatch, contentTypeMergePatch))
+	}
+}
@@ -0,0 +1,36 @@
+package resource
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// UnescapePath URL-decodes a path segment captured by Huma. Resource
+// names allow `/` (DNS-subdomain-style like `ai.exa/exa`) so callers
+// pass them as `%2F`-escaped path segments. Huma keeps the raw path
+// captures, so the handler must unescape before consulting the Store —
+// otherwise rows stored as `ai.exa/exa` are unreachable via GET/DELETE.
+// Returns a 400 on decode failure (malformed escape sequence).
+func UnescapePath(field, value string) (string, error) {
+	out, err := url.PathUnescape(value)
+	if err != nil {
+		return "", huma.Error400BadRequest(fmt.Sprintf("invalid %s path segment: %v", field, err))
+	}
+	return out, nil
+}
+
+// UnescapeName URL-decodes a `{name}` path capture and returns its
+// canonical form, so `Weather-Bot` finds the resource published as
+// `weather-bot` (see v1alpha1.CanonicalName). Every handler that looks
+// a resource up by a `{name}` capture goes through it.
+func UnescapeName(value string) (string, error) {
+	name, err := UnescapePath("name", value)
+	if err != nil {
+		return "", err
+	}
+	return v1alpha1.CanonicalName(name), nil
+}
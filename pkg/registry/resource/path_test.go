@@ -0,0 +1,41 @@
+package resource_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/registry/resource"
+)
+
+func TestUnescapeName(t *testing.T) {
+	for raw, want := range map[string]string{
+		"weather-bot":      "weather-bot",
+		"Weather-Bot":      "weather-bot",
+		"ｗｅａｔｈｅｒ-bot":      "weather-bot",
+		"ai.exa%2FExa":     "ai.exa/exa",
+		"io.github%2fAcme": "io.github/acme",
+	} {
+		got, err := resource.UnescapeName(raw)
+		require.NoError(t, err, raw)
+		require.Equal(t, want, got, raw)
+	}
+
+	_, err := resource.UnescapeName("bad%zz")
+	var statusErr huma.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusBadRequest, statusErr.GetStatus())
+	require.Contains(t, err.Error(), "invalid name path segment")
+}
+
+func TestUnescapePath(t *testing.T) {
+	// Tags are unescaped but keep their case.
+	got, err := resource.UnescapePath("tag", "1.0.0-RC%2B1")
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0-RC+1", got)
+
+	_, err = resource.UnescapePath("tag", "%")
+	require.ErrorContains(t, err, "invalid tag path segment")
+}
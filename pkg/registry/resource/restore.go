@@ -0,0 +1,62 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+type restoreInput struct {
+	Namespace string `query:"namespace" doc:"Namespace (internal; defaults to 'default')."`
+	Name      string `path:"name"`
+	Tag       string `path:"tag"`
+}
+
+// registerRestoreTagged wires POST {name}/{tag}/restore, which undoes the
+// DELETE of one tag while it is still inside the store's deleted-artifact
+// retention window (see v1alpha1store.WithDeletedRetention). Restoring is
+// authorized like an apply, since it makes the tag live again.
+func registerRestoreTagged[T v1alpha1.Object](api huma.API, cfg Config, newObj func() T, kind, itemTagPath string) {
+	huma.Register(api, huma.Operation{
+		OperationID: "restore-" + strings.ToLower(kind),
+		Method:      http.MethodPost,
+		Path:        itemTagPath + "/restore",
+		Summary:     fmt.Sprintf("Restore a deleted %s tag", kind),
+		Description: "Makes a deleted tag live again with the content it had when deleted. Deleted tags are kept " +
+			"for the server's deleted-artifact retention period and listed with ?includeTerminating=true; " +
+			"a tag that is live, already purged or never existed answers 404.",
+	}, func(ctx context.Context, in *restoreInput) (*bodyOutput[T], error) {
+		ns := resolveNamespace(in.Namespace, false)
+		name, err := UnescapeName(in.Name)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := UnescapePath("tag", in.Tag)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Authorize != nil {
+			if err := cfg.Authorize(ctx, AuthorizeInput{Verb: "apply", Kind: kind, Namespace: ns, Name: name, Tag: tag}); err != nil {
+				return nil, err
+			}
+		}
+		if err := cfg.Store.Restore(ctx, ns, name, tag); err != nil {
+			return nil, mapNotFound(err, kind, ns, name, tag)
+		}
+		row, err := cfg.Store.Get(ctx, ns, name, tag)
+		if err != nil {
+			return nil, mapNotFound(err, kind, ns, name, tag)
+		}
+		obj, err := v1alpha1.EnvelopeFromRaw(newObj, row, kind)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("decode "+kind, err)
+		}
+		cfg.redact(obj)
+		return &bodyOutput[T]{ETag: contentETag(row), Body: obj}, nil
+	})
+}
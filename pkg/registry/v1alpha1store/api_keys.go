@@ -0,0 +1,226 @@
+package v1alpha1store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// APIKey is one scoped credential (migration 021). Only the SHA-256 of its
+// secret is stored.
+type APIKey struct {
+	ID         string
+	Name       string
+	Owner      string
+	SecretHash string
+	// Kinds, Prefixes and Actions scope the key. Empty Kinds or Prefixes
+	// mean every artifact kind or name.
+	Kinds    []string
+	Prefixes []string
+	Actions  []string
+	// OwnerPermissions are the permissions the owner held when the key
+	// was created (migration 036). The key never reaches beyond them.
+	OwnerPermissions []APIKeyPermission
+	ExpiresAt        *time.Time
+	LastUsedAt       *time.Time
+	RevokedAt        *time.Time
+	CreatedAt        time.Time
+}
+
+// APIKeyPermission is one permission recorded on a key: an auth
+// permission action and the resource pattern it applies to ("acme/*").
+type APIKeyPermission struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+}
+
+// Active reports whether the key authenticates at now: not revoked and
+// not expired.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APIKeyStore reads and writes API keys.
+type APIKeyStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewAPIKeyStore constructs an API key store.
+func NewAPIKeyStore(pool *pgxpool.Pool, schema pkgdb.Schema) *APIKeyStore {
+	return &APIKeyStore{pool: pool, qualified: schema.Qualify("api_keys")}
+}
+
+const apiKeyColumns = `id, name, owner, secret_hash, kinds, prefixes, actions, owner_permissions, expires_at, last_used_at, revoked_at, created_at`
+
+// Create inserts key. Its ID and SecretHash are chosen by the caller; an
+// ID collision returns an error matching pkgdb.ErrAlreadyExists.
+func (s *APIKeyStore) Create(ctx context.Context, key *APIKey) (*APIKey, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: API key store has nil pool")
+	}
+	kinds, err := marshalAPIKeyScope(key.Kinds)
+	if err != nil {
+		return nil, err
+	}
+	prefixes, err := marshalAPIKeyScope(key.Prefixes)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := marshalAPIKeyScope(key.Actions)
+	if err != nil {
+		return nil, err
+	}
+	ownerPermissions := key.OwnerPermissions
+	if ownerPermissions == nil {
+		ownerPermissions = []APIKeyPermission{}
+	}
+	perms, err := json.Marshal(ownerPermissions)
+	if err != nil {
+		return nil, fmt.Errorf("encode API key owner permissions: %w", err)
+	}
+	row := s.pool.QueryRow(ctx, `
+		INSERT INTO `+s.qualified+` (id, name, owner, secret_hash, kinds, prefixes, actions, owner_permissions, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO NOTHING
+		RETURNING `+apiKeyColumns,
+		key.ID, key.Name, key.Owner, key.SecretHash, kinds, prefixes, actions, perms, key.ExpiresAt)
+	out, err := scanAPIKey(row)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("%w: API key %s", pkgdb.ErrAlreadyExists, key.ID)
+	case err != nil:
+		return nil, fmt.Errorf("create API key: %w", err)
+	}
+	return out, nil
+}
+
+// Get returns the key with id, revoked or not, or pkgdb.ErrNotFound.
+func (s *APIKeyStore) Get(ctx context.Context, id string) (*APIKey, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: API key store has nil pool")
+	}
+	row := s.pool.QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM `+s.qualified+` WHERE id = $1`, id)
+	key, err := scanAPIKey(row)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, pkgdb.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("get API key %s: %w", id, err)
+	}
+	return key, nil
+}
+
+// List returns API keys, newest first. A non-empty owner limits them to
+// that owner's.
+func (s *APIKeyStore) List(ctx context.Context, owner string) ([]*APIKey, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: API key store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+apiKeyColumns+` FROM `+s.qualified+`
+		WHERE $1 = '' OR owner = $1
+		ORDER BY created_at DESC, id`, owner)
+	if err != nil {
+		return nil, fmt.Errorf("list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan API key: %w", err)
+		}
+		out = append(out, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read API keys: %w", err)
+	}
+	return out, nil
+}
+
+// Revoke stops key id from authenticating. Revoking an already revoked
+// key keeps its original revocation time. Returns pkgdb.ErrNotFound when
+// no such key exists.
+func (s *APIKeyStore) Revoke(ctx context.Context, id string) (*APIKey, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: API key store has nil pool")
+	}
+	row := s.pool.QueryRow(ctx, `
+		UPDATE `+s.qualified+` SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1
+		RETURNING `+apiKeyColumns, id)
+	key, err := scanAPIKey(row)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, pkgdb.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("revoke API key %s: %w", id, err)
+	}
+	return key, nil
+}
+
+// Touch records that key id authenticated a request at at.
+func (s *APIKeyStore) Touch(ctx context.Context, id string, at time.Time) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: API key store has nil pool")
+	}
+	if _, err := s.pool.Exec(ctx, `UPDATE `+s.qualified+` SET last_used_at = $2 WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("touch API key %s: %w", id, err)
+	}
+	return nil
+}
+
+func marshalAPIKeyScope(values []string) ([]byte, error) {
+	if values == nil {
+		values = []string{}
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("encode API key scope: %w", err)
+	}
+	return raw, nil
+}
+
+func scanAPIKey(row pgx.Row) (*APIKey, error) {
+	var (
+		key                             APIKey
+		kinds, prefixes, actions, perms []byte
+	)
+	if err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Owner,
+		&key.SecretHash,
+		&kinds,
+		&prefixes,
+		&actions,
+		&perms,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	for _, field := range []struct {
+		raw []byte
+		dst *[]string
+	}{{kinds, &key.Kinds}, {prefixes, &key.Prefixes}, {actions, &key.Actions}} {
+		if err := json.Unmarshal(field.raw, field.dst); err != nil {
+			return nil, fmt.Errorf("decode scopes of API key %s: %w", key.ID, err)
+		}
+	}
+	if err := json.Unmarshal(perms, &key.OwnerPermissions); err != nil {
+		return nil, fmt.Errorf("decode owner permissions of API key %s: %w", key.ID, err)
+	}
+	return &key, nil
+}
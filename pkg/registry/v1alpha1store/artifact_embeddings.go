@@ -0,0 +1,109 @@
+package v1alpha1store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// ArtifactEmbedding is the semantic search embedding of one artifact's
+// latest tag (migration 025). Model names the provider model that produced
+// Vector; Checksum is the checksum of the text it was generated from.
+type ArtifactEmbedding struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Model     string
+	Checksum  string
+	Vector    []float32
+	UpdatedAt time.Time
+}
+
+// ArtifactEmbeddingStore records embeddings for agents, MCP servers,
+// skills and prompts, one per artifact.
+type ArtifactEmbeddingStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewArtifactEmbeddingStore constructs an artifact embedding store.
+func NewArtifactEmbeddingStore(pool *pgxpool.Pool, schema pkgdb.Schema) *ArtifactEmbeddingStore {
+	return &ArtifactEmbeddingStore{
+		pool:      pool,
+		qualified: schema.Qualify("artifact_embeddings"),
+	}
+}
+
+// Put replaces the embedding recorded for the artifact.
+func (s *ArtifactEmbeddingStore) Put(ctx context.Context, e ArtifactEmbedding) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: artifact embedding store has nil pool")
+	}
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO `+s.qualified+` (kind, namespace, name, model, checksum, embedding, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (kind, namespace, name) DO UPDATE SET
+			model = EXCLUDED.model,
+			checksum = EXCLUDED.checksum,
+			embedding = EXCLUDED.embedding,
+			updated_at = EXCLUDED.updated_at`,
+		e.Kind, e.Namespace, e.Name, e.Model, e.Checksum, e.Vector)
+	if err != nil {
+		return fmt.Errorf("put %s embedding %s/%s: %w", e.Kind, e.Namespace, e.Name, err)
+	}
+	return nil
+}
+
+// Get returns the embedding recorded for the artifact, or
+// pkgdb.ErrNotFound when it has none.
+func (s *ArtifactEmbeddingStore) Get(ctx context.Context, kind, namespace, name string) (*ArtifactEmbedding, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: artifact embedding store has nil pool")
+	}
+	out := &ArtifactEmbedding{Kind: kind, Namespace: namespace, Name: name}
+	err := s.pool.QueryRow(ctx, `
+		SELECT model, checksum, embedding, updated_at
+		FROM `+s.qualified+`
+		WHERE kind = $1 AND namespace = $2 AND name = $3`, kind, namespace, name).
+		Scan(&out.Model, &out.Checksum, &out.Vector, &out.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, pkgdb.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s embedding %s/%s: %w", kind, namespace, name, err)
+	}
+	return out, nil
+}
+
+// List returns every embedding of the given kinds produced by model.
+func (s *ArtifactEmbeddingStore) List(ctx context.Context, kinds []string, model string) ([]ArtifactEmbedding, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: artifact embedding store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT kind, namespace, name, model, checksum, embedding, updated_at
+		FROM `+s.qualified+`
+		WHERE model = $1 AND kind = ANY($2)`, model, kinds)
+	if err != nil {
+		return nil, fmt.Errorf("list embeddings: %w", err)
+	}
+	defer rows.Close()
+	var out []ArtifactEmbedding
+	for rows.Next() {
+		var e ArtifactEmbedding
+		if err := rows.Scan(&e.Kind, &e.Namespace, &e.Name, &e.Model, &e.Checksum, &e.Vector, &e.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan embedding: %w", err)
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read embeddings: %w", err)
+	}
+	return out, nil
+}
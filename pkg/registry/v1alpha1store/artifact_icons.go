@@ -0,0 +1,102 @@
+package v1alpha1store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// ArtifactIcon is the image uploaded as the icon of one artifact name
+// (migration 027). Digest is the hex SHA-256 of Content, set by Put.
+type ArtifactIcon struct {
+	Kind        string
+	Namespace   string
+	Name        string
+	ContentType string
+	Content     []byte
+	Digest      string
+	UpdatedAt   time.Time
+}
+
+// ArtifactIconStore records icons for MCP servers, agents and skills,
+// keyed by kind and name.
+type ArtifactIconStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewArtifactIconStore constructs an artifact icon store.
+func NewArtifactIconStore(pool *pgxpool.Pool, schema pkgdb.Schema) *ArtifactIconStore {
+	return &ArtifactIconStore{
+		pool:      pool,
+		qualified: schema.Qualify("artifact_icons"),
+	}
+}
+
+// Put replaces the icon recorded for the artifact name.
+func (s *ArtifactIconStore) Put(ctx context.Context, icon ArtifactIcon) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: artifact icon store has nil pool")
+	}
+	sum := sha256.Sum256(icon.Content)
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO `+s.qualified+` (kind, namespace, name, content_type, content, digest, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (kind, namespace, name) DO UPDATE SET
+			content_type = EXCLUDED.content_type,
+			content = EXCLUDED.content,
+			digest = EXCLUDED.digest,
+			updated_at = EXCLUDED.updated_at`,
+		icon.Kind, icon.Namespace, icon.Name, icon.ContentType, icon.Content, hex.EncodeToString(sum[:]))
+	if err != nil {
+		return fmt.Errorf("put %s icon %s/%s: %w", icon.Kind, icon.Namespace, icon.Name, err)
+	}
+	return nil
+}
+
+// Get returns the icon recorded for the artifact name, or
+// pkgdb.ErrNotFound when none has been uploaded.
+func (s *ArtifactIconStore) Get(ctx context.Context, kind, namespace, name string) (*ArtifactIcon, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: artifact icon store has nil pool")
+	}
+	out := &ArtifactIcon{Kind: kind, Namespace: namespace, Name: name}
+	err := s.pool.QueryRow(ctx, `
+		SELECT content_type, content, digest, updated_at
+		FROM `+s.qualified+`
+		WHERE kind = $1 AND namespace = $2 AND name = $3`, kind, namespace, name).
+		Scan(&out.ContentType, &out.Content, &out.Digest, &out.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, pkgdb.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s icon %s/%s: %w", kind, namespace, name, err)
+	}
+	return out, nil
+}
+
+// Delete removes the icon recorded for the artifact name, returning
+// pkgdb.ErrNotFound when there was none.
+func (s *ArtifactIconStore) Delete(ctx context.Context, kind, namespace, name string) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: artifact icon store has nil pool")
+	}
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM `+s.qualified+`
+		WHERE kind = $1 AND namespace = $2 AND name = $3`, kind, namespace, name)
+	if err != nil {
+		return fmt.Errorf("delete %s icon %s/%s: %w", kind, namespace, name, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pkgdb.ErrNotFound
+	}
+	return nil
+}
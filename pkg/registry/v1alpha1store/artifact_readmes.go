@@ -0,0 +1,82 @@
+package v1alpha1store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// ArtifactReadme is the Markdown README uploaded for one artifact tag
+// (migration 023). ArtifactUID is the uid of the artifact row it was
+// uploaded for; callers compare it against the live row so a tag that was
+// purged and published again does not serve its predecessor's README.
+type ArtifactReadme struct {
+	Kind        string
+	Namespace   string
+	Name        string
+	Tag         string
+	ArtifactUID string
+	Content     string
+	UpdatedAt   time.Time
+}
+
+// ArtifactReadmeStore records READMEs for agents, MCP servers, skills and
+// prompts, keyed by kind and tag.
+type ArtifactReadmeStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewArtifactReadmeStore constructs an artifact README store.
+func NewArtifactReadmeStore(pool *pgxpool.Pool, schema pkgdb.Schema) *ArtifactReadmeStore {
+	return &ArtifactReadmeStore{
+		pool:      pool,
+		qualified: schema.Qualify("artifact_readmes"),
+	}
+}
+
+// Put replaces the README recorded for the artifact tag.
+func (s *ArtifactReadmeStore) Put(ctx context.Context, r ArtifactReadme) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: artifact readme store has nil pool")
+	}
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO `+s.qualified+` (kind, namespace, name, tag, artifact_uid, content, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (kind, namespace, name, tag) DO UPDATE SET
+			artifact_uid = EXCLUDED.artifact_uid,
+			content = EXCLUDED.content,
+			updated_at = EXCLUDED.updated_at`,
+		r.Kind, r.Namespace, r.Name, r.Tag, r.ArtifactUID, r.Content)
+	if err != nil {
+		return fmt.Errorf("put %s readme %s/%s:%s: %w", r.Kind, r.Namespace, r.Name, r.Tag, err)
+	}
+	return nil
+}
+
+// Get returns the README recorded for the artifact tag, or
+// pkgdb.ErrNotFound when none has been uploaded.
+func (s *ArtifactReadmeStore) Get(ctx context.Context, kind, namespace, name, tag string) (*ArtifactReadme, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: artifact readme store has nil pool")
+	}
+	out := &ArtifactReadme{Kind: kind, Namespace: namespace, Name: name, Tag: tag}
+	err := s.pool.QueryRow(ctx, `
+		SELECT artifact_uid::text, content, updated_at
+		FROM `+s.qualified+`
+		WHERE kind = $1 AND namespace = $2 AND name = $3 AND tag = $4`, kind, namespace, name, tag).
+		Scan(&out.ArtifactUID, &out.Content, &out.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, pkgdb.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s readme %s/%s:%s: %w", kind, namespace, name, tag, err)
+	}
+	return out, nil
+}
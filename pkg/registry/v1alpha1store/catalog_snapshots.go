@@ -0,0 +1,247 @@
+package v1alpha1store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// CatalogSnapshot is a named copy of the catalog's tagged artifacts
+// (migration 035). Namespace is the namespace it captured, empty for every
+// namespace.
+type CatalogSnapshot struct {
+	Name        string
+	Namespace   string
+	Description string
+	CreatedBy   string
+	CreatedAt   time.Time
+	// Entries is the number of artifact tags the snapshot holds.
+	Entries int
+}
+
+// CatalogSnapshotEntry identifies one artifact tag held by a snapshot.
+type CatalogSnapshotEntry struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Tag       string
+}
+
+// CatalogSnapshotStore reads and writes catalog snapshots and the artifact
+// tags they hold.
+type CatalogSnapshotStore struct {
+	pool      *pgxpool.Pool
+	snapshots string
+	entries   string
+}
+
+// NewCatalogSnapshotStore constructs a catalog snapshot store.
+func NewCatalogSnapshotStore(pool *pgxpool.Pool, schema pkgdb.Schema) *CatalogSnapshotStore {
+	return &CatalogSnapshotStore{
+		pool:      pool,
+		snapshots: schema.Qualify("catalog_snapshots"),
+		entries:   schema.Qualify("catalog_snapshot_entries"),
+	}
+}
+
+// Create stores snapshot holding objects, each a tagged artifact whose
+// TypeMeta.Kind is set. A snapshot of the same name returns an error
+// matching pkgdb.ErrAlreadyExists; snapshots are never overwritten.
+func (s *CatalogSnapshotStore) Create(ctx context.Context, snapshot *CatalogSnapshot, objects []*v1alpha1.RawObject) (*CatalogSnapshot, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: catalog snapshot store has nil pool")
+	}
+	out := *snapshot
+	err := runInTx(ctx, s.pool, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO `+s.snapshots+` (name, namespace, description, created_by)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (name) DO NOTHING
+			RETURNING created_at`,
+			snapshot.Name, snapshot.Namespace, snapshot.Description, snapshot.CreatedBy).Scan(&out.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: catalog snapshot %s", pkgdb.ErrAlreadyExists, snapshot.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("create catalog snapshot %s: %w", snapshot.Name, err)
+		}
+		for _, obj := range objects {
+			data, err := json.Marshal(obj)
+			if err != nil {
+				return fmt.Errorf("encode %s %s/%s@%s: %w", obj.Kind, obj.Metadata.NamespaceOrDefault(), obj.Metadata.Name, obj.Metadata.Tag, err)
+			}
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO `+s.entries+` (snapshot, kind, namespace, name, tag, object)
+				VALUES ($1, $2, $3, $4, $5, $6)`,
+				snapshot.Name, obj.Kind, obj.Metadata.NamespaceOrDefault(), obj.Metadata.Name, obj.Metadata.Tag, data); err != nil {
+				return fmt.Errorf("add %s %s/%s@%s to catalog snapshot %s: %w",
+					obj.Kind, obj.Metadata.NamespaceOrDefault(), obj.Metadata.Name, obj.Metadata.Tag, snapshot.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	out.Entries = len(objects)
+	return &out, nil
+}
+
+const catalogSnapshotColumns = `s.name, s.namespace, s.description, s.created_by, s.created_at`
+
+func (s *CatalogSnapshotStore) entryCount() string {
+	return `(SELECT COUNT(*) FROM ` + s.entries + ` e WHERE e.snapshot = s.name)`
+}
+
+// Get returns the snapshot called name, or pkgdb.ErrNotFound.
+func (s *CatalogSnapshotStore) Get(ctx context.Context, name string) (*CatalogSnapshot, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: catalog snapshot store has nil pool")
+	}
+	row := s.pool.QueryRow(ctx, `
+		SELECT `+catalogSnapshotColumns+`, `+s.entryCount()+`
+		FROM `+s.snapshots+` s WHERE s.name = $1`, name)
+	snapshot, err := scanCatalogSnapshot(row)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, pkgdb.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("get catalog snapshot %s: %w", name, err)
+	}
+	return snapshot, nil
+}
+
+// List returns every snapshot, newest first.
+func (s *CatalogSnapshotStore) List(ctx context.Context) ([]*CatalogSnapshot, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: catalog snapshot store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+catalogSnapshotColumns+`, `+s.entryCount()+`
+		FROM `+s.snapshots+` s
+		ORDER BY s.created_at DESC, s.name`)
+	if err != nil {
+		return nil, fmt.Errorf("list catalog snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*CatalogSnapshot
+	for rows.Next() {
+		snapshot, err := scanCatalogSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan catalog snapshot: %w", err)
+		}
+		out = append(out, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read catalog snapshots: %w", err)
+	}
+	return out, nil
+}
+
+// Delete removes the snapshot called name and its entries. Returns
+// pkgdb.ErrNotFound when no such snapshot exists.
+func (s *CatalogSnapshotStore) Delete(ctx context.Context, name string) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: catalog snapshot store has nil pool")
+	}
+	tag, err := s.pool.Exec(ctx, `DELETE FROM `+s.snapshots+` WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("delete catalog snapshot %s: %w", name, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pkgdb.ErrNotFound
+	}
+	return nil
+}
+
+// Entries lists the artifact tags snapshot holds, ordered by kind,
+// namespace, name and tag.
+func (s *CatalogSnapshotStore) Entries(ctx context.Context, snapshot string) ([]CatalogSnapshotEntry, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: catalog snapshot store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT kind, namespace, name, tag FROM `+s.entries+`
+		WHERE snapshot = $1
+		ORDER BY kind, namespace, name, tag`, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("list catalog snapshot %s entries: %w", snapshot, err)
+	}
+	defer rows.Close()
+
+	var out []CatalogSnapshotEntry
+	for rows.Next() {
+		var entry CatalogSnapshotEntry
+		if err := rows.Scan(&entry.Kind, &entry.Namespace, &entry.Name, &entry.Tag); err != nil {
+			return nil, fmt.Errorf("scan catalog snapshot entry: %w", err)
+		}
+		out = append(out, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read catalog snapshot %s entries: %w", snapshot, err)
+	}
+	return out, nil
+}
+
+// Entry returns the artifact tag snapshot holds for kind
+// namespace/name@tag as it was captured, or pkgdb.ErrNotFound when the
+// snapshot doesn't hold it or doesn't exist.
+func (s *CatalogSnapshotStore) Entry(ctx context.Context, snapshot, kind, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: catalog snapshot store has nil pool")
+	}
+	var data []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT object FROM `+s.entries+`
+		WHERE snapshot = $1 AND kind = $2 AND namespace = $3 AND name = $4 AND tag = $5`,
+		snapshot, kind, namespace, name, tag).Scan(&data)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, pkgdb.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("get %s %s/%s@%s from catalog snapshot %s: %w", kind, namespace, name, tag, snapshot, err)
+	}
+	var obj v1alpha1.RawObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("decode %s %s/%s@%s from catalog snapshot %s: %w", kind, namespace, name, tag, snapshot, err)
+	}
+	return &obj, nil
+}
+
+// Tags returns the tags snapshot holds for kind namespace/name, in no
+// particular order.
+func (s *CatalogSnapshotStore) Tags(ctx context.Context, snapshot, kind, namespace, name string) ([]string, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: catalog snapshot store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT tag FROM `+s.entries+`
+		WHERE snapshot = $1 AND kind = $2 AND namespace = $3 AND name = $4`,
+		snapshot, kind, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("list %s %s/%s tags in catalog snapshot %s: %w", kind, namespace, name, snapshot, err)
+	}
+	tags, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("read %s %s/%s tags in catalog snapshot %s: %w", kind, namespace, name, snapshot, err)
+	}
+	return tags, nil
+}
+
+func scanCatalogSnapshot(row rowScanner) (*CatalogSnapshot, error) {
+	var snapshot CatalogSnapshot
+	if err := row.Scan(&snapshot.Name, &snapshot.Namespace, &snapshot.Description,
+		&snapshot.CreatedBy, &snapshot.CreatedAt, &snapshot.Entries); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
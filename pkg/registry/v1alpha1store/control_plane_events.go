@@ -43,15 +43,17 @@ type ControlPlaneEvent struct {
 // ControlPlaneEventStore reads and prunes the durable invalidation cursor used
 // by controllers.
 type ControlPlaneEventStore struct {
-	pool      *pgxpool.Pool
-	qualified string
+	pool       *pgxpool.Pool
+	qualified  string
+	partitions *PartitionManager
 }
 
 // NewControlPlaneEventStore constructs a control-plane event reader.
 func NewControlPlaneEventStore(pool *pgxpool.Pool, schema pkgdb.Schema) *ControlPlaneEventStore {
 	return &ControlPlaneEventStore{
-		pool:      pool,
-		qualified: schema.Qualify("control_plane_events"),
+		pool:       pool,
+		qualified:  schema.Qualify(ControlPlaneEventsTable.Name),
+		partitions: NewPartitionManager(pool, schema),
 	}
 }
 
@@ -117,7 +119,9 @@ func (s *ControlPlaneEventStore) CurrentRevision(ctx context.Context) (int64, er
 	return revision, nil
 }
 
-// PruneBefore deletes retained events in bounded batches. At least one of
+// PruneBefore deletes retained events. Day partitions wholly older than
+// before go first, unless one still holds a revision >= keepAfterRevision;
+// the remaining rows are then deleted in one bounded batch. At least one of
 // before or keepAfterRevision must be set. Controllers must use gap detection
 // before relying on pruning in production.
 func (s *ControlPlaneEventStore) PruneBefore(ctx context.Context, before time.Time, keepAfterRevision int64, limit int) (int64, error) {
@@ -130,9 +134,27 @@ func (s *ControlPlaneEventStore) PruneBefore(ctx context.Context, before time.Ti
 	if limit <= 0 {
 		limit = defaultEventBatchLimit
 	}
-	var beforeArg any
+	var (
+		beforeArg any
+		dropped   int64
+	)
 	if !before.IsZero() {
 		beforeArg = before
+		var keep func(ctx context.Context, tx pgx.Tx, partition string) (bool, error)
+		if keepAfterRevision > 0 {
+			keep = func(ctx context.Context, tx pgx.Tx, partition string) (bool, error) {
+				var newest int64
+				if err := tx.QueryRow(ctx, `SELECT COALESCE(MAX(revision), 0) FROM `+partition).Scan(&newest); err != nil {
+					return false, fmt.Errorf("load newest revision of %s: %w", partition, err)
+				}
+				return newest >= keepAfterRevision, nil
+			}
+		}
+		n, err := s.partitions.DropPartitionsBefore(ctx, ControlPlaneEventsTable, before, keep)
+		if err != nil {
+			return n, fmt.Errorf("prune control-plane event partitions: %w", err)
+		}
+		dropped = n
 	}
 	cmdTag, err := s.pool.Exec(ctx, `
 		WITH doomed AS (
@@ -147,9 +169,9 @@ func (s *ControlPlaneEventStore) PruneBefore(ctx context.Context, before time.Ti
 		USING doomed
 		WHERE e.revision = doomed.revision`, beforeArg, keepAfterRevision, limit)
 	if err != nil {
-		return 0, fmt.Errorf("prune control-plane events: %w", err)
+		return dropped, fmt.Errorf("prune control-plane events: %w", err)
 	}
-	return cmdTag.RowsAffected(), nil
+	return dropped + cmdTag.RowsAffected(), nil
 }
 
 func scanControlPlaneEvent(row pgx.Row) (ControlPlaneEvent, error) {
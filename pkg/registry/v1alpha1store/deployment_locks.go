@@ -0,0 +1,204 @@
+package v1alpha1store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// Operations recorded on a held deployment lock.
+const (
+	DeploymentLockApply  = "apply"
+	DeploymentLockRemove = "remove"
+)
+
+// deploymentLockReleaseTimeout bounds releasing a lock after the caller's
+// context is already done.
+const deploymentLockReleaseTimeout = 5 * time.Second
+
+// DeploymentLockTarget is what a deployment lock covers: one target
+// deployed onto one runtime. The target's tag is left out, so a Deployment
+// moving to a new tag contends with the rollout of the old one.
+type DeploymentLockTarget struct {
+	Target  v1alpha1.ResourceRef
+	Runtime v1alpha1.ResourceRef
+}
+
+// DeploymentLockTargetFor returns the lock target of deployment, with
+// omitted ref namespaces defaulted to the Deployment's own.
+func DeploymentLockTargetFor(deployment *v1alpha1.Deployment) DeploymentLockTarget {
+	ns := deployment.Metadata.NamespaceOrDefault()
+	target, runtime := deployment.Spec.TargetRef, deployment.Spec.RuntimeRef
+	if target.Namespace == "" {
+		target.Namespace = ns
+	}
+	if runtime.Namespace == "" {
+		runtime.Namespace = ns
+	}
+	target.Tag = ""
+	runtime.Tag = ""
+	return DeploymentLockTarget{Target: target, Runtime: runtime}
+}
+
+// Key returns the lock's row key and advisory-lock name.
+func (t DeploymentLockTarget) Key() string {
+	return fmt.Sprintf("deployment:%s/%s/%s/%s@%s/%s",
+		t.Target.Registry, t.Target.Kind, t.Target.Namespace, t.Target.Name, t.Runtime.Namespace, t.Runtime.Name)
+}
+
+func (t DeploymentLockTarget) String() string {
+	return fmt.Sprintf("%s %s/%s on Runtime %s/%s",
+		t.Target.Kind, t.Target.Namespace, t.Target.Name, t.Runtime.Namespace, t.Runtime.Name)
+}
+
+// DeploymentLockHolder describes the reconcile holding a deployment lock.
+type DeploymentLockHolder struct {
+	// Namespace and Name identify the Deployment being reconciled.
+	Namespace  string
+	Name       string
+	Operation  string
+	Holder     string
+	AcquiredAt time.Time
+}
+
+// DeploymentLockedError reports a deployment lock held by another
+// reconcile. It matches pkgdb.ErrConflict so apply handlers answer 409.
+type DeploymentLockedError struct {
+	Target DeploymentLockTarget
+	// Holder is nil when the holder's record isn't visible to the caller,
+	// e.g. under a namespace scope that excludes the holding Deployment.
+	Holder *DeploymentLockHolder
+}
+
+func (e *DeploymentLockedError) Error() string {
+	if e.Holder == nil {
+		return fmt.Sprintf("deployment of %s is in progress", e.Target)
+	}
+	return fmt.Sprintf("deployment of %s is in progress: %s of Deployment %s/%s by %s since %s",
+		e.Target, e.Holder.Operation, e.Holder.Namespace, e.Holder.Name,
+		e.Holder.Holder, e.Holder.AcquiredAt.UTC().Format(time.RFC3339))
+}
+
+// Is makes errors.Is(err, pkgdb.ErrConflict) hold.
+func (e *DeploymentLockedError) Is(target error) bool {
+	return target == pkgdb.ErrConflict
+}
+
+// DeploymentLocks serializes runtime work per deployment lock target
+// across every registry replica sharing the database (migration 016). A
+// lock is a session advisory lock, so it is released when its holder's
+// connection ends even if the holder crashes; the deployment_locks row
+// only names the holder for contended callers.
+type DeploymentLocks struct {
+	pool      *pgxpool.Pool
+	qualified string
+	holder    string
+}
+
+// NewDeploymentLocks constructs a lock manager. holder names this process
+// in DeploymentLockHolder.Holder, e.g. its hostname.
+func NewDeploymentLocks(pool *pgxpool.Pool, schema pkgdb.Schema, holder string) *DeploymentLocks {
+	return &DeploymentLocks{
+		pool:      pool,
+		qualified: schema.Qualify("deployment_locks"),
+		holder:    holder,
+	}
+}
+
+// Acquire takes deployment's lock for operation without waiting. It
+// returns a *DeploymentLockedError when another reconcile holds the lock;
+// otherwise the caller must call release once the runtime work is done.
+// The lock pins one pooled connection until then.
+func (l *DeploymentLocks) Acquire(ctx context.Context, deployment *v1alpha1.Deployment, operation string) (release func(), err error) {
+	if l == nil || l.pool == nil {
+		return nil, errors.New("v1alpha1 store: deployment locks have nil pool")
+	}
+	target := DeploymentLockTargetFor(deployment)
+	key := target.Key()
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire connection for deployment lock: %w", err)
+	}
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, key).Scan(&locked); err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("take deployment lock %s: %w", key, err)
+	}
+	if !locked {
+		defer conn.Release()
+		return nil, lockedError(ctx, conn.Conn(), l.qualified, target)
+	}
+
+	unlock := func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deploymentLockReleaseTimeout)
+		defer cancel()
+		_, delErr := conn.Exec(ctx, `DELETE FROM `+l.qualified+` WHERE lock_key = $1 AND holder = $2`, key, l.holder)
+		_, unlockErr := conn.Exec(ctx, `SELECT pg_advisory_unlock(hashtext($1))`, key)
+		if delErr != nil || unlockErr != nil {
+			// Never hand a connection that may still hold the lock back to
+			// the pool; closing it ends the session and the lock with it.
+			_ = conn.Conn().Close(ctx)
+		}
+		conn.Release()
+	}
+	_, err = conn.Exec(ctx, `
+		INSERT INTO `+l.qualified+` (lock_key, namespace, name, operation, holder, acquired_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (lock_key) DO UPDATE SET
+			namespace = EXCLUDED.namespace,
+			name = EXCLUDED.name,
+			operation = EXCLUDED.operation,
+			holder = EXCLUDED.holder,
+			acquired_at = EXCLUDED.acquired_at`,
+		key, deployment.Metadata.NamespaceOrDefault(), deployment.Metadata.Name, operation, l.holder)
+	if err != nil {
+		unlock()
+		return nil, fmt.Errorf("record deployment lock %s: %w", key, err)
+	}
+	return unlock, nil
+}
+
+// Check returns a *DeploymentLockedError when deployment's lock is held,
+// without keeping it. API writes call it so a deploy or undeploy that would
+// race a running reconcile is refused up front.
+func (l *DeploymentLocks) Check(ctx context.Context, deployment *v1alpha1.Deployment) error {
+	if l == nil || l.pool == nil {
+		return errors.New("v1alpha1 store: deployment locks have nil pool")
+	}
+	target := DeploymentLockTargetFor(deployment)
+	key := target.Key()
+	return runInTx(ctx, l.pool, func(tx pgx.Tx) error {
+		var free bool
+		if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(hashtext($1))`, key).Scan(&free); err != nil {
+			return fmt.Errorf("probe deployment lock %s: %w", key, err)
+		}
+		if free {
+			return nil
+		}
+		return lockedError(ctx, tx.Conn(), l.qualified, target)
+	})
+}
+
+// lockedError reads the holder of target's lock into a
+// *DeploymentLockedError.
+func lockedError(ctx context.Context, conn *pgx.Conn, qualified string, target DeploymentLockTarget) error {
+	var h DeploymentLockHolder
+	err := conn.QueryRow(ctx, `
+		SELECT namespace, name, operation, holder, acquired_at
+		FROM `+qualified+`
+		WHERE lock_key = $1`, target.Key()).Scan(&h.Namespace, &h.Name, &h.Operation, &h.Holder, &h.AcquiredAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return &DeploymentLockedError{Target: target}
+	case err != nil:
+		return fmt.Errorf("read deployment lock holder %s: %w", target.Key(), err)
+	}
+	return &DeploymentLockedError{Target: target, Holder: &h}
+}
@@ -0,0 +1,154 @@
+package v1alpha1store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// DeploymentLogLine is one retained log line of a Deployment (migration
+// 022).
+type DeploymentLogLine struct {
+	Timestamp time.Time
+	Stream    string
+	Line      string
+}
+
+// DeploymentLogStore retains the log lines runtime adapters report, so
+// logs outlive the containers that wrote them. Retention is bounded by
+// PurgeBefore and TrimToBytes.
+type DeploymentLogStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewDeploymentLogStore constructs a deployment log store.
+func NewDeploymentLogStore(pool *pgxpool.Pool, schema pkgdb.Schema) *DeploymentLogStore {
+	return &DeploymentLogStore{
+		pool:      pool,
+		qualified: schema.Qualify("deployment_logs"),
+	}
+}
+
+// Append stores lines for the Deployment.
+func (s *DeploymentLogStore) Append(ctx context.Context, namespace, name string, lines []DeploymentLogLine) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: deployment log store has nil pool")
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	timestamps := make([]time.Time, len(lines))
+	streams := make([]string, len(lines))
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		timestamps[i], streams[i], texts[i] = line.Timestamp, line.Stream, line.Line
+	}
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO `+s.qualified+` (namespace, name, logged_at, stream, line)
+		SELECT $1, $2, l.logged_at, l.stream, l.line
+		FROM unnest($3::timestamptz[], $4::text[], $5::text[]) WITH ORDINALITY AS l(logged_at, stream, line, n)
+		ORDER BY l.n`,
+		namespace, name, timestamps, streams, texts)
+	if err != nil {
+		return fmt.Errorf("append deployment logs %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// Latest returns the timestamp of the Deployment's newest retained line,
+// or the zero time when none is retained.
+func (s *DeploymentLogStore) Latest(ctx context.Context, namespace, name string) (time.Time, error) {
+	if s == nil || s.pool == nil {
+		return time.Time{}, errors.New("v1alpha1 store: deployment log store has nil pool")
+	}
+	var latest *time.Time
+	err := s.pool.QueryRow(ctx, `
+		SELECT MAX(logged_at) FROM `+s.qualified+`
+		WHERE namespace = $1 AND name = $2`, namespace, name).Scan(&latest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("latest deployment log %s/%s: %w", namespace, name, err)
+	}
+	if latest == nil {
+		return time.Time{}, nil
+	}
+	return *latest, nil
+}
+
+// List returns the Deployment's newest limit lines logged at or after
+// since, oldest first. A zero since returns lines of any age; limit <= 0
+// returns every matching line.
+func (s *DeploymentLogStore) List(ctx context.Context, namespace, name string, since time.Time, limit int) ([]DeploymentLogLine, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: deployment log store has nil pool")
+	}
+	query := `
+		SELECT logged_at, stream, line FROM (
+			SELECT id, logged_at, stream, line FROM ` + s.qualified + `
+			WHERE namespace = $1 AND name = $2 AND logged_at >= $3
+			ORDER BY logged_at DESC, id DESC`
+	args := []any{namespace, name, since}
+	if limit > 0 {
+		query += ` LIMIT $4`
+		args = append(args, limit)
+	}
+	query += `
+		) newest ORDER BY logged_at, id`
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list deployment logs %s/%s: %w", namespace, name, err)
+	}
+	defer rows.Close()
+	var out []DeploymentLogLine
+	for rows.Next() {
+		var line DeploymentLogLine
+		if err := rows.Scan(&line.Timestamp, &line.Stream, &line.Line); err != nil {
+			return nil, fmt.Errorf("scan deployment log: %w", err)
+		}
+		out = append(out, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list deployment logs %s/%s: %w", namespace, name, err)
+	}
+	return out, nil
+}
+
+// PurgeBefore deletes every line logged before the cutoff.
+func (s *DeploymentLogStore) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	if s == nil || s.pool == nil {
+		return 0, errors.New("v1alpha1 store: deployment log store has nil pool")
+	}
+	cmdTag, err := s.pool.Exec(ctx, `DELETE FROM `+s.qualified+` WHERE logged_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("purge deployment logs: %w", err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+// TrimToBytes caps each Deployment's retained lines at maxBytes of log
+// text, deleting its oldest lines beyond the cap.
+func (s *DeploymentLogStore) TrimToBytes(ctx context.Context, maxBytes int64) (int64, error) {
+	if s == nil || s.pool == nil {
+		return 0, errors.New("v1alpha1 store: deployment log store has nil pool")
+	}
+	cmdTag, err := s.pool.Exec(ctx, `
+		DELETE FROM `+s.qualified+` WHERE id IN (
+			SELECT id FROM (
+				SELECT id, SUM(octet_length(line)) OVER (
+					PARTITION BY namespace, name
+					ORDER BY logged_at DESC, id DESC
+				) AS retained
+				FROM `+s.qualified+`
+			) sized
+			WHERE retained > $1
+		)`, maxBytes)
+	if err != nil {
+		return 0, fmt.Errorf("trim deployment logs: %w", err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
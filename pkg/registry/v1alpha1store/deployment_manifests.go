@@ -0,0 +1,111 @@
+package v1alpha1store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// DeploymentManifests is the set of runtime artifacts last applied for a
+// Deployment (migration 018), secret values already redacted.
+type DeploymentManifests struct {
+	Namespace  string
+	Name       string
+	Generation int64
+	Runtime    string
+	Manifests  []DeploymentManifest
+	AppliedAt  time.Time
+}
+
+// DeploymentManifest is one rendered artifact.
+type DeploymentManifest struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// DeploymentManifestStore records the manifests each Deployment's last
+// successful apply produced.
+type DeploymentManifestStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewDeploymentManifestStore constructs a deployment manifest store.
+func NewDeploymentManifestStore(pool *pgxpool.Pool, schema pkgdb.Schema) *DeploymentManifestStore {
+	return &DeploymentManifestStore{
+		pool:      pool,
+		qualified: schema.Qualify("deployment_manifests"),
+	}
+}
+
+// Record replaces the manifests recorded for the Deployment.
+func (s *DeploymentManifestStore) Record(ctx context.Context, m DeploymentManifests) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: deployment manifest store has nil pool")
+	}
+	manifests := m.Manifests
+	if manifests == nil {
+		manifests = []DeploymentManifest{}
+	}
+	data, err := json.Marshal(manifests)
+	if err != nil {
+		return fmt.Errorf("encode deployment manifests: %w", err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO `+s.qualified+` (namespace, name, generation, runtime, manifests, applied_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (namespace, name) DO UPDATE SET
+			generation = EXCLUDED.generation,
+			runtime = EXCLUDED.runtime,
+			manifests = EXCLUDED.manifests,
+			applied_at = EXCLUDED.applied_at`,
+		m.Namespace, m.Name, m.Generation, m.Runtime, data)
+	if err != nil {
+		return fmt.Errorf("record deployment manifests %s/%s: %w", m.Namespace, m.Name, err)
+	}
+	return nil
+}
+
+// Get returns the manifests recorded for the Deployment, or
+// pkgdb.ErrNotFound when none have been.
+func (s *DeploymentManifestStore) Get(ctx context.Context, namespace, name string) (*DeploymentManifests, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: deployment manifest store has nil pool")
+	}
+	out := &DeploymentManifests{Namespace: namespace, Name: name}
+	var data []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT generation, runtime, manifests, applied_at
+		FROM `+s.qualified+`
+		WHERE namespace = $1 AND name = $2`, namespace, name).
+		Scan(&out.Generation, &out.Runtime, &data, &out.AppliedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, pkgdb.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get deployment manifests %s/%s: %w", namespace, name, err)
+	}
+	if err := json.Unmarshal(data, &out.Manifests); err != nil {
+		return nil, fmt.Errorf("decode deployment manifests %s/%s: %w", namespace, name, err)
+	}
+	return out, nil
+}
+
+// Delete drops the manifests recorded for the Deployment. Deleting a
+// Deployment with no record is not an error.
+func (s *DeploymentManifestStore) Delete(ctx context.Context, namespace, name string) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: deployment manifest store has nil pool")
+	}
+	if _, err := s.pool.Exec(ctx, `DELETE FROM `+s.qualified+` WHERE namespace = $1 AND name = $2`, namespace, name); err != nil {
+		return fmt.Errorf("delete deployment manifests %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package v1alpha1store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// DeploymentNote is one revision of a Deployment's notes (migration 028):
+// the free-form note and links as they stood after one edit.
+type DeploymentNote struct {
+	ID        int64
+	Namespace string
+	Name      string
+	Note      string
+	Links     []DeploymentLink
+	Author    string
+	CreatedAt time.Time
+}
+
+// DeploymentLink is a titled URL attached to a Deployment, such as its
+// runbook or dashboard.
+type DeploymentLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// DeploymentNoteStore keeps the append-only revision history of each
+// Deployment's notes.
+type DeploymentNoteStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewDeploymentNoteStore constructs a deployment note store.
+func NewDeploymentNoteStore(pool *pgxpool.Pool, schema pkgdb.Schema) *DeploymentNoteStore {
+	return &DeploymentNoteStore{
+		pool:      pool,
+		qualified: schema.Qualify("deployment_notes"),
+	}
+}
+
+// Append saves n as the Deployment's newest revision and returns it with
+// its ID and CreatedAt set.
+func (s *DeploymentNoteStore) Append(ctx context.Context, n DeploymentNote) (*DeploymentNote, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: deployment note store has nil pool")
+	}
+	links := n.Links
+	if links == nil {
+		links = []DeploymentLink{}
+	}
+	data, err := json.Marshal(links)
+	if err != nil {
+		return nil, fmt.Errorf("encode deployment links: %w", err)
+	}
+	out := n
+	out.Links = links
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO `+s.qualified+` (namespace, name, note, links, author)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		n.Namespace, n.Name, n.Note, data, n.Author).Scan(&out.ID, &out.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("append deployment notes %s/%s: %w", n.Namespace, n.Name, err)
+	}
+	return &out, nil
+}
+
+// History returns the Deployment's revisions, newest first, so the first
+// entry is the current notes. A Deployment never annotated has none.
+func (s *DeploymentNoteStore) History(ctx context.Context, namespace, name string) ([]DeploymentNote, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: deployment note store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, note, links, author, created_at
+		FROM `+s.qualified+`
+		WHERE namespace = $1 AND name = $2
+		ORDER BY id DESC`, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("list deployment notes %s/%s: %w", namespace, name, err)
+	}
+	defer rows.Close()
+	var out []DeploymentNote
+	for rows.Next() {
+		n := DeploymentNote{Namespace: namespace, Name: name}
+		var links []byte
+		if err := rows.Scan(&n.ID, &n.Note, &links, &n.Author, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan deployment note: %w", err)
+		}
+		if err := json.Unmarshal(links, &n.Links); err != nil {
+			return nil, fmt.Errorf("decode deployment links %s/%s: %w", namespace, name, err)
+		}
+		out = append(out, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list deployment notes %s/%s: %w", namespace, name, err)
+	}
+	return out, nil
+}
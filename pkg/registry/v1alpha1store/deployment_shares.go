@@ -0,0 +1,171 @@
+package v1alpha1store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// DeploymentShare is one share link of a Deployment (migration 029). Only
+// the SHA-256 of its secret is stored.
+type DeploymentShare struct {
+	ID         string
+	Namespace  string
+	Name       string
+	SecretHash string
+	CreatedBy  string
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	RevokedBy  string
+	UseCount   int64
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// Active reports whether the share grants access at now: not revoked and
+// not expired.
+func (s *DeploymentShare) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// DeploymentShareStore reads and writes Deployment share links.
+type DeploymentShareStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewDeploymentShareStore constructs a deployment share store.
+func NewDeploymentShareStore(pool *pgxpool.Pool, schema pkgdb.Schema) *DeploymentShareStore {
+	return &DeploymentShareStore{pool: pool, qualified: schema.Qualify("deployment_shares")}
+}
+
+const deploymentShareColumns = `id, namespace, name, secret_hash, created_by, expires_at, revoked_at, revoked_by, use_count, last_used_at, created_at`
+
+// Create inserts share. Its ID and SecretHash are chosen by the caller; an
+// ID collision returns an error matching pkgdb.ErrAlreadyExists.
+func (s *DeploymentShareStore) Create(ctx context.Context, share *DeploymentShare) (*DeploymentShare, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: deployment share store has nil pool")
+	}
+	row := s.pool.QueryRow(ctx, `
+		INSERT INTO `+s.qualified+` (id, namespace, name, secret_hash, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO NOTHING
+		RETURNING `+deploymentShareColumns,
+		share.ID, share.Namespace, share.Name, share.SecretHash, share.CreatedBy, share.ExpiresAt)
+	out, err := scanDeploymentShare(row)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("%w: deployment share %s", pkgdb.ErrAlreadyExists, share.ID)
+	case err != nil:
+		return nil, fmt.Errorf("create deployment share: %w", err)
+	}
+	return out, nil
+}
+
+// Get returns the share with id, active or not, or pkgdb.ErrNotFound.
+func (s *DeploymentShareStore) Get(ctx context.Context, id string) (*DeploymentShare, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: deployment share store has nil pool")
+	}
+	row := s.pool.QueryRow(ctx, `SELECT `+deploymentShareColumns+` FROM `+s.qualified+` WHERE id = $1`, id)
+	share, err := scanDeploymentShare(row)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, pkgdb.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("get deployment share %s: %w", id, err)
+	}
+	return share, nil
+}
+
+// List returns the Deployment's shares, newest first.
+func (s *DeploymentShareStore) List(ctx context.Context, namespace, name string) ([]*DeploymentShare, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: deployment share store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+deploymentShareColumns+` FROM `+s.qualified+`
+		WHERE namespace = $1 AND name = $2
+		ORDER BY created_at DESC, id`, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("list deployment shares %s/%s: %w", namespace, name, err)
+	}
+	defer rows.Close()
+
+	var out []*DeploymentShare
+	for rows.Next() {
+		share, err := scanDeploymentShare(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan deployment share: %w", err)
+		}
+		out = append(out, share)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read deployment shares: %w", err)
+	}
+	return out, nil
+}
+
+// Revoke stops share id from granting access and records by as the
+// caller that revoked it. Revoking an already revoked share keeps its
+// original revocation. Returns pkgdb.ErrNotFound when no such share
+// exists.
+func (s *DeploymentShareStore) Revoke(ctx context.Context, id, by string) (*DeploymentShare, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: deployment share store has nil pool")
+	}
+	row := s.pool.QueryRow(ctx, `
+		UPDATE `+s.qualified+`
+		SET revoked_by = CASE WHEN revoked_at IS NULL THEN $2 ELSE revoked_by END,
+		    revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1
+		RETURNING `+deploymentShareColumns, id, by)
+	share, err := scanDeploymentShare(row)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, pkgdb.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("revoke deployment share %s: %w", id, err)
+	}
+	return share, nil
+}
+
+// Touch records that share id authenticated a request at at.
+func (s *DeploymentShareStore) Touch(ctx context.Context, id string, at time.Time) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: deployment share store has nil pool")
+	}
+	if _, err := s.pool.Exec(ctx, `
+		UPDATE `+s.qualified+` SET use_count = use_count + 1, last_used_at = $2
+		WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("touch deployment share %s: %w", id, err)
+	}
+	return nil
+}
+
+func scanDeploymentShare(row pgx.Row) (*DeploymentShare, error) {
+	var share DeploymentShare
+	if err := row.Scan(
+		&share.ID,
+		&share.Namespace,
+		&share.Name,
+		&share.SecretHash,
+		&share.CreatedBy,
+		&share.ExpiresAt,
+		&share.RevokedAt,
+		&share.RevokedBy,
+		&share.UseCount,
+		&share.LastUsedAt,
+		&share.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
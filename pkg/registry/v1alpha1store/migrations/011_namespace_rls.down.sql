@@ -0,0 +1,17 @@
+-- Reverses 011_namespace_rls.up.sql: drops the namespace_scope policies,
+-- turns row-level security back off, and removes the predicate function.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'agents', 'mcp_servers', 'skills', 'prompts', 'runtimes',
+        'deployments', 'plugins', 'control_plane_events'
+    ] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS namespace_scope ON %I', t);
+        EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', t);
+    END LOOP;
+END $$;
+
+DROP FUNCTION IF EXISTS namespace_in_scope(TEXT);
@@ -0,0 +1,37 @@
+-- Namespace row-level security: defense in depth for multi-tenant installs.
+--
+-- Every namespaced table gets a policy that admits only rows whose namespace
+-- is listed in the `registry.namespace_scope` setting (comma-separated).
+-- The registry sets it per pool checkout when
+-- AGENT_REGISTRY_DATABASE_RLS_ENABLED is on and the caller's principal is
+-- confined to a set of namespaces. An unset or empty setting admits every row,
+-- so installs that leave the flag off, system/controller sessions, and
+-- unconfined principals behave exactly as before this migration.
+--
+-- FORCE applies the policies to the table owner as well; the registry
+-- normally connects as the owner. Superusers and BYPASSRLS roles still skip
+-- them, so multi-tenant installs must connect as an ordinary role.
+
+CREATE OR REPLACE FUNCTION namespace_in_scope(ns TEXT)
+RETURNS BOOLEAN AS $$
+    SELECT COALESCE(current_setting('registry.namespace_scope', true), '') = ''
+        OR ns = ANY (string_to_array(current_setting('registry.namespace_scope', true), ','));
+$$ LANGUAGE sql STABLE;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'agents', 'mcp_servers', 'skills', 'prompts', 'runtimes',
+        'deployments', 'plugins', 'control_plane_events'
+    ] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS namespace_scope ON %I', t);
+        EXECUTE format(
+            'CREATE POLICY namespace_scope ON %I '
+            'USING (namespace_in_scope(namespace)) '
+            'WITH CHECK (namespace_in_scope(namespace))', t);
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+    END LOOP;
+END $$;
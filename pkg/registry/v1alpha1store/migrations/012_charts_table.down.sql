@@ -0,0 +1,10 @@
+-- Reverses 012_charts_table.up.sql. Dropping the table removes its indexes,
+-- triggers and namespace_scope policy; the shared functions (set_updated_at,
+-- notify_status_change, record_control_plane_event, namespace_in_scope) are
+-- owned by earlier migrations and left in place.
+DROP INDEX IF EXISTS charts_terminating;
+DROP INDEX IF EXISTS charts_tags_alive;
+DROP INDEX IF EXISTS charts_list_alive;
+DROP INDEX IF EXISTS charts_labels_gin;
+
+DROP TABLE IF EXISTS charts;
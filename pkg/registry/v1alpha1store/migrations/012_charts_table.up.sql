@@ -0,0 +1,58 @@
+-- Charts: Helm chart references (repository, chart, version, values schema)
+-- for infrastructure add-ons agents depend on. A content-registry kind keyed
+-- by (namespace, name, tag), immutable by tag, with the same updated-at,
+-- status-notify, and control-plane-event triggers (009) as the other content
+-- tables, plus the namespace_scope row-level security policy from 011.
+
+CREATE TABLE IF NOT EXISTS charts (
+    namespace character varying(255) NOT NULL,
+    name character varying(255) NOT NULL,
+    tag character varying(255) NOT NULL,
+    uid uuid DEFAULT gen_random_uuid() NOT NULL,
+    generation bigint DEFAULT 1 NOT NULL,
+    labels jsonb DEFAULT '{}'::jsonb NOT NULL,
+    annotations jsonb DEFAULT '{}'::jsonb NOT NULL,
+    spec jsonb NOT NULL,
+    content_hash character(64) NOT NULL,
+    status jsonb DEFAULT '{}'::jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    deletion_timestamp timestamp with time zone,
+    PRIMARY KEY (namespace, name, tag)
+);
+
+-- list by labels
+CREATE INDEX IF NOT EXISTS charts_labels_gin
+    ON charts USING gin (labels);
+
+-- list live chart rows
+CREATE INDEX IF NOT EXISTS charts_list_alive
+    ON charts USING btree (namespace, name, tag, updated_at)
+    WHERE deletion_timestamp IS NULL;
+
+-- list tags for one chart
+CREATE INDEX IF NOT EXISTS charts_tags_alive
+    ON charts USING btree (namespace, name, updated_at DESC, tag DESC)
+    WHERE deletion_timestamp IS NULL;
+
+-- purge terminating rows
+CREATE INDEX IF NOT EXISTS charts_terminating
+    ON charts USING btree (deletion_timestamp)
+    WHERE deletion_timestamp IS NOT NULL;
+
+CREATE OR REPLACE TRIGGER charts_set_updated_at
+    BEFORE UPDATE ON charts
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE OR REPLACE TRIGGER charts_notify_status
+    AFTER INSERT OR UPDATE OR DELETE ON charts
+    FOR EACH ROW EXECUTE FUNCTION notify_status_change('charts_status');
+CREATE OR REPLACE TRIGGER charts_control_plane_event
+    AFTER INSERT OR UPDATE OR DELETE ON charts
+    FOR EACH ROW EXECUTE FUNCTION record_control_plane_event('Chart');
+
+DROP POLICY IF EXISTS namespace_scope ON charts;
+CREATE POLICY namespace_scope ON charts
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE charts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE charts FORCE ROW LEVEL SECURITY;
@@ -0,0 +1,11 @@
+-- Reverses 013_webhooks.up.sql. Dropping the tables removes their indexes,
+-- triggers and namespace_scope policies; the shared functions
+-- (set_updated_at, namespace_in_scope) are owned by earlier migrations and
+-- left in place.
+DROP INDEX IF EXISTS webhook_deliveries_delivered_at;
+DROP INDEX IF EXISTS webhook_deliveries_webhook;
+DROP TABLE IF EXISTS webhook_deliveries;
+
+DROP INDEX IF EXISTS webhooks_terminating;
+DROP INDEX IF EXISTS webhooks_labels_gin;
+DROP TABLE IF EXISTS webhooks;
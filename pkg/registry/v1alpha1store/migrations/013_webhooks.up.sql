@@ -0,0 +1,75 @@
+-- Webhooks: per-namespace subscriptions to registry lifecycle events
+-- (server/agent/skill published, deployment created/failed). A mutable kind
+-- keyed by (namespace, name) like runtimes and deployments, with the
+-- updated-at trigger from 001 and the namespace_scope row-level security
+-- policy from 011. Webhook writes never affect Deployment desired state, so
+-- the table has no control-plane-event trigger.
+--
+-- `webhook_deliveries` is the delivery log: one row per attempt, pruned by
+-- the dispatcher after AGENT_REGISTRY_WEBHOOK_DELIVERY_RETENTION.
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    namespace character varying(255) NOT NULL,
+    name character varying(255) NOT NULL,
+    uid uuid DEFAULT gen_random_uuid() NOT NULL,
+    generation bigint DEFAULT 1 NOT NULL,
+    labels jsonb DEFAULT '{}'::jsonb NOT NULL,
+    annotations jsonb DEFAULT '{}'::jsonb NOT NULL,
+    spec jsonb NOT NULL,
+    status jsonb DEFAULT '{}'::jsonb NOT NULL,
+    deletion_timestamp timestamp with time zone,
+    finalizers jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (namespace, name)
+);
+
+-- list by labels
+CREATE INDEX IF NOT EXISTS webhooks_labels_gin
+    ON webhooks USING gin (labels);
+
+-- purge terminating rows
+CREATE INDEX IF NOT EXISTS webhooks_terminating
+    ON webhooks USING btree (deletion_timestamp)
+    WHERE deletion_timestamp IS NOT NULL;
+
+CREATE OR REPLACE TRIGGER webhooks_set_updated_at
+    BEFORE UPDATE ON webhooks
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id           BIGSERIAL    PRIMARY KEY,
+    delivery_id  TEXT         NOT NULL,
+    namespace    VARCHAR(255) NOT NULL,
+    webhook      VARCHAR(255) NOT NULL,
+    event        TEXT         NOT NULL,
+    url          TEXT         NOT NULL,
+    attempt      INTEGER      NOT NULL,
+    status_code  INTEGER      NOT NULL DEFAULT 0,
+    error        TEXT         NOT NULL DEFAULT '',
+    payload      JSONB        NOT NULL,
+    delivered_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- delivery log for one webhook, newest first
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook
+    ON webhook_deliveries (namespace, webhook, id DESC);
+
+-- retention pruning
+CREATE INDEX IF NOT EXISTS webhook_deliveries_delivered_at
+    ON webhook_deliveries (delivered_at);
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['webhooks', 'webhook_deliveries'] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS namespace_scope ON %I', t);
+        EXECUTE format(
+            'CREATE POLICY namespace_scope ON %I '
+            'USING (namespace_in_scope(namespace)) '
+            'WITH CHECK (namespace_in_scope(namespace))', t);
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+    END LOOP;
+END $$;
@@ -0,0 +1,9 @@
+-- Reverses 014_feature_flags_table.up.sql. Dropping the table removes its
+-- indexes, triggers and namespace_scope policy; the shared functions
+-- (set_updated_at, notify_status_change, namespace_in_scope) are owned by
+-- earlier migrations and left in place.
+DROP INDEX IF EXISTS feature_flags_updated_at_desc;
+DROP INDEX IF EXISTS feature_flags_terminating;
+DROP INDEX IF EXISTS feature_flags_labels_gin;
+
+DROP TABLE IF EXISTS feature_flags;
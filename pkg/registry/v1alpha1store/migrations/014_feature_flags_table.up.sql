@@ -0,0 +1,48 @@
+-- Feature flags: boolean switches deployed agents evaluate at runtime. A
+-- mutable kind keyed by (namespace, name) with the same column layout as
+-- runtimes and deployments, the updated-at and status-notify triggers, and
+-- the namespace_scope row-level security policy from 011. Flags carry no
+-- control-plane-event trigger: toggling one must not wake the Deployment
+-- controller, since agents pick the change up without a redeploy.
+
+CREATE TABLE IF NOT EXISTS feature_flags (
+    namespace character varying(255) NOT NULL,
+    name character varying(255) NOT NULL,
+    uid uuid DEFAULT gen_random_uuid() NOT NULL,
+    generation bigint DEFAULT 1 NOT NULL,
+    labels jsonb DEFAULT '{}'::jsonb NOT NULL,
+    annotations jsonb DEFAULT '{}'::jsonb NOT NULL,
+    spec jsonb NOT NULL,
+    status jsonb DEFAULT '{}'::jsonb NOT NULL,
+    deletion_timestamp timestamp with time zone,
+    finalizers jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (namespace, name)
+);
+
+-- list by labels
+CREATE INDEX IF NOT EXISTS feature_flags_labels_gin
+    ON feature_flags USING gin (labels);
+
+-- purge terminating rows
+CREATE INDEX IF NOT EXISTS feature_flags_terminating
+    ON feature_flags USING btree (deletion_timestamp)
+    WHERE deletion_timestamp IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS feature_flags_updated_at_desc
+    ON feature_flags USING btree (updated_at DESC);
+
+CREATE OR REPLACE TRIGGER feature_flags_set_updated_at
+    BEFORE UPDATE ON feature_flags
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE OR REPLACE TRIGGER feature_flags_notify_status
+    AFTER INSERT OR UPDATE OR DELETE ON feature_flags
+    FOR EACH ROW EXECUTE FUNCTION notify_status_change('feature_flags_status');
+
+DROP POLICY IF EXISTS namespace_scope ON feature_flags;
+CREATE POLICY namespace_scope ON feature_flags
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE feature_flags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE feature_flags FORCE ROW LEVEL SECURITY;
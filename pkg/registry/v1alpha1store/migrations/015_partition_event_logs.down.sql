@@ -0,0 +1,85 @@
+-- Reverses 015_partition_event_logs.up.sql: folds every partition back into
+-- plain tables with the 009 and 013 shapes. Dropping the partitioned tables
+-- removes their day partitions, the DEFAULT partitions, their indexes and
+-- namespace_scope policies; the sequences are reattached first so they
+-- survive. The policies are recreated on the plain tables, so 011's and
+-- 013's down migrations still find them.
+
+ALTER TABLE control_plane_events RENAME TO control_plane_events_partitioned;
+ALTER TABLE control_plane_events_partitioned
+    RENAME CONSTRAINT control_plane_events_pkey TO control_plane_events_partitioned_pkey;
+DROP INDEX IF EXISTS control_plane_events_committed_at;
+DROP INDEX IF EXISTS control_plane_events_identity;
+
+CREATE TABLE IF NOT EXISTS control_plane_events (
+    revision     BIGINT       PRIMARY KEY DEFAULT nextval('control_plane_events_revision_seq'),
+    kind         TEXT         NOT NULL,
+    namespace    VARCHAR(255) NOT NULL,
+    name         VARCHAR(255) NOT NULL,
+    tag          VARCHAR(255) NOT NULL DEFAULT '',
+    uid          UUID         NOT NULL,
+    generation   BIGINT       NOT NULL,
+    op           TEXT         NOT NULL CHECK (op IN ('insert', 'update', 'delete')),
+    committed_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+ALTER SEQUENCE control_plane_events_revision_seq OWNED BY control_plane_events.revision;
+
+INSERT INTO control_plane_events (revision, kind, namespace, name, tag, uid, generation, op, committed_at)
+SELECT revision, kind, namespace, name, tag, uid, generation, op, committed_at
+FROM control_plane_events_partitioned;
+
+DROP TABLE IF EXISTS control_plane_events_partitioned;
+
+CREATE INDEX IF NOT EXISTS control_plane_events_committed_at
+    ON control_plane_events (committed_at, revision);
+CREATE INDEX IF NOT EXISTS control_plane_events_identity
+    ON control_plane_events (kind, namespace, name, tag, generation);
+
+ALTER TABLE webhook_deliveries RENAME TO webhook_deliveries_partitioned;
+ALTER TABLE webhook_deliveries_partitioned
+    RENAME CONSTRAINT webhook_deliveries_pkey TO webhook_deliveries_partitioned_pkey;
+DROP INDEX IF EXISTS webhook_deliveries_webhook;
+DROP INDEX IF EXISTS webhook_deliveries_delivered_at;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id           BIGINT       PRIMARY KEY DEFAULT nextval('webhook_deliveries_id_seq'),
+    delivery_id  TEXT         NOT NULL,
+    namespace    VARCHAR(255) NOT NULL,
+    webhook      VARCHAR(255) NOT NULL,
+    event        TEXT         NOT NULL,
+    url          TEXT         NOT NULL,
+    attempt      INTEGER      NOT NULL,
+    status_code  INTEGER      NOT NULL DEFAULT 0,
+    error        TEXT         NOT NULL DEFAULT '',
+    payload      JSONB        NOT NULL,
+    delivered_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+ALTER SEQUENCE webhook_deliveries_id_seq OWNED BY webhook_deliveries.id;
+
+INSERT INTO webhook_deliveries (id, delivery_id, namespace, webhook, event, url, attempt, status_code, error, payload, delivered_at)
+SELECT id, delivery_id, namespace, webhook, event, url, attempt, status_code, error, payload, delivered_at
+FROM webhook_deliveries_partitioned;
+
+DROP TABLE IF EXISTS webhook_deliveries_partitioned;
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook
+    ON webhook_deliveries (namespace, webhook, id DESC);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_delivered_at
+    ON webhook_deliveries (delivered_at);
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['control_plane_events', 'webhook_deliveries'] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS namespace_scope ON %I', t);
+        EXECUTE format(
+            'CREATE POLICY namespace_scope ON %I '
+            'USING (namespace_in_scope(namespace)) '
+            'WITH CHECK (namespace_in_scope(namespace))', t);
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+    END LOOP;
+END $$;
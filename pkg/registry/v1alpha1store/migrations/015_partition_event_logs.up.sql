@@ -0,0 +1,110 @@
+-- Daily range partitioning for the append-only event logs.
+--
+-- `control_plane_events` and `webhook_deliveries` only grow at the tail and
+-- shrink at the head, so DELETE-based retention leaves vacuum chasing dead
+-- tuples on the hottest tables in the schema. Both are now partitioned by
+-- day on their timestamp column, and retention drops whole day partitions.
+--
+-- The registry creates the day partitions itself
+-- (v1alpha1store.PartitionManager) after startup migrations and from the
+-- deployment controller's hourly maintenance pass. The DEFAULT partition
+-- catches any row whose day has no partition yet, including every row
+-- copied over here; creating a day's partition moves that day's rows out of
+-- it.
+--
+-- A partitioned table's primary key must include the partition key, so the
+-- keys widen to (revision, committed_at) and (id, delivered_at). The
+-- existing sequences are reattached, so revisions and delivery ids keep
+-- counting from where they were and controller checkpoints stay valid.
+
+ALTER TABLE control_plane_events RENAME TO control_plane_events_unpartitioned;
+ALTER TABLE control_plane_events_unpartitioned
+    RENAME CONSTRAINT control_plane_events_pkey TO control_plane_events_unpartitioned_pkey;
+DROP INDEX IF EXISTS control_plane_events_committed_at;
+DROP INDEX IF EXISTS control_plane_events_identity;
+
+CREATE TABLE IF NOT EXISTS control_plane_events (
+    revision     BIGINT       NOT NULL DEFAULT nextval('control_plane_events_revision_seq'),
+    kind         TEXT         NOT NULL,
+    namespace    VARCHAR(255) NOT NULL,
+    name         VARCHAR(255) NOT NULL,
+    tag          VARCHAR(255) NOT NULL DEFAULT '',
+    uid          UUID         NOT NULL,
+    generation   BIGINT       NOT NULL,
+    op           TEXT         NOT NULL CHECK (op IN ('insert', 'update', 'delete')),
+    committed_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (revision, committed_at)
+) PARTITION BY RANGE (committed_at);
+
+ALTER SEQUENCE control_plane_events_revision_seq OWNED BY control_plane_events.revision;
+
+CREATE TABLE IF NOT EXISTS control_plane_events_default
+    PARTITION OF control_plane_events DEFAULT;
+
+INSERT INTO control_plane_events (revision, kind, namespace, name, tag, uid, generation, op, committed_at)
+SELECT revision, kind, namespace, name, tag, uid, generation, op, committed_at
+FROM control_plane_events_unpartitioned;
+
+DROP TABLE IF EXISTS control_plane_events_unpartitioned;
+
+CREATE INDEX IF NOT EXISTS control_plane_events_committed_at
+    ON control_plane_events (committed_at, revision);
+CREATE INDEX IF NOT EXISTS control_plane_events_identity
+    ON control_plane_events (kind, namespace, name, tag, generation);
+
+ALTER TABLE webhook_deliveries RENAME TO webhook_deliveries_unpartitioned;
+ALTER TABLE webhook_deliveries_unpartitioned
+    RENAME CONSTRAINT webhook_deliveries_pkey TO webhook_deliveries_unpartitioned_pkey;
+DROP INDEX IF EXISTS webhook_deliveries_webhook;
+DROP INDEX IF EXISTS webhook_deliveries_delivered_at;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id           BIGINT       NOT NULL DEFAULT nextval('webhook_deliveries_id_seq'),
+    delivery_id  TEXT         NOT NULL,
+    namespace    VARCHAR(255) NOT NULL,
+    webhook      VARCHAR(255) NOT NULL,
+    event        TEXT         NOT NULL,
+    url          TEXT         NOT NULL,
+    attempt      INTEGER      NOT NULL,
+    status_code  INTEGER      NOT NULL DEFAULT 0,
+    error        TEXT         NOT NULL DEFAULT '',
+    payload      JSONB        NOT NULL,
+    delivered_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, delivered_at)
+) PARTITION BY RANGE (delivered_at);
+
+ALTER SEQUENCE webhook_deliveries_id_seq OWNED BY webhook_deliveries.id;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries_default
+    PARTITION OF webhook_deliveries DEFAULT;
+
+INSERT INTO webhook_deliveries (id, delivery_id, namespace, webhook, event, url, attempt, status_code, error, payload, delivered_at)
+SELECT id, delivery_id, namespace, webhook, event, url, attempt, status_code, error, payload, delivered_at
+FROM webhook_deliveries_unpartitioned;
+
+DROP TABLE IF EXISTS webhook_deliveries_unpartitioned;
+
+-- delivery log for one webhook, newest first
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook
+    ON webhook_deliveries (namespace, webhook, id DESC);
+
+-- retention pruning of the default partition
+CREATE INDEX IF NOT EXISTS webhook_deliveries_delivered_at
+    ON webhook_deliveries (delivered_at);
+
+-- The namespace_scope policies from 011 and 013 went with the old tables.
+-- Policies on a partitioned table apply to every query through it.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['control_plane_events', 'webhook_deliveries'] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS namespace_scope ON %I', t);
+        EXECUTE format(
+            'CREATE POLICY namespace_scope ON %I '
+            'USING (namespace_in_scope(namespace)) '
+            'WITH CHECK (namespace_in_scope(namespace))', t);
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+    END LOOP;
+END $$;
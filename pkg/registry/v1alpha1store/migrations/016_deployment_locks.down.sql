@@ -0,0 +1,3 @@
+-- Reverses 016_deployment_locks.up.sql. Dropping the table removes its
+-- namespace_scope policy.
+DROP TABLE IF EXISTS deployment_locks;
@@ -0,0 +1,26 @@
+-- Per-resource deployment locks.
+--
+-- The deployment controller takes a session advisory lock on a
+-- Deployment's target + runtime for the length of each apply or remove, so
+-- two Deployments of the same agent onto one runtime, or two registry
+-- replicas reconciling the same Deployment, never drive the runtime adapter
+-- at once. The advisory lock is the lock; `deployment_locks` only records
+-- who holds it, so a contended deploy can answer 409 naming the holder. A
+-- row left behind by a crashed replica is stale as soon as its session
+-- ends and is overwritten by the next holder.
+
+CREATE TABLE IF NOT EXISTS deployment_locks (
+    lock_key    TEXT         PRIMARY KEY,
+    namespace   VARCHAR(255) NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    operation   TEXT         NOT NULL,
+    holder      TEXT         NOT NULL,
+    acquired_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+DROP POLICY IF EXISTS namespace_scope ON deployment_locks;
+CREATE POLICY namespace_scope ON deployment_locks
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE deployment_locks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE deployment_locks FORCE ROW LEVEL SECURITY;
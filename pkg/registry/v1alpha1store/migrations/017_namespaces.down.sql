@@ -0,0 +1,3 @@
+-- Reverses 017_namespaces.up.sql. Dropping the table removes its trigger
+-- and namespace_scope policy; set_updated_at is owned by 001.
+DROP TABLE IF EXISTS namespaces;
@@ -0,0 +1,32 @@
+-- Namespace ownership.
+--
+-- A row claims one namespace (e.g. `io.github.acme`) for an owner. Until
+-- the claim is verified, by a DNS TXT record on the namespace's reversed
+-- domain or by a registry admin, it restricts nothing. Once verified, only
+-- the owner and listed members may publish or delete Agents, MCPServers
+-- and Skills in the namespace. Unclaimed namespaces stay open.
+--
+-- `members` is a JSON array of principal subjects. `verification_token`
+-- is the TXT record value the owner publishes; it is kept after
+-- verification so the record can be re-checked.
+
+CREATE TABLE IF NOT EXISTS namespaces (
+    name               VARCHAR(255) PRIMARY KEY,
+    owner              TEXT         NOT NULL,
+    members            JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    verification_token TEXT         NOT NULL,
+    verified_at        TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE TRIGGER namespaces_set_updated_at
+    BEFORE UPDATE ON namespaces
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+DROP POLICY IF EXISTS namespace_scope ON namespaces;
+CREATE POLICY namespace_scope ON namespaces
+    USING (namespace_in_scope(name))
+    WITH CHECK (namespace_in_scope(name));
+ALTER TABLE namespaces ENABLE ROW LEVEL SECURITY;
+ALTER TABLE namespaces FORCE ROW LEVEL SECURITY;
@@ -0,0 +1,3 @@
+-- Reverses 018_deployment_manifests.up.sql. Dropping the table removes its
+-- namespace_scope policy.
+DROP TABLE IF EXISTS deployment_manifests;
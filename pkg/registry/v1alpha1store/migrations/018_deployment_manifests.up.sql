@@ -0,0 +1,28 @@
+-- Rendered deployment manifests.
+--
+-- After each successful apply the deployment controller records the runtime
+-- artifacts the adapter submitted (compose services and gateway config for
+-- Local, kagent/kmcp resources and Helm releases for Kubernetes) so
+-- operators can read what is running without access to the runtime host.
+-- Secret values are redacted before the row is written. One row per
+-- Deployment, overwritten on every apply and deleted with the Deployment.
+--
+-- `manifests` is a JSON array of {name, content} objects, content being
+-- YAML.
+
+CREATE TABLE IF NOT EXISTS deployment_manifests (
+    namespace  VARCHAR(255) NOT NULL,
+    name       VARCHAR(255) NOT NULL,
+    generation BIGINT       NOT NULL,
+    runtime    TEXT         NOT NULL,
+    manifests  JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    applied_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (namespace, name)
+);
+
+DROP POLICY IF EXISTS namespace_scope ON deployment_manifests;
+CREATE POLICY namespace_scope ON deployment_manifests
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE deployment_manifests ENABLE ROW LEVEL SECURITY;
+ALTER TABLE deployment_manifests FORCE ROW LEVEL SECURITY;
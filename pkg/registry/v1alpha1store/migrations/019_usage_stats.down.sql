@@ -0,0 +1,3 @@
+-- Reverses 019_usage_stats.up.sql. Dropping the table removes its index
+-- and namespace_scope policy.
+DROP TABLE IF EXISTS usage_stats;
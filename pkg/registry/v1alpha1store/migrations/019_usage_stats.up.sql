@@ -0,0 +1,31 @@
+-- Per-artifact usage counters.
+--
+-- One row per Agent, MCPServer or Skill name (all tags together) counting
+-- how often it is fetched (GET of the artifact or one of its tags), how
+-- often a Deployment of it is applied, and how often it is returned by an
+-- MCP registry `?search=` query. Replicas buffer counts in memory and add
+-- them here in periodic batches, so the counters trail live traffic by up
+-- to one flush interval. Rows outlive the artifact so a re-published name
+-- keeps its history.
+
+CREATE TABLE IF NOT EXISTS usage_stats (
+    kind        VARCHAR(64)  NOT NULL,
+    namespace   VARCHAR(255) NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    downloads   BIGINT       NOT NULL DEFAULT 0,
+    deploys     BIGINT       NOT NULL DEFAULT 0,
+    search_hits BIGINT       NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (kind, namespace, name)
+);
+
+-- Popularity ranking: ORDER BY the combined count within one kind.
+CREATE INDEX IF NOT EXISTS usage_stats_popularity_idx
+    ON usage_stats (kind, (downloads + deploys + search_hits) DESC);
+
+DROP POLICY IF EXISTS namespace_scope ON usage_stats;
+CREATE POLICY namespace_scope ON usage_stats
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE usage_stats ENABLE ROW LEVEL SECURITY;
+ALTER TABLE usage_stats FORCE ROW LEVEL SECURITY;
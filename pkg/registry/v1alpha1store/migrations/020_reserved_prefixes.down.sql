@@ -0,0 +1,4 @@
+-- Reverses 020_reserved_prefixes.up.sql. Dropping the tables removes their
+-- trigger and index; set_updated_at is owned by 001.
+DROP TABLE IF EXISTS reserved_prefix_requests;
+DROP TABLE IF EXISTS reserved_prefixes;
@@ -0,0 +1,38 @@
+-- Reserved name prefixes.
+--
+-- A row reserves a name prefix (e.g. `official/`) so that only its owners
+-- may publish Agents, MCPServers, Skills and Prompts whose name or
+-- namespace starts with it. Prefixes listed in the server configuration
+-- are reserved too; a row for one of them only records its owners.
+--
+-- `owners` is a JSON array of principal subjects allocated the prefix.
+-- Callers ask for an allocation with a row in reserved_prefix_requests,
+-- which a registry admin approves (adding the requester to `owners`) or
+-- rejects. A caller has at most one pending request per prefix.
+
+CREATE TABLE IF NOT EXISTS reserved_prefixes (
+    prefix     VARCHAR(255) PRIMARY KEY,
+    reason     TEXT         NOT NULL DEFAULT '',
+    owners     JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE TRIGGER reserved_prefixes_set_updated_at
+    BEFORE UPDATE ON reserved_prefixes
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS reserved_prefix_requests (
+    id            BIGSERIAL    PRIMARY KEY,
+    prefix        VARCHAR(255) NOT NULL,
+    requester     TEXT         NOT NULL,
+    justification TEXT         NOT NULL DEFAULT '',
+    status        VARCHAR(16)  NOT NULL DEFAULT 'pending',
+    decided_by    TEXT         NOT NULL DEFAULT '',
+    decided_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS reserved_prefix_requests_one_pending
+    ON reserved_prefix_requests (prefix, requester)
+    WHERE status = 'pending';
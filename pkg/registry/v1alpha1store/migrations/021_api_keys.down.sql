@@ -0,0 +1,2 @@
+-- Reverses 021_api_keys.up.sql. Dropping the table removes its index.
+DROP TABLE IF EXISTS api_keys;
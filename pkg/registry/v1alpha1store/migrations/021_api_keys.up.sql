@@ -0,0 +1,26 @@
+-- API keys.
+--
+-- A row is one long-lived credential for automation (CI pipelines) that
+-- acts on behalf of its `owner` within narrow scopes: `kinds` (artifact
+-- kinds, e.g. ["Agent", "MCPServer"]; empty means every artifact kind),
+-- `prefixes` (name prefixes; empty means every name) and `actions`
+-- (any of read, push, deploy). The secret is shown once at creation and
+-- only its SHA-256 is stored.
+--
+-- Revoked keys keep their row so listings show when they stopped working.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           VARCHAR(32)  PRIMARY KEY,
+    name         VARCHAR(255) NOT NULL,
+    owner        TEXT         NOT NULL,
+    secret_hash  TEXT         NOT NULL,
+    kinds        JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    prefixes     JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    actions      JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_owner ON api_keys (owner);
@@ -0,0 +1,3 @@
+-- Reverses 022_deployment_logs.up.sql. Dropping the table removes its
+-- indexes and namespace_scope policy.
+DROP TABLE IF EXISTS deployment_logs;
@@ -0,0 +1,32 @@
+-- Retained deployment logs.
+--
+-- When log shipping is enabled the registry periodically copies the lines
+-- runtime adapters report for each Deployment into this table, so
+-- `GET /v0/deployments/{name}/logs` can still answer after containers
+-- restart or rotate their logs. Retention is bounded twice: rows older
+-- than the configured retention are purged, and each Deployment keeps at
+-- most a configured number of bytes, dropping its oldest lines first.
+--
+-- `logged_at` is the timestamp the runtime reported for the line; `id`
+-- breaks ties between lines logged in the same instant.
+
+CREATE TABLE IF NOT EXISTS deployment_logs (
+    id        BIGSERIAL    PRIMARY KEY,
+    namespace VARCHAR(255) NOT NULL,
+    name      VARCHAR(255) NOT NULL,
+    logged_at TIMESTAMPTZ  NOT NULL,
+    stream    VARCHAR(32)  NOT NULL DEFAULT '',
+    line      TEXT         NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS deployment_logs_deployment
+    ON deployment_logs (namespace, name, logged_at, id);
+CREATE INDEX IF NOT EXISTS deployment_logs_logged_at
+    ON deployment_logs (logged_at);
+
+DROP POLICY IF EXISTS namespace_scope ON deployment_logs;
+CREATE POLICY namespace_scope ON deployment_logs
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE deployment_logs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE deployment_logs FORCE ROW LEVEL SECURITY;
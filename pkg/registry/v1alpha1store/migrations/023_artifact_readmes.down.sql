@@ -0,0 +1,3 @@
+-- Reverses 023_artifact_readmes.up.sql. Dropping the table removes its
+-- namespace_scope policy.
+DROP TABLE IF EXISTS artifact_readmes;
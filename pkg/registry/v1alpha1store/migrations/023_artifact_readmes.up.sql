@@ -0,0 +1,29 @@
+-- Artifact READMEs.
+--
+-- Agents, MCP servers, skills and prompts can carry a Markdown README,
+-- uploaded per tag through `PUT /v0/{plural}/{name}/{tag}/readme` (`arctl
+-- publish` sends the project's README.md). READMEs live beside the artifact
+-- rather than in its spec so they can be revised without re-publishing the
+-- tag.
+--
+-- `artifact_uid` is the uid of the artifact row the README was uploaded for.
+-- A tag that is purged and published again gets a new uid, so the stale
+-- README is no longer served; the next upload replaces the row.
+
+CREATE TABLE IF NOT EXISTS artifact_readmes (
+    kind         VARCHAR(64)  NOT NULL,
+    namespace    VARCHAR(255) NOT NULL,
+    name         VARCHAR(255) NOT NULL,
+    tag          VARCHAR(255) NOT NULL,
+    artifact_uid UUID         NOT NULL,
+    content      TEXT         NOT NULL,
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (kind, namespace, name, tag)
+);
+
+DROP POLICY IF EXISTS namespace_scope ON artifact_readmes;
+CREATE POLICY namespace_scope ON artifact_readmes
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE artifact_readmes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE artifact_readmes FORCE ROW LEVEL SECURITY;
@@ -0,0 +1,3 @@
+-- Reverses 024_webhook_dead_letters.up.sql. Dropping the table removes its
+-- namespace_scope policy.
+DROP TABLE IF EXISTS webhook_dead_letters;
@@ -0,0 +1,46 @@
+-- Webhook dead letters.
+--
+-- A delivery whose in-process attempts (AGENT_REGISTRY_WEBHOOK_MAX_ATTEMPTS)
+-- all failed is parked here with its full payload instead of being dropped,
+-- so an endpoint that was down still receives the event once it recovers.
+-- The dispatcher retries `pending` rows when `next_attempt_at` passes, with
+-- exponential backoff up to a cap; a row whose retries run out, or whose
+-- endpoint rejected it outright, becomes `exhausted` and is only sent again
+-- by an explicit replay. A row that is finally delivered becomes
+-- `delivered`. Every retry and replay is also an attempt in
+-- webhook_deliveries, under the same delivery_id.
+
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    delivery_id      TEXT         PRIMARY KEY,
+    namespace        VARCHAR(255) NOT NULL,
+    webhook          VARCHAR(255) NOT NULL,
+    event            TEXT         NOT NULL,
+    url              TEXT         NOT NULL,
+    payload          JSONB        NOT NULL,
+    state            TEXT         NOT NULL,
+    attempts         INTEGER      NOT NULL,
+    retries          INTEGER      NOT NULL DEFAULT 0,
+    status_code      INTEGER      NOT NULL DEFAULT 0,
+    error            TEXT         NOT NULL DEFAULT '',
+    next_attempt_at  TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT webhook_dead_letters_state_check
+        CHECK (state IN ('pending', 'exhausted', 'delivered'))
+);
+
+-- dead letters of one webhook, newest first
+CREATE INDEX IF NOT EXISTS webhook_dead_letters_webhook
+    ON webhook_dead_letters (namespace, webhook, created_at DESC);
+
+-- due automatic retries
+CREATE INDEX IF NOT EXISTS webhook_dead_letters_due
+    ON webhook_dead_letters (next_attempt_at)
+    WHERE state = 'pending';
+
+DROP POLICY IF EXISTS namespace_scope ON webhook_dead_letters;
+CREATE POLICY namespace_scope ON webhook_dead_letters
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE webhook_dead_letters ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_dead_letters FORCE ROW LEVEL SECURITY;
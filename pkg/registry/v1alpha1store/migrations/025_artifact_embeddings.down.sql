@@ -0,0 +1,3 @@
+-- Reverses 025_artifact_embeddings.up.sql. Dropping the table removes its
+-- index and namespace_scope policy.
+DROP TABLE IF EXISTS artifact_embeddings;
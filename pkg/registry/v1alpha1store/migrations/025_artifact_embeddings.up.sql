@@ -0,0 +1,29 @@
+-- Artifact embeddings.
+--
+-- The built-in semantic index keeps one embedding per artifact, generated
+-- from the latest tag's name, title, description and README. `model` names
+-- the provider model that produced the vector, so vectors from different
+-- models are never compared; `checksum` is the checksum of the text it was
+-- generated from, so the embeddings backfill can tell a stale embedding
+-- from an up-to-date one.
+
+CREATE TABLE IF NOT EXISTS artifact_embeddings (
+    kind       VARCHAR(64)  NOT NULL,
+    namespace  VARCHAR(255) NOT NULL,
+    name       VARCHAR(255) NOT NULL,
+    model      VARCHAR(255) NOT NULL,
+    checksum   VARCHAR(64)  NOT NULL,
+    embedding  REAL[]       NOT NULL,
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (kind, namespace, name)
+);
+
+CREATE INDEX IF NOT EXISTS artifact_embeddings_model_kind
+    ON artifact_embeddings (model, kind);
+
+DROP POLICY IF EXISTS namespace_scope ON artifact_embeddings;
+CREATE POLICY namespace_scope ON artifact_embeddings
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE artifact_embeddings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE artifact_embeddings FORCE ROW LEVEL SECURITY;
@@ -0,0 +1,2 @@
+-- Reverses 026_artifact_readme_search.up.sql.
+ALTER TABLE artifact_readmes DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,10 @@
+-- Full-text search over uploaded READMEs.
+--
+-- Search parses every latest tag's README on each query. READMEs are the
+-- longest text in the document, so the uploaded ones are parsed once, on
+-- write, into a stored tsvector that Search reads instead. It uses the
+-- same 'english' configuration as the queries.
+
+ALTER TABLE artifact_readmes
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('english', content)) STORED;
@@ -0,0 +1,3 @@
+-- Reverses 027_artifact_icons.up.sql. Dropping the table removes its
+-- namespace_scope policy.
+DROP TABLE IF EXISTS artifact_icons;
@@ -0,0 +1,26 @@
+-- Artifact icons.
+--
+-- MCP servers, agents and skills can carry an uploaded logo, through
+-- `PUT /v0/{plural}/{name}/icon`, that catalogs show in place of the URL in
+-- spec.icon. An icon belongs to the artifact name rather than to a tag, so
+-- publishing a new version keeps the branding.
+--
+-- `digest` is the hex SHA-256 of `content`; GET serves it as the ETag.
+
+CREATE TABLE IF NOT EXISTS artifact_icons (
+    kind         VARCHAR(64)  NOT NULL,
+    namespace    VARCHAR(255) NOT NULL,
+    name         VARCHAR(255) NOT NULL,
+    content_type VARCHAR(64)  NOT NULL,
+    content      BYTEA        NOT NULL,
+    digest       CHAR(64)     NOT NULL,
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (kind, namespace, name)
+);
+
+DROP POLICY IF EXISTS namespace_scope ON artifact_icons;
+CREATE POLICY namespace_scope ON artifact_icons
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE artifact_icons ENABLE ROW LEVEL SECURITY;
+ALTER TABLE artifact_icons FORCE ROW LEVEL SECURITY;
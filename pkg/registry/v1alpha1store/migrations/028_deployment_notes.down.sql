@@ -0,0 +1,3 @@
+-- Reverses 028_deployment_notes.up.sql. Dropping the table removes its
+-- namespace_scope policy and index.
+DROP TABLE IF EXISTS deployment_notes;
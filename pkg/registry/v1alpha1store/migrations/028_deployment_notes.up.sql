@@ -0,0 +1,32 @@
+-- Deployment notes.
+--
+-- Operators attach context to a Deployment, such as why it was rolled
+-- back and where its runbook lives, through
+-- `PATCH /v0/deployments/{name}/notes`. Every edit appends a revision
+-- holding the full note and links as they stand after it, so the newest row
+-- is the current notes and older rows are the history. Rows are never
+-- updated or deleted, so the history outlives the Deployment and a
+-- Deployment re-created under the same name picks it up again.
+--
+-- `author` is the subject of the caller that saved the revision, empty
+-- when auth is disabled.
+
+CREATE TABLE IF NOT EXISTS deployment_notes (
+    id         BIGSERIAL    PRIMARY KEY,
+    namespace  VARCHAR(255) NOT NULL,
+    name       VARCHAR(255) NOT NULL,
+    note       TEXT         NOT NULL DEFAULT '',
+    links      JSONB        NOT NULL DEFAULT '[]',
+    author     VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS deployment_notes_deployment
+    ON deployment_notes (namespace, name, id);
+
+DROP POLICY IF EXISTS namespace_scope ON deployment_notes;
+CREATE POLICY namespace_scope ON deployment_notes
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE deployment_notes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE deployment_notes FORCE ROW LEVEL SECURITY;
@@ -0,0 +1,3 @@
+-- Reverses 029_deployment_shares.up.sql. Dropping the table removes its
+-- namespace_scope policy and index.
+DROP TABLE IF EXISTS deployment_shares;
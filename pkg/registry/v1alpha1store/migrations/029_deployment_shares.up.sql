@@ -0,0 +1,36 @@
+-- Deployment share links.
+--
+-- A row is one expiring credential created through
+-- `POST /v0/deployments/{name}/share` that lets anyone holding its token
+-- read that one Deployment's status and logs and chat with it under
+-- `/v0/shared/{token}`, without a registry account. The token is shown once
+-- at creation and only the SHA-256 of its secret is stored.
+--
+-- `created_by` and `revoked_by` are the subjects of the callers that
+-- created and revoked the share, empty when auth is disabled. `use_count`
+-- and `last_used_at` record every request the token authenticated. Revoked
+-- and expired shares keep their row so listings show who had access when.
+
+CREATE TABLE IF NOT EXISTS deployment_shares (
+    id           VARCHAR(32)  PRIMARY KEY,
+    namespace    VARCHAR(255) NOT NULL,
+    name         VARCHAR(255) NOT NULL,
+    secret_hash  TEXT         NOT NULL,
+    created_by   VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at   TIMESTAMPTZ  NOT NULL,
+    revoked_at   TIMESTAMPTZ,
+    revoked_by   VARCHAR(255) NOT NULL DEFAULT '',
+    use_count    BIGINT       NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS deployment_shares_deployment
+    ON deployment_shares (namespace, name, created_at);
+
+DROP POLICY IF EXISTS namespace_scope ON deployment_shares;
+CREATE POLICY namespace_scope ON deployment_shares
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE deployment_shares ENABLE ROW LEVEL SECURITY;
+ALTER TABLE deployment_shares FORCE ROW LEVEL SECURITY;
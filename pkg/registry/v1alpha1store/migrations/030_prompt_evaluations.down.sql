@@ -0,0 +1,3 @@
+-- Reverses 030_prompt_evaluations.up.sql. Dropping the table removes its
+-- namespace_scope policy and index.
+DROP TABLE IF EXISTS prompt_evaluations;
@@ -0,0 +1,35 @@
+-- Prompt evaluation scores.
+--
+-- A row is one run of a Prompt tag's `spec.evaluation` against the
+-- registry's evaluation model: started when the tag is published, or on
+-- demand through `POST /v0/prompts/{name}/{tag}/evaluations`. `score` is
+-- the mean case score from 0 to 100, NULL when the run failed with
+-- `error`. `results` holds each case's answer and checks. Rows are never
+-- updated, so a Prompt's score history shows how its tags compare.
+--
+-- `trigger` is `publish` or `manual`; `requested_by` is the subject of the
+-- caller that published or asked for the run, empty when auth is disabled.
+
+CREATE TABLE IF NOT EXISTS prompt_evaluations (
+    id           BIGSERIAL        PRIMARY KEY,
+    namespace    VARCHAR(255)     NOT NULL,
+    name         VARCHAR(255)     NOT NULL,
+    tag          VARCHAR(255)     NOT NULL,
+    model        VARCHAR(255)     NOT NULL,
+    trigger      VARCHAR(16)      NOT NULL,
+    requested_by VARCHAR(255)     NOT NULL DEFAULT '',
+    score        DOUBLE PRECISION,
+    results      JSONB            NOT NULL DEFAULT '[]',
+    error        TEXT             NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS prompt_evaluations_prompt
+    ON prompt_evaluations (namespace, name, id);
+
+DROP POLICY IF EXISTS namespace_scope ON prompt_evaluations;
+CREATE POLICY namespace_scope ON prompt_evaluations
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE prompt_evaluations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE prompt_evaluations FORCE ROW LEVEL SECURITY;
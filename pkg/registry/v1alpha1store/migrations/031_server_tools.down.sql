@@ -0,0 +1,4 @@
+-- Reverses 031_server_tools.up.sql.
+DROP TRIGGER IF EXISTS mcp_servers_sync_tools ON mcp_servers;
+DROP FUNCTION IF EXISTS sync_server_tools();
+DROP TABLE IF EXISTS server_tools;
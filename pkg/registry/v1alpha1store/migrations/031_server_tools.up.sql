@@ -0,0 +1,70 @@
+-- MCP server tools, one row per tool of each MCPServer tag.
+--
+-- The tools an MCPServer declares in `spec.tools` — recorded by the
+-- publisher or by `arctl apply --validate-runtime` — are copied here by a
+-- trigger whenever a tag is published or its spec changes, so they can be
+-- listed per tag and matched by full-text search without unpacking every
+-- spec. `position` keeps the order the server listed them in.
+-- `search_vector` weights the tool name, split on separators, above its
+-- description. Rows go with their tag when it is purged.
+
+CREATE TABLE IF NOT EXISTS server_tools (
+    namespace     VARCHAR(255) NOT NULL,
+    name          VARCHAR(255) NOT NULL,
+    tag           VARCHAR(255) NOT NULL,
+    tool_name     TEXT         NOT NULL,
+    position      INTEGER      NOT NULL,
+    description   TEXT         NOT NULL DEFAULT '',
+    input_schema  JSONB,
+    search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', translate(tool_name, '-_./', '    ')), 'A') ||
+        setweight(to_tsvector('english', description), 'B')
+    ) STORED,
+    PRIMARY KEY (namespace, name, tag, tool_name),
+    FOREIGN KEY (namespace, name, tag) REFERENCES mcp_servers (namespace, name, tag) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS server_tools_search_vector
+    ON server_tools USING gin (search_vector);
+
+CREATE OR REPLACE FUNCTION sync_server_tools() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND (NEW.spec->'tools') IS NOT DISTINCT FROM (OLD.spec->'tools') THEN
+        RETURN NEW;
+    END IF;
+    DELETE FROM server_tools
+    WHERE namespace = NEW.namespace AND name = NEW.name AND tag = NEW.tag;
+    INSERT INTO server_tools (namespace, name, tag, tool_name, position, description, input_schema)
+    SELECT NEW.namespace, NEW.name, NEW.tag, tool->>'name', ord,
+           coalesce(tool->>'description', ''), tool->'inputSchema'
+    FROM jsonb_array_elements(
+             CASE WHEN jsonb_typeof(NEW.spec->'tools') = 'array' THEN NEW.spec->'tools' ELSE '[]' END
+         ) WITH ORDINALITY AS t(tool, ord)
+    WHERE coalesce(tool->>'name', '') <> ''
+    ON CONFLICT DO NOTHING;
+    RETURN NEW;
+END;
+$$;
+
+CREATE OR REPLACE TRIGGER mcp_servers_sync_tools
+    AFTER INSERT OR UPDATE OF spec ON mcp_servers
+    FOR EACH ROW EXECUTE FUNCTION sync_server_tools();
+
+INSERT INTO server_tools (namespace, name, tag, tool_name, position, description, input_schema)
+SELECT s.namespace, s.name, s.tag, t.tool->>'name', t.ord,
+       coalesce(t.tool->>'description', ''), t.tool->'inputSchema'
+FROM mcp_servers s,
+     jsonb_array_elements(
+         CASE WHEN jsonb_typeof(s.spec->'tools') = 'array' THEN s.spec->'tools' ELSE '[]' END
+     ) WITH ORDINALITY AS t(tool, ord)
+WHERE coalesce(t.tool->>'name', '') <> ''
+ON CONFLICT DO NOTHING;
+
+DROP POLICY IF EXISTS namespace_scope ON server_tools;
+CREATE POLICY namespace_scope ON server_tools
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE server_tools ENABLE ROW LEVEL SECURITY;
+ALTER TABLE server_tools FORCE ROW LEVEL SECURITY;
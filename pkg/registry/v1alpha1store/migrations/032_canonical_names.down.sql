@@ -0,0 +1,12 @@
+-- Reverses 032_canonical_names.up.sql.
+DROP INDEX IF EXISTS agents_canonical_name;
+DROP INDEX IF EXISTS mcp_servers_canonical_name;
+DROP INDEX IF EXISTS skills_canonical_name;
+DROP INDEX IF EXISTS prompts_canonical_name;
+DROP INDEX IF EXISTS plugins_canonical_name;
+DROP INDEX IF EXISTS charts_canonical_name;
+DROP INDEX IF EXISTS runtimes_canonical_name;
+DROP INDEX IF EXISTS deployments_canonical_name;
+DROP INDEX IF EXISTS webhooks_canonical_name;
+DROP INDEX IF EXISTS feature_flags_canonical_name;
+DROP TABLE IF EXISTS name_collisions;
@@ -0,0 +1,109 @@
+-- Canonical resource names.
+--
+-- Names are published and looked up in canonical form (Unicode NFKC, then
+-- lower case), so `Weather-Bot` and `weather-bot` name one resource. Upsert
+-- refuses a new name when the namespace already holds another spelling of
+-- it; the `(namespace, lower(name))` indexes keep that check cheap.
+--
+-- Rows stored before names were canonical are not renamed: other specs,
+-- READMEs, icons and usage counts refer to them by name. Instead every
+-- group of rows whose names are not canonical, or differ only in case, is
+-- recorded in `name_collisions` with the spellings found, for an operator
+-- to resolve. A group with one spelling is a name that canonical lookups
+-- no longer reach; a group with several is a near-duplicate.
+
+CREATE TABLE IF NOT EXISTS name_collisions (
+    kind           VARCHAR(64)  NOT NULL,
+    namespace      VARCHAR(255) NOT NULL,
+    canonical_name VARCHAR(255) NOT NULL,
+    names          TEXT[]       NOT NULL,
+    detected_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (kind, namespace, canonical_name)
+);
+
+CREATE INDEX IF NOT EXISTS agents_canonical_name ON agents (namespace, lower(name));
+INSERT INTO name_collisions (kind, namespace, canonical_name, names)
+SELECT 'Agent', namespace, lower(name), array_agg(DISTINCT name ORDER BY name)
+FROM agents
+GROUP BY namespace, lower(name)
+HAVING count(DISTINCT name) > 1 OR bool_or(name <> lower(name))
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS mcp_servers_canonical_name ON mcp_servers (namespace, lower(name));
+INSERT INTO name_collisions (kind, namespace, canonical_name, names)
+SELECT 'MCPServer', namespace, lower(name), array_agg(DISTINCT name ORDER BY name)
+FROM mcp_servers
+GROUP BY namespace, lower(name)
+HAVING count(DISTINCT name) > 1 OR bool_or(name <> lower(name))
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS skills_canonical_name ON skills (namespace, lower(name));
+INSERT INTO name_collisions (kind, namespace, canonical_name, names)
+SELECT 'Skill', namespace, lower(name), array_agg(DISTINCT name ORDER BY name)
+FROM skills
+GROUP BY namespace, lower(name)
+HAVING count(DISTINCT name) > 1 OR bool_or(name <> lower(name))
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS prompts_canonical_name ON prompts (namespace, lower(name));
+INSERT INTO name_collisions (kind, namespace, canonical_name, names)
+SELECT 'Prompt', namespace, lower(name), array_agg(DISTINCT name ORDER BY name)
+FROM prompts
+GROUP BY namespace, lower(name)
+HAVING count(DISTINCT name) > 1 OR bool_or(name <> lower(name))
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS plugins_canonical_name ON plugins (namespace, lower(name));
+INSERT INTO name_collisions (kind, namespace, canonical_name, names)
+SELECT 'Plugin', namespace, lower(name), array_agg(DISTINCT name ORDER BY name)
+FROM plugins
+GROUP BY namespace, lower(name)
+HAVING count(DISTINCT name) > 1 OR bool_or(name <> lower(name))
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS charts_canonical_name ON charts (namespace, lower(name));
+INSERT INTO name_collisions (kind, namespace, canonical_name, names)
+SELECT 'Chart', namespace, lower(name), array_agg(DISTINCT name ORDER BY name)
+FROM charts
+GROUP BY namespace, lower(name)
+HAVING count(DISTINCT name) > 1 OR bool_or(name <> lower(name))
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS runtimes_canonical_name ON runtimes (namespace, lower(name));
+INSERT INTO name_collisions (kind, namespace, canonical_name, names)
+SELECT 'Runtime', namespace, lower(name), array_agg(DISTINCT name ORDER BY name)
+FROM runtimes
+GROUP BY namespace, lower(name)
+HAVING count(DISTINCT name) > 1 OR bool_or(name <> lower(name))
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS deployments_canonical_name ON deployments (namespace, lower(name));
+INSERT INTO name_collisions (kind, namespace, canonical_name, names)
+SELECT 'Deployment', namespace, lower(name), array_agg(DISTINCT name ORDER BY name)
+FROM deployments
+GROUP BY namespace, lower(name)
+HAVING count(DISTINCT name) > 1 OR bool_or(name <> lower(name))
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS webhooks_canonical_name ON webhooks (namespace, lower(name));
+INSERT INTO name_collisions (kind, namespace, canonical_name, names)
+SELECT 'Webhook', namespace, lower(name), array_agg(DISTINCT name ORDER BY name)
+FROM webhooks
+GROUP BY namespace, lower(name)
+HAVING count(DISTINCT name) > 1 OR bool_or(name <> lower(name))
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS feature_flags_canonical_name ON feature_flags (namespace, lower(name));
+INSERT INTO name_collisions (kind, namespace, canonical_name, names)
+SELECT 'FeatureFlag', namespace, lower(name), array_agg(DISTINCT name ORDER BY name)
+FROM feature_flags
+GROUP BY namespace, lower(name)
+HAVING count(DISTINCT name) > 1 OR bool_or(name <> lower(name))
+ON CONFLICT DO NOTHING;
+
+DROP POLICY IF EXISTS namespace_scope ON name_collisions;
+CREATE POLICY namespace_scope ON name_collisions
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE name_collisions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE name_collisions FORCE ROW LEVEL SECURITY;
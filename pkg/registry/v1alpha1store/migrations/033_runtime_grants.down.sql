@@ -0,0 +1,3 @@
+-- Reverses 033_runtime_grants.up.sql. Dropping the table removes its
+-- trigger; set_updated_at is owned by 001.
+DROP TABLE IF EXISTS runtime_grants;
@@ -0,0 +1,22 @@
+-- Runtime deploy grants.
+--
+-- A row restricts who may deploy to, undeploy from or delete Deployments
+-- on one Runtime: only the subjects in `deployers` (and registry admins).
+-- A Runtime without a row is unrestricted, and a row with no deployers
+-- leaves the Runtime to registry admins.
+--
+-- The table carries no namespace_scope policy on purpose: a caller whose
+-- scope hid a row would see the Runtime as unrestricted.
+
+CREATE TABLE IF NOT EXISTS runtime_grants (
+    namespace  VARCHAR(255) NOT NULL,
+    runtime    VARCHAR(255) NOT NULL,
+    deployers  JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (namespace, runtime)
+);
+
+CREATE OR REPLACE TRIGGER runtime_grants_set_updated_at
+    BEFORE UPDATE ON runtime_grants
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,10 @@
+-- Reverses 034_deployment_templates_table.up.sql. Dropping the table removes
+-- its indexes, triggers and namespace_scope policy; the shared functions
+-- (set_updated_at, notify_status_change, namespace_in_scope) are owned by
+-- earlier migrations and left in place.
+DROP INDEX IF EXISTS deployment_templates_canonical_name;
+DROP INDEX IF EXISTS deployment_templates_updated_at_desc;
+DROP INDEX IF EXISTS deployment_templates_terminating;
+DROP INDEX IF EXISTS deployment_templates_labels_gin;
+
+DROP TABLE IF EXISTS deployment_templates;
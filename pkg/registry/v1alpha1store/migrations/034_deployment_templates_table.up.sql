@@ -0,0 +1,53 @@
+-- Deployment templates: named presets of Deployment settings (runtime
+-- placement, env, runtimeConfig, resources) applied when a Deployment is
+-- created from one. A mutable kind keyed by (namespace, name) with the same
+-- column layout as runtimes and feature_flags, the updated-at and
+-- status-notify triggers, the canonical-name index from 032 and the
+-- namespace_scope row-level security policy from 011. Templates carry no
+-- control-plane-event trigger: editing one does not touch Deployments
+-- already created from it.
+
+CREATE TABLE IF NOT EXISTS deployment_templates (
+    namespace character varying(255) NOT NULL,
+    name character varying(255) NOT NULL,
+    uid uuid DEFAULT gen_random_uuid() NOT NULL,
+    generation bigint DEFAULT 1 NOT NULL,
+    labels jsonb DEFAULT '{}'::jsonb NOT NULL,
+    annotations jsonb DEFAULT '{}'::jsonb NOT NULL,
+    spec jsonb NOT NULL,
+    status jsonb DEFAULT '{}'::jsonb NOT NULL,
+    deletion_timestamp timestamp with time zone,
+    finalizers jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (namespace, name)
+);
+
+-- list by labels
+CREATE INDEX IF NOT EXISTS deployment_templates_labels_gin
+    ON deployment_templates USING gin (labels);
+
+-- purge terminating rows
+CREATE INDEX IF NOT EXISTS deployment_templates_terminating
+    ON deployment_templates USING btree (deletion_timestamp)
+    WHERE deletion_timestamp IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS deployment_templates_updated_at_desc
+    ON deployment_templates USING btree (updated_at DESC);
+
+CREATE INDEX IF NOT EXISTS deployment_templates_canonical_name
+    ON deployment_templates (namespace, lower(name));
+
+CREATE OR REPLACE TRIGGER deployment_templates_set_updated_at
+    BEFORE UPDATE ON deployment_templates
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE OR REPLACE TRIGGER deployment_templates_notify_status
+    AFTER INSERT OR UPDATE OR DELETE ON deployment_templates
+    FOR EACH ROW EXECUTE FUNCTION notify_status_change('deployment_templates_status');
+
+DROP POLICY IF EXISTS namespace_scope ON deployment_templates;
+CREATE POLICY namespace_scope ON deployment_templates
+    USING (namespace_in_scope(namespace))
+    WITH CHECK (namespace_in_scope(namespace));
+ALTER TABLE deployment_templates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE deployment_templates FORCE ROW LEVEL SECURITY;
@@ -0,0 +1,3 @@
+-- Reverses 035_catalog_snapshots.up.sql.
+DROP TABLE IF EXISTS catalog_snapshot_entries;
+DROP TABLE IF EXISTS catalog_snapshots;
@@ -0,0 +1,34 @@
+-- Catalog snapshots.
+--
+-- A snapshot is a named, immutable copy of the catalog taken through
+-- `POST /v0/snapshots`: one catalog_snapshot_entries row per live tag of
+-- every tagged artifact (Agents, MCPServers, Skills, Prompts, ...) in its
+-- namespace, or in every namespace when `namespace` is empty. `object` is
+-- the tag's full envelope as it was captured, so a Deployment with
+-- spec.snapshot resolves the same content whatever is published, replaced
+-- or deleted later.
+--
+-- `created_by` is the subject of the caller that took the snapshot, empty
+-- when auth is disabled. Deleting a snapshot deletes its entries.
+--
+-- Neither table carries a namespace_scope policy on purpose: snapshots are
+-- managed by registry admins, and an entry a caller's scope hid would make
+-- a pinned ref look dangling.
+
+CREATE TABLE IF NOT EXISTS catalog_snapshots (
+    name        VARCHAR(255) PRIMARY KEY,
+    namespace   VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT         NOT NULL DEFAULT '',
+    created_by  VARCHAR(255) NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS catalog_snapshot_entries (
+    snapshot  VARCHAR(255) NOT NULL REFERENCES catalog_snapshots (name) ON DELETE CASCADE,
+    kind      VARCHAR(64)  NOT NULL,
+    namespace VARCHAR(255) NOT NULL,
+    name      VARCHAR(255) NOT NULL,
+    tag       VARCHAR(255) NOT NULL,
+    object    JSONB        NOT NULL,
+    PRIMARY KEY (snapshot, kind, namespace, name, tag)
+);
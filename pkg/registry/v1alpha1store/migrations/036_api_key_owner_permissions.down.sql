@@ -0,0 +1,2 @@
+-- Reverses 036_api_key_owner_permissions.up.sql.
+ALTER TABLE api_keys DROP COLUMN IF EXISTS owner_permissions;
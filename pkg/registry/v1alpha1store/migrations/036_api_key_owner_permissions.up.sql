@@ -0,0 +1,23 @@
+-- API key owner permissions.
+--
+-- `owner_permissions` records the permissions the key's owner held when
+-- the key was created (e.g. [{"action": "publish", "resource": "acme/*"}]).
+-- A key acts only where both its own scopes and its owner's permissions
+-- reach, so it can never do more than the person who minted it. Authz
+-- providers that resolve permissions by subject see the owner's current
+-- grants instead; providers that read them from the token see these.
+--
+-- Before this column existed only registry admins could create keys, so
+-- existing admin keys (empty owner) keep every permission. Keys of other
+-- owners get none and must be recreated.
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS owner_permissions JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+UPDATE api_keys SET owner_permissions = '[
+    {"action": "read", "resource": "*"},
+    {"action": "publish", "resource": "*"},
+    {"action": "edit", "resource": "*"},
+    {"action": "delete", "resource": "*"},
+    {"action": "deploy", "resource": "*"}
+]'::jsonb
+WHERE owner = '' AND owner_permissions = '[]'::jsonb;
@@ -0,0 +1,21 @@
+-- Reverses 037_unique_canonical_names.up.sql. Names it lower-cased keep
+-- their canonical spelling.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'agents', 'mcp_servers', 'skills', 'prompts', 'plugins', 'charts',
+        'runtimes', 'deployments', 'webhooks', 'feature_flags', 'deployment_templates'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I DROP CONSTRAINT IF EXISTS %I', t, t || '_name_canonical');
+        EXECUTE format('DROP INDEX IF EXISTS %I', t || '_canonical_name');
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I (namespace, lower(name))', t || '_canonical_name', t);
+    END LOOP;
+END $$;
+
+ALTER TABLE server_tools
+    DROP CONSTRAINT IF EXISTS server_tools_namespace_name_tag_fkey,
+    ADD CONSTRAINT server_tools_namespace_name_tag_fkey
+        FOREIGN KEY (namespace, name, tag) REFERENCES mcp_servers (namespace, name, tag)
+        ON DELETE CASCADE;
@@ -0,0 +1,74 @@
+-- Unique canonical names.
+--
+-- 032 indexed (namespace, lower(name)) without making it unique, so two
+-- writes of spellings differing only in case could both pass the store's
+-- check for the other, and it left names stored before they were canonical
+-- where canonical lookups cannot reach them. The store now writes and
+-- looks up canonical names only. This migration:
+--
+--   1. refuses to run while a namespace holds two spellings of one name
+--      (032 listed them in name_collisions): delete or rename all but one
+--      spelling of each, then migrate again;
+--   2. lower-cases every other name that is not canonical, along with the
+--      rows that refer to the resource by name;
+--   3. replaces the 032 indexes with unique ones and checks that names are
+--      stored lower-case, which makes the index unique across the tags of
+--      a tagged artifact too.
+
+DO $$
+DECLARE
+    t   TEXT;
+    dup TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'agents', 'mcp_servers', 'skills', 'prompts', 'plugins', 'charts',
+        'runtimes', 'deployments', 'webhooks', 'feature_flags', 'deployment_templates'
+    ] LOOP
+        EXECUTE format(
+            'SELECT string_agg(DISTINCT o.namespace || ''/'' || o.name, '', '') FROM %I o '
+            'WHERE EXISTS (SELECT 1 FROM %I p WHERE p.namespace = o.namespace '
+            'AND lower(p.name) = lower(o.name) AND p.name <> o.name)', t, t)
+        INTO dup;
+        IF dup IS NOT NULL THEN
+            RAISE EXCEPTION '% holds names differing only in case: %; keep one spelling of each and migrate again', t, dup;
+        END IF;
+    END LOOP;
+END $$;
+
+-- Let renaming a server carry its tools along.
+ALTER TABLE server_tools
+    DROP CONSTRAINT IF EXISTS server_tools_namespace_name_tag_fkey,
+    ADD CONSTRAINT server_tools_namespace_name_tag_fkey
+        FOREIGN KEY (namespace, name, tag) REFERENCES mcp_servers (namespace, name, tag)
+        ON DELETE CASCADE ON UPDATE CASCADE;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'agents', 'mcp_servers', 'skills', 'prompts', 'plugins', 'charts',
+        'runtimes', 'deployments', 'webhooks', 'feature_flags', 'deployment_templates',
+        'artifact_readmes', 'artifact_embeddings', 'artifact_icons', 'usage_stats',
+        'prompt_evaluations', 'deployment_manifests', 'deployment_logs',
+        'deployment_notes', 'deployment_shares'
+    ] LOOP
+        EXECUTE format('UPDATE %I SET name = lower(name) WHERE name <> lower(name)', t);
+    END LOOP;
+END $$;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['agents', 'mcp_servers', 'skills', 'prompts', 'plugins', 'charts'] LOOP
+        EXECUTE format('DROP INDEX IF EXISTS %I', t || '_canonical_name');
+        EXECUTE format('CREATE UNIQUE INDEX %I ON %I (namespace, lower(name), tag)', t || '_canonical_name', t);
+        EXECUTE format('ALTER TABLE %I ADD CONSTRAINT %I CHECK (name = lower(name))', t, t || '_name_canonical');
+    END LOOP;
+    FOREACH t IN ARRAY ARRAY['runtimes', 'deployments', 'webhooks', 'feature_flags', 'deployment_templates'] LOOP
+        EXECUTE format('DROP INDEX IF EXISTS %I', t || '_canonical_name');
+        EXECUTE format('CREATE UNIQUE INDEX %I ON %I (namespace, lower(name))', t || '_canonical_name', t);
+        EXECUTE format('ALTER TABLE %I ADD CONSTRAINT %I CHECK (name = lower(name))', t, t || '_name_canonical');
+    END LOOP;
+END $$;
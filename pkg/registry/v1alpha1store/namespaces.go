@@ -0,0 +1,229 @@
+package v1alpha1store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// Namespace is one claimed namespace (migration 017). A claim restricts
+// publishing in the namespace only once VerifiedAt is set.
+type Namespace struct {
+	Name              string
+	Owner             string
+	Members           []string
+	VerificationToken string
+	VerifiedAt        *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// Verified reports whether the claim has been verified.
+func (n *Namespace) Verified() bool {
+	return n != nil && n.VerifiedAt != nil
+}
+
+// IsMember reports whether subject owns the namespace or is listed as a
+// member of it.
+func (n *Namespace) IsMember(subject string) bool {
+	if n == nil || subject == "" {
+		return false
+	}
+	return n.Owner == subject || slices.Contains(n.Members, subject)
+}
+
+// NamespaceStore reads and writes namespace claims.
+type NamespaceStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewNamespaceStore constructs a namespace claim store.
+func NewNamespaceStore(pool *pgxpool.Pool, schema pkgdb.Schema) *NamespaceStore {
+	return &NamespaceStore{
+		pool:      pool,
+		qualified: schema.Qualify("namespaces"),
+	}
+}
+
+const namespaceColumns = `name, owner, members, verification_token, verified_at, created_at, updated_at`
+
+// Get returns the claim on name, or pkgdb.ErrNotFound when it is
+// unclaimed.
+func (s *NamespaceStore) Get(ctx context.Context, name string) (*Namespace, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: namespace store has nil pool")
+	}
+	return getNamespace(ctx, s.pool, s.qualified, name)
+}
+
+// List returns every claim, ordered by name.
+func (s *NamespaceStore) List(ctx context.Context) ([]*Namespace, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: namespace store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `SELECT `+namespaceColumns+` FROM `+s.qualified+` ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list namespaces: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Namespace
+	for rows.Next() {
+		ns, err := scanNamespace(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan namespace: %w", err)
+		}
+		out = append(out, ns)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read namespaces: %w", err)
+	}
+	return out, nil
+}
+
+// Claim records a pending claim on name for owner with the given
+// verification token. Claiming again as the same owner returns the
+// existing claim unchanged. A pending claim by another owner created
+// before staleBefore is replaced; any other existing claim makes Claim
+// return an error matching pkgdb.ErrAlreadyExists.
+func (s *NamespaceStore) Claim(ctx context.Context, name, owner, token string, staleBefore time.Time) (*Namespace, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: namespace store has nil pool")
+	}
+	var out *Namespace
+	err := runInTx(ctx, s.pool, func(tx pgx.Tx) error {
+		row := tx.QueryRow(ctx, `
+			INSERT INTO `+s.qualified+` (name, owner, verification_token)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (name) DO UPDATE SET
+				owner = EXCLUDED.owner,
+				members = '[]'::jsonb,
+				verification_token = EXCLUDED.verification_token,
+				created_at = NOW()
+			WHERE `+s.qualified+`.verified_at IS NULL
+				AND `+s.qualified+`.owner <> EXCLUDED.owner
+				AND `+s.qualified+`.created_at < $4
+			RETURNING `+namespaceColumns, name, owner, token, staleBefore)
+		ns, err := scanNamespace(row)
+		if err == nil {
+			out = ns
+			return nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("claim namespace %s: %w", name, err)
+		}
+		existing, err := getNamespace(ctx, tx, s.qualified, name)
+		if err != nil {
+			return err
+		}
+		if existing.Owner != owner {
+			return fmt.Errorf("%w: namespace %s is already claimed", pkgdb.ErrAlreadyExists, name)
+		}
+		out = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Verify marks the claim on name verified. Verifying an already verified
+// claim keeps its original VerifiedAt.
+func (s *NamespaceStore) Verify(ctx context.Context, name string) (*Namespace, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: namespace store has nil pool")
+	}
+	row := s.pool.QueryRow(ctx, `
+		UPDATE `+s.qualified+`
+		SET verified_at = COALESCE(verified_at, NOW())
+		WHERE name = $1
+		RETURNING `+namespaceColumns, name)
+	return namespaceOrNotFound(row, name, "verify")
+}
+
+// SetMembers replaces the member list of the claim on name.
+func (s *NamespaceStore) SetMembers(ctx context.Context, name string, members []string) (*Namespace, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: namespace store has nil pool")
+	}
+	if members == nil {
+		members = []string{}
+	}
+	raw, err := json.Marshal(members)
+	if err != nil {
+		return nil, fmt.Errorf("encode namespace members: %w", err)
+	}
+	row := s.pool.QueryRow(ctx, `
+		UPDATE `+s.qualified+`
+		SET members = $2
+		WHERE name = $1
+		RETURNING `+namespaceColumns, name, raw)
+	return namespaceOrNotFound(row, name, "update members of")
+}
+
+// Delete releases the claim on name.
+func (s *NamespaceStore) Delete(ctx context.Context, name string) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: namespace store has nil pool")
+	}
+	cmdTag, err := s.pool.Exec(ctx, `DELETE FROM `+s.qualified+` WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("delete namespace %s: %w", name, err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return pkgdb.ErrNotFound
+	}
+	return nil
+}
+
+type namespaceQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func getNamespace(ctx context.Context, q namespaceQuerier, qualified, name string) (*Namespace, error) {
+	row := q.QueryRow(ctx, `SELECT `+namespaceColumns+` FROM `+qualified+` WHERE name = $1`, name)
+	return namespaceOrNotFound(row, name, "get")
+}
+
+func namespaceOrNotFound(row pgx.Row, name, op string) (*Namespace, error) {
+	ns, err := scanNamespace(row)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, pkgdb.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("%s namespace %s: %w", op, name, err)
+	}
+	return ns, nil
+}
+
+func scanNamespace(row pgx.Row) (*Namespace, error) {
+	var (
+		ns      Namespace
+		members []byte
+	)
+	if err := row.Scan(
+		&ns.Name,
+		&ns.Owner,
+		&members,
+		&ns.VerificationToken,
+		&ns.VerifiedAt,
+		&ns.CreatedAt,
+		&ns.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(members, &ns.Members); err != nil {
+		return nil, fmt.Errorf("decode members of namespace %s: %w", ns.Name, err)
+	}
+	return &ns, nil
+}
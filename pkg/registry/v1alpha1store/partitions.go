@@ -0,0 +1,264 @@
+package v1alpha1store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// PartitionDaysAhead is how many days past today EnsurePartitions keeps
+// partitions created, so a missed maintenance pass never routes a day's
+// rows into the DEFAULT partition.
+const PartitionDaysAhead = 7
+
+// partitionDayLayout is the day suffix of a partition name:
+// control_plane_events_p20261017.
+const partitionDayLayout = "20060102"
+
+// PartitionedTable is an append-only table range-partitioned by day on a
+// timestamp column (migration 015).
+type PartitionedTable struct {
+	Name   string
+	Column string
+}
+
+var (
+	// ControlPlaneEventsTable is control_plane_events, partitioned on
+	// committed_at.
+	ControlPlaneEventsTable = PartitionedTable{Name: "control_plane_events", Column: "committed_at"}
+	// WebhookDeliveriesTable is webhook_deliveries, partitioned on
+	// delivered_at.
+	WebhookDeliveriesTable = PartitionedTable{Name: "webhook_deliveries", Column: "delivered_at"}
+)
+
+// PartitionedTables returns every day-partitioned table.
+func PartitionedTables() []PartitionedTable {
+	return []PartitionedTable{ControlPlaneEventsTable, WebhookDeliveriesTable}
+}
+
+// PartitionName returns the name of t's partition holding rows from the
+// UTC day containing at.
+func (t PartitionedTable) PartitionName(at time.Time) string {
+	return t.Name + "_p" + partitionDay(at).Format(partitionDayLayout)
+}
+
+// partitionDayOf parses a name built by PartitionName back to its day. ok
+// is false for any other child, such as the DEFAULT partition.
+func (t PartitionedTable) partitionDayOf(name string) (day time.Time, ok bool) {
+	suffix, found := strings.CutPrefix(name, t.Name+"_p")
+	if !found || len(suffix) != len(partitionDayLayout) {
+		return time.Time{}, false
+	}
+	day, err := time.Parse(partitionDayLayout, suffix)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+// partitionDay truncates at to the start of its UTC day.
+func partitionDay(at time.Time) time.Time {
+	y, m, d := at.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// PartitionManager creates and drops the day partitions of the
+// partitioned tables. Every method is a no-op for a table that is not
+// partitioned, so it is safe against a schema older than migration 015.
+type PartitionManager struct {
+	pool   *pgxpool.Pool
+	schema pkgdb.Schema
+}
+
+// NewPartitionManager constructs a partition manager for the tables in
+// schema.
+func NewPartitionManager(pool *pgxpool.Pool, schema pkgdb.Schema) *PartitionManager {
+	return &PartitionManager{pool: pool, schema: schema}
+}
+
+// EnsurePartitions creates every partitioned table's partitions for the
+// day containing now through daysAhead days later, returning how many it
+// created. Rows the DEFAULT partition already holds for one of those days
+// move into the new partition.
+func (m *PartitionManager) EnsurePartitions(ctx context.Context, now time.Time, daysAhead int) (int, error) {
+	if m == nil || m.pool == nil {
+		return 0, errors.New("v1alpha1 store: partition manager has nil pool")
+	}
+	if daysAhead < 0 {
+		daysAhead = 0
+	}
+	var created int
+	for _, t := range PartitionedTables() {
+		partitioned, err := m.isPartitioned(ctx, t)
+		if err != nil {
+			return created, err
+		}
+		if !partitioned {
+			continue
+		}
+		for i := 0; i <= daysAhead; i++ {
+			ok, err := m.ensurePartition(ctx, t, partitionDay(now).AddDate(0, 0, i))
+			if err != nil {
+				return created, err
+			}
+			if ok {
+				created++
+			}
+		}
+	}
+	return created, nil
+}
+
+// DropPartitionsBefore drops t's day partitions that end at or before
+// before, returning how many rows they held. keep, when non-nil, is asked
+// about each candidate inside the dropping transaction and vetoes the drop
+// by returning true. Rows in the DEFAULT partition are never touched; the
+// caller's row-level DELETE covers them.
+func (m *PartitionManager) DropPartitionsBefore(
+	ctx context.Context,
+	t PartitionedTable,
+	before time.Time,
+	keep func(ctx context.Context, tx pgx.Tx, partition string) (bool, error),
+) (int64, error) {
+	if m == nil || m.pool == nil {
+		return 0, errors.New("v1alpha1 store: partition manager has nil pool")
+	}
+	names, err := m.partitions(ctx, t)
+	if err != nil {
+		return 0, err
+	}
+	var dropped int64
+	for _, name := range names {
+		day, ok := t.partitionDayOf(name)
+		if !ok || day.AddDate(0, 0, 1).After(before) {
+			continue
+		}
+		qualified := m.schema.Qualify(name)
+		err := runInTx(ctx, m.pool, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, qualified); err != nil {
+				return fmt.Errorf("lock partition %s: %w", name, err)
+			}
+			var exists bool
+			if err := tx.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`, qualified).Scan(&exists); err != nil {
+				return fmt.Errorf("look up partition %s: %w", name, err)
+			}
+			if !exists {
+				return nil
+			}
+			if keep != nil {
+				kept, err := keep(ctx, tx, qualified)
+				if err != nil {
+					return err
+				}
+				if kept {
+					return nil
+				}
+			}
+			var rows int64
+			if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM `+qualified).Scan(&rows); err != nil {
+				return fmt.Errorf("count partition %s: %w", name, err)
+			}
+			if _, err := tx.Exec(ctx, `DROP TABLE `+qualified); err != nil {
+				return fmt.Errorf("drop partition %s: %w", name, err)
+			}
+			dropped += rows
+			return nil
+		})
+		if err != nil {
+			return dropped, err
+		}
+	}
+	return dropped, nil
+}
+
+func (m *PartitionManager) isPartitioned(ctx context.Context, t PartitionedTable) (bool, error) {
+	var partitioned bool
+	err := m.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = to_regclass($1))`,
+		m.schema.Qualify(t.Name)).Scan(&partitioned)
+	if err != nil {
+		return false, fmt.Errorf("inspect %s partitioning: %w", t.Name, err)
+	}
+	return partitioned, nil
+}
+
+// partitions lists t's child tables, oldest day first.
+func (m *PartitionManager) partitions(ctx context.Context, t PartitionedTable) ([]string, error) {
+	rows, err := m.pool.Query(ctx, `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = to_regclass($1)
+		ORDER BY c.relname`, m.schema.Qualify(t.Name))
+	if err != nil {
+		return nil, fmt.Errorf("list %s partitions: %w", t.Name, err)
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("read %s partitions: %w", t.Name, err)
+	}
+	return names, nil
+}
+
+// ensurePartition creates t's partition for day unless it exists. The
+// partition is built detached, filled with the day's rows from the DEFAULT
+// partition and then attached, because Postgres refuses to attach a range
+// the DEFAULT partition still holds rows for.
+func (m *PartitionManager) ensurePartition(ctx context.Context, t PartitionedTable, day time.Time) (bool, error) {
+	name := t.PartitionName(day)
+	qualified := m.schema.Qualify(name)
+	parent := m.schema.Qualify(t.Name)
+	defaultPartition := m.schema.Qualify(t.Name + "_default")
+	column := pgx.Identifier{t.Column}.Sanitize()
+	from, to := day, day.AddDate(0, 0, 1)
+
+	var created bool
+	err := runInTx(ctx, m.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, qualified); err != nil {
+			return fmt.Errorf("lock partition %s: %w", name, err)
+		}
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`, qualified).Scan(&exists); err != nil {
+			return fmt.Errorf("look up partition %s: %w", name, err)
+		}
+		if exists {
+			return nil
+		}
+		// Hold off inserts routed to the DEFAULT partition until the
+		// range is attached, so none land between the move and the attach.
+		if _, err := tx.Exec(ctx, `LOCK TABLE `+defaultPartition+` IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+			return fmt.Errorf("lock %s default partition: %w", t.Name, err)
+		}
+		if _, err := tx.Exec(ctx, `CREATE TABLE `+qualified+` (LIKE `+parent+` INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`); err != nil {
+			return fmt.Errorf("create partition %s: %w", name, err)
+		}
+		if _, err := tx.Exec(ctx, `
+			WITH moved AS (
+				DELETE FROM `+defaultPartition+`
+				WHERE `+column+` >= $1 AND `+column+` < $2
+				RETURNING *
+			)
+			INSERT INTO `+qualified+` SELECT * FROM moved`, from, to); err != nil {
+			return fmt.Errorf("move default rows into partition %s: %w", name, err)
+		}
+		// ATTACH PARTITION takes literal bounds, not parameters; both are
+		// formatted from UTC midnights here.
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')`,
+			parent, qualified, from.Format(time.RFC3339), to.Format(time.RFC3339))); err != nil {
+			return fmt.Errorf("attach partition %s: %w", name, err)
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
@@ -0,0 +1,60 @@
+package v1alpha1store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartitionName(t *testing.T) {
+	pst := time.FixedZone("PST", -8*60*60)
+	tests := []struct {
+		name  string
+		table PartitionedTable
+		at    time.Time
+		want  string
+	}{
+		{
+			name:  "midday",
+			table: ControlPlaneEventsTable,
+			at:    time.Date(2026, 10, 17, 12, 30, 0, 0, time.UTC),
+			want:  "control_plane_events_p20261017",
+		},
+		{
+			name:  "midnight starts the day",
+			table: WebhookDeliveriesTable,
+			at:    time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+			want:  "webhook_deliveries_p20260102",
+		},
+		{
+			name:  "days are UTC",
+			table: ControlPlaneEventsTable,
+			at:    time.Date(2026, 12, 31, 20, 0, 0, 0, pst),
+			want:  "control_plane_events_p20270101",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.table.PartitionName(tt.at)
+			if got != tt.want {
+				t.Fatalf("PartitionName() = %q, want %q", got, tt.want)
+			}
+			day, ok := tt.table.partitionDayOf(got)
+			if !ok || !day.Equal(partitionDay(tt.at)) {
+				t.Fatalf("partitionDayOf(%q) = %s, %t; want %s", got, day, ok, partitionDay(tt.at))
+			}
+		})
+	}
+}
+
+func TestPartitionDayOfRejectsOtherChildren(t *testing.T) {
+	for _, name := range []string{
+		"control_plane_events_default",
+		"control_plane_events_p2026101",
+		"control_plane_events_p20261399",
+		"webhook_deliveries_p20261017",
+	} {
+		if day, ok := ControlPlaneEventsTable.partitionDayOf(name); ok {
+			t.Fatalf("partitionDayOf(%q) = %s, want not a day partition", name, day)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package v1alpha1store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// Prompt evaluation triggers.
+const (
+	PromptEvalTriggerPublish = "publish"
+	PromptEvalTriggerManual  = "manual"
+)
+
+// PromptEvaluation is one run of a Prompt tag's evaluation suite
+// (migration 030). Score is nil when the run failed with Error.
+type PromptEvaluation struct {
+	ID          int64
+	Namespace   string
+	Name        string
+	Tag         string
+	Model       string
+	Trigger     string
+	RequestedBy string
+	Score       *float64
+	Results     []PromptCaseResult
+	Error       string
+	CreatedAt   time.Time
+}
+
+// PromptCaseResult is how one evaluation case scored.
+type PromptCaseResult struct {
+	Name   string              `json:"name"`
+	Score  float64             `json:"score"`
+	Answer string              `json:"answer,omitempty"`
+	Checks []PromptCheckResult `json:"checks,omitempty"`
+	Error  string              `json:"error,omitempty"`
+}
+
+// PromptCheckResult is one assertion or rubric of a case. Score is 0 or 1
+// for an assertion and the grade over 10 for a rubric.
+type PromptCheckResult struct {
+	Check  string  `json:"check"`
+	Score  float64 `json:"score"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// PromptEvaluationStore keeps the append-only score history of Prompts.
+type PromptEvaluationStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewPromptEvaluationStore constructs a prompt evaluation store.
+func NewPromptEvaluationStore(pool *pgxpool.Pool, schema pkgdb.Schema) *PromptEvaluationStore {
+	return &PromptEvaluationStore{
+		pool:      pool,
+		qualified: schema.Qualify("prompt_evaluations"),
+	}
+}
+
+// Record saves e and returns it with its ID and CreatedAt set.
+func (s *PromptEvaluationStore) Record(ctx context.Context, e PromptEvaluation) (*PromptEvaluation, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: prompt evaluation store has nil pool")
+	}
+	results := e.Results
+	if results == nil {
+		results = []PromptCaseResult{}
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("encode prompt evaluation results: %w", err)
+	}
+	out := e
+	out.Results = results
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO `+s.qualified+` (namespace, name, tag, model, trigger, requested_by, score, results, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at`,
+		e.Namespace, e.Name, e.Tag, e.Model, e.Trigger, e.RequestedBy, e.Score, data, e.Error).Scan(&out.ID, &out.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("record prompt evaluation %s/%s@%s: %w", e.Namespace, e.Name, e.Tag, err)
+	}
+	return &out, nil
+}
+
+// History returns the Prompt's evaluations, newest first, limited to tag
+// when it is non-empty and to at most limit rows when limit is positive.
+func (s *PromptEvaluationStore) History(ctx context.Context, namespace, name, tag string, limit int) ([]PromptEvaluation, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: prompt evaluation store has nil pool")
+	}
+	query := `
+		SELECT id, tag, model, trigger, requested_by, score, results, error, created_at
+		FROM ` + s.qualified + `
+		WHERE namespace = $1 AND name = $2 AND ($3 = '' OR tag = $3)
+		ORDER BY id DESC`
+	args := []any{namespace, name, tag}
+	if limit > 0 {
+		query += ` LIMIT $4`
+		args = append(args, limit)
+	}
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list prompt evaluations %s/%s: %w", namespace, name, err)
+	}
+	defer rows.Close()
+	var out []PromptEvaluation
+	for rows.Next() {
+		e := PromptEvaluation{Namespace: namespace, Name: name}
+		var results []byte
+		if err := rows.Scan(&e.ID, &e.Tag, &e.Model, &e.Trigger, &e.RequestedBy, &e.Score, &results, &e.Error, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan prompt evaluation: %w", err)
+		}
+		if err := json.Unmarshal(results, &e.Results); err != nil {
+			return nil, fmt.Errorf("decode prompt evaluation results %s/%s: %w", namespace, name, err)
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list prompt evaluations %s/%s: %w", namespace, name, err)
+	}
+	return out, nil
+}
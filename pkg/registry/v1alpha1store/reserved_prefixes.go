@@ -0,0 +1,284 @@
+package v1alpha1store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// Reserved prefix request states.
+const (
+	PrefixRequestPending  = "pending"
+	PrefixRequestApproved = "approved"
+	PrefixRequestRejected = "rejected"
+)
+
+// ReservedPrefix is one admin-managed reserved name prefix (migration
+// 020). Owners may publish names under it.
+type ReservedPrefix struct {
+	Prefix    string
+	Reason    string
+	Owners    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IsOwner reports whether subject is allocated the prefix.
+func (p *ReservedPrefix) IsOwner(subject string) bool {
+	return p != nil && subject != "" && slices.Contains(p.Owners, subject)
+}
+
+// PrefixRequest is one caller's request to be allocated a reserved
+// prefix.
+type PrefixRequest struct {
+	ID            int64
+	Prefix        string
+	Requester     string
+	Justification string
+	Status        string
+	DecidedBy     string
+	DecidedAt     *time.Time
+	CreatedAt     time.Time
+}
+
+// ReservedPrefixStore reads and writes reserved prefixes and allocation
+// requests.
+type ReservedPrefixStore struct {
+	pool              *pgxpool.Pool
+	qualifiedPrefixes string
+	qualifiedRequests string
+}
+
+// NewReservedPrefixStore constructs a reserved prefix store.
+func NewReservedPrefixStore(pool *pgxpool.Pool, schema pkgdb.Schema) *ReservedPrefixStore {
+	return &ReservedPrefixStore{
+		pool:              pool,
+		qualifiedPrefixes: schema.Qualify("reserved_prefixes"),
+		qualifiedRequests: schema.Qualify("reserved_prefix_requests"),
+	}
+}
+
+const (
+	reservedPrefixColumns = `prefix, reason, owners, created_at, updated_at`
+	prefixRequestColumns  = `id, prefix, requester, justification, status, decided_by, decided_at, created_at`
+)
+
+// Get returns the reserved prefix row for prefix, or pkgdb.ErrNotFound.
+func (s *ReservedPrefixStore) Get(ctx context.Context, prefix string) (*ReservedPrefix, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: reserved prefix store has nil pool")
+	}
+	row := s.pool.QueryRow(ctx, `SELECT `+reservedPrefixColumns+` FROM `+s.qualifiedPrefixes+` WHERE prefix = $1`, prefix)
+	return reservedPrefixOrNotFound(row, prefix, "get")
+}
+
+// List returns every reserved prefix row, ordered by prefix.
+func (s *ReservedPrefixStore) List(ctx context.Context) ([]*ReservedPrefix, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: reserved prefix store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `SELECT `+reservedPrefixColumns+` FROM `+s.qualifiedPrefixes+` ORDER BY prefix`)
+	if err != nil {
+		return nil, fmt.Errorf("list reserved prefixes: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*ReservedPrefix
+	for rows.Next() {
+		p, err := scanReservedPrefix(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan reserved prefix: %w", err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read reserved prefixes: %w", err)
+	}
+	return out, nil
+}
+
+// Put creates or replaces the reserved prefix row for prefix.
+func (s *ReservedPrefixStore) Put(ctx context.Context, prefix, reason string, owners []string) (*ReservedPrefix, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: reserved prefix store has nil pool")
+	}
+	if owners == nil {
+		owners = []string{}
+	}
+	raw, err := json.Marshal(owners)
+	if err != nil {
+		return nil, fmt.Errorf("encode reserved prefix owners: %w", err)
+	}
+	row := s.pool.QueryRow(ctx, `
+		INSERT INTO `+s.qualifiedPrefixes+` (prefix, reason, owners)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (prefix) DO UPDATE SET reason = EXCLUDED.reason, owners = EXCLUDED.owners
+		RETURNING `+reservedPrefixColumns, prefix, reason, raw)
+	return reservedPrefixOrNotFound(row, prefix, "put")
+}
+
+// Delete removes the reserved prefix row for prefix. Its requests are
+// kept as history.
+func (s *ReservedPrefixStore) Delete(ctx context.Context, prefix string) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: reserved prefix store has nil pool")
+	}
+	cmdTag, err := s.pool.Exec(ctx, `DELETE FROM `+s.qualifiedPrefixes+` WHERE prefix = $1`, prefix)
+	if err != nil {
+		return fmt.Errorf("delete reserved prefix %s: %w", prefix, err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return pkgdb.ErrNotFound
+	}
+	return nil
+}
+
+// CreateRequest records a pending request by requester to be allocated
+// prefix. A second pending request by the same requester for the same
+// prefix returns an error matching pkgdb.ErrAlreadyExists.
+func (s *ReservedPrefixStore) CreateRequest(ctx context.Context, prefix, requester, justification string) (*PrefixRequest, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: reserved prefix store has nil pool")
+	}
+	row := s.pool.QueryRow(ctx, `
+		INSERT INTO `+s.qualifiedRequests+` (prefix, requester, justification)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (prefix, requester) WHERE status = 'pending' DO NOTHING
+		RETURNING `+prefixRequestColumns, prefix, requester, justification)
+	req, err := scanPrefixRequest(row)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("%w: %s already has a pending request for %s", pkgdb.ErrAlreadyExists, requester, prefix)
+	case err != nil:
+		return nil, fmt.Errorf("request reserved prefix %s: %w", prefix, err)
+	}
+	return req, nil
+}
+
+// ListRequests returns allocation requests, newest first. A non-empty
+// requester limits them to that caller's.
+func (s *ReservedPrefixStore) ListRequests(ctx context.Context, requester string) ([]*PrefixRequest, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: reserved prefix store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+prefixRequestColumns+` FROM `+s.qualifiedRequests+`
+		WHERE $1 = '' OR requester = $1
+		ORDER BY created_at DESC, id DESC`, requester)
+	if err != nil {
+		return nil, fmt.Errorf("list reserved prefix requests: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*PrefixRequest
+	for rows.Next() {
+		req, err := scanPrefixRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan reserved prefix request: %w", err)
+		}
+		out = append(out, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read reserved prefix requests: %w", err)
+	}
+	return out, nil
+}
+
+// DecideRequest approves or rejects the pending request id on behalf of
+// decidedBy. Approving adds the requester to the prefix's owners,
+// creating its row if only the server configuration reserves it. Returns
+// pkgdb.ErrNotFound when id names no pending request.
+func (s *ReservedPrefixStore) DecideRequest(ctx context.Context, id int64, approve bool, decidedBy string) (*PrefixRequest, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: reserved prefix store has nil pool")
+	}
+	status := PrefixRequestRejected
+	if approve {
+		status = PrefixRequestApproved
+	}
+	var out *PrefixRequest
+	err := runInTx(ctx, s.pool, func(tx pgx.Tx) error {
+		row := tx.QueryRow(ctx, `
+			UPDATE `+s.qualifiedRequests+`
+			SET status = $2, decided_by = $3, decided_at = NOW()
+			WHERE id = $1 AND status = 'pending'
+			RETURNING `+prefixRequestColumns, id, status, decidedBy)
+		req, err := scanPrefixRequest(row)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return pkgdb.ErrNotFound
+		case err != nil:
+			return fmt.Errorf("decide reserved prefix request %d: %w", id, err)
+		}
+		out = req
+		if !approve {
+			return nil
+		}
+		owner, err := json.Marshal([]string{req.Requester})
+		if err != nil {
+			return fmt.Errorf("encode reserved prefix owner: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO `+s.qualifiedPrefixes+` (prefix, owners)
+			VALUES ($1, $2)
+			ON CONFLICT (prefix) DO UPDATE SET owners = `+s.qualifiedPrefixes+`.owners || EXCLUDED.owners
+			WHERE NOT `+s.qualifiedPrefixes+`.owners @> EXCLUDED.owners`, req.Prefix, owner); err != nil {
+			return fmt.Errorf("allocate reserved prefix %s: %w", req.Prefix, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func reservedPrefixOrNotFound(row pgx.Row, prefix, op string) (*ReservedPrefix, error) {
+	p, err := scanReservedPrefix(row)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, pkgdb.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("%s reserved prefix %s: %w", op, prefix, err)
+	}
+	return p, nil
+}
+
+func scanReservedPrefix(row pgx.Row) (*ReservedPrefix, error) {
+	var (
+		p      ReservedPrefix
+		owners []byte
+	)
+	if err := row.Scan(&p.Prefix, &p.Reason, &owners, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(owners, &p.Owners); err != nil {
+		return nil, fmt.Errorf("decode owners of reserved prefix %s: %w", p.Prefix, err)
+	}
+	return &p, nil
+}
+
+func scanPrefixRequest(row pgx.Row) (*PrefixRequest, error) {
+	var req PrefixRequest
+	if err := row.Scan(
+		&req.ID,
+		&req.Prefix,
+		&req.Requester,
+		&req.Justification,
+		&req.Status,
+		&req.DecidedBy,
+		&req.DecidedAt,
+		&req.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
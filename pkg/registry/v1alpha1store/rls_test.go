@@ -0,0 +1,78 @@
+//go:build integration
+
+package v1alpha1store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// TestNamespaceRLS exercises the namespace_scope policies from migration
+// 011 as a non-superuser role (superusers bypass RLS): an unset scope sees
+// every namespace, a scoped session sees and writes only its own.
+func TestNamespaceRLS(t *testing.T) {
+	pool := NewTestPool(t)
+	store := NewStore(pool, TestSchema(), testTable)
+	ctx := context.Background()
+
+	for _, ns := range []string{"team-a", "team-b"} {
+		_, err := store.Upsert(ctx, &v1alpha1.Agent{
+			Metadata: v1alpha1.ObjectMeta{Namespace: ns, Name: "rls"},
+			Spec:     v1alpha1.AgentSpec{Title: "RLS"},
+		})
+		require.NoError(t, err)
+	}
+
+	role := fmt.Sprintf("rls_test_%d", time.Now().UnixNano())
+	schema := TestSchema().Quoted()
+	for _, stmt := range []string{
+		"CREATE ROLE " + role + " NOLOGIN",
+		"GRANT USAGE ON SCHEMA " + schema + " TO " + role,
+		"GRANT ALL ON ALL TABLES IN SCHEMA " + schema + " TO " + role,
+		"GRANT USAGE ON ALL SEQUENCES IN SCHEMA " + schema + " TO " + role,
+	} {
+		_, err := pool.Exec(ctx, stmt)
+		require.NoError(t, err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), "DROP OWNED BY "+role)
+		_, _ = pool.Exec(context.Background(), "DROP ROLE IF EXISTS "+role)
+	})
+
+	asRole := func(scope string, fn func(tx pgx.Tx) error) error {
+		tx, err := pool.Begin(ctx)
+		require.NoError(t, err)
+		defer func() { _ = tx.Rollback(ctx) }()
+		_, err = tx.Exec(ctx, "SET LOCAL ROLE "+role)
+		require.NoError(t, err)
+		_, err = tx.Exec(ctx, "SELECT set_config('registry.namespace_scope', $1, true)", scope)
+		require.NoError(t, err)
+		return fn(tx)
+	}
+	countAgents := func(scope string) int {
+		var n int
+		require.NoError(t, asRole(scope, func(tx pgx.Tx) error {
+			return tx.QueryRow(ctx, "SELECT count(*) FROM agents WHERE name = 'rls'").Scan(&n)
+		}))
+		return n
+	}
+
+	require.Equal(t, 2, countAgents(""))
+	require.Equal(t, 1, countAgents("team-a"))
+	require.Equal(t, 2, countAgents("team-a,team-b"))
+	require.Equal(t, 0, countAgents("team-c"))
+
+	err := asRole("team-a", func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `INSERT INTO agents (namespace, name, tag, spec, content_hash)
+			VALUES ('team-b', 'sneaky', 'v1', '{}', repeat('0', 64))`)
+		return err
+	})
+	require.ErrorContains(t, err, "row-level security")
+}
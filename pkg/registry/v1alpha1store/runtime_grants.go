@@ -0,0 +1,143 @@
+package v1alpha1store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// RuntimeGrant restricts deploys to one Runtime (migration 033) to its
+// Deployers.
+type RuntimeGrant struct {
+	Namespace string
+	Runtime   string
+	Deployers []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IsDeployer reports whether subject may deploy to the Runtime.
+func (g *RuntimeGrant) IsDeployer(subject string) bool {
+	return g != nil && subject != "" && slices.Contains(g.Deployers, subject)
+}
+
+// RuntimeGrantStore reads and writes Runtime deploy grants.
+type RuntimeGrantStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewRuntimeGrantStore constructs a Runtime grant store.
+func NewRuntimeGrantStore(pool *pgxpool.Pool, schema pkgdb.Schema) *RuntimeGrantStore {
+	return &RuntimeGrantStore{pool: pool, qualified: schema.Qualify("runtime_grants")}
+}
+
+const runtimeGrantColumns = `namespace, runtime, deployers, created_at, updated_at`
+
+// Get returns the grant of the Runtime namespace/runtime, or
+// pkgdb.ErrNotFound when the Runtime is unrestricted.
+func (s *RuntimeGrantStore) Get(ctx context.Context, namespace, runtime string) (*RuntimeGrant, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: runtime grant store has nil pool")
+	}
+	row := s.pool.QueryRow(ctx, `SELECT `+runtimeGrantColumns+` FROM `+s.qualified+` WHERE namespace = $1 AND runtime = $2`, namespace, runtime)
+	return runtimeGrantOrNotFound(row, namespace, runtime, "get")
+}
+
+// List returns every grant, ordered by namespace and Runtime. A non-empty
+// namespace limits them to that namespace's Runtimes.
+func (s *RuntimeGrantStore) List(ctx context.Context, namespace string) ([]*RuntimeGrant, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: runtime grant store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+runtimeGrantColumns+` FROM `+s.qualified+`
+		WHERE $1 = '' OR namespace = $1
+		ORDER BY namespace, runtime`, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("list runtime grants: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*RuntimeGrant
+	for rows.Next() {
+		g, err := scanRuntimeGrant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan runtime grant: %w", err)
+		}
+		out = append(out, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read runtime grants: %w", err)
+	}
+	return out, nil
+}
+
+// Put creates or replaces the grant of the Runtime namespace/runtime.
+func (s *RuntimeGrantStore) Put(ctx context.Context, namespace, runtime string, deployers []string) (*RuntimeGrant, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: runtime grant store has nil pool")
+	}
+	if deployers == nil {
+		deployers = []string{}
+	}
+	raw, err := json.Marshal(deployers)
+	if err != nil {
+		return nil, fmt.Errorf("encode runtime grant deployers: %w", err)
+	}
+	row := s.pool.QueryRow(ctx, `
+		INSERT INTO `+s.qualified+` (namespace, runtime, deployers)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (namespace, runtime) DO UPDATE SET deployers = EXCLUDED.deployers
+		RETURNING `+runtimeGrantColumns, namespace, runtime, raw)
+	return runtimeGrantOrNotFound(row, namespace, runtime, "put")
+}
+
+// Delete removes the grant of the Runtime namespace/runtime, leaving it
+// unrestricted. Returns pkgdb.ErrNotFound when it had none.
+func (s *RuntimeGrantStore) Delete(ctx context.Context, namespace, runtime string) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: runtime grant store has nil pool")
+	}
+	cmdTag, err := s.pool.Exec(ctx, `DELETE FROM `+s.qualified+` WHERE namespace = $1 AND runtime = $2`, namespace, runtime)
+	if err != nil {
+		return fmt.Errorf("delete runtime grant %s/%s: %w", namespace, runtime, err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return pkgdb.ErrNotFound
+	}
+	return nil
+}
+
+func runtimeGrantOrNotFound(row pgx.Row, namespace, runtime, op string) (*RuntimeGrant, error) {
+	g, err := scanRuntimeGrant(row)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, pkgdb.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("%s runtime grant %s/%s: %w", op, namespace, runtime, err)
+	}
+	return g, nil
+}
+
+func scanRuntimeGrant(row pgx.Row) (*RuntimeGrant, error) {
+	var (
+		g         RuntimeGrant
+		deployers []byte
+	)
+	if err := row.Scan(&g.Namespace, &g.Runtime, &deployers, &g.CreatedAt, &g.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(deployers, &g.Deployers); err != nil {
+		return nil, fmt.Errorf("decode deployers of runtime grant %s/%s: %w", g.Namespace, g.Runtime, err)
+	}
+	return &g, nil
+}
@@ -0,0 +1,160 @@
+package v1alpha1store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+)
+
+// searchConfig is the text search configuration documents and queries are
+// parsed with.
+const searchConfig = "english"
+
+// SearchOpts narrows a Search.
+type SearchOpts struct {
+	// Query is free text in websearch syntax: bare words are ANDed,
+	// "quoted phrases" match in order, `or` and `-word` work as on web
+	// search engines.
+	Query string
+	// Namespace narrows results to one namespace. Empty searches all.
+	Namespace string
+	// Limit caps the number of hits. Zero means default (50).
+	Limit int
+	// ExtraWhere and ExtraArgs follow the ListOpts contract.
+	ExtraWhere string
+	ExtraArgs  []any
+}
+
+// SearchHit is one latest tag matched by Search.
+type SearchHit struct {
+	Object *v1alpha1.RawObject
+	// Rank is the full-text rank of the row's document against the query;
+	// zero when only the name matched.
+	Rank float64
+	// NameMatch reports that the query appears in the name as typed.
+	NameMatch bool
+	// ReadmeMatch reports that the README alone satisfies the query, so
+	// the artifact documents what was searched for.
+	ReadmeMatch bool
+	// Highlight is an excerpt of the title, description and README with
+	// the matched terms wrapped in <mark></mark>; empty when only the name
+	// matched.
+	Highlight string
+}
+
+// Search matches the latest tag of every live artifact against a free-text
+// query. A row matches when its document — name, then spec.title and
+// spec.description, then its README (spec.readme and any uploaded
+// README) — satisfies the query, or when its name contains the query as a
+// substring. Hits are ordered by full-text rank, then by name; callers
+// that blend in other rankings re-order them.
+//
+// The document is built per query rather than indexed, as it spans the
+// README side table; registry catalogues are small enough that a scan of
+// the latest tags is cheap. Uploaded READMEs, the longest part, are read
+// pre-parsed from their stored tsvector (migration 026).
+func (s *Store) Search(ctx context.Context, opts SearchOpts) ([]SearchHit, error) {
+	if s.behavior != TaggedArtifactStore {
+		return nil, errors.New("v1alpha1 store: search requires a tagged artifact store")
+	}
+	query := strings.TrimSpace(opts.Query)
+	if query == "" {
+		return nil, nil
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	args := []any{query, s.kind, "%" + query + "%", DefaultTag()}
+	where := []string{"tag = $4", "deletion_timestamp IS NULL", "(doc @@ q OR name ILIKE $3)"}
+	if opts.Namespace != "" {
+		args = append(args, opts.Namespace)
+		where = append(where, fmt.Sprintf("namespace = $%d", len(args)))
+	}
+	if opts.ExtraWhere != "" || len(opts.ExtraArgs) > 0 {
+		placeholders := countDistinctPlaceholders(opts.ExtraWhere)
+		if placeholders != len(opts.ExtraArgs) {
+			return nil, fmt.Errorf("%w: fragment references %d distinct placeholder(s) but %d arg(s) supplied",
+				ErrInvalidExtraWhere, placeholders, len(opts.ExtraArgs))
+		}
+		args = append(args, opts.ExtraArgs...)
+		if opts.ExtraWhere != "" {
+			where = append(where, rebaseSQLPlaceholders(opts.ExtraWhere, len(args)-len(opts.ExtraArgs)))
+		}
+	}
+	args = append(args, limit)
+
+	// Names are split on separators so "weather-forecast" matches
+	// "forecast". Weights rank name hits above title and description hits,
+	// and those above README hits.
+	sql := fmt.Sprintf(`
+		SELECT %[1]s,
+		       ts_rank_cd(doc, q) AS rank,
+		       name ILIKE $3 AS name_match,
+		       readme_doc @@ q AS readme_match,
+		       CASE WHEN doc @@ q
+		            THEN ts_headline('%[4]s', body, q, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5')
+		            ELSE '' END AS highlight
+		FROM (
+			SELECT readmes.*,
+			       setweight(to_tsvector('%[4]s', translate(name, '-_./', '    ')), 'A') ||
+			       setweight(to_tsvector('%[4]s', concat_ws(' ', spec->>'title', spec->>'description')), 'B') ||
+			       setweight(readme_doc, 'C') AS doc
+			FROM (
+				SELECT t.*,
+				       concat_ws(' ', t.spec->>'title', t.spec->>'description', t.spec->>'readme', r.content) AS body,
+				       to_tsvector('%[4]s', coalesce(t.spec->>'readme', '')) ||
+				       coalesce(r.search_vector, ''::tsvector) AS readme_doc
+				FROM %[2]s t
+				LEFT JOIN %[3]s r
+				       ON r.kind = $2 AND r.namespace = t.namespace AND r.name = t.name
+				      AND r.tag = t.tag AND r.artifact_uid = t.uid
+			) readmes
+		) docs, websearch_to_tsquery('%[4]s', $1) q
+		WHERE %[5]s
+		ORDER BY rank DESC, namespace, name
+		LIMIT $%[6]d`,
+		s.selectColumns(), s.qualified, s.readmes, searchConfig, strings.Join(where, " AND "), len(args))
+
+	rows, err := s.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+	defer rows.Close()
+
+	var out []SearchHit
+	for rows.Next() {
+		var hit SearchHit
+		obj, err := scanRow(searchRow{rows: rows, hit: &hit}, true)
+		if err != nil {
+			return nil, err
+		}
+		hit.Object = obj
+		out = append(out, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// searchRow lets scanRow read the object columns of a Search row while the
+// trailing rank, name match, README match and highlight columns land on
+// hit.
+type searchRow struct {
+	rows rowScanner
+	hit  *SearchHit
+}
+
+func (r searchRow) Scan(dest ...any) error {
+	var rank float32
+	if err := r.rows.Scan(append(dest, &rank, &r.hit.NameMatch, &r.hit.ReadmeMatch, &r.hit.Highlight)...); err != nil {
+		return err
+	}
+	r.hit.Rank = float64(rank)
+	return nil
+}
@@ -0,0 +1,172 @@
+package v1alpha1store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/agentregistry-dev/agentregistry/pkg/api/v1alpha1"
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// ToolHit is one tool of a latest MCPServer tag matched by SearchTools.
+type ToolHit struct {
+	Namespace   string
+	Name        string
+	Tag         string
+	Tool        string
+	Description string
+	// Rank is the full-text rank of the tool's name and description
+	// against the query; zero when only the tool name matched as typed.
+	Rank float64
+}
+
+// ServerToolStore reads the tools MCPServer tags declare in spec.tools,
+// which migration 031 copies into server_tools on every publish.
+type ServerToolStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+	servers   string
+}
+
+// NewServerToolStore constructs a server tool store.
+func NewServerToolStore(pool *pgxpool.Pool, schema pkgdb.Schema) *ServerToolStore {
+	return &ServerToolStore{
+		pool:      pool,
+		qualified: schema.Qualify("server_tools"),
+		servers:   schema.Qualify("mcp_servers"),
+	}
+}
+
+// Tools returns the tools of the MCPServer tag in the order the server
+// lists them, or pkgdb.ErrNotFound when the tag does not exist or is being
+// deleted. A tag that declares no tools returns an empty slice.
+func (s *ServerToolStore) Tools(ctx context.Context, namespace, name, tag string) ([]v1alpha1.MCPTool, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: server tool store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT t.tool_name, t.description, t.input_schema
+		FROM `+s.servers+` s
+		LEFT JOIN `+s.qualified+` t
+		       ON t.namespace = s.namespace AND t.name = s.name AND t.tag = s.tag
+		WHERE s.namespace = $1 AND s.name = $2 AND s.tag = $3 AND s.deletion_timestamp IS NULL
+		ORDER BY t.position`, namespace, name, tag)
+	if err != nil {
+		return nil, fmt.Errorf("list tools %s/%s:%s: %w", namespace, name, tag, err)
+	}
+	defer rows.Close()
+
+	var (
+		found bool
+		out   = []v1alpha1.MCPTool{}
+	)
+	for rows.Next() {
+		found = true
+		var (
+			toolName, description *string
+			schema                []byte
+		)
+		if err := rows.Scan(&toolName, &description, &schema); err != nil {
+			return nil, fmt.Errorf("scan tool: %w", err)
+		}
+		if toolName == nil {
+			continue
+		}
+		tool := v1alpha1.MCPTool{Name: *toolName, Description: *description}
+		if len(schema) > 0 && string(schema) != "null" {
+			if err := json.Unmarshal(schema, &tool.InputSchema); err != nil {
+				return nil, fmt.Errorf("decode input schema of tool %s: %w", tool.Name, err)
+			}
+		}
+		out = append(out, tool)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list tools %s/%s:%s: %w", namespace, name, tag, err)
+	}
+	if !found {
+		return nil, pkgdb.ErrNotFound
+	}
+	return out, nil
+}
+
+// SearchTools matches the tools of every live MCPServer's latest tag
+// against a free-text query. A tool matches when its name or description
+// satisfies the query, or when its name contains the query as a
+// substring. opts.ExtraWhere filters the MCPServer rows the tools belong
+// to, following the ListOpts contract. Hits are ordered by rank, then by
+// server and the tool's position.
+func (s *ServerToolStore) SearchTools(ctx context.Context, opts SearchOpts) ([]ToolHit, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: server tool store has nil pool")
+	}
+	query := strings.TrimSpace(opts.Query)
+	if query == "" {
+		return nil, nil
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	args := []any{query, "%" + query + "%", DefaultTag()}
+	where := []string{"tag = $3", "deletion_timestamp IS NULL"}
+	if opts.Namespace != "" {
+		args = append(args, opts.Namespace)
+		where = append(where, fmt.Sprintf("namespace = $%d", len(args)))
+	}
+	if opts.ExtraWhere != "" || len(opts.ExtraArgs) > 0 {
+		placeholders := countDistinctPlaceholders(opts.ExtraWhere)
+		if placeholders != len(opts.ExtraArgs) {
+			return nil, fmt.Errorf("%w: fragment references %d distinct placeholder(s) but %d arg(s) supplied",
+				ErrInvalidExtraWhere, placeholders, len(opts.ExtraArgs))
+		}
+		args = append(args, opts.ExtraArgs...)
+		if opts.ExtraWhere != "" {
+			where = append(where, rebaseSQLPlaceholders(opts.ExtraWhere, len(args)-len(opts.ExtraArgs)))
+		}
+	}
+	args = append(args, limit)
+
+	// The server filter runs in its own subquery so ExtraWhere's column
+	// names resolve against mcp_servers alone.
+	sql := fmt.Sprintf(`
+		SELECT t.namespace, t.name, t.tag, t.tool_name, t.description,
+		       ts_rank_cd(t.search_vector, q) AS rank
+		FROM %[1]s t
+		JOIN (
+			SELECT namespace, name, tag FROM %[2]s WHERE %[4]s
+		) s ON s.namespace = t.namespace AND s.name = t.name AND s.tag = t.tag,
+		websearch_to_tsquery('%[3]s', $1) q
+		WHERE t.search_vector @@ q OR t.tool_name ILIKE $2
+		ORDER BY rank DESC, t.namespace, t.name, t.position
+		LIMIT $%[5]d`,
+		s.qualified, s.servers, searchConfig, strings.Join(where, " AND "), len(args))
+
+	rows, err := s.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search tools: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ToolHit
+	for rows.Next() {
+		var (
+			hit  ToolHit
+			rank float32
+		)
+		if err := rows.Scan(&hit.Namespace, &hit.Name, &hit.Tag, &hit.Tool, &hit.Description, &rank); err != nil {
+			return nil, fmt.Errorf("scan tool hit: %w", err)
+		}
+		hit.Rank = float64(rank)
+		out = append(out, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -60,6 +60,11 @@ const (
 // exact Get can still load them, while GetLatest/List hide them unless the
 // caller explicitly includes terminating rows. PurgeFinalized removes
 // terminating mutable rows after finalizers are empty.
+//
+// With WithDeletedRetention, tagged-artifact deletes are soft instead: the
+// row keeps its content with deletion_timestamp set, every read except a
+// terminating-inclusive List hides it, Restore brings it back, and
+// PurgeDeletedBefore removes it once the grace period has passed.
 type Store struct {
 	pool *pgxpool.Pool
 	// table is the unqualified table name (e.g. "agents") — the identity
@@ -74,6 +79,12 @@ type Store struct {
 	behavior  StoreBehavior
 	kind      string
 	auditor   types.Auditor
+	// deletedRetention > 0 makes tagged-artifact deletes soft; see
+	// WithDeletedRetention.
+	deletedRetention time.Duration
+	// readmes is the schema-qualified artifact_readmes table Search reads
+	// uploaded READMEs from.
+	readmes string
 }
 
 // Behavior reports which private persistence behavior this Store uses. Generic
@@ -113,6 +124,14 @@ func WithKind(kind string) StoreOption {
 	return func(s *Store) { s.kind = kind }
 }
 
+// WithDeletedRetention makes tagged-artifact deletes soft: deleted tags stay
+// restorable until a purge pass removes rows deleted more than retention
+// ago. Zero or negative keeps the immediate hard delete. Mutable-object
+// stores ignore it.
+func WithDeletedRetention(retention time.Duration) StoreOption {
+	return func(s *Store) { s.deletedRetention = retention }
+}
+
 // NewStore constructs a tagged-artifact Store bound to a single table
 // (e.g. "agents") in schema. The table must exist; NewStore does not
 // validate it. Queries qualify the table with schema explicitly, so the
@@ -120,7 +139,7 @@ func WithKind(kind string) StoreOption {
 //
 // For mutable object tables, use NewMutableObjectStore.
 func NewStore(pool *pgxpool.Pool, schema pkgdb.Schema, table string, opts ...StoreOption) *Store {
-	s := &Store{pool: pool, table: table, qualified: schema.Qualify(table), behavior: TaggedArtifactStore, auditor: types.NoopAuditor}
+	s := &Store{pool: pool, table: table, qualified: schema.Qualify(table), readmes: schema.Qualify("artifact_readmes"), behavior: TaggedArtifactStore, auditor: types.NoopAuditor}
 	for _, opt := range opts {
 		opt(s)
 	}
@@ -168,6 +187,11 @@ type UpsertOpts struct {
 	// InitialFinalizers is applied only on the create path for mutable-object
 	// stores. Updates preserve existing finalizers.
 	InitialFinalizers []string
+	// IfContentHash, when set, makes a tagged-artifact write conditional:
+	// it proceeds only if the existing row's content hash (see ContentHash)
+	// equals it, and fails with ErrPreconditionFailed otherwise, including
+	// when the row does not exist. Ignored by mutable-object stores.
+	IfContentHash string
 }
 
 // ErrInvalidCursor reports that a list pagination cursor could not be parsed.
@@ -186,6 +210,10 @@ var ErrInvalidExtraWhere = errors.New("v1alpha1 store: ExtraWhere / ExtraArgs pl
 // recreate").
 var ErrTerminating = errors.New("v1alpha1 store: object is terminating")
 
+// ErrPreconditionFailed reports that a conditional Upsert
+// (UpsertOpts.IfContentHash) found the row changed or missing.
+var ErrPreconditionFailed = errors.New("v1alpha1 store: precondition failed")
+
 // ListOpts controls paginated list queries.
 type ListOpts struct {
 	// Namespace narrows results to a specific namespace. Empty means "across
@@ -236,12 +264,75 @@ type ListOpts struct {
 	// ExtraArgs are the bind parameters for ExtraWhere. Number of entries
 	// MUST equal the distinct placeholder count in ExtraWhere.
 	ExtraArgs []any
+	// Sort orders rows by an expression ahead of the resource key. Nil
+	// keeps the resource-key order. Cursors only continue the order they
+	// were issued for.
+	Sort *ListSort
+}
+
+// ListSort orders List results by a SQL expression, with the resource key
+// breaking ties.
+type ListSort struct {
+	// Expr is a text-valued SQL expression over the table's columns, such
+	// as "spec->>'title'"; NULL sorts as the empty string. Expr is spliced
+	// into the query, so it must be a constant chosen by the caller —
+	// NEVER request input.
+	Expr string
+	// Desc orders Expr descending. Ties stay in ascending key order.
+	Desc bool
+}
+
+// SortByUpdatedAt lists the most recently updated rows first. Updates,
+// status patches included, move a row, so a row changed while a client
+// pages through the list can be skipped or returned twice.
+var SortByUpdatedAt = &ListSort{
+	Expr: `to_char(updated_at AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US')`,
+	Desc: true,
+}
+
+// SortByDeploymentStatus groups Deployment rows by the phase arctl shows for
+// them, in alphabetical order: deployed, deploying, failed, pending,
+// terminating, undeployed. The phase is derived from the row's conditions
+// as in the CLI's DeploymentStatus; keep the two in step.
+var SortByDeploymentStatus = &ListSort{
+	Expr: `CASE
+		WHEN deletion_timestamp IS NOT NULL THEN 'terminating'
+		WHEN status->'conditions' @> '[{"type":"Ready","status":"True"}]'::jsonb THEN 'deployed'
+		WHEN status->'conditions' @> '[{"type":"Degraded","status":"True"}]'::jsonb THEN 'failed'
+		WHEN spec->>'desiredState' = 'undeployed' THEN 'undeployed'
+		WHEN status->'conditions' @> '[{"type":"Progressing"}]'::jsonb
+			AND NOT status->'conditions' @> '[{"type":"Progressing","status":"False"}]'::jsonb THEN 'deploying'
+		WHEN status->'conditions' @> '[{"type":"RuntimeConfigured","status":"True"}]'::jsonb THEN 'deploying'
+		ELSE 'pending'
+	END`,
+}
+
+// id fingerprints the sort so a cursor issued under one order is rejected
+// under another.
+func (s *ListSort) id() string {
+	if s == nil {
+		return ""
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s.Expr))
+	if s.Desc {
+		_, _ = h.Write([]byte{0})
+	}
+	return strconv.FormatUint(uint64(h.Sum32()), 36)
+}
+
+// sortKeyExpr is the text sort key List selects and compares for s.
+func (s *ListSort) sortKeyExpr() string {
+	return "COALESCE((" + s.Expr + ")::text, '')"
 }
 
 // listCursor is the opaque pagination position for List. Tagged-artifact
 // stores include Tag because their sort key is (namespace, name, tag,
 // updated_at); mutable-object stores sort by (namespace, name, updated_at).
+// Sorted lists also record the sort and the row's sort key.
 type listCursor struct {
+	Sort      string    `json:"sort,omitempty"`
+	SortKey   string    `json:"sortKey,omitempty"`
 	Namespace string    `json:"namespace"`
 	Name      string    `json:"name"`
 	Tag       string    `json:"tag,omitempty"`
@@ -269,6 +360,9 @@ func (s *Store) Upsert(ctx context.Context, obj v1alpha1.Object, opts ...UpsertO
 	if meta == nil || meta.Namespace == "" || meta.Name == "" {
 		return UpsertResult{}, errors.New("v1alpha1 store: namespace and name are required")
 	}
+	// Rows are stored under their canonical identity; the
+	// (namespace, lower(name)) unique indexes reject any other spelling.
+	meta.Namespace, meta.Name = v1alpha1.CanonicalName(meta.Namespace), v1alpha1.CanonicalName(meta.Name)
 	specJSON, err := obj.MarshalSpec()
 	if err != nil {
 		return UpsertResult{}, fmt.Errorf("v1alpha1 store: marshal spec: %w", err)
@@ -283,7 +377,7 @@ func (s *Store) Upsert(ctx context.Context, obj v1alpha1.Object, opts ...UpsertO
 	}
 
 	if s.behavior == TaggedArtifactStore {
-		res, err := s.upsertTagged(ctx, meta, specJSON)
+		res, err := s.upsertTagged(ctx, meta, specJSON, opt.IfContentHash)
 		if err != nil {
 			return res, err
 		}
@@ -296,7 +390,17 @@ func (s *Store) Upsert(ctx context.Context, obj v1alpha1.Object, opts ...UpsertO
 		}
 		return res, nil
 	}
-	return s.upsertMutable(ctx, meta, specJSON, opt)
+	res, err := s.upsertMutable(ctx, meta, specJSON, opt)
+	if err != nil {
+		return res, err
+	}
+	// Same post-commit rule for mutable kinds, for auditors that opt in.
+	if res.Outcome == UpsertCreated {
+		if oa, ok := s.auditor.(types.ObjectAuditor); ok {
+			oa.ObjectCreated(ctx, s.kindFor(obj), meta.Namespace, meta.Name)
+		}
+	}
+	return res, nil
 }
 
 // kindFor returns the canonical Kind name to attach to audit events.
@@ -312,7 +416,7 @@ func (s *Store) kindFor(obj v1alpha1.Object) string {
 
 // upsertTagged implements the tag apply semantics for tagged artifact tables.
 // See Upsert for the full state machine.
-func (s *Store) upsertTagged(ctx context.Context, meta *v1alpha1.ObjectMeta, specJSON json.RawMessage) (UpsertResult, error) {
+func (s *Store) upsertTagged(ctx context.Context, meta *v1alpha1.ObjectMeta, specJSON json.RawMessage, ifContentHash string) (UpsertResult, error) {
 	if meta.Tag == "" {
 		meta.Tag = DefaultTag()
 	}
@@ -338,7 +442,8 @@ func (s *Store) upsertTagged(ctx context.Context, meta *v1alpha1.ObjectMeta, spe
 		// An advisory transaction lock serializes the entire
 		// (lookup, insert) decision per resource name. The lock auto-releases
 		// at COMMIT/ROLLBACK because we use pg_advisory_xact_lock.
-		key := s.advisoryLockKey(s.table, meta.Namespace, meta.Name)
+		// Keyed by the lower-cased name so case variants serialize too.
+		key := s.advisoryLockKey(s.table, meta.Namespace, strings.ToLower(meta.Name))
 		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, key); err != nil {
 			return fmt.Errorf("advisory lock: %w", err)
 		}
@@ -366,10 +471,12 @@ func (s *Store) upsertTagged(ctx context.Context, meta *v1alpha1.ObjectMeta, spe
 			return fmt.Errorf("load latest: %w", err)
 		}
 
-		// Reject mutations on terminating rows. Mirrors Kubernetes:
-		// `kubectl apply` on an object with deletionTimestamp returns 409.
-		if found && existingDeletionTS.Valid {
-			return ErrTerminating
+		// A soft-deleted tag is gone as far as callers are concerned:
+		// re-applying it publishes over the retained row rather than
+		// waiting for the purge.
+		deleted := found && existingDeletionTS.Valid
+		if ifContentHash != "" && (!found || deleted || existingHash != ifContentHash) {
+			return ErrPreconditionFailed
 		}
 
 		if !found {
@@ -386,7 +493,7 @@ func (s *Store) upsertTagged(ctx context.Context, meta *v1alpha1.ObjectMeta, spe
 			return nil
 		}
 
-		if incomingHash == existingHash {
+		if incomingHash == existingHash && !deleted {
 			result = UpsertResult{Tag: meta.Tag, UID: existingUID, Generation: existingGeneration, Outcome: UpsertNoOp}
 			return nil
 		}
@@ -402,7 +509,11 @@ func (s *Store) upsertTagged(ctx context.Context, meta *v1alpha1.ObjectMeta, spe
 			meta.Namespace, meta.Name, meta.Tag, incomingLabelsJSON, incomingAnnotationsJSON, []byte(specJSON), incomingHash, nextGeneration).Scan(&uid); err != nil {
 			return fmt.Errorf("replace tag: %w", err)
 		}
-		result = UpsertResult{Tag: meta.Tag, UID: uid, Generation: nextGeneration, Outcome: UpsertReplaced}
+		outcome := UpsertReplaced
+		if deleted {
+			outcome = UpsertCreated
+		}
+		result = UpsertResult{Tag: meta.Tag, UID: uid, Generation: nextGeneration, Outcome: outcome}
 		return nil
 	})
 	if err != nil {
@@ -424,6 +535,13 @@ func (s *Store) upsertMutable(ctx context.Context, meta *v1alpha1.ObjectMeta, sp
 
 	var result UpsertResult
 	err = runInTx(ctx, s.pool, func(tx pgx.Tx) error {
+		// Same per-name serialization as upsertTagged: without it two
+		// concurrent creates both see no row and race to the INSERT.
+		key := s.advisoryLockKey(s.table, meta.Namespace, strings.ToLower(meta.Name))
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, key); err != nil {
+			return fmt.Errorf("advisory lock: %w", err)
+		}
+
 		var (
 			oldSpec        []byte
 			oldGen         int64
@@ -712,10 +830,13 @@ func (s *Store) PatchAnnotations(ctx context.Context, namespace, name, tag strin
 	return s.ApplyPatch(ctx, namespace, name, tag, PatchOpts{Annotations: mutate})
 }
 
-// Get returns a single row, including terminating rows. For tagged-artifact
-// stores, tag is metadata.tag. Mutable-object stores ignore tag and
-// load by namespace/name. Returns pkgdb.ErrNotFound if missing.
+// Get returns a single row, including terminating mutable rows. For
+// tagged-artifact stores, tag is metadata.tag and soft-deleted tags are
+// excluded. Mutable-object stores ignore tag and load by namespace/name.
+// namespace and name are canonicalized (see v1alpha1.CanonicalName).
+// Returns pkgdb.ErrNotFound if missing.
 func (s *Store) Get(ctx context.Context, namespace, name, tag string) (*v1alpha1.RawObject, error) {
+	namespace, name = v1alpha1.CanonicalName(namespace), v1alpha1.CanonicalName(name)
 	if s.behavior == TaggedArtifactStore {
 		if tag == "" {
 			return nil, errors.New("v1alpha1 store: tag is required")
@@ -724,7 +845,7 @@ func (s *Store) Get(ctx context.Context, namespace, name, tag string) (*v1alpha1
 			fmt.Sprintf(`
 				SELECT %s
 				FROM %s
-				WHERE namespace=$1 AND name=$2 AND tag=$3`, s.selectColumns(), s.qualified),
+				WHERE namespace=$1 AND name=$2 AND tag=$3 AND deletion_timestamp IS NULL`, s.selectColumns(), s.qualified),
 			namespace, name, tag)
 		return scanRow(row, true)
 	}
@@ -754,8 +875,10 @@ func (s *Store) GetByRef(ctx context.Context, namespace, name, tag string) (*v1a
 // GetLatest returns the literal "latest" live tag for (namespace, name) on
 // tagged-artifact tables, or the current live row for mutable-object stores.
 // Returns pkgdb.ErrNotFound if no live row exists.
-// Terminating rows are excluded.
+// Terminating rows are excluded. namespace and name are canonicalized as
+// in Get.
 func (s *Store) GetLatest(ctx context.Context, namespace, name string) (*v1alpha1.RawObject, error) {
+	namespace, name = v1alpha1.CanonicalName(namespace), v1alpha1.CanonicalName(name)
 	var query string
 	if s.behavior == TaggedArtifactStore {
 		query = fmt.Sprintf(`
@@ -781,6 +904,7 @@ func (s *Store) GetLatest(ctx context.Context, namespace, name string) (*v1alpha
 // handlers contradict LIST, which surfaces the terminating row.
 // Returns pkgdb.ErrNotFound only when no row exists at all.
 func (s *Store) GetLatestIncludingTerminating(ctx context.Context, namespace, name string) (*v1alpha1.RawObject, error) {
+	namespace, name = v1alpha1.CanonicalName(namespace), v1alpha1.CanonicalName(name)
 	var query string
 	if s.behavior == TaggedArtifactStore {
 		query = fmt.Sprintf(`
@@ -800,8 +924,9 @@ func (s *Store) GetLatestIncludingTerminating(ctx context.Context, namespace, na
 
 // Delete removes a single row. Mutable-object stores may use soft-delete plus
 // finalizer drain. Tagged-artifact rows have no finalizers and are hard-deleted
-// immediately so name/tag can be reapplied without waiting for GC. Returns
-// pkgdb.ErrNotFound if the row doesn't exist.
+// immediately, or soft-deleted under WithDeletedRetention; either way the
+// name/tag can be reapplied at once. Returns pkgdb.ErrNotFound if the row
+// doesn't exist or is already deleted.
 func (s *Store) Delete(ctx context.Context, namespace, name, tag string) error {
 	if s.behavior == TaggedArtifactStore {
 		if tag == "" {
@@ -845,6 +970,7 @@ func (s *Store) ListTags(ctx context.Context, namespace, name string) ([]*v1alph
 	if namespace == "" || name == "" {
 		return nil, errors.New("v1alpha1 store: namespace and name are required")
 	}
+	namespace, name = v1alpha1.CanonicalName(namespace), v1alpha1.CanonicalName(name)
 	rows, err := s.pool.Query(ctx,
 		fmt.Sprintf(`
 			SELECT %s
@@ -872,10 +998,11 @@ func (s *Store) ListTags(ctx context.Context, namespace, name string) ([]*v1alph
 }
 
 // DeleteAllTags hard-deletes every tag row for (namespace, name)
-// on a tagged-artifact table. This is the contract of the
+// on a tagged-artifact table, or soft-deletes the live ones under
+// WithDeletedRetention. This is the contract of the
 // batch DELETE endpoint when metadata.tag is omitted; callers delete a
 // single tag by including metadata.tag. Returns pkgdb.ErrNotFound
-// when no row exists for (namespace, name).
+// when no live row exists for (namespace, name).
 //
 // Calling on a mutable-object Store is a programming error; the per-kind Store
 // hands mutable objects to the single-row Delete path
@@ -887,11 +1014,15 @@ func (s *Store) DeleteAllTags(ctx context.Context, namespace, name string) error
 	if namespace == "" || name == "" {
 		return errors.New("v1alpha1 store: namespace and name are required")
 	}
-	cmdTag, err := s.pool.Exec(ctx,
-		fmt.Sprintf(`
+	query := fmt.Sprintf(`
 			DELETE FROM %s
-			WHERE namespace=$1 AND name=$2`, s.qualified),
-		namespace, name)
+			WHERE namespace=$1 AND name=$2`, s.qualified)
+	if s.deletedRetention > 0 {
+		query = fmt.Sprintf(`
+			UPDATE %s SET deletion_timestamp = NOW()
+			WHERE namespace=$1 AND name=$2 AND deletion_timestamp IS NULL`, s.qualified)
+	}
+	cmdTag, err := s.pool.Exec(ctx, query, namespace, name)
 	if err != nil {
 		return fmt.Errorf("delete all tags: %w", err)
 	}
@@ -918,6 +1049,18 @@ func (s *Store) deleteTagged(ctx context.Context, args []any) error {
 			return fmt.Errorf("load row: %w", err)
 		}
 
+		if deletionTS.Valid {
+			return pkgdb.ErrNotFound
+		}
+		if s.deletedRetention > 0 {
+			if _, err := tx.Exec(ctx,
+				fmt.Sprintf(`UPDATE %s SET deletion_timestamp = NOW() WHERE namespace=$1 AND name=$2 AND tag=$3`, s.qualified),
+				args...); err != nil {
+				return fmt.Errorf("soft delete: %w", err)
+			}
+			return nil
+		}
+
 		// Tagged-artifact tables have no finalizers — hard-delete
 		// immediately. This matches the OSS fast-path for finalizer-free
 		// rows: `arctl delete X` then `arctl apply X` works without any
@@ -1012,90 +1155,123 @@ func (s *Store) PurgeFinalized(ctx context.Context) (int64, error) {
 	return cmdTag.RowsAffected(), nil
 }
 
+// Restore clears the deletion of a soft-deleted tag so it is live again with
+// the content it had when deleted. Tagged-artifact stores only. Returns
+// pkgdb.ErrNotFound when the tag is not soft-deleted: it is live, was
+// purged, or never existed.
+func (s *Store) Restore(ctx context.Context, namespace, name, tag string) error {
+	if s.behavior == MutableObjectStore {
+		return errors.New("v1alpha1 store: Restore is not supported on mutable-object stores")
+	}
+	if namespace == "" || name == "" || tag == "" {
+		return errors.New("v1alpha1 store: namespace, name and tag are required")
+	}
+	cmdTag, err := s.pool.Exec(ctx,
+		fmt.Sprintf(`
+			UPDATE %s SET deletion_timestamp = NULL, updated_at = NOW()
+			WHERE namespace=$1 AND name=$2 AND tag=$3 AND deletion_timestamp IS NOT NULL`, s.qualified),
+		namespace, name, tag)
+	if err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return pkgdb.ErrNotFound
+	}
+	return nil
+}
+
+// DeletedRetention reports how long soft-deleted tags are kept; zero means
+// deletes are immediate. See WithDeletedRetention.
+func (s *Store) DeletedRetention() time.Duration {
+	if s == nil || s.behavior == MutableObjectStore {
+		return 0
+	}
+	return s.deletedRetention
+}
+
+// PurgeDeletedBefore hard-deletes tagged rows soft-deleted before the given
+// time. Mutable-object stores purge through PurgeFinalized instead and
+// report zero. Returns the number of rows purged.
+func (s *Store) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	if s.behavior == MutableObjectStore {
+		return 0, nil
+	}
+	cmdTag, err := s.pool.Exec(ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE deletion_timestamp IS NOT NULL AND deletion_timestamp < $1`, s.qualified),
+		before)
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted: %w", err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
 // List returns rows filtered by opts, ordered by stable resource key
-// (namespace, name, tag) with updated_at as a stable tiebreaker. Pagination cursor
-// is returned when more rows are available; pass it back via
-// ListOpts.Cursor to continue. Terminating rows are excluded unless
-// IncludeTerminating is true.
+// (namespace, name, tag) with updated_at as a stable tiebreaker, or by
+// opts.Sort ahead of that key. Pagination cursor is returned when more rows
+// are available; pass it back via ListOpts.Cursor to continue. Terminating
+// rows are excluded unless IncludeTerminating is true.
 func (s *Store) List(ctx context.Context, opts ListOpts) ([]*v1alpha1.RawObject, string, error) {
 	limit := opts.Limit
 	if limit <= 0 {
 		limit = 50
 	}
 
-	args := make([]any, 0, 4)
-	where := make([]string, 0, 4)
-
-	if opts.Namespace != "" {
-		args = append(args, opts.Namespace)
-		where = append(where, fmt.Sprintf("namespace = $%d", len(args)))
-	}
-	if s.behavior == TaggedArtifactStore {
-		// Tag wins when set; otherwise LatestOnly falls back to the literal
-		// "latest" filter for callers that pre-date the Tag field.
-		switch {
-		case opts.Tag != "":
-			args = append(args, opts.Tag)
-			where = append(where, fmt.Sprintf("tag = $%d", len(args)))
-		case opts.LatestOnly:
-			args = append(args, DefaultTag())
-			where = append(where, fmt.Sprintf("tag = $%d", len(args)))
-		}
-	}
-	if !opts.IncludeTerminating {
-		where = append(where, "deletion_timestamp IS NULL")
-	}
-	if len(opts.LabelSelector) > 0 {
-		labelJSON, err := json.Marshal(opts.LabelSelector)
-		if err != nil {
-			return nil, "", fmt.Errorf("marshal labels: %w", err)
-		}
-		args = append(args, labelJSON)
-		where = append(where, fmt.Sprintf("labels @> $%d", len(args)))
+	where, args, err := s.listWhere(opts)
+	if err != nil {
+		return nil, "", err
 	}
 	if opts.Cursor != "" {
 		cursor, err := s.decodeListCursor(opts.Cursor)
 		if err != nil {
 			return nil, "", err
 		}
+		if cursor.Sort != opts.Sort.id() {
+			return nil, "", fmt.Errorf("%w: issued for a different sort", ErrInvalidCursor)
+		}
+		keyCols := "namespace, name, updated_at"
+		keyArgs := []any{cursor.Namespace, cursor.Name, cursor.UpdatedAt}
 		if s.behavior == TaggedArtifactStore {
 			// Order by stable tag before updated_at so status patches do not
 			// let a row skip across pages.
-			args = append(args, cursor.Namespace, cursor.Name, cursor.Tag, cursor.UpdatedAt)
-			where = append(where, fmt.Sprintf(
-				"(namespace, name, tag, updated_at) > ($%d, $%d, $%d, $%d)",
-				len(args)-3, len(args)-2, len(args)-1, len(args),
-			))
-		} else {
-			args = append(args, cursor.Namespace, cursor.Name, cursor.UpdatedAt)
-			where = append(where, fmt.Sprintf(
-				"(namespace, name, updated_at) > ($%d, $%d, $%d)",
-				len(args)-2, len(args)-1, len(args),
-			))
-		}
-	}
-	if opts.ExtraWhere != "" || len(opts.ExtraArgs) > 0 {
-		placeholders := countDistinctPlaceholders(opts.ExtraWhere)
-		if placeholders != len(opts.ExtraArgs) {
-			return nil, "", fmt.Errorf("%w: fragment references %d distinct placeholder(s) but %d arg(s) supplied",
-				ErrInvalidExtraWhere, placeholders, len(opts.ExtraArgs))
+			keyCols = "namespace, name, tag, updated_at"
+			keyArgs = []any{cursor.Namespace, cursor.Name, cursor.Tag, cursor.UpdatedAt}
 		}
-		if len(opts.ExtraArgs) > 0 {
-			args = append(args, opts.ExtraArgs...)
+		placeholders := make([]string, len(keyArgs))
+		for i := range keyArgs {
+			placeholders[i] = fmt.Sprintf("$%d", len(args)+i+1)
 		}
-		if opts.ExtraWhere != "" {
-			where = append(where, rebaseSQLPlaceholders(opts.ExtraWhere, len(args)-len(opts.ExtraArgs)))
+		args = append(args, keyArgs...)
+		after := fmt.Sprintf("(%s) > (%s)", keyCols, strings.Join(placeholders, ", "))
+		if opts.Sort != nil {
+			args = append(args, cursor.SortKey)
+			op := ">"
+			if opts.Sort.Desc {
+				op = "<"
+			}
+			key := opts.Sort.sortKeyExpr()
+			after = fmt.Sprintf("(%s %s $%d OR (%s = $%d AND %s))", key, op, len(args), key, len(args), after)
 		}
+		where = append(where, after)
 	}
 
+	columns := s.selectColumns()
+	orderBy := s.listOrderBy()
+	if opts.Sort != nil {
+		columns += ", " + opts.Sort.sortKeyExpr() + " AS sort_key"
+		dir := ""
+		if opts.Sort.Desc {
+			dir = " DESC"
+		}
+		orderBy = "sort_key" + dir + ", " + orderBy
+	}
 	query := fmt.Sprintf(`
 		SELECT %s
-		FROM %s`, s.selectColumns(), s.qualified)
+		FROM %s`, columns, s.qualified)
 	if len(where) > 0 {
 		query += " WHERE " + strings.Join(where, " AND ")
 	}
 	args = append(args, limit+1)
-	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d", s.listOrderBy(), len(args))
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d", orderBy, len(args))
 
 	rows, err := s.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -1104,12 +1280,19 @@ func (s *Store) List(ctx context.Context, opts ListOpts) ([]*v1alpha1.RawObject,
 	defer rows.Close()
 
 	out := make([]*v1alpha1.RawObject, 0, limit)
+	sortKeys := make([]string, 0, limit)
 	for rows.Next() {
-		obj, err := scanRow(rows, s.behavior == TaggedArtifactStore)
+		var row rowScanner = rows
+		var sortKey string
+		if opts.Sort != nil {
+			row = extraColumns{row: rows, dest: []any{&sortKey}}
+		}
+		obj, err := scanRow(row, s.behavior == TaggedArtifactStore)
 		if err != nil {
 			return nil, "", err
 		}
 		out = append(out, obj)
+		sortKeys = append(sortKeys, sortKey)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, "", err
@@ -1118,7 +1301,7 @@ func (s *Store) List(ctx context.Context, opts ListOpts) ([]*v1alpha1.RawObject,
 	var nextCursor string
 	if len(out) > limit {
 		out = out[:limit]
-		cursor, err := s.encodeListCursor(out[len(out)-1])
+		cursor, err := s.encodeListCursor(out[len(out)-1], opts.Sort, sortKeys[limit-1])
 		if err != nil {
 			return nil, "", fmt.Errorf("encode next cursor: %w", err)
 		}
@@ -1127,6 +1310,83 @@ func (s *Store) List(ctx context.Context, opts ListOpts) ([]*v1alpha1.RawObject,
 	return out, nextCursor, nil
 }
 
+// Count returns how many rows List would return across all pages for
+// opts. Limit, Cursor and Sort are ignored.
+func (s *Store) Count(ctx context.Context, opts ListOpts) (int, error) {
+	where, args, err := s.listWhere(opts)
+	if err != nil {
+		return 0, err
+	}
+	query := "SELECT COUNT(*) FROM " + s.qualified
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	var n int
+	if err := s.pool.QueryRow(ctx, query, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count: %w", err)
+	}
+	return n, nil
+}
+
+// listWhere returns the predicates and bind args of opts' filters, shared
+// by List and Count. The pagination cursor is List's to add.
+func (s *Store) listWhere(opts ListOpts) ([]string, []any, error) {
+	args := make([]any, 0, 4)
+	where := make([]string, 0, 4)
+
+	if opts.Namespace != "" {
+		args = append(args, opts.Namespace)
+		where = append(where, fmt.Sprintf("namespace = $%d", len(args)))
+	}
+	if s.behavior == TaggedArtifactStore {
+		// Tag wins when set; otherwise LatestOnly falls back to the literal
+		// "latest" filter for callers that pre-date the Tag field.
+		switch {
+		case opts.Tag != "":
+			args = append(args, opts.Tag)
+			where = append(where, fmt.Sprintf("tag = $%d", len(args)))
+		case opts.LatestOnly:
+			args = append(args, DefaultTag())
+			where = append(where, fmt.Sprintf("tag = $%d", len(args)))
+		}
+	}
+	if !opts.IncludeTerminating {
+		where = append(where, "deletion_timestamp IS NULL")
+	}
+	if len(opts.LabelSelector) > 0 {
+		labelJSON, err := json.Marshal(opts.LabelSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("marshal labels: %w", err)
+		}
+		args = append(args, labelJSON)
+		where = append(where, fmt.Sprintf("labels @> $%d", len(args)))
+	}
+	if opts.ExtraWhere != "" || len(opts.ExtraArgs) > 0 {
+		placeholders := countDistinctPlaceholders(opts.ExtraWhere)
+		if placeholders != len(opts.ExtraArgs) {
+			return nil, nil, fmt.Errorf("%w: fragment references %d distinct placeholder(s) but %d arg(s) supplied",
+				ErrInvalidExtraWhere, placeholders, len(opts.ExtraArgs))
+		}
+		if len(opts.ExtraArgs) > 0 {
+			args = append(args, opts.ExtraArgs...)
+		}
+		if opts.ExtraWhere != "" {
+			where = append(where, rebaseSQLPlaceholders(opts.ExtraWhere, len(args)-len(opts.ExtraArgs)))
+		}
+	}
+	return where, args, nil
+}
+
+// extraColumns scans the columns a query selects after scanRow's into dest.
+type extraColumns struct {
+	row  rowScanner
+	dest []any
+}
+
+func (e extraColumns) Scan(dest ...any) error {
+	return e.row.Scan(append(dest, e.dest...)...)
+}
+
 var sqlPlaceholderPattern = regexp.MustCompile(`\$(\d+)`)
 
 // rebaseSQLPlaceholders rewrites every `$N` token in a SQL fragment to
@@ -1180,11 +1440,13 @@ func (s *Store) decodeListCursor(token string) (listCursor, error) {
 	return cursor, nil
 }
 
-func (s *Store) encodeListCursor(obj *v1alpha1.RawObject) (string, error) {
+func (s *Store) encodeListCursor(obj *v1alpha1.RawObject, sort *ListSort, sortKey string) (string, error) {
 	if obj == nil {
 		return "", errors.New("nil row")
 	}
 	cursor := listCursor{
+		Sort:      sort.id(),
+		SortKey:   sortKey,
 		UpdatedAt: obj.Metadata.UpdatedAt,
 		Namespace: obj.Metadata.Namespace,
 		Name:      obj.Metadata.Name,
@@ -264,6 +264,7 @@ func TestUpsert_AuditorCalledOnUpsertCreated(t *testing.T) {
 // TestUpsert_AuditorNotCalledForMutableObjectKinds verifies the
 // Runtime/Deployment upsert path does not fire ResourceTagCreated; those kinds
 // model lifecycle state and are out of scope for tag-creation audit events.
+// Auditors implementing types.ObjectAuditor see the creation instead.
 func TestUpsert_AuditorNotCalledForMutableObjectKinds(t *testing.T) {
 	auditor := &typestest.RecordingAuditor{}
 	store := setupProviderStoreWithAuditor(t, auditor)
@@ -276,4 +277,14 @@ func TestUpsert_AuditorNotCalledForMutableObjectKinds(t *testing.T) {
 	})
 	require.NoError(t, err)
 	require.Empty(t, auditor.Events(), "mutable-object kinds must not emit ResourceTagCreated")
+	require.Equal(t, []typestest.ObjectEvent{{Kind: v1alpha1.KindRuntime, Namespace: "default", Name: "p1"}}, auditor.Objects())
+
+	// Updating the same runtime is not a creation.
+	_, err = store.Upsert(ctx, &v1alpha1.Runtime{
+		TypeMeta: v1alpha1.TypeMeta{APIVersion: v1alpha1.GroupVersion, Kind: v1alpha1.KindRuntime},
+		Metadata: v1alpha1.ObjectMeta{Namespace: "default", Name: "p1"},
+		Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeKubernetes},
+	})
+	require.NoError(t, err)
+	require.Len(t, auditor.Objects(), 1, "updates must not emit ObjectCreated")
 }
@@ -4,6 +4,7 @@ package v1alpha1store
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -81,6 +82,38 @@ func TestStore_UpsertReplacesLatestOnSpecChange(t *testing.T) {
 	require.Equal(t, DefaultTag(), obj.Metadata.Tag)
 }
 
+// TestStore_UpsertIfContentHash verifies that a conditional upsert only
+// replaces the row it was computed from.
+func TestStore_UpsertIfContentHash(t *testing.T) {
+	pool := NewTestPool(t)
+	store := NewStore(pool, TestSchema(), testTable)
+	ctx := context.Background()
+
+	upsertAgent(t, store, "foo", v1alpha1.AgentSpec{Title: "first"}, nil)
+	row, err := store.Get(ctx, testNS, "foo", DefaultTag())
+	require.NoError(t, err)
+	hash, err := ContentHash(&row.Metadata, row.Spec)
+	require.NoError(t, err)
+
+	next := func(title string) *v1alpha1.Agent {
+		return &v1alpha1.Agent{
+			Metadata: v1alpha1.ObjectMeta{Namespace: testNS, Name: "foo"},
+			Spec:     v1alpha1.AgentSpec{Title: title},
+		}
+	}
+	res, err := store.Upsert(ctx, next("second"), UpsertOpts{IfContentHash: hash})
+	require.NoError(t, err)
+	require.Equal(t, UpsertReplaced, res.Outcome)
+
+	_, err = store.Upsert(ctx, next("third"), UpsertOpts{IfContentHash: hash})
+	require.ErrorIs(t, err, ErrPreconditionFailed, "the row changed since hash was taken")
+	_, err = store.Upsert(ctx, &v1alpha1.Agent{
+		Metadata: v1alpha1.ObjectMeta{Namespace: testNS, Name: "bar"},
+		Spec:     v1alpha1.AgentSpec{Title: "new"},
+	}, UpsertOpts{IfContentHash: hash})
+	require.ErrorIs(t, err, ErrPreconditionFailed, "a missing row never matches")
+}
+
 // TestStore_GetLatestReadsLiteralLatestTag verifies that GetLatest returns the
 // row tagged "latest", not the newest or lexicographically highest tag.
 func TestStore_GetLatestReadsLiteralLatestTag(t *testing.T) {
@@ -245,6 +278,49 @@ func TestStore_GetNotFound(t *testing.T) {
 	require.True(t, errors.Is(err, pkgdb.ErrNotFound))
 }
 
+func TestStore_CanonicalNames(t *testing.T) {
+	pool := NewTestPool(t)
+	store := NewStore(pool, TestSchema(), testTable)
+	runtimes := NewMutableObjectStore(pool, TestSchema(), "runtimes")
+	ctx := context.Background()
+
+	upsertAgent(t, store, "Weather-Bot", v1alpha1.AgentSpec{Title: "Weather"}, nil)
+	row, err := store.Get(ctx, "Default", "WEATHER-BOT", DefaultTag())
+	require.NoError(t, err)
+	require.Equal(t, "weather-bot", row.Metadata.Name)
+	_, err = store.GetLatest(ctx, testNS, "weather-bot")
+	require.NoError(t, err)
+	tags, err := store.ListTags(ctx, testNS, "Weather-Bot")
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+
+	// Concurrent creates of one name in two spellings make one row.
+	outcomes := make(chan UpsertOutcome, 2)
+	for _, name := range []string{"Edge", "EDGE"} {
+		go func() {
+			res, err := runtimes.Upsert(ctx, &v1alpha1.Runtime{
+				Metadata: v1alpha1.ObjectMeta{Namespace: testNS, Name: name},
+				Spec:     v1alpha1.RuntimeSpec{Type: v1alpha1.TypeLocal},
+			})
+			require.NoError(t, err)
+			outcomes <- res.Outcome
+		}()
+	}
+	created := 0
+	for range 2 {
+		if <-outcomes == UpsertCreated {
+			created++
+		}
+	}
+	require.Equal(t, 1, created)
+	_, err = runtimes.GetLatest(ctx, testNS, "edge")
+	require.NoError(t, err)
+
+	// Rows written around the store cannot hold another spelling either.
+	_, err = pool.Exec(ctx, `INSERT INTO `+TestSchema().Qualify("runtimes")+` (namespace, name, spec) VALUES ($1, $2, '{}')`, testNS, "Local")
+	require.ErrorContains(t, err, "runtimes_name_canonical")
+}
+
 // TestStore_DeleteHardDeletesTaggedRow guards the tagged-artifact fast path:
 // rows have no finalizers and Delete
 // hard-deletes immediately. arctl delete + arctl apply works without any
@@ -285,6 +361,53 @@ func TestStore_DeleteHardDeletesTaggedRow(t *testing.T) {
 	require.ErrorIs(t, err, pkgdb.ErrNotFound)
 }
 
+// TestStore_DeletedRetentionSoftDeletesTags covers the soft-delete path:
+// deleted tags disappear from reads, stay listable with IncludeTerminating,
+// can be restored or re-applied, and are purged after the grace period.
+func TestStore_DeletedRetentionSoftDeletesTags(t *testing.T) {
+	pool := NewTestPool(t)
+	store := NewStore(pool, TestSchema(), testTable, WithDeletedRetention(time.Hour))
+	ctx := context.Background()
+
+	_, err := store.Upsert(ctx, &v1alpha1.Agent{
+		Metadata: v1alpha1.ObjectMeta{Namespace: testNS, Name: "foo", Tag: "stable"},
+		Spec:     v1alpha1.AgentSpec{Title: "stable"},
+	})
+	require.NoError(t, err)
+	upsertAgent(t, store, "foo", v1alpha1.AgentSpec{Title: "current"}, nil)
+
+	require.NoError(t, store.Delete(ctx, testNS, "foo", "stable"))
+	_, err = store.Get(ctx, testNS, "foo", "stable")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+	require.ErrorIs(t, store.Delete(ctx, testNS, "foo", "stable"), pkgdb.ErrNotFound, "already deleted")
+
+	rows, _, err := store.List(ctx, ListOpts{Namespace: testNS, IncludeTerminating: true})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	require.NoError(t, store.Restore(ctx, testNS, "foo", "stable"))
+	stable, err := store.Get(ctx, testNS, "foo", "stable")
+	require.NoError(t, err)
+	require.Nil(t, stable.Metadata.DeletionTimestamp)
+	require.ErrorIs(t, store.Restore(ctx, testNS, "foo", "stable"), pkgdb.ErrNotFound, "live tags are not restorable")
+
+	// Deleting every tag and re-applying one publishes over the retained row.
+	require.NoError(t, store.DeleteAllTags(ctx, testNS, "foo"))
+	require.ErrorIs(t, store.DeleteAllTags(ctx, testNS, "foo"), pkgdb.ErrNotFound)
+	res := upsertAgent(t, store, "foo", v1alpha1.AgentSpec{Title: "current"}, nil)
+	require.Equal(t, UpsertCreated, res.Outcome)
+	_, err = store.GetLatest(ctx, testNS, "foo")
+	require.NoError(t, err)
+
+	purged, err := store.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, purged, "stable was deleted within the grace period")
+	purged, err = store.PurgeDeletedBefore(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, purged)
+	require.ErrorIs(t, store.Restore(ctx, testNS, "foo", "stable"), pkgdb.ErrNotFound)
+}
+
 func TestStore_List(t *testing.T) {
 	pool := NewTestPool(t)
 	store := NewStore(pool, TestSchema(), testTable)
@@ -480,6 +603,127 @@ func TestStore_ListCursorStableUnderStatusChurn(t *testing.T) {
 	}
 }
 
+func TestStore_ListSortedPagination(t *testing.T) {
+	pool := NewTestPool(t)
+	store := NewStore(pool, TestSchema(), testTable)
+	ctx := context.Background()
+
+	for name, title := range map[string]string{"n1": "charlie", "n2": "alpha", "n3": "bravo", "n4": "alpha"} {
+		upsertAgent(t, store, name, v1alpha1.AgentSpec{Title: title}, nil)
+	}
+	byTitle := &ListSort{Expr: "spec->>'title'"}
+
+	names := func(rows []*v1alpha1.RawObject) []string {
+		out := make([]string, len(rows))
+		for i, row := range rows {
+			out[i] = row.Metadata.Name
+		}
+		return out
+	}
+	page1, cursor, err := store.List(ctx, ListOpts{Limit: 3, Sort: byTitle})
+	require.NoError(t, err)
+	require.Equal(t, []string{"n2", "n4", "n3"}, names(page1), "ties fall back to key order")
+	page2, cursor2, err := store.List(ctx, ListOpts{Limit: 3, Sort: byTitle, Cursor: cursor})
+	require.NoError(t, err)
+	require.Empty(t, cursor2)
+	require.Equal(t, []string{"n1"}, names(page2))
+
+	desc := &ListSort{Expr: "spec->>'title'", Desc: true}
+	page1, cursor, err = store.List(ctx, ListOpts{Limit: 2, Sort: desc})
+	require.NoError(t, err)
+	require.Equal(t, []string{"n1", "n3"}, names(page1))
+	page2, _, err = store.List(ctx, ListOpts{Limit: 2, Sort: desc, Cursor: cursor})
+	require.NoError(t, err)
+	require.Equal(t, []string{"n2", "n4"}, names(page2), "ties stay in ascending key order")
+
+	_, _, err = store.List(ctx, ListOpts{Limit: 2, Cursor: cursor})
+	require.ErrorIs(t, err, ErrInvalidCursor, "a cursor only continues its own sort")
+}
+
+func TestStore_ListSortByUpdatedAt(t *testing.T) {
+	pool := NewTestPool(t)
+	store := NewStore(pool, TestSchema(), testTable)
+	ctx := context.Background()
+
+	for _, name := range []string{"first", "second", "third"} {
+		upsertAgent(t, store, name, v1alpha1.AgentSpec{Title: name}, nil)
+	}
+	upsertAgent(t, store, "first", v1alpha1.AgentSpec{Title: "first, edited"}, nil)
+
+	page1, cursor, err := store.List(ctx, ListOpts{Limit: 2, Sort: SortByUpdatedAt})
+	require.NoError(t, err)
+	require.Len(t, page1, 2)
+	require.Equal(t, "first", page1[0].Metadata.Name)
+	require.Equal(t, "third", page1[1].Metadata.Name)
+	page2, _, err := store.List(ctx, ListOpts{Limit: 2, Sort: SortByUpdatedAt, Cursor: cursor})
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+	require.Equal(t, "second", page2[0].Metadata.Name)
+}
+
+func TestStore_ListSortByDeploymentStatus(t *testing.T) {
+	pool := NewTestPool(t)
+	store := NewMutableObjectStore(pool, TestSchema(), "deployments")
+	ctx := context.Background()
+
+	conditions := map[string]string{
+		"ready":    `{"conditions":[{"type":"Ready","status":"True"}]}`,
+		"broken":   `{"conditions":[{"type":"Ready","status":"False"},{"type":"Degraded","status":"True"}]}`,
+		"rolling":  `{"conditions":[{"type":"Progressing","status":"Unknown"}]}`,
+		"new":      `{}`,
+		"shelved":  `{}`,
+		"settling": `{"conditions":[{"type":"Progressing","status":"False"}]}`,
+	}
+	for name, status := range conditions {
+		dep := &v1alpha1.Deployment{
+			Metadata: v1alpha1.ObjectMeta{Namespace: testNS, Name: name},
+			Spec: v1alpha1.DeploymentSpec{
+				TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "bot"},
+				RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "local"},
+			},
+		}
+		if name == "shelved" {
+			dep.Spec.DesiredState = v1alpha1.DesiredStateUndeployed
+		}
+		_, err := store.Upsert(ctx, dep)
+		require.NoError(t, err)
+		require.NoError(t, store.PatchStatus(ctx, testNS, name, "", func(json.RawMessage) (json.RawMessage, error) {
+			return json.RawMessage(status), nil
+		}))
+	}
+
+	rows, _, err := store.List(ctx, ListOpts{Sort: SortByDeploymentStatus})
+	require.NoError(t, err)
+	got := make([]string, len(rows))
+	for i, row := range rows {
+		got[i] = row.Metadata.Name
+	}
+	// deployed, deploying, failed, pending (new, settling), undeployed.
+	require.Equal(t, []string{"ready", "rolling", "broken", "new", "settling", "shelved"}, got)
+}
+
+func TestStore_Count(t *testing.T) {
+	pool := NewTestPool(t)
+	store := NewStore(pool, TestSchema(), testTable)
+	ctx := context.Background()
+
+	upsertAgent(t, store, "a", v1alpha1.AgentSpec{Title: "a"}, map[string]string{"team": "red"})
+	upsertAgent(t, store, "b", v1alpha1.AgentSpec{Title: "b"}, map[string]string{"team": "blue"})
+	upsertAgent(t, store, "c", v1alpha1.AgentSpec{Title: "c"}, map[string]string{"team": "red"})
+
+	n, err := store.Count(ctx, ListOpts{Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, 3, n, "Limit does not cap the count")
+
+	n, err = store.Count(ctx, ListOpts{LabelSelector: map[string]string{"team": "red"}})
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	n, err = store.Count(ctx, ListOpts{ExtraWhere: "name <> $1", ExtraArgs: []any{"a"}})
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+}
+
 func TestStore_PatchAnnotationsPreservesExistingKeys(t *testing.T) {
 	pool := NewTestPool(t)
 	store := NewStore(pool, TestSchema(), testTable)
@@ -815,3 +1059,746 @@ func TestControlPlaneEventStore_PruneBeforeHonorsKeepAfterRevision(t *testing.T)
 	require.Len(t, remaining, 1)
 	require.Equal(t, keep, remaining[0].Revision)
 }
+
+// TestPartitions_CrossPartitionQueries spreads event-log rows over several
+// day partitions and checks that reads through the parent table see them
+// as one log, and that pruning drops whole partitions.
+func TestPartitions_CrossPartitionQueries(t *testing.T) {
+	pool := NewTestPool(t)
+	schema := TestSchema()
+	events := NewControlPlaneEventStore(pool, schema)
+	deliveries := NewWebhookDeliveryStore(pool, schema)
+	partitions := NewPartitionManager(pool, schema)
+	ctx := context.Background()
+
+	today := partitionDay(time.Now())
+	days := []time.Time{today.AddDate(0, 0, -3), today.AddDate(0, 0, -2), today}
+	// Rows land in the DEFAULT partition until their day's partition exists.
+	for i, day := range days {
+		_, err := pool.Exec(ctx, `
+			INSERT INTO `+schema.Qualify("control_plane_events")+` (kind, namespace, name, uid, generation, op, committed_at)
+			VALUES ('Agent', $1, $2, gen_random_uuid(), 1, 'insert', $3)`,
+			testNS, fmt.Sprintf("agent-%d", i), day.Add(time.Hour))
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, `
+			INSERT INTO `+schema.Qualify("webhook_deliveries")+` (delivery_id, namespace, webhook, event, url, attempt, payload, delivered_at)
+			VALUES ($1, $2, 'ci', 'server.published', 'https://ci.example.com/hook', 1, '{}', $3)`,
+			fmt.Sprintf("delivery-%d", i), testNS, day.Add(time.Hour))
+		require.NoError(t, err)
+	}
+
+	created, err := partitions.EnsurePartitions(ctx, days[0], 3)
+	require.NoError(t, err)
+	require.Equal(t, 8, created, "four days for each of the two tables")
+	created, err = partitions.EnsurePartitions(ctx, days[0], 3)
+	require.NoError(t, err)
+	require.Zero(t, created)
+
+	for _, table := range PartitionedTables() {
+		var parked int
+		require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM `+schema.Qualify(table.Name+"_default")).Scan(&parked))
+		require.Zero(t, parked, "%s rows must move out of the default partition", table.Name)
+	}
+
+	all, err := events.ListAfter(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	for i, event := range all {
+		require.Equal(t, fmt.Sprintf("agent-%d", i), event.Key.Name)
+		if i > 0 {
+			require.Greater(t, event.Revision, all[i-1].Revision)
+		}
+	}
+	after, err := events.ListAfter(ctx, all[0].Revision, 10)
+	require.NoError(t, err)
+	require.Len(t, after, 2)
+	oldest, ok, err := events.OldestRevision(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, all[0].Revision, oldest)
+	current, err := events.CurrentRevision(ctx)
+	require.NoError(t, err)
+	require.Equal(t, all[2].Revision, current)
+
+	got, err := deliveries.List(ctx, testNS, "ci", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	require.Equal(t, "delivery-2", got[0].DeliveryID)
+	require.Equal(t, "delivery-0", got[2].DeliveryID)
+
+	// The second day's partition still holds a revision the keep bound
+	// protects, so only the first day's partition goes.
+	pruned, err := events.PruneBefore(ctx, today, all[1].Revision, 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, pruned)
+	var exists bool
+	require.NoError(t, pool.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`,
+		schema.Qualify(ControlPlaneEventsTable.PartitionName(days[0]))).Scan(&exists))
+	require.False(t, exists)
+	require.NoError(t, pool.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`,
+		schema.Qualify(ControlPlaneEventsTable.PartitionName(days[1]))).Scan(&exists))
+	require.True(t, exists)
+
+	pruned, err = events.PruneBefore(ctx, today, 0, 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, pruned)
+	remaining, err := events.ListAfter(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	require.Equal(t, all[2].Revision, remaining[0].Revision)
+
+	pruned, err = deliveries.PruneBefore(ctx, today)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, pruned)
+	got, err = deliveries.List(ctx, testNS, "ci", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, "delivery-2", got[0].DeliveryID)
+}
+
+func TestWebhookDeliveryStore_RecordListPrune(t *testing.T) {
+	pool := NewTestPool(t)
+	deliveries := NewWebhookDeliveryStore(pool, TestSchema())
+	ctx := context.Background()
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		require.NoError(t, deliveries.Record(ctx, WebhookDelivery{
+			DeliveryID: "6f1c1b7e3a574d8e9d4c7f0e8b1f2a10",
+			Namespace:  testNS,
+			Webhook:    "ci",
+			Event:      v1alpha1.WebhookEventServerPublished,
+			URL:        "https://ci.example.com/hook",
+			Attempt:    attempt,
+			StatusCode: 500,
+			Payload:    json.RawMessage(`{"event":"server.published"}`),
+		}))
+	}
+	require.NoError(t, deliveries.Record(ctx, WebhookDelivery{
+		DeliveryID: "0b7e5c1d9f2a4e0b8a553d1f6c2e9b44",
+		Namespace:  testNS,
+		Webhook:    "other",
+		Event:      v1alpha1.WebhookEventAgentPublished,
+		URL:        "https://other.example.com/hook",
+		Attempt:    1,
+		Payload:    json.RawMessage(`{}`),
+	}))
+
+	got, err := deliveries.List(ctx, testNS, "ci", 0, 2)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, 3, got[0].Attempt)
+	require.Equal(t, 2, got[1].Attempt)
+	require.Equal(t, 500, got[0].StatusCode)
+
+	older, err := deliveries.List(ctx, testNS, "ci", got[1].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, older, 1)
+	require.Equal(t, 1, older[0].Attempt)
+
+	pruned, err := deliveries.PruneBefore(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.EqualValues(t, 4, pruned)
+}
+
+func TestDeploymentLocks_ContendedLockNamesHolder(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	replicaA := NewDeploymentLocks(pool, TestSchema(), "replica-a")
+	replicaB := NewDeploymentLocks(pool, TestSchema(), "replica-b")
+
+	deployment := &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Namespace: testNS, Name: "bot-prod"},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Name: "bot", Tag: "1.0.0"},
+			RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "k8s"},
+		},
+	}
+	// A second Deployment of another tag of the same agent on the same
+	// runtime shares the lock.
+	sibling := &v1alpha1.Deployment{
+		Metadata: v1alpha1.ObjectMeta{Namespace: testNS, Name: "bot-canary"},
+		Spec: v1alpha1.DeploymentSpec{
+			TargetRef:  v1alpha1.ResourceRef{Kind: v1alpha1.KindAgent, Namespace: testNS, Name: "bot", Tag: "2.0.0"},
+			RuntimeRef: v1alpha1.ResourceRef{Kind: v1alpha1.KindRuntime, Name: "k8s"},
+		},
+	}
+	require.NoError(t, replicaB.Check(ctx, sibling))
+
+	release, err := replicaA.Acquire(ctx, deployment, DeploymentLockApply)
+	require.NoError(t, err)
+
+	_, err = replicaB.Acquire(ctx, sibling, DeploymentLockApply)
+	require.ErrorIs(t, err, pkgdb.ErrConflict)
+	var locked *DeploymentLockedError
+	require.True(t, errors.As(err, &locked))
+	require.NotNil(t, locked.Holder)
+	require.Equal(t, "bot-prod", locked.Holder.Name)
+	require.Equal(t, DeploymentLockApply, locked.Holder.Operation)
+	require.Equal(t, "replica-a", locked.Holder.Holder)
+	require.Contains(t, err.Error(), "Agent default/bot on Runtime default/k8s")
+
+	err = replicaB.Check(ctx, sibling)
+	require.ErrorIs(t, err, pkgdb.ErrConflict)
+	require.Contains(t, err.Error(), "apply of Deployment default/bot-prod by replica-a")
+
+	release()
+	require.NoError(t, replicaB.Check(ctx, sibling))
+	release, err = replicaB.Acquire(ctx, sibling, DeploymentLockRemove)
+	require.NoError(t, err)
+	release()
+
+	var rows int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM `+TestSchema().Qualify("deployment_locks")).Scan(&rows))
+	require.Zero(t, rows)
+}
+
+func TestNamespaceStore_ClaimVerifyAndMembers(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	store := NewNamespaceStore(pool, TestSchema())
+	stale := time.Now().Add(-time.Hour)
+
+	_, err := store.Get(ctx, "io.github.acme")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+
+	claimed, err := store.Claim(ctx, "io.github.acme", "alice", "tok-a", stale)
+	require.NoError(t, err)
+	require.Equal(t, "alice", claimed.Owner)
+	require.Empty(t, claimed.Members)
+	require.False(t, claimed.Verified())
+
+	// Re-claiming as the owner keeps the claim; a fresh pending claim holds
+	// against others.
+	again, err := store.Claim(ctx, "io.github.acme", "alice", "tok-other", stale)
+	require.NoError(t, err)
+	require.Equal(t, "tok-a", again.VerificationToken)
+	_, err = store.Claim(ctx, "io.github.acme", "mallory", "tok-m", stale)
+	require.ErrorIs(t, err, pkgdb.ErrAlreadyExists)
+
+	// A pending claim older than staleBefore can be taken over.
+	taken, err := store.Claim(ctx, "io.github.acme", "bob", "tok-b", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, "bob", taken.Owner)
+	require.Equal(t, "tok-b", taken.VerificationToken)
+
+	verified, err := store.Verify(ctx, "io.github.acme")
+	require.NoError(t, err)
+	require.True(t, verified.Verified())
+	// Verified claims are never taken over.
+	_, err = store.Claim(ctx, "io.github.acme", "alice", "tok-a2", time.Now().Add(time.Hour))
+	require.ErrorIs(t, err, pkgdb.ErrAlreadyExists)
+
+	updated, err := store.SetMembers(ctx, "io.github.acme", []string{"carol", "dave"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"carol", "dave"}, updated.Members)
+	require.True(t, updated.IsMember("bob"))
+	require.True(t, updated.IsMember("carol"))
+	require.False(t, updated.IsMember("alice"))
+
+	list, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	require.NoError(t, store.Delete(ctx, "io.github.acme"))
+	require.ErrorIs(t, store.Delete(ctx, "io.github.acme"), pkgdb.ErrNotFound)
+	_, err = store.Verify(ctx, "io.github.acme")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+}
+
+func TestReservedPrefixStore_RequestsAndAllocation(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	store := NewReservedPrefixStore(pool, TestSchema())
+
+	_, err := store.Get(ctx, "acme/")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+
+	put, err := store.Put(ctx, "acme/", "trademark", nil)
+	require.NoError(t, err)
+	require.Equal(t, "trademark", put.Reason)
+	require.Empty(t, put.Owners)
+
+	req, err := store.CreateRequest(ctx, "acme/", "alice", "we are acme")
+	require.NoError(t, err)
+	require.Equal(t, PrefixRequestPending, req.Status)
+	_, err = store.CreateRequest(ctx, "acme/", "alice", "again")
+	require.ErrorIs(t, err, pkgdb.ErrAlreadyExists)
+
+	// A config-only prefix has no row until a request for it is approved.
+	other, err := store.CreateRequest(ctx, "official/", "bob", "")
+	require.NoError(t, err)
+
+	mine, err := store.ListRequests(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, mine, 1)
+	all, err := store.ListRequests(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	approved, err := store.DecideRequest(ctx, req.ID, true, "admin")
+	require.NoError(t, err)
+	require.Equal(t, PrefixRequestApproved, approved.Status)
+	require.Equal(t, "admin", approved.DecidedBy)
+	require.NotNil(t, approved.DecidedAt)
+	_, err = store.DecideRequest(ctx, req.ID, false, "admin")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound, "decided requests stay decided")
+
+	got, err := store.Get(ctx, "acme/")
+	require.NoError(t, err)
+	require.True(t, got.IsOwner("alice"))
+	require.Equal(t, "trademark", got.Reason)
+
+	allocated, err := store.DecideRequest(ctx, other.ID, true, "admin")
+	require.NoError(t, err)
+	require.Equal(t, "official/", allocated.Prefix)
+	got, err = store.Get(ctx, "official/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"bob"}, got.Owners)
+
+	list, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	require.NoError(t, store.Delete(ctx, "acme/"))
+	require.ErrorIs(t, store.Delete(ctx, "acme/"), pkgdb.ErrNotFound)
+}
+
+func TestRuntimeGrantStore_PutListDelete(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	store := NewRuntimeGrantStore(pool, TestSchema())
+
+	_, err := store.Get(ctx, "default", "prod-k8s")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+
+	put, err := store.Put(ctx, "default", "prod-k8s", []string{"ops"})
+	require.NoError(t, err)
+	require.True(t, put.IsDeployer("ops"))
+	require.False(t, put.IsDeployer("dev"))
+
+	put, err = store.Put(ctx, "default", "prod-k8s", nil)
+	require.NoError(t, err)
+	require.Empty(t, put.Deployers, "an empty grant leaves the Runtime to admins")
+	_, err = store.Put(ctx, "team-a", "prod-k8s", []string{"alice"})
+	require.NoError(t, err)
+
+	all, err := store.List(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	teamA, err := store.List(ctx, "team-a")
+	require.NoError(t, err)
+	require.Len(t, teamA, 1)
+	require.Equal(t, []string{"alice"}, teamA[0].Deployers)
+
+	require.NoError(t, store.Delete(ctx, "default", "prod-k8s"))
+	require.ErrorIs(t, store.Delete(ctx, "default", "prod-k8s"), pkgdb.ErrNotFound)
+}
+
+func TestAPIKeyStore_CreateListRevoke(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	store := NewAPIKeyStore(pool, TestSchema())
+
+	_, err := store.Get(ctx, "k1")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+
+	created, err := store.Create(ctx, &APIKey{
+		ID: "k1", Name: "ci", Owner: "alice", SecretHash: "h1",
+		Kinds: []string{"Agent"}, Prefixes: []string{"acme/"}, Actions: []string{"read", "push"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"acme/"}, created.Prefixes)
+	require.True(t, created.Active(time.Now()))
+	_, err = store.Create(ctx, &APIKey{ID: "k1", Name: "dup", Owner: "alice", SecretHash: "h"})
+	require.ErrorIs(t, err, pkgdb.ErrAlreadyExists)
+
+	expired := time.Now().Add(-time.Minute)
+	_, err = store.Create(ctx, &APIKey{ID: "k2", Name: "old", Owner: "bob", SecretHash: "h2", ExpiresAt: &expired})
+	require.NoError(t, err)
+	got, err := store.Get(ctx, "k2")
+	require.NoError(t, err)
+	require.Empty(t, got.Kinds)
+	require.False(t, got.Active(time.Now()))
+
+	mine, err := store.List(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, mine, 1)
+	all, err := store.List(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	require.NoError(t, store.Touch(ctx, "k1", time.Now()))
+	revoked, err := store.Revoke(ctx, "k1")
+	require.NoError(t, err)
+	require.NotNil(t, revoked.LastUsedAt)
+	require.NotNil(t, revoked.RevokedAt)
+	require.False(t, revoked.Active(time.Now()))
+	again, err := store.Revoke(ctx, "k1")
+	require.NoError(t, err)
+	require.True(t, again.RevokedAt.Equal(*revoked.RevokedAt))
+	_, err = store.Revoke(ctx, "missing")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+}
+
+func TestDeploymentManifestStore_RecordGetDelete(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	store := NewDeploymentManifestStore(pool, TestSchema())
+
+	_, err := store.Get(ctx, "default", "bot-prod")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+
+	require.NoError(t, store.Record(ctx, DeploymentManifests{
+		Namespace: "default", Name: "bot-prod", Generation: 1, Runtime: "Local",
+		Manifests: []DeploymentManifest{{Name: "docker-compose.yaml", Content: "services: {}\n"}},
+	}))
+	require.NoError(t, store.Record(ctx, DeploymentManifests{
+		Namespace: "default", Name: "bot-prod", Generation: 2, Runtime: "Local",
+		Manifests: []DeploymentManifest{{Name: "docker-compose.yaml", Content: "services:\n  bot: {}\n"}},
+	}))
+
+	got, err := store.Get(ctx, "default", "bot-prod")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), got.Generation)
+	require.Equal(t, "Local", got.Runtime)
+	require.Equal(t, []DeploymentManifest{{Name: "docker-compose.yaml", Content: "services:\n  bot: {}\n"}}, got.Manifests)
+	require.False(t, got.AppliedAt.IsZero())
+
+	require.NoError(t, store.Delete(ctx, "default", "bot-prod"))
+	require.NoError(t, store.Delete(ctx, "default", "bot-prod"))
+	_, err = store.Get(ctx, "default", "bot-prod")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+}
+
+func TestStore_Search(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	store := NewStore(pool, TestSchema(), testTable, WithKind(v1alpha1.KindAgent))
+	readmes := NewArtifactReadmeStore(pool, TestSchema())
+
+	upsertAgent(t, store, "weather-bot", v1alpha1.AgentSpec{Title: "Weather", Description: "Forecasts for any city."}, nil)
+	upsertAgent(t, store, "triage", v1alpha1.AgentSpec{Description: "Routes support tickets."}, nil)
+	res := upsertAgent(t, store, "summarizer", v1alpha1.AgentSpec{Description: "Summarizes documents."}, nil)
+	row, err := store.Get(ctx, testNS, "summarizer", res.Tag)
+	require.NoError(t, err)
+	require.NoError(t, readmes.Put(ctx, ArtifactReadme{
+		Kind: v1alpha1.KindAgent, Namespace: testNS, Name: "summarizer", Tag: res.Tag,
+		ArtifactUID: row.Metadata.UID, Content: "Also produces weekly weather digests.",
+	}))
+
+	hits, err := store.Search(ctx, SearchOpts{Query: "weather"})
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+	require.Equal(t, "weather-bot", hits[0].Object.Metadata.Name)
+	require.True(t, hits[0].NameMatch)
+	require.Equal(t, "summarizer", hits[1].Object.Metadata.Name)
+	require.False(t, hits[1].NameMatch)
+	require.Greater(t, hits[0].Rank, hits[1].Rank)
+	require.Contains(t, hits[1].Highlight, "<mark>weather</mark>")
+	require.False(t, hits[0].ReadmeMatch)
+	require.True(t, hits[1].ReadmeMatch)
+
+	hits, err = store.Search(ctx, SearchOpts{Query: "ticket"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Equal(t, "triage", hits[0].Object.Metadata.Name)
+
+	hits, err = store.Search(ctx, SearchOpts{Query: "weath"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Zero(t, hits[0].Rank)
+
+	hits, err = store.Search(ctx, SearchOpts{Query: "weather", ExtraWhere: "name <> $1", ExtraArgs: []any{"weather-bot"}})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+}
+
+func TestArtifactReadmeStore_PutGet(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	store := NewArtifactReadmeStore(pool, TestSchema())
+
+	_, err := store.Get(ctx, v1alpha1.KindAgent, "default", "bot", "v1")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+
+	const uid = "7d3c6a1e-8f3b-4d2a-9c1e-0a5b6c7d8e9f"
+	require.NoError(t, store.Put(ctx, ArtifactReadme{
+		Kind: v1alpha1.KindAgent, Namespace: "default", Name: "bot", Tag: "v1", ArtifactUID: uid, Content: "# Bot\n",
+	}))
+	require.NoError(t, store.Put(ctx, ArtifactReadme{
+		Kind: v1alpha1.KindAgent, Namespace: "default", Name: "bot", Tag: "v1", ArtifactUID: uid, Content: "# Bot\n\nRevised.\n",
+	}))
+
+	got, err := store.Get(ctx, v1alpha1.KindAgent, "default", "bot", "v1")
+	require.NoError(t, err)
+	require.Equal(t, uid, got.ArtifactUID)
+	require.Equal(t, "# Bot\n\nRevised.\n", got.Content)
+	require.False(t, got.UpdatedAt.IsZero())
+
+	_, err = store.Get(ctx, v1alpha1.KindSkill, "default", "bot", "v1")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+}
+
+func TestArtifactIconStore_PutGetDelete(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	store := NewArtifactIconStore(pool, TestSchema())
+
+	_, err := store.Get(ctx, v1alpha1.KindAgent, "default", "bot")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+
+	require.NoError(t, store.Put(ctx, ArtifactIcon{
+		Kind: v1alpha1.KindAgent, Namespace: "default", Name: "bot", ContentType: "image/png", Content: []byte("first"),
+	}))
+	require.NoError(t, store.Put(ctx, ArtifactIcon{
+		Kind: v1alpha1.KindAgent, Namespace: "default", Name: "bot", ContentType: "image/svg+xml", Content: []byte("<svg/>"),
+	}))
+
+	got, err := store.Get(ctx, v1alpha1.KindAgent, "default", "bot")
+	require.NoError(t, err)
+	require.Equal(t, "image/svg+xml", got.ContentType)
+	require.Equal(t, []byte("<svg/>"), got.Content)
+	require.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte("<svg/>"))), got.Digest)
+	require.False(t, got.UpdatedAt.IsZero())
+
+	_, err = store.Get(ctx, v1alpha1.KindSkill, "default", "bot")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+
+	require.NoError(t, store.Delete(ctx, v1alpha1.KindAgent, "default", "bot"))
+	require.ErrorIs(t, store.Delete(ctx, v1alpha1.KindAgent, "default", "bot"), pkgdb.ErrNotFound)
+}
+
+func TestDeploymentNoteStore_AppendHistory(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	store := NewDeploymentNoteStore(pool, TestSchema())
+
+	history, err := store.History(ctx, "default", "bot-prod")
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	first, err := store.Append(ctx, DeploymentNote{
+		Namespace: "default", Name: "bot-prod", Note: "canary", Author: "alice",
+		Links: []DeploymentLink{{Title: "Runbook", URL: "https://runbooks.example.com/bot"}},
+	})
+	require.NoError(t, err)
+	require.NotZero(t, first.ID)
+	require.False(t, first.CreatedAt.IsZero())
+	_, err = store.Append(ctx, DeploymentNote{Namespace: "default", Name: "bot-prod", Note: "rolled back due to 5xx spike", Author: "bob"})
+	require.NoError(t, err)
+	_, err = store.Append(ctx, DeploymentNote{Namespace: "default", Name: "other", Note: "unrelated"})
+	require.NoError(t, err)
+
+	history, err = store.History(ctx, "default", "bot-prod")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "rolled back due to 5xx spike", history[0].Note)
+	require.Equal(t, "bob", history[0].Author)
+	require.Empty(t, history[0].Links)
+	require.Equal(t, "canary", history[1].Note)
+	require.Equal(t, []DeploymentLink{{Title: "Runbook", URL: "https://runbooks.example.com/bot"}}, history[1].Links)
+}
+
+func TestDeploymentLogStore_AppendListRetention(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	store := NewDeploymentLogStore(pool, TestSchema())
+
+	latest, err := store.Latest(ctx, "default", "bot-prod")
+	require.NoError(t, err)
+	require.True(t, latest.IsZero())
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Append(ctx, "default", "bot-prod", []DeploymentLogLine{
+		{Timestamp: base, Stream: "stdout", Line: "starting"},
+		{Timestamp: base.Add(time.Minute), Stream: "stderr", Line: "warn"},
+		{Timestamp: base.Add(2 * time.Minute), Stream: "stdout", Line: "ready"},
+	}))
+	require.NoError(t, store.Append(ctx, "default", "other", []DeploymentLogLine{
+		{Timestamp: base, Stream: "stdout", Line: "other"},
+	}))
+
+	latest, err = store.Latest(ctx, "default", "bot-prod")
+	require.NoError(t, err)
+	require.True(t, latest.Equal(base.Add(2*time.Minute)))
+
+	lines, err := store.List(ctx, "default", "bot-prod", time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	require.Equal(t, "starting", lines[0].Line)
+	lines, err = store.List(ctx, "default", "bot-prod", base.Add(time.Minute), 0)
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	lines, err = store.List(ctx, "default", "bot-prod", time.Time{}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []DeploymentLogLine{{Timestamp: lines[0].Timestamp, Stream: "stdout", Line: "ready"}}, lines)
+
+	// "ready" alone fits in six bytes; "warn" and "starting" are trimmed.
+	trimmed, err := store.TrimToBytes(ctx, 6)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), trimmed)
+	purged, err := store.PurgeBefore(ctx, base.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+	lines, err = store.List(ctx, "default", "bot-prod", time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+}
+
+func TestUsageStatsStore_AddRankTotals(t *testing.T) {
+	pool := NewTestPool(t)
+	usage := NewUsageStatsStore(pool, TestSchema())
+	ctx := context.Background()
+
+	tools := UsageKey{Kind: v1alpha1.KindMCPServer, Namespace: testNS, Name: "tools"}
+	weather := UsageKey{Kind: v1alpha1.KindMCPServer, Namespace: testNS, Name: "weather"}
+	bot := UsageKey{Kind: v1alpha1.KindAgent, Namespace: testNS, Name: "bot"}
+
+	require.NoError(t, usage.Add(ctx, map[UsageKey]UsageCounts{
+		tools:   {Downloads: 2, SearchHits: 1},
+		weather: {Downloads: 1},
+		bot:     {Deploys: 1},
+	}))
+	require.NoError(t, usage.Add(ctx, map[UsageKey]UsageCounts{
+		weather: {Downloads: 3, Deploys: 1},
+	}))
+
+	got, err := usage.Get(ctx, weather)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), got.Downloads)
+	require.Equal(t, int64(1), got.Deploys)
+	require.False(t, got.UpdatedAt.IsZero())
+
+	missing, err := usage.Get(ctx, UsageKey{Kind: v1alpha1.KindSkill, Namespace: testNS, Name: "missing"})
+	require.NoError(t, err)
+	require.Zero(t, missing)
+
+	many, err := usage.GetMany(ctx, []UsageKey{tools, bot, {Kind: v1alpha1.KindSkill, Namespace: testNS, Name: "missing"}})
+	require.NoError(t, err)
+	require.Len(t, many, 2)
+	require.Equal(t, int64(3), many[tools].Popularity())
+
+	top, err := usage.Top(ctx, v1alpha1.KindMCPServer, testNS, 10)
+	require.NoError(t, err)
+	require.Equal(t, []UsageKey{weather, tools}, top)
+
+	totals, err := usage.Totals(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), totals[v1alpha1.KindMCPServer].Downloads)
+	require.Equal(t, int64(1), totals[v1alpha1.KindAgent].Deploys)
+}
+
+func TestServerToolStore_ToolsAndSearch(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	servers := NewStore(pool, TestSchema(), "mcp_servers", WithKind(v1alpha1.KindMCPServer))
+	store := NewServerToolStore(pool, TestSchema())
+
+	upsert := func(name string, tools ...v1alpha1.MCPTool) string {
+		t.Helper()
+		res, err := servers.Upsert(ctx, &v1alpha1.MCPServer{
+			Metadata: v1alpha1.ObjectMeta{Namespace: testNS, Name: name},
+			Spec:     v1alpha1.MCPServerSpec{Description: "An MCP server.", Tools: tools},
+		})
+		require.NoError(t, err)
+		return res.Tag
+	}
+	tag := upsert("github",
+		v1alpha1.MCPTool{Name: "create_issue", Description: "Open an issue in a repository.", InputSchema: map[string]any{"type": "object"}},
+		v1alpha1.MCPTool{Name: "list_pulls", Description: "List pull requests."},
+	)
+	upsert("weather", v1alpha1.MCPTool{Name: "get_forecast", Description: "Forecast for a city."})
+	upsert("empty")
+
+	tools, err := store.Tools(ctx, testNS, "github", tag)
+	require.NoError(t, err)
+	require.Equal(t, []v1alpha1.MCPTool{
+		{Name: "create_issue", Description: "Open an issue in a repository.", InputSchema: map[string]any{"type": "object"}},
+		{Name: "list_pulls", Description: "List pull requests."},
+	}, tools)
+
+	tools, err = store.Tools(ctx, testNS, "empty", tag)
+	require.NoError(t, err)
+	require.Empty(t, tools)
+	require.NotNil(t, tools)
+
+	_, err = store.Tools(ctx, testNS, "missing", tag)
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+
+	hits, err := store.SearchTools(ctx, SearchOpts{Query: "issues"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Equal(t, "github", hits[0].Name)
+	require.Equal(t, "create_issue", hits[0].Tool)
+	require.Positive(t, hits[0].Rank)
+
+	hits, err = store.SearchTools(ctx, SearchOpts{Query: "forec"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Equal(t, "get_forecast", hits[0].Tool)
+
+	hits, err = store.SearchTools(ctx, SearchOpts{Query: "issue", ExtraWhere: "name <> $1", ExtraArgs: []any{"github"}})
+	require.NoError(t, err)
+	require.Empty(t, hits)
+
+	// A republished spec replaces the recorded tools.
+	upsert("github", v1alpha1.MCPTool{Name: "search_code", Description: "Search code."})
+	tools, err = store.Tools(ctx, testNS, "github", tag)
+	require.NoError(t, err)
+	require.Equal(t, []v1alpha1.MCPTool{{Name: "search_code", Description: "Search code."}}, tools)
+	hits, err = store.SearchTools(ctx, SearchOpts{Query: "issue"})
+	require.NoError(t, err)
+	require.Empty(t, hits)
+}
+
+func TestCatalogSnapshotStore_CreateResolveDelete(t *testing.T) {
+	pool := NewTestPool(t)
+	ctx := context.Background()
+	store := NewStore(pool, TestSchema(), testTable, WithKind(v1alpha1.KindAgent))
+	snapshots := NewCatalogSnapshotStore(pool, TestSchema())
+
+	upsertAgent(t, store, "weather-bot", v1alpha1.AgentSpec{Title: "Weather v1"}, nil)
+	row, err := store.Get(ctx, testNS, "weather-bot", DefaultTag())
+	require.NoError(t, err)
+	row.Kind = v1alpha1.KindAgent
+
+	created, err := snapshots.Create(ctx, &CatalogSnapshot{Name: "2024-06-release", Description: "June", CreatedBy: "alice"}, []*v1alpha1.RawObject{row})
+	require.NoError(t, err)
+	require.Equal(t, 1, created.Entries)
+	require.False(t, created.CreatedAt.IsZero())
+	_, err = snapshots.Create(ctx, &CatalogSnapshot{Name: "2024-06-release"}, nil)
+	require.ErrorIs(t, err, pkgdb.ErrAlreadyExists)
+
+	// Republishing the tag leaves the snapshot's copy alone.
+	upsertAgent(t, store, "weather-bot", v1alpha1.AgentSpec{Title: "Weather v2"}, nil)
+	pinned, err := snapshots.Entry(ctx, "2024-06-release", v1alpha1.KindAgent, testNS, "weather-bot", DefaultTag())
+	require.NoError(t, err)
+	var spec v1alpha1.AgentSpec
+	require.NoError(t, json.Unmarshal(pinned.Spec, &spec))
+	require.Equal(t, "Weather v1", spec.Title)
+	_, err = snapshots.Entry(ctx, "2024-06-release", v1alpha1.KindAgent, testNS, "weather-bot", "2.0.0")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+
+	tags, err := snapshots.Tags(ctx, "2024-06-release", v1alpha1.KindAgent, testNS, "weather-bot")
+	require.NoError(t, err)
+	require.Equal(t, []string{DefaultTag()}, tags)
+	entries, err := snapshots.Entries(ctx, "2024-06-release")
+	require.NoError(t, err)
+	require.Equal(t, []CatalogSnapshotEntry{{Kind: v1alpha1.KindAgent, Namespace: testNS, Name: "weather-bot", Tag: DefaultTag()}}, entries)
+
+	got, err := snapshots.Get(ctx, "2024-06-release")
+	require.NoError(t, err)
+	require.Equal(t, "alice", got.CreatedBy)
+	require.Equal(t, 1, got.Entries)
+	all, err := snapshots.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	require.NoError(t, snapshots.Delete(ctx, "2024-06-release"))
+	require.ErrorIs(t, snapshots.Delete(ctx, "2024-06-release"), pkgdb.ErrNotFound)
+	_, err = snapshots.Get(ctx, "2024-06-release")
+	require.ErrorIs(t, err, pkgdb.ErrNotFound)
+	entries, err = snapshots.Entries(ctx, "2024-06-release")
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -13,13 +13,17 @@ import (
 // come from v1alpha1.KindDescriptor so the registration record remains the
 // single source of per-kind metadata.
 var builtInKinds = map[string]struct{}{
-	v1alpha1.KindAgent:      {},
-	v1alpha1.KindMCPServer:  {},
-	v1alpha1.KindSkill:      {},
-	v1alpha1.KindPlugin:     {},
-	v1alpha1.KindPrompt:     {},
-	v1alpha1.KindRuntime:    {},
-	v1alpha1.KindDeployment: {},
+	v1alpha1.KindAgent:              {},
+	v1alpha1.KindMCPServer:          {},
+	v1alpha1.KindSkill:              {},
+	v1alpha1.KindPlugin:             {},
+	v1alpha1.KindPrompt:             {},
+	v1alpha1.KindChart:              {},
+	v1alpha1.KindRuntime:            {},
+	v1alpha1.KindDeployment:         {},
+	v1alpha1.KindDeploymentTemplate: {},
+	v1alpha1.KindWebhook:            {},
+	v1alpha1.KindFeatureFlag:        {},
 }
 
 // NewStores builds one *Store per OSS built-in v1alpha1 Kind, bound to its
@@ -0,0 +1,197 @@
+package v1alpha1store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// UsageKey identifies the artifact a usage_stats row (migration 019)
+// counts: every tag of one name.
+type UsageKey struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// UsageCounts are the counters kept per artifact.
+type UsageCounts struct {
+	Downloads  int64
+	Deploys    int64
+	SearchHits int64
+	// UpdatedAt is when the counters last changed; zero for an artifact
+	// with no recorded use. Ignored by Add.
+	UpdatedAt time.Time
+}
+
+// Popularity is the combined count artifacts are ranked by.
+func (c UsageCounts) Popularity() int64 {
+	return c.Downloads + c.Deploys + c.SearchHits
+}
+
+// UsageStatsStore keeps per-artifact download, deploy and search-hit
+// counters.
+type UsageStatsStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewUsageStatsStore constructs a usage stats store.
+func NewUsageStatsStore(pool *pgxpool.Pool, schema pkgdb.Schema) *UsageStatsStore {
+	return &UsageStatsStore{
+		pool:      pool,
+		qualified: schema.Qualify("usage_stats"),
+	}
+}
+
+// Add increments the counters of each artifact in deltas in one statement.
+func (s *UsageStatsStore) Add(ctx context.Context, deltas map[UsageKey]UsageCounts) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: usage stats store has nil pool")
+	}
+	if len(deltas) == 0 {
+		return nil
+	}
+	var kinds, namespaces, names []string
+	var downloads, deploys, searchHits []int64
+	for key, c := range deltas {
+		kinds = append(kinds, key.Kind)
+		namespaces = append(namespaces, key.Namespace)
+		names = append(names, key.Name)
+		downloads = append(downloads, c.Downloads)
+		deploys = append(deploys, c.Deploys)
+		searchHits = append(searchHits, c.SearchHits)
+	}
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO `+s.qualified+` AS u (kind, namespace, name, downloads, deploys, search_hits)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::text[], $4::bigint[], $5::bigint[], $6::bigint[])
+		ON CONFLICT (kind, namespace, name) DO UPDATE SET
+			downloads = u.downloads + EXCLUDED.downloads,
+			deploys = u.deploys + EXCLUDED.deploys,
+			search_hits = u.search_hits + EXCLUDED.search_hits,
+			updated_at = NOW()`,
+		kinds, namespaces, names, downloads, deploys, searchHits)
+	if err != nil {
+		return fmt.Errorf("add usage stats: %w", err)
+	}
+	return nil
+}
+
+// Get returns the counters of one artifact; all zero when it has no
+// recorded use.
+func (s *UsageStatsStore) Get(ctx context.Context, key UsageKey) (UsageCounts, error) {
+	if s == nil || s.pool == nil {
+		return UsageCounts{}, errors.New("v1alpha1 store: usage stats store has nil pool")
+	}
+	var c UsageCounts
+	err := s.pool.QueryRow(ctx, `
+		SELECT downloads, deploys, search_hits, updated_at
+		FROM `+s.qualified+`
+		WHERE kind = $1 AND namespace = $2 AND name = $3`,
+		key.Kind, key.Namespace, key.Name).
+		Scan(&c.Downloads, &c.Deploys, &c.SearchHits, &c.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return UsageCounts{}, nil
+	}
+	if err != nil {
+		return UsageCounts{}, fmt.Errorf("get usage stats %s %s/%s: %w", key.Kind, key.Namespace, key.Name, err)
+	}
+	return c, nil
+}
+
+// GetMany returns the counters of each key that has recorded use. Keys
+// without a row are absent from the result.
+func (s *UsageStatsStore) GetMany(ctx context.Context, keys []UsageKey) (map[UsageKey]UsageCounts, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: usage stats store has nil pool")
+	}
+	out := make(map[UsageKey]UsageCounts, len(keys))
+	if len(keys) == 0 {
+		return out, nil
+	}
+	kinds := make([]string, len(keys))
+	namespaces := make([]string, len(keys))
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		kinds[i], namespaces[i], names[i] = key.Kind, key.Namespace, key.Name
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT u.kind, u.namespace, u.name, u.downloads, u.deploys, u.search_hits, u.updated_at
+		FROM `+s.qualified+` u
+		JOIN unnest($1::text[], $2::text[], $3::text[]) AS k(kind, namespace, name)
+			ON u.kind = k.kind AND u.namespace = k.namespace AND u.name = k.name`,
+		kinds, namespaces, names)
+	if err != nil {
+		return nil, fmt.Errorf("get usage stats: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key UsageKey
+		var c UsageCounts
+		if err := rows.Scan(&key.Kind, &key.Namespace, &key.Name, &c.Downloads, &c.Deploys, &c.SearchHits, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("read usage stats: %w", err)
+		}
+		out[key] = c
+	}
+	return out, rows.Err()
+}
+
+// Top returns up to limit artifacts of kind, most popular first. An empty
+// namespace ranks across every namespace. Artifacts with no recorded use
+// are not returned.
+func (s *UsageStatsStore) Top(ctx context.Context, kind, namespace string, limit int) ([]UsageKey, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: usage stats store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT namespace, name
+		FROM `+s.qualified+`
+		WHERE kind = $1 AND ($2 = '' OR namespace = $2)
+		ORDER BY downloads + deploys + search_hits DESC, namespace, name
+		LIMIT $3`,
+		kind, namespace, limit)
+	if err != nil {
+		return nil, fmt.Errorf("rank usage stats: %w", err)
+	}
+	defer rows.Close()
+	var out []UsageKey
+	for rows.Next() {
+		key := UsageKey{Kind: kind}
+		if err := rows.Scan(&key.Namespace, &key.Name); err != nil {
+			return nil, fmt.Errorf("read usage stats: %w", err)
+		}
+		out = append(out, key)
+	}
+	return out, rows.Err()
+}
+
+// Totals sums the counters of every artifact, per kind.
+func (s *UsageStatsStore) Totals(ctx context.Context) (map[string]UsageCounts, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: usage stats store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT kind, SUM(downloads)::bigint, SUM(deploys)::bigint, SUM(search_hits)::bigint
+		FROM `+s.qualified+`
+		GROUP BY kind`)
+	if err != nil {
+		return nil, fmt.Errorf("total usage stats: %w", err)
+	}
+	defer rows.Close()
+	out := map[string]UsageCounts{}
+	for rows.Next() {
+		var kind string
+		var c UsageCounts
+		if err := rows.Scan(&kind, &c.Downloads, &c.Deploys, &c.SearchHits); err != nil {
+			return nil, fmt.Errorf("read usage stats: %w", err)
+		}
+		out[kind] = c
+	}
+	return out, rows.Err()
+}
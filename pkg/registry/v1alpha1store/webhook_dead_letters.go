@@ -0,0 +1,209 @@
+package v1alpha1store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+// Dead letter states (migration 024).
+const (
+	// WebhookDeadLetterPending is retried automatically at NextAttemptAt.
+	WebhookDeadLetterPending = "pending"
+	// WebhookDeadLetterExhausted is only sent again by a replay.
+	WebhookDeadLetterExhausted = "exhausted"
+	// WebhookDeadLetterDelivered was delivered by a retry or replay.
+	WebhookDeadLetterDelivered = "delivered"
+)
+
+// WebhookDeadLetter is a webhook delivery whose attempts all failed, kept
+// with its payload for automatic retry and replay.
+type WebhookDeadLetter struct {
+	DeliveryID string          `json:"deliveryId"`
+	Namespace  string          `json:"namespace"`
+	Webhook    string          `json:"webhook"`
+	Event      string          `json:"event"`
+	URL        string          `json:"url"`
+	Payload    json.RawMessage `json:"payload"`
+	State      string          `json:"state" enum:"pending,exhausted,delivered"`
+	// Attempts counts every attempt of the delivery, including those made
+	// before it was parked. Retries counts the automatic retries since.
+	Attempts int `json:"attempts"`
+	Retries  int `json:"retries"`
+	// StatusCode and Error describe the last attempt.
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// NextAttemptAt is when a pending dead letter is retried.
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// WebhookDeadLetterStore parks failed webhook deliveries and hands due ones
+// back for retry.
+type WebhookDeadLetterStore struct {
+	pool      *pgxpool.Pool
+	qualified string
+}
+
+// NewWebhookDeadLetterStore constructs a dead letter store.
+func NewWebhookDeadLetterStore(pool *pgxpool.Pool, schema pkgdb.Schema) *WebhookDeadLetterStore {
+	return &WebhookDeadLetterStore{
+		pool:      pool,
+		qualified: schema.Qualify("webhook_dead_letters"),
+	}
+}
+
+const webhookDeadLetterColumns = `delivery_id, namespace, webhook, event, url, payload, state, attempts, retries,
+	status_code, error, next_attempt_at, created_at, updated_at`
+
+// Put parks a dead letter, or replaces the state, counters and last
+// outcome of the one with the same DeliveryID.
+func (s *WebhookDeadLetterStore) Put(ctx context.Context, d WebhookDeadLetter) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: webhook dead letter store has nil pool")
+	}
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO `+s.qualified+` (delivery_id, namespace, webhook, event, url, payload, state, attempts, retries,
+			status_code, error, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (delivery_id) DO UPDATE SET
+			state = EXCLUDED.state,
+			attempts = EXCLUDED.attempts,
+			retries = EXCLUDED.retries,
+			status_code = EXCLUDED.status_code,
+			error = EXCLUDED.error,
+			next_attempt_at = EXCLUDED.next_attempt_at,
+			updated_at = NOW()`,
+		d.DeliveryID, d.Namespace, d.Webhook, d.Event, d.URL, d.Payload, d.State, d.Attempts, d.Retries,
+		d.StatusCode, d.Error, d.NextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("put webhook dead letter %s: %w", d.DeliveryID, err)
+	}
+	return nil
+}
+
+// ClaimDue returns up to limit pending dead letters whose retry is due at
+// now, oldest first, and pushes their NextAttemptAt to now+lease so
+// another replica polling meanwhile skips them. The caller records the
+// retry's outcome with Put; a claim it abandons comes due again after the
+// lease.
+func (s *WebhookDeadLetterStore) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]WebhookDeadLetter, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: webhook dead letter store has nil pool")
+	}
+	rows, err := s.pool.Query(ctx, `
+		UPDATE `+s.qualified+` SET next_attempt_at = $2
+		WHERE delivery_id IN (
+			SELECT delivery_id FROM `+s.qualified+`
+			WHERE state = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED)
+		RETURNING `+webhookDeadLetterColumns, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("claim due webhook dead letters: %w", err)
+	}
+	return collectWebhookDeadLetters(rows)
+}
+
+// Get returns one webhook's dead letter, or pkgdb.ErrNotFound.
+func (s *WebhookDeadLetterStore) Get(ctx context.Context, namespace, webhook, deliveryID string) (*WebhookDeadLetter, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: webhook dead letter store has nil pool")
+	}
+	row := s.pool.QueryRow(ctx, `
+		SELECT `+webhookDeadLetterColumns+`
+		FROM `+s.qualified+`
+		WHERE namespace = $1 AND webhook = $2 AND delivery_id = $3`, namespace, webhook, deliveryID)
+	d, err := scanWebhookDeadLetter(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, pkgdb.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// List returns up to limit dead letters of one webhook, newest first. A
+// non-empty state returns only dead letters in that state.
+func (s *WebhookDeadLetterStore) List(ctx context.Context, namespace, webhook, state string, limit int) ([]WebhookDeadLetter, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: webhook dead letter store has nil pool")
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryListLimit
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+webhookDeadLetterColumns+`
+		FROM `+s.qualified+`
+		WHERE namespace = $1 AND webhook = $2 AND ($3 = '' OR state = $3)
+		ORDER BY created_at DESC, delivery_id
+		LIMIT $4`, namespace, webhook, state, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook dead letters: %w", err)
+	}
+	return collectWebhookDeadLetters(rows)
+}
+
+// PruneResolvedBefore deletes delivered and exhausted dead letters last
+// updated before before. Pending ones are kept until they resolve.
+func (s *WebhookDeadLetterStore) PruneResolvedBefore(ctx context.Context, before time.Time) (int64, error) {
+	if s == nil || s.pool == nil {
+		return 0, errors.New("v1alpha1 store: webhook dead letter store has nil pool")
+	}
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM `+s.qualified+`
+		WHERE state <> 'pending' AND updated_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("prune webhook dead letters: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func collectWebhookDeadLetters(rows pgx.Rows) ([]WebhookDeadLetter, error) {
+	defer rows.Close()
+	var out []WebhookDeadLetter
+	for rows.Next() {
+		d, err := scanWebhookDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read webhook dead letters: %w", err)
+	}
+	return out, nil
+}
+
+func scanWebhookDeadLetter(row pgx.Row) (WebhookDeadLetter, error) {
+	var d WebhookDeadLetter
+	if err := row.Scan(
+		&d.DeliveryID,
+		&d.Namespace,
+		&d.Webhook,
+		&d.Event,
+		&d.URL,
+		&d.Payload,
+		&d.State,
+		&d.Attempts,
+		&d.Retries,
+		&d.StatusCode,
+		&d.Error,
+		&d.NextAttemptAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	); err != nil {
+		return WebhookDeadLetter{}, fmt.Errorf("scan webhook dead letter: %w", err)
+	}
+	return d, nil
+}
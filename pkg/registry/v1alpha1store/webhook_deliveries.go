@@ -0,0 +1,152 @@
+package v1alpha1store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pkgdb "github.com/agentregistry-dev/agentregistry/pkg/registry/database"
+)
+
+const defaultDeliveryListLimit = 50
+
+// Delivery attempt statuses, derived from StatusCode and Error.
+const (
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one delivery attempt of a webhook event. A delivery
+// retried three times is three rows sharing DeliveryID.
+type WebhookDelivery struct {
+	ID          int64           `json:"id"`
+	DeliveryID  string          `json:"deliveryId"`
+	Namespace   string          `json:"namespace"`
+	Webhook     string          `json:"webhook"`
+	Event       string          `json:"event"`
+	URL         string          `json:"url"`
+	Attempt     int             `json:"attempt"`
+	StatusCode  int             `json:"statusCode,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+	DeliveredAt time.Time       `json:"deliveredAt"`
+	// Status is "succeeded" for a 2xx answer and "failed" otherwise. It
+	// is derived when the attempt is read, not stored.
+	Status string `json:"status,omitempty" enum:"succeeded,failed"`
+}
+
+// Succeeded reports whether the attempt got a 2xx answer.
+func (d WebhookDelivery) Succeeded() bool {
+	return d.Error == "" && d.StatusCode >= 200 && d.StatusCode < 300
+}
+
+// WebhookDeliveryStore records and lists webhook delivery attempts.
+type WebhookDeliveryStore struct {
+	pool       *pgxpool.Pool
+	qualified  string
+	partitions *PartitionManager
+}
+
+// NewWebhookDeliveryStore constructs a delivery-log store.
+func NewWebhookDeliveryStore(pool *pgxpool.Pool, schema pkgdb.Schema) *WebhookDeliveryStore {
+	return &WebhookDeliveryStore{
+		pool:       pool,
+		qualified:  schema.Qualify(WebhookDeliveriesTable.Name),
+		partitions: NewPartitionManager(pool, schema),
+	}
+}
+
+// Record appends one delivery attempt.
+func (s *WebhookDeliveryStore) Record(ctx context.Context, d WebhookDelivery) error {
+	if s == nil || s.pool == nil {
+		return errors.New("v1alpha1 store: webhook delivery store has nil pool")
+	}
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO `+s.qualified+` (delivery_id, namespace, webhook, event, url, attempt, status_code, error, payload)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		d.DeliveryID, d.Namespace, d.Webhook, d.Event, d.URL, d.Attempt, d.StatusCode, d.Error, d.Payload)
+	if err != nil {
+		return fmt.Errorf("record webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// List returns the newest attempts for one webhook, newest first. A
+// positive before returns only attempts with ID < before, for paging.
+func (s *WebhookDeliveryStore) List(ctx context.Context, namespace, webhook string, before int64, limit int) ([]WebhookDelivery, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("v1alpha1 store: webhook delivery store has nil pool")
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryListLimit
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, delivery_id, namespace, webhook, event, url, attempt, status_code, error, payload, delivered_at
+		FROM `+s.qualified+`
+		WHERE namespace = $1 AND webhook = $2 AND ($3::bigint <= 0 OR id < $3)
+		ORDER BY id DESC
+		LIMIT $4`, namespace, webhook, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var out []WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read webhook deliveries: %w", err)
+	}
+	return out, nil
+}
+
+// PruneBefore deletes attempts delivered before the cutoff, dropping day
+// partitions wholly older than it before deleting what remains.
+func (s *WebhookDeliveryStore) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	if s == nil || s.pool == nil {
+		return 0, errors.New("v1alpha1 store: webhook delivery store has nil pool")
+	}
+	dropped, err := s.partitions.DropPartitionsBefore(ctx, WebhookDeliveriesTable, before, nil)
+	if err != nil {
+		return dropped, fmt.Errorf("prune webhook delivery partitions: %w", err)
+	}
+	cmdTag, err := s.pool.Exec(ctx, `DELETE FROM `+s.qualified+` WHERE delivered_at < $1`, before)
+	if err != nil {
+		return dropped, fmt.Errorf("prune webhook deliveries: %w", err)
+	}
+	return dropped + cmdTag.RowsAffected(), nil
+}
+
+func scanWebhookDelivery(row pgx.Row) (WebhookDelivery, error) {
+	var d WebhookDelivery
+	if err := row.Scan(
+		&d.ID,
+		&d.DeliveryID,
+		&d.Namespace,
+		&d.Webhook,
+		&d.Event,
+		&d.URL,
+		&d.Attempt,
+		&d.StatusCode,
+		&d.Error,
+		&d.Payload,
+		&d.DeliveredAt,
+	); err != nil {
+		return WebhookDelivery{}, fmt.Errorf("scan webhook delivery: %w", err)
+	}
+	d.Status = WebhookDeliveryFailed
+	if d.Succeeded() {
+		d.Status = WebhookDeliverySucceeded
+	}
+	return d, nil
+}
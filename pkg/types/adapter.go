@@ -119,7 +119,8 @@ type ApplyResult struct {
 	// RuntimeMetadata carries adapter-internal state to persist
 	// into Deployment.Metadata.Annotations (keyed under
 	// runtimes.agentregistry.solo.io/<type>/*). Callers marshal
-	// to string values since Annotations is map[string]string.
+	// to string values since Annotations is map[string]string. An
+	// empty value removes the annotation.
 	RuntimeMetadata map[string]string
 
 	// Details is a map of top-level keys to JSON-encoded values to merge into
@@ -130,6 +131,22 @@ type ApplyResult struct {
 	// Use Details for structured state that Conditions cannot express cleanly;
 	// stable, typed status should still be modeled as Conditions.
 	Details map[string]json.RawMessage
+
+	// Manifests are the runtime artifacts Apply submitted (compose
+	// services, kagent resources, Helm releases), rendered as YAML with
+	// secret values redacted. The reconciler records the latest set so
+	// operators can inspect what is running; nil leaves the previous
+	// record in place.
+	Manifests []RenderedManifest
+}
+
+// RenderedManifest is one runtime artifact as Apply submitted it.
+type RenderedManifest struct {
+	// Name identifies the artifact within the Deployment, e.g.
+	// "docker-compose.yaml" or "Agent/kagent/bot-prod".
+	Name string
+	// Content is the artifact as YAML.
+	Content string
 }
 
 // RemoveInput carries the Deployment being torn down plus its resolved
@@ -154,6 +171,8 @@ type RemoveResult struct {
 // LogsInput selects a log stream for the deployed workload.
 type LogsInput struct {
 	Deployment *v1alpha1.Deployment
+	// Runtime is the resolved RuntimeRef.
+	Runtime *v1alpha1.Runtime
 	// Follow ⇒ stream indefinitely until ctx is cancelled. !Follow ⇒
 	// return the available backlog and close.
 	Follow bool
@@ -176,6 +195,29 @@ type DeploymentDiscoverySource interface {
 	Discover(ctx context.Context, in DiscoverInput) ([]DiscoveryResult, error)
 }
 
+// DeploymentRenderer is an optional adapter capability for runtimes that
+// can translate a Deployment without touching the runtime. Render returns
+// the manifests Apply would submit for the same input, redacted the same
+// way; it backs the deployment dry run.
+type DeploymentRenderer interface {
+	Render(ctx context.Context, in ApplyInput) ([]RenderedManifest, error)
+}
+
+// MCPEndpointSource is an optional adapter capability for runtimes whose
+// gateway serves a Deployment's MCP servers on a URL the registry can
+// reach. MCPEndpoint returns that URL; it backs the recording MCP relay
+// of Deployments in traffic debug mode.
+type MCPEndpointSource interface {
+	MCPEndpoint(ctx context.Context, in MCPEndpointInput) (string, error)
+}
+
+// MCPEndpointInput selects the Deployment whose MCP endpoint to return.
+type MCPEndpointInput struct {
+	Deployment *v1alpha1.Deployment
+	// Runtime is the resolved RuntimeRef.
+	Runtime *v1alpha1.Runtime
+}
+
 // DiscoverInput scopes a Discover call.
 type DiscoverInput struct {
 	Runtime *v1alpha1.Runtime
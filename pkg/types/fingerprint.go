@@ -239,17 +239,21 @@ func defaultApplyDependencies(ctx context.Context, in ApplyInput) ([]v1alpha1.Ob
 		return nil, nil
 	}
 	if in.Getter == nil {
-		if len(agent.Spec.MCPServers) > 0 || hasHarnessCompositionRefs(in.Deployment, agent) {
+		if len(agent.Spec.MCPServers) > 0 || len(agent.Spec.Charts) > 0 || hasHarnessCompositionRefs(in.Deployment, agent) {
 			return nil, fmt.Errorf("fingerprint: getter required to resolve Agent dependency refs")
 		}
 		return nil, nil
 	}
-	deps := make([]v1alpha1.Object, 0, len(agent.Spec.MCPServers)+len(agent.Spec.Plugins)+len(agent.Spec.Skills)+1)
+	deps := make([]v1alpha1.Object, 0, len(agent.Spec.MCPServers)+len(agent.Spec.Charts)+len(agent.Spec.Plugins)+len(agent.Spec.Skills)+1)
 	var err error
 	deps, err = appendResolvedRefs(ctx, deps, in.Getter, agent.Metadata.NamespaceOrDefault(), agent.Spec.MCPServers, v1alpha1.KindMCPServer, "target spec.mcpServers")
 	if err != nil {
 		return nil, err
 	}
+	deps, err = appendResolvedRefs(ctx, deps, in.Getter, agent.Metadata.NamespaceOrDefault(), agent.Spec.Charts, v1alpha1.KindChart, "target spec.charts")
+	if err != nil {
+		return nil, err
+	}
 	if !deploymentSelectsHarness(in.Deployment) {
 		return deps, nil
 	}
@@ -261,6 +265,12 @@ func defaultApplyDependencies(ctx context.Context, in ApplyInput) ([]v1alpha1.Ob
 	if err != nil {
 		return nil, err
 	}
+	if len(agent.Spec.Skills) > 0 {
+		deps, err = appendSkillDependencies(ctx, deps, in.Getter, agent)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if agent.Spec.Instructions != nil {
 		deps, err = appendResolvedRefs(ctx, deps, in.Getter, agent.Metadata.NamespaceOrDefault(), []v1alpha1.ResourceRef{*agent.Spec.Instructions}, v1alpha1.KindPrompt, "target spec.instructions")
 		if err != nil {
@@ -279,6 +289,35 @@ func deploymentSelectsHarness(deployment *v1alpha1.Deployment) bool {
 	return deployment != nil && deployment.Spec.Harness != nil
 }
 
+// appendSkillDependencies appends the skills and MCP servers the agent's
+// skills depend on, transitively, that deps doesn't already hold, so
+// publishing a new tag of one of them marks the Deployment stale.
+func appendSkillDependencies(ctx context.Context, deps []v1alpha1.Object, getter v1alpha1.GetterFunc, agent *v1alpha1.Agent) ([]v1alpha1.Object, error) {
+	closure, err := v1alpha1.ResolveAgentDependencies(ctx, getter, agent)
+	if err != nil {
+		return nil, fmt.Errorf("fingerprint: %w", err)
+	}
+	key := func(obj v1alpha1.Object) string {
+		meta := obj.GetMetadata()
+		return obj.GetKind() + "/" + meta.NamespaceOrDefault() + "/" + meta.Name + "@" + meta.Tag
+	}
+	have := make(map[string]bool, len(deps))
+	for _, dep := range deps {
+		have[key(dep)] = true
+	}
+	for _, server := range closure.MCPServers {
+		if !have[key(server)] {
+			deps = append(deps, server)
+		}
+	}
+	for _, skill := range closure.Skills {
+		if !have[key(skill)] {
+			deps = append(deps, skill)
+		}
+	}
+	return deps, nil
+}
+
 func appendResolvedRefs(
 	ctx context.Context,
 	deps []v1alpha1.Object,
@@ -293,7 +332,7 @@ func appendResolvedRefs(
 		if normalized.Kind == "" {
 			normalized.Kind = defaultKind
 		}
-		if normalized.Namespace == "" {
+		if normalized.Namespace == "" && normalized.Registry == "" {
 			normalized.Namespace = namespace
 		}
 		obj, err := getter(ctx, normalized)
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"context"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManifestMutator edits what the built-in local and kubernetes
+// DeploymentAdapters render for a Deployment before it is applied, so a
+// platform can inject labels, annotations, env or sidecars into every
+// workload the registry deploys. Mutators are registered in order through
+// AppOptions.ManifestMutators and run in that order, each seeing the
+// edits of the ones before it. The edited manifests are also what dry
+// runs and the recorded Deployment manifests show.
+//
+// A mutator implements ComposeServiceMutator, KubernetesObjectMutator or
+// both; the adapters skip mutators that do not handle their runtime. A
+// render can run more than once per Apply, so mutations must be
+// idempotent. A mutator error fails the Apply or render.
+//
+// Adapters registered through AppOptions.DeploymentAdapters are not
+// affected; they own their rendering.
+type ManifestMutator interface {
+	// Name identifies the mutator in errors.
+	Name() string
+}
+
+// ComposeServiceMutator edits the docker compose services the local
+// adapter renders for a Deployment's agent and MCP servers. The shared
+// agentgateway service is never passed to it.
+type ComposeServiceMutator interface {
+	ManifestMutator
+	MutateComposeService(ctx context.Context, in ApplyInput, service *composetypes.ServiceConfig) error
+}
+
+// KubernetesObjectMutator edits the kagent and kmcp resources
+// (*v1alpha2.Agent, *v1alpha2.RemoteMCPServer, *kmcpv1alpha1.MCPServer)
+// and ConfigMaps the kubernetes adapter renders for a Deployment.
+// Helm releases installed for Charts are not passed to it.
+type KubernetesObjectMutator interface {
+	ManifestMutator
+	MutateKubernetesObject(ctx context.Context, in ApplyInput, obj client.Object) error
+}
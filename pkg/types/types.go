@@ -103,6 +103,10 @@ type AdmissionInput struct {
 	Store             any
 	PostUpsert        PostUpsert
 	InitialFinalizers func(v1alpha1.Object) []string
+	// IfContentHash, when set, asks for the write to proceed only while the
+	// stored row still has this content hash (v1alpha1store.ContentHash).
+	// Set by the PATCH handler, which computed the object from that row.
+	IfContentHash string
 }
 
 type AdmissionResult struct {
@@ -160,10 +164,13 @@ type ResourceRouteContext struct {
 // produces a recordable state change calls into Auditor directly,
 // rather than relying on observers (PostUpsert hooks, etc.) to remember
 // to log.
+//
+// Events receive the request context, so sinks attribute them with
+// auth.SubjectFrom(ctx) and the namespace argument.
 type Auditor interface {
 	// ResourceTagCreated is invoked when Store.Upsert creates a new tag row
 	// for a content-registry kind. Mutable-object kinds do not produce this
-	// event.
+	// event; see ObjectAuditor.
 	ResourceTagCreated(ctx context.Context, kind, namespace, name, tag string)
 }
 
@@ -172,9 +179,70 @@ type noopAuditor struct{}
 func (noopAuditor) ResourceTagCreated(ctx context.Context, kind, namespace, name, tag string) {
 }
 
+// ObjectAuditor is an optional Auditor extension for mutable-object kinds
+// (Deployment, Runtime, ...). Auditors that implement it also receive
+// ObjectCreated when Store.Upsert inserts a new namespace/name row.
+type ObjectAuditor interface {
+	ObjectCreated(ctx context.Context, kind, namespace, name string)
+}
+
+// ShareAuditor is an optional Auditor extension. Auditors that implement
+// it also receive an event when a share link granting outside access to a
+// Deployment is created or revoked; shareID names the link.
+type ShareAuditor interface {
+	DeploymentShareCreated(ctx context.Context, namespace, name, shareID string)
+	DeploymentShareRevoked(ctx context.Context, namespace, name, shareID string)
+}
+
 // NoopAuditor is the default Auditor used when none is plugged in.
 var NoopAuditor Auditor = noopAuditor{}
 
+// MultiAuditor fans every event out to each non-nil auditor, in order.
+func MultiAuditor(auditors ...Auditor) Auditor {
+	var out multiAuditor
+	for _, a := range auditors {
+		if a != nil {
+			out = append(out, a)
+		}
+	}
+	if len(out) == 0 {
+		return NoopAuditor
+	}
+	return out
+}
+
+type multiAuditor []Auditor
+
+func (m multiAuditor) ResourceTagCreated(ctx context.Context, kind, namespace, name, tag string) {
+	for _, a := range m {
+		a.ResourceTagCreated(ctx, kind, namespace, name, tag)
+	}
+}
+
+func (m multiAuditor) ObjectCreated(ctx context.Context, kind, namespace, name string) {
+	for _, a := range m {
+		if oa, ok := a.(ObjectAuditor); ok {
+			oa.ObjectCreated(ctx, kind, namespace, name)
+		}
+	}
+}
+
+func (m multiAuditor) DeploymentShareCreated(ctx context.Context, namespace, name, shareID string) {
+	for _, a := range m {
+		if sa, ok := a.(ShareAuditor); ok {
+			sa.DeploymentShareCreated(ctx, namespace, name, shareID)
+		}
+	}
+}
+
+func (m multiAuditor) DeploymentShareRevoked(ctx context.Context, namespace, name, shareID string) {
+	for _, a := range m {
+		if sa, ok := a.(ShareAuditor); ok {
+			sa.DeploymentShareRevoked(ctx, namespace, name, shareID)
+		}
+	}
+}
+
 // AppOptions contains configuration for the registry app.
 // All fields are optional and allow external developers to extend
 // functionality.
@@ -218,6 +286,14 @@ type AppOptions struct {
 	// additional adapters here.
 	DeploymentAdapters map[string]DeploymentAdapter
 
+	// ManifestMutators edit what the built-in local and kubernetes
+	// DeploymentAdapters render for every Deployment — compose services
+	// and kagent/kmcp resources — before it is applied, in order. Use
+	// them to inject labels, annotations, env or sidecars platform-wide;
+	// pkg/mutators has ready-made ones. Adapters registered through
+	// DeploymentAdapters are not affected.
+	ManifestMutators []ManifestMutator
+
 	// Authorizers gates every read + write operation on the
 	// generic v1alpha1 resource handler, keyed by canonical Kind name
 	// (v1alpha1.KindAgent, v1alpha1.KindMCPServer, etc.). Downstream
@@ -358,6 +434,24 @@ type AppOptions struct {
 	// InitialFinalizers seeds finalizers atomically on create for kinds
 	// whose external teardown must be protected from a concurrent delete.
 	InitialFinalizers map[string]func(v1alpha1.Object) []string
+
+	// SecretsCipher encrypts the secret keys of Runtime spec.config at
+	// rest, e.g. through a cloud KMS. Wins over the AES-GCM master key in
+	// the SECRETS_MASTER_KEY setting. With neither, secret config values
+	// are refused, unless SECRETS_ALLOW_PLAINTEXT stores them as given
+	// (API responses still redact them).
+	SecretsCipher SecretsCipher
+}
+
+// SecretsCipher encrypts and decrypts secret Runtime config values. Every
+// value is stored with the KeyID it was encrypted under, so a cipher can
+// rotate keys and still decrypt values sealed with earlier ones.
+type SecretsCipher interface {
+	// KeyID names the key Encrypt uses.
+	KeyID() string
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	// Decrypt opens ciphertext that Encrypt produced under keyID.
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
 }
 
 // Server represents the HTTP server and provides access to the Huma API
@@ -17,13 +17,21 @@ type ResourceTagEvent struct {
 	Tag       string
 }
 
+// ObjectEvent is one captured ObjectAuditor.ObjectCreated call.
+type ObjectEvent struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
 // RecordingAuditor is a thread-safe types.Auditor that captures every
-// ResourceTagCreated event for assertions in tests. The mutex is
+// ResourceTagCreated (and ObjectCreated) event for assertions in tests. The mutex is
 // load-bearing because the v1alpha1store concurrency test invokes the
 // auditor from multiple goroutines.
 type RecordingAuditor struct {
-	mu     sync.Mutex
-	events []ResourceTagEvent
+	mu      sync.Mutex
+	events  []ResourceTagEvent
+	objects []ObjectEvent
 }
 
 // ResourceTagCreated records the event under the auditor's mutex.
@@ -48,4 +56,23 @@ func (r *RecordingAuditor) Events() []ResourceTagEvent {
 	return out
 }
 
-var _ types.Auditor = (*RecordingAuditor)(nil)
+// ObjectCreated records the event under the auditor's mutex.
+func (r *RecordingAuditor) ObjectCreated(_ context.Context, kind, namespace, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.objects = append(r.objects, ObjectEvent{Kind: kind, Namespace: namespace, Name: name})
+}
+
+// Objects returns a copy of the captured ObjectCreated events.
+func (r *RecordingAuditor) Objects() []ObjectEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]ObjectEvent, len(r.objects))
+	copy(out, r.objects)
+	return out
+}
+
+var (
+	_ types.Auditor       = (*RecordingAuditor)(nil)
+	_ types.ObjectAuditor = (*RecordingAuditor)(nil)
+)
@@ -1622,6 +1622,10 @@ func TestArctl_KeptCommandsResolve(t *testing.T) {
 		{"build"},
 		{"run"},
 		{"pull"},
+		{"push"},
+		{"mcp", "add-remote"},
+		{"registry", "export"},
+		{"registry", "import"},
 	}
 	for _, args := range cases {
 		args := args